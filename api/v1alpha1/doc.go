@@ -0,0 +1,10 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package v1alpha1 contains API Schema definitions for the workspaces v1alpha1 API group.
+// +kubebuilder:object:generate=true
+// +groupName=workspace.jupyter.org
+// +groupGoName=Workspace
+package v1alpha1
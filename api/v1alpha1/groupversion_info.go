@@ -3,9 +3,6 @@ Copyright (c) Amazon Web Services
 Distributed under the terms of the MIT license
 */
 
-// Package v1alpha1 contains API Schema definitions for the workspaces v1alpha1 API group.
-// +kubebuilder:object:generate=true
-// +groupName=workspace.jupyter.org
 package v1alpha1
 
 import (
@@ -22,4 +19,12 @@ var (
 
 	// AddToScheme adds the types in this group-version to the given scheme.
 	AddToScheme = SchemeBuilder.AddToScheme
+
+	// SchemeGroupVersion is the name the generated clients in pkg/client use for GroupVersion.
+	SchemeGroupVersion = GroupVersion
 )
+
+// Resource takes an unqualified resource and returns a group qualified GroupResource.
+func Resource(resource string) schema.GroupResource {
+	return GroupVersion.WithResource(resource).GroupResource()
+}
@@ -14,6 +14,14 @@ import (
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// TmpVolumeSpec defines the emptyDir volume mounted at /tmp
+type TmpVolumeSpec struct {
+	// SizeLimit is the most data /tmp may hold; the kubelet evicts the workspace pod past it.
+	// Unset, /tmp is only bounded by the ephemeral-storage limit of the pod.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
 // VolumeSpec defines a volume to mount from an existing PVC
 type VolumeSpec struct {
 	// Name is a unique identifier for this volume within the pod (maps to pod.spec.volumes[].name)
@@ -26,6 +34,99 @@ type VolumeSpec struct {
 	MountPath string `json:"mountPath"`
 }
 
+// VolumeSourceType is the kind of object an extra volume mounts
+type VolumeSourceType string
+
+// Volume source types of extra volumes
+const (
+	VolumeSourcePersistentVolumeClaim VolumeSourceType = "PersistentVolumeClaim"
+	VolumeSourceConfigMap             VolumeSourceType = "ConfigMap"
+	VolumeSourceSecret                VolumeSourceType = "Secret"
+)
+
+// ExtraVolumeSpec defines a volume to mount from an existing PVC, ConfigMap or Secret of the workspace namespace
+// +kubebuilder:validation:XValidation:rule="[has(self.persistentVolumeClaimName), has(self.configMapName), has(self.secretName)].filter(x, x).size() == 1",message="exactly one of persistentVolumeClaimName, configMapName or secretName must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.defaultMode) || !has(self.persistentVolumeClaimName)",message="defaultMode applies to configMapName and secretName only"
+type ExtraVolumeSpec struct {
+	// Name is a unique identifier for this volume within the pod (maps to pod.spec.volumes[].name)
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// MountPath is the absolute path where the volume is mounted; it must not be, or contain, the home directory
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:MaxLength=1024
+	MountPath string `json:"mountPath"`
+
+	// ReadOnly mounts the volume read-only
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// PersistentVolumeClaimName is the name of an existing PVC to mount, e.g. a shared dataset
+	// +optional
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap whose keys are mounted as files, e.g. a startup script
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// SecretName is the name of a Secret whose keys are mounted as files
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// DefaultMode is the permission of the files of a ConfigMap or Secret volume (e.g. 0755 for scripts)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	DefaultMode *int32 `json:"defaultMode,omitempty"`
+}
+
+// SourceType returns the kind of object the extra volume mounts
+func (v *ExtraVolumeSpec) SourceType() VolumeSourceType {
+	switch {
+	case v.ConfigMapName != "":
+		return VolumeSourceConfigMap
+	case v.SecretName != "":
+		return VolumeSourceSecret
+	default:
+		return VolumeSourcePersistentVolumeClaim
+	}
+}
+
+// GitRepositorySpec defines a git repository cloned into the home directory before the workspace starts
+type GitRepositorySpec struct {
+	// URL is the address of the repository, e.g. https://github.com/org/repo.git
+	// +kubebuilder:validation:Pattern=`^(https?://|ssh://|git@)`
+	// +kubebuilder:validation:MaxLength=2048
+	URL string `json:"url"`
+
+	// Ref is the branch, tag or commit to check out, the default branch of the repository when omitted
+	// +kubebuilder:validation:MaxLength=255
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// Directory is the directory of the home directory the repository is checked out in
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_][A-Za-z0-9._-]*$`
+	// +kubebuilder:validation:MaxLength=255
+	Directory string `json:"directory"`
+}
+
+// BootstrapSpec defines the preparation of the home directory before the workspace container starts
+type BootstrapSpec struct {
+	// GitRepositories are cloned into the home directory by an init container; cloning requires storage.
+	// A repository whose directory exists already is left as it is.
+	// +kubebuilder:validation:MaxItems=10
+	// +listType=map
+	// +listMapKey=directory
+	// +optional
+	GitRepositories []GitRepositorySpec `json:"gitRepositories,omitempty"`
+
+	// Timeout is how long the init containers of a workspace pod may take before the workspace is marked
+	// failed, the controller --workspace-bootstrap-timeout when omitted
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
 // ContainerConfig defines container command and args configuration
 type ContainerConfig struct {
 	// Command specifies the container command
@@ -35,15 +136,36 @@ type ContainerConfig struct {
 	Args []string `json:"args,omitempty"`
 }
 
+// StorageMode is whether the home directory of a workspace outlives its pod
+// +kubebuilder:validation:Enum=Persistent;Ephemeral
+type StorageMode string
+
+const (
+	// StorageModePersistent keeps the home directory in a PVC of the workspace
+	StorageModePersistent StorageMode = "Persistent"
+	// StorageModeEphemeral backs the home directory with an emptyDir volume, lost when the pod stops
+	StorageModeEphemeral StorageMode = "Ephemeral"
+)
+
 // StorageSpec defines the storage configuration for Workspace
 type StorageSpec struct {
-	// StorageClassName specifies the storage class to use for persistent storage
+	// Mode is Persistent for a home volume in a PVC, or Ephemeral for an emptyDir volume lost when the
+	// workspace stops; defaulted from the template, Persistent otherwise. It cannot change once the
+	// workspace has storage.
+	// +optional
+	Mode StorageMode `json:"mode,omitempty"`
+
+	// StorageClassName specifies the storage class to use for persistent storage, defaulted from the template.
+	// It cannot change once the workspace has storage.
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="storage class name is immutable"
 	StorageClassName *string `json:"storageClassName,omitempty"`
 
 	// Size specifies the size of the persistent volume
 	// Supports standard Kubernetes resource quantities (e.g., "10Gi", "500Mi", "1Ti")
 	// Integer values without units are interpreted as bytes
+	// Increasing it expands the existing volume when its StorageClass allows volume expansion; it cannot
+	// be decreased. The StorageResizing condition tracks the expansion.
+	// Under the Ephemeral mode, it is the size limit of the emptyDir volume.
 	// +kubebuilder:default="10Gi"
 	Size resource.Quantity `json:"size,omitempty"`
 
@@ -51,6 +173,29 @@ type StorageSpec struct {
 	// Default is /home/jovyan (jovyan is the standard user in Jupyter images)
 	// +kubebuilder:default="/home/jovyan"
 	MountPath string `json:"mountPath,omitempty"`
+
+	// SubPath is the directory of the volume mounted at the mount path, relative to the root of the volume,
+	// defaulted from the homeSubPath of the template. It cannot change once the workspace has storage.
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('/') && !self.split('/').exists(p, p == '..')",message="subPath must be a relative path without '..'"
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="subPath is immutable"
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+}
+
+// CloneSpec references the workspace whose home volume a new workspace is created as a copy of
+type CloneSpec struct {
+	// WorkspaceName is the name of the source workspace, in the namespace of the workspace
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	WorkspaceName string `json:"workspaceName"`
+
+	// VolumeSnapshotClassName copies the source volume through a VolumeSnapshot of that class, which lets
+	// the copy use another StorageClass of the same CSI driver. When omitted, the home volume is cloned from
+	// the source volume directly, in the StorageClass of the source.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	VolumeSnapshotClassName string `json:"volumeSnapshotClassName,omitempty"`
 }
 
 // AccessStrategyRef defines a reference to a WorkspaceAccessStrategy
@@ -72,9 +217,108 @@ type TemplateRef struct {
 	// When omitted, defaults to the workspace's namespace
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
+
+	// UpdatePolicy is Follow to admit the workspace against the current template each time it changes,
+	// or Pin to keep the template generation it was first admitted against and report template changes
+	// in the TemplateDrifted condition. Defaults to Follow.
+	// +optional
+	UpdatePolicy TemplateUpdatePolicy `json:"updatePolicy,omitempty"`
+}
+
+// TemplateUpdatePolicy defines how a workspace takes template changes
+// +kubebuilder:validation:Enum=Follow;Pin
+type TemplateUpdatePolicy string
+
+const (
+	// TemplateUpdatePolicyFollow admits the workspace against the current template
+	TemplateUpdatePolicyFollow TemplateUpdatePolicy = "Follow"
+	// TemplateUpdatePolicyPin keeps the template generation the workspace was first admitted against
+	TemplateUpdatePolicyPin TemplateUpdatePolicy = "Pin"
+)
+
+// WorkspaceCollaborator grants a user or a group access to a workspace
+type WorkspaceCollaborator struct {
+	// Kind is User or Group
+	// +kubebuilder:validation:Enum=User;Group
+	Kind string `json:"kind"`
+
+	// Name of the user or group
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Role is Viewer to get the workspace, or Editor to also update it
+	Role CollaboratorRole `json:"role"`
+}
+
+// CollaboratorRole defines the access of a collaborator to a workspace
+// +kubebuilder:validation:Enum=Viewer;Editor
+type CollaboratorRole string
+
+const (
+	// CollaboratorRoleViewer grants get on the workspace
+	CollaboratorRoleViewer CollaboratorRole = "Viewer"
+	// CollaboratorRoleEditor grants get, update and patch on the workspace
+	CollaboratorRoleEditor CollaboratorRole = "Editor"
+)
+
+// ResolvedTemplateStatus records the content of the template a workspace resolved
+type ResolvedTemplateStatus struct {
+	// Name of the WorkspaceTemplate
+	Name string `json:"name"`
+
+	// Namespace of the WorkspaceTemplate
+	Namespace string `json:"namespace"`
+
+	// Generation of the template when its checksum was recorded
+	Generation int64 `json:"generation"`
+
+	// Checksum is the content hash of the template spec, see TemplateSpecChecksum
+	Checksum string `json:"checksum"`
+}
+
+// SchedulingDefaults records the scheduling defaults of the template a workspace resolved
+type SchedulingDefaults struct {
+	// NodeSelector is the default node selector of the template
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are the default tolerations of the template
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ScheduleSpec defines when a workspace is started and stopped. Each expression is a standard cron
+// expression of five fields: minute, hour, day of month, month and day of week.
+// +kubebuilder:validation:XValidation:rule="has(self.startCron) || has(self.stopCron)",message="schedule requires startCron or stopCron"
+type ScheduleSpec struct {
+	// StartCron is when the workspace is started, e.g. "0 8 * * 1-5"
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	StartCron string `json:"startCron,omitempty"`
+
+	// StopCron is when the workspace is stopped, e.g. "0 18 * * 1-5"
+	// +kubebuilder:validation:MaxLength=100
+	// +optional
+	StopCron string `json:"stopCron,omitempty"`
+
+	// TimeZone is the IANA time zone the expressions are evaluated in, e.g. "Europe/Paris". Defaults to UTC.
+	// +kubebuilder:validation:MaxLength=64
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// RetentionSpec defines how long a workspace is kept once it no longer runs
+type RetentionSpec struct {
+	// DeleteAfterStoppedSeconds is how long the workspace may stay stopped or paused before the controller
+	// deletes it, with its storage. Zero keeps it. Workspaces with deletionProtection or the label
+	// workspace.jupyter.org/retain: "true" are kept.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DeleteAfterStoppedSeconds *int64 `json:"deleteAfterStoppedSeconds,omitempty"`
 }
 
 // IdleShutdownSpec defines idle shutdown configuration
+// +kubebuilder:validation:XValidation:rule="!has(self.neverConnectedTimeoutInMinutes) || self.neverConnectedTimeoutInMinutes >= self.idleTimeoutInMinutes",message="neverConnectedTimeoutInMinutes must not be shorter than idleTimeoutInMinutes"
 type IdleShutdownSpec struct {
 	// Enabled indicates if idle shutdown is enabled
 	Enabled bool `json:"enabled"`
@@ -83,6 +327,12 @@ type IdleShutdownSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	IdleTimeoutInMinutes int `json:"idleTimeoutInMinutes"`
 
+	// NeverConnectedTimeoutInMinutes specifies how long a workspace nobody connected to yet may run,
+	// counted from its start, before it is stopped. When unset, the idle timeout applies from the start.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NeverConnectedTimeoutInMinutes *int `json:"neverConnectedTimeoutInMinutes,omitempty"`
+
 	// Detection specifies how to detect idle state
 	Detection IdleDetectionSpec `json:"detection"`
 }
@@ -92,9 +342,120 @@ type IdleDetectionSpec struct {
 	// HTTPGet specifies the HTTP request to perform for idle detection
 	// +optional
 	HTTPGet *corev1.HTTPGetAction `json:"httpGet,omitempty"`
+
+	// LastActivityField is the field of the JSON response holding the last activity time,
+	// as an RFC 3339 time or milliseconds since the epoch. Defaults to lastActiveTimestamp.
+	// +optional
+	LastActivityField string `json:"lastActivityField,omitempty"`
+}
+
+// ServiceMeshSpec defines how the workspace pod participates in a service mesh
+type ServiceMeshSpec struct {
+	// SidecarInjection controls whether the mesh proxy sidecar is injected into the workspace pod.
+	// When unset, the namespace-level injection policy applies.
+	// Only takes effect when the controller runs with a service mesh mode (istio or linkerd).
+	// +optional
+	SidecarInjection *bool `json:"sidecarInjection,omitempty"`
+}
+
+// ServerAdapterPreset names a built-in description of how to talk to a workspace server
+// +kubebuilder:validation:Enum=jupyterlab;notebook-classic;code-server
+type ServerAdapterPreset string
+
+const (
+	// ServerAdapterPresetJupyterLab describes JupyterLab on Jupyter Server
+	ServerAdapterPresetJupyterLab ServerAdapterPreset = "jupyterlab"
+	// ServerAdapterPresetNotebookClassic describes the classic Notebook server
+	ServerAdapterPresetNotebookClassic ServerAdapterPreset = "notebook-classic"
+	// ServerAdapterPresetCodeServer describes code-server
+	ServerAdapterPresetCodeServer ServerAdapterPreset = "code-server"
+)
+
+// ServerAdapterSpec describes how the operator talks to the server of the workspace image:
+// where it listens, where it reports readiness and activity, how it shuts down and how it receives
+// its token and base URL. Fields set explicitly override those of the preset.
+type ServerAdapterSpec struct {
+	// Preset selects a built-in adapter for a well-known server
+	// +optional
+	Preset ServerAdapterPreset `json:"preset,omitempty"`
+
+	// Port is the port the server listens on
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// BaseURLEnv is the environment variable the server reads its base URL from. The base URL
+	// an access strategy provides as JUPYTER_BASE_URL is set under this name, and the paths
+	// below are served under it. When unset, paths are served from the root.
+	// +optional
+	BaseURLEnv string `json:"baseURLEnv,omitempty"`
+
+	// ReadinessPath is the path of the readiness probe of the workspace container, e.g. /api.
+	// When unset, the container has no readiness probe.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	ReadinessPath string `json:"readinessPath,omitempty"`
+
+	// ActivityPath is the path reporting the last activity of the server, used for idle detection
+	// when the idle shutdown configuration does not set its own endpoint
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	ActivityPath string `json:"activityPath,omitempty"`
+
+	// ActivityField is the field of the activity response holding the last activity time
+	// +optional
+	ActivityField string `json:"activityField,omitempty"`
+
+	// ShutdownPath is the path the operator POSTs to before stopping the workspace,
+	// so that the server shuts down cleanly
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	ShutdownPath string `json:"shutdownPath,omitempty"`
+
+	// Token delivers the token of the server from a Secret. The operator authenticates its own
+	// activity and shutdown requests with it.
+	// +optional
+	Token *ServerTokenSpec `json:"token,omitempty"`
+}
+
+// ServerTokenSpec defines how the server token is delivered to the workspace container
+// +kubebuilder:validation:XValidation:rule="has(self.envName) || has(self.argTemplate) || has(self.filePath)",message="one of envName, argTemplate or filePath must be set"
+type ServerTokenSpec struct {
+	// SecretKeyRef selects the token in a Secret of the workspace namespace
+	SecretKeyRef corev1.SecretKeySelector `json:"secretKeyRef"`
+
+	// EnvName is the environment variable the token is set in
+	// +optional
+	EnvName string `json:"envName,omitempty"`
+
+	// ArgTemplate is an argument appended to the container arguments, with {token} replaced
+	// by the token, e.g. --IdentityProvider.token={token}
+	// +kubebuilder:validation:XValidation:rule="self.contains('{token}')",message="argTemplate must contain {token}"
+	// +optional
+	ArgTemplate string `json:"argTemplate,omitempty"`
+
+	// FilePath is the path of a file the token is mounted at
+	// +kubebuilder:validation:Pattern=`^/`
+	// +optional
+	FilePath string `json:"filePath,omitempty"`
+}
+
+// EnvFromSource exposes the keys of a ConfigMap or Secret as environment variables of the workspace container
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef or secretRef must be set"
+type EnvFromSource struct {
+	corev1.EnvFromSource `json:",inline"`
+
+	// SourceNamespace is the namespace of the ConfigMap or Secret when it is not the workspace namespace.
+	// It is set when the source comes from a template in another namespace;
+	// the controller mirrors such sources into the workspace namespace and keeps them in sync.
+	// +optional
+	SourceNamespace string `json:"sourceNamespace,omitempty"`
 }
 
 // WorkspaceSpec defines the desired state of Workspace
+// +kubebuilder:validation:XValidation:rule="!has(self.gpuCount) || self.gpuCount == 0 || has(self.templateRef)",message="gpuCount requires a templateRef whose template defines accelerators"
+// +kubebuilder:validation:XValidation:rule="!has(self.bootstrap) || !has(self.bootstrap.gitRepositories) || size(self.bootstrap.gitRepositories) == 0 || has(self.storage)",message="bootstrap.gitRepositories requires storage"
 type WorkspaceSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -107,10 +468,33 @@ type WorkspaceSpec struct {
 	// Image specifies the container image to use
 	Image string `json:"image,omitempty"`
 
-	// DesiredStatus specifies the desired operational status
-	// +kubebuilder:validation:Enum=Running;Stopped
+	// ImagePullPolicy overrides the controller-wide pull policy for the workspace container image
+	// Set from the template's imagePolicy when the template enforces a pull policy
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// DesiredStatus specifies the desired operational status, Running when omitted.
+	// Paused stops the workspace pod but keeps its service, access resources and storage,
+	// so that the workspace resumes quickly at the same URL.
+	// Hibernated stops the workspace and keeps its home volume in a VolumeSnapshot instead of a PVC;
+	// the PVC is restored from the snapshot when the workspace runs again.
+	// +kubebuilder:validation:Enum=Running;Stopped;Paused;Hibernated
+	// +kubebuilder:default=Running
 	DesiredStatus string `json:"desiredStatus,omitempty"`
 
+	// RestartRequestedAt requests a restart of the workspace pod, like kubectl rollout restart: the
+	// controller rolls the deployment when the value is newer than status.lastRestartTime.
+	// Requests made before the restart happens are coalesced; a request made while the workspace does
+	// not run is skipped, its next start bringing a new pod.
+	// +optional
+	RestartRequestedAt *metav1.Time `json:"restartRequestedAt,omitempty"`
+
+	// DeletionProtection rejects the deletion of the workspace until it is set to false in a separate update.
+	// Deleting the namespace of the workspace still deletes it.
+	// +optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
 	// OwnershipType specifies who can modify the workspace.
 	// Public means anyone with RBAC permissions can update/delete the workspace.
 	// OwnerOnly means only the creator can update/delete the workspace.
@@ -125,24 +509,74 @@ type WorkspaceSpec struct {
 	// +optional
 	AccessType string `json:"accessType,omitempty"`
 
+	// Collaborators are the users and groups, besides the owner, granted access to the workspace through
+	// the Roles and RoleBindings the controller keeps. Only the owner and admins may change them.
+	// +listType=map
+	// +listMapKey=kind
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=50
+	// +optional
+	Collaborators []WorkspaceCollaborator `json:"collaborators,omitempty"`
+
 	// Resources specifies the resource requirements
 	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// GPUCount is the number of accelerators the workspace requests, of the kind the template
+	// accelerators define and up to their maxCount
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	GPUCount *int32 `json:"gpuCount,omitempty"`
+
 	// Storage specifies the storage configuration
 	Storage *StorageSpec `json:"storage,omitempty"`
 
+	// CloneFrom creates the home volume of the workspace as a copy of the bound home volume of another
+	// workspace of the namespace; it requires storage at least the size of the source volume. It cannot
+	// change after creation. The CloneInProgress and CloneComplete conditions report when the data is ready.
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="cloneFrom is immutable"
+	// +optional
+	CloneFrom *CloneSpec `json:"cloneFrom,omitempty"`
+
 	// Volumes specifies additional volumes to mount from existing PersistantVolumeClaims
 	// +kubebuilder:validation:XValidation:rule="!self.exists(v, v.name == 'workspace-storage')",message="volume name 'workspace-storage' is reserved"
 	Volumes []VolumeSpec `json:"volumes,omitempty"`
 
-	// ContainerConfig specifies container command and args configuration
+	// ExtraVolumes mounts existing PVCs, ConfigMaps and Secrets of the workspace namespace, e.g. scratch space,
+	// shared datasets or startup scripts. The template's AllowedVolumeSources limits the source types.
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:XValidation:rule="self.all(v, self.exists_one(o, o.mountPath == v.mountPath))",message="extra volume mount paths must be unique"
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ExtraVolumes []ExtraVolumeSpec `json:"extraVolumes,omitempty"`
+
+	// TmpVolume mounts an emptyDir volume at /tmp, so that temporary files count against the
+	// workspace's own ephemeral-storage instead of filling the node
+	// +optional
+	TmpVolume *TmpVolumeSpec `json:"tmpVolume,omitempty"`
+
+	// ContainerConfig specifies container command and args configuration. A workspace with a template
+	// may only set a command and args other than the template defaults when the template allows command
+	// overrides; the template's server adapter then does not apply (see AllowCommandOverride).
 	ContainerConfig *ContainerConfig `json:"containerConfig,omitempty"`
 
+	// Bootstrap prepares the home directory before the workspace container starts, e.g. by cloning
+	// git repositories. Failures are reported in the BootstrapFailed condition.
+	// +optional
+	Bootstrap *BootstrapSpec `json:"bootstrap,omitempty"`
+
 	// Env specifies environment variables for the workspace container
 	// When a template is used, template's BaseEnv vars are merged (workspace vars take precedence by name)
+	// and the names must match the template's AllowedEnvPatterns
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// EnvFrom specifies ConfigMaps and Secrets whose keys are exposed as environment variables
+	// When a template is used, template's BaseEnvFrom sources are merged
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	EnvFrom []EnvFromSource `json:"envFrom,omitempty"`
+
 	// NodeSelector specifies node selection constraints for the workspace pod
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 
@@ -152,6 +586,12 @@ type WorkspaceSpec struct {
 	// Tolerations specifies tolerations for the workspace pod to schedule on nodes with matching taints
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// PriorityClassName is the PriorityClass of the workspace pod, deciding which pods the scheduler
+	// preempts for the others. When a template is used, it must be one of its allowedPriorityClassNames.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Lifecycle specifies actions that the management system should take
 	// in response to container lifecycle events (for instance, lifecycle hooks)
 	Lifecycle *corev1.Lifecycle `json:"lifecycle,omitempty"`
@@ -160,6 +600,13 @@ type WorkspaceSpec struct {
 	// +optional
 	AccessStrategy *AccessStrategyRef `json:"accessStrategy,omitempty"`
 
+	// DisabledSidecars lists the names of sidecars of the AccessStrategy not to run in the workspace pod.
+	// Only sidecars the AccessStrategy lists in its optionalContainers can be disabled;
+	// their volumes not used by any other container are omitted as well.
+	// +optional
+	// +listType=set
+	DisabledSidecars []string `json:"disabledSidecars,omitempty"`
+
 	// TemplateRef references a WorkspaceTemplate to use as base configuration
 	// When set, template provides defaults and workspace spec fields act as overrides
 	// +optional
@@ -169,6 +616,16 @@ type WorkspaceSpec struct {
 	// +optional
 	IdleShutdown *IdleShutdownSpec `json:"idleShutdown,omitempty"`
 
+	// Schedule starts and stops the workspace at set times. A manual change of desiredStatus wins until
+	// the next scheduled start or stop.
+	// +optional
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+
+	// Retention deletes the workspace once it has been stopped for long enough.
+	// Defaults to the retention of the template.
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+
 	// AppType specifies the application type for this workspace
 	// +optional
 	AppType string `json:"appType,omitempty"`
@@ -177,6 +634,13 @@ type WorkspaceSpec struct {
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
 
+	// ImagePullSecrets are Secrets in the workspace namespace to pull the images of the workspace pod with,
+	// e.g. for a registry of the user. They are added to those of the template.
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
 	// PodSecurityContext specifies pod-level security context
 	// Overrides template defaults when specified
 	// +optional
@@ -187,6 +651,27 @@ type WorkspaceSpec struct {
 	// Overrides template defaults when specified
 	// +optional
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+
+	// ServiceMesh specifies service mesh integration settings for the workspace pod
+	// Overrides template defaults when specified
+	// +optional
+	ServiceMesh *ServiceMeshSpec `json:"serviceMesh,omitempty"`
+
+	// ServerAdapter describes how to talk to the server of the workspace image
+	// Overrides template defaults when specified
+	// +optional
+	ServerAdapter *ServerAdapterSpec `json:"serverAdapter,omitempty"`
+
+	// ServerType selects by name one of the server types of the template the workspace runs.
+	// Defaults to the first server type of the template.
+	// +optional
+	ServerType string `json:"serverType,omitempty"`
+
+	// PodMetadata specifies labels and annotations of the workspace pod, merged over the podMetadata of the
+	// template. Keys with the workspace.jupyter.org/ prefix are reserved for the controller.
+	// Changes restart the workspace pod.
+	// +optional
+	PodMetadata *ChildObjectMetadata `json:"podMetadata,omitempty"`
 }
 
 // AccessResourceStatus defines the status of a resource created from a template
@@ -204,6 +689,396 @@ type AccessResourceStatus struct {
 	Namespace string `json:"namespace"`
 }
 
+// EnvFromMirrorStatus describes a copy of an envFrom source of another namespace in the workspace namespace
+type EnvFromMirrorStatus struct {
+	// Kind of the mirrored resource, ConfigMap or Secret
+	Kind string `json:"kind"`
+
+	// Name of the copy in the workspace namespace
+	Name string `json:"name"`
+
+	// SourceNamespace is the namespace of the mirrored resource
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// SourceName is the name of the mirrored resource
+	SourceName string `json:"sourceName"`
+}
+
+// DesiredStatusIntent describes the actor intent that currently determines the workspace desired status.
+// Intents are resolved by precedence: Maintenance > User > Schedule > Culler.
+type DesiredStatusIntent struct {
+	// Actor identifies who expressed the winning intent
+	// +kubebuilder:validation:Enum=Maintenance;User;Schedule;Culler;Default
+	Actor string `json:"actor"`
+
+	// DesiredStatus is the desired status requested by the winning intent
+	DesiredStatus string `json:"desiredStatus"`
+
+	// SetAt is the time at which the winning intent was recorded, when known
+	// +optional
+	SetAt *metav1.Time `json:"setAt,omitempty"`
+
+	// ExpiresAt is the time after which the winning intent no longer applies, when bounded
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// PolicySource identifies where an effective lifecycle policy of a workspace comes from
+// +kubebuilder:validation:Enum=Workspace;Template;Namespace;Operator
+type PolicySource string
+
+const (
+	// PolicySourceWorkspace is a policy set on the workspace itself
+	PolicySourceWorkspace PolicySource = "Workspace"
+	// PolicySourceTemplate is a policy defaulted from the template of the workspace
+	PolicySourceTemplate PolicySource = "Template"
+	// PolicySourceNamespace is a policy configured on the namespace of the workspace
+	PolicySourceNamespace PolicySource = "Namespace"
+	// PolicySourceOperator is a policy configured on the controller
+	PolicySourceOperator PolicySource = "Operator"
+)
+
+// EffectivePolicies is the snapshot of the lifecycle policies that govern a workspace once its
+// template, namespace and operator defaults are resolved. A policy that does not apply is unset.
+type EffectivePolicies struct {
+	// IdleShutdown is the effective idle shutdown policy
+	// +optional
+	IdleShutdown *EffectiveIdleShutdownPolicy `json:"idleShutdown,omitempty"`
+
+	// Schedule is the effective start and stop schedule
+	// +optional
+	Schedule *EffectiveSchedulePolicy `json:"schedule,omitempty"`
+
+	// Retention is the effective stale workspace retention policy
+	// +optional
+	Retention *EffectiveRetentionPolicy `json:"retention,omitempty"`
+
+	// DeleteAfterStopped is the effective deletion of stopped workspaces
+	// +optional
+	DeleteAfterStopped *EffectiveDeleteAfterStoppedPolicy `json:"deleteAfterStopped,omitempty"`
+
+	// MaxLifetime is the effective maximum lifetime of the workspace
+	// +optional
+	MaxLifetime *EffectiveMaxLifetimePolicy `json:"maxLifetime,omitempty"`
+}
+
+// EffectiveIdleShutdownPolicy is the idle shutdown policy that applies to a workspace
+type EffectiveIdleShutdownPolicy struct {
+	// IdleTimeoutInMinutes is how long the workspace may stay idle before it is stopped
+	IdleTimeoutInMinutes int `json:"idleTimeoutInMinutes"`
+
+	// NeverConnectedTimeoutInMinutes is how long the workspace may run before anybody connects to it
+	// +optional
+	NeverConnectedTimeoutInMinutes *int `json:"neverConnectedTimeoutInMinutes,omitempty"`
+
+	// Source is where the policy comes from
+	Source PolicySource `json:"source"`
+}
+
+// EffectiveSchedulePolicy is the start and stop schedule that applies to a workspace
+type EffectiveSchedulePolicy struct {
+	// StartCron is when the workspace is started
+	// +optional
+	StartCron string `json:"startCron,omitempty"`
+
+	// StopCron is when the workspace is stopped
+	// +optional
+	StopCron string `json:"stopCron,omitempty"`
+
+	// TimeZone of the cron expressions
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Source is where the policy comes from
+	Source PolicySource `json:"source"`
+}
+
+// EffectiveRetentionPolicy is the stale workspace retention policy that applies to a workspace
+type EffectiveRetentionPolicy struct {
+	// StaleAfter is how long a stopped workspace may stay unused before it is marked stale
+	StaleAfter metav1.Duration `json:"staleAfter"`
+
+	// ArchiveGracePeriod is how long a stale workspace is kept before it is archived
+	ArchiveGracePeriod metav1.Duration `json:"archiveGracePeriod"`
+
+	// Exempt is true when the workspace is exempt from the policy
+	// +optional
+	Exempt bool `json:"exempt,omitempty"`
+
+	// Source is where the policy comes from
+	Source PolicySource `json:"source"`
+}
+
+// EffectiveDeleteAfterStoppedPolicy is the deletion of stopped workspaces that applies to a workspace
+type EffectiveDeleteAfterStoppedPolicy struct {
+	// After is how long the workspace may stay stopped or paused before it is deleted
+	After metav1.Duration `json:"after"`
+
+	// Exempt is true when the workspace is kept regardless
+	// +optional
+	Exempt bool `json:"exempt,omitempty"`
+
+	// Source is where the policy comes from
+	Source PolicySource `json:"source"`
+}
+
+// EffectiveMaxLifetimePolicy is the maximum lifetime that applies to a workspace
+type EffectiveMaxLifetimePolicy struct {
+	// MaxLifetime is how long the workspace may exist, counted from its creation
+	MaxLifetime metav1.Duration `json:"maxLifetime"`
+
+	// Action is what happens to the workspace when it reaches its maximum lifetime
+	Action MaxLifetimeAction `json:"action"`
+
+	// ExpiresAt is when the workspace reaches its maximum lifetime; unset in template defaults
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Exempt is true when an administrator exempted the workspace
+	// +optional
+	Exempt bool `json:"exempt,omitempty"`
+
+	// Source is where the policy comes from
+	Source PolicySource `json:"source"`
+}
+
+// CleanupHookPhase is the progress of an external cleanup hook
+// +kubebuilder:validation:Enum=Pending;Acknowledged;Skipped;Abandoned;Failed
+type CleanupHookPhase string
+
+const (
+	// CleanupHookPending is a hook that has not acknowledged the deletion yet
+	CleanupHookPending CleanupHookPhase = "Pending"
+	// CleanupHookAcknowledged is a hook that acknowledged the deletion
+	CleanupHookAcknowledged CleanupHookPhase = "Acknowledged"
+	// CleanupHookSkipped is a hook an administrator skipped with an annotation
+	CleanupHookSkipped CleanupHookPhase = "Skipped"
+	// CleanupHookAbandoned is a hook whose retries are exhausted, under a failure policy that proceeds
+	CleanupHookAbandoned CleanupHookPhase = "Abandoned"
+	// CleanupHookFailed is a hook whose retries are exhausted, under a failure policy that holds the deletion
+	CleanupHookFailed CleanupHookPhase = "Failed"
+)
+
+// CleanupHookStatus reports the progress of an external cleanup hook called while the workspace is deleted
+type CleanupHookStatus struct {
+	// Name of the hook in the operator configuration
+	Name string `json:"name"`
+
+	// Phase of the hook
+	Phase CleanupHookPhase `json:"phase"`
+
+	// Attempts is the number of calls made to the hook
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastAttemptTime is when the hook was last called
+	// +optional
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+
+	// Message describes the outcome of the last call
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ChildEventStatus summarizes the Kubernetes events of one reason on a resource of the workspace
+type ChildEventStatus struct {
+	// Kind of the resource the event is about, e.g. Pod or PersistentVolumeClaim
+	Kind string `json:"kind"`
+
+	// Name of the resource the event is about
+	Name string `json:"name"`
+
+	// Type of the event, Normal or Warning
+	Type string `json:"type"`
+
+	// Reason of the event, e.g. FailedScheduling
+	Reason string `json:"reason"`
+
+	// Message of the latest event
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Count is the number of occurrences observed since the first one
+	Count int32 `json:"count"`
+
+	// FirstTimestamp is when the first occurrence was observed
+	FirstTimestamp metav1.Time `json:"firstTimestamp"`
+
+	// LastTimestamp is when the latest occurrence was observed
+	LastTimestamp metav1.Time `json:"lastTimestamp"`
+
+	// MirroredTimestamp is when an occurrence was last re-emitted as an event of the workspace
+	// +optional
+	MirroredTimestamp *metav1.Time `json:"mirroredTimestamp,omitempty"`
+}
+
+// CullingDecision is the outcome of an evaluation of the idle shutdown rules of a workspace
+type CullingDecision string
+
+// Culling decisions
+const (
+	// CullingDecisionKeep keeps the workspace running: no idle shutdown rule applies yet
+	CullingDecisionKeep CullingDecision = "Keep"
+	// CullingDecisionCull stops the workspace
+	CullingDecisionCull CullingDecision = "Cull"
+	// CullingDecisionExempt keeps running a workspace a rule applies to, as a higher-precedence desired
+	// status intent (maintenance, a recent manual change, a schedule) holds it
+	CullingDecisionExempt CullingDecision = "Exempt"
+	// CullingDecisionDryRun keeps running a workspace a rule applies to, as the controller runs the culler
+	// in dry-run mode
+	CullingDecisionDryRun CullingDecision = "DryRun"
+	// CullingDecisionCheckFailed is an evaluation whose idle check failed
+	CullingDecisionCheckFailed CullingDecision = "CheckFailed"
+)
+
+// CullingStatus reports the inputs and the outcome of the last evaluation of the idle shutdown rules of
+// a running workspace, updated at each idle check
+type CullingStatus struct {
+	// EvaluatedAt is when the rules were last evaluated
+	EvaluatedAt metav1.Time `json:"evaluatedAt"`
+
+	// ActivitySource is the endpoint of the workspace server the last activity was read from
+	// +optional
+	ActivitySource string `json:"activitySource,omitempty"`
+
+	// LastActivity is the last activity the server reported
+	// +optional
+	LastActivity *metav1.Time `json:"lastActivity,omitempty"`
+
+	// Kernels is the number of kernels the server reported, when it reports them
+	// +optional
+	Kernels *int32 `json:"kernels,omitempty"`
+
+	// IdleTimeoutInMinutes is the idle timeout in effect
+	IdleTimeoutInMinutes int `json:"idleTimeoutInMinutes"`
+
+	// NeverConnectedTimeoutInMinutes is the never-connected timeout in effect, if any
+	// +optional
+	NeverConnectedTimeoutInMinutes *int `json:"neverConnectedTimeoutInMinutes,omitempty"`
+
+	// Decision is the outcome of the evaluation
+	// +kubebuilder:validation:Enum=Keep;Cull;Exempt;DryRun;CheckFailed
+	Decision CullingDecision `json:"decision"`
+
+	// Rule is the idle shutdown rule evaluated: Idle or NeverConnected
+	// +optional
+	Rule string `json:"rule,omitempty"`
+
+	// Message explains the decision
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// StartupProgress reports the milestones the latest start of the workspace reached
+type StartupProgress struct {
+	// Trigger is what began the start: Created for the first start of the workspace,
+	// Resumed for a start from Stopped, Restarted for a replacement of its running pod
+	// +kubebuilder:validation:Enum=Created;Resumed;Restarted
+	Trigger string `json:"trigger"`
+
+	// StartedAt is when the start began, from which the elapsed time of the milestones is measured
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// PodName is the workspace pod the milestones were observed on
+	// +optional
+	PodName string `json:"podName,omitempty"`
+
+	// RestartCount is the restart count of the workspace container when the milestones were observed
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty"`
+
+	// Milestone is the latest milestone reached, e.g. PodScheduled or ServerReady
+	// +optional
+	Milestone string `json:"milestone,omitempty"`
+
+	// Milestones lists the milestones reached, in the order they were reached
+	// +listType=atomic
+	// +optional
+	Milestones []StartupMilestone `json:"milestones,omitempty"`
+}
+
+// StartupMilestone records when a start reached a milestone
+type StartupMilestone struct {
+	// Name of the milestone: StorageBound, PodScheduled, PodInitialized, ContainerStarted or ServerReady
+	Name string `json:"name"`
+
+	// ReachedAt is when the milestone was reached
+	ReachedAt metav1.Time `json:"reachedAt"`
+
+	// ElapsedSeconds is the time from the beginning of the start to the milestone
+	ElapsedSeconds int32 `json:"elapsedSeconds"`
+}
+
+// ResourceRecommendations suggests resources for the workspace container from its observed usage.
+// Applying them is up to the owner, by hand or with the annotation
+// workspace.jupyter.org/apply-resource-recommendations.
+type ResourceRecommendations struct {
+	// CPU is the recommendation for the CPU of the workspace container
+	// +optional
+	CPU *ResourceRecommendation `json:"cpu,omitempty"`
+
+	// Memory is the recommendation for the memory of the workspace container
+	// +optional
+	Memory *ResourceRecommendation `json:"memory,omitempty"`
+
+	// ObservedSince is when the first usage sample the recommendations are based on was taken
+	ObservedSince metav1.Time `json:"observedSince"`
+
+	// SampleCount is the number of usage samples the recommendations are based on
+	SampleCount int64 `json:"sampleCount"`
+
+	// LastUpdateTime is when the recommendations were last computed
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
+}
+
+// ResourceRecommendation recommends a request and a limit for a resource
+type ResourceRecommendation struct {
+	// Usage is the observed usage at the percentile the request covers
+	Usage resource.Quantity `json:"usage"`
+
+	// Request is the recommended request: the usage plus headroom
+	Request resource.Quantity `json:"request"`
+
+	// Limit is the recommended limit: the usage at a higher percentile plus headroom
+	Limit resource.Quantity `json:"limit"`
+}
+
+// HibernationStatus reports the VolumeSnapshot the home volume of a hibernated workspace is kept in
+type HibernationStatus struct {
+	// SnapshotRef is the name of the VolumeSnapshot of the home volume, in the namespace of the workspace
+	SnapshotRef string `json:"snapshotRef"`
+
+	// HibernatedAt is when the PVC of the home volume was deleted, unset while it is being deleted
+	// +optional
+	HibernatedAt *metav1.Time `json:"hibernatedAt,omitempty"`
+}
+
+// WorkspaceConnectionStatus describes the Service a workspace is reached through
+type WorkspaceConnectionStatus struct {
+	// ServiceName is the name of the Service of the workspace
+	ServiceName string `json:"serviceName"`
+
+	// Port is the port of the Service serving the workspace
+	Port int32 `json:"port"`
+
+	// PortForwardCommand forwards the Service port to the local machine, for workspaces not exposed outside the cluster
+	// +optional
+	PortForwardCommand string `json:"portForwardCommand,omitempty"`
+}
+
+// WorkspaceHistoryEntry records an action the controller took on its own on the workspace
+type WorkspaceHistoryEntry struct {
+	// Time is when the action was taken
+	Time metav1.Time `json:"time"`
+
+	// Action identifies the action (e.g. FlaggedStale, ArchivalRequested)
+	Action string `json:"action"`
+
+	// Message describes why the action was taken
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // WorkspaceStatus defines the observed state of Workspace.
 type WorkspaceStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
@@ -220,10 +1095,29 @@ type WorkspaceStatus struct {
 	// +optional
 	ServiceName string `json:"serviceName,omitempty"`
 
+	// ChildNamePrefix is the prefix of the names of the resources generated for the Workspace.
+	// It is recorded once and kept until the Workspace is recreated; unset, the default "workspace" prefix applies.
+	// +optional
+	ChildNamePrefix string `json:"childNamePrefix,omitempty"`
+
+	// LegacyChildNames is true when the resources of the Workspace were created before long workspace
+	// names were shortened in the names of their resources, and keep their full names until it is recreated.
+	// +optional
+	LegacyChildNames bool `json:"legacyChildNames,omitempty"`
+
 	// AccessURL is the URL at which the workspace can be accessed
 	// +optional
 	AccessURL string `json:"accessURL,omitempty"`
 
+	// URL is the address to connect to the running workspace: the AccessURL of its access strategy, or the
+	// in-cluster DNS name of its Service when it has none
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// Connection describes the Service the running workspace is reached through
+	// +optional
+	Connection *WorkspaceConnectionStatus `json:"connection,omitempty"`
+
 	// AccessResourceSelector is a label selector that can be used to find all resources
 	// created from the workspace's AccessStrategy templates
 	// +optional
@@ -234,6 +1128,213 @@ type WorkspaceStatus struct {
 	// +optional
 	AccessResources []AccessResourceStatus `json:"accessResources,omitempty"`
 
+	// EnvFromMirrors lists the copies of envFrom sources of other namespaces
+	// the controller maintains in the workspace namespace
+	// +optional
+	EnvFromMirrors []EnvFromMirrorStatus `json:"envFromMirrors,omitempty"`
+
+	// EnvFromSecretsChecksum is the checksum of the data of the Secrets exposed through envFrom,
+	// as last read by the controller. The workspace pod records the checksum it started with:
+	// when they differ, the pod holds the values of rotated Secrets until it restarts.
+	// +optional
+	EnvFromSecretsChecksum string `json:"envFromSecretsChecksum,omitempty"`
+
+	// ChildMetadata reports the labels and annotations resolved from the template childMetadata
+	// that the controller sets on the workspace resources
+	// +optional
+	ChildMetadata *ChildMetadata `json:"childMetadata,omitempty"`
+
+	// ClusterAccess reports the cluster access resolved from the template that the controller
+	// grants the workspace pod; no service account token is mounted when unset
+	// +optional
+	ClusterAccess *ClusterAccessSpec `json:"clusterAccess,omitempty"`
+
+	// Accelerators reports the accelerators resolved from the template that the controller schedules
+	// the workspace pod on when spec.gpuCount is set
+	// +optional
+	Accelerators *AcceleratorSpec `json:"accelerators,omitempty"`
+
+	// Scheduling reports the default node selector and tolerations resolved from the template that the
+	// controller merges with those of the workspace into the workspace pod
+	// +optional
+	Scheduling *SchedulingDefaults `json:"scheduling,omitempty"`
+
+	// ImagePullSecrets reports the image pull secrets resolved from the template that the controller merges
+	// with those of the workspace into the workspace pod
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// SharedVolumes reports the shared volumes resolved from the template that the controller mounts in
+	// the workspace pod
+	// +optional
+	SharedVolumes []SharedVolume `json:"sharedVolumes,omitempty"`
+
+	// Sidecars reports the sidecar containers resolved from the template that the controller adds to
+	// the workspace pod. Under the OnRestart sidecar update policy, they are resolved again when the
+	// workspace starts.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// SidecarResourceAccounting reports the sidecar resource accounting resolved from the template with
+	// the sidecars: Carved when the workspace container gives the sidecars their resources
+	// +optional
+	SidecarResourceAccounting SidecarResourceAccounting `json:"sidecarResourceAccounting,omitempty"`
+
+	// IsolationLevel reports the isolation level resolved from the template that the controller runs the
+	// workspace pod under: Rootless when the template asks for UserNamespace on a cluster without user
+	// namespaces. Empty is Standard.
+	// +optional
+	IsolationLevel IsolationLevel `json:"isolationLevel,omitempty"`
+
+	// InitContainers reports the init containers resolved from the template that the controller adds to
+	// the workspace pod. They are resolved again when the workspace starts.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// ResolvedTemplate records the checksum of the template content the workspace resolved, so that
+	// later changes to the template are detected
+	// +optional
+	ResolvedTemplate *ResolvedTemplateStatus `json:"resolvedTemplate,omitempty"`
+
+	// DesiredStatusIntent reports which actor intent won desired status resolution
+	// during the last reconciliation
+	// +optional
+	DesiredStatusIntent *DesiredStatusIntent `json:"desiredStatusIntent,omitempty"`
+
+	// NextScheduledStart is the next time spec.schedule starts the workspace
+	// +optional
+	NextScheduledStart *metav1.Time `json:"nextScheduledStart,omitempty"`
+
+	// NextScheduledStop is the next time spec.schedule stops the workspace
+	// +optional
+	NextScheduledStop *metav1.Time `json:"nextScheduledStop,omitempty"`
+
+	// EffectivePolicies is the snapshot of the lifecycle policies that govern the workspace,
+	// maintained by the controller
+	// +optional
+	EffectivePolicies *EffectivePolicies `json:"effectivePolicies,omitempty"`
+
+	// StoppedAt is when the workspace last stopped or paused; unset while it runs
+	// +optional
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// Storage reports whether the home directory of the workspace is Persistent or Ephemeral; unset
+	// without storage
+	// +optional
+	Storage StorageMode `json:"storage,omitempty"`
+
+	// Hibernation reports the VolumeSnapshot holding the home volume of a hibernated workspace,
+	// kept until the PVC restored from it is bound
+	// +optional
+	Hibernation *HibernationStatus `json:"hibernation,omitempty"`
+
+	// DeletionScheduledAt is when the controller deletes the workspace under spec.retention, set once its
+	// owner has been warned
+	// +optional
+	DeletionScheduledAt *metav1.Time `json:"deletionScheduledAt,omitempty"`
+
+	// CleanupHooks reports the progress of the external cleanup hooks called while the workspace is deleted
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	CleanupHooks []CleanupHookStatus `json:"cleanupHooks,omitempty"`
+
+	// LastStartTime is the last time the workspace became available
+	// +optional
+	LastStartTime *metav1.Time `json:"lastStartTime,omitempty"`
+
+	// LastRestartTime is the spec.restartRequestedAt of the last restart request handled by the controller
+	// +optional
+	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
+
+	// ResolvedImage is the image the workspace pod runs under the Digest image pinning of its template:
+	// the image with the digest its tag pointed at when resolved, e.g. quay.io/jupyter/base-notebook:latest@sha256:...
+	// It is resolved again when the image of the workspace changes.
+	// +optional
+	ResolvedImage string `json:"resolvedImage,omitempty"`
+
+	// ServerType reports the server type of the template the workspace runs, whose port, readiness
+	// path and args the controller renders into the workspace container
+	// +optional
+	ServerType *ServerType `json:"serverType,omitempty"`
+
+	// LastActivityTime is the last time the workspace was observed in use: the last activity
+	// reported by its idle endpoint, or the time it stopped. Recorded with a granularity of an hour.
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// FirstConnectedAt is the first activity observed on the workspace after it started, i.e. the first
+	// time a user connected to it. Unset while nobody has connected yet.
+	// +optional
+	FirstConnectedAt *metav1.Time `json:"firstConnectedAt,omitempty"`
+
+	// Culling reports the last evaluation of the idle shutdown rules of the workspace while it runs
+	// +optional
+	Culling *CullingStatus `json:"culling,omitempty"`
+
+	// StartupCheckPodUID is the UID of the workspace pod the template startup check last ran in.
+	// The check runs once per pod; its result is in the StartupCheckPassed condition.
+	// +optional
+	StartupCheckPodUID string `json:"startupCheckPodUID,omitempty"`
+
+	// PostStartScript reports the post-start script resolved from the template that the workspace container
+	// runs when it starts. It is resolved again when the workspace starts.
+	// +optional
+	PostStartScript string `json:"postStartScript,omitempty"`
+
+	// PostStartPodUID is the UID of the workspace pod whose post-start script outcome was last recorded,
+	// in the PostStartFailed condition
+	// +optional
+	PostStartPodUID string `json:"postStartPodUID,omitempty"`
+
+	// Phase summarizes the conditions of the workspace: Running, Pending, Stopping, Stopped or Unknown.
+	// A paused workspace is Stopped. The Ready condition is True only when the phase is Running.
+	// +kubebuilder:validation:Enum=Running;Pending;Stopping;Stopped;Unknown
+	// +optional
+	Phase string `json:"phase,omitempty"`
+
+	// BlockedReason summarizes why a workspace meant to run is not running, derived from its conditions.
+	// When several apply, the first in the order ValidationFailed, TemplateMissing, QuotaExceeded,
+	// WaitingForCapacity, StorageProvisioning, ImagePull, Initializing, Unknown is reported. Unset when the workspace is
+	// running, or stopped or stopping as desired.
+	// +kubebuilder:validation:Enum=ValidationFailed;TemplateMissing;QuotaExceeded;WaitingForCapacity;StorageProvisioning;ImagePull;Initializing;Unknown
+	// +optional
+	BlockedReason string `json:"blockedReason,omitempty"`
+
+	// BlockedMessage details the blocked reason
+	// +optional
+	BlockedMessage string `json:"blockedMessage,omitempty"`
+
+	// History lists the latest actions the controller took on its own on the workspace, oldest first
+	// +listType=atomic
+	// +optional
+	History []WorkspaceHistoryEntry `json:"history,omitempty"`
+
+	// Collaborators lists the collaborators the RoleBindings of the workspace currently grant access to it
+	// +listType=atomic
+	// +optional
+	Collaborators []WorkspaceCollaborator `json:"collaborators,omitempty"`
+
+	// ChildEvents lists the latest Kubernetes events of the resources of the workspace, such as its pod,
+	// mirrored on the workspace by the controller when event mirroring is enabled, most recent last
+	// +listType=atomic
+	// +optional
+	ChildEvents []ChildEventStatus `json:"childEvents,omitempty"`
+
+	// StartupProgress reports the milestones the latest start of the workspace reached, for
+	// user interfaces to show the progress of a start
+	// +optional
+	StartupProgress *StartupProgress `json:"startupProgress,omitempty"`
+
+	// Recommendations suggests resources for the workspace container from its observed usage,
+	// when resource recommendations are enabled and enough usage was observed
+	// +optional
+	Recommendations *ResourceRecommendations `json:"recommendations,omitempty"`
+
 	// Conditions represent the current state of the Workspace resource.
 	// Each condition has a unique type and reflects the status of a specific aspect of the resource.
 	//
@@ -242,6 +1343,7 @@ type WorkspaceStatus struct {
 	// - "Progressing": the resource is being created, updated, or stopped
 	// - "Degraded": the resource failed to reach or maintain its desired state
 	// - "Stopped": the workspace has been stopped and resources scaled down
+	// - "Paused": the workspace pod has been stopped, its service, access resources and storage are kept
 	//
 	// The status of each condition is one of True, False, or Unknown.
 	// +listType=map
@@ -252,14 +1354,21 @@ type WorkspaceStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=".status.conditions[?(@.type==\"Available\")].status"
 // +kubebuilder:printcolumn:name="Progressing",type="string",JSONPath=".status.conditions[?(@.type==\"Progressing\")].status"
 // +kubebuilder:printcolumn:name="Degraded",type="string",JSONPath=".status.conditions[?(@.type==\"Degraded\")].status"
+// +kubebuilder:printcolumn:name="Paused",type="string",JSONPath=".status.conditions[?(@.type==\"ReconciliationPaused\")].status"
+// +kubebuilder:printcolumn:name="Blocked",type="string",JSONPath=".status.blockedReason"
+// +kubebuilder:printcolumn:name="Protected",type="boolean",JSONPath=".spec.deletionProtection"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="CreatedBy",type="string",JSONPath=`.metadata.annotations['workspace\.jupyter\.org/created-by']`,priority=1
 // +kubebuilder:printcolumn:name="AccessType",type="string",JSONPath=".spec.accessType",priority=1
+// +kubebuilder:printcolumn:name="Storage",type="string",JSONPath=".status.storage",priority=1
 
 // Workspace is the Schema for the workspaces API
 type Workspace struct {
@@ -51,6 +51,12 @@ type PodModifications struct {
 	// +optional
 	AdditionalContainers []corev1.Container `json:"additionalContainers,omitempty"`
 
+	// OptionalContainers lists the names of the AdditionalContainers that workspaces
+	// may disable through spec.disabledSidecars. Other additional containers are mandatory.
+	// +optional
+	// +listType=set
+	OptionalContainers []string `json:"optionalContainers,omitempty"`
+
 	// Volumes to add to the pod
 	// +optional
 	Volumes []corev1.Volume `json:"volumes,omitempty"`
@@ -137,6 +143,7 @@ type WorkspaceAccessStrategyStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -0,0 +1,105 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspaceNamespaceStatusName is the name of the single WorkspaceNamespaceStatus object maintained per namespace
+const WorkspaceNamespaceStatusName = "workspaces"
+
+// WorkspacePhaseCounts counts workspaces by their derived phase
+type WorkspacePhaseCounts struct {
+	// Running workspaces are available
+	Running int32 `json:"running"`
+
+	// Pending workspaces are starting
+	Pending int32 `json:"pending"`
+
+	// Stopping workspaces are scaling down
+	Stopping int32 `json:"stopping"`
+
+	// Stopped workspaces have no compute running
+	Stopped int32 `json:"stopped"`
+
+	// Unknown workspaces have not been reconciled yet
+	Unknown int32 `json:"unknown"`
+}
+
+// NamespaceQuotaUsage reports the utilization of a ResourceQuota in the namespace
+type NamespaceQuotaUsage struct {
+	// Name of the ResourceQuota
+	Name string `json:"name"`
+
+	// Hard is the enforced limit for each resource
+	// +optional
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+
+	// Used is the current consumption of each resource
+	// +optional
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// WorkspaceNamespaceSummary defines the aggregated state of all workspaces in a namespace
+type WorkspaceNamespaceSummary struct {
+	// TotalWorkspaces is the number of workspaces in the namespace, excluding those being deleted
+	TotalWorkspaces int32 `json:"totalWorkspaces"`
+
+	// PhaseCounts counts workspaces by phase
+	PhaseCounts WorkspacePhaseCounts `json:"phaseCounts"`
+
+	// DegradedWorkspaces is the number of workspaces whose Degraded condition is True
+	DegradedWorkspaces int32 `json:"degradedWorkspaces"`
+
+	// OldestPendingSince is the time the longest-pending workspace started progressing
+	// +optional
+	OldestPendingSince *metav1.Time `json:"oldestPendingSince,omitempty"`
+
+	// Quotas reports the utilization of the namespace's ResourceQuotas
+	// +optional
+	Quotas []NamespaceQuotaUsage `json:"quotas,omitempty"`
+
+	// LastUpdateTime is the last time the summary was written by the controller.
+	// Updates are debounced, so the summary may lag behind individual workspaces by a few seconds.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalWorkspaces"
+// +kubebuilder:printcolumn:name="Running",type="integer",JSONPath=".status.phaseCounts.running"
+// +kubebuilder:printcolumn:name="Pending",type="integer",JSONPath=".status.phaseCounts.pending"
+// +kubebuilder:printcolumn:name="Stopped",type="integer",JSONPath=".status.phaseCounts.stopped"
+// +kubebuilder:printcolumn:name="Degraded",type="integer",JSONPath=".status.degradedWorkspaces"
+// +kubebuilder:printcolumn:name="OldestPending",type="date",JSONPath=".status.oldestPendingSince"
+// +kubebuilder:printcolumn:name="Updated",type="date",JSONPath=".status.lastUpdateTime",priority=1
+
+// WorkspaceNamespaceStatus is a controller-maintained summary of the workspaces in a namespace
+type WorkspaceNamespaceStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Status defines the aggregated state of the namespace's workspaces
+	// +optional
+	Status WorkspaceNamespaceSummary `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkspaceNamespaceStatusList contains a list of WorkspaceNamespaceStatus
+type WorkspaceNamespaceStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []WorkspaceNamespaceStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&WorkspaceNamespaceStatus{}, &WorkspaceNamespaceStatusList{})
+}
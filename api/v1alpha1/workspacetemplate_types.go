@@ -11,7 +11,69 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ImagePolicyMode defines which images workspaces using a template may set in spec.image
+// +kubebuilder:validation:Enum=DefaultOnly;AllowList;Any
+type ImagePolicyMode string
+
+const (
+	// ImagePolicyModeDefaultOnly restricts workspaces to the default image of the template
+	ImagePolicyModeDefaultOnly ImagePolicyMode = "DefaultOnly"
+	// ImagePolicyModeAllowList restricts workspaces to the default image and the images matching allowedImages
+	ImagePolicyModeAllowList ImagePolicyMode = "AllowList"
+	// ImagePolicyModeAny lets workspaces set any image
+	ImagePolicyModeAny ImagePolicyMode = "Any"
+)
+
+// MaxLifetimeAction defines what happens to workspaces reaching the maximum lifetime of their template
+// +kubebuilder:validation:Enum=Stop;Delete
+type MaxLifetimeAction string
+
+const (
+	// MaxLifetimeActionStop keeps workspaces reaching their maximum lifetime stopped until they are recreated
+	MaxLifetimeActionStop MaxLifetimeAction = "Stop"
+	// MaxLifetimeActionDelete deletes workspaces reaching their maximum lifetime, with their storage
+	MaxLifetimeActionDelete MaxLifetimeAction = "Delete"
+)
+
+// ImagePinningMode defines whether workspace pods run the image tag or the digest it pointed at
+// +kubebuilder:validation:Enum=Digest;Tag
+type ImagePinningMode string
+
+const (
+	// ImagePinningModeDigest runs workspace pods on the digest the image tag pointed at when first resolved
+	ImagePinningModeDigest ImagePinningMode = "Digest"
+	// ImagePinningModeTag runs workspace pods on the image tag, pulled again by each new pod
+	ImagePinningModeTag ImagePinningMode = "Tag"
+)
+
+// ImagePolicy defines image pull and reference constraints for workspaces using a template
+type ImagePolicy struct {
+	// Mode defines which images workspaces may set: DefaultOnly (spec.image empty or the default image),
+	// AllowList (the default image or an image matching a glob of allowedImages, e.g. quay.io/jupyter/*:2024-*)
+	// or Any. When unset, allowCustomImages and the exact images of allowedImages apply.
+	// Workspaces created before the mode changed keep running; their next spec update is validated against it.
+	// +optional
+	Mode ImagePolicyMode `json:"mode,omitempty"`
+
+	// PullPolicy forces the image pull policy of the workspace container
+	// +kubebuilder:validation:Enum=Always;IfNotPresent;Never
+	// +optional
+	PullPolicy corev1.PullPolicy `json:"pullPolicy,omitempty"`
+
+	// ForbidMutableTags rejects image references without a tag or with the "latest" tag
+	// References pinned by digest are always accepted
+	// +optional
+	ForbidMutableTags bool `json:"forbidMutableTags,omitempty"`
+
+	// RequireDigest rejects image references that are not pinned by digest (e.g. repo/image@sha256:...)
+	// +optional
+	RequireDigest bool `json:"requireDigest,omitempty"`
+}
+
 // WorkspaceTemplateSpec defines the desired state of WorkspaceTemplate
+// +kubebuilder:validation:XValidation:rule="!has(self.imagePolicy) || !has(self.imagePolicy.mode) || self.imagePolicy.mode != 'AllowList' || (has(self.allowedImages) && size(self.allowedImages) > 0)",message="allowedImages must be set when imagePolicy.mode is AllowList"
+// +kubebuilder:validation:XValidation:rule="!has(self.imagePolicy) || !has(self.imagePolicy.mode) || self.imagePolicy.mode == 'Any' || !has(self.allowCustomImages) || !self.allowCustomImages",message="allowCustomImages requires imagePolicy.mode Any when a mode is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.requireStartApproval) || !self.requireStartApproval || (has(self.startApproverGroups) && size(self.startApproverGroups) > 0)",message="startApproverGroups must be set when requireStartApproval is true"
 type WorkspaceTemplateSpec struct {
 	// DisplayName is the human-readable name of this template
 	// +kubebuilder:validation:Required
@@ -24,6 +86,11 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	Description string `json:"description,omitempty"`
 
+	// Deprecated marks the template as scheduled for removal; the template catalog reports it
+	// so that UIs can steer users towards other templates. Existing workspaces are not affected.
+	// +optional
+	Deprecated bool `json:"deprecated,omitempty"`
+
 	// DefaultImage is the default container image for workspaces using this template
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
@@ -32,7 +99,8 @@ type WorkspaceTemplateSpec struct {
 
 	// AllowedImages is a list of container images that can be used with this template
 	// If empty, only DefaultImage is allowed (secure by default)
-	// If populated, workspace can override image with any from this list
+	// If populated, workspace can override image with any from this list; under imagePolicy.mode
+	// AllowList the entries are globs, where * does not match a slash
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
 	AllowedImages []string `json:"allowedImages,omitempty"`
@@ -43,6 +111,18 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	AllowCustomImages *bool `json:"allowCustomImages,omitempty"`
 
+	// ImagePolicy enforces image pull and reference constraints on workspaces using this template
+	// and on the template's own image options (defaultImage and allowedImages)
+	// +optional
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// ImagePinning is Digest to resolve the image tag of workspaces to a digest through the registry API
+	// when the image is first resolved, and run their pods on it, so that restarts do not pick up another
+	// image pushed to a mutable tag. The registry is authenticated with the image pull secrets of the
+	// service account of the workspace pod. Defaults to Tag.
+	// +optional
+	ImagePinning ImagePinningMode `json:"imagePinning,omitempty"`
+
 	// DefaultResources specifies the default resource requirements
 	// +optional
 	DefaultResources *corev1.ResourceRequirements `json:"defaultResources,omitempty"`
@@ -55,27 +135,85 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	PrimaryStorage *StorageConfig `json:"primaryStorage,omitempty"`
 
+	// HomeMountPath is where the home volume of the workspaces is mounted, for images expecting their home
+	// directory elsewhere than /home/jovyan, e.g. /workspace or /home/user. It takes precedence over
+	// primaryStorage.defaultMountPath and over the /home/jovyan default of the workspaces; system paths
+	// such as / or /etc are rejected.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:MaxLength=1024
+	// +optional
+	HomeMountPath string `json:"homeMountPath,omitempty"`
+
+	// HomeSubPath is the directory of the home volume mounted as the home directory, relative to the root
+	// of the volume. It applies to the workspaces getting their storage from the template.
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('/') && !self.split('/').exists(p, p == '..')",message="homeSubPath must be a relative path without '..'"
+	// +optional
+	HomeSubPath string `json:"homeSubPath,omitempty"`
+
 	// DefaultContainerConfig specifies default container command and args configuration
 	// +optional
 	DefaultContainerConfig *ContainerConfig `json:"defaultContainerConfig,omitempty"`
 
+	// AllowCommandOverride lets workspaces set a container command and args, spec.containerConfig, other
+	// than DefaultContainerConfig. Defaults to false. A workspace overriding the command does not inherit
+	// the ServerAdapter of the template, whose readiness probe, activity path and token args describe the
+	// default entrypoint: it gets no readiness probe and no activity detection unless it sets a
+	// spec.serverAdapter matching its own server. The post-start script of the template runs in the
+	// postStart hook whatever the entrypoint.
+	// +kubebuilder:default=false
+	// +optional
+	AllowCommandOverride *bool `json:"allowCommandOverride,omitempty"`
+
 	// BaseEnv specifies environment variables to add to workspaces using this template
 	// Variables are added during defaulting if no variable with the same name exists on the workspace
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
 	BaseEnv []corev1.EnvVar `json:"baseEnv,omitempty"`
 
+	// BaseEnvFrom specifies ConfigMaps and Secrets in the template namespace whose keys are exposed
+	// as environment variables of workspaces using this template.
+	// Sources are added during defaulting; workspaces in other namespaces get a copy mirrored by the controller
+	// +kubebuilder:validation:MaxItems=20
+	// +kubebuilder:validation:XValidation:rule="self.all(e, has(e.configMapRef) != has(e.secretRef))",message="exactly one of configMapRef or secretRef must be set"
+	// +optional
+	BaseEnvFrom []corev1.EnvFromSource `json:"baseEnvFrom,omitempty"`
+
 	// EnvRequirements specifies validation rules for workspace environment variables
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
 	EnvRequirements []EnvRequirement `json:"envRequirements,omitempty"`
 
+	// AllowedEnvPatterns restricts the names of the environment variables workspaces set in spec.env
+	// to those matching one of these globs (e.g. MLFLOW_*), so that variables such as LD_PRELOAD can be blocked.
+	// The names of baseEnv and envRequirements are always allowed. Any name is allowed when empty.
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:MinLength=1
+	// +optional
+	AllowedEnvPatterns []string `json:"allowedEnvPatterns,omitempty"`
+
 	// AllowSecondaryStorages controls whether workspaces using this template
 	// can mount additional storage volumes beyond the primary storage
 	// +kubebuilder:default=true
 	// +optional
 	AllowSecondaryStorages *bool `json:"allowSecondaryStorages,omitempty"`
 
+	// SharedVolumes are existing PVCs mounted in the pods of all workspaces using this template, e.g. a
+	// reference dataset on a ReadWriteMany volume. Workspaces cannot remove them, nor mount another volume
+	// at or under their mount paths.
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	SharedVolumes []SharedVolume `json:"sharedVolumes,omitempty"`
+
+	// AllowedVolumeSources lists the source types workspaces using this template may mount as extraVolumes.
+	// All source types are allowed when unset.
+	// +kubebuilder:validation:MaxItems=3
+	// +kubebuilder:validation:items:Enum=PersistentVolumeClaim;ConfigMap;Secret
+	// +optional
+	AllowedVolumeSources []VolumeSourceType `json:"allowedVolumeSources,omitempty"`
+
 	// DefaultVolumes specifies default additional volumes for workspaces using this template
 	// Volumes are applied during defaulting only if the workspace does not specify any volumes
 	// Each volume references a pre-existing PVC by name in the workspace's namespace
@@ -83,6 +221,10 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultVolumes []VolumeSpec `json:"defaultVolumes,omitempty"`
 
+	// DefaultTmpVolume specifies the /tmp volume of workspaces that do not specify one
+	// +optional
+	DefaultTmpVolume *TmpVolumeSpec `json:"defaultTmpVolume,omitempty"`
+
 	// DefaultNodeSelector specifies default node selection constraints
 	// +optional
 	DefaultNodeSelector map[string]string `json:"defaultNodeSelector,omitempty"`
@@ -95,6 +237,48 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultTolerations []corev1.Toleration `json:"defaultTolerations,omitempty"`
 
+	// DefaultPriorityClassName specifies the PriorityClass of the pods of workspaces that do not set one
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DefaultPriorityClassName string `json:"defaultPriorityClassName,omitempty"`
+
+	// AllowedPriorityClassNames restricts the PriorityClass workspaces may set to these names.
+	// The defaultPriorityClassName is always allowed. Any PriorityClass is allowed when empty.
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=atomic
+	// +optional
+	AllowedPriorityClassNames []string `json:"allowedPriorityClassNames,omitempty"`
+
+	// SchedulingPolicy bounds the node selector and tolerations workspaces using this template may set.
+	// The controller merges the template defaults into the workspace pod, the workspace winning for the
+	// node selector keys and toleration keys both set.
+	// +optional
+	SchedulingPolicy *SchedulingPolicy `json:"schedulingPolicy,omitempty"`
+
+	// RequireStartApproval makes workspaces using this template start Stopped until a member of
+	// StartApproverGroups approves them with the workspace.jupyter.org/start-approved-by annotation.
+	// Removing the approval stops the workspace.
+	// +optional
+	RequireStartApproval bool `json:"requireStartApproval,omitempty"`
+
+	// StartApproverGroups lists the groups whose members may approve workspace starts
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	StartApproverGroups []string `json:"startApproverGroups,omitempty"`
+
+	// StartupCheck runs a command in the workspace container each time a workspace using this template
+	// starts, so that images missing what the template expects fail fast with a clear message
+	// +optional
+	StartupCheck *StartupCheckSpec `json:"startupCheck,omitempty"`
+
+	// StartupTimeoutSeconds is how long the server of a workspace using this template may take to become
+	// ready once its container started. A workspace that does not become ready in time gets the
+	// StartupTimedOut condition and a warning event; it keeps starting.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StartupTimeoutSeconds *int32 `json:"startupTimeoutSeconds,omitempty"`
+
 	// DefaultOwnershipType specifies default ownershipType for workspaces using this template
 	// OwnershipType controls which users may edit/delete the workspace
 	// +kubebuilder:validation:Enum=Public;OwnerOnly
@@ -109,6 +293,18 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	BaseLabels []TemplateLabel `json:"baseLabels,omitempty"`
 
+	// ChildMetadata specifies labels and annotations the controller sets on the resources it generates
+	// for workspaces using this template, e.g. for external-dns, cert-manager or backup tooling.
+	// Changes apply to the resources of existing workspaces.
+	// +optional
+	ChildMetadata *ChildMetadata `json:"childMetadata,omitempty"`
+
+	// PodMetadata specifies labels and annotations of the pods of workspaces using this template, e.g. the
+	// team and cost center read by cost-allocation tooling. They are the defaults of the workspace
+	// podMetadata, whose entries win. Unlike childMetadata, values are used verbatim.
+	// +optional
+	PodMetadata *ChildObjectMetadata `json:"podMetadata,omitempty"`
+
 	// LabelRequirements specifies validation rules for workspace labels
 	// +kubebuilder:validation:MaxItems=50
 	// +optional
@@ -122,6 +318,30 @@ type WorkspaceTemplateSpec struct {
 	// IdleShutdownOverrides controls override behavior and bounds
 	// +optional
 	IdleShutdownOverrides *IdleShutdownOverridePolicy `json:"idleShutdownOverrides,omitempty"`
+
+	// DefaultSchedule specifies the start and stop schedule of workspaces that do not specify one
+	// +optional
+	DefaultSchedule *ScheduleSpec `json:"defaultSchedule,omitempty"`
+
+	// Retention specifies the retention of the workspaces using this template, e.g. the deletion of the
+	// workspaces stopped for long enough. Workspaces can override it.
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+
+	// MaxLifetime is how long workspaces using this template may exist, counted from their creation.
+	// Their owner is warned 48 hours ahead with an event and the ExpiringSoon condition; then the
+	// maxLifetimeAction applies. Administrators exempt a workspace with annotation
+	// workspace.jupyter.org/max-lifetime-exempt=true.
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="maxLifetime must be positive"
+	// +optional
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+
+	// MaxLifetimeAction is what happens to workspaces reaching their maximum lifetime: Stop keeps them
+	// stopped until they are recreated, Delete deletes them with their storage. Workspaces protected
+	// from deletion are stopped instead. Defaults to Stop.
+	// +optional
+	MaxLifetimeAction MaxLifetimeAction `json:"maxLifetimeAction,omitempty"`
+
 	// DefaultAccessType specifies the default accessType for workspaces using this template
 	// AccessType controls which users may create connections to the workspace.
 	// +kubebuilder:validation:Enum=Public;OwnerOnly
@@ -137,6 +357,10 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultLifecycle *corev1.Lifecycle `json:"defaultLifecycle,omitempty"`
 
+	// Lifecycle specifies what the workspace container of every workspace using this template runs when it starts
+	// +optional
+	Lifecycle *TemplateLifecycleSpec `json:"lifecycle,omitempty"`
+
 	// DefaultPodSecurityContext specifies default pod-level security context
 	// +optional
 	DefaultPodSecurityContext *corev1.PodSecurityContext `json:"defaultPodSecurityContext,omitempty"`
@@ -145,9 +369,309 @@ type WorkspaceTemplateSpec struct {
 	// +optional
 	DefaultContainerSecurityContext *corev1.SecurityContext `json:"defaultContainerSecurityContext,omitempty"`
 
+	// IsolationLevel hardens the workspace container against the code it runs. Rootless runs it as a
+	// non-root user with a fixed UID and a read-only root filesystem, /tmp and a home directory without
+	// storage being writable emptyDirs. UserNamespace runs the pod in a user namespace (hostUsers: false),
+	// on Kubernetes 1.30 and later; on older clusters the controller runs it Rootless instead and records
+	// an event. Workspaces cannot set security contexts that weaken the level. Defaults to Standard.
+	// +optional
+	IsolationLevel IsolationLevel `json:"isolationLevel,omitempty"`
+
+	// DefaultServiceMesh specifies default service mesh integration settings for workspaces using this template
+	// +optional
+	DefaultServiceMesh *ServiceMeshSpec `json:"defaultServiceMesh,omitempty"`
+
+	// ClusterAccess grants the code of workspaces using this template credentials for the Kubernetes API.
+	// Without it, workspace pods get no service account token.
+	// +optional
+	ClusterAccess *ClusterAccessSpec `json:"clusterAccess,omitempty"`
+
+	// DefaultServiceAccountName is the ServiceAccount of the pods of workspaces that do not set one, e.g. a
+	// ServiceAccount allowed to run Spark executors. Users still need access to it, see the
+	// service-account-users annotations.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	DefaultServiceAccountName string `json:"defaultServiceAccountName,omitempty"`
+
+	// AllowedServiceAccounts restricts the ServiceAccount workspaces may set to these names, besides the
+	// defaultServiceAccountName. When empty and defaultServiceAccountName is set, only the default is allowed;
+	// when both are empty, any ServiceAccount is.
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=set
+	// +optional
+	AllowedServiceAccounts []string `json:"allowedServiceAccounts,omitempty"`
+
+	// ImagePullSecrets are the Secrets in the workspace namespace the pods of workspaces using this template
+	// pull their images with, merged with those of the workspace
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=atomic
+	// +optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Accelerators lets workspaces using this template request accelerators with spec.gpuCount, and
+	// schedules the pods of those that do on the nodes providing them.
+	// Without it, workspaces cannot request accelerators.
+	// +optional
+	Accelerators *AcceleratorSpec `json:"accelerators,omitempty"`
+
+	// Sidecars are containers the controller adds beside the workspace container of every workspace
+	// pod using this template, e.g. an oauth proxy or a metrics exporter. Workspaces cannot override
+	// them. A sidecar shares the home directory of the workspace by listing a volume mount named
+	// "workspace-storage"; sidecars mount no other volume of the workspace. The template webhook
+	// validates the containers, at most 10.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// SidecarUpdatePolicy is OnRestart to give running workspaces the sidecar changes of the template
+	// when they next start, or Rolling to roll them out to running workspaces at once.
+	// Defaults to OnRestart.
+	// +optional
+	SidecarUpdatePolicy SidecarUpdatePolicy `json:"sidecarUpdatePolicy,omitempty"`
+
+	// SidecarResourceAccounting is Additive to give the sidecars their resources on top of those the workspace
+	// requests, or Carved to take the requests and limits of the sidecars out of those of the workspace
+	// container, so that the pod totals what the user asked for. Defaults to Additive.
+	// +optional
+	SidecarResourceAccounting SidecarResourceAccounting `json:"sidecarResourceAccounting,omitempty"`
+
+	// MinPrimaryContainerResources are, under Carved sidecar resource accounting, the least requests the
+	// workspace container keeps once the sidecar requests are taken out of those of the workspace.
+	// Workspaces leaving less are rejected.
+	// +optional
+	MinPrimaryContainerResources corev1.ResourceList `json:"minPrimaryContainerResources,omitempty"`
+
+	// InitContainers run in order before the workspace container of every workspace pod using this template,
+	// e.g. to download a dataset, after the git repositories of the workspace bootstrap are cloned. Like
+	// sidecars, they may only mount the home directory, as "workspace-storage". Changes apply to running
+	// workspaces when they next start. The template webhook validates the containers, at most 10.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
+	// ServerAdapter describes how to talk to the server of the template images, for images that do not
+	// follow the Jupyter defaults: their port, readiness, activity and shutdown paths, token and base URL
+	// +optional
+	ServerAdapter *ServerAdapterSpec `json:"serverAdapter,omitempty"`
+
+	// ServerTypes are the servers workspaces using this template choose from by name in spec.serverType,
+	// e.g. JupyterLab, the classic Notebook and VS Code. The first entry is the default of workspaces
+	// that do not choose.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	ServerTypes []ServerType `json:"serverTypes,omitempty"`
+
 	// AppType specifies the application type for workspaces using this template
 	// +optional
 	AppType string `json:"appType,omitempty"`
+
+	// ExampleWorkspaces are workspaces the template must keep accepting: a change of the template
+	// that would make an example invalid is rejected. They are offered to users as starter configurations.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	ExampleWorkspaces []TemplateExampleWorkspace `json:"exampleWorkspaces,omitempty"`
+}
+
+// TemplateLifecycleSpec specifies what the workspace containers of a template run in their lifecycle hooks
+type TemplateLifecycleSpec struct {
+	// PostStartScript is a script the workspace container runs in its postStart hook, e.g. to write a
+	// .condarc, a pip index configuration or a git configuration. Without a shebang it runs with sh.
+	// A failing script does not stop the container: the workspace gets a Warning event and a PostStartFailed
+	// condition. A workspace exec postStart hook runs after it. Changes apply to workspaces when they next start.
+	// +optional
+	// +kubebuilder:validation:MaxLength=65536
+	PostStartScript string `json:"postStartScript,omitempty"`
+}
+
+// ServiceAccountTokenMount is how workspace pods get the token of their service account
+// +kubebuilder:validation:Enum=Projected;Legacy
+type ServiceAccountTokenMount string
+
+const (
+	// ServiceAccountTokenMountProjected mounts a projected token bound to an audience, which the kubelet
+	// renews before it expires
+	ServiceAccountTokenMountProjected ServiceAccountTokenMount = "Projected"
+	// ServiceAccountTokenMountLegacy automounts the service account token, as workspace pods did before
+	// cluster access was configurable
+	ServiceAccountTokenMountLegacy ServiceAccountTokenMount = "Legacy"
+)
+
+// IsolationLevel defines how the workspace container is isolated from the node it runs on
+// +kubebuilder:validation:Enum=Standard;Rootless;UserNamespace
+type IsolationLevel string
+
+const (
+	// IsolationLevelStandard runs the workspace container with the security contexts of the workspace
+	IsolationLevelStandard IsolationLevel = "Standard"
+	// IsolationLevelRootless runs the workspace container as a non-root user on a read-only root filesystem
+	IsolationLevelRootless IsolationLevel = "Rootless"
+	// IsolationLevelUserNamespace runs the workspace pod in a user namespace, mapping its users to
+	// unprivileged users of the node
+	IsolationLevelUserNamespace IsolationLevel = "UserNamespace"
+)
+
+// SidecarResourceAccounting defines how the resources of the sidecars count against those the workspace requests
+// +kubebuilder:validation:Enum=Additive;Carved
+type SidecarResourceAccounting string
+
+const (
+	// SidecarResourceAccountingAdditive gives the sidecars their resources on top of those of the workspace
+	SidecarResourceAccountingAdditive SidecarResourceAccounting = "Additive"
+	// SidecarResourceAccountingCarved takes the resources of the sidecars out of those of the workspace container
+	SidecarResourceAccountingCarved SidecarResourceAccounting = "Carved"
+)
+
+// SidecarUpdatePolicy defines when running workspaces take the sidecar changes of their template
+// +kubebuilder:validation:Enum=OnRestart;Rolling
+type SidecarUpdatePolicy string
+
+const (
+	// SidecarUpdatePolicyOnRestart gives a workspace the template sidecars when its pod next starts
+	SidecarUpdatePolicyOnRestart SidecarUpdatePolicy = "OnRestart"
+	// SidecarUpdatePolicyRolling rolls the template sidecars out to running workspaces
+	SidecarUpdatePolicyRolling SidecarUpdatePolicy = "Rolling"
+)
+
+// ClusterAccessSpec configures the service account token of workspace pods
+type ClusterAccessSpec struct {
+	// Enabled mounts a service account token in workspace pods
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// TokenMount is how the token is mounted. Projected tokens are short-lived and audience-bound;
+	// Legacy keeps the automounted token for images that rely on it, and is flagged at admission.
+	// +kubebuilder:default=Projected
+	// +optional
+	TokenMount ServiceAccountTokenMount `json:"tokenMount,omitempty"`
+
+	// Audience is the audience of the projected token. Defaults to the API server audience; other
+	// audiences are rejected by the API server and serve external services.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// ExpirationSeconds is the validity of the projected token
+	// +kubebuilder:validation:Minimum=600
+	// +kubebuilder:validation:Maximum=86400
+	// +kubebuilder:default=3600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+}
+
+// AcceleratorSpec describes the accelerators workspaces may request and the nodes providing them
+type AcceleratorSpec struct {
+	// ResourceName is the extended resource the device plugin of the accelerator advertises
+	// +kubebuilder:default="nvidia.com/gpu"
+	// +optional
+	ResourceName corev1.ResourceName `json:"resourceName,omitempty"`
+
+	// MaxCount is the largest spec.gpuCount a workspace may request
+	// +kubebuilder:validation:Minimum=1
+	MaxCount int32 `json:"maxCount"`
+
+	// NodeSelector is added to the node selector of the pods requesting accelerators, to place them on
+	// the nodes providing them
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the tolerations of the pods requesting accelerators, typically to tolerate
+	// the taints keeping other pods off the accelerator nodes
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// TemplateExampleWorkspace is an example workspace of the template
+type TemplateExampleWorkspace struct {
+	// Name identifies the example
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Description explains what the example is for
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Spec is the spec of the example workspace. The templateRef is implied:
+	// the example is defaulted and validated like a workspace referencing the template.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec WorkspaceSpec `json:"spec"`
+}
+
+// StartupCheckFailurePolicy defines what a failed startup check does to the workspace
+// +kubebuilder:validation:Enum=Warn;Fail
+type StartupCheckFailurePolicy string
+
+const (
+	// StartupCheckFailurePolicyWarn reports the failure and keeps the workspace available
+	StartupCheckFailurePolicyWarn StartupCheckFailurePolicy = "Warn"
+	// StartupCheckFailurePolicyFail marks the workspace degraded and keeps it unavailable until it restarts
+	StartupCheckFailurePolicyFail StartupCheckFailurePolicy = "Fail"
+)
+
+// StartupCheckSpec defines a command validating the workspace environment after the workspace starts,
+// e.g. `python -c "import torch; assert torch.cuda.is_available()"`
+type StartupCheckSpec struct {
+	// Command is run in the workspace container, not in a shell; a non-zero exit code fails the check
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=50
+	Command []string `json:"command"`
+
+	// TimeoutSeconds bounds the command; a command still running when it expires fails the check.
+	// The check runs within a workspace reconcile, which leaves it at most a minute
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +kubebuilder:default=30
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// FailurePolicy is Warn to report a failed check and keep the workspace available,
+	// or Fail to mark the workspace degraded and keep it unavailable
+	// +kubebuilder:default=Warn
+	// +optional
+	FailurePolicy StartupCheckFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// ServerType is a server workspaces of a template may run
+type ServerType struct {
+	// Name is what workspaces select the server type by
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// Image runs the server, the default image of workspaces selecting the server type.
+	// Defaults to the default image of the template.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Preset is the built-in server adapter of the server, for workspaces without a server adapter
+	// +optional
+	Preset ServerAdapterPreset `json:"preset,omitempty"`
+
+	// Port is the port the server listens on, overriding the port of the server adapter
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+
+	// ReadinessPath is the path the readiness probe of the server requests under its base URL,
+	// overriding the readiness path of the server adapter
+	// +optional
+	ReadinessPath string `json:"readinessPath,omitempty"`
+
+	// Args are passed to the server after the args of the container config of the workspace
+	// +optional
+	// +kubebuilder:validation:MaxItems=50
+	Args []string `json:"args,omitempty"`
 }
 
 // TemplateLabel defines a label key-value pair to add to workspaces
@@ -162,6 +686,47 @@ type TemplateLabel struct {
 	Value string `json:"value"`
 }
 
+// ChildMetadata defines the labels and annotations set on the resources generated for a workspace, per kind.
+// Values may reference ${workspace.name}, ${workspace.namespace} and ${workspace.owner}.
+// Keys with the reserved prefix workspace.jupyter.org/ and keys set by the controller are left untouched.
+type ChildMetadata struct {
+	// Pod specifies the metadata of the workspace pod
+	// +optional
+	Pod *ChildObjectMetadata `json:"pod,omitempty"`
+
+	// Service specifies the metadata of the workspace service
+	// +optional
+	Service *ChildObjectMetadata `json:"service,omitempty"`
+
+	// Ingress specifies the metadata of the access resources routing traffic to the workspace
+	// (Ingress, IngressRoute and HTTPRoute kinds)
+	// +optional
+	Ingress *ChildObjectMetadata `json:"ingress,omitempty"`
+
+	// PVC specifies the metadata of the workspace primary storage PersistentVolumeClaim
+	// +optional
+	PVC *ChildObjectMetadata `json:"pvc,omitempty"`
+
+	// Secret specifies the metadata of the Secrets the controller copies into the workspace namespace
+	// +optional
+	Secret *ChildObjectMetadata `json:"secret,omitempty"`
+}
+
+// ChildObjectMetadata defines the labels and annotations of a generated resource
+type ChildObjectMetadata struct {
+	// Labels to set on the resource
+	// +kubebuilder:validation:MaxProperties=20
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('workspace.jupyter.org/'))",message="labels cannot use reserved prefix workspace.jupyter.org/"
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations to set on the resource
+	// +kubebuilder:validation:MaxProperties=20
+	// +kubebuilder:validation:XValidation:rule="self.all(k, !k.startsWith('workspace.jupyter.org/'))",message="annotations cannot use reserved prefix workspace.jupyter.org/"
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // LabelRequirement defines a validation rule for a workspace label
 type LabelRequirement struct {
 	// Key is the label key to validate
@@ -236,7 +801,17 @@ type ResourceRange struct {
 // StorageConfig defines storage settings
 // NOTE: CEL validation for minSize <= maxSize is not possible due to resource.Quantity type limitations
 // Validation is enforced at runtime in the template resolver
+// +kubebuilder:validation:XValidation:rule="!has(self.allowedStorageClassNames) || (has(self.defaultStorageClassName) && self.defaultStorageClassName in self.allowedStorageClassNames)",message="defaultStorageClassName must be set and be one of allowedStorageClassNames"
 type StorageConfig struct {
+	// Mode is the storage mode of the workspaces using this template, Persistent when empty. Ephemeral
+	// workspaces get an emptyDir home volume, bounded by their storage size, instead of a PVC.
+	// +optional
+	Mode StorageMode `json:"mode,omitempty"`
+
+	// AllowModeOverride lets workspaces set another storage mode than the one of the template
+	// +optional
+	AllowModeOverride bool `json:"allowModeOverride,omitempty"`
+
 	// DefaultSize is the default storage size
 	// +kubebuilder:default="10Gi"
 	// +optional
@@ -254,12 +829,78 @@ type StorageConfig struct {
 	// +optional
 	DefaultStorageClassName *string `json:"defaultStorageClassName,omitempty"`
 
+	// AllowedStorageClassNames restricts the storage classes of the workspace PVCs. Workspaces may use any
+	// storage class when empty.
+	// +kubebuilder:validation:MaxItems=20
+	// +listType=set
+	// +optional
+	AllowedStorageClassNames []string `json:"allowedStorageClassNames,omitempty"`
+
 	// DefaultMountPath is the default mount path for the storage
 	// +kubebuilder:default="/home/jovyan"
 	// +optional
 	DefaultMountPath string `json:"defaultMountPath,omitempty"`
 }
 
+// SharedVolume is a PVC the template mounts in every workspace pod. A pod only mounts PVCs of its own
+// namespace: for workspaces in other namespaces than the template, bind a PVC of that name in each of
+// their namespaces to a PersistentVolume of the shared volume, e.g. a static PersistentVolume per
+// namespace with the volumeHandle of the RWX volume, or the same NFS server and path.
+type SharedVolume struct {
+	// Name is the name of the volume in the workspace pod
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// PersistentVolumeClaimName is the PVC of the workspace namespace to mount; workspaces do not start
+	// while it is missing
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	PersistentVolumeClaimName string `json:"persistentVolumeClaimName"`
+
+	// MountPath is the absolute path where the volume is mounted in the workspace container
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:MaxLength=1024
+	MountPath string `json:"mountPath"`
+
+	// ReadOnly mounts the volume read-only
+	// +kubebuilder:default=true
+	// +optional
+	ReadOnly *bool `json:"readOnly,omitempty"`
+}
+
+// SchedulingPolicy defines the scheduling constraints workspaces may set
+type SchedulingPolicy struct {
+	// AllowedNodeSelectors restricts the node selector entries of workspaces to those matching one of
+	// these key and value globs (e.g. pool=highmem, or pool=* for any pool). While set, workspaces may not
+	// set a node affinity other than the template default either. The entries of defaultNodeSelector are
+	// always allowed. Any entry is allowed when empty.
+	// +kubebuilder:validation:MaxItems=50
+	// +listType=atomic
+	// +optional
+	AllowedNodeSelectors []NodeSelectorPattern `json:"allowedNodeSelectors,omitempty"`
+
+	// AllowedTolerationKeys restricts the keys of the tolerations of workspaces to those matching one of
+	// these globs (e.g. gpu-*). A toleration without key tolerates every taint, and is only allowed by *.
+	// The defaultTolerations are always allowed. Any toleration is allowed when empty.
+	// +kubebuilder:validation:MaxItems=50
+	// +kubebuilder:validation:items:MinLength=1
+	// +listType=atomic
+	// +optional
+	AllowedTolerationKeys []string `json:"allowedTolerationKeys,omitempty"`
+}
+
+// NodeSelectorPattern is a node selector entry workspaces may set, as globs of its key and value
+type NodeSelectorPattern struct {
+	// Key is the glob the node selector key matches
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+
+	// Value is the glob the node selector value matches
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
 // IdleShutdownOverridePolicy defines idle shutdown override constraints
 type IdleShutdownOverridePolicy struct {
 	// Allow controls whether workspaces can override idle shutdown
@@ -284,13 +925,21 @@ type WorkspaceTemplateStatus struct {
 	// When metadata.generation != status.observedGeneration, the controller has not yet processed the latest spec.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// WorkspaceCount is the number of workspaces using the template, as last counted by the controller.
+	// Deleting a template used by workspaces requires the workspace.jupyter.org/confirm-delete
+	// annotation set to this count.
+	// +optional
+	WorkspaceCount int32 `json:"workspaceCount,omitempty"`
 }
 
+// +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Display Name",type="string",JSONPath=".spec.displayName"
 // +kubebuilder:printcolumn:name="Default Image",type="string",JSONPath=".spec.defaultImage"
+// +kubebuilder:printcolumn:name="Workspaces",type="integer",JSONPath=".status.workspaceCount"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // WorkspaceTemplate is the Schema for the workspacetemplates API
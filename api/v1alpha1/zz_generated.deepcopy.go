@@ -10,11 +10,40 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AcceleratorSpec) DeepCopyInto(out *AcceleratorSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AcceleratorSpec.
+func (in *AcceleratorSpec) DeepCopy() *AcceleratorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AcceleratorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccessEnvTemplate) DeepCopyInto(out *AccessEnvTemplate) {
 	*out = *in
@@ -76,389 +105,1263 @@ func (in *AccessStrategyRef) DeepCopy() *AccessStrategyRef {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
+func (in *BootstrapSpec) DeepCopyInto(out *BootstrapSpec) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
+	if in.GitRepositories != nil {
+		in, out := &in.GitRepositories, &out.GitRepositories
+		*out = make([]GitRepositorySpec, len(*in))
 		copy(*out, *in)
 	}
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfig.
-func (in *ContainerConfig) DeepCopy() *ContainerConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapSpec.
+func (in *BootstrapSpec) DeepCopy() *BootstrapSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ContainerConfig)
+	out := new(BootstrapSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DeploymentModifications) DeepCopyInto(out *DeploymentModifications) {
+func (in *ChildEventStatus) DeepCopyInto(out *ChildEventStatus) {
 	*out = *in
-	if in.PodModifications != nil {
-		in, out := &in.PodModifications, &out.PodModifications
-		*out = new(PodModifications)
-		(*in).DeepCopyInto(*out)
+	in.FirstTimestamp.DeepCopyInto(&out.FirstTimestamp)
+	in.LastTimestamp.DeepCopyInto(&out.LastTimestamp)
+	if in.MirroredTimestamp != nil {
+		in, out := &in.MirroredTimestamp, &out.MirroredTimestamp
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentModifications.
-func (in *DeploymentModifications) DeepCopy() *DeploymentModifications {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildEventStatus.
+func (in *ChildEventStatus) DeepCopy() *ChildEventStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(DeploymentModifications)
+	out := new(ChildEventStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *EnvRequirement) DeepCopyInto(out *EnvRequirement) {
+func (in *ChildMetadata) DeepCopyInto(out *ChildMetadata) {
 	*out = *in
-	if in.Required != nil {
-		in, out := &in.Required, &out.Required
-		*out = new(bool)
-		**out = **in
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Service != nil {
+		in, out := &in.Service, &out.Service
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvRequirement.
-func (in *EnvRequirement) DeepCopy() *EnvRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildMetadata.
+func (in *ChildMetadata) DeepCopy() *ChildMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(EnvRequirement)
+	out := new(ChildMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdleDetectionSpec) DeepCopyInto(out *IdleDetectionSpec) {
+func (in *ChildObjectMetadata) DeepCopyInto(out *ChildObjectMetadata) {
 	*out = *in
-	if in.HTTPGet != nil {
-		in, out := &in.HTTPGet, &out.HTTPGet
-		*out = new(v1.HTTPGetAction)
-		(*in).DeepCopyInto(*out)
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleDetectionSpec.
-func (in *IdleDetectionSpec) DeepCopy() *IdleDetectionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChildObjectMetadata.
+func (in *ChildObjectMetadata) DeepCopy() *ChildObjectMetadata {
 	if in == nil {
 		return nil
 	}
-	out := new(IdleDetectionSpec)
+	out := new(ChildObjectMetadata)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdleShutdownOverridePolicy) DeepCopyInto(out *IdleShutdownOverridePolicy) {
+func (in *CleanupHookStatus) DeepCopyInto(out *CleanupHookStatus) {
 	*out = *in
-	if in.Allow != nil {
-		in, out := &in.Allow, &out.Allow
-		*out = new(bool)
-		**out = **in
-	}
-	if in.MinIdleTimeoutInMinutes != nil {
-		in, out := &in.MinIdleTimeoutInMinutes, &out.MinIdleTimeoutInMinutes
-		*out = new(int)
-		**out = **in
-	}
-	if in.MaxIdleTimeoutInMinutes != nil {
-		in, out := &in.MaxIdleTimeoutInMinutes, &out.MaxIdleTimeoutInMinutes
-		*out = new(int)
-		**out = **in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleShutdownOverridePolicy.
-func (in *IdleShutdownOverridePolicy) DeepCopy() *IdleShutdownOverridePolicy {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CleanupHookStatus.
+func (in *CleanupHookStatus) DeepCopy() *CleanupHookStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IdleShutdownOverridePolicy)
+	out := new(CleanupHookStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdleShutdownSpec) DeepCopyInto(out *IdleShutdownSpec) {
+func (in *CloneSpec) DeepCopyInto(out *CloneSpec) {
 	*out = *in
-	in.Detection.DeepCopyInto(&out.Detection)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleShutdownSpec.
-func (in *IdleShutdownSpec) DeepCopy() *IdleShutdownSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSpec.
+func (in *CloneSpec) DeepCopy() *CloneSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IdleShutdownSpec)
+	out := new(CloneSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LabelRequirement) DeepCopyInto(out *LabelRequirement) {
+func (in *ClusterAccessSpec) DeepCopyInto(out *ClusterAccessSpec) {
 	*out = *in
-	if in.Required != nil {
-		in, out := &in.Required, &out.Required
-		*out = new(bool)
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelRequirement.
-func (in *LabelRequirement) DeepCopy() *LabelRequirement {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAccessSpec.
+func (in *ClusterAccessSpec) DeepCopy() *ClusterAccessSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LabelRequirement)
+	out := new(ClusterAccessSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodModifications) DeepCopyInto(out *PodModifications) {
+func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
 	*out = *in
-	if in.AdditionalContainers != nil {
-		in, out := &in.AdditionalContainers, &out.AdditionalContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Volumes != nil {
-		in, out := &in.Volumes, &out.Volumes
-		*out = make([]v1.Volume, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.InitContainers != nil {
-		in, out := &in.InitContainers, &out.InitContainers
-		*out = make([]v1.Container, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.PrimaryContainerModifications != nil {
-		in, out := &in.PrimaryContainerModifications, &out.PrimaryContainerModifications
-		*out = new(PrimaryContainerModifications)
-		(*in).DeepCopyInto(*out)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodModifications.
-func (in *PodModifications) DeepCopy() *PodModifications {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerConfig.
+func (in *ContainerConfig) DeepCopy() *ContainerConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(PodModifications)
+	out := new(ContainerConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PrimaryContainerModifications) DeepCopyInto(out *PrimaryContainerModifications) {
+func (in *CullingStatus) DeepCopyInto(out *CullingStatus) {
 	*out = *in
-	if in.VolumeMounts != nil {
-		in, out := &in.VolumeMounts, &out.VolumeMounts
-		*out = make([]v1.VolumeMount, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	in.EvaluatedAt.DeepCopyInto(&out.EvaluatedAt)
+	if in.LastActivity != nil {
+		in, out := &in.LastActivity, &out.LastActivity
+		*out = (*in).DeepCopy()
+	}
+	if in.Kernels != nil {
+		in, out := &in.Kernels, &out.Kernels
+		*out = new(int32)
+		**out = **in
 	}
-	if in.MergeEnv != nil {
-		in, out := &in.MergeEnv, &out.MergeEnv
-		*out = make([]AccessEnvTemplate, len(*in))
-		copy(*out, *in)
+	if in.NeverConnectedTimeoutInMinutes != nil {
+		in, out := &in.NeverConnectedTimeoutInMinutes, &out.NeverConnectedTimeoutInMinutes
+		*out = new(int)
+		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryContainerModifications.
-func (in *PrimaryContainerModifications) DeepCopy() *PrimaryContainerModifications {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CullingStatus.
+func (in *CullingStatus) DeepCopy() *CullingStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(PrimaryContainerModifications)
+	out := new(CullingStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+func (in *DeploymentModifications) DeepCopyInto(out *DeploymentModifications) {
 	*out = *in
-	if in.Resources != nil {
-		in, out := &in.Resources, &out.Resources
-		*out = make(map[v1.ResourceName]ResourceRange, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.PodModifications != nil {
+		in, out := &in.PodModifications, &out.PodModifications
+		*out = new(PodModifications)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
-func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeploymentModifications.
+func (in *DeploymentModifications) DeepCopy() *DeploymentModifications {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceBounds)
+	out := new(DeploymentModifications)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceRange) DeepCopyInto(out *ResourceRange) {
+func (in *DesiredStatusIntent) DeepCopyInto(out *DesiredStatusIntent) {
 	*out = *in
-	out.Min = in.Min.DeepCopy()
-	out.Max = in.Max.DeepCopy()
+	if in.SetAt != nil {
+		in, out := &in.SetAt, &out.SetAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRange.
-func (in *ResourceRange) DeepCopy() *ResourceRange {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DesiredStatusIntent.
+func (in *DesiredStatusIntent) DeepCopy() *DesiredStatusIntent {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceRange)
+	out := new(DesiredStatusIntent)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+func (in *EffectiveDeleteAfterStoppedPolicy) DeepCopyInto(out *EffectiveDeleteAfterStoppedPolicy) {
 	*out = *in
-	out.DefaultSize = in.DefaultSize.DeepCopy()
-	if in.MinSize != nil {
-		in, out := &in.MinSize, &out.MinSize
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.MaxSize != nil {
-		in, out := &in.MaxSize, &out.MaxSize
-		x := (*in).DeepCopy()
-		*out = &x
-	}
-	if in.DefaultStorageClassName != nil {
-		in, out := &in.DefaultStorageClassName, &out.DefaultStorageClassName
-		*out = new(string)
-		**out = **in
-	}
+	out.After = in.After
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
-func (in *StorageConfig) DeepCopy() *StorageConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveDeleteAfterStoppedPolicy.
+func (in *EffectiveDeleteAfterStoppedPolicy) DeepCopy() *EffectiveDeleteAfterStoppedPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageConfig)
+	out := new(EffectiveDeleteAfterStoppedPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+func (in *EffectiveIdleShutdownPolicy) DeepCopyInto(out *EffectiveIdleShutdownPolicy) {
 	*out = *in
-	if in.StorageClassName != nil {
-		in, out := &in.StorageClassName, &out.StorageClassName
-		*out = new(string)
+	if in.NeverConnectedTimeoutInMinutes != nil {
+		in, out := &in.NeverConnectedTimeoutInMinutes, &out.NeverConnectedTimeoutInMinutes
+		*out = new(int)
 		**out = **in
 	}
-	out.Size = in.Size.DeepCopy()
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
-func (in *StorageSpec) DeepCopy() *StorageSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveIdleShutdownPolicy.
+func (in *EffectiveIdleShutdownPolicy) DeepCopy() *EffectiveIdleShutdownPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(StorageSpec)
+	out := new(EffectiveIdleShutdownPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TemplateLabel) DeepCopyInto(out *TemplateLabel) {
+func (in *EffectiveMaxLifetimePolicy) DeepCopyInto(out *EffectiveMaxLifetimePolicy) {
 	*out = *in
+	out.MaxLifetime = in.MaxLifetime
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLabel.
-func (in *TemplateLabel) DeepCopy() *TemplateLabel {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveMaxLifetimePolicy.
+func (in *EffectiveMaxLifetimePolicy) DeepCopy() *EffectiveMaxLifetimePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(TemplateLabel)
+	out := new(EffectiveMaxLifetimePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TemplateRef) DeepCopyInto(out *TemplateRef) {
+func (in *EffectivePolicies) DeepCopyInto(out *EffectivePolicies) {
 	*out = *in
+	if in.IdleShutdown != nil {
+		in, out := &in.IdleShutdown, &out.IdleShutdown
+		*out = new(EffectiveIdleShutdownPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(EffectiveSchedulePolicy)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(EffectiveRetentionPolicy)
+		**out = **in
+	}
+	if in.DeleteAfterStopped != nil {
+		in, out := &in.DeleteAfterStopped, &out.DeleteAfterStopped
+		*out = new(EffectiveDeleteAfterStoppedPolicy)
+		**out = **in
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(EffectiveMaxLifetimePolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRef.
-func (in *TemplateRef) DeepCopy() *TemplateRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectivePolicies.
+func (in *EffectivePolicies) DeepCopy() *EffectivePolicies {
 	if in == nil {
 		return nil
 	}
-	out := new(TemplateRef)
+	out := new(EffectivePolicies)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+func (in *EffectiveRetentionPolicy) DeepCopyInto(out *EffectiveRetentionPolicy) {
 	*out = *in
+	out.StaleAfter = in.StaleAfter
+	out.ArchiveGracePeriod = in.ArchiveGracePeriod
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
-func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveRetentionPolicy.
+func (in *EffectiveRetentionPolicy) DeepCopy() *EffectiveRetentionPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(VolumeSpec)
+	out := new(EffectiveRetentionPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Workspace) DeepCopyInto(out *Workspace) {
+func (in *EffectiveSchedulePolicy) DeepCopyInto(out *EffectiveSchedulePolicy) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
-func (in *Workspace) DeepCopy() *Workspace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveSchedulePolicy.
+func (in *EffectiveSchedulePolicy) DeepCopy() *EffectiveSchedulePolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(Workspace)
+	out := new(EffectiveSchedulePolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Workspace) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategy) DeepCopyInto(out *WorkspaceAccessStrategy) {
+func (in *EnvFromMirrorStatus) DeepCopyInto(out *EnvFromMirrorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvFromMirrorStatus.
+func (in *EnvFromMirrorStatus) DeepCopy() *EnvFromMirrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvFromMirrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvFromSource) DeepCopyInto(out *EnvFromSource) {
+	*out = *in
+	in.EnvFromSource.DeepCopyInto(&out.EnvFromSource)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvFromSource.
+func (in *EnvFromSource) DeepCopy() *EnvFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EnvRequirement) DeepCopyInto(out *EnvRequirement) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvRequirement.
+func (in *EnvRequirement) DeepCopy() *EnvRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtraVolumeSpec) DeepCopyInto(out *ExtraVolumeSpec) {
+	*out = *in
+	if in.DefaultMode != nil {
+		in, out := &in.DefaultMode, &out.DefaultMode
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtraVolumeSpec.
+func (in *ExtraVolumeSpec) DeepCopy() *ExtraVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtraVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepositorySpec) DeepCopyInto(out *GitRepositorySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepositorySpec.
+func (in *GitRepositorySpec) DeepCopy() *GitRepositorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepositorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HibernationStatus) DeepCopyInto(out *HibernationStatus) {
+	*out = *in
+	if in.HibernatedAt != nil {
+		in, out := &in.HibernatedAt, &out.HibernatedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HibernationStatus.
+func (in *HibernationStatus) DeepCopy() *HibernationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HibernationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleDetectionSpec) DeepCopyInto(out *IdleDetectionSpec) {
+	*out = *in
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleDetectionSpec.
+func (in *IdleDetectionSpec) DeepCopy() *IdleDetectionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleDetectionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleShutdownOverridePolicy) DeepCopyInto(out *IdleShutdownOverridePolicy) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MinIdleTimeoutInMinutes != nil {
+		in, out := &in.MinIdleTimeoutInMinutes, &out.MinIdleTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxIdleTimeoutInMinutes != nil {
+		in, out := &in.MaxIdleTimeoutInMinutes, &out.MaxIdleTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleShutdownOverridePolicy.
+func (in *IdleShutdownOverridePolicy) DeepCopy() *IdleShutdownOverridePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleShutdownOverridePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdleShutdownSpec) DeepCopyInto(out *IdleShutdownSpec) {
+	*out = *in
+	if in.NeverConnectedTimeoutInMinutes != nil {
+		in, out := &in.NeverConnectedTimeoutInMinutes, &out.NeverConnectedTimeoutInMinutes
+		*out = new(int)
+		**out = **in
+	}
+	in.Detection.DeepCopyInto(&out.Detection)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdleShutdownSpec.
+func (in *IdleShutdownSpec) DeepCopy() *IdleShutdownSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IdleShutdownSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePolicy) DeepCopyInto(out *ImagePolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePolicy.
+func (in *ImagePolicy) DeepCopy() *ImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LabelRequirement) DeepCopyInto(out *LabelRequirement) {
+	*out = *in
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LabelRequirement.
+func (in *LabelRequirement) DeepCopy() *LabelRequirement {
+	if in == nil {
+		return nil
+	}
+	out := new(LabelRequirement)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceQuotaUsage) DeepCopyInto(out *NamespaceQuotaUsage) {
+	*out = *in
+	if in.Hard != nil {
+		in, out := &in.Hard, &out.Hard
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceQuotaUsage.
+func (in *NamespaceQuotaUsage) DeepCopy() *NamespaceQuotaUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceQuotaUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeSelectorPattern) DeepCopyInto(out *NodeSelectorPattern) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSelectorPattern.
+func (in *NodeSelectorPattern) DeepCopy() *NodeSelectorPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeSelectorPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodModifications) DeepCopyInto(out *PodModifications) {
+	*out = *in
+	if in.AdditionalContainers != nil {
+		in, out := &in.AdditionalContainers, &out.AdditionalContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OptionalContainers != nil {
+		in, out := &in.OptionalContainers, &out.OptionalContainers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrimaryContainerModifications != nil {
+		in, out := &in.PrimaryContainerModifications, &out.PrimaryContainerModifications
+		*out = new(PrimaryContainerModifications)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodModifications.
+func (in *PodModifications) DeepCopy() *PodModifications {
+	if in == nil {
+		return nil
+	}
+	out := new(PodModifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryContainerModifications) DeepCopyInto(out *PrimaryContainerModifications) {
+	*out = *in
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MergeEnv != nil {
+		in, out := &in.MergeEnv, &out.MergeEnv
+		*out = make([]AccessEnvTemplate, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryContainerModifications.
+func (in *PrimaryContainerModifications) DeepCopy() *PrimaryContainerModifications {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryContainerModifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedTemplateStatus) DeepCopyInto(out *ResolvedTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedTemplateStatus.
+func (in *ResolvedTemplateStatus) DeepCopy() *ResolvedTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceBounds) DeepCopyInto(out *ResourceBounds) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[corev1.ResourceName]ResourceRange, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceBounds.
+func (in *ResourceBounds) DeepCopy() *ResourceBounds {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceBounds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRange) DeepCopyInto(out *ResourceRange) {
+	*out = *in
+	out.Min = in.Min.DeepCopy()
+	out.Max = in.Max.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRange.
+func (in *ResourceRange) DeepCopy() *ResourceRange {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	out.Usage = in.Usage.DeepCopy()
+	out.Request = in.Request.DeepCopy()
+	out.Limit = in.Limit.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendations) DeepCopyInto(out *ResourceRecommendations) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourceRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourceRecommendation)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ObservedSince.DeepCopyInto(&out.ObservedSince)
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendations.
+func (in *ResourceRecommendations) DeepCopy() *ResourceRecommendations {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionSpec) DeepCopyInto(out *RetentionSpec) {
+	*out = *in
+	if in.DeleteAfterStoppedSeconds != nil {
+		in, out := &in.DeleteAfterStoppedSeconds, &out.DeleteAfterStoppedSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionSpec.
+func (in *RetentionSpec) DeepCopy() *RetentionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleSpec) DeepCopyInto(out *ScheduleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleSpec.
+func (in *ScheduleSpec) DeepCopy() *ScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingDefaults) DeepCopyInto(out *SchedulingDefaults) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingDefaults.
+func (in *SchedulingDefaults) DeepCopy() *SchedulingDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulingPolicy) DeepCopyInto(out *SchedulingPolicy) {
+	*out = *in
+	if in.AllowedNodeSelectors != nil {
+		in, out := &in.AllowedNodeSelectors, &out.AllowedNodeSelectors
+		*out = make([]NodeSelectorPattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTolerationKeys != nil {
+		in, out := &in.AllowedTolerationKeys, &out.AllowedTolerationKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulingPolicy.
+func (in *SchedulingPolicy) DeepCopy() *SchedulingPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulingPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerAdapterSpec) DeepCopyInto(out *ServerAdapterSpec) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(ServerTokenSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerAdapterSpec.
+func (in *ServerAdapterSpec) DeepCopy() *ServerAdapterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerAdapterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerTokenSpec) DeepCopyInto(out *ServerTokenSpec) {
+	*out = *in
+	in.SecretKeyRef.DeepCopyInto(&out.SecretKeyRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerTokenSpec.
+func (in *ServerTokenSpec) DeepCopy() *ServerTokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerTokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServerType) DeepCopyInto(out *ServerType) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServerType.
+func (in *ServerType) DeepCopy() *ServerType {
+	if in == nil {
+		return nil
+	}
+	out := new(ServerType)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMeshSpec) DeepCopyInto(out *ServiceMeshSpec) {
+	*out = *in
+	if in.SidecarInjection != nil {
+		in, out := &in.SidecarInjection, &out.SidecarInjection
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMeshSpec.
+func (in *ServiceMeshSpec) DeepCopy() *ServiceMeshSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMeshSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SharedVolume) DeepCopyInto(out *SharedVolume) {
+	*out = *in
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SharedVolume.
+func (in *SharedVolume) DeepCopy() *SharedVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(SharedVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupCheckSpec) DeepCopyInto(out *StartupCheckSpec) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupCheckSpec.
+func (in *StartupCheckSpec) DeepCopy() *StartupCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupMilestone) DeepCopyInto(out *StartupMilestone) {
+	*out = *in
+	in.ReachedAt.DeepCopyInto(&out.ReachedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupMilestone.
+func (in *StartupMilestone) DeepCopy() *StartupMilestone {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupMilestone)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StartupProgress) DeepCopyInto(out *StartupProgress) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+	if in.Milestones != nil {
+		in, out := &in.Milestones, &out.Milestones
+		*out = make([]StartupMilestone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StartupProgress.
+func (in *StartupProgress) DeepCopy() *StartupProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(StartupProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
+	*out = *in
+	out.DefaultSize = in.DefaultSize.DeepCopy()
+	if in.MinSize != nil {
+		in, out := &in.MinSize, &out.MinSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.DefaultStorageClassName != nil {
+		in, out := &in.DefaultStorageClassName, &out.DefaultStorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.AllowedStorageClassNames != nil {
+		in, out := &in.AllowedStorageClassNames, &out.AllowedStorageClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfig.
+func (in *StorageConfig) DeepCopy() *StorageConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageSpec.
+func (in *StorageSpec) DeepCopy() *StorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateExampleWorkspace) DeepCopyInto(out *TemplateExampleWorkspace) {
+	*out = *in
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateExampleWorkspace.
+func (in *TemplateExampleWorkspace) DeepCopy() *TemplateExampleWorkspace {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateExampleWorkspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLabel) DeepCopyInto(out *TemplateLabel) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLabel.
+func (in *TemplateLabel) DeepCopy() *TemplateLabel {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLabel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateLifecycleSpec) DeepCopyInto(out *TemplateLifecycleSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateLifecycleSpec.
+func (in *TemplateLifecycleSpec) DeepCopy() *TemplateLifecycleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateLifecycleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateRef) DeepCopyInto(out *TemplateRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateRef.
+func (in *TemplateRef) DeepCopy() *TemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TmpVolumeSpec) DeepCopyInto(out *TmpVolumeSpec) {
+	*out = *in
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TmpVolumeSpec.
+func (in *TmpVolumeSpec) DeepCopy() *TmpVolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TmpVolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VolumeSpec) DeepCopyInto(out *VolumeSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VolumeSpec.
+func (in *VolumeSpec) DeepCopy() *VolumeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VolumeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workspace) DeepCopyInto(out *Workspace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workspace.
+func (in *Workspace) DeepCopy() *Workspace {
+	if in == nil {
+		return nil
+	}
+	out := new(Workspace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Workspace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategy) DeepCopyInto(out *WorkspaceAccessStrategy) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -503,13 +1406,185 @@ func (in *WorkspaceAccessStrategyList) DeepCopy() *WorkspaceAccessStrategyList {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategyList)
+	out := new(WorkspaceAccessStrategyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategySpec) DeepCopyInto(out *WorkspaceAccessStrategySpec) {
+	*out = *in
+	if in.AccessResourceTemplates != nil {
+		in, out := &in.AccessResourceTemplates, &out.AccessResourceTemplates
+		*out = make([]AccessResourceTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.CreateConnectionHandlerMap != nil {
+		in, out := &in.CreateConnectionHandlerMap, &out.CreateConnectionHandlerMap
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.CreateConnectionContext != nil {
+		in, out := &in.CreateConnectionContext, &out.CreateConnectionContext
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PodEventsContext != nil {
+		in, out := &in.PodEventsContext, &out.PodEventsContext
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeploymentModifications != nil {
+		in, out := &in.DeploymentModifications, &out.DeploymentModifications
+		*out = new(DeploymentModifications)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategySpec.
+func (in *WorkspaceAccessStrategySpec) DeepCopy() *WorkspaceAccessStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceAccessStrategyStatus) DeepCopyInto(out *WorkspaceAccessStrategyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyStatus.
+func (in *WorkspaceAccessStrategyStatus) DeepCopy() *WorkspaceAccessStrategyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceAccessStrategyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceCollaborator) DeepCopyInto(out *WorkspaceCollaborator) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceCollaborator.
+func (in *WorkspaceCollaborator) DeepCopy() *WorkspaceCollaborator {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceCollaborator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceConnectionStatus) DeepCopyInto(out *WorkspaceConnectionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceConnectionStatus.
+func (in *WorkspaceConnectionStatus) DeepCopy() *WorkspaceConnectionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceConnectionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceHistoryEntry) DeepCopyInto(out *WorkspaceHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceHistoryEntry.
+func (in *WorkspaceHistoryEntry) DeepCopy() *WorkspaceHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Workspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceList.
+func (in *WorkspaceList) DeepCopy() *WorkspaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceNamespaceStatus) DeepCopyInto(out *WorkspaceNamespaceStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceNamespaceStatus.
+func (in *WorkspaceNamespaceStatus) DeepCopy() *WorkspaceNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceNamespaceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
+func (in *WorkspaceNamespaceStatus) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -517,131 +1592,152 @@ func (in *WorkspaceAccessStrategyList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategySpec) DeepCopyInto(out *WorkspaceAccessStrategySpec) {
+func (in *WorkspaceNamespaceStatusList) DeepCopyInto(out *WorkspaceNamespaceStatusList) {
 	*out = *in
-	if in.AccessResourceTemplates != nil {
-		in, out := &in.AccessResourceTemplates, &out.AccessResourceTemplates
-		*out = make([]AccessResourceTemplate, len(*in))
-		copy(*out, *in)
-	}
-	if in.CreateConnectionHandlerMap != nil {
-		in, out := &in.CreateConnectionHandlerMap, &out.CreateConnectionHandlerMap
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.CreateConnectionContext != nil {
-		in, out := &in.CreateConnectionContext, &out.CreateConnectionContext
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.PodEventsContext != nil {
-		in, out := &in.PodEventsContext, &out.PodEventsContext
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WorkspaceNamespaceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.DeploymentModifications != nil {
-		in, out := &in.DeploymentModifications, &out.DeploymentModifications
-		*out = new(DeploymentModifications)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategySpec.
-func (in *WorkspaceAccessStrategySpec) DeepCopy() *WorkspaceAccessStrategySpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceNamespaceStatusList.
+func (in *WorkspaceNamespaceStatusList) DeepCopy() *WorkspaceNamespaceStatusList {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategySpec)
+	out := new(WorkspaceNamespaceStatusList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkspaceNamespaceStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceAccessStrategyStatus) DeepCopyInto(out *WorkspaceAccessStrategyStatus) {
+func (in *WorkspaceNamespaceSummary) DeepCopyInto(out *WorkspaceNamespaceSummary) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+	out.PhaseCounts = in.PhaseCounts
+	if in.OldestPendingSince != nil {
+		in, out := &in.OldestPendingSince, &out.OldestPendingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]NamespaceQuotaUsage, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceAccessStrategyStatus.
-func (in *WorkspaceAccessStrategyStatus) DeepCopy() *WorkspaceAccessStrategyStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceNamespaceSummary.
+func (in *WorkspaceNamespaceSummary) DeepCopy() *WorkspaceNamespaceSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceAccessStrategyStatus)
+	out := new(WorkspaceNamespaceSummary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *WorkspaceList) DeepCopyInto(out *WorkspaceList) {
+func (in *WorkspacePhaseCounts) DeepCopyInto(out *WorkspacePhaseCounts) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Workspace, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceList.
-func (in *WorkspaceList) DeepCopy() *WorkspaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspacePhaseCounts.
+func (in *WorkspacePhaseCounts) DeepCopy() *WorkspacePhaseCounts {
 	if in == nil {
 		return nil
 	}
-	out := new(WorkspaceList)
+	out := new(WorkspacePhaseCounts)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *WorkspaceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
+	if in.RestartRequestedAt != nil {
+		in, out := &in.RestartRequestedAt, &out.RestartRequestedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Collaborators != nil {
+		in, out := &in.Collaborators, &out.Collaborators
+		*out = make([]WorkspaceCollaborator, len(*in))
+		copy(*out, *in)
+	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GPUCount != nil {
+		in, out := &in.GPUCount, &out.GPUCount
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Storage != nil {
 		in, out := &in.Storage, &out.Storage
 		*out = new(StorageSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CloneFrom != nil {
+		in, out := &in.CloneFrom, &out.CloneFrom
+		*out = new(CloneSpec)
+		**out = **in
+	}
 	if in.Volumes != nil {
 		in, out := &in.Volumes, &out.Volumes
 		*out = make([]VolumeSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]ExtraVolumeSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TmpVolume != nil {
+		in, out := &in.TmpVolume, &out.TmpVolume
+		*out = new(TmpVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ContainerConfig != nil {
 		in, out := &in.ContainerConfig, &out.ContainerConfig
 		*out = new(ContainerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(BootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]EnvFromSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -655,19 +1751,19 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	}
 	if in.Affinity != nil {
 		in, out := &in.Affinity, &out.Affinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Lifecycle != nil {
 		in, out := &in.Lifecycle, &out.Lifecycle
-		*out = new(v1.Lifecycle)
+		*out = new(corev1.Lifecycle)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.AccessStrategy != nil {
@@ -675,6 +1771,11 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(AccessStrategyRef)
 		**out = **in
 	}
+	if in.DisabledSidecars != nil {
+		in, out := &in.DisabledSidecars, &out.DisabledSidecars
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.TemplateRef != nil {
 		in, out := &in.TemplateRef, &out.TemplateRef
 		*out = new(TemplateRef)
@@ -685,14 +1786,44 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(IdleShutdownSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleSpec)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.PodSecurityContext != nil {
 		in, out := &in.PodSecurityContext, &out.PodSecurityContext
-		*out = new(v1.PodSecurityContext)
+		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ContainerSecurityContext != nil {
 		in, out := &in.ContainerSecurityContext, &out.ContainerSecurityContext
-		*out = new(v1.SecurityContext)
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServiceMesh != nil {
+		in, out := &in.ServiceMesh, &out.ServiceMesh
+		*out = new(ServiceMeshSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerAdapter != nil {
+		in, out := &in.ServerAdapter, &out.ServerAdapter
+		*out = new(ServerAdapterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMetadata != nil {
+		in, out := &in.PodMetadata, &out.PodMetadata
+		*out = new(ChildObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -710,14 +1841,168 @@ func (in *WorkspaceSpec) DeepCopy() *WorkspaceSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceStatus) DeepCopyInto(out *WorkspaceStatus) {
 	*out = *in
+	if in.Connection != nil {
+		in, out := &in.Connection, &out.Connection
+		*out = new(WorkspaceConnectionStatus)
+		**out = **in
+	}
 	if in.AccessResources != nil {
 		in, out := &in.AccessResources, &out.AccessResources
 		*out = make([]AccessResourceStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnvFromMirrors != nil {
+		in, out := &in.EnvFromMirrors, &out.EnvFromMirrors
+		*out = make([]EnvFromMirrorStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildMetadata != nil {
+		in, out := &in.ChildMetadata, &out.ChildMetadata
+		*out = new(ChildMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterAccess != nil {
+		in, out := &in.ClusterAccess, &out.ClusterAccess
+		*out = new(ClusterAccessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = new(AcceleratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scheduling != nil {
+		in, out := &in.Scheduling, &out.Scheduling
+		*out = new(SchedulingDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedVolumes != nil {
+		in, out := &in.SharedVolumes, &out.SharedVolumes
+		*out = make([]SharedVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResolvedTemplate != nil {
+		in, out := &in.ResolvedTemplate, &out.ResolvedTemplate
+		*out = new(ResolvedTemplateStatus)
+		**out = **in
+	}
+	if in.DesiredStatusIntent != nil {
+		in, out := &in.DesiredStatusIntent, &out.DesiredStatusIntent
+		*out = new(DesiredStatusIntent)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NextScheduledStart != nil {
+		in, out := &in.NextScheduledStart, &out.NextScheduledStart
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduledStop != nil {
+		in, out := &in.NextScheduledStop, &out.NextScheduledStop
+		*out = (*in).DeepCopy()
+	}
+	if in.EffectivePolicies != nil {
+		in, out := &in.EffectivePolicies, &out.EffectivePolicies
+		*out = new(EffectivePolicies)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(HibernationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionScheduledAt != nil {
+		in, out := &in.DeletionScheduledAt, &out.DeletionScheduledAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CleanupHooks != nil {
+		in, out := &in.CleanupHooks, &out.CleanupHooks
+		*out = make([]CleanupHookStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastStartTime != nil {
+		in, out := &in.LastStartTime, &out.LastStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRestartTime != nil {
+		in, out := &in.LastRestartTime, &out.LastRestartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ServerType != nil {
+		in, out := &in.ServerType, &out.ServerType
+		*out = new(ServerType)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastActivityTime != nil {
+		in, out := &in.LastActivityTime, &out.LastActivityTime
+		*out = (*in).DeepCopy()
+	}
+	if in.FirstConnectedAt != nil {
+		in, out := &in.FirstConnectedAt, &out.FirstConnectedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Culling != nil {
+		in, out := &in.Culling, &out.Culling
+		*out = new(CullingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]WorkspaceHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Collaborators != nil {
+		in, out := &in.Collaborators, &out.Collaborators
+		*out = make([]WorkspaceCollaborator, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildEvents != nil {
+		in, out := &in.ChildEvents, &out.ChildEvents
+		*out = make([]ChildEventStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartupProgress != nil {
+		in, out := &in.StartupProgress, &out.StartupProgress
+		*out = new(StartupProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(ResourceRecommendations)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -806,9 +2091,14 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ImagePolicy != nil {
+		in, out := &in.ImagePolicy, &out.ImagePolicy
+		*out = new(ImagePolicy)
+		**out = **in
+	}
 	if in.DefaultResources != nil {
 		in, out := &in.DefaultResources, &out.DefaultResources
-		*out = new(v1.ResourceRequirements)
+		*out = new(corev1.ResourceRequirements)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.ResourceBounds != nil {
@@ -826,9 +2116,21 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(ContainerConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AllowCommandOverride != nil {
+		in, out := &in.AllowCommandOverride, &out.AllowCommandOverride
+		*out = new(bool)
+		**out = **in
+	}
 	if in.BaseEnv != nil {
 		in, out := &in.BaseEnv, &out.BaseEnv
-		*out = make([]v1.EnvVar, len(*in))
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BaseEnvFrom != nil {
+		in, out := &in.BaseEnvFrom, &out.BaseEnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -840,16 +2142,38 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AllowedEnvPatterns != nil {
+		in, out := &in.AllowedEnvPatterns, &out.AllowedEnvPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.AllowSecondaryStorages != nil {
 		in, out := &in.AllowSecondaryStorages, &out.AllowSecondaryStorages
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SharedVolumes != nil {
+		in, out := &in.SharedVolumes, &out.SharedVolumes
+		*out = make([]SharedVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowedVolumeSources != nil {
+		in, out := &in.AllowedVolumeSources, &out.AllowedVolumeSources
+		*out = make([]VolumeSourceType, len(*in))
+		copy(*out, *in)
+	}
 	if in.DefaultVolumes != nil {
 		in, out := &in.DefaultVolumes, &out.DefaultVolumes
 		*out = make([]VolumeSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.DefaultTmpVolume != nil {
+		in, out := &in.DefaultTmpVolume, &out.DefaultTmpVolume
+		*out = new(TmpVolumeSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DefaultNodeSelector != nil {
 		in, out := &in.DefaultNodeSelector, &out.DefaultNodeSelector
 		*out = make(map[string]string, len(*in))
@@ -859,21 +2183,56 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 	}
 	if in.DefaultAffinity != nil {
 		in, out := &in.DefaultAffinity, &out.DefaultAffinity
-		*out = new(v1.Affinity)
+		*out = new(corev1.Affinity)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.DefaultTolerations != nil {
 		in, out := &in.DefaultTolerations, &out.DefaultTolerations
-		*out = make([]v1.Toleration, len(*in))
+		*out = make([]corev1.Toleration, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AllowedPriorityClassNames != nil {
+		in, out := &in.AllowedPriorityClassNames, &out.AllowedPriorityClassNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SchedulingPolicy != nil {
+		in, out := &in.SchedulingPolicy, &out.SchedulingPolicy
+		*out = new(SchedulingPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartApproverGroups != nil {
+		in, out := &in.StartApproverGroups, &out.StartApproverGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartupCheck != nil {
+		in, out := &in.StartupCheck, &out.StartupCheck
+		*out = new(StartupCheckSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StartupTimeoutSeconds != nil {
+		in, out := &in.StartupTimeoutSeconds, &out.StartupTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	if in.BaseLabels != nil {
 		in, out := &in.BaseLabels, &out.BaseLabels
 		*out = make([]TemplateLabel, len(*in))
 		copy(*out, *in)
 	}
+	if in.ChildMetadata != nil {
+		in, out := &in.ChildMetadata, &out.ChildMetadata
+		*out = new(ChildMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodMetadata != nil {
+		in, out := &in.PodMetadata, &out.PodMetadata
+		*out = new(ChildObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LabelRequirements != nil {
 		in, out := &in.LabelRequirements, &out.LabelRequirements
 		*out = make([]LabelRequirement, len(*in))
@@ -891,6 +2250,21 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 		*out = new(IdleShutdownOverridePolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DefaultSchedule != nil {
+		in, out := &in.DefaultSchedule, &out.DefaultSchedule
+		*out = new(ScheduleSpec)
+		**out = **in
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(RetentionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.DefaultAccessStrategy != nil {
 		in, out := &in.DefaultAccessStrategy, &out.DefaultAccessStrategy
 		*out = new(AccessStrategyRef)
@@ -898,19 +2272,89 @@ func (in *WorkspaceTemplateSpec) DeepCopyInto(out *WorkspaceTemplateSpec) {
 	}
 	if in.DefaultLifecycle != nil {
 		in, out := &in.DefaultLifecycle, &out.DefaultLifecycle
-		*out = new(v1.Lifecycle)
+		*out = new(corev1.Lifecycle)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(TemplateLifecycleSpec)
+		**out = **in
+	}
 	if in.DefaultPodSecurityContext != nil {
 		in, out := &in.DefaultPodSecurityContext, &out.DefaultPodSecurityContext
-		*out = new(v1.PodSecurityContext)
+		*out = new(corev1.PodSecurityContext)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.DefaultContainerSecurityContext != nil {
 		in, out := &in.DefaultContainerSecurityContext, &out.DefaultContainerSecurityContext
-		*out = new(v1.SecurityContext)
+		*out = new(corev1.SecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DefaultServiceMesh != nil {
+		in, out := &in.DefaultServiceMesh, &out.DefaultServiceMesh
+		*out = new(ServiceMeshSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClusterAccess != nil {
+		in, out := &in.ClusterAccess, &out.ClusterAccess
+		*out = new(ClusterAccessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedServiceAccounts != nil {
+		in, out := &in.AllowedServiceAccounts, &out.AllowedServiceAccounts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Accelerators != nil {
+		in, out := &in.Accelerators, &out.Accelerators
+		*out = new(AcceleratorSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MinPrimaryContainerResources != nil {
+		in, out := &in.MinPrimaryContainerResources, &out.MinPrimaryContainerResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.InitContainers != nil {
+		in, out := &in.InitContainers, &out.InitContainers
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ServerAdapter != nil {
+		in, out := &in.ServerAdapter, &out.ServerAdapter
+		*out = new(ServerAdapterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ServerTypes != nil {
+		in, out := &in.ServerTypes, &out.ServerTypes
+		*out = make([]ServerType, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExampleWorkspaces != nil {
+		in, out := &in.ExampleWorkspaces, &out.ExampleWorkspaces
+		*out = make([]TemplateExampleWorkspace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceTemplateSpec.
@@ -0,0 +1,97 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/extensionapi"
+)
+
+// debugLogPath is the namespaced path of the debug logs served by the operator extension API
+const debugLogPath = "/apis/connection.workspace.jupyter.org/v1alpha1/namespaces/%s/workspacedebuglogs"
+
+func runDebugLog(args []string) error {
+	fs := flag.NewFlagSet("debug-log", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspace")
+	since := fs.Duration("since", extensionapi.DefaultDebugLogWindow, "How far back the records go (e.g. 30m)")
+	output := fs.String("o", "", "Output format: json, or empty for one line per record")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("debug-log requires exactly one workspace NAME")
+	}
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	data, err := clientset.Discovery().RESTClient().Get().
+		AbsPath(fmt.Sprintf(debugLogPath, *namespace)).
+		Param("workspace", fs.Arg(0)).
+		Param("since", since.String()).
+		DoRaw(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get the debug log, is the extension API enabled? %w", err)
+	}
+	if *output == "json" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	var debugLog extensionapi.WorkspaceDebugLog
+	if err := json.Unmarshal(data, &debugLog); err != nil {
+		return fmt.Errorf("failed to decode the debug log: %w", err)
+	}
+	return printDebugLog(os.Stdout, debugLog)
+}
+
+// printDebugLog writes one line per record, oldest first, with the values sorted by key
+func printDebugLog(w io.Writer, debugLog extensionapi.WorkspaceDebugLog) error {
+	if len(debugLog.Records) == 0 {
+		_, err := fmt.Fprintf(w, "No records for workspace %s/%s on %s. Records are kept in memory by the operator "+
+			"replica reconciling the workspace; set the workspace.jupyter.org/log-level=debug annotation for debug records.\n",
+			debugLog.Namespace, debugLog.Workspace, debugLog.Replica)
+		return err
+	}
+	for _, record := range debugLog.Records {
+		var line strings.Builder
+		fmt.Fprintf(&line, "%s V%d %q", record.Time.UTC().Format(time.RFC3339Nano), record.Level, record.Message)
+		if record.Error != "" {
+			fmt.Fprintf(&line, " error=%q", record.Error)
+		}
+		keys := make([]string, 0, len(record.Values))
+		for key := range record.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&line, " %s=%s", key, record.Values[key])
+		}
+		if _, err := fmt.Fprintln(w, line.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
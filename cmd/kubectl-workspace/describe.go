@@ -0,0 +1,140 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+func runDescribe(args []string) error {
+	fs := flag.NewFlagSet("describe", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspace")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("describe requires exactly one workspace NAME")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	key := client.ObjectKey{Name: fs.Arg(0), Namespace: *namespace}
+	if err := k8sClient.Get(context.Background(), key, workspace); err != nil {
+		return fmt.Errorf("failed to get workspace %s: %w", fs.Arg(0), err)
+	}
+	return printWorkspaceDescription(os.Stdout, workspace)
+}
+
+// printWorkspaceDescription describes the workspace and the lifecycle policies the controller reports for it,
+// which users may not be allowed to read from its template or namespace
+func printWorkspaceDescription(w io.Writer, workspace *workspacev1alpha1.Workspace) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	blockedReason, blockedMessage := controller.GetWorkspaceBlockedReason(workspace)
+	template := ""
+	if workspace.Spec.TemplateRef != nil {
+		template = workspace.Spec.TemplateRef.Name
+		if workspace.Spec.TemplateRef.Namespace != "" {
+			template = workspace.Spec.TemplateRef.Namespace + "/" + template
+		}
+	}
+	_, _ = fmt.Fprintf(tw, "Name:\t%s\n", workspace.Name)
+	_, _ = fmt.Fprintf(tw, "Namespace:\t%s\n", workspace.Namespace)
+	_, _ = fmt.Fprintf(tw, "Owner:\t%s\n", valueOrNone(workspace.Annotations[controller.AnnotationCreatedBy]))
+	_, _ = fmt.Fprintf(tw, "Template:\t%s\n", valueOrNone(template))
+	_, _ = fmt.Fprintf(tw, "Phase:\t%s\n", controller.GetWorkspacePhase(workspace))
+	_, _ = fmt.Fprintf(tw, "Blocked:\t%s\n", valueOrNone(blockedReason))
+	if blockedMessage != "" {
+		_, _ = fmt.Fprintf(tw, "Message:\t%s\n", blockedMessage)
+	}
+	_, _ = fmt.Fprintf(tw, "URL:\t%s\n", valueOrNone(workspace.Status.URL))
+	if connection := workspace.Status.Connection; connection != nil && workspace.Status.AccessURL == "" {
+		_, _ = fmt.Fprintf(tw, "Port forward:\t%s\n", connection.PortForwardCommand)
+	}
+
+	_, _ = fmt.Fprintln(tw, "Effective policies:")
+	policies := workspace.Status.EffectivePolicies
+	if policies == nil {
+		policies = &workspacev1alpha1.EffectivePolicies{}
+	}
+	if idle := policies.IdleShutdown; idle != nil {
+		value := fmt.Sprintf("after %dm idle", idle.IdleTimeoutInMinutes)
+		if idle.NeverConnectedTimeoutInMinutes != nil {
+			value += fmt.Sprintf(", %dm if never connected", *idle.NeverConnectedTimeoutInMinutes)
+		}
+		_, _ = fmt.Fprintf(tw, "  Idle shutdown:\t%s\t(%s)\n", value, idle.Source)
+	} else {
+		_, _ = fmt.Fprintln(tw, "  Idle shutdown:\t<none>")
+	}
+	if schedule := policies.Schedule; schedule != nil {
+		value := fmt.Sprintf("start %s, stop %s", valueOrNone(schedule.StartCron), valueOrNone(schedule.StopCron))
+		if schedule.TimeZone != "" {
+			value += " " + schedule.TimeZone
+		}
+		_, _ = fmt.Fprintf(tw, "  Schedule:\t%s\t(%s)\n", value, schedule.Source)
+	} else {
+		_, _ = fmt.Fprintln(tw, "  Schedule:\t<none>")
+	}
+	if retention := policies.Retention; retention != nil {
+		value := fmt.Sprintf("stale after %s unused, archived %s later",
+			days(retention.StaleAfter.Duration), days(retention.ArchiveGracePeriod.Duration))
+		if retention.Exempt {
+			value += ", exempt"
+		}
+		_, _ = fmt.Fprintf(tw, "  Retention:\t%s\t(%s)\n", value, retention.Source)
+	} else {
+		_, _ = fmt.Fprintln(tw, "  Retention:\t<none>")
+	}
+	if deletion := policies.DeleteAfterStopped; deletion != nil {
+		value := fmt.Sprintf("after %s stopped", days(deletion.After.Duration))
+		if deletion.Exempt {
+			value += ", exempt"
+		}
+		_, _ = fmt.Fprintf(tw, "  Delete after stopped:\t%s\t(%s)\n", value, deletion.Source)
+	} else {
+		_, _ = fmt.Fprintln(tw, "  Delete after stopped:\t<none>")
+	}
+	if lifetime := policies.MaxLifetime; lifetime != nil {
+		value := fmt.Sprintf("%s, then %s", days(lifetime.MaxLifetime.Duration), strings.ToLower(string(lifetime.Action)))
+		if lifetime.ExpiresAt != nil {
+			value += " at " + lifetime.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		if lifetime.Exempt {
+			value += ", exempt"
+		}
+		_, _ = fmt.Fprintf(tw, "  Max lifetime:\t%s\t(%s)\n", value, lifetime.Source)
+	} else {
+		_, _ = fmt.Fprintln(tw, "  Max lifetime:\t<none>")
+	}
+	if scheduled := workspace.Status.DeletionScheduledAt; scheduled != nil {
+		_, _ = fmt.Fprintf(tw, "Deletion scheduled:\t%s\n", scheduled.UTC().Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+// days formats a duration of whole days like the stale workspace annotations of namespaces express it
+func days(d time.Duration) string {
+	if d%(24*time.Hour) != 0 {
+		return d.String()
+	}
+	return fmt.Sprintf("%dd", d/(24*time.Hour))
+}
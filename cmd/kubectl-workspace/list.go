@@ -0,0 +1,78 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func runList(args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspaces")
+	allNamespaces := fs.Bool("A", false, "List the workspaces of all namespaces")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	var opts []client.ListOption
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(*namespace))
+	}
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := workspaceutil.ListAll(context.Background(), k8sClient, workspaces, opts...); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return printWorkspaceList(os.Stdout, workspaces.Items)
+}
+
+// printWorkspaceList lists the workspaces with their phase and, for those not running, why
+func printWorkspaceList(w io.Writer, workspaces []workspacev1alpha1.Workspace) error {
+	if len(workspaces) == 0 {
+		_, err := fmt.Fprintln(w, "No workspaces found.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAMESPACE\tNAME\tPHASE\tBLOCKED\tOWNER\tMESSAGE")
+	for i := range workspaces {
+		workspace := &workspaces[i]
+		// Derive the blocked reason rather than reading it, so that workspaces last written by an
+		// older controller are reported too
+		blockedReason, blockedMessage := controller.GetWorkspaceBlockedReason(workspace)
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			workspace.Namespace,
+			workspace.Name,
+			controller.GetWorkspacePhase(workspace),
+			valueOrNone(blockedReason),
+			workspace.Annotations[controller.AnnotationCreatedBy],
+			blockedMessage)
+	}
+	return tw.Flush()
+}
+
+// valueOrNone returns the value, or <none> when it is empty, as kubectl prints missing columns
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
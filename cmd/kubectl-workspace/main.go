@@ -0,0 +1,210 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package main implements the kubectl-workspace plugin.
+// Install the binary on the PATH to use it as `kubectl workspace`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/bundle"
+)
+
+const usage = `Usage:
+  kubectl workspace debug-log NAME [-n NAMESPACE] [--since DURATION] [-o json]
+  kubectl workspace describe NAME [-n NAMESPACE]
+  kubectl workspace export NAME [-n NAMESPACE] [-o FILE] [--include-scheduling] [--data-archive-ref REF]
+  kubectl workspace import -f FILE [-n NAMESPACE] [--name NEW_NAME] [--dry-run]
+  kubectl workspace list [-n NAMESPACE | -A]
+  kubectl workspace migrate-template --from NAMESPACE/TEMPLATE --to NAMESPACE/TEMPLATE [--dry-run]
+  kubectl workspace protect NAME [-n NAMESPACE]
+  kubectl workspace render -f FILE [-n NAMESPACE] [-t TEMPLATE_FILE] [--access-strategy FILE] [--offline]
+  kubectl workspace rightsize [-n NAMESPACE | -A]
+  kubectl workspace stale [-n NAMESPACE | -A]
+  kubectl workspace unprotect NAME [-n NAMESPACE]
+  kubectl workspace version [--server]
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "debug-log":
+		err = runDebugLog(os.Args[2:])
+	case "describe":
+		err = runDescribe(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	case "list":
+		err = runList(os.Args[2:])
+	case "migrate-template":
+		err = runMigrateTemplate(os.Args[2:])
+	case "protect":
+		err = runProtect("protect", os.Args[2:], true)
+	case "render":
+		err = runRender(os.Args[2:])
+	case "rightsize":
+		err = runRightsize(os.Args[2:])
+	case "stale":
+		err = runStale(os.Args[2:])
+	case "unprotect":
+		err = runProtect("unprotect", os.Args[2:], false)
+	case "version":
+		err = runVersion(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspace")
+	output := fs.String("o", "", "Write the bundle to this file instead of stdout")
+	includeScheduling := fs.Bool("include-scheduling", false, "Keep node selectors, affinity and tolerations")
+	dataArchiveRef := fs.String("data-archive-ref", "", "Reference to an archive of the workspace data to record in the bundle")
+	defaultTemplateNamespace := fs.String("default-template-namespace", "", "Fallback namespace for template resolution")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("export requires exactly one workspace NAME")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	b, err := bundle.Export(context.Background(), k8sClient, *namespace, fs.Arg(0), bundle.ExportOptions{
+		IncludeScheduling:        *includeScheduling,
+		DataArchiveRef:           *dataArchiveRef,
+		DefaultTemplateNamespace: *defaultTemplateNamespace,
+	})
+	if err != nil {
+		return err
+	}
+
+	data, err := bundle.Encode(b)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o600)
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("f", "", "Bundle file to import (- for stdin)")
+	namespace := fs.String("n", "default", "Target namespace")
+	name := fs.String("name", "", "New name for the workspace (defaults to the exported name)")
+	dryRun := fs.Bool("dry-run", false, "Validate against the API server without creating anything")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("import requires -f FILE")
+	}
+
+	var data []byte
+	var err error
+	if *file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(*file)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	b, err := bundle.Decode(data)
+	if err != nil {
+		return err
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	ws, template, err := bundle.Import(context.Background(), k8sClient, b, bundle.ImportOptions{
+		Namespace: *namespace,
+		Name:      *name,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	suffix := ""
+	if *dryRun {
+		suffix = " (dry run)"
+	}
+	if template != nil {
+		fmt.Printf("workspacetemplate.workspace.jupyter.org/%s created%s\n", template.Name, suffix)
+	}
+	fmt.Printf("workspace.workspace.jupyter.org/%s created%s\n", ws.Name, suffix)
+	return nil
+}
+
+// reorderArgs moves positional arguments after flags so that `export NAME -n ns` works like kubectl
+func reorderArgs(args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) > 1 && arg[0] == '-' {
+			flags = append(flags, arg)
+			// Flags with a separate value (boolean flags use -flag or -flag=value)
+			if arg != "--include-scheduling" && arg != "-include-scheduling" && i+1 < len(args) && !strings.Contains(arg, "=") {
+				flags = append(flags, args[i+1])
+				i++
+			}
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	return append(flags, positional...)
+}
+
+func newClient() (client.Client, error) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return client.New(cfg, client.Options{Scheme: scheme})
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/migrate"
+)
+
+func runMigrateTemplate(args []string) error {
+	fs := flag.NewFlagSet("migrate-template", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of templates given without a namespace")
+	from := fs.String("from", "", "Template the workspaces reference, as NAMESPACE/NAME")
+	to := fs.String("to", "", "Template to move the workspaces to, as NAMESPACE/NAME")
+	dryRun := fs.Bool("dry-run", false, "Report which workspaces the target template accepts without updating them")
+	defaultTemplateNamespace := fs.String("default-template-namespace", "", "Shared template namespace, as configured on the operator")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return errors.New("migrate-template requires --from and --to templates")
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	opts := migrate.Options{
+		From:                     parseTemplateKey(*from, *namespace),
+		To:                       parseTemplateKey(*to, *namespace),
+		DefaultTemplateNamespace: *defaultTemplateNamespace,
+		DryRun:                   *dryRun,
+	}
+	results, err := migrate.MigrateTemplate(context.Background(), k8sClient, opts)
+	if err != nil {
+		return err
+	}
+	if err := printMigrationReport(os.Stdout, opts, results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Succeeded() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d workspaces were not migrated", failed, len(results))
+	}
+	return nil
+}
+
+// parseTemplateKey parses a NAMESPACE/NAME template reference, defaulting the namespace
+func parseTemplateKey(value, defaultNamespace string) types.NamespacedName {
+	if namespace, name, ok := strings.Cut(value, "/"); ok {
+		return types.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return types.NamespacedName{Namespace: defaultNamespace, Name: value}
+}
+
+// printMigrationReport lists the outcome of the migration for each workspace
+func printMigrationReport(w io.Writer, opts migrate.Options, results []migrate.Result) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintf(w, "No workspaces reference template %s.\n", opts.From)
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAMESPACE\tNAME\tRESULT\tMESSAGE")
+	for _, result := range results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			result.Workspace.Namespace,
+			result.Workspace.Name,
+			result.Outcome,
+			result.Message)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	suffix := ""
+	if opts.DryRun {
+		suffix = " (dry run)"
+	}
+	succeeded := 0
+	for _, result := range results {
+		if result.Succeeded() {
+			succeeded++
+		}
+	}
+	_, err := fmt.Fprintf(w, "%d of %d workspaces moved from %s to %s%s\n",
+		succeeded, len(results), opts.From, opts.To, suffix)
+	return err
+}
@@ -0,0 +1,50 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// runProtect sets spec.deletionProtection of a workspace: the webhook rejects the deletion of protected
+// workspaces until they are unprotected
+func runProtect(command string, args []string, protect bool) error {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspace")
+	if err := fs.Parse(reorderArgs(args)); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%s requires exactly one workspace NAME", command)
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	workspace.Name = fs.Arg(0)
+	workspace.Namespace = *namespace
+	patch := fmt.Appendf(nil, `{"spec":{"deletionProtection":%t}}`, protect)
+	if err := k8sClient.Patch(context.Background(), workspace, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to %s workspace %s: %w", command, fs.Arg(0), err)
+	}
+
+	state := "protected from deletion"
+	if !protect {
+		state = "no longer protected from deletion"
+	}
+	fmt.Printf("workspace.workspace.jupyter.org/%s %s\n", workspace.Name, state)
+	return nil
+}
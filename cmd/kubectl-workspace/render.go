@@ -0,0 +1,153 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/render"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func runRender(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	file := fs.String("f", "", "Workspace manifest to render (- for stdin)")
+	namespace := fs.String("n", "", "Namespace of the workspace (defaults to the manifest namespace)")
+	templateFile := fs.String("t", "", "Template manifest to render with, instead of the referenced template")
+	accessStrategyFile := fs.String("access-strategy", "", "Access strategy manifest to render with, instead of the referenced one")
+	offline := fs.Bool("offline", false, "Never contact the API server; referenced objects must be given as files")
+	defaultTemplateNamespace := fs.String("default-template-namespace", "", "Fallback namespace for template resolution")
+	registry := fs.String("application-images-registry", "", "Registry prefix applied to application images, as configured on the operator")
+	pullPolicy := fs.String("application-images-pull-policy", "", "Pull policy of application images, as configured on the operator")
+	serviceMeshMode := fs.String("service-mesh-mode", "", "Service mesh mode, as configured on the operator")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("render requires -f FILE")
+	}
+
+	ws := &workspacev1alpha1.Workspace{}
+	if err := readManifest(*file, ws); err != nil {
+		return err
+	}
+	if *namespace != "" {
+		ws.Namespace = *namespace
+	}
+
+	opts := render.Options{
+		ControllerOptions: controller.WorkspaceControllerOptions{
+			ApplicationImagesRegistry:   *registry,
+			ApplicationImagesPullPolicy: corev1.PullPolicy(*pullPolicy),
+			ServiceMeshMode:             *serviceMeshMode,
+		},
+	}
+	if *templateFile != "" {
+		opts.Template = &workspacev1alpha1.WorkspaceTemplate{}
+		if err := readManifest(*templateFile, opts.Template); err != nil {
+			return err
+		}
+	}
+	if *accessStrategyFile != "" {
+		opts.AccessStrategy = &workspacev1alpha1.WorkspaceAccessStrategy{}
+		if err := readManifest(*accessStrategyFile, opts.AccessStrategy); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	needsTemplate := opts.Template == nil && ws.Spec.TemplateRef != nil && ws.Spec.TemplateRef.Name != ""
+	needsAccessStrategy := opts.AccessStrategy == nil && ws.Spec.AccessStrategy != nil && ws.Spec.AccessStrategy.Name != ""
+	if (needsTemplate || needsAccessStrategy) && !*offline {
+		k8sClient, err := newClient()
+		if err != nil {
+			return err
+		}
+		if err := resolveReferences(ctx, k8sClient, ws, &opts, *defaultTemplateNamespace); err != nil {
+			return err
+		}
+	}
+
+	result, err := render.Render(ctx, ws, opts)
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	data, err := render.EncodeYAML(result.Objects)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// resolveReferences fetches the template and access strategy referenced by the workspace
+func resolveReferences(
+	ctx context.Context,
+	k8sClient client.Client,
+	ws *workspacev1alpha1.Workspace,
+	opts *render.Options,
+	defaultTemplateNamespace string) error {
+	namespace := ws.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	if opts.Template == nil && ws.Spec.TemplateRef != nil && ws.Spec.TemplateRef.Name != "" {
+		template, err := workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace).
+			ResolveTemplate(ctx, ws.Spec.TemplateRef, namespace)
+		if err != nil {
+			return err
+		}
+		opts.Template = template
+	}
+
+	if opts.AccessStrategy == nil && ws.Spec.AccessStrategy != nil && ws.Spec.AccessStrategy.Name != "" {
+		accessStrategyNamespace := ws.Spec.AccessStrategy.Namespace
+		if accessStrategyNamespace == "" {
+			accessStrategyNamespace = namespace
+		}
+		accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+		key := client.ObjectKey{Name: ws.Spec.AccessStrategy.Name, Namespace: accessStrategyNamespace}
+		if err := k8sClient.Get(ctx, key, accessStrategy); err != nil {
+			return fmt.Errorf("failed to get access strategy %s: %w", key, err)
+		}
+		opts.AccessStrategy = accessStrategy
+	}
+	return nil
+}
+
+// readManifest decodes a YAML or JSON manifest file (- for stdin) into obj
+func readManifest(path string, obj any) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
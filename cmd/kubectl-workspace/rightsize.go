@@ -0,0 +1,95 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func runRightsize(args []string) error {
+	fs := flag.NewFlagSet("rightsize", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspaces")
+	allNamespaces := fs.Bool("A", false, "Report the workspaces of all namespaces")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	var opts []client.ListOption
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(*namespace))
+	}
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := workspaceutil.ListAll(context.Background(), k8sClient, workspaces, opts...); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return printRightsizeReport(os.Stdout, workspaces.Items)
+}
+
+// printRightsizeReport lists the requested resources of the workspaces next to the recommendations
+// derived from their observed usage
+func printRightsizeReport(w io.Writer, workspaces []workspacev1alpha1.Workspace) error {
+	if len(workspaces) == 0 {
+		_, err := fmt.Fprintln(w, "No workspaces found.")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAMESPACE\tNAME\tCPU REQUEST\tCPU RECOMMENDED\tMEMORY REQUEST\tMEMORY RECOMMENDED\tSAMPLES")
+	for i := range workspaces {
+		workspace := &workspaces[i]
+		recommendations := workspace.Status.Recommendations
+		cpuRecommended, memoryRecommended, samples := "", "", ""
+		if recommendations != nil {
+			cpuRecommended = recommendedValue(recommendations.CPU)
+			memoryRecommended = recommendedValue(recommendations.Memory)
+			samples = fmt.Sprint(recommendations.SampleCount)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			workspace.Namespace,
+			workspace.Name,
+			valueOrNone(requestedValue(workspace.Spec.Resources, corev1.ResourceCPU)),
+			valueOrNone(cpuRecommended),
+			valueOrNone(requestedValue(workspace.Spec.Resources, corev1.ResourceMemory)),
+			valueOrNone(memoryRecommended),
+			valueOrNone(samples))
+	}
+	return tw.Flush()
+}
+
+// requestedValue returns the request of the resource, or "" when none is set
+func requestedValue(resources *corev1.ResourceRequirements, name corev1.ResourceName) string {
+	if resources == nil {
+		return ""
+	}
+	if request, found := resources.Requests[name]; found {
+		return request.String()
+	}
+	return ""
+}
+
+// recommendedValue returns the recommended request/limit of the resource, or "" when none is recommended
+func recommendedValue(recommendation *workspacev1alpha1.ResourceRecommendation) string {
+	if recommendation == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", recommendation.Request.String(), recommendation.Limit.String())
+}
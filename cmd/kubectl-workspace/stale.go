@@ -0,0 +1,84 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func runStale(args []string) error {
+	fs := flag.NewFlagSet("stale", flag.ExitOnError)
+	namespace := fs.String("n", "default", "Namespace of the workspaces")
+	allNamespaces := fs.Bool("A", false, "Report stale workspaces of all namespaces")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	k8sClient, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	var opts []client.ListOption
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(*namespace))
+	}
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := workspaceutil.ListAll(context.Background(), k8sClient, workspaces, opts...); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return printStaleReport(os.Stdout, workspaces.Items)
+}
+
+// printStaleReport lists the workspaces flagged by the stale workspace policy, longest flagged first
+func printStaleReport(w io.Writer, workspaces []workspacev1alpha1.Workspace) error {
+	type staleRow struct {
+		workspace *workspacev1alpha1.Workspace
+		condition *metav1.Condition
+	}
+	var rows []staleRow
+	for i := range workspaces {
+		workspace := &workspaces[i]
+		condition := controller.FindCondition(&workspace.Status.Conditions, controller.ConditionTypeStale)
+		if condition != nil && condition.Status == metav1.ConditionTrue {
+			rows = append(rows, staleRow{workspace: workspace, condition: condition})
+		}
+	}
+	if len(rows) == 0 {
+		_, err := fmt.Fprintln(w, "No stale workspaces found.")
+		return err
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].condition.LastTransitionTime.Before(&rows[j].condition.LastTransitionTime)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAMESPACE\tNAME\tSTATE\tFLAGGED\tOWNER\tMESSAGE")
+	for _, row := range rows {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.workspace.Namespace,
+			row.workspace.Name,
+			row.condition.Reason,
+			row.condition.LastTransitionTime.UTC().Format(time.RFC3339),
+			row.workspace.Annotations[controller.AnnotationCreatedBy],
+			row.condition.Message)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,72 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+)
+
+// serverVersionPath is served by the operator extension API through the API server aggregation layer
+const serverVersionPath = "/apis/connection.workspace.jupyter.org/v1alpha1/version"
+
+func runVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	server := fs.Bool("server", false, "Also print the version and features of the operator running in the cluster")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	printInfo(os.Stdout, "Client", buildinfo.Get())
+	if !*server {
+		return nil
+	}
+
+	info, err := fetchServerInfo(context.Background())
+	if err != nil {
+		return err
+	}
+	printInfo(os.Stdout, "Server", info)
+	return nil
+}
+
+func fetchServerInfo(ctx context.Context) (buildinfo.Info, error) {
+	var info buildinfo.Info
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return info, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return info, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	data, err := clientset.Discovery().RESTClient().Get().AbsPath(serverVersionPath).DoRaw(ctx)
+	if err != nil {
+		return info, fmt.Errorf("failed to get the operator version, is the extension API enabled? %w", err)
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return info, fmt.Errorf("failed to decode the operator version: %w", err)
+	}
+	return info, nil
+}
+
+func printInfo(w io.Writer, title string, info buildinfo.Info) {
+	_, _ = fmt.Fprintf(w, "%s Version: %s (commit %s, built %s, %s)\n",
+		title, info.Version, info.GitCommit, info.BuildDate, info.GoVersion)
+	for _, name := range info.FeatureNames() {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", name, info.Features[name])
+	}
+}
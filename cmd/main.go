@@ -24,10 +24,15 @@ SOFTWARE.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +41,7 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -50,9 +56,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+	"github.com/jupyter-infra/jupyter-k8s/internal/cleanuphook"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 	"github.com/jupyter-infra/jupyter-k8s/internal/extensionapi"
+	"github.com/jupyter-infra/jupyter-k8s/internal/health"
+	"github.com/jupyter-infra/jupyter-k8s/internal/inventory"
+	"github.com/jupyter-infra/jupyter-k8s/internal/pluginclient"
+	"github.com/jupyter-infra/jupyter-k8s/internal/rightsizing"
 	webhookv1alpha1 "github.com/jupyter-infra/jupyter-k8s/internal/webhook/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhookconfig"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -68,6 +82,12 @@ var (
 	setupLog = ctrl.Log.WithName("setup")
 )
 
+// Names of the readiness checks registered by the manager
+const (
+	readyzCheckWebhookServer = "webhook-server"
+	readyzCheckPlugins       = "plugins"
+)
+
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
@@ -123,6 +143,30 @@ func parsePluginEndpoints(raw string) (map[string]string, error) {
 	return endpoints, nil
 }
 
+// parseCommaSeparatedList parses a comma-separated list, ignoring blank entries
+func parseCommaSeparatedList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseServiceMeshMode validates the service mesh mode flag.
+// An empty value disables service mesh integration.
+func parseServiceMeshMode(raw string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(raw))
+	switch mode {
+	case "", controller.ServiceMeshModeIstio, controller.ServiceMeshModeLinkerd:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid service mesh mode: %q. Expected one of: %s, %s",
+			raw, controller.ServiceMeshModeIstio, controller.ServiceMeshModeLinkerd)
+	}
+}
+
 // nolint:gocyclo
 func main() {
 	var metricsAddr string
@@ -144,8 +188,41 @@ func main() {
 	var jwtAudience string
 	var jwtSecretName string
 	var jwtTTL time.Duration
+	var catalogCacheTTL time.Duration
 	var newKeyUseDelay time.Duration
 	var pluginEndpointsFlag string
+	var serviceMeshModeFlag string
+	var userIntentCooldown time.Duration
+	var connectionDrainPeriod time.Duration
+	var deletionWarningPeriod time.Duration
+	var cleanupHooksConfig string
+	var debugLogDuration time.Duration
+	var bootstrapTimeout time.Duration
+	var cullingDryRun bool
+	var childNamePrefix string
+	var workspaceSelector string
+	var staleWorkspaceAfterDays int
+	var staleWorkspaceGraceDays int
+	var templateNamespacesFlag string
+	var pluginReachabilityWindow time.Duration
+	var mirrorChildEvents bool
+	var mirrorChildEventTypes string
+	var mirrorChildEventReasons string
+	var mirrorRepeatedChildEvents bool
+	var legacyTemplateLabelKeys string
+	var manageWebhookConfigurations bool
+	var enableResourceRecommendations bool
+	var resourceRecommendationMinHistory time.Duration
+	var resourceRecommendationHeadroom float64
+	var resourceRecommendationGapThreshold float64
+	var enableBootstrap bool
+	var bootstrapStarterTemplate bool
+	var webhookNamespaceSelector string
+	var webhookObjectSelector string
+	var inventoryAddr string
+	var inventoryTokenFile string
+	var inventoryCertPath string
+	var inventoryClusterName string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -183,12 +260,85 @@ func main() {
 		"JWT audience claim. Uses server default if not set.")
 	flag.StringVar(&jwtSecretName, "jwt-secret-name", "",
 		"K8s Secret name for JWT signing keys (enables k8s-native signing)")
+	flag.DurationVar(&catalogCacheTTL, "template-catalog-cache-ttl", 0,
+		"How long the extension API serves a cached template catalog, including its permission check (default 30s)")
 	flag.DurationVar(&jwtTTL, "jwt-ttl", 0,
 		"JWT expiration duration (e.g. 5m). Uses server default if not set.")
 	flag.DurationVar(&newKeyUseDelay, "new-key-use-delay", 0,
 		"Delay before using a newly rotated signing key (e.g. 5s). Uses server default if not set.")
 	flag.StringVar(&pluginEndpointsFlag, "plugin-endpoints", "",
 		"Comma-separated list of plugin name=endpoint pairs (e.g. aws=http://localhost:8080)")
+	flag.StringVar(&serviceMeshModeFlag, "service-mesh-mode", "",
+		"Service mesh running in the cluster (istio or linkerd). Enables mesh-aware workspace pod annotations.")
+	flag.DurationVar(&userIntentCooldown, "user-intent-cooldown", controller.DefaultUserIntentCooldown,
+		"How long a manual desiredStatus change suppresses schedule and idle culling intents (e.g. 30m)")
+	flag.DurationVar(&connectionDrainPeriod, "connection-drain-period", controller.DefaultConnectionDrainPeriod,
+		"How long in-flight requests may complete after a stopping workspace stops accepting connections, before its pod stops (e.g. 10s)")
+	flag.DurationVar(&deletionWarningPeriod, "stopped-workspace-deletion-warning", controller.DefaultDeletionWarningPeriod,
+		"How long before deleting a workspace stopped for longer than its spec.retention its owner is warned with an event (e.g. 24h)")
+	flag.StringVar(&cleanupHooksConfig, "cleanup-hooks-config", "",
+		"YAML file of the external cleanup hooks called when a workspace is deleted, e.g. by a home directory provisioner. Disabled if not set.")
+	flag.DurationVar(&debugLogDuration, "debug-log-duration", controller.DefaultDebugLogDuration,
+		"How long the workspace.jupyter.org/log-level: debug annotation raises the log verbosity of a workspace (e.g. 30m)")
+	flag.DurationVar(&bootstrapTimeout, "workspace-bootstrap-timeout", controller.DefaultBootstrapTimeout,
+		"How long the init containers of a workspace pod may take before the workspace is marked failed, unless it sets spec.bootstrap.timeout (e.g. 15m)")
+	flag.BoolVar(&cullingDryRun, "culling-dry-run", false,
+		"If set, the idle culler reports the workspaces its rules would stop in status.culling, events and metrics without stopping them")
+	flag.StringVar(&childNamePrefix, "child-name-prefix", "",
+		"Prefix of the names of the resources generated for new workspaces (default \"workspace\"). Namespaces can override it.")
+	flag.IntVar(&staleWorkspaceAfterDays, "stale-workspace-after-days", 0,
+		"Days without use after which a workspace is flagged stale (0 disables the stale workspace policy)")
+	flag.IntVar(&staleWorkspaceGraceDays, "stale-workspace-grace-days", int(controller.DefaultStaleWorkspaceGracePeriod/(24*time.Hour)),
+		"Days between flagging a workspace stale and requesting its archival")
+	flag.StringVar(&workspaceSelector, "workspace-selector", "",
+		"Label selector restricting the workspaces this instance reconciles and admits (e.g. team=ml). All workspaces if not set.")
+	flag.StringVar(&templateNamespacesFlag, "template-namespaces", "",
+		"Comma-separated list of namespaces whose templates this instance manages workspaces for. All namespaces if not set.")
+	flag.DurationVar(&pluginReachabilityWindow, "plugin-reachability-window", 5*time.Minute,
+		"How long plugins may fail their health check before the plugins readiness check fails")
+	flag.BoolVar(&mirrorChildEvents, "mirror-child-events", false,
+		"If set, events of the pods, volumes, deployments and services of workspaces are mirrored on the workspaces")
+	flag.StringVar(&mirrorChildEventTypes, "mirror-child-event-types", corev1.EventTypeWarning,
+		"Comma-separated list of the types of child events to mirror. All types if empty.")
+	flag.StringVar(&mirrorChildEventReasons, "mirror-child-event-reasons", "",
+		"Comma-separated list of the reasons of child events to mirror (e.g. FailedScheduling). All reasons if empty.")
+	flag.BoolVar(&mirrorRepeatedChildEvents, "mirror-repeated-child-events", false,
+		"If set, repeated child events are mirrored again at most every 5 minutes, instead of only their first occurrence")
+	flag.StringVar(&legacyTemplateLabelKeys, "legacy-template-label-keys", "",
+		"Comma-separated list of label keys earlier deployments named the template of a workspace with, "+
+			"removed by the template label migration")
+	flag.BoolVar(&manageWebhookConfigurations, "manage-webhook-configurations", false,
+		"If set, the operator issues the webhook serving certificate and owns the webhook configurations, "+
+			"instead of the deployment manifests and cert-manager")
+	flag.StringVar(&webhookNamespaceSelector, "webhook-namespace-selector", "",
+		"Label selector of the namespaces the workspace webhooks cover (e.g. jupyter.org/webhooks=enabled). "+
+			"Workspaces of other namespaces are revalidated by the controller. All namespaces if not set. "+
+			"Requires --manage-webhook-configurations.")
+	flag.StringVar(&webhookObjectSelector, "webhook-object-selector", "",
+		"Label selector of the workspaces the workspace webhooks cover, on top of --workspace-selector. "+
+			"Requires --manage-webhook-configurations.")
+	flag.BoolVar(&enableResourceRecommendations, "enable-resource-recommendations", false,
+		"If set, the usage of workspaces is sampled from the metrics server to recommend resources in status.recommendations")
+	flag.DurationVar(&resourceRecommendationMinHistory, "resource-recommendation-min-history", rightsizing.DefaultPolicy.MinHistory,
+		"How long the usage of a workspace is observed before resources are recommended for it (e.g. 168h)")
+	flag.Float64Var(&resourceRecommendationHeadroom, "resource-recommendation-headroom", rightsizing.DefaultPolicy.Headroom,
+		"Fraction added on top of the observed usage percentiles in resource recommendations (e.g. 0.15)")
+	flag.Float64Var(&resourceRecommendationGapThreshold, "resource-recommendation-gap-threshold", controller.DefaultRecommendationGapThreshold,
+		"Relative gap between the requested resources and the recommendations above which an event is recorded on the workspace")
+	flag.BoolVar(&enableBootstrap, "bootstrap", false,
+		"If set, the --default-template-namespace and --template-namespaces namespaces are created on startup when missing, "+
+			"for installations without the Helm chart. What was created is recorded in a ConfigMap in the controller namespace.")
+	flag.BoolVar(&bootstrapStarterTemplate, "bootstrap-starter-template", false,
+		"If set with --bootstrap, a starter template is installed in --default-template-namespace when no template exists. "+
+			"A starter template modified by an administrator is never overwritten.")
+	flag.StringVar(&inventoryAddr, "inventory-bind-address", "",
+		"The address the read-only workspace inventory endpoint binds to (e.g. :8090). Disabled if not set.")
+	flag.StringVar(&inventoryTokenFile, "inventory-token-file", "",
+		"File of the bearer tokens accepted by the inventory endpoint, one per line. Required with --inventory-bind-address.")
+	flag.StringVar(&inventoryCertPath, "inventory-cert-path", "",
+		"The directory that contains the tls.crt and tls.key of the inventory endpoint. HTTP is served if not set.")
+	flag.StringVar(&inventoryClusterName, "inventory-cluster-name", "",
+		"Name of the cluster reported in the inventory pages, for aggregators polling several clusters")
 	opts := zap.Options{
 		Development: false,
 	}
@@ -212,13 +362,32 @@ func main() {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
+	// When the operator owns its webhooks, it serves the certificate it issues
+	var webhookConfigManager *webhookconfig.Manager
+	var admissionCoverage *controller.AdmissionCoverage
+	if manageWebhookConfigurations {
+		var err error
+		webhookConfigManager, admissionCoverage, err = newWebhookConfigManager(
+			workspaceSelector, webhookNamespaceSelector, webhookObjectSelector)
+		if err != nil {
+			setupLog.Error(err, "Error configuring the webhook configurations")
+			os.Exit(1)
+		}
+	} else if webhookNamespaceSelector != "" || webhookObjectSelector != "" {
+		setupLog.Error(nil, "--webhook-namespace-selector and --webhook-object-selector require --manage-webhook-configurations")
+		os.Exit(1)
+	}
+
 	// Initial webhook TLS options
 	webhookTLSOpts := tlsOpts
+	if webhookConfigManager != nil {
+		webhookTLSOpts = append(slices.Clone(tlsOpts), webhookConfigManager.TLSOption())
+	}
 	webhookServerOptions := webhook.Options{
 		TLSOpts: webhookTLSOpts,
 	}
 
-	if len(webhookCertPath) > 0 {
+	if len(webhookCertPath) > 0 && webhookConfigManager == nil {
 		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
 			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
 
@@ -237,6 +406,9 @@ func main() {
 		BindAddress:   metricsAddr,
 		SecureServing: secureMetrics,
 		TLSOpts:       tlsOpts,
+		ExtraHandlers: map[string]http.Handler{
+			"/version": buildinfo.Handler(),
+		},
 	}
 
 	if secureMetrics {
@@ -318,6 +490,46 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Parse service mesh mode
+	serviceMeshMode, err := parseServiceMeshMode(serviceMeshModeFlag)
+	if err != nil {
+		setupLog.Error(err, "Error parsing service mesh mode")
+		os.Exit(1)
+	}
+
+	// Validate the child name prefix
+	if childNamePrefix != "" {
+		if err := controller.ValidateChildNamePrefix(childNamePrefix); err != nil {
+			setupLog.Error(err, "Error parsing child name prefix")
+			os.Exit(1)
+		}
+	}
+
+	// Parse the scope of this instance, for clusters running several operator instances
+	workspaceScope, err := workspaceutil.NewScope(workspaceSelector, parseCommaSeparatedList(templateNamespacesFlag), defaultTemplateNamespace)
+	if err != nil {
+		setupLog.Error(err, "Error parsing workspace scope")
+		os.Exit(1)
+	}
+	setupLog.Info("Managing workspaces in scope", "scope", workspaceScope.String())
+
+	// Load the cleanup hooks called when a workspace is deleted
+	var cleanupHooks []cleanuphook.Hook
+	if cleanupHooksConfig != "" {
+		cleanupHooks, err = cleanuphook.LoadConfig(cleanupHooksConfig)
+		if err != nil {
+			setupLog.Error(err, "Error loading cleanup hooks")
+			os.Exit(1)
+		}
+		setupLog.Info("Calling cleanup hooks when workspaces are deleted", "hooks", len(cleanupHooks))
+	}
+
+	// Detect whether workspace pods can run in user namespaces, for templates with the UserNamespace isolation level
+	userNamespacesSupported, err := controller.DetectUserNamespaceSupport(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "Error detecting user namespace support, the UserNamespace isolation level runs Rootless")
+	}
+
 	// Configure controller options
 	controllerOpts := controller.WorkspaceControllerOptions{
 		ApplicationImagesPullPolicy: getImagePullPolicy(applicationImagesPullPolicy),
@@ -327,6 +539,22 @@ func main() {
 		EnableWorkspacePodWatching:  enableWorkspacePodWatching,
 		DefaultTemplateNamespace:    defaultTemplateNamespace,
 		PluginEndpoints:             pluginEndpoints,
+		ServiceMeshMode:             serviceMeshMode,
+		UserIntentCooldown:          userIntentCooldown,
+		ConnectionDrainPeriod:       connectionDrainPeriod,
+		DeletionWarningPeriod:       deletionWarningPeriod,
+		CleanupHooks:                cleanupHooks,
+		DebugLogDuration:            debugLogDuration,
+		BootstrapTimeout:            bootstrapTimeout,
+		CullingDryRun:               cullingDryRun,
+		ChildNamePrefix:             childNamePrefix,
+		Scope:                       workspaceScope,
+		AdmissionCoverage:           admissionCoverage,
+		UserNamespacesSupported:     userNamespacesSupported,
+		StaleWorkspacePolicy: controller.StaleWorkspacePolicy{
+			Threshold:   time.Duration(staleWorkspaceAfterDays) * 24 * time.Hour,
+			GracePeriod: time.Duration(staleWorkspaceGraceDays) * 24 * time.Hour,
+		},
 	}
 
 	// Convert parsed GVKWatches to controller.GVKWatch format
@@ -338,12 +566,50 @@ func main() {
 		})
 	}
 
+	if admissionCoverage != nil {
+		admissionCoverage.Revalidator = webhookv1alpha1.NewWorkspaceRevalidator(mgr.GetClient(), defaultTemplateNamespace)
+	}
+
 	if err := controller.SetupWorkspaceController(mgr, controllerOpts); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Workspace")
 		os.Exit(1)
 	}
 
-	if err := controller.SetupWorkspaceTemplateController(mgr); err != nil {
+	// Backfill the template labels of workspaces created before the template label contract
+	labelMigration, err := controller.SetupTemplateLabelMigration(
+		mgr, defaultTemplateNamespace, parseCommaSeparatedList(legacyTemplateLabelKeys), workspaceScope)
+	if err != nil {
+		setupLog.Error(err, "unable to set up the template label migration")
+		os.Exit(1)
+	}
+
+	if enableBootstrap {
+		namespaces := parseCommaSeparatedList(templateNamespacesFlag)
+		if defaultTemplateNamespace != "" && !slices.Contains(namespaces, defaultTemplateNamespace) {
+			namespaces = append([]string{defaultTemplateNamespace}, namespaces...)
+		}
+		if err := controller.SetupBootstrap(mgr, controller.BootstrapOptions{
+			Namespaces:              namespaces,
+			StarterTemplate:         bootstrapStarterTemplate,
+			SharedTemplateNamespace: defaultTemplateNamespace,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up the bootstrap")
+			os.Exit(1)
+		}
+	}
+
+	if enableResourceRecommendations {
+		recommendationOptions := controller.DefaultResourceRecommendationOptions()
+		recommendationOptions.Policy.MinHistory = resourceRecommendationMinHistory
+		recommendationOptions.Policy.Headroom = resourceRecommendationHeadroom
+		recommendationOptions.GapThreshold = resourceRecommendationGapThreshold
+		if err := controller.SetupResourceRecommender(mgr, workspaceScope, recommendationOptions); err != nil {
+			setupLog.Error(err, "unable to set up the resource recommender")
+			os.Exit(1)
+		}
+	}
+
+	if err := controller.SetupWorkspaceTemplateController(mgr, labelMigration); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "WorkspaceTemplate")
 		os.Exit(1)
 	}
@@ -352,10 +618,33 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "WorkspaceAccessStrategy")
 		os.Exit(1)
 	}
+
+	if err := controller.SetupWorkspaceNamespaceStatusController(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "WorkspaceNamespaceStatus")
+		os.Exit(1)
+	}
+
+	eventMirrorPolicy := controller.EventMirrorPolicy{
+		Enabled:       mirrorChildEvents,
+		Types:         parseCommaSeparatedList(mirrorChildEventTypes),
+		Reasons:       parseCommaSeparatedList(mirrorChildEventReasons),
+		MirrorRepeats: mirrorRepeatedChildEvents,
+	}
+	if err := controller.SetupChildEventMirrorController(mgr, eventMirrorPolicy, workspaceScope); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ChildEventMirror")
+		os.Exit(1)
+	}
+	if webhookConfigManager != nil {
+		if err := webhookConfigManager.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to set up the webhook configurations")
+			os.Exit(1)
+		}
+	}
+
 	// Set up Workspace webhook (enabled by default, controlled by ENABLE_WORKSPACE_WEBHOOK)
 	// nolint:goconst
 	if os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false" {
-		if err := webhookv1alpha1.SetupWorkspaceWebhookWithManager(mgr, defaultTemplateNamespace); err != nil {
+		if err := webhookv1alpha1.SetupWorkspaceWebhookWithManager(mgr, defaultTemplateNamespace, workspaceScope); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Workspace")
 			os.Exit(1)
 		}
@@ -384,6 +673,10 @@ func main() {
 		configOpts := []extensionapi.ConfigOption{
 			extensionapi.WithServerPort(7443),
 			extensionapi.WithControllerNamespace(os.Getenv("CONTROLLER_POD_NAMESPACE")),
+			extensionapi.WithDefaultTemplateNamespace(defaultTemplateNamespace),
+		}
+		if catalogCacheTTL > 0 {
+			configOpts = append(configOpts, extensionapi.WithCatalogCacheTTL(catalogCacheTTL))
 		}
 
 		if len(pluginEndpoints) > 0 {
@@ -414,6 +707,27 @@ func main() {
 		}
 	} else {
 		setupLog.Info("Extension API server is disabled. Use --enable-extension-api to enable it.")
+		if err := health.AddSkippedChecks(mgr, extensionapi.ReadyzCheckServer, extensionapi.ReadyzCheckTemplateCatalog); err != nil {
+			setupLog.Error(err, "unable to set up ready check")
+			os.Exit(1)
+		}
+	}
+
+	if inventoryAddr != "" {
+		inventoryServer, err := inventory.NewServer(mgr.GetCache(), inventory.Config{
+			BindAddress: inventoryAddr,
+			TokenFile:   inventoryTokenFile,
+			CertDir:     inventoryCertPath,
+			ClusterName: inventoryClusterName,
+		}, ctrl.Log.WithName("inventory"))
+		if err != nil {
+			setupLog.Error(err, "unable to create the inventory server")
+			os.Exit(1)
+		}
+		if err := mgr.Add(inventoryServer); err != nil {
+			setupLog.Error(err, "unable to add the inventory server to the manager")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -425,6 +739,30 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if err := addSubsystemReadyzChecks(mgr, pluginEndpoints, pluginReachabilityWindow); err != nil {
+		setupLog.Error(err, "unable to set up ready check")
+		os.Exit(1)
+	}
+
+	buildinfo.SetFeatures(map[string]string{
+		"extensionApi":             strconv.FormatBool(enableExtensionAPI),
+		"watchTraefik":             strconv.FormatBool(watchTraefik),
+		"workspacePodWatching":     strconv.FormatBool(enableWorkspacePodWatching),
+		"workspaceWebhook":         strconv.FormatBool(os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false"),
+		"workspaceTemplateWebhook": strconv.FormatBool(os.Getenv("ENABLE_WORKSPACE_TEMPLATE_WEBHOOK") != "false"),
+		"serviceMesh":              featureValueOrNone(serviceMeshMode),
+		"plugins":                  featureValueOrNone(pluginNames(pluginEndpoints)),
+		"workspaceScope":           strconv.FormatBool(workspaceScope != nil),
+		"staleWorkspaces":          strconv.FormatBool(staleWorkspaceAfterDays > 0),
+		"childEventMirroring":      strconv.FormatBool(mirrorChildEvents),
+		"managedWebhooks":          strconv.FormatBool(manageWebhookConfigurations),
+		"resourceRecommendations":  strconv.FormatBool(enableResourceRecommendations),
+		"bootstrap":                strconv.FormatBool(enableBootstrap),
+		"inventory":                strconv.FormatBool(inventoryAddr != ""),
+	})
+	info := buildinfo.Get()
+	setupLog.Info("build info", "version", info.Version, "gitCommit", info.GitCommit,
+		"buildDate", info.BuildDate, "goVersion", info.GoVersion, "features", info.Features)
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -433,6 +771,87 @@ func main() {
 	}
 }
 
+// newWebhookConfigManager returns the manager of the webhook configurations, and the coverage of the
+// workspace webhooks for the controller to revalidate the workspaces they do not see. The workspace
+// selector of the operator scope also restricts the workspaces the webhooks see.
+func newWebhookConfigManager(
+	workspaceSelector, namespaceSelector, objectSelector string,
+) (*webhookconfig.Manager, *controller.AdmissionCoverage, error) {
+	namespace := os.Getenv("CONTROLLER_POD_NAMESPACE")
+	if namespace == "" {
+		return nil, nil, fmt.Errorf("CONTROLLER_POD_NAMESPACE is required to manage the webhook configurations")
+	}
+
+	var objectSelectors []string
+	for _, selector := range []string{workspaceSelector, objectSelector} {
+		if selector != "" {
+			objectSelectors = append(objectSelectors, selector)
+		}
+	}
+	webhookNamespaceSelector, err := metav1.ParseToLabelSelector(namespaceSelector)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid webhook namespace selector %q: %w", namespaceSelector, err)
+	}
+	webhookObjectSelector, err := metav1.ParseToLabelSelector(strings.Join(objectSelectors, ","))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid webhook object selector %q: %w", objectSelector, err)
+	}
+
+	// nolint:goconst
+	workspaceWebhooks := os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false"
+	configManager, err := webhookconfig.NewManager(webhookconfig.Options{
+		Namespace:               namespace,
+		NamespaceSelector:       webhookNamespaceSelector,
+		ObjectSelector:          webhookObjectSelector,
+		EnableWorkspaceWebhooks: workspaceWebhooks,
+		EnableTemplateWebhook:   os.Getenv("ENABLE_WORKSPACE_TEMPLATE_WEBHOOK") != "false",
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !workspaceWebhooks || (namespaceSelector == "" && objectSelector == "") {
+		return configManager, nil, nil
+	}
+
+	coverage := &controller.AdmissionCoverage{}
+	if coverage.NamespaceSelector, err = metav1.LabelSelectorAsSelector(webhookNamespaceSelector); err != nil {
+		return nil, nil, err
+	}
+	if coverage.ObjectSelector, err = metav1.LabelSelectorAsSelector(webhookObjectSelector); err != nil {
+		return nil, nil, err
+	}
+	return configManager, coverage, nil
+}
+
+// addSubsystemReadyzChecks registers the readiness checks of the webhook server and the plugins.
+// The checks of the informers and the extension API server are registered with their subsystem.
+func addSubsystemReadyzChecks(mgr ctrl.Manager, pluginEndpoints map[string]string, pluginReachabilityWindow time.Duration) error {
+	// nolint:goconst
+	if os.Getenv("ENABLE_WORKSPACE_WEBHOOK") != "false" || os.Getenv("ENABLE_WORKSPACE_TEMPLATE_WEBHOOK") != "false" {
+		// The checker completes a TLS handshake, so it also fails when the certificate is not loaded
+		if err := health.AddReadyzChecks(mgr, health.Check{
+			Name:    readyzCheckWebhookServer,
+			Checker: mgr.GetWebhookServer().StartedChecker(),
+		}); err != nil {
+			return err
+		}
+	} else if err := health.AddSkippedChecks(mgr, readyzCheckWebhookServer); err != nil {
+		return err
+	}
+
+	if len(pluginEndpoints) == 0 {
+		return health.AddSkippedChecks(mgr, readyzCheckPlugins)
+	}
+	probes := make(map[string]func(ctx context.Context) error, len(pluginEndpoints))
+	for name, endpoint := range pluginEndpoints {
+		probes[name] = pluginclient.NewPluginClient(endpoint, setupLog.WithName("plugin-health-"+name)).CheckHealth
+	}
+	return health.AddReadyzChecks(mgr, health.Check{
+		Name:    readyzCheckPlugins,
+		Checker: health.AllReachableWithin(pluginReachabilityWindow, probes),
+	})
+}
+
 // getImagePullPolicy converts a string pull policy to a Kubernetes PullPolicy
 func getImagePullPolicy(policyStr string) corev1.PullPolicy {
 	switch strings.ToLower(policyStr) {
@@ -447,3 +866,21 @@ func getImagePullPolicy(policyStr string) corev1.PullPolicy {
 		return corev1.PullIfNotPresent
 	}
 }
+
+// pluginNames returns the sorted, comma-separated names of the configured plugins
+func pluginNames(endpoints map[string]string) string {
+	names := make([]string, 0, len(endpoints))
+	for name := range endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+// featureValueOrNone reports an unset feature value as "none"
+func featureValueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -169,7 +169,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = controller.SetupWorkspaceTemplateController(mgr); err != nil {
+	if err = controller.SetupWorkspaceTemplateController(mgr, nil); err != nil {
 		setupLog.Error(err, "Error setting up workspace template controller")
 		os.Exit(1)
 	}
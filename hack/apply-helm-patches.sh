@@ -206,7 +206,12 @@ if [ -f "${PATCHES_DIR}/values.yaml.patch" ]; then
         # Add env section right after container: (macOS compatible)
         sed -i.bak '/container:/a\
     env:\
-      CLUSTER_ADMIN_GROUP: "cluster-workspace-admin"
+      CLUSTER_ADMIN_GROUP: "cluster-workspace-admin"\
+      # Members of this group may set the workspace.jupyter.org/paused annotation\
+      # PAUSE_RECONCILIATION_GROUP: "workspace-break-glass"\
+      # Principals allowed to create workspaces on behalf of users (workspace.jupyter.org/on-behalf-of)\
+      # TRUSTED_CREATORS: "system:serviceaccount:portal:portal"\
+      # TRUSTED_CREATOR_GROUPS: "workspace-portals"
 ' "${CHART_DIR}/values.yaml" && rm "${CHART_DIR}/values.yaml.bak"
     fi
 
@@ -227,6 +232,29 @@ if [ -f "${PATCHES_DIR}/values.yaml.patch" ]; then
         }' "${CHART_DIR}/values.yaml"
     fi
 
+    # Add the webhook configuration ownership settings to the webhook section
+    if ! grep -q "manageConfigurations:" "${CHART_DIR}/values.yaml"; then
+        if [[ "$OSTYPE" == "darwin"* ]]; then
+            sed -i '' '/^webhook:/,/^$/{
+                /^  enable: true$/a\
+  # When true, the operator issues the webhook serving certificate and owns the webhook\
+  # configurations at runtime, instead of this chart and cert-manager\
+  manageConfigurations: false\
+  # Labels of the namespaces the workspace webhooks cover, to roll them out namespace by\
+  # namespace (e.g. jupyter.org/webhooks: enabled). Workspaces in other namespaces are\
+  # revalidated by the controller. All namespaces if empty. Requires manageConfigurations.\
+  namespaceSelector: {}\
+  # Labels of the workspaces the workspace webhooks cover, on top of workspaceScope.matchLabels.\
+  # Requires manageConfigurations.\
+  objectSelector: {}
+            }' "${CHART_DIR}/values.yaml"
+        else
+            sed -i '/^webhook:/,/^$/{
+                /^  enable: true$/a\  # When true, the operator issues the webhook serving certificate and owns the webhook\n  # configurations at runtime, instead of this chart and cert-manager\n  manageConfigurations: false\n  # Labels of the namespaces the workspace webhooks cover, to roll them out namespace by\n  # namespace (e.g. jupyter.org/webhooks: enabled). Workspaces in other namespaces are\n  # revalidated by the controller. All namespaces if empty. Requires manageConfigurations.\n  namespaceSelector: {}\n  # Labels of the workspaces the workspace webhooks cover, on top of workspaceScope.matchLabels.\n  # Requires manageConfigurations.\n  objectSelector: {}
+            }' "${CHART_DIR}/values.yaml"
+        fi
+    fi
+
     # Check if the application section already exists
     if grep -q "^# \[APPLICATION\]" "${CHART_DIR}/values.yaml"; then
         echo "Removing existing APPLICATION section from values.yaml"
@@ -277,6 +305,21 @@ if [ -f "${PATCHES_DIR}/values.yaml.patch" ]; then
         fi
     fi
 
+    # Add the template catalog cache duration to the controllerManager section
+    if ! grep -q "templateCatalogCacheTTL:" "${CHART_DIR}/values.yaml"; then
+        if [[ "$OSTYPE" == "darwin"* ]]; then
+            sed -i '' '/^  pod:$/,/^    annotations: {}$/{
+                /^    annotations: {}$/a\
+  # How long the extension API serves a cached template catalog (e.g. 1m), 30s if empty\
+  templateCatalogCacheTTL: ""
+            }' "${CHART_DIR}/values.yaml"
+        else
+            sed -i '/^  pod:$/,/^    annotations: {}$/{
+                /^    annotations: {}$/a\  # How long the extension API serves a cached template catalog (e.g. 1m), 30s if empty\n  templateCatalogCacheTTL: ""
+            }' "${CHART_DIR}/values.yaml"
+        fi
+    fi
+
     # Append the entire patch file content to values.yaml
     echo "Appending patch content to values.yaml"
     cat "${PATCHES_DIR}/values.yaml.patch" >> "${CHART_DIR}/values.yaml"
@@ -333,18 +376,115 @@ if [ -f "${PATCHES_DIR}/manager.yaml.patch" ]; then
             - "--new-key-use-delay={{ .Values.extensionApi.jwtSecret.newKeyUseDelay }}"\
             {{- end}}\
             {{- end}}\
+            {{- if and .Values.extensionApi.enable .Values.controllerManager.templateCatalogCacheTTL }}\
+            - "--template-catalog-cache-ttl={{ .Values.controllerManager.templateCatalogCacheTTL }}"\
+            {{- end}}\
             {{- if .Values.workspacePodWatching.enable }}\
             - "--enable-workspace-pod-watching"\
             {{- end}}\
+            {{- if .Values.serviceMesh.mode }}\
+            - "--service-mesh-mode={{ .Values.serviceMesh.mode }}"\
+            {{- end}}\
+            {{- if .Values.desiredStatusIntents.userCooldown }}\
+            - "--user-intent-cooldown={{ .Values.desiredStatusIntents.userCooldown }}"\
+            {{- end}}\
+            {{- if .Values.workspaceStop.connectionDrainPeriod }}\
+            - "--connection-drain-period={{ .Values.workspaceStop.connectionDrainPeriod }}"\
+            {{- end}}\
+            {{- if .Values.stoppedWorkspaces.deletionWarning }}\
+            - "--stopped-workspace-deletion-warning={{ .Values.stoppedWorkspaces.deletionWarning }}"\
+            {{- end}}\
+            {{- if .Values.cleanupHooks }}\
+            - "--cleanup-hooks-config=/etc/jupyter-k8s/cleanup-hooks/hooks.yaml"\
+            {{- end}}\
+            {{- if .Values.debugLogs.duration }}\
+            - "--debug-log-duration={{ .Values.debugLogs.duration }}"\
+            {{- end}}\
+            {{- if .Values.workspaceBootstrap.timeout }}\
+            - "--workspace-bootstrap-timeout={{ .Values.workspaceBootstrap.timeout }}"\
+            {{- end}}\
+            {{- if .Values.idleCulling.dryRun }}\
+            - "--culling-dry-run"\
+            {{- end}}\
+            {{- if .Values.workspaceNaming.childNamePrefix }}\
+            - "--child-name-prefix={{ .Values.workspaceNaming.childNamePrefix }}"\
+            {{- end}}\
+            {{- if .Values.staleWorkspaces.afterDays }}\
+            - "--stale-workspace-after-days={{ .Values.staleWorkspaces.afterDays }}"\
+            - "--stale-workspace-grace-days={{ .Values.staleWorkspaces.graceDays }}"\
+            {{- end}}\
+            {{- if .Values.resourceRecommendations.enable }}\
+            - "--enable-resource-recommendations"\
+            - "--resource-recommendation-min-history={{ .Values.resourceRecommendations.minHistory }}"\
+            - "--resource-recommendation-headroom={{ .Values.resourceRecommendations.headroom }}"\
+            - "--resource-recommendation-gap-threshold={{ .Values.resourceRecommendations.gapThreshold }}"\
+            {{- end}}\
+            {{- if .Values.bootstrap.enable }}\
+            - "--bootstrap"\
+            {{- if .Values.bootstrap.starterTemplate }}\
+            - "--bootstrap-starter-template"\
+            {{- end}}\
+            {{- end}}\
+            {{- if .Values.inventory.enable }}\
+            - "--inventory-bind-address=:{{ .Values.inventory.port }}"\
+            - "--inventory-token-file=/etc/jupyter-k8s/inventory/tokens"\
+            {{- if .Values.inventory.clusterName }}\
+            - "--inventory-cluster-name={{ .Values.inventory.clusterName }}"\
+            {{- end}}\
+            {{- if .Values.inventory.certSecretName }}\
+            - "--inventory-cert-path=/etc/jupyter-k8s/inventory-tls"\
+            {{- end}}\
+            {{- end}}\
+            {{- with .Values.workspaceScope.matchLabels }}\
+            {{- \$selector := list }}\
+            {{- range \$key, \$value := . }}\
+            {{- \$selector = append \$selector (printf "%s=%s" \$key \$value) }}\
+            {{- end }}\
+            - "--workspace-selector={{ join "," \$selector }}"\
+            {{- end}}\
+            {{- if .Values.workspaceScope.templateNamespaces }}\
+            - "--template-namespaces={{ join "," .Values.workspaceScope.templateNamespaces }}"\
+            {{- end}}\
+            {{- if .Values.childEventMirroring.enable }}\
+            - "--mirror-child-events"\
+            - "--mirror-child-event-types={{ join "," .Values.childEventMirroring.types }}"\
+            - "--mirror-child-event-reasons={{ join "," .Values.childEventMirroring.reasons }}"\
+            {{- if .Values.childEventMirroring.mirrorRepeats }}\
+            - "--mirror-repeated-child-events"\
+            {{- end}}\
+            {{- end}}\
+            {{- if .Values.templateLabelMigration.legacyLabelKeys }}\
+            - "--legacy-template-label-keys={{ join "," .Values.templateLabelMigration.legacyLabelKeys }}"\
+            {{- end}}\
+            {{- if and .Values.webhook.enable .Values.webhook.manageConfigurations }}\
+            - "--manage-webhook-configurations"\
+            {{- with .Values.webhook.namespaceSelector }}\
+            {{- \$selector := list }}\
+            {{- range \$key, \$value := . }}\
+            {{- \$selector = append \$selector (printf "%s=%s" \$key \$value) }}\
+            {{- end }}\
+            - "--webhook-namespace-selector={{ join "," \$selector }}"\
+            {{- end}}\
+            {{- with .Values.webhook.objectSelector }}\
+            {{- \$selector := list }}\
+            {{- range \$key, \$value := . }}\
+            {{- \$selector = append \$selector (printf "%s=%s" \$key \$value) }}\
+            {{- end }}\
+            - "--webhook-object-selector={{ join "," \$selector }}"\
+            {{- end}}\
+            {{- end}}\
             {{- if .Values.controller.plugins }}\
             - "--plugin-endpoints={{ range \$i, \$p := .Values.controller.plugins }}{{ if \$i }},{{ end }}{{ \$p.name }}=http://localhost:{{ \$p.port }}{{ end }}"\
+            {{- if .Values.controller.pluginReachabilityWindow }}\
+            - "--plugin-reachability-window={{ .Values.controller.pluginReachabilityWindow }}"\
+            {{- end}}\
             {{- end}}
                     }' "${MANAGER_YAML}"
                 else
                     # Linux sed
                     sed -i '/args:/,/command:/ {
                     /command:/!d
-                    i\          args:\n            {{- range .Values.controllerManager.container.args }}\n            - {{ . }}\n            {{- end }}\n            - "--application-images-pull-policy={{ .Values.application.imagesPullPolicy }}"\n            - "--application-images-registry={{ .Values.application.imagesRegistry }}"\n            - "--default-template-namespace={{ .Values.workspaceTemplates.defaultNamespace }}"\n            {{- if .Values.accessResources.traefik.enable }}\n            - "--watch-traefik"\n            {{- end}}\n            {{- if .Values.extensionApi.enable }}\n            - "--enable-extension-api"\n            {{- if .Values.extensionApi.jwtIssuer }}\n            - "--jwt-issuer={{ .Values.extensionApi.jwtIssuer }}"\n            {{- end}}\n            {{- if .Values.extensionApi.jwtAudience }}\n            - "--jwt-audience={{ .Values.extensionApi.jwtAudience }}"\n            {{- end}}\n            {{- end}}\n            {{- if and .Values.extensionApi.enable .Values.extensionApi.jwtSecret.enable }}\n            - "--jwt-secret-name={{ .Values.extensionApi.jwtSecret.secretName }}"\n            {{- if .Values.extensionApi.jwtSecret.tokenTTL }}\n            - "--jwt-ttl={{ .Values.extensionApi.jwtSecret.tokenTTL }}"\n            {{- end}}\n            {{- if .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n            - "--new-key-use-delay={{ .Values.extensionApi.jwtSecret.newKeyUseDelay }}"\n            {{- end}}\n            {{- end}}\n            {{- if .Values.workspacePodWatching.enable }}\n            - "--enable-workspace-pod-watching"\n            {{- end}}\n            {{- if .Values.controller.plugins }}\n            - "--plugin-endpoints={{ range $i, $p := .Values.controller.plugins }}{{ if $i }},{{ end }}{{ $p.name }}=http://localhost:{{ $p.port }}{{ end }}"\n            {{- end}}
+                    i\          args:\n            {{- range .Values.controllerManager.container.args }}\n            - {{ . }}\n            {{- end }}\n            - "--application-images-pull-policy={{ .Values.application.imagesPullPolicy }}"\n            - "--application-images-registry={{ .Values.application.imagesRegistry }}"\n            - "--default-template-namespace={{ .Values.workspaceTemplates.defaultNamespace }}"\n            {{- if .Values.accessResources.traefik.enable }}\n            - "--watch-traefik"\n            {{- end}}\n            {{- if .Values.extensionApi.enable }}\n            - "--enable-extension-api"\n            {{- if .Values.extensionApi.jwtIssuer }}\n            - "--jwt-issuer={{ .Values.extensionApi.jwtIssuer }}"\n            {{- end}}\n            {{- if .Values.extensionApi.jwtAudience }}\n            - "--jwt-audience={{ .Values.extensionApi.jwtAudience }}"\n            {{- end}}\n            {{- end}}\n            {{- if and .Values.extensionApi.enable .Values.extensionApi.jwtSecret.enable }}\n            - "--jwt-secret-name={{ .Values.extensionApi.jwtSecret.secretName }}"\n            {{- if .Values.extensionApi.jwtSecret.tokenTTL }}\n            - "--jwt-ttl={{ .Values.extensionApi.jwtSecret.tokenTTL }}"\n            {{- end}}\n            {{- if .Values.extensionApi.jwtSecret.newKeyUseDelay }}\n            - "--new-key-use-delay={{ .Values.extensionApi.jwtSecret.newKeyUseDelay }}"\n            {{- end}}\n            {{- end}}\n            {{- if and .Values.extensionApi.enable .Values.controllerManager.templateCatalogCacheTTL }}\n            - "--template-catalog-cache-ttl={{ .Values.controllerManager.templateCatalogCacheTTL }}"\n            {{- end}}\n            {{- if .Values.workspacePodWatching.enable }}\n            - "--enable-workspace-pod-watching"\n            {{- end}}\n            {{- if .Values.serviceMesh.mode }}\n            - "--service-mesh-mode={{ .Values.serviceMesh.mode }}"\n            {{- end}}\n            {{- if .Values.desiredStatusIntents.userCooldown }}\n            - "--user-intent-cooldown={{ .Values.desiredStatusIntents.userCooldown }}"\n            {{- end}}\n            {{- if .Values.workspaceStop.connectionDrainPeriod }}\n            - "--connection-drain-period={{ .Values.workspaceStop.connectionDrainPeriod }}"\n            {{- end}}\n            {{- if .Values.stoppedWorkspaces.deletionWarning }}\n            - "--stopped-workspace-deletion-warning={{ .Values.stoppedWorkspaces.deletionWarning }}"\n            {{- end}}\n            {{- if .Values.cleanupHooks }}\n            - "--cleanup-hooks-config=/etc/jupyter-k8s/cleanup-hooks/hooks.yaml"\n            {{- end}}\n            {{- if .Values.debugLogs.duration }}\n            - "--debug-log-duration={{ .Values.debugLogs.duration }}"\n            {{- end}}\n            {{- if .Values.workspaceBootstrap.timeout }}\n            - "--workspace-bootstrap-timeout={{ .Values.workspaceBootstrap.timeout }}"\n            {{- end}}\n            {{- if .Values.idleCulling.dryRun }}\n            - "--culling-dry-run"\n            {{- end}}\n            {{- if .Values.workspaceNaming.childNamePrefix }}\n            - "--child-name-prefix={{ .Values.workspaceNaming.childNamePrefix }}"\n            {{- end}}\n            {{- if .Values.staleWorkspaces.afterDays }}\n            - "--stale-workspace-after-days={{ .Values.staleWorkspaces.afterDays }}"\n            - "--stale-workspace-grace-days={{ .Values.staleWorkspaces.graceDays }}"\n            {{- end}}\n            {{- if .Values.resourceRecommendations.enable }}\n            - "--enable-resource-recommendations"\n            - "--resource-recommendation-min-history={{ .Values.resourceRecommendations.minHistory }}"\n            - "--resource-recommendation-headroom={{ .Values.resourceRecommendations.headroom }}"\n            - "--resource-recommendation-gap-threshold={{ .Values.resourceRecommendations.gapThreshold }}"\n            {{- end}}\n            {{- if .Values.bootstrap.enable }}\n            - "--bootstrap"\n            {{- if .Values.bootstrap.starterTemplate }}\n            - "--bootstrap-starter-template"\n            {{- end}}\n            {{- end}}\n            {{- if .Values.inventory.enable }}\n            - "--inventory-bind-address=:{{ .Values.inventory.port }}"\n            - "--inventory-token-file=/etc/jupyter-k8s/inventory/tokens"\n            {{- if .Values.inventory.clusterName }}\n            - "--inventory-cluster-name={{ .Values.inventory.clusterName }}"\n            {{- end}}\n            {{- if .Values.inventory.certSecretName }}\n            - "--inventory-cert-path=/etc/jupyter-k8s/inventory-tls"\n            {{- end}}\n            {{- end}}\n            {{- with .Values.workspaceScope.matchLabels }}\n            {{- $selector := list }}\n            {{- range $key, $value := . }}\n            {{- $selector = append $selector (printf "%s=%s" $key $value) }}\n            {{- end }}\n            - "--workspace-selector={{ join "," $selector }}"\n            {{- end}}\n            {{- if .Values.workspaceScope.templateNamespaces }}\n            - "--template-namespaces={{ join "," .Values.workspaceScope.templateNamespaces }}"\n            {{- end}}\n            {{- if .Values.childEventMirroring.enable }}\n            - "--mirror-child-events"\n            - "--mirror-child-event-types={{ join "," .Values.childEventMirroring.types }}"\n            - "--mirror-child-event-reasons={{ join "," .Values.childEventMirroring.reasons }}"\n            {{- if .Values.childEventMirroring.mirrorRepeats }}\n            - "--mirror-repeated-child-events"\n            {{- end}}\n            {{- end}}\n            {{- if .Values.templateLabelMigration.legacyLabelKeys }}\n            - "--legacy-template-label-keys={{ join "," .Values.templateLabelMigration.legacyLabelKeys }}"\n            {{- end}}\n            {{- if and .Values.webhook.enable .Values.webhook.manageConfigurations }}\n            - "--manage-webhook-configurations"\n            {{- with .Values.webhook.namespaceSelector }}\n            {{- $selector := list }}\n            {{- range $key, $value := . }}\n            {{- $selector = append $selector (printf "%s=%s" $key $value) }}\n            {{- end }}\n            - "--webhook-namespace-selector={{ join "," $selector }}"\n            {{- end}}\n            {{- with .Values.webhook.objectSelector }}\n            {{- $selector := list }}\n            {{- range $key, $value := . }}\n            {{- $selector = append $selector (printf "%s=%s" $key $value) }}\n            {{- end }}\n            - "--webhook-object-selector={{ join "," $selector }}"\n            {{- end}}\n            {{- end}}\n            {{- if .Values.controller.plugins }}\n            - "--plugin-endpoints={{ range $i, $p := .Values.controller.plugins }}{{ if $i }},{{ end }}{{ $p.name }}=http://localhost:{{ $p.port }}{{ end }}"\n            {{- if .Values.controller.pluginReachabilityWindow }}\n            - "--plugin-reachability-window={{ .Values.controller.pluginReachabilityWindow }}"\n            {{- end}}\n            {{- end}}
                 }' "${MANAGER_YAML}"
                 fi
                 # Also add extension API volume mount if not already present
@@ -559,6 +699,180 @@ if [ -f "${CERT_YAML}" ]; then
     echo "Updated issuer name to jupyter-k8s-selfsigned-issuer"
 fi
 
+# Scope the workspace webhooks to the workspaces of this operator instance (workspaceScope.matchLabels)
+echo "Patching webhook/webhooks.yaml..."
+WEBHOOKS_YAML="${CHART_DIR}/templates/webhook/webhooks.yaml"
+if [ -f "${WEBHOOKS_YAML}" ]; then
+    if ! grep -q "workspaceScope.matchLabels" "${WEBHOOKS_YAML}"; then
+        for webhook in mworkspace-v1alpha1 vworkspace-v1alpha1 vworkspace-status-v1alpha1; do
+            if [[ "$OSTYPE" == "darwin"* ]]; then
+                sed -i '' '/- name: '"${webhook}"'\.kb\.io$/,/sideEffects: None$/ {
+                    /sideEffects: None$/a\
+    {{- with .Values.workspaceScope.matchLabels }}\
+    objectSelector:\
+      matchLabels:\
+        {{- toYaml . | nindent 8 }}\
+    {{- end }}
+                }' "${WEBHOOKS_YAML}"
+            else
+                sed -i '/- name: '"${webhook}"'\.kb\.io$/,/sideEffects: None$/ {
+                    /sideEffects: None$/a\    {{- with .Values.workspaceScope.matchLabels }}\n    objectSelector:\n      matchLabels:\n        {{- toYaml . | nindent 8 }}\n    {{- end }}
+                }' "${WEBHOOKS_YAML}"
+            fi
+        done
+        echo "Added the workspace scope objectSelector to the workspace webhooks"
+    fi
+    # Call the mutating webhook again after other webhooks changed the workspace
+    if ! grep -q "reinvocationPolicy:" "${WEBHOOKS_YAML}"; then
+        if [[ "$OSTYPE" == "darwin"* ]]; then
+            sed -i '' '/- name: mworkspace-v1alpha1\.kb\.io$/,/sideEffects: None$/ {
+                /sideEffects: None$/a\
+    reinvocationPolicy: IfNeeded
+            }' "${WEBHOOKS_YAML}"
+        else
+            sed -i '/- name: mworkspace-v1alpha1\.kb\.io$/,/sideEffects: None$/ {
+                /sideEffects: None$/a\    reinvocationPolicy: IfNeeded
+            }' "${WEBHOOKS_YAML}"
+        fi
+        echo "Set the reinvocation policy of the workspace mutating webhook"
+    fi
+fi
+
+# Leave the webhook configurations and serving certificate to the operator when it manages them
+echo "Patching the webhook configurations for webhook.manageConfigurations..."
+if ! grep -q "webhook.manageConfigurations" "${WEBHOOKS_YAML}"; then
+    if [[ "$OSTYPE" == "darwin"* ]]; then
+        sed -i '' '1s/^{{- if .Values.webhook.enable }}$/{{- if and .Values.webhook.enable (not .Values.webhook.manageConfigurations) }}/' "${WEBHOOKS_YAML}"
+        sed -i '' 's/^{{- if .Values.webhook.enable }}$/{{- if and .Values.webhook.enable (not .Values.webhook.manageConfigurations) }}/' "${CERT_YAML}"
+        sed -i '' 's/{{- if and .Values.webhook.enable .Values.certmanager.enable }}/{{- if and .Values.webhook.enable .Values.certmanager.enable (not .Values.webhook.manageConfigurations) }}/' "${CHART_DIR}/templates/manager/manager.yaml"
+    else
+        sed -i '1s/^{{- if .Values.webhook.enable }}$/{{- if and .Values.webhook.enable (not .Values.webhook.manageConfigurations) }}/' "${WEBHOOKS_YAML}"
+        sed -i 's/^{{- if .Values.webhook.enable }}$/{{- if and .Values.webhook.enable (not .Values.webhook.manageConfigurations) }}/' "${CERT_YAML}"
+        sed -i 's/{{- if and .Values.webhook.enable .Values.certmanager.enable }}/{{- if and .Values.webhook.enable .Values.certmanager.enable (not .Values.webhook.manageConfigurations) }}/' "${CHART_DIR}/templates/manager/manager.yaml"
+    fi
+    echo "Made the webhook configurations and certificate conditional on webhook.manageConfigurations"
+fi
+
+# Serve the workspace inventory on its own port, with the tokens Secret mounted (inventory.enable)
+echo "Creating inventory Service template..."
+mkdir -p "${CHART_DIR}/templates/inventory"
+cat > "${CHART_DIR}/templates/inventory/inventory-service.yaml" << 'INVENTORYEOF'
+{{- if .Values.inventory.enable }}
+apiVersion: v1
+kind: Service
+metadata:
+  name: jupyter-k8s-controller-manager-inventory-service
+  namespace: {{ .Release.Namespace }}
+  labels:
+    {{- include "chart.labels" . | nindent 4 }}
+    control-plane: controller-manager
+spec:
+  ports:
+    - port: {{ .Values.inventory.port }}
+      targetPort: inventory
+      protocol: TCP
+      name: {{ if .Values.inventory.certSecretName }}https{{ else }}http{{ end }}
+  selector:
+    control-plane: controller-manager
+{{- end }}
+INVENTORYEOF
+MANAGER_YAML="${CHART_DIR}/templates/manager/manager.yaml"
+if ! grep -q "inventory-tokens" "${MANAGER_YAML}"; then
+    if [[ "$OSTYPE" == "darwin"* ]]; then
+        sed -i '' 's/^          {{- if .Values.webhook.enable }}$/          {{- if or .Values.webhook.enable .Values.inventory.enable }}/' "${MANAGER_YAML}"
+        sed -i '' '/^            - containerPort: 9443$/i\
+            {{- if .Values.webhook.enable }}
+' "${MANAGER_YAML}"
+        sed -i '' '/name: webhook-server$/,/protocol: TCP$/ {
+            /protocol: TCP$/a\
+            {{- end }}\
+            {{- if .Values.inventory.enable }}\
+            - containerPort: {{ .Values.inventory.port }}\
+              name: inventory\
+              protocol: TCP\
+            {{- end }}
+        }' "${MANAGER_YAML}"
+        sed -i '' 's/{{- if and .Values.certmanager.enable (or .Values.webhook.enable .Values.metrics.enable .Values.extensionApi.enable) }}/{{- if or .Values.inventory.enable (and .Values.certmanager.enable (or .Values.webhook.enable .Values.metrics.enable .Values.extensionApi.enable)) }}/g' "${MANAGER_YAML}"
+        sed -i '' '/^            {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\
+            {{- if .Values.inventory.enable }}\
+            - name: inventory-tokens\
+              mountPath: /etc/jupyter-k8s/inventory\
+              readOnly: true\
+            {{- if .Values.inventory.certSecretName }}\
+            - name: inventory-tls\
+              mountPath: /etc/jupyter-k8s/inventory-tls\
+              readOnly: true\
+            {{- end }}\
+            {{- end }}
+' "${MANAGER_YAML}"
+        sed -i '' '/^        {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\
+        {{- if .Values.inventory.enable }}\
+        - name: inventory-tokens\
+          secret:\
+            secretName: {{ .Values.inventory.tokenSecretName }}\
+        {{- if .Values.inventory.certSecretName }}\
+        - name: inventory-tls\
+          secret:\
+            secretName: {{ .Values.inventory.certSecretName }}\
+        {{- end }}\
+        {{- end }}
+' "${MANAGER_YAML}"
+    else
+        sed -i 's/^          {{- if .Values.webhook.enable }}$/          {{- if or .Values.webhook.enable .Values.inventory.enable }}/' "${MANAGER_YAML}"
+        sed -i '/^            - containerPort: 9443$/i\            {{- if .Values.webhook.enable }}' "${MANAGER_YAML}"
+        sed -i '/name: webhook-server$/,/protocol: TCP$/ {
+            /protocol: TCP$/a\            {{- end }}\n            {{- if .Values.inventory.enable }}\n            - containerPort: {{ .Values.inventory.port }}\n              name: inventory\n              protocol: TCP\n            {{- end }}
+        }' "${MANAGER_YAML}"
+        sed -i 's/{{- if and .Values.certmanager.enable (or .Values.webhook.enable .Values.metrics.enable .Values.extensionApi.enable) }}/{{- if or .Values.inventory.enable (and .Values.certmanager.enable (or .Values.webhook.enable .Values.metrics.enable .Values.extensionApi.enable)) }}/g' "${MANAGER_YAML}"
+        sed -i '/^            {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\            {{- if .Values.inventory.enable }}\n            - name: inventory-tokens\n              mountPath: /etc/jupyter-k8s/inventory\n              readOnly: true\n            {{- if .Values.inventory.certSecretName }}\n            - name: inventory-tls\n              mountPath: /etc/jupyter-k8s/inventory-tls\n              readOnly: true\n            {{- end }}\n            {{- end }}' "${MANAGER_YAML}"
+        sed -i '/^        {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\        {{- if .Values.inventory.enable }}\n        - name: inventory-tokens\n          secret:\n            secretName: {{ .Values.inventory.tokenSecretName }}\n        {{- if .Values.inventory.certSecretName }}\n        - name: inventory-tls\n          secret:\n            secretName: {{ .Values.inventory.certSecretName }}\n        {{- end }}\n        {{- end }}' "${MANAGER_YAML}"
+    fi
+    echo "Added the inventory port, tokens volume and TLS volume to manager.yaml"
+fi
+
+# Mount the cleanup hooks configuration rendered from cleanupHooks
+echo "Creating cleanup hooks ConfigMap template..."
+mkdir -p "${CHART_DIR}/templates/cleanup-hooks"
+cat > "${CHART_DIR}/templates/cleanup-hooks/cleanup-hooks-configmap.yaml" << 'CLEANUPHOOKSEOF'
+{{- if .Values.cleanupHooks }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: jupyter-k8s-cleanup-hooks
+  namespace: {{ .Release.Namespace }}
+  labels:
+    {{- include "chart.labels" . | nindent 4 }}
+    control-plane: controller-manager
+data:
+  hooks.yaml: |
+    {{- dict "hooks" .Values.cleanupHooks | toYaml | nindent 4 }}
+{{- end }}
+CLEANUPHOOKSEOF
+if ! grep -q "name: cleanup-hooks$" "${MANAGER_YAML}"; then
+    if [[ "$OSTYPE" == "darwin"* ]]; then
+        sed -i '' 's/{{- if or .Values.inventory.enable (and/{{- if or .Values.inventory.enable .Values.cleanupHooks (and/g' "${MANAGER_YAML}"
+        sed -i '' '/^            {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\
+            {{- if .Values.cleanupHooks }}\
+            - name: cleanup-hooks\
+              mountPath: /etc/jupyter-k8s/cleanup-hooks\
+              readOnly: true\
+            {{- end }}
+' "${MANAGER_YAML}"
+        sed -i '' '/^        {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\
+        {{- if .Values.cleanupHooks }}\
+        - name: cleanup-hooks\
+          configMap:\
+            name: jupyter-k8s-cleanup-hooks\
+        {{- end }}
+' "${MANAGER_YAML}"
+    else
+        sed -i 's/{{- if or .Values.inventory.enable (and/{{- if or .Values.inventory.enable .Values.cleanupHooks (and/g' "${MANAGER_YAML}"
+        sed -i '/^            {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\            {{- if .Values.cleanupHooks }}\n            - name: cleanup-hooks\n              mountPath: /etc/jupyter-k8s/cleanup-hooks\n              readOnly: true\n            {{- end }}' "${MANAGER_YAML}"
+        sed -i '/^        {{- if and .Values.extensionApi.enable .Values.certmanager.enable }}$/i\        {{- if .Values.cleanupHooks }}\n        - name: cleanup-hooks\n          configMap:\n            name: jupyter-k8s-cleanup-hooks\n        {{- end }}' "${MANAGER_YAML}"
+    fi
+    echo "Added the cleanup hooks volume to manager.yaml"
+fi
+
 # Handle manager labels patch
 if [ -f "${PATCHES_DIR}/manager-labels.yaml.patch" ]; then
     MANAGER_YAML="${CHART_DIR}/templates/manager/manager.yaml"
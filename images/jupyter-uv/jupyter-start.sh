@@ -2,15 +2,18 @@
 set -e
 
 BASE_URL="${JUPYTER_BASE_URL:-/}"
+# The operator sets JUPYTER_ROOT_DIR when the template mounts the home volume elsewhere
+ROOT_DIR="${JUPYTER_ROOT_DIR:-/home/jovyan}"
+cd "$ROOT_DIR"
 
 echo "Setting up uv environment..."
-cp /opt/uv/jupyter/pyproject.toml /home/jovyan/
-cp /opt/uv/jupyter/uv.lock /home/jovyan/
+cp /opt/uv/jupyter/pyproject.toml "$ROOT_DIR/"
+cp /opt/uv/jupyter/uv.lock "$ROOT_DIR/"
 
-if [ ! -f "/home/jovyan/pyproject.toml" ] || [ ! -f "/home/jovyan/uv.lock" ]; then
-    echo "Did not find uv environment files in /home/jovyan."
-    cp /opt/uv/jupyter/pyproject.toml /home/jovyan/
-    cp /opt/uv/jupyter/uv.lock /home/jovyan/
+if [ ! -f "$ROOT_DIR/pyproject.toml" ] || [ ! -f "$ROOT_DIR/uv.lock" ]; then
+    echo "Did not find uv environment files in $ROOT_DIR."
+    cp /opt/uv/jupyter/pyproject.toml "$ROOT_DIR/"
+    cp /opt/uv/jupyter/uv.lock "$ROOT_DIR/"
 else
     echo "Found existing uv environment files, syncing..."
 fi
@@ -23,7 +26,8 @@ uv run jupyter lab \
     --no-browser \
     --ip=0.0.0.0 \
     --IdentityProvider.token= \
-    --ServerApp.base_url="$BASE_URL"
+    --ServerApp.base_url="$BASE_URL" \
+    --ServerApp.root_dir="$ROOT_DIR"
 
 # captures jupyterlab exit code
 jupyter_exit_code=$?
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package buildinfo reports the version of the operator binary and the optional features
+// enabled in its runtime configuration. The version fields are set at build time, e.g.
+//
+//	go build -ldflags "-X github.com/jupyter-infra/jupyter-k8s/internal/buildinfo.Version=v0.3.0"
+package buildinfo
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Set at build time with -ldflags -X
+var (
+	// Version is the release version of the binary
+	Version = "dev"
+	// GitCommit is the commit the binary was built from
+	GitCommit = "unknown"
+	// BuildDate is the RFC 3339 time the binary was built at
+	BuildDate = "unknown"
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildDate string `json:"buildDate"`
+	GoVersion string `json:"goVersion"`
+	// Features maps each optional feature to its configured value, e.g. "ingress": "traefik"
+	Features map[string]string `json:"features,omitempty"`
+}
+
+var (
+	mu       sync.RWMutex
+	features map[string]string
+)
+
+// SetFeatures records the optional features of the runtime configuration, replacing any previous set
+func SetFeatures(values map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	features = maps.Clone(values)
+
+	featureInfo.Reset()
+	for feature, value := range features {
+		featureInfo.WithLabelValues(feature, value).Set(1)
+	}
+}
+
+// Get returns the build and feature information of the running binary
+func Get() Info {
+	mu.RLock()
+	defer mu.RUnlock()
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Features:  maps.Clone(features),
+	}
+}
+
+// FeatureNames returns the names of the recorded features in sorted order
+func (i Info) FeatureNames() []string {
+	names := make([]string, 0, len(i.Features))
+	for name := range i.Features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Handler serves the information as JSON, for the /version endpoint
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Get()); err != nil {
+			http.Error(w, "failed to encode build info", http.StatusInternalServerError)
+		}
+	})
+}
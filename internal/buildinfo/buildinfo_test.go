@@ -0,0 +1,55 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReportsFeaturesWithoutSharingThem(t *testing.T) {
+	values := map[string]string{"ingress": "traefik", "extensionApi": "true"}
+	SetFeatures(values)
+	values["ingress"] = "none"
+
+	info := Get()
+	assert.Equal(t, Version, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, "traefik", info.Features["ingress"])
+	assert.Equal(t, []string{"extensionApi", "ingress"}, info.FeatureNames())
+
+	info.Features["ingress"] = "changed"
+	assert.Equal(t, "traefik", Get().Features["ingress"])
+}
+
+func TestSetFeaturesReplacesMetricSeries(t *testing.T) {
+	SetFeatures(map[string]string{"ingress": "traefik"})
+	SetFeatures(map[string]string{"ingress": "none"})
+
+	assert.Equal(t, 1, testutil.CollectAndCount(featureInfo))
+	assert.Equal(t, float64(1), testutil.ToFloat64(featureInfo.WithLabelValues("ingress", "none")))
+	assert.Equal(t, 1, testutil.CollectAndCount(buildInfo))
+}
+
+func TestHandlerServesJSON(t *testing.T) {
+	SetFeatures(map[string]string{"podWatching": "false"})
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var info Info
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &info))
+	assert.Equal(t, Get(), info)
+}
@@ -0,0 +1,30 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package buildinfo
+
+import (
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// buildInfo is always 1, the build details are in its labels
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "jupyter_k8s_build_info",
+	Help: "Build information of the jupyter-k8s operator, always 1",
+}, []string{"version", "git_commit", "build_date", "go_version"})
+
+// featureInfo has one series set to 1 per optional feature and its configured value
+var featureInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "jupyter_k8s_feature_info",
+	Help: "Optional features of the jupyter-k8s operator runtime configuration, always 1",
+}, []string{"feature", "value"})
+
+func init() {
+	metrics.Registry.MustRegister(buildInfo, featureInfo)
+	buildInfo.WithLabelValues(Version, GitCommit, BuildDate, runtime.Version()).Set(1)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package bundle implements portable export/import of workspaces across clusters.
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// APIVersion is the version of the bundle document format
+	APIVersion = "bundle.workspace.jupyter.org/v1alpha1"
+
+	// Kind is the kind of the bundle document
+	Kind = "WorkspaceBundle"
+
+	// TemplateNameSuffix is appended to the workspace name to name the inlined template snapshot
+	TemplateNameSuffix = "-template"
+
+	// reservedMetadataPrefix marks labels and annotations managed by the controller and webhooks
+	reservedMetadataPrefix = "workspace.jupyter.org/"
+)
+
+// Metadata describes where a bundle came from
+type Metadata struct {
+	// SourceNamespace is the namespace the workspace was exported from
+	SourceNamespace string `json:"sourceNamespace"`
+
+	// SourceName is the name of the exported workspace
+	SourceName string `json:"sourceName"`
+
+	// SourceTemplate is the namespaced name of the template the snapshot was taken from
+	// +optional
+	SourceTemplate string `json:"sourceTemplate,omitempty"`
+
+	// ExportedAt is the time the bundle was produced
+	ExportedAt metav1.Time `json:"exportedAt"`
+
+	// ImageDigestPinned indicates that the workspace image was replaced by the digest of the running image
+	// +optional
+	ImageDigestPinned bool `json:"imageDigestPinned,omitempty"`
+
+	// DataArchiveRef optionally points at an archive of the workspace data (e.g. an object store URL)
+	// +optional
+	DataArchiveRef string `json:"dataArchiveRef,omitempty"`
+}
+
+// Bundle is a self-contained description of a workspace and the effective template it was created from
+type Bundle struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+
+	// Metadata describes the origin of the bundle
+	Metadata Metadata `json:"metadata"`
+
+	// Template is a snapshot of the workspace's effective template, if the workspace uses one
+	// +optional
+	Template *workspacev1alpha1.WorkspaceTemplate `json:"template,omitempty"`
+
+	// Workspace is the workspace with cluster-specific fields stripped
+	Workspace workspacev1alpha1.Workspace `json:"workspace"`
+}
+
+// Encode serializes a bundle to YAML
+func Encode(b *Bundle) ([]byte, error) {
+	data, err := yaml.Marshal(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	return data, nil
+}
+
+// Decode parses a YAML bundle and checks its format version
+func Decode(data []byte) (*Bundle, error) {
+	b := &Bundle{}
+	if err := yaml.UnmarshalStrict(data, b); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	if b.APIVersion != APIVersion || b.Kind != Kind {
+		return nil, fmt.Errorf("unsupported bundle %s/%s, expected %s/%s", b.APIVersion, b.Kind, APIVersion, Kind)
+	}
+	if b.Workspace.Name == "" {
+		return nil, fmt.Errorf("bundle does not contain a workspace name")
+	}
+	return b, nil
+}
+
+// GenerateTemplateName returns the name of the template snapshot for a workspace
+func GenerateTemplateName(workspaceName string) string {
+	return workspaceName + TemplateNameSuffix
+}
+
+// cleanObjectMeta keeps only the portable parts of an object's metadata
+func cleanObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        meta.Name,
+		Labels:      filterPortableMetadata(meta.Labels),
+		Annotations: filterPortableMetadata(meta.Annotations),
+	}
+}
+
+// filterPortableMetadata drops keys managed by the operator and kubectl bookkeeping
+func filterPortableMetadata(in map[string]string) map[string]string {
+	var out map[string]string
+	for key, value := range in {
+		if strings.HasPrefix(key, reservedMetadataPrefix) || key == "kubectl.kubernetes.io/last-applied-configuration" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// pinImageDigest replaces the tag of an image reference with the digest found in a container status imageID.
+// Returns the original image if the imageID does not carry a digest.
+func pinImageDigest(image string, imageID string) (string, bool) {
+	at := strings.LastIndex(imageID, "@")
+	if image == "" || at < 0 {
+		return image, false
+	}
+	digest := imageID[at+1:]
+
+	repository := image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+	// A colon after the last slash separates the tag (a colon before it is a registry port)
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+
+	return repository + "@" + digest, true
+}
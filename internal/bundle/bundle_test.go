@@ -0,0 +1,265 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	return scheme
+}
+
+func sourceObjects() []client.Object {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "gpu-template",
+			Namespace:       "shared",
+			UID:             "template-uid",
+			ResourceVersion: "42",
+			Finalizers:      []string{workspaceutil.TemplateFinalizerName},
+			Labels:          map[string]string{"team": "ml"},
+		},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:         "GPU",
+			DefaultImage:        "jupyter/scipy-notebook:latest",
+			DefaultNodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p3.2xlarge"},
+		},
+		Status: workspacev1alpha1.WorkspaceTemplateStatus{ObservedGeneration: 3},
+	}
+
+	ws := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "analysis",
+			Namespace:       "alice",
+			UID:             "workspace-uid",
+			ResourceVersion: "7",
+			Finalizers:      []string{"workspace.jupyter.org/workspace-protection"},
+			Labels: map[string]string{
+				"project":                            "thesis",
+				workspaceutil.LabelWorkspaceTemplate: "gpu-template",
+				workspaceutil.LabelWorkspaceTemplateNamespace: "shared",
+			},
+			Annotations: map[string]string{
+				"workspace.jupyter.org/created-by": "alice",
+				"note":                             "keep",
+			},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:  "Analysis",
+			Image:        "registry.example.com:5000/jupyter/scipy-notebook:latest",
+			TemplateRef:  &workspacev1alpha1.TemplateRef{Name: "gpu-template", Namespace: "shared"},
+			NodeSelector: map[string]string{"node.kubernetes.io/instance-type": "p3.2xlarge"},
+			Storage:      &workspacev1alpha1.StorageSpec{Size: resource.MustParse("20Gi")},
+		},
+		Status: workspacev1alpha1.WorkspaceStatus{DeploymentName: "workspace-analysis"},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "workspace-analysis-abc",
+			Namespace: "alice",
+			Labels:    map[string]string{workspaceutil.LabelWorkspaceName: "analysis"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "sidecar", ImageID: "docker.io/library/busybox@sha256:1111"},
+				{Name: "workspace", ImageID: "registry.example.com:5000/jupyter/scipy-notebook@sha256:abcd"},
+			},
+		},
+	}
+
+	return []client.Object{template, ws, pod}
+}
+
+func TestExportStripsClusterSpecificFields(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(sourceObjects()...).Build()
+
+	b, err := Export(context.Background(), k8sClient, "alice", "analysis", ExportOptions{DataArchiveRef: "s3://bucket/analysis.tgz"})
+	require.NoError(t, err)
+
+	assert.Equal(t, APIVersion, b.APIVersion)
+	assert.Equal(t, Kind, b.Kind)
+	assert.Equal(t, "alice", b.Metadata.SourceNamespace)
+	assert.Equal(t, "shared/gpu-template", b.Metadata.SourceTemplate)
+	assert.Equal(t, "s3://bucket/analysis.tgz", b.Metadata.DataArchiveRef)
+
+	ws := b.Workspace
+	assert.Equal(t, "analysis", ws.Name)
+	assert.Empty(t, ws.Namespace)
+	assert.Empty(t, ws.UID)
+	assert.Empty(t, ws.ResourceVersion)
+	assert.Empty(t, ws.Finalizers)
+	assert.Equal(t, workspacev1alpha1.WorkspaceStatus{}, ws.Status)
+	assert.Equal(t, map[string]string{"project": "thesis"}, ws.Labels)
+	assert.Equal(t, map[string]string{"note": "keep"}, ws.Annotations)
+	assert.Nil(t, ws.Spec.NodeSelector)
+	assert.Equal(t, &workspacev1alpha1.TemplateRef{Name: "analysis-template"}, ws.Spec.TemplateRef)
+
+	require.NotNil(t, b.Template)
+	assert.Equal(t, "analysis-template", b.Template.Name)
+	assert.Empty(t, b.Template.Namespace)
+	assert.Empty(t, b.Template.Finalizers)
+	assert.Equal(t, int64(0), b.Template.Status.ObservedGeneration)
+	assert.Equal(t, "GPU", b.Template.Spec.DisplayName)
+	assert.Nil(t, b.Template.Spec.DefaultNodeSelector)
+}
+
+func TestExportPinsImageDigest(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(sourceObjects()...).Build()
+
+	b, err := Export(context.Background(), k8sClient, "alice", "analysis", ExportOptions{})
+	require.NoError(t, err)
+
+	assert.True(t, b.Metadata.ImageDigestPinned)
+	assert.Equal(t, "registry.example.com:5000/jupyter/scipy-notebook@sha256:abcd", b.Workspace.Spec.Image)
+}
+
+func TestExportKeepsSchedulingWhenRequested(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newScheme(t)).WithObjects(sourceObjects()...).Build()
+
+	b, err := Export(context.Background(), k8sClient, "alice", "analysis", ExportOptions{IncludeScheduling: true})
+	require.NoError(t, err)
+
+	assert.Equal(t, "p3.2xlarge", b.Workspace.Spec.NodeSelector["node.kubernetes.io/instance-type"])
+	assert.Equal(t, "p3.2xlarge", b.Template.Spec.DefaultNodeSelector["node.kubernetes.io/instance-type"])
+}
+
+func TestExportWorkspaceNotFound(t *testing.T) {
+	k8sClient := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+
+	_, err := Export(context.Background(), k8sClient, "alice", "missing", ExportOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get workspace alice/missing")
+}
+
+func TestRoundTrip(t *testing.T) {
+	scheme := newScheme(t)
+	source := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sourceObjects()...).Build()
+	target := fake.NewClientBuilder().WithScheme(scheme).Build()
+	ctx := context.Background()
+
+	exported, err := Export(ctx, source, "alice", "analysis", ExportOptions{})
+	require.NoError(t, err)
+
+	data, err := Encode(exported)
+	require.NoError(t, err)
+
+	decoded, err := Decode(data)
+	require.NoError(t, err)
+
+	ws, template, err := Import(ctx, target, decoded, ImportOptions{Namespace: "bob", Name: "analysis-copy"})
+	require.NoError(t, err)
+	require.NotNil(t, template)
+
+	created := &workspacev1alpha1.Workspace{}
+	require.NoError(t, target.Get(ctx, client.ObjectKey{Namespace: "bob", Name: "analysis-copy"}, created))
+	assert.Equal(t, ws.Spec.Image, created.Spec.Image)
+	assert.Equal(t, "Analysis", created.Spec.DisplayName)
+	assert.True(t, resource.MustParse("20Gi").Equal(created.Spec.Storage.Size))
+	assert.Equal(t, &workspacev1alpha1.TemplateRef{Name: "analysis-copy-template"}, created.Spec.TemplateRef)
+
+	createdTemplate := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, target.Get(ctx, client.ObjectKey{Namespace: "bob", Name: "analysis-copy-template"}, createdTemplate))
+	assert.Equal(t, "GPU", createdTemplate.Spec.DisplayName)
+	assert.Equal(t, "jupyter/scipy-notebook:latest", createdTemplate.Spec.DefaultImage)
+}
+
+func TestImportDetectsConflicts(t *testing.T) {
+	scheme := newScheme(t)
+	ctx := context.Background()
+	source := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sourceObjects()...).Build()
+
+	b, err := Export(ctx, source, "alice", "analysis", ExportOptions{})
+	require.NoError(t, err)
+
+	t.Run("workspace exists", func(t *testing.T) {
+		existing := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "analysis", Namespace: "bob"}}
+		target := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		_, _, err := Import(ctx, target, b, ImportOptions{Namespace: "bob"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrAlreadyExists))
+	})
+
+	t.Run("template exists", func(t *testing.T) {
+		existing := &workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "analysis-template", Namespace: "bob"}}
+		target := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		_, _, err := Import(ctx, target, b, ImportOptions{Namespace: "bob"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrAlreadyExists))
+
+		// Nothing must have been created
+		ws := &workspacev1alpha1.Workspace{}
+		assert.Error(t, target.Get(ctx, client.ObjectKey{Namespace: "bob", Name: "analysis"}, ws))
+	})
+
+	t.Run("renaming avoids the conflict", func(t *testing.T) {
+		existing := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "analysis", Namespace: "bob"}}
+		target := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+
+		_, _, err := Import(ctx, target, b, ImportOptions{Namespace: "bob", Name: "analysis-2"})
+		require.NoError(t, err)
+	})
+}
+
+func TestImportRequiresNamespace(t *testing.T) {
+	target := fake.NewClientBuilder().WithScheme(newScheme(t)).Build()
+
+	_, _, err := Import(context.Background(), target, &Bundle{}, ImportOptions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "target namespace is required")
+}
+
+func TestDecodeRejectsUnknownFormat(t *testing.T) {
+	_, err := Decode([]byte("apiVersion: v1\nkind: ConfigMap\nworkspace:\n  metadata:\n    name: x\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported bundle")
+}
+
+func TestPinImageDigest(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		imageID  string
+		expected string
+		pinned   bool
+	}{
+		{"tagged image", "jupyter/base:2024.1", "docker.io/jupyter/base@sha256:aa", "jupyter/base@sha256:aa", true},
+		{"untagged image", "jupyter/base", "docker-pullable://jupyter/base@sha256:bb", "jupyter/base@sha256:bb", true},
+		{"registry with port", "host:5000/base:v1", "host:5000/base@sha256:cc", "host:5000/base@sha256:cc", true},
+		{"already pinned", "base@sha256:old", "base@sha256:new", "base@sha256:new", true},
+		{"no digest", "base:v1", "sha256:dd", "base:v1", false},
+		{"no image id", "base:v1", "", "base:v1", false},
+		{"empty image", "", "base@sha256:ee", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pinned := pinImageDigest(tt.image, tt.imageID)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.pinned, pinned)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// workspaceContainerName is the name of the primary container in workspace pods
+const workspaceContainerName = "workspace"
+
+// ExportOptions controls which cluster-specific details are kept in an exported bundle
+type ExportOptions struct {
+	// IncludeScheduling keeps node selectors, affinity and tolerations, which are usually cluster-specific
+	IncludeScheduling bool
+
+	// DataArchiveRef is recorded in the bundle metadata as a pointer to the workspace data
+	DataArchiveRef string
+
+	// DefaultTemplateNamespace is used to resolve templates when templateRef.namespace is not found
+	DefaultTemplateNamespace string
+}
+
+// Export builds a bundle for the named workspace.
+// The workspace's template is resolved and inlined as a new template object, and the image is pinned
+// to the digest of the running workspace container when a pod is available.
+func Export(ctx context.Context, k8sClient client.Client, namespace, name string, opts ExportOptions) (*Bundle, error) {
+	ws := &workspacev1alpha1.Workspace{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, ws); err != nil {
+		return nil, fmt.Errorf("failed to get workspace %s/%s: %w", namespace, name, err)
+	}
+
+	b := &Bundle{
+		APIVersion: APIVersion,
+		Kind:       Kind,
+		Metadata: Metadata{
+			SourceNamespace: namespace,
+			SourceName:      name,
+			ExportedAt:      metav1.Now(),
+			DataArchiveRef:  opts.DataArchiveRef,
+		},
+	}
+
+	exported := &workspacev1alpha1.Workspace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: workspacev1alpha1.GroupVersion.String(),
+			Kind:       "Workspace",
+		},
+		ObjectMeta: cleanObjectMeta(ws.ObjectMeta),
+		Spec:       *ws.Spec.DeepCopy(),
+	}
+
+	if ws.Spec.TemplateRef != nil && ws.Spec.TemplateRef.Name != "" {
+		resolver := workspaceutil.NewTemplateResolver(k8sClient, opts.DefaultTemplateNamespace)
+		template, err := resolver.ResolveTemplateForWorkspace(ctx, ws)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve template for workspace %s/%s: %w", namespace, name, err)
+		}
+
+		b.Metadata.SourceTemplate = template.Namespace + "/" + template.Name
+		b.Template = &workspacev1alpha1.WorkspaceTemplate{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: workspacev1alpha1.GroupVersion.String(),
+				Kind:       "WorkspaceTemplate",
+			},
+			ObjectMeta: cleanObjectMeta(template.ObjectMeta),
+			Spec:       *template.Spec.DeepCopy(),
+		}
+		b.Template.Name = GenerateTemplateName(name)
+		exported.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: b.Template.Name}
+	}
+
+	if !opts.IncludeScheduling {
+		exported.Spec.NodeSelector = nil
+		exported.Spec.Affinity = nil
+		exported.Spec.Tolerations = nil
+		if b.Template != nil {
+			b.Template.Spec.DefaultNodeSelector = nil
+			b.Template.Spec.DefaultAffinity = nil
+			b.Template.Spec.DefaultTolerations = nil
+		}
+	}
+
+	imageID, err := findRunningImageID(ctx, k8sClient, ws)
+	if err != nil {
+		return nil, err
+	}
+	if pinned, ok := pinImageDigest(exported.Spec.Image, imageID); ok {
+		exported.Spec.Image = pinned
+		b.Metadata.ImageDigestPinned = true
+	}
+
+	b.Workspace = *exported
+	return b, nil
+}
+
+// findRunningImageID returns the imageID reported for the workspace container, or "" when no pod is running
+func findRunningImageID(ctx context.Context, k8sClient client.Client, ws *workspacev1alpha1.Workspace) (string, error) {
+	pods := &corev1.PodList{}
+	if err := k8sClient.List(ctx, pods,
+		client.InNamespace(ws.Namespace),
+		client.MatchingLabels{workspaceutil.LabelWorkspaceName: ws.Name}); err != nil {
+		return "", fmt.Errorf("failed to list pods for workspace %s/%s: %w", ws.Namespace, ws.Name, err)
+	}
+
+	for _, pod := range pods.Items {
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name == workspaceContainerName && cs.ImageID != "" {
+				return cs.ImageID, nil
+			}
+		}
+	}
+	return "", nil
+}
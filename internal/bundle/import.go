@@ -0,0 +1,99 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ErrAlreadyExists is returned by Import when an object of the bundle already exists in the target namespace
+var ErrAlreadyExists = errors.New("already exists")
+
+// ImportOptions controls where and under which name a bundle is applied
+type ImportOptions struct {
+	// Namespace is the target namespace
+	Namespace string
+
+	// Name renames the workspace (and its template snapshot). Defaults to the exported name.
+	Name string
+
+	// DryRun validates the objects against the API server without persisting them
+	DryRun bool
+}
+
+// Import creates the template snapshot and the workspace of a bundle in the target namespace.
+// Import never overwrites existing objects: it fails with ErrAlreadyExists before creating anything.
+func Import(
+	ctx context.Context,
+	k8sClient client.Client,
+	b *Bundle,
+	opts ImportOptions,
+) (*workspacev1alpha1.Workspace, *workspacev1alpha1.WorkspaceTemplate, error) {
+	if opts.Namespace == "" {
+		return nil, nil, fmt.Errorf("target namespace is required")
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = b.Workspace.Name
+	}
+
+	ws := b.Workspace.DeepCopy()
+	ws.Name = name
+	ws.Namespace = opts.Namespace
+
+	var template *workspacev1alpha1.WorkspaceTemplate
+	if b.Template != nil {
+		template = b.Template.DeepCopy()
+		template.Name = GenerateTemplateName(name)
+		template.Namespace = opts.Namespace
+		ws.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: template.Name}
+	}
+
+	if err := checkConflict(ctx, k8sClient, &workspacev1alpha1.Workspace{}, "workspace", opts.Namespace, name); err != nil {
+		return nil, nil, err
+	}
+	if template != nil {
+		if err := checkConflict(ctx, k8sClient, &workspacev1alpha1.WorkspaceTemplate{}, "template", opts.Namespace, template.Name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var createOpts []client.CreateOption
+	if opts.DryRun {
+		createOpts = append(createOpts, client.DryRunAll)
+	}
+
+	if template != nil {
+		if err := k8sClient.Create(ctx, template, createOpts...); err != nil {
+			return nil, nil, fmt.Errorf("failed to create template %s/%s: %w", template.Namespace, template.Name, err)
+		}
+	}
+	if err := k8sClient.Create(ctx, ws, createOpts...); err != nil {
+		return nil, nil, fmt.Errorf("failed to create workspace %s/%s: %w", ws.Namespace, ws.Name, err)
+	}
+
+	return ws, template, nil
+}
+
+// checkConflict returns ErrAlreadyExists if the object exists in the target namespace
+func checkConflict(ctx context.Context, k8sClient client.Client, obj client.Object, kind, namespace, name string) error {
+	err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)
+	if err == nil {
+		return fmt.Errorf("%s %s/%s %w", kind, namespace, name, ErrAlreadyExists)
+	}
+	if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to check for existing %s %s/%s: %w", kind, namespace, name, err)
+	}
+	return nil
+}
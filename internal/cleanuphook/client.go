@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package cleanuphook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// HeaderIdempotencyKey is the same for every call of a hook for a workspace, so that the hook can
+	// recognize retries of a deletion it already handles
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// maxErrorBodyLength bounds the part of a response body reported in errors, which end in the workspace status
+	maxErrorBodyLength = 256
+)
+
+// Request is the JSON body POSTed to a hook
+type Request struct {
+	// Hook is the name of the hook called
+	Hook string `json:"hook"`
+
+	// Workspace is the deleted workspace
+	Workspace Workspace `json:"workspace"`
+}
+
+// Workspace identifies a deleted workspace
+type Workspace struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+	// Owner is the user who created the workspace
+	Owner string `json:"owner,omitempty"`
+}
+
+// StatusError is returned when a hook answers with a status that neither acknowledges the deletion nor
+// reports it in progress
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("hook answered %d", e.Code)
+	}
+	return fmt.Sprintf("hook answered %d: %s", e.Code, e.Body)
+}
+
+// Client calls cleanup hooks
+type Client struct {
+	httpClient      *http.Client
+	secrets         client.Reader
+	secretNamespace string
+}
+
+// NewClient returns a client reading the bearer tokens of the hooks from the Secrets of secretNamespace
+func NewClient(secrets client.Reader, secretNamespace string) *Client {
+	return &Client{
+		httpClient:      &http.Client{},
+		secrets:         secrets,
+		secretNamespace: secretNamespace,
+	}
+}
+
+// Call POSTs the deletion of a workspace to a hook, within the timeout of the hook. It returns true when
+// the hook acknowledges the deletion with 200 or 204, and false when it reports it in progress with 202,
+// in which case the hook is called again. Calls are not retried here: the caller owns the retry policy.
+func (c *Client) Call(ctx context.Context, hook Hook, request Request) (bool, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal cleanup hook request: %w", err)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, hook.Timeout.Duration)
+	defer cancel()
+	httpReq, err := http.NewRequestWithContext(callCtx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create cleanup hook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderIdempotencyKey, request.Workspace.UID+"/"+hook.Name)
+	if hook.AuthSecretRef != nil {
+		token, err := c.readToken(ctx, hook.AuthSecretRef)
+		if err != nil {
+			return false, err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to call cleanup hook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return true, nil
+	case http.StatusAccepted:
+		return false, nil
+	}
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+	return false, &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+}
+
+// readToken reads the bearer token of a hook; it is read on every call, so that rotations apply right away
+func (c *Client) readToken(ctx context.Context, ref *SecretKeyRef) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.secrets.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: c.secretNamespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to read the token of cleanup hook from secret %s: %w", ref.Name, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok || len(token) == 0 {
+		return "", fmt.Errorf("secret %s has no key %s holding the token of cleanup hook", ref.Name, ref.Key)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
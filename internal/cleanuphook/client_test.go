@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package cleanuphook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestHook(url string) Hook {
+	hook := Hook{Name: "home-dirs", URL: url}
+	setDefaults(&hook)
+	return hook
+}
+
+var testRequest = Request{
+	Hook:      "home-dirs",
+	Workspace: Workspace{Name: "ws", Namespace: "team-a", UID: "ws-uid", Owner: "alice"},
+}
+
+func TestCallReportsTheAnswerOfTheHook(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		body         string
+		acknowledged bool
+		err          string
+	}{
+		{name: "acknowledged", status: http.StatusOK, acknowledged: true},
+		{name: "acknowledged without content", status: http.StatusNoContent, acknowledged: true},
+		{name: "in progress", status: http.StatusAccepted},
+		{name: "failed", status: http.StatusInternalServerError, body: "volume busy\n", err: "hook answered 500: volume busy"},
+		{name: "failed with a long body", status: http.StatusBadGateway, body: strings.Repeat("x", 1000),
+			err: "hook answered 502: " + strings.Repeat("x", maxErrorBodyLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			acknowledged, err := NewClient(fake.NewClientBuilder().Build(), "system").
+				Call(context.Background(), newTestHook(server.URL), testRequest)
+			assert.Equal(t, tt.acknowledged, acknowledged)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var statusErr *StatusError
+			require.True(t, errors.As(err, &statusErr), "expected a StatusError, got %v", err)
+			assert.Equal(t, tt.status, statusErr.Code)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestCallPostsTheWorkspaceWithItsToken(t *testing.T) {
+	var received *http.Request
+	var body Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "provisioner", Namespace: "system"},
+		Data:       map[string][]byte{"token": []byte("s3cret\n")},
+	}
+	hook := newTestHook(server.URL)
+	hook.AuthSecretRef = &SecretKeyRef{Name: "provisioner", Key: "token"}
+
+	acknowledged, err := NewClient(fake.NewClientBuilder().WithObjects(secret).Build(), "system").
+		Call(context.Background(), hook, testRequest)
+	require.NoError(t, err)
+	assert.True(t, acknowledged)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "Bearer s3cret", received.Header.Get("Authorization"))
+	assert.Equal(t, "ws-uid/home-dirs", received.Header.Get(HeaderIdempotencyKey))
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, testRequest, body)
+}
+
+func TestCallFailsWithoutItsToken(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+	hook := newTestHook(server.URL)
+	hook.AuthSecretRef = &SecretKeyRef{Name: "provisioner", Key: "token"}
+
+	acknowledged, err := NewClient(fake.NewClientBuilder().Build(), "system").
+		Call(context.Background(), hook, testRequest)
+	assert.False(t, acknowledged)
+	assert.ErrorContains(t, err, "secret provisioner")
+	assert.False(t, called, "the hook is not called without its token")
+}
+
+func TestCallTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	hook := newTestHook(server.URL)
+	hook.Timeout = metav1.Duration{Duration: 50 * time.Millisecond}
+
+	start := time.Now()
+	acknowledged, err := NewClient(fake.NewClientBuilder().Build(), "system").
+		Call(context.Background(), hook, testRequest)
+	assert.False(t, acknowledged)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second, "the call is bounded by the timeout of the hook")
+}
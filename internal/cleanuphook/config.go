@@ -0,0 +1,144 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package cleanuphook calls the external cleanup hooks of the operator configuration, e.g. a home
+// directory provisioner, when a workspace is deleted. The controller calls them while it finalizes the
+// workspace, so that the external resources of a workspace are not leaked when it is deleted.
+package cleanuphook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// FailurePolicy is what finalization does once the retries of a hook are exhausted
+type FailurePolicy string
+
+const (
+	// FailurePolicyHold keeps the workspace terminating until an administrator skips the hook
+	FailurePolicyHold FailurePolicy = "Hold"
+	// FailurePolicyProceed abandons the hook and finalizes the workspace
+	FailurePolicyProceed FailurePolicy = "Proceed"
+)
+
+const (
+	// DefaultTimeout is the default timeout of a call to a hook
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxAttempts is the default number of calls made to a hook before its retries are exhausted
+	DefaultMaxAttempts = 5
+	// DefaultRetryInterval is the default time between two calls to a hook
+	DefaultRetryInterval = 30 * time.Second
+)
+
+// Config is the cleanup hook configuration of the operator
+type Config struct {
+	// Hooks are called in order when a workspace is deleted
+	Hooks []Hook `json:"hooks"`
+}
+
+// Hook is an HTTP endpoint notified of the deletion of workspaces
+type Hook struct {
+	// Name identifies the hook in the workspace status and in the skip annotation
+	Name string `json:"name"`
+
+	// URL the deletion is POSTed to
+	URL string `json:"url"`
+
+	// AuthSecretRef is the key of a Secret in the controller namespace holding a bearer token for the hook
+	AuthSecretRef *SecretKeyRef `json:"authSecretRef,omitempty"`
+
+	// Timeout of a call; zero uses DefaultTimeout
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+
+	// Retry bounds the calls made to the hook
+	Retry RetryPolicy `json:"retry,omitempty"`
+
+	// FailurePolicy applies once the retries are exhausted; empty uses FailurePolicyHold
+	FailurePolicy FailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// SecretKeyRef selects a key of a Secret in the controller namespace
+type SecretKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// RetryPolicy bounds the calls made to a hook
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made before the retries are exhausted; zero uses DefaultMaxAttempts
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+
+	// Interval between two calls; zero uses DefaultRetryInterval
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
+// LoadConfig reads the hooks of a YAML configuration file, with their defaults applied
+func LoadConfig(path string) ([]Hook, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cleanup hook configuration: %w", err)
+	}
+	config := Config{}
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse cleanup hook configuration %s: %w", path, err)
+	}
+	if err := validate(config.Hooks); err != nil {
+		return nil, fmt.Errorf("invalid cleanup hook configuration %s: %w", path, err)
+	}
+	for i := range config.Hooks {
+		setDefaults(&config.Hooks[i])
+	}
+	return config.Hooks, nil
+}
+
+func validate(hooks []Hook) error {
+	names := map[string]bool{}
+	var errs []error
+	for i, hook := range hooks {
+		if hook.Name == "" {
+			errs = append(errs, fmt.Errorf("hooks[%d]: name is required", i))
+		} else if names[hook.Name] {
+			errs = append(errs, fmt.Errorf("hooks[%d]: duplicate name %q", i, hook.Name))
+		}
+		names[hook.Name] = true
+		if parsed, err := url.Parse(hook.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("hooks[%d]: url %q is not an http or https URL", i, hook.URL))
+		}
+		if ref := hook.AuthSecretRef; ref != nil && (ref.Name == "" || ref.Key == "") {
+			errs = append(errs, fmt.Errorf("hooks[%d]: authSecretRef requires a name and a key", i))
+		}
+		if hook.Timeout.Duration < 0 || hook.Retry.Interval.Duration < 0 || hook.Retry.MaxAttempts < 0 {
+			errs = append(errs, fmt.Errorf("hooks[%d]: timeout, retry.interval and retry.maxAttempts may not be negative", i))
+		}
+		switch hook.FailurePolicy {
+		case "", FailurePolicyHold, FailurePolicyProceed:
+		default:
+			errs = append(errs, fmt.Errorf("hooks[%d]: failurePolicy %q is neither %s nor %s",
+				i, hook.FailurePolicy, FailurePolicyHold, FailurePolicyProceed))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func setDefaults(hook *Hook) {
+	if hook.Timeout.Duration == 0 {
+		hook.Timeout.Duration = DefaultTimeout
+	}
+	if hook.Retry.MaxAttempts == 0 {
+		hook.Retry.MaxAttempts = DefaultMaxAttempts
+	}
+	if hook.Retry.Interval.Duration == 0 {
+		hook.Retry.Interval.Duration = DefaultRetryInterval
+	}
+	if hook.FailurePolicy == "" {
+		hook.FailurePolicy = FailurePolicyHold
+	}
+}
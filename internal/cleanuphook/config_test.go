@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package cleanuphook
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "hooks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfigAppliesDefaults(t *testing.T) {
+	hooks, err := LoadConfig(writeConfig(t, `
+hooks:
+  - name: home-dirs
+    url: https://provisioner.example.com/cleanup
+    authSecretRef:
+      name: provisioner
+      key: token
+  - name: audit
+    url: http://audit.tools.svc/workspaces
+    timeout: 2s
+    retry:
+      maxAttempts: 3
+      interval: 1m
+    failurePolicy: Proceed
+`))
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+
+	assert.Equal(t, "home-dirs", hooks[0].Name)
+	assert.Equal(t, &SecretKeyRef{Name: "provisioner", Key: "token"}, hooks[0].AuthSecretRef)
+	assert.Equal(t, DefaultTimeout, hooks[0].Timeout.Duration)
+	assert.Equal(t, DefaultMaxAttempts, hooks[0].Retry.MaxAttempts)
+	assert.Equal(t, DefaultRetryInterval, hooks[0].Retry.Interval.Duration)
+	assert.Equal(t, FailurePolicyHold, hooks[0].FailurePolicy)
+
+	assert.Nil(t, hooks[1].AuthSecretRef)
+	assert.Equal(t, 2*time.Second, hooks[1].Timeout.Duration)
+	assert.Equal(t, 3, hooks[1].Retry.MaxAttempts)
+	assert.Equal(t, time.Minute, hooks[1].Retry.Interval.Duration)
+	assert.Equal(t, FailurePolicyProceed, hooks[1].FailurePolicy)
+}
+
+func TestLoadConfigRejectsInvalidHooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "unknown field",
+			content: "hooks:\n  - name: a\n    url: http://a\n    retries: 3\n",
+			err:     "unknown field",
+		},
+		{
+			name:    "duplicate name",
+			content: "hooks:\n  - name: a\n    url: http://a\n  - name: a\n    url: http://b\n",
+			err:     `duplicate name "a"`,
+		},
+		{
+			name:    "missing name",
+			content: "hooks:\n  - url: http://a\n",
+			err:     "name is required",
+		},
+		{
+			name:    "not an http URL",
+			content: "hooks:\n  - name: a\n    url: ftp://a\n",
+			err:     "not an http or https URL",
+		},
+		{
+			name:    "incomplete secret reference",
+			content: "hooks:\n  - name: a\n    url: http://a\n    authSecretRef:\n      name: s\n",
+			err:     "authSecretRef requires a name and a key",
+		},
+		{
+			name:    "negative retries",
+			content: "hooks:\n  - name: a\n    url: http://a\n    retry:\n      maxAttempts: -1\n",
+			err:     "may not be negative",
+		},
+		{
+			name:    "unknown failure policy",
+			content: "hooks:\n  - name: a\n    url: http://a\n    failurePolicy: Ignore\n",
+			err:     `failurePolicy "Ignore"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoadConfigFailsOnAMissingFile(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read cleanup hook configuration")
+}
@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// DefaultAcceleratorResourceName is the extended resource accelerators are requested as when the
+// template does not name one
+const DefaultAcceleratorResourceName = corev1.ResourceName("nvidia.com/gpu")
+
+// ResolveAccelerators records the accelerators of the admitted template revision in Status.Accelerators
+// for the deployment builder to apply. The status is updated in memory. A nil template, for workspaces
+// without one, clears the accelerators.
+func (rm *ResourceManager) ResolveAccelerators(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.Accelerators = nil
+		return nil
+	}
+	workspace.Status.Accelerators = template.Spec.Accelerators.DeepCopy()
+	return nil
+}
+
+// requestedAccelerators returns the resource name and count of the accelerators the workspace requests,
+// or a zero count when it requests none or its template defines no accelerators
+func requestedAccelerators(workspace *workspacev1alpha1.Workspace) (corev1.ResourceName, int32) {
+	accelerators := workspace.Status.Accelerators
+	if accelerators == nil || workspace.Spec.GPUCount == nil || *workspace.Spec.GPUCount <= 0 {
+		return "", 0
+	}
+	resourceName := accelerators.ResourceName
+	if resourceName == "" {
+		resourceName = DefaultAcceleratorResourceName
+	}
+	return resourceName, *workspace.Spec.GPUCount
+}
+
+// applyAccelerators requests the accelerators of the workspace in its container and places the pod
+// on the nodes the template says provide them. The template node selector wins over the workspace one
+// for the keys both set. Pods requesting no accelerator are left untouched.
+func applyAccelerators(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	resourceName, count := requestedAccelerators(workspace)
+	if count == 0 {
+		return
+	}
+	accelerators := workspace.Status.Accelerators
+	quantity := *resource.NewQuantity(int64(count), resource.DecimalSI)
+
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name != workspaceContainerName {
+			continue
+		}
+		// Extended resources cannot be overcommitted: the request must equal the limit
+		resources := &podSpec.Containers[i].Resources
+		resources.Limits = maps.Clone(resources.Limits)
+		if resources.Limits == nil {
+			resources.Limits = corev1.ResourceList{}
+		}
+		resources.Limits[resourceName] = quantity
+		resources.Requests = maps.Clone(resources.Requests)
+		if resources.Requests == nil {
+			resources.Requests = corev1.ResourceList{}
+		}
+		resources.Requests[resourceName] = quantity
+	}
+
+	if len(accelerators.NodeSelector) > 0 {
+		nodeSelector := maps.Clone(podSpec.NodeSelector)
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		maps.Copy(nodeSelector, accelerators.NodeSelector)
+		podSpec.NodeSelector = nodeSelector
+	}
+
+	tolerations := slices.Clone(podSpec.Tolerations)
+	for _, toleration := range accelerators.Tolerations {
+		if !hasToleration(tolerations, toleration) {
+			tolerations = append(tolerations, toleration)
+		}
+	}
+	podSpec.Tolerations = tolerations
+}
+
+// hasToleration returns true if the tolerations already hold the toleration
+func hasToleration(tolerations []corev1.Toleration, toleration corev1.Toleration) bool {
+	for i := range tolerations {
+		if equality.Semantic.DeepEqual(tolerations[i], toleration) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceleratorsUnschedulableMessage returns the message reporting that no node has the accelerators the
+// workspace requests, or "" when the scheduler message does not point at them
+func acceleratorsUnschedulableMessage(workspace *workspacev1alpha1.Workspace, schedulerMessage string) string {
+	resourceName, count := requestedAccelerators(workspace)
+	if count == 0 || !strings.Contains(schedulerMessage, "Insufficient "+string(resourceName)) {
+		return ""
+	}
+	return fmt.Sprintf("No node has %d %s available for the workspace pod: %s", count, resourceName, schedulerMessage)
+}
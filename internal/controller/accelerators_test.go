@@ -0,0 +1,134 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newAcceleratorTestSpec returns template accelerators placing pods on a tainted GPU node pool
+func newAcceleratorTestSpec() *workspacev1alpha1.AcceleratorSpec {
+	return &workspacev1alpha1.AcceleratorSpec{
+		MaxCount:     4,
+		NodeSelector: map[string]string{"node-pool": "gpu", "accelerator": "a100"},
+		Tolerations: []corev1.Toleration{{
+			Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule,
+		}},
+	}
+}
+
+func TestPodsRequestingAcceleratorsAreScheduledOnAcceleratorNodes(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Spec.GPUCount = ptr.To[int32](2)
+	workspace.Status.Accelerators = newAcceleratorTestSpec()
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	resources := podSpec.Containers[0].Resources
+	gpus := resources.Limits[DefaultAcceleratorResourceName]
+	assert.Equal(t, int64(2), gpus.Value())
+	gpus = resources.Requests[DefaultAcceleratorResourceName]
+	assert.Equal(t, int64(2), gpus.Value(), "extended resources request what they limit")
+	assert.Equal(t, map[string]string{"node-pool": "gpu", "accelerator": "a100"}, podSpec.NodeSelector,
+		"the template node selector wins over the workspace one")
+	assert.Len(t, podSpec.Tolerations, 2)
+	assert.Equal(t, "nvidia.com/gpu", podSpec.Tolerations[1].Key)
+
+	// The workspace spec is not changed by the rendering
+	assert.Equal(t, map[string]string{"node-pool": "notebooks"}, workspace.Spec.NodeSelector)
+	assert.Len(t, workspace.Spec.Tolerations, 1)
+	_, found := workspace.Spec.Resources.Limits[DefaultAcceleratorResourceName]
+	assert.False(t, found)
+}
+
+func TestPodsRequestingAcceleratorsUseTheTemplateResourceName(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Spec.GPUCount = ptr.To[int32](1)
+	workspace.Status.Accelerators = &workspacev1alpha1.AcceleratorSpec{ResourceName: "amd.com/gpu", MaxCount: 1}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	gpus := podSpec.Containers[0].Resources.Limits["amd.com/gpu"]
+	assert.Equal(t, int64(1), gpus.Value())
+	assert.NotContains(t, podSpec.Containers[0].Resources.Limits, DefaultAcceleratorResourceName)
+	assert.Empty(t, podSpec.NodeSelector)
+	assert.Empty(t, podSpec.Tolerations)
+}
+
+func TestPodsRequestingNoAcceleratorIgnoreTheTemplateAccelerators(t *testing.T) {
+	for name, gpuCount := range map[string]*int32{"unset": nil, "zero": ptr.To[int32](0)} {
+		t.Run(name, func(t *testing.T) {
+			workspace := newGoldenHashWorkspaces()["full"]
+			workspace.Spec.GPUCount = gpuCount
+			workspace.Status.Accelerators = newAcceleratorTestSpec()
+
+			podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+			assert.NotContains(t, podSpec.Containers[0].Resources.Limits, DefaultAcceleratorResourceName)
+			assert.Equal(t, map[string]string{"node-pool": "notebooks"}, podSpec.NodeSelector)
+			assert.Len(t, podSpec.Tolerations, 1)
+		})
+	}
+}
+
+func TestResolveAcceleratorsFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  "gpu",
+			Accelerators: newAcceleratorTestSpec(),
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "gpu"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveAccelerators(ctx, workspace, template))
+	require.NotNil(t, workspace.Status.Accelerators)
+	assert.Equal(t, int32(4), workspace.Status.Accelerators.MaxCount)
+
+	// A deleted template leaves the resolved accelerators in place
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.NotNil(t, workspace.Status.Accelerators)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveAccelerators(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.Accelerators)
+}
+
+func TestDiagnoseComputeNotReadyReportsMissingAccelerators(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.GPUCount = ptr.To[int32](2)
+	workspace.Status.Accelerators = newAcceleratorTestSpec()
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+	}}, nil)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	reason, message := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonInsufficientAccelerators, reason)
+	assert.Contains(t, message, "No node has 2 nvidia.com/gpu available")
+	assert.Contains(t, message, "Insufficient nvidia.com/gpu")
+
+	// Other shortages are reported as capacity
+	pod.Status.Conditions[0].Message = "0/3 nodes are available: 3 Insufficient memory."
+	sm, _ = newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	reason, _ = sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonInsufficientCapacity, reason)
+}
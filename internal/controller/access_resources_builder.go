@@ -120,6 +120,19 @@ func (b *AccessResourcesBuilder) BuildUnstructuredResource(
 	labels[LabelAccessStrategyNamespace] = accessStrategyNamespace
 	obj.SetLabels(labels)
 
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for key, value := range GenerateAnnotations() {
+		annotations[key] = value
+	}
+	obj.SetAnnotations(annotations)
+
+	if ingressAccessResourceKinds[accessResourceTemplate.Kind] {
+		applyChildMetadata(obj, childMetadataOf(workspace).Ingress)
+	}
+
 	return obj, nil
 }
 
@@ -0,0 +1,102 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// WorkspaceRevalidator runs against a stored workspace the admission checks that do not depend on the user
+// who submitted it. It returns the violation rejecting the workspace, empty if it passes, or an error if the
+// checks could not run.
+type WorkspaceRevalidator interface {
+	Revalidate(ctx context.Context, workspace *workspacev1alpha1.Workspace) (string, error)
+}
+
+// AdmissionCoverage describes the workspaces the admission webhooks see while they roll out gradually,
+// so that the controller revalidates the others
+type AdmissionCoverage struct {
+	// NamespaceSelector selects the namespaces the workspace webhooks see. Nil selects every namespace.
+	NamespaceSelector labels.Selector
+	// ObjectSelector selects the workspaces the workspace webhooks see. Nil selects every workspace.
+	ObjectSelector labels.Selector
+	// Revalidator checks the workspaces the webhooks do not see
+	Revalidator WorkspaceRevalidator
+}
+
+// covers returns true if the admission webhooks see the workspace
+func (c *AdmissionCoverage) covers(ctx context.Context, reader client.Reader, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if c.ObjectSelector != nil && !c.ObjectSelector.Matches(labels.Set(workspace.Labels)) {
+		return false, nil
+	}
+	if c.NamespaceSelector == nil || c.NamespaceSelector.Empty() {
+		return true, nil
+	}
+	namespace := &corev1.Namespace{}
+	if err := reader.Get(ctx, client.ObjectKey{Name: workspace.Namespace}, namespace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get namespace %s: %w", workspace.Namespace, err)
+	}
+	return c.NamespaceSelector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// reconcileAdmissionCoverage revalidates the workspaces the admission webhooks did not see, and returns
+// true when the workspace is parked: it failed revalidation, and no resource is acted on until it passes.
+// A parked workspace is revalidated on every reconciliation, including once its namespace is covered.
+func (r *WorkspaceReconciler) reconcileAdmissionCoverage(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (bool, ctrl.Result, error) {
+	coverage := r.options.AdmissionCoverage
+	if coverage == nil || coverage.Revalidator == nil {
+		return false, ctrl.Result{}, nil
+	}
+	logger := logf.FromContext(ctx)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeValidationFailed)
+	parked := condition != nil && condition.Status == metav1.ConditionTrue
+	covered, err := coverage.covers(ctx, r.Client, workspace)
+	if err != nil {
+		return false, ctrl.Result{}, err
+	}
+	if covered && !parked {
+		return false, ctrl.Result{}, nil
+	}
+
+	violation, err := coverage.Revalidator.Revalidate(ctx, workspace)
+	if err != nil {
+		return false, ctrl.Result{}, fmt.Errorf("failed to revalidate workspace: %w", err)
+	}
+
+	snapshotStatus := workspace.DeepCopy().Status
+	if violation != "" {
+		logger.Info("Workspace failed revalidation, parking it", "violation", violation)
+		if err := r.statusManager.UpdateValidationFailedStatus(ctx, workspace, violation, &snapshotStatus); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		// Fixing the workspace triggers a reconciliation through the workspace watch; templates may change too
+		return true, ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+	}
+	if parked {
+		logger.Info("Workspace passed revalidation, resuming reconciliation")
+		if err := r.statusManager.UpdateValidationPassedStatus(ctx, workspace, &snapshotStatus); err != nil {
+			return false, ctrl.Result{}, err
+		}
+	}
+	return false, ctrl.Result{}, nil
+}
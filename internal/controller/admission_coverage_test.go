@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeRevalidator rejects workspaces with the violation, and counts its calls
+type fakeRevalidator struct {
+	violation string
+	calls     int
+}
+
+func (f *fakeRevalidator) Revalidate(context.Context, *workspacev1alpha1.Workspace) (string, error) {
+	f.calls++
+	return f.violation, nil
+}
+
+func newCoverageTestNamespace(name string, webhooksEnabled bool) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if webhooksEnabled {
+		namespace.Labels = map[string]string{"jupyter.org/webhooks": "enabled"}
+	}
+	return namespace
+}
+
+func newCoverageTestReconciler(t *testing.T, revalidator *fakeRevalidator, objects ...client.Object) *WorkspaceReconciler {
+	r := newPauseTestReconciler(t, objects...)
+	r.options.AdmissionCoverage = &AdmissionCoverage{
+		NamespaceSelector: labels.SelectorFromSet(labels.Set{"jupyter.org/webhooks": "enabled"}),
+		Revalidator:       revalidator,
+	}
+	return r
+}
+
+func TestAdmissionCoverageParksInvalidWorkspacesOfUncoveredNamespaces(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	revalidator := &fakeRevalidator{violation: "image is not allowed by template 'base'"}
+	r := newCoverageTestReconciler(t, revalidator, newCoverageTestNamespace("team-a", false), workspace)
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(workspace)}
+
+	result, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, LongRequeueDelay, result.RequeueAfter)
+
+	parked := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, request.NamespacedName, parked))
+	assert.Empty(t, parked.Finalizers, "a parked workspace is not acted on")
+	condition := FindCondition(&parked.Status.Conditions, ConditionTypeValidationFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonRevalidationFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "image is not allowed")
+	assert.Equal(t, BlockedReasonValidationFailed, parked.Status.BlockedReason)
+
+	// Fixed, the workspace passes and resumes
+	revalidator.violation = ""
+	snapshotStatus := parked.DeepCopy().Status
+	stillParked, _, err := r.reconcileAdmissionCoverage(ctx, parked)
+	require.NoError(t, err)
+	assert.False(t, stillParked)
+	assert.NotEqual(t, snapshotStatus, parked.Status)
+	condition = FindCondition(&parked.Status.Conditions, ConditionTypeValidationFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonRevalidationPassed, condition.Reason)
+}
+
+func TestAdmissionCoverageSkipsWorkspacesTheWebhooksSee(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	revalidator := &fakeRevalidator{violation: "rejected"}
+	r := newCoverageTestReconciler(t, revalidator, newCoverageTestNamespace("team-a", true), workspace)
+
+	parked, _, err := r.reconcileAdmissionCoverage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, parked)
+	assert.Zero(t, revalidator.calls, "the webhooks validated the workspace")
+
+	// The object selector narrows the coverage to the workspaces it selects
+	r.options.AdmissionCoverage.ObjectSelector = labels.SelectorFromSet(labels.Set{"rollout": "webhooks"})
+	parked, _, err = r.reconcileAdmissionCoverage(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, parked)
+	assert.Equal(t, 1, revalidator.calls)
+}
+
+func TestAdmissionCoverageKeepsRevalidatingParkedWorkspacesOnceCovered(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.Conditions = []metav1.Condition{
+		NewCondition(ConditionTypeValidationFailed, metav1.ConditionTrue, ReasonRevalidationFailed, "rejected"),
+	}
+	revalidator := &fakeRevalidator{violation: "rejected"}
+	r := newCoverageTestReconciler(t, revalidator, newCoverageTestNamespace("team-a", true), workspace)
+
+	parked, _, err := r.reconcileAdmissionCoverage(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, parked, "enabling the webhooks of the namespace does not validate the stored workspace")
+	assert.Equal(t, 1, revalidator.calls)
+}
@@ -0,0 +1,368 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=create
+
+const (
+	// StarterTemplateName is the name of the template the bootstrap installs when no template exists
+	StarterTemplateName = "starter"
+
+	// StarterTemplateVersion is the version of the starter template spec. Bump it when the spec changes, for
+	// the bootstrap to upgrade the starter templates that administrators did not modify.
+	StarterTemplateVersion = 1
+
+	// AnnotationBootstrapHash records on the starter template the checksum of the spec the bootstrap wrote;
+	// a template whose spec no longer matches it was modified by an administrator and is left alone
+	AnnotationBootstrapHash = "workspace.jupyter.org/bootstrap-hash"
+
+	// AnnotationBootstrapVersion records on the starter template the StarterTemplateVersion the bootstrap wrote
+	AnnotationBootstrapVersion = "workspace.jupyter.org/bootstrap-version"
+
+	// BootstrapStateConfigMapName is the name of the ConfigMap, in the controller namespace, recording what
+	// the bootstrap created
+	BootstrapStateConfigMapName = "jupyter-k8s-bootstrap-state"
+
+	// BootstrapStateKeyNamespaces is the key of the BootstrapStateConfigMapName ConfigMap listing, comma
+	// separated, the namespaces the bootstrap created
+	BootstrapStateKeyNamespaces = "namespaces"
+
+	// BootstrapStateKeyStarterTemplate is the key of the BootstrapStateConfigMapName ConfigMap naming the
+	// starter template, as namespace/name, and what the last bootstrap found it in
+	BootstrapStateKeyStarterTemplate = "starter-template"
+
+	// BootstrapRetryDelay is the delay before the bootstrap runs again after it failed
+	BootstrapRetryDelay = time.Minute
+)
+
+// Starter template states recorded in the bootstrap state
+const (
+	StarterTemplateInstalled = "Installed"
+	StarterTemplateUpgraded  = "Upgraded"
+	StarterTemplateCurrent   = "Current"
+	StarterTemplateModified  = "ModifiedByAdministrator"
+	StarterTemplateSkipped   = "SkippedTemplatesExist"
+)
+
+// BootstrapOptions configures what the bootstrap ensures on startup
+type BootstrapOptions struct {
+	// Namespaces are created when missing
+	Namespaces []string
+	// StarterTemplate installs the starter template in the shared template namespace when no template exists
+	StarterTemplate bool
+	// SharedTemplateNamespace is the namespace of the starter template
+	SharedTemplateNamespace string
+}
+
+// Bootstrap prepares a fresh installation without the Helm chart: it creates the template namespaces the
+// controller is configured with, and installs a starter template when no template exists anywhere. It runs
+// on every start and only creates what is missing; a starter template modified by an administrator is never
+// overwritten. What was created is recorded in a ConfigMap in the controller namespace.
+type Bootstrap struct {
+	client client.Client
+	reader client.Reader
+
+	// namespace is the controller namespace holding the bootstrap state; no state is kept when empty
+	namespace string
+	options   BootstrapOptions
+}
+
+// NewBootstrap creates a new Bootstrap
+func NewBootstrap(k8sClient client.Client, reader client.Reader, namespace string, options BootstrapOptions) *Bootstrap {
+	return &Bootstrap{
+		client:    k8sClient,
+		reader:    reader,
+		namespace: namespace,
+		options:   options,
+	}
+}
+
+// Start runs the bootstrap, retrying until it succeeds.
+// Implements the controller-runtime Runnable interface.
+func (b *Bootstrap) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("bootstrap")
+	ctx = logf.IntoContext(ctx, logger)
+
+	for {
+		err := b.run(ctx)
+		if err == nil {
+			return nil
+		}
+		logger.Error(err, "Bootstrap failed, retrying", "retryDelay", BootstrapRetryDelay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(BootstrapRetryDelay):
+		}
+	}
+}
+
+// NeedLeaderElection returns true because the bootstrap creates resources.
+func (b *Bootstrap) NeedLeaderElection() bool {
+	return true
+}
+
+// run ensures the namespaces and the starter template, then records what it did
+func (b *Bootstrap) run(ctx context.Context) error {
+	createdNamespaces, err := b.ensureNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+	starterTemplate := ""
+	if b.options.StarterTemplate {
+		state, err := b.ensureStarterTemplate(ctx)
+		if err != nil {
+			return err
+		}
+		if state != "" {
+			starterTemplate = fmt.Sprintf("%s/%s %s", b.options.SharedTemplateNamespace, StarterTemplateName, state)
+		}
+	}
+	return b.recordState(ctx, createdNamespaces, starterTemplate)
+}
+
+// ensureNamespaces creates the missing namespaces and returns those it created
+func (b *Bootstrap) ensureNamespaces(ctx context.Context) ([]string, error) {
+	logger := logf.FromContext(ctx)
+	var created []string
+	for _, name := range b.options.Namespaces {
+		err := b.reader.Get(ctx, client.ObjectKey{Name: name}, &corev1.Namespace{})
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get namespace %s: %w", name, err)
+		}
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{"app.kubernetes.io/managed-by": "jupyter-k8s"},
+		}}
+		if err := b.client.Create(ctx, namespace); err != nil && !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create namespace %s: %w", name, err)
+		}
+		logger.Info("Created namespace", "namespace", name)
+		created = append(created, name)
+	}
+	return created, nil
+}
+
+// ensureStarterTemplate installs the starter template when no template exists, and upgrades a starter
+// template the administrators did not modify. It returns the state the starter template was found in,
+// or "" when there is no shared template namespace to install it in.
+func (b *Bootstrap) ensureStarterTemplate(ctx context.Context) (string, error) {
+	logger := logf.FromContext(ctx)
+	if b.options.SharedTemplateNamespace == "" {
+		logger.Info("No shared template namespace, skipping the starter template")
+		return "", nil
+	}
+
+	existing := &workspacev1alpha1.WorkspaceTemplate{}
+	err := b.reader.Get(ctx, client.ObjectKey{Name: StarterTemplateName, Namespace: b.options.SharedTemplateNamespace}, existing)
+	if err == nil {
+		return b.upgradeStarterTemplate(ctx, existing)
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get the starter template: %w", err)
+	}
+
+	templates := &workspacev1alpha1.WorkspaceTemplateList{}
+	if err := b.reader.List(ctx, templates, client.Limit(1)); err != nil {
+		return "", fmt.Errorf("failed to list templates: %w", err)
+	}
+	if len(templates.Items) > 0 {
+		logger.V(1).Info("Templates exist, skipping the starter template")
+		return StarterTemplateSkipped, nil
+	}
+
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        StarterTemplateName,
+			Namespace:   b.options.SharedTemplateNamespace,
+			Annotations: map[string]string{AnnotationBootstrapVersion: strconv.Itoa(StarterTemplateVersion)},
+		},
+		Spec: StarterTemplateSpec(),
+	}
+	if err := b.client.Create(ctx, template); err != nil {
+		return "", fmt.Errorf("failed to create the starter template: %w", err)
+	}
+	if err := b.recordStarterTemplateHash(ctx, template); err != nil {
+		return "", err
+	}
+	logger.Info("Installed the starter template", "template", StarterTemplateName,
+		"templateNamespace", b.options.SharedTemplateNamespace)
+	return StarterTemplateInstalled, nil
+}
+
+// upgradeStarterTemplate brings an existing starter template to the current StarterTemplateVersion, unless an
+// administrator modified it since the bootstrap wrote it
+func (b *Bootstrap) upgradeStarterTemplate(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) (string, error) {
+	logger := logf.FromContext(ctx)
+	checksum, err := workspaceutil.TemplateSpecChecksum(&template.Spec)
+	if err != nil {
+		return "", err
+	}
+	if template.Annotations[AnnotationBootstrapHash] != checksum {
+		logger.Info("Starter template was modified by an administrator, leaving it alone", "template", template.Name)
+		return StarterTemplateModified, nil
+	}
+	version, _ := strconv.Atoi(template.Annotations[AnnotationBootstrapVersion])
+	if version >= StarterTemplateVersion {
+		return StarterTemplateCurrent, nil
+	}
+
+	original := template.DeepCopy()
+	template.Spec = StarterTemplateSpec()
+	template.Annotations[AnnotationBootstrapVersion] = strconv.Itoa(StarterTemplateVersion)
+	if err := b.client.Patch(ctx, template, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return "", fmt.Errorf("failed to upgrade the starter template: %w", err)
+	}
+	if err := b.recordStarterTemplateHash(ctx, template); err != nil {
+		return "", err
+	}
+	logger.Info("Upgraded the starter template", "template", template.Name, "version", StarterTemplateVersion)
+	return StarterTemplateUpgraded, nil
+}
+
+// recordStarterTemplateHash records the checksum of the starter template spec as stored, with the defaults
+// the API server applied, for a later bootstrap to tell whether an administrator modified it
+func (b *Bootstrap) recordStarterTemplateHash(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	checksum, err := workspaceutil.TemplateSpecChecksum(&template.Spec)
+	if err != nil {
+		return err
+	}
+	original := template.DeepCopy()
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[AnnotationBootstrapHash] = checksum
+	if err := b.client.Patch(ctx, template, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to record the starter template checksum: %w", err)
+	}
+	return nil
+}
+
+// recordState records in the bootstrap state the namespaces created, added to those earlier starts created,
+// and the state of the starter template
+func (b *Bootstrap) recordState(ctx context.Context, createdNamespaces []string, starterTemplate string) error {
+	if b.namespace == "" {
+		logf.FromContext(ctx).Info("Controller namespace unknown, the bootstrap state is not recorded",
+			"envVar", ControllerPodNamespaceEnv)
+		return nil
+	}
+	state := &corev1.ConfigMap{}
+	err := b.reader.Get(ctx, client.ObjectKey{Name: BootstrapStateConfigMapName, Namespace: b.namespace}, state)
+	if apierrors.IsNotFound(err) {
+		state = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: BootstrapStateConfigMapName, Namespace: b.namespace},
+			Data:       bootstrapStateData(nil, createdNamespaces, starterTemplate),
+		}
+		if err := b.client.Create(ctx, state); err != nil {
+			return fmt.Errorf("failed to record bootstrap state: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get bootstrap state: %w", err)
+	}
+	data := bootstrapStateData(state.Data, createdNamespaces, starterTemplate)
+	if maps.Equal(state.Data, data) {
+		return nil
+	}
+	state.Data = data
+	if err := b.client.Update(ctx, state); err != nil {
+		return fmt.Errorf("failed to record bootstrap state: %w", err)
+	}
+	return nil
+}
+
+// bootstrapStateData returns the bootstrap state data with the namespaces created added to the recorded ones
+func bootstrapStateData(recorded map[string]string, createdNamespaces []string, starterTemplate string) map[string]string {
+	data := maps.Clone(recorded)
+	if data == nil {
+		data = map[string]string{}
+	}
+	namespaces := parseNamespaceList(data[BootstrapStateKeyNamespaces])
+	for _, namespace := range createdNamespaces {
+		if !slices.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) > 0 {
+		data[BootstrapStateKeyNamespaces] = strings.Join(namespaces, ",")
+	}
+	if starterTemplate != "" {
+		data[BootstrapStateKeyStarterTemplate] = starterTemplate
+	}
+	return data
+}
+
+// parseNamespaceList splits a comma separated list of namespaces
+func parseNamespaceList(list string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(list, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// StarterTemplateSpec returns the spec of the starter template: the default Jupyter image with small
+// resources and storage, for a first workspace to start on a fresh installation
+func StarterTemplateSpec() workspacev1alpha1.WorkspaceTemplateSpec {
+	return workspacev1alpha1.WorkspaceTemplateSpec{
+		DisplayName: "Starter",
+		Description: "Jupyter notebook with the default image, installed by the operator bootstrap. " +
+			"Edit it or add other templates; the bootstrap leaves modified copies alone.",
+		DefaultImage: DefaultJupyterImage,
+		DefaultResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(DefaultCPURequest),
+				corev1.ResourceMemory: resource.MustParse(DefaultMemoryRequest),
+			},
+		},
+		PrimaryStorage: &workspacev1alpha1.StorageConfig{
+			DefaultSize: resource.MustParse("1Gi"),
+		},
+		AppType: "jupyter",
+	}
+}
+
+// SetupBootstrap adds the bootstrap to the manager
+func SetupBootstrap(mgr ctrl.Manager, options BootstrapOptions) error {
+	bootstrap := NewBootstrap(
+		newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout),
+		newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
+		os.Getenv(ControllerPodNamespaceEnv),
+		options,
+	)
+	if err := mgr.Add(bootstrap); err != nil {
+		return fmt.Errorf("failed to add bootstrap: %w", err)
+	}
+	return nil
+}
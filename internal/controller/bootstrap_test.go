@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newBootstrapTestOptions() BootstrapOptions {
+	return BootstrapOptions{
+		Namespaces:              []string{"jupyter-k8s-shared", "team-a"},
+		StarterTemplate:         true,
+		SharedTemplateNamespace: "jupyter-k8s-shared",
+	}
+}
+
+func getBootstrapTestState(t *testing.T, k8sClient client.Client) map[string]string {
+	state := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(context.Background(),
+		client.ObjectKey{Name: BootstrapStateConfigMapName, Namespace: "jupyter-k8s-system"}, state))
+	return state.Data
+}
+
+func getStarterTemplate(t *testing.T, k8sClient client.Client) *workspacev1alpha1.WorkspaceTemplate {
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(context.Background(),
+		client.ObjectKey{Name: StarterTemplateName, Namespace: "jupyter-k8s-shared"}, template))
+	return template
+}
+
+func TestBootstrapCreatesNamespacesAndStarterTemplate(t *testing.T) {
+	ctx := context.Background()
+	existing := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	k8sClient := newMigrationTestClient(t, existing)
+
+	bootstrap := NewBootstrap(k8sClient, k8sClient, "jupyter-k8s-system", newBootstrapTestOptions())
+	require.NoError(t, bootstrap.Start(ctx))
+
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "jupyter-k8s-shared"}, &corev1.Namespace{}))
+	template := getStarterTemplate(t, k8sClient)
+	assert.Equal(t, DefaultJupyterImage, template.Spec.DefaultImage)
+	assert.NotEmpty(t, template.Annotations[AnnotationBootstrapHash])
+	assert.Equal(t, map[string]string{
+		BootstrapStateKeyNamespaces:      "jupyter-k8s-shared",
+		BootstrapStateKeyStarterTemplate: "jupyter-k8s-shared/starter Installed",
+	}, getBootstrapTestState(t, k8sClient), "only the namespaces the bootstrap created are recorded")
+
+	// Running again changes nothing
+	resourceVersion := template.ResourceVersion
+	require.NoError(t, bootstrap.Start(ctx))
+	assert.Equal(t, resourceVersion, getStarterTemplate(t, k8sClient).ResourceVersion)
+	assert.Equal(t, "jupyter-k8s-shared/starter Current",
+		getBootstrapTestState(t, k8sClient)[BootstrapStateKeyStarterTemplate])
+	assert.Equal(t, "jupyter-k8s-shared", getBootstrapTestState(t, k8sClient)[BootstrapStateKeyNamespaces])
+}
+
+func TestBootstrapSkipsStarterTemplateWhenTemplatesExist(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newMigrationTestClient(t, newRevisionTestTemplate())
+
+	bootstrap := NewBootstrap(k8sClient, k8sClient, "jupyter-k8s-system", newBootstrapTestOptions())
+	require.NoError(t, bootstrap.Start(ctx))
+
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: StarterTemplateName, Namespace: "jupyter-k8s-shared"},
+		&workspacev1alpha1.WorkspaceTemplate{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Equal(t, "jupyter-k8s-shared/starter SkippedTemplatesExist",
+		getBootstrapTestState(t, k8sClient)[BootstrapStateKeyStarterTemplate])
+}
+
+func TestBootstrapLeavesModifiedStarterTemplateAlone(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newMigrationTestClient(t)
+	bootstrap := NewBootstrap(k8sClient, k8sClient, "jupyter-k8s-system", newBootstrapTestOptions())
+	require.NoError(t, bootstrap.Start(ctx))
+
+	// An administrator changes the image, and the operator is upgraded to a newer starter template
+	template := getStarterTemplate(t, k8sClient)
+	template.Spec.DefaultImage = "registry.example.com/jupyter:custom"
+	template.Annotations[AnnotationBootstrapVersion] = "0"
+	require.NoError(t, k8sClient.Update(ctx, template))
+
+	require.NoError(t, bootstrap.Start(ctx))
+	assert.Equal(t, "registry.example.com/jupyter:custom", getStarterTemplate(t, k8sClient).Spec.DefaultImage)
+	assert.Equal(t, "jupyter-k8s-shared/starter ModifiedByAdministrator",
+		getBootstrapTestState(t, k8sClient)[BootstrapStateKeyStarterTemplate])
+}
+
+func TestBootstrapUpgradesUnmodifiedStarterTemplate(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newMigrationTestClient(t)
+	bootstrap := NewBootstrap(k8sClient, k8sClient, "jupyter-k8s-system", newBootstrapTestOptions())
+	require.NoError(t, bootstrap.Start(ctx))
+
+	// A starter template written by an older operator
+	template := getStarterTemplate(t, k8sClient)
+	template.Annotations[AnnotationBootstrapVersion] = "0"
+	require.NoError(t, k8sClient.Update(ctx, template))
+
+	require.NoError(t, bootstrap.Start(ctx))
+	assert.Equal(t, "1", getStarterTemplate(t, k8sClient).Annotations[AnnotationBootstrapVersion])
+	assert.Equal(t, "jupyter-k8s-shared/starter Upgraded",
+		getBootstrapTestState(t, k8sClient)[BootstrapStateKeyStarterTemplate])
+}
+
+func TestBootstrapWithoutStarterTemplate(t *testing.T) {
+	ctx := context.Background()
+	k8sClient := newMigrationTestClient(t)
+	options := newBootstrapTestOptions()
+	options.StarterTemplate = false
+
+	require.NoError(t, NewBootstrap(k8sClient, k8sClient, "jupyter-k8s-system", options).Start(ctx))
+	templates := &workspacev1alpha1.WorkspaceTemplateList{}
+	require.NoError(t, k8sClient.List(ctx, templates))
+	assert.Empty(t, templates.Items)
+	assert.Equal(t, map[string]string{BootstrapStateKeyNamespaces: "jupyter-k8s-shared,team-a"},
+		getBootstrapTestState(t, k8sClient))
+}
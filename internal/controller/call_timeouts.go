@@ -0,0 +1,173 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Types of outbound calls, used as the call_type label of the timeout metric
+const (
+	CallTypeKubernetesAPI = "kubernetes_api"
+	CallTypeWorkspaceHTTP = "workspace_http"
+	CallTypePlugin        = "plugin"
+	CallTypeRegistry      = "registry"
+	CallTypeReconcile     = "reconcile"
+)
+
+const (
+	// ReconcileTimeout bounds a whole workspace reconcile, so that a hanging call cannot hold a worker of the queue
+	ReconcileTimeout = 2 * time.Minute
+	// KubernetesAPICallTimeout bounds one call to the Kubernetes API
+	KubernetesAPICallTimeout = 15 * time.Second
+	// IdleProbeTimeout bounds one call to the idle endpoint of a workspace
+	IdleProbeTimeout = 15 * time.Second
+	// IdleProbeConcurrency is the number of idle probes running at once
+	IdleProbeConcurrency = 10
+	// PluginCallTimeout bounds the handling of one pod event by a plugin adapter, which may call
+	// the plugin sidecar and exec into the pod several times
+	PluginCallTimeout = 2 * time.Minute
+	// RegistryCallTimeout bounds the resolution of an image digest through the registry API, token request included
+	RegistryCallTimeout = 30 * time.Second
+)
+
+// recordCallTimeout counts err in the timeout metric when the call ran out of time, and returns err
+func recordCallTimeout(callType string, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		callTimeoutsTotal.WithLabelValues(callType).Inc()
+	}
+	return err
+}
+
+// timeoutClient bounds every call of the wrapped client with a per-call timeout derived from the caller context
+type timeoutClient struct {
+	client.Client
+	timeout time.Duration
+}
+
+// newTimeoutClient wraps k8sClient so that each of its API calls times out after timeout
+func newTimeoutClient(k8sClient client.Client, timeout time.Duration) client.Client {
+	return &timeoutClient{Client: k8sClient, timeout: timeout}
+}
+
+func (c *timeoutClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.Get(callCtx, key, obj, opts...))
+}
+
+func (c *timeoutClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.List(callCtx, list, opts...))
+}
+
+func (c *timeoutClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.Create(callCtx, obj, opts...))
+}
+
+func (c *timeoutClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.Update(callCtx, obj, opts...))
+}
+
+func (c *timeoutClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.Patch(callCtx, obj, patch, opts...))
+}
+
+func (c *timeoutClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.Delete(callCtx, obj, opts...))
+}
+
+func (c *timeoutClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.Client.DeleteAllOf(callCtx, obj, opts...))
+}
+
+func (c *timeoutClient) Status() client.SubResourceWriter {
+	return &timeoutSubResourceClient{SubResourceWriter: c.Client.Status(), timeout: c.timeout}
+}
+
+func (c *timeoutClient) SubResource(subResource string) client.SubResourceClient {
+	return &timeoutSubResourceClient{
+		SubResourceWriter: c.Client.SubResource(subResource),
+		reader:            c.Client.SubResource(subResource),
+		timeout:           c.timeout,
+	}
+}
+
+// timeoutSubResourceClient bounds the calls to a subresource, e.g. status, like timeoutClient
+type timeoutSubResourceClient struct {
+	client.SubResourceWriter
+	reader  client.SubResourceReader
+	timeout time.Duration
+}
+
+func (c *timeoutSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.reader.Get(callCtx, obj, subResource, opts...))
+}
+
+func (c *timeoutSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.SubResourceWriter.Create(callCtx, obj, subResource, opts...))
+}
+
+func (c *timeoutSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.SubResourceWriter.Update(callCtx, obj, opts...))
+}
+
+func (c *timeoutSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, c.SubResourceWriter.Patch(callCtx, obj, patch, opts...))
+}
+
+// timeoutReader bounds every call of the wrapped reader, like timeoutClient
+type timeoutReader struct {
+	reader  client.Reader
+	timeout time.Duration
+}
+
+// newTimeoutReader wraps reader so that each of its API calls times out after timeout
+func newTimeoutReader(reader client.Reader, timeout time.Duration) client.Reader {
+	return &timeoutReader{reader: reader, timeout: timeout}
+}
+
+func (r *timeoutReader) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, r.reader.Get(callCtx, key, obj, opts...))
+}
+
+func (r *timeoutReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	callCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return recordCallTimeout(CallTypeKubernetesAPI, r.reader.List(callCtx, list, opts...))
+}
+
+// Interface guards
+var (
+	_ client.Client            = &timeoutClient{}
+	_ client.SubResourceClient = &timeoutSubResourceClient{}
+	_ client.Reader            = &timeoutReader{}
+)
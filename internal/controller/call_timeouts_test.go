@@ -0,0 +1,196 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// hangUntilDone blocks like an unresponsive API server until the caller gives up
+func hangUntilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTimeoutClientBoundsHangingCalls(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+			return hangUntilDone(ctx)
+		},
+		SubResourceUpdate: func(ctx context.Context, _ client.Client, _ string, _ client.Object, _ ...client.SubResourceUpdateOption) error {
+			return hangUntilDone(ctx)
+		},
+	}, workspace)
+	bounded := newTimeoutClient(k8sClient, 50*time.Millisecond)
+	before := testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeKubernetesAPI))
+
+	start := time.Now()
+	err := bounded.Get(ctx, types.NamespacedName{Name: "ws", Namespace: "team-a"}, &workspacev1alpha1.Workspace{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, bounded.Status().Update(ctx, workspace), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, before+2, testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeKubernetesAPI)))
+}
+
+func TestTimeoutClientKeepsCallerCancellation(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, _ client.WithWatch, _ client.ObjectKey, _ client.Object, _ ...client.GetOption) error {
+			return hangUntilDone(ctx)
+		},
+	}, workspace)
+	bounded := newTimeoutClient(k8sClient, time.Minute)
+	before := testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeKubernetesAPI))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := bounded.Get(ctx, types.NamespacedName{Name: "ws", Namespace: "team-a"}, &workspacev1alpha1.Workspace{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, before, testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeKubernetesAPI)),
+		"a cancellation is not a timeout")
+}
+
+func TestReconcileRunningReturnsWithinBudgetWhenAPIHangs(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Create: func(ctx context.Context, _ client.WithWatch, _ client.Object, _ ...client.CreateOption) error {
+			return hangUntilDone(ctx)
+		},
+	}, workspace)
+	sm.resourceManager.client = newTimeoutClient(k8sClient, 50*time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sm.reconcileDesiredRunningStatus(context.Background(), workspace, workspace.Status.DeepCopy(), nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconcile did not return within its budget")
+	}
+}
+
+// newHangingIdleEndpoint serves an idle endpoint that never answers until the test ends
+func newHangingIdleEndpoint(t *testing.T) *httptest.Server {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+	return server
+}
+
+// httpIdleCheck probes url like the idle detector probes the workspace endpoint
+func httpIdleCheck(url string) idleCheckFunc {
+	return func(ctx context.Context, _ *workspacev1alpha1.Workspace, _ *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return &IdleCheckResult{ShouldRetry: false}, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return &IdleCheckResult{ShouldRetry: true}, err
+		}
+		defer func() { _ = resp.Body.Close() }()
+		return &IdleCheckResult{IsIdle: true}, nil
+	}
+}
+
+func TestIdleShutdownDoesNotWaitForHangingEndpoint(t *testing.T) {
+	ctx := context.Background()
+	server := newHangingIdleEndpoint(t)
+
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.IdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-pod", Namespace: "team-a", Labels: GenerateLabels("ws")},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	sm.idleProbes = newIdleProbeWorker(httpIdleCheck(server.URL), 100*time.Millisecond, 1)
+	before := testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeWorkspaceHTTP))
+
+	start := time.Now()
+	result, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second, "the reconcile does not wait for the probe")
+	assert.Equal(t, IdleProbeTimeout, result.RequeueAfter)
+
+	// The probe gives up after its own timeout, in the background
+	key := client.ObjectKeyFromObject(workspace)
+	var outcome idleProbeOutcome
+	require.Eventually(t, func() bool {
+		var ok bool
+		outcome, ok = sm.idleProbes.take(key, time.Minute)
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, outcome.err, context.DeadlineExceeded)
+	assert.Equal(t, before+1, testutil.ToFloat64(callTimeoutsTotal.WithLabelValues(CallTypeWorkspaceHTTP)))
+}
+
+func TestIdleProbeOutcomeIsConsumedBySecondPass(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.IdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-pod", Namespace: "team-a", Labels: GenerateLabels("ws")},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	probes := 0
+	sm.idleProbes = newIdleProbeWorker(func(context.Context, *workspacev1alpha1.Workspace, *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error) {
+		probes++
+		return &IdleCheckResult{IsIdle: false}, nil
+	}, time.Second, 1)
+
+	result, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, IdleProbeTimeout, result.RequeueAfter)
+
+	key := client.ObjectKeyFromObject(workspace)
+	require.Eventually(t, func() bool {
+		sm.idleProbes.mu.Lock()
+		defer sm.idleProbes.mu.Unlock()
+		_, ok := sm.idleProbes.outcomes[key]
+		return ok
+	}, 5*time.Second, 10*time.Millisecond)
+
+	result, err = sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, IdleCheckInterval, result.RequeueAfter)
+	assert.Equal(t, 1, probes)
+}
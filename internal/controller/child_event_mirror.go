@@ -0,0 +1,283 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// childEventKinds are the kinds of the resources of a workspace whose events are mirrored,
+// with the API version to read them with
+var childEventKinds = map[string]schema.GroupVersion{
+	"Pod":                   corev1.SchemeGroupVersion,
+	"PersistentVolumeClaim": corev1.SchemeGroupVersion,
+	"Service":               corev1.SchemeGroupVersion,
+	"Deployment":            {Group: "apps", Version: "v1"},
+	"ReplicaSet":            {Group: "apps", Version: "v1"},
+}
+
+// EventMirrorPolicy configures which events of the resources of a workspace are mirrored on the workspace
+type EventMirrorPolicy struct {
+	// Enabled turns event mirroring on
+	Enabled bool
+
+	// Types are the event types mirrored, e.g. Warning; empty mirrors every type
+	Types []string
+
+	// Reasons are the event reasons mirrored, e.g. FailedScheduling; empty mirrors every reason
+	Reasons []string
+
+	// MirrorRepeats re-emits later occurrences of a reason on the same resource, at most once per
+	// ChildEventMirrorInterval. Otherwise only the first occurrence is re-emitted; later ones are
+	// only counted in status.
+	MirrorRepeats bool
+}
+
+// DefaultEventMirrorPolicy mirrors the first occurrence of each Warning reason
+func DefaultEventMirrorPolicy() EventMirrorPolicy {
+	return EventMirrorPolicy{Types: []string{corev1.EventTypeWarning}}
+}
+
+// matches returns true if the policy mirrors events of the type and reason
+func (p EventMirrorPolicy) matches(eventType, reason string) bool {
+	return (len(p.Types) == 0 || slices.Contains(p.Types, eventType)) &&
+		(len(p.Reasons) == 0 || slices.Contains(p.Reasons, reason))
+}
+
+// ChildEventMirrorReconciler mirrors the events of the resources of workspaces on the workspaces, for
+// users allowed to read their workspace but not the events of its pod
+type ChildEventMirrorReconciler struct {
+	client.Client
+
+	// apiReader reads the resources the events are about, which the cache does not all hold
+	apiReader client.Reader
+	recorder  record.EventRecorder
+	policy    EventMirrorPolicy
+	scope     *workspaceutil.Scope
+
+	// now returns the current time (allows for unit testing)
+	now func() time.Time
+}
+
+// NewChildEventMirrorReconciler creates a new ChildEventMirrorReconciler
+func NewChildEventMirrorReconciler(
+	k8sClient client.Client,
+	apiReader client.Reader,
+	recorder record.EventRecorder,
+	policy EventMirrorPolicy,
+	scope *workspaceutil.Scope,
+) *ChildEventMirrorReconciler {
+	return &ChildEventMirrorReconciler{
+		Client:    k8sClient,
+		apiReader: apiReader,
+		recorder:  recorder,
+		policy:    policy,
+		scope:     scope,
+		now:       time.Now,
+	}
+}
+
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get
+
+// Reconcile records the event in the status of the workspace of the resource it is about,
+// and re-emits it on the workspace unless it repeats an event already mirrored
+func (r *ChildEventMirrorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	childEvent := &corev1.Event{}
+	if err := r.Get(ctx, req.NamespacedName, childEvent); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !r.policy.matches(childEvent.Type, childEvent.Reason) {
+		return ctrl.Result{}, nil
+	}
+
+	workspace, err := r.workspaceOf(ctx, childEvent)
+	if err != nil || workspace == nil {
+		return ctrl.Result{}, err
+	}
+
+	entries, mirror := recordChildEvent(workspace.Status.ChildEvents, childEvent, r.policy, r.now())
+	if entries == nil {
+		return ctrl.Result{}, nil
+	}
+	patch := client.MergeFrom(workspace.DeepCopy())
+	workspace.Status.ChildEvents = entries
+	if err := r.Status().Patch(ctx, workspace, patch); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to record child event: %w", err)
+	}
+
+	if mirror {
+		logger.V(1).Info("Mirroring child event on workspace",
+			"workspace", workspace.Name, "kind", childEvent.InvolvedObject.Kind,
+			"name", childEvent.InvolvedObject.Name, "reason", childEvent.Reason)
+		r.recorder.Eventf(workspace, childEvent.Type, childEvent.Reason, "%s %s: %s",
+			childEvent.InvolvedObject.Kind, childEvent.InvolvedObject.Name, childEvent.Message)
+	}
+	return ctrl.Result{}, nil
+}
+
+// workspaceOf returns the workspace the resource of the event belongs to, or nil when the resource
+// is gone or is not the resource of a workspace this instance manages
+func (r *ChildEventMirrorReconciler) workspaceOf(ctx context.Context, childEvent *corev1.Event) (*workspacev1alpha1.Workspace, error) {
+	involved := childEvent.InvolvedObject
+	child := &metav1.PartialObjectMetadata{}
+	child.SetGroupVersionKind(childEventKinds[involved.Kind].WithKind(involved.Kind))
+	if err := r.apiReader.Get(ctx, client.ObjectKey{Namespace: involved.Namespace, Name: involved.Name}, child); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get %s %s: %w", involved.Kind, involved.Name, err)
+	}
+	workspaceName := child.Labels[workspaceutil.LabelWorkspaceName]
+	if workspaceName == "" || child.Labels[AppLabel] != AppLabelValue {
+		return nil, nil
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: involved.Namespace, Name: workspaceName}, workspace); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get workspace %s: %w", workspaceName, err)
+	}
+	if !workspace.DeletionTimestamp.IsZero() || !r.scope.Matches(workspace) {
+		return nil, nil
+	}
+	return workspace, nil
+}
+
+// recordChildEvent returns the child events of the workspace status with the occurrence of the event,
+// keeping the latest MaxChildEvents, and whether to re-emit it on the workspace. It returns nil entries
+// when the occurrence was already recorded.
+func recordChildEvent(
+	entries []workspacev1alpha1.ChildEventStatus,
+	childEvent *corev1.Event,
+	policy EventMirrorPolicy,
+	now time.Time,
+) ([]workspacev1alpha1.ChildEventStatus, bool) {
+	occurred := metav1.NewTime(childEventTime(childEvent).Truncate(time.Second))
+	mirroredAt := metav1.NewTime(now.Truncate(time.Second))
+
+	index := slices.IndexFunc(entries, func(entry workspacev1alpha1.ChildEventStatus) bool {
+		return entry.Kind == childEvent.InvolvedObject.Kind &&
+			entry.Name == childEvent.InvolvedObject.Name &&
+			entry.Reason == childEvent.Reason
+	})
+	if index < 0 {
+		entries = append(slices.Clone(entries), workspacev1alpha1.ChildEventStatus{
+			Kind:              childEvent.InvolvedObject.Kind,
+			Name:              childEvent.InvolvedObject.Name,
+			Type:              childEvent.Type,
+			Reason:            childEvent.Reason,
+			Message:           childEvent.Message,
+			Count:             1,
+			FirstTimestamp:    occurred,
+			LastTimestamp:     occurred,
+			MirroredTimestamp: &mirroredAt,
+		})
+		if len(entries) > MaxChildEvents {
+			entries = entries[len(entries)-MaxChildEvents:]
+		}
+		return entries, true
+	}
+
+	entry := entries[index]
+	if !occurred.After(entry.LastTimestamp.Time) {
+		return nil, false
+	}
+	entry.Type = childEvent.Type
+	entry.Message = childEvent.Message
+	entry.Count++
+	entry.LastTimestamp = occurred
+	mirror := policy.MirrorRepeats &&
+		(entry.MirroredTimestamp == nil || now.Sub(entry.MirroredTimestamp.Time) >= ChildEventMirrorInterval)
+	if mirror {
+		entry.MirroredTimestamp = &mirroredAt
+	}
+
+	// The entry moves last, as the most recent
+	updated := slices.Delete(slices.Clone(entries), index, index+1)
+	return append(updated, entry), mirror
+}
+
+// childEventTime returns when the latest occurrence of the event happened
+func childEventTime(childEvent *corev1.Event) time.Time {
+	switch {
+	case childEvent.Series != nil && !childEvent.Series.LastObservedTime.IsZero():
+		return childEvent.Series.LastObservedTime.Time
+	case !childEvent.LastTimestamp.IsZero():
+		return childEvent.LastTimestamp.Time
+	case !childEvent.EventTime.IsZero():
+		return childEvent.EventTime.Time
+	case !childEvent.FirstTimestamp.IsZero():
+		return childEvent.FirstTimestamp.Time
+	default:
+		return childEvent.CreationTimestamp.Time
+	}
+}
+
+// childEventPredicate selects the events of the kinds of workspace resources the policy mirrors
+func childEventPredicate(policy EventMirrorPolicy) predicate.Funcs {
+	selected := func(obj client.Object) bool {
+		childEvent, ok := obj.(*corev1.Event)
+		if !ok {
+			return false
+		}
+		_, childKind := childEventKinds[childEvent.InvolvedObject.Kind]
+		return childKind && policy.matches(childEvent.Type, childEvent.Reason)
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return selected(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return selected(e.ObjectNew) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ChildEventMirrorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Event{}).
+		WithEventFilter(childEventPredicate(r.policy)).
+		Named("childeventmirror").
+		Complete(r)
+}
+
+// SetupChildEventMirrorController sets up the controller mirroring the events of workspace resources,
+// when the policy enables it
+func SetupChildEventMirrorController(mgr ctrl.Manager, policy EventMirrorPolicy, scope *workspaceutil.Scope) error {
+	if !policy.Enabled {
+		return nil
+	}
+	return NewChildEventMirrorReconciler(
+		newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout),
+		newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
+		mgr.GetEventRecorderFor("workspace-controller"),
+		policy,
+		scope,
+	).SetupWithManager(mgr)
+}
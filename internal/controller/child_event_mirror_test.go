@@ -0,0 +1,176 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var childEventTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newChildEventTestEvent(name, reason string, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "ws-pod",
+			Namespace: "team-a",
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        reason,
+		Message:       "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+		LastTimestamp: metav1.NewTime(lastTimestamp),
+	}
+}
+
+func newChildEventTestReconciler(
+	t *testing.T,
+	policy EventMirrorPolicy,
+	objects ...client.Object) (*ChildEventMirrorReconciler, client.Client, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-pod", Namespace: "team-a", Labels: GenerateLabels("ws")},
+	}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(append(objects, workspace, pod)...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := NewChildEventMirrorReconciler(k8sClient, k8sClient, recorder, policy, nil)
+	reconciler.now = func() time.Time { return childEventTestNow }
+	return reconciler, k8sClient, recorder
+}
+
+func reconcileChildEvent(t *testing.T, reconciler *ChildEventMirrorReconciler, k8sClient client.Client, name string) *workspacev1alpha1.Workspace {
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: client.ObjectKey{Namespace: "team-a", Name: name},
+	})
+	require.NoError(t, err)
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "team-a", Name: "ws"}, workspace))
+	return workspace
+}
+
+func TestChildEventMirrorReemitsTheFirstOccurrenceOnTheWorkspace(t *testing.T) {
+	childEvent := newChildEventTestEvent("ws-pod.1", "FailedScheduling", childEventTestNow)
+	reconciler, k8sClient, recorder := newChildEventTestReconciler(t, DefaultEventMirrorPolicy(), childEvent)
+
+	workspace := reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.1")
+
+	require.Len(t, workspace.Status.ChildEvents, 1)
+	entry := workspace.Status.ChildEvents[0]
+	assert.Equal(t, "Pod", entry.Kind)
+	assert.Equal(t, "ws-pod", entry.Name)
+	assert.Equal(t, "FailedScheduling", entry.Reason)
+	assert.Equal(t, int32(1), entry.Count)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Warning FailedScheduling Pod ws-pod: 0/3 nodes are available: 3 Insufficient nvidia.com/gpu.", <-recorder.Events)
+
+	// Reconciling the same occurrence again changes nothing
+	workspace = reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.1")
+	assert.Equal(t, int32(1), workspace.Status.ChildEvents[0].Count)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestChildEventMirrorOnlyCountsRepeatsByDefault(t *testing.T) {
+	childEvent := newChildEventTestEvent("ws-pod.1", "FailedScheduling", childEventTestNow.Add(-time.Minute))
+	reconciler, k8sClient, recorder := newChildEventTestReconciler(t, DefaultEventMirrorPolicy(), childEvent)
+	reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.1")
+	<-recorder.Events
+
+	childEvent.Count = 2
+	childEvent.LastTimestamp = metav1.NewTime(childEventTestNow)
+	require.NoError(t, k8sClient.Update(context.Background(), childEvent))
+	workspace := reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.1")
+
+	assert.Equal(t, int32(2), workspace.Status.ChildEvents[0].Count)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestChildEventMirrorSkipsFilteredEvents(t *testing.T) {
+	normal := newChildEventTestEvent("ws-pod.1", "Scheduled", childEventTestNow)
+	normal.Type = corev1.EventTypeNormal
+	mount := newChildEventTestEvent("ws-pod.2", "FailedMount", childEventTestNow)
+	policy := DefaultEventMirrorPolicy()
+	policy.Reasons = []string{"FailedScheduling"}
+	reconciler, k8sClient, recorder := newChildEventTestReconciler(t, policy, normal, mount)
+
+	reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.1")
+	workspace := reconcileChildEvent(t, reconciler, k8sClient, "ws-pod.2")
+
+	assert.Empty(t, workspace.Status.ChildEvents)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestChildEventMirrorIgnoresResourcesOfNoWorkspace(t *testing.T) {
+	childEvent := newChildEventTestEvent("other.1", "FailedScheduling", childEventTestNow)
+	childEvent.InvolvedObject.Name = "other"
+	other := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-a"}}
+	reconciler, k8sClient, recorder := newChildEventTestReconciler(t, DefaultEventMirrorPolicy(), childEvent, other)
+
+	workspace := reconcileChildEvent(t, reconciler, k8sClient, "other.1")
+
+	assert.Empty(t, workspace.Status.ChildEvents)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestRecordChildEventRateLimitsRepeats(t *testing.T) {
+	policy := DefaultEventMirrorPolicy()
+	policy.MirrorRepeats = true
+	childEvent := newChildEventTestEvent("ws-pod.1", "FailedScheduling", childEventTestNow)
+
+	entries, mirror := recordChildEvent(nil, childEvent, policy, childEventTestNow)
+	assert.True(t, mirror)
+
+	childEvent.LastTimestamp = metav1.NewTime(childEventTestNow.Add(time.Minute))
+	entries, mirror = recordChildEvent(entries, childEvent, policy, childEventTestNow.Add(time.Minute))
+	assert.False(t, mirror, "repeats within the interval are only counted")
+	assert.Equal(t, int32(2), entries[0].Count)
+
+	later := childEventTestNow.Add(ChildEventMirrorInterval)
+	childEvent.LastTimestamp = metav1.NewTime(later)
+	entries, mirror = recordChildEvent(entries, childEvent, policy, later)
+	assert.True(t, mirror)
+	assert.Equal(t, int32(3), entries[0].Count)
+	assert.Equal(t, later, entries[0].MirroredTimestamp.Time)
+}
+
+func TestRecordChildEventKeepsTheLatestEntries(t *testing.T) {
+	var entries []workspacev1alpha1.ChildEventStatus
+	for i := 0; i <= MaxChildEvents; i++ {
+		childEvent := newChildEventTestEvent("ws-pod.1", fmt.Sprintf("Reason%d", i), childEventTestNow)
+		entries, _ = recordChildEvent(entries, childEvent, DefaultEventMirrorPolicy(), childEventTestNow)
+	}
+	require.Len(t, entries, MaxChildEvents)
+	assert.Equal(t, "Reason1", entries[0].Reason)
+
+	// A repeated reason moves last, as the most recent
+	childEvent := newChildEventTestEvent("ws-pod.1", "Reason1", childEventTestNow.Add(time.Minute))
+	entries, _ = recordChildEvent(entries, childEvent, DefaultEventMirrorPolicy(), childEventTestNow)
+	assert.Equal(t, "Reason1", entries[MaxChildEvents-1].Reason)
+	assert.Equal(t, "Reason2", entries[0].Reason)
+}
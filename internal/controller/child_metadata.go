@@ -0,0 +1,160 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Kinds of the access resources the ingress child metadata applies to
+var ingressAccessResourceKinds = map[string]bool{
+	"Ingress":      true,
+	"IngressRoute": true,
+	"HTTPRoute":    true,
+}
+
+// ResolveChildMetadata records the child metadata of the admitted template revision, expanded for the
+// workspace, in Status.ChildMetadata for the builders to apply. The status is updated in memory.
+// A nil template, for workspaces without one, clears the metadata.
+func (rm *ResourceManager) ResolveChildMetadata(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.ChildMetadata = nil
+		return nil
+	}
+
+	metadata, skipped := workspaceutil.ExpandChildMetadata(template.Spec.ChildMetadata, workspaceutil.ChildMetadataVars{
+		WorkspaceName:      workspace.Name,
+		WorkspaceNamespace: workspace.Namespace,
+		WorkspaceOwner:     workspace.Annotations[AnnotationCreatedBy],
+	})
+	if len(skipped) > 0 {
+		logf.FromContext(ctx).Info("Skipping template child labels whose value is not a valid label value",
+			"template", template.Name, "labels", skipped)
+	}
+	workspace.Status.ChildMetadata = metadata
+	return nil
+}
+
+// childMetadataOf returns the resolved child metadata of the workspace
+func childMetadataOf(workspace *workspacev1alpha1.Workspace) workspacev1alpha1.ChildMetadata {
+	if workspace.Status.ChildMetadata == nil {
+		return workspacev1alpha1.ChildMetadata{}
+	}
+	return *workspace.Status.ChildMetadata
+}
+
+// mergeChildMetadata adds child metadata entries to the labels or annotations of a generated child.
+// Keys with the reserved prefix and keys in protected, which the controller sets, are left untouched.
+func mergeChildMetadata(target, entries, protected map[string]string) map[string]string {
+	for key, value := range entries {
+		if strings.HasPrefix(key, ReservedMetadataPrefix) {
+			continue
+		}
+		if _, ok := protected[key]; ok {
+			continue
+		}
+		if target == nil {
+			target = map[string]string{}
+		}
+		target[key] = value
+	}
+	return target
+}
+
+// applyChildMetadata merges child metadata into the metadata of a generated child
+func applyChildMetadata(object metav1.Object, metadata *workspacev1alpha1.ChildObjectMetadata) {
+	if metadata == nil {
+		return
+	}
+	object.SetLabels(mergeChildMetadata(object.GetLabels(), metadata.Labels, GenerateLabels("")))
+	object.SetAnnotations(mergeChildMetadata(object.GetAnnotations(), metadata.Annotations, GenerateAnnotations()))
+}
+
+// childMetadataInSync returns true if the existing child carries the child metadata entries of the desired child
+func childMetadataInSync(existing, desired metav1.Object, metadata *workspacev1alpha1.ChildObjectMetadata) bool {
+	if metadata == nil {
+		return true
+	}
+	return entriesInSync(existing.GetLabels(), desired.GetLabels(), metadata.Labels) &&
+		entriesInSync(existing.GetAnnotations(), desired.GetAnnotations(), metadata.Annotations)
+}
+
+func entriesInSync(existing, desired, entries map[string]string) bool {
+	for key := range entries {
+		want, ok := desired[key]
+		if !ok {
+			continue
+		}
+		if got, ok := existing[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// syncChildMetadata sets the child metadata entries of the desired child on the existing child.
+// Other keys are preserved: entries removed from the template are left on existing children.
+func syncChildMetadata(existing, desired metav1.Object, metadata *workspacev1alpha1.ChildObjectMetadata) {
+	if metadata == nil {
+		return
+	}
+	existing.SetLabels(syncEntries(existing.GetLabels(), desired.GetLabels(), metadata.Labels))
+	existing.SetAnnotations(syncEntries(existing.GetAnnotations(), desired.GetAnnotations(), metadata.Annotations))
+}
+
+func syncEntries(existing, desired, entries map[string]string) map[string]string {
+	for key := range entries {
+		want, ok := desired[key]
+		if !ok {
+			continue
+		}
+		if existing == nil {
+			existing = map[string]string{}
+		}
+		existing[key] = want
+	}
+	return existing
+}
+
+// templateChildMetadataChangedPredicate only passes WorkspaceTemplate updates that change the child metadata
+func templateChildMetadataChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldTemplate, oldOk := e.ObjectOld.(*workspacev1alpha1.WorkspaceTemplate)
+			newTemplate, newOk := e.ObjectNew.(*workspacev1alpha1.WorkspaceTemplate)
+			return oldOk && newOk &&
+				!equality.Semantic.DeepEqual(oldTemplate.Spec.ChildMetadata, newTemplate.Spec.ChildMetadata)
+		},
+	}
+}
+
+// templateChildMetadataEventHandler maps WorkspaceTemplate events to the workspaces using the template
+func (r *WorkspaceReconciler) templateChildMetadataEventHandler(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+	requests, err := workspaceutil.GetWorkspaceReconciliationRequestsForTemplate(ctx, r.Client, obj.GetName(), obj.GetNamespace())
+	if err != nil {
+		logger.Error(err, "Failed to list workspaces of template", "template", obj.GetName(), "namespace", obj.GetNamespace())
+		return nil
+	}
+	logger.Info("Template changed", "template", obj.GetName(), "namespace", obj.GetNamespace(),
+		"workspaceCount", len(requests))
+	return requests
+}
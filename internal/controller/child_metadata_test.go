@@ -0,0 +1,175 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// newChildMetadataTestWorkspace returns an available workspace with resolved child metadata for the service and PVC
+func newChildMetadataTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newRolloutTestWorkspace()
+	workspace.Status.ChildMetadata = &workspacev1alpha1.ChildMetadata{
+		Service: &workspacev1alpha1.ChildObjectMetadata{
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "ws.team-a.example.com"},
+		},
+		PVC: &workspacev1alpha1.ChildObjectMetadata{
+			Labels: map[string]string{"backup.example.com/policy": "daily"},
+		},
+	}
+	return workspace
+}
+
+func TestResolveChildMetadataExpandsTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "DNS",
+			ChildMetadata: &workspacev1alpha1.ChildMetadata{
+				Ingress: &workspacev1alpha1.ChildObjectMetadata{
+					Annotations: map[string]string{"cert-manager.io/common-name": "${workspace.name}.${workspace.namespace}"},
+				},
+			},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationCreatedBy: "alice"}
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "dns"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveChildMetadata(ctx, workspace, template))
+	require.NotNil(t, workspace.Status.ChildMetadata)
+	assert.Equal(t, "ws.team-a", workspace.Status.ChildMetadata.Ingress.Annotations["cert-manager.io/common-name"])
+
+	// A deleted template leaves the resolved metadata in place
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.NotNil(t, workspace.Status.ChildMetadata)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveChildMetadata(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.ChildMetadata)
+}
+
+func TestChildMetadataDoesNotOverrideOperatorKeys(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Labels = map[string]string{"team": "ml"}
+	workspace.Status.ChildMetadata = &workspacev1alpha1.ChildMetadata{
+		Pod: &workspacev1alpha1.ChildObjectMetadata{
+			Labels: map[string]string{
+				AppLabel:                         "other",
+				workspaceutil.LabelWorkspaceName: "other",
+				"team":                           "platform",
+				"sidecar.istio.io/inject":        "false",
+			},
+			Annotations: map[string]string{AnnotationManagedByVersion: "other", "prometheus.io/scrape": "true"},
+		},
+	}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+	podMeta := deployment.Spec.Template.ObjectMeta
+	assert.Equal(t, AppLabelValue, podMeta.Labels[AppLabel])
+	assert.Equal(t, "ws", podMeta.Labels[workspaceutil.LabelWorkspaceName])
+	assert.Equal(t, "platform", podMeta.Labels["team"])
+	assert.Equal(t, "false", podMeta.Labels["sidecar.istio.io/inject"])
+	assert.Equal(t, "true", podMeta.Annotations["prometheus.io/scrape"])
+	assert.NotEqual(t, "other", podMeta.Annotations[AnnotationManagedByVersion])
+
+	// Selector labels are not affected by child metadata
+	assert.Equal(t, "ws", deployment.Spec.Selector.MatchLabels[workspaceutil.LabelWorkspaceName])
+}
+
+func TestChildMetadataDriftIsReconciled(t *testing.T) {
+	ctx := context.Background()
+	workspace := newChildMetadataTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "ws.team-a.example.com", service.Annotations["external-dns.alpha.kubernetes.io/hostname"])
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "daily", pvc.Labels["backup.example.com/policy"])
+
+	// Another process edits the managed keys and adds its own
+	service.Annotations["external-dns.alpha.kubernetes.io/hostname"] = "edited"
+	service.Annotations["unrelated"] = "kept"
+	require.NoError(t, k8sClient.Update(ctx, service))
+	delete(pvc.Labels, "backup.example.com/policy")
+	require.NoError(t, k8sClient.Update(ctx, pvc))
+
+	_, err = sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+	_, err = sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+
+	storedService := &corev1.Service{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(service), storedService))
+	assert.Equal(t, "ws.team-a.example.com", storedService.Annotations["external-dns.alpha.kubernetes.io/hostname"])
+	assert.Equal(t, "kept", storedService.Annotations["unrelated"])
+	storedPVC := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), storedPVC))
+	assert.Equal(t, "daily", storedPVC.Labels["backup.example.com/policy"])
+}
+
+func TestTemplateChildMetadataChangedPredicate(t *testing.T) {
+	oldTemplate := &workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "t"}}
+	newTemplate := oldTemplate.DeepCopy()
+	newTemplate.Spec.DisplayName = "renamed"
+	pred := templateChildMetadataChangedPredicate()
+	assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: oldTemplate, ObjectNew: newTemplate}))
+
+	newTemplate.Spec.ChildMetadata = &workspacev1alpha1.ChildMetadata{
+		Service: &workspacev1alpha1.ChildObjectMetadata{Labels: map[string]string{"team": "ml"}},
+	}
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: oldTemplate, ObjectNew: newTemplate}))
+}
+
+func TestPodMetadataOfTheWorkspaceRollsThePod(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.ChildMetadata = &workspacev1alpha1.ChildMetadata{
+		Pod: &workspacev1alpha1.ChildObjectMetadata{Labels: map[string]string{"team": "platform"}},
+	}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	builder := sm.resourceManager.deploymentBuilder
+	before, err := builder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+
+	workspace.Spec.PodMetadata = &workspacev1alpha1.ChildObjectMetadata{
+		Labels: map[string]string{
+			"team":                          "research",
+			"cost-center":                   "cc-42",
+			LabelWorkspaceTemplateNamespace: "spoofed",
+		},
+		Annotations: map[string]string{"example.com/owner": "alice"},
+	}
+	after, err := builder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+
+	pod := after.Spec.Template
+	assert.Equal(t, "research", pod.Labels["team"], "the workspace wins over the template")
+	assert.Equal(t, "cc-42", pod.Labels["cost-center"])
+	assert.NotContains(t, pod.Labels, LabelWorkspaceTemplateNamespace, "reserved keys are left to the controller")
+	assert.Equal(t, "alice", pod.Annotations["example.com/owner"])
+	assert.NotEqual(t, before.Annotations[AnnotationPodTemplateHash], after.Annotations[AnnotationPodTemplateHash])
+}
@@ -0,0 +1,257 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	serviceNameSuffix  = "service"
+	pvcNameSuffix      = "pvc"
+	usageNameSuffix    = "usage"
+	postStartSuffix    = "post-start"
+	cloneSuffix        = "clone"
+	hibernationSuffix  = "hibernation"
+	ownerAccessSuffix  = "owner"
+	viewerAccessSuffix = "viewer"
+	editorAccessSuffix = "editor"
+
+	// workspaceContainerName is the name of the container running the workspace application
+	workspaceContainerName = "workspace"
+
+	// childNameHashLength is the length of the hash replacing the end of workspace names too long
+	// for the names of their resources to be valid DNS labels
+	childNameHashLength = 8
+
+	// MaxChildNamePrefixLength keeps room in generated names for the suffixes, the hash of long
+	// workspace names and the first characters of the workspace name
+	MaxChildNamePrefixLength = validation.DNS1035LabelMaxLength - len(serviceNameSuffix) - childNameHashLength - 12
+)
+
+// ValidateChildNamePrefix checks that names generated with the prefix are valid DNS labels
+func ValidateChildNamePrefix(prefix string) error {
+	if errs := validation.IsDNS1035Label(prefix); len(errs) > 0 {
+		return fmt.Errorf("invalid child name prefix %q: %s", prefix, strings.Join(errs, "; "))
+	}
+	if len(prefix) > MaxChildNamePrefixLength {
+		return fmt.Errorf("invalid child name prefix %q: must be no more than %d characters", prefix, MaxChildNamePrefixLength)
+	}
+	return nil
+}
+
+// generateChildName returns "<prefix>-<workspace name>[-<suffix>]". Names longer than a DNS label
+// shorten the workspace name and append its hash, so that they stay valid and distinct.
+func generateChildName(prefix string, workspaceName string, suffix string) string {
+	if suffix != "" {
+		suffix = "-" + suffix
+	}
+	name := fmt.Sprintf("%s-%s%s", prefix, workspaceName, suffix)
+	if len(name) <= validation.DNS1035LabelMaxLength {
+		return name
+	}
+
+	sum := sha256.Sum256([]byte(workspaceName))
+	hash := hex.EncodeToString(sum[:])[:childNameHashLength]
+	keep := validation.DNS1035LabelMaxLength - len(prefix) - len(suffix) - len(hash) - 2
+	shortened := strings.TrimRight(workspaceName[:max(keep, 0)], "-.")
+	return fmt.Sprintf("%s-%s-%s%s", prefix, shortened, hash, suffix)
+}
+
+// legacyChildName returns "<prefix>-<workspace name>[-<suffix>]" in full, the name resources of long
+// workspace names were given before names were shortened. It may be longer than a DNS label.
+func legacyChildName(prefix string, workspaceName string, suffix string) string {
+	if suffix != "" {
+		suffix = "-" + suffix
+	}
+	return fmt.Sprintf("%s-%s%s", prefix, workspaceName, suffix)
+}
+
+// childNameFor returns the name of the resource of the workspace with the suffix, in full for the
+// workspaces whose resources were created before long names were shortened
+func childNameFor(workspace *workspacev1alpha1.Workspace, suffix string) string {
+	if workspace.Status.LegacyChildNames {
+		return legacyChildName(childNamePrefixFor(workspace), workspace.Name, suffix)
+	}
+	return generateChildName(childNamePrefixFor(workspace), workspace.Name, suffix)
+}
+
+// childNamePrefixFor returns the prefix the resources of the workspace are named with
+func childNamePrefixFor(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Status.ChildNamePrefix != "" {
+		return workspace.Status.ChildNamePrefix
+	}
+	return ResourcePrefix
+}
+
+// deploymentNameFor returns the name of the deployment of the workspace
+func deploymentNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, "")
+}
+
+// serviceNameFor returns the name of the service of the workspace. Service names must be DNS labels, so
+// the name is shortened for legacy workspaces too: their full-length service could not be created.
+func serviceNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return generateChildName(childNamePrefixFor(workspace), workspace.Name, serviceNameSuffix)
+}
+
+// pvcNameFor returns the name of the PVC of the workspace
+func pvcNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, pvcNameSuffix)
+}
+
+// HomePVCNameFor returns the name of the PVC holding the home directory of the workspace, for the webhooks
+func HomePVCNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return pvcNameFor(workspace)
+}
+
+// cloneSnapshotNameFor returns the name of the VolumeSnapshot the home volume of the workspace is cloned through
+func cloneSnapshotNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, cloneSuffix)
+}
+
+// hibernationSnapshotNameFor returns the name of the VolumeSnapshot the home volume of the hibernated
+// workspace is kept in
+func hibernationSnapshotNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, hibernationSuffix)
+}
+
+// ownerAccessNameFor returns the name of the Role and RoleBinding granting the owner of the workspace access to it
+func ownerAccessNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, ownerAccessSuffix)
+}
+
+// collaboratorAccessNameFor returns the name of the Role and RoleBinding granting the collaborators of the
+// workspace in role access to it
+func collaboratorAccessNameFor(workspace *workspacev1alpha1.Workspace, role workspacev1alpha1.CollaboratorRole) string {
+	suffix := viewerAccessSuffix
+	if role == workspacev1alpha1.CollaboratorRoleEditor {
+		suffix = editorAccessSuffix
+	}
+	return childNameFor(workspace, suffix)
+}
+
+// usageConfigMapNameFor returns the name of the ConfigMap holding the usage summary of the workspace
+func usageConfigMapNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, usageNameSuffix)
+}
+
+// postStartConfigMapNameFor returns the name of the ConfigMap holding the post-start script of the workspace
+func postStartConfigMapNameFor(workspace *workspacev1alpha1.Workspace) string {
+	return childNameFor(workspace, postStartSuffix)
+}
+
+// configuredChildNamePrefix returns the operator child name prefix, overridden by the annotation of the namespace.
+// The namespace must be read: falling back to the operator prefix would name the resources for good.
+func (rm *ResourceManager) configuredChildNamePrefix(ctx context.Context, namespace string) (string, error) {
+	prefix := rm.childNamePrefix
+	if prefix == "" {
+		prefix = ResourcePrefix
+	}
+
+	ns := &corev1.Namespace{}
+	if err := rm.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return prefix, nil
+		}
+		return "", fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	override, ok := ns.Annotations[AnnotationChildNamePrefix]
+	if !ok {
+		return prefix, nil
+	}
+	if err := ValidateChildNamePrefix(override); err != nil {
+		logf.FromContext(ctx).Error(err, "Ignoring namespace child name prefix", "namespace", namespace)
+		return prefix, nil
+	}
+	return override, nil
+}
+
+// ResolveChildNamePrefix records in Status.ChildNamePrefix the prefix the resources of the workspace are
+// named with. The status is updated in memory.
+// The prefix is chosen once, the default one included, so that later reconciles do not look it up again:
+// workspaces whose resources already exist under the default names keep them until they are recreated, so
+// that changing the prefix never orphans a deployment, service or volume. Likewise, workspaces with a long
+// name whose resources exist under their full names record it in Status.LegacyChildNames and keep them.
+func (rm *ResourceManager) ResolveChildNamePrefix(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Status.ChildNamePrefix != "" || workspace.Status.LegacyChildNames {
+		return nil
+	}
+	legacyNames, err := rm.hasLegacyNamedChildren(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if legacyNames {
+		logf.FromContext(ctx).V(1).Info("Keeping the full names of existing workspace resources")
+		workspace.Status.LegacyChildNames = true
+		return nil
+	}
+	prefix, err := rm.configuredChildNamePrefix(ctx, workspace.Namespace)
+	if err != nil {
+		return err
+	}
+	if prefix != ResourcePrefix {
+		legacy, err := rm.hasDefaultNamedChildren(ctx, workspace)
+		if err != nil {
+			return err
+		}
+		if legacy {
+			logf.FromContext(ctx).V(1).Info("Keeping the default names of existing workspace resources",
+				"childNamePrefix", prefix)
+			prefix = ResourcePrefix
+		}
+	}
+	workspace.Status.ChildNamePrefix = prefix
+	return nil
+}
+
+// hasLegacyNamedChildren returns true if a deployment or PVC of a workspace whose name is too long for the
+// names of its resources to be DNS labels exists under its full default name
+func (rm *ResourceManager) hasLegacyNamedChildren(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	deploymentName := legacyChildName(ResourcePrefix, workspace.Name, "")
+	if deploymentName == GenerateDeploymentName(workspace.Name) {
+		return false, nil
+	}
+	return rm.childExists(ctx, workspace.Namespace, map[string]client.Object{
+		deploymentName: &appsv1.Deployment{},
+		legacyChildName(ResourcePrefix, workspace.Name, pvcNameSuffix): &corev1.PersistentVolumeClaim{},
+	})
+}
+
+// hasDefaultNamedChildren returns true if a deployment, service or PVC exists under the default name
+func (rm *ResourceManager) hasDefaultNamedChildren(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	return rm.childExists(ctx, workspace.Namespace, map[string]client.Object{
+		GenerateDeploymentName(workspace.Name): &appsv1.Deployment{},
+		GenerateServiceName(workspace.Name):    &corev1.Service{},
+		GeneratePVCName(workspace.Name):        &corev1.PersistentVolumeClaim{},
+	})
+}
+
+// childExists returns true if one of the children exists in the namespace under its name
+func (rm *ResourceManager) childExists(ctx context.Context, namespace string, children map[string]client.Object) (bool, error) {
+	for name, obj := range children {
+		err := rm.client.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, obj)
+		if err == nil {
+			return true, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to look up workspace resources: %w", err)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newChildNameTestNamespace(prefix string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{AnnotationChildNamePrefix: prefix},
+	}}
+}
+
+func TestValidateChildNamePrefix(t *testing.T) {
+	assert.NoError(t, ValidateChildNamePrefix("team-a"))
+	assert.Error(t, ValidateChildNamePrefix(""))
+	assert.Error(t, ValidateChildNamePrefix("Team-A"))
+	assert.Error(t, ValidateChildNamePrefix("1team"))
+	assert.Error(t, ValidateChildNamePrefix("team-"))
+	assert.Error(t, ValidateChildNamePrefix(strings.Repeat("a", MaxChildNamePrefixLength+1)))
+}
+
+func TestGenerateChildNameFitsDNSLabel(t *testing.T) {
+	assert.Equal(t, "workspace-ws-service", generateChildName(ResourcePrefix, "ws", serviceNameSuffix))
+	assert.Equal(t, "team-a-ws", generateChildName("team-a", "ws", ""))
+
+	prefix := strings.Repeat("p", MaxChildNamePrefixLength)
+	long := strings.Repeat("n", 60)
+	name := generateChildName(prefix, long+"-a", serviceNameSuffix)
+	assert.LessOrEqual(t, len(name), validation.DNS1035LabelMaxLength)
+	assert.Empty(t, validation.IsDNS1035Label(name))
+	assert.True(t, strings.HasSuffix(name, "-service"))
+	assert.NotEqual(t, name, generateChildName(prefix, long+"-b", serviceNameSuffix),
+		"shortened names of distinct workspaces stay distinct")
+}
+
+func TestNewWorkspaceUsesConfiguredChildNamePrefix(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.resourceManager.childNamePrefix = "team-a"
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, "team-a", workspace.Status.ChildNamePrefix)
+
+	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a-ws", deployment.Name)
+	assert.Equal(t, "team-a-ws-service", service.Name)
+	assert.Equal(t, "team-a-ws-pvc", deployment.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "team-a-ws-pvc", Namespace: "team-a"}, pvc))
+}
+
+func TestNamespaceChildNamePrefixOverridesOperatorPrefix(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newChildNameTestNamespace("ns-team"))
+	sm.resourceManager.childNamePrefix = "team-a"
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, "ns-team", workspace.Status.ChildNamePrefix)
+}
+
+func TestInvalidNamespaceChildNamePrefixIsIgnored(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newChildNameTestNamespace("Not_Valid"))
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, ResourcePrefix, workspace.Status.ChildNamePrefix)
+	assert.Equal(t, "workspace-ws", deploymentNameFor(workspace))
+}
+
+func TestChildNamePrefixIsRetriedWhenTheNamespaceCannotBeRead(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*corev1.Namespace); ok {
+				return errors.New("connection refused")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, workspace, newChildNameTestNamespace("ns-team"))
+
+	// The default prefix is not recorded in place of the one of the namespace
+	require.Error(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Empty(t, workspace.Status.ChildNamePrefix)
+}
+
+func TestResolvedDefaultChildNamePrefixIsNotLookedUpAgain(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace(strings.Repeat("n", 60))
+	gets := 0
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			gets++
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, workspace)
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, ResourcePrefix, workspace.Status.ChildNamePrefix)
+	require.NotZero(t, gets)
+
+	gets = 0
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Zero(t, gets, "neither the namespace nor the legacy names are looked up again")
+}
+
+func TestExistingWorkspaceKeepsDefaultNamesUntilRecreated(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	// The workspace was created before the prefix was configured
+	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	legacy, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	require.Equal(t, "workspace-ws", legacy.Name)
+
+	sm.resourceManager.childNamePrefix = "team-a"
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, ResourcePrefix, workspace.Status.ChildNamePrefix)
+
+	// Lookups keep finding the existing resources rather than creating new ones
+	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, legacy.Name, deployment.Name)
+	pvc, err := sm.resourceManager.getPVC(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "workspace-ws-pvc", pvc.Name)
+
+	// Once its resources are gone, the recreated workspace takes the prefix
+	require.NoError(t, k8sClient.Delete(ctx, deployment))
+	require.NoError(t, k8sClient.Delete(ctx, pvc))
+	recreated := newAdoptionTestWorkspace("ws")
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, recreated))
+	assert.Equal(t, "team-a", recreated.Status.ChildNamePrefix)
+}
+
+func TestRecordedChildNamePrefixSurvivesConfigurationChange(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.ChildNamePrefix = "team-a"
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newChildNameTestNamespace("team-b"))
+
+	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.Equal(t, "team-a", workspace.Status.ChildNamePrefix)
+	found, err := sm.resourceManager.getDeployment(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, deployment.Name, found.Name)
+	assert.Equal(t, "team-a-ws", found.Name)
+}
+
+func TestDeletionFindsPrefixedResourcesWithoutRecordedPrefix(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.resourceManager.childNamePrefix = "team-a"
+
+	// The resources were created before the recorded prefix was persisted
+	prefixed := workspace.DeepCopy()
+	prefixed.Status.ChildNamePrefix = "team-a"
+	_, err := sm.resourceManager.EnsureDeploymentExists(ctx, prefixed, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	deleted, err := sm.resourceManager.EnsureDeploymentDeleted(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, deleted)
+	assert.Equal(t, "team-a-ws", deleted.Name)
+	assert.Equal(t, "team-a", workspace.Status.ChildNamePrefix)
+}
+
+func TestLongNamedWorkspaceKeepsFullLengthResourceNames(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace(strings.Repeat("n", 60))
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	// The workspace was created before long names were shortened
+	legacyName := "workspace-" + workspace.Name
+	require.Greater(t, len(legacyName), validation.DNS1035LabelMaxLength)
+	legacyWorkspace := workspace.DeepCopy()
+	legacyWorkspace.Status.LegacyChildNames = true
+	_, err := sm.resourceManager.EnsurePVCExists(ctx, legacyWorkspace)
+	require.NoError(t, err)
+
+	sm.resourceManager.childNamePrefix = "team-a"
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.True(t, workspace.Status.LegacyChildNames)
+	assert.Empty(t, workspace.Status.ChildNamePrefix)
+
+	// Lookups keep finding the existing resources rather than creating new ones
+	pvc, err := sm.resourceManager.getPVC(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, pvc)
+	assert.Equal(t, legacyName+"-pvc", pvc.Name)
+	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, legacyName, deployment.Name)
+	assert.Empty(t, validation.IsDNS1035Label(serviceNameFor(workspace)), "the service name stays a DNS label")
+}
+
+func TestNewLongNamedWorkspaceGetsShortenedResourceNames(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace(strings.Repeat("n", 60))
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	require.NoError(t, sm.resourceManager.ResolveChildNamePrefix(ctx, workspace))
+	assert.False(t, workspace.Status.LegacyChildNames)
+	assert.LessOrEqual(t, len(deploymentNameFor(workspace)), validation.DNS1035LabelMaxLength)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/cleanuphook"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// EventReasonCleanupHookAcknowledged is the reason of the event recorded when a cleanup hook
+	// acknowledges the deletion of a workspace
+	EventReasonCleanupHookAcknowledged = "CleanupHookAcknowledged"
+	// EventReasonCleanupHookSkipped is the reason of the warning event recorded when an administrator
+	// skips a cleanup hook with AnnotationSkipCleanupHooks
+	EventReasonCleanupHookSkipped = "CleanupHookSkipped"
+	// EventReasonCleanupHookFailed is the reason of the warning event recorded once the retries of a
+	// cleanup hook are exhausted
+	EventReasonCleanupHookFailed = "CleanupHookFailed"
+)
+
+// cleanupHooksStep returns the finalization step calling the cleanup hooks of the operator configuration.
+// It runs once the storage of the workspace is deleted, so that the hooks clean up after a workspace
+// that can no longer use what they delete.
+func (sm *StateMachine) cleanupHooksStep() finalizationStep {
+	return finalizationStep{
+		reason:   ReasonRunningCleanupHooks,
+		resource: "cleanup hooks",
+		run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+			return sm.runCleanupHooks(ctx, workspace, time.Now()), nil
+		},
+		progress: func(workspace *workspacev1alpha1.Workspace) (string, time.Duration) {
+			return sm.cleanupHooksProgress(workspace, time.Now())
+		},
+	}
+}
+
+// skippedCleanupHooks returns the hooks listed in AnnotationSkipCleanupHooks
+func skippedCleanupHooks(workspace *workspacev1alpha1.Workspace) []string {
+	var names []string
+	for _, name := range strings.Split(workspace.Annotations[AnnotationSkipCleanupHooks], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// cleanupHookStatus returns the status of a hook, added as pending when the hook was not called yet
+func cleanupHookStatus(workspace *workspacev1alpha1.Workspace, name string) *workspacev1alpha1.CleanupHookStatus {
+	for i := range workspace.Status.CleanupHooks {
+		if workspace.Status.CleanupHooks[i].Name == name {
+			return &workspace.Status.CleanupHooks[i]
+		}
+	}
+	workspace.Status.CleanupHooks = append(workspace.Status.CleanupHooks,
+		workspacev1alpha1.CleanupHookStatus{Name: name, Phase: workspacev1alpha1.CleanupHookPending})
+	return &workspace.Status.CleanupHooks[len(workspace.Status.CleanupHooks)-1]
+}
+
+// cleanupHookDone returns whether finalization no longer waits for a hook in the phase
+func cleanupHookDone(phase workspacev1alpha1.CleanupHookPhase) bool {
+	switch phase {
+	case workspacev1alpha1.CleanupHookAcknowledged, workspacev1alpha1.CleanupHookSkipped,
+		workspacev1alpha1.CleanupHookAbandoned:
+		return true
+	}
+	return false
+}
+
+// runCleanupHooks calls the cleanup hooks in order, one at a time, and returns true once none is waited
+// for. A hook is called again after its retry interval until it acknowledges the deletion or its
+// attempts are exhausted; calls that fail and calls the hook answers in progress are both attempts.
+// Exhausted hooks are abandoned under FailurePolicyProceed, and hold the workspace terminating under
+// FailurePolicyHold until an administrator lists them in AnnotationSkipCleanupHooks. The progress of
+// the hooks is recorded in Status.CleanupHooks, in memory, so that a restarted controller resumes it.
+func (sm *StateMachine) runCleanupHooks(ctx context.Context, workspace *workspacev1alpha1.Workspace, now time.Time) bool {
+	logger := logf.FromContext(ctx)
+	skipped := skippedCleanupHooks(workspace)
+	for _, hook := range sm.cleanupHooks {
+		status := cleanupHookStatus(workspace, hook.Name)
+		if cleanupHookDone(status.Phase) {
+			continue
+		}
+		if slices.Contains(skipped, hook.Name) {
+			logger.Info("Skipping cleanup hook", "hook", hook.Name, "phase", status.Phase)
+			status.Phase = workspacev1alpha1.CleanupHookSkipped
+			status.Message = "Skipped with the annotation " + AnnotationSkipCleanupHooks
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonCleanupHookSkipped,
+				fmt.Sprintf("Cleanup hook %s skipped after %d attempts, its external resources may remain",
+					hook.Name, status.Attempts))
+			continue
+		}
+		if status.Phase == workspacev1alpha1.CleanupHookFailed {
+			return false
+		}
+		if status.LastAttemptTime != nil && now.Before(status.LastAttemptTime.Add(hook.Retry.Interval.Duration)) {
+			return false
+		}
+
+		status.Attempts++
+		status.LastAttemptTime = &metav1.Time{Time: now.Truncate(time.Second)}
+		acknowledged, err := sm.cleanupHookClient.Call(ctx, hook, cleanuphook.Request{
+			Hook: hook.Name,
+			Workspace: cleanuphook.Workspace{
+				Name:      workspace.Name,
+				Namespace: workspace.Namespace,
+				UID:       string(workspace.UID),
+				Owner:     workspace.Annotations[AnnotationCreatedBy],
+			},
+		})
+		if acknowledged {
+			logger.Info("Cleanup hook acknowledged the deletion", "hook", hook.Name, "attempts", status.Attempts)
+			status.Phase = workspacev1alpha1.CleanupHookAcknowledged
+			status.Message = ""
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, EventReasonCleanupHookAcknowledged,
+				fmt.Sprintf("Cleanup hook %s acknowledged the deletion", hook.Name))
+			continue
+		}
+		status.Message = "Deletion in progress"
+		if err != nil {
+			status.Message = err.Error()
+			logger.Error(err, "Cleanup hook failed", "hook", hook.Name, "attempt", status.Attempts)
+		}
+		if int(status.Attempts) < hook.Retry.MaxAttempts {
+			return false
+		}
+
+		message := fmt.Sprintf("Cleanup hook %s did not acknowledge the deletion after %d attempts: %s",
+			hook.Name, status.Attempts, status.Message)
+		if hook.FailurePolicy == cleanuphook.FailurePolicyProceed {
+			status.Phase = workspacev1alpha1.CleanupHookAbandoned
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonCleanupHookFailed,
+				message+"; deletion proceeds, its external resources may remain")
+			continue
+		}
+		status.Phase = workspacev1alpha1.CleanupHookFailed
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonCleanupHookFailed,
+			fmt.Sprintf("%s; deletion is held until an administrator adds the hook to the annotation %s",
+				message, AnnotationSkipCleanupHooks))
+		return false
+	}
+	return true
+}
+
+// cleanupHooksProgress describes the state of every cleanup hook for the Terminating condition, and
+// returns when the hook waited for is called again. Held hooks are checked every LongRequeueDelay:
+// adding the skip annotation updates the workspace, which reconciles it right away.
+func (sm *StateMachine) cleanupHooksProgress(workspace *workspacev1alpha1.Workspace, now time.Time) (string, time.Duration) {
+	states := make([]string, 0, len(sm.cleanupHooks))
+	retryAfter := time.Duration(0)
+	for _, hook := range sm.cleanupHooks {
+		status := cleanupHookStatus(workspace, hook.Name)
+		state := fmt.Sprintf("%s %s", hook.Name, status.Phase)
+		if !cleanupHookDone(status.Phase) {
+			state += fmt.Sprintf(" (%d/%d attempts)", status.Attempts, hook.Retry.MaxAttempts)
+		}
+		states = append(states, state)
+		if retryAfter != 0 || cleanupHookDone(status.Phase) {
+			continue
+		}
+		retryAfter = LongRequeueDelay
+		if status.Phase == workspacev1alpha1.CleanupHookPending {
+			retryAfter = MinimalRequeueDelay
+			if status.LastAttemptTime != nil {
+				retryAfter = max(status.LastAttemptTime.Add(hook.Retry.Interval.Duration).Sub(now), MinimalRequeueDelay)
+			}
+		}
+	}
+	return "Waiting for cleanup hooks: " + strings.Join(states, ", "), retryAfter
+}
@@ -0,0 +1,205 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/cleanuphook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// cleanupHookServer answers the calls of a test with the statuses in order, repeating the last one
+type cleanupHookServer struct {
+	*httptest.Server
+	calls    atomic.Int32
+	requests chan cleanuphook.Request
+}
+
+func newCleanupHookServer(t *testing.T, statuses ...int) *cleanupHookServer {
+	server := &cleanupHookServer{requests: make(chan cleanuphook.Request, 10)}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(server.calls.Add(1))
+		request := cleanuphook.Request{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		server.requests <- request
+		w.WriteHeader(statuses[min(call, len(statuses))-1])
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newCleanupHook(name, url string, maxAttempts int, policy cleanuphook.FailurePolicy) cleanuphook.Hook {
+	return cleanuphook.Hook{
+		Name:          name,
+		URL:           url,
+		Timeout:       metav1.Duration{Duration: 5 * time.Second},
+		Retry:         cleanuphook.RetryPolicy{MaxAttempts: maxAttempts, Interval: metav1.Duration{Duration: time.Minute}},
+		FailurePolicy: policy,
+	}
+}
+
+func newCleanupHookTestStateMachine(t *testing.T, workspace *workspacev1alpha1.Workspace, hooks ...cleanuphook.Hook) *StateMachine {
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.cleanupHooks = hooks
+	sm.cleanupHookClient = cleanuphook.NewClient(k8sClient, "jupyter-k8s-system")
+	return sm
+}
+
+func TestCleanupHooksAreRetriedUntilAcknowledged(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	server := newCleanupHookServer(t, http.StatusInternalServerError, http.StatusAccepted, http.StatusNoContent)
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationCreatedBy: "alice"}
+	sm := newCleanupHookTestStateMachine(t, workspace,
+		newCleanupHook("home-dirs", server.URL, 5, cleanuphook.FailurePolicyHold))
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now))
+	require.Len(t, workspace.Status.CleanupHooks, 1)
+	status := workspace.Status.CleanupHooks[0]
+	assert.Equal(t, workspacev1alpha1.CleanupHookPending, status.Phase)
+	assert.Equal(t, int32(1), status.Attempts)
+	assert.Contains(t, status.Message, "hook answered 500")
+	assert.Equal(t, cleanuphook.Request{
+		Hook:      "home-dirs",
+		Workspace: cleanuphook.Workspace{Name: "ws", Namespace: "team-a", UID: "ws-uid", Owner: "alice"},
+	}, <-server.requests)
+
+	// The hook is not called again before its retry interval
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now.Add(30*time.Second)))
+	assert.Equal(t, int32(1), server.calls.Load())
+	message, retryAfter := sm.cleanupHooksProgress(workspace, now.Add(30*time.Second))
+	assert.Equal(t, "Waiting for cleanup hooks: home-dirs Pending (1/5 attempts)", message)
+	assert.Equal(t, 30*time.Second, retryAfter)
+
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now.Add(time.Minute)))
+	assert.Equal(t, "Deletion in progress", workspace.Status.CleanupHooks[0].Message)
+
+	assert.True(t, sm.runCleanupHooks(ctx, workspace, now.Add(2*time.Minute)))
+	status = workspace.Status.CleanupHooks[0]
+	assert.Equal(t, workspacev1alpha1.CleanupHookAcknowledged, status.Phase)
+	assert.Equal(t, int32(3), status.Attempts)
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, fmt.Sprintf("%s %s", corev1.EventTypeNormal, EventReasonCleanupHookAcknowledged))
+
+	// Acknowledged hooks are not called again, e.g. when finalization restarts
+	assert.True(t, sm.runCleanupHooks(ctx, workspace, now.Add(time.Hour)))
+	assert.Equal(t, int32(3), server.calls.Load())
+}
+
+func TestExhaustedCleanupHooksHoldTheDeletionUntilSkipped(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	server := newCleanupHookServer(t, http.StatusServiceUnavailable)
+	workspace := newAdoptionTestWorkspace("ws")
+	sm := newCleanupHookTestStateMachine(t, workspace,
+		newCleanupHook("home-dirs", server.URL, 2, cleanuphook.FailurePolicyHold))
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now))
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now.Add(time.Minute)))
+	assert.Equal(t, workspacev1alpha1.CleanupHookFailed, workspace.Status.CleanupHooks[0].Phase)
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Contains(t, event, fmt.Sprintf("%s %s", corev1.EventTypeWarning, EventReasonCleanupHookFailed))
+	assert.Contains(t, event, AnnotationSkipCleanupHooks)
+
+	// Held hooks are neither called again nor announced again
+	assert.False(t, sm.runCleanupHooks(ctx, workspace, now.Add(time.Hour)))
+	assert.Equal(t, int32(2), server.calls.Load())
+	assert.Empty(t, events)
+	message, retryAfter := sm.cleanupHooksProgress(workspace, now.Add(time.Hour))
+	assert.Equal(t, "Waiting for cleanup hooks: home-dirs Failed (2/2 attempts)", message)
+	assert.Equal(t, LongRequeueDelay, retryAfter)
+
+	workspace.Annotations = map[string]string{AnnotationSkipCleanupHooks: "other, home-dirs"}
+	assert.True(t, sm.runCleanupHooks(ctx, workspace, now.Add(time.Hour)))
+	assert.Equal(t, workspacev1alpha1.CleanupHookSkipped, workspace.Status.CleanupHooks[0].Phase)
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, fmt.Sprintf("%s %s", corev1.EventTypeWarning, EventReasonCleanupHookSkipped))
+}
+
+func TestExhaustedCleanupHooksProceedToTheNextHook(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	failing := newCleanupHookServer(t, http.StatusInternalServerError)
+	acknowledging := newCleanupHookServer(t, http.StatusOK)
+	workspace := newAdoptionTestWorkspace("ws")
+	sm := newCleanupHookTestStateMachine(t, workspace,
+		newCleanupHook("audit", failing.URL, 1, cleanuphook.FailurePolicyProceed),
+		newCleanupHook("home-dirs", acknowledging.URL, 5, cleanuphook.FailurePolicyHold))
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	assert.True(t, sm.runCleanupHooks(ctx, workspace, now))
+	require.Len(t, workspace.Status.CleanupHooks, 2)
+	assert.Equal(t, workspacev1alpha1.CleanupHookAbandoned, workspace.Status.CleanupHooks[0].Phase)
+	assert.Equal(t, workspacev1alpha1.CleanupHookAcknowledged, workspace.Status.CleanupHooks[1].Phase)
+	require.Len(t, events, 2)
+	assert.Contains(t, <-events, EventReasonCleanupHookFailed)
+	assert.Contains(t, <-events, EventReasonCleanupHookAcknowledged)
+}
+
+func TestCleanupHooksRunOneAtATime(t *testing.T) {
+	first := newCleanupHookServer(t, http.StatusAccepted)
+	second := newCleanupHookServer(t, http.StatusOK)
+	workspace := newAdoptionTestWorkspace("ws")
+	sm := newCleanupHookTestStateMachine(t, workspace,
+		newCleanupHook("home-dirs", first.URL, 5, cleanuphook.FailurePolicyHold),
+		newCleanupHook("audit", second.URL, 5, cleanuphook.FailurePolicyHold))
+
+	assert.False(t, sm.runCleanupHooks(context.Background(), workspace, time.Now()))
+	assert.Equal(t, int32(0), second.calls.Load(), "a hook is only called once the previous one is done")
+	message, _ := sm.cleanupHooksProgress(workspace, time.Now())
+	assert.Equal(t, "Waiting for cleanup hooks: home-dirs Pending (1/5 attempts), audit Pending (0/5 attempts)", message)
+}
+
+func TestCleanupHookTimeoutsAreAttempts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	hook := newCleanupHook("home-dirs", server.URL, 1, cleanuphook.FailurePolicyProceed)
+	hook.Timeout = metav1.Duration{Duration: 50 * time.Millisecond}
+	workspace := newAdoptionTestWorkspace("ws")
+	sm := newCleanupHookTestStateMachine(t, workspace, hook)
+
+	assert.True(t, sm.runCleanupHooks(context.Background(), workspace, time.Now()))
+	status := workspace.Status.CleanupHooks[0]
+	assert.Equal(t, workspacev1alpha1.CleanupHookAbandoned, status.Phase)
+	assert.Equal(t, int32(1), status.Attempts)
+	assert.Contains(t, status.Message, "deadline exceeded")
+}
+
+func TestCleanupHooksStepIsOnlyAddedWhenConfigured(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	sm := newCleanupHookTestStateMachine(t, workspace)
+	steps := sm.finalizationSteps()
+	assert.Equal(t, ReasonDeletingStorage, steps[len(steps)-1].reason)
+
+	sm.cleanupHooks = []cleanuphook.Hook{newCleanupHook("home-dirs", "http://localhost", 1, cleanuphook.FailurePolicyHold)}
+	steps = sm.finalizationSteps()
+	assert.Equal(t, ReasonRunningCleanupHooks, steps[len(steps)-1].reason, "the hooks run once the storage is deleted")
+}
@@ -0,0 +1,225 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;create;delete
+
+// CloneRequeueDelay is how often a workspace whose clone source is not ready yet is reconciled again:
+// the controller does not watch the source workspace nor the VolumeSnapshot
+const CloneRequeueDelay = 10 * time.Second
+
+// volumeSnapshotGVK is the kind of the VolumeSnapshots home volumes are cloned through; the snapshot API is an
+// optional CRD, so it is handled unstructured
+var volumeSnapshotGVK = schema.GroupVersionKind{Group: "snapshot.storage.k8s.io", Version: "v1", Kind: "VolumeSnapshot"}
+
+// pvcDataSource is what the PVC of a cloned workspace is populated from
+type pvcDataSource struct {
+	ref corev1.TypedLocalObjectReference
+	// storageClassName is the StorageClass of the source volume, used when the workspace sets none
+	storageClassName *string
+}
+
+// reconcileClone populates the home volume of a workspace with spec.cloneFrom from the volume of its source,
+// and reports the copy in the CloneInProgress and CloneComplete conditions, with an event on each transition.
+// The status is updated in memory. Before the PVC exists, it returns the condition blocking its creation
+// while the source is not ready; once the PVC exists, the workspace starts and its pod waits for the copy.
+func (sm *StateMachine) reconcileClone(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*metav1.Condition, error) {
+	if workspace.Spec.CloneFrom == nil || !hasPersistentStorage(workspace) ||
+		apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeCloneComplete) {
+		return nil, nil
+	}
+	rm := sm.resourceManager
+	source := workspace.Spec.CloneFrom.WorkspaceName
+
+	pvc, err := rm.getPVC(ctx, workspace)
+	if err == nil {
+		if ownership, _ := classifyChild(workspace, pvc); ownership != childOwned {
+			return nil, nil
+		}
+		if !pvcBound(pvc) {
+			sm.setCloneConditions(workspace, true, ReasonCloneProvisioning, fmt.Sprintf(
+				"PVC %s is being populated from the home volume of workspace %s", pvc.Name, source))
+			return nil, nil
+		}
+		if err := rm.deleteCloneSnapshot(ctx, workspace); err != nil {
+			return nil, err
+		}
+		sm.setCloneConditions(workspace, false, ReasonCloneSucceeded, fmt.Sprintf(
+			"PVC %s holds a copy of the home volume of workspace %s", pvc.Name, source))
+		return nil, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get PVC: %w", err)
+	}
+
+	dataSource, reason, message, err := rm.cloneDataSource(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	if dataSource == nil {
+		blocking := sm.setCloneConditions(workspace, true, reason, message)
+		return &blocking, nil
+	}
+	pvc, err = rm.createPVCFrom(ctx, workspace, dataSource)
+	if err != nil {
+		return nil, err
+	}
+	logf.FromContext(ctx).Info("Cloning the home volume", "pvc", pvc.Name, "source", dataSource.ref.Name)
+	sm.setCloneConditions(workspace, true, ReasonCloneProvisioning, fmt.Sprintf(
+		"PVC %s is being populated from the home volume of workspace %s", pvc.Name, source))
+	return nil, nil
+}
+
+// setCloneConditions sets the CloneInProgress and CloneComplete conditions, with an event when the reason
+// changes, and returns the CloneInProgress condition
+func (sm *StateMachine) setCloneConditions(
+	workspace *workspacev1alpha1.Workspace, inProgress bool, reason, message string) metav1.Condition {
+	inProgressStatus, completeStatus := metav1.ConditionFalse, metav1.ConditionTrue
+	if inProgress {
+		inProgressStatus, completeStatus = metav1.ConditionTrue, metav1.ConditionFalse
+	}
+	if previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeCloneInProgress); previous == nil || previous.Reason != reason {
+		eventType := corev1.EventTypeNormal
+		if reason == ReasonCloneSourceUnavailable {
+			eventType = corev1.EventTypeWarning
+		}
+		sm.recorder.Event(workspace, eventType, reason, message)
+	}
+	inProgressCondition := NewCondition(ConditionTypeCloneInProgress, inProgressStatus, reason, message)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, inProgressCondition)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeCloneComplete, completeStatus, reason, message))
+	return inProgressCondition
+}
+
+// cloneDataSource returns what the PVC of the workspace is populated from: the home volume of the source
+// workspace, or a VolumeSnapshot of it when the workspace names a VolumeSnapshotClass. It returns no data
+// source, and the reason and message why, while the source is not ready.
+func (rm *ResourceManager) cloneDataSource(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) (*pvcDataSource, string, string, error) {
+	cloneFrom := workspace.Spec.CloneFrom
+	source := &workspacev1alpha1.Workspace{}
+	err := rm.client.Get(ctx, types.NamespacedName{Name: cloneFrom.WorkspaceName, Namespace: workspace.Namespace}, source)
+	if apierrors.IsNotFound(err) {
+		return nil, ReasonCloneSourceUnavailable, fmt.Sprintf(
+			"Source workspace %s does not exist", cloneFrom.WorkspaceName), nil
+	}
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get source workspace %s: %w", cloneFrom.WorkspaceName, err)
+	}
+
+	sourcePVC := &corev1.PersistentVolumeClaim{}
+	err = rm.client.Get(ctx, types.NamespacedName{Name: pvcNameFor(source), Namespace: source.Namespace}, sourcePVC)
+	if apierrors.IsNotFound(err) {
+		return nil, ReasonCloneSourceUnavailable, fmt.Sprintf(
+			"Source workspace %s has no home volume", source.Name), nil
+	}
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get PVC of source workspace %s: %w", source.Name, err)
+	}
+	if !pvcBound(sourcePVC) {
+		return nil, ReasonCloneSourceUnavailable, fmt.Sprintf(
+			"Home volume %s of source workspace %s is not bound", sourcePVC.Name, source.Name), nil
+	}
+
+	if cloneFrom.VolumeSnapshotClassName == "" {
+		return &pvcDataSource{
+			ref:              corev1.TypedLocalObjectReference{Kind: KindPersistentVolumeClaim, Name: sourcePVC.Name},
+			storageClassName: sourcePVC.Spec.StorageClassName,
+		}, "", "", nil
+	}
+	snapshot, reason, message, err := rm.ensureCloneSnapshot(ctx, workspace, sourcePVC)
+	if err != nil || snapshot == "" {
+		return nil, reason, message, err
+	}
+	return &pvcDataSource{ref: corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To(volumeSnapshotGVK.Group),
+		Kind:     volumeSnapshotGVK.Kind,
+		Name:     snapshot,
+	}}, "", "", nil
+}
+
+// ensureCloneSnapshot creates the VolumeSnapshot of the source volume the workspace is cloned through, and
+// returns its name once it is ready to use. It returns an empty name, and the reason and message why, until then.
+func (rm *ResourceManager) ensureCloneSnapshot(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	sourcePVC *corev1.PersistentVolumeClaim) (string, string, string, error) {
+	name := cloneSnapshotNameFor(workspace)
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := rm.client.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, snapshot)
+	if apimeta.IsNoMatchError(err) {
+		return "", ReasonCloneSourceUnavailable, fmt.Sprintf(
+			"The cluster has no VolumeSnapshot API to clone through VolumeSnapshotClass %s",
+			workspace.Spec.CloneFrom.VolumeSnapshotClassName), nil
+	}
+	if apierrors.IsNotFound(err) {
+		snapshot.SetName(name)
+		snapshot.SetNamespace(workspace.Namespace)
+		snapshot.SetLabels(GenerateLabels(workspace.Name))
+		snapshot.Object["spec"] = map[string]any{
+			"volumeSnapshotClassName": workspace.Spec.CloneFrom.VolumeSnapshotClassName,
+			"source":                  map[string]any{"persistentVolumeClaimName": sourcePVC.Name},
+		}
+		if err := controllerutil.SetControllerReference(workspace, snapshot, rm.scheme); err != nil {
+			return "", "", "", fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := rm.client.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+			return "", "", "", fmt.Errorf("failed to create VolumeSnapshot %s: %w", name, err)
+		}
+		return "", ReasonCloneSnapshotting, fmt.Sprintf(
+			"Taking VolumeSnapshot %s of the home volume %s", name, sourcePVC.Name), nil
+	}
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get VolumeSnapshot %s: %w", name, err)
+	}
+
+	if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); ready {
+		return name, "", "", nil
+	}
+	if failure, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); failure != "" {
+		return "", ReasonCloneSourceUnavailable, fmt.Sprintf(
+			"VolumeSnapshot %s of the home volume %s failed: %s", name, sourcePVC.Name, failure), nil
+	}
+	return "", ReasonCloneSnapshotting, fmt.Sprintf(
+		"Waiting for VolumeSnapshot %s of the home volume %s to be ready", name, sourcePVC.Name), nil
+}
+
+// deleteCloneSnapshot deletes the VolumeSnapshot the workspace was cloned through, once its volume is bound
+func (rm *ResourceManager) deleteCloneSnapshot(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.CloneFrom.VolumeSnapshotClassName == "" {
+		return nil
+	}
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(cloneSnapshotNameFor(workspace))
+	snapshot.SetNamespace(workspace.Namespace)
+	err := rm.client.Delete(ctx, snapshot)
+	if err != nil && !apierrors.IsNotFound(err) && !apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete VolumeSnapshot %s: %w", snapshot.GetName(), err)
+	}
+	return nil
+}
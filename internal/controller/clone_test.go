@@ -0,0 +1,206 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newCloneTestSource returns the source workspace of the clone tests and its bound home volume
+func newCloneTestSource() (*workspacev1alpha1.Workspace, *corev1.PersistentVolumeClaim) {
+	source := newAdoptionTestWorkspace("onboarding")
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: pvcNameFor(source), Namespace: source.Namespace},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To("gp3"),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+	return source, pvc
+}
+
+// newCloneTestWorkspace returns a workspace cloning the source workspace
+func newCloneTestWorkspace(snapshotClass string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("new-teammate")
+	workspace.Spec.CloneFrom = &workspacev1alpha1.CloneSpec{WorkspaceName: "onboarding", VolumeSnapshotClassName: snapshotClass}
+	return workspace
+}
+
+// bindCloneTestPVC marks the PVC of the workspace bound
+func bindCloneTestPVC(t *testing.T, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) {
+	ctx := context.Background()
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: pvcNameFor(workspace), Namespace: workspace.Namespace}, pvc))
+	pvc.Status.Phase = corev1.ClaimBound
+	require.NoError(t, k8sClient.Status().Update(ctx, pvc))
+}
+
+func TestCloneCreatesTheHomeVolumeFromTheSourceVolume(t *testing.T) {
+	ctx := context.Background()
+	source, sourcePVC := newCloneTestSource()
+	workspace := newCloneTestWorkspace("")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, source, sourcePVC, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	blocking, err := sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocking, "the workspace starts while its volume is populated")
+	pvc, err := sm.resourceManager.getPVC(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, pvc.Spec.DataSource)
+	assert.Equal(t, corev1.TypedLocalObjectReference{Kind: "PersistentVolumeClaim", Name: sourcePVC.Name}, *pvc.Spec.DataSource)
+	assert.Equal(t, ptr.To("gp3"), pvc.Spec.StorageClassName, "a clone is in the StorageClass of its source")
+	assert.True(t, apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeCloneInProgress))
+	assert.True(t, apimeta.IsStatusConditionFalse(workspace.Status.Conditions, ConditionTypeCloneComplete))
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal CloneProvisioning")
+
+	// Still provisioning: nothing is reported again
+	_, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Events)
+
+	bindCloneTestPVC(t, k8sClient, workspace)
+	_, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, apimeta.IsStatusConditionFalse(workspace.Status.Conditions, ConditionTypeCloneInProgress))
+	complete := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeCloneComplete)
+	require.NotNil(t, complete)
+	assert.Equal(t, metav1.ConditionTrue, complete.Status)
+	assert.Equal(t, ReasonCloneSucceeded, complete.Reason)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal CloneSucceeded")
+}
+
+func TestCloneWaitsForItsSource(t *testing.T) {
+	ctx := context.Background()
+	source, sourcePVC := newCloneTestSource()
+	sourcePVC.Status.Phase = corev1.ClaimPending
+	workspace := newCloneTestWorkspace("")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	blocking, err := sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocking)
+	assert.Equal(t, ReasonCloneSourceUnavailable, blocking.Reason)
+	assert.Contains(t, blocking.Message, "Source workspace onboarding does not exist")
+	_, err = sm.resourceManager.getPVC(ctx, workspace)
+	assert.True(t, apierrors.IsNotFound(err), "no empty volume is created in place of the copy")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning CloneSourceUnavailable")
+
+	require.NoError(t, k8sClient.Create(ctx, source))
+	require.NoError(t, k8sClient.Create(ctx, sourcePVC))
+	blocking, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocking)
+	assert.Contains(t, blocking.Message, "is not bound")
+	assert.Empty(t, recorder.Events, "the reason did not change")
+
+	sourcePVC.Status.Phase = corev1.ClaimBound
+	require.NoError(t, k8sClient.Status().Update(ctx, sourcePVC))
+	blocking, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocking)
+	_, err = sm.resourceManager.getPVC(ctx, workspace)
+	assert.NoError(t, err)
+}
+
+func TestCloneThroughAVolumeSnapshot(t *testing.T) {
+	ctx := context.Background()
+	source, sourcePVC := newCloneTestSource()
+	workspace := newCloneTestWorkspace("ebs-snapshots")
+	workspace.Spec.Storage.StorageClassName = ptr.To("io2")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, source, sourcePVC, workspace)
+
+	blocking, err := sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocking)
+	assert.Equal(t, ReasonCloneSnapshotting, blocking.Reason)
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	key := client.ObjectKey{Name: cloneSnapshotNameFor(workspace), Namespace: workspace.Namespace}
+	require.NoError(t, k8sClient.Get(ctx, key, snapshot))
+	className, _, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	assert.Equal(t, "ebs-snapshots", className)
+	sourceName, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal(t, sourcePVC.Name, sourceName)
+	require.Len(t, snapshot.GetOwnerReferences(), 1)
+	assert.Equal(t, workspace.Name, snapshot.GetOwnerReferences()[0].Name)
+
+	// The volume is created once the snapshot is ready to use
+	require.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+	require.NoError(t, k8sClient.Update(ctx, snapshot))
+	blocking, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocking)
+	pvc, err := sm.resourceManager.getPVC(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, pvc.Spec.DataSource)
+	assert.Equal(t, "VolumeSnapshot", pvc.Spec.DataSource.Kind)
+	assert.Equal(t, ptr.To("snapshot.storage.k8s.io"), pvc.Spec.DataSource.APIGroup)
+	assert.Equal(t, ptr.To("io2"), pvc.Spec.StorageClassName, "a snapshot is restored in the StorageClass of the workspace")
+
+	// The snapshot is deleted once the copy is bound
+	bindCloneTestPVC(t, k8sClient, workspace)
+	_, err = sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeCloneComplete))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, key, snapshot)))
+}
+
+func TestCloneThroughAVolumeSnapshotWithoutTheSnapshotAPI(t *testing.T) {
+	ctx := context.Background()
+	source, sourcePVC := newCloneTestSource()
+	workspace := newCloneTestWorkspace("ebs-snapshots")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				return &apimeta.NoKindMatchError{GroupKind: volumeSnapshotGVK.GroupKind()}
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, source, sourcePVC, workspace)
+
+	blocking, err := sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocking)
+	assert.Equal(t, ReasonCloneSourceUnavailable, blocking.Reason)
+	assert.Contains(t, blocking.Message, "no VolumeSnapshot API")
+}
+
+func TestWorkspacesThatDoNotCloneAreNotReported(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	blocking, err := sm.reconcileClone(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocking)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeCloneInProgress))
+	_, err = sm.resourceManager.getPVC(ctx, workspace)
+	assert.True(t, apierrors.IsNotFound(err), "EnsurePVCExists creates the volume of other workspaces")
+}
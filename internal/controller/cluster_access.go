@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ServiceAccountTokenVolumeName is the name of the projected volume holding the service account token
+	ServiceAccountTokenVolumeName = "workspace-sa-token"
+
+	// ServiceAccountTokenMountPath is where the projected service account token is mounted: the path
+	// in-cluster Kubernetes clients read it from
+	ServiceAccountTokenMountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+	// DefaultServiceAccountTokenExpirationSeconds is the validity of projected service account tokens
+	// when the template does not set one
+	DefaultServiceAccountTokenExpirationSeconds = int64(3600)
+)
+
+// ResolveClusterAccess records the cluster access of the admitted template revision in Status.ClusterAccess
+// for the deployment builder to apply. The status is updated in memory. A nil template, for workspaces
+// without one, clears the cluster access.
+func (rm *ResourceManager) ResolveClusterAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.ClusterAccess = nil
+		return nil
+	}
+	workspace.Status.ClusterAccess = template.Spec.ClusterAccess.DeepCopy()
+	return nil
+}
+
+// applyClusterAccess sets how the workspace pod gets the token of its service account. Without cluster
+// access no token is mounted; with it, a projected token is mounted in the workspace container where
+// in-cluster clients expect it, unless the template keeps the legacy automounted token.
+func applyClusterAccess(podSpec *corev1.PodSpec, access *workspacev1alpha1.ClusterAccessSpec) {
+	if access == nil || !access.Enabled {
+		podSpec.AutomountServiceAccountToken = ptr.To(false)
+		return
+	}
+	if access.TokenMount == workspacev1alpha1.ServiceAccountTokenMountLegacy {
+		// The service account decides, as before cluster access was configurable
+		return
+	}
+
+	podSpec.AutomountServiceAccountToken = ptr.To(false)
+	expirationSeconds := DefaultServiceAccountTokenExpirationSeconds
+	if access.ExpirationSeconds != nil {
+		expirationSeconds = *access.ExpirationSeconds
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: ServiceAccountTokenVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+						Audience:          access.Audience,
+						ExpirationSeconds: &expirationSeconds,
+						Path:              "token",
+					}},
+					{ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "kube-root-ca.crt"},
+						Items:                []corev1.KeyToPath{{Key: "ca.crt", Path: "ca.crt"}},
+					}},
+					{DownwardAPI: &corev1.DownwardAPIProjection{
+						Items: []corev1.DownwardAPIVolumeFile{{
+							Path:     "namespace",
+							FieldRef: &corev1.ObjectFieldSelector{APIVersion: "v1", FieldPath: "metadata.namespace"},
+						}},
+					}},
+				},
+			},
+		},
+	})
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name != workspaceContainerName {
+			continue
+		}
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      ServiceAccountTokenVolumeName,
+			MountPath: ServiceAccountTokenMountPath,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// renderClusterAccessTestPod renders the pod of a workspace with the resolved cluster access
+func renderClusterAccessTestPod(t *testing.T, access *workspacev1alpha1.ClusterAccessSpec) corev1.PodSpec {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Status.ClusterAccess = access
+	return renderHashTestDeployment(t, workspace).Spec.Template.Spec
+}
+
+// findTokenVolume returns the projected service account token volume of the pod, nil if none
+func findTokenVolume(podSpec corev1.PodSpec) *corev1.Volume {
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == ServiceAccountTokenVolumeName {
+			return &podSpec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+// findTokenMount returns the service account token mount of the workspace container, nil if none
+func findTokenMount(podSpec corev1.PodSpec) *corev1.VolumeMount {
+	for i := range podSpec.Containers[0].VolumeMounts {
+		if podSpec.Containers[0].VolumeMounts[i].Name == ServiceAccountTokenVolumeName {
+			return &podSpec.Containers[0].VolumeMounts[i]
+		}
+	}
+	return nil
+}
+
+func TestPodsWithoutClusterAccessMountNoToken(t *testing.T) {
+	for name, access := range map[string]*workspacev1alpha1.ClusterAccessSpec{
+		"no cluster access":        nil,
+		"disabled":                 {Enabled: false},
+		"disabled legacy mount":    {Enabled: false, TokenMount: workspacev1alpha1.ServiceAccountTokenMountLegacy},
+		"disabled projected mount": {Enabled: false, TokenMount: workspacev1alpha1.ServiceAccountTokenMountProjected},
+	} {
+		t.Run(name, func(t *testing.T) {
+			podSpec := renderClusterAccessTestPod(t, access)
+			assert.Equal(t, ptr.To(false), podSpec.AutomountServiceAccountToken)
+			assert.Nil(t, findTokenVolume(podSpec))
+			assert.Nil(t, findTokenMount(podSpec))
+		})
+	}
+}
+
+func TestPodsWithClusterAccessMountAProjectedToken(t *testing.T) {
+	podSpec := renderClusterAccessTestPod(t, &workspacev1alpha1.ClusterAccessSpec{
+		Enabled:    true,
+		TokenMount: workspacev1alpha1.ServiceAccountTokenMountProjected,
+	})
+	assert.Equal(t, ptr.To(false), podSpec.AutomountServiceAccountToken, "the legacy token is not mounted")
+	assert.Equal(t, "notebook", podSpec.ServiceAccountName)
+
+	volume := findTokenVolume(podSpec)
+	require.NotNil(t, volume)
+	require.NotNil(t, volume.Projected)
+	require.Len(t, volume.Projected.Sources, 3)
+	token := volume.Projected.Sources[0].ServiceAccountToken
+	require.NotNil(t, token)
+	assert.Empty(t, token.Audience, "the API server audience")
+	assert.Equal(t, ptr.To(DefaultServiceAccountTokenExpirationSeconds), token.ExpirationSeconds)
+	assert.Equal(t, "kube-root-ca.crt", volume.Projected.Sources[1].ConfigMap.Name)
+	assert.Equal(t, "metadata.namespace", volume.Projected.Sources[2].DownwardAPI.Items[0].FieldRef.FieldPath)
+
+	mount := findTokenMount(podSpec)
+	require.NotNil(t, mount)
+	assert.Equal(t, ServiceAccountTokenMountPath, mount.MountPath)
+	assert.True(t, mount.ReadOnly)
+}
+
+func TestPodsWithClusterAccessUseTheTemplateAudienceAndExpiration(t *testing.T) {
+	// An unset token mount is the Projected default
+	podSpec := renderClusterAccessTestPod(t, &workspacev1alpha1.ClusterAccessSpec{
+		Enabled:           true,
+		Audience:          "vault",
+		ExpirationSeconds: ptr.To(int64(600)),
+	})
+	volume := findTokenVolume(podSpec)
+	require.NotNil(t, volume)
+	token := volume.Projected.Sources[0].ServiceAccountToken
+	assert.Equal(t, "vault", token.Audience)
+	assert.Equal(t, ptr.To(int64(600)), token.ExpirationSeconds)
+}
+
+func TestPodsWithLegacyClusterAccessKeepTheAutomountedToken(t *testing.T) {
+	podSpec := renderClusterAccessTestPod(t, &workspacev1alpha1.ClusterAccessSpec{
+		Enabled:    true,
+		TokenMount: workspacev1alpha1.ServiceAccountTokenMountLegacy,
+	})
+	assert.Nil(t, podSpec.AutomountServiceAccountToken, "the service account decides")
+	assert.Nil(t, findTokenVolume(podSpec))
+	assert.Nil(t, findTokenMount(podSpec))
+}
+
+func TestResolveClusterAccessFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "kubectl", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:   "kubectl",
+			ClusterAccess: &workspacev1alpha1.ClusterAccessSpec{Enabled: true},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "kubectl"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveClusterAccess(ctx, workspace, template))
+	require.NotNil(t, workspace.Status.ClusterAccess)
+	assert.True(t, workspace.Status.ClusterAccess.Enabled)
+
+	// A deleted template leaves the resolved cluster access in place
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.NotNil(t, workspace.Status.ClusterAccess)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveClusterAccess(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.ClusterAccess)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// collaboratorRoles are the roles of the collaborators of a workspace, each granted by its own Role and RoleBinding
+var collaboratorRoles = []workspacev1alpha1.CollaboratorRole{
+	workspacev1alpha1.CollaboratorRoleViewer,
+	workspacev1alpha1.CollaboratorRoleEditor,
+}
+
+// collaboratorAccessVerbs are the verbs each collaborator role is granted on the workspace; the webhook admits
+// the updates of editors of OwnerOnly workspaces, but not their changes to its sharing
+var collaboratorAccessVerbs = map[workspacev1alpha1.CollaboratorRole][]string{
+	workspacev1alpha1.CollaboratorRoleViewer: {"get"},
+	workspacev1alpha1.CollaboratorRoleEditor: {"get", "update", "patch"},
+}
+
+// collaboratorSubjects returns the subjects of the collaborators of the workspace in role, in the order of the spec
+func collaboratorSubjects(workspace *workspacev1alpha1.Workspace, role workspacev1alpha1.CollaboratorRole) []rbacv1.Subject {
+	var subjects []rbacv1.Subject
+	for _, collaborator := range workspace.Spec.Collaborators {
+		if collaborator.Role == role {
+			subjects = append(subjects, rbacv1.Subject{Kind: collaborator.Kind, APIGroup: rbacv1.GroupName, Name: collaborator.Name})
+		}
+	}
+	return subjects
+}
+
+// BuildCollaboratorAccess returns the Role, owned by the workspace, granting the verbs of role on the workspace
+// only, and the RoleBinding granting it to the collaborators of the workspace in role
+func BuildCollaboratorAccess(
+	workspace *workspacev1alpha1.Workspace,
+	role workspacev1alpha1.CollaboratorRole,
+	scheme *runtime.Scheme) (*rbacv1.Role, *rbacv1.RoleBinding, error) {
+	return buildWorkspaceAccess(workspace, collaboratorAccessNameFor(workspace, role), collaboratorAccessVerbs[role],
+		collaboratorSubjects(workspace, role), scheme)
+}
+
+// ReconcileCollaboratorAccess keeps a Role and RoleBinding per collaborator role granting the collaborators
+// of the workspace access to it, and deletes those of the roles no collaborator has anymore. Once every
+// binding is in sync, the collaborators are reported in status, for the next status update to persist.
+func (rm *ResourceManager) ReconcileCollaboratorAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	for _, role := range collaboratorRoles {
+		if len(collaboratorSubjects(workspace, role)) == 0 {
+			if err := rm.deleteWorkspaceAccess(ctx, workspace, collaboratorAccessNameFor(workspace, role)); err != nil {
+				return err
+			}
+			continue
+		}
+		desiredRole, desiredBinding, err := BuildCollaboratorAccess(workspace, role, rm.scheme)
+		if err != nil {
+			return err
+		}
+		if err := rm.ensureWorkspaceAccess(ctx, workspace, desiredRole, desiredBinding); err != nil {
+			return err
+		}
+	}
+
+	var collaborators []workspacev1alpha1.WorkspaceCollaborator
+	for _, collaborator := range workspace.Spec.Collaborators {
+		if _, known := collaboratorAccessVerbs[collaborator.Role]; known {
+			collaborators = append(collaborators, collaborator)
+		}
+	}
+	workspace.Status.Collaborators = collaborators
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestCollaboratorsAreGrantedAccessByRole(t *testing.T) {
+	ctx := context.Background()
+	workspace := newOwnerOnlyTestWorkspace("ws")
+	workspace.Spec.Collaborators = []workspacev1alpha1.WorkspaceCollaborator{
+		{Kind: "User", Name: "bob", Role: workspacev1alpha1.CollaboratorRoleViewer},
+		{Kind: "Group", Name: "reviewers", Role: workspacev1alpha1.CollaboratorRoleEditor},
+		{Kind: "User", Name: "carol", Role: workspacev1alpha1.CollaboratorRoleViewer},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	viewerKey := client.ObjectKey{Name: collaboratorAccessNameFor(workspace, workspacev1alpha1.CollaboratorRoleViewer), Namespace: workspace.Namespace}
+	editorKey := client.ObjectKey{Name: collaboratorAccessNameFor(workspace, workspacev1alpha1.CollaboratorRoleEditor), Namespace: workspace.Namespace}
+
+	require.NoError(t, sm.resourceManager.ReconcileCollaboratorAccess(ctx, workspace))
+	role := &rbacv1.Role{}
+	require.NoError(t, k8sClient.Get(ctx, viewerKey, role))
+	assert.Equal(t, []string{"ws"}, role.Rules[0].ResourceNames)
+	assert.Equal(t, []string{"get"}, role.Rules[0].Verbs)
+	binding := &rbacv1.RoleBinding{}
+	require.NoError(t, k8sClient.Get(ctx, viewerKey, binding))
+	assert.Equal(t, []rbacv1.Subject{
+		{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "bob"},
+		{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "carol"},
+	}, binding.Subjects)
+	require.NoError(t, k8sClient.Get(ctx, editorKey, role))
+	assert.Equal(t, []string{"get", "update", "patch"}, role.Rules[0].Verbs)
+	require.NoError(t, k8sClient.Get(ctx, editorKey, binding))
+	assert.Equal(t, []rbacv1.Subject{{Kind: "Group", APIGroup: "rbac.authorization.k8s.io", Name: "reviewers"}}, binding.Subjects)
+	assert.Equal(t, workspace.Spec.Collaborators, workspace.Status.Collaborators)
+
+	// Removing a collaborator removes it from the binding, and the binding of a role without collaborators
+	workspace.Spec.Collaborators = workspace.Spec.Collaborators[:1]
+	require.NoError(t, sm.resourceManager.ReconcileCollaboratorAccess(ctx, workspace))
+	require.NoError(t, k8sClient.Get(ctx, viewerKey, binding))
+	assert.Equal(t, "bob", binding.Subjects[0].Name)
+	assert.Len(t, binding.Subjects, 1)
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, editorKey, &rbacv1.RoleBinding{})))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, editorKey, &rbacv1.Role{})))
+	assert.Equal(t, workspace.Spec.Collaborators, workspace.Status.Collaborators)
+
+	workspace.Spec.Collaborators = nil
+	require.NoError(t, sm.resourceManager.ReconcileCollaboratorAccess(ctx, workspace))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, viewerKey, &rbacv1.RoleBinding{})))
+	assert.Empty(t, workspace.Status.Collaborators)
+}
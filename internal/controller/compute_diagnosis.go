@@ -0,0 +1,197 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// imagePullWaitingReasons are the container waiting reasons reported when the image cannot be pulled
+var imagePullWaitingReasons = map[string]bool{
+	"ErrImagePull":      true,
+	"ImagePullBackOff":  true,
+	"InvalidImageName":  true,
+	"ErrImageNeverPull": true,
+}
+
+// diagnoseComputeNotReady looks for a known obstacle to the workspace pod becoming ready and returns
+// the Progressing reason and message reporting it, or empty strings when the compute is just starting.
+// Obstacles are looked for in the order the blocked reason reports them: a missing template, a pod the
+// scheduler cannot place, a volume that is not bound, then an image that cannot be pulled.
+// Pods evicted for their ephemeral-storage usage are recorded in the EphemeralStorageEvicted condition, and
+// containers that cannot pull their image in the ImagePullFailed condition.
+func (sm *StateMachine) diagnoseComputeNotReady(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (string, string) {
+	logger := logf.FromContext(ctx)
+	var pods []*corev1.Pod
+	podList := &corev1.PodList{}
+	if err := sm.resourceManager.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace), client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		logger.Error(err, "Failed to list workspace pods")
+	}
+	for i := range podList.Items {
+		if podList.Items[i].DeletionTimestamp == nil {
+			pods = append(pods, &podList.Items[i])
+		}
+	}
+	// The replacement of an evicted pod is not ready yet: this is when the eviction is noticed
+	recordEphemeralStorageEviction(workspace, pods)
+	pullFailure := findImagePullFailure(pods)
+	recordImagePullFailure(workspace, pullFailure)
+
+	resolver := sm.resourceManager.templateResolver
+	if resolver != nil && workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		_, err := resolver.ResolveTemplateRevision(ctx, workspace)
+		if apierrors.IsNotFound(err) {
+			return ReasonTemplateMissing, fmt.Sprintf("Template %q was not found", workspace.Spec.TemplateRef.Name)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to look up the workspace template")
+		}
+	}
+
+	scheduled := false
+	for _, pod := range pods {
+		condition := findPodCondition(pod, corev1.PodScheduled)
+		if condition == nil {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			scheduled = true
+			continue
+		}
+		if condition.Reason != corev1.PodReasonUnschedulable {
+			continue
+		}
+		// The scheduler reports volumes it waits for among the reasons no node fits
+		if strings.Contains(strings.ToLower(condition.Message), "persistentvolumeclaim") {
+			return ReasonStorageNotBound, condition.Message
+		}
+		if message := acceleratorsUnschedulableMessage(workspace, condition.Message); message != "" {
+			return ReasonInsufficientAccelerators, message
+		}
+		return ReasonInsufficientCapacity, condition.Message
+	}
+
+	// Volumes binding on first consumer stay pending until the pod is scheduled
+	if scheduled && hasPersistentStorage(workspace) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := sm.resourceManager.client.Get(ctx,
+			client.ObjectKey{Name: pvcNameFor(workspace), Namespace: workspace.Namespace}, pvc)
+		if err == nil && pvc.Status.Phase == corev1.ClaimPending {
+			return ReasonStorageNotBound, fmt.Sprintf("PersistentVolumeClaim %q is not bound yet", pvc.Name)
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to get the workspace PVC")
+		}
+	}
+
+	if pullFailure != nil {
+		return ReasonImagePullFailed, imagePullFailureMessage(pullFailure)
+	}
+	return "", ""
+}
+
+// findImagePullFailure returns the status of the first container of the pods whose image cannot be pulled,
+// or nil if there is none
+func findImagePullFailure(pods []*corev1.Pod) *corev1.ContainerStatus {
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...),
+			pod.Status.ContainerStatuses...)
+		for i := range statuses {
+			if statuses[i].State.Waiting != nil && imagePullWaitingReasons[statuses[i].State.Waiting.Reason] {
+				return &statuses[i]
+			}
+		}
+	}
+	return nil
+}
+
+// imagePullFailureMessage reports the container that cannot pull its image with the message of the kubelet
+func imagePullFailureMessage(status *corev1.ContainerStatus) string {
+	return fmt.Sprintf("Container %q cannot pull image %q: %s",
+		status.Name, status.Image, status.State.Waiting.Message)
+}
+
+// recordImagePullFailure sets the ImagePullFailed condition in memory while a container of the workspace pod
+// cannot pull its image, and removes it once none fails
+func recordImagePullFailure(workspace *workspacev1alpha1.Workspace, status *corev1.ContainerStatus) {
+	if status == nil {
+		clearImagePullFailedCondition(workspace)
+		return
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeImagePullFailed,
+		metav1.ConditionTrue,
+		ReasonImagePullFailed,
+		imagePullFailureMessage(status),
+	))
+}
+
+// clearImagePullFailedCondition removes the ImagePullFailed condition in memory
+func clearImagePullFailedCondition(workspace *workspacev1alpha1.Workspace) {
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeImagePullFailed)
+}
+
+// recordEphemeralStorageEviction sets the EphemeralStorageEvicted condition in memory when a workspace pod
+// was evicted for its ephemeral-storage usage, advising a larger request. The latest eviction is reported.
+func recordEphemeralStorageEviction(workspace *workspacev1alpha1.Workspace, pods []*corev1.Pod) {
+	var evicted *corev1.Pod
+	for _, pod := range pods {
+		if !isEphemeralStorageEviction(pod) {
+			continue
+		}
+		if evicted == nil || evicted.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			evicted = pod
+		}
+	}
+	if evicted == nil {
+		return
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeEphemeralStorageEvicted,
+		metav1.ConditionTrue,
+		ReasonEphemeralStorageExceeded,
+		fmt.Sprintf("Workspace pod %s was evicted: %s Request more ephemeral-storage in spec.resources, "+
+			"or bound temporary files with spec.tmpVolume.sizeLimit", evicted.Name, strings.TrimSpace(evicted.Status.Message)),
+	))
+}
+
+// clearEphemeralStorageEvictedCondition removes the EphemeralStorageEvicted condition in memory
+func clearEphemeralStorageEvictedCondition(workspace *workspacev1alpha1.Workspace) {
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeEphemeralStorageEvicted)
+}
+
+// isEphemeralStorageEviction returns true if the kubelet evicted the pod for ephemeral-storage: node
+// pressure, a container or pod over its ephemeral-storage limit, or an emptyDir over its size limit
+func isEphemeralStorageEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodFailed || pod.Status.Reason != "Evicted" {
+		return false
+	}
+	message := strings.ToLower(pod.Status.Message)
+	return strings.Contains(message, "ephemeral") || strings.Contains(message, "emptydir")
+}
+
+// findPodCondition returns the condition of the pod matching the type, or nil if not found
+func findPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == conditionType {
+			return &pod.Status.Conditions[i]
+		}
+	}
+	return nil
+}
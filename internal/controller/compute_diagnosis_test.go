@@ -0,0 +1,201 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newPendingWorkspacePod(conditions []corev1.PodCondition, statuses []corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-pod", Namespace: "team-a", Labels: GenerateLabels("ws")},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodPending,
+			Conditions:        conditions,
+			ContainerStatuses: statuses,
+		},
+	}
+}
+
+func TestDiagnoseComputeNotReadyReportsUnschedulablePod(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: 3 Insufficient nvidia.com/gpu.",
+	}}, nil)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	reason, message := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonInsufficientCapacity, reason)
+	assert.Contains(t, message, "Insufficient nvidia.com/gpu")
+}
+
+func TestDiagnoseComputeNotReadyReportsUnboundVolume(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{
+		Type:    corev1.PodScheduled,
+		Status:  corev1.ConditionFalse,
+		Reason:  corev1.PodReasonUnschedulable,
+		Message: "0/3 nodes are available: pod has unbound immediate PersistentVolumeClaims.",
+	}}, nil)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	reason, _ := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonStorageNotBound, reason)
+}
+
+func TestDiagnoseComputeNotReadyReportsPendingVolumeOfScheduledPod(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}}, nil)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	// A volume binding on first consumer is pending until the pod is scheduled
+	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: pvcNameFor(workspace), Namespace: "team-a"}, pvc))
+	pvc.Status.Phase = corev1.ClaimPending
+	require.NoError(t, k8sClient.Status().Update(ctx, pvc))
+
+	reason, message := sm.diagnoseComputeNotReady(ctx, workspace)
+	assert.Equal(t, ReasonStorageNotBound, reason)
+	assert.Contains(t, message, pvc.Name)
+
+	pvc.Status.Phase = corev1.ClaimBound
+	require.NoError(t, k8sClient.Status().Update(ctx, pvc))
+	reason, _ = sm.diagnoseComputeNotReady(ctx, workspace)
+	assert.Empty(t, reason)
+}
+
+func TestDiagnoseComputeNotReadyReportsImagePull(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newPendingWorkspacePod(
+		[]corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue}},
+		[]corev1.ContainerStatus{{
+			Name:  "workspace",
+			Image: "registry.example.com/missing:latest",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image",
+			}},
+		}})
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	reason, message := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonImagePullFailed, reason)
+	assert.Contains(t, message, "registry.example.com/missing:latest")
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeImagePullFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, message, condition.Message, "the condition holds the kubelet message")
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+	require.NoError(t, k8sClient.Status().Update(context.Background(), pod))
+	reason, _ = sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Empty(t, reason)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeImagePullFailed))
+}
+
+func TestDiagnoseComputeNotReadyReportsMissingTemplateFirst(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "deleted"}
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{
+		Type:   corev1.PodScheduled,
+		Status: corev1.ConditionFalse,
+		Reason: corev1.PodReasonUnschedulable,
+	}}, nil)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	reason, message := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Equal(t, ReasonTemplateMissing, reason)
+	assert.Contains(t, message, `"deleted"`)
+}
+
+func TestDiagnoseComputeNotReadyWithoutObstacle(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	reason, message := sm.diagnoseComputeNotReady(context.Background(), workspace)
+	assert.Empty(t, reason)
+	assert.Empty(t, message)
+}
+
+func TestStatusWritesKeepBlockedReasonInSync(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	readiness := WorkspaceRunningReadiness{
+		serviceReady:           true,
+		computeNotReadyReason:  ReasonInsufficientCapacity,
+		computeNotReadyMessage: "0/3 nodes are available",
+	}
+	snapshot := stored.Status.DeepCopy()
+	require.NoError(t, sm.statusManager.UpdateStartingStatus(ctx, stored, readiness, snapshot))
+
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, BlockedReasonWaitingForCapacity, stored.Status.BlockedReason)
+	assert.Equal(t, "0/3 nodes are available", stored.Status.BlockedMessage)
+
+	snapshot = stored.Status.DeepCopy()
+	require.NoError(t, sm.statusManager.UpdateRunningStatus(ctx, stored, snapshot))
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Empty(t, stored.Status.BlockedReason)
+	assert.Empty(t, stored.Status.BlockedMessage)
+}
+
+func TestEphemeralStorageEvictionIsRecorded(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	evicted := newPendingWorkspacePod(nil, nil)
+	evicted.Name = "ws-pod-evicted"
+	evicted.Status.Phase = corev1.PodFailed
+	evicted.Status.Reason = "Evicted"
+	evicted.Status.Message = "Pod ephemeral local storage usage exceeds the total limit of containers 2Gi."
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, evicted, newPendingWorkspacePod(nil, nil))
+
+	sm.diagnoseComputeNotReady(context.Background(), workspace)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeEphemeralStorageEvicted)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonEphemeralStorageExceeded, condition.Reason)
+	assert.Contains(t, condition.Message, "ws-pod-evicted")
+	assert.Contains(t, condition.Message, "Request more ephemeral-storage")
+
+	clearEphemeralStorageEvictedCondition(workspace)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeEphemeralStorageEvicted))
+}
+
+func TestOtherEvictionsAreNotRecorded(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	evicted := newPendingWorkspacePod(nil, nil)
+	evicted.Status.Phase = corev1.PodFailed
+	evicted.Status.Reason = "Evicted"
+	evicted.Status.Message = "The node was low on resource: memory."
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, evicted)
+
+	sm.diagnoseComputeNotReady(context.Background(), workspace)
+
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeEphemeralStorageEvicted))
+}
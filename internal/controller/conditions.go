@@ -25,6 +25,105 @@ const (
 
 	// ConditionTypeStopped indicates if the Workspace is in a stopped state
 	ConditionTypeStopped = "Stopped"
+
+	// ConditionTypeReady rolls the phase of the Workspace up: True only while it runs, its reason is the phase
+	// otherwise, or Paused for a paused Workspace
+	ConditionTypeReady = "Ready"
+
+	// ConditionTypeReconciliationPaused indicates the controller has suspended all mutating actions for the Workspace
+	ConditionTypeReconciliationPaused = "ReconciliationPaused"
+
+	// ConditionTypeTerminating indicates the Workspace is being finalized; its reason records the current step
+	ConditionTypeTerminating = "Terminating"
+
+	// ConditionTypeQuotaExceeded indicates a namespace ResourceQuota prevents the Workspace resources from being created
+	ConditionTypeQuotaExceeded = "QuotaExceeded"
+
+	// ConditionTypeStale indicates the Workspace has not been used for longer than the stale workspace policy allows
+	ConditionTypeStale = "StaleWorkspace"
+
+	// ConditionTypeAwaitingApproval indicates the Workspace template requires start approval, which was not granted
+	ConditionTypeAwaitingApproval = "AwaitingApproval"
+
+	// ConditionTypeSecretRotationPending indicates a Secret exposed through envFrom changed after the Workspace pod started
+	ConditionTypeSecretRotationPending = "SecretRotationPending"
+
+	// ConditionTypeImageResolutionFailed indicates the registry could not resolve the image tag of a Workspace whose
+	// template pins images by digest; its message holds the registry error
+	ConditionTypeImageResolutionFailed = "ImageResolutionFailed"
+
+	// ConditionTypeStartupCheckPassed indicates whether the template startup check passed in the current Workspace pod
+	ConditionTypeStartupCheckPassed = "StartupCheckPassed"
+
+	// ConditionTypeStartupTimedOut indicates the server of the Workspace did not become ready within the startup
+	// timeout of its template once its container started
+	ConditionTypeStartupTimedOut = "StartupTimedOut"
+
+	// ConditionTypePostStartFailed indicates whether the template post-start script failed in the current Workspace pod
+	ConditionTypePostStartFailed = "PostStartFailed"
+
+	// ConditionTypeEphemeralStorageEvicted indicates a Workspace pod was evicted for its ephemeral-storage usage
+	// since the Workspace last started
+	ConditionTypeEphemeralStorageEvicted = "EphemeralStorageEvicted"
+
+	// ConditionTypeImagePullFailed indicates the kubelet cannot pull an image of the Workspace pod; its message
+	// holds the kubelet error
+	ConditionTypeImagePullFailed = "ImagePullFailed"
+
+	// ConditionTypeTemplateDrifted indicates the template of a Workspace pinning its template generation
+	// changed since the Workspace resolved it. It is informational.
+	ConditionTypeTemplateDrifted = "TemplateDrifted"
+
+	// ConditionTypeIdleShutdown indicates the Workspace was stopped by idle shutdown; its reason records the rule that applied
+	ConditionTypeIdleShutdown = "IdleShutdown"
+
+	// ConditionTypeTemplateMissing indicates the template label migration could not resolve the template the Workspace
+	// references. It is cleared once the template resolves.
+	ConditionTypeTemplateMissing = "TemplateMissing"
+
+	// ConditionTypeValidationFailed indicates a Workspace the admission webhooks do not cover failed the revalidation
+	// of the controller, which parks it until it passes
+	ConditionTypeValidationFailed = "ValidationFailed"
+
+	// ConditionTypePaused indicates the Workspace pod is stopped while its service, access resources and
+	// storage are kept for a quick resume
+	ConditionTypePaused = "Paused"
+
+	// ConditionTypeBootstrapFailed indicates an init container of the Workspace pod failed, or the init
+	// containers did not complete within the bootstrap timeout
+	ConditionTypeBootstrapFailed = "BootstrapFailed"
+
+	// ConditionTypeStorageResizing indicates the PVC of the Workspace is expanded to a larger storage size,
+	// until its filesystem resize completes
+	ConditionTypeStorageResizing = "StorageResizing"
+
+	// ConditionTypeStorageDegraded indicates the StorageClass the PVC of the Workspace was created with no
+	// longer exists: a bound volume keeps working but cannot be resized, an unbound one is never provisioned
+	ConditionTypeStorageDegraded = "StorageDegraded"
+
+	// ConditionTypeCloneInProgress indicates the home volume of a Workspace cloned from another one is being
+	// copied, or waits for its source
+	ConditionTypeCloneInProgress = "CloneInProgress"
+
+	// ConditionTypeCloneComplete indicates the home volume of a Workspace cloned from another one holds the
+	// copied data
+	ConditionTypeCloneComplete = "CloneComplete"
+
+	// ConditionTypeSharedVolumeMissing indicates a PVC of a shared volume of the Workspace template does not exist
+	// in the Workspace namespace; the Workspace does not start until it does
+	ConditionTypeSharedVolumeMissing = "SharedVolumeMissing"
+
+	// ConditionTypeHibernated indicates the home volume of a stopped Workspace is kept in a VolumeSnapshot
+	// and its PVC is deleted
+	ConditionTypeHibernated = "Hibernated"
+
+	// ConditionTypePreempted indicates the Workspace was stopped because the scheduler preempted its pod for
+	// pods of higher priority. It is cleared once the Workspace runs again.
+	ConditionTypePreempted = "Preempted"
+
+	// ConditionTypeExpiringSoon indicates the Workspace reaches, or reached, the maximum lifetime of its template,
+	// when the maxLifetimeAction of the template stops or deletes it
+	ConditionTypeExpiringSoon = "ExpiringSoon"
 )
 
 // Condition reasons for Workspace resources
@@ -36,22 +135,151 @@ const (
 	ReasonAccessNotReady      = "AccessNotReady"
 	ReasonResourcesReady      = "ResourcesReady"
 	ReasonDesiredStateStopped = "DesiredStateStopped"
+	ReasonDesiredStatePaused  = "DesiredStatePaused"
+
+	// ConditionTypeAvailable and ConditionTypeProgressing reasons explaining why the compute is not ready
+	ReasonTemplateMissing          = "TemplateMissing"
+	ReasonInsufficientCapacity     = "InsufficientCapacity"
+	ReasonInsufficientAccelerators = "InsufficientAccelerators"
+	ReasonStorageNotBound          = "StorageNotBound"
+	ReasonImagePullFailed          = "ImagePullFailed"
+	ReasonSharedVolumeMissing      = "SharedVolumeMissing"
 
 	// StoppedTypeCondition reasons and ConditionTypeProgressing reasons
 	ReasonResourcesNotStopped = "ResourcesNotStopped"
+	ReasonDrainingConnections = "DrainingConnections"
 	ReasonComputeNotStopped   = "ComputeNotStopped"
 	ReasonServiceNotStopped   = "ServiceNotStopped"
 	ReasonAccessNotStopped    = "AccessNotStopped"
 	ReasonResourcesStopped    = "AllResourcesStopped"
 	ReasonDesiredStateRunning = "DesiredStateRunning"
 
+	// ConditionTypePaused reasons; ReasonDrainingConnections and ReasonComputeNotStopped apply while pausing,
+	// ReasonDesiredStateRunning and ReasonDesiredStateStopped once the workspace leaves the pause
+	ReasonComputePaused = "ComputePaused"
+
+	// ConditionTypeReady reasons, besides the phases
+	ReasonPaused = "Paused"
+
 	// ConditionTypeDegraded reasons
-	ReasonDeploymentError = "ComputeError"
-	ReasonServiceError    = "ServiceError"
-	ReasonNoError         = "NoError"
+	ReasonDeploymentError      = "ComputeError"
+	ReasonServiceError         = "ServiceError"
+	ReasonResourceConflict     = "ResourceConflict"
+	ReasonEnvFromSourceMissing = "EnvFromSourceMissing"
+	ReasonNoError              = "NoError"
 
-	// ConditionTypeAvailable reasons (special cases)
+	// ConditionTypeAvailable and ConditionTypePreempted reasons (special cases)
 	ReasonPreempted = "Preempted"
+
+	// ConditionTypeReconciliationPaused reasons
+	ReasonPausedByAnnotation    = "PausedByAnnotation"
+	ReasonReconciliationResumed = "ReconciliationResumed"
+
+	// ConditionTypeTerminating reasons, one per finalization step in execution order
+	ReasonDeletingAccessResources = "DeletingAccessResources"
+	ReasonDeletingDeployment      = "DeletingDeployment"
+	ReasonDeletingService         = "DeletingService"
+	ReasonDeletingStorage         = "DeletingStorage"
+	ReasonRunningCleanupHooks     = "RunningCleanupHooks"
+	ReasonRemovingFinalizer       = "RemovingFinalizer"
+
+	// ConditionTypeQuotaExceeded reasons
+	ReasonQuotaExceeded = "QuotaExceeded"
+	ReasonWithinQuota   = "WithinQuota"
+
+	// ConditionTypeAvailable and ConditionTypeProgressing reasons while a workspace blocked by a quota
+	// is parked until the quota has room again
+	ReasonQuotaRecheckPending = "QuotaRecheckPending"
+
+	// ConditionTypeStale reasons
+	ReasonInactive          = "Inactive"
+	ReasonArchivalRequested = "ArchivalRequested"
+	ReasonInUse             = "InUse"
+	ReasonStaleExempt       = "StaleExempt"
+
+	// ConditionTypeAwaitingApproval reasons
+	ReasonApprovalRequired = "ApprovalRequired"
+	ReasonApprovalRevoked  = "ApprovalRevoked"
+	ReasonStartApproved    = "StartApproved"
+
+	// ConditionTypeSecretRotationPending reasons
+	ReasonEnvFromSecretChanged = "EnvFromSecretChanged"
+
+	// ConditionTypeImageResolutionFailed reasons
+	ReasonImageResolutionFailed = "ImageResolutionFailed"
+
+	// ConditionTypeExpiringSoon reasons
+	ReasonExpiringSoon       = "ExpiringSoon"
+	ReasonMaxLifetimeReached = "MaxLifetimeReached"
+
+	// ConditionTypeIdleShutdown reasons
+	ReasonIdle           = "Idle"
+	ReasonNeverConnected = "NeverConnected"
+
+	// Events of idle shutdown rules reached by workspaces the culler keeps running
+	ReasonIdleShutdownExempt = "IdleShutdownExempt"
+	ReasonIdleShutdownDryRun = "IdleShutdownDryRun"
+
+	// ConditionTypeEphemeralStorageEvicted reasons
+	ReasonEphemeralStorageExceeded = "EphemeralStorageExceeded"
+
+	// ConditionTypeImagePullFailed reasons use ReasonImagePullFailed
+
+	// ConditionTypeValidationFailed reasons
+	ReasonRevalidationFailed = "RevalidationFailed"
+	ReasonRevalidationPassed = "RevalidationPassed"
+
+	// ConditionTypeTemplateDrifted reasons
+	ReasonTemplateContentChanged = "TemplateContentChanged"
+
+	// ConditionTypeTemplateMissing reasons
+	ReasonTemplateNotFound = "TemplateNotFound"
+
+	// ConditionTypeStartupCheckPassed reasons
+	ReasonStartupCheckSucceeded   = "StartupCheckSucceeded"
+	ReasonStartupCheckFailed      = "StartupCheckFailed"
+	ReasonStartupCheckUnavailable = "StartupCheckUnavailable"
+
+	// ConditionTypeStartupTimedOut reasons
+	ReasonStartupTimedOut = "StartupTimedOut"
+	ReasonServerReady     = "ServerReady"
+
+	// ConditionTypePostStartFailed reasons
+	ReasonPostStartScriptSucceeded   = "PostStartScriptSucceeded"
+	ReasonPostStartScriptFailed      = "PostStartScriptFailed"
+	ReasonPostStartScriptUnavailable = "PostStartScriptUnavailable"
+
+	// ConditionTypeBootstrapFailed reasons; ReasonBootstrapTimedOut also marks the workspace Degraded
+	ReasonInitContainerFailed = "InitContainerFailed"
+	ReasonBootstrapTimedOut   = "BootstrapTimedOut"
+	ReasonBootstrapCompleted  = "BootstrapCompleted"
+	ReasonBootstrapRunning    = "BootstrapRunning"
+
+	// ConditionTypeStorageResizing reasons
+	ReasonVolumeResizing          = "VolumeResizing"
+	ReasonFileSystemResizePending = "FileSystemResizePending"
+	ReasonStorageResizeFailed     = "StorageResizeFailed"
+	ReasonStorageResized          = "StorageResized"
+	ReasonExpansionNotSupported   = "ExpansionNotSupported"
+
+	// ConditionTypeStorageDegraded reasons, and the events of the recreation of an unbound PVC
+	ReasonStorageClassMissing     = "StorageClassMissing"
+	ReasonStorageUnprovisionable  = "StorageUnprovisionable"
+	ReasonStorageClassAvailable   = "StorageClassAvailable"
+	ReasonStorageRecreated        = "StorageRecreated"
+	ReasonStorageRecreateRejected = "StorageRecreateRejected"
+
+	// ConditionTypeCloneInProgress and ConditionTypeCloneComplete reasons
+	ReasonCloneSourceUnavailable = "CloneSourceUnavailable"
+	ReasonCloneSnapshotting      = "CloneSnapshotting"
+	ReasonCloneProvisioning      = "CloneProvisioning"
+	ReasonCloneSucceeded         = "CloneSucceeded"
+
+	// ConditionTypeHibernated reasons; ReasonHibernated is also a ConditionTypeReady reason
+	ReasonHibernating       = "Hibernating"
+	ReasonHibernated        = "Hibernated"
+	ReasonHibernationFailed = "HibernationFailed"
+	ReasonRestoringSnapshot = "RestoringSnapshot"
 )
 
 // NewCondition creates a new condition with the specified status
@@ -11,6 +11,7 @@ import (
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -92,3 +93,155 @@ func MergeConditionsIfChanged(
 		return conditionsToUpdate
 	}
 }
+
+// Workspace phases derived from conditions, used for aggregated reporting
+const (
+	WorkspacePhaseRunning  = "Running"
+	WorkspacePhasePending  = "Pending"
+	WorkspacePhaseStopping = "Stopping"
+	WorkspacePhaseStopped  = "Stopped"
+	WorkspacePhaseUnknown  = "Unknown"
+)
+
+// GetWorkspacePhase derives a single phase from the workspace conditions
+func GetWorkspacePhase(workspace *workspacev1alpha1.Workspace) string {
+	isTrue := func(conditionType string) bool {
+		condition := FindCondition(&workspace.Status.Conditions, conditionType)
+		return condition != nil && condition.Status == metav1.ConditionTrue
+	}
+
+	switch {
+	case isTrue(ConditionTypeAvailable):
+		return WorkspacePhaseRunning
+	case isTrue(ConditionTypeStopped) && workspace.Spec.DesiredStatus == DesiredStateHibernated &&
+		!isTrue(ConditionTypeHibernated):
+		// The home volume of a hibernating workspace is still being snapshotted
+		return WorkspacePhaseStopping
+	case isTrue(ConditionTypeStopped), isTrue(ConditionTypePaused):
+		// A paused workspace has no compute running either
+		return WorkspacePhaseStopped
+	case isTrue(ConditionTypeProgressing) && isStopDesiredStatus(workspace.Spec.DesiredStatus):
+		return WorkspacePhaseStopping
+	case isTrue(ConditionTypeProgressing):
+		return WorkspacePhasePending
+	default:
+		return WorkspacePhaseUnknown
+	}
+}
+
+// isStopDesiredStatus returns true for the desired statuses releasing the compute of the workspace
+func isStopDesiredStatus(desiredStatus string) bool {
+	return desiredStatus == DesiredStateStopped || desiredStatus == DesiredStatePaused ||
+		desiredStatus == DesiredStateHibernated
+}
+
+// readyMessages describe the phases in the Ready condition
+var readyMessages = map[string]string{
+	WorkspacePhaseRunning:  "Workspace is running",
+	WorkspacePhasePending:  "Workspace is starting",
+	WorkspacePhaseStopping: "Workspace is stopping",
+	WorkspacePhaseStopped:  "Workspace is stopped",
+	WorkspacePhaseUnknown:  "Workspace status is unknown",
+}
+
+// setPhase records the phase of the workspace in its status and in its Ready condition, which only
+// changes its transition time when it becomes ready or stops being ready
+func setPhase(workspace *workspacev1alpha1.Workspace) {
+	phase := GetWorkspacePhase(workspace)
+	workspace.Status.Phase = phase
+
+	status, reason, message := metav1.ConditionFalse, phase, readyMessages[phase]
+	if phase == WorkspacePhaseRunning {
+		status = metav1.ConditionTrue
+	}
+	if paused := FindCondition(&workspace.Status.Conditions, ConditionTypePaused); phase == WorkspacePhaseStopped &&
+		paused != nil && paused.Status == metav1.ConditionTrue {
+		reason, message = ReasonPaused, "Workspace is paused"
+	}
+	if phase == WorkspacePhaseStopped && apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeHibernated) {
+		reason, message = ReasonHibernated, "Workspace is hibernated"
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(ConditionTypeReady, status, reason, message))
+}
+
+// Blocked reasons explaining why a workspace meant to run is not running, in priority order
+const (
+	BlockedReasonValidationFailed    = "ValidationFailed"
+	BlockedReasonTemplateMissing     = "TemplateMissing"
+	BlockedReasonQuotaExceeded       = "QuotaExceeded"
+	BlockedReasonWaitingForCapacity  = "WaitingForCapacity"
+	BlockedReasonStorageProvisioning = "StorageProvisioning"
+	BlockedReasonImagePull           = "ImagePull"
+	BlockedReasonInitializing        = "Initializing"
+	BlockedReasonUnknown             = "Unknown"
+)
+
+// progressingReasonsToBlockedReasons maps the Progressing reasons the controller reports while the
+// compute is not ready to the blocked reason they roll up to, after TemplateMissing and QuotaExceeded
+var progressingReasonsToBlockedReasons = []struct {
+	conditionReason string
+	blockedReason   string
+}{
+	{ReasonInsufficientCapacity, BlockedReasonWaitingForCapacity},
+	{ReasonInsufficientAccelerators, BlockedReasonWaitingForCapacity},
+	{ReasonStorageNotBound, BlockedReasonStorageProvisioning},
+	{ReasonSharedVolumeMissing, BlockedReasonStorageProvisioning},
+	{ReasonImagePullFailed, BlockedReasonImagePull},
+	{ReasonImageResolutionFailed, BlockedReasonImagePull},
+}
+
+// GetWorkspaceBlockedReason derives from the workspace conditions a single reason, with its message,
+// explaining why a workspace meant to run is not running. It returns empty strings when the workspace
+// is running, or stopped, paused or on its way there as desired.
+// A workspace parked by a failed revalidation is ValidationFailed. A QuotaExceeded condition rolls up to
+// QuotaExceeded; otherwise the reason of the Progressing condition
+// tells what the workspace waits for, and a workspace progressing without a known obstacle is Initializing.
+// Errors reported by the Degraded condition roll up to Unknown, with the error as message.
+func GetWorkspaceBlockedReason(workspace *workspacev1alpha1.Workspace) (string, string) {
+	desiredStatus := workspace.Spec.DesiredStatus
+	if workspace.Status.DesiredStatusIntent != nil {
+		desiredStatus = workspace.Status.DesiredStatusIntent.DesiredStatus
+	}
+	switch GetWorkspacePhase(workspace) {
+	case WorkspacePhaseRunning, WorkspacePhaseStopped, WorkspacePhaseStopping:
+		return "", ""
+	}
+	if isStopDesiredStatus(desiredStatus) {
+		return "", ""
+	}
+
+	conditionIfTrue := func(conditionType string) *metav1.Condition {
+		condition := FindCondition(&workspace.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			return nil
+		}
+		return condition
+	}
+	progressing := conditionIfTrue(ConditionTypeProgressing)
+	degraded := conditionIfTrue(ConditionTypeDegraded)
+	quota := conditionIfTrue(ConditionTypeQuotaExceeded)
+
+	if validation := conditionIfTrue(ConditionTypeValidationFailed); validation != nil {
+		return BlockedReasonValidationFailed, validation.Message
+	}
+	if progressing != nil && progressing.Reason == ReasonTemplateMissing {
+		return BlockedReasonTemplateMissing, progressing.Message
+	}
+	if quota != nil {
+		return BlockedReasonQuotaExceeded, quota.Message
+	}
+	if progressing != nil {
+		for _, mapping := range progressingReasonsToBlockedReasons {
+			if progressing.Reason == mapping.conditionReason {
+				return mapping.blockedReason, progressing.Message
+			}
+		}
+		if degraded == nil {
+			return BlockedReasonInitializing, progressing.Message
+		}
+	}
+	if degraded != nil {
+		return BlockedReasonUnknown, degraded.Message
+	}
+	return BlockedReasonUnknown, "Workspace status is not reported yet"
+}
@@ -8,9 +8,11 @@ package controller
 import (
 	"context"
 	"testing"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -93,3 +95,294 @@ func TestMergeConditionsIfChanged(t *testing.T) {
 	result = MergeConditionsIfChanged(ctx, workspace, &unchangedConditions)
 	assert.Empty(t, result, "Should return empty slice when no changes")
 }
+
+func TestGetWorkspacePhase(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+
+	tests := []struct {
+		name          string
+		desiredStatus string
+		conditions    []metav1.Condition
+		expected      string
+	}{
+		{
+			name:     "no conditions",
+			expected: WorkspacePhaseUnknown,
+		},
+		{
+			name: "available",
+			conditions: []metav1.Condition{
+				condition(ConditionTypeAvailable, metav1.ConditionTrue),
+				condition(ConditionTypeProgressing, metav1.ConditionFalse),
+			},
+			expected: WorkspacePhaseRunning,
+		},
+		{
+			name:          "starting",
+			desiredStatus: DesiredStateRunning,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeAvailable, metav1.ConditionFalse),
+				condition(ConditionTypeProgressing, metav1.ConditionTrue),
+			},
+			expected: WorkspacePhasePending,
+		},
+		{
+			name:          "stopping",
+			desiredStatus: DesiredStateStopped,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeProgressing, metav1.ConditionTrue),
+				condition(ConditionTypeStopped, metav1.ConditionFalse),
+			},
+			expected: WorkspacePhaseStopping,
+		},
+		{
+			name:          "stopped",
+			desiredStatus: DesiredStateStopped,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeProgressing, metav1.ConditionFalse),
+				condition(ConditionTypeStopped, metav1.ConditionTrue),
+			},
+			expected: WorkspacePhaseStopped,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &workspacev1alpha1.Workspace{
+				Spec:   workspacev1alpha1.WorkspaceSpec{DesiredStatus: tt.desiredStatus},
+				Status: workspacev1alpha1.WorkspaceStatus{Conditions: tt.conditions},
+			}
+			assert.Equal(t, tt.expected, GetWorkspacePhase(ws))
+		})
+	}
+}
+
+func TestSetPhaseRollsThePhaseUpIntoTheReadyCondition(t *testing.T) {
+	ws := &workspacev1alpha1.Workspace{}
+	// setConditions replaces the conditions the phase is derived from, keeping the Ready condition
+	setConditions := func(conditions ...metav1.Condition) *metav1.Condition {
+		if ready := FindCondition(&ws.Status.Conditions, ConditionTypeReady); ready != nil {
+			conditions = append(conditions, *ready)
+		}
+		ws.Status.Conditions = conditions
+		setPhase(ws)
+		return FindCondition(&ws.Status.Conditions, ConditionTypeReady)
+	}
+	condition := func(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status}
+	}
+
+	ready := setConditions(condition(ConditionTypeAvailable, metav1.ConditionFalse), condition(ConditionTypeStopped, metav1.ConditionTrue))
+	assert.Equal(t, WorkspacePhaseStopped, ws.Status.Phase)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, WorkspacePhaseStopped, ready.Reason)
+
+	// A starting workspace is not ready since it stopped
+	stoppedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	ready.LastTransitionTime = stoppedAt
+	ws.Spec.DesiredStatus = DesiredStateRunning
+	ready = setConditions(condition(ConditionTypeAvailable, metav1.ConditionFalse), condition(ConditionTypeProgressing, metav1.ConditionTrue))
+	assert.Equal(t, WorkspacePhasePending, ready.Reason)
+	assert.Equal(t, stoppedAt, ready.LastTransitionTime)
+
+	ready = setConditions(condition(ConditionTypeAvailable, metav1.ConditionTrue))
+	assert.Equal(t, WorkspacePhaseRunning, ws.Status.Phase)
+	assert.Equal(t, metav1.ConditionTrue, ready.Status)
+	assert.NotEqual(t, stoppedAt, ready.LastTransitionTime)
+
+	ws.Spec.DesiredStatus = DesiredStatePaused
+	ready = setConditions(condition(ConditionTypeAvailable, metav1.ConditionFalse), condition(ConditionTypePaused, metav1.ConditionTrue))
+	assert.Equal(t, WorkspacePhaseStopped, ws.Status.Phase)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, ReasonPaused, ready.Reason)
+}
+
+func TestGetWorkspaceBlockedReason(t *testing.T) {
+	condition := func(conditionType string, status metav1.ConditionStatus, reason string) metav1.Condition {
+		return metav1.Condition{Type: conditionType, Status: status, Reason: reason, Message: reason + " message"}
+	}
+	starting := func(reason string) []metav1.Condition {
+		return []metav1.Condition{
+			condition(ConditionTypeAvailable, metav1.ConditionFalse, reason),
+			condition(ConditionTypeProgressing, metav1.ConditionTrue, reason),
+			condition(ConditionTypeDegraded, metav1.ConditionFalse, ReasonNoError),
+			condition(ConditionTypeStopped, metav1.ConditionFalse, ReasonDesiredStateRunning),
+		}
+	}
+
+	tests := []struct {
+		name            string
+		desiredStatus   string
+		intentStatus    string
+		conditions      []metav1.Condition
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "running",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     []metav1.Condition{condition(ConditionTypeAvailable, metav1.ConditionTrue, ReasonResourcesReady)},
+			expectedReason: "",
+		},
+		{
+			name:          "stopped",
+			desiredStatus: DesiredStateStopped,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeProgressing, metav1.ConditionFalse, ReasonResourcesStopped),
+				condition(ConditionTypeStopped, metav1.ConditionTrue, ReasonResourcesStopped),
+			},
+			expectedReason: "",
+		},
+		{
+			name:          "stopping",
+			desiredStatus: DesiredStateStopped,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeProgressing, metav1.ConditionTrue, ReasonComputeNotStopped),
+				condition(ConditionTypeStopped, metav1.ConditionFalse, ReasonComputeNotStopped),
+			},
+			expectedReason: "",
+		},
+		{
+			name:           "stopping by a winning intent",
+			desiredStatus:  DesiredStateRunning,
+			intentStatus:   DesiredStateStopped,
+			conditions:     starting(ReasonComputeNotStopped),
+			expectedReason: "",
+		},
+		{
+			name:            "no conditions",
+			desiredStatus:   DesiredStateRunning,
+			expectedReason:  BlockedReasonUnknown,
+			expectedMessage: "Workspace status is not reported yet",
+		},
+		{
+			name:            "resources not ready",
+			desiredStatus:   DesiredStateRunning,
+			conditions:      starting(ReasonResourcesNotReady),
+			expectedReason:  BlockedReasonInitializing,
+			expectedMessage: "ResourcesNotReady message",
+		},
+		{
+			name:           "compute not ready",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     starting(ReasonComputeNotReady),
+			expectedReason: BlockedReasonInitializing,
+		},
+		{
+			name:           "service not ready",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     starting(ReasonServiceNotReady),
+			expectedReason: BlockedReasonInitializing,
+		},
+		{
+			name:           "access not ready",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     starting(ReasonAccessNotReady),
+			expectedReason: BlockedReasonInitializing,
+		},
+		{
+			name:            "template missing",
+			desiredStatus:   DesiredStateRunning,
+			conditions:      starting(ReasonTemplateMissing),
+			expectedReason:  BlockedReasonTemplateMissing,
+			expectedMessage: "TemplateMissing message",
+		},
+		{
+			name:          "quota exceeded",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonQuotaRecheckPending),
+				condition(ConditionTypeQuotaExceeded, metav1.ConditionTrue, ReasonQuotaExceeded)),
+			expectedReason:  BlockedReasonQuotaExceeded,
+			expectedMessage: "QuotaExceeded message",
+		},
+		{
+			name:          "quota cleared",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonComputeNotReady),
+				condition(ConditionTypeQuotaExceeded, metav1.ConditionFalse, ReasonWithinQuota)),
+			expectedReason: BlockedReasonInitializing,
+		},
+		{
+			name:          "template missing takes precedence over quota",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonTemplateMissing),
+				condition(ConditionTypeQuotaExceeded, metav1.ConditionTrue, ReasonQuotaExceeded)),
+			expectedReason: BlockedReasonTemplateMissing,
+		},
+		{
+			name:          "quota takes precedence over capacity",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonInsufficientCapacity),
+				condition(ConditionTypeQuotaExceeded, metav1.ConditionTrue, ReasonQuotaExceeded)),
+			expectedReason: BlockedReasonQuotaExceeded,
+		},
+		{
+			name:            "waiting for capacity",
+			desiredStatus:   DesiredStateRunning,
+			conditions:      starting(ReasonInsufficientCapacity),
+			expectedReason:  BlockedReasonWaitingForCapacity,
+			expectedMessage: "InsufficientCapacity message",
+		},
+		{
+			name:           "storage provisioning",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     starting(ReasonStorageNotBound),
+			expectedReason: BlockedReasonStorageProvisioning,
+		},
+		{
+			name:           "image pull",
+			desiredStatus:  DesiredStateRunning,
+			conditions:     starting(ReasonImagePullFailed),
+			expectedReason: BlockedReasonImagePull,
+		},
+		{
+			name:          "known obstacle reported with an error",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonImagePullFailed)[:2],
+				condition(ConditionTypeDegraded, metav1.ConditionTrue, ReasonDeploymentError)),
+			expectedReason: BlockedReasonImagePull,
+		},
+		{
+			name:          "progressing with an error",
+			desiredStatus: DesiredStateRunning,
+			conditions: append(starting(ReasonComputeNotReady)[:2],
+				condition(ConditionTypeDegraded, metav1.ConditionTrue, ReasonEnvFromSourceMissing)),
+			expectedReason:  BlockedReasonUnknown,
+			expectedMessage: "EnvFromSourceMissing message",
+		},
+		{
+			name:          "startup check failed",
+			desiredStatus: DesiredStateRunning,
+			conditions: []metav1.Condition{
+				condition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonStartupCheckFailed),
+				condition(ConditionTypeProgressing, metav1.ConditionFalse, ReasonStartupCheckFailed),
+				condition(ConditionTypeDegraded, metav1.ConditionTrue, ReasonStartupCheckFailed),
+			},
+			expectedReason:  BlockedReasonUnknown,
+			expectedMessage: "StartupCheckFailed message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := &workspacev1alpha1.Workspace{
+				Spec:   workspacev1alpha1.WorkspaceSpec{DesiredStatus: tt.desiredStatus},
+				Status: workspacev1alpha1.WorkspaceStatus{Conditions: tt.conditions},
+			}
+			if tt.intentStatus != "" {
+				ws.Status.DesiredStatusIntent = &workspacev1alpha1.DesiredStatusIntent{DesiredStatus: tt.intentStatus}
+			}
+			reason, message := GetWorkspaceBlockedReason(ws)
+			assert.Equal(t, tt.expectedReason, reason)
+			if tt.expectedMessage != "" {
+				assert.Equal(t, tt.expectedMessage, message)
+			}
+			if reason == "" {
+				assert.Empty(t, message)
+			}
+		})
+	}
+}
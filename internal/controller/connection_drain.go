@@ -0,0 +1,92 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// isServiceDraining returns true if the service selector was changed to stop matching the workspace pods
+func isServiceDraining(service *corev1.Service) bool {
+	_, ok := service.Spec.Selector[LabelConnectionsDraining]
+	return ok
+}
+
+// EnsureServiceDraining changes the selector of the workspace service so that it no longer matches the
+// workspace pods: the access resources keep routing to the service, which stops sending new connections
+// to the pods. Returns false if there is no service to drain.
+func (rm *ResourceManager) EnsureServiceDraining(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	service, err := rm.getService(ctx, workspace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get service: %w", err)
+	}
+	if rm.IsServiceMissingOrDeleting(service) {
+		return false, nil
+	}
+	if isServiceDraining(service) {
+		return true, nil
+	}
+
+	logf.FromContext(ctx).Info("Draining Service", "service", service.Name, "namespace", service.Namespace)
+	selector := maps.Clone(service.Spec.Selector)
+	if selector == nil {
+		selector = map[string]string{}
+	}
+	selector[LabelConnectionsDraining] = "true"
+	service.Spec.Selector = selector
+	if err := rm.client.Update(ctx, service); err != nil {
+		return false, fmt.Errorf("failed to drain service: %w", err)
+	}
+	return true, nil
+}
+
+// connectionDrainRemaining drains the workspace service and returns how much longer in-flight requests
+// are given before the workspace pod stops. The drain starts when drainCondition takes the reason
+// ReasonDrainingConnections, so that it resumes across controller restarts; it is over once the
+// deployment deletion was issued.
+func (sm *StateMachine) connectionDrainRemaining(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	drainCondition *metav1.Condition,
+	now time.Time) (time.Duration, error) {
+	if sm.drainPeriod <= 0 {
+		return 0, nil
+	}
+
+	deployment, err := sm.resourceManager.getDeployment(ctx, workspace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	if sm.resourceManager.IsDeploymentMissingOrDeleting(deployment) {
+		return 0, nil
+	}
+
+	draining, err := sm.resourceManager.EnsureServiceDraining(ctx, workspace)
+	if err != nil || !draining {
+		return 0, err
+	}
+
+	startedAt := now
+	if drainCondition != nil && drainCondition.Reason == ReasonDrainingConnections {
+		startedAt = drainCondition.LastTransitionTime.Time
+	}
+	return max(startedAt.Add(sm.drainPeriod).Sub(now), 0), nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newDrainTestStateMachine returns a state machine for a running workspace being stopped,
+// with its deployment and service
+func newDrainTestStateMachine(t *testing.T) (*StateMachine, client.Client, *workspacev1alpha1.Workspace) {
+	workspace := newRolloutTestWorkspace()
+	workspace.Spec.DesiredStatus = DesiredStateStopped
+	workspace.Status.AccessURL = "https://example.com/workspaces/team-a/ws/"
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.drainPeriod = time.Minute
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Create(context.Background(), deployment))
+	service, err := sm.resourceManager.serviceBuilder.BuildService(workspace)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Create(context.Background(), service))
+	return sm, k8sClient, workspace
+}
+
+// reconcileDrainTestStop runs one stop pass on the stored workspace and returns it
+func reconcileDrainTestStop(t *testing.T, sm *StateMachine, k8sClient client.Client) (*workspacev1alpha1.Workspace, time.Duration) {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "ws", Namespace: "team-a"}, workspace))
+	result, err := sm.reconcileDesiredStoppedStatus(context.Background(), workspace, workspace.Status.DeepCopy())
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), workspace))
+	return workspace, result.RequeueAfter
+}
+
+func stoppedConditionReason(workspace *workspacev1alpha1.Workspace) string {
+	if condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStopped); condition != nil {
+		return condition.Reason
+	}
+	return ""
+}
+
+func TestStopDrainsConnectionsBeforeStoppingCompute(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+
+	workspace, requeueAfter := reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonDrainingConnections, stoppedConditionReason(workspace))
+	assert.InDelta(t, time.Minute.Seconds(), requeueAfter.Seconds(), 5)
+
+	service := &corev1.Service{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateServiceName("ws"), Namespace: "team-a"}, service))
+	assert.Equal(t, "true", service.Spec.Selector[LabelConnectionsDraining])
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateDeploymentName("ws"), Namespace: "team-a"}, &appsv1.Deployment{}))
+	assert.NotEmpty(t, workspace.Status.AccessURL)
+
+	// A pass during the drain period keeps waiting from when the drain started
+	workspace, requeueAfter = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonDrainingConnections, stoppedConditionReason(workspace))
+	assert.LessOrEqual(t, requeueAfter, time.Minute)
+}
+
+func TestStopTearsDownInOrderOnceDrained(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+	reconcileDrainTestStop(t, sm, k8sClient)
+
+	// The drain period elapsed, possibly under another controller instance
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "ws", Namespace: "team-a"}, workspace))
+	FindCondition(&workspace.Status.Conditions, ConditionTypeStopped).LastTransitionTime =
+		metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	require.NoError(t, k8sClient.Status().Update(ctx, workspace))
+
+	// The pod stops while the access URL and the service remain
+	workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonComputeNotStopped, stoppedConditionReason(workspace))
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: GenerateDeploymentName("ws"), Namespace: "team-a"}, &appsv1.Deployment{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.NotEmpty(t, workspace.Status.AccessURL)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateServiceName("ws"), Namespace: "team-a"}, &corev1.Service{}))
+
+	// Then the access URL and the service are removed
+	workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonServiceNotStopped, stoppedConditionReason(workspace))
+	assert.Empty(t, workspace.Status.AccessURL)
+
+	workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonResourcesStopped, stoppedConditionReason(workspace))
+}
+
+func TestStopSkipsDrainWithoutDrainPeriod(t *testing.T) {
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+
+	workspace, _ := reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonComputeNotStopped, stoppedConditionReason(workspace))
+}
+
+func TestRunningWorkspaceRestoresDrainingService(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newDrainTestStateMachine(t)
+	reconcileDrainTestStop(t, sm, k8sClient)
+
+	// The workspace is set back to Running before the drain completed
+	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, GenerateLabels("ws"), service.Spec.Selector)
+}
@@ -6,9 +6,9 @@ Distributed under the terms of the MIT license
 package controller
 
 import (
-	"fmt"
 	"time"
 
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
@@ -17,12 +17,30 @@ const (
 	DefaultCPURequest = "100m"
 	// DefaultMemoryRequest is the default memory request for workspace containers
 	DefaultMemoryRequest = "128Mi"
+	// DefaultEphemeralStorageRequest is the ephemeral-storage request new workspaces are admitted with,
+	// reserving room on the node for pip caches and temporary files
+	DefaultEphemeralStorageRequest = "1Gi"
 
 	// JupyterPort is the default port for Jupyter server
 	JupyterPort = 8888
+	// BaseURLEnv is the environment variable access strategies provide the base URL of the workspace in
+	BaseURLEnv = "JUPYTER_BASE_URL"
+	// ServerTokenEnv is the environment variable the server token is set in when the server adapter
+	// delivers it as an argument only
+	ServerTokenEnv = "WORKSPACE_SERVER_TOKEN"
+	// ServerTokenVolumeName is the name of the volume mounting the server token file
+	ServerTokenVolumeName = "workspace-server-token"
 
 	// DefaultMountPath is the default mount path for workspace storage
 	DefaultMountPath = "/home/jovyan"
+	// RootDirEnv is the environment variable the home directory of the workspace is set in, for the server to
+	// open in it (e.g. --ServerApp.root_dir of Jupyter)
+	RootDirEnv = "JUPYTER_ROOT_DIR"
+
+	// TmpVolumeName is the name of the emptyDir volume mounted at TmpMountPath
+	TmpVolumeName = "workspace-tmp"
+	// TmpMountPath is where the tmpVolume of a workspace is mounted
+	TmpMountPath = "/tmp"
 
 	// AppLabel is the label key for application identification
 	AppLabel = "app"
@@ -42,14 +60,100 @@ const (
 
 	// LabelComponent is the label key for component identification
 	LabelComponent = "workspace.jupyter.org/component"
+	// LabelRetain is the label key an owner sets to "true" to keep a workspace spec.retention would delete
+	LabelRetain = "workspace.jupyter.org/retain"
+	// LabelConnectionsDraining is added to the selector of a workspace service being drained, so that
+	// it no longer matches the workspace pods. Pods never carry this label.
+	LabelConnectionsDraining = "workspace.jupyter.org/connections-draining"
 
 	// AppLabelValue is the label value for app label
 	AppLabelValue = "jupyter"
 
 	// AnnotationCreatedBy is the annotation key for tracking resource creator
 	AnnotationCreatedBy = "workspace.jupyter.org/created-by"
+	// AnnotationOnBehalfOf is the annotation key a trusted creator sets to create a workspace for another user
+	AnnotationOnBehalfOf = "workspace.jupyter.org/on-behalf-of"
+	// AnnotationCreatedByDelegate is the annotation key recording the trusted creator that created the workspace
+	AnnotationCreatedByDelegate = "workspace.jupyter.org/created-by-delegate"
 	// AnnotationLastUpdatedBy is the annotation key for tracking last updater
 	AnnotationLastUpdatedBy = "workspace.jupyter.org/last-updated-by"
+	// AnnotationDesiredStatusSetAt is the annotation key recording when a user last set spec.desiredStatus
+	AnnotationDesiredStatusSetAt = "workspace.jupyter.org/desired-status-set-at"
+	// AnnotationMaintenanceIntent is the annotation key for an administrator maintenance desired status intent
+	AnnotationMaintenanceIntent = "workspace.jupyter.org/maintenance-intent"
+	// AnnotationScheduleIntent is the annotation key for a scheduled desired status intent
+	AnnotationScheduleIntent = "workspace.jupyter.org/schedule-intent"
+	// AnnotationCullerIntent is the annotation key for the idle culler desired status intent
+	AnnotationCullerIntent = "workspace.jupyter.org/culler-intent"
+	// AnnotationPaused is the annotation key that suspends reconciliation of a workspace when set to "true"
+	AnnotationPaused = "workspace.jupyter.org/paused"
+	// AnnotationManagedByVersion is the annotation key recording the operator version that created a child resource
+	AnnotationManagedByVersion = "workspace.jupyter.org/managed-by-version"
+	// AnnotationPodTemplateHash is the deployment annotation key recording the fingerprint of its pod template
+	AnnotationPodTemplateHash = "workspace.jupyter.org/pod-template-hash"
+	// AnnotationRenderedGeneration is the deployment annotation key recording the workspace generation its pod template was rendered from
+	AnnotationRenderedGeneration = "workspace.jupyter.org/rendered-generation"
+	// AnnotationPendingPodTemplateHash is the deployment annotation key recording a pod template rollout
+	// deferred until the next restart of the workspace or a maintenance window
+	AnnotationPendingPodTemplateHash = "workspace.jupyter.org/pending-pod-template-hash"
+	// AnnotationEnvFromSource is the annotation key recording the namespace/name an envFrom copy is synced from
+	AnnotationEnvFromSource = "workspace.jupyter.org/envfrom-source"
+	// AnnotationEnvFromSecretsChecksum is the pod template annotation key recording the checksum of the
+	// envFrom Secret data the workspace pod was started with
+	AnnotationEnvFromSecretsChecksum = "workspace.jupyter.org/envfrom-secrets-checksum"
+	// AnnotationSecretRotationRequested is the annotation key an owner sets, e.g. to a timestamp, to restart
+	// the workspace pod with the current values of its envFrom Secrets; each new value requests a restart
+	AnnotationSecretRotationRequested = "workspace.jupyter.org/secret-rotation-requested"
+	// AnnotationMaxLifetimeExempt is the annotation key an administrator sets to "true" to exempt a workspace
+	// from the maximum lifetime of its template
+	AnnotationMaxLifetimeExempt = "workspace.jupyter.org/max-lifetime-exempt"
+	// AnnotationRestartedAt is the pod template annotation key recording the spec.restartRequestedAt the
+	// workspace pod was started for
+	AnnotationRestartedAt = "workspace.jupyter.org/restarted-at"
+	// AnnotationApplyResourceRecommendations is the annotation key an owner sets to apply status.recommendations
+	// to the resources of the workspace; the controller removes it once handled
+	AnnotationApplyResourceRecommendations = "workspace.jupyter.org/apply-resource-recommendations"
+	// AnnotationRecreateUnboundStorage is the annotation key an owner sets to recreate the unbound PVC of a
+	// workspace whose StorageClass no longer exists under the default StorageClass of the namespace; the
+	// controller removes it once handled
+	AnnotationRecreateUnboundStorage = "workspace.jupyter.org/recreate-unbound-storage"
+	// AnnotationResetToDefaults is the annotation key an owner sets to "true" to reset the template-managed
+	// fields of the workspace to the defaults of its template; the controller removes it once handled
+	AnnotationResetToDefaults = "workspace.jupyter.org/reset-to-defaults"
+	// AnnotationSkipCleanupHooks is the annotation key an administrator sets to a comma separated list of
+	// cleanup hooks that a deleted workspace stops waiting for
+	AnnotationSkipCleanupHooks = "workspace.jupyter.org/skip-cleanup-hooks"
+	// AnnotationStaleExempt is the annotation key an administrator sets to "true" to exempt a workspace from stale workspace archival
+	AnnotationStaleExempt = "workspace.jupyter.org/stale-exempt"
+	// AnnotationArchiveRequested is the annotation key recording when the controller requested archival of a stale workspace
+	AnnotationArchiveRequested = "workspace.jupyter.org/archive-requested"
+	// AnnotationStartApprovalRequired is the annotation key set to "true" by the webhook on workspaces
+	// whose template requires start approval; users other than administrators cannot change it
+	AnnotationStartApprovalRequired = "workspace.jupyter.org/start-approval-required"
+	// AnnotationStartApprovedBy is the annotation key recording the approver of a workspace start;
+	// the webhook replaces the value with the identity of the user setting it
+	AnnotationStartApprovedBy = "workspace.jupyter.org/start-approved-by"
+	// AnnotationTemplateGeneration is the annotation key recording the generation of the template the webhook
+	// admitted the workspace against; the controller resolves the template at that revision
+	AnnotationTemplateGeneration = workspaceutil.AnnotationTemplateGeneration
+	// AnnotationStaleAfterDays is the namespace annotation key overriding the days of inactivity after which
+	// workspaces are flagged stale; "0" disables the stale workspace policy for the namespace
+	AnnotationStaleAfterDays = "workspace.jupyter.org/stale-after-days"
+	// AnnotationStaleGraceDays is the namespace annotation key overriding the days between flagging a
+	// workspace stale and requesting its archival
+	AnnotationStaleGraceDays = "workspace.jupyter.org/stale-grace-days"
+	// AnnotationChildNamePrefix is the namespace annotation key overriding the prefix of the names of the
+	// resources generated for new workspaces of the namespace
+	AnnotationChildNamePrefix = "workspace.jupyter.org/child-name-prefix"
+	// AnnotationDefaultStorageClass is the namespace annotation key naming the StorageClass unbound PVCs of the
+	// namespace are recreated under, the cluster default StorageClass when omitted
+	AnnotationDefaultStorageClass = "workspace.jupyter.org/default-storage-class"
+	// AnnotationConfirmDelete is the template annotation key an administrator sets to the number of workspaces
+	// using the template, to confirm deleting it
+	AnnotationConfirmDelete = "workspace.jupyter.org/confirm-delete"
+	// AnnotationLogLevel is the workspace annotation key raising the log verbosity of the controller for the
+	// workspace alone, for a bounded duration; the only level is LogLevelDebug
+	AnnotationLogLevel = "workspace.jupyter.org/log-level"
 	// AnnotationServiceAccountUsers is the annotation key for service account users
 	AnnotationServiceAccountUsers = "workspace.jupyter.org/service-account-users"
 	// AnnotationServiceAccountUserPatterns is the annotation key for service account user patterns
@@ -61,6 +165,10 @@ const (
 	DesiredStateRunning = "Running"
 	// DesiredStateStopped indicates the workspace is stopped
 	DesiredStateStopped = "Stopped"
+	// DesiredStatePaused indicates the workspace pod is stopped, its service, access resources and storage are kept
+	DesiredStatePaused = "Paused"
+	// DesiredStateHibernated indicates the workspace is stopped and its home volume is kept in a VolumeSnapshot
+	DesiredStateHibernated = "Hibernated"
 
 	// PreemptedReason is the reason for preempted workspaces
 	PreemptedReason = "Workspace preempted due to resource contention"
@@ -70,6 +178,12 @@ const (
 
 	// KindPod represents the Pod resource kind
 	KindPod = "Pod"
+	// KindDeployment represents the Deployment resource kind
+	KindDeployment = "Deployment"
+	// KindService represents the Service resource kind
+	KindService = "Service"
+	// KindPersistentVolumeClaim represents the PersistentVolumeClaim resource kind
+	KindPersistentVolumeClaim = "PersistentVolumeClaim"
 
 	// MessageCreating is the status message for creating workspaces
 	MessageCreating = "Jupyter server is starting"
@@ -87,10 +201,61 @@ const (
 	PollRequeueDelay = 200 * time.Millisecond
 	// LongRequeueDelay is the delay for long reconciliation cycles
 	LongRequeueDelay = 60 * time.Second
+	// QuotaExceededRequeueDelay is the delay before retrying a workspace blocked by a ResourceQuota;
+	// headroom appearing on the quota triggers an earlier retry
+	QuotaExceededRequeueDelay = 5 * time.Minute
+
+	// EnvFromMirrorResyncInterval is the interval for syncing copies of envFrom sources of other namespaces
+	// and checking envFrom Secrets for rotation
+	EnvFromMirrorResyncInterval = 2 * time.Minute
 
 	// IdleCheckInterval is the interval for checking workspace idle status
 	IdleCheckInterval = 5 * time.Minute
 
+	// ActivityRecordGranularity is the minimal move of the last activity time that is written to status
+	ActivityRecordGranularity = time.Hour
+
+	// MaxWorkspaceHistoryEntries is the number of entries kept in the workspace status history
+	MaxWorkspaceHistoryEntries = 20
+
+	// MaxChildEvents is the number of child events kept in the workspace status
+	MaxChildEvents = 10
+
+	// ChildEventMirrorInterval is the minimum interval between two mirrors of the same child event,
+	// when repeated occurrences are mirrored
+	ChildEventMirrorInterval = 5 * time.Minute
+
+	// DefaultStartupCheckTimeout bounds a template startup check that does not set a timeout
+	DefaultStartupCheckTimeout = 30 * time.Second
+	// MaxStartupCheckTimeout bounds any template startup check, well within the ReconcileTimeout it runs under
+	MaxStartupCheckTimeout = 60 * time.Second
+	// ServerShutdownTimeout bounds the shutdown request sent to the workspace server before it stops
+	ServerShutdownTimeout = 10 * time.Second
+	// MaxStartupCheckOutputLength is the length of the startup check output kept in the condition message
+	MaxStartupCheckOutputLength = 512
+
+	// DefaultStaleWorkspaceGracePeriod is the default time between flagging a workspace stale and
+	// requesting its archival
+	DefaultStaleWorkspaceGracePeriod = 14 * 24 * time.Hour
+
+	// DefaultDeletionWarningPeriod is the default time between warning the owner of a stopped workspace
+	// and deleting it under spec.retention
+	DefaultDeletionWarningPeriod = 24 * time.Hour
+	// MaxLifetimeWarningPeriod is the time between warning the owner of a workspace reaching the maximum
+	// lifetime of its template and applying the maxLifetimeAction
+	MaxLifetimeWarningPeriod = 48 * time.Hour
+
+	// DefaultConnectionDrainPeriod is the default time in-flight requests are given to complete,
+	// once the workspace service stops routing new connections, before the workspace pod stops
+	DefaultConnectionDrainPeriod = 10 * time.Second
+
+	// DefaultUserIntentCooldown is the default period during which a manual desiredStatus change
+	// suppresses lower-precedence intents such as idle culling
+	DefaultUserIntentCooldown = 30 * time.Minute
+
+	// NamespaceStatusDebounceInterval is the minimum interval between two writes of a WorkspaceNamespaceStatus
+	NamespaceStatusDebounceInterval = 10 * time.Second
+
 	// WorkspaceFinalizerName is the finalizer name for workspace cleanup protection
 	WorkspaceFinalizerName = "workspace.jupyter.org/workspace-protection"
 
@@ -115,33 +280,55 @@ const (
 	SetOnCreateOnly MetadataKeyPolicy = iota
 	// SetAlways indicates the key is set on every create/update by the system
 	SetAlways
+	// SetBySystemOnly indicates the key may only be added, changed or removed by the controller or an administrator
+	SetBySystemOnly
 )
 
 // SystemManagedMetadataKeys defines all workspace.jupyter.org/ prefixed keys that the system manages.
-// Any new system-managed key with the reserved prefix MUST be added here.
+// Any new system-managed key with the reserved prefix MUST be added here. Other keys belong to users:
+// the controller writes the keys it owns with merge patches so that concurrent user edits survive.
 var SystemManagedMetadataKeys = map[string]MetadataKeyPolicy{
-	AnnotationCreatedBy:             SetOnCreateOnly,
-	AnnotationLastUpdatedBy:         SetAlways,
-	PreemptionReasonAnnotation:      SetAlways,
-	LabelWorkspaceTemplate:          SetAlways,
-	LabelWorkspaceTemplateNamespace: SetAlways,
-	LabelAccessStrategyName:         SetAlways,
-	LabelAccessStrategyNamespace:    SetAlways,
+	AnnotationCreatedBy:                    SetOnCreateOnly,
+	AnnotationCreatedByDelegate:            SetOnCreateOnly,
+	AnnotationOnBehalfOf:                   SetAlways,
+	AnnotationLastUpdatedBy:                SetAlways,
+	PreemptionReasonAnnotation:             SetAlways,
+	AnnotationDesiredStatusSetAt:           SetAlways,
+	AnnotationPaused:                       SetAlways,
+	AnnotationMaintenanceIntent:            SetBySystemOnly,
+	AnnotationScheduleIntent:               SetBySystemOnly,
+	AnnotationCullerIntent:                 SetBySystemOnly,
+	AnnotationStaleExempt:                  SetBySystemOnly,
+	AnnotationArchiveRequested:             SetBySystemOnly,
+	AnnotationStartApprovalRequired:        SetAlways,
+	AnnotationStartApprovedBy:              SetAlways,
+	AnnotationSecretRotationRequested:      SetAlways,
+	AnnotationApplyResourceRecommendations: SetAlways,
+	AnnotationRecreateUnboundStorage:       SetAlways,
+	AnnotationResetToDefaults:              SetAlways,
+	AnnotationSkipCleanupHooks:             SetBySystemOnly,
+	AnnotationMaxLifetimeExempt:            SetBySystemOnly,
+	LabelRetain:                            SetAlways,
+	AnnotationTemplateGeneration:           SetAlways,
+	LabelWorkspaceTemplate:                 SetAlways,
+	LabelWorkspaceTemplateNamespace:        SetAlways,
+	LabelAccessStrategyName:                SetAlways,
+	LabelAccessStrategyNamespace:           SetAlways,
 }
 
-// GenerateDeploymentName creates a consistent deployment name
+// GenerateDeploymentName creates a consistent deployment name, with the default child name prefix
 func GenerateDeploymentName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s", ResourcePrefix, workspaceName)
+	return generateChildName(ResourcePrefix, workspaceName, "")
 }
 
-// GenerateServiceName creates a consistent service name
+// GenerateServiceName creates a consistent service name, with the default child name prefix
 func GenerateServiceName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s-service", ResourcePrefix, workspaceName)
+	return generateChildName(ResourcePrefix, workspaceName, serviceNameSuffix)
 }
 
-// GeneratePVCName creates a consistent PVC name
+// GeneratePVCName creates a consistent PVC name, with the default child name prefix
 func GeneratePVCName(workspaceName string) string {
-	return fmt.Sprintf("%s-%s-pvc", ResourcePrefix, workspaceName)
+	return generateChildName(ResourcePrefix, workspaceName, pvcNameSuffix)
 }
 
 // GenerateLabels creates consistent labels for resources
@@ -152,3 +339,10 @@ func GenerateLabels(workspaceName string) map[string]string {
 		LabelComponent:                   "workspace",
 	}
 }
+
+// GenerateAnnotations creates consistent annotations for resources
+func GenerateAnnotations() map[string]string {
+	return map[string]string{
+		AnnotationManagedByVersion: buildinfo.Version,
+	}
+}
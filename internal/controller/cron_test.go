@@ -0,0 +1,108 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	// Monday 2026-03-02 10:30 UTC
+	monday := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		after      time.Time
+		expect     time.Time
+	}{
+		{"every minute", "* * * * *", monday, monday.Add(time.Minute)},
+		{"seconds are ignored", "* * * * *", monday.Add(30 * time.Second), monday.Add(time.Minute)},
+		{"later today", "0 18 * * *", monday, time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)},
+		{"tomorrow", "0 8 * * *", monday, time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC)},
+		{"strictly after", "30 10 * * *", monday, time.Date(2026, 3, 3, 10, 30, 0, 0, time.UTC)},
+		{"weekdays skip the weekend", "0 8 * * 1-5", time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 9, 8, 0, 0, 0, time.UTC)},
+		{"day names", "0 8 * * MON,wed", monday, time.Date(2026, 3, 4, 8, 0, 0, 0, time.UTC)},
+		{"sunday is 7", "0 8 * * 7", monday, time.Date(2026, 3, 8, 8, 0, 0, 0, time.UTC)},
+		{"steps", "*/20 * * * *", monday, time.Date(2026, 3, 2, 10, 40, 0, 0, time.UTC)},
+		{"steps from a value", "5/20 * * * *", monday, time.Date(2026, 3, 2, 10, 45, 0, 0, time.UTC)},
+		{"month names", "0 0 1 jun *", monday, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or day of week", "0 0 15 * 5", monday, time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", monday, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"macro", "@daily", monday, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", monday, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression, "")
+			require.NoError(t, err)
+			assert.True(t, tt.expect.Equal(schedule.Next(tt.after)), "got %s", schedule.Next(tt.after))
+		})
+	}
+}
+
+func TestCronScheduleTimeZone(t *testing.T) {
+	schedule, err := ParseCronSchedule("0 8 * * *", "Europe/Paris")
+	require.NoError(t, err)
+
+	// 8am in Paris is 7am UTC in winter and 6am UTC in summer
+	winter := schedule.Next(time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 3, 7, 0, 0, 0, time.UTC), winter.UTC())
+	summer := schedule.Next(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 7, 2, 6, 0, 0, 0, time.UTC), summer.UTC())
+}
+
+func TestCronSchedulePrev(t *testing.T) {
+	monday := time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		expression string
+		expect     time.Time
+	}{
+		{"every minute", "* * * * *", monday},
+		{"at the activation", "30 10 * * *", monday},
+		{"earlier today", "0 8 * * *", time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{"yesterday", "0 18 * * *", time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)},
+		{"last friday", "0 18 * * 5", time.Date(2026, 2, 27, 18, 0, 0, 0, time.UTC)},
+		{"last year", "0 0 1 jun *", time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression, "")
+			require.NoError(t, err)
+			assert.True(t, tt.expect.Equal(schedule.Prev(monday)), "got %s", schedule.Prev(monday))
+		})
+	}
+}
+
+func TestParseCronScheduleRejectsInvalidExpressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		expectErr  string
+	}{
+		{"0 8 * *", "must have 5 fields"},
+		{"60 8 * * *", "invalid value \"60\" in minute field"},
+		{"0 8 0 * *", "invalid value \"0\" in day of month field"},
+		{"0 8 * foo *", "invalid value \"foo\" in month field"},
+		{"0 18-8 * * *", "invalid range \"18-8\" in hour field"},
+		{"*/0 * * * *", "invalid step \"0\" in minute field"},
+		{"@often", "must have 5 fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ParseCronSchedule(tt.expression, "")
+			assert.ErrorContains(t, err, tt.expectErr)
+		})
+	}
+
+	_, err := ParseCronSchedule("0 8 * * *", "Mars/Olympus_Mons")
+	assert.ErrorContains(t, err, "unknown time zone")
+}
@@ -0,0 +1,73 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+	crcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/debuglog"
+)
+
+const (
+	// LogLevelDebug is the value of the log level annotation raising the verbosity of a workspace
+	LogLevelDebug = "debug"
+
+	// DefaultDebugLogDuration is how long the log level annotation raises the verbosity of a workspace
+	DefaultDebugLogDuration = 30 * time.Minute
+
+	// DecisionLogLevel is the verbosity of the decisions of the reconcilers, written for every
+	// workspace with -zap-log-level=debug and for one workspace with the log level annotation
+	DecisionLogLevel = 1
+)
+
+// withDebugLogCapture returns a context whose logger keeps its records in the debug log buffer of the
+// workspace, and writes the decisions of the reconcilers when the debug log level of the workspace is
+// requested. workspace is nil when it no longer exists.
+func (r *WorkspaceReconciler) withDebugLogCapture(
+	ctx context.Context,
+	key types.NamespacedName,
+	workspace *workspacev1alpha1.Workspace) context.Context {
+	debug := debugLogLevelActive(debuglog.Default, key, workspace, r.debugLogDuration(), time.Now())
+	logger := debuglog.NewCaptureLogger(logf.FromContext(ctx), debuglog.Default, key, debug,
+		"workspace", key.String(), "reconcileID", string(crcontroller.ReconcileIDFromContext(ctx)))
+	return logf.IntoContext(ctx, logger)
+}
+
+// debugLogDuration returns how long the log level annotation raises the verbosity of a workspace
+func (r *WorkspaceReconciler) debugLogDuration() time.Duration {
+	if r.options.DebugLogDuration > 0 {
+		return r.options.DebugLogDuration
+	}
+	return DefaultDebugLogDuration
+}
+
+// debugLogLevelActive returns true while the log level annotation of the workspace raises its verbosity:
+// from the first reconcile that observes the annotation, for duration. Removing the annotation ends the
+// period, adding it again starts a new one.
+func debugLogLevelActive(
+	buffer *debuglog.Buffer,
+	key types.NamespacedName,
+	workspace *workspacev1alpha1.Workspace,
+	duration time.Duration,
+	now time.Time) bool {
+	if workspace == nil || workspace.Annotations[AnnotationLogLevel] != LogLevelDebug {
+		buffer.ClearDebug(key)
+		return false
+	}
+	return now.Before(buffer.DebugSince(key, now).Add(duration))
+}
+
+// logDecision writes a decision of a reconciler at DecisionLogLevel, with the fields it was made on
+func logDecision(logger logr.Logger, decision string, keysAndValues ...any) {
+	logger.V(DecisionLogLevel).Info("Reconcile decision", append([]any{"decision", decision}, keysAndValues...)...)
+}
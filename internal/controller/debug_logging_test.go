@@ -0,0 +1,76 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/debuglog"
+)
+
+func TestDebugLogLevelIsBounded(t *testing.T) {
+	buffer := debuglog.NewBuffer(10, 10)
+	key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{
+		Name: "ws", Namespace: "team-a", Annotations: map[string]string{AnnotationLogLevel: LogLevelDebug},
+	}}
+	start := time.Now()
+
+	assert.True(t, debugLogLevelActive(buffer, key, workspace, time.Hour, start))
+	assert.True(t, debugLogLevelActive(buffer, key, workspace, time.Hour, start.Add(59*time.Minute)))
+	assert.False(t, debugLogLevelActive(buffer, key, workspace, time.Hour, start.Add(61*time.Minute)),
+		"the annotation stops raising the verbosity after the duration")
+
+	// Removing and adding the annotation again starts a new period
+	workspace.Annotations = nil
+	assert.False(t, debugLogLevelActive(buffer, key, workspace, time.Hour, start.Add(62*time.Minute)))
+	workspace.Annotations = map[string]string{AnnotationLogLevel: LogLevelDebug}
+	assert.True(t, debugLogLevelActive(buffer, key, workspace, time.Hour, start.Add(63*time.Minute)))
+	assert.False(t, debugLogLevelActive(buffer, key, nil, time.Hour, start.Add(64*time.Minute)))
+}
+
+func TestDebugLogCaptureKeepsTheDecisionsOfTheWorkspace(t *testing.T) {
+	var lines []string
+	base := funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{})
+	ctx := logf.IntoContext(context.Background(), base)
+	reconciler := &WorkspaceReconciler{}
+
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Name = "debug-log-capture"
+	workspace.Annotations = map[string]string{AnnotationLogLevel: LogLevelDebug}
+	workspace.Spec.Env = []corev1.EnvVar{{Name: "API_KEY", Value: "sk-12345"}}
+	key := types.NamespacedName{Namespace: workspace.Namespace, Name: workspace.Name}
+
+	logger := logf.FromContext(reconciler.withDebugLogCapture(ctx, key, workspace))
+	logger.V(DecisionLogLevel).Info("Reconciling workspace", "spec", workspace.Spec)
+	logDecision(logger, "ReconcileTowards", "desiredStatus", DesiredStateRunning)
+
+	records := debuglog.Default.Records(key, time.Time{})
+	require.Len(t, records, 2)
+	assert.Equal(t, key.String(), records[1].Values["workspace"])
+	assert.Equal(t, "ReconcileTowards", records[1].Values["decision"])
+	assert.NotContains(t, records[0].Values["spec"], "sk-12345")
+	require.Len(t, lines, 2, "the decisions are written at the verbosity of the operator")
+	assert.NotContains(t, lines[0], "sk-12345")
+
+	// Without the annotation, debug records are neither written nor kept
+	workspace.Annotations = nil
+	logger = logf.FromContext(reconciler.withDebugLogCapture(ctx, key, workspace))
+	logDecision(logger, "ReconcileTowards", "desiredStatus", DesiredStateRunning)
+	assert.Len(t, debuglog.Default.Records(key, time.Time{}), 2)
+	assert.Len(t, lines, 2)
+}
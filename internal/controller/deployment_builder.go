@@ -8,16 +8,16 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -28,8 +28,9 @@ type DeploymentBuilder struct {
 	imageResolver *ImageResolver
 }
 
-// NewDeploymentBuilder creates a new DeploymentBuilder
-func NewDeploymentBuilder(scheme *runtime.Scheme, options WorkspaceControllerOptions, k8sClient client.Client) *DeploymentBuilder {
+// NewDeploymentBuilder creates a new DeploymentBuilder.
+// The builder performs no API calls: its output only depends on the workspace and the options.
+func NewDeploymentBuilder(scheme *runtime.Scheme, options WorkspaceControllerOptions) *DeploymentBuilder {
 	return &DeploymentBuilder{
 		scheme:        scheme,
 		options:       options,
@@ -72,6 +73,12 @@ func (db *DeploymentBuilder) BuildDeploymentWithAccessStrategy(
 		}
 	}
 
+	db.applyServerAdapter(deployment, workspace)
+
+	if err := stampPodTemplate(deployment, workspace); err != nil {
+		return nil, fmt.Errorf("failed to fingerprint pod template: %w", err)
+	}
+
 	return deployment, nil
 }
 
@@ -87,16 +94,13 @@ func (db *DeploymentBuilder) buildObjectMeta(workspace *workspacev1alpha1.Worksp
 	}
 
 	// Copy all workspace annotations to deployment
-	var annotations map[string]string
-	if workspace.Annotations != nil {
-		annotations = make(map[string]string)
-		for key, value := range workspace.Annotations {
-			annotations[key] = value
-		}
+	annotations := GenerateAnnotations()
+	for key, value := range workspace.Annotations {
+		annotations[key] = value
 	}
 
 	return metav1.ObjectMeta{
-		Name:        GenerateDeploymentName(workspace.Name),
+		Name:        deploymentNameFor(workspace),
 		Namespace:   workspace.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
@@ -114,28 +118,44 @@ func (db *DeploymentBuilder) buildPodLabels(workspace *workspacev1alpha1.Workspa
 		}
 	}
 
+	if metadata := childMetadataOf(workspace).Pod; metadata != nil {
+		labels = mergeChildMetadata(labels, metadata.Labels, GenerateLabels(workspace.Name))
+	}
+	if metadata := workspace.Spec.PodMetadata; metadata != nil {
+		labels = mergeChildMetadata(labels, metadata.Labels, GenerateLabels(workspace.Name))
+	}
 	return labels
 }
 
 // buildPodAnnotations creates annotations for pod template, including workspace annotations
-func (db *DeploymentBuilder) buildPodAnnotations(workspace *workspacev1alpha1.Workspace) map[string]string {
-	var annotations map[string]string
+func (db *DeploymentBuilder) buildPodAnnotations(workspace *workspacev1alpha1.Workspace, podSpec *corev1.PodSpec) map[string]string {
+	annotations := GenerateAnnotations()
 
 	// Copy all workspace annotations to pod
-	if workspace.Annotations != nil {
-		annotations = make(map[string]string)
-		for key, value := range workspace.Annotations {
-			annotations[key] = value
-		}
+	for key, value := range workspace.Annotations {
+		annotations[key] = value
+	}
+	if workspace.Status.EnvFromSecretsChecksum != "" {
+		annotations[AnnotationEnvFromSecretsChecksum] = workspace.Status.EnvFromSecretsChecksum
+	}
+	if requested := workspace.Spec.RestartRequestedAt; requested != nil {
+		annotations[AnnotationRestartedAt] = requested.UTC().Format(time.RFC3339)
+	}
+	if metadata := childMetadataOf(workspace).Pod; metadata != nil {
+		annotations = mergeChildMetadata(annotations, metadata.Annotations, GenerateAnnotations())
+	}
+	if metadata := workspace.Spec.PodMetadata; metadata != nil {
+		annotations = mergeChildMetadata(annotations, metadata.Annotations, GenerateAnnotations())
 	}
 
-	return annotations
+	return applyServiceMeshAnnotations(annotations, db.options.ServiceMeshMode, workspace.Spec.ServiceMesh, podSpec)
 }
 
 // buildDeploymentSpec creates the deployment specification
 func (db *DeploymentBuilder) buildDeploymentSpec(workspace *workspacev1alpha1.Workspace, resources corev1.ResourceRequirements) appsv1.DeploymentSpec {
 	// Single replica for Jupyter workspaces (stateful, user-specific workloads)
 	replicas := int32(1)
+	podSpec := db.buildPodSpec(workspace, resources)
 
 	return appsv1.DeploymentSpec{
 		Replicas: &replicas,
@@ -148,9 +168,9 @@ func (db *DeploymentBuilder) buildDeploymentSpec(workspace *workspacev1alpha1.Wo
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels:      db.buildPodLabels(workspace),
-				Annotations: db.buildPodAnnotations(workspace),
+				Annotations: db.buildPodAnnotations(workspace, &podSpec),
 			},
-			Spec: db.buildPodSpec(workspace, resources),
+			Spec: podSpec,
 		},
 	}
 }
@@ -165,22 +185,25 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 
 	storageConfig := ResolveStorageConfig(workspace)
 	if storageConfig != nil {
-		podSpec.Volumes = []corev1.Volume{
-			{
-				Name: "workspace-storage",
-				VolumeSource: corev1.VolumeSource{
-					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
-						ClaimName: GeneratePVCName(workspace.Name),
-					},
-				},
+		volumeSource := corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: pvcNameFor(workspace),
 			},
 		}
+		if storageConfig.Mode == workspacev1alpha1.StorageModeEphemeral {
+			// The home directory lives as long as the pod, bounded by the storage size
+			volumeSource = corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &storageConfig.Size},
+			}
+		}
+		podSpec.Volumes = []corev1.Volume{{Name: "workspace-storage", VolumeSource: volumeSource}}
 	}
 
 	// Add additional volumes from spec
 	for _, vol := range workspace.Spec.Volumes {
-		if vol.Name == "workspace-storage" {
-			// Skip if name conflicts with primary storage
+		if vol.Name == "workspace-storage" || isTmpVolumeConflict(workspace, vol) ||
+			SharedVolumeConflict(workspace.Status.SharedVolumes, vol.Name, vol.MountPath) != "" {
+			// Skip if name conflicts with primary storage, the tmp volume or a shared volume
 			continue
 		}
 		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
@@ -193,34 +216,65 @@ func (db *DeploymentBuilder) buildPodSpec(workspace *workspacev1alpha1.Workspace
 		})
 	}
 
-	// Set scheduling fields from workspace spec
-	if len(workspace.Spec.NodeSelector) > 0 {
-		podSpec.NodeSelector = workspace.Spec.NodeSelector
+	// Back /tmp with a volume of the pod, bounded by its own size limit
+	if workspace.Spec.TmpVolume != nil {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: TmpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: workspace.Spec.TmpVolume.SizeLimit},
+			},
+		})
 	}
 
-	if workspace.Spec.Affinity != nil {
-		podSpec.Affinity = workspace.Spec.Affinity
-	}
+	// Mount the shared volumes of the template
+	applySharedVolumes(&podSpec, workspace)
 
-	if len(workspace.Spec.Tolerations) > 0 {
-		podSpec.Tolerations = workspace.Spec.Tolerations
-	}
+	// Mount the PVCs, ConfigMaps and Secrets of spec.extraVolumes
+	applyExtraVolumes(&podSpec, workspace)
+
+	// Run the post-start script of the template when the workspace container starts
+	applyPostStartScript(&podSpec, workspace)
+
+	// Run the sidecars of the template beside the workspace container
+	applySidecars(&podSpec, workspace)
+
+	// Prepare the home directory before the workspace container starts
+	applyInitContainers(&podSpec, workspace)
+
+	// Set scheduling fields from workspace spec, merged with the template defaults
+	applyScheduling(&podSpec, workspace)
 
 	if workspace.Spec.ServiceAccountName != "" {
 		podSpec.ServiceAccountName = workspace.Spec.ServiceAccountName
 	}
 
+	// Pull the images with the secrets of the template and of the workspace
+	podSpec.ImagePullSecrets = imagePullSecretsOf(workspace)
+
 	// Apply pod security context
 	if workspace.Spec.PodSecurityContext != nil {
 		podSpec.SecurityContext = workspace.Spec.PodSecurityContext
 	}
 
+	// Request the accelerators of the workspace on the nodes the template says provide them
+	applyAccelerators(&podSpec, workspace)
+
+	// Mount a service account token only for templates granting cluster access
+	applyClusterAccess(&podSpec, workspace.Status.ClusterAccess)
+
+	// Harden the pod to the isolation level of the template
+	applyIsolationLevel(&podSpec, workspace)
+
 	return podSpec
 }
 
 // buildPrimaryContainer creates the container specification
 func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.Workspace, resources corev1.ResourceRequirements) corev1.Container {
 	image := db.imageResolver.ResolveImage(workspace)
+	// Run the digest the tag pointed at under the Digest image pinning of the template
+	if pinned := resolvedImageOf(workspace, image); pinned != "" {
+		image = pinned
+	}
 
 	// Get command and args from container config if specified
 	var command []string
@@ -229,25 +283,36 @@ func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.
 		command = workspace.Spec.ContainerConfig.Command
 		args = workspace.Spec.ContainerConfig.Args
 	}
+	// The args of the server type of the template follow those of the container config
+	if serverType := workspace.Status.ServerType; serverType != nil && len(serverType.Args) > 0 {
+		args = append(slices.Clone(args), serverType.Args...)
+	}
+
+	// Workspace pull policy (forced by the template image policy) wins over the controller-wide one
+	pullPolicy := db.options.ApplicationImagesPullPolicy
+	if workspace.Spec.ImagePullPolicy != "" {
+		pullPolicy = workspace.Spec.ImagePullPolicy
+	}
 
 	container := corev1.Container{
-		Name:            "workspace",
+		Name:            workspaceContainerName,
 		Image:           image,
-		ImagePullPolicy: db.options.ApplicationImagesPullPolicy,
+		ImagePullPolicy: pullPolicy,
 		SecurityContext: workspace.Spec.ContainerSecurityContext,
 		Command:         command,
 		Args:            args,
 		Lifecycle:       workspace.Spec.Lifecycle,
-		Env:             workspace.Spec.Env,
+		Env:             buildContainerEnv(workspace),
+		EnvFrom:         buildContainerEnvFrom(workspace),
 		Ports: []corev1.ContainerPort{
 			{
 				Name:          "http",
-				ContainerPort: JupyterPort,
+				ContainerPort: serverPort(ResolveServerAdapter(workspace)),
 				Protocol:      corev1.ProtocolTCP,
 			},
 		},
 		Resources: resources,
-		// TODO: Add probes
+		// The readiness probe comes from the server adapter, see applyServerAdapter
 	}
 
 	storageConfig := ResolveStorageConfig(workspace)
@@ -256,14 +321,16 @@ func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.
 			{
 				Name:      "workspace-storage",
 				MountPath: storageConfig.MountPath,
+				SubPath:   storageConfig.SubPath,
 			},
 		}
 	}
 
 	// Add additional volume mounts from spec
 	for _, vol := range workspace.Spec.Volumes {
-		if vol.Name == "workspace-storage" {
-			// Skip if name conflicts with primary storage
+		if vol.Name == "workspace-storage" || isTmpVolumeConflict(workspace, vol) ||
+			SharedVolumeConflict(workspace.Status.SharedVolumes, vol.Name, vol.MountPath) != "" {
+			// Skip if name conflicts with primary storage, the tmp volume or a shared volume
 			continue
 		}
 		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
@@ -272,27 +339,72 @@ func (db *DeploymentBuilder) buildPrimaryContainer(workspace *workspacev1alpha1.
 		})
 	}
 
+	if workspace.Spec.TmpVolume != nil {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      TmpVolumeName,
+			MountPath: TmpMountPath,
+		})
+	}
+
 	return container
 }
 
-// parseResourceRequirements extracts and validates resource requirements
+// buildContainerEnv returns the env of the workspace container: the env of the workspace, and the home
+// directory in RootDirEnv when it is not the default one, which images open in, unless the workspace sets it
+func buildContainerEnv(workspace *workspacev1alpha1.Workspace) []corev1.EnvVar {
+	storageConfig := ResolveStorageConfig(workspace)
+	if storageConfig == nil || storageConfig.MountPath == DefaultMountPath || slices.ContainsFunc(workspace.Spec.Env, func(env corev1.EnvVar) bool {
+		return env.Name == RootDirEnv
+	}) {
+		return workspace.Spec.Env
+	}
+	env := slices.Clone(workspace.Spec.Env)
+	return append(env, corev1.EnvVar{Name: RootDirEnv, Value: storageConfig.MountPath})
+}
+
+// workspaceContainer returns the container of the pod spec running the workspace application, or nil.
+// Containers are always looked up by name: mutating webhooks of the cluster, such as service mesh
+// injectors, may add containers before it or reorder them.
+func workspaceContainer(podSpec *corev1.PodSpec) *corev1.Container {
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == workspaceContainerName {
+			return &podSpec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// parseResourceRequirements returns the resources of the workspace container: those of the workspace,
+// less those of its sidecars under Carved sidecar resource accounting
 func (db *DeploymentBuilder) parseResourceRequirements(workspace *workspacev1alpha1.Workspace) corev1.ResourceRequirements {
-	defaultCPU := resource.MustParse(DefaultCPURequest)
-	defaultMemory := resource.MustParse(DefaultMemoryRequest)
+	return PrimaryContainerResources(RequestedResources(workspace), workspace.Status.Sidecars,
+		workspace.Status.SidecarResourceAccounting)
+}
 
+// RequestedResources returns the resources the workspace requests, the default resources when it requests none
+func RequestedResources(workspace *workspacev1alpha1.Workspace) corev1.ResourceRequirements {
 	// Use provided resources if available, otherwise use defaults
 	if workspace.Spec.Resources != nil {
 		result := *workspace.Spec.Resources
 		if result.Requests == nil {
-			result.Requests = corev1.ResourceList{
-				corev1.ResourceCPU:    defaultCPU,
-				corev1.ResourceMemory: defaultMemory,
-			}
+			result.Requests = DefaultResourceRequirements().Requests
 		}
 
 		return result
 	}
 
+	return DefaultResourceRequirements()
+}
+
+// isTmpVolumeConflict returns true if the volume would shadow the tmp volume of the workspace
+func isTmpVolumeConflict(workspace *workspacev1alpha1.Workspace, vol workspacev1alpha1.VolumeSpec) bool {
+	return workspace.Spec.TmpVolume != nil && (vol.Name == TmpVolumeName || vol.MountPath == TmpMountPath)
+}
+
+// DefaultResourceRequirements returns the resources of workspace containers that do not specify any
+func DefaultResourceRequirements() corev1.ResourceRequirements {
+	defaultCPU := resource.MustParse(DefaultCPURequest)
+	defaultMemory := resource.MustParse(DefaultMemoryRequest)
 	return corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
 			corev1.ResourceCPU:    defaultCPU,
@@ -318,19 +430,12 @@ func (db *DeploymentBuilder) NeedsUpdate(
 		return false, fmt.Errorf("failed to build desired deployment: %w", err)
 	}
 
-	// Compare pod template specs and metadata using semantic equality
-	if !equality.Semantic.DeepEqual(existingDeployment.Spec.Template.Spec, desiredDeployment.Spec.Template.Spec) {
-		return true, nil
-	}
-
-	// Compare pod template metadata (labels and annotations)
-	if !equality.Semantic.DeepEqual(existingDeployment.Spec.Template.Labels, desiredDeployment.Spec.Template.Labels) {
-		return true, nil
-	}
-
-	if !equality.Semantic.DeepEqual(existingDeployment.Spec.Template.Annotations, desiredDeployment.Spec.Template.Annotations) {
-		return true, nil
+	// Compare fingerprints rather than the objects: the existing pod template carries API server
+	// defaults, and cosmetic rendering changes (list order, reserved annotations such as the operator
+	// version) must not restart workspaces
+	existingHash, err := existingPodTemplateHash(existingDeployment)
+	if err != nil {
+		return false, fmt.Errorf("failed to fingerprint existing pod template: %w", err)
 	}
-
-	return false, nil
+	return existingHash != desiredDeployment.Annotations[AnnotationPodTemplateHash], nil
 }
@@ -139,7 +139,10 @@ func (db *DeploymentBuilder) ApplyAccessStrategyToDeployment(
 	if accessStrategy == nil {
 		return nil // Nothing to do
 	}
-	primaryContainer := &deployment.Spec.Template.Spec.Containers[0]
+	primaryContainer := workspaceContainer(&deployment.Spec.Template.Spec)
+	if primaryContainer == nil {
+		return fmt.Errorf("cannot apply AccessStrategy '%s': deployment has no '%s' container", accessStrategy.Name, workspaceContainerName)
+	}
 
 	// Apply environment variables to primary container
 	if err := db.addAccessStrategyEnvToContainer(primaryContainer, workspace, accessStrategy); err != nil {
@@ -147,16 +150,18 @@ func (db *DeploymentBuilder) ApplyAccessStrategyToDeployment(
 	}
 
 	// Apply deployment spec modifications if defined
-	if err := db.applyDeploymentSpecModifications(deployment, accessStrategy); err != nil {
+	if err := db.applyDeploymentSpecModifications(deployment, workspace, accessStrategy); err != nil {
 		return fmt.Errorf("failed to apply deployment spec modifications: %w", err)
 	}
 
 	return nil
 }
 
-// applyDeploymentSpecModifications applies deployment modifications from access strategy
+// applyDeploymentSpecModifications applies deployment modifications from access strategy,
+// omitting the optional sidecars the workspace disables
 func (db *DeploymentBuilder) applyDeploymentSpecModifications(
 	deployment *appsv1.Deployment,
+	workspace *workspacev1alpha1.Workspace,
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
 ) error {
 	if deployment == nil {
@@ -167,6 +172,11 @@ func (db *DeploymentBuilder) applyDeploymentSpecModifications(
 	}
 
 	mods := accessStrategy.Spec.DeploymentModifications
+	if mods.PodModifications != nil {
+		mods = &workspacev1alpha1.DeploymentModifications{
+			PodModifications: omitDisabledSidecars(deployment, workspace, accessStrategy),
+		}
+	}
 
 	// Add volumes
 	if mods.PodModifications != nil && len(mods.PodModifications.Volumes) > 0 {
@@ -191,15 +201,15 @@ func (db *DeploymentBuilder) applyDeploymentSpecModifications(
 		mods.PodModifications.PrimaryContainerModifications != nil &&
 		len(mods.PodModifications.PrimaryContainerModifications.VolumeMounts) > 0 {
 
-		if len(deployment.Spec.Template.Spec.Containers) == 0 {
-			return fmt.Errorf("no containers found in deployment to add volume mounts")
+		primaryContainer := workspaceContainer(&deployment.Spec.Template.Spec)
+		if primaryContainer == nil {
+			return fmt.Errorf("no '%s' container found in deployment to add volume mounts", workspaceContainerName)
 		}
 
 		logf.Log.V(1).Info("Adding volume mounts to primary container",
 			"accessStrategy", accessStrategy.Name,
 			"mountCount", len(mods.PodModifications.PrimaryContainerModifications.VolumeMounts))
 
-		primaryContainer := &deployment.Spec.Template.Spec.Containers[0]
 		primaryContainer.VolumeMounts = append(
 			primaryContainer.VolumeMounts,
 			mods.PodModifications.PrimaryContainerModifications.VolumeMounts...,
@@ -256,3 +266,78 @@ func (db *DeploymentBuilder) applyDeploymentSpecModifications(
 
 	return nil
 }
+
+// omitDisabledSidecars returns the pod modifications of the access strategy without the optional
+// sidecars the workspace disables, nor the volumes only these sidecars mount.
+// Names of mandatory sidecars are ignored: the webhook rejects them.
+func omitDisabledSidecars(
+	deployment *appsv1.Deployment,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy,
+) *workspacev1alpha1.PodModifications {
+	podMods := accessStrategy.Spec.DeploymentModifications.PodModifications
+	if workspace == nil || len(workspace.Spec.DisabledSidecars) == 0 {
+		return podMods
+	}
+
+	optional := make(map[string]bool, len(podMods.OptionalContainers))
+	for _, name := range podMods.OptionalContainers {
+		optional[name] = true
+	}
+	disabled := make(map[string]bool, len(workspace.Spec.DisabledSidecars))
+	for _, name := range workspace.Spec.DisabledSidecars {
+		if optional[name] {
+			disabled[name] = true
+		}
+	}
+	if len(disabled) == 0 {
+		return podMods
+	}
+
+	filtered := podMods.DeepCopy()
+	filtered.AdditionalContainers = nil
+	omittedMounts := map[string]bool{}
+	for _, container := range podMods.AdditionalContainers {
+		if !disabled[container.Name] {
+			filtered.AdditionalContainers = append(filtered.AdditionalContainers, container)
+			continue
+		}
+		logf.Log.V(1).Info("Omitting sidecar disabled by the workspace",
+			"accessStrategy", accessStrategy.Name,
+			"containerName", container.Name)
+		for _, mount := range container.VolumeMounts {
+			omittedMounts[mount.Name] = true
+		}
+	}
+
+	// Volumes stay as long as any remaining container mounts them
+	mounted := map[string]bool{}
+	addMounts := func(containers []corev1.Container) {
+		for _, container := range containers {
+			for _, mount := range container.VolumeMounts {
+				mounted[mount.Name] = true
+			}
+		}
+	}
+	addMounts(deployment.Spec.Template.Spec.Containers)
+	addMounts(deployment.Spec.Template.Spec.InitContainers)
+	addMounts(filtered.InitContainers)
+	addMounts(filtered.AdditionalContainers)
+	if filtered.PrimaryContainerModifications != nil {
+		for _, mount := range filtered.PrimaryContainerModifications.VolumeMounts {
+			mounted[mount.Name] = true
+		}
+	}
+
+	filtered.Volumes = nil
+	for _, volume := range podMods.Volumes {
+		if omittedMounts[volume.Name] && !mounted[volume.Name] {
+			logf.Log.V(1).Info("Omitting volume of disabled sidecar",
+				"accessStrategy", accessStrategy.Name,
+				"volumeName", volume.Name)
+			continue
+		}
+		filtered.Volumes = append(filtered.Volumes, volume)
+	}
+	return filtered
+}
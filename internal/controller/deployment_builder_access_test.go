@@ -42,7 +42,7 @@ var _ = Describe("DeploymentBuilderForAccess", func() {
 			ApplicationImagesRegistry:   "quay.io",
 		}
 
-		deploymentBuilder = NewDeploymentBuilder(scheme, options, nil)
+		deploymentBuilder = NewDeploymentBuilder(scheme, options)
 
 		// Create test workspace
 		testWorkspace = &workspacev1alpha1.Workspace{
@@ -558,4 +558,86 @@ var _ = Describe("DeploymentBuilderForAccess", func() {
 			Expect(additionalContainer.VolumeMounts[0].MountPath).To(Equal("/shared-data"))
 		})
 	})
+
+	Context("Disabled sidecars", func() {
+		var sidecarAccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
+
+		BeforeEach(func() {
+			sidecarAccessStrategy = &workspacev1alpha1.WorkspaceAccessStrategy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecars"},
+				Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+					DisplayName: "Test Sidecars",
+					DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+						PodModifications: &workspacev1alpha1.PodModifications{
+							AdditionalContainers: []corev1.Container{
+								{
+									Name:  "telemetry",
+									Image: "telemetry:1.0",
+									VolumeMounts: []corev1.VolumeMount{
+										{Name: "telemetry-buffer", MountPath: "/buffer"},
+										{Name: "shared-storage", MountPath: "/shared-data"},
+									},
+								},
+								{
+									Name:         "auth-proxy",
+									Image:        "auth-proxy:1.0",
+									VolumeMounts: []corev1.VolumeMount{{Name: "shared-storage", MountPath: "/shared-data"}},
+								},
+							},
+							OptionalContainers: []string{"telemetry"},
+							Volumes: []corev1.Volume{
+								{Name: "telemetry-buffer", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+								{Name: "shared-storage", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+							},
+						},
+					},
+				},
+			}
+		})
+
+		containerNames := func(deployment *appsv1.Deployment) []string {
+			var names []string
+			for _, container := range deployment.Spec.Template.Spec.Containers {
+				names = append(names, container.Name)
+			}
+			return names
+		}
+
+		volumeNames := func(deployment *appsv1.Deployment) []string {
+			var names []string
+			for _, volume := range deployment.Spec.Template.Spec.Volumes {
+				names = append(names, volume.Name)
+			}
+			return names
+		}
+
+		It("Should add every sidecar when the workspace disables none", func() {
+			err := deploymentBuilder.ApplyAccessStrategyToDeployment(testDeployment, testWorkspace, sidecarAccessStrategy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerNames(testDeployment)).To(Equal([]string{"workspace", "telemetry", "auth-proxy"}))
+			Expect(volumeNames(testDeployment)).To(Equal([]string{"telemetry-buffer", "shared-storage"}))
+		})
+
+		It("Should omit a disabled optional sidecar and the volumes only it mounts", func() {
+			testWorkspace.Spec.DisabledSidecars = []string{"telemetry"}
+
+			err := deploymentBuilder.ApplyAccessStrategyToDeployment(testDeployment, testWorkspace, sidecarAccessStrategy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerNames(testDeployment)).To(Equal([]string{"workspace", "auth-proxy"}))
+			Expect(volumeNames(testDeployment)).To(Equal([]string{"shared-storage"}))
+			Expect(sidecarAccessStrategy.Spec.DeploymentModifications.PodModifications.AdditionalContainers).To(HaveLen(2))
+		})
+
+		It("Should keep a mandatory sidecar the workspace lists as disabled", func() {
+			testWorkspace.Spec.DisabledSidecars = []string{"auth-proxy"}
+
+			err := deploymentBuilder.ApplyAccessStrategyToDeployment(testDeployment, testWorkspace, sidecarAccessStrategy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerNames(testDeployment)).To(Equal([]string{"workspace", "telemetry", "auth-proxy"}))
+			Expect(volumeNames(testDeployment)).To(Equal([]string{"telemetry-buffer", "shared-storage"}))
+		})
+	})
 })
@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("DeploymentBuilder home directory", func() {
+	var (
+		ctx       context.Context
+		builder   *DeploymentBuilder
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		builder = NewDeploymentBuilder(scheme, WorkspaceControllerOptions{
+			ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		})
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace-home",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Home Workspace",
+				Storage: &workspacev1alpha1.StorageSpec{
+					Size:      resource.MustParse("1Gi"),
+					MountPath: "/home/user",
+					SubPath:   "home",
+				},
+			},
+		}
+	})
+
+	It("should mount the subPath of the volume at the mount path and open the server in it", func() {
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      HomeVolumeName,
+			MountPath: "/home/user",
+			SubPath:   "home",
+		}))
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: RootDirEnv, Value: "/home/user"}))
+	})
+
+	It("should not set the root directory of workspaces using the default home directory", func() {
+		workspace.Spec.Storage.MountPath = DefaultMountPath
+
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+			Expect(env.Name).NotTo(Equal(RootDirEnv))
+		}
+	})
+
+	It("should keep the root directory the workspace sets", func() {
+		workspace.Spec.Env = []corev1.EnvVar{{Name: RootDirEnv, Value: "/home/user/notebooks"}}
+
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(Equal(workspace.Spec.Env))
+	})
+
+	It("should mount the home directory of sidecars under the subPath", func() {
+		workspace.Status.Sidecars = []corev1.Container{{
+			Name:  "sync",
+			Image: "sync:latest",
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: HomeVolumeName, MountPath: "/data"},
+				{Name: HomeVolumeName, MountPath: "/notebooks", SubPath: "notebooks"},
+			},
+		}}
+
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		var sidecar *corev1.Container
+		for i := range deployment.Spec.Template.Spec.Containers {
+			if deployment.Spec.Template.Spec.Containers[i].Name == "sync" {
+				sidecar = &deployment.Spec.Template.Spec.Containers[i]
+			}
+		}
+		Expect(sidecar).NotTo(BeNil())
+		Expect(sidecar.VolumeMounts).To(Equal([]corev1.VolumeMount{
+			{Name: HomeVolumeName, MountPath: "/data", SubPath: "home"},
+			{Name: HomeVolumeName, MountPath: "/notebooks", SubPath: "home/notebooks"},
+		}))
+	})
+})
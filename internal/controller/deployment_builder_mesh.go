@@ -0,0 +1,113 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Supported service mesh modes
+const (
+	ServiceMeshModeIstio   = "istio"
+	ServiceMeshModeLinkerd = "linkerd"
+)
+
+// Service mesh pod annotations
+const (
+	// IstioSidecarInjectAnnotation toggles istio-proxy injection for a pod
+	IstioSidecarInjectAnnotation = "sidecar.istio.io/inject"
+	// IstioProxyConfigAnnotation overrides the istio-proxy configuration for a pod
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+	// IstioHoldApplicationUntilProxyStarts delays the workspace container until the proxy is ready,
+	// so that startup traffic (e.g. extension installs) does not fail before the mesh is up
+	IstioHoldApplicationUntilProxyStarts = `{"holdApplicationUntilProxyStarts":true}`
+	// IstioRewriteAppHTTPProbersAnnotation sends the kubelet HTTP probes of the pod through the istio-proxy,
+	// so that the readiness probe of the server keeps passing under STRICT mTLS
+	IstioRewriteAppHTTPProbersAnnotation = "sidecar.istio.io/rewriteAppHTTPProbers"
+	// IstioNativeSidecarAnnotation injects the istio-proxy as a native sidecar, started before the init
+	// containers and stopped after the workspace container
+	IstioNativeSidecarAnnotation = "sidecar.istio.io/nativeSidecar"
+
+	// LinkerdInjectAnnotation toggles linkerd-proxy injection for a pod
+	LinkerdInjectAnnotation = "linkerd.io/inject"
+	// LinkerdProxyAwaitAnnotation delays the workspace container until the proxy is ready
+	LinkerdProxyAwaitAnnotation = "config.linkerd.io/proxy-await"
+	// LinkerdNativeSidecarAnnotation injects the linkerd-proxy as a native sidecar, started before the init
+	// containers and stopped after the workspace container
+	LinkerdNativeSidecarAnnotation = "config.alpha.linkerd.io/proxy-enable-native-sidecar"
+)
+
+// Names of the proxy containers injected by the service meshes
+const (
+	IstioProxyContainerName   = "istio-proxy"
+	LinkerdProxyContainerName = "linkerd-proxy"
+)
+
+// applyServiceMeshAnnotations adds the sidecar injection annotations for the configured mesh.
+// Pods with init containers get a native sidecar, so that the init containers reach the network through
+// the proxy; linkerd pods always do, since the linkerd-proxy cannot be asked to quit when the workspace
+// stops. Annotations already present on the workspace take precedence so that users can fine-tune the proxy.
+func applyServiceMeshAnnotations(
+	annotations map[string]string,
+	meshMode string,
+	meshSpec *workspacev1alpha1.ServiceMeshSpec,
+	podSpec *corev1.PodSpec,
+) map[string]string {
+	if meshSpec == nil || meshSpec.SidecarInjection == nil {
+		return annotations
+	}
+	inject := *meshSpec.SidecarInjection
+
+	meshAnnotations := map[string]string{}
+	switch meshMode {
+	case ServiceMeshModeIstio:
+		meshAnnotations[IstioSidecarInjectAnnotation] = "false"
+		if inject {
+			meshAnnotations[IstioSidecarInjectAnnotation] = "true"
+			meshAnnotations[IstioProxyConfigAnnotation] = IstioHoldApplicationUntilProxyStarts
+			meshAnnotations[IstioRewriteAppHTTPProbersAnnotation] = "true"
+			if hasInitContainers(podSpec) {
+				meshAnnotations[IstioNativeSidecarAnnotation] = "true"
+			}
+		}
+	case ServiceMeshModeLinkerd:
+		meshAnnotations[LinkerdInjectAnnotation] = "disabled"
+		if inject {
+			meshAnnotations[LinkerdInjectAnnotation] = "enabled"
+			meshAnnotations[LinkerdProxyAwaitAnnotation] = "enabled"
+			meshAnnotations[LinkerdNativeSidecarAnnotation] = "true"
+		}
+	default:
+		return annotations
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	for key, value := range meshAnnotations {
+		if _, exists := annotations[key]; !exists {
+			annotations[key] = value
+		}
+	}
+
+	return annotations
+}
+
+// hasInitContainers returns whether the pod runs init containers before the workspace container, leaving
+// out the native sidecars
+func hasInitContainers(podSpec *corev1.PodSpec) bool {
+	if podSpec == nil {
+		return false
+	}
+	for _, container := range podSpec.InitContainers {
+		if container.RestartPolicy == nil || *container.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			return true
+		}
+	}
+	return false
+}
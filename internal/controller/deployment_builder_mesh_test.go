@@ -0,0 +1,141 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("DeploymentBuilder service mesh", func() {
+	var (
+		ctx       context.Context
+		scheme    *runtime.Scheme
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	newBuilder := func(meshMode string) *DeploymentBuilder {
+		return NewDeploymentBuilder(scheme, WorkspaceControllerOptions{
+			ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+			ServiceMeshMode:             meshMode,
+		})
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace-mesh",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Mesh Workspace",
+			},
+		}
+	})
+
+	It("should not add mesh annotations when mesh mode is not configured", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+
+		deployment, err := newBuilder("").BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations).To(Equal(GenerateAnnotations()))
+	})
+
+	It("should not add mesh annotations when sidecar injection is unset", func() {
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations).To(Equal(GenerateAnnotations()))
+	})
+
+	It("should enable istio injection and hold the application until the proxy starts", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		annotations := deployment.Spec.Template.Annotations
+		Expect(annotations).To(HaveKeyWithValue(IstioSidecarInjectAnnotation, "true"))
+		Expect(annotations).To(HaveKeyWithValue(IstioProxyConfigAnnotation, IstioHoldApplicationUntilProxyStarts))
+		Expect(annotations).To(HaveKeyWithValue(IstioRewriteAppHTTPProbersAnnotation, "true"))
+		Expect(annotations).NotTo(HaveKey(IstioNativeSidecarAnnotation))
+	})
+
+	It("should run the istio proxy as a native sidecar when the pod has init containers", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+		workspace.Status.InitContainers = []corev1.Container{{Name: "setup", Image: "busybox"}}
+
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(IstioNativeSidecarAnnotation, "true"))
+	})
+
+	It("should disable istio injection", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(false)}
+
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		annotations := deployment.Spec.Template.Annotations
+		Expect(annotations).To(HaveKeyWithValue(IstioSidecarInjectAnnotation, "false"))
+		Expect(annotations).NotTo(HaveKey(IstioProxyConfigAnnotation))
+	})
+
+	It("should enable linkerd injection and await the proxy", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+
+		deployment, err := newBuilder(ServiceMeshModeLinkerd).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		annotations := deployment.Spec.Template.Annotations
+		Expect(annotations).To(HaveKeyWithValue(LinkerdInjectAnnotation, "enabled"))
+		Expect(annotations).To(HaveKeyWithValue(LinkerdProxyAwaitAnnotation, "enabled"))
+		Expect(annotations).To(HaveKeyWithValue(LinkerdNativeSidecarAnnotation, "true"))
+	})
+
+	It("should disable linkerd injection", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(false)}
+
+		deployment, err := newBuilder(ServiceMeshModeLinkerd).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		annotations := deployment.Spec.Template.Annotations
+		Expect(annotations).To(HaveKeyWithValue(LinkerdInjectAnnotation, "disabled"))
+		Expect(annotations).NotTo(HaveKey(LinkerdProxyAwaitAnnotation))
+	})
+
+	It("should keep mesh annotations set explicitly on the workspace", func() {
+		workspace.Annotations = map[string]string{
+			IstioProxyConfigAnnotation: `{"holdApplicationUntilProxyStarts":false}`,
+		}
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		annotations := deployment.Spec.Template.Annotations
+		Expect(annotations).To(HaveKeyWithValue(IstioSidecarInjectAnnotation, "true"))
+		Expect(annotations).To(HaveKeyWithValue(IstioProxyConfigAnnotation, `{"holdApplicationUntilProxyStarts":false}`))
+	})
+
+	It("should not add mesh annotations to the deployment metadata", func() {
+		workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{SidecarInjection: boolPtr(true)}
+
+		deployment, err := newBuilder(ServiceMeshModeIstio).BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deployment.Annotations).NotTo(HaveKey(IstioSidecarInjectAnnotation))
+	})
+})
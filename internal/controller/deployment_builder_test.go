@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
 )
 
 var _ = Describe("DeploymentBuilder", func() {
@@ -37,7 +38,7 @@ var _ = Describe("DeploymentBuilder", func() {
 			ApplicationImagesRegistry:   "quay.io",
 		}
 
-		deploymentBuilder = NewDeploymentBuilder(scheme, options, k8sClient)
+		deploymentBuilder = NewDeploymentBuilder(scheme, options)
 	})
 
 	// Note: Environment variables tests removed as they are now applied by webhooks
@@ -153,6 +154,37 @@ var _ = Describe("DeploymentBuilder", func() {
 		})
 	})
 
+	Context("Image Pull Policy", func() {
+		It("should use the controller pull policy by default", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-pull-policy",
+					Namespace: "default",
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+		})
+
+		It("should use the workspace pull policy when set", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-pull-policy",
+					Namespace: "default",
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					ImagePullPolicy: corev1.PullAlways,
+				},
+			}
+
+			deployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+		})
+	})
+
 	Context("Container Configuration", func() {
 		It("should set custom command and args", func() {
 			workspace := &workspacev1alpha1.Workspace{
@@ -566,9 +598,9 @@ var _ = Describe("DeploymentBuilder", func() {
 				},
 			}
 
-			// Create existing deployment
+			// Create existing deployment, rendered as the controller does
 			var err error
-			existingDeployment, err = deploymentBuilder.BuildDeployment(ctx, workspace)
+			existingDeployment, err = deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
 			Expect(err).NotTo(HaveOccurred())
 		})
 
@@ -691,14 +723,16 @@ var _ = Describe("DeploymentBuilder", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			// Deployment should have all workspace annotations
-			Expect(deployment.Annotations).To(HaveLen(4))
+			Expect(deployment.Annotations).To(HaveLen(5))
+			Expect(deployment.Annotations).To(HaveKeyWithValue(AnnotationManagedByVersion, buildinfo.Version))
 			Expect(deployment.Annotations["custom.io/annotation"]).To(Equal("value1"))
 			Expect(deployment.Annotations["another.io/annotation"]).To(Equal("value2"))
 			Expect(deployment.Annotations["prometheus.io/scrape"]).To(Equal("true"))
 			Expect(deployment.Annotations["prometheus.io/port"]).To(Equal("8080"))
 
 			// Pod template should have all workspace annotations
-			Expect(deployment.Spec.Template.Annotations).To(HaveLen(4))
+			Expect(deployment.Spec.Template.Annotations).To(HaveLen(5))
+			Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(AnnotationManagedByVersion, buildinfo.Version))
 			Expect(deployment.Spec.Template.Annotations["custom.io/annotation"]).To(Equal("value1"))
 			Expect(deployment.Spec.Template.Annotations["another.io/annotation"]).To(Equal("value2"))
 			Expect(deployment.Spec.Template.Annotations["prometheus.io/scrape"]).To(Equal("true"))
@@ -734,14 +768,34 @@ var _ = Describe("DeploymentBuilder", func() {
 			newDeployment, err := deploymentBuilder.BuildDeployment(ctx, workspace)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(newDeployment.Annotations).To(HaveLen(2))
+			Expect(newDeployment.Annotations).To(HaveLen(3))
 			Expect(newDeployment.Annotations["new-annotation"]).To(Equal("new-value"))
 			Expect(newDeployment.Annotations["initial-annotation"]).To(Equal("updated-value"))
 
-			Expect(newDeployment.Spec.Template.Annotations).To(HaveLen(2))
+			Expect(newDeployment.Spec.Template.Annotations).To(HaveLen(3))
 			Expect(newDeployment.Spec.Template.Annotations["new-annotation"]).To(Equal("new-value"))
 			Expect(newDeployment.Spec.Template.Annotations["initial-annotation"]).To(Equal("updated-value"))
 		})
+
+		It("should not trigger deployment update when only the operator version changed", func() {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-workspace-version-update",
+					Namespace: "default",
+				},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					DisplayName: "Test Workspace",
+				},
+			}
+
+			existingDeployment, err := deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+			Expect(err).NotTo(HaveOccurred())
+			existingDeployment.Spec.Template.Annotations[AnnotationManagedByVersion] = "v0.0.1"
+
+			needsUpdate, err := deploymentBuilder.NeedsUpdate(ctx, existingDeployment, workspace, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(needsUpdate).To(BeFalse(), "an operator upgrade alone should not restart workspaces")
+		})
 	})
 })
 
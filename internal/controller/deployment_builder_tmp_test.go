@@ -0,0 +1,91 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("DeploymentBuilder tmp volume", func() {
+	var (
+		ctx       context.Context
+		builder   *DeploymentBuilder
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		builder = NewDeploymentBuilder(scheme, WorkspaceControllerOptions{
+			ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		})
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace-tmp",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Tmp Workspace",
+			},
+		}
+	})
+
+	It("should not mount a tmp volume by default", func() {
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		for _, volume := range deployment.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).NotTo(Equal(TmpVolumeName))
+		}
+	})
+
+	It("should mount an emptyDir at /tmp bounded by the size limit", func() {
+		sizeLimit := resource.MustParse("2Gi")
+		workspace.Spec.TmpVolume = &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &sizeLimit}
+
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		podSpec := deployment.Spec.Template.Spec
+		Expect(podSpec.Volumes).To(ContainElement(corev1.Volume{
+			Name: TmpVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &sizeLimit},
+			},
+		}))
+		Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name:      TmpVolumeName,
+			MountPath: TmpMountPath,
+		}))
+	})
+
+	It("should skip workspace volumes that would shadow the tmp volume", func() {
+		workspace.Spec.TmpVolume = &workspacev1alpha1.TmpVolumeSpec{}
+		workspace.Spec.Volumes = []workspacev1alpha1.VolumeSpec{
+			{Name: "scratch", PersistentVolumeClaimName: "scratch", MountPath: TmpMountPath},
+			{Name: "data", PersistentVolumeClaimName: "data", MountPath: "/data"},
+		}
+
+		deployment, err := builder.BuildDeployment(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+
+		mounts := map[string]string{}
+		for _, mount := range deployment.Spec.Template.Spec.Containers[0].VolumeMounts {
+			mounts[mount.Name] = mount.MountPath
+		}
+		Expect(mounts).To(Equal(map[string]string{TmpVolumeName: TmpMountPath, "data": "/data"}))
+	})
+})
@@ -0,0 +1,92 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileTestPause runs one pause pass on the stored workspace and returns it
+func reconcileTestPause(t *testing.T, sm *StateMachine, k8sClient client.Client) *workspacev1alpha1.Workspace {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "ws", Namespace: "team-a"}, workspace))
+	_, err := sm.reconcileDesiredPausedStatus(context.Background(), workspace, workspace.Status.DeepCopy())
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), workspace))
+	return workspace
+}
+
+func TestPauseStopsComputeAndKeepsServiceAndAccess(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+	workspace.Spec.DesiredStatus = DesiredStatePaused
+	require.NoError(t, k8sClient.Update(ctx, workspace))
+
+	workspace = reconcileTestPause(t, sm, k8sClient)
+	paused := FindCondition(&workspace.Status.Conditions, ConditionTypePaused)
+	require.NotNil(t, paused)
+	assert.Equal(t, metav1.ConditionFalse, paused.Status)
+	assert.Equal(t, ReasonComputeNotStopped, paused.Reason)
+	assert.Equal(t, WorkspacePhaseStopping, GetWorkspacePhase(workspace))
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: GenerateDeploymentName("ws"), Namespace: "team-a"}, &appsv1.Deployment{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	workspace = reconcileTestPause(t, sm, k8sClient)
+	paused = FindCondition(&workspace.Status.Conditions, ConditionTypePaused)
+	require.NotNil(t, paused)
+	assert.Equal(t, metav1.ConditionTrue, paused.Status)
+	assert.Equal(t, ReasonComputePaused, paused.Reason)
+	assert.Equal(t, WorkspacePhaseStopped, GetWorkspacePhase(workspace))
+	blockedReason, _ := GetWorkspaceBlockedReason(workspace)
+	assert.Empty(t, blockedReason)
+
+	// The service and the access URL stay for the workspace to resume at the same URL
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateServiceName("ws"), Namespace: "team-a"}, &corev1.Service{}))
+	assert.NotEmpty(t, workspace.Status.AccessURL)
+	assert.Empty(t, workspace.Status.DeploymentName)
+	stopped := FindCondition(&workspace.Status.Conditions, ConditionTypeStopped)
+	require.NotNil(t, stopped)
+	assert.Equal(t, metav1.ConditionFalse, stopped.Status)
+}
+
+func TestResumeFromPauseClearsThePausedCondition(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+	workspace.Spec.DesiredStatus = DesiredStatePaused
+	require.NoError(t, k8sClient.Update(ctx, workspace))
+	reconcileTestPause(t, sm, k8sClient)
+	workspace = reconcileTestPause(t, sm, k8sClient)
+
+	snapshotStatus := workspace.DeepCopy().Status
+	require.NoError(t, sm.statusManager.UpdateStartingStatus(ctx, workspace, WorkspaceRunningReadiness{}, &snapshotStatus))
+	paused := FindCondition(&workspace.Status.Conditions, ConditionTypePaused)
+	require.NotNil(t, paused)
+	assert.Equal(t, metav1.ConditionFalse, paused.Status)
+	assert.Equal(t, ReasonDesiredStateRunning, paused.Reason)
+}
+
+func TestWorkspacesThatNeverPausedHaveNoPausedCondition(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+
+	workspace, _ := reconcileDrainTestStop(t, sm, k8sClient)
+	snapshotStatus := workspace.DeepCopy().Status
+	require.NoError(t, sm.statusManager.UpdateStartingStatus(ctx, workspace, WorkspaceRunningReadiness{}, &snapshotStatus))
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypePaused))
+}
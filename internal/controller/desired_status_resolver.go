@@ -0,0 +1,206 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// IntentActorMaintenance identifies an administrator maintenance intent
+	IntentActorMaintenance = "Maintenance"
+	// IntentActorUser identifies a manual desiredStatus change by a user
+	IntentActorUser = "User"
+	// IntentActorSchedule identifies a scheduled start/stop intent
+	IntentActorSchedule = "Schedule"
+	// IntentActorCuller identifies an idle culling intent
+	IntentActorCuller = "Culler"
+	// IntentActorDefault identifies the fallback when no actor expressed an intent
+	IntentActorDefault = "Default"
+)
+
+// DesiredStatusIntentRecord is the value stored in the maintenance, schedule and culler intent annotations
+type DesiredStatusIntentRecord struct {
+	// DesiredStatus is the requested desired status (Running, Stopped, Paused or Hibernated)
+	DesiredStatus string `json:"desiredStatus"`
+
+	// SetAt is when the intent was recorded
+	SetAt *metav1.Time `json:"setAt,omitempty"`
+
+	// ExpiresAt is when the intent stops applying; the intent is unbounded when unset
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// EncodeDesiredStatusIntent serializes an intent record into its annotation value
+func EncodeDesiredStatusIntent(record DesiredStatusIntentRecord) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode desired status intent: %w", err)
+	}
+	return string(data), nil
+}
+
+// intentDesiredStatuses lists the desired statuses an intent may request
+var intentDesiredStatuses = []string{DesiredStateRunning, DesiredStateStopped, DesiredStatePaused, DesiredStateHibernated}
+
+// parseDesiredStatusIntent parses an intent annotation value; malformed values and unknown desired statuses
+// are ignored
+func parseDesiredStatusIntent(value string) (*DesiredStatusIntentRecord, bool) {
+	if value == "" {
+		return nil, false
+	}
+	record := &DesiredStatusIntentRecord{}
+	if err := json.Unmarshal([]byte(value), record); err != nil {
+		return nil, false
+	}
+	if !slices.Contains(intentDesiredStatuses, record.DesiredStatus) {
+		return nil, false
+	}
+	return record, true
+}
+
+// isExpired returns true if the record has an expiry at or before now
+func (r *DesiredStatusIntentRecord) isExpired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(r.ExpiresAt.Time)
+}
+
+// DesiredStatusResolution is the outcome of resolving competing desired status intents
+type DesiredStatusResolution struct {
+	// Intent is the winning intent
+	Intent workspacev1alpha1.DesiredStatusIntent
+
+	// NextTransition is the earliest time at which an active intent lapses and the
+	// resolution may change; zero when no active intent is time-bounded
+	NextTransition time.Time
+}
+
+// DesiredStatusResolver resolves the effective desired status of a workspace from the intents
+// recorded by each actor. Precedence is: admin maintenance > user manual > schedule > culler.
+// A manual user change is only considered an active intent during the user cooldown; afterwards
+// spec.desiredStatus still applies, but lower-precedence actors may override it. A schedule intent
+// recorded before the last manual change no longer applies.
+type DesiredStatusResolver struct {
+	userCooldown time.Duration
+	now          func() time.Time
+}
+
+// NewDesiredStatusResolver creates a new DesiredStatusResolver
+func NewDesiredStatusResolver(userCooldown time.Duration) *DesiredStatusResolver {
+	return &DesiredStatusResolver{
+		userCooldown: userCooldown,
+		now:          time.Now,
+	}
+}
+
+// Resolve returns the winning desired status intent for the workspace
+func (r *DesiredStatusResolver) Resolve(workspace *workspacev1alpha1.Workspace) DesiredStatusResolution {
+	return r.resolveAt(workspace, r.now())
+}
+
+// CullerMayStop returns true if an idle culling intent recorded now would win resolution,
+// i.e. no higher-precedence actor currently wants to keep the workspace running
+func (r *DesiredStatusResolver) CullerMayStop(workspace *workspacev1alpha1.Workspace) bool {
+	now := r.now()
+	candidate := workspace.DeepCopy()
+	candidate.Spec.DesiredStatus = DesiredStateStopped
+	value, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+		DesiredStatus: DesiredStateStopped,
+		SetAt:         &metav1.Time{Time: now},
+	})
+	if err != nil {
+		return false
+	}
+	if candidate.Annotations == nil {
+		candidate.Annotations = map[string]string{}
+	}
+	candidate.Annotations[AnnotationCullerIntent] = value
+	return r.resolveAt(candidate, now).Intent.Actor == IntentActorCuller
+}
+
+func (r *DesiredStatusResolver) resolveAt(workspace *workspacev1alpha1.Workspace, now time.Time) DesiredStatusResolution {
+	annotations := workspace.Annotations
+	userSetAt, hasUserSetAt := r.userSetAt(workspace)
+
+	// Admin maintenance
+	if record, ok := parseDesiredStatusIntent(annotations[AnnotationMaintenanceIntent]); ok && !record.isExpired(now) {
+		return newResolution(IntentActorMaintenance, record.DesiredStatus, record.SetAt, record.ExpiresAt)
+	}
+
+	// User manual change within the cooldown
+	if hasUserSetAt {
+		cooldownEnd := userSetAt.Add(r.userCooldown)
+		if now.Before(cooldownEnd) {
+			return newResolution(IntentActorUser, specDesiredStatus(workspace),
+				&metav1.Time{Time: userSetAt}, &metav1.Time{Time: cooldownEnd})
+		}
+	}
+
+	// Schedule, unless a user changed spec.desiredStatus since the scheduled start or stop: the manual
+	// change then wins until the next one
+	if record, ok := parseDesiredStatusIntent(annotations[AnnotationScheduleIntent]); ok && !record.isExpired(now) &&
+		(!hasUserSetAt || record.SetAt == nil || !userSetAt.After(record.SetAt.Time)) {
+		return newResolution(IntentActorSchedule, record.DesiredStatus, record.SetAt, record.ExpiresAt)
+	}
+
+	// Culler, as long as it has not been superseded by a later user change
+	if record, ok := parseDesiredStatusIntent(annotations[AnnotationCullerIntent]); ok && !record.isExpired(now) &&
+		workspace.Spec.DesiredStatus == record.DesiredStatus &&
+		(!hasUserSetAt || (record.SetAt != nil && record.SetAt.After(userSetAt))) {
+		return newResolution(IntentActorCuller, record.DesiredStatus, record.SetAt, record.ExpiresAt)
+	}
+
+	// Fall back to the spec
+	if workspace.Spec.DesiredStatus != "" {
+		var setAt *metav1.Time
+		if hasUserSetAt {
+			setAt = &metav1.Time{Time: userSetAt}
+		}
+		return newResolution(IntentActorUser, workspace.Spec.DesiredStatus, setAt, nil)
+	}
+	return newResolution(IntentActorDefault, DefaultDesiredStatus, nil, nil)
+}
+
+// specDesiredStatus returns spec.desiredStatus with default fallback
+func specDesiredStatus(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Spec.DesiredStatus == "" {
+		return DefaultDesiredStatus
+	}
+	return workspace.Spec.DesiredStatus
+}
+
+// userSetAt returns the time of the last manual desiredStatus change
+func (r *DesiredStatusResolver) userSetAt(workspace *workspacev1alpha1.Workspace) (time.Time, bool) {
+	value := workspace.Annotations[AnnotationDesiredStatusSetAt]
+	if value == "" {
+		return time.Time{}, false
+	}
+	setAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return setAt, true
+}
+
+func newResolution(actor, desiredStatus string, setAt, expiresAt *metav1.Time) DesiredStatusResolution {
+	resolution := DesiredStatusResolution{
+		Intent: workspacev1alpha1.DesiredStatusIntent{
+			Actor:         actor,
+			DesiredStatus: desiredStatus,
+			SetAt:         setAt,
+			ExpiresAt:     expiresAt,
+		},
+	}
+	if expiresAt != nil {
+		resolution.NextTransition = expiresAt.Time
+	}
+	return resolution
+}
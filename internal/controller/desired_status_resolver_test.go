@@ -0,0 +1,354 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+var resolverTestNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestResolver() *DesiredStatusResolver {
+	resolver := NewDesiredStatusResolver(30 * time.Minute)
+	resolver.now = func() time.Time { return resolverTestNow }
+	return resolver
+}
+
+func intentAnnotation(t *testing.T, desiredStatus string, setAt, expiresAt time.Duration) string {
+	t.Helper()
+	record := DesiredStatusIntentRecord{DesiredStatus: desiredStatus}
+	if setAt != 0 {
+		record.SetAt = &metav1.Time{Time: resolverTestNow.Add(setAt)}
+	}
+	if expiresAt != 0 {
+		record.ExpiresAt = &metav1.Time{Time: resolverTestNow.Add(expiresAt)}
+	}
+	value, err := EncodeDesiredStatusIntent(record)
+	require.NoError(t, err)
+	return value
+}
+
+func userStamp(offset time.Duration) string {
+	return resolverTestNow.Add(offset).Format(time.RFC3339)
+}
+
+func TestDesiredStatusResolverConflictMatrix(t *testing.T) {
+	tests := []struct {
+		name          string
+		specStatus    string
+		annotations   func(t *testing.T) map[string]string
+		expectActor   string
+		expectStatus  string
+		expectNextSet bool
+	}{
+		{
+			name:         "no intents defaults to running",
+			annotations:  func(t *testing.T) map[string]string { return nil },
+			expectActor:  IntentActorDefault,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:         "spec without stamp is a user intent",
+			specStatus:   DesiredStateStopped,
+			annotations:  func(t *testing.T) map[string]string { return nil },
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateStopped,
+		},
+		{
+			name:       "maintenance beats user within cooldown",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent:  intentAnnotation(t, DesiredStateStopped, -time.Hour, time.Hour),
+					AnnotationDesiredStatusSetAt: userStamp(-time.Minute),
+				}
+			},
+			expectActor:   IntentActorMaintenance,
+			expectStatus:  DesiredStateStopped,
+			expectNextSet: true,
+		},
+		{
+			name:       "expired maintenance is ignored",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent: intentAnnotation(t, DesiredStateStopped, -2*time.Hour, -time.Hour),
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:       "maintenance beats schedule",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent: intentAnnotation(t, DesiredStateStopped, 0, 0),
+					AnnotationScheduleIntent:    intentAnnotation(t, DesiredStateRunning, 0, time.Hour),
+				}
+			},
+			expectActor:  IntentActorMaintenance,
+			expectStatus: DesiredStateStopped,
+		},
+		{
+			name:       "maintenance beats culler",
+			specStatus: DesiredStateStopped,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent: intentAnnotation(t, DesiredStateRunning, 0, time.Hour),
+					AnnotationCullerIntent:      intentAnnotation(t, DesiredStateStopped, -time.Minute, 0),
+				}
+			},
+			expectActor:   IntentActorMaintenance,
+			expectStatus:  DesiredStateRunning,
+			expectNextSet: true,
+		},
+		{
+			name:       "user within cooldown beats schedule",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent:     intentAnnotation(t, DesiredStateStopped, -time.Hour, time.Hour),
+					AnnotationDesiredStatusSetAt: userStamp(-10 * time.Minute),
+				}
+			},
+			expectActor:   IntentActorUser,
+			expectStatus:  DesiredStateRunning,
+			expectNextSet: true,
+		},
+		{
+			name:       "schedule beats user after cooldown",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent:     intentAnnotation(t, DesiredStateStopped, -time.Hour, time.Hour),
+					AnnotationDesiredStatusSetAt: userStamp(-2 * time.Hour),
+				}
+			},
+			expectActor:   IntentActorSchedule,
+			expectStatus:  DesiredStateStopped,
+			expectNextSet: true,
+		},
+		{
+			name:       "user change after the scheduled stop beats schedule until the next boundary",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent:     intentAnnotation(t, DesiredStateStopped, -2*time.Hour, time.Hour),
+					AnnotationDesiredStatusSetAt: userStamp(-time.Hour),
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:       "expired schedule falls back to spec",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent: intentAnnotation(t, DesiredStateStopped, -2*time.Hour, -time.Hour),
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:       "schedule beats culler",
+			specStatus: DesiredStateStopped,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent: intentAnnotation(t, DesiredStateRunning, -time.Hour, time.Hour),
+					AnnotationCullerIntent:   intentAnnotation(t, DesiredStateStopped, -time.Minute, 0),
+				}
+			},
+			expectActor:   IntentActorSchedule,
+			expectStatus:  DesiredStateRunning,
+			expectNextSet: true,
+		},
+		{
+			name:       "culler newer than user stamp wins over spec fallback",
+			specStatus: DesiredStateStopped,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationCullerIntent:       intentAnnotation(t, DesiredStateStopped, -time.Minute, 0),
+					AnnotationDesiredStatusSetAt: userStamp(-2 * time.Hour),
+				}
+			},
+			expectActor:  IntentActorCuller,
+			expectStatus: DesiredStateStopped,
+		},
+		{
+			name:       "user restart supersedes older culler intent",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationCullerIntent:       intentAnnotation(t, DesiredStateStopped, -2*time.Hour, 0),
+					AnnotationDesiredStatusSetAt: userStamp(-time.Hour),
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:       "culler intent ignored once spec no longer matches",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationCullerIntent: intentAnnotation(t, DesiredStateStopped, -time.Minute, 0),
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+		{
+			name:       "schedule intent may pause",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent: intentAnnotation(t, DesiredStatePaused, -time.Minute, 0),
+				}
+			},
+			expectActor:  IntentActorSchedule,
+			expectStatus: DesiredStatePaused,
+		},
+		{
+			name:       "maintenance intent may hibernate",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent: intentAnnotation(t, DesiredStateHibernated, -time.Minute, time.Hour),
+				}
+			},
+			expectActor:   IntentActorMaintenance,
+			expectStatus:  DesiredStateHibernated,
+			expectNextSet: true,
+		},
+		{
+			name:       "malformed intents are ignored",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent:  "not-json",
+					AnnotationScheduleIntent:     `{"desiredStatus":"Sleeping"}`,
+					AnnotationDesiredStatusSetAt: "yesterday",
+				}
+			},
+			expectActor:  IntentActorUser,
+			expectStatus: DesiredStateRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations(t)},
+				Spec:       workspacev1alpha1.WorkspaceSpec{DesiredStatus: tt.specStatus},
+			}
+
+			resolution := newTestResolver().Resolve(workspace)
+
+			assert.Equal(t, tt.expectActor, resolution.Intent.Actor)
+			assert.Equal(t, tt.expectStatus, resolution.Intent.DesiredStatus)
+			assert.Equal(t, tt.expectNextSet, !resolution.NextTransition.IsZero())
+		})
+	}
+}
+
+func TestDesiredStatusResolverCullerMayStop(t *testing.T) {
+	tests := []struct {
+		name        string
+		specStatus  string
+		annotations func(t *testing.T) map[string]string
+		expect      bool
+	}{
+		{
+			name:        "no competing intents",
+			specStatus:  DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string { return nil },
+			expect:      true,
+		},
+		{
+			name:       "user change outside cooldown",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{AnnotationDesiredStatusSetAt: userStamp(-time.Hour)}
+			},
+			expect: true,
+		},
+		{
+			name:       "user change within cooldown suppresses culler",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{AnnotationDesiredStatusSetAt: userStamp(-5 * time.Minute)}
+			},
+			expect: false,
+		},
+		{
+			name:       "running schedule suppresses culler",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent: intentAnnotation(t, DesiredStateRunning, -time.Hour, time.Hour),
+				}
+			},
+			expect: false,
+		},
+		{
+			name:       "running maintenance suppresses culler",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationMaintenanceIntent: intentAnnotation(t, DesiredStateRunning, 0, time.Hour),
+				}
+			},
+			expect: false,
+		},
+		{
+			name:       "expired schedule does not suppress culler",
+			specStatus: DesiredStateRunning,
+			annotations: func(t *testing.T) map[string]string {
+				return map[string]string{
+					AnnotationScheduleIntent: intentAnnotation(t, DesiredStateRunning, -2*time.Hour, -time.Hour),
+				}
+			},
+			expect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations(t)},
+				Spec:       workspacev1alpha1.WorkspaceSpec{DesiredStatus: tt.specStatus},
+			}
+
+			assert.Equal(t, tt.expect, newTestResolver().CullerMayStop(workspace))
+		})
+	}
+}
+
+func TestRequeueAtIntentTransition(t *testing.T) {
+	soon := DesiredStatusResolution{NextTransition: time.Now().Add(time.Minute)}
+
+	result := requeueAtIntentTransition(ctrl.Result{}, nil, soon)
+	assert.InDelta(t, time.Minute.Seconds(), result.RequeueAfter.Seconds(), 1)
+
+	result = requeueAtIntentTransition(ctrl.Result{RequeueAfter: time.Second}, nil, soon)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+
+	result = requeueAtIntentTransition(ctrl.Result{RequeueAfter: time.Hour}, nil, soon)
+	assert.InDelta(t, time.Minute.Seconds(), result.RequeueAfter.Seconds(), 1)
+
+	result = requeueAtIntentTransition(ctrl.Result{}, nil, DesiredStatusResolution{})
+	assert.Zero(t, result.RequeueAfter)
+}
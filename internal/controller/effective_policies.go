@@ -0,0 +1,76 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reconcileEffectivePolicies reports the lifecycle policies that govern the workspace in
+// Status.EffectivePolicies, so that users who may not read its template or namespace know them.
+// The template is the revision the workspace resolves: workspaces following their template report the
+// changes of its defaults, pinned ones keep the defaults of the generation they were admitted against.
+// The maximum lifetime comes from the template only: it is kept while the template cannot be resolved,
+// so that the controller keeps enforcing it. The status is updated in memory.
+func (sm *StateMachine) reconcileEffectivePolicies(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	var template *workspacev1alpha1.WorkspaceTemplate
+	resolved := true
+	templateResolver := sm.resourceManager.templateResolver
+	if templateResolver != nil && workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		revision, err := templateResolver.ResolveTemplateRevision(ctx, workspace)
+		if resolved = err == nil; resolved {
+			template = revision
+		} else if !apierrors.IsNotFound(err) {
+			logf.FromContext(ctx).Error(err, "Failed to resolve the template, reporting the workspace policies")
+		}
+	}
+
+	policies := workspaceutil.ResolvePolicies(workspace, template)
+	if !resolved && workspace.Status.EffectivePolicies != nil && workspace.Status.EffectivePolicies.MaxLifetime != nil {
+		if policies == nil {
+			policies = &workspacev1alpha1.EffectivePolicies{}
+		}
+		policies.MaxLifetime = workspace.Status.EffectivePolicies.MaxLifetime
+	}
+	if policies != nil && policies.DeleteAfterStopped != nil {
+		policies.DeleteAfterStopped.Exempt = workspace.Spec.DeletionProtection || workspace.Labels[LabelRetain] == "true"
+	}
+	if policies != nil && policies.MaxLifetime != nil {
+		policies.MaxLifetime.Exempt = workspace.Annotations[AnnotationMaxLifetimeExempt] == "true"
+	}
+	if retention := sm.retentionPolicyFor(ctx, workspace); retention != nil {
+		if policies == nil {
+			policies = &workspacev1alpha1.EffectivePolicies{}
+		}
+		policies.Retention = retention
+	}
+	workspace.Status.EffectivePolicies = policies
+}
+
+// retentionPolicyFor returns the stale workspace policy that applies to the workspace, nil when disabled
+func (sm *StateMachine) retentionPolicyFor(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.EffectiveRetentionPolicy {
+	policy := sm.staleWorkspacePolicyFor(ctx, workspace.Namespace)
+	if !policy.Enabled() {
+		return nil
+	}
+	source := workspacev1alpha1.PolicySourceOperator
+	if policy != sm.stalePolicy {
+		source = workspacev1alpha1.PolicySourceNamespace
+	}
+	return &workspacev1alpha1.EffectiveRetentionPolicy{
+		StaleAfter:         metav1.Duration{Duration: policy.Threshold},
+		ArchiveGracePeriod: metav1.Duration{Duration: policy.GracePeriod},
+		Exempt:             workspace.Annotations[AnnotationStaleExempt] == "true",
+		Source:             source,
+	}
+}
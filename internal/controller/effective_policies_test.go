@@ -0,0 +1,127 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newPoliciesTestTemplate(generation int64, idleTimeout int) *workspacev1alpha1.WorkspaceTemplate {
+	template := newDriftTestTemplate(generation, "jupyter/base-notebook:2025.01")
+	template.Spec.DefaultIdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: idleTimeout}
+	template.Spec.DefaultSchedule = &workspacev1alpha1.ScheduleSpec{StopCron: "0 20 * * *"}
+	template.Spec.Retention = &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: ptr.To[int64](30 * 86400)}
+	return template
+}
+
+// newPoliciesTestWorkspace returns a workspace admitted against the first generation of the template
+func newPoliciesTestWorkspace(policy workspacev1alpha1.TemplateUpdatePolicy) *workspacev1alpha1.Workspace {
+	workspace := newDriftTestWorkspace(policy, "1")
+	admitted := newPoliciesTestTemplate(1, 60)
+	workspace.Spec.IdleShutdown = admitted.Spec.DefaultIdleShutdown.DeepCopy()
+	workspace.Spec.Schedule = admitted.Spec.DefaultSchedule.DeepCopy()
+	workspace.Spec.Retention = admitted.Spec.Retention.DeepCopy()
+	return workspace
+}
+
+func TestEffectivePoliciesAttributeTemplateDefaults(t *testing.T) {
+	ctx := context.Background()
+	workspace := newPoliciesTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyFollow)
+	workspace.Spec.Schedule.TimeZone = "Europe/Paris"
+	workspace.Labels = map[string]string{LabelRetain: "true"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newPoliciesTestTemplate(1, 60))
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	policies := workspace.Status.EffectivePolicies
+	require.NotNil(t, policies)
+	require.NotNil(t, policies.IdleShutdown)
+	assert.Equal(t, 60, policies.IdleShutdown.IdleTimeoutInMinutes)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.IdleShutdown.Source)
+	require.NotNil(t, policies.Schedule)
+	assert.Equal(t, "Europe/Paris", policies.Schedule.TimeZone)
+	assert.Equal(t, workspacev1alpha1.PolicySourceWorkspace, policies.Schedule.Source)
+	assert.Nil(t, policies.Retention, "the operator has no stale workspace policy")
+	require.NotNil(t, policies.DeleteAfterStopped)
+	assert.Equal(t, 30*day, policies.DeleteAfterStopped.After.Duration)
+	assert.True(t, policies.DeleteAfterStopped.Exempt, "the workspace is labeled retained")
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.DeleteAfterStopped.Source)
+}
+
+func TestEffectivePoliciesFollowTheTemplateUpdatePolicy(t *testing.T) {
+	ctx := context.Background()
+	admitted := newPoliciesTestTemplate(1, 60)
+	revision, err := workspaceutil.NewTemplateRevision(admitted)
+	require.NoError(t, err)
+	template := newPoliciesTestTemplate(2, 30)
+	pinned := newPoliciesTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyPin)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, pinned, template, revision)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	// The pinned workspace keeps the defaults of the generation it was admitted against
+	sm.reconcileEffectivePolicies(ctx, pinned)
+	require.NotNil(t, pinned.Status.EffectivePolicies)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, pinned.Status.EffectivePolicies.IdleShutdown.Source)
+
+	// The webhook admits the following workspace against the new generation
+	following := newPoliciesTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyFollow)
+	following.Annotations[AnnotationTemplateGeneration] = "2"
+	following.Spec.IdleShutdown.IdleTimeoutInMinutes = 30
+	sm.reconcileEffectivePolicies(ctx, following)
+	require.NotNil(t, following.Status.EffectivePolicies)
+	assert.Equal(t, 30, following.Status.EffectivePolicies.IdleShutdown.IdleTimeoutInMinutes)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, following.Status.EffectivePolicies.IdleShutdown.Source)
+}
+
+func TestEffectivePoliciesReportTheRetentionPolicy(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.stalePolicy = StaleWorkspacePolicy{Threshold: 7 * day, GracePeriod: 3 * day}
+
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	policies := workspace.Status.EffectivePolicies
+	require.NotNil(t, policies)
+	assert.Nil(t, policies.IdleShutdown)
+	assert.Nil(t, policies.Schedule)
+	require.NotNil(t, policies.Retention)
+	assert.Equal(t, metav1.Duration{Duration: 7 * day}, policies.Retention.StaleAfter)
+	assert.Equal(t, metav1.Duration{Duration: 3 * day}, policies.Retention.ArchiveGracePeriod)
+	assert.False(t, policies.Retention.Exempt)
+	assert.Equal(t, workspacev1alpha1.PolicySourceOperator, policies.Retention.Source)
+
+	// The namespace overrides the operator policy
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{AnnotationStaleAfterDays: "30"},
+	}}
+	require.NoError(t, k8sClient.Create(ctx, namespace))
+	workspace.Annotations = map[string]string{AnnotationStaleExempt: "true"}
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	retention := workspace.Status.EffectivePolicies.Retention
+	require.NotNil(t, retention)
+	assert.Equal(t, metav1.Duration{Duration: 30 * day}, retention.StaleAfter)
+	assert.True(t, retention.Exempt)
+	assert.Equal(t, workspacev1alpha1.PolicySourceNamespace, retention.Source)
+
+	// Disabled by the namespace
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace))
+	namespace.Annotations[AnnotationStaleAfterDays] = "0"
+	require.NoError(t, k8sClient.Update(ctx, namespace))
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	assert.Nil(t, workspace.Status.EffectivePolicies)
+}
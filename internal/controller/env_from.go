@@ -0,0 +1,318 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// EnvFromSourceMissingError reports that a required envFrom source of the workspace does not exist
+type EnvFromSourceMissingError struct {
+	Source workspaceutil.EnvFromSourceRef
+}
+
+func (e *EnvFromSourceMissingError) Error() string {
+	return fmt.Sprintf("%s referenced by envFrom does not exist", e.Source)
+}
+
+// asEnvFromSourceMissing returns the missing source error wrapped in err, if any
+func asEnvFromSourceMissing(err error) (*EnvFromSourceMissingError, bool) {
+	var missing *EnvFromSourceMissingError
+	if errors.As(err, &missing) {
+		return missing, true
+	}
+	return nil, false
+}
+
+// GenerateEnvFromMirrorName creates a consistent name for the copy of an envFrom source of another namespace
+func GenerateEnvFromMirrorName(workspace *workspacev1alpha1.Workspace, source workspaceutil.EnvFromSourceRef) string {
+	return fmt.Sprintf("%s-%s-envfrom.%s.%s", childNamePrefixFor(workspace), workspace.Name, source.Namespace, source.Name)
+}
+
+// buildContainerEnvFrom returns the envFrom of the workspace container; sources of other
+// namespaces are replaced by their copy in the workspace namespace
+func buildContainerEnvFrom(workspace *workspacev1alpha1.Workspace) []corev1.EnvFromSource {
+	if len(workspace.Spec.EnvFrom) == 0 {
+		return nil
+	}
+
+	envFrom := make([]corev1.EnvFromSource, 0, len(workspace.Spec.EnvFrom))
+	for _, source := range workspace.Spec.EnvFrom {
+		entry := *source.EnvFromSource.DeepCopy()
+		if ref := workspaceutil.ResolveEnvFromSource(workspace, source); ref.Mirrored(workspace.Namespace) {
+			mirrorName := GenerateEnvFromMirrorName(workspace, ref)
+			if entry.ConfigMapRef != nil {
+				entry.ConfigMapRef.Name = mirrorName
+			}
+			if entry.SecretRef != nil {
+				entry.SecretRef.Name = mirrorName
+			}
+		}
+		envFrom = append(envFrom, entry)
+	}
+	return envFrom
+}
+
+// newEnvFromObject returns an empty object of the kind of an envFrom source
+func newEnvFromObject(kind string) client.Object {
+	if kind == workspaceutil.EnvFromKindSecret {
+		return &corev1.Secret{}
+	}
+	return &corev1.ConfigMap{}
+}
+
+// copyEnvFromData copies the keys of src into dst and returns true if dst changed
+func copyEnvFromData(dst, src client.Object) bool {
+	switch source := src.(type) {
+	case *corev1.ConfigMap:
+		target := dst.(*corev1.ConfigMap)
+		if equality.Semantic.DeepEqual(target.Data, source.Data) &&
+			equality.Semantic.DeepEqual(target.BinaryData, source.BinaryData) {
+			return false
+		}
+		target.Data = maps.Clone(source.Data)
+		target.BinaryData = maps.Clone(source.BinaryData)
+		return true
+	case *corev1.Secret:
+		target := dst.(*corev1.Secret)
+		if equality.Semantic.DeepEqual(target.Data, source.Data) && target.Type == source.Type {
+			return false
+		}
+		target.Data = maps.Clone(source.Data)
+		target.Type = source.Type
+		return true
+	}
+	return false
+}
+
+// EnsureEnvFromSources checks that the required envFrom sources of the workspace exist before its pod
+// references them, and keeps the copies of sources of other namespaces in sync with their source.
+// Copies the workspace no longer references are deleted. Status.EnvFromMirrors and
+// Status.EnvFromSecretsChecksum are updated in memory.
+func (rm *ResourceManager) EnsureEnvFromSources(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	var mirrors []workspacev1alpha1.EnvFromMirrorStatus
+	secrets := map[workspaceutil.EnvFromSourceRef]*corev1.Secret{}
+	for _, source := range workspace.Spec.EnvFrom {
+		ref := workspaceutil.ResolveEnvFromSource(workspace, source)
+		if ref.Mirrored(workspace.Namespace) {
+			mirror, object, err := rm.ensureEnvFromMirror(ctx, workspace, ref)
+			if err != nil {
+				return err
+			}
+			if mirror != nil {
+				mirrors = append(mirrors, *mirror)
+			}
+			if secret, ok := object.(*corev1.Secret); ok {
+				secrets[ref] = secret
+			}
+			continue
+		}
+		// Secrets are read even when optional, to detect their rotation
+		if ref.Optional && ref.Kind != workspaceutil.EnvFromKindSecret {
+			continue
+		}
+		object := newEnvFromObject(ref.Kind)
+		err := rm.reader().Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, object)
+		if apierrors.IsNotFound(err) {
+			if ref.Optional {
+				continue
+			}
+			return &EnvFromSourceMissingError{Source: ref}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get envFrom %s: %w", ref, err)
+		}
+		if secret, ok := object.(*corev1.Secret); ok {
+			secrets[ref] = secret
+		}
+	}
+
+	for _, previous := range workspace.Status.EnvFromMirrors {
+		if containsEnvFromMirror(mirrors, previous) {
+			continue
+		}
+		if err := rm.deleteEnvFromMirror(ctx, workspace, previous.Kind, previous.Name); err != nil {
+			return err
+		}
+	}
+	workspace.Status.EnvFromMirrors = mirrors
+	workspace.Status.EnvFromSecretsChecksum = envFromSecretsChecksum(secrets)
+	return nil
+}
+
+// envFromSecretsChecksum fingerprints the data of the Secrets exposed through envFrom, or returns
+// an empty string when the workspace exposes none
+func envFromSecretsChecksum(secrets map[workspaceutil.EnvFromSourceRef]*corev1.Secret) string {
+	if len(secrets) == 0 {
+		return ""
+	}
+	refs := slices.SortedFunc(maps.Keys(secrets), func(a, b workspaceutil.EnvFromSourceRef) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	hash := sha256.New()
+	for _, ref := range refs {
+		data := secrets[ref].Data
+		fmt.Fprintf(hash, "%s/%s\x00", ref.Namespace, ref.Name)
+		for _, key := range slices.Sorted(maps.Keys(data)) {
+			fmt.Fprintf(hash, "%s\x00%d\x00", key, len(data[key]))
+			hash.Write(data[key])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// ensureEnvFromMirror creates or updates the copy of a source of another namespace and returns the
+// source. It returns nil without error when an optional source does not exist, after deleting a
+// stale copy.
+func (rm *ResourceManager) ensureEnvFromMirror(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	ref workspaceutil.EnvFromSourceRef) (*workspacev1alpha1.EnvFromMirrorStatus, client.Object, error) {
+	logger := logf.FromContext(ctx)
+	mirrorName := GenerateEnvFromMirrorName(workspace, ref)
+
+	source := newEnvFromObject(ref.Kind)
+	if err := rm.reader().Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, source); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, nil, fmt.Errorf("failed to get envFrom %s: %w", ref, err)
+		}
+		if !ref.Optional {
+			return nil, nil, &EnvFromSourceMissingError{Source: ref}
+		}
+		return nil, nil, rm.deleteEnvFromMirror(ctx, workspace, ref.Kind, mirrorName)
+	}
+
+	desired := newEnvFromObject(ref.Kind)
+	desired.SetName(mirrorName)
+	desired.SetNamespace(workspace.Namespace)
+	desired.SetLabels(GenerateLabels(workspace.Name))
+	annotations := GenerateAnnotations()
+	annotations[AnnotationEnvFromSource] = ref.Namespace + "/" + ref.Name
+	desired.SetAnnotations(annotations)
+	copyEnvFromData(desired, source)
+	if ref.Kind == workspaceutil.EnvFromKindSecret {
+		applyChildMetadata(desired, childMetadataOf(workspace).Secret)
+	}
+	if err := controllerutil.SetControllerReference(workspace, desired, rm.scheme); err != nil {
+		return nil, nil, fmt.Errorf("failed to set controller reference on %s %s: %w", ref.Kind, mirrorName, err)
+	}
+
+	mirror := &workspacev1alpha1.EnvFromMirrorStatus{
+		Kind:            ref.Kind,
+		Name:            mirrorName,
+		SourceNamespace: ref.Namespace,
+		SourceName:      ref.Name,
+	}
+
+	existing := newEnvFromObject(ref.Kind)
+	err := rm.reader().Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		logger.Info("Mirroring envFrom source", "source", ref.String(), "mirror", mirrorName)
+		err = rm.client.Create(ctx, desired)
+		if err == nil {
+			return mirror, source, nil
+		}
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, nil, fmt.Errorf("failed to create copy of envFrom %s: %w", ref, err)
+		}
+		// Another reconcile created the copy concurrently
+		if err := rm.adoptExistingChild(ctx, workspace, ref.Kind, desired, existing); err != nil {
+			return nil, nil, err
+		}
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to get copy of envFrom %s: %w", ref, err)
+	}
+
+	ownership, controllerRef := classifyChild(workspace, existing)
+	if ownership == childForeign {
+		return nil, nil, newChildResourceConflict(ref.Kind, existing, controllerRef)
+	}
+	changed := copyEnvFromData(existing, source)
+	if ref.Kind == workspaceutil.EnvFromKindSecret && !childMetadataInSync(existing, desired, childMetadataOf(workspace).Secret) {
+		syncChildMetadata(existing, desired, childMetadataOf(workspace).Secret)
+		changed = true
+	}
+	if ownership == childAdoptable {
+		if err := controllerutil.SetControllerReference(workspace, existing, rm.scheme); err != nil {
+			return nil, nil, fmt.Errorf("failed to set controller reference on %s %s: %w", ref.Kind, mirrorName, err)
+		}
+		changed = true
+	}
+	if changed {
+		logger.Info("Syncing copy of envFrom source", "source", ref.String(), "mirror", mirrorName)
+		if err := rm.client.Update(ctx, existing); err != nil {
+			return nil, nil, fmt.Errorf("failed to update copy of envFrom %s: %w", ref, err)
+		}
+	}
+	return mirror, source, nil
+}
+
+// deleteEnvFromMirror deletes a copy of an envFrom source, if it exists and belongs to the workspace
+func (rm *ResourceManager) deleteEnvFromMirror(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	kind string,
+	name string) error {
+	existing := newEnvFromObject(kind)
+	err := rm.reader().Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, existing)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get copy of envFrom source %s %s: %w", kind, name, err)
+	}
+	if ownership, _ := classifyChild(workspace, existing); ownership != childOwned {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting copy of envFrom source", "kind", kind, "mirror", name)
+	if err := rm.client.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete copy of envFrom source %s %s: %w", kind, name, err)
+	}
+	return nil
+}
+
+// containsEnvFromMirror returns true if mirrors lists the copy
+func containsEnvFromMirror(mirrors []workspacev1alpha1.EnvFromMirrorStatus, mirror workspacev1alpha1.EnvFromMirrorStatus) bool {
+	for _, candidate := range mirrors {
+		if candidate.Kind == mirror.Kind && candidate.Name == mirror.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// withEnvFromResync shortens the requeue of a running workspace that copies sources of other
+// namespaces or exposes Secrets: those sources are not watched, so their copies are resynced and
+// the Secrets checked for rotation periodically
+func withEnvFromResync(workspace *workspacev1alpha1.Workspace, result ctrl.Result) ctrl.Result {
+	if len(workspace.Status.EnvFromMirrors) == 0 && workspace.Status.EnvFromSecretsChecksum == "" {
+		return result
+	}
+	if result.RequeueAfter == 0 || result.RequeueAfter > EnvFromMirrorResyncInterval {
+		result.RequeueAfter = EnvFromMirrorResyncInterval
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func configMapEnvFrom(name, sourceNamespace string, optional bool) workspacev1alpha1.EnvFromSource {
+	return workspacev1alpha1.EnvFromSource{
+		EnvFromSource: corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Optional:             &optional,
+			},
+		},
+		SourceNamespace: sourceNamespace,
+	}
+}
+
+func secretEnvFrom(name, sourceNamespace string) workspacev1alpha1.EnvFromSource {
+	return workspacev1alpha1.EnvFromSource{
+		EnvFromSource: corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+		},
+		SourceNamespace: sourceNamespace,
+	}
+}
+
+func TestBuildContainerEnvFromPointsToMirrors(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{
+		configMapEnvFrom("local", "", false),
+		configMapEnvFrom("same", "team-a", false),
+		secretEnvFrom("shared", "platform"),
+	}
+
+	envFrom := buildContainerEnvFrom(workspace)
+	require.Len(t, envFrom, 3)
+	assert.Equal(t, "local", envFrom[0].ConfigMapRef.Name)
+	assert.Equal(t, "same", envFrom[1].ConfigMapRef.Name)
+	assert.Equal(t, "workspace-ws-envfrom.platform.shared", envFrom[2].SecretRef.Name)
+	assert.Equal(t, "shared", workspace.Spec.EnvFrom[2].SecretRef.Name, "the spec is not modified")
+	assert.Nil(t, buildContainerEnvFrom(newAdoptionTestWorkspace("empty")))
+}
+
+func TestEnsureEnvFromSourcesRequiresLocalSources(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{
+		configMapEnvFrom("maybe", "", true),
+		configMapEnvFrom("settings", "", false),
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	err := sm.resourceManager.EnsureEnvFromSources(ctx, workspace)
+	missing, ok := asEnvFromSourceMissing(err)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, "settings", missing.Source.Name)
+	assert.Contains(t, err.Error(), "ConfigMap team-a/settings")
+
+	require.NoError(t, k8sClient.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team-a"},
+	}))
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.Empty(t, workspace.Status.EnvFromMirrors)
+}
+
+func TestEnsureEnvFromSourcesMirrorsOtherNamespaces(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{secretEnvFrom("shared", "platform")}
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "platform"},
+		Data:       map[string][]byte{"TOKEN": []byte("one")},
+		Type:       corev1.SecretTypeOpaque,
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, source)
+
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	require.Len(t, workspace.Status.EnvFromMirrors, 1)
+	assert.Equal(t, workspacev1alpha1.EnvFromMirrorStatus{
+		Kind:            workspaceutil.EnvFromKindSecret,
+		Name:            "workspace-ws-envfrom.platform.shared",
+		SourceNamespace: "platform",
+		SourceName:      "shared",
+	}, workspace.Status.EnvFromMirrors[0])
+
+	mirrorKey := types.NamespacedName{Name: "workspace-ws-envfrom.platform.shared", Namespace: "team-a"}
+	mirror := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(ctx, mirrorKey, mirror))
+	assert.Equal(t, []byte("one"), mirror.Data["TOKEN"])
+	assert.Equal(t, "platform/shared", mirror.Annotations[AnnotationEnvFromSource])
+	assert.Equal(t, "ws", mirror.Labels[LabelWorkspaceName])
+	require.NotNil(t, metav1.GetControllerOf(mirror))
+	assert.Equal(t, workspace.UID, metav1.GetControllerOf(mirror).UID)
+
+	// Changes of the source are synced to the copy
+	source.Data["TOKEN"] = []byte("two")
+	require.NoError(t, k8sClient.Update(ctx, source))
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	require.NoError(t, k8sClient.Get(ctx, mirrorKey, mirror))
+	assert.Equal(t, []byte("two"), mirror.Data["TOKEN"])
+
+	// A copy the workspace no longer references is deleted
+	workspace.Spec.EnvFrom = nil
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.Empty(t, workspace.Status.EnvFromMirrors)
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, mirrorKey, mirror)))
+}
+
+func TestEnsureEnvFromSourcesMissingMirrorSource(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{configMapEnvFrom("shared", "platform", false)}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	_, ok := asEnvFromSourceMissing(sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.True(t, ok)
+
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{configMapEnvFrom("shared", "platform", true)}
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.Empty(t, workspace.Status.EnvFromMirrors)
+}
+
+func TestEnsureEnvFromSourcesDoesNotOverwriteForeignObject(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{configMapEnvFrom("shared", "platform", false)}
+	source := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "platform"},
+		Data:       map[string]string{"A": "1"},
+	}
+	foreign := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace-ws-envfrom.platform.shared", Namespace: "team-a"},
+		Data:       map[string]string{"A": "mine"},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, source, foreign)
+
+	_, ok := asChildResourceConflict(sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.True(t, ok)
+
+	existing := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: foreign.Name, Namespace: "team-a"}, existing))
+	assert.Equal(t, "mine", existing.Data["A"])
+}
+
+func TestReconcileRunningReportsMissingEnvFromSource(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{configMapEnvFrom("settings", "", false)}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	result, err := sm.reconcileDesiredRunningStatus(ctx, workspace, workspace.Status.DeepCopy(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, LongRequeueDelay, result.RequeueAfter)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "ws", Namespace: "team-a"}, stored))
+	degraded := FindCondition(&stored.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, ReasonEnvFromSourceMissing, degraded.Reason)
+	assert.Contains(t, degraded.Message, "ConfigMap team-a/settings")
+
+	deployments := &appsv1.DeploymentList{}
+	require.NoError(t, k8sClient.List(ctx, deployments))
+	assert.Empty(t, deployments.Items)
+}
+
+func TestWithEnvFromResync(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	assert.Equal(t, ctrl.Result{}, withEnvFromResync(workspace, ctrl.Result{}))
+
+	workspace.Status.EnvFromMirrors = []workspacev1alpha1.EnvFromMirrorStatus{{Kind: "ConfigMap", Name: "copy"}}
+	assert.Equal(t, EnvFromMirrorResyncInterval, withEnvFromResync(workspace, ctrl.Result{}).RequeueAfter)
+	assert.Equal(t, EnvFromMirrorResyncInterval,
+		withEnvFromResync(workspace, ctrl.Result{RequeueAfter: IdleCheckInterval}).RequeueAfter)
+	assert.Equal(t, time.Second, withEnvFromResync(workspace, ctrl.Result{RequeueAfter: time.Second}).RequeueAfter)
+
+	// Local Secrets are not watched either: they are checked for rotation
+	workspace.Status.EnvFromMirrors = nil
+	workspace.Status.EnvFromSecretsChecksum = "0000000000000001"
+	assert.Equal(t, EnvFromMirrorResyncInterval, withEnvFromResync(workspace, ctrl.Result{}).RequeueAfter)
+}
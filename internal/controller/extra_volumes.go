@@ -0,0 +1,105 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// homeDirectory returns the home directory of the workspace container: the mount path of its primary
+// storage, or the default one when it has no storage
+func homeDirectory(workspace *workspacev1alpha1.Workspace) string {
+	if storageConfig := ResolveStorageConfig(workspace); storageConfig != nil {
+		return storageConfig.MountPath
+	}
+	return DefaultMountPath
+}
+
+// ExtraVolumeConflict returns why the extra volume cannot be mounted in the workspace container, or ""
+// when it can. An extra volume must not take the name of a volume of the controller or of spec.volumes,
+// and must not be mounted at, or above, the home directory or another mount of the workspace. Nor may it
+// remount a shared volume of the template resolved in the status.
+func ExtraVolumeConflict(workspace *workspacev1alpha1.Workspace, vol workspacev1alpha1.ExtraVolumeSpec) string {
+	switch vol.Name {
+	case "workspace-storage", TmpVolumeName, ServiceAccountTokenVolumeName, ServerTokenVolumeName:
+		return fmt.Sprintf("volume name '%s' is reserved", vol.Name)
+	}
+	if conflict := SharedVolumeConflict(workspace.Status.SharedVolumes, vol.Name, vol.MountPath); conflict != "" {
+		return conflict
+	}
+
+	mountPath := path.Clean(vol.MountPath)
+	if home := homeDirectory(workspace); isPathOrParent(mountPath, home) {
+		return fmt.Sprintf("mount path '%s' would hide the home directory '%s'", vol.MountPath, home)
+	}
+	if workspace.Spec.TmpVolume != nil && isPathOrParent(mountPath, TmpMountPath) {
+		return fmt.Sprintf("mount path '%s' would hide the tmp volume", vol.MountPath)
+	}
+	for _, other := range workspace.Spec.Volumes {
+		if other.Name == vol.Name {
+			return fmt.Sprintf("volume name '%s' is used by spec.volumes", vol.Name)
+		}
+		if isPathOrParent(mountPath, path.Clean(other.MountPath)) {
+			return fmt.Sprintf("mount path '%s' would hide volume '%s' of spec.volumes", vol.MountPath, other.Name)
+		}
+	}
+	return ""
+}
+
+// isPathOrParent returns true if candidate is target or one of its parent directories
+func isPathOrParent(candidate, target string) bool {
+	return candidate == target || candidate == "/" || strings.HasPrefix(target, candidate+"/")
+}
+
+// applyExtraVolumes mounts the extra volumes of the workspace in its container. Volumes that conflict
+// with the other mounts of the workspace are skipped; the webhook rejects them.
+func applyExtraVolumes(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	for _, vol := range workspace.Spec.ExtraVolumes {
+		if ExtraVolumeConflict(workspace, vol) != "" {
+			continue
+		}
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name:         vol.Name,
+			VolumeSource: extraVolumeSource(vol),
+		})
+		for i := range podSpec.Containers {
+			if podSpec.Containers[i].Name != workspaceContainerName {
+				continue
+			}
+			podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+				Name:      vol.Name,
+				MountPath: vol.MountPath,
+				ReadOnly:  vol.ReadOnly,
+			})
+		}
+	}
+}
+
+// extraVolumeSource returns the source of the pod volume an extra volume mounts
+func extraVolumeSource(vol workspacev1alpha1.ExtraVolumeSpec) corev1.VolumeSource {
+	switch vol.SourceType() {
+	case workspacev1alpha1.VolumeSourceConfigMap:
+		return corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: vol.ConfigMapName},
+			DefaultMode:          vol.DefaultMode,
+		}}
+	case workspacev1alpha1.VolumeSourceSecret:
+		return corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+			SecretName:  vol.SecretName,
+			DefaultMode: vol.DefaultMode,
+		}}
+	default:
+		return corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+			ClaimName: vol.PersistentVolumeClaimName,
+			ReadOnly:  vol.ReadOnly,
+		}}
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+// findVolume returns the pod volume of the name, or nil
+func findVolume(podSpec corev1.PodSpec, name string) *corev1.Volume {
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == name {
+			return &podSpec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+func TestExtraVolumesAreMountedInTheWorkspaceContainer(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{
+		{Name: "datasets", MountPath: "/datasets", ReadOnly: true, PersistentVolumeClaimName: "shared-datasets"},
+		{Name: "startup", MountPath: "/opt/startup", ConfigMapName: "startup-script", DefaultMode: ptr.To[int32](0o755)},
+		{Name: "credentials", MountPath: "/etc/credentials", SecretName: "mlflow-credentials"},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+
+	datasets := findVolume(podSpec, "datasets")
+	require.NotNil(t, datasets)
+	require.NotNil(t, datasets.PersistentVolumeClaim)
+	assert.Equal(t, "shared-datasets", datasets.PersistentVolumeClaim.ClaimName)
+	assert.True(t, datasets.PersistentVolumeClaim.ReadOnly)
+
+	startup := findVolume(podSpec, "startup")
+	require.NotNil(t, startup)
+	require.NotNil(t, startup.ConfigMap)
+	assert.Equal(t, "startup-script", startup.ConfigMap.Name)
+	assert.Equal(t, int32(0o755), *startup.ConfigMap.DefaultMode)
+
+	credentials := findVolume(podSpec, "credentials")
+	require.NotNil(t, credentials)
+	require.NotNil(t, credentials.Secret)
+	assert.Equal(t, "mlflow-credentials", credentials.Secret.SecretName)
+
+	mounts := map[string]corev1.VolumeMount{}
+	for _, mount := range podSpec.Containers[0].VolumeMounts {
+		mounts[mount.Name] = mount
+	}
+	assert.Equal(t, corev1.VolumeMount{Name: "datasets", MountPath: "/datasets", ReadOnly: true}, mounts["datasets"])
+	assert.Equal(t, "/opt/startup", mounts["startup"].MountPath)
+	assert.Equal(t, "/etc/credentials", mounts["credentials"].MountPath)
+}
+
+func TestExtraVolumesCollidingWithTheHomeDirectoryAreSkipped(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	home := homeDirectory(workspace)
+	workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{
+		{Name: "shadow", MountPath: home, ConfigMapName: "c"},
+		{Name: "workspace-storage", MountPath: "/x", ConfigMapName: "c"},
+		{Name: "nested", MountPath: home + "/datasets", PersistentVolumeClaimName: "shared-datasets"},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Nil(t, findVolume(podSpec, "shadow"))
+	assert.NotNil(t, findVolume(podSpec, "nested"), "mounts below the home directory are allowed")
+
+	homeMounts := 0
+	for _, mount := range podSpec.Containers[0].VolumeMounts {
+		if mount.MountPath == home {
+			homeMounts++
+		}
+		assert.NotEqual(t, "/x", mount.MountPath, "a reserved volume name is not reused")
+	}
+	assert.Equal(t, 1, homeMounts)
+}
+
+func TestExtraVolumeConflict(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Spec.Storage = nil
+
+	// Without storage the home directory is the default one of the images
+	conflict := ExtraVolumeConflict(workspace, workspacev1alpha1.ExtraVolumeSpec{Name: "root", MountPath: "/", ConfigMapName: "c"})
+	assert.Contains(t, conflict, DefaultMountPath)
+	assert.Empty(t, ExtraVolumeConflict(workspace,
+		workspacev1alpha1.ExtraVolumeSpec{Name: "homes", MountPath: "/home/jovyan-shared", ConfigMapName: "c"}))
+}
@@ -0,0 +1,248 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// HibernationRequeueDelay is how often a hibernating workspace is reconciled again while the VolumeSnapshot
+// of its home volume is not ready: the controller does not watch VolumeSnapshots
+const HibernationRequeueDelay = 10 * time.Second
+
+// reconcileDesiredHibernatedStatus stops the workspace, then keeps its home volume in a VolumeSnapshot:
+// once the snapshot is ready to use, its name is recorded in status.hibernation and the PVC is deleted.
+// The progress is reported in the Hibernated condition.
+func (sm *StateMachine) reconcileDesiredHibernatedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	result, err := sm.reconcileDesiredStoppedStatus(ctx, workspace, snapshotStatus)
+	if err != nil || !apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeStopped) {
+		return result, err
+	}
+
+	logger.Info("Attempting to bring Workspace status to 'Hibernated'")
+	requeueAfter, err := sm.hibernateHomeVolume(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to hibernate the workspace home volume")
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonHibernationFailed, err.Error(), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return ctrl.Result{}, err
+	}
+	if err := sm.statusManager.UpdateStoppedStatus(ctx, workspace, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// hibernateHomeVolume takes the VolumeSnapshot of the PVC of the stopped workspace and deletes the PVC once
+// the snapshot is ready to use. The status is updated in memory. It returns how long to wait before checking
+// the progress again, zero once the workspace is hibernated or its hibernation failed for good.
+func (sm *StateMachine) hibernateHomeVolume(ctx context.Context, workspace *workspacev1alpha1.Workspace) (time.Duration, error) {
+	rm := sm.resourceManager
+
+	if hibernation := workspace.Status.Hibernation; hibernation != nil {
+		pvc, err := rm.EnsurePVCDeleted(ctx, workspace)
+		if err != nil {
+			return 0, fmt.Errorf("failed to delete PVC: %w", err)
+		}
+		if pvc != nil {
+			sm.setHibernatedCondition(workspace, false, ReasonHibernating, fmt.Sprintf(
+				"Deleting PVC %s, kept in VolumeSnapshot %s", pvc.Name, hibernation.SnapshotRef))
+			return PollRequeueDelay, nil
+		}
+		if hibernation.HibernatedAt == nil {
+			hibernation.HibernatedAt = &metav1.Time{Time: time.Now().Truncate(time.Second)}
+		}
+		sm.setHibernatedCondition(workspace, true, ReasonHibernated, fmt.Sprintf(
+			"Home volume is kept in VolumeSnapshot %s", hibernation.SnapshotRef))
+		return 0, nil
+	}
+
+	if hasEphemeralStorage(workspace) {
+		sm.setHibernatedCondition(workspace, true, ReasonHibernated, "Workspace has no home volume to keep")
+		return 0, nil
+	}
+	pvc, err := rm.getPVC(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		sm.setHibernatedCondition(workspace, true, ReasonHibernated, "Workspace has no home volume to keep")
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get PVC: %w", err)
+	}
+	if ownership, _ := classifyChild(workspace, pvc); ownership != childOwned {
+		sm.setHibernatedCondition(workspace, false, ReasonHibernationFailed, fmt.Sprintf(
+			"PVC %s is not owned by the workspace, so it is not deleted", pvc.Name))
+		return 0, nil
+	}
+
+	snapshot, reason, message, err := rm.ensureHibernationSnapshot(ctx, workspace, pvc)
+	if err != nil {
+		return 0, err
+	}
+	if snapshot == "" {
+		sm.setHibernatedCondition(workspace, false, reason, message)
+		if reason == ReasonHibernationFailed {
+			return LongRequeueDelay, nil
+		}
+		return HibernationRequeueDelay, nil
+	}
+	logf.FromContext(ctx).Info("Home volume snapshot is ready, deleting the PVC", "pvc", pvc.Name, "snapshot", snapshot)
+	workspace.Status.Hibernation = &workspacev1alpha1.HibernationStatus{SnapshotRef: snapshot}
+	return sm.hibernateHomeVolume(ctx, workspace)
+}
+
+// reconcileHibernationRestore recreates the PVC of a hibernated workspace from its VolumeSnapshot before the
+// pod comes up; once the PVC is bound, the snapshot is deleted and status.hibernation is cleared. The status
+// is updated in memory. It returns the condition blocking the start while the PVC deleted by the hibernation
+// is still terminating.
+func (sm *StateMachine) reconcileHibernationRestore(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*metav1.Condition, error) {
+	hibernation := workspace.Status.Hibernation
+	if hibernation == nil {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeHibernated)
+		return nil, nil
+	}
+	rm := sm.resourceManager
+
+	pvc, err := rm.getPVC(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		pvc, err = rm.createPVCFrom(ctx, workspace, &pvcDataSource{ref: corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To(volumeSnapshotGVK.Group),
+			Kind:     volumeSnapshotGVK.Kind,
+			Name:     hibernation.SnapshotRef,
+		}})
+		if err != nil || pvc == nil {
+			// Without storage in its spec anymore, the workspace keeps its snapshot
+			return nil, err
+		}
+		logf.FromContext(ctx).Info("Restoring the home volume", "pvc", pvc.Name, "snapshot", hibernation.SnapshotRef)
+		sm.setHibernatedCondition(workspace, false, ReasonRestoringSnapshot, fmt.Sprintf(
+			"PVC %s is being restored from VolumeSnapshot %s", pvc.Name, hibernation.SnapshotRef))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PVC: %w", err)
+	}
+	if !pvc.DeletionTimestamp.IsZero() {
+		blocking := sm.setHibernatedCondition(workspace, false, ReasonRestoringSnapshot, fmt.Sprintf(
+			"Waiting for PVC %s to be deleted before restoring it from VolumeSnapshot %s", pvc.Name, hibernation.SnapshotRef))
+		return &blocking, nil
+	}
+	if !pvcBound(pvc) {
+		sm.setHibernatedCondition(workspace, false, ReasonRestoringSnapshot, fmt.Sprintf(
+			"PVC %s is being restored from VolumeSnapshot %s", pvc.Name, hibernation.SnapshotRef))
+		return nil, nil
+	}
+
+	// The PVC holds the data again, either restored or never deleted when the workspace resumed early
+	if err := rm.deleteHibernationSnapshot(ctx, workspace, hibernation.SnapshotRef); err != nil {
+		return nil, err
+	}
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceRestored", fmt.Sprintf(
+		"Home volume %s is restored from VolumeSnapshot %s", pvc.Name, hibernation.SnapshotRef))
+	workspace.Status.Hibernation = nil
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeHibernated)
+	return nil, nil
+}
+
+// setHibernatedCondition sets the Hibernated condition, with an event when the workspace becomes hibernated
+// or its hibernation fails, and returns it
+func (sm *StateMachine) setHibernatedCondition(
+	workspace *workspacev1alpha1.Workspace, hibernated bool, reason, message string) metav1.Condition {
+	status := metav1.ConditionFalse
+	if hibernated {
+		status = metav1.ConditionTrue
+	}
+	if previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeHibernated); previous == nil || previous.Reason != reason {
+		switch reason {
+		case ReasonHibernated:
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceHibernated", message)
+		case ReasonHibernationFailed:
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonHibernationFailed, message)
+		}
+	}
+	condition := NewCondition(ConditionTypeHibernated, status, reason, message)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, condition)
+	return condition
+}
+
+// ensureHibernationSnapshot creates the VolumeSnapshot of the home volume of the hibernating workspace, in the
+// default VolumeSnapshotClass, and returns its name once it is ready to use. It returns an empty name, and the
+// reason and message why, until then.
+func (rm *ResourceManager) ensureHibernationSnapshot(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	pvc *corev1.PersistentVolumeClaim) (string, string, string, error) {
+	name := hibernationSnapshotNameFor(workspace)
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	err := rm.client.Get(ctx, types.NamespacedName{Name: name, Namespace: workspace.Namespace}, snapshot)
+	if apimeta.IsNoMatchError(err) {
+		return "", ReasonHibernationFailed, "The cluster has no VolumeSnapshot API to keep the home volume in", nil
+	}
+	if apierrors.IsNotFound(err) {
+		snapshot.SetName(name)
+		snapshot.SetNamespace(workspace.Namespace)
+		snapshot.SetLabels(GenerateLabels(workspace.Name))
+		snapshot.Object["spec"] = map[string]any{
+			"source": map[string]any{"persistentVolumeClaimName": pvc.Name},
+		}
+		if err := controllerutil.SetControllerReference(workspace, snapshot, rm.scheme); err != nil {
+			return "", "", "", fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := rm.client.Create(ctx, snapshot); err != nil && !apierrors.IsAlreadyExists(err) {
+			return "", "", "", fmt.Errorf("failed to create VolumeSnapshot %s: %w", name, err)
+		}
+		return "", ReasonHibernating, fmt.Sprintf("Taking VolumeSnapshot %s of the home volume %s", name, pvc.Name), nil
+	}
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to get VolumeSnapshot %s: %w", name, err)
+	}
+
+	if ready, _, _ := unstructured.NestedBool(snapshot.Object, "status", "readyToUse"); ready {
+		return name, "", "", nil
+	}
+	if failure, _, _ := unstructured.NestedString(snapshot.Object, "status", "error", "message"); failure != "" {
+		return "", ReasonHibernationFailed, fmt.Sprintf(
+			"VolumeSnapshot %s of the home volume %s failed: %s", name, pvc.Name, failure), nil
+	}
+	return "", ReasonHibernating, fmt.Sprintf(
+		"Waiting for VolumeSnapshot %s of the home volume %s to be ready", name, pvc.Name), nil
+}
+
+// deleteHibernationSnapshot deletes the VolumeSnapshot a hibernated workspace was restored from
+func (rm *ResourceManager) deleteHibernationSnapshot(ctx context.Context, workspace *workspacev1alpha1.Workspace, name string) error {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(name)
+	snapshot.SetNamespace(workspace.Namespace)
+	err := rm.client.Delete(ctx, snapshot)
+	if err != nil && !apierrors.IsNotFound(err) && !apimeta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete VolumeSnapshot %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newHibernationTestStateMachine returns a state machine for a stopped workspace with a bound home volume
+func newHibernationTestStateMachine(
+	t *testing.T, funcs interceptor.Funcs, workspace *workspacev1alpha1.Workspace) (*StateMachine, client.Client) {
+	sm, k8sClient := newAdoptionTestStateMachine(t, funcs, workspace)
+	_, err := sm.resourceManager.createPVC(context.Background(), workspace)
+	require.NoError(t, err)
+	bindCloneTestPVC(t, k8sClient, workspace)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeStopped, metav1.ConditionTrue, ReasonResourcesStopped, "Workspace is stopped"))
+	return sm, k8sClient
+}
+
+func TestHibernationKeepsTheHomeVolumeInASnapshot(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.DesiredStatus = DesiredStateHibernated
+	sm, k8sClient := newHibernationTestStateMachine(t, interceptor.Funcs{}, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	requeueAfter, err := sm.hibernateHomeVolume(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, HibernationRequeueDelay, requeueAfter)
+	hibernated := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeHibernated)
+	require.NotNil(t, hibernated)
+	assert.Equal(t, metav1.ConditionFalse, hibernated.Status)
+	assert.Equal(t, ReasonHibernating, hibernated.Reason)
+	assert.Equal(t, WorkspacePhaseStopping, GetWorkspacePhase(workspace), "the workspace is stopping until its volume is kept")
+	assert.Nil(t, workspace.Status.Hibernation)
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	key := client.ObjectKey{Name: hibernationSnapshotNameFor(workspace), Namespace: workspace.Namespace}
+	require.NoError(t, k8sClient.Get(ctx, key, snapshot))
+	source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	assert.Equal(t, pvcNameFor(workspace), source)
+	_, hasClass, _ := unstructured.NestedString(snapshot.Object, "spec", "volumeSnapshotClassName")
+	assert.False(t, hasClass, "the snapshot is taken in the default VolumeSnapshotClass")
+	require.Len(t, snapshot.GetOwnerReferences(), 1)
+	assert.Equal(t, workspace.Name, snapshot.GetOwnerReferences()[0].Name)
+
+	// The PVC is deleted once the snapshot is ready to use
+	require.NoError(t, unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse"))
+	require.NoError(t, k8sClient.Update(ctx, snapshot))
+	requeueAfter, err = sm.hibernateHomeVolume(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, PollRequeueDelay, requeueAfter)
+	require.NotNil(t, workspace.Status.Hibernation)
+	assert.Equal(t, key.Name, workspace.Status.Hibernation.SnapshotRef)
+	assert.Nil(t, workspace.Status.Hibernation.HibernatedAt, "the PVC is still being deleted")
+	assert.Empty(t, recorder.Events)
+
+	requeueAfter, err = sm.hibernateHomeVolume(ctx, workspace)
+	require.NoError(t, err)
+	assert.Zero(t, requeueAfter)
+	assert.NotNil(t, workspace.Status.Hibernation.HibernatedAt)
+	_, err = sm.resourceManager.getPVC(ctx, workspace)
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.True(t, apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeHibernated))
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal WorkspaceHibernated")
+
+	setPhase(workspace)
+	assert.Equal(t, WorkspacePhaseStopped, workspace.Status.Phase)
+	assert.Equal(t, ReasonHibernated, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeReady).Reason)
+}
+
+func TestHibernationWithoutTheSnapshotAPIKeepsThePVC(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newHibernationTestStateMachine(t, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if _, ok := obj.(*unstructured.Unstructured); ok {
+				return &apimeta.NoKindMatchError{GroupKind: volumeSnapshotGVK.GroupKind()}
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	requeueAfter, err := sm.hibernateHomeVolume(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, LongRequeueDelay, requeueAfter)
+	hibernated := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeHibernated)
+	require.NotNil(t, hibernated)
+	assert.Equal(t, ReasonHibernationFailed, hibernated.Reason)
+	assert.Contains(t, hibernated.Message, "no VolumeSnapshot API")
+	_, err = sm.resourceManager.getPVC(ctx, workspace)
+	assert.NoError(t, err, "the PVC is kept without a snapshot")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning HibernationFailed")
+}
+
+func TestHibernatedWorkspacesAreRestoredFromTheirSnapshot(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.Hibernation = &workspacev1alpha1.HibernationStatus{SnapshotRef: hibernationSnapshotNameFor(workspace)}
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(volumeSnapshotGVK)
+	snapshot.SetName(workspace.Status.Hibernation.SnapshotRef)
+	snapshot.SetNamespace(workspace.Namespace)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, snapshot)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	blocking, err := sm.reconcileHibernationRestore(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocking, "the workspace starts while its volume is restored")
+	pvc, err := sm.resourceManager.getPVC(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, pvc.Spec.DataSource)
+	assert.Equal(t, corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To("snapshot.storage.k8s.io"),
+		Kind:     "VolumeSnapshot",
+		Name:     snapshot.GetName(),
+	}, *pvc.Spec.DataSource)
+	restoring := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeHibernated)
+	require.NotNil(t, restoring)
+	assert.Equal(t, ReasonRestoringSnapshot, restoring.Reason)
+
+	// The snapshot is deleted once the restored volume is bound
+	bindCloneTestPVC(t, k8sClient, workspace)
+	_, err = sm.reconcileHibernationRestore(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, workspace.Status.Hibernation)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeHibernated))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(snapshot), snapshot)))
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal WorkspaceRestored")
+}
+
+func TestHibernationRestoreWaitsForTheDeletedPVC(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.Hibernation = &workspacev1alpha1.HibernationStatus{SnapshotRef: hibernationSnapshotNameFor(workspace)}
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name:              pvcNameFor(workspace),
+		Namespace:         workspace.Namespace,
+		Finalizers:        []string{"kubernetes.io/pvc-protection"},
+		DeletionTimestamp: ptr.To(metav1.Now()),
+	}}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pvc)
+
+	blocking, err := sm.reconcileHibernationRestore(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocking)
+	assert.Equal(t, ReasonRestoringSnapshot, blocking.Reason)
+	assert.Contains(t, blocking.Message, "Waiting for PVC")
+	assert.NotNil(t, workspace.Status.Hibernation)
+}
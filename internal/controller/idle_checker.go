@@ -8,6 +8,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -25,6 +26,15 @@ type IdleCheckResult struct {
 	// true = temporary failure, retry later
 	// false = permanent failure, stop checking
 	ShouldRetry bool
+
+	// LastActivity is the last activity reported by the workspace, zero when unknown
+	LastActivity time.Time
+
+	// Kernels is the number of kernels reported by the workspace, nil when it does not report them
+	Kernels *int32
+
+	// ActivitySource is the endpoint the activity was read from
+	ActivitySource string
 }
 
 // WorkspaceIdleChecker provides utilities for checking workspace idle status
@@ -50,6 +60,16 @@ func (w *WorkspaceIdleChecker) CheckWorkspaceIdle(ctx context.Context, workspace
 		return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, fmt.Errorf("failed to find workspace pod: %w", err)
 	}
 
+	// Servers described by an adapter report their activity on its endpoint
+	adapter := ResolveServerAdapter(workspace)
+	token, err := resolveServerToken(ctx, w.client, workspace, adapter)
+	if err != nil {
+		logger.Error(err, "Failed to resolve the server token")
+		return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, err
+	}
+	idleConfig = applyServerAdapterToIdleConfig(idleConfig, adapter, pod, token)
+	idleConfig = applyServiceMeshToIdleConfig(idleConfig, workspace, pod)
+
 	// Create appropriate detector
 	detector, err := CreateIdleDetector(&idleConfig.Detection)
 	if err != nil {
@@ -59,6 +79,9 @@ func (w *WorkspaceIdleChecker) CheckWorkspaceIdle(ctx context.Context, workspace
 
 	// Use detector to check idle status
 	result, err := detector.CheckIdle(ctx, workspace.Name, pod, idleConfig)
+	if result != nil && idleConfig.Detection.HTTPGet != nil {
+		result.ActivitySource = fmt.Sprintf(":%s%s", idleConfig.Detection.HTTPGet.Port.String(), idleConfig.Detection.HTTPGet.Path)
+	}
 	return result, err
 }
 
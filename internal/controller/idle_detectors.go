@@ -21,8 +21,14 @@ import (
 // EndpointIdleResponse represents the response from /api/idle endpoint
 type EndpointIdleResponse struct {
 	LastActivity string `json:"lastActiveTimestamp"`
+
+	// Kernels is the number of kernels of the server, for servers reporting it (Jupyter /api/status)
+	Kernels *int32 `json:"kernels,omitempty"`
 }
 
+// kernelsField is the field of the idle endpoint response holding the number of kernels
+const kernelsField = "kernels"
+
 // IdleDetector interface for different detection methods
 type IdleDetector interface {
 	CheckIdle(ctx context.Context, workspaceName string, pod *corev1.Pod, idleConfig *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error)
@@ -77,17 +83,20 @@ func (h *HTTPGetDetector) CheckIdle(ctx context.Context, workspaceName string, p
 	if scheme == "" {
 		scheme = "http"
 	}
+	host := httpGetConfig.Host
+	if host == "" {
+		host = "localhost"
+	}
 	port := httpGetConfig.Port.String()
-	url := fmt.Sprintf("%s://localhost:%s%s", scheme, port, httpGetConfig.Path)
+	url := fmt.Sprintf("%s://%s:%s%s", scheme, host, port, httpGetConfig.Path)
 
 	// Single curl call with status code
-	cmd := []string{"curl", "-s", "-w", "\\nHTTP Status: %{http_code}\\n", url}
+	cmd, stdin := curlCommand([]string{"-s", "-w", "\\nHTTP Status: %{http_code}\\n"}, url, httpGetConfig.HTTPHeaders)
 
 	logger.V(1).Info("Calling idle endpoint", "port", port, "path", httpGetConfig.Path)
 
 	// Always execute in the workspace container
-	const workspaceContainerName = "workspace"
-	output, err := h.execUtil.ExecInPod(ctx, pod, workspaceContainerName, cmd, "")
+	output, err := h.execUtil.ExecInPod(ctx, pod, workspaceContainerName, cmd, stdin)
 	if err != nil {
 		// Handle curl exit codes - connection refused (temporary failure)
 		if strings.Contains(err.Error(), "exit code 7") {
@@ -118,37 +127,77 @@ func (h *HTTPGetDetector) CheckIdle(ctx context.Context, workspaceName string, p
 		return &IdleCheckResult{IsIdle: false, ShouldRetry: false}, fmt.Errorf("endpoint not found")
 	case "200":
 		// Parse the JSON response
-		var idleResp EndpointIdleResponse
-		if err := json.Unmarshal([]byte(responseBody.String()), &idleResp); err != nil {
+		field := idleConfig.Detection.LastActivityField
+		if field == "" {
+			field = DefaultLastActivityField
+		}
+		idleResp, err := parseIdleResponse(responseBody.String(), field)
+		if err != nil {
 			logger.Error(err, "Failed to parse idle response", "output", responseBody.String())
 			return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, fmt.Errorf("failed to parse idle response: %w", err)
 		}
 
 		// Validate the response
 		if idleResp.LastActivity == "" {
-			logger.Error(nil, "Empty lastActiveTimestamp in response", "output", responseBody.String())
-			return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, fmt.Errorf("invalid idle response: empty lastActiveTimestamp")
+			logger.Error(nil, "Empty last activity in response", "field", field, "output", responseBody.String())
+			return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, fmt.Errorf("invalid idle response: empty %s", field)
 		}
 
 		// Check if workspace is idle based on timeout
-		isIdle := h.checkIdleTimeout(ctx, workspaceName, &idleResp, idleConfig)
+		isIdle := h.checkIdleTimeout(ctx, workspaceName, idleResp, idleConfig)
 		logger.V(1).Info("Successfully retrieved idle status", "lastActivity", idleResp.LastActivity, "isIdle", isIdle)
-		return &IdleCheckResult{IsIdle: isIdle, ShouldRetry: true}, nil
+		lastActivity, _ := parseLastActivity(idleResp.LastActivity)
+		return &IdleCheckResult{IsIdle: isIdle, ShouldRetry: true, LastActivity: lastActivity, Kernels: idleResp.Kernels}, nil
 	default:
 		// treat other HTTP errors as retryable
 		return &IdleCheckResult{IsIdle: false, ShouldRetry: true}, fmt.Errorf("unexpected HTTP status: %s", statusCode)
 	}
 }
 
+// DefaultLastActivityField is the field of the idle endpoint response holding the last activity time
+const DefaultLastActivityField = "lastActiveTimestamp"
+
+// parseIdleResponse reads the last activity time from the field of the idle endpoint response.
+// Times in milliseconds since the epoch are written as RFC 3339 times.
+func parseIdleResponse(body string, field string) (*EndpointIdleResponse, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(body), &fields); err != nil {
+		return nil, err
+	}
+	idleResp := &EndpointIdleResponse{}
+	var kernels int32
+	if raw, ok := fields[kernelsField]; ok && json.Unmarshal(raw, &kernels) == nil {
+		idleResp.Kernels = &kernels
+	}
+	raw, ok := fields[field]
+	if !ok || string(raw) == "null" {
+		return idleResp, nil
+	}
+	var millis int64
+	if err := json.Unmarshal(raw, &millis); err == nil {
+		if millis > 0 {
+			idleResp.LastActivity = time.UnixMilli(millis).UTC().Format(time.RFC3339Nano)
+		}
+		return idleResp, nil
+	}
+	if err := json.Unmarshal(raw, &idleResp.LastActivity); err != nil {
+		return nil, fmt.Errorf("field %s is neither a time nor a number: %w", field, err)
+	}
+	return idleResp, nil
+}
+
+// parseLastActivity parses the last activity time with case-insensitive timezone.
+// Some Jupyter servers return lowercase 'z' instead of uppercase 'Z' for UTC timezone
+// RFC3339 requires uppercase 'Z', so we normalize it here
+func parseLastActivity(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, strings.ToUpper(value)) // Convert 'z' to 'Z'
+}
+
 // checkIdleTimeout checks if workspace should be stopped due to idle timeout
 func (h *HTTPGetDetector) checkIdleTimeout(ctx context.Context, workspaceName string, idleResp *EndpointIdleResponse, idleConfig *workspacev1alpha1.IdleShutdownSpec) bool {
 	logger := logf.FromContext(ctx).WithValues("workspace", workspaceName)
 
-	// Parse last activity time with case-insensitive timezone
-	// Some Jupyter servers return lowercase 'z' instead of uppercase 'Z' for UTC timezone
-	// RFC3339 requires uppercase 'Z', so we normalize it here
-	lastActivityStr := strings.ToUpper(idleResp.LastActivity) // Convert 'z' to 'Z'
-	lastActivity, err := time.Parse(time.RFC3339, lastActivityStr)
+	lastActivity, err := parseLastActivity(idleResp.LastActivity)
 	if err != nil {
 		logger.Error(err, "Failed to parse last activity time", "lastActivity", idleResp.LastActivity)
 		return false
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// idleCheckFunc checks whether a workspace is idle, e.g. WorkspaceIdleChecker.CheckWorkspaceIdle
+type idleCheckFunc func(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	idleConfig *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error)
+
+// idleProbeOutcome is the result of a finished idle probe
+type idleProbeOutcome struct {
+	result     *IdleCheckResult
+	err        error
+	finishedAt time.Time
+}
+
+// idleProbeWorker runs idle checks off the reconcile path. A slow or hanging workspace endpoint
+// then only holds one of its goroutines, never a worker of the reconcile queue: the reconcile
+// submits a probe, requeues, and picks up the outcome on a later pass.
+type idleProbeWorker struct {
+	check   idleCheckFunc
+	timeout time.Duration
+	slots   chan struct{}
+
+	mu       sync.Mutex
+	inFlight map[types.NamespacedName]struct{}
+	outcomes map[types.NamespacedName]idleProbeOutcome
+}
+
+// newIdleProbeWorker creates a worker running at most concurrency probes at once, each bounded by timeout
+func newIdleProbeWorker(check idleCheckFunc, timeout time.Duration, concurrency int) *idleProbeWorker {
+	return &idleProbeWorker{
+		check:    check,
+		timeout:  timeout,
+		slots:    make(chan struct{}, concurrency),
+		inFlight: map[types.NamespacedName]struct{}{},
+		outcomes: map[types.NamespacedName]idleProbeOutcome{},
+	}
+}
+
+// submit starts a probe of the workspace unless one is already running. The probe keeps the values
+// of ctx, such as its logger, but not its cancellation: it outlives the reconcile that submitted it.
+func (w *idleProbeWorker) submit(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	idleConfig *workspacev1alpha1.IdleShutdownSpec) {
+	key := types.NamespacedName{Name: workspace.Name, Namespace: workspace.Namespace}
+
+	w.mu.Lock()
+	if _, running := w.inFlight[key]; running {
+		w.mu.Unlock()
+		return
+	}
+	w.inFlight[key] = struct{}{}
+	w.mu.Unlock()
+
+	workspace = workspace.DeepCopy()
+	idleConfig = idleConfig.DeepCopy()
+	probeCtx := context.WithoutCancel(ctx)
+	go func() {
+		w.slots <- struct{}{}
+		defer func() { <-w.slots }()
+
+		callCtx, cancel := context.WithTimeout(probeCtx, w.timeout)
+		defer cancel()
+		result, err := w.check(callCtx, workspace, idleConfig)
+		if recordCallTimeout(CallTypeWorkspaceHTTP, callCtx.Err()) != nil {
+			logf.FromContext(probeCtx).Info("Idle probe timed out", "workspace", key, "timeout", w.timeout)
+		}
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.inFlight, key)
+		w.outcomes[key] = idleProbeOutcome{result: result, err: err, finishedAt: time.Now()}
+	}()
+}
+
+// take returns and forgets the outcome of the last probe of the workspace, if it finished within maxAge
+func (w *idleProbeWorker) take(key types.NamespacedName, maxAge time.Duration) (idleProbeOutcome, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	outcome, ok := w.outcomes[key]
+	if !ok {
+		return idleProbeOutcome{}, false
+	}
+	delete(w.outcomes, key)
+	if time.Since(outcome.finishedAt) > maxAge {
+		return idleProbeOutcome{}, false
+	}
+	return outcome, true
+}
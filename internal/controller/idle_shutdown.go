@@ -0,0 +1,164 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// idleShutdownDecision is the outcome of applying the idle shutdown rules to a running workspace
+type idleShutdownDecision struct {
+	// stop is true when the workspace must be stopped
+	stop bool
+
+	// reason is the rule that applies: ReasonIdle or ReasonNeverConnected
+	reason string
+
+	// message explains the rule to users, in events and the IdleShutdown condition
+	message string
+}
+
+// decideIdleShutdown applies the idle shutdown rules to the outcome of an idle check. A workspace
+// nobody connected to yet is stopped once the never-connected timeout elapsed since its start,
+// when one is configured; other workspaces are stopped once idle for longer than the idle timeout.
+func decideIdleShutdown(
+	workspace *workspacev1alpha1.Workspace,
+	idleConfig *workspacev1alpha1.IdleShutdownSpec,
+	result *IdleCheckResult,
+	now time.Time) idleShutdownDecision {
+	if workspace.Status.FirstConnectedAt == nil && idleConfig.NeverConnectedTimeoutInMinutes != nil {
+		timeout := time.Duration(*idleConfig.NeverConnectedTimeoutInMinutes) * time.Minute
+		return idleShutdownDecision{
+			stop:   now.Sub(workspaceStartTime(workspace)) > timeout,
+			reason: ReasonNeverConnected,
+			message: fmt.Sprintf("Stopping workspace nobody connected to within %d minutes of its start",
+				*idleConfig.NeverConnectedTimeoutInMinutes),
+		}
+	}
+	return idleShutdownDecision{
+		stop:    result.IsIdle,
+		reason:  ReasonIdle,
+		message: fmt.Sprintf("Stopping workspace due to idle timeout of %d minutes", idleConfig.IdleTimeoutInMinutes),
+	}
+}
+
+// cullingOutcome returns what the culler does with a running workspace: a workspace an idle shutdown rule
+// applies to is stopped unless a higher-precedence intent holds it or the culler runs in dry-run mode
+func (sm *StateMachine) cullingOutcome(
+	workspace *workspacev1alpha1.Workspace,
+	decision idleShutdownDecision) (workspacev1alpha1.CullingDecision, string) {
+	switch {
+	case !decision.stop:
+		return workspacev1alpha1.CullingDecisionKeep, "No idle shutdown rule applies"
+	case !sm.intentResolver.CullerMayStop(workspace):
+		return workspacev1alpha1.CullingDecisionExempt, fmt.Sprintf(
+			"Idle shutdown rule %s applies, but the %s desired status intent takes precedence over the culler",
+			decision.reason, sm.intentResolver.Resolve(workspace).Intent.Actor)
+	case sm.cullingDryRun:
+		return workspacev1alpha1.CullingDecisionDryRun, fmt.Sprintf(
+			"Idle shutdown rule %s applies; the culler runs in dry-run mode: %s", decision.reason, decision.message)
+	}
+	return workspacev1alpha1.CullingDecisionCull, decision.message
+}
+
+// newCullingStatus returns the record of an evaluation of the idle shutdown rules
+func newCullingStatus(
+	idleConfig *workspacev1alpha1.IdleShutdownSpec,
+	result *IdleCheckResult,
+	decision workspacev1alpha1.CullingDecision,
+	rule, message string,
+	now time.Time) *workspacev1alpha1.CullingStatus {
+	culling := &workspacev1alpha1.CullingStatus{
+		EvaluatedAt:                    metav1.Time{Time: now.Truncate(time.Second)},
+		IdleTimeoutInMinutes:           idleConfig.IdleTimeoutInMinutes,
+		NeverConnectedTimeoutInMinutes: idleConfig.NeverConnectedTimeoutInMinutes,
+		Decision:                       decision,
+		Rule:                           rule,
+		Message:                        message,
+	}
+	if result != nil {
+		culling.ActivitySource = result.ActivitySource
+		culling.Kernels = result.Kernels
+		if !result.LastActivity.IsZero() {
+			culling.LastActivity = &metav1.Time{Time: result.LastActivity.Truncate(time.Second)}
+		}
+	}
+	return culling
+}
+
+// reportCullingOutcome emits the event and the metrics of a culling evaluation. Exempt and dry-run outcomes
+// are reported when the decision changes, not at each idle check.
+func (sm *StateMachine) reportCullingOutcome(
+	workspace *workspacev1alpha1.Workspace,
+	previous, culling *workspacev1alpha1.CullingStatus,
+	now time.Time) {
+	changed := previous == nil || previous.Decision != culling.Decision
+	mode := "enforce"
+	switch culling.Decision {
+	case workspacev1alpha1.CullingDecisionExempt:
+		if changed {
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, ReasonIdleShutdownExempt, culling.Message)
+		}
+		return
+	case workspacev1alpha1.CullingDecisionDryRun:
+		if !changed {
+			return
+		}
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, ReasonIdleShutdownDryRun, culling.Message)
+		mode = "dry-run"
+	case workspacev1alpha1.CullingDecisionCull:
+	default:
+		return
+	}
+
+	idleSince := workspaceStartTime(workspace)
+	if culling.Rule == ReasonIdle && culling.LastActivity != nil {
+		idleSince = culling.LastActivity.Time
+	}
+	templateNamespace, template := workspace.Labels[LabelWorkspaceTemplateNamespace], workspace.Labels[LabelWorkspaceTemplate]
+	culledWorkspacesTotal.WithLabelValues(workspace.Namespace, templateNamespace, template, mode).Inc()
+	culledIdleDurationSeconds.WithLabelValues(mode).Observe(now.Sub(idleSince).Seconds())
+}
+
+// workspaceStartTime returns the last time the workspace became available, or its creation time
+func workspaceStartTime(workspace *workspacev1alpha1.Workspace) time.Time {
+	if workspace.Status.LastStartTime != nil {
+		return workspace.Status.LastStartTime.Time
+	}
+	return workspace.CreationTimestamp.Time
+}
+
+// recordFirstConnection records in status the first activity reported after the workspace started.
+// The activity the server reports before anyone connects dates from its own start, before the
+// workspace became available.
+func recordFirstConnection(workspace *workspacev1alpha1.Workspace, lastActivity time.Time) bool {
+	if workspace.Status.FirstConnectedAt != nil || lastActivity.IsZero() || !lastActivity.After(workspaceStartTime(workspace)) {
+		return false
+	}
+	workspace.Status.FirstConnectedAt = &metav1.Time{Time: lastActivity.Truncate(time.Second)}
+	return true
+}
+
+// setIdleShutdownCondition records which idle shutdown rule stopped the workspace
+func setIdleShutdownCondition(workspace *workspacev1alpha1.Workspace, decision idleShutdownDecision) {
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type:    ConditionTypeIdleShutdown,
+		Status:  metav1.ConditionTrue,
+		Reason:  decision.reason,
+		Message: decision.message,
+	})
+}
+
+// clearIdleShutdownCondition removes the idle shutdown condition once the workspace runs again
+func clearIdleShutdownCondition(workspace *workspacev1alpha1.Workspace) {
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeIdleShutdown)
+}
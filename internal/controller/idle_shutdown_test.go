@@ -0,0 +1,210 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var idleTestStart = time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+// newIdleTestWorkspace returns a workspace that started at idleTestStart with a 10 minute idle timeout
+// and a 2 hour never-connected timeout
+func newIdleTestWorkspace() (*workspacev1alpha1.Workspace, *workspacev1alpha1.IdleShutdownSpec) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.LastStartTime = &metav1.Time{Time: idleTestStart}
+	idleConfig := &workspacev1alpha1.IdleShutdownSpec{
+		Enabled:                        true,
+		IdleTimeoutInMinutes:           10,
+		NeverConnectedTimeoutInMinutes: ptr.To(120),
+	}
+	workspace.Spec.IdleShutdown = idleConfig
+	return workspace, idleConfig
+}
+
+func TestDecideIdleShutdownWaitsForNeverConnectedTimeout(t *testing.T) {
+	workspace, idleConfig := newIdleTestWorkspace()
+	idle := &IdleCheckResult{IsIdle: true}
+
+	decision := decideIdleShutdown(workspace, idleConfig, idle, idleTestStart.Add(30*time.Minute))
+	assert.False(t, decision.stop)
+	assert.Equal(t, ReasonNeverConnected, decision.reason)
+
+	decision = decideIdleShutdown(workspace, idleConfig, idle, idleTestStart.Add(121*time.Minute))
+	assert.True(t, decision.stop)
+	assert.Equal(t, ReasonNeverConnected, decision.reason)
+	assert.Contains(t, decision.message, "nobody connected to within 120 minutes")
+}
+
+func TestDecideIdleShutdownAppliesIdleTimeoutOnceConnected(t *testing.T) {
+	workspace, idleConfig := newIdleTestWorkspace()
+	workspace.Status.FirstConnectedAt = &metav1.Time{Time: idleTestStart.Add(5 * time.Minute)}
+
+	decision := decideIdleShutdown(workspace, idleConfig, &IdleCheckResult{IsIdle: true}, idleTestStart.Add(30*time.Minute))
+	assert.True(t, decision.stop)
+	assert.Equal(t, ReasonIdle, decision.reason)
+	assert.Contains(t, decision.message, "idle timeout of 10 minutes")
+
+	decision = decideIdleShutdown(workspace, idleConfig, &IdleCheckResult{IsIdle: false}, idleTestStart.Add(30*time.Minute))
+	assert.False(t, decision.stop)
+}
+
+func TestDecideIdleShutdownWithoutNeverConnectedTimeout(t *testing.T) {
+	workspace, idleConfig := newIdleTestWorkspace()
+	idleConfig.NeverConnectedTimeoutInMinutes = nil
+
+	decision := decideIdleShutdown(workspace, idleConfig, &IdleCheckResult{IsIdle: true}, idleTestStart.Add(11*time.Minute))
+	assert.True(t, decision.stop)
+	assert.Equal(t, ReasonIdle, decision.reason)
+}
+
+func TestRecordFirstConnection(t *testing.T) {
+	workspace, _ := newIdleTestWorkspace()
+
+	// The server reports its own start until someone connects
+	assert.False(t, recordFirstConnection(workspace, idleTestStart.Add(-time.Minute)))
+	assert.False(t, recordFirstConnection(workspace, time.Time{}))
+	assert.Nil(t, workspace.Status.FirstConnectedAt)
+
+	connectedAt := idleTestStart.Add(42 * time.Minute)
+	assert.True(t, recordFirstConnection(workspace, connectedAt))
+	require.NotNil(t, workspace.Status.FirstConnectedAt)
+	assert.True(t, connectedAt.Equal(workspace.Status.FirstConnectedAt.Time))
+
+	// Later activity leaves the first connection alone
+	assert.False(t, recordFirstConnection(workspace, connectedAt.Add(time.Hour)))
+	assert.True(t, connectedAt.Equal(workspace.Status.FirstConnectedAt.Time))
+}
+
+func TestStopWorkspaceDueToIdleRecordsRule(t *testing.T) {
+	ctx := context.Background()
+	workspace, idleConfig := newIdleTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.intentResolver = NewDesiredStatusResolver(0)
+
+	decision := decideIdleShutdown(workspace, idleConfig, &IdleCheckResult{}, idleTestStart.Add(3*time.Hour))
+	_, err := sm.stopWorkspaceDueToIdle(ctx, workspace, decision)
+	require.NoError(t, err)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	condition := FindCondition(&stored.Status.Conditions, ConditionTypeIdleShutdown)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonNeverConnected, condition.Reason)
+	assert.Equal(t, decision.message, condition.Message)
+}
+
+func TestCullingOutcome(t *testing.T) {
+	workspace, idleConfig := newIdleTestWorkspace()
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.intentResolver = NewDesiredStatusResolver(0)
+	stop := decideIdleShutdown(workspace, idleConfig, &IdleCheckResult{}, idleTestStart.Add(3*time.Hour))
+
+	decision, _ := sm.cullingOutcome(workspace, idleShutdownDecision{reason: ReasonIdle})
+	assert.Equal(t, workspacev1alpha1.CullingDecisionKeep, decision)
+
+	decision, message := sm.cullingOutcome(workspace, stop)
+	assert.Equal(t, workspacev1alpha1.CullingDecisionCull, decision)
+	assert.Equal(t, stop.message, message)
+
+	sm.cullingDryRun = true
+	decision, message = sm.cullingOutcome(workspace, stop)
+	assert.Equal(t, workspacev1alpha1.CullingDecisionDryRun, decision)
+	assert.Contains(t, message, "dry-run mode")
+
+	// Maintenance takes precedence over the culler, in dry-run mode too
+	maintenance, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+		DesiredStatus: DesiredStateRunning,
+		ExpiresAt:     &metav1.Time{Time: time.Now().Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	workspace.Annotations = map[string]string{AnnotationMaintenanceIntent: maintenance}
+	decision, message = sm.cullingOutcome(workspace, stop)
+	assert.Equal(t, workspacev1alpha1.CullingDecisionExempt, decision)
+	assert.Contains(t, message, "the Maintenance desired status intent takes precedence")
+}
+
+func TestIdleCheckRecordsCullingStatusInDryRun(t *testing.T) {
+	ctx := context.Background()
+	workspace, _ := newIdleTestWorkspace()
+	workspace.Status.FirstConnectedAt = &metav1.Time{Time: idleTestStart}
+	workspace.Labels = map[string]string{LabelWorkspaceTemplateNamespace: "shared", LabelWorkspaceTemplate: "dry-run-template"}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws-pod", Namespace: "team-a", Labels: GenerateLabels("ws")},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	sm.intentResolver = NewDesiredStatusResolver(0)
+	sm.cullingDryRun = true
+	lastActivity := time.Now().Add(-time.Hour)
+	probes := 0
+	sm.idleProbes = newIdleProbeWorker(func(context.Context, *workspacev1alpha1.Workspace, *workspacev1alpha1.IdleShutdownSpec) (*IdleCheckResult, error) {
+		probes++
+		return &IdleCheckResult{IsIdle: true, LastActivity: lastActivity, Kernels: ptr.To(int32(2)), ActivitySource: ":8888/api/status"}, nil
+	}, time.Second, 1)
+	culled := culledWorkspacesTotal.WithLabelValues("team-a", "shared", "dry-run-template", "dry-run")
+
+	evaluate := func() {
+		_, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			sm.idleProbes.mu.Lock()
+			defer sm.idleProbes.mu.Unlock()
+			_, ok := sm.idleProbes.outcomes[client.ObjectKeyFromObject(workspace)]
+			return ok
+		}, 5*time.Second, 10*time.Millisecond)
+		_, err = sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+		require.NoError(t, err)
+	}
+	evaluate()
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateRunning, stored.Spec.DesiredStatus)
+	culling := stored.Status.Culling
+	require.NotNil(t, culling)
+	assert.Equal(t, workspacev1alpha1.CullingDecisionDryRun, culling.Decision)
+	assert.Equal(t, ReasonIdle, culling.Rule)
+	assert.Equal(t, ":8888/api/status", culling.ActivitySource)
+	assert.Equal(t, ptr.To(int32(2)), culling.Kernels)
+	assert.Equal(t, 10, culling.IdleTimeoutInMinutes)
+	require.NotNil(t, culling.LastActivity)
+	assert.True(t, lastActivity.Truncate(time.Second).Equal(culling.LastActivity.Time))
+	assert.Equal(t, 1.0, testutil.ToFloat64(culled))
+	recorder := sm.recorder.(*record.FakeRecorder)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, ReasonIdleShutdownDryRun)
+
+	// The next check waits for the idle check interval since the evaluation
+	result, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, IdleCheckInterval-time.Minute)
+	assert.Equal(t, 1, probes)
+
+	// A later evaluation with the same decision is neither reported nor counted again
+	workspace.Status.Culling.EvaluatedAt = metav1.Time{Time: time.Now().Add(-IdleCheckInterval)}
+	evaluate()
+	assert.Equal(t, 2, probes)
+	assert.Empty(t, recorder.Events)
+	assert.Equal(t, 1.0, testutil.ToFloat64(culled))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/registry"
+)
+
+// DigestResolver resolves image tags to digests, see registry.Client
+type DigestResolver interface {
+	ResolveDigest(ctx context.Context, image string, credentials registry.Credentials) (string, error)
+}
+
+// ImageResolutionFailedError reports that the registry could not resolve the image tag of a workspace
+// whose template pins images by digest
+type ImageResolutionFailedError struct {
+	Image string
+	Err   error
+}
+
+func (e *ImageResolutionFailedError) Error() string {
+	return fmt.Sprintf("failed to resolve the digest of image %s: %v", e.Image, e.Err)
+}
+
+func (e *ImageResolutionFailedError) Unwrap() error {
+	return e.Err
+}
+
+// asImageResolutionFailed returns the resolution failure wrapped in err, if any
+func asImageResolutionFailed(err error) (*ImageResolutionFailedError, bool) {
+	var failed *ImageResolutionFailedError
+	if errors.As(err, &failed) {
+		return failed, true
+	}
+	return nil, false
+}
+
+// ResolveImageDigest records in Status.ResolvedImage the image of the workspace pinned to the digest its tag
+// points at, when the admitted template revision sets the Digest image pinning. The digest is resolved once
+// per image, so that restarts run the same image; the status is updated in memory. A nil template, for
+// workspaces without one, clears the image. Registry failures are returned as ImageResolutionFailedError.
+func (rm *ResourceManager) ResolveImageDigest(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if rm.digestResolver == nil || template == nil {
+		clearResolvedImage(workspace)
+		return nil
+	}
+	image := rm.deploymentBuilder.imageResolver.ResolveImage(workspace)
+	if template.Spec.ImagePinning != workspacev1alpha1.ImagePinningModeDigest || strings.Contains(image, "@") {
+		clearResolvedImage(workspace)
+		return nil
+	}
+	if resolvedImageOf(workspace, image) != "" {
+		return nil
+	}
+
+	credentials, err := rm.imagePullCredentials(ctx, workspace)
+	if err != nil {
+		return &ImageResolutionFailedError{Image: image, Err: err}
+	}
+	callCtx, cancel := context.WithTimeout(ctx, RegistryCallTimeout)
+	defer cancel()
+	digest, err := rm.digestResolver.ResolveDigest(callCtx, image, credentials)
+	if err != nil {
+		return &ImageResolutionFailedError{Image: image, Err: recordCallTimeout(CallTypeRegistry, err)}
+	}
+	workspace.Status.ResolvedImage = image + "@" + digest
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeImageResolutionFailed)
+	return nil
+}
+
+// clearResolvedImage forgets the pinned image of a workspace whose image is no longer pinned by the controller
+func clearResolvedImage(workspace *workspacev1alpha1.Workspace) {
+	workspace.Status.ResolvedImage = ""
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeImageResolutionFailed)
+}
+
+// resolvedImageOf returns the pinned image of the workspace when it was resolved for image, or an empty string
+func resolvedImageOf(workspace *workspacev1alpha1.Workspace, image string) string {
+	if strings.HasPrefix(workspace.Status.ResolvedImage, image+"@") {
+		return workspace.Status.ResolvedImage
+	}
+	return ""
+}
+
+// imagePullCredentials reads the registry credentials of the image pull secrets of the workspace pod or, when it
+// has none, of the service account it runs as, as the service account admission does. Missing secrets are
+// skipped, as the kubelet does.
+func (rm *ResourceManager) imagePullCredentials(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) (registry.Credentials, error) {
+	refs := imagePullSecretsOf(workspace)
+	if len(refs) == 0 {
+		name := workspace.Spec.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		serviceAccount := &corev1.ServiceAccount{}
+		err := rm.reader().Get(ctx, client.ObjectKey{Name: name, Namespace: workspace.Namespace}, serviceAccount)
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service account %s: %w", name, err)
+		}
+		refs = serviceAccount.ImagePullSecrets
+	}
+
+	secrets := make([]corev1.Secret, 0, len(refs))
+	for _, ref := range refs {
+		secret := corev1.Secret{}
+		err := rm.reader().Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: workspace.Namespace}, &secret)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image pull secret %s: %w", ref.Name, err)
+		}
+		secrets = append(secrets, secret)
+	}
+	return registry.CredentialsFromSecrets(secrets)
+}
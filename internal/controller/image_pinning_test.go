@@ -0,0 +1,153 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/registry"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const pinningTestDigest = "sha256:9d3e2b4c6a1f0e8d7c5b3a2918f7e6d5c4b3a29180f7e6d5c4b3a2918f7e6d5c"
+
+// fakeDigestResolver resolves every tag to pinningTestDigest, or fails with err when set
+type fakeDigestResolver struct {
+	calls       int
+	credentials registry.Credentials
+	err         error
+}
+
+func (f *fakeDigestResolver) ResolveDigest(_ context.Context, _ string, credentials registry.Credentials) (string, error) {
+	f.calls++
+	f.credentials = credentials
+	if f.err != nil {
+		return "", f.err
+	}
+	return pinningTestDigest, nil
+}
+
+// newPinningTestStateMachine returns a state machine resolving digests with resolver, for a workspace of a
+// template pinning images by digest
+func newPinningTestStateMachine(t *testing.T, resolver *fakeDigestResolver, objs ...client.Object) (
+	*StateMachine, client.Client, *workspacev1alpha1.Workspace, *workspacev1alpha1.WorkspaceTemplate) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "pinned", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  "pinned",
+			ImagePinning: workspacev1alpha1.ImagePinningModeDigest,
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Image = "quay.io/jupyter/base-notebook:latest"
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "pinned"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, append(objs, workspace, template)...)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	sm.resourceManager.digestResolver = resolver
+	return sm, k8sClient, workspace, template
+}
+
+func TestResolveImageDigestPinsTheImageOnce(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeDigestResolver{}
+	sm, _, workspace, template := newPinningTestStateMachine(t, resolver)
+
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Equal(t, "quay.io/jupyter/base-notebook:latest@"+pinningTestDigest, workspace.Status.ResolvedImage)
+
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Equal(t, 1, resolver.calls, "restarts run the image resolved at creation")
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, workspace.Status.ResolvedImage, deployment.Spec.Template.Spec.Containers[0].Image)
+
+	workspace.Spec.Image = "quay.io/jupyter/base-notebook:2026-03-02"
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Equal(t, 2, resolver.calls, "a new image is resolved again")
+	assert.Equal(t, "quay.io/jupyter/base-notebook:2026-03-02@"+pinningTestDigest, workspace.Status.ResolvedImage)
+}
+
+func TestResolveImageDigestLeavesUnpinnedImagesAlone(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeDigestResolver{}
+	sm, k8sClient, workspace, template := newPinningTestStateMachine(t, resolver)
+
+	workspace.Spec.Image = "quay.io/jupyter/base-notebook@" + pinningTestDigest
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Empty(t, workspace.Status.ResolvedImage, "images pinned by the workspace need no resolution")
+
+	workspace.Spec.Image = "quay.io/jupyter/base-notebook:latest"
+	workspace.Status.ResolvedImage = "quay.io/jupyter/base-notebook:latest@" + pinningTestDigest
+	template.Spec.ImagePinning = workspacev1alpha1.ImagePinningModeTag
+	require.NoError(t, k8sClient.Update(ctx, template))
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Empty(t, workspace.Status.ResolvedImage)
+	assert.Zero(t, resolver.calls)
+}
+
+func TestResolveImageDigestAuthenticatesWithThePullSecretsOfTheServiceAccount(t *testing.T) {
+	resolver := &fakeDigestResolver{}
+	sm, _, workspace, template := newPinningTestStateMachine(t, resolver,
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "notebook", Namespace: "team-a"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "missing"}, {Name: "quay"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "quay", Namespace: "team-a"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(
+				`{"auths":{"quay.io":{"username":"robot","password":"s3cret"}}}`)},
+		})
+	workspace.Spec.ServiceAccountName = "notebook"
+
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(context.Background(), workspace, template))
+	assert.Equal(t, registry.Credentials{"quay.io": {Username: "robot", Password: "s3cret"}}, resolver.credentials)
+}
+
+func TestImageResolutionFailureBlocksTheWorkspaceAndIsRetried(t *testing.T) {
+	ctx := context.Background()
+	resolver := &fakeDigestResolver{err: &registry.StatusError{Code: 404, Body: "manifest unknown"}}
+	sm, _, workspace, template := newPinningTestStateMachine(t, resolver)
+	recorder := record.NewFakeRecorder(10)
+	sm.recorder = recorder
+
+	err := sm.resourceManager.ResolveImageDigest(ctx, workspace, template)
+	failed, ok := asImageResolutionFailed(err)
+	require.True(t, ok)
+	snapshot := workspace.DeepCopy().Status
+	result, err := sm.handleImageResolutionFailed(ctx, workspace, failed, &snapshot)
+	assert.ErrorIs(t, err, resolver.err, "the error is returned for the backoff of the controller")
+	assert.Equal(t, ctrl.Result{}, result)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeImageResolutionFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "failed to resolve the digest of image quay.io/jupyter/base-notebook:latest: "+
+		"registry answered 404: manifest unknown", condition.Message)
+	assert.Equal(t, BlockedReasonImagePull, workspace.Status.BlockedReason)
+	require.Len(t, recorder.Events, 1)
+	<-recorder.Events
+
+	snapshot = workspace.DeepCopy().Status
+	_, _ = sm.handleImageResolutionFailed(ctx, workspace, failed, &snapshot)
+	assert.Empty(t, recorder.Events, "the event is recorded once per failure")
+
+	resolver.err = nil
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(ctx, workspace, template))
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeImageResolutionFailed))
+}
@@ -0,0 +1,40 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ResolveImagePullSecrets records the image pull secrets of the admitted template revision in
+// Status.ImagePullSecrets for the deployment builder to merge. The status is updated in memory. A nil
+// template, for workspaces without one, clears the secrets.
+func (rm *ResourceManager) ResolveImagePullSecrets(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.ImagePullSecrets = nil
+		return nil
+	}
+	workspace.Status.ImagePullSecrets = slices.Clone(template.Spec.ImagePullSecrets)
+	return nil
+}
+
+// imagePullSecretsOf returns the image pull secrets of the workspace pod: those of the template followed by the
+// ones the workspace adds, each name once
+func imagePullSecretsOf(workspace *workspacev1alpha1.Workspace) []corev1.LocalObjectReference {
+	var secrets []corev1.LocalObjectReference
+	for _, ref := range slices.Concat(workspace.Status.ImagePullSecrets, workspace.Spec.ImagePullSecrets) {
+		if ref.Name == "" || slices.Contains(secrets, ref) {
+			continue
+		}
+		secrets = append(secrets, ref)
+	}
+	return secrets
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/registry"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func TestImagePullSecretsOfTheTemplateAndWorkspaceAreMerged(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "lab", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:      "Lab",
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "company-registry"}},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "lab"}
+	workspace.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "my-registry"}, {Name: "company-registry"}}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveImagePullSecrets(ctx, workspace, template))
+	assert.Equal(t, template.Spec.ImagePullSecrets, workspace.Status.ImagePullSecrets)
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "company-registry"}, {Name: "my-registry"}},
+		deployment.Spec.Template.Spec.ImagePullSecrets, "each secret is listed once")
+
+	// The resolved secrets are kept while the template cannot be found
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.Equal(t, template.Spec.ImagePullSecrets, workspace.Status.ImagePullSecrets)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveImagePullSecrets(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.ImagePullSecrets)
+}
+
+func TestResolveImageDigestAuthenticatesWithTheImagePullSecretsOfThePod(t *testing.T) {
+	resolver := &fakeDigestResolver{}
+	sm, _, workspace, template := newPinningTestStateMachine(t, resolver,
+		&corev1.ServiceAccount{
+			ObjectMeta:       metav1.ObjectMeta{Name: "default", Namespace: "team-a"},
+			ImagePullSecrets: []corev1.LocalObjectReference{{Name: "docker-hub"}},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "docker-hub", Namespace: "team-a"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(
+				`{"auths":{"docker.io":{"username":"hub","password":"hub"}}}`)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "quay", Namespace: "team-a"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(
+				`{"auths":{"quay.io":{"username":"robot","password":"s3cret"}}}`)},
+		})
+	workspace.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "quay"}}
+
+	require.NoError(t, sm.resourceManager.ResolveImageDigest(context.Background(), workspace, template))
+	assert.Equal(t, registry.Credentials{"quay.io": {Username: "robot", Password: "s3cret"}}, resolver.credentials,
+		"the secrets of the service account are not added to a pod with its own")
+}
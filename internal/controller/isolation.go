@@ -0,0 +1,169 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// RootlessUID is the user Rootless workspace containers run as when the workspace sets none:
+	// the user of the Jupyter docker stacks images
+	RootlessUID = int64(1000)
+
+	// RootlessGID is the group Rootless workspace containers run as, and own their volumes with, when
+	// the workspace sets none: the group of the Jupyter docker stacks images
+	RootlessGID = int64(100)
+
+	// RootlessTmpVolumeName is the name of the emptyDir backing /tmp in Rootless workspaces without a tmp volume
+	RootlessTmpVolumeName = "rootless-tmp"
+
+	// RootlessHomeVolumeName is the name of the emptyDir backing the home directory of Rootless workspaces
+	// without storage
+	RootlessHomeVolumeName = "rootless-home"
+
+	// EventReasonIsolationLevelDegraded is the reason of the warning event recorded when a workspace
+	// runs Rootless because the cluster does not support the UserNamespace level of its template
+	EventReasonIsolationLevelDegraded = "IsolationLevelDegraded"
+)
+
+// minUserNamespaceVersion is the first Kubernetes version running pods with hostUsers: false by default
+var minUserNamespaceVersion = version.MajorMinor(1, 30)
+
+// DetectUserNamespaceSupport returns whether the API server is recent enough to run pods in user namespaces
+func DetectUserNamespaceSupport(config *rest.Config) (bool, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return false, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	info, err := discoveryClient.ServerVersion()
+	if err != nil {
+		return false, fmt.Errorf("failed to get the server version: %w", err)
+	}
+	return SupportsUserNamespaces(info.GitVersion)
+}
+
+// SupportsUserNamespaces returns whether a Kubernetes version runs pods in user namespaces
+func SupportsUserNamespaces(gitVersion string) (bool, error) {
+	serverVersion, err := version.ParseGeneric(gitVersion)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse the server version %q: %w", gitVersion, err)
+	}
+	return serverVersion.AtLeast(minUserNamespaceVersion), nil
+}
+
+// ResolveIsolationLevel records the isolation level of the admitted template revision in Status.IsolationLevel
+// for the deployment builder to apply; UserNamespace degrades to Rootless on clusters without user namespaces.
+// It returns true when the workspace newly runs at a degraded level. The status is updated in memory. A nil
+// template, for workspaces without one, clears the level.
+func (rm *ResourceManager) ResolveIsolationLevel(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	if template == nil {
+		workspace.Status.IsolationLevel = ""
+		return false
+	}
+	level := EffectiveIsolationLevel(template.Spec.IsolationLevel, rm.userNamespacesSupported)
+	degraded := level != template.Spec.IsolationLevel && level != "" && workspace.Status.IsolationLevel != level
+	workspace.Status.IsolationLevel = level
+	return degraded
+}
+
+// resolveIsolationLevel resolves the isolation level of the workspace, with a warning event when it newly runs
+// at a degraded level
+func (sm *StateMachine) resolveIsolationLevel(
+	_ context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	template *workspacev1alpha1.WorkspaceTemplate) error {
+	if sm.resourceManager.ResolveIsolationLevel(workspace, template) {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonIsolationLevelDegraded,
+			"The cluster does not support user namespaces, the workspace runs with the Rootless isolation level instead")
+	}
+	return nil
+}
+
+// EffectiveIsolationLevel returns the isolation level workspaces of a template run under, empty for Standard
+func EffectiveIsolationLevel(level workspacev1alpha1.IsolationLevel, userNamespacesSupported bool) workspacev1alpha1.IsolationLevel {
+	switch level {
+	case workspacev1alpha1.IsolationLevelStandard:
+		return ""
+	case workspacev1alpha1.IsolationLevelUserNamespace:
+		if !userNamespacesSupported {
+			return workspacev1alpha1.IsolationLevelRootless
+		}
+	}
+	return level
+}
+
+// applyIsolationLevel hardens the workspace pod to the isolation level resolved from the template. Values of
+// the security contexts of the workspace that are at least as strict are kept; the webhook rejects the others,
+// which are overridden here for the workspaces admitted before the template changed its level.
+func applyIsolationLevel(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	container := workspaceContainer(podSpec)
+	if container == nil {
+		return
+	}
+	switch workspace.Status.IsolationLevel {
+	case workspacev1alpha1.IsolationLevelRootless:
+		container.SecurityContext = rootlessSecurityContext(container.SecurityContext)
+		podSecurityContext := podSpec.SecurityContext.DeepCopy()
+		if podSecurityContext == nil {
+			podSecurityContext = &corev1.PodSecurityContext{}
+		}
+		if podSecurityContext.FSGroup == nil {
+			podSecurityContext.FSGroup = ptr.To(RootlessGID)
+		}
+		podSpec.SecurityContext = podSecurityContext
+		addWritableEmptyDir(podSpec, container, RootlessTmpVolumeName, TmpMountPath)
+		if ResolveStorageConfig(workspace) == nil {
+			addWritableEmptyDir(podSpec, container, RootlessHomeVolumeName, DefaultMountPath)
+		}
+	case workspacev1alpha1.IsolationLevelUserNamespace:
+		podSpec.HostUsers = ptr.To(false)
+	}
+}
+
+// rootlessSecurityContext returns a copy of the security context of a container hardened to run rootless
+func rootlessSecurityContext(securityContext *corev1.SecurityContext) *corev1.SecurityContext {
+	hardened := securityContext.DeepCopy()
+	if hardened == nil {
+		hardened = &corev1.SecurityContext{}
+	}
+	hardened.RunAsNonRoot = ptr.To(true)
+	if hardened.RunAsUser == nil || *hardened.RunAsUser == 0 {
+		hardened.RunAsUser = ptr.To(RootlessUID)
+	}
+	if hardened.RunAsGroup == nil || *hardened.RunAsGroup == 0 {
+		hardened.RunAsGroup = ptr.To(RootlessGID)
+	}
+	hardened.ReadOnlyRootFilesystem = ptr.To(true)
+	hardened.AllowPrivilegeEscalation = ptr.To(false)
+	hardened.Privileged = nil
+	hardened.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	return hardened
+}
+
+// addWritableEmptyDir backs a path of the read-only root filesystem of the container with an emptyDir,
+// unless a volume of the workspace is already mounted there
+func addWritableEmptyDir(podSpec *corev1.PodSpec, container *corev1.Container, name, mountPath string) {
+	if slices.ContainsFunc(container.VolumeMounts, func(mount corev1.VolumeMount) bool {
+		return mount.MountPath == mountPath
+	}) {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name:         name,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: name, MountPath: mountPath})
+}
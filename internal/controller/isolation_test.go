@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// findVolumeMount returns the mount of the container at the path, nil if none
+func findVolumeMount(container corev1.Container, mountPath string) *corev1.VolumeMount {
+	for i := range container.VolumeMounts {
+		if container.VolumeMounts[i].MountPath == mountPath {
+			return &container.VolumeMounts[i]
+		}
+	}
+	return nil
+}
+
+func TestStandardIsolationKeepsTheSecurityContexts(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	deployment := renderHashTestDeployment(t, workspace)
+	assert.Equal(t, goldenPodTemplateHashes["full"], deployment.Annotations[AnnotationPodTemplateHash])
+	assert.Nil(t, deployment.Spec.Template.Spec.HostUsers)
+	assert.Nil(t, deployment.Spec.Template.Spec.Containers[0].SecurityContext)
+}
+
+func TestRootlessIsolationHardensTheWorkspaceContainer(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Status.IsolationLevel = workspacev1alpha1.IsolationLevelRootless
+	workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+		RunAsUser:    ptr.To(int64(0)),
+		Privileged:   ptr.To(true),
+		Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_ADMIN"}},
+	}
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, &corev1.SecurityContext{
+		RunAsNonRoot:             ptr.To(true),
+		RunAsUser:                ptr.To(RootlessUID),
+		RunAsGroup:               ptr.To(RootlessGID),
+		ReadOnlyRootFilesystem:   ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}, container.SecurityContext, "values weakening the level are overridden")
+	assert.Equal(t, ptr.To(RootlessGID), podSpec.SecurityContext.FSGroup)
+	assert.Equal(t, RootlessTmpVolumeName, findVolumeMount(container, TmpMountPath).Name)
+	assert.Equal(t, RootlessHomeVolumeName, findVolumeMount(container, DefaultMountPath).Name,
+		"the home directory of a workspace without storage is writable")
+	assert.Equal(t, ptr.To(true), workspace.Spec.ContainerSecurityContext.Privileged, "the workspace is left unchanged")
+}
+
+func TestRootlessIsolationKeepsStricterValuesAndWorkspaceVolumes(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Status.IsolationLevel = workspacev1alpha1.IsolationLevelRootless
+	workspace.Spec.TmpVolume = &workspacev1alpha1.TmpVolumeSpec{}
+	workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{RunAsUser: ptr.To(int64(2000))}
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, ptr.To(int64(2000)), container.SecurityContext.RunAsUser)
+	assert.Equal(t, TmpVolumeName, findVolumeMount(container, TmpMountPath).Name)
+	assert.Equal(t, HomeVolumeName, findVolumeMount(container, DefaultMountPath).Name)
+	for _, volume := range podSpec.Volumes {
+		assert.NotContains(t, []string{RootlessTmpVolumeName, RootlessHomeVolumeName}, volume.Name)
+	}
+	assert.Equal(t, ptr.To(int64(100)), podSpec.SecurityContext.FSGroup, "the fsGroup of the workspace is kept")
+}
+
+func TestUserNamespaceIsolationDisablesHostUsers(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Status.IsolationLevel = workspacev1alpha1.IsolationLevelUserNamespace
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Equal(t, ptr.To(false), podSpec.HostUsers)
+	assert.Nil(t, podSpec.Containers[0].SecurityContext)
+}
+
+func TestSupportsUserNamespaces(t *testing.T) {
+	for gitVersion, expected := range map[string]bool{
+		"v1.29.4":             false,
+		"v1.30.0":             true,
+		"v1.31.2-eks-7f9249a": true,
+		"v2.0.0":              true,
+	} {
+		supported, err := SupportsUserNamespaces(gitVersion)
+		require.NoError(t, err)
+		assert.Equal(t, expected, supported, gitVersion)
+	}
+	_, err := SupportsUserNamespaces("unknown")
+	assert.Error(t, err)
+}
+
+func TestResolveIsolationLevelDegradesWithoutUserNamespaces(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "untrusted", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:    "Untrusted",
+			IsolationLevel: workspacev1alpha1.IsolationLevelUserNamespace,
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "untrusted"}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	recorder := record.NewFakeRecorder(10)
+	sm.recorder = recorder
+
+	require.NoError(t, sm.resolveIsolationLevel(ctx, workspace, template))
+	assert.Equal(t, workspacev1alpha1.IsolationLevelRootless, workspace.Status.IsolationLevel)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, EventReasonIsolationLevelDegraded)
+
+	// The degradation is only reported once
+	require.NoError(t, sm.resolveIsolationLevel(ctx, workspace, template))
+	assert.Empty(t, recorder.Events)
+
+	sm.resourceManager.userNamespacesSupported = true
+	assert.False(t, sm.resourceManager.ResolveIsolationLevel(workspace, template))
+	assert.Equal(t, workspacev1alpha1.IsolationLevelUserNamespace, workspace.Status.IsolationLevel)
+
+	assert.False(t, sm.resourceManager.ResolveIsolationLevel(workspace, nil))
+	assert.Empty(t, workspace.Status.IsolationLevel)
+}
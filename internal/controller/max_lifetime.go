@@ -0,0 +1,152 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// EventReasonExpiringSoon is the reason of the warning event recorded ahead of a workspace reaching the
+	// maximum lifetime of its template
+	EventReasonExpiringSoon = "ExpiringSoon"
+	// EventReasonMaxLifetimeReached is the reason of the warning event recorded when the controller stops a
+	// workspace that reached the maximum lifetime of its template
+	EventReasonMaxLifetimeReached = "MaxLifetimeReached"
+	// EventReasonDeletedAtMaxLifetime is the reason of the event recorded when the controller deletes a
+	// workspace that reached the maximum lifetime of its template
+	EventReasonDeletedAtMaxLifetime = "DeletedAtMaxLifetime"
+)
+
+// maxLifetimeOutcome is the result of enforcing the maximum lifetime of the workspace template
+type maxLifetimeOutcome struct {
+	// desiredStatus is the desired status to reconcile, Stopped once the workspace expired
+	desiredStatus string
+
+	// nextTransition is when the evaluation may change; zero when it only changes on a workspace update
+	nextTransition time.Time
+
+	// updated is true when the workspace object was updated and must be reconciled again
+	updated bool
+
+	// deleted is true when the workspace was deleted
+	deleted bool
+}
+
+// maxLifetimeActionOf returns what happens to the workspace when it expires: protected workspaces are
+// stopped rather than deleted
+func maxLifetimeActionOf(workspace *workspacev1alpha1.Workspace,
+	policy *workspacev1alpha1.EffectiveMaxLifetimePolicy) workspacev1alpha1.MaxLifetimeAction {
+	if policy.Action == workspacev1alpha1.MaxLifetimeActionDelete && !workspace.Spec.DeletionProtection {
+		return workspacev1alpha1.MaxLifetimeActionDelete
+	}
+	return workspacev1alpha1.MaxLifetimeActionStop
+}
+
+// reconcileMaxLifetime enforces the maximum lifetime of the workspace template, reported in
+// Status.EffectivePolicies. Its owner is warned with an event and the ExpiringSoon condition
+// MaxLifetimeWarningPeriod before the workspace expires; an expired workspace is then deleted, or
+// kept stopped whichever actor wants it running, per the maxLifetimeAction of the template. Exempted
+// workspaces are left alone. Condition changes are left in the workspace status, for the status
+// update of the reconciliation to persist.
+func (sm *StateMachine) reconcileMaxLifetime(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	desiredStatus string,
+	now time.Time) (maxLifetimeOutcome, error) {
+	var policy *workspacev1alpha1.EffectiveMaxLifetimePolicy
+	if workspace.Status.EffectivePolicies != nil {
+		policy = workspace.Status.EffectivePolicies.MaxLifetime
+	}
+	if policy == nil || policy.ExpiresAt == nil || policy.Exempt {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon)
+		return maxLifetimeOutcome{desiredStatus: desiredStatus}, nil
+	}
+
+	logger := logf.FromContext(ctx)
+	expiresAt := policy.ExpiresAt.Time
+	if warnAt := expiresAt.Add(-MaxLifetimeWarningPeriod); now.Before(warnAt) {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon)
+		return maxLifetimeOutcome{desiredStatus: desiredStatus, nextTransition: warnAt}, nil
+	}
+
+	action := maxLifetimeActionOf(workspace, policy)
+	if now.Before(expiresAt) {
+		outcome := "stopped"
+		if action == workspacev1alpha1.MaxLifetimeActionDelete {
+			outcome = "deleted with its storage"
+		}
+		message := fmt.Sprintf("Workspace reaches the maximum lifetime of %s of its template at %s, when it will be %s",
+			policy.MaxLifetime.Duration, expiresAt.UTC().Format(time.RFC3339), outcome)
+		condition := FindCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon)
+		if condition == nil || condition.Status != metav1.ConditionTrue {
+			logger.Info("Warning of workspace reaching its maximum lifetime", "expiresAt", expiresAt)
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonExpiringSoon, message)
+		}
+		setExpiringSoonCondition(workspace, ReasonExpiringSoon, message)
+		return maxLifetimeOutcome{desiredStatus: desiredStatus, nextTransition: expiresAt}, nil
+	}
+
+	if action == workspacev1alpha1.MaxLifetimeActionDelete {
+		// The preconditions fail if the workspace changed since it was read, e.g. it was exempted
+		uid, resourceVersion := workspace.UID, workspace.ResourceVersion
+		err := sm.resourceManager.client.Delete(ctx, workspace,
+			client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion})
+		if apierrors.IsNotFound(err) {
+			return maxLifetimeOutcome{deleted: true}, nil
+		}
+		if err != nil {
+			return maxLifetimeOutcome{}, fmt.Errorf("failed to delete expired workspace: %w", err)
+		}
+		logger.Info("Deleted workspace that reached its maximum lifetime", "expiresAt", expiresAt)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonDeletedAtMaxLifetime,
+			fmt.Sprintf("Deleted workspace created at %s, which reached the maximum lifetime of %s of its template",
+				workspace.CreationTimestamp.UTC().Format(time.RFC3339), policy.MaxLifetime.Duration))
+		return maxLifetimeOutcome{deleted: true}, nil
+	}
+
+	message := fmt.Sprintf("Workspace reached the maximum lifetime of %s of its template at %s; it must be recreated to run again",
+		policy.MaxLifetime.Duration, expiresAt.UTC().Format(time.RFC3339))
+	setExpiringSoonCondition(workspace, ReasonMaxLifetimeReached, message)
+	if desiredStatus != DesiredStateRunning {
+		return maxLifetimeOutcome{desiredStatus: desiredStatus}, nil
+	}
+	if workspace.Spec.DesiredStatus != DesiredStateRunning {
+		// A schedule or maintenance intent wants the workspace running; hold it stopped
+		logger.Info("Keeping workspace that reached its maximum lifetime stopped")
+		return maxLifetimeOutcome{desiredStatus: DesiredStateStopped}, nil
+	}
+
+	logger.Info("Stopping workspace that reached its maximum lifetime", "expiresAt", expiresAt)
+	original := workspace.DeepCopy()
+	workspace.Spec.DesiredStatus = DesiredStateStopped
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+		return maxLifetimeOutcome{}, fmt.Errorf("failed to stop expired workspace: %w", err)
+	}
+	sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonMaxLifetimeReached, message)
+	return maxLifetimeOutcome{desiredStatus: DesiredStateStopped, updated: true}, nil
+}
+
+// setExpiringSoonCondition sets the maximum lifetime condition
+func setExpiringSoonCondition(workspace *workspacev1alpha1.Workspace, reason, message string) {
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type:    ConditionTypeExpiringSoon,
+		Status:  metav1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
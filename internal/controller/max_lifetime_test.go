@@ -0,0 +1,149 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newMaxLifetimeTestWorkspace returns a running workspace of a template with a maximum lifetime of 30 days,
+// expiring at expiresAt
+func newMaxLifetimeTestWorkspace(expiresAt time.Time, action workspacev1alpha1.MaxLifetimeAction) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.EffectivePolicies = &workspacev1alpha1.EffectivePolicies{
+		MaxLifetime: &workspacev1alpha1.EffectiveMaxLifetimePolicy{
+			MaxLifetime: metav1.Duration{Duration: 30 * day},
+			Action:      action,
+			ExpiresAt:   &metav1.Time{Time: expiresAt},
+			Source:      workspacev1alpha1.PolicySourceTemplate,
+		},
+	}
+	return workspace
+}
+
+func TestMaxLifetimeWarnsTheOwnerAheadOfTheExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	expiresAt := now.Add(3 * day)
+	workspace := newMaxLifetimeTestWorkspace(expiresAt, workspacev1alpha1.MaxLifetimeActionStop)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	outcome, err := sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateRunning, nextTransition: now.Add(day)}, outcome,
+		"the owner is warned 48h before the expiry")
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon))
+
+	outcome, err = sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now.Add(day))
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateRunning, nextTransition: expiresAt}, outcome)
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonExpiringSoon, condition.Reason)
+	assert.Contains(t, condition.Message, "when it will be stopped")
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, fmt.Sprintf("%s %s", corev1.EventTypeWarning, EventReasonExpiringSoon))
+
+	_, err = sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now.Add(day+time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, events, "the owner is warned once")
+}
+
+func TestMaxLifetimeStopsExpiredWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	workspace := newMaxLifetimeTestWorkspace(now.Add(-time.Minute), workspacev1alpha1.MaxLifetimeActionStop)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	outcome, err := sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateStopped, updated: true}, outcome)
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, EventReasonMaxLifetimeReached)
+
+	// A schedule starting the expired workspace is overridden
+	outcome, err = sm.reconcileMaxLifetime(ctx, stored, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateStopped}, outcome)
+	condition := FindCondition(&stored.Status.Conditions, ConditionTypeExpiringSoon)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonMaxLifetimeReached, condition.Reason)
+	assert.Empty(t, events)
+}
+
+func TestMaxLifetimeDeletesExpiredWorkspacesUnlessProtected(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	workspace := newMaxLifetimeTestWorkspace(now.Add(-time.Minute), workspacev1alpha1.MaxLifetimeActionDelete)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace))
+	workspace.Status.EffectivePolicies = newMaxLifetimeTestWorkspace(now.Add(-time.Minute),
+		workspacev1alpha1.MaxLifetimeActionDelete).Status.EffectivePolicies
+
+	protected := workspace.DeepCopy()
+	protected.Spec.DeletionProtection = true
+	outcome, err := sm.reconcileMaxLifetime(ctx, protected, DesiredStateStopped, now)
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateStopped}, outcome, "protected workspaces are stopped instead")
+
+	outcome, err = sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.True(t, outcome.deleted)
+	err = k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), &workspacev1alpha1.Workspace{})
+	assert.True(t, apierrors.IsNotFound(err))
+	assert.Contains(t, <-sm.recorder.(*record.FakeRecorder).Events, EventReasonDeletedAtMaxLifetime)
+}
+
+func TestMaxLifetimeSparesExemptWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	template := newPoliciesTestTemplate(1, 60)
+	template.Spec.MaxLifetime = &metav1.Duration{Duration: 30 * day}
+	workspace := newPoliciesTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyFollow)
+	workspace.CreationTimestamp = metav1.NewTime(now.Add(-31 * day))
+	workspace.Annotations = map[string]string{AnnotationMaxLifetimeExempt: "true"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	policy := workspace.Status.EffectivePolicies.MaxLifetime
+	require.NotNil(t, policy)
+	assert.True(t, policy.Exempt)
+	assert.Equal(t, now.Add(-day), policy.ExpiresAt.Time)
+
+	setExpiringSoonCondition(workspace, ReasonExpiringSoon, "expiring")
+	outcome, err := sm.reconcileMaxLifetime(ctx, workspace, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.Equal(t, maxLifetimeOutcome{desiredStatus: DesiredStateRunning}, outcome)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeExpiringSoon))
+
+	// The lifetime keeps being enforced while the template cannot be resolved
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	delete(workspace.Annotations, AnnotationMaxLifetimeExempt)
+	sm.reconcileEffectivePolicies(ctx, workspace)
+	require.NotNil(t, workspace.Status.EffectivePolicies.MaxLifetime)
+	assert.False(t, workspace.Status.EffectivePolicies.MaxLifetime.Exempt)
+}
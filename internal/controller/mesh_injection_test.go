@@ -0,0 +1,258 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// meshProxyContainerName is the container the test mesh injector adds before the others
+	meshProxyContainerName = "istio-proxy"
+
+	// meshInjectionLabel labels the namespaces whose deployments the test mesh injector mutates
+	meshInjectionLabel = "istio-injection"
+
+	meshInjectorPath = "/inject-mesh-proxy"
+)
+
+// injectMeshProxy mutates the pod template as sidecar injectors of service meshes do: a proxy container
+// is added before the others, and the env vars of the containers are reordered
+func injectMeshProxy(template *corev1.PodTemplateSpec) {
+	for i := range template.Spec.Containers {
+		if template.Spec.Containers[i].Name == meshProxyContainerName {
+			return
+		}
+		slices.Reverse(template.Spec.Containers[i].Env)
+	}
+	proxy := corev1.Container{
+		Name:  meshProxyContainerName,
+		Image: "docker.io/istio/proxyv2:1.22.0",
+		Env:   []corev1.EnvVar{{Name: "ISTIO_META_WORKLOAD_NAME", Value: template.Name}},
+		ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{Path: "/healthz/ready"},
+		}},
+	}
+	template.Spec.Containers = append([]corev1.Container{proxy}, template.Spec.Containers...)
+}
+
+// meshInjectorWebhookConfiguration returns the mutating webhook of the test mesh injector, registered in the
+// envtest API server after the operator webhooks, on the deployments of the namespaces with meshInjectionLabel
+func meshInjectorWebhookConfiguration() *admissionregistrationv1.MutatingWebhookConfiguration {
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-mesh-injector"},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "inject.mesh.test.jupyter.org",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				Service: &admissionregistrationv1.ServiceReference{
+					Name: "test-mesh-injector", Namespace: "default", Path: ptr.To(meshInjectorPath),
+				},
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups: []string{"apps"}, APIVersions: []string{"v1"}, Resources: []string{"deployments"},
+				},
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{meshInjectionLabel: "enabled"},
+			},
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+}
+
+// startMeshInjector serves the test mesh injector on the address envtest points its webhooks at
+func startMeshInjector(ctx context.Context, options *envtest.WebhookInstallOptions) {
+	server := webhook.NewServer(webhook.Options{
+		Host:    options.LocalServingHost,
+		Port:    options.LocalServingPort,
+		CertDir: options.LocalServingCertDir,
+	})
+	server.Register(meshInjectorPath, &webhook.Admission{Handler: admission.HandlerFunc(
+		func(_ context.Context, req admission.Request) admission.Response {
+			deployment := &appsv1.Deployment{}
+			if err := json.Unmarshal(req.Object.Raw, deployment); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			injectMeshProxy(&deployment.Spec.Template)
+			mutated, err := json.Marshal(deployment)
+			if err != nil {
+				return admission.Errored(http.StatusInternalServerError, err)
+			}
+			return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+		})})
+	go func() {
+		defer GinkgoRecover()
+		Expect(server.Start(ctx)).To(Succeed())
+	}()
+	Eventually(func() error {
+		return server.StartedChecker()(nil)
+	}).Should(Succeed())
+}
+
+// newMeshInjectionTestWorkspace returns a JupyterLab workspace of the namespace, whose adapter sets the
+// readiness probe of the workspace container
+func newMeshInjectionTestWorkspace(namespace string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "meshed", Namespace: namespace},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:   "Meshed",
+			Image:         "jupyter/base-notebook:2024-01-01",
+			DesiredStatus: DesiredStateRunning,
+			ServerAdapter: &workspacev1alpha1.ServerAdapterSpec{Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab},
+			Env:           []corev1.EnvVar{{Name: "A_FIRST", Value: "1"}, {Name: "Z_LAST", Value: "2"}},
+		},
+	}
+}
+
+// expectWorkspaceContainerProbed asserts the pod template keeps the workspace container, with its
+// readiness probe, next to the injected proxy
+func expectWorkspaceContainerProbed(g Gomega, template *corev1.PodTemplateSpec) {
+	g.Expect(template.Spec.Containers).To(HaveLen(2))
+	g.Expect(template.Spec.Containers[0].Name).To(Equal(meshProxyContainerName))
+	container := workspaceContainer(&template.Spec)
+	g.Expect(container).NotTo(BeNil())
+	g.Expect(container.Image).To(Equal("jupyter/base-notebook:2024-01-01"))
+	g.Expect(container.ReadinessProbe).NotTo(BeNil())
+	g.Expect(container.ReadinessProbe.HTTPGet.Path).NotTo(Equal("/healthz/ready"))
+}
+
+var _ = Describe("Workspaces in namespaces with a sidecar injector", func() {
+	var namespace *corev1.Namespace
+
+	BeforeEach(func() {
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "meshed-",
+			Labels:       map[string]string{meshInjectionLabel: "enabled"},
+		}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+	})
+
+	It("should reconcile and probe the workspace container after the injector reorders the pod template", func() {
+		workspace := newMeshInjectionTestWorkspace(namespace.Name)
+		Expect(k8sClient.Create(ctx, workspace)).To(Succeed())
+
+		statusManager := NewStatusManager(k8sClient)
+		resourceManager := NewResourceManager(k8sClient, scheme.Scheme,
+			NewDeploymentBuilder(scheme.Scheme, WorkspaceControllerOptions{}), NewServiceBuilder(scheme.Scheme),
+			NewPVCBuilder(scheme.Scheme), NewAccessResourcesBuilder(), statusManager)
+
+		By("creating the deployment, which the injector mutates")
+		_, err := resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+		Expect(err).NotTo(HaveOccurred())
+		deployment := &appsv1.Deployment{}
+		key := client.ObjectKey{Name: GenerateDeploymentName(workspace.Name), Namespace: namespace.Name}
+		Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+		expectWorkspaceContainerProbed(Default, &deployment.Spec.Template)
+		Expect(workspaceContainer(&deployment.Spec.Template.Spec).Env[0].Name).To(Equal("Z_LAST"),
+			"the injector reordered the env vars")
+
+		By("reconciling the running workspace without rolling its pod template")
+		workspace.Status.Conditions = []metav1.Condition{{Type: ConditionTypeAvailable, Status: metav1.ConditionTrue}}
+		generation := deployment.Generation
+		for range 3 {
+			_, err = resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(ctx, key, deployment)).To(Succeed())
+			g.Expect(deployment.Generation).To(Equal(generation))
+			expectWorkspaceContainerProbed(g, &deployment.Spec.Template)
+		}, time.Second, 200*time.Millisecond).Should(Succeed())
+	})
+})
+
+func TestEnsureDeploymentKeepsAPodTemplateReorderedByAnInjector(t *testing.T) {
+	ctx := context.Background()
+	workspace := newMeshInjectionTestWorkspace("team-a")
+	updates := 0
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if deployment, ok := obj.(*appsv1.Deployment); ok {
+				injectMeshProxy(&deployment.Spec.Template)
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				updates++
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}, workspace)
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	workspace.Status.Conditions = []metav1.Condition{{Type: ConditionTypeAvailable, Status: metav1.ConditionTrue}}
+	_, err = sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Zero(t, updates, "the injected proxy and the env order do not change the fingerprint")
+
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateDeploymentName(workspace.Name), Namespace: "team-a"}, deployment))
+	expectWorkspaceContainerProbed(NewWithT(t), &deployment.Spec.Template)
+}
+
+func TestAccessStrategyAppliesToTheWorkspaceContainerWhateverItsIndex(t *testing.T) {
+	workspace := newMeshInjectionTestWorkspace("team-a")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{})
+	builder := sm.resourceManager.deploymentBuilder
+	deployment, err := builder.BuildDeployment(context.Background(), workspace)
+	require.NoError(t, err)
+	injectMeshProxy(&deployment.Spec.Template)
+
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "routing", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+			DisplayName:             "Routing",
+			AccessResourceTemplates: []workspacev1alpha1.AccessResourceTemplate{},
+			DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+				PodModifications: &workspacev1alpha1.PodModifications{
+					PrimaryContainerModifications: &workspacev1alpha1.PrimaryContainerModifications{
+						VolumeMounts: []corev1.VolumeMount{{Name: "routing-config", MountPath: "/etc/routing"}},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, builder.ApplyAccessStrategyToDeployment(deployment, workspace, accessStrategy))
+
+	proxy := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, meshProxyContainerName, proxy.Name)
+	assert.Empty(t, proxy.VolumeMounts)
+	assert.Contains(t, workspaceContainer(&deployment.Spec.Template.Spec).VolumeMounts,
+		corev1.VolumeMount{Name: "routing-config", MountPath: "/etc/routing"})
+
+	deployment.Spec.Template.Spec.Containers = deployment.Spec.Template.Spec.Containers[:1]
+	assert.ErrorContains(t, builder.ApplyAccessStrategyToDeployment(deployment, workspace, accessStrategy),
+		"deployment has no 'workspace' container")
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// pausedWorkspacesGauge reports the number of workspaces whose reconciliation is paused
+	pausedWorkspacesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "jupyter_k8s_workspaces_reconciliation_paused",
+		Help: "Number of workspaces with reconciliation paused by the workspace.jupyter.org/paused annotation",
+	})
+
+	// callTimeoutsTotal counts outbound calls of the controllers that ran out of time, by call type
+	callTimeoutsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jupyter_k8s_outbound_call_timeouts_total",
+		Help: "Number of outbound calls of the workspace controller that exceeded their timeout, by call type",
+	}, []string{"call_type"})
+
+	// unclaimedWorkspacesGauge reports the number of workspaces no operator instance manages
+	unclaimedWorkspacesGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "jupyter_k8s_workspaces_unclaimed",
+		Help: "Number of workspaces outside the scope of this operator instance that no other instance claimed",
+	})
+
+	// templateDriftedWorkspacesGauge reports the number of pinned workspaces whose template changed, by template
+	templateDriftedWorkspacesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "jupyter_k8s_template_drifted_workspaces",
+		Help: "Number of workspaces pinning their template generation whose template content changed, by template",
+	}, []string{"template_namespace", "template"})
+
+	// culledWorkspacesTotal counts the workspaces the idle culler stopped, or would have stopped in dry-run mode
+	culledWorkspacesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jupyter_k8s_workspaces_culled_total",
+		Help: "Number of workspaces the idle culler stopped (mode enforce) or would have stopped (mode dry-run), by namespace and template",
+	}, []string{"namespace", "template_namespace", "template", "mode"})
+
+	// culledIdleDurationSeconds observes how long workspaces were idle when the idle culler stopped them
+	culledIdleDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jupyter_k8s_culled_workspace_idle_duration_seconds",
+		Help:    "Time since the last activity, or the start of workspaces nobody connected to, when the idle culler stopped them",
+		Buckets: prometheus.ExponentialBuckets(5*60, 2, 10),
+	}, []string{"mode"})
+
+	// pausedWorkspaces tracks paused workspaces observed by the workspace controller
+	pausedWorkspaces = newWorkspaceSetTracker(pausedWorkspacesGauge)
+
+	// unclaimedWorkspaces tracks out-of-scope workspaces without the finalizer of any instance
+	unclaimedWorkspaces = newWorkspaceSetTracker(unclaimedWorkspacesGauge)
+
+	// templateDriftedWorkspaces tracks pinned workspaces whose template drifted
+	templateDriftedWorkspaces = newTemplateBindingTracker(templateDriftedWorkspacesGauge)
+)
+
+func init() {
+	metrics.Registry.MustRegister(pausedWorkspacesGauge, callTimeoutsTotal, unclaimedWorkspacesGauge,
+		templateDriftedWorkspacesGauge, culledWorkspacesTotal, culledIdleDurationSeconds)
+}
+
+// workspaceSetTracker keeps a set of workspaces so the gauge counting them can be kept exact
+type workspaceSetTracker struct {
+	mu      sync.Mutex
+	members map[types.NamespacedName]struct{}
+	gauge   prometheus.Gauge
+}
+
+func newWorkspaceSetTracker(gauge prometheus.Gauge) *workspaceSetTracker {
+	return &workspaceSetTracker{
+		members: map[types.NamespacedName]struct{}{},
+		gauge:   gauge,
+	}
+}
+
+// set records whether the workspace is in the set and refreshes the gauge
+func (t *workspaceSetTracker) set(key types.NamespacedName, member bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if member {
+		t.members[key] = struct{}{}
+	} else {
+		delete(t.members, key)
+	}
+	t.gauge.Set(float64(len(t.members)))
+}
+
+// templateBindingTracker keeps the template of each workspace in a set, so that the gauge counting
+// the workspaces of each template can be kept exact
+type templateBindingTracker struct {
+	mu       sync.Mutex
+	bindings map[types.NamespacedName]types.NamespacedName
+	counts   map[types.NamespacedName]int
+	gauge    *prometheus.GaugeVec
+}
+
+func newTemplateBindingTracker(gauge *prometheus.GaugeVec) *templateBindingTracker {
+	return &templateBindingTracker{
+		bindings: map[types.NamespacedName]types.NamespacedName{},
+		counts:   map[types.NamespacedName]int{},
+		gauge:    gauge,
+	}
+}
+
+// set records whether the workspace is in the set with the template and refreshes the gauge
+func (t *templateBindingTracker) set(key types.NamespacedName, template types.NamespacedName, member bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if previous, ok := t.bindings[key]; ok {
+		if member && previous == template {
+			return
+		}
+		delete(t.bindings, key)
+		t.refresh(previous, -1)
+	}
+	if member {
+		t.bindings[key] = template
+		t.refresh(template, 1)
+	}
+}
+
+// remove records that the workspace is not in the set
+func (t *templateBindingTracker) remove(key types.NamespacedName) {
+	t.set(key, types.NamespacedName{}, false)
+}
+
+// refresh adds delta to the count of the template; templates no workspace counts for are not reported
+func (t *templateBindingTracker) refresh(template types.NamespacedName, delta int) {
+	t.counts[template] += delta
+	if t.counts[template] <= 0 {
+		delete(t.counts, template)
+		t.gauge.DeleteLabelValues(template.Namespace, template.Name)
+		return
+	}
+	t.gauge.WithLabelValues(template.Namespace, template.Name).Set(float64(t.counts[template]))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+// ownerAccessVerbs are the verbs the owner of an OwnerOnly workspace is granted on it; patch is the form of
+// update kubectl edit and apply use
+var ownerAccessVerbs = []string{"get", "update", "patch", "delete"}
+
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// ownerAccessUser returns the owner of an OwnerOnly workspace, the user recorded in its created-by annotation
+// on creation, or an empty string for the workspaces of other ownership types
+func ownerAccessUser(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Spec.OwnershipType != webhookconst.OwnershipTypeOwnerOnly {
+		return ""
+	}
+	return workspace.Annotations[AnnotationCreatedBy]
+}
+
+// BuildOwnerAccess returns the Role, owned by the workspace, granting the verbs of ownerAccessVerbs on the
+// workspace only, and the RoleBinding granting it to the owner
+func BuildOwnerAccess(
+	workspace *workspacev1alpha1.Workspace, owner string, scheme *runtime.Scheme) (*rbacv1.Role, *rbacv1.RoleBinding, error) {
+	return buildWorkspaceAccess(workspace, ownerAccessNameFor(workspace), ownerAccessVerbs,
+		[]rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: owner}}, scheme)
+}
+
+// buildWorkspaceAccess returns the Role named name, owned by the workspace, granting verbs on the workspace
+// only, and the RoleBinding of the same name granting it to subjects
+func buildWorkspaceAccess(
+	workspace *workspacev1alpha1.Workspace,
+	name string,
+	verbs []string,
+	subjects []rbacv1.Subject,
+	scheme *runtime.Scheme) (*rbacv1.Role, *rbacv1.RoleBinding, error) {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: workspace.Namespace, Labels: GenerateLabels(workspace.Name)},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups:     []string{workspacev1alpha1.GroupVersion.Group},
+			Resources:     []string{"workspaces"},
+			ResourceNames: []string{workspace.Name},
+			Verbs:         verbs,
+		}},
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: workspace.Namespace, Labels: GenerateLabels(workspace.Name)},
+		Subjects:   subjects,
+		RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+	}
+	for _, obj := range []client.Object{role, binding} {
+		if err := controllerutil.SetControllerReference(workspace, obj, scheme); err != nil {
+			return nil, nil, fmt.Errorf("failed to set controller reference: %w", err)
+		}
+	}
+	return role, binding, nil
+}
+
+// ReconcileOwnerAccess keeps the Role and RoleBinding granting the owner of an OwnerOnly workspace access to
+// it, so that owners need no permission on the other workspaces of the namespace; the webhook still rejects
+// the updates and deletes of other users. Both are deleted once the workspace is no longer OwnerOnly.
+func (rm *ResourceManager) ReconcileOwnerAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	owner := ownerAccessUser(workspace)
+	if owner == "" {
+		return rm.deleteWorkspaceAccess(ctx, workspace, ownerAccessNameFor(workspace))
+	}
+	desiredRole, desiredBinding, err := BuildOwnerAccess(workspace, owner, rm.scheme)
+	if err != nil {
+		return err
+	}
+	return rm.ensureWorkspaceAccess(ctx, workspace, desiredRole, desiredBinding)
+}
+
+// ensureWorkspaceAccess creates the desired Role and RoleBinding, or updates them when they drifted
+func (rm *ResourceManager) ensureWorkspaceAccess(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, desiredRole *rbacv1.Role, desiredBinding *rbacv1.RoleBinding) error {
+	role := &rbacv1.Role{}
+	if err := rm.ensureAccessChild(ctx, workspace, "Role", desiredRole, role,
+		func() bool { return equality.Semantic.DeepEqual(role.Rules, desiredRole.Rules) },
+		func() { role.Rules = desiredRole.Rules }); err != nil {
+		return err
+	}
+	// The role reference of a binding is immutable, and the same for every binding of the workspace
+	binding := &rbacv1.RoleBinding{}
+	return rm.ensureAccessChild(ctx, workspace, "RoleBinding", desiredBinding, binding,
+		func() bool { return equality.Semantic.DeepEqual(binding.Subjects, desiredBinding.Subjects) },
+		func() { binding.Subjects = desiredBinding.Subjects })
+}
+
+// deleteWorkspaceAccess deletes the RoleBinding and the Role named name, when the workspace owns them
+func (rm *ResourceManager) deleteWorkspaceAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace, name string) error {
+	if err := rm.deleteAccessChild(ctx, workspace, "RoleBinding", name, &rbacv1.RoleBinding{}); err != nil {
+		return err
+	}
+	return rm.deleteAccessChild(ctx, workspace, "Role", name, &rbacv1.Role{})
+}
+
+// ensureAccessChild creates the desired Role or RoleBinding, or reads it into existing and updates it
+// with sync when inSync reports it drifted
+func (rm *ResourceManager) ensureAccessChild(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	kind string,
+	desired client.Object,
+	existing client.Object,
+	inSync func() bool,
+	sync func()) error {
+	err := rm.client.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		if err := rm.client.Create(ctx, desired); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return rm.adoptExistingChild(ctx, workspace, kind, desired, existing)
+			}
+			return fmt.Errorf("failed to create %s %s: %w", kind, desired.GetName(), err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s %s: %w", kind, desired.GetName(), err)
+	}
+
+	ownership, controllerRef := classifyChild(workspace, existing)
+	if ownership == childForeign {
+		return newChildResourceConflict(kind, existing, controllerRef)
+	}
+	if ownership == childOwned && inSync() {
+		return nil
+	}
+	sync()
+	if err := controllerutil.SetControllerReference(workspace, existing, rm.scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on %s %s: %w", kind, desired.GetName(), err)
+	}
+	if err := rm.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update %s %s: %w", kind, desired.GetName(), err)
+	}
+	return nil
+}
+
+// deleteAccessChild deletes the Role or RoleBinding named name, when the workspace owns it
+func (rm *ResourceManager) deleteAccessChild(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, kind, name string, existing client.Object) error {
+	key := client.ObjectKey{Name: name, Namespace: workspace.Namespace}
+	if err := rm.client.Get(ctx, key, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get %s %s: %w", kind, name, err)
+	}
+	if ownership, _ := classifyChild(workspace, existing); ownership != childOwned {
+		return nil
+	}
+	if err := rm.client.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete %s %s: %w", kind, name, err)
+	}
+	return nil
+}
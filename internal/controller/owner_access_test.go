@@ -0,0 +1,88 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newOwnerOnlyTestWorkspace(name string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace(name)
+	workspace.Annotations = map[string]string{AnnotationCreatedBy: "alice"}
+	workspace.Spec.OwnershipType = "OwnerOnly"
+	return workspace
+}
+
+func TestOwnerOnlyWorkspacesGrantTheirOwnerAccessToThemOnly(t *testing.T) {
+	ctx := context.Background()
+	workspace := newOwnerOnlyTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	key := client.ObjectKey{Name: ownerAccessNameFor(workspace), Namespace: workspace.Namespace}
+
+	require.NoError(t, sm.resourceManager.ReconcileOwnerAccess(ctx, workspace))
+	role := &rbacv1.Role{}
+	require.NoError(t, k8sClient.Get(ctx, key, role))
+	assert.Equal(t, []rbacv1.PolicyRule{{
+		APIGroups:     []string{"workspace.jupyter.org"},
+		Resources:     []string{"workspaces"},
+		ResourceNames: []string{"ws"},
+		Verbs:         []string{"get", "update", "patch", "delete"},
+	}}, role.Rules)
+	binding := &rbacv1.RoleBinding{}
+	require.NoError(t, k8sClient.Get(ctx, key, binding))
+	assert.Equal(t, []rbacv1.Subject{{Kind: "User", APIGroup: "rbac.authorization.k8s.io", Name: "alice"}}, binding.Subjects)
+	assert.Equal(t, rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: key.Name}, binding.RoleRef)
+	for _, obj := range []client.Object{role, binding} {
+		ownership, _ := classifyChild(workspace, obj)
+		assert.Equal(t, childOwned, ownership)
+	}
+
+	// A binding granted to someone else is restored
+	binding.Subjects[0].Name = "mallory"
+	require.NoError(t, k8sClient.Update(ctx, binding))
+	require.NoError(t, sm.resourceManager.ReconcileOwnerAccess(ctx, workspace))
+	require.NoError(t, k8sClient.Get(ctx, key, binding))
+	assert.Equal(t, "alice", binding.Subjects[0].Name)
+
+	// The access is revoked once the workspace is Public
+	workspace.Spec.OwnershipType = "Public"
+	require.NoError(t, sm.resourceManager.ReconcileOwnerAccess(ctx, workspace))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, key, &rbacv1.Role{})))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, key, &rbacv1.RoleBinding{})))
+}
+
+func TestOwnerAccessKeepsRolesOfOthers(t *testing.T) {
+	ctx := context.Background()
+	workspace := newOwnerOnlyTestWorkspace("ws")
+	foreign := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: ownerAccessNameFor(workspace), Namespace: workspace.Namespace,
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "v1", Kind: "ConfigMap", Name: "other", UID: "other-uid", Controller: ptr.To(true),
+		}}}}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, foreign)
+
+	err := sm.resourceManager.ReconcileOwnerAccess(ctx, workspace)
+	var conflict *ChildResourceConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "Role", conflict.Kind)
+
+	// Nor are they deleted once the workspace is Public
+	workspace.Spec.OwnershipType = "Public"
+	require.NoError(t, sm.resourceManager.ReconcileOwnerAccess(ctx, workspace))
+	assert.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(foreign), &rbacv1.Role{}))
+}
@@ -27,6 +27,8 @@ type PodEventHandler struct {
 	resourceManager *ResourceManager
 	// podEventAdapters maps plugin names (e.g. "aws") to their pod event adapter implementation.
 	podEventAdapters map[string]pluginadapters.PodEventPluginAdapter
+	// scope restricts the workspaces whose pod events are handled; nil handles all of them
+	scope *workspaceutil.Scope
 }
 
 // NewPodEventHandler creates a new PodEventHandler.
@@ -85,6 +87,12 @@ func (h *PodEventHandler) HandleWorkspacePodEvents(ctx context.Context, obj clie
 	logger := logf.FromContext(ctx).WithValues("pod", pod.Name, "namespace", pod.Namespace)
 	logger.V(1).Info("Received pod event", "phase", pod.Status.Phase)
 
+	// Pods of workspaces managed by other operator instances carry labels outside the scope
+	if !h.scope.MatchesLabels(pod.Labels) {
+		logger.V(1).Info("Pod of a workspace outside the scope of this instance, ignoring")
+		return nil
+	}
+
 	// Get workspace name from labels (predicate ensures this exists)
 	workspaceName := pod.Labels[workspaceutil.LabelWorkspaceName]
 
@@ -127,9 +135,7 @@ func (h *PodEventHandler) HandleKubernetesEvents(ctx context.Context, obj client
 	logger := logf.FromContext(ctx).WithValues("event", event.Name, "pod", event.InvolvedObject.Name)
 
 	// Check if this is a preemption event
-	if event.InvolvedObject.Kind == KindPod &&
-		event.Reason == DesiredStateStopped &&
-		strings.Contains(event.Message, "Preempted") {
+	if isPreemptionEvent(event) {
 
 		logger.Info("Detected pod preemption event",
 			"pod", event.InvolvedObject.Name,
@@ -183,6 +189,10 @@ func (h *PodEventHandler) handlePodRunning(ctx context.Context, pod *corev1.Pod,
 		logger.V(1).Info("Workspace already deleted, skipping pod event processing - this is expected during workspace cleanup")
 		return
 	}
+	if !h.scope.Matches(workspace) {
+		logger.V(1).Info("Workspace outside the scope of this instance, skipping pod event processing")
+		return
+	}
 
 	// Get access strategy using resource manager
 	accessStrategy, err := h.resourceManager.GetAccessStrategyForWorkspace(ctx, workspace)
@@ -202,7 +212,9 @@ func (h *PodEventHandler) handlePodRunning(ctx context.Context, pod *corev1.Pod,
 			resolvedCtx, err := pluginadapters.ResolvePodContext(accessStrategy.Spec.PodEventsContext, pod)
 			if err != nil {
 				logger.Error(err, "Failed to resolve pod events context", "plugin", pluginName)
-			} else if err := adapter.HandlePodRunning(ctx, pod, workspaceName, pod.Namespace, resolvedCtx); err != nil {
+			} else if err := h.callPlugin(ctx, func(callCtx context.Context) error {
+				return adapter.HandlePodRunning(callCtx, pod, workspaceName, pod.Namespace, resolvedCtx)
+			}); err != nil {
 				logger.Error(err, "Failed to setup containers", "plugin", pluginName)
 			}
 		}
@@ -250,7 +262,9 @@ func (h *PodEventHandler) handlePodDeleted(ctx context.Context, pod *corev1.Pod,
 			resolvedCtx, err := pluginadapters.ResolvePodContext(accessStrategy.Spec.PodEventsContext, pod)
 			if err != nil {
 				logger.Error(err, "Failed to resolve pod events context", "plugin", pluginName)
-			} else if err := adapter.HandlePodDeleted(ctx, pod, resolvedCtx); err != nil {
+			} else if err := h.callPlugin(ctx, func(callCtx context.Context) error {
+				return adapter.HandlePodDeleted(callCtx, pod, resolvedCtx)
+			}); err != nil {
 				logger.Error(err, "Failed to cleanup managed nodes", "plugin", pluginName)
 			}
 		}
@@ -260,6 +274,14 @@ func (h *PodEventHandler) handlePodDeleted(ctx context.Context, pod *corev1.Pod,
 	}
 }
 
+// callPlugin runs a call of a pod event adapter bounded by PluginCallTimeout, so that an unresponsive
+// plugin sidecar cannot block the handling of pod events
+func (h *PodEventHandler) callPlugin(ctx context.Context, call func(context.Context) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, PluginCallTimeout)
+	defer cancel()
+	return recordCallTimeout(CallTypePlugin, call(callCtx))
+}
+
 // updateWorkspaceDesiredStatus updates the workspace desiredStatus
 func (h *PodEventHandler) updateWorkspaceDesiredStatus(ctx context.Context, workspaceName string, namespace string, desiredStatus string) {
 	logger := logf.FromContext(ctx).WithValues("workspace", workspaceName, "namespace", namespace)
@@ -274,7 +296,18 @@ func (h *PodEventHandler) updateWorkspaceDesiredStatus(ctx context.Context, work
 		return
 	}
 
+	if IsReconciliationPaused(workspace) {
+		logger.Info("Reconciliation paused, skipping desired status update")
+		return
+	}
+
+	if !h.scope.Matches(workspace) {
+		logger.V(1).Info("Workspace outside the scope of this instance, skipping desired status update")
+		return
+	}
+
 	// Add annotation to track preemption reason
+	original := workspace.DeepCopy()
 	if desiredStatus == DesiredStateStopped {
 		if workspace.Annotations == nil {
 			workspace.Annotations = make(map[string]string)
@@ -286,7 +319,7 @@ func (h *PodEventHandler) updateWorkspaceDesiredStatus(ctx context.Context, work
 		workspace.Spec.DesiredStatus = desiredStatus
 	}
 
-	if err := h.client.Update(ctx, workspace); err != nil {
+	if err := workspaceutil.PatchChanges(ctx, h.client, workspace, original); err != nil {
 		logger.Error(err, "Failed to update workspace")
 	} else {
 		logger.Info("Successfully updated workspace due to preemption", "desiredStatus", desiredStatus)
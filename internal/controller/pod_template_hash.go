@@ -0,0 +1,143 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+)
+
+// PodTemplateHash fingerprints the parts of a pod template that change the behavior of the pod.
+// Any change to the fingerprint restarts the workspace pod, so the fingerprint is stable across
+// cosmetic rendering changes:
+//   - lists whose order Kubernetes ignores (env vars, ports, mounts, volumes, tolerations, pull
+//     secrets) are sorted;
+//   - annotations with the reserved prefix are left out, they only record operator state;
+//   - quantities are compared in their canonical form.
+//
+// The golden values in pod_template_hash_test.go pin the fingerprint of reference workspaces.
+func PodTemplateHash(template *corev1.PodTemplateSpec) (string, error) {
+	normalized := normalizePodTemplate(template)
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize pod template: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// normalizePodTemplate returns a copy of the template in its order-independent form
+func normalizePodTemplate(template *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	normalized := template.DeepCopy()
+
+	normalized.Annotations = nil
+	for key, value := range template.Annotations {
+		if strings.HasPrefix(key, ReservedMetadataPrefix) {
+			continue
+		}
+		if normalized.Annotations == nil {
+			normalized.Annotations = map[string]string{}
+		}
+		normalized.Annotations[key] = value
+	}
+
+	spec := &normalized.Spec
+	for i := range spec.InitContainers {
+		normalizeContainer(&spec.InitContainers[i])
+	}
+	for i := range spec.Containers {
+		normalizeContainer(&spec.Containers[i])
+	}
+	sort.SliceStable(spec.Volumes, func(i, j int) bool {
+		return spec.Volumes[i].Name < spec.Volumes[j].Name
+	})
+	sort.SliceStable(spec.ImagePullSecrets, func(i, j int) bool {
+		return spec.ImagePullSecrets[i].Name < spec.ImagePullSecrets[j].Name
+	})
+	sort.SliceStable(spec.Tolerations, func(i, j int) bool {
+		return tolerationKey(spec.Tolerations[i]) < tolerationKey(spec.Tolerations[j])
+	})
+	return normalized
+}
+
+// normalizeContainer sorts the lists of the container whose order Kubernetes ignores.
+// Env vars are sorted too: their order only matters for $(VAR) references to a later variable.
+func normalizeContainer(container *corev1.Container) {
+	sort.SliceStable(container.Env, func(i, j int) bool {
+		return container.Env[i].Name < container.Env[j].Name
+	})
+	sort.SliceStable(container.Ports, func(i, j int) bool {
+		if container.Ports[i].ContainerPort != container.Ports[j].ContainerPort {
+			return container.Ports[i].ContainerPort < container.Ports[j].ContainerPort
+		}
+		return container.Ports[i].Protocol < container.Ports[j].Protocol
+	})
+	sort.SliceStable(container.VolumeMounts, func(i, j int) bool {
+		return container.VolumeMounts[i].MountPath < container.VolumeMounts[j].MountPath
+	})
+}
+
+func tolerationKey(toleration corev1.Toleration) string {
+	return strings.Join([]string{toleration.Key, string(toleration.Operator), toleration.Value, string(toleration.Effect)}, "\x00")
+}
+
+// stampPodTemplate records the fingerprint of the pod template and the workspace generation it was
+// rendered from on the deployment
+func stampPodTemplate(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace) error {
+	hash, err := PodTemplateHash(&deployment.Spec.Template)
+	if err != nil {
+		return err
+	}
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[AnnotationPodTemplateHash] = hash
+	deployment.Annotations[AnnotationRenderedGeneration] = strconv.FormatInt(workspace.Generation, 10)
+	return nil
+}
+
+// existingPodTemplateHash returns the recorded fingerprint of the deployment pod template. Deployments
+// created before fingerprints were recorded are fingerprinted from their pod template.
+func existingPodTemplateHash(deployment *appsv1.Deployment) (string, error) {
+	if hash, ok := deployment.Annotations[AnnotationPodTemplateHash]; ok {
+		return hash, nil
+	}
+	return PodTemplateHash(&deployment.Spec.Template)
+}
+
+// podTemplateRolloutDeferred returns true if the pod template only differs because another operator
+// version renders the same workspace differently. Such rollouts wait for the next restart of the
+// workspace, which recreates its deployment, or for an administrator maintenance window, instead of
+// restarting every workspace pod when the operator is upgraded. Changes to the workspace itself, or
+// rendered by the same operator version (e.g. an access strategy update), roll out immediately.
+func podTemplateRolloutDeferred(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace, now time.Time) bool {
+	if maintenanceWindowOpen(workspace, now) {
+		return false
+	}
+	if deployment.Spec.Template.Annotations[AnnotationManagedByVersion] == buildinfo.Version {
+		return false
+	}
+	renderedGeneration, ok := deployment.Annotations[AnnotationRenderedGeneration]
+	return !ok || renderedGeneration == strconv.FormatInt(workspace.Generation, 10)
+}
+
+// maintenanceWindowOpen returns true while an administrator maintenance intent is active on the workspace
+func maintenanceWindowOpen(workspace *workspacev1alpha1.Workspace, now time.Time) bool {
+	record, ok := parseDesiredStatusIntent(workspace.Annotations[AnnotationMaintenanceIntent])
+	return ok && !record.isExpired(now)
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// goldenPodTemplateHashes pin the pod template fingerprint of reference workspaces. A failure means
+// the operator renders these workspaces differently, and an upgrade restarts their pods only once
+// they restart or a maintenance window opens. Only update a value after confirming the rendering
+// change is intended and behavior-changing; cosmetic changes must keep the fingerprint stable.
+var goldenPodTemplateHashes = map[string]string{
+	"minimal": "7589ffc942a392ff",
+	"full":    "ec43f73bafb621d9",
+}
+
+func newGoldenHashWorkspaces() map[string]*workspacev1alpha1.Workspace {
+	fsGroup := int64(100)
+	return map[string]*workspacev1alpha1.Workspace{
+		"minimal": {
+			ObjectMeta: metav1.ObjectMeta{Name: "golden", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image: "jupyter/base-notebook:2024-01-01",
+			},
+		},
+		"full": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "golden",
+				Namespace:   "team-a",
+				Labels:      map[string]string{"team": "a"},
+				Annotations: map[string]string{"prometheus.io/scrape": "true"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image: "jupyter/scipy-notebook:2024-01-01",
+				Resources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+				Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+				Volumes: []workspacev1alpha1.VolumeSpec{
+					{Name: "datasets", PersistentVolumeClaimName: "datasets", MountPath: "/data"},
+				},
+				Env: []corev1.EnvVar{
+					{Name: "JUPYTER_ENABLE_LAB", Value: "yes"},
+					{Name: "GRANT_SUDO", Value: "no"},
+				},
+				NodeSelector:       map[string]string{"node-pool": "notebooks"},
+				Tolerations:        []corev1.Toleration{{Key: "notebooks", Operator: corev1.TolerationOpExists}},
+				ServiceAccountName: "notebook",
+				PodSecurityContext: &corev1.PodSecurityContext{FSGroup: &fsGroup},
+			},
+		},
+	}
+}
+
+func newHashTestDeploymentBuilder(t *testing.T) *DeploymentBuilder {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	return NewDeploymentBuilder(scheme, WorkspaceControllerOptions{
+		ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		ApplicationImagesRegistry:   "quay.io",
+	})
+}
+
+func renderHashTestDeployment(t *testing.T, workspace *workspacev1alpha1.Workspace) *appsv1.Deployment {
+	deployment, err := newHashTestDeploymentBuilder(t).BuildDeploymentWithAccessStrategy(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	return deployment
+}
+
+func TestPodTemplateHashGoldenValues(t *testing.T) {
+	for name, workspace := range newGoldenHashWorkspaces() {
+		t.Run(name, func(t *testing.T) {
+			deployment := renderHashTestDeployment(t, workspace)
+			assert.Equal(t, goldenPodTemplateHashes[name], deployment.Annotations[AnnotationPodTemplateHash],
+				"the pod template rendering changed; see goldenPodTemplateHashes before updating the value")
+		})
+	}
+}
+
+func TestPodTemplateHashIgnoresCosmeticChanges(t *testing.T) {
+	template := &renderHashTestDeployment(t, newGoldenHashWorkspaces()["full"]).Spec.Template
+	hash, err := PodTemplateHash(template)
+	require.NoError(t, err)
+
+	reordered := template.DeepCopy()
+	env := reordered.Spec.Containers[0].Env
+	env[0], env[1] = env[1], env[0]
+	volumes := reordered.Spec.Volumes
+	volumes[0], volumes[1] = volumes[1], volumes[0]
+	mounts := reordered.Spec.Containers[0].VolumeMounts
+	mounts[0], mounts[1] = mounts[1], mounts[0]
+	reordered.Annotations[AnnotationManagedByVersion] = "v9.9.9"
+	reordered.Annotations[ReservedMetadataPrefix+"new-default"] = "true"
+	reordered.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("0.5")
+
+	reorderedHash, err := PodTemplateHash(reordered)
+	require.NoError(t, err)
+	assert.Equal(t, hash, reorderedHash)
+	assert.Equal(t, "JUPYTER_ENABLE_LAB", template.Spec.Containers[0].Env[0].Name, "the template itself is left unchanged")
+}
+
+func TestPodTemplateHashDetectsBehaviorChanges(t *testing.T) {
+	template := &renderHashTestDeployment(t, newGoldenHashWorkspaces()["full"]).Spec.Template
+	hash, err := PodTemplateHash(template)
+	require.NoError(t, err)
+
+	changes := map[string]func(*corev1.PodTemplateSpec){
+		"image":      func(p *corev1.PodTemplateSpec) { p.Spec.Containers[0].Image = "jupyter/scipy-notebook:2025-01-01" },
+		"env value":  func(p *corev1.PodTemplateSpec) { p.Spec.Containers[0].Env[0].Value = "no" },
+		"annotation": func(p *corev1.PodTemplateSpec) { p.Annotations["sidecar.istio.io/inject"] = "true" },
+		"label":      func(p *corev1.PodTemplateSpec) { p.Labels["team"] = "b" },
+		"memory": func(p *corev1.PodTemplateSpec) {
+			p.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("2Gi")
+		},
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			changed := template.DeepCopy()
+			change(changed)
+			changedHash, err := PodTemplateHash(changed)
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, changedHash)
+		})
+	}
+}
+
+func TestNeedsUpdateIgnoresReservedWorkspaceAnnotations(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	existing := renderHashTestDeployment(t, workspace)
+
+	workspace.Annotations[AnnotationStaleExempt] = "true"
+	needsUpdate, err := newHashTestDeploymentBuilder(t).NeedsUpdate(context.Background(), existing, workspace, nil)
+	require.NoError(t, err)
+	assert.False(t, needsUpdate, "operator state annotations must not restart the workspace")
+}
+
+// newRolloutTestWorkspace returns an available workspace at generation 2
+func newRolloutTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Generation = 2
+	workspace.Spec.Image = "jupyter/base-notebook:2024-01-01"
+	workspace.Status.Conditions = []metav1.Condition{{Type: ConditionTypeAvailable, Status: metav1.ConditionTrue}}
+	return workspace
+}
+
+// newPreviousVersionDeployment returns the deployment of the workspace as rendered by a previous
+// operator version, with a pod template this version renders differently
+func newPreviousVersionDeployment(t *testing.T, workspace *workspacev1alpha1.Workspace) *appsv1.Deployment {
+	deployment := renderHashTestDeployment(t, workspace)
+	deployment.Spec.Template.Annotations[AnnotationManagedByVersion] = "v0.0.1"
+	deployment.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/old-log"
+	hash, err := PodTemplateHash(&deployment.Spec.Template)
+	require.NoError(t, err)
+	deployment.Annotations[AnnotationPodTemplateHash] = hash
+	return deployment
+}
+
+func getRolloutTestDeployment(t *testing.T, k8sClient client.Client) *appsv1.Deployment {
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, k8sClient.Get(context.Background(),
+		client.ObjectKey{Name: GenerateDeploymentName("ws"), Namespace: "team-a"}, deployment))
+	return deployment
+}
+
+func TestEnsureDeploymentDefersRolloutRenderedByNewOperatorVersion(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newPreviousVersionDeployment(t, workspace))
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "/dev/old-log", stored.Spec.Template.Spec.Containers[0].TerminationMessagePath, "the pod is not restarted")
+	assert.NotEmpty(t, stored.Annotations[AnnotationPendingPodTemplateHash])
+}
+
+func TestEnsureDeploymentRollsOutWorkspaceChangeImmediately(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newPreviousVersionDeployment(t, workspace))
+
+	workspace.Generation = 3
+	workspace.Spec.Image = "jupyter/base-notebook:2025-01-01"
+	_, err := sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "jupyter/base-notebook:2025-01-01", stored.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, buildinfo.Version, stored.Spec.Template.Annotations[AnnotationManagedByVersion])
+	assert.Equal(t, "3", stored.Annotations[AnnotationRenderedGeneration])
+	assert.NotContains(t, stored.Annotations, AnnotationPendingPodTemplateHash)
+}
+
+func TestEnsureDeploymentRollsOutDuringMaintenanceWindow(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	maintenance, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+		DesiredStatus: DesiredStateRunning,
+		ExpiresAt:     &metav1.Time{Time: time.Now().Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	workspace.Annotations = map[string]string{AnnotationMaintenanceIntent: maintenance}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, newPreviousVersionDeployment(t, workspace))
+
+	_, err = sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Empty(t, stored.Spec.Template.Spec.Containers[0].TerminationMessagePath)
+}
+
+func TestEnsureDeploymentStampsDeploymentWithoutFingerprint(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	legacy := newPreviousVersionDeployment(t, workspace)
+	delete(legacy.Annotations, AnnotationPodTemplateHash)
+	delete(legacy.Annotations, AnnotationRenderedGeneration)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, legacy)
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "/dev/old-log", stored.Spec.Template.Spec.Containers[0].TerminationMessagePath, "the pod is not restarted")
+	assert.NotEmpty(t, stored.Annotations[AnnotationPodTemplateHash])
+	assert.Equal(t, "2", stored.Annotations[AnnotationRenderedGeneration])
+}
@@ -0,0 +1,255 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// PostStartScriptVolumeName is the name of the pod volume holding the post-start script of the template
+	PostStartScriptVolumeName = "post-start-script"
+
+	// PostStartScriptMountPath is where the workspace container mounts the post-start script
+	PostStartScriptMountPath = "/opt/jupyter-k8s/post-start"
+
+	// PostStartScriptKey is the key of the post-start ConfigMap of a workspace holding the script
+	PostStartScriptKey = "post-start.sh"
+
+	// PostStartStatusFile is where the postStart hook of the workspace container records the exit code of the
+	// script, which the controller reads once the pod is ready
+	PostStartStatusFile = "/tmp/jupyter-k8s-post-start.status"
+
+	// PostStartLogFile is where the postStart hook of the workspace container writes the output of the script
+	PostStartLogFile = "/tmp/jupyter-k8s-post-start.log"
+
+	// postStartScriptMode lets any user of the workspace container run the script
+	postStartScriptMode int32 = 0o555
+)
+
+// postStartHookScript runs the script and records its exit code; it always succeeds, since the kubelet kills
+// a container whose postStart hook fails. A workspace exec postStart hook, passed as arguments, runs after it.
+var postStartHookScript = fmt.Sprintf(
+	`status=0; %s >%s 2>&1 || status=$?; echo "$status" >%s; if [ "$#" -gt 0 ]; then exec "$@"; fi`,
+	path.Join(PostStartScriptMountPath, PostStartScriptKey), PostStartLogFile, PostStartStatusFile)
+
+// ResolvePostStartScript records the post-start script of the admitted template revision in
+// Status.PostStartScript; EnsurePostStartConfigMap keeps the ConfigMap of the workspace holding it. The script
+// of a workspace whose deployment exists is kept until it restarts. The status is updated in memory. A nil
+// template, for workspaces without one, clears the script.
+func (rm *ResourceManager) ResolvePostStartScript(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.PostStartScript = ""
+		return nil
+	}
+	script := ""
+	if template.Spec.Lifecycle != nil {
+		script = template.Spec.Lifecycle.PostStartScript
+	}
+	if workspace.Status.PostStartScript == script {
+		return nil
+	}
+
+	exists, err := rm.deploymentExists(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if exists {
+		logDecision(logf.FromContext(ctx), "DeferPostStartScriptUpdate",
+			"reason", "the template post-start script applies when the workspace restarts")
+		return nil
+	}
+	workspace.Status.PostStartScript = script
+	return nil
+}
+
+// BuildPostStartConfigMap returns the ConfigMap, owned by the workspace, holding the post-start script resolved
+// in its status
+func BuildPostStartConfigMap(workspace *workspacev1alpha1.Workspace, scheme *runtime.Scheme) (*corev1.ConfigMap, error) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      postStartConfigMapNameFor(workspace),
+			Namespace: workspace.Namespace,
+			Labels:    GenerateLabels(workspace.Name),
+		},
+		Data: map[string]string{PostStartScriptKey: workspace.Status.PostStartScript},
+	}
+	if err := controllerutil.SetControllerReference(workspace, configMap, scheme); err != nil {
+		return nil, fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	return configMap, nil
+}
+
+// EnsurePostStartConfigMap creates or updates the post-start ConfigMap of the workspace from its status, and
+// deletes it once the workspace has no script
+func (rm *ResourceManager) EnsurePostStartConfigMap(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	existing := &corev1.ConfigMap{}
+	err := rm.client.Get(ctx, client.ObjectKey{Name: postStartConfigMapNameFor(workspace), Namespace: workspace.Namespace}, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get post-start ConfigMap: %w", err)
+	}
+	found := err == nil
+
+	if workspace.Status.PostStartScript == "" {
+		if !found {
+			return nil
+		}
+		if ownership, _ := classifyChild(workspace, existing); ownership != childOwned {
+			return nil
+		}
+		if err := rm.client.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete post-start ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	desired, err := BuildPostStartConfigMap(workspace, rm.scheme)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if err := rm.client.Create(ctx, desired); err != nil {
+			if apierrors.IsAlreadyExists(err) {
+				return rm.adoptExistingChild(ctx, workspace, "ConfigMap", desired, &corev1.ConfigMap{})
+			}
+			return fmt.Errorf("failed to create post-start ConfigMap: %w", err)
+		}
+		return nil
+	}
+
+	ownership, controllerRef := classifyChild(workspace, existing)
+	if ownership == childForeign {
+		return newChildResourceConflict("ConfigMap", existing, controllerRef)
+	}
+	if ownership == childOwned && equality.Semantic.DeepEqual(existing.Data, desired.Data) {
+		return nil
+	}
+	existing.Data = desired.Data
+	if err := controllerutil.SetControllerReference(workspace, existing, rm.scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference on post-start ConfigMap: %w", err)
+	}
+	if err := rm.client.Update(ctx, existing); err != nil {
+		return fmt.Errorf("failed to update post-start ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// applyPostStartScript mounts the post-start script resolved in the status of the workspace in its container,
+// and runs it in the postStart hook, before the exec postStart hook of the workspace. A workspace with another
+// kind of postStart hook keeps it, without the script; the webhook rejects it.
+func applyPostStartScript(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	container := workspaceContainer(podSpec)
+	if workspace.Status.PostStartScript == "" || container == nil {
+		return
+	}
+	lifecycle := &corev1.Lifecycle{}
+	if container.Lifecycle != nil {
+		lifecycle = container.Lifecycle.DeepCopy()
+	}
+	command := []string{"sh", "-c", postStartHookScript, "post-start"}
+	if hook := lifecycle.PostStart; hook != nil {
+		if hook.Exec == nil {
+			return
+		}
+		command = append(command, hook.Exec.Command...)
+	}
+	lifecycle.PostStart = &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: command}}
+	container.Lifecycle = lifecycle
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: PostStartScriptVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: postStartConfigMapNameFor(workspace)},
+				DefaultMode:          ptr.To(postStartScriptMode),
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      PostStartScriptVolumeName,
+		MountPath: PostStartScriptMountPath,
+		ReadOnly:  true,
+	})
+}
+
+// reconcilePostStartScript records, once per workspace pod, the outcome of the post-start script in the
+// PostStartFailed condition, with a Warning event when the script failed. The status is updated in memory.
+// An outcome that cannot be read is recorded as Unknown.
+func (sm *StateMachine) reconcilePostStartScript(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Status.PostStartScript == "" {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypePostStartFailed)
+		workspace.Status.PostStartPodUID = ""
+		return nil
+	}
+
+	pod, err := sm.findReadyWorkspacePod(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if pod == nil {
+		return nil
+	}
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypePostStartFailed)
+	if condition != nil && workspace.Status.PostStartPodUID == string(pod.UID) {
+		return nil
+	}
+
+	status, reason, message := sm.readPostStartOutcome(ctx, pod)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypePostStartFailed, status, reason, message))
+	workspace.Status.PostStartPodUID = string(pod.UID)
+	if status == metav1.ConditionTrue {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, reason, message)
+	}
+	return nil
+}
+
+// readPostStartOutcome reads the exit code the postStart hook recorded in the workspace container, and the
+// end of the output of the script, and returns the PostStartFailed condition to record
+func (sm *StateMachine) readPostStartOutcome(ctx context.Context, pod *corev1.Pod) (metav1.ConditionStatus, string, string) {
+	if sm.podExec == nil {
+		return metav1.ConditionUnknown, ReasonPostStartScriptUnavailable,
+			"The outcome of the post-start script could not be read: pod exec is not available"
+	}
+	cmd := []string{"sh", "-c", fmt.Sprintf("cat %s && { tail -c %d %s 2>/dev/null || true; }",
+		PostStartStatusFile, MaxStartupCheckOutputLength, PostStartLogFile)}
+	output, err := sm.podExec.ExecInPod(ctx, pod, workspaceContainerName, cmd, "")
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to read the outcome of the post-start script", "pod", pod.Name)
+		return metav1.ConditionUnknown, ReasonPostStartScriptUnavailable,
+			fmt.Sprintf("The outcome of the post-start script could not be read: %v", err)
+	}
+
+	exitCode, scriptOutput, _ := strings.Cut(output, "\n")
+	code, err := strconv.Atoi(strings.TrimSpace(exitCode))
+	switch {
+	case err != nil:
+		return metav1.ConditionUnknown, ReasonPostStartScriptUnavailable,
+			fmt.Sprintf("The post-start script recorded no exit code: %q", strings.TrimSpace(exitCode))
+	case code == 0:
+		return metav1.ConditionFalse, ReasonPostStartScriptSucceeded, "The post-start script succeeded"
+	default:
+		return metav1.ConditionTrue, ReasonPostStartScriptFailed,
+			withStartupCheckOutput(fmt.Sprintf("The post-start script exited with code %d", code), strings.TrimSpace(scriptOutput))
+	}
+}
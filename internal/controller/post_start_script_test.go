@@ -0,0 +1,193 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const postStartTestScript = "#!/bin/sh\ncp /etc/jupyter/condarc \"$HOME/.condarc\"\n"
+
+// newPostStartTestStateMachine returns a state machine for a workspace whose template has the post-start script
+func newPostStartTestStateMachine(t *testing.T, script string, objects ...client.Object) (*StateMachine, client.Client, *workspacev1alpha1.Workspace) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "conda", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "Conda",
+			Lifecycle:   &workspacev1alpha1.TemplateLifecycleSpec{PostStartScript: script},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.UID = "ws-uid"
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "conda"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, append(objects, workspace, template)...)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	return sm, k8sClient, workspace
+}
+
+func TestPodsRunThePostStartScriptInTheirPostStartHook(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Status.PostStartScript = postStartTestScript
+
+	container := renderHashTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	require.NotNil(t, container.Lifecycle)
+	require.NotNil(t, container.Lifecycle.PostStart.Exec)
+	assert.Equal(t, []string{"sh", "-c", postStartHookScript, "post-start"}, container.Lifecycle.PostStart.Exec.Command)
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{
+		Name: PostStartScriptVolumeName, MountPath: PostStartScriptMountPath, ReadOnly: true})
+
+	// The exec hook of the workspace runs after the script; other hooks keep the workspace without the script
+	workspace.Spec.Lifecycle = &corev1.Lifecycle{PostStart: &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{Command: []string{"touch", "/tmp/started"}},
+	}}
+	container = renderHashTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"sh", "-c", postStartHookScript, "post-start", "touch", "/tmp/started"},
+		container.Lifecycle.PostStart.Exec.Command)
+	assert.Equal(t, []string{"touch", "/tmp/started"}, workspace.Spec.Lifecycle.PostStart.Exec.Command,
+		"the workspace lifecycle is not modified")
+
+	workspace.Spec.Lifecycle = &corev1.Lifecycle{PostStart: &corev1.LifecycleHandler{
+		HTTPGet: &corev1.HTTPGetAction{Path: "/started"},
+	}}
+	container = renderHashTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Nil(t, container.Lifecycle.PostStart.Exec)
+	assert.NotContains(t, container.VolumeMounts, corev1.VolumeMount{
+		Name: PostStartScriptVolumeName, MountPath: PostStartScriptMountPath, ReadOnly: true})
+}
+
+func TestPostStartHookScriptRecordsTheExitCodeAndSucceeds(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, PostStartScriptKey)
+	require.NoError(t, os.WriteFile(script, []byte("echo configuring\nexit 3\n"), 0o755))
+	hook := strings.NewReplacer(
+		path.Join(PostStartScriptMountPath, PostStartScriptKey), script,
+		PostStartLogFile, filepath.Join(dir, "log"),
+		PostStartStatusFile, filepath.Join(dir, "status"),
+	).Replace(postStartHookScript)
+
+	output, err := exec.Command("sh", "-c", hook, "post-start", "echo", "workspace hook").CombinedOutput()
+	require.NoError(t, err, "a failing script does not fail the hook")
+	assert.Equal(t, "workspace hook\n", string(output))
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	require.NoError(t, err)
+	assert.Equal(t, "3\n", string(status))
+	log, err := os.ReadFile(filepath.Join(dir, "log"))
+	require.NoError(t, err)
+	assert.Equal(t, "configuring\n", string(log))
+}
+
+func TestResolvePostStartScriptKeepsTheConfigMapOfTheWorkspace(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newPostStartTestStateMachine(t, postStartTestScript)
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "conda", Namespace: "team-a"}, template))
+
+	require.NoError(t, sm.resourceManager.ResolvePostStartScript(ctx, workspace, template))
+	require.NoError(t, sm.resourceManager.EnsurePostStartConfigMap(ctx, workspace))
+	assert.Equal(t, postStartTestScript, workspace.Status.PostStartScript)
+	configMap := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: postStartConfigMapNameFor(workspace), Namespace: workspace.Namespace}
+	require.NoError(t, k8sClient.Get(ctx, key, configMap))
+	assert.Equal(t, postStartTestScript, configMap.Data[PostStartScriptKey])
+	assert.True(t, metav1.IsControlledBy(configMap, workspace))
+
+	// A template change applies when the workspace restarts
+	deployment, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+	require.NoError(t, err)
+	template.Spec.Lifecycle = nil
+	require.NoError(t, sm.resourceManager.ResolvePostStartScript(ctx, workspace, template))
+	require.NoError(t, sm.resourceManager.EnsurePostStartConfigMap(ctx, workspace))
+	assert.Equal(t, postStartTestScript, workspace.Status.PostStartScript)
+	require.NoError(t, k8sClient.Get(ctx, key, configMap))
+
+	require.NoError(t, k8sClient.Delete(ctx, deployment))
+	require.NoError(t, sm.resourceManager.ResolvePostStartScript(ctx, workspace, template))
+	require.NoError(t, sm.resourceManager.EnsurePostStartConfigMap(ctx, workspace))
+	assert.Empty(t, workspace.Status.PostStartScript)
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, key, configMap)))
+}
+
+func TestEnsurePostStartConfigMapRejectsAForeignConfigMap(t *testing.T) {
+	foreign := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "workspace-ws-post-start", Namespace: "team-a"}}
+	sm, _, workspace := newPostStartTestStateMachine(t, postStartTestScript, foreign)
+	require.Equal(t, foreign.Name, postStartConfigMapNameFor(workspace))
+	workspace.Status.PostStartScript = postStartTestScript
+
+	err := sm.resourceManager.EnsurePostStartConfigMap(context.Background(), workspace)
+	_, isConflict := asChildResourceConflict(err)
+	assert.True(t, isConflict)
+}
+
+func TestReconcilePostStartScriptRecordsTheOutcomeOncePerPod(t *testing.T) {
+	ctx := context.Background()
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, workspaceContainerName, mock.Anything, "").
+		Return("3\npip: index unreachable\n", nil).Once()
+	sm, _, workspace := newPostStartTestStateMachine(t, postStartTestScript, newStartupCheckTestPod("pod-1"))
+	sm.podExec = execUtil
+	workspace.Status.PostStartScript = postStartTestScript
+
+	require.NoError(t, sm.reconcilePostStartScript(ctx, workspace))
+	require.NoError(t, sm.reconcilePostStartScript(ctx, workspace))
+	execUtil.AssertExpectations(t)
+
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypePostStartFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonPostStartScriptFailed, condition.Reason)
+	assert.Equal(t, "The post-start script exited with code 3: pip: index unreachable", condition.Message)
+	assert.Equal(t, "pod-1", workspace.Status.PostStartPodUID)
+	recorder := sm.recorder.(*record.FakeRecorder)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning PostStartScriptFailed")
+
+	// Without a script the condition is removed
+	workspace.Status.PostStartScript = ""
+	require.NoError(t, sm.reconcilePostStartScript(ctx, workspace))
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypePostStartFailed))
+}
+
+func TestReadPostStartOutcome(t *testing.T) {
+	for name, tc := range map[string]struct {
+		output         string
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		"succeeded":    {output: "0\n", expectedStatus: metav1.ConditionFalse, expectedReason: ReasonPostStartScriptSucceeded},
+		"failed":       {output: "1\n", expectedStatus: metav1.ConditionTrue, expectedReason: ReasonPostStartScriptFailed},
+		"not recorded": {output: "", expectedStatus: metav1.ConditionUnknown, expectedReason: ReasonPostStartScriptUnavailable},
+	} {
+		t.Run(name, func(t *testing.T) {
+			execUtil := &MockPodExecUtil{}
+			execUtil.On("ExecInPod", mock.Anything, mock.Anything, workspaceContainerName, mock.Anything, "").
+				Return(tc.output, nil)
+			sm := &StateMachine{podExec: execUtil}
+
+			status, reason, _ := sm.readPostStartOutcome(context.Background(), newStartupCheckTestPod("pod-1"))
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// isPreemptionEvent returns true if the event reports a pod deleted by the scheduler to make room for pods
+// of higher priority. The scheduler reports it with the Preempted reason.
+func isPreemptionEvent(event *corev1.Event) bool {
+	return event.InvolvedObject.Kind == KindPod &&
+		(event.Reason == ReasonPreempted || event.Reason == DesiredStateStopped) &&
+		strings.Contains(event.Message, "Preempted")
+}
+
+// isPreempted returns true if the workspace was stopped for the preemption of its pod
+func isPreempted(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.Annotations[PreemptionReasonAnnotation] == PreemptedReason
+}
+
+// preemptionMessage describes the preemption of the workspace pod, with the priority it ran at
+func preemptionMessage(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Spec.PriorityClassName == "" {
+		return PreemptedReason + ": the scheduler deleted the workspace pod for pods of higher priority"
+	}
+	return fmt.Sprintf("%s: the scheduler deleted the workspace pod for pods of higher priority than PriorityClass %s",
+		PreemptedReason, workspace.Spec.PriorityClassName)
+}
+
+// newPreemptedCondition returns the Preempted condition of a workspace stopped for the preemption of its pod
+func newPreemptedCondition(workspace *workspacev1alpha1.Workspace) metav1.Condition {
+	return NewCondition(ConditionTypePreempted, metav1.ConditionTrue, ReasonPreempted, preemptionMessage(workspace))
+}
+
+// clearPreemption removes the Preempted condition in memory and the preemption annotation once the workspace
+// runs again, so that a later stop is not reported as a preemption
+func (sm *StateMachine) clearPreemption(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypePreempted)
+	if !isPreempted(workspace) {
+		return nil
+	}
+
+	updated := workspace.DeepCopy()
+	delete(updated.Annotations, PreemptionReasonAnnotation)
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, updated, workspace); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", PreemptionReasonAnnotation, err)
+	}
+	// Keep the status updated in memory by this reconcile
+	updated.ObjectMeta.DeepCopyInto(&workspace.ObjectMeta)
+	return nil
+}
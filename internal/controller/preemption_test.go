@@ -0,0 +1,71 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func TestPreemptionEventsOfTheScheduler(t *testing.T) {
+	pod := corev1.ObjectReference{Kind: "Pod", Name: "jupyter-ws-abc123-xyz789", Namespace: "team-a"}
+	for name, tc := range map[string]struct {
+		event     corev1.Event
+		preempted bool
+	}{
+		"scheduler": {corev1.Event{InvolvedObject: pod, Reason: "Preempted",
+			Message: "Preempted by pod 6c7a0b1e-5d2f-4c1e-9a57-3f0e5b8d2c41 on node ip-10-0-1-17"}, true},
+		"stopped": {corev1.Event{InvolvedObject: pod, Reason: "Stopped", Message: "Pod was Preempted by scheduler"}, true},
+		"killing": {corev1.Event{InvolvedObject: pod, Reason: "Killing", Message: "Stopping container jupyter"}, false},
+		"not a pod": {corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "Deployment"}, Reason: "Preempted",
+			Message: "Preempted by pod 6c7a0b1e"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.preempted, isPreemptionEvent(&tc.event))
+		})
+	}
+}
+
+func TestPreemptionMessageNamesThePriorityClass(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	assert.Equal(t, "Workspace preempted due to resource contention: the scheduler deleted the workspace pod "+
+		"for pods of higher priority", preemptionMessage(workspace))
+
+	workspace.Spec.PriorityClassName = "notebooks"
+	assert.Equal(t, "Workspace preempted due to resource contention: the scheduler deleted the workspace pod "+
+		"for pods of higher priority than PriorityClass notebooks", preemptionMessage(workspace))
+}
+
+func TestPreemptionIsClearedOnceTheWorkspaceRunsAgain(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationCreatedBy: "alice", PreemptionReasonAnnotation: PreemptedReason}
+	workspace.Status.Conditions = []metav1.Condition{newPreemptedCondition(workspace)}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	startedAt := metav1.NewTime(time.Now())
+	workspace.Status.LastStartTime = &startedAt
+	require.NoError(t, sm.clearPreemption(ctx, workspace))
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypePreempted))
+	assert.NotContains(t, workspace.Annotations, PreemptionReasonAnnotation)
+	assert.Equal(t, &startedAt, workspace.Status.LastStartTime, "the status of the reconciliation is kept in memory")
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, PreemptionReasonAnnotation)
+	assert.Equal(t, "alice", stored.Annotations[AnnotationCreatedBy])
+}
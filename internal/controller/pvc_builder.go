@@ -33,9 +33,34 @@ func NewPVCBuilder(scheme *runtime.Scheme) *PVCBuilder {
 
 // ResolvedStorageConfig contains all resolved storage configuration
 type ResolvedStorageConfig struct {
+	Mode             workspacev1alpha1.StorageMode
 	Size             resource.Quantity
 	StorageClassName *string
 	MountPath        string
+	SubPath          string
+}
+
+// StorageModeOf returns the storage mode of the workspace, Persistent unless it sets another, or an empty
+// mode for a workspace without storage
+func StorageModeOf(workspace *workspacev1alpha1.Workspace) workspacev1alpha1.StorageMode {
+	if workspace.Spec.Storage == nil {
+		return ""
+	}
+	if workspace.Spec.Storage.Mode == "" {
+		return workspacev1alpha1.StorageModePersistent
+	}
+	return workspace.Spec.Storage.Mode
+}
+
+// hasPersistentStorage returns true if the home directory of the workspace is kept in its PVC
+func hasPersistentStorage(workspace *workspacev1alpha1.Workspace) bool {
+	return StorageModeOf(workspace) == workspacev1alpha1.StorageModePersistent
+}
+
+// hasEphemeralStorage returns true if the home directory of the workspace is an emptyDir volume: the
+// workspace has no PVC to create or clean up
+func hasEphemeralStorage(workspace *workspacev1alpha1.Workspace) bool {
+	return StorageModeOf(workspace) == workspacev1alpha1.StorageModeEphemeral
 }
 
 // resolveStorageSize returns the storage size from workspace, with fallback to default
@@ -71,9 +96,11 @@ func ResolveStorageConfig(workspace *workspacev1alpha1.Workspace) *ResolvedStora
 	}
 
 	return &ResolvedStorageConfig{
+		Mode:             StorageModeOf(workspace),
 		Size:             resolveStorageSize(workspace),
 		StorageClassName: resolveStorageClassName(workspace),
 		MountPath:        resolveMountPath(workspace),
+		SubPath:          workspace.Spec.Storage.SubPath,
 	}
 }
 
@@ -81,8 +108,8 @@ func ResolveStorageConfig(workspace *workspacev1alpha1.Workspace) *ResolvedStora
 // It uses workspace storage configuration
 func (pb *PVCBuilder) BuildPVC(workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
 	storageConfig := ResolveStorageConfig(workspace)
-	if storageConfig == nil {
-		return nil, nil // No storage requested
+	if storageConfig == nil || storageConfig.Mode == workspacev1alpha1.StorageModeEphemeral {
+		return nil, nil // No storage requested, or none outliving the pod
 	}
 
 	pvc := &corev1.PersistentVolumeClaim{
@@ -90,6 +117,8 @@ func (pb *PVCBuilder) BuildPVC(workspace *workspacev1alpha1.Workspace) (*corev1.
 		Spec:       pb.buildPVCSpecWithSize(storageConfig.Size, storageConfig.StorageClassName),
 	}
 
+	applyChildMetadata(pvc, childMetadataOf(workspace).PVC)
+
 	// Set owner reference for garbage collection
 	if err := controllerutil.SetControllerReference(workspace, pvc, pb.scheme); err != nil {
 		return nil, fmt.Errorf("failed to set controller reference: %w", err)
@@ -101,9 +130,10 @@ func (pb *PVCBuilder) BuildPVC(workspace *workspacev1alpha1.Workspace) (*corev1.
 // buildObjectMeta creates the metadata for the PVC
 func (pb *PVCBuilder) buildObjectMeta(workspace *workspacev1alpha1.Workspace) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
-		Name:      GeneratePVCName(workspace.Name),
-		Namespace: workspace.Namespace,
-		Labels:    GenerateLabels(workspace.Name),
+		Name:        pvcNameFor(workspace),
+		Namespace:   workspace.Namespace,
+		Labels:      GenerateLabels(workspace.Name),
+		Annotations: GenerateAnnotations(),
 	}
 }
 
@@ -158,7 +188,8 @@ func (pb *PVCBuilder) NeedsUpdate(ctx context.Context, existingPVC *corev1.Persi
 	// 3. DO NOT check StorageClassName - it's immutable after creation
 	// Changing it would cause Kubernetes API to reject the update
 
-	return false, nil
+	// 4. Check the labels and annotations set from the template childMetadata
+	return !childMetadataInSync(existingPVC, desiredPVC, childMetadataOf(workspace).PVC), nil
 }
 
 // UpdatePVCSpec updates the existing PVC with the desired spec
@@ -178,6 +209,7 @@ func (pb *PVCBuilder) UpdatePVCSpec(ctx context.Context, existingPVC *corev1.Per
 	existingPVC.Spec.AccessModes = desiredPVC.Spec.AccessModes
 	existingPVC.Spec.Resources = desiredPVC.Spec.Resources
 	// DO NOT update StorageClassName - it's immutable after PVC creation
+	syncChildMetadata(existingPVC, desiredPVC, childMetadataOf(workspace).PVC)
 
 	return nil
 }
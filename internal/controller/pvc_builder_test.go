@@ -10,11 +10,14 @@ import (
 	"testing"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 func setupPVCBuilder() *PVCBuilder {
@@ -88,6 +91,57 @@ func TestPVCBuilder_NoStorage(t *testing.T) {
 	}
 }
 
+func TestPVCBuilder_EphemeralStorage(t *testing.T) {
+	builder := setupPVCBuilder()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{
+				Mode: workspacev1alpha1.StorageModeEphemeral,
+				Size: resource.MustParse("2Gi"),
+			},
+		},
+	}
+
+	pvc, err := builder.BuildPVC(workspace)
+	if err != nil {
+		t.Fatalf("BuildPVC failed: %v", err)
+	}
+	if pvc != nil {
+		t.Fatal("Expected nil PVC for ephemeral storage, got PVC")
+	}
+}
+
+func TestEphemeralStorageIsAnEmptyDirVolume(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{
+		Mode: workspacev1alpha1.StorageModeEphemeral,
+		Size: resource.MustParse("2Gi"),
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, pvc)
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	require.NoError(t, k8sClient.List(ctx, pvcs))
+	assert.Empty(t, pvcs.Items, "no PVC is created for ephemeral storage")
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	volume := deployment.Spec.Template.Spec.Volumes[0]
+	assert.Equal(t, "workspace-storage", volume.Name)
+	require.NotNil(t, volume.EmptyDir)
+	assert.Equal(t, "2Gi", volume.EmptyDir.SizeLimit.String())
+	assert.Nil(t, volume.PersistentVolumeClaim)
+
+	pvc, err = sm.resourceManager.EnsurePVCDeleted(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, pvc)
+	assert.Equal(t, workspacev1alpha1.StorageModeEphemeral, StorageModeOf(workspace))
+}
+
 func TestPVCBuilder_DefaultSize(t *testing.T) {
 	builder := setupPVCBuilder()
 	workspace := &workspacev1alpha1.Workspace{
@@ -228,3 +282,28 @@ func TestPVCBuilder_UpdateDetection(t *testing.T) {
 		t.Error("Expected update needed")
 	}
 }
+
+func TestPVCBuilder_EquivalentSizeNeedsNoUpdate(t *testing.T) {
+	ctx := context.Background()
+	builder := setupPVCBuilder()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("50Gi")},
+		},
+	}
+
+	existingPVC, err := builder.BuildPVC(workspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingPVC.Spec.Resources.Requests[corev1.ResourceStorage] = resource.MustParse("51200Mi")
+
+	needsUpdate, err := builder.NeedsUpdate(ctx, existingPVC, workspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if needsUpdate {
+		t.Error("Expected no update needed for an equivalent size")
+	}
+}
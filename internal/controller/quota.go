@@ -0,0 +1,227 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// quotaExceededPattern matches the message of the ResourceQuota admission plugin, e.g.
+// "exceeded quota: compute, requested: requests.cpu=4, used: requests.cpu=8, limited: requests.cpu=10"
+var quotaExceededPattern = regexp.MustCompile(`exceeded quota: ([^,\s]+), requested: (\S*), used: (\S*), limited: (\S*)`)
+
+// QuotaResourceShortfall describes one resource of a ResourceQuota that a request would exceed
+type QuotaResourceShortfall struct {
+	Resource  string
+	Requested resource.Quantity
+	Used      resource.Quantity
+	Limited   resource.Quantity
+}
+
+// Free returns the amount of the resource still available under the quota, floored at zero
+func (s QuotaResourceShortfall) Free() resource.Quantity {
+	free := s.Limited.DeepCopy()
+	free.Sub(s.Used)
+	if free.Sign() < 0 {
+		return resource.MustParse("0")
+	}
+	return free
+}
+
+// QuotaExceeded holds the details of a ResourceQuota denial
+type QuotaExceeded struct {
+	QuotaName string
+	Resources []QuotaResourceShortfall
+}
+
+// Message describes the denial in terms a workspace user understands
+func (q *QuotaExceeded) Message(namespace string) string {
+	parts := make([]string, 0, len(q.Resources))
+	for _, shortfall := range q.Resources {
+		free := shortfall.Free()
+		parts = append(parts, fmt.Sprintf("namespace %s has %s free, this workspace requests %s",
+			namespace, describeQuantity(shortfall.Resource, free), shortfall.Requested.String()))
+	}
+	return fmt.Sprintf("%s (ResourceQuota %s)", strings.Join(parts, "; "), q.QuotaName)
+}
+
+// describeQuantity renders a quantity of a quota resource, e.g. "2 CPUs" or "1Gi of memory"
+func describeQuantity(resourceName string, quantity resource.Quantity) string {
+	kind := ""
+	name := resourceName
+	if trimmed, ok := strings.CutPrefix(name, "limits."); ok {
+		kind, name = " limits", trimmed
+	} else if trimmed, ok := strings.CutPrefix(name, "requests."); ok {
+		name = trimmed
+	}
+
+	switch corev1.ResourceName(name) {
+	case corev1.ResourceCPU:
+		return fmt.Sprintf("%s CPUs%s", quantity.String(), kind)
+	case corev1.ResourceMemory, corev1.ResourceStorage, corev1.ResourceEphemeralStorage:
+		return fmt.Sprintf("%s of %s%s", quantity.String(), name, kind)
+	default:
+		return fmt.Sprintf("%s %s", quantity.String(), resourceName)
+	}
+}
+
+// ParseQuotaExceeded extracts the quota details from a ResourceQuota admission message
+func ParseQuotaExceeded(message string) (*QuotaExceeded, bool) {
+	match := quotaExceededPattern.FindStringSubmatch(message)
+	if match == nil {
+		return nil, false
+	}
+
+	requested := parseResourceList(match[2])
+	used := parseResourceList(match[3])
+	limited := parseResourceList(match[4])
+
+	names := make([]string, 0, len(requested))
+	for name := range requested {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	details := &QuotaExceeded{QuotaName: match[1]}
+	for _, name := range names {
+		details.Resources = append(details.Resources, QuotaResourceShortfall{
+			Resource:  name,
+			Requested: requested[name],
+			Used:      used[name],
+			Limited:   limited[name],
+		})
+	}
+	return details, true
+}
+
+// parseResourceList parses "cpu=4,memory=1Gi" into quantities, skipping malformed entries
+func parseResourceList(list string) map[string]resource.Quantity {
+	quantities := map[string]resource.Quantity{}
+	for _, entry := range strings.Split(list, ",") {
+		name, value, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			continue
+		}
+		quantities[name] = quantity
+	}
+	return quantities
+}
+
+// QuotaExceededFromError returns the quota details when err is a ResourceQuota denial
+func QuotaExceededFromError(err error) (*QuotaExceeded, bool) {
+	if err == nil || !apierrors.IsForbidden(err) {
+		return nil, false
+	}
+	return ParseQuotaExceeded(err.Error())
+}
+
+// QuotaExceededFromDeployment returns the quota details when the deployment cannot create
+// its pods because of a ResourceQuota; the condition is propagated from the ReplicaSet
+func QuotaExceededFromDeployment(deployment *appsv1.Deployment) (*QuotaExceeded, bool) {
+	if deployment == nil {
+		return nil, false
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue {
+			return ParseQuotaExceeded(condition.Message)
+		}
+	}
+	return nil, false
+}
+
+// IsQuotaExceeded returns true if the workspace is currently blocked by a ResourceQuota
+func IsQuotaExceeded(workspace *workspacev1alpha1.Workspace) bool {
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeQuotaExceeded)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// quotaHeadroomIncreased returns true if any resource of the quota has more room than before
+func quotaHeadroomIncreased(oldQuota, newQuota *corev1.ResourceQuota) bool {
+	for name, hard := range newQuota.Status.Hard {
+		newFree := hard.DeepCopy()
+		newFree.Sub(newQuota.Status.Used[name])
+
+		oldHard, tracked := oldQuota.Status.Hard[name]
+		if !tracked {
+			continue
+		}
+		oldFree := oldHard.DeepCopy()
+		oldFree.Sub(oldQuota.Status.Used[name])
+
+		if newFree.Cmp(oldFree) > 0 {
+			return true
+		}
+	}
+	// A resource that stopped being limited also frees room
+	for name := range oldQuota.Status.Hard {
+		if _, stillLimited := newQuota.Status.Hard[name]; !stillLimited {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceQuotaHeadroomPredicate lets through ResourceQuota events that may unblock workspaces:
+// an update that frees room under the quota, or the deletion of the quota
+func resourceQuotaHeadroomPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldQuota, oldOk := e.ObjectOld.(*corev1.ResourceQuota)
+			newQuota, newOk := e.ObjectNew.(*corev1.ResourceQuota)
+			return oldOk && newOk && quotaHeadroomIncreased(oldQuota, newQuota)
+		},
+	}
+}
+
+// resourceQuotaEventHandler maps ResourceQuota events to the workspaces of the namespace blocked by a quota
+func (r *WorkspaceReconciler) resourceQuotaEventHandler(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := logf.FromContext(ctx)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := r.List(ctx, workspaces, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Error(err, "Failed to list workspaces for ResourceQuota event", "namespace", obj.GetNamespace())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, workspace := range workspaces.Items {
+		if IsQuotaExceeded(&workspace) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: workspace.Name, Namespace: workspace.Namespace},
+			})
+		}
+	}
+	if len(requests) > 0 {
+		logger.Info("ResourceQuota headroom appeared, retrying blocked workspaces",
+			"resourceQuota", obj.GetName(), "namespace", obj.GetNamespace(), "workspaceCount", len(requests))
+	}
+	return requests
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const podQuotaMessage = `pods "workspace-ws-5d8f7-abcde" is forbidden: exceeded quota: compute-quota, ` +
+	`requested: requests.cpu=4,requests.memory=8Gi, used: requests.cpu=8,requests.memory=8Gi, ` +
+	`limited: requests.cpu=10,requests.memory=12Gi`
+
+func TestParseQuotaExceeded(t *testing.T) {
+	quota, ok := ParseQuotaExceeded(podQuotaMessage)
+	require.True(t, ok)
+	assert.Equal(t, "compute-quota", quota.QuotaName)
+	require.Len(t, quota.Resources, 2)
+
+	cpu := quota.Resources[0]
+	assert.Equal(t, "requests.cpu", cpu.Resource)
+	assert.Equal(t, "4", cpu.Requested.String())
+	free := cpu.Free()
+	assert.Equal(t, "2", free.String())
+
+	assert.Equal(t,
+		"namespace team-a has 2 CPUs free, this workspace requests 4; "+
+			"namespace team-a has 4Gi of memory free, this workspace requests 8Gi (ResourceQuota compute-quota)",
+		quota.Message("team-a"))
+}
+
+func TestParseQuotaExceededIgnoresOtherMessages(t *testing.T) {
+	_, ok := ParseQuotaExceeded(`pods "x" is forbidden: failed quota: compute-quota: must specify limits.cpu`)
+	assert.False(t, ok)
+}
+
+func TestQuotaResourceShortfallFreeIsNeverNegative(t *testing.T) {
+	shortfall := QuotaResourceShortfall{
+		Resource: "requests.storage",
+		Used:     resource.MustParse("120Gi"),
+		Limited:  resource.MustParse("100Gi"),
+	}
+	free := shortfall.Free()
+	assert.Equal(t, "0", free.String())
+}
+
+func TestQuotaExceededFromError(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, "workspace-ws-pvc",
+		assert.AnError)
+	_, ok := QuotaExceededFromError(forbidden)
+	assert.False(t, ok, "forbidden errors without quota details are not quota denials")
+
+	quotaErr := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    403,
+		Reason:  metav1.StatusReasonForbidden,
+		Message: `persistentvolumeclaims "workspace-ws-pvc" is forbidden: exceeded quota: storage, requested: requests.storage=10Gi, used: requests.storage=95Gi, limited: requests.storage=100Gi`,
+	}}
+	quota, ok := QuotaExceededFromError(quotaErr)
+	require.True(t, ok)
+	assert.Equal(t, "namespace team-a has 5Gi of storage free, this workspace requests 10Gi (ResourceQuota storage)",
+		quota.Message("team-a"))
+
+	_, ok = QuotaExceededFromError(nil)
+	assert.False(t, ok)
+}
+
+func TestQuotaExceededFromDeployment(t *testing.T) {
+	deployment := &appsv1.Deployment{}
+	_, ok := QuotaExceededFromDeployment(deployment)
+	assert.False(t, ok)
+
+	deployment.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type:    appsv1.DeploymentReplicaFailure,
+		Status:  corev1.ConditionTrue,
+		Reason:  "FailedCreate",
+		Message: podQuotaMessage,
+	}}
+	quota, ok := QuotaExceededFromDeployment(deployment)
+	require.True(t, ok)
+	assert.Equal(t, "compute-quota", quota.QuotaName)
+}
+
+func TestResourceQuotaHeadroomPredicate(t *testing.T) {
+	quotaWith := func(hard, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(hard)},
+			Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(used)},
+		}}
+	}
+	pred := resourceQuotaHeadroomPredicate()
+
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: quotaWith("10", "8"), ObjectNew: quotaWith("10", "4")}),
+		"usage dropping frees room")
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: quotaWith("10", "8"), ObjectNew: quotaWith("20", "8")}),
+		"a higher limit frees room")
+	assert.False(t, pred.Update(event.UpdateEvent{ObjectOld: quotaWith("10", "4"), ObjectNew: quotaWith("10", "8")}),
+		"usage growing does not")
+	assert.True(t, pred.Update(event.UpdateEvent{ObjectOld: quotaWith("10", "8"), ObjectNew: &corev1.ResourceQuota{}}),
+		"a resource no longer limited frees room")
+	assert.True(t, pred.Delete(event.DeleteEvent{Object: quotaWith("10", "8")}))
+	assert.False(t, pred.Create(event.CreateEvent{Object: quotaWith("10", "8")}))
+}
+
+func TestQuotaExceededStatusIsSetAndCleared(t *testing.T) {
+	ctx := context.Background()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+	}
+	reconciler := newPauseTestReconciler(t, workspace)
+	statusManager := reconciler.statusManager
+
+	snapshot := workspace.Status.DeepCopy()
+	require.NoError(t, statusManager.UpdateQuotaExceededStatus(ctx, workspace, "no room", snapshot))
+	assert.True(t, IsQuotaExceeded(workspace))
+	available := FindCondition(&workspace.Status.Conditions, ConditionTypeAvailable)
+	require.NotNil(t, available)
+	assert.Equal(t, ReasonQuotaRecheckPending, available.Reason)
+
+	// The quota watch picks up workspaces blocked in the namespace only
+	requests := reconciler.resourceQuotaEventHandler(ctx, &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute-quota", Namespace: "team-a"},
+	})
+	assert.Equal(t, types.NamespacedName{Name: "ws", Namespace: "team-a"}, requests[0].NamespacedName)
+	assert.Empty(t, reconciler.resourceQuotaEventHandler(ctx, &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute-quota", Namespace: "team-b"},
+	}))
+
+	snapshot = workspace.Status.DeepCopy()
+	readiness := WorkspaceRunningReadiness{serviceReady: true, accessResourcesReady: true}
+	require.NoError(t, statusManager.UpdateStartingStatus(ctx, workspace, readiness, snapshot))
+	assert.False(t, IsQuotaExceeded(workspace))
+	quotaCondition := FindCondition(&workspace.Status.Conditions, ConditionTypeQuotaExceeded)
+	require.NotNil(t, quotaCondition)
+	assert.Equal(t, ReasonWithinQuota, quotaCondition.Reason)
+}
+
+func TestQuotaConditionNotAddedToUnaffectedWorkspaces(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{}
+	conditions := appendQuotaClearedCondition(workspace, nil)
+	assert.Empty(t, conditions)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	// HistoryActionResetToDefaults records the reset of a workspace to the defaults of its template
+	HistoryActionResetToDefaults = "ResetToDefaults"
+
+	// ReasonResetToDefaults is the event reason of a workspace reset to the defaults of its template
+	ReasonResetToDefaults = "ResetToDefaults"
+	// ReasonResetToDefaultsRejected is the event reason of a reset to the template defaults that could not be applied
+	ReasonResetToDefaultsRejected = "ResetToDefaultsRejected"
+)
+
+// templateManagedField is a workspace spec field the defaulting webhook fills from the template
+type templateManagedField struct {
+	// name is the JSON name of the field
+	name string
+	// field returns a pointer to the field of the spec
+	field func(spec *workspacev1alpha1.WorkspaceSpec) any
+}
+
+// resetToDefaultsFields are the fields a reset to the template defaults clears, for the defaulting webhook
+// to fill again from the template: those shaping the workspace container and its scheduling. The identity,
+// ownership, sharing, storage and lifecycle policy fields of the workspace are kept.
+var resetToDefaultsFields = []templateManagedField{
+	{"image", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Image }},
+	{"imagePullPolicy", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.ImagePullPolicy }},
+	{"containerConfig", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.ContainerConfig }},
+	{"resources", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Resources }},
+	{"env", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Env }},
+	{"envFrom", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.EnvFrom }},
+	{"nodeSelector", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.NodeSelector }},
+	{"affinity", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Affinity }},
+	{"tolerations", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Tolerations }},
+	{"priorityClassName", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.PriorityClassName }},
+	{"lifecycle", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.Lifecycle }},
+	{"podSecurityContext", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.PodSecurityContext }},
+	{"containerSecurityContext", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.ContainerSecurityContext }},
+	{"serverAdapter", func(spec *workspacev1alpha1.WorkspaceSpec) any { return &spec.ServerAdapter }},
+}
+
+// clearTemplateManagedFields clears the resetToDefaultsFields of the spec
+func clearTemplateManagedFields(spec *workspacev1alpha1.WorkspaceSpec) {
+	for _, f := range resetToDefaultsFields {
+		reflect.ValueOf(f.field(spec)).Elem().SetZero()
+	}
+}
+
+// changedTemplateManagedFields returns the names of the resetToDefaultsFields that differ between the specs
+func changedTemplateManagedFields(before, after *workspacev1alpha1.WorkspaceSpec) []string {
+	var changed []string
+	for _, f := range resetToDefaultsFields {
+		if !reflect.DeepEqual(reflect.ValueOf(f.field(before)).Elem().Interface(),
+			reflect.ValueOf(f.field(after)).Elem().Interface()) {
+			changed = append(changed, f.name)
+		}
+	}
+	return changed
+}
+
+// resetToDefaults resets the workspace to the defaults of its current template on request of the owner, with
+// the AnnotationResetToDefaults annotation set to "true". The resetToDefaultsFields are cleared and the
+// annotation removed in one patch, which the defaulting webhook fills again from the template; the deployment
+// then rolls out the workspace pod. The reset is recorded in the workspace history, for the status update of
+// the reconciliation to persist. It returns true when the request was rejected and the annotation removed,
+// for the workspace to be reconciled again.
+func (sm *StateMachine) resetToDefaults(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if workspace.Annotations[AnnotationResetToDefaults] != "true" {
+		return false, nil
+	}
+	logger := logf.FromContext(ctx)
+
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonResetToDefaultsRejected,
+			"Workspace was not reset to its template defaults: it does not use a template")
+		return true, sm.removeResetToDefaultsAnnotation(ctx, workspace)
+	}
+
+	updated := workspace.DeepCopy()
+	delete(updated.Annotations, AnnotationResetToDefaults)
+	clearTemplateManagedFields(&updated.Spec)
+	err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, updated, workspace)
+	if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+		// The template defaults are rejected, e.g. by a quota; keep the workspace and drop the request
+		logger.Info("Reset to template defaults rejected", "error", err.Error())
+		sm.recorder.Eventf(workspace, corev1.EventTypeWarning, ReasonResetToDefaultsRejected,
+			"Workspace was not reset to its template defaults: %v", err)
+		return true, sm.removeResetToDefaultsAnnotation(ctx, workspace)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reset the workspace to its template defaults: %w", err)
+	}
+
+	message := fmt.Sprintf("Workspace already matched the defaults of template %s", workspace.Spec.TemplateRef.Name)
+	if changed := changedTemplateManagedFields(&workspace.Spec, &updated.Spec); len(changed) > 0 {
+		message = fmt.Sprintf("Reset %s to the defaults of template %s",
+			strings.Join(changed, ", "), workspace.Spec.TemplateRef.Name)
+	}
+	logger.Info(message)
+	sm.recorder.Event(updated, corev1.EventTypeNormal, ReasonResetToDefaults, message)
+	// Keep the status updated in memory by this reconcile
+	updated.ObjectMeta.DeepCopyInto(&workspace.ObjectMeta)
+	updated.Spec.DeepCopyInto(&workspace.Spec)
+	appendHistory(workspace, HistoryActionResetToDefaults, message, time.Now())
+	return false, nil
+}
+
+// removeResetToDefaultsAnnotation removes the AnnotationResetToDefaults annotation
+func (sm *StateMachine) removeResetToDefaultsAnnotation(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	patch := client.MergeFrom(workspace.DeepCopy())
+	delete(workspace.Annotations, AnnotationResetToDefaults)
+	if err := sm.resourceManager.client.Patch(ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", AnnotationResetToDefaults, err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newResetTestWorkspace returns a workspace of template base whose template-managed fields were edited by hand
+func newResetTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationCreatedBy: "alice", AnnotationResetToDefaults: "true"}
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "base"}
+	workspace.Spec.Image = "jupyter/broken:dev"
+	workspace.Spec.Env = []corev1.EnvVar{{Name: "PYTHONPATH", Value: "/nowhere"}}
+	workspace.Spec.DisplayName = "Alice"
+	workspace.Spec.OwnershipType = "OwnerOnly"
+	return workspace
+}
+
+func TestResetToDefaultsClearsTheTemplateManagedFieldsOnly(t *testing.T) {
+	kept := workspacev1alpha1.WorkspaceSpec{
+		DisplayName:        "Alice",
+		DesiredStatus:      DesiredStateRunning,
+		DeletionProtection: true,
+		OwnershipType:      "OwnerOnly",
+		AccessType:         "Public",
+		GPUCount:           ptr.To(int32(1)),
+		Storage:            &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+		Volumes:            []workspacev1alpha1.VolumeSpec{{Name: "data"}},
+		TmpVolume:          &workspacev1alpha1.TmpVolumeSpec{},
+		AccessStrategy:     &workspacev1alpha1.AccessStrategyRef{Name: "oauth"},
+		TemplateRef:        &workspacev1alpha1.TemplateRef{Name: "base"},
+		IdleShutdown:       &workspacev1alpha1.IdleShutdownSpec{Enabled: true},
+		Retention:          &workspacev1alpha1.RetentionSpec{},
+		AppType:            "jupyterlab",
+		ServiceAccountName: "alice",
+		ServiceMesh:        &workspacev1alpha1.ServiceMeshSpec{},
+	}
+	spec := kept.DeepCopy()
+	spec.Image = "jupyter/broken:dev"
+	spec.ImagePullPolicy = corev1.PullAlways
+	spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"sleep"}}
+	spec.Resources = &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Mi")}}
+	spec.Env = []corev1.EnvVar{{Name: "PYTHONPATH", Value: "/nowhere"}}
+	spec.EnvFrom = []workspacev1alpha1.EnvFromSource{{EnvFromSource: corev1.EnvFromSource{Prefix: "DB_"}}}
+	spec.NodeSelector = map[string]string{"pool": "gone"}
+	spec.Affinity = &corev1.Affinity{}
+	spec.Tolerations = []corev1.Toleration{{Key: "gpu"}}
+	spec.PriorityClassName = "batch-high"
+	spec.Lifecycle = &corev1.Lifecycle{}
+	spec.PodSecurityContext = &corev1.PodSecurityContext{RunAsUser: ptr.To(int64(0))}
+	spec.ContainerSecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true)}
+	spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{}
+
+	assert.Equal(t, []string{
+		"image", "imagePullPolicy", "containerConfig", "resources", "env", "envFrom", "nodeSelector", "affinity",
+		"tolerations", "priorityClassName", "lifecycle", "podSecurityContext", "containerSecurityContext",
+		"serverAdapter",
+	}, changedTemplateManagedFields(&kept, spec))
+	clearTemplateManagedFields(spec)
+	assert.Equal(t, kept, *spec, "the identity, ownership, storage and policy fields are kept")
+}
+
+func TestResetToDefaultsRecordsTheReset(t *testing.T) {
+	ctx := context.Background()
+	workspace := newResetTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if err := c.Patch(ctx, obj, patch, opts...); err != nil {
+				return err
+			}
+			// The defaulting webhook fills the cleared image from the template
+			if ws := obj.(*workspacev1alpha1.Workspace); ws.Spec.Image == "" {
+				ws.Spec.Image = "jupyter/base-notebook:latest"
+				return c.Update(ctx, ws)
+			}
+			return nil
+		},
+	}, workspace)
+
+	rejected, err := sm.resetToDefaults(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, rejected)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationResetToDefaults)
+	assert.Equal(t, "alice", stored.Annotations[AnnotationCreatedBy])
+	assert.Equal(t, "jupyter/base-notebook:latest", stored.Spec.Image)
+	assert.Empty(t, stored.Spec.Env)
+	assert.Equal(t, "Alice", stored.Spec.DisplayName)
+	assert.Equal(t, "OwnerOnly", stored.Spec.OwnershipType)
+	assert.Equal(t, "1Gi", stored.Spec.Storage.Size.String())
+
+	// The reset is left in memory for the status update of the reconciliation
+	assert.Equal(t, stored.Spec, workspace.Spec)
+	require.Len(t, workspace.Status.History, 1)
+	assert.Equal(t, HistoryActionResetToDefaults, workspace.Status.History[0].Action)
+	assert.Equal(t, "Reset image, env to the defaults of template base", workspace.Status.History[0].Message)
+	event := <-sm.recorder.(*record.FakeRecorder).Events
+	assert.Contains(t, event, ReasonResetToDefaults)
+
+	// Without the annotation there is nothing to do
+	rejected, err = sm.resetToDefaults(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, rejected)
+	assert.Len(t, workspace.Status.History, 1)
+}
+
+func TestResetToDefaultsWithoutTemplateIsRejected(t *testing.T) {
+	ctx := context.Background()
+	workspace := newResetTestWorkspace()
+	workspace.Spec.TemplateRef = nil
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	rejected, err := sm.resetToDefaults(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, rejected)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationResetToDefaults, "the request is dropped")
+	assert.Equal(t, "jupyter/broken:dev", stored.Spec.Image)
+	assert.Empty(t, stored.Status.History)
+	event := <-sm.recorder.(*record.FakeRecorder).Events
+	assert.Contains(t, event, ReasonResetToDefaultsRejected)
+}
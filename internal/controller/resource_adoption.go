@@ -0,0 +1,119 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ChildResourceConflictError reports that the name of a workspace child resource is taken
+// by an object that belongs to something else
+type ChildResourceConflictError struct {
+	Kind      string
+	Name      string
+	Namespace string
+	// Owner describes the controller of the conflicting object, empty when it has none
+	Owner string
+}
+
+func (e *ChildResourceConflictError) Error() string {
+	owner := "no controller"
+	if e.Owner != "" {
+		owner = "controller " + e.Owner
+	}
+	return fmt.Sprintf("%s %s/%s already exists and is not owned by this workspace (%s)", e.Kind, e.Namespace, e.Name, owner)
+}
+
+// asChildResourceConflict returns the conflict wrapped in err, if any
+func asChildResourceConflict(err error) (*ChildResourceConflictError, bool) {
+	var conflict *ChildResourceConflictError
+	if errors.As(err, &conflict) {
+		return conflict, true
+	}
+	return nil, false
+}
+
+// newChildResourceConflict builds the conflict error for an object controlled by controllerRef, if any
+func newChildResourceConflict(kind string, existing client.Object, controllerRef *metav1.OwnerReference) *ChildResourceConflictError {
+	conflict := &ChildResourceConflictError{Kind: kind, Name: existing.GetName(), Namespace: existing.GetNamespace()}
+	if controllerRef != nil {
+		conflict.Owner = fmt.Sprintf("%s/%s", controllerRef.Kind, controllerRef.Name)
+	}
+	return conflict
+}
+
+// childOwnership classifies an existing object that has the name of a workspace child resource
+type childOwnership int
+
+const (
+	// childOwned objects are controlled by the workspace
+	childOwned childOwnership = iota
+	// childAdoptable objects carry the workspace labels but have no controller,
+	// e.g. when the owner reference was stripped by a backup restore
+	childAdoptable
+	// childForeign objects belong to something else
+	childForeign
+)
+
+// classifyChild decides whether the existing object belongs to the workspace
+func classifyChild(workspace *workspacev1alpha1.Workspace, existing client.Object) (childOwnership, *metav1.OwnerReference) {
+	if controllerRef := metav1.GetControllerOf(existing); controllerRef != nil {
+		if controllerRef.UID == workspace.UID {
+			return childOwned, controllerRef
+		}
+		return childForeign, controllerRef
+	}
+	if existing.GetLabels()[LabelWorkspaceName] == workspace.Name {
+		return childAdoptable, nil
+	}
+	return childForeign, nil
+}
+
+// adoptExistingChild is called when creating a child resource fails with AlreadyExists, typically
+// because two reconciles of the same workspace raced. It fetches the object named like desired into
+// existing and succeeds when it belongs to the workspace, adopting it first if it has no controller.
+// It returns a ChildResourceConflictError when the object belongs to something else.
+func (rm *ResourceManager) adoptExistingChild(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	kind string,
+	desired client.Object,
+	existing client.Object) error {
+	logger := logf.FromContext(ctx)
+
+	if err := rm.client.Get(ctx, client.ObjectKeyFromObject(desired), existing); err != nil {
+		return fmt.Errorf("failed to get existing %s %s: %w", kind, desired.GetName(), err)
+	}
+
+	ownership, controllerRef := classifyChild(workspace, existing)
+	switch ownership {
+	case childOwned:
+		logger.Info("Child resource already exists and is owned by the workspace",
+			"kind", kind, "name", existing.GetName(), "namespace", existing.GetNamespace())
+		return nil
+	case childAdoptable:
+		logger.Info("Adopting child resource",
+			"kind", kind, "name", existing.GetName(), "namespace", existing.GetNamespace())
+		if err := controllerutil.SetControllerReference(workspace, existing, rm.scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference on %s %s: %w", kind, existing.GetName(), err)
+		}
+		if err := rm.client.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to adopt %s %s: %w", kind, existing.GetName(), err)
+		}
+		return nil
+	default:
+		return newChildResourceConflict(kind, existing, controllerRef)
+	}
+}
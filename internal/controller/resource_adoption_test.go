@@ -0,0 +1,283 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newAdoptionTestStateMachine(t *testing.T, funcs interceptor.Funcs, objects ...client.Object) (*StateMachine, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, storagev1.AddToScheme(scheme))
+	require.NoError(t, rbacv1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+
+	statusManager := NewStatusManager(k8sClient)
+	resourceManager := NewResourceManager(
+		k8sClient,
+		scheme,
+		NewDeploymentBuilder(scheme, WorkspaceControllerOptions{}),
+		NewServiceBuilder(scheme),
+		NewPVCBuilder(scheme),
+		NewAccessResourcesBuilder(),
+		statusManager,
+	)
+	return NewStateMachine(resourceManager, statusManager, record.NewFakeRecorder(100), nil, nil), k8sClient
+}
+
+func newAdoptionTestWorkspace(name string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a", UID: types.UID(name + "-uid")},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image:         "jupyter/base-notebook:latest",
+			DesiredStatus: DesiredStateRunning,
+			Storage:       &workspacev1alpha1.StorageSpec{Size: resource.MustParse("1Gi")},
+		},
+	}
+}
+
+func TestCreateDeploymentAlreadyExistsOwnedIsSuccess(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	// The first reconcile created the deployment; a second one racing it lost the Get
+	first, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+	require.NoError(t, err)
+	second, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.Name, second.Name)
+	assert.NotEmpty(t, second.ResourceVersion, "the stored object is returned")
+
+	deployments := &appsv1.DeploymentList{}
+	require.NoError(t, k8sClient.List(ctx, deployments))
+	assert.Len(t, deployments.Items, 1)
+}
+
+func TestConcurrentEnsureChildrenOfSameWorkspace(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sm.resourceManager.EnsurePVCExists(ctx, workspace.DeepCopy()); err != nil {
+				errs[i] = err
+				return
+			}
+			if _, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace.DeepCopy(), nil); err != nil {
+				errs[i] = err
+				return
+			}
+			_, errs[i] = sm.resourceManager.EnsureServiceExists(ctx, workspace.DeepCopy())
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+func TestCreateChildAdoptsLabeledOrphan(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	orphan := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateServiceName(workspace.Name),
+			Namespace: workspace.Namespace,
+			Labels:    map[string]string{LabelWorkspaceName: workspace.Name},
+		},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, orphan)
+
+	_, err := sm.resourceManager.createService(ctx, workspace)
+	require.NoError(t, err)
+
+	adopted := &corev1.Service{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(orphan), adopted))
+	controllerRef := metav1.GetControllerOf(adopted)
+	require.NotNil(t, controllerRef)
+	assert.Equal(t, workspace.UID, controllerRef.UID)
+}
+
+func TestCreateChildOwnedByAnotherObjectIsConflict(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	isController := true
+	foreign := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateDeploymentName(workspace.Name),
+			Namespace: workspace.Namespace,
+			Labels:    map[string]string{LabelWorkspaceName: workspace.Name},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "workspace.jupyter.org/v1alpha1",
+				Kind:       "Workspace",
+				Name:       workspace.Name,
+				UID:        "previous-incarnation-uid",
+				Controller: &isController,
+			}},
+		},
+	}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, foreign)
+
+	_, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+	conflict, ok := asChildResourceConflict(err)
+	require.True(t, ok, "expected a conflict, got %v", err)
+	assert.Equal(t, KindDeployment, conflict.Kind)
+	assert.Equal(t, "Workspace/ws", conflict.Owner)
+	assert.Contains(t, conflict.Error(), "team-a/workspace-ws")
+}
+
+func TestReconcileReportsChildResourceConflict(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Storage = nil
+	foreign := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateDeploymentName(workspace.Name), Namespace: workspace.Namespace},
+	}
+	// The Get misses the foreign deployment, as it would when another client creates it concurrently
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*appsv1.Deployment); ok {
+				if err := c.Create(ctx, foreign.DeepCopy()); err != nil {
+					return err
+				}
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}, workspace)
+
+	snapshot := workspace.Status.DeepCopy()
+	result, err := sm.reconcileDesiredRunningStatus(ctx, workspace, snapshot, nil)
+	require.NoError(t, err)
+	assert.Equal(t, LongRequeueDelay, result.RequeueAfter)
+
+	degraded := FindCondition(&workspace.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, ReasonResourceConflict, degraded.Reason)
+	assert.Contains(t, degraded.Message, GenerateDeploymentName(workspace.Name))
+}
+
+// TestConcurrentCreatesUnderQuotaConverge creates 10 workspaces at once in a namespace whose
+// ResourceQuota admits 5 PVCs: exactly 5 must progress and the other 5 must be parked for a recheck
+func TestConcurrentCreatesUnderQuotaConverge(t *testing.T) {
+	const workspaceCount, pvcLimit = 10, 5
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	admitted := 0
+	quotaAdmission := interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*corev1.PersistentVolumeClaim); !ok {
+				return c.Create(ctx, obj, opts...)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if admitted >= pvcLimit {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "persistentvolumeclaims"}, obj.GetName(),
+					fmt.Errorf("exceeded quota: pvc-count, requested: persistentvolumeclaims=1, used: persistentvolumeclaims=%d, limited: persistentvolumeclaims=%d", admitted, pvcLimit))
+			}
+			if err := c.Create(ctx, obj, opts...); err != nil {
+				return err
+			}
+			admitted++
+			return nil
+		},
+	}
+
+	var objects []client.Object
+	for i := range workspaceCount {
+		objects = append(objects, newAdoptionTestWorkspace(fmt.Sprintf("ws-%d", i)))
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, quotaAdmission, objects...)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workspaceCount)
+	for i := range workspaceCount {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workspace := &workspacev1alpha1.Workspace{}
+			key := types.NamespacedName{Name: fmt.Sprintf("ws-%d", i), Namespace: "team-a"}
+			if err := k8sClient.Get(ctx, key, workspace); err != nil {
+				errs <- err
+				return
+			}
+			snapshot := workspace.Status.DeepCopy()
+			if _, err := sm.reconcileDesiredRunningStatus(ctx, workspace, snapshot, nil); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	require.NoError(t, k8sClient.List(ctx, deployments))
+	assert.Len(t, deployments.Items, pvcLimit)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	require.NoError(t, k8sClient.List(ctx, workspaces))
+	parked := 0
+	for _, workspace := range workspaces.Items {
+		if IsQuotaExceeded(&workspace) {
+			parked++
+			progressing := FindCondition(&workspace.Status.Conditions, ConditionTypeProgressing)
+			require.NotNil(t, progressing)
+			assert.Equal(t, ReasonQuotaRecheckPending, progressing.Reason)
+		}
+	}
+	assert.Equal(t, workspaceCount-pvcLimit, parked)
+}
+
+func TestChildResourceConflictErrorUnwrapsThroughWrapping(t *testing.T) {
+	conflict := &ChildResourceConflictError{Kind: KindService, Name: "svc", Namespace: "ns"}
+	found, ok := asChildResourceConflict(fmt.Errorf("failed to ensure service exists: %w", conflict))
+	require.True(t, ok)
+	assert.Same(t, conflict, found)
+
+	_, ok = asChildResourceConflict(errors.New("other"))
+	assert.False(t, ok)
+}
@@ -8,12 +8,17 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,6 +34,17 @@ type ResourceManager struct {
 	pvcBuilder             *PVCBuilder
 	accessResourcesBuilder *AccessResourcesBuilder
 	statusManager          *StatusManager
+	// apiReader reads objects the cache does not hold, e.g. Secrets outside the controller namespace
+	apiReader client.Reader
+	// templateResolver resolves the template whose child metadata applies to generated resources
+	templateResolver *workspaceutil.TemplateResolver
+	// digestResolver resolves the image tags of workspaces whose template pins images by digest
+	digestResolver DigestResolver
+	// childNamePrefix is the operator prefix of the names of generated resources; empty uses ResourcePrefix
+	childNamePrefix string
+	// userNamespacesSupported is true when the cluster runs pods in user namespaces; templates with the
+	// UserNamespace isolation level run Rootless otherwise
+	userNamespacesSupported bool
 }
 
 // NewResourceManager creates a new ResourceManager
@@ -52,10 +68,18 @@ func NewResourceManager(
 	}
 }
 
+// reader returns the uncached API reader when set, or the client otherwise
+func (rm *ResourceManager) reader() client.Reader {
+	if rm.apiReader != nil {
+		return rm.apiReader
+	}
+	return rm.client
+}
+
 // GetDeployment retrieves the deployment for a Workspace
 func (rm *ResourceManager) getDeployment(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*appsv1.Deployment, error) {
 	deployment := &appsv1.Deployment{}
-	deploymentName := GenerateDeploymentName(workspace.Name)
+	deploymentName := deploymentNameFor(workspace)
 
 	err := rm.client.Get(ctx, types.NamespacedName{
 		Name:      deploymentName,
@@ -68,7 +92,7 @@ func (rm *ResourceManager) getDeployment(ctx context.Context, workspace *workspa
 // GetService retrieves the service for a Workspace
 func (rm *ResourceManager) getService(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.Service, error) {
 	service := &corev1.Service{}
-	serviceName := GenerateServiceName(workspace.Name)
+	serviceName := serviceNameFor(workspace)
 
 	err := rm.client.Get(ctx, types.NamespacedName{
 		Name:      serviceName,
@@ -81,7 +105,7 @@ func (rm *ResourceManager) getService(ctx context.Context, workspace *workspacev
 // getPVC retrieves the PVC for a Workspace
 func (rm *ResourceManager) getPVC(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
 	pvc := &corev1.PersistentVolumeClaim{}
-	pvcName := GeneratePVCName(workspace.Name)
+	pvcName := pvcNameFor(workspace)
 
 	err := rm.client.Get(ctx, types.NamespacedName{
 		Name:      pvcName,
@@ -104,7 +128,14 @@ func (rm *ResourceManager) createDeployment(ctx context.Context, workspace *work
 		"deployment", deployment.Name,
 		"namespace", deployment.Namespace)
 	if err := rm.client.Create(ctx, deployment); err != nil {
-		return nil, fmt.Errorf("failed to create deployment: %w", err)
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create deployment: %w", err)
+		}
+		existing := &appsv1.Deployment{}
+		if err := rm.adoptExistingChild(ctx, workspace, KindDeployment, deployment, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
 	}
 
 	return deployment, nil
@@ -124,7 +155,14 @@ func (rm *ResourceManager) createService(ctx context.Context, workspace *workspa
 		"namespace", service.Namespace)
 
 	if err := rm.client.Create(ctx, service); err != nil {
-		return nil, fmt.Errorf("failed to create service: %w", err)
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create service: %w", err)
+		}
+		existing := &corev1.Service{}
+		if err := rm.adoptExistingChild(ctx, workspace, KindService, service, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
 	}
 
 	return service, nil
@@ -132,6 +170,14 @@ func (rm *ResourceManager) createService(ctx context.Context, workspace *workspa
 
 // createPVC creates a new PVC for the Workspace
 func (rm *ResourceManager) createPVC(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
+	return rm.createPVCFrom(ctx, workspace, nil)
+}
+
+// createPVCFrom creates the PVC of the workspace, populated from the data source when not nil
+func (rm *ResourceManager) createPVCFrom(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	dataSource *pvcDataSource) (*corev1.PersistentVolumeClaim, error) {
 	logger := logf.FromContext(ctx)
 
 	pvc, err := rm.pvcBuilder.BuildPVC(workspace)
@@ -142,13 +188,26 @@ func (rm *ResourceManager) createPVC(ctx context.Context, workspace *workspacev1
 	if pvc == nil {
 		return nil, nil // No storage requested
 	}
+	if dataSource != nil {
+		pvc.Spec.DataSource = &dataSource.ref
+		if pvc.Spec.StorageClassName == nil {
+			pvc.Spec.StorageClassName = dataSource.storageClassName
+		}
+	}
 
 	logger.Info("Creating PVC",
 		"pvc", pvc.Name,
 		"namespace", pvc.Namespace)
 
 	if err := rm.client.Create(ctx, pvc); err != nil {
-		return nil, fmt.Errorf("failed to create PVC: %w", err)
+		if !errors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("failed to create PVC: %w", err)
+		}
+		existing := &corev1.PersistentVolumeClaim{}
+		if err := rm.adoptExistingChild(ctx, workspace, KindPersistentVolumeClaim, pvc, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
 	}
 
 	return pvc, nil
@@ -192,7 +251,12 @@ func (rm *ResourceManager) EnsureDeploymentExists(
 	deployment, err := rm.getDeployment(ctx, workspace)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return rm.createDeployment(ctx, workspace, accessStrategy)
+			deployment, err = rm.createDeployment(ctx, workspace, accessStrategy)
+			if err == nil {
+				// The new pod satisfies any pending restart request
+				markRestartHandled(workspace)
+			}
+			return deployment, err
 		}
 		return nil, fmt.Errorf("failed to get deployment: %w", err)
 	}
@@ -202,6 +266,11 @@ func (rm *ResourceManager) EnsureDeploymentExists(
 
 // ensureDeploymentUpToDate checks if deployment needs update and updates it if necessary
 func (rm *ResourceManager) ensureDeploymentUpToDate(ctx context.Context, deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (*appsv1.Deployment, error) {
+	// Restart the pod on request of the owner; the restart rolls out any other change as well
+	if restarted, ok, err := rm.ensureRestarted(ctx, deployment, workspace, accessStrategy); err != nil || ok {
+		return restarted, err
+	}
+
 	// Only perform updates when workspace is available to avoid interfering with creation
 	if !rm.statusManager.IsWorkspaceAvailable(workspace) {
 		return deployment, nil
@@ -222,10 +291,51 @@ func (rm *ResourceManager) ensureDeploymentUpToDate(ctx context.Context, deploym
 		return nil, fmt.Errorf("failed to check if deployment needs update: %w", err)
 	}
 
-	if needsUpdate {
-		return rm.updateDeployment(ctx, deployment, workspace, accessStrategy)
+	if !needsUpdate {
+		return rm.ensureEnvFromSecretsRotated(ctx, deployment, workspace, accessStrategy)
+	}
+	if podTemplateRolloutDeferred(deployment, workspace, time.Now()) {
+		return rm.deferDeploymentRollout(ctx, deployment, workspace, accessStrategy)
+	}
+	// The new pod starts with the current Secret values
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending)
+	return rm.updateDeployment(ctx, deployment, workspace, accessStrategy)
+}
+
+// deferDeploymentRollout records a pod template change rendered by another operator version on the
+// deployment, leaving the pod template, and so the running pod, unchanged
+func (rm *ResourceManager) deferDeploymentRollout(ctx context.Context, deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (*appsv1.Deployment, error) {
+	desiredDeployment, err := rm.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, accessStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build desired deployment: %w", err)
+	}
+	pendingHash := desiredDeployment.Annotations[AnnotationPodTemplateHash]
+	if deployment.Annotations[AnnotationPendingPodTemplateHash] == pendingHash {
+		return deployment, nil
 	}
 
+	existingHash, err := existingPodTemplateHash(deployment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fingerprint existing pod template: %w", err)
+	}
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	// Deployments created before fingerprints were recorded are stamped from their current state,
+	// so that the next change to the workspace rolls out immediately
+	deployment.Annotations[AnnotationPodTemplateHash] = existingHash
+	if _, ok := deployment.Annotations[AnnotationRenderedGeneration]; !ok {
+		deployment.Annotations[AnnotationRenderedGeneration] = strconv.FormatInt(workspace.Generation, 10)
+	}
+	deployment.Annotations[AnnotationPendingPodTemplateHash] = pendingHash
+
+	logf.FromContext(ctx).Info("Deferring pod template rollout rendered by a new operator version until the workspace restarts",
+		"deployment", deployment.Name,
+		"renderedBy", deployment.Spec.Template.Annotations[AnnotationManagedByVersion],
+		"operatorVersion", buildinfo.Version)
+	if err := rm.client.Update(ctx, deployment); err != nil {
+		return nil, fmt.Errorf("failed to record deferred deployment rollout: %w", err)
+	}
 	return deployment, nil
 }
 
@@ -251,6 +361,12 @@ func (rm *ResourceManager) updateDeployment(ctx context.Context, deployment *app
 
 	// Update the existing deployment spec while preserving metadata like resourceVersion
 	deployment.Spec = updatedDeployment.Spec
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[AnnotationPodTemplateHash] = updatedDeployment.Annotations[AnnotationPodTemplateHash]
+	deployment.Annotations[AnnotationRenderedGeneration] = updatedDeployment.Annotations[AnnotationRenderedGeneration]
+	delete(deployment.Annotations, AnnotationPendingPodTemplateHash)
 
 	logger.Info("Updating Deployment",
 		"deployment", deployment.Name,
@@ -278,6 +394,11 @@ func (rm *ResourceManager) EnsureServiceExists(ctx context.Context, workspace *w
 
 // ensureServiceUpToDate checks if service needs update and updates it if necessary
 func (rm *ResourceManager) ensureServiceUpToDate(ctx context.Context, service *corev1.Service, workspace *workspacev1alpha1.Workspace) (*corev1.Service, error) {
+	// A stop interrupted while draining left the service detached from the workspace pods
+	if isServiceDraining(service) {
+		return rm.updateService(ctx, service, workspace)
+	}
+
 	// Only perform updates when workspace is available to avoid interfering with creation
 	if !rm.statusManager.IsWorkspaceAvailable(workspace) {
 		return service, nil
@@ -331,6 +452,26 @@ func (rm *ResourceManager) EnsureDeploymentDeleted(ctx context.Context, workspac
 	return deployment, nil
 }
 
+// CountWorkspacePods returns the number of pods of the workspace that still hold node resources.
+// Pods of a deleted deployment keep their resource requests and node placement until they
+// terminate; pods that succeeded or failed no longer count against the node.
+func (rm *ResourceManager) CountWorkspacePods(ctx context.Context, workspace *workspacev1alpha1.Workspace) (int, error) {
+	podList := &corev1.PodList{}
+	if err := rm.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	count := 0
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // EnsureServiceDeleted initiates deletion, or returns the service if it is already being deleted
 func (rm *ResourceManager) EnsureServiceDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.Service, error) {
 	service, err := rm.getService(ctx, workspace)
@@ -348,6 +489,9 @@ func (rm *ResourceManager) EnsureServiceDeleted(ctx context.Context, workspace *
 
 // EnsurePVCDeleted initiates PVC deletion (used during workspace deletion, not stop)
 func (rm *ResourceManager) EnsurePVCDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
+	if hasEphemeralStorage(workspace) {
+		return nil, nil // Ephemeral storage never has a PVC
+	}
 	pvc, err := rm.getPVC(ctx, workspace)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -368,10 +512,8 @@ func (rm *ResourceManager) EnsurePVCDeleted(ctx context.Context, workspace *work
 // EnsurePVCExists creates a PVC if it doesn't exist, or updates it if the spec differs
 // It uses workspace storage if specified
 func (rm *ResourceManager) EnsurePVCExists(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*corev1.PersistentVolumeClaim, error) {
-	// Check if storage is needed from workspace
-	hasStorage := workspace.Spec.Storage != nil
-
-	if !hasStorage {
+	// Ephemeral storage is an emptyDir volume of the pod
+	if !hasPersistentStorage(workspace) {
 		return nil, nil // No storage requested
 	}
 
@@ -393,6 +535,12 @@ func (rm *ResourceManager) ensurePVCUpToDate(ctx context.Context, pvc *corev1.Pe
 		return pvc, nil
 	}
 
+	// The PVC only grows, when its StorageClass allows volume expansion
+	workspace, err := rm.withResizableStorageSize(ctx, pvc, workspace)
+	if err != nil {
+		return nil, err
+	}
+
 	needsUpdate, err := rm.pvcBuilder.NeedsUpdate(ctx, pvc, workspace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if PVC needs update: %w", err)
@@ -425,45 +573,6 @@ func (rm *ResourceManager) updatePVC(ctx context.Context, pvc *corev1.Persistent
 	return pvc, nil
 }
 
-// CleanupAllResources performs comprehensive cleanup of all workspace resources
-func (rm *ResourceManager) CleanupAllResources(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
-	logger := logf.FromContext(ctx)
-
-	// Delete access strategy resources first
-	accessError := rm.EnsureAccessResourcesDeleted(ctx, workspace)
-	if accessError != nil {
-		logger.Error(accessError, "Failed to delete access strategy resources")
-		// Continue with other deletions, don't block on access strategy
-	}
-
-	// Delete deployment
-	_, err := rm.EnsureDeploymentDeleted(ctx, workspace)
-	if err != nil {
-		return false, err
-	}
-
-	// Delete service
-	_, err = rm.EnsureServiceDeleted(ctx, workspace)
-	if err != nil {
-		return false, err
-	}
-
-	// Delete PVC
-	_, err = rm.EnsurePVCDeleted(ctx, workspace)
-	if err != nil {
-		return false, err
-	}
-
-	// Check if all resources are fully deleted using helper function
-	if rm.AreAllResourcesDeleted(ctx, workspace) {
-		logger.Info("All resources successfully deleted")
-		return true, nil
-	}
-
-	// Resources still being deleted - requeue to check again
-	return false, nil
-}
-
 // AreAllResourcesDeleted checks if all workspace resources are fully removed (not found)
 func (rm *ResourceManager) AreAllResourcesDeleted(ctx context.Context, workspace *workspacev1alpha1.Workspace) bool {
 	// Check deployment - must be NotFound (fully deleted)
@@ -478,10 +587,12 @@ func (rm *ResourceManager) AreAllResourcesDeleted(ctx context.Context, workspace
 		return false // Still exists or other error
 	}
 
-	// Check PVC - must be NotFound (fully deleted)
-	_, err = rm.getPVC(ctx, workspace)
-	if err == nil || !errors.IsNotFound(err) {
-		return false // Still exists or other error
+	// Check PVC - must be NotFound (fully deleted), unless the storage is ephemeral
+	if !hasEphemeralStorage(workspace) {
+		_, err = rm.getPVC(ctx, workspace)
+		if err == nil || !errors.IsNotFound(err) {
+			return false // Still exists or other error
+		}
 	}
 
 	// Check access resources are deleted
@@ -190,12 +190,16 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 			}
 
 			// If specs are different, update the access resource
-			// This captures two different scenarios
+			// This captures three different scenarios
 			// case 1: the access resource was modified by another process (in spite of owner reference)
 			// case 2: the AccessStrategy modified the resource template, so that the actual resource
 			// no longer conforms to the resource defined in AccessStrategy
-			if existingFound && expectedFound && !reflect.DeepEqual(existingSpec, expectedSpec) {
-				logger.Info("AccessResource spec doesn't match template, updating",
+			// case 3: the workspace template changed the labels or annotations of ingress resources
+			specDrifted := existingFound && expectedFound && !reflect.DeepEqual(existingSpec, expectedSpec)
+			metadataDrifted := ingressAccessResourceKinds[resourceTemplate.Kind] &&
+				!childMetadataInSync(existingObj, expectedObj, childMetadataOf(workspace).Ingress)
+			if specDrifted || metadataDrifted {
+				logger.Info("AccessResource doesn't match template, updating",
 					"kind", existingObj.GetKind(),
 					"name", existingObj.GetName(),
 					"namespace", existingObj.GetNamespace())
@@ -260,6 +264,11 @@ func (rm *ResourceManager) ensureAccessResourceExists(
 				return fmt.Errorf("failed to get existing resource: %w", err)
 			}
 
+			// Never overwrite an object that belongs to something else
+			if ownership, controllerRef := classifyChild(workspace, existingObj); ownership == childForeign {
+				return newChildResourceConflict(obj.GetKind(), existingObj, controllerRef)
+			}
+
 			// Copy resource version to ensure proper update
 			obj.SetResourceVersion(existingObj.GetResourceVersion())
 
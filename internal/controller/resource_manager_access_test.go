@@ -990,6 +990,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 				u.SetName(key.Name)
 				u.SetNamespace(key.Namespace)
 				u.SetResourceVersion("1")
+				u.SetLabels(map[string]string{LabelWorkspaceName: workspace.Name})
 				return nil
 			}
 
@@ -1044,6 +1045,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 				u.SetName(key.Name)
 				u.SetNamespace(key.Namespace)
 				u.SetResourceVersion("1")
+				u.SetLabels(map[string]string{LabelWorkspaceName: workspace.Name})
 				return nil
 			}
 
@@ -1076,6 +1078,7 @@ var _ = Describe("ResourceManagerForAccess", func() {
 				u.SetResourceVersion("1")
 				u.SetAPIVersion("traefik.io/v1alpha1")
 				u.SetKind("IngressRoute")
+				u.SetLabels(map[string]string{LabelWorkspaceName: workspace.Name})
 				return nil
 			}
 
@@ -1103,6 +1106,35 @@ var _ = Describe("ResourceManagerForAccess", func() {
 			Expect(workspace.Status.AccessResources[0].Kind).To(Equal("IngressRoute"))
 			Expect(workspace.Status.AccessResources[0].APIVersion).To(Equal("traefik.io/v1alpha1"))
 		})
+
+		It("Should return a conflict without updating if the existing resource belongs to something else", func() {
+			updateCalled := false
+			mockK8sClient.getFunc = func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				u := obj.(*unstructured.Unstructured)
+				u.SetName(key.Name)
+				u.SetNamespace(key.Namespace)
+				u.SetResourceVersion("1")
+				return nil
+			}
+			mockK8sClient.updateFunc = func(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+				updateCalled = true
+				return nil
+			}
+
+			err := resourceManager.ensureAccessResourceExists(
+				ctx,
+				workspace,
+				accessStrategy,
+				service,
+				&accessStrategy.Spec.AccessResourceTemplates[0],
+				workspace.Namespace,
+			)
+
+			_, isConflict := asChildResourceConflict(err)
+			Expect(isConflict).To(BeTrue())
+			Expect(updateCalled).To(BeFalse())
+			Expect(workspace.Status.AccessResources).To(BeEmpty())
+		})
 	})
 
 	Context("ensureAccessResourceDeleted", func() {
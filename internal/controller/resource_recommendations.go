@@ -0,0 +1,560 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/rightsizing"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	// DefaultRecommendationSampleInterval is the default time between two usage samples of the workspaces
+	DefaultRecommendationSampleInterval = time.Minute
+
+	// DefaultRecommendationCheckpointInterval is the default time between two writes of the usage summary
+	// and the recommendations of a workspace
+	DefaultRecommendationCheckpointInterval = 10 * time.Minute
+
+	// DefaultRecommendationGapThreshold is the default relative gap between the requested resources and the
+	// recommendations above which an event is recorded
+	DefaultRecommendationGapThreshold = 0.5
+
+	// UsageConfigMapKey is the key of the usage ConfigMap of a workspace holding its usage summary
+	UsageConfigMapKey = "usage.json"
+
+	// ReasonResourceRecommendation is the event reason of a gap between the requested and recommended resources
+	ReasonResourceRecommendation = "ResourceRecommendation"
+	// ReasonResourceRecommendationsApplied is the event reason of applied resource recommendations
+	ReasonResourceRecommendationsApplied = "ResourceRecommendationsApplied"
+	// ReasonResourceRecommendationsNotApplied is the event reason of resource recommendations that could not be applied
+	ReasonResourceRecommendationsNotApplied = "ResourceRecommendationsNotApplied"
+)
+
+// podMetricsListGVK is the kind of the pod metrics the metrics server serves; they are read unstructured
+// to keep the metrics API out of the operator dependencies
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// ResourceRecommendationOptions configures the resource recommendations
+type ResourceRecommendationOptions struct {
+	// SampleInterval is the time between two usage samples of the workspaces
+	SampleInterval time.Duration
+	// CheckpointInterval is the time between two writes of the usage summary and the recommendations of a workspace
+	CheckpointInterval time.Duration
+	// HalfLife is the time after which a usage sample weighs half as much as a new one
+	HalfLife time.Duration
+	// Policy derives the recommendations from the usage
+	Policy rightsizing.Policy
+	// GapThreshold is the relative gap between the requested resources and the recommendations above which
+	// an event is recorded
+	GapThreshold float64
+}
+
+// DefaultResourceRecommendationOptions returns the default resource recommendation options
+func DefaultResourceRecommendationOptions() ResourceRecommendationOptions {
+	return ResourceRecommendationOptions{
+		SampleInterval:     DefaultRecommendationSampleInterval,
+		CheckpointInterval: DefaultRecommendationCheckpointInterval,
+		HalfLife:           rightsizing.DefaultHalfLife,
+		Policy:             rightsizing.DefaultPolicy,
+		GapThreshold:       DefaultRecommendationGapThreshold,
+	}
+}
+
+// workspaceUsageSample is the usage of the workspace container of a workspace pod
+type workspaceUsageSample struct {
+	workspaceName string
+	cpuCores      float64
+	memoryBytes   float64
+	timestamp     time.Time
+}
+
+// trackedUsage is the usage history of a workspace, with its checkpoint state
+type trackedUsage struct {
+	history *rightsizing.UsageHistory
+	// checkpointedAt is when the history was last written to the usage ConfigMap, or loaded from it
+	checkpointedAt time.Time
+	// dirty is true when samples were added since the last checkpoint
+	dirty bool
+}
+
+// ResourceRecommender samples the usage of the workspace containers from the metrics server, and recommends
+// resources from it in status.recommendations. The usage of each workspace is summarized in a decaying
+// histogram, written to a ConfigMap owned by the workspace so that it survives operator restarts. An event
+// flags workspaces whose requests are far from the recommendations; the owner applies them by hand or with
+// the AnnotationApplyResourceRecommendations annotation.
+type ResourceRecommender struct {
+	client   client.Client
+	reader   client.Reader
+	recorder record.EventRecorder
+	scope    *workspaceutil.Scope
+	options  ResourceRecommendationOptions
+
+	// listUsage returns the usage samples of the workspace pods of a namespace
+	listUsage func(ctx context.Context, namespace string) ([]workspaceUsageSample, error)
+	now       func() time.Time
+
+	usage              map[types.NamespacedName]*trackedUsage
+	metricsUnavailable bool
+}
+
+// NewResourceRecommender creates a new ResourceRecommender
+func NewResourceRecommender(
+	k8sClient client.Client,
+	reader client.Reader,
+	recorder record.EventRecorder,
+	scope *workspaceutil.Scope,
+	options ResourceRecommendationOptions,
+) (*ResourceRecommender, error) {
+	if err := options.Policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid resource recommendation policy: %w", err)
+	}
+	if options.SampleInterval <= 0 || options.CheckpointInterval <= 0 {
+		return nil, fmt.Errorf("invalid resource recommendation intervals: sample %s, checkpoint %s",
+			options.SampleInterval, options.CheckpointInterval)
+	}
+	if _, err := rightsizing.NewUsageHistory(options.HalfLife); err != nil {
+		return nil, fmt.Errorf("invalid resource recommendation half-life: %w", err)
+	}
+	recommender := &ResourceRecommender{
+		client:   k8sClient,
+		reader:   reader,
+		recorder: recorder,
+		scope:    scope,
+		options:  options,
+		now:      time.Now,
+		usage:    map[types.NamespacedName]*trackedUsage{},
+	}
+	recommender.listUsage = recommender.listPodMetrics
+	return recommender, nil
+}
+
+// Start samples the usage of the workspaces every sample interval until the context is done.
+// Implements the controller-runtime Runnable interface.
+func (r *ResourceRecommender) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("resource-recommender")
+	ctx = logf.IntoContext(ctx, logger)
+
+	ticker := time.NewTicker(r.options.SampleInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.Sample(ctx); err != nil {
+			logger.Error(err, "Failed to sample workspace usage")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection returns true because the recommender updates workspace statuses.
+func (r *ResourceRecommender) NeedLeaderElection() bool {
+	return true
+}
+
+// Sample adds the current usage of the workspaces in scope to their histories, and checkpoints the histories
+// due. Histories of workspaces that are gone are dropped.
+func (r *ResourceRecommender) Sample(ctx context.Context) error {
+	logger := logf.FromContext(ctx)
+
+	workspaces, err := r.listWorkspaces(ctx)
+	if err != nil {
+		return err
+	}
+	for key := range r.usage {
+		if _, found := workspaces[key]; !found {
+			delete(r.usage, key)
+		}
+	}
+
+	namespaces := map[string]bool{}
+	for key := range workspaces {
+		namespaces[key.Namespace] = true
+	}
+	sortedNamespaces := make([]string, 0, len(namespaces))
+	for namespace := range namespaces {
+		sortedNamespaces = append(sortedNamespaces, namespace)
+	}
+	sort.Strings(sortedNamespaces)
+
+	var errs []error
+	for _, namespace := range sortedNamespaces {
+		samples, err := r.listUsage(ctx, namespace)
+		if apimeta.IsNoMatchError(err) || apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			if !r.metricsUnavailable {
+				logger.Info("Pod metrics are not available, is the metrics server installed?", "error", err.Error())
+				r.metricsUnavailable = true
+			}
+			return nil
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to list pod metrics of namespace %s: %w", namespace, err))
+			continue
+		}
+		r.metricsUnavailable = false
+
+		for _, sample := range samples {
+			workspace, found := workspaces[types.NamespacedName{Namespace: namespace, Name: sample.workspaceName}]
+			if !found {
+				continue
+			}
+			tracked, err := r.trackedUsageFor(ctx, workspace)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if tracked.history.AddSample(sample.cpuCores, sample.memoryBytes, sample.timestamp) {
+				tracked.dirty = true
+			}
+		}
+	}
+
+	now := r.now()
+	for key, tracked := range r.usage {
+		if !tracked.dirty || now.Sub(tracked.checkpointedAt) < r.options.CheckpointInterval {
+			continue
+		}
+		if err := r.checkpoint(ctx, workspaces[key], tracked); err != nil {
+			errs = append(errs, fmt.Errorf("failed to checkpoint the usage of workspace %s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listWorkspaces returns the workspaces in scope that are not being deleted
+func (r *ResourceRecommender) listWorkspaces(ctx context.Context) (map[types.NamespacedName]*workspacev1alpha1.Workspace, error) {
+	list := &workspacev1alpha1.WorkspaceList{}
+	if err := workspaceutil.ListAll(ctx, r.client, list); err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	workspaces := map[types.NamespacedName]*workspacev1alpha1.Workspace{}
+	for i := range list.Items {
+		workspace := &list.Items[i]
+		if !workspace.DeletionTimestamp.IsZero() || !r.scope.Matches(workspace) {
+			continue
+		}
+		workspaces[client.ObjectKeyFromObject(workspace)] = workspace
+	}
+	return workspaces, nil
+}
+
+// listPodMetrics returns the usage samples of the workspace pods of the namespace, from the metrics server
+func (r *ResourceRecommender) listPodMetrics(ctx context.Context, namespace string) ([]workspaceUsageSample, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := r.reader.List(ctx, list, client.InNamespace(namespace),
+		client.HasLabels{workspaceutil.LabelWorkspaceName}); err != nil {
+		return nil, err
+	}
+	return workspaceUsageSamples(list), nil
+}
+
+// podMetrics is the part of a metrics.k8s.io PodMetrics the recommender reads
+type podMetrics struct {
+	Timestamp  metav1.Time `json:"timestamp"`
+	Containers []struct {
+		Name  string              `json:"name"`
+		Usage corev1.ResourceList `json:"usage"`
+	} `json:"containers"`
+}
+
+// workspaceUsageSamples returns the usage of the workspace containers of the pod metrics. Pods without a
+// workspace container, such as pods still starting, are skipped.
+func workspaceUsageSamples(list *unstructured.UnstructuredList) []workspaceUsageSample {
+	samples := make([]workspaceUsageSample, 0, len(list.Items))
+	for _, item := range list.Items {
+		workspaceName := item.GetLabels()[workspaceutil.LabelWorkspaceName]
+		if workspaceName == "" {
+			continue
+		}
+		metrics := podMetrics{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &metrics); err != nil {
+			continue
+		}
+		for _, container := range metrics.Containers {
+			if container.Name != workspaceContainerName {
+				continue
+			}
+			samples = append(samples, workspaceUsageSample{
+				workspaceName: workspaceName,
+				cpuCores:      container.Usage.Cpu().AsApproximateFloat64(),
+				memoryBytes:   container.Usage.Memory().AsApproximateFloat64(),
+				timestamp:     metrics.Timestamp.Time,
+			})
+		}
+	}
+	return samples
+}
+
+// trackedUsageFor returns the usage history of the workspace, loaded from its usage ConfigMap the first time
+func (r *ResourceRecommender) trackedUsageFor(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*trackedUsage, error) {
+	key := client.ObjectKeyFromObject(workspace)
+	if tracked, found := r.usage[key]; found {
+		return tracked, nil
+	}
+
+	history, err := rightsizing.NewUsageHistory(r.options.HalfLife)
+	if err != nil {
+		return nil, err
+	}
+	usageConfigMap := &corev1.ConfigMap{}
+	err = r.reader.Get(ctx, client.ObjectKey{Name: usageConfigMapNameFor(workspace), Namespace: workspace.Namespace}, usageConfigMap)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get the usage ConfigMap of workspace %s: %w", key, err)
+	default:
+		checkpoint := rightsizing.UsageCheckpoint{}
+		if err := json.Unmarshal([]byte(usageConfigMap.Data[UsageConfigMapKey]), &checkpoint); err != nil {
+			logf.FromContext(ctx).Error(err, "Ignoring unreadable usage summary", "workspace", key)
+		} else if err := history.LoadCheckpoint(checkpoint); err != nil {
+			logf.FromContext(ctx).Error(err, "Ignoring invalid usage summary", "workspace", key)
+			history, _ = rightsizing.NewUsageHistory(r.options.HalfLife)
+		}
+	}
+
+	tracked := &trackedUsage{history: history, checkpointedAt: r.now()}
+	r.usage[key] = tracked
+	return tracked, nil
+}
+
+// checkpoint writes the usage history of the workspace to its usage ConfigMap, then updates its
+// recommendations, recording an event when its requests are newly far from them
+func (r *ResourceRecommender) checkpoint(ctx context.Context, workspace *workspacev1alpha1.Workspace, tracked *trackedUsage) error {
+	data, err := json.Marshal(tracked.history.Checkpoint())
+	if err != nil {
+		return fmt.Errorf("failed to serialize the usage summary: %w", err)
+	}
+	if err := r.writeUsageConfigMap(ctx, workspace, string(data)); err != nil {
+		return err
+	}
+	tracked.checkpointedAt = r.now()
+	tracked.dirty = false
+
+	recommendations := buildResourceRecommendations(tracked.history, r.options.Policy, r.now())
+	if recommendations == nil {
+		return nil
+	}
+	workspace = workspace.DeepCopy()
+	previousGaps := recommendationGaps(workspace.Spec.Resources, workspace.Status.Recommendations, r.options.GapThreshold)
+	patch := client.MergeFrom(workspace.DeepCopy())
+	workspace.Status.Recommendations = recommendations
+	if _, err := patchChangedStatus(ctx, r.client, workspace, patch); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if gaps := recommendationGaps(workspace.Spec.Resources, recommendations, r.options.GapThreshold); len(gaps) > 0 && len(previousGaps) == 0 {
+		r.recorder.Eventf(workspace, corev1.EventTypeNormal, ReasonResourceRecommendation,
+			"Requested resources are far from the observed usage: %s; set annotation %s to apply the recommendations",
+			strings.Join(gaps, ", "), AnnotationApplyResourceRecommendations)
+	}
+	return nil
+}
+
+// writeUsageConfigMap creates or updates the usage ConfigMap of the workspace, owned by the workspace
+func (r *ResourceRecommender) writeUsageConfigMap(ctx context.Context, workspace *workspacev1alpha1.Workspace, usage string) error {
+	usageConfigMap := &corev1.ConfigMap{}
+	err := r.reader.Get(ctx, client.ObjectKey{Name: usageConfigMapNameFor(workspace), Namespace: workspace.Namespace}, usageConfigMap)
+	if apierrors.IsNotFound(err) {
+		usageConfigMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      usageConfigMapNameFor(workspace),
+				Namespace: workspace.Namespace,
+				Labels:    map[string]string{workspaceutil.LabelWorkspaceName: workspace.Name},
+			},
+			Data: map[string]string{UsageConfigMapKey: usage},
+		}
+		if err := controllerutil.SetControllerReference(workspace, usageConfigMap, r.client.Scheme()); err != nil {
+			return fmt.Errorf("failed to set the owner of the usage ConfigMap: %w", err)
+		}
+		if err := r.client.Create(ctx, usageConfigMap); err != nil {
+			return fmt.Errorf("failed to create the usage ConfigMap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the usage ConfigMap: %w", err)
+	}
+	usageConfigMap.Data = map[string]string{UsageConfigMapKey: usage}
+	if err := r.client.Update(ctx, usageConfigMap); err != nil {
+		return fmt.Errorf("failed to update the usage ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// buildResourceRecommendations returns the recommendations of the policy for the usage history, or nil
+// while the history is too short
+func buildResourceRecommendations(
+	history *rightsizing.UsageHistory,
+	policy rightsizing.Policy,
+	now time.Time) *workspacev1alpha1.ResourceRecommendations {
+	cpu, memory, ok := policy.Recommend(history)
+	if !ok {
+		return nil
+	}
+	return &workspacev1alpha1.ResourceRecommendations{
+		CPU: &workspacev1alpha1.ResourceRecommendation{
+			Usage:   cpuQuantity(cpu.Usage),
+			Request: cpuQuantity(cpu.Request),
+			Limit:   cpuQuantity(cpu.Limit),
+		},
+		Memory: &workspacev1alpha1.ResourceRecommendation{
+			Usage:   memoryQuantity(memory.Usage),
+			Request: memoryQuantity(memory.Request),
+			Limit:   memoryQuantity(memory.Limit),
+		},
+		ObservedSince:  metav1.NewTime(history.FirstSampleTime),
+		SampleCount:    history.SampleCount,
+		LastUpdateTime: metav1.NewTime(now),
+	}
+}
+
+// cpuQuantity returns the cores rounded up to the millicore
+func cpuQuantity(cores float64) resource.Quantity {
+	return *resource.NewMilliQuantity(int64(math.Ceil(cores*1000)), resource.DecimalSI)
+}
+
+// memoryQuantity returns the bytes rounded up to the mebibyte
+func memoryQuantity(bytes float64) resource.Quantity {
+	const mebibyte = 1024 * 1024
+	return *resource.NewQuantity(int64(math.Ceil(bytes/mebibyte))*mebibyte, resource.BinarySI)
+}
+
+// recommendationGaps describes the requests whose relative gap to the recommendations exceeds the threshold
+func recommendationGaps(
+	resources *corev1.ResourceRequirements,
+	recommendations *workspacev1alpha1.ResourceRecommendations,
+	threshold float64) []string {
+	if resources == nil || recommendations == nil {
+		return nil
+	}
+	var gaps []string
+	check := func(name corev1.ResourceName, recommendation *workspacev1alpha1.ResourceRecommendation) {
+		requested, found := resources.Requests[name]
+		if !found || recommendation == nil {
+			return
+		}
+		gap := rightsizing.RelativeGap(requested.AsApproximateFloat64(), recommendation.Request.AsApproximateFloat64())
+		if gap > threshold {
+			gaps = append(gaps, fmt.Sprintf("%s request %s, recommended %s", name, requested.String(), recommendation.Request.String()))
+		}
+	}
+	check(corev1.ResourceCPU, recommendations.CPU)
+	check(corev1.ResourceMemory, recommendations.Memory)
+	return gaps
+}
+
+// applyResourceRecommendations sets the CPU and memory of the workspace container to the recommendations
+// when the owner requests it with the AnnotationApplyResourceRecommendations annotation, and removes the
+// annotation. The deployment then rolls out the new resources. It returns true when the workspace was
+// updated and must be reconciled again.
+func (sm *StateMachine) applyResourceRecommendations(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if _, requested := workspace.Annotations[AnnotationApplyResourceRecommendations]; !requested {
+		return false, nil
+	}
+	logger := logf.FromContext(ctx)
+
+	recommendations := workspace.Status.Recommendations
+	if recommendations == nil || recommendations.CPU == nil || recommendations.Memory == nil {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonResourceRecommendationsNotApplied,
+			"No resource recommendations to apply yet: not enough usage was observed")
+		return true, sm.removeApplyRecommendationsAnnotation(ctx, workspace)
+	}
+
+	updated := workspace.DeepCopy()
+	delete(updated.Annotations, AnnotationApplyResourceRecommendations)
+	if updated.Spec.Resources == nil {
+		updated.Spec.Resources = &corev1.ResourceRequirements{}
+	}
+	if updated.Spec.Resources.Requests == nil {
+		updated.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	if updated.Spec.Resources.Limits == nil {
+		updated.Spec.Resources.Limits = corev1.ResourceList{}
+	}
+	updated.Spec.Resources.Requests[corev1.ResourceCPU] = recommendations.CPU.Request
+	updated.Spec.Resources.Limits[corev1.ResourceCPU] = recommendations.CPU.Limit
+	updated.Spec.Resources.Requests[corev1.ResourceMemory] = recommendations.Memory.Request
+	updated.Spec.Resources.Limits[corev1.ResourceMemory] = recommendations.Memory.Limit
+
+	err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, updated, workspace)
+	if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+		// The template bounds or a quota reject the recommendations; keep the resources and drop the request
+		logger.Info("Resource recommendations rejected", "error", err.Error())
+		sm.recorder.Eventf(workspace, corev1.EventTypeWarning, ReasonResourceRecommendationsNotApplied,
+			"Resource recommendations were rejected: %v", err)
+		return true, sm.removeApplyRecommendationsAnnotation(ctx, workspace)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to apply the resource recommendations: %w", err)
+	}
+
+	message := fmt.Sprintf("Applied resource recommendations: cpu %s/%s, memory %s/%s (request/limit)",
+		recommendations.CPU.Request.String(), recommendations.CPU.Limit.String(),
+		recommendations.Memory.Request.String(), recommendations.Memory.Limit.String())
+	logger.Info(message)
+	sm.recorder.Event(updated, corev1.EventTypeNormal, ReasonResourceRecommendationsApplied, message)
+	updated.DeepCopyInto(workspace)
+	return true, nil
+}
+
+// removeApplyRecommendationsAnnotation removes the AnnotationApplyResourceRecommendations annotation
+func (sm *StateMachine) removeApplyRecommendationsAnnotation(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	patch := client.MergeFrom(workspace.DeepCopy())
+	delete(workspace.Annotations, AnnotationApplyResourceRecommendations)
+	if err := sm.resourceManager.client.Patch(ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", AnnotationApplyResourceRecommendations, err)
+	}
+	return nil
+}
+
+// SetupResourceRecommender adds the resource recommender to the manager
+func SetupResourceRecommender(
+	mgr ctrl.Manager,
+	scope *workspaceutil.Scope,
+	options ResourceRecommendationOptions,
+) error {
+	recommender, err := NewResourceRecommender(
+		newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout),
+		newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
+		mgr.GetEventRecorderFor("resource-recommender"),
+		scope,
+		options,
+	)
+	if err != nil {
+		return err
+	}
+	if err := mgr.Add(recommender); err != nil {
+		return fmt.Errorf("failed to add resource recommender: %w", err)
+	}
+	return nil
+}
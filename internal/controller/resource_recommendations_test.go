@@ -0,0 +1,319 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/rightsizing"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var recommenderTestStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+// recommenderTestClock is a settable clock for the recommender
+type recommenderTestClock struct {
+	now time.Time
+}
+
+func (c *recommenderTestClock) Now() time.Time {
+	return c.now
+}
+
+// newTestResourceRecommender returns a recommender reporting cpuCores and memoryBytes for every workspace, at the
+// time of the clock, with a one hour minimum history
+func newTestResourceRecommender(
+	t *testing.T,
+	k8sClient client.Client,
+	clock *recommenderTestClock,
+	cpuCores, memoryBytes *float64) (*ResourceRecommender, *record.FakeRecorder) {
+	options := DefaultResourceRecommendationOptions()
+	options.Policy.MinHistory = time.Hour
+	recorder := record.NewFakeRecorder(100)
+	recommender, err := NewResourceRecommender(k8sClient, k8sClient, recorder, nil, options)
+	require.NoError(t, err)
+	recommender.now = clock.Now
+	recommender.listUsage = func(ctx context.Context, namespace string) ([]workspaceUsageSample, error) {
+		return []workspaceUsageSample{
+			{workspaceName: "ws", cpuCores: *cpuCores, memoryBytes: *memoryBytes, timestamp: clock.now},
+			{workspaceName: "unknown", cpuCores: 1, memoryBytes: 1, timestamp: clock.now},
+		}, nil
+	}
+	return recommender, recorder
+}
+
+// sampleFor samples every sample interval for the duration
+func sampleFor(t *testing.T, recommender *ResourceRecommender, clock *recommenderTestClock, duration time.Duration) {
+	for end := clock.now.Add(duration); clock.now.Before(end); clock.now = clock.now.Add(recommender.options.SampleInterval) {
+		require.NoError(t, recommender.Sample(context.Background()))
+	}
+}
+
+func newRecommenderTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+	return workspace
+}
+
+func TestResourceRecommenderRecommendsAfterTheMinimumHistory(t *testing.T) {
+	ctx := context.Background()
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, newRecommenderTestWorkspace())
+	clock := &recommenderTestClock{now: recommenderTestStart}
+	cpuCores, memoryBytes := 0.5, float64(900*1024*1024)
+	recommender, recorder := newTestResourceRecommender(t, k8sClient, clock, &cpuCores, &memoryBytes)
+
+	sampleFor(t, recommender, clock, 30*time.Minute)
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "ws", Namespace: "team-a"}, workspace))
+	assert.Nil(t, workspace.Status.Recommendations, "half of the minimum history")
+	assert.Len(t, recommender.usage, 1, "unknown workspaces are not tracked")
+
+	sampleFor(t, recommender, clock, time.Hour)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), workspace))
+	recommendations := workspace.Status.Recommendations
+	require.NotNil(t, recommendations)
+	assert.Equal(t, recommenderTestStart, recommendations.ObservedSince.UTC())
+	assert.Positive(t, recommendations.SampleCount)
+	require.NotNil(t, recommendations.CPU)
+	assert.InEpsilon(t, 0.5*1.15, recommendations.CPU.Request.AsApproximateFloat64(), 0.1)
+	assert.GreaterOrEqual(t, recommendations.CPU.Limit.Cmp(recommendations.CPU.Request), 0)
+	require.NotNil(t, recommendations.Memory)
+	assert.InEpsilon(t, 900*1024*1024*1.15, recommendations.Memory.Request.AsApproximateFloat64(), 0.1)
+
+	// The usage summary survives restarts in the usage ConfigMap
+	usage := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: usageConfigMapNameFor(workspace), Namespace: "team-a"}, usage))
+	assert.Contains(t, usage.Data, UsageConfigMapKey)
+	require.Len(t, usage.OwnerReferences, 1)
+	assert.Equal(t, workspace.UID, usage.OwnerReferences[0].UID)
+
+	// 4 cores requested for half a core used: one event, not one per checkpoint
+	require.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, ReasonResourceRecommendation)
+	assert.Contains(t, event, "cpu request 4")
+	assert.NotContains(t, event, "memory", "the memory request is close to the recommendation")
+	sampleFor(t, recommender, clock, time.Hour)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestResourceRecommenderRestoresTheUsageSummary(t *testing.T) {
+	ctx := context.Background()
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, newRecommenderTestWorkspace())
+	clock := &recommenderTestClock{now: recommenderTestStart}
+	cpuCores, memoryBytes := 2.0, float64(900*1024*1024)
+	recommender, _ := newTestResourceRecommender(t, k8sClient, clock, &cpuCores, &memoryBytes)
+	sampleFor(t, recommender, clock, 50*time.Minute)
+	expected := recommender.usage[client.ObjectKey{Name: "ws", Namespace: "team-a"}].history
+
+	// A new operator instance picks up the history where the last checkpoint left it
+	restarted, _ := newTestResourceRecommender(t, k8sClient, clock, &cpuCores, &memoryBytes)
+	sampleFor(t, restarted, clock, 20*time.Minute)
+	restored := restarted.usage[client.ObjectKey{Name: "ws", Namespace: "team-a"}].history
+	assert.Equal(t, expected.FirstSampleTime, restored.FirstSampleTime)
+
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "ws", Namespace: "team-a"}, workspace))
+	require.NotNil(t, workspace.Status.Recommendations, "an hour is observed across the restart")
+}
+
+func TestResourceRecommenderDropsDeletedWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	workspace := newRecommenderTestWorkspace()
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	clock := &recommenderTestClock{now: recommenderTestStart}
+	cpuCores, memoryBytes := 1.0, float64(1024*1024*1024)
+	recommender, _ := newTestResourceRecommender(t, k8sClient, clock, &cpuCores, &memoryBytes)
+	sampleFor(t, recommender, clock, time.Minute)
+	require.Len(t, recommender.usage, 1)
+
+	require.NoError(t, k8sClient.Delete(ctx, workspace))
+	sampleFor(t, recommender, clock, time.Minute)
+	assert.Empty(t, recommender.usage)
+}
+
+func TestResourceRecommenderToleratesMissingMetricsServer(t *testing.T) {
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, newRecommenderTestWorkspace())
+	clock := &recommenderTestClock{now: recommenderTestStart}
+	cpuCores, memoryBytes := 1.0, float64(1024*1024*1024)
+	recommender, _ := newTestResourceRecommender(t, k8sClient, clock, &cpuCores, &memoryBytes)
+	recommender.listUsage = func(ctx context.Context, namespace string) ([]workspaceUsageSample, error) {
+		return nil, &apimeta.NoKindMatchError{GroupKind: schema.GroupKind{Group: "metrics.k8s.io", Kind: "PodMetrics"}}
+	}
+
+	require.NoError(t, recommender.Sample(context.Background()))
+	assert.True(t, recommender.metricsUnavailable)
+	assert.Empty(t, recommender.usage)
+}
+
+func TestWorkspaceUsageSamples(t *testing.T) {
+	list := &unstructured.UnstructuredList{}
+	list.Items = []unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"metadata":  map[string]interface{}{"name": "pod-a", "labels": map[string]interface{}{workspaceutil.LabelWorkspaceName: "ws"}},
+			"timestamp": "2026-10-01T00:00:00Z",
+			"containers": []interface{}{
+				map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"cpu": "1", "memory": "1Gi"}},
+				map[string]interface{}{"name": workspaceContainerName, "usage": map[string]interface{}{"cpu": "250m", "memory": "512Mi"}},
+			},
+		}},
+		{Object: map[string]interface{}{
+			"metadata":   map[string]interface{}{"name": "unrelated"},
+			"timestamp":  "2026-10-01T00:00:00Z",
+			"containers": []interface{}{map[string]interface{}{"name": workspaceContainerName, "usage": map[string]interface{}{"cpu": "1"}}},
+		}},
+	}
+
+	samples := workspaceUsageSamples(list)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "ws", samples[0].workspaceName)
+	assert.InDelta(t, 0.25, samples[0].cpuCores, 1e-9)
+	assert.InDelta(t, 512*1024*1024, samples[0].memoryBytes, 1)
+	assert.Equal(t, recommenderTestStart, samples[0].timestamp.UTC())
+}
+
+func TestBuildResourceRecommendationsRoundsUp(t *testing.T) {
+	history, err := rightsizing.NewUsageHistory(rightsizing.DefaultHalfLife)
+	require.NoError(t, err)
+	policy := rightsizing.DefaultPolicy
+	assert.Nil(t, buildResourceRecommendations(history, policy, recommenderTestStart))
+
+	policy.MinHistory = 0
+	history.AddSample(0.1234, 100*1024*1024+1, recommenderTestStart)
+	recommendations := buildResourceRecommendations(history, policy, recommenderTestStart)
+	require.NotNil(t, recommendations)
+	cpuMillis := recommendations.CPU.Request.MilliValue()
+	assert.Equal(t, float64(cpuMillis)/1000, recommendations.CPU.Request.AsApproximateFloat64(), "whole millicores")
+	assert.Zero(t, recommendations.Memory.Request.Value()%(1024*1024), "whole mebibytes")
+	assert.GreaterOrEqual(t, recommendations.Memory.Request.Value(), int64(100*1024*1024+1))
+}
+
+func TestRecommendationGaps(t *testing.T) {
+	recommendations := &workspacev1alpha1.ResourceRecommendations{
+		CPU:    &workspacev1alpha1.ResourceRecommendation{Request: resource.MustParse("500m")},
+		Memory: &workspacev1alpha1.ResourceRecommendation{Request: resource.MustParse("1Gi")},
+	}
+	assert.Empty(t, recommendationGaps(nil, recommendations, 0.5), "nothing requested")
+	assert.Empty(t, recommendationGaps(&corev1.ResourceRequirements{}, nil, 0.5), "nothing recommended")
+
+	resources := &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("1200Mi"),
+	}}
+	gaps := recommendationGaps(resources, recommendations, 0.5)
+	require.Len(t, gaps, 1)
+	assert.True(t, strings.HasPrefix(gaps[0], "cpu request 2"), gaps[0])
+	assert.Len(t, recommendationGaps(resources, recommendations, 0.1), 2)
+}
+
+// newApplyRecommendationsTestWorkspace returns a workspace with recommendations, requesting to apply them
+func newApplyRecommendationsTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newRecommenderTestWorkspace()
+	workspace.Annotations = map[string]string{AnnotationApplyResourceRecommendations: "true"}
+	workspace.Status.Recommendations = &workspacev1alpha1.ResourceRecommendations{
+		CPU: &workspacev1alpha1.ResourceRecommendation{
+			Request: resource.MustParse("600m"),
+			Limit:   resource.MustParse("2"),
+		},
+		Memory: &workspacev1alpha1.ResourceRecommendation{
+			Request: resource.MustParse("1100Mi"),
+			Limit:   resource.MustParse("3Gi"),
+		},
+	}
+	return workspace
+}
+
+func TestApplyResourceRecommendations(t *testing.T) {
+	ctx := context.Background()
+	workspace := newApplyRecommendationsTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	applied, err := sm.applyResourceRecommendations(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationApplyResourceRecommendations)
+	resources := stored.Spec.Resources
+	assert.Equal(t, "600m", resources.Requests.Cpu().String())
+	assert.Equal(t, "2", resources.Limits.Cpu().String())
+	assert.Equal(t, "1100Mi", resources.Requests.Memory().String())
+	assert.Equal(t, "3Gi", resources.Limits.Memory().String())
+
+	// Without the annotation there is nothing to do
+	applied, err = sm.applyResourceRecommendations(ctx, stored)
+	require.NoError(t, err)
+	assert.False(t, applied)
+}
+
+func TestApplyResourceRecommendationsWithoutRecommendations(t *testing.T) {
+	ctx := context.Background()
+	workspace := newApplyRecommendationsTestWorkspace()
+	workspace.Status.Recommendations = nil
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	applied, err := sm.applyResourceRecommendations(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationApplyResourceRecommendations)
+	assert.Equal(t, "4", stored.Spec.Resources.Requests.Cpu().String(), "the resources are left alone")
+	event := <-sm.recorder.(*record.FakeRecorder).Events
+	assert.Contains(t, event, ReasonResourceRecommendationsNotApplied)
+}
+
+func TestApplyResourceRecommendationsRejected(t *testing.T) {
+	ctx := context.Background()
+	workspace := newApplyRecommendationsTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			// Quota admission rejects the new resources, not the removal of the annotation
+			data, err := patch.Data(obj)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(string(data), `"resources"`) {
+				return apierrors.NewForbidden(schema.GroupResource{Group: "workspace.jupyter.org", Resource: "workspaces"},
+					obj.GetName(), nil)
+			}
+			return c.Patch(ctx, obj, patch, opts...)
+		},
+	}, workspace)
+
+	applied, err := sm.applyResourceRecommendations(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, applied)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationApplyResourceRecommendations, "the request is dropped")
+	assert.Equal(t, "4", stored.Spec.Resources.Requests.Cpu().String())
+	event := <-sm.recorder.(*record.FakeRecorder).Events
+	assert.Contains(t, event, ReasonResourceRecommendationsNotApplied)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// EventReasonRestartSkipped is the reason of the event recorded when a restart is requested while the
+	// workspace does not run
+	EventReasonRestartSkipped = "RestartSkipped"
+)
+
+// restartRequested returns true if spec.restartRequestedAt is newer than the last restart request handled
+func restartRequested(workspace *workspacev1alpha1.Workspace) bool {
+	requested := workspace.Spec.RestartRequestedAt
+	if requested == nil {
+		return false
+	}
+	last := workspace.Status.LastRestartTime
+	return last == nil || requested.After(last.Time)
+}
+
+// markRestartHandled records the pending restart request as handled, in memory. The status keeps the
+// requested time rather than the time of the restart, so that the requests made until the restart
+// happens are coalesced into it.
+func markRestartHandled(workspace *workspacev1alpha1.Workspace) {
+	if restartRequested(workspace) {
+		requested := *workspace.Spec.RestartRequestedAt
+		workspace.Status.LastRestartTime = &requested
+	}
+}
+
+// ensureRestarted rolls the deployment of the workspace on request of spec.restartRequestedAt: the pod
+// template records the request, so the deployment replaces the pod. Restart requests are handled
+// whether the workspace is available or not, to bounce a pod that fails to start.
+func (rm *ResourceManager) ensureRestarted(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (*appsv1.Deployment, bool, error) {
+	if !restartRequested(workspace) {
+		return deployment, false, nil
+	}
+
+	logf.FromContext(ctx).Info("Restarting workspace pod on request",
+		"deployment", deployment.Name, "restartRequestedAt", workspace.Spec.RestartRequestedAt)
+	updated, err := rm.updateDeployment(ctx, deployment, workspace, accessStrategy)
+	if err != nil {
+		return nil, false, err
+	}
+	markRestartHandled(workspace)
+	// The new pod starts with the current Secret values
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending)
+	return updated, true, nil
+}
+
+// skipRestartOfStoppedWorkspace handles a restart requested while the workspace does not run: there is
+// no pod to restart and the next start brings a new one. The request is recorded as handled in memory,
+// with an event explaining why nothing restarted.
+func (sm *StateMachine) skipRestartOfStoppedWorkspace(workspace *workspacev1alpha1.Workspace, desiredStatus string) {
+	if desiredStatus == DesiredStateRunning || !restartRequested(workspace) {
+		return
+	}
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, EventReasonRestartSkipped,
+		fmt.Sprintf("Restart requested at %s skipped: the workspace is %s, its next start brings a new pod",
+			workspace.Spec.RestartRequestedAt.UTC().Format(time.RFC3339), desiredStatus))
+	markRestartHandled(workspace)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestRestartRequestedOnlyByNewerRequests(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	assert.False(t, restartRequested(workspace))
+
+	requested := metav1.NewTime(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC))
+	workspace.Spec.RestartRequestedAt = &requested
+	assert.True(t, restartRequested(workspace))
+
+	markRestartHandled(workspace)
+	assert.Equal(t, &requested, workspace.Status.LastRestartTime)
+	assert.False(t, restartRequested(workspace), "a handled request does not restart again")
+
+	older := metav1.NewTime(requested.Add(-time.Minute))
+	workspace.Spec.RestartRequestedAt = &older
+	assert.False(t, restartRequested(workspace))
+}
+
+func TestEnsureDeploymentRestartsThePodOnRequest(t *testing.T) {
+	ctx := context.Background()
+	workspace := newRolloutTestWorkspace()
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	createStartedDeployment(t, sm, k8sClient, workspace, "")
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.NotContains(t, getRolloutTestDeployment(t, k8sClient).Spec.Template.Annotations, AnnotationRestartedAt)
+
+	// Requests made until the restart happens are coalesced into it
+	requested := metav1.NewTime(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC))
+	workspace.Spec.RestartRequestedAt = &requested
+	workspace.Status.Conditions = nil
+	_, err = sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "2026-03-02T09:30:00Z", stored.Spec.Template.Annotations[AnnotationRestartedAt],
+		"the pod is restarted even while the workspace is not available")
+	assert.Equal(t, &requested, workspace.Status.LastRestartTime)
+
+	_, err = sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, stored.ResourceVersion, getRolloutTestDeployment(t, k8sClient).ResourceVersion,
+		"a handled request does not restart the pod again")
+}
+
+func TestEnsureDeploymentCreationHandlesPendingRestart(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	requested := metav1.NewTime(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC))
+	workspace.Spec.RestartRequestedAt = &requested
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &requested, workspace.Status.LastRestartTime)
+}
+
+func TestRestartOfStoppedWorkspaceIsSkipped(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	requested := metav1.NewTime(time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC))
+	workspace.Spec.RestartRequestedAt = &requested
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{})
+	recorder := record.NewFakeRecorder(10)
+	sm.recorder = recorder
+
+	sm.skipRestartOfStoppedWorkspace(workspace, DesiredStateRunning)
+	assert.Nil(t, workspace.Status.LastRestartTime, "running workspaces restart through their deployment")
+
+	sm.skipRestartOfStoppedWorkspace(workspace, DesiredStateStopped)
+	assert.Equal(t, &requested, workspace.Status.LastRestartTime)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal RestartSkipped Restart requested at 2026-03-02T09:30:00Z skipped: the workspace is Stopped, "+
+		"its next start brings a new pod", <-recorder.Events)
+
+	sm.skipRestartOfStoppedWorkspace(workspace, DesiredStateStopped)
+	assert.Empty(t, recorder.Events, "the event is recorded once per request")
+}
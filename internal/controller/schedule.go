@@ -0,0 +1,160 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// EventReasonScheduledStart is the reason of the event recorded when the schedule starts a workspace
+	EventReasonScheduledStart = "ScheduledStart"
+	// EventReasonScheduledStop is the reason of the event recorded when the schedule stops a workspace
+	EventReasonScheduledStop = "ScheduledStop"
+)
+
+// scheduleOutcome is the result of evaluating the schedule of a workspace
+type scheduleOutcome struct {
+	// nextTransition is the next scheduled start or stop; zero when there is none
+	nextTransition time.Time
+
+	// updated is true when the workspace object was updated and must be reconciled again
+	updated bool
+}
+
+// ParseSchedule parses the start and stop expressions of a schedule; an unset expression is nil
+func ParseSchedule(schedule *workspacev1alpha1.ScheduleSpec) (start, stop *CronSchedule, err error) {
+	if schedule.StartCron != "" {
+		if start, err = ParseCronSchedule(schedule.StartCron, schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid startCron: %w", err)
+		}
+	}
+	if schedule.StopCron != "" {
+		if stop, err = ParseCronSchedule(schedule.StopCron, schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid stopCron: %w", err)
+		}
+	}
+	return start, stop, nil
+}
+
+// scheduledBoundaries returns the last and next activations of a cron schedule, zero when it is nil
+func scheduledBoundaries(schedule *CronSchedule, now time.Time) (last, next time.Time) {
+	if schedule == nil {
+		return time.Time{}, time.Time{}
+	}
+	return schedule.Prev(now), schedule.Next(now)
+}
+
+// metaTimeOrNil returns t as a metav1.Time, nil when it is zero
+func metaTimeOrNil(t time.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &metav1.Time{Time: t}
+}
+
+// reconcileSchedule records the schedule intent of the last scheduled start or stop, which applies until
+// the next one, and reports the next ones in the status. When a scheduled start or stop passes and the
+// schedule wins resolution, spec.desiredStatus is flipped like the culler does, with an event. A manual
+// change of spec.desiredStatus after the scheduled start or stop wins until the next one.
+// The status is updated in memory.
+func (sm *StateMachine) reconcileSchedule(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, now time.Time) (scheduleOutcome, error) {
+	logger := logf.FromContext(ctx)
+
+	// Reported after the patches, which reload the status
+	var lastStart, nextStart, lastStop, nextStop time.Time
+	defer func() {
+		workspace.Status.NextScheduledStart = metaTimeOrNil(nextStart)
+		workspace.Status.NextScheduledStop = metaTimeOrNil(nextStop)
+	}()
+
+	if workspace.Spec.Schedule == nil {
+		return sm.removeScheduleIntent(ctx, workspace, time.Time{})
+	}
+	start, stop, err := ParseSchedule(workspace.Spec.Schedule)
+	if err != nil {
+		// The webhook validates the schedule; the time zone may still be unknown to the controller
+		logger.Error(err, "Ignoring the schedule of the workspace")
+		return sm.removeScheduleIntent(ctx, workspace, time.Time{})
+	}
+	lastStart, nextStart = scheduledBoundaries(start, now)
+	lastStop, nextStop = scheduledBoundaries(stop, now)
+
+	next := nextStart
+	if next.IsZero() || (!nextStop.IsZero() && nextStop.Before(next)) {
+		next = nextStop
+	}
+	// A stop at the same minute as a start wins
+	desiredStatus, boundary := DesiredStateStopped, lastStop
+	if lastStart.After(lastStop) {
+		desiredStatus, boundary = DesiredStateRunning, lastStart
+	}
+	if boundary.IsZero() {
+		// Nothing was scheduled yet
+		return sm.removeScheduleIntent(ctx, workspace, next)
+	}
+
+	record := DesiredStatusIntentRecord{
+		DesiredStatus: desiredStatus,
+		SetAt:         &metav1.Time{Time: boundary},
+		ExpiresAt:     metaTimeOrNil(next),
+	}
+	previous, hasPrevious := parseDesiredStatusIntent(workspace.Annotations[AnnotationScheduleIntent])
+	if hasPrevious && previous.DesiredStatus == record.DesiredStatus && previous.SetAt.Equal(record.SetAt) &&
+		previous.ExpiresAt.Equal(record.ExpiresAt) {
+		return scheduleOutcome{nextTransition: next}, nil
+	}
+	value, err := EncodeDesiredStatusIntent(record)
+	if err != nil {
+		return scheduleOutcome{}, err
+	}
+
+	original := workspace.DeepCopy()
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[AnnotationScheduleIntent] = value
+	passed := !hasPrevious || !previous.SetAt.Equal(record.SetAt)
+	if passed && workspace.Spec.DesiredStatus != desiredStatus &&
+		sm.intentResolver.resolveAt(workspace, now).Intent.Actor == IntentActorSchedule {
+		workspace.Spec.DesiredStatus = desiredStatus
+		reason, message := EventReasonScheduledStop,
+			fmt.Sprintf("Stopping the workspace on schedule %q", workspace.Spec.Schedule.StopCron)
+		if desiredStatus == DesiredStateRunning {
+			reason, message = EventReasonScheduledStart,
+				fmt.Sprintf("Starting the workspace on schedule %q", workspace.Spec.Schedule.StartCron)
+		}
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, reason, message)
+		logger.Info("Applying the schedule", "desiredStatus", desiredStatus, "boundary", boundary, "next", next)
+	}
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+		return scheduleOutcome{}, fmt.Errorf("failed to record the schedule intent: %w", err)
+	}
+	return scheduleOutcome{nextTransition: next, updated: true}, nil
+}
+
+// removeScheduleIntent removes the schedule intent of a workspace whose schedule no longer applies
+func (sm *StateMachine) removeScheduleIntent(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace, next time.Time) (scheduleOutcome, error) {
+	if _, ok := workspace.Annotations[AnnotationScheduleIntent]; !ok {
+		return scheduleOutcome{nextTransition: next}, nil
+	}
+	original := workspace.DeepCopy()
+	delete(workspace.Annotations, AnnotationScheduleIntent)
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+		return scheduleOutcome{}, fmt.Errorf("failed to remove the schedule intent: %w", err)
+	}
+	return scheduleOutcome{nextTransition: next, updated: true}, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// scheduleTestMonday is a Monday of the classroom schedule of the tests, between its start and stop
+var scheduleTestMonday = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+func newScheduleTestWorkspace() *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("classroom")
+	workspace.Spec.Schedule = &workspacev1alpha1.ScheduleSpec{StartCron: "0 8 * * 1-5", StopCron: "0 18 * * 1-5"}
+	return workspace
+}
+
+func newScheduleTestStateMachine(t *testing.T, workspace *workspacev1alpha1.Workspace) (*StateMachine, client.Client) {
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.intentResolver = NewDesiredStatusResolver(30 * time.Minute)
+	return sm, k8sClient
+}
+
+func scheduleIntentOf(t *testing.T, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.Workspace, *DesiredStatusIntentRecord) {
+	t.Helper()
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), stored))
+	record, ok := parseDesiredStatusIntent(stored.Annotations[AnnotationScheduleIntent])
+	if !ok {
+		return stored, nil
+	}
+	return stored, record
+}
+
+func TestScheduleStopsAndStartsTheWorkspace(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScheduleTestWorkspace()
+	sm, k8sClient := newScheduleTestStateMachine(t, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	// During class, the workspace runs until the stop
+	outcome, err := sm.reconcileSchedule(ctx, workspace, scheduleTestMonday)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+	stop := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	assert.True(t, stop.Equal(outcome.nextTransition))
+	assert.True(t, stop.Equal(workspace.Status.NextScheduledStop.Time))
+	assert.True(t, time.Date(2026, 3, 3, 8, 0, 0, 0, time.UTC).Equal(workspace.Status.NextScheduledStart.Time))
+	stored, intent := scheduleIntentOf(t, k8sClient, workspace)
+	require.NotNil(t, intent)
+	assert.Equal(t, DesiredStateRunning, intent.DesiredStatus)
+	assert.True(t, time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC).Equal(intent.SetAt.Time))
+	assert.True(t, stop.Equal(intent.ExpiresAt.Time))
+	assert.Equal(t, DesiredStateRunning, stored.Spec.DesiredStatus)
+	assert.Empty(t, recorder.Events, "the workspace was already running")
+
+	// Nothing changes until the stop
+	outcome, err = sm.reconcileSchedule(ctx, stored, scheduleTestMonday.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+
+	// The stop flips spec.desiredStatus
+	outcome, err = sm.reconcileSchedule(ctx, stored, stop.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+	stored, intent = scheduleIntentOf(t, k8sClient, workspace)
+	require.NotNil(t, intent)
+	assert.Equal(t, DesiredStateStopped, intent.DesiredStatus)
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal ScheduledStop")
+
+	// The next morning, the start flips it back
+	_, err = sm.reconcileSchedule(ctx, stored, time.Date(2026, 3, 3, 8, 0, 30, 0, time.UTC))
+	require.NoError(t, err)
+	stored, _ = scheduleIntentOf(t, k8sClient, workspace)
+	assert.Equal(t, DesiredStateRunning, stored.Spec.DesiredStatus)
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal ScheduledStart")
+}
+
+func TestManualChangeWinsUntilTheNextScheduledBoundary(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScheduleTestWorkspace()
+	sm, k8sClient := newScheduleTestStateMachine(t, workspace)
+	recorder := sm.recorder.(*record.FakeRecorder)
+	stop := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+
+	_, err := sm.reconcileSchedule(ctx, workspace, stop.Add(time.Minute))
+	require.NoError(t, err)
+	stored, _ := scheduleIntentOf(t, k8sClient, workspace)
+	require.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	<-recorder.Events
+
+	// A student restarts the workspace in the evening, after the cooldown has passed
+	stored.Spec.DesiredStatus = DesiredStateRunning
+	stored.Annotations[AnnotationDesiredStatusSetAt] = stop.Add(time.Hour).Format(time.RFC3339)
+	require.NoError(t, k8sClient.Update(ctx, stored))
+	evening := stop.Add(3 * time.Hour)
+	outcome, err := sm.reconcileSchedule(ctx, stored, evening)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.Equal(t, IntentActorUser, sm.intentResolver.resolveAt(stored, evening).Intent.Actor)
+	assert.Equal(t, DesiredStateRunning, sm.intentResolver.resolveAt(stored, evening).Intent.DesiredStatus)
+
+	// The next stop applies again
+	_, err = sm.reconcileSchedule(ctx, stored, time.Date(2026, 3, 3, 18, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	stored, _ = scheduleIntentOf(t, k8sClient, workspace)
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+	assert.Len(t, recorder.Events, 1, "the start of the morning found the workspace running")
+}
+
+func TestScheduleIntentIsRemovedWithTheSchedule(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScheduleTestWorkspace()
+	sm, k8sClient := newScheduleTestStateMachine(t, workspace)
+
+	_, err := sm.reconcileSchedule(ctx, workspace, scheduleTestMonday)
+	require.NoError(t, err)
+	stored, intent := scheduleIntentOf(t, k8sClient, workspace)
+	require.NotNil(t, intent)
+
+	stored.Spec.Schedule = nil
+	outcome, err := sm.reconcileSchedule(ctx, stored, scheduleTestMonday)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+	assert.Nil(t, stored.Status.NextScheduledStart)
+	assert.Nil(t, stored.Status.NextScheduledStop)
+	_, intent = scheduleIntentOf(t, k8sClient, workspace)
+	assert.Nil(t, intent)
+}
+
+func TestScheduleWithOnlyAStopReportsNoStart(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScheduleTestWorkspace()
+	workspace.Spec.Schedule = &workspacev1alpha1.ScheduleSpec{StopCron: "0 20 * * *", TimeZone: "America/New_York"}
+	sm, k8sClient := newScheduleTestStateMachine(t, workspace)
+
+	// 10am UTC is 5am in New York: the last stop was yesterday at 8pm
+	outcome, err := sm.reconcileSchedule(ctx, workspace, scheduleTestMonday)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+	assert.Nil(t, workspace.Status.NextScheduledStart)
+	assert.True(t, time.Date(2026, 3, 3, 1, 0, 0, 0, time.UTC).Equal(workspace.Status.NextScheduledStop.Time))
+	stored, intent := scheduleIntentOf(t, k8sClient, workspace)
+	require.NotNil(t, intent)
+	assert.True(t, time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC).Equal(intent.SetAt.Time))
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+}
+
+func TestInvalidScheduleIsIgnored(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScheduleTestWorkspace()
+	workspace.Spec.Schedule.TimeZone = "Mars/Olympus_Mons"
+	workspace.Status.NextScheduledStop = &metav1.Time{Time: scheduleTestMonday}
+	sm, k8sClient := newScheduleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileSchedule(ctx, workspace, scheduleTestMonday)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.Nil(t, workspace.Status.NextScheduledStop)
+	_, intent := scheduleIntentOf(t, k8sClient, workspace)
+	assert.Nil(t, intent)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// ResolveScheduling records the default node selector and tolerations of the admitted template revision in
+// Status.Scheduling for the deployment builder to merge. The status is updated in memory. A nil template, for
+// workspaces without one, clears the defaults.
+func (rm *ResourceManager) ResolveScheduling(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.Scheduling = nil
+		return nil
+	}
+	workspace.Status.Scheduling = TemplateSchedulingDefaults(template)
+	return nil
+}
+
+// TemplateSchedulingDefaults returns the default node selector and tolerations of the template, nil when it
+// sets neither
+func TemplateSchedulingDefaults(template *workspacev1alpha1.WorkspaceTemplate) *workspacev1alpha1.SchedulingDefaults {
+	if len(template.Spec.DefaultNodeSelector) == 0 && len(template.Spec.DefaultTolerations) == 0 {
+		return nil
+	}
+	return &workspacev1alpha1.SchedulingDefaults{
+		NodeSelector: maps.Clone(template.Spec.DefaultNodeSelector),
+		Tolerations:  slices.Clone(template.Spec.DefaultTolerations),
+	}
+}
+
+// applyScheduling places the pod with the node selector, affinity, tolerations and priority class of the
+// workspace, merged with the scheduling defaults of its template. The workspace wins for the node selector
+// keys both set, and its tolerations replace the template ones of the same key and effect.
+func applyScheduling(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	var defaults workspacev1alpha1.SchedulingDefaults
+	if workspace.Status.Scheduling != nil {
+		defaults = *workspace.Status.Scheduling
+	}
+
+	if len(workspace.Spec.NodeSelector) > 0 || len(defaults.NodeSelector) > 0 {
+		nodeSelector := maps.Clone(defaults.NodeSelector)
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		maps.Copy(nodeSelector, workspace.Spec.NodeSelector)
+		podSpec.NodeSelector = nodeSelector
+	}
+
+	if workspace.Spec.Affinity != nil {
+		podSpec.Affinity = workspace.Spec.Affinity
+	}
+
+	tolerations := slices.Clone(workspace.Spec.Tolerations)
+	for _, toleration := range defaults.Tolerations {
+		if !slices.ContainsFunc(workspace.Spec.Tolerations, func(own corev1.Toleration) bool {
+			return own.Key == toleration.Key && own.Effect == toleration.Effect
+		}) {
+			tolerations = append(tolerations, toleration)
+		}
+	}
+	if len(tolerations) > 0 {
+		podSpec.Tolerations = tolerations
+	}
+
+	podSpec.PriorityClassName = workspace.Spec.PriorityClassName
+}
@@ -0,0 +1,96 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newSchedulingTestDefaults returns template scheduling defaults placing pods on the standard node pool
+func newSchedulingTestDefaults() *workspacev1alpha1.SchedulingDefaults {
+	return &workspacev1alpha1.SchedulingDefaults{
+		NodeSelector: map[string]string{"node-pool": "standard", "zone": "eu-west-1a"},
+		Tolerations: []corev1.Toleration{
+			{Key: "notebooks", Operator: corev1.TolerationOpEqual, Value: "shared"},
+			{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+		},
+	}
+}
+
+func TestWorkspaceSchedulingWinsOverTheTemplateDefaults(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Status.Scheduling = newSchedulingTestDefaults()
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Equal(t, map[string]string{"node-pool": "notebooks", "zone": "eu-west-1a"}, podSpec.NodeSelector,
+		"the workspace node selector wins for the keys both set")
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "notebooks", Operator: corev1.TolerationOpExists},
+		{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	}, podSpec.Tolerations, "the workspace toleration replaces the template one of the same key and effect")
+
+	// The workspace spec is not changed by the rendering
+	assert.Equal(t, map[string]string{"node-pool": "notebooks"}, workspace.Spec.NodeSelector)
+	assert.Len(t, workspace.Spec.Tolerations, 1)
+}
+
+func TestWorkspacesWithoutSchedulingUseTheTemplateDefaults(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Empty(t, podSpec.NodeSelector)
+	assert.Empty(t, podSpec.Tolerations)
+
+	workspace.Status.Scheduling = newSchedulingTestDefaults()
+	podSpec = renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Equal(t, workspace.Status.Scheduling.NodeSelector, podSpec.NodeSelector)
+	assert.Equal(t, workspace.Status.Scheduling.Tolerations, podSpec.Tolerations)
+}
+
+func TestResolveSchedulingFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	defaults := newSchedulingTestDefaults()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "standard", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:         "standard",
+			DefaultNodeSelector: defaults.NodeSelector,
+			DefaultTolerations:  defaults.Tolerations,
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "standard"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveScheduling(ctx, workspace, template))
+	assert.Equal(t, defaults, workspace.Status.Scheduling)
+
+	// A deleted template leaves the resolved defaults in place
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.NotNil(t, workspace.Status.Scheduling)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveScheduling(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.Scheduling)
+}
+
+func TestWorkspacePodsRunAtThePriorityClassOfTheWorkspace(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	assert.Empty(t, renderHashTestDeployment(t, workspace).Spec.Template.Spec.PriorityClassName)
+
+	workspace.Spec.PriorityClassName = "notebooks-high"
+	assert.Equal(t, "notebooks-high", renderHashTestDeployment(t, workspace).Spec.Template.Spec.PriorityClassName)
+}
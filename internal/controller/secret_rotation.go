@@ -0,0 +1,67 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// envFromSecretRotationPending returns true if a Secret exposed through envFrom changed after the
+// workspace pod started: the container env still holds the previous values. Pods started before
+// the checksum was recorded are assumed current; they record it at their next restart.
+func envFromSecretRotationPending(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace) bool {
+	startedWith, ok := deployment.Spec.Template.Annotations[AnnotationEnvFromSecretsChecksum]
+	return ok && startedWith != workspace.Status.EnvFromSecretsChecksum
+}
+
+// secretRotationRequested returns true if the owner set a new value of the secret rotation annotation
+// since the workspace pod started. The pod template copies the workspace annotations, so it records
+// the last request it applied.
+func secretRotationRequested(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace) bool {
+	requested := workspace.Annotations[AnnotationSecretRotationRequested]
+	return requested != "" && requested != deployment.Spec.Template.Annotations[AnnotationSecretRotationRequested]
+}
+
+// ensureEnvFromSecretsRotated restarts the workspace pod whose envFrom Secrets were rotated, so that it
+// picks up the new values. The restart happens on request of the owner through the secret rotation
+// annotation, or during an administrator maintenance window; until then the SecretRotationPending
+// condition reports the stale values. The condition is updated in memory.
+func (rm *ResourceManager) ensureEnvFromSecretsRotated(
+	ctx context.Context,
+	deployment *appsv1.Deployment,
+	workspace *workspacev1alpha1.Workspace,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (*appsv1.Deployment, error) {
+	requested := secretRotationRequested(deployment, workspace)
+	pending := envFromSecretRotationPending(deployment, workspace)
+	if !requested && !pending {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending)
+		return deployment, nil
+	}
+
+	if requested || maintenanceWindowOpen(workspace, time.Now()) {
+		logf.FromContext(ctx).Info("Restarting workspace pod to apply envFrom Secrets",
+			"deployment", deployment.Name, "requested", requested, "secretsChanged", pending)
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending)
+		return rm.updateDeployment(ctx, deployment, workspace, accessStrategy)
+	}
+
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type:   ConditionTypeSecretRotationPending,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonEnvFromSecretChanged,
+		Message: fmt.Sprintf("A Secret exposed through envFrom changed; the workspace restarts with the new values "+
+			"at the next maintenance window, or when annotation %s is set to a new value", AnnotationSecretRotationRequested),
+	})
+	return deployment, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newRotationTestSecret(namespace, name, token string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string][]byte{"API_TOKEN": []byte(token)},
+	}
+}
+
+// createStartedDeployment creates the deployment of the workspace as rendered when its pod started
+// with the given envFrom Secrets checksum
+func createStartedDeployment(t *testing.T, sm *StateMachine, k8sClient client.Client, workspace *workspacev1alpha1.Workspace, checksum string) *appsv1.Deployment {
+	started := workspace.DeepCopy()
+	started.Status.EnvFromSecretsChecksum = checksum
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(context.Background(), started, nil)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Create(context.Background(), deployment))
+	return deployment
+}
+
+func TestEnsureEnvFromSourcesFingerprintsSecrets(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{
+		secretEnvFrom("credentials", ""),
+		secretEnvFrom("shared", "platform"),
+	}
+	secret := newRotationTestSecret("team-a", "credentials", "v1")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, secret,
+		newRotationTestSecret("platform", "shared", "v1"))
+
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	first := workspace.Status.EnvFromSecretsChecksum
+	require.Len(t, first, 16)
+
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.Equal(t, first, workspace.Status.EnvFromSecretsChecksum, "the checksum is stable")
+
+	secret.Data["API_TOKEN"] = []byte("v2")
+	require.NoError(t, k8sClient.Update(ctx, secret))
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.NotEqual(t, first, workspace.Status.EnvFromSecretsChecksum, "rotating a Secret changes the checksum")
+}
+
+func TestEnsureEnvFromSourcesChecksumIgnoresConfigMaps(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{configMapEnvFrom("settings", "", false)}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team-a"},
+	})
+
+	require.NoError(t, sm.resourceManager.EnsureEnvFromSources(ctx, workspace))
+	assert.Empty(t, workspace.Status.EnvFromSecretsChecksum)
+}
+
+// newRotatedSecretWorkspace returns an available workspace whose pod started before its Secret was rotated
+func newRotatedSecretWorkspace(t *testing.T, annotations map[string]string) (*workspacev1alpha1.Workspace, *StateMachine, client.Client) {
+	workspace := newRolloutTestWorkspace()
+	workspace.Annotations = annotations
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	createStartedDeployment(t, sm, k8sClient, workspace, "0000000000000001")
+	workspace.Status.EnvFromSecretsChecksum = "0000000000000002"
+	return workspace, sm, k8sClient
+}
+
+func TestEnsureDeploymentReportsRotatedSecretWithoutRestart(t *testing.T) {
+	workspace, sm, k8sClient := newRotatedSecretWorkspace(t, nil)
+
+	_, err := sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "0000000000000001", stored.Spec.Template.Annotations[AnnotationEnvFromSecretsChecksum], "the pod is not restarted")
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonEnvFromSecretChanged, condition.Reason)
+}
+
+func TestEnsureDeploymentAppliesRotatedSecretDuringMaintenanceWindow(t *testing.T) {
+	maintenance, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+		DesiredStatus: DesiredStateRunning,
+		ExpiresAt:     &metav1.Time{Time: time.Now().Add(time.Hour)},
+	})
+	require.NoError(t, err)
+	workspace, sm, k8sClient := newRotatedSecretWorkspace(t, map[string]string{AnnotationMaintenanceIntent: maintenance})
+
+	_, err = sm.resourceManager.EnsureDeploymentExists(context.Background(), workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "0000000000000002", stored.Spec.Template.Annotations[AnnotationEnvFromSecretsChecksum])
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeSecretRotationPending))
+}
+
+func TestEnsureDeploymentAppliesRequestedSecretRotation(t *testing.T) {
+	ctx := context.Background()
+	workspace, sm, k8sClient := newRotatedSecretWorkspace(t, nil)
+
+	workspace.Annotations = map[string]string{AnnotationSecretRotationRequested: "2025-06-01T10:00:00Z"}
+	_, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+
+	stored := getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "0000000000000002", stored.Spec.Template.Annotations[AnnotationEnvFromSecretsChecksum])
+	assert.Equal(t, "2025-06-01T10:00:00Z", stored.Spec.Template.Annotations[AnnotationSecretRotationRequested])
+	assert.False(t, secretRotationRequested(stored, workspace), "the request is applied once")
+
+	// A new request restarts the pod again, even when the Secrets did not change
+	workspace.Annotations[AnnotationSecretRotationRequested] = "2025-06-02T10:00:00Z"
+	_, err = sm.resourceManager.EnsureDeploymentExists(ctx, workspace, nil)
+	require.NoError(t, err)
+	stored = getRolloutTestDeployment(t, k8sClient)
+	assert.Equal(t, "2025-06-02T10:00:00Z", stored.Spec.Template.Annotations[AnnotationSecretRotationRequested])
+}
+
+func TestEnvFromSecretRotationPendingAssumesUnstampedPodsCurrent(t *testing.T) {
+	workspace := newRolloutTestWorkspace()
+	deployment := renderHashTestDeployment(t, workspace)
+	workspace.Status.EnvFromSecretsChecksum = "0000000000000002"
+	assert.False(t, envFromSecretRotationPending(deployment, workspace))
+
+	workspace.Status.EnvFromSecretsChecksum = ""
+	deployment.Spec.Template.Annotations[AnnotationEnvFromSecretsChecksum] = "0000000000000001"
+	assert.True(t, envFromSecretRotationPending(deployment, workspace), "removing all Secrets is a change too")
+}
@@ -0,0 +1,335 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// serverContainerName is the container the workspace server runs in
+const serverContainerName = "workspace"
+
+// serverAdapterPresets are the built-in server adapters, selectable by name
+var serverAdapterPresets = map[workspacev1alpha1.ServerAdapterPreset]workspacev1alpha1.ServerAdapterSpec{
+	// Jupyter Server reports its last activity in /api/status and shuts down on POST /api/shutdown
+	workspacev1alpha1.ServerAdapterPresetJupyterLab: {
+		Port:          ptr.To(int32(JupyterPort)),
+		BaseURLEnv:    BaseURLEnv,
+		ReadinessPath: "/api",
+		ActivityPath:  "/api/status",
+		ActivityField: "last_activity",
+		ShutdownPath:  "/api/shutdown",
+	},
+	// The classic Notebook server serves the same REST API as Jupyter Server
+	workspacev1alpha1.ServerAdapterPresetNotebookClassic: {
+		Port:          ptr.To(int32(JupyterPort)),
+		BaseURLEnv:    BaseURLEnv,
+		ReadinessPath: "/api",
+		ActivityPath:  "/api/status",
+		ActivityField: "last_activity",
+		ShutdownPath:  "/api/shutdown",
+	},
+	// code-server serves relative URLs under any prefix, reports its last heartbeat in milliseconds
+	// since the epoch on /healthz, and has no shutdown endpoint
+	workspacev1alpha1.ServerAdapterPresetCodeServer: {
+		Port:          ptr.To(int32(8080)),
+		ReadinessPath: "/healthz",
+		ActivityPath:  "/healthz",
+		ActivityField: "lastHeartbeat",
+	},
+}
+
+// ResolveServerAdapter returns the server adapter of the workspace with the fields of its preset
+// filled in, or nil when the workspace has none. The port and readiness path of the server type of
+// the workspace override those of its adapter; a workspace without adapter gets the preset of its
+// server type.
+func ResolveServerAdapter(workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.ServerAdapterSpec {
+	spec := workspace.Spec.ServerAdapter
+	serverType := workspace.Status.ServerType
+	if spec == nil && serverType != nil {
+		spec = &workspacev1alpha1.ServerAdapterSpec{Preset: serverType.Preset}
+	}
+	if spec == nil {
+		return nil
+	}
+	resolved := workspacev1alpha1.ServerAdapterSpec{}
+	if preset, ok := serverAdapterPresets[spec.Preset]; ok {
+		resolved = *preset.DeepCopy()
+	}
+	resolved.Preset = spec.Preset
+	if spec.Port != nil {
+		resolved.Port = ptr.To(*spec.Port)
+	}
+	if spec.BaseURLEnv != "" {
+		resolved.BaseURLEnv = spec.BaseURLEnv
+	}
+	if spec.ReadinessPath != "" {
+		resolved.ReadinessPath = spec.ReadinessPath
+	}
+	if spec.ActivityPath != "" {
+		resolved.ActivityPath = spec.ActivityPath
+	}
+	if spec.ActivityField != "" {
+		resolved.ActivityField = spec.ActivityField
+	}
+	if spec.ShutdownPath != "" {
+		resolved.ShutdownPath = spec.ShutdownPath
+	}
+	if spec.Token != nil {
+		resolved.Token = spec.Token.DeepCopy()
+	}
+	if serverType != nil && serverType.Port != nil {
+		resolved.Port = ptr.To(*serverType.Port)
+	}
+	if serverType != nil && serverType.ReadinessPath != "" {
+		resolved.ReadinessPath = serverType.ReadinessPath
+	}
+	return &resolved
+}
+
+// serverPort returns the port the workspace server listens on
+func serverPort(adapter *workspacev1alpha1.ServerAdapterSpec) int32 {
+	if adapter == nil || adapter.Port == nil {
+		return JupyterPort
+	}
+	return *adapter.Port
+}
+
+// serverPath returns the path under the base URL the server is served at
+func serverPath(baseURL string, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// serverBaseURL returns the base URL the container passes to the server, or "" when it is served from the root
+func serverBaseURL(container *corev1.Container, adapter *workspacev1alpha1.ServerAdapterSpec) string {
+	if adapter == nil || adapter.BaseURLEnv == "" {
+		return ""
+	}
+	for _, env := range container.Env {
+		if env.Name == adapter.BaseURLEnv {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+// applyServerAdapter sets up the primary container for the server adapter of the workspace: the base URL
+// under the name the server reads, the token delivery and the readiness probe. It applies after the
+// access strategy, which provides the base URL. A workspace without server adapter running the entrypoint
+// of its image runs Jupyter Server, and gets the readiness probe of the JupyterLab preset.
+func (db *DeploymentBuilder) applyServerAdapter(deployment *appsv1.Deployment, workspace *workspacev1alpha1.Workspace) {
+	podSpec := &deployment.Spec.Template.Spec
+	container := workspaceContainer(podSpec)
+	if container == nil {
+		return
+	}
+	adapter := ResolveServerAdapter(workspace)
+	if adapter == nil {
+		if workspace.Spec.ContainerConfig == nil || len(workspace.Spec.ContainerConfig.Command) == 0 {
+			preset := serverAdapterPresets[workspacev1alpha1.ServerAdapterPresetJupyterLab]
+			container.ReadinessProbe = serverReadinessProbe(container, &preset)
+		}
+		return
+	}
+	// The container shares its env and args with the workspace spec
+	container.Env = slices.Clone(container.Env)
+	container.Args = slices.Clone(container.Args)
+
+	if adapter.BaseURLEnv != "" && adapter.BaseURLEnv != BaseURLEnv {
+		for i := range container.Env {
+			if container.Env[i].Name == BaseURLEnv {
+				container.Env[i].Name = adapter.BaseURLEnv
+			}
+		}
+	}
+
+	if token := adapter.Token; token != nil {
+		if token.EnvName != "" || token.ArgTemplate != "" {
+			envName := token.EnvName
+			if envName == "" {
+				envName = ServerTokenEnv
+			}
+			container.Env = append(container.Env, corev1.EnvVar{
+				Name:      envName,
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: token.SecretKeyRef.DeepCopy()},
+			})
+			// The kubelet expands references to container variables in the arguments
+			if token.ArgTemplate != "" {
+				container.Args = append(container.Args,
+					strings.ReplaceAll(token.ArgTemplate, "{token}", fmt.Sprintf("$(%s)", envName)))
+			}
+		}
+		if token.FilePath != "" {
+			podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+				Name: ServerTokenVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: token.SecretKeyRef.Name,
+						Items:      []corev1.KeyToPath{{Key: token.SecretKeyRef.Key, Path: "token"}},
+						Optional:   token.SecretKeyRef.Optional,
+					},
+				},
+			})
+			container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+				Name:      ServerTokenVolumeName,
+				MountPath: token.FilePath,
+				SubPath:   "token",
+				ReadOnly:  true,
+			})
+		}
+	}
+
+	if adapter.ReadinessPath != "" {
+		container.ReadinessProbe = serverReadinessProbe(container, adapter)
+	}
+}
+
+// serverReadinessProbe returns the readiness probe of the server, under the base URL of the container. The
+// probe carries no token: the kubelet cannot read it from its Secret, and the readiness paths of the presets,
+// such as GET /api of Jupyter Server, answer without authentication.
+func serverReadinessProbe(container *corev1.Container, adapter *workspacev1alpha1.ServerAdapterSpec) *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: serverPath(serverBaseURL(container, adapter), adapter.ReadinessPath),
+				Port: intstr.FromInt32(serverPort(adapter)),
+			},
+		},
+	}
+}
+
+// resolveServerToken returns the server token of the workspace, or "" when the adapter delivers none
+func resolveServerToken(
+	ctx context.Context,
+	k8sClient client.Client,
+	workspace *workspacev1alpha1.Workspace,
+	adapter *workspacev1alpha1.ServerAdapterSpec) (string, error) {
+	if adapter == nil || adapter.Token == nil {
+		return "", nil
+	}
+	ref := adapter.Token.SecretKeyRef
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: workspace.Namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get server token secret %s: %w", ref.Name, err)
+	}
+	token, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("server token secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return string(token), nil
+}
+
+// serverAuthHeaders returns the headers authenticating a request with the server token
+func serverAuthHeaders(token string) []corev1.HTTPHeader {
+	if token == "" {
+		return nil
+	}
+	return []corev1.HTTPHeader{{Name: "Authorization", Value: "token " + token}}
+}
+
+// applyServerAdapterToIdleConfig returns the idle configuration checking the activity endpoint of the
+// server adapter, when the configuration does not set its own endpoint, and authenticating with the token
+func applyServerAdapterToIdleConfig(
+	idleConfig *workspacev1alpha1.IdleShutdownSpec,
+	adapter *workspacev1alpha1.ServerAdapterSpec,
+	pod *corev1.Pod,
+	token string) *workspacev1alpha1.IdleShutdownSpec {
+	if adapter == nil {
+		return idleConfig
+	}
+	resolved := idleConfig.DeepCopy()
+	if resolved.Detection.HTTPGet == nil && adapter.ActivityPath != "" {
+		baseURL := ""
+		if container := findContainer(pod, serverContainerName); container != nil {
+			baseURL = serverBaseURL(container, adapter)
+		}
+		resolved.Detection.HTTPGet = &corev1.HTTPGetAction{
+			Path: serverPath(baseURL, adapter.ActivityPath),
+			Port: intstr.FromInt32(serverPort(adapter)),
+		}
+		if resolved.Detection.LastActivityField == "" {
+			resolved.Detection.LastActivityField = adapter.ActivityField
+		}
+	}
+	if resolved.Detection.HTTPGet != nil {
+		resolved.Detection.HTTPGet.HTTPHeaders = append(resolved.Detection.HTTPGet.HTTPHeaders, serverAuthHeaders(token)...)
+	}
+	return resolved
+}
+
+// findContainer returns the container of the pod spec with the name, or nil if not found
+func findContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// requestServerShutdown asks the workspace server to shut down cleanly through the shutdown endpoint of
+// its adapter, before the compute is stopped. The request is best effort: the pod stops regardless.
+func (sm *StateMachine) requestServerShutdown(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	logger := logf.FromContext(ctx)
+	adapter := ResolveServerAdapter(workspace)
+	if adapter == nil || adapter.ShutdownPath == "" || sm.podExec == nil {
+		return
+	}
+	// Only once: the request precedes issuing the deployment deletion
+	deployment, err := sm.resourceManager.getDeployment(ctx, workspace)
+	if err != nil || sm.resourceManager.IsDeploymentMissingOrDeleting(deployment) {
+		return
+	}
+	pod, err := sm.findReadyWorkspacePod(ctx, workspace)
+	if err != nil || pod == nil {
+		return
+	}
+	token, err := resolveServerToken(ctx, sm.resourceManager.client, workspace, adapter)
+	if err != nil {
+		logger.Error(err, "Failed to resolve the server token, requesting shutdown without it")
+	}
+
+	baseURL := ""
+	if container := findContainer(pod, serverContainerName); container != nil {
+		baseURL = serverBaseURL(container, adapter)
+	}
+	url := fmt.Sprintf("http://localhost:%d%s", serverPort(adapter), serverPath(baseURL, adapter.ShutdownPath))
+	cmd, stdin := curlCommand([]string{"-s", "-o", "/dev/null", "-w", "%{http_code}", "-X", "POST"}, url, serverAuthHeaders(token))
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, ServerShutdownTimeout)
+	defer cancel()
+	output, err := sm.podExec.ExecInPod(shutdownCtx, pod, serverContainerName, cmd, stdin)
+	if err != nil {
+		logger.Error(err, "Failed to request server shutdown", "pod", pod.Name)
+		return
+	}
+	logger.Info("Requested server shutdown", "pod", pod.Name, "status", strings.TrimSpace(output))
+}
+
+// curlCommand returns the curl command requesting the url with the options, with the headers passed on
+// stdin so that their values such as tokens do not show in the exec request
+func curlCommand(options []string, url string, headers []corev1.HTTPHeader) ([]string, string) {
+	cmd := append([]string{"curl"}, options...)
+	if len(headers) == 0 {
+		return append(cmd, url), ""
+	}
+	var stdin strings.Builder
+	for _, header := range headers {
+		stdin.WriteString(fmt.Sprintf("%s: %s\n", header.Name, header.Value))
+	}
+	return append(cmd, "-H", "@-", url), stdin.String()
+}
@@ -0,0 +1,320 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newServerAdapterTestWorkspace returns a workspace using the preset, served under a base URL
+// as an access strategy provides it
+func newServerAdapterTestWorkspace(preset workspacev1alpha1.ServerAdapterPreset) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{Preset: preset}
+	workspace.Spec.Env = []corev1.EnvVar{{Name: BaseURLEnv, Value: "/workspaces/team-a/ws/"}}
+	return workspace
+}
+
+func renderServerAdapterTestDeployment(t *testing.T, workspace *workspacev1alpha1.Workspace) *appsv1.Deployment {
+	deployment, err := newHashTestDeploymentBuilder(t).BuildDeploymentWithAccessStrategy(context.Background(), workspace, nil)
+	require.NoError(t, err)
+	return deployment
+}
+
+func TestServerAdapterPresets(t *testing.T) {
+	tests := []struct {
+		preset            workspacev1alpha1.ServerAdapterPreset
+		port              int32
+		baseURLEnv        string
+		readinessPath     string
+		activityPath      string
+		activityResponse  string
+		shutdownPath      string
+		expectedActivity  time.Time
+		expectedActivityF string
+	}{
+		{
+			preset:            workspacev1alpha1.ServerAdapterPresetJupyterLab,
+			port:              8888,
+			baseURLEnv:        BaseURLEnv,
+			readinessPath:     "/workspaces/team-a/ws/api",
+			activityPath:      "/workspaces/team-a/ws/api/status",
+			activityResponse:  `{"started": "2026-10-14T08:00:00.000000Z", "last_activity": "2026-10-14T09:30:00.123456Z", "connections": 1, "kernels": 2}`,
+			shutdownPath:      "/api/shutdown",
+			expectedActivity:  time.Date(2026, 10, 14, 9, 30, 0, 123456000, time.UTC),
+			expectedActivityF: "last_activity",
+		},
+		{
+			preset:            workspacev1alpha1.ServerAdapterPresetNotebookClassic,
+			port:              8888,
+			baseURLEnv:        BaseURLEnv,
+			readinessPath:     "/workspaces/team-a/ws/api",
+			activityPath:      "/workspaces/team-a/ws/api/status",
+			activityResponse:  `{"started": "2026-10-14T08:00:00.000000Z", "last_activity": "2026-10-14T09:30:00.000000Z", "connections": 0, "kernels": 0}`,
+			shutdownPath:      "/api/shutdown",
+			expectedActivity:  time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC),
+			expectedActivityF: "last_activity",
+		},
+		{
+			preset:            workspacev1alpha1.ServerAdapterPresetCodeServer,
+			port:              8080,
+			readinessPath:     "/healthz",
+			activityPath:      "/healthz",
+			activityResponse:  `{"status": "alive", "lastHeartbeat": 1791970200000}`,
+			expectedActivity:  time.UnixMilli(1791970200000).UTC(),
+			expectedActivityF: "lastHeartbeat",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.preset), func(t *testing.T) {
+			workspace := newServerAdapterTestWorkspace(test.preset)
+			adapter := ResolveServerAdapter(workspace)
+			require.NotNil(t, adapter)
+			assert.Equal(t, test.port, serverPort(adapter))
+			assert.Equal(t, test.baseURLEnv, adapter.BaseURLEnv)
+			assert.Equal(t, test.shutdownPath, adapter.ShutdownPath)
+			assert.Equal(t, test.expectedActivityF, adapter.ActivityField)
+
+			// The workspace container listens and reports readiness where the server does
+			deployment := renderServerAdapterTestDeployment(t, workspace)
+			container := deployment.Spec.Template.Spec.Containers[0]
+			assert.Equal(t, test.port, container.Ports[0].ContainerPort)
+			require.NotNil(t, container.ReadinessProbe)
+			assert.Equal(t, test.readinessPath, container.ReadinessProbe.HTTPGet.Path)
+			assert.Equal(t, intstr.FromInt32(test.port), container.ReadinessProbe.HTTPGet.Port)
+
+			// The service keeps its port for the access resources and targets the server port
+			service, err := NewServiceBuilder(newHashTestDeploymentBuilder(t).scheme).BuildService(workspace)
+			require.NoError(t, err)
+			assert.Equal(t, int32(JupyterPort), service.Spec.Ports[0].Port)
+			assert.Equal(t, intstr.FromInt32(test.port), service.Spec.Ports[0].TargetPort)
+
+			// Idle detection checks the activity endpoint and reads its field
+			pod := &corev1.Pod{Spec: deployment.Spec.Template.Spec}
+			idleConfig := applyServerAdapterToIdleConfig(
+				&workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30}, adapter, pod, "")
+			require.NotNil(t, idleConfig.Detection.HTTPGet)
+			assert.Equal(t, test.activityPath, idleConfig.Detection.HTTPGet.Path)
+			assert.Equal(t, intstr.FromInt32(test.port), idleConfig.Detection.HTTPGet.Port)
+
+			idleResp, err := parseIdleResponse(test.activityResponse, idleConfig.Detection.LastActivityField)
+			require.NoError(t, err)
+			lastActivity, err := parseLastActivity(idleResp.LastActivity)
+			require.NoError(t, err)
+			assert.True(t, test.expectedActivity.Equal(lastActivity), "got %s", lastActivity)
+		})
+	}
+}
+
+func TestResolveServerAdapterOverridesPreset(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+		Preset:        workspacev1alpha1.ServerAdapterPresetJupyterLab,
+		Port:          ptr.To(int32(9000)),
+		BaseURLEnv:    "LAUNCHER_PREFIX",
+		ReadinessPath: "/status",
+	}
+
+	adapter := ResolveServerAdapter(workspace)
+	assert.Equal(t, int32(9000), serverPort(adapter))
+	assert.Equal(t, "LAUNCHER_PREFIX", adapter.BaseURLEnv)
+	assert.Equal(t, "/status", adapter.ReadinessPath)
+	assert.Equal(t, "/api/status", adapter.ActivityPath)
+	assert.Equal(t, "/api/shutdown", adapter.ShutdownPath)
+
+	// The preset is left untouched
+	assert.Equal(t, "/api", serverAdapterPresets[workspacev1alpha1.ServerAdapterPresetJupyterLab].ReadinessPath)
+
+	// The base URL from the access strategy is passed under the name the server reads
+	workspace.Spec.Env = []corev1.EnvVar{{Name: BaseURLEnv, Value: "/workspaces/team-a/ws/"}}
+	container := renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []corev1.EnvVar{{Name: "LAUNCHER_PREFIX", Value: "/workspaces/team-a/ws/"}}, container.Env)
+	assert.Equal(t, "/workspaces/team-a/ws/status", container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(t, BaseURLEnv, workspace.Spec.Env[0].Name)
+}
+
+func TestWorkspaceWithoutServerAdapterIsProbedAsJupyterServer(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	assert.Nil(t, ResolveServerAdapter(workspace))
+
+	container := renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	require.NotNil(t, container.ReadinessProbe)
+	assert.Equal(t, "/api", container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(t, intstr.FromInt32(JupyterPort), container.ReadinessProbe.HTTPGet.Port)
+	assert.Empty(t, container.ReadinessProbe.HTTPGet.HTTPHeaders)
+	assert.Equal(t, int32(JupyterPort), container.Ports[0].ContainerPort)
+
+	// The probe is served under the base URL of the access strategy
+	workspace.Spec.Env = []corev1.EnvVar{{Name: BaseURLEnv, Value: "/workspaces/team-a/ws/"}}
+	container = renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "/workspaces/team-a/ws/api", container.ReadinessProbe.HTTPGet.Path)
+
+	idleConfig := createTestIdleConfig()
+	assert.Same(t, idleConfig, applyServerAdapterToIdleConfig(idleConfig, nil, &corev1.Pod{}, ""))
+}
+
+func TestCommandOverrideRendersWithoutTheJupyterProbe(t *testing.T) {
+	// A workspace overriding the command of its template does not inherit the template server adapter
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{
+		Command: []string{"code-server"},
+		Args:    []string{"--bind-addr", "0.0.0.0:8888"},
+	}
+	workspace.Status.PostStartScript = "echo started"
+
+	container := renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"code-server"}, container.Command)
+	assert.Equal(t, []string{"--bind-addr", "0.0.0.0:8888"}, container.Args)
+	assert.Nil(t, container.ReadinessProbe)
+	assert.Nil(t, container.LivenessProbe)
+
+	// The post-start script runs whatever the entrypoint
+	require.NotNil(t, container.Lifecycle)
+	require.NotNil(t, container.Lifecycle.PostStart)
+	assert.Equal(t, []string{"sh", "-c", postStartHookScript, "post-start"}, container.Lifecycle.PostStart.Exec.Command)
+
+	// A server adapter matching the entrypoint gives it its own probe
+	workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{Preset: workspacev1alpha1.ServerAdapterPresetCodeServer}
+	container = renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	require.NotNil(t, container.ReadinessProbe)
+	assert.Equal(t, serverAdapterPresets[workspacev1alpha1.ServerAdapterPresetCodeServer].ReadinessPath,
+		container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(t, []string{"code-server"}, container.Command)
+}
+
+func TestServerAdapterDeliversToken(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Args: []string{"--no-browser"}}
+	workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+		Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab,
+		Token: &workspacev1alpha1.ServerTokenSpec{
+			SecretKeyRef: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "server-token"},
+				Key:                  "token",
+			},
+			ArgTemplate: "--IdentityProvider.token={token}",
+			FilePath:    "/etc/jupyter/token",
+		},
+	}
+
+	podSpec := renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec
+	container := podSpec.Containers[0]
+	require.Len(t, container.Env, 1)
+	assert.Equal(t, ServerTokenEnv, container.Env[0].Name)
+	assert.Equal(t, "server-token", container.Env[0].ValueFrom.SecretKeyRef.Name)
+	assert.Equal(t, []string{"--no-browser", "--IdentityProvider.token=$(WORKSPACE_SERVER_TOKEN)"}, container.Args)
+
+	require.NotEmpty(t, podSpec.Volumes)
+	volume := podSpec.Volumes[len(podSpec.Volumes)-1]
+	assert.Equal(t, ServerTokenVolumeName, volume.Name)
+	assert.Equal(t, "server-token", volume.Secret.SecretName)
+	mount := container.VolumeMounts[len(container.VolumeMounts)-1]
+	assert.Equal(t, "/etc/jupyter/token", mount.MountPath)
+	assert.Equal(t, "token", mount.SubPath)
+
+	// A named environment variable carries the token to the arguments
+	workspace.Spec.ServerAdapter.Token.EnvName = "LAUNCHER_TOKEN"
+	container = renderServerAdapterTestDeployment(t, workspace).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "LAUNCHER_TOKEN", container.Env[0].Name)
+	assert.Contains(t, container.Args, "--IdentityProvider.token=$(LAUNCHER_TOKEN)")
+}
+
+func TestParseIdleResponse(t *testing.T) {
+	idleResp, err := parseIdleResponse(`{"lastActiveTimestamp": "2026-10-14T09:30:00z"}`, DefaultLastActivityField)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-14T09:30:00z", idleResp.LastActivity)
+	assert.Nil(t, idleResp.Kernels)
+
+	// Jupyter Server reports its kernels beside its last activity
+	idleResp, err = parseIdleResponse(`{"last_activity": "2026-10-14T09:30:00Z", "kernels": 3, "connections": 1}`, "last_activity")
+	require.NoError(t, err)
+	require.NotNil(t, idleResp.Kernels)
+	assert.Equal(t, int32(3), *idleResp.Kernels)
+
+	// A server that never reported a heartbeat has no last activity
+	idleResp, err = parseIdleResponse(`{"status": "expired", "lastHeartbeat": 0}`, "lastHeartbeat")
+	require.NoError(t, err)
+	assert.Empty(t, idleResp.LastActivity)
+
+	idleResp, err = parseIdleResponse(`{"other": "value"}`, DefaultLastActivityField)
+	require.NoError(t, err)
+	assert.Empty(t, idleResp.LastActivity)
+
+	_, err = parseIdleResponse(`{"lastActiveTimestamp": true}`, DefaultLastActivityField)
+	assert.Error(t, err)
+}
+
+func TestHTTPGetDetectorPassesHeadersOnStdin(t *testing.T) {
+	mockExecUtil := &MockPodExecUtil{}
+	detector := createDetectorWithMock(mockExecUtil)
+	ctx := context.Background()
+	pod := createTestPod()
+	idleConfig := createTestIdleConfig()
+	idleConfig.Detection.HTTPGet.HTTPHeaders = serverAuthHeaders("secret-token")
+	idleConfig.Detection.LastActivityField = "last_activity"
+
+	curlOutput := fmt.Sprintf(`{"last_activity": "%s"}
+HTTP Status: 200`, time.Now().Add(-5*time.Minute).Format(time.RFC3339))
+	mockExecUtil.On("ExecInPod", ctx, pod, "workspace",
+		[]string{"curl", "-s", "-w", "\\nHTTP Status: %{http_code}\\n", "-H", "@-", "HTTP://localhost:8888/api/idle"},
+		"Authorization: token secret-token\n").Return(curlOutput, nil)
+
+	result, err := detector.CheckIdle(ctx, testWorkspaceName, pod, idleConfig)
+	require.NoError(t, err)
+	assert.False(t, result.IsIdle)
+	mockExecUtil.AssertExpectations(t)
+}
+
+func TestRequestServerShutdown(t *testing.T) {
+	ctx := context.Background()
+	workspace := newServerAdapterTestWorkspace(workspacev1alpha1.ServerAdapterPresetJupyterLab)
+	workspace.Spec.ServerAdapter.Token = &workspacev1alpha1.ServerTokenSpec{
+		SecretKeyRef: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "server-token"},
+			Key:                  "token",
+		},
+		EnvName: "JUPYTER_TOKEN",
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "server-token", Namespace: "team-a"},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deploymentNameFor(workspace), Namespace: "team-a"}}
+	pod := newPendingWorkspacePod([]corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}, nil)
+	pod.Status.Phase = corev1.PodRunning
+	pod.Spec.Containers = []corev1.Container{{Name: "workspace", Env: workspace.Spec.Env}}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, secret, deployment, pod)
+	mockExecUtil := &MockPodExecUtil{}
+	sm.podExec = mockExecUtil
+
+	mockExecUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace",
+		[]string{"curl", "-s", "-o", "/dev/null", "-w", "%{http_code}", "-X", "POST",
+			"-H", "@-", "http://localhost:8888/workspaces/team-a/ws/api/shutdown"},
+		"Authorization: token secret-token\n").Return("200", nil).Once()
+
+	sm.requestServerShutdown(ctx, workspace)
+	mockExecUtil.AssertExpectations(t)
+
+	// Presets without a shutdown endpoint stop without a request
+	workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{Preset: workspacev1alpha1.ServerAdapterPresetCodeServer}
+	sm.requestServerShutdown(ctx, workspace)
+	mockExecUtil.AssertNumberOfCalls(t, "ExecInPod", 1)
+}
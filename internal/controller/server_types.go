@@ -0,0 +1,54 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// ResolveServerType records in Status.ServerType the server type of the admitted template revision that the
+// workspace selects in spec.serverType, or the first one when it selects none. The deployment builder renders
+// its port, readiness probe and args. The status is updated in memory. When the server type cannot be found,
+// the previously resolved server type is kept; a nil template, for workspaces without one, clears it.
+func (rm *ResourceManager) ResolveServerType(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.ServerType = nil
+		return nil
+	}
+	if len(template.Spec.ServerTypes) == 0 {
+		workspace.Status.ServerType = nil
+		return nil
+	}
+
+	if serverType := TemplateServerType(template, workspace); serverType != nil {
+		workspace.Status.ServerType = serverType
+		return nil
+	}
+	logf.FromContext(ctx).Info("Template does not define the server type of the workspace, keeping the resolved one",
+		"serverType", workspace.Spec.ServerType)
+	return nil
+}
+
+// TemplateServerType returns a copy of the server type of the template the workspace selects in spec.serverType,
+// or of the first one when it selects none, and nil when the template does not define it
+func TemplateServerType(template *workspacev1alpha1.WorkspaceTemplate, workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.ServerType {
+	if len(template.Spec.ServerTypes) == 0 {
+		return nil
+	}
+	name := workspace.Spec.ServerType
+	if name == "" {
+		name = template.Spec.ServerTypes[0].Name
+	}
+	for i := range template.Spec.ServerTypes {
+		if template.Spec.ServerTypes[i].Name == name {
+			return template.Spec.ServerTypes[i].DeepCopy()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newServerTypesTestStateMachine returns a state machine for a workspace of a template offering JupyterLab
+// and VS Code
+func newServerTypesTestStateMachine(t *testing.T) (*StateMachine, *workspacev1alpha1.Workspace, *workspacev1alpha1.WorkspaceTemplate) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "servers", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "Servers",
+			ServerTypes: []workspacev1alpha1.ServerType{
+				{Name: "lab", Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab},
+				{
+					Name:          "vscode",
+					Image:         "codercom/code-server:4.96.2",
+					Preset:        workspacev1alpha1.ServerAdapterPresetCodeServer,
+					Port:          ptr.To[int32](8443),
+					ReadinessPath: "/healthz",
+					Args:          []string{"--auth", "none"},
+				},
+			},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "servers"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	return sm, workspace, template
+}
+
+func TestResolveServerTypeDefaultsToTheFirstServerType(t *testing.T) {
+	sm, workspace, template := newServerTypesTestStateMachine(t)
+
+	require.NoError(t, sm.resourceManager.ResolveServerType(context.Background(), workspace, template))
+	require.NotNil(t, workspace.Status.ServerType)
+	assert.Equal(t, "lab", workspace.Status.ServerType.Name)
+
+	workspace.Spec.ServerType = "rstudio"
+	require.NoError(t, sm.resourceManager.ResolveServerType(context.Background(), workspace, template))
+	assert.Equal(t, "lab", workspace.Status.ServerType.Name, "an unknown server type keeps the resolved one")
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveServerType(context.Background(), workspace, nil))
+	assert.Nil(t, workspace.Status.ServerType)
+}
+
+func TestDeploymentRendersTheSelectedServerType(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace, template := newServerTypesTestStateMachine(t)
+	workspace.Spec.ServerType = "vscode"
+	workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Args: []string{"--bind-addr", "0.0.0.0:8443"}}
+	require.NoError(t, sm.resourceManager.ResolveServerType(ctx, workspace, template))
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	container := deployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"--bind-addr", "0.0.0.0:8443", "--auth", "none"}, container.Args)
+	assert.Equal(t, []string{"--bind-addr", "0.0.0.0:8443"}, workspace.Spec.ContainerConfig.Args)
+	assert.Equal(t, int32(8443), container.Ports[0].ContainerPort)
+	require.NotNil(t, container.ReadinessProbe)
+	assert.Equal(t, "/healthz", container.ReadinessProbe.HTTPGet.Path)
+	assert.Equal(t, intstr.FromInt32(8443), container.ReadinessProbe.HTTPGet.Port)
+
+	adapter := ResolveServerAdapter(workspace)
+	require.NotNil(t, adapter)
+	assert.Equal(t, "lastHeartbeat", adapter.ActivityField, "the preset of the server type applies")
+}
@@ -38,6 +38,8 @@ func (sb *ServiceBuilder) BuildService(workspace *workspacev1alpha1.Workspace) (
 		Spec:       sb.buildServiceSpec(workspace),
 	}
 
+	applyChildMetadata(service, childMetadataOf(workspace).Service)
+
 	// Set owner reference for garbage collection
 	if err := controllerutil.SetControllerReference(workspace, service, sb.scheme); err != nil {
 		return nil, fmt.Errorf("failed to set controller reference: %w", err)
@@ -49,9 +51,10 @@ func (sb *ServiceBuilder) BuildService(workspace *workspacev1alpha1.Workspace) (
 // buildObjectMeta creates the metadata for the Service
 func (sb *ServiceBuilder) buildObjectMeta(workspace *workspacev1alpha1.Workspace) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
-		Name:      GenerateServiceName(workspace.Name),
-		Namespace: workspace.Namespace,
-		Labels:    GenerateLabels(workspace.Name),
+		Name:        serviceNameFor(workspace),
+		Namespace:   workspace.Namespace,
+		Labels:      GenerateLabels(workspace.Name),
+		Annotations: GenerateAnnotations(),
 	}
 }
 
@@ -64,7 +67,7 @@ func (sb *ServiceBuilder) buildServiceSpec(workspace *workspacev1alpha1.Workspac
 			{
 				Name:       "http",
 				Port:       JupyterPort,
-				TargetPort: intstr.FromInt(JupyterPort),
+				TargetPort: intstr.FromInt32(serverPort(ResolveServerAdapter(workspace))),
 				Protocol:   corev1.ProtocolTCP,
 			},
 		},
@@ -80,7 +83,8 @@ func (sb *ServiceBuilder) NeedsUpdate(ctx context.Context, existingService *core
 	}
 
 	// Compare service specs using semantic equality
-	return !equality.Semantic.DeepEqual(existingService.Spec, desiredService.Spec), nil
+	return !equality.Semantic.DeepEqual(existingService.Spec, desiredService.Spec) ||
+		!childMetadataInSync(existingService, desiredService, childMetadataOf(workspace).Service), nil
 }
 
 // UpdateServiceSpec updates the existing service with the desired spec
@@ -93,6 +97,7 @@ func (sb *ServiceBuilder) UpdateServiceSpec(ctx context.Context, existingService
 
 	// Update the service spec while preserving metadata like resourceVersion
 	existingService.Spec = desiredService.Spec
+	syncChildMetadata(existingService, desiredService, childMetadataOf(workspace).Service)
 
 	return nil
 }
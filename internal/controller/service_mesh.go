@@ -0,0 +1,103 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// IstioQuitCommand asks the istio-proxy to exit, run in its own container
+var IstioQuitCommand = []string{"pilot-agent", "request", "POST", "quitquitquit"}
+
+// isMeshedPod returns whether a service mesh proxy was injected in the pod
+func isMeshedPod(pod *corev1.Pod) bool {
+	for _, containers := range [][]corev1.Container{pod.Spec.Containers, pod.Spec.InitContainers} {
+		for _, container := range containers {
+			if container.Name == IstioProxyContainerName || container.Name == LinkerdProxyContainerName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// applyServiceMeshToIdleConfig returns the idle configuration calling the endpoint through the workspace
+// Service rather than on localhost in meshed pods, so that the check goes through the proxy with the mTLS
+// settings of the mesh. Endpoints on a port the Service does not expose keep being called on localhost.
+func applyServiceMeshToIdleConfig(
+	idleConfig *workspacev1alpha1.IdleShutdownSpec,
+	workspace *workspacev1alpha1.Workspace,
+	pod *corev1.Pod) *workspacev1alpha1.IdleShutdownSpec {
+	httpGet := idleConfig.Detection.HTTPGet
+	if httpGet == nil || httpGet.Host != "" || !isMeshedPod(pod) {
+		return idleConfig
+	}
+	if httpGet.Port.IntValue() != int(serverPort(ResolveServerAdapter(workspace))) {
+		return idleConfig
+	}
+	resolved := idleConfig.DeepCopy()
+	resolved.Detection.HTTPGet.Host = fmt.Sprintf("%s.%s.svc", serviceNameFor(workspace), workspace.Namespace)
+	resolved.Detection.HTTPGet.Port = intstr.FromInt32(JupyterPort)
+	return resolved
+}
+
+// quitMeshSidecars asks the istio-proxy of the terminating workspace pods whose workspace container exited
+// to quit, so that the pods do not hang in Terminating while the proxy waits for its drain. Linkerd proxies
+// run as native sidecars, which the kubelet stops after the workspace container. Failures are logged: the
+// pods terminate at the end of their grace period regardless.
+func (sm *StateMachine) quitMeshSidecars(ctx context.Context, workspace *workspacev1alpha1.Workspace) {
+	if sm.podExec == nil {
+		return
+	}
+	logger := logf.FromContext(ctx)
+	podList := &corev1.PodList{}
+	if err := sm.resourceManager.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace),
+		client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		logger.Error(err, "Failed to list the terminating workspace pods")
+		return
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp == nil ||
+			!containerTerminated(pod, workspaceContainerName) || !containerRunning(pod, IstioProxyContainerName) {
+			continue
+		}
+		if _, err := sm.podExec.ExecInPod(ctx, pod, IstioProxyContainerName, IstioQuitCommand, ""); err != nil {
+			logger.Error(err, "Failed to ask the istio-proxy to quit", "pod", pod.Name)
+			continue
+		}
+		logger.Info("Asked the istio-proxy of the stopped workspace container to quit", "pod", pod.Name)
+	}
+}
+
+// containerTerminated returns whether the container of the pod exited
+func containerTerminated(pod *corev1.Pod, name string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == name {
+			return status.State.Terminated != nil
+		}
+	}
+	return false
+}
+
+// containerRunning returns whether the container of the pod runs
+func containerRunning(pod *corev1.Pod, name string) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == name {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newMeshedWorkspacePod(proxyName string) *corev1.Pod {
+	pod := createTestPod()
+	pod.Spec.Containers = []corev1.Container{{Name: workspaceContainerName}, {Name: proxyName}}
+	return pod
+}
+
+func TestApplyServiceMeshToIdleConfigRoutesMeshedPodsThroughService(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	idleConfig := createTestIdleConfig()
+
+	resolved := applyServiceMeshToIdleConfig(idleConfig, workspace, newMeshedWorkspacePod(IstioProxyContainerName))
+
+	assert.Equal(t, serviceNameFor(workspace)+".team-a.svc", resolved.Detection.HTTPGet.Host)
+	assert.Equal(t, intstr.FromInt32(JupyterPort), resolved.Detection.HTTPGet.Port)
+	assert.Empty(t, idleConfig.Detection.HTTPGet.Host, "the resolved idle configuration must be a copy")
+}
+
+func TestApplyServiceMeshToIdleConfigKeepsLocalhostOutsideTheMesh(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	idleConfig := createTestIdleConfig()
+
+	assert.Same(t, idleConfig, applyServiceMeshToIdleConfig(idleConfig, workspace, createTestPod()))
+}
+
+func TestApplyServiceMeshToIdleConfigKeepsLocalhostForPortsOffTheService(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	idleConfig := createTestIdleConfig()
+	idleConfig.Detection.HTTPGet.Port = intstr.FromInt(9999)
+
+	assert.Same(t, idleConfig, applyServiceMeshToIdleConfig(idleConfig, workspace,
+		newMeshedWorkspacePod(LinkerdProxyContainerName)))
+}
+
+func TestHTTPGetDetectorCallsTheConfiguredHost(t *testing.T) {
+	mockExecUtil := &MockPodExecUtil{}
+	detector := createDetectorWithMock(mockExecUtil)
+	ctx := context.Background()
+	pod := createTestPod()
+	idleConfig := createTestIdleConfig()
+	idleConfig.Detection.HTTPGet.Host = "ws-service.team-a.svc"
+
+	mockExecUtil.On("ExecInPod", ctx, pod, workspaceContainerName,
+		mock.MatchedBy(func(cmd []string) bool {
+			return slices.Contains(cmd, "HTTP://ws-service.team-a.svc:8888/api/idle")
+		}), "").
+		Return(`{"lastActiveTimestamp": "`+time.Now().Format(time.RFC3339)+`"}
+HTTP Status: 200`, nil)
+
+	_, err := detector.CheckIdle(ctx, testWorkspaceName, pod, idleConfig)
+
+	require.NoError(t, err)
+	mockExecUtil.AssertExpectations(t)
+}
+
+func newTerminatingMeshedPod(workspaceState, proxyState corev1.ContainerState) *corev1.Pod {
+	pod := newPendingWorkspacePod(nil, []corev1.ContainerStatus{
+		{Name: workspaceContainerName, State: workspaceState},
+		{Name: IstioProxyContainerName, State: proxyState},
+	})
+	pod.Spec.Containers = []corev1.Container{{Name: workspaceContainerName}, {Name: IstioProxyContainerName}}
+	pod.Finalizers = []string{"test.jupyter.org/hold"}
+	now := metav1.Now()
+	pod.DeletionTimestamp = &now
+	return pod
+}
+
+func TestQuitMeshSidecarsAsksTheProxyOfStoppedPodsToQuit(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newTerminatingMeshedPod(
+		corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+		corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	mockExecUtil := &MockPodExecUtil{}
+	mockExecUtil.On("ExecInPod", mock.Anything, mock.Anything, IstioProxyContainerName, IstioQuitCommand, "").
+		Return("", nil)
+	sm.podExec = mockExecUtil
+
+	sm.quitMeshSidecars(ctx, workspace)
+
+	mockExecUtil.AssertNumberOfCalls(t, "ExecInPod", 1)
+}
+
+func TestQuitMeshSidecarsWaitsForTheWorkspaceContainer(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	pod := newTerminatingMeshedPod(
+		corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	mockExecUtil := &MockPodExecUtil{}
+	sm.podExec = mockExecUtil
+
+	sm.quitMeshSidecars(ctx, workspace)
+
+	mockExecUtil.AssertNotCalled(t, "ExecInPod", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// SharedVolumeRequeueDelay is how often a workspace waiting for the PVC of a shared volume is reconciled
+// again: the controller does not watch PVCs it does not own
+const SharedVolumeRequeueDelay = 10 * time.Second
+
+// ResolveSharedVolumes records the shared volumes of the admitted template revision in Status.SharedVolumes
+// for the deployment builder to mount. The status is updated in memory. A nil template, for workspaces
+// without one, clears the volumes.
+func (rm *ResourceManager) ResolveSharedVolumes(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.SharedVolumes = nil
+		return nil
+	}
+	workspace.Status.SharedVolumes = slices.Clone(template.Spec.SharedVolumes)
+	return nil
+}
+
+// reconcileSharedVolumes reports in the SharedVolumeMissing condition a shared volume whose PVC does not exist
+// in the workspace namespace, with a warning event when it goes missing. It returns the condition blocking the
+// start of a workspace that is not available yet; a running pod keeps the volumes it mounted. The status is
+// updated in memory.
+func (sm *StateMachine) reconcileSharedVolumes(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*metav1.Condition, error) {
+	var missing []string
+	for _, volume := range workspace.Status.SharedVolumes {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := sm.resourceManager.reader().Get(ctx,
+			client.ObjectKey{Name: volume.PersistentVolumeClaimName, Namespace: workspace.Namespace}, pvc)
+		if apierrors.IsNotFound(err) {
+			missing = append(missing, fmt.Sprintf("PVC %s of volume %s", volume.PersistentVolumeClaimName, volume.Name))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PVC %s of shared volume %s: %w",
+				volume.PersistentVolumeClaimName, volume.Name, err)
+		}
+	}
+	if len(missing) == 0 {
+		clearSharedVolumeMissingCondition(workspace)
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("Shared volumes of the template are missing in namespace %s: %s",
+		workspace.Namespace, strings.Join(missing, ", "))
+	if previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeSharedVolumeMissing); previous == nil ||
+		previous.Message != message {
+		logf.FromContext(ctx).Info("Shared volume of the template is missing", "missing", missing)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonSharedVolumeMissing, message)
+	}
+	condition := NewCondition(ConditionTypeSharedVolumeMissing, metav1.ConditionTrue, ReasonSharedVolumeMissing, message)
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, condition)
+	if sm.statusManager.IsWorkspaceAvailable(workspace) {
+		return nil, nil
+	}
+	return &condition, nil
+}
+
+// clearSharedVolumeMissingCondition removes the SharedVolumeMissing condition in memory
+func clearSharedVolumeMissingCondition(workspace *workspacev1alpha1.Workspace) {
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeSharedVolumeMissing)
+}
+
+// SharedVolumeConflict returns why a volume of the workspace named name and mounted at mountPath would remove
+// or remount one of the shared volumes of its template, or "" when it would not: it must not take the name of
+// a shared volume, nor be mounted at or under its mount path
+func SharedVolumeConflict(sharedVolumes []workspacev1alpha1.SharedVolume, name, mountPath string) string {
+	for _, shared := range sharedVolumes {
+		if name == shared.Name {
+			return fmt.Sprintf("volume name '%s' is used by a shared volume of the template", name)
+		}
+		if mountPath != "" && isPathOrParent(path.Clean(shared.MountPath), path.Clean(mountPath)) {
+			return fmt.Sprintf("mount path '%s' would remount shared volume '%s' of the template at '%s'",
+				mountPath, shared.Name, shared.MountPath)
+		}
+	}
+	return ""
+}
+
+// applySharedVolumes mounts the shared volumes of the template in the workspace container
+func applySharedVolumes(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	container := workspaceContainer(podSpec)
+	if container == nil {
+		return
+	}
+	for _, volume := range workspace.Status.SharedVolumes {
+		readOnly := ptr.Deref(volume.ReadOnly, true)
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volume.Name,
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: volume.PersistentVolumeClaimName,
+				ReadOnly:  readOnly,
+			}},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: volume.MountPath,
+			ReadOnly:  readOnly,
+		})
+	}
+}
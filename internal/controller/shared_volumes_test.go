@@ -0,0 +1,116 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// newSharedVolumesTestStateMachine returns a state machine for a workspace of a template sharing the reference
+// PVC at /data/reference
+func newSharedVolumesTestStateMachine(t *testing.T) (*StateMachine, *workspacev1alpha1.Workspace) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "genomics", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "Genomics",
+			SharedVolumes: []workspacev1alpha1.SharedVolume{
+				{Name: "reference", PersistentVolumeClaimName: "reference-genomes", MountPath: "/data/reference"},
+			},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "genomics"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	return sm, workspace
+}
+
+func TestSharedVolumesOfTheTemplateAreMountedReadOnly(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newSharedVolumesTestStateMachine(t)
+	workspace.Spec.Volumes = []workspacev1alpha1.VolumeSpec{
+		{Name: "scratch", PersistentVolumeClaimName: "scratch", MountPath: "/data/reference/scratch"},
+	}
+	workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{
+		{Name: "reference", PersistentVolumeClaimName: "my-reference", MountPath: "/mine"},
+	}
+
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	require.Len(t, workspace.Status.SharedVolumes, 1)
+
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	podSpec := deployment.Spec.Template.Spec
+	var claims []string
+	for _, volume := range podSpec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	assert.Equal(t, []string{"workspace-ws-pvc", "reference-genomes"}, claims,
+		"workspace volumes remounting the shared volume are skipped")
+	assert.True(t, podSpec.Volumes[1].PersistentVolumeClaim.ReadOnly)
+	container := workspaceContainer(&podSpec)
+	require.NotNil(t, container)
+	assert.Contains(t, container.VolumeMounts,
+		corev1.VolumeMount{Name: "reference", MountPath: "/data/reference", ReadOnly: true})
+
+	workspace.Status.SharedVolumes[0].ReadOnly = ptr.To(false)
+	deployment, err = sm.resourceManager.deploymentBuilder.BuildDeploymentWithAccessStrategy(ctx, workspace, nil)
+	require.NoError(t, err)
+	assert.Contains(t, workspaceContainer(&deployment.Spec.Template.Spec).VolumeMounts,
+		corev1.VolumeMount{Name: "reference", MountPath: "/data/reference"})
+}
+
+func TestMissingSharedVolumeBlocksTheStart(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newSharedVolumesTestStateMachine(t)
+	events := sm.recorder.(*record.FakeRecorder).Events
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+
+	blocked, err := sm.reconcileSharedVolumes(ctx, workspace)
+	require.NoError(t, err)
+	require.NotNil(t, blocked)
+	assert.Equal(t, ReasonSharedVolumeMissing, blocked.Reason)
+	assert.Equal(t, "Shared volumes of the template are missing in namespace team-a: "+
+		"PVC reference-genomes of volume reference", blocked.Message)
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, corev1.EventTypeWarning+" "+ReasonSharedVolumeMissing)
+
+	_, err = sm.reconcileSharedVolumes(ctx, workspace)
+	require.NoError(t, err)
+	assert.Empty(t, events, "the event is recorded once")
+
+	require.NoError(t, sm.resourceManager.client.Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "reference-genomes", Namespace: "team-a"},
+	}))
+	blocked, err = sm.reconcileSharedVolumes(ctx, workspace)
+	require.NoError(t, err)
+	assert.Nil(t, blocked)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeSharedVolumeMissing))
+}
+
+func TestSharedVolumeConflict(t *testing.T) {
+	shared := []workspacev1alpha1.SharedVolume{{Name: "reference", MountPath: "/data/reference"}}
+
+	assert.Empty(t, SharedVolumeConflict(shared, "scratch", "/data"), "a parent directory keeps the nested mount")
+	assert.Empty(t, SharedVolumeConflict(shared, "scratch", "/data/references"))
+	assert.Contains(t, SharedVolumeConflict(shared, "reference", "/mine"), "volume name 'reference'")
+	assert.Contains(t, SharedVolumeConflict(shared, "scratch", "/data/reference/"), "would remount shared volume 'reference'")
+	assert.Contains(t, SharedVolumeConflict(shared, "scratch", "/data/reference/hg38"), "would remount")
+}
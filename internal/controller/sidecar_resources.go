@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// The pod builder, the template webhook and the inventory all account for the resources of the sidecars
+// through the functions below, so that the pod the controller creates totals what the webhook admitted
+// and what the inventory reports.
+
+// containerRequests returns the requests of the container as the scheduler counts them: a resource the
+// container only limits is requested at its limit
+func containerRequests(container corev1.Container) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for name, limit := range container.Resources.Limits {
+		requests[name] = limit.DeepCopy()
+	}
+	for name, request := range container.Resources.Requests {
+		requests[name] = request.DeepCopy()
+	}
+	return requests
+}
+
+// addResourceList adds the quantities of the list to the total
+func addResourceList(total, list corev1.ResourceList) {
+	for name, quantity := range list {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// sidecarResources returns the requests and limits the sidecars total
+func sidecarResources(sidecars []corev1.Container) corev1.ResourceRequirements {
+	total := corev1.ResourceRequirements{Requests: corev1.ResourceList{}, Limits: corev1.ResourceList{}}
+	for _, sidecar := range sidecars {
+		addResourceList(total.Requests, containerRequests(sidecar))
+		addResourceList(total.Limits, sidecar.Resources.Limits)
+	}
+	return total
+}
+
+// carveResourceList returns a copy of the list less the quantities of the sidecars, for the resources of the list
+func carveResourceList(list, sidecars corev1.ResourceList) corev1.ResourceList {
+	if list == nil {
+		return nil
+	}
+	carved := corev1.ResourceList{}
+	for name, quantity := range list {
+		remainder := quantity.DeepCopy()
+		if taken, ok := sidecars[name]; ok {
+			remainder.Sub(taken)
+		}
+		carved[name] = remainder
+	}
+	return carved
+}
+
+// PrimaryContainerResources returns the resources of the workspace container for the resources the workspace
+// requests. Under Carved accounting, the requests and limits of the sidecars are taken out of them; a resource
+// the workspace does not request cannot be carved, the sidecars request it on top. Under Additive accounting,
+// the workspace container has the resources of the workspace.
+func PrimaryContainerResources(
+	requested corev1.ResourceRequirements,
+	sidecars []corev1.Container,
+	accounting workspacev1alpha1.SidecarResourceAccounting) corev1.ResourceRequirements {
+	if accounting != workspacev1alpha1.SidecarResourceAccountingCarved || len(sidecars) == 0 {
+		return requested
+	}
+	taken := sidecarResources(sidecars)
+	primary := *requested.DeepCopy()
+	primary.Requests = carveResourceList(requested.Requests, taken.Requests)
+	primary.Limits = carveResourceList(requested.Limits, taken.Limits)
+	return primary
+}
+
+// PodResourceRequests returns the requests the workspace pod totals, its workspace container and sidecars,
+// for the resources the workspace requests under the accounting
+func PodResourceRequests(
+	requested corev1.ResourceRequirements,
+	sidecars []corev1.Container,
+	accounting workspacev1alpha1.SidecarResourceAccounting) corev1.ResourceList {
+	primary := PrimaryContainerResources(requested, sidecars, accounting)
+	total := containerRequests(corev1.Container{Resources: primary})
+	addResourceList(total, sidecarResources(sidecars).Requests)
+	return total
+}
+
+// CarvedResourceShortfalls describes, under Carved accounting, each request of the workspace container that
+// the sidecar requests take from and leave at zero or below, or below the minimum of the template, and each limit the
+// sidecar limits leave below its request, sorted by resource. There are none under Additive accounting.
+func CarvedResourceShortfalls(
+	requested corev1.ResourceRequirements,
+	sidecars []corev1.Container,
+	accounting workspacev1alpha1.SidecarResourceAccounting,
+	minimum corev1.ResourceList) []string {
+	if accounting != workspacev1alpha1.SidecarResourceAccountingCarved || len(sidecars) == 0 {
+		return nil
+	}
+	primary := PrimaryContainerResources(requested, sidecars, accounting)
+	taken := sidecarResources(sidecars).Requests
+
+	names := make([]string, 0, len(primary.Requests))
+	for name := range primary.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var shortfalls []string
+	for _, key := range names {
+		name := corev1.ResourceName(key)
+		if _, carvedOut := taken[name]; !carvedOut {
+			continue
+		}
+		remainder := primary.Requests[name]
+		if limit, limited := primary.Limits[name]; limited && limit.Cmp(remainder) < 0 {
+			requestedLimit := requested.Limits[name]
+			shortfalls = append(shortfalls, fmt.Sprintf(
+				"%s limit %s less the sidecar limits leaves %s to the workspace container, below its request %s",
+				name, requestedLimit.String(), limit.String(), remainder.String()))
+		}
+		least, hasMinimum := minimum[name]
+		if remainder.Sign() > 0 && (!hasMinimum || remainder.Cmp(least) >= 0) {
+			continue
+		}
+		requestedQuantity := requested.Requests[name]
+		takenQuantity := taken[name]
+		shortfall := fmt.Sprintf("%s request %s less %s for the sidecars leaves %s to the workspace container",
+			name, requestedQuantity.String(), takenQuantity.String(), remainder.String())
+		if hasMinimum {
+			shortfall += fmt.Sprintf(", below its minimum %s", least.String())
+		}
+		shortfalls = append(shortfalls, shortfall)
+	}
+	return shortfalls
+}
@@ -0,0 +1,77 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newCarvingTestSidecar returns a sidecar with the requests and limits
+func newCarvingTestSidecar(requests, limits corev1.ResourceList) corev1.Container {
+	return corev1.Container{
+		Name:      "oauth-proxy",
+		Image:     "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0",
+		Resources: corev1.ResourceRequirements{Requests: requests, Limits: limits},
+	}
+}
+
+func TestCarvedAccountingTakesTheSidecarsOutOfTheWorkspaceContainer(t *testing.T) {
+	requested := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+	}
+	sidecars := []corev1.Container{newCarvingTestSidecar(
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceEphemeralStorage: resource.MustParse("1Gi")},
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+	)}
+
+	assert.Equal(t, requested, PrimaryContainerResources(requested, sidecars, workspacev1alpha1.SidecarResourceAccountingAdditive))
+	assert.Equal(t, requested, PrimaryContainerResources(requested, sidecars, ""))
+
+	primary := PrimaryContainerResources(requested, sidecars, workspacev1alpha1.SidecarResourceAccountingCarved)
+	assert.Equal(t, "750m", primary.Requests.Cpu().String())
+	assert.Equal(t, "2Gi", primary.Requests.Memory().String())
+	assert.Equal(t, "1500m", primary.Limits.Cpu().String())
+	assert.NotContains(t, primary.Requests, corev1.ResourceEphemeralStorage, "resources the workspace does not request are not carved")
+	assert.Equal(t, "1", requested.Requests.Cpu().String(), "the requested resources are not modified")
+
+	total := PodResourceRequests(requested, sidecars, workspacev1alpha1.SidecarResourceAccountingCarved)
+	assert.Equal(t, "1", total.Cpu().String())
+	assert.Equal(t, "1Gi", total.StorageEphemeral().String())
+	total = PodResourceRequests(requested, sidecars, workspacev1alpha1.SidecarResourceAccountingAdditive)
+	assert.Equal(t, "1250m", total.Cpu().String())
+}
+
+func TestCarvedResourceShortfalls(t *testing.T) {
+	requested := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+	carved := workspacev1alpha1.SidecarResourceAccountingCarved
+
+	sidecars := []corev1.Container{newCarvingTestSidecar(
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("1Gi")}, nil)}
+	assert.Empty(t, CarvedResourceShortfalls(requested, sidecars, workspacev1alpha1.SidecarResourceAccountingAdditive, nil))
+	assert.Equal(t, []string{
+		"memory request 1Gi less 1Gi for the sidecars leaves 0 to the workspace container",
+	}, CarvedResourceShortfalls(requested, sidecars, carved, nil))
+
+	sidecars = []corev1.Container{newCarvingTestSidecar(
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")})}
+	shortfalls := CarvedResourceShortfalls(requested, sidecars, carved,
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")})
+	require.Len(t, shortfalls, 2)
+	assert.Equal(t, "cpu limit 1 less the sidecar limits leaves 500m to the workspace container, below its request 900m", shortfalls[0])
+	assert.Equal(t, "cpu request 1 less 100m for the sidecars leaves 900m to the workspace container, below its minimum 1", shortfalls[1])
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// HomeVolumeName is the name of the pod volume of the home directory of the workspace, which
+	// template sidecars mount to share it
+	HomeVolumeName = "workspace-storage"
+
+	// MaxTemplateSidecars is the number of sidecars a template may define
+	MaxTemplateSidecars = 10
+)
+
+// ResolveSidecars records the sidecars of the admitted template revision in Status.Sidecars for the deployment
+// builder to apply, with their resource accounting. Under the OnRestart sidecar update policy, the sidecars of
+// a workspace whose deployment exists are kept until it restarts. The status is updated in memory. A nil
+// template, for workspaces without one, clears the sidecars.
+func (rm *ResourceManager) ResolveSidecars(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.Sidecars = nil
+		workspace.Status.SidecarResourceAccounting = ""
+		return nil
+	}
+	if equality.Semantic.DeepEqual(workspace.Status.Sidecars, template.Spec.Sidecars) &&
+		workspace.Status.SidecarResourceAccounting == template.Spec.SidecarResourceAccounting {
+		return nil
+	}
+
+	if template.Spec.SidecarUpdatePolicy != workspacev1alpha1.SidecarUpdatePolicyRolling {
+		exists, err := rm.deploymentExists(ctx, workspace)
+		if err != nil {
+			return err
+		}
+		if exists {
+			logDecision(logf.FromContext(ctx), "DeferSidecarUpdate",
+				"reason", "the template sidecars apply when the workspace restarts")
+			return nil
+		}
+	}
+
+	workspace.Status.Sidecars = nil
+	workspace.Status.SidecarResourceAccounting = template.Spec.SidecarResourceAccounting
+	for i := range template.Spec.Sidecars {
+		workspace.Status.Sidecars = append(workspace.Status.Sidecars, *template.Spec.Sidecars[i].DeepCopy())
+	}
+	return nil
+}
+
+// deploymentExists returns true if the deployment of the workspace exists, i.e. the workspace is not stopped
+func (rm *ResourceManager) deploymentExists(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	_, err := rm.getDeployment(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get deployment: %w", err)
+	}
+	return true, nil
+}
+
+// applySidecars adds the template sidecars resolved in the status of the workspace to its pod. A sidecar
+// mounts the home directory when it lists a mount of HomeVolumeName and the workspace has storage; its
+// other mounts, and sidecars whose name is taken, are skipped, the webhook rejects them.
+func applySidecars(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	storageConfig := ResolveStorageConfig(workspace)
+	for _, sidecar := range workspace.Status.Sidecars {
+		if containerNameTaken(podSpec, sidecar.Name) {
+			continue
+		}
+		podSpec.Containers = append(podSpec.Containers, withHomeMountOnly(sidecar, storageConfig))
+	}
+}
+
+// withHomeMountOnly returns a copy of the template container keeping its mounts of the home directory
+// only, and none when the workspace has no storage. Their subPath is relative to the home directory,
+// which is the subPath of the workspace storage in its volume.
+func withHomeMountOnly(container corev1.Container, storageConfig *ResolvedStorageConfig) corev1.Container {
+	filtered := *container.DeepCopy()
+	filtered.VolumeMounts = nil
+	for _, mount := range container.VolumeMounts {
+		if mount.Name == HomeVolumeName && storageConfig != nil {
+			if storageConfig.SubPath != "" {
+				mount.SubPath = path.Join(storageConfig.SubPath, mount.SubPath)
+			}
+			filtered.VolumeMounts = append(filtered.VolumeMounts, mount)
+		}
+	}
+	return filtered
+}
+
+// containerNameTaken returns true if a container or an init container of the pod has the name
+func containerNameTaken(podSpec *corev1.PodSpec, name string) bool {
+	for _, containers := range [][]corev1.Container{podSpec.Containers, podSpec.InitContainers} {
+		for i := range containers {
+			if containers[i].Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TemplateSidecarConflict returns why the template sidecar cannot run beside the workspace container,
+// or "" when it can
+func TemplateSidecarConflict(sidecar corev1.Container) string {
+	return templateContainerConflict("sidecar", sidecar)
+}
+
+// templateContainerConflict returns why the template container, of the kind, cannot run in the workspace pod
+func templateContainerConflict(kind string, container corev1.Container) string {
+	if container.Name == workspaceContainerName {
+		return fmt.Sprintf("%s name '%s' is the name of the workspace container", kind, container.Name)
+	}
+	for _, mount := range container.VolumeMounts {
+		if mount.Name != HomeVolumeName {
+			return fmt.Sprintf("%s '%s' mounts volume '%s', %ss may only mount '%s'",
+				kind, container.Name, mount.Name, kind, HomeVolumeName)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newSidecarTestTemplate returns a template with an oauth proxy sidecar reading the home directory
+func newSidecarTestTemplate(policy workspacev1alpha1.SidecarUpdatePolicy, image string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxied", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:         "proxied",
+			SidecarUpdatePolicy: policy,
+			Sidecars: []corev1.Container{{
+				Name:         "oauth-proxy",
+				Image:        image,
+				VolumeMounts: []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/data", ReadOnly: true}},
+			}},
+		},
+	}
+}
+
+func TestPodsRunTheTemplateSidecarsBesideTheWorkspace(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Status.Sidecars = []corev1.Container{
+		{
+			Name:         "oauth-proxy",
+			Image:        "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0",
+			VolumeMounts: []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/data", ReadOnly: true}},
+		},
+		{
+			Name:         "exporter",
+			Image:        "prom/node-exporter:v1.8.0",
+			VolumeMounts: []corev1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+		},
+		{Name: workspaceContainerName, Image: "busybox"},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	proxy := findContainer(&corev1.Pod{Spec: podSpec}, "oauth-proxy")
+	require.NotNil(t, proxy)
+	assert.Equal(t, []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/data", ReadOnly: true}}, proxy.VolumeMounts)
+	exporter := findContainer(&corev1.Pod{Spec: podSpec}, "exporter")
+	require.NotNil(t, exporter)
+	assert.Empty(t, exporter.VolumeMounts, "sidecars mount no other volume than the home directory")
+	assert.Equal(t, "jupyter/scipy-notebook:2024-01-01", podSpec.Containers[0].Image,
+		"a sidecar named like the workspace container is skipped")
+	assert.Len(t, podSpec.Containers, 3)
+}
+
+func TestPodsWithoutStorageRunSidecarsWithoutTheHomeMount(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Spec.Storage = nil
+	workspace.Status.Sidecars = newSidecarTestTemplate("", "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0").Spec.Sidecars
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	proxy := findContainer(&corev1.Pod{Spec: podSpec}, "oauth-proxy")
+	require.NotNil(t, proxy)
+	assert.Empty(t, proxy.VolumeMounts)
+}
+
+func TestResolveSidecarsFromTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := newSidecarTestTemplate("", "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0")
+	template.Spec.SidecarResourceAccounting = workspacev1alpha1.SidecarResourceAccountingCarved
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "proxied"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveSidecars(ctx, workspace, template))
+	require.Len(t, workspace.Status.Sidecars, 1)
+	assert.Equal(t, "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0", workspace.Status.Sidecars[0].Image)
+	assert.Equal(t, workspacev1alpha1.SidecarResourceAccountingCarved, workspace.Status.SidecarResourceAccounting)
+
+	// A deleted template leaves the resolved sidecars in place
+	require.NoError(t, k8sClient.Delete(ctx, template))
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.Len(t, workspace.Status.Sidecars, 1)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveSidecars(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.Sidecars)
+	assert.Empty(t, workspace.Status.SidecarResourceAccounting)
+}
+
+func TestResolveSidecarsFollowsTheUpdatePolicy(t *testing.T) {
+	for name, tc := range map[string]struct {
+		policy        workspacev1alpha1.SidecarUpdatePolicy
+		expectedImage string
+	}{
+		"on restart": {policy: workspacev1alpha1.SidecarUpdatePolicyOnRestart, expectedImage: "oauth2-proxy:v7.5.0"},
+		"default":    {policy: "", expectedImage: "oauth2-proxy:v7.5.0"},
+		"rolling":    {policy: workspacev1alpha1.SidecarUpdatePolicyRolling, expectedImage: "oauth2-proxy:v7.6.0"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			template := newSidecarTestTemplate(tc.policy, "oauth2-proxy:v7.6.0")
+			workspace := newAdoptionTestWorkspace("ws")
+			workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "proxied"}
+			workspace.Status.Sidecars = newSidecarTestTemplate(tc.policy, "oauth2-proxy:v7.5.0").Spec.Sidecars
+			sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+			sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+			_, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+			require.NoError(t, err)
+
+			require.NoError(t, sm.resourceManager.ResolveSidecars(ctx, workspace, template))
+			require.Len(t, workspace.Status.Sidecars, 1)
+			assert.Equal(t, tc.expectedImage, workspace.Status.Sidecars[0].Image)
+		})
+	}
+}
+
+func TestTemplateSidecarConflict(t *testing.T) {
+	assert.Empty(t, TemplateSidecarConflict(newSidecarTestTemplate("", "proxy").Spec.Sidecars[0]))
+	assert.Contains(t, TemplateSidecarConflict(corev1.Container{Name: workspaceContainerName}),
+		"name of the workspace container")
+	assert.Contains(t, TemplateSidecarConflict(corev1.Container{
+		Name:         "exporter",
+		VolumeMounts: []corev1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+	}), "may only mount 'workspace-storage'")
+}
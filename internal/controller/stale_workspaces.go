@@ -0,0 +1,256 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// HistoryActionFlaggedStale records a workspace flagged stale by the stale workspace policy
+	HistoryActionFlaggedStale = "FlaggedStale"
+	// HistoryActionArchivalRequested records the archival request of a stale workspace
+	HistoryActionArchivalRequested = "ArchivalRequested"
+	// HistoryActionStaleCleared records a stale workspace used again or exempted by its owner
+	HistoryActionStaleCleared = "StaleCleared"
+)
+
+// StaleWorkspacePolicy configures when unused workspaces are flagged stale, then archived
+type StaleWorkspacePolicy struct {
+	// Threshold is how long a workspace may go unused before it is flagged stale; zero disables the policy
+	Threshold time.Duration
+
+	// GracePeriod is how long the owner of a stale workspace has to use or exempt it before its
+	// archival is requested
+	GracePeriod time.Duration
+}
+
+// Enabled returns true if the policy flags workspaces
+func (p StaleWorkspacePolicy) Enabled() bool {
+	return p.Threshold > 0
+}
+
+// staleOutcome is the result of evaluating the stale workspace policy
+type staleOutcome struct {
+	// nextTransition is when the evaluation may change; zero when it only changes on a workspace update
+	nextTransition time.Time
+
+	// updated is true when the workspace object was updated and must be reconciled again
+	updated bool
+}
+
+// staleWorkspacePolicyFor returns the operator policy, overridden by the annotations of the namespace
+func (sm *StateMachine) staleWorkspacePolicyFor(ctx context.Context, namespace string) StaleWorkspacePolicy {
+	policy := sm.stalePolicy
+
+	ns := &corev1.Namespace{}
+	if err := sm.resourceManager.client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if !apierrors.IsNotFound(err) {
+			logf.FromContext(ctx).Error(err, "Failed to get namespace, using the operator stale workspace policy")
+		}
+		return policy
+	}
+	if threshold, ok := parseDays(ns.Annotations[AnnotationStaleAfterDays]); ok {
+		policy.Threshold = threshold
+	}
+	if gracePeriod, ok := parseDays(ns.Annotations[AnnotationStaleGraceDays]); ok {
+		policy.GracePeriod = gracePeriod
+	}
+	return policy
+}
+
+// parseDays parses a non-negative number of days; malformed values are ignored
+func parseDays(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 0 {
+		return 0, false
+	}
+	return time.Duration(days) * 24 * time.Hour, true
+}
+
+// lastUsedTime returns the last time the workspace is known to have been in use. A running workspace
+// without idle detection reports no activity, and is considered in use as long as it runs.
+func lastUsedTime(workspace *workspacev1alpha1.Workspace, running bool, now time.Time) time.Time {
+	if running && (workspace.Spec.IdleShutdown == nil || !workspace.Spec.IdleShutdown.Enabled) {
+		return now
+	}
+	lastUsed := workspace.CreationTimestamp.Time
+	for _, t := range []*metav1.Time{workspace.Status.LastStartTime, workspace.Status.LastActivityTime} {
+		if t != nil && t.After(lastUsed) {
+			lastUsed = t.Time
+		}
+	}
+	return lastUsed
+}
+
+// advanceLastActivityTime records a newer activity in status. Moves shorter than
+// ActivityRecordGranularity are dropped so that activity reports do not cause a write each time.
+func advanceLastActivityTime(workspace *workspacev1alpha1.Workspace, lastActivity time.Time) bool {
+	if lastActivity.IsZero() {
+		return false
+	}
+	if current := workspace.Status.LastActivityTime; current != nil && lastActivity.Sub(current.Time) < ActivityRecordGranularity {
+		return false
+	}
+	workspace.Status.LastActivityTime = &metav1.Time{Time: lastActivity.Truncate(time.Second)}
+	return true
+}
+
+// appendHistory records an automatic action in status, keeping the latest MaxWorkspaceHistoryEntries
+func appendHistory(workspace *workspacev1alpha1.Workspace, action, message string, now time.Time) {
+	history := append(workspace.Status.History, workspacev1alpha1.WorkspaceHistoryEntry{
+		Time:    metav1.NewTime(now),
+		Action:  action,
+		Message: message,
+	})
+	if len(history) > MaxWorkspaceHistoryEntries {
+		history = history[len(history)-MaxWorkspaceHistoryEntries:]
+	}
+	workspace.Status.History = history
+}
+
+// setStaleCondition sets the stale condition, keeping its transition time while its status is unchanged
+func setStaleCondition(workspace *workspacev1alpha1.Workspace, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type:    ConditionTypeStale,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// reconcileStaleness applies the stale workspace policy. A workspace unused for longer than the
+// threshold is flagged stale and its owner is notified with an event. Once the grace period has
+// passed, the workspace is stopped and annotated for the archival tooling. Using the workspace
+// again, or annotating it as exempt, withdraws the archival request. Condition and history changes
+// are left in the workspace status, for the status update of the reconciliation to persist.
+func (sm *StateMachine) reconcileStaleness(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	desiredStatus string) (staleOutcome, error) {
+	logger := logf.FromContext(ctx)
+	now := time.Now()
+	policy := sm.staleWorkspacePolicyFor(ctx, workspace.Namespace)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStale)
+	flagged := condition != nil && condition.Status == metav1.ConditionTrue
+	archiveRequestedAt, archiveRequested := workspace.Annotations[AnnotationArchiveRequested]
+
+	var clearReason, clearMessage string
+	var lastUsed, staleAt time.Time
+	switch {
+	case !policy.Enabled():
+		clearReason, clearMessage = ReasonInUse, "The stale workspace policy does not apply to this workspace"
+	case workspace.Annotations[AnnotationStaleExempt] == "true":
+		clearReason, clearMessage = ReasonStaleExempt, fmt.Sprintf("Exempted by annotation %s=true", AnnotationStaleExempt)
+	default:
+		lastUsed = lastUsedTime(workspace, desiredStatus == DesiredStateRunning, now)
+		staleAt = lastUsed.Add(policy.Threshold)
+		if now.Before(staleAt) {
+			clearReason, clearMessage = ReasonInUse, "Workspace was used since it was flagged stale"
+		}
+	}
+
+	if clearReason != "" {
+		if archiveRequested {
+			logger.Info("Withdrawing archival request of workspace no longer stale", "reason", clearReason)
+			original := workspace.DeepCopy()
+			delete(workspace.Annotations, AnnotationArchiveRequested)
+			if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+				return staleOutcome{}, fmt.Errorf("failed to withdraw archival request: %w", err)
+			}
+			return staleOutcome{updated: true}, nil
+		}
+		if flagged {
+			setStaleCondition(workspace, metav1.ConditionFalse, clearReason, clearMessage)
+			appendHistory(workspace, HistoryActionStaleCleared, clearMessage, now)
+		}
+		return staleOutcome{nextTransition: staleAt}, nil
+	}
+
+	if !flagged {
+		archiveAt := now.Add(policy.GracePeriod)
+		message := fmt.Sprintf("Workspace not used since %s; its archival will be requested after %s unless it is used or an administrator annotates it %s=true",
+			lastUsed.UTC().Format(time.RFC3339), archiveAt.UTC().Format(time.RFC3339), AnnotationStaleExempt)
+		logger.Info("Flagging stale workspace", "lastUsed", lastUsed, "archiveAt", archiveAt)
+		setStaleCondition(workspace, metav1.ConditionTrue, ReasonInactive, message)
+		appendHistory(workspace, HistoryActionFlaggedStale, message, now)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ConditionTypeStale, message)
+		return staleOutcome{nextTransition: archiveAt}, nil
+	}
+
+	if archiveAt := condition.LastTransitionTime.Add(policy.GracePeriod); now.Before(archiveAt) {
+		return staleOutcome{nextTransition: archiveAt}, nil
+	}
+
+	if !archiveRequested {
+		return sm.requestArchival(ctx, workspace, desiredStatus, lastUsed, now)
+	}
+
+	if condition.Reason != ReasonArchivalRequested {
+		message := fmt.Sprintf("Archival requested at %s; workspace not used since %s",
+			archiveRequestedAt, lastUsed.UTC().Format(time.RFC3339))
+		setStaleCondition(workspace, metav1.ConditionTrue, ReasonArchivalRequested, message)
+		appendHistory(workspace, HistoryActionArchivalRequested, message, now)
+	}
+	return staleOutcome{}, nil
+}
+
+// requestArchival stops a stale workspace and annotates it for the archival tooling. The stop is
+// recorded as a culler intent, so that a maintenance window or a schedule keeping the workspace
+// running takes precedence.
+func (sm *StateMachine) requestArchival(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	desiredStatus string,
+	lastUsed time.Time,
+	now time.Time) (staleOutcome, error) {
+	logger := logf.FromContext(ctx)
+
+	original := workspace.DeepCopy()
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	if desiredStatus == DesiredStateRunning {
+		if !sm.intentResolver.CullerMayStop(workspace) {
+			logger.Info("Archival of stale workspace suppressed by a higher-precedence desired status intent")
+			return staleOutcome{nextTransition: now.Add(LongRequeueDelay)}, nil
+		}
+		cullerIntent, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+			DesiredStatus: DesiredStateStopped,
+			SetAt:         &metav1.Time{Time: now},
+		})
+		if err != nil {
+			return staleOutcome{}, err
+		}
+		workspace.Annotations[AnnotationCullerIntent] = cullerIntent
+		workspace.Spec.DesiredStatus = DesiredStateStopped
+	}
+	workspace.Annotations[AnnotationArchiveRequested] = now.UTC().Format(time.RFC3339)
+
+	sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonArchivalRequested,
+		fmt.Sprintf("Requesting archival of workspace not used since %s", lastUsed.UTC().Format(time.RFC3339)))
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+		return staleOutcome{}, fmt.Errorf("failed to request archival: %w", err)
+	}
+	logger.Info("Requested archival of stale workspace", "lastUsed", lastUsed)
+	return staleOutcome{updated: true}, nil
+}
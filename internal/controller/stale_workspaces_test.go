@@ -0,0 +1,225 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const day = 24 * time.Hour
+
+func newStaleTestStateMachine(t *testing.T, objects ...client.Object) (*StateMachine, client.Client) {
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, objects...)
+	sm.intentResolver = NewDesiredStatusResolver(DefaultUserIntentCooldown)
+	sm.stalePolicy = StaleWorkspacePolicy{Threshold: 7 * day, GracePeriod: 3 * day}
+	return sm, k8sClient
+}
+
+// newStaleTestWorkspace returns a stopped workspace last used lastUsedAgo ago
+func newStaleTestWorkspace(lastUsedAgo time.Duration) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.CreationTimestamp = metav1.NewTime(time.Now().Add(-60 * day))
+	workspace.Spec.DesiredStatus = DesiredStateStopped
+	lastActivity := metav1.NewTime(time.Now().Add(-lastUsedAgo))
+	workspace.Status.LastActivityTime = &lastActivity
+	return workspace
+}
+
+// flagStale marks the workspace as flagged stale flaggedAgo ago
+func flagStale(workspace *workspacev1alpha1.Workspace, flaggedAgo time.Duration) {
+	workspace.Status.Conditions = append(workspace.Status.Conditions, metav1.Condition{
+		Type:               ConditionTypeStale,
+		Status:             metav1.ConditionTrue,
+		Reason:             ReasonInactive,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-flaggedAgo)),
+	})
+}
+
+func getStaleTestWorkspace(t *testing.T, k8sClient client.Client) *workspacev1alpha1.Workspace {
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "ws", Namespace: "team-a"}, stored))
+	return stored
+}
+
+func TestStalenessLeavesRecentlyUsedWorkspace(t *testing.T) {
+	workspace := newStaleTestWorkspace(2 * day)
+	sm, _ := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.WithinDuration(t, workspace.Status.LastActivityTime.Add(7*day), outcome.nextTransition, time.Second)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStale))
+	assert.Empty(t, workspace.Status.History)
+}
+
+func TestStalenessFlagsUnusedWorkspaceAndNotifiesOwner(t *testing.T) {
+	workspace := newStaleTestWorkspace(10 * day)
+	sm, _ := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.WithinDuration(t, time.Now().Add(3*day), outcome.nextTransition, time.Minute)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStale)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonInactive, condition.Reason)
+	assert.Contains(t, condition.Message, AnnotationStaleExempt)
+	require.Len(t, workspace.Status.History, 1)
+	assert.Equal(t, HistoryActionFlaggedStale, workspace.Status.History[0].Action)
+
+	events := sm.recorder.(*record.FakeRecorder).Events
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, fmt.Sprintf("%s %s", corev1.EventTypeWarning, ConditionTypeStale))
+}
+
+func TestStalenessWaitsForGracePeriod(t *testing.T) {
+	workspace := newStaleTestWorkspace(10 * day)
+	flagStale(workspace, day)
+	sm, k8sClient := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.WithinDuration(t, time.Now().Add(2*day), outcome.nextTransition, time.Minute)
+	assert.NotContains(t, getStaleTestWorkspace(t, k8sClient).Annotations, AnnotationArchiveRequested)
+}
+
+func TestStalenessRequestsArchivalAfterGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	workspace := newStaleTestWorkspace(10 * day)
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	workspace.Spec.IdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30}
+	flagStale(workspace, 4*day)
+	sm, k8sClient := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(ctx, workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+
+	stored := getStaleTestWorkspace(t, k8sClient)
+	assert.Contains(t, stored.Annotations, AnnotationArchiveRequested)
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus, "the compute is released")
+	assert.Contains(t, stored.Annotations, AnnotationCullerIntent)
+
+	// The next pass records the request in status
+	outcome, err = sm.reconcileStaleness(ctx, stored, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	condition := FindCondition(&stored.Status.Conditions, ConditionTypeStale)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonArchivalRequested, condition.Reason)
+	require.NotEmpty(t, stored.Status.History)
+	assert.Equal(t, HistoryActionArchivalRequested, stored.Status.History[len(stored.Status.History)-1].Action)
+}
+
+func TestStalenessArchivalYieldsToMaintenance(t *testing.T) {
+	workspace := newStaleTestWorkspace(10 * day)
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	workspace.Spec.IdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30}
+	maintenance, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{DesiredStatus: DesiredStateRunning})
+	require.NoError(t, err)
+	workspace.Annotations = map[string]string{AnnotationMaintenanceIntent: maintenance}
+	flagStale(workspace, 4*day)
+	sm, k8sClient := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.Equal(t, DesiredStateRunning, getStaleTestWorkspace(t, k8sClient).Spec.DesiredStatus)
+}
+
+func TestStalenessOwnerVetoWithdrawsArchival(t *testing.T) {
+	ctx := context.Background()
+	workspace := newStaleTestWorkspace(10 * day)
+	workspace.Annotations = map[string]string{
+		AnnotationArchiveRequested: time.Now().UTC().Format(time.RFC3339),
+		AnnotationStaleExempt:      "true",
+	}
+	flagStale(workspace, 4*day)
+	sm, k8sClient := newStaleTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStaleness(ctx, workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+	stored := getStaleTestWorkspace(t, k8sClient)
+	assert.NotContains(t, stored.Annotations, AnnotationArchiveRequested)
+
+	outcome, err = sm.reconcileStaleness(ctx, stored, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.True(t, outcome.nextTransition.IsZero(), "an exempt workspace is not evaluated again")
+	condition := FindCondition(&stored.Status.Conditions, ConditionTypeStale)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonStaleExempt, condition.Reason)
+	require.NotEmpty(t, stored.Status.History)
+	assert.Equal(t, HistoryActionStaleCleared, stored.Status.History[len(stored.Status.History)-1].Action)
+}
+
+func TestStalenessNamespaceOverridesPolicy(t *testing.T) {
+	workspace := newStaleTestWorkspace(10 * day)
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{AnnotationStaleAfterDays: "30"},
+	}}
+	sm, _ := newStaleTestStateMachine(t, workspace, namespace)
+
+	outcome, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStale))
+	assert.WithinDuration(t, workspace.Status.LastActivityTime.Add(30*day), outcome.nextTransition, time.Second)
+
+	namespace.Annotations[AnnotationStaleAfterDays] = "0"
+	sm, _ = newStaleTestStateMachine(t, workspace, namespace)
+	outcome, err = sm.reconcileStaleness(context.Background(), workspace, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.True(t, outcome.nextTransition.IsZero(), "the namespace disables the policy")
+}
+
+func TestStalenessConsidersRunningWorkspaceWithoutIdleDetectionInUse(t *testing.T) {
+	workspace := newStaleTestWorkspace(30 * day)
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	sm, _ := newStaleTestStateMachine(t, workspace)
+
+	_, err := sm.reconcileStaleness(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeStale))
+}
+
+func TestAdvanceLastActivityTimeDropsSmallMoves(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	now := time.Now()
+
+	assert.False(t, advanceLastActivityTime(workspace, time.Time{}))
+	assert.True(t, advanceLastActivityTime(workspace, now))
+	assert.False(t, advanceLastActivityTime(workspace, now.Add(10*time.Minute)))
+	assert.True(t, advanceLastActivityTime(workspace, now.Add(ActivityRecordGranularity)))
+}
+
+func TestAppendHistoryKeepsLatestEntries(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	for i := range MaxWorkspaceHistoryEntries + 5 {
+		appendHistory(workspace, HistoryActionFlaggedStale, fmt.Sprintf("entry %d", i), time.Now())
+	}
+
+	require.Len(t, workspace.Status.History, MaxWorkspaceHistoryEntries)
+	assert.Equal(t, "entry 5", workspace.Status.History[0].Message)
+	assert.Equal(t, fmt.Sprintf("entry %d", MaxWorkspaceHistoryEntries+4), workspace.Status.History[MaxWorkspaceHistoryEntries-1].Message)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// HistoryActionStartApproved records the approval of a workspace start
+	HistoryActionStartApproved = "StartApproved"
+	// HistoryActionStartApprovalRevoked records the withdrawal of a workspace start approval
+	HistoryActionStartApprovalRevoked = "StartApprovalRevoked"
+)
+
+// startApprovalOutcome is the result of enforcing a template start approval requirement
+type startApprovalOutcome struct {
+	// desiredStatus is the desired status to reconcile, Stopped while the start is not approved
+	desiredStatus string
+
+	// updated is true when the workspace object was updated and must be reconciled again
+	updated bool
+}
+
+// reconcileStartApproval enforces the start approval requirement of the workspace template, recorded
+// by the webhook. An unapproved workspace is kept stopped whichever actor wants it running, and a
+// running workspace whose approval is removed is stopped. Approvals and revocations are recorded in
+// the AwaitingApproval condition and the workspace history, for the reconciliation to persist.
+func (sm *StateMachine) reconcileStartApproval(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	desiredStatus string) (startApprovalOutcome, error) {
+	if workspace.Annotations[AnnotationStartApprovalRequired] != "true" {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeAwaitingApproval)
+		return startApprovalOutcome{desiredStatus: desiredStatus}, nil
+	}
+
+	logger := logf.FromContext(ctx)
+	now := time.Now()
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeAwaitingApproval)
+	wasApproved := condition != nil && condition.Status == metav1.ConditionFalse
+
+	if approver := workspace.Annotations[AnnotationStartApprovedBy]; approver != "" {
+		message := fmt.Sprintf("Start approved by %s", approver)
+		if !wasApproved || condition.Message != message {
+			setAwaitingApprovalCondition(workspace, metav1.ConditionFalse, ReasonStartApproved, message)
+			appendHistory(workspace, HistoryActionStartApproved, message, now)
+		}
+		return startApprovalOutcome{desiredStatus: desiredStatus}, nil
+	}
+
+	if wasApproved {
+		message := fmt.Sprintf("Start approval withdrawn; annotation %s must be set by an approver before the workspace can run", AnnotationStartApprovedBy)
+		setAwaitingApprovalCondition(workspace, metav1.ConditionTrue, ReasonApprovalRevoked, message)
+		appendHistory(workspace, HistoryActionStartApprovalRevoked, message, now)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonApprovalRevoked, message)
+	} else if condition == nil || condition.Status != metav1.ConditionTrue {
+		setAwaitingApprovalCondition(workspace, metav1.ConditionTrue, ReasonApprovalRequired,
+			fmt.Sprintf("The workspace template requires a member of its start approver groups to set annotation %s", AnnotationStartApprovedBy))
+	}
+
+	if desiredStatus != DesiredStateRunning {
+		return startApprovalOutcome{desiredStatus: desiredStatus}, nil
+	}
+	if workspace.Spec.DesiredStatus != DesiredStateRunning {
+		// A schedule or maintenance intent wants the workspace running; hold it stopped
+		logger.Info("Keeping workspace without start approval stopped")
+		return startApprovalOutcome{desiredStatus: DesiredStateStopped}, nil
+	}
+
+	logger.Info("Stopping workspace without start approval")
+	original := workspace.DeepCopy()
+	workspace.Spec.DesiredStatus = DesiredStateStopped
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
+		return startApprovalOutcome{}, fmt.Errorf("failed to stop workspace without start approval: %w", err)
+	}
+	return startApprovalOutcome{desiredStatus: DesiredStateStopped, updated: true}, nil
+}
+
+// setAwaitingApprovalCondition sets the start approval condition
+func setAwaitingApprovalCondition(workspace *workspacev1alpha1.Workspace, status metav1.ConditionStatus, reason, message string) {
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type:    ConditionTypeAwaitingApproval,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
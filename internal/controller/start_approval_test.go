@@ -0,0 +1,106 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newApprovalTestWorkspace returns a workspace whose template requires start approval
+func newApprovalTestWorkspace(desiredStatus string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationStartApprovalRequired: "true"}
+	workspace.Spec.DesiredStatus = desiredStatus
+	return workspace
+}
+
+func TestStartApprovalHoldsUnapprovedWorkspaceStopped(t *testing.T) {
+	workspace := newApprovalTestWorkspace(DesiredStateStopped)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	// A schedule wants the workspace running
+	outcome, err := sm.reconcileStartApproval(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	assert.Equal(t, DesiredStateStopped, outcome.desiredStatus)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeAwaitingApproval)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonApprovalRequired, condition.Reason)
+	assert.Empty(t, workspace.Status.History)
+}
+
+func TestStartApprovalRecordsApprover(t *testing.T) {
+	workspace := newApprovalTestWorkspace(DesiredStateRunning)
+	workspace.Annotations[AnnotationStartApprovedBy] = "lead1"
+	setAwaitingApprovalCondition(workspace, metav1.ConditionTrue, ReasonApprovalRequired, "")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	outcome, err := sm.reconcileStartApproval(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.Equal(t, DesiredStateRunning, outcome.desiredStatus)
+
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeAwaitingApproval)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonStartApproved, condition.Reason)
+	require.Len(t, workspace.Status.History, 1)
+	assert.Equal(t, HistoryActionStartApproved, workspace.Status.History[0].Action)
+	assert.Contains(t, workspace.Status.History[0].Message, "lead1")
+	assert.WithinDuration(t, time.Now(), workspace.Status.History[0].Time.Time, time.Minute)
+
+	// Later passes do not repeat the entry
+	_, err = sm.reconcileStartApproval(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.Len(t, workspace.Status.History, 1)
+}
+
+func TestStartApprovalRevocationStopsWorkspace(t *testing.T) {
+	ctx := context.Background()
+	workspace := newApprovalTestWorkspace(DesiredStateRunning)
+	setAwaitingApprovalCondition(workspace, metav1.ConditionFalse, ReasonStartApproved, "Start approved by lead1")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	outcome, err := sm.reconcileStartApproval(ctx, workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.True(t, outcome.updated)
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, DesiredStateStopped, stored.Spec.DesiredStatus)
+
+	// The next pass records the revocation in status
+	outcome, err = sm.reconcileStartApproval(ctx, stored, DesiredStateStopped)
+	require.NoError(t, err)
+	assert.False(t, outcome.updated)
+	condition := FindCondition(&stored.Status.Conditions, ConditionTypeAwaitingApproval)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonApprovalRevoked, condition.Reason)
+	require.Len(t, stored.Status.History, 1)
+	assert.Equal(t, HistoryActionStartApprovalRevoked, stored.Status.History[0].Action)
+}
+
+func TestStartApprovalClearsConditionWhenNoLongerRequired(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	setAwaitingApprovalCondition(workspace, metav1.ConditionTrue, ReasonApprovalRequired, "")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	outcome, err := sm.reconcileStartApproval(context.Background(), workspace, DesiredStateRunning)
+	require.NoError(t, err)
+	assert.Equal(t, DesiredStateRunning, outcome.desiredStatus)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeAwaitingApproval))
+}
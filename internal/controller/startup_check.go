@@ -0,0 +1,176 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// startupCheckContainerName is the container the startup check runs in
+const startupCheckContainerName = "workspace"
+
+// startupCheckOutcome is the result of the template startup check for the current workspace pod
+type startupCheckOutcome struct {
+	// failed is true if the check failed under FailurePolicy Fail
+	failed  bool
+	message string
+}
+
+// reconcileStartupCheck runs the startup check of the workspace template once per workspace pod,
+// and records the result in the StartupCheckPassed condition. The status is updated in memory.
+// A check that cannot be executed is recorded as Unknown and never fails the workspace.
+func (sm *StateMachine) reconcileStartupCheck(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (startupCheckOutcome, error) {
+	check, err := sm.resolveStartupCheck(ctx, workspace)
+	if err != nil {
+		return startupCheckOutcome{}, err
+	}
+	if check == nil {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+		workspace.Status.StartupCheckPodUID = ""
+		return startupCheckOutcome{}, nil
+	}
+
+	pod, err := sm.findReadyWorkspacePod(ctx, workspace)
+	if err != nil {
+		return startupCheckOutcome{}, err
+	}
+	if pod == nil {
+		return startupCheckOutcome{}, nil
+	}
+
+	// The check runs when the workspace (re)starts, not on every reconciliation
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+	if condition == nil || workspace.Status.StartupCheckPodUID != string(pod.UID) {
+		status, reason, message := sm.runStartupCheck(ctx, pod, check)
+		apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+			Type:    ConditionTypeStartupCheckPassed,
+			Status:  status,
+			Reason:  reason,
+			Message: message,
+		})
+		workspace.Status.StartupCheckPodUID = string(pod.UID)
+		if status != metav1.ConditionTrue {
+			sm.recorder.Event(workspace, corev1.EventTypeWarning, reason, message)
+		}
+		condition = apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+	}
+
+	return startupCheckOutcome{
+		failed: condition.Reason == ReasonStartupCheckFailed &&
+			check.FailurePolicy == workspacev1alpha1.StartupCheckFailurePolicyFail,
+		message: condition.Message,
+	}, nil
+}
+
+// resolveStartupCheck returns the startup check of the workspace template, at the revision the workspace
+// was admitted against, or nil if the workspace has none
+func (sm *StateMachine) resolveStartupCheck(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.StartupCheckSpec, error) {
+	resolver := sm.resourceManager.templateResolver
+	if resolver == nil || workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil, nil
+	}
+	template, err := resolver.ResolveTemplateRevision(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve template startup check: %w", err)
+	}
+	if template.Spec.StartupCheck == nil || len(template.Spec.StartupCheck.Command) == 0 {
+		return nil, nil
+	}
+	return template.Spec.StartupCheck, nil
+}
+
+// findReadyWorkspacePod returns a running and ready workspace pod, or nil if there is none
+func (sm *StateMachine) findReadyWorkspacePod(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := sm.resourceManager.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace), client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return pod, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// runStartupCheck executes the check in the workspace container and returns the condition to record
+func (sm *StateMachine) runStartupCheck(
+	ctx context.Context,
+	pod *corev1.Pod,
+	check *workspacev1alpha1.StartupCheckSpec) (metav1.ConditionStatus, string, string) {
+	logger := logf.FromContext(ctx).WithValues("pod", pod.Name)
+	if sm.podExec == nil {
+		return metav1.ConditionUnknown, ReasonStartupCheckUnavailable, "Startup check could not be executed: pod exec is not available"
+	}
+
+	timeout := DefaultStartupCheckTimeout
+	if check.TimeoutSeconds > 0 {
+		timeout = time.Duration(check.TimeoutSeconds) * time.Second
+	}
+	// Templates stored before the timeout was capped may exceed the cap
+	timeout = min(timeout, MaxStartupCheckTimeout)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output, err := sm.podExec.ExecInPod(checkCtx, pod, startupCheckContainerName, check.Command, "")
+	var exitErr utilexec.ExitError
+	switch {
+	case err == nil:
+		logger.Info("Startup check passed")
+		return metav1.ConditionTrue, ReasonStartupCheckSucceeded, withStartupCheckOutput("Startup check passed", output)
+	case errors.As(err, &exitErr):
+		logger.Info("Startup check failed", "exitCode", exitErr.ExitStatus())
+		return metav1.ConditionFalse, ReasonStartupCheckFailed,
+			withStartupCheckOutput(fmt.Sprintf("Startup check exited with code %d", exitErr.ExitStatus()), output)
+	case errors.Is(checkCtx.Err(), context.DeadlineExceeded):
+		logger.Info("Startup check timed out", "timeout", timeout)
+		return metav1.ConditionFalse, ReasonStartupCheckFailed,
+			withStartupCheckOutput(fmt.Sprintf("Startup check timed out after %s", timeout), output)
+	default:
+		logger.Error(err, "Failed to execute startup check")
+		return metav1.ConditionUnknown, ReasonStartupCheckUnavailable,
+			fmt.Sprintf("Startup check could not be executed: %v", err)
+	}
+}
+
+// withStartupCheckOutput appends an excerpt of the check output to the condition message
+func withStartupCheckOutput(message string, output string) string {
+	if output == "" {
+		return message
+	}
+	if len(output) > MaxStartupCheckOutputLength {
+		output = output[len(output)-MaxStartupCheckOutputLength:]
+	}
+	return fmt.Sprintf("%s: %s", message, output)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilexec "k8s.io/client-go/util/exec"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newStartupCheckTestPod(uid string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws-pod-" + uid,
+			Namespace: "team-a",
+			UID:       types.UID(uid),
+			Labels:    GenerateLabels("ws"),
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+}
+
+// newStartupCheckTestStateMachine returns a state machine for a workspace whose template defines a
+// startup check with the given failure policy, and a ready workspace pod
+func newStartupCheckTestStateMachine(
+	t *testing.T,
+	policy workspacev1alpha1.StartupCheckFailurePolicy,
+	execUtil *MockPodExecUtil) (*StateMachine, client.Client, *workspacev1alpha1.Workspace) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "GPU",
+			StartupCheck: &workspacev1alpha1.StartupCheckSpec{
+				Command:        []string{"nvidia-smi"},
+				TimeoutSeconds: 5,
+				FailurePolicy:  policy,
+			},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "gpu"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template, newStartupCheckTestPod("pod-1"))
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	sm.podExec = execUtil
+	return sm, k8sClient, workspace
+}
+
+func TestStartupCheckPassRecordsOutput(t *testing.T) {
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace", []string{"nvidia-smi"}, "").
+		Return("GPU 0: A10G", nil).Once()
+	sm, _, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyFail, execUtil)
+
+	outcome, err := sm.reconcileStartupCheck(context.Background(), workspace)
+	require.NoError(t, err)
+	assert.False(t, outcome.failed)
+
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonStartupCheckSucceeded, condition.Reason)
+	assert.Contains(t, condition.Message, "GPU 0: A10G")
+	assert.Equal(t, "pod-1", workspace.Status.StartupCheckPodUID)
+	execUtil.AssertExpectations(t)
+}
+
+func TestStartupCheckFailureUnderWarnKeepsWorkspaceRunning(t *testing.T) {
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace", mock.Anything, "").
+		Return("", utilexec.CodeExitError{Err: errors.New("command terminated"), Code: 9}).Once()
+	sm, _, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyWarn, execUtil)
+
+	outcome, err := sm.reconcileStartupCheck(context.Background(), workspace)
+	require.NoError(t, err)
+	assert.False(t, outcome.failed)
+
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonStartupCheckFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "exited with code 9")
+}
+
+func TestStartupCheckFailureUnderFailDegradesWorkspace(t *testing.T) {
+	ctx := context.Background()
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace", mock.Anything, "").
+		Return("no devices found", utilexec.CodeExitError{Err: errors.New("command terminated"), Code: 1}).Once()
+	sm, k8sClient, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyFail, execUtil)
+
+	outcome, err := sm.reconcileStartupCheck(ctx, workspace)
+	require.NoError(t, err)
+	require.True(t, outcome.failed)
+	assert.Contains(t, outcome.message, "no devices found")
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	require.NoError(t, sm.statusManager.UpdateStartupCheckFailedStatus(ctx, workspace, outcome.message, &stored.Status))
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.False(t, sm.statusManager.IsWorkspaceAvailable(stored))
+	degraded := FindCondition(&stored.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+	assert.Equal(t, ReasonStartupCheckFailed, degraded.Reason)
+	assert.NotNil(t, FindCondition(&stored.Status.Conditions, ConditionTypeStartupCheckPassed))
+}
+
+func TestStartupCheckExecErrorDegradesToWarn(t *testing.T) {
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace", mock.Anything, "").
+		Return("", errors.New("unable to upgrade connection")).Once()
+	sm, _, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyFail, execUtil)
+
+	outcome, err := sm.reconcileStartupCheck(context.Background(), workspace)
+	require.NoError(t, err)
+	assert.False(t, outcome.failed)
+
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+	assert.Equal(t, ReasonStartupCheckUnavailable, condition.Reason)
+}
+
+func TestStartupCheckRunsOncePerPod(t *testing.T) {
+	ctx := context.Background()
+	execUtil := &MockPodExecUtil{}
+	execUtil.On("ExecInPod", mock.Anything, mock.Anything, "workspace", mock.Anything, "").
+		Return("", utilexec.CodeExitError{Err: errors.New("command terminated"), Code: 1}).Twice()
+	sm, k8sClient, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyFail, execUtil)
+
+	_, err := sm.reconcileStartupCheck(ctx, workspace)
+	require.NoError(t, err)
+
+	// Later reconciliations reuse the result recorded for the pod
+	outcome, err := sm.reconcileStartupCheck(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, outcome.failed)
+	execUtil.AssertNumberOfCalls(t, "ExecInPod", 1)
+
+	// The check runs again once the workspace restarts in a new pod
+	require.NoError(t, k8sClient.Delete(ctx, newStartupCheckTestPod("pod-1")))
+	require.NoError(t, k8sClient.Create(ctx, newStartupCheckTestPod("pod-2")))
+	_, err = sm.reconcileStartupCheck(ctx, workspace)
+	require.NoError(t, err)
+	execUtil.AssertNumberOfCalls(t, "ExecInPod", 2)
+	assert.Equal(t, "pod-2", workspace.Status.StartupCheckPodUID)
+}
+
+func TestStartupCheckClearedWithoutTemplateCheck(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.StartupCheckPodUID = "pod-1"
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, metav1.Condition{
+		Type: ConditionTypeStartupCheckPassed, Status: metav1.ConditionTrue, Reason: ReasonStartupCheckSucceeded,
+	})
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	outcome, err := sm.reconcileStartupCheck(context.Background(), workspace)
+	require.NoError(t, err)
+	assert.False(t, outcome.failed)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupCheckPassed))
+	assert.Empty(t, workspace.Status.StartupCheckPodUID)
+}
+
+func TestStartupCheckTimeoutIsCappedWithinTheReconcileTimeout(t *testing.T) {
+	execUtil := &MockPodExecUtil{}
+	withinCap := mock.MatchedBy(func(ctx context.Context) bool {
+		deadline, ok := ctx.Deadline()
+		return ok && time.Until(deadline) <= MaxStartupCheckTimeout
+	})
+	execUtil.On("ExecInPod", withinCap, mock.Anything, "workspace", []string{"nvidia-smi"}, "").
+		Return("GPU 0: A10G", nil).Once()
+	sm, k8sClient, workspace := newStartupCheckTestStateMachine(t, workspacev1alpha1.StartupCheckFailurePolicyFail, execUtil)
+
+	// A template stored before the timeout was capped
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "gpu", Namespace: "team-a"}, template))
+	template.Spec.StartupCheck.TimeoutSeconds = 120
+	require.NoError(t, k8sClient.Update(context.Background(), template))
+
+	_, err := sm.reconcileStartupCheck(context.Background(), workspace)
+	require.NoError(t, err)
+	execUtil.AssertExpectations(t)
+}
@@ -0,0 +1,268 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const (
+	// StartupTriggerCreated marks the first start of a workspace, measured from its creation
+	StartupTriggerCreated = "Created"
+	// StartupTriggerResumed marks a start from Stopped, measured from the request to run
+	StartupTriggerResumed = "Resumed"
+	// StartupTriggerRestarted marks the replacement of the pod or container of a running workspace
+	StartupTriggerRestarted = "Restarted"
+)
+
+// Startup milestones, in the order a start usually reaches them
+const (
+	// StartupMilestoneStorageBound is reached when the PVC of the workspace is bound
+	StartupMilestoneStorageBound = "StorageBound"
+	// StartupMilestonePodScheduled is reached when the workspace pod is assigned a node
+	StartupMilestonePodScheduled = "PodScheduled"
+	// StartupMilestonePodInitialized is reached when the init containers of the pod completed,
+	// after which the kubelet pulls the images of its containers
+	StartupMilestonePodInitialized = "PodInitialized"
+	// StartupMilestoneContainerStarted is reached when the image was pulled and the workspace container runs
+	StartupMilestoneContainerStarted = "ContainerStarted"
+	// StartupMilestoneServerReady is reached when the workspace becomes available
+	StartupMilestoneServerReady = "ServerReady"
+
+	// ReasonStartupProgress is the reason of the events reporting startup milestones
+	ReasonStartupProgress = "StartupProgress"
+)
+
+// startupMilestoneMessages describe the milestones in the events reporting them
+var startupMilestoneMessages = map[string]string{
+	StartupMilestoneStorageBound:     "Storage bound",
+	StartupMilestonePodScheduled:     "Pod scheduled",
+	StartupMilestonePodInitialized:   "Image pull started",
+	StartupMilestoneContainerStarted: "Image pull finished and container started",
+	StartupMilestoneServerReady:      "Server ready",
+}
+
+// startupObservation is what a reconciliation observed of the start of a workspace
+type startupObservation struct {
+	// pod is the latest workspace pod, nil while none exists
+	pod *corev1.Pod
+	// storageBound is true when the workspace has storage and its PVC is bound
+	storageBound bool
+	// serverReady is true when the workspace becomes available
+	serverReady bool
+}
+
+// reconcileStartupProgress records the milestones the start of the workspace reached in its status, and emits
+// an event for each milestone newly reached. The status is written by the caller.
+func (sm *StateMachine) reconcileStartupProgress(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	serverReady bool) {
+	observation := sm.observeStartup(ctx, workspace)
+	observation.serverReady = serverReady
+	for _, milestone := range recordStartupProgress(workspace, observation, time.Now()) {
+		sm.recorder.Eventf(workspace, corev1.EventTypeNormal, ReasonStartupProgress, "%s after %s",
+			startupMilestoneMessages[milestone.Name], time.Duration(milestone.ElapsedSeconds)*time.Second)
+	}
+}
+
+// observeStartup reads the pod and PVC of the workspace. Failures are logged: progress reporting never
+// blocks a start.
+func (sm *StateMachine) observeStartup(ctx context.Context, workspace *workspacev1alpha1.Workspace) startupObservation {
+	logger := logf.FromContext(ctx)
+	observation := startupObservation{}
+
+	podList := &corev1.PodList{}
+	if err := sm.resourceManager.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace), client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		logger.Error(err, "Failed to list workspace pods")
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		if observation.pod == nil || observation.pod.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			observation.pod = pod
+		}
+	}
+
+	if hasPersistentStorage(workspace) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := sm.resourceManager.client.Get(ctx,
+			client.ObjectKey{Name: pvcNameFor(workspace), Namespace: workspace.Namespace}, pvc)
+		if err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to get the workspace PVC")
+		}
+		observation.storageBound = err == nil && pvc.Status.Phase == corev1.ClaimBound
+	}
+	return observation
+}
+
+// recordStartupProgress updates the startup progress of the workspace status with the observation, and returns
+// the milestones newly reached, in order. A start begins when the workspace is created, resumed from Stopped,
+// or when the pod or container of a workspace that was ready is replaced; its milestones are measured from then.
+func recordStartupProgress(
+	workspace *workspacev1alpha1.Workspace,
+	observation startupObservation,
+	now time.Time) []workspacev1alpha1.StartupMilestone {
+	progress := workspace.Status.StartupProgress
+	if progress == nil {
+		progress = beginStartup(workspace, now)
+		if progress == nil {
+			return nil
+		}
+	} else if restartedAt, restarted := podRestartedAt(progress, observation.pod, now); restarted {
+		progress = &workspacev1alpha1.StartupProgress{
+			Trigger:   StartupTriggerRestarted,
+			StartedAt: metav1.NewTime(restartedAt.Truncate(time.Second)),
+		}
+	} else {
+		progress = progress.DeepCopy()
+	}
+
+	pod := observation.pod
+	if pod != nil {
+		progress.PodName = pod.Name
+		progress.RestartCount = workspaceContainerRestartCount(pod)
+	}
+
+	var reached []workspacev1alpha1.StartupMilestone
+	reach := func(name string, at time.Time) {
+		if progressReached(progress, name) {
+			return
+		}
+		if at.IsZero() {
+			at = now
+		}
+		// A milestone before the start belongs to an earlier start of the same pod
+		if at.Before(progress.StartedAt.Time) {
+			return
+		}
+		reached = append(reached, workspacev1alpha1.StartupMilestone{
+			Name:           name,
+			ReachedAt:      metav1.NewTime(at.Truncate(time.Second)),
+			ElapsedSeconds: int32(at.Sub(progress.StartedAt.Time) / time.Second),
+		})
+	}
+
+	// The storage of a resumed or restarted workspace is bound since its first start
+	if observation.storageBound && progress.Trigger == StartupTriggerCreated {
+		reach(StartupMilestoneStorageBound, now)
+	}
+	if pod != nil {
+		if condition := findPodCondition(pod, corev1.PodScheduled); condition != nil && condition.Status == corev1.ConditionTrue {
+			reach(StartupMilestonePodScheduled, condition.LastTransitionTime.Time)
+		}
+		if condition := findPodCondition(pod, corev1.PodInitialized); condition != nil && condition.Status == corev1.ConditionTrue {
+			reach(StartupMilestonePodInitialized, condition.LastTransitionTime.Time)
+		}
+		if running := workspaceContainerRunning(pod); running != nil {
+			reach(StartupMilestoneContainerStarted, running.StartedAt.Time)
+		}
+	}
+	if observation.serverReady {
+		reach(StartupMilestoneServerReady, now)
+	}
+
+	// Milestones are reported in the order they were reached; ties keep the usual order
+	slices.SortStableFunc(reached, func(a, b workspacev1alpha1.StartupMilestone) int {
+		return a.ReachedAt.Compare(b.ReachedAt.Time)
+	})
+	progress.Milestones = append(progress.Milestones, reached...)
+	if len(progress.Milestones) > 0 {
+		progress.Milestone = progress.Milestones[len(progress.Milestones)-1].Name
+	}
+	workspace.Status.StartupProgress = progress
+	return reached
+}
+
+// beginStartup returns the progress of a start the workspace was not tracking yet, or nil when the workspace
+// already started before its progress was tracked
+func beginStartup(workspace *workspacev1alpha1.Workspace, now time.Time) *workspacev1alpha1.StartupProgress {
+	if stopped := FindCondition(&workspace.Status.Conditions, ConditionTypeStopped); stopped != nil &&
+		stopped.Status == metav1.ConditionTrue {
+		// Measured from the request to run, when it was made since the workspace stopped
+		startedAt := now
+		if setAt, err := time.Parse(time.RFC3339, workspace.Annotations[AnnotationDesiredStatusSetAt]); err == nil &&
+			!setAt.Before(stopped.LastTransitionTime.Time) && !setAt.After(now) {
+			startedAt = setAt
+		}
+		return &workspacev1alpha1.StartupProgress{
+			Trigger:   StartupTriggerResumed,
+			StartedAt: metav1.NewTime(startedAt.Truncate(time.Second)),
+		}
+	}
+	if workspace.Status.LastStartTime != nil {
+		return nil
+	}
+	startedAt := workspace.CreationTimestamp
+	if startedAt.IsZero() {
+		startedAt = metav1.NewTime(now.Truncate(time.Second))
+	}
+	return &workspacev1alpha1.StartupProgress{Trigger: StartupTriggerCreated, StartedAt: startedAt}
+}
+
+// podRestartedAt returns when the workspace was restarted, if the start it tracks completed and its pod was
+// replaced or its container restarted since
+func podRestartedAt(progress *workspacev1alpha1.StartupProgress, pod *corev1.Pod, now time.Time) (time.Time, bool) {
+	if pod == nil || !progressReached(progress, StartupMilestoneServerReady) {
+		return time.Time{}, false
+	}
+	if pod.Name != progress.PodName {
+		if pod.CreationTimestamp.IsZero() {
+			return now, true
+		}
+		return pod.CreationTimestamp.Time, true
+	}
+	if workspaceContainerRestartCount(pod) <= progress.RestartCount {
+		return time.Time{}, false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == serverContainerName && status.LastTerminationState.Terminated != nil &&
+			!status.LastTerminationState.Terminated.FinishedAt.IsZero() {
+			return status.LastTerminationState.Terminated.FinishedAt.Time, true
+		}
+	}
+	return now, true
+}
+
+// progressReached returns true if the start reached the milestone
+func progressReached(progress *workspacev1alpha1.StartupProgress, name string) bool {
+	return slices.ContainsFunc(progress.Milestones, func(milestone workspacev1alpha1.StartupMilestone) bool {
+		return milestone.Name == name
+	})
+}
+
+// workspaceContainerRestartCount returns the restart count of the workspace container of the pod
+func workspaceContainerRestartCount(pod *corev1.Pod) int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == serverContainerName {
+			return status.RestartCount
+		}
+	}
+	return 0
+}
+
+// workspaceContainerRunning returns the running state of the workspace container of the pod, or nil
+func workspaceContainerRunning(pod *corev1.Pod) *corev1.ContainerStateRunning {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == serverContainerName {
+			return status.State.Running
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var startupTestCreated = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newStartupTestWorkspace() *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ws",
+			Namespace:         "team-a",
+			CreationTimestamp: metav1.NewTime(startupTestCreated),
+		},
+	}
+}
+
+// newStartupTestPod returns a workspace pod created at the time, scheduled and initialized after the delays
+func newStartupTestPod(name string, created time.Time, scheduled, initialized time.Duration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "team-a",
+			Labels:            GenerateLabels("ws"),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(scheduled))},
+				{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(initialized))},
+			},
+		},
+	}
+}
+
+// startContainer marks the workspace container of the pod running since the time
+func startContainer(pod *corev1.Pod, startedAt time.Time, restartCount int32) {
+	pod.Status.Phase = corev1.PodRunning
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:         serverContainerName,
+		RestartCount: restartCount,
+		State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(startedAt)}},
+	}}
+}
+
+func milestoneNames(milestones []workspacev1alpha1.StartupMilestone) []string {
+	var names []string
+	for _, milestone := range milestones {
+		names = append(names, milestone.Name)
+	}
+	return names
+}
+
+func TestRecordStartupProgressMeasuresTheFirstStartFromCreation(t *testing.T) {
+	workspace := newStartupTestWorkspace()
+	pod := newStartupTestPod("ws-pod", startupTestCreated.Add(time.Second), 5*time.Second, 7*time.Second)
+
+	reached := recordStartupProgress(workspace, startupObservation{pod: pod, storageBound: true},
+		startupTestCreated.Add(10*time.Second))
+	progress := workspace.Status.StartupProgress
+	require.NotNil(t, progress)
+	assert.Equal(t, StartupTriggerCreated, progress.Trigger)
+	assert.Equal(t, "ws-pod", progress.PodName)
+	assert.Equal(t, []string{StartupMilestonePodScheduled, StartupMilestonePodInitialized, StartupMilestoneStorageBound},
+		milestoneNames(reached), "milestones are ordered by the time they were reached")
+	assert.Equal(t, int32(6), reached[0].ElapsedSeconds)
+	assert.Equal(t, StartupMilestoneStorageBound, progress.Milestone)
+
+	startContainer(pod, startupTestCreated.Add(38*time.Second), 0)
+	reached = recordStartupProgress(workspace, startupObservation{pod: pod, storageBound: true},
+		startupTestCreated.Add(40*time.Second))
+	require.Equal(t, []string{StartupMilestoneContainerStarted}, milestoneNames(reached))
+	assert.Equal(t, int32(38), reached[0].ElapsedSeconds)
+
+	reached = recordStartupProgress(workspace, startupObservation{pod: pod, storageBound: true, serverReady: true},
+		startupTestCreated.Add(71*time.Second))
+	require.Equal(t, []string{StartupMilestoneServerReady}, milestoneNames(reached))
+	assert.Equal(t, int32(71), reached[0].ElapsedSeconds)
+	assert.Len(t, workspace.Status.StartupProgress.Milestones, 5)
+
+	// Milestones already reached are not reported again
+	assert.Empty(t, recordStartupProgress(workspace, startupObservation{pod: pod, storageBound: true, serverReady: true},
+		startupTestCreated.Add(80*time.Second)))
+}
+
+func TestRecordStartupProgressMeasuresAResumeFromTheRequestToRun(t *testing.T) {
+	workspace := newStartupTestWorkspace()
+	lastStart := metav1.NewTime(startupTestCreated)
+	workspace.Status.LastStartTime = &lastStart
+	stoppedAt := startupTestCreated.Add(time.Hour)
+	workspace.Status.Conditions = []metav1.Condition{{
+		Type: ConditionTypeStopped, Status: metav1.ConditionTrue, LastTransitionTime: metav1.NewTime(stoppedAt),
+	}}
+	requestedAt := stoppedAt.Add(time.Hour)
+	workspace.Annotations = map[string]string{AnnotationDesiredStatusSetAt: requestedAt.Format(time.RFC3339)}
+
+	pod := newStartupTestPod("ws-pod", requestedAt.Add(time.Second), 3*time.Second, 4*time.Second)
+	reached := recordStartupProgress(workspace, startupObservation{pod: pod, storageBound: true},
+		requestedAt.Add(10*time.Second))
+
+	progress := workspace.Status.StartupProgress
+	require.NotNil(t, progress)
+	assert.Equal(t, StartupTriggerResumed, progress.Trigger)
+	assert.Equal(t, requestedAt, progress.StartedAt.Time)
+	assert.Equal(t, []string{StartupMilestonePodScheduled, StartupMilestonePodInitialized}, milestoneNames(reached),
+		"the storage of a resumed workspace was bound before")
+	assert.Equal(t, int32(4), reached[0].ElapsedSeconds)
+}
+
+func TestRecordStartupProgressIgnoresWorkspacesStartedBeforeTracking(t *testing.T) {
+	workspace := newStartupTestWorkspace()
+	lastStart := metav1.NewTime(startupTestCreated)
+	workspace.Status.LastStartTime = &lastStart
+	pod := newStartupTestPod("ws-pod", startupTestCreated, time.Second, time.Second)
+
+	assert.Empty(t, recordStartupProgress(workspace, startupObservation{pod: pod, serverReady: true},
+		startupTestCreated.Add(time.Hour)))
+	assert.Nil(t, workspace.Status.StartupProgress)
+}
+
+func TestRecordStartupProgressResetsOnRestarts(t *testing.T) {
+	workspace := newStartupTestWorkspace()
+	pod := newStartupTestPod("ws-pod", startupTestCreated, time.Second, 2*time.Second)
+	startContainer(pod, startupTestCreated.Add(3*time.Second), 0)
+	recordStartupProgress(workspace, startupObservation{pod: pod, serverReady: true}, startupTestCreated.Add(5*time.Second))
+	require.Equal(t, StartupMilestoneServerReady, workspace.Status.StartupProgress.Milestone)
+
+	// The container restarts in the same pod: only the container milestones are reached again
+	crashedAt := startupTestCreated.Add(time.Hour)
+	startContainer(pod, crashedAt.Add(2*time.Second), 1)
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{
+		FinishedAt: metav1.NewTime(crashedAt),
+	}
+	reached := recordStartupProgress(workspace, startupObservation{pod: pod}, crashedAt.Add(3*time.Second))
+	progress := workspace.Status.StartupProgress
+	assert.Equal(t, StartupTriggerRestarted, progress.Trigger)
+	assert.Equal(t, crashedAt, progress.StartedAt.Time)
+	assert.Equal(t, int32(1), progress.RestartCount)
+	assert.Equal(t, []string{StartupMilestoneContainerStarted}, milestoneNames(reached))
+	assert.Equal(t, int32(2), reached[0].ElapsedSeconds)
+
+	// The container recovers, then the pod is replaced: the new pod is measured from its creation
+	recordStartupProgress(workspace, startupObservation{pod: pod, serverReady: true}, crashedAt.Add(5*time.Second))
+	replacedAt := crashedAt.Add(time.Hour)
+	replacement := newStartupTestPod("ws-pod-2", replacedAt, time.Second, 2*time.Second)
+	reached = recordStartupProgress(workspace, startupObservation{pod: replacement, storageBound: true},
+		replacedAt.Add(5*time.Second))
+	progress = workspace.Status.StartupProgress
+	assert.Equal(t, StartupTriggerRestarted, progress.Trigger)
+	assert.Equal(t, "ws-pod-2", progress.PodName)
+	assert.Equal(t, []string{StartupMilestonePodScheduled, StartupMilestonePodInitialized}, milestoneNames(reached))
+	assert.Equal(t, StartupMilestonePodInitialized, progress.Milestone)
+}
+
+func TestReconcileStartupProgressEmitsAnEventPerMilestone(t *testing.T) {
+	ctx := context.Background()
+	workspace := newStartupTestWorkspace()
+	workspace.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	pod := newStartupTestPod("ws-pod", workspace.CreationTimestamp.Time, 4*time.Second, 6*time.Second)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{})
+	require.NoError(t, k8sClient.Create(ctx, pod))
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	sm.reconcileStartupProgress(ctx, workspace, false)
+
+	require.Len(t, recorder.Events, 2)
+	assert.Equal(t, "Normal StartupProgress Pod scheduled after 4s", <-recorder.Events)
+	assert.Equal(t, "Normal StartupProgress Image pull started after 6s", <-recorder.Events)
+	assert.Equal(t, StartupMilestonePodInitialized, workspace.Status.StartupProgress.Milestone)
+}
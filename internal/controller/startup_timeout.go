@@ -0,0 +1,91 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// reconcileStartupTimeout records in the StartupTimedOut condition a workspace whose server did not become
+// ready within the startup timeout of its template, measured from the ContainerStarted milestone of the
+// current start; the owner is warned with an event once per start. The workspace keeps starting. It runs
+// after reconcileStartupProgress, and the status is updated in memory.
+func (sm *StateMachine) reconcileStartupTimeout(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	serverReady bool,
+	now time.Time) {
+	timeout := sm.startupTimeoutFor(ctx, workspace)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut)
+	if timeout == 0 {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeStartupTimedOut)
+		return
+	}
+	if serverReady {
+		if condition != nil {
+			apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+				ConditionTypeStartupTimedOut, metav1.ConditionFalse, ReasonServerReady, "Workspace server is ready"))
+		}
+		return
+	}
+
+	startedAt := containerStartedAt(workspace.Status.StartupProgress)
+	if startedAt.IsZero() || now.Sub(startedAt) <= timeout {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeStartupTimedOut)
+		return
+	}
+	message := fmt.Sprintf("Workspace server did not become ready within %s of its container start at %s",
+		timeout, startedAt.UTC().Format(time.RFC3339))
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		logf.FromContext(ctx).Info("Workspace server did not become ready in time", "startupTimeout", timeout)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonStartupTimedOut, message)
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeStartupTimedOut, metav1.ConditionTrue, ReasonStartupTimedOut, message))
+}
+
+// startupTimeoutFor returns the startup timeout of the workspace template, at the revision the workspace
+// was admitted against, or zero when it has none. Resolution failures are logged: the timeout only reports.
+func (sm *StateMachine) startupTimeoutFor(ctx context.Context, workspace *workspacev1alpha1.Workspace) time.Duration {
+	resolver := sm.resourceManager.templateResolver
+	if resolver == nil || workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return 0
+	}
+	template, err := resolver.ResolveTemplateRevision(ctx, workspace)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			logf.FromContext(ctx).Error(err, "Failed to resolve the template startup timeout")
+		}
+		return 0
+	}
+	if template.Spec.StartupTimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*template.Spec.StartupTimeoutSeconds) * time.Second
+}
+
+// containerStartedAt returns when the workspace container started in the current start, or zero before it did
+func containerStartedAt(progress *workspacev1alpha1.StartupProgress) time.Time {
+	if progress == nil {
+		return time.Time{}
+	}
+	for _, milestone := range progress.Milestones {
+		if milestone.Name == StartupMilestoneContainerStarted {
+			return milestone.ReachedAt.Time
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newStartupTimeoutTestStateMachine returns a state machine for a workspace of a template with a startup
+// timeout of 5 minutes, whose container started at startedAt
+func newStartupTimeoutTestStateMachine(t *testing.T, startedAt time.Time) (*StateMachine, *workspacev1alpha1.Workspace) {
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "lab", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:           "Lab",
+			StartupTimeoutSeconds: ptr.To[int32](300),
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "lab"}
+	workspace.Status.StartupProgress = &workspacev1alpha1.StartupProgress{
+		Trigger:   StartupTriggerCreated,
+		StartedAt: metav1.NewTime(startedAt.Add(-time.Minute)),
+		Milestones: []workspacev1alpha1.StartupMilestone{
+			{Name: StartupMilestoneContainerStarted, ReachedAt: metav1.NewTime(startedAt), ElapsedSeconds: 60},
+		},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	return sm, workspace
+}
+
+func TestStartupTimeoutReportsWorkspacesNeverReady(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	sm, workspace := newStartupTimeoutTestStateMachine(t, now.Add(-4*time.Minute))
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	sm.reconcileStartupTimeout(ctx, workspace, false, now)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut))
+
+	sm.reconcileStartupTimeout(ctx, workspace, false, now.Add(2*time.Minute))
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Contains(t, condition.Message, "did not become ready within 5m0s")
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, corev1.EventTypeWarning+" "+ReasonStartupTimedOut)
+
+	sm.reconcileStartupTimeout(ctx, workspace, false, now.Add(3*time.Minute))
+	assert.Empty(t, events, "the owner is warned once per start")
+
+	sm.reconcileStartupTimeout(ctx, workspace, true, now.Add(4*time.Minute))
+	condition = apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonServerReady, condition.Reason)
+}
+
+func TestStartupTimeoutWaitsForTheContainerToStart(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	sm, workspace := newStartupTimeoutTestStateMachine(t, now.Add(-time.Hour))
+	workspace.Status.StartupProgress.Milestones = nil
+
+	sm.reconcileStartupTimeout(ctx, workspace, false, now)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut),
+		"image pulls and init containers are not bounded by the startup timeout")
+
+	sm.reconcileStartupTimeout(ctx, workspace, true, now)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStartupTimedOut))
+}
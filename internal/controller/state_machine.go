@@ -8,10 +8,16 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/cleanuphook"
+	"github.com/jupyter-infra/jupyter-k8s/internal/pluginadapters"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -33,6 +39,22 @@ type StateMachine struct {
 	statusManager   *StatusManager
 	recorder        record.EventRecorder
 	idleChecker     *WorkspaceIdleChecker
+	idleProbes      *idleProbeWorker
+	intentResolver  *DesiredStatusResolver
+	stalePolicy     StaleWorkspacePolicy
+	drainPeriod     time.Duration
+	// deletionWarningPeriod is how long before deleting a stopped workspace under spec.retention its owner is
+	// warned; zero uses DefaultDeletionWarningPeriod
+	deletionWarningPeriod time.Duration
+	// bootstrapTimeout is how long the init containers of a workspace pod may take; zero uses DefaultBootstrapTimeout
+	bootstrapTimeout time.Duration
+	// podExec runs template startup checks and server shutdown requests; checks are recorded as unavailable when nil
+	podExec pluginadapters.PodExecInterface
+	// cullingDryRun records the workspaces the idle shutdown rules would stop without stopping them
+	cullingDryRun bool
+	// cleanupHooks are called when a workspace is deleted, with cleanupHookClient
+	cleanupHooks      []cleanuphook.Hook
+	cleanupHookClient *cleanuphook.Client
 }
 
 // NewStateMachine creates a new StateMachine
@@ -41,13 +63,19 @@ func NewStateMachine(
 	statusManager *StatusManager,
 	recorder record.EventRecorder,
 	idleChecker *WorkspaceIdleChecker,
+	intentResolver *DesiredStatusResolver,
 ) *StateMachine {
-	return &StateMachine{
+	sm := &StateMachine{
 		resourceManager: resourceManager,
 		statusManager:   statusManager,
 		recorder:        recorder,
 		idleChecker:     idleChecker,
+		intentResolver:  intentResolver,
 	}
+	if idleChecker != nil {
+		sm.idleProbes = newIdleProbeWorker(idleChecker.CheckWorkspaceIdle, IdleProbeTimeout, IdleProbeConcurrency)
+	}
+	return sm
 }
 
 // ReconcileDesiredState handles the state machine logic for Workspace
@@ -57,14 +85,145 @@ func (sm *StateMachine) ReconcileDesiredState(
 	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 
-	desiredStatus := sm.getDesiredStatus(workspace)
 	snapshotStatus := workspace.DeepCopy().Status
 
+	// Name the resources of the workspace; the prefix is persisted with the next status update
+	if err := sm.resourceManager.ResolveChildNamePrefix(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to resolve the child name prefix")
+		return ctrl.Result{}, err
+	}
+
+	// Detect template changes; the checksum is persisted with the next status update
+	if err := sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to check the template checksum")
+	}
+
+	// Grant the owner of an OwnerOnly workspace access to it, whatever its desired status
+	err := sm.resourceManager.ReconcileOwnerAccess(ctx, workspace)
+	if conflict, ok := asChildResourceConflict(err); ok {
+		return sm.handleChildResourceConflict(ctx, workspace, conflict, &snapshotStatus)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile the access of the workspace owner")
+		return ctrl.Result{}, err
+	}
+
+	// Grant the collaborators of the workspace access to it; they are reported with the next status update
+	err = sm.resourceManager.ReconcileCollaboratorAccess(ctx, workspace)
+	if conflict, ok := asChildResourceConflict(err); ok {
+		return sm.handleChildResourceConflict(ctx, workspace, conflict, &snapshotStatus)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to reconcile the access of the workspace collaborators")
+		return ctrl.Result{}, err
+	}
+
+	// Record the intent of the last scheduled start or stop; the next ones are persisted with the next status update
+	schedule, err := sm.reconcileSchedule(ctx, workspace, time.Now())
+	if err != nil {
+		logger.Error(err, "Failed to apply the schedule")
+		return ctrl.Result{}, err
+	}
+	if schedule.updated {
+		logDecision(logger, "ApplySchedule")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+
+	// Report the lifecycle policies of the workspace; they are persisted with the next status update
+	sm.reconcileEffectivePolicies(ctx, workspace)
+
+	// Expose the winning intent; it is persisted with the next status update
+	resolution := sm.intentResolver.Resolve(workspace)
+	workspace.Status.DesiredStatusIntent = &resolution.Intent
+	desiredStatus := resolution.Intent.DesiredStatus
+	logDecision(logger, "ResolveIntent", "actor", resolution.Intent.Actor, "desiredStatus", desiredStatus)
+
+	// Hold workspaces whose template requires start approval stopped until they are approved
+	approval, err := sm.reconcileStartApproval(ctx, workspace, desiredStatus)
+	if err != nil {
+		logger.Error(err, "Failed to enforce start approval")
+		return ctrl.Result{}, err
+	}
+	if approval.updated {
+		logDecision(logger, "HoldForStartApproval")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+	desiredStatus = approval.desiredStatus
+
+	// Stop or delete workspaces older than the maximum lifetime of their template, once their owner has been warned
+	lifetime, err := sm.reconcileMaxLifetime(ctx, workspace, desiredStatus, time.Now())
+	if err != nil {
+		logger.Error(err, "Failed to enforce the maximum lifetime")
+		return ctrl.Result{}, err
+	}
+	if lifetime.deleted {
+		logDecision(logger, "DeleteExpiredWorkspace")
+		return ctrl.Result{}, nil
+	}
+	if lifetime.updated {
+		logDecision(logger, "StopExpiredWorkspace")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+	desiredStatus = lifetime.desiredStatus
+
+	// Flag workspaces unused for too long, then request their archival
+	staleness, err := sm.reconcileStaleness(ctx, workspace, desiredStatus)
+	if err != nil {
+		logger.Error(err, "Failed to apply the stale workspace policy")
+		return ctrl.Result{}, err
+	}
+	if staleness.updated {
+		logDecision(logger, "UpdateStaleness")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+
+	// Delete workspaces stopped for longer than their retention, once their owner has been warned
+	stoppedDeletion, err := sm.reconcileStoppedDeletion(ctx, workspace, desiredStatus, time.Now())
+	if err != nil {
+		logger.Error(err, "Failed to apply the retention of the stopped workspace")
+		return ctrl.Result{}, err
+	}
+	if stoppedDeletion.deleted {
+		logDecision(logger, "DeleteStoppedWorkspace")
+		return ctrl.Result{}, nil
+	}
+
+	// Reset the workspace to its template defaults on request of the owner; the deployment rolls out the defaults
+	rejected, err := sm.resetToDefaults(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to reset the workspace to its template defaults")
+		return ctrl.Result{}, err
+	}
+	if rejected {
+		logDecision(logger, "RejectResetToDefaults")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+
+	// Apply the resource recommendations on request of the owner; the deployment rolls out the new resources
+	applied, err := sm.applyResourceRecommendations(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to apply the resource recommendations")
+		return ctrl.Result{}, err
+	}
+	if applied {
+		logDecision(logger, "ApplyResourceRecommendations")
+		return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
+	}
+
+	// A restart requested while the workspace does not run is skipped; it is persisted with the next status update
+	sm.skipRestartOfStoppedWorkspace(workspace, desiredStatus)
+
+	logDecision(logger, "ReconcileTowards", "desiredStatus", desiredStatus)
+	var result ctrl.Result
 	switch desiredStatus {
 	case DesiredStateStopped:
-		return sm.reconcileDesiredStoppedStatus(ctx, workspace, &snapshotStatus)
+		result, err = sm.reconcileDesiredStoppedStatus(ctx, workspace, &snapshotStatus)
 	case DesiredStateRunning:
-		return sm.reconcileDesiredRunningStatus(ctx, workspace, &snapshotStatus, accessStrategy)
+		result, err = sm.reconcileDesiredRunningStatus(ctx, workspace, &snapshotStatus, accessStrategy)
+	case DesiredStatePaused:
+		result, err = sm.reconcileDesiredPausedStatus(ctx, workspace, &snapshotStatus)
+	case DesiredStateHibernated:
+		result, err = sm.reconcileDesiredHibernatedStatus(ctx, workspace, &snapshotStatus)
 	default:
 		err := fmt.Errorf("unknown desired status: %s", desiredStatus)
 		// Update error condition
@@ -75,14 +234,38 @@ func (sm *StateMachine) ReconcileDesiredState(
 		}
 		return ctrl.Result{RequeueAfter: LongRequeueDelay}, err
 	}
+
+	// Reconcile again at the next time-based transition of the workspace
+	result = requeueAtIntentTransition(result, err, resolution)
+	result = requeueAt(result, err, schedule.nextTransition)
+	result = requeueAt(result, err, staleness.nextTransition)
+	result = requeueAt(result, err, lifetime.nextTransition)
+	return requeueAt(result, err, stoppedDeletion.nextTransition), err
 }
 
-// getDesiredStatus returns the desired status with default fallback
+// getDesiredStatus returns the desired status resolved from the competing actor intents
 func (sm *StateMachine) getDesiredStatus(workspace *workspacev1alpha1.Workspace) string {
-	if workspace.Spec.DesiredStatus == "" {
-		return DefaultDesiredStatus
+	return sm.intentResolver.Resolve(workspace).Intent.DesiredStatus
+}
+
+// requeueAtIntentTransition makes sure the workspace is reconciled again when the winning intent lapses
+func requeueAtIntentTransition(result ctrl.Result, err error, resolution DesiredStatusResolution) ctrl.Result {
+	return requeueAt(result, err, resolution.NextTransition)
+}
+
+// requeueAt makes sure the workspace is reconciled again at transition, unless it is zero
+func requeueAt(result ctrl.Result, err error, transition time.Time) ctrl.Result {
+	if err != nil || transition.IsZero() {
+		return result
+	}
+	untilTransition := time.Until(transition)
+	if untilTransition < MinimalRequeueDelay {
+		untilTransition = MinimalRequeueDelay
+	}
+	if result.RequeueAfter == 0 || untilTransition < result.RequeueAfter {
+		result.RequeueAfter = untilTransition
 	}
-	return workspace.Spec.DesiredStatus
+	return result
 }
 
 // GetAccessStrategyForWorkspace retrieves the AccessStrategy for a workspace
@@ -97,25 +280,35 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 	logger := logf.FromContext(ctx)
 	logger.Info("Attempting to bring Workspace status to 'Stopped'")
 
-	// Remove access strategy resources first
-	accessError := sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace)
-	if accessError != nil {
-		logger.Error(accessError, "Failed to remove access strategy resources")
-		// Continue with deletion of other resources, don't block on access strategy
+	// Tear down in order, so that open tabs are not routed to a dying pod and then to missing routes:
+	// the service stops routing new connections, in-flight requests get the drain period, then the pod
+	// stops, and only then are the access resources, the access URL and the service removed.
+	// Each step is recorded in the Stopped condition; a restarted controller picks up from the resources left.
+	released, result, err := sm.releaseCompute(ctx, workspace, ConditionTypeStopped, snapshotStatus,
+		func(readiness WorkspaceStoppingReadiness) error {
+			return sm.statusManager.UpdateStoppingStatus(ctx, workspace, readiness, snapshotStatus)
+		})
+	if !released {
+		return result, err
 	}
 
-	// Ensure deployment is deleted - this is an asynchronous operation
-	// EnsureDeploymentDeleted only ensures the delete API request is accepted by K8s
-	// It does not wait for the deployment to be fully removed
-	deployment, deploymentErr := sm.resourceManager.EnsureDeploymentDeleted(ctx, workspace)
-	if deploymentErr != nil {
-		err := fmt.Errorf("failed to get deployment: %w", deploymentErr)
-		// Update error condition
+	// Remove access resources and the access URL once the compute is gone
+	if accessErr := sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace); accessErr != nil {
+		logger.Error(accessErr, "Failed to remove access strategy resources")
 		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, err.Error(), snapshotStatus); statusErr != nil {
+			ctx, workspace, ReasonServiceError, accessErr.Error(), snapshotStatus); statusErr != nil {
 			logger.Error(statusErr, "Failed to update error status")
 		}
-		return ctrl.Result{}, err
+		return ctrl.Result{}, accessErr
+	}
+
+	if !sm.resourceManager.AreAccessResourcesDeleted(workspace) {
+		// AccessResources are not fully deleted, requeue
+		readiness := WorkspaceStoppingReadiness{computeStopped: true}
+		if err := sm.statusManager.UpdateStoppingStatus(ctx, workspace, readiness, snapshotStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
 	}
 
 	// Ensure service is deleted - this is an asynchronous operation
@@ -131,75 +324,158 @@ func (sm *StateMachine) reconcileDesiredStoppedStatus(
 		}
 		return ctrl.Result{}, err
 	}
+	if !sm.resourceManager.IsServiceMissingOrDeleting(service) {
+		// Deletion was just issued, requeue to check its progress
+		readiness := WorkspaceStoppingReadiness{computeStopped: true, accessResourcesStopped: true}
+		if err := sm.statusManager.UpdateStoppingStatus(ctx, workspace, readiness, snapshotStatus); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+	}
 
-	// Check if resources are fully deleted (asynchronous deletion check)
-	// A nil resource means the resource has been fully deleted
-	deploymentDeleted := sm.resourceManager.IsDeploymentMissingOrDeleting(deployment)
-	serviceDeleted := sm.resourceManager.IsServiceMissingOrDeleting(service)
-	accessResourcesDeleted := sm.resourceManager.AreAccessResourcesDeleted(workspace)
+	// All resources are fully deleted, update to stopped status
+	logger.Info("Deployment and Service are both deleted, updating to Stopped status")
 
-	if deploymentDeleted && serviceDeleted {
-		// Flag as Error if AccessResources failed to delete
-		if accessError != nil {
-			if statusErr := sm.statusManager.UpdateErrorStatus(
-				ctx, workspace, ReasonServiceError, accessError.Error(), snapshotStatus); statusErr != nil {
-				logger.Error(statusErr, "Failed to update error status")
-			}
-			return ctrl.Result{}, accessError
-		} else if !accessResourcesDeleted {
-			// AccessResources are not fully deleted, requeue
-			readiness := WorkspaceStoppingReadiness{
-				computeStopped:         deploymentDeleted,
-				serviceStopped:         serviceDeleted,
-				accessResourcesStopped: accessResourcesDeleted,
-			}
-			if err := sm.statusManager.UpdateStoppingStatus(ctx, workspace, readiness, snapshotStatus); err != nil {
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
-		} else {
-			// All resources are fully deleted, update to stopped status
-			logger.Info("Deployment and Service are both deleted, updating to Stopped status")
-
-			// Record workspace stopped event with specific message for preemption
-			if workspace.Annotations != nil && workspace.Annotations[PreemptionReasonAnnotation] == PreemptedReason {
-				sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceStopped", PreemptedReason)
-			} else {
-				sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceStopped", "Workspace has been stopped")
-			}
+	// Record workspace stopped event with specific message for preemption
+	if isPreempted(workspace) {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonPreempted, preemptionMessage(workspace))
+	} else {
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceStopped", "Workspace has been stopped")
+	}
 
-			if err := sm.statusManager.UpdateStoppedStatus(ctx, workspace, snapshotStatus); err != nil {
-				return ctrl.Result{}, err
-			}
-			return ctrl.Result{}, nil
+	// The workspace was in use until it stopped
+	if stopped := FindCondition(&workspace.Status.Conditions, ConditionTypeStopped); stopped == nil ||
+		stopped.Status != metav1.ConditionTrue {
+		advanceLastActivityTime(workspace, time.Now())
+	}
+	recordStoppedAt(workspace)
+	// Evicted pods and failed image pulls went away with the deployment; the next start begins with a clean slate
+	clearEphemeralStorageEvictedCondition(workspace)
+	clearImagePullFailedCondition(workspace)
+	// Shared volumes are looked up again on the next start
+	clearSharedVolumeMissingCondition(workspace)
+	// The next start is measured from the request to run, not from the creation of the workspace
+	workspace.Status.StartupProgress = nil
+
+	if err := sm.statusManager.UpdateStoppedStatus(ctx, workspace, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (sm *StateMachine) reconcileDesiredPausedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	logger.Info("Attempting to bring Workspace status to 'Paused'")
+
+	// Only the compute is released: the storage, the service, the access resources and the access URL
+	// are kept, so that resuming only recreates the deployment and the workspace keeps its URL
+	released, result, err := sm.releaseCompute(ctx, workspace, ConditionTypePaused, snapshotStatus,
+		func(readiness WorkspaceStoppingReadiness) error {
+			return sm.statusManager.UpdatePausingStatus(ctx, workspace, readiness, snapshotStatus)
+		})
+	if !released {
+		return result, err
+	}
+
+	if paused := FindCondition(&workspace.Status.Conditions, ConditionTypePaused); paused == nil ||
+		paused.Status != metav1.ConditionTrue {
+		logger.Info("Deployment is deleted, updating to Paused status")
+		sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspacePaused", "Workspace has been paused")
+		// The workspace was in use until it paused
+		advanceLastActivityTime(workspace, time.Now())
+	}
+	recordStoppedAt(workspace)
+	// Evicted pods and failed image pulls went away with the deployment; the next start begins with a clean slate
+	clearEphemeralStorageEvictedCondition(workspace)
+	clearImagePullFailedCondition(workspace)
+	// Shared volumes are looked up again on the next start
+	clearSharedVolumeMissingCondition(workspace)
+	// The resume is measured from the request to run
+	workspace.Status.StartupProgress = nil
+
+	if err := sm.statusManager.UpdateComputePausedStatus(ctx, workspace, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// releaseCompute stops the workspace pod for the workspace to stop or pause: the service stops routing
+// new connections, in-flight requests get the drain period, then the deployment is deleted. It returns
+// true once the pods are gone, and otherwise the result to return, after reporting the progress through
+// updateProgress. The drain is tracked in the condition of drainConditionType.
+func (sm *StateMachine) releaseCompute(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	drainConditionType string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus,
+	updateProgress func(readiness WorkspaceStoppingReadiness) error) (bool, ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	remaining, drainErr := sm.connectionDrainRemaining(
+		ctx, workspace, FindCondition(&workspace.Status.Conditions, drainConditionType), time.Now())
+	if drainErr != nil {
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonServiceError, drainErr.Error(), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
 		}
+		return false, ctrl.Result{}, drainErr
 	}
+	if remaining > 0 {
+		logger.Info("Draining connections before stopping compute", "remaining", remaining)
+		readiness := WorkspaceStoppingReadiness{connectionsDraining: true}
+		if err := updateProgress(readiness); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	sm.requestServerShutdown(ctx, workspace)
 
-	// If EITHER deployment OR service is still in the process of being deleted
-	// Update status to Stopping and requeue to check again later
-	if deploymentDeleted || serviceDeleted {
-		logger.Info("Resources still being deleted", "deploymentDeleted", deploymentDeleted, "serviceDeleted", serviceDeleted)
-		readiness := WorkspaceStoppingReadiness{
-			computeStopped:         deploymentDeleted,
-			serviceStopped:         serviceDeleted,
-			accessResourcesStopped: accessResourcesDeleted,
+	// Ensure deployment is deleted - this is an asynchronous operation
+	// EnsureDeploymentDeleted only ensures the delete API request is accepted by K8s
+	// It does not wait for the deployment to be fully removed
+	deployment, deploymentErr := sm.resourceManager.EnsureDeploymentDeleted(ctx, workspace)
+	if deploymentErr != nil {
+		err := fmt.Errorf("failed to get deployment: %w", deploymentErr)
+		// Update error condition
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonDeploymentError, err.Error(), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
 		}
-		if err := sm.statusManager.UpdateStoppingStatus(
-			ctx, workspace, readiness, snapshotStatus); err != nil {
-			return ctrl.Result{}, err
+		return false, ctrl.Result{}, err
+	}
+	if !sm.resourceManager.IsDeploymentMissingOrDeleting(deployment) {
+		// Deletion was just issued, requeue to check its progress
+		readiness := WorkspaceStoppingReadiness{}
+		if err := updateProgress(readiness); err != nil {
+			return false, ctrl.Result{}, err
 		}
-		// Requeue to check deletion progress again later
-		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+		return false, ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
 	}
 
-	// This should not happen, return an error
-	err := fmt.Errorf("unexpected state: both deployment and service should be in deletion process")
-	// Update error condition
-	if statusErr := sm.statusManager.UpdateErrorStatus(
-		ctx, workspace, ReasonDeploymentError, err.Error(), snapshotStatus); statusErr != nil {
-		logger.Error(statusErr, "Failed to update error status")
+	// The compute is released once its pods are gone: until they terminate, they keep their
+	// resource requests, such as GPUs, and their place on the node
+	remainingPods, podsErr := sm.resourceManager.CountWorkspacePods(ctx, workspace)
+	if podsErr != nil {
+		if statusErr := sm.statusManager.UpdateErrorStatus(
+			ctx, workspace, ReasonDeploymentError, podsErr.Error(), snapshotStatus); statusErr != nil {
+			logger.Error(statusErr, "Failed to update error status")
+		}
+		return false, ctrl.Result{}, podsErr
+	}
+	if remainingPods > 0 {
+		logger.Info("Waiting for workspace pods to terminate", "pods", remainingPods)
+		sm.quitMeshSidecars(ctx, workspace)
+		readiness := WorkspaceStoppingReadiness{podsTerminating: true}
+		if err := updateProgress(readiness); err != nil {
+			return false, ctrl.Result{}, err
+		}
+		return false, ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
 	}
-	return ctrl.Result{}, err
+	return true, ctrl.Result{}, nil
 }
 
 func (sm *StateMachine) reconcileDesiredRunningStatus(
@@ -210,81 +486,300 @@ func (sm *StateMachine) reconcileDesiredRunningStatus(
 	logger := logf.FromContext(ctx)
 	logger.Info("Attempting to bring Workspace status to 'Running'")
 
+	if resolved, result, err := sm.resolveRunningInputs(ctx, workspace, snapshotStatus); !resolved {
+		return result, err
+	}
+	if ensured, result, err := sm.ensureRunningStorage(ctx, workspace, snapshotStatus); !ensured {
+		return result, err
+	}
+	compute, ensured, result, err := sm.ensureRunningCompute(ctx, workspace, snapshotStatus, accessStrategy)
+	if !ensured {
+		return result, err
+	}
+
+	// Check if resources are fully ready (asynchronous readiness check)
+	// For deployments, we check the Available condition and/or replica counts
+	// For services, we just check if the Service object exists
+	deploymentReady := sm.resourceManager.IsDeploymentAvailable(compute.deployment)
+	serviceReady := sm.resourceManager.IsServiceAvailable(compute.service)
+
+	// Pods the deployment cannot create because of a ResourceQuota will not appear on their own
+	if quota, ok := QuotaExceededFromDeployment(compute.deployment); ok && !deploymentReady {
+		compute.recordNames(workspace)
+		return sm.handleQuotaExceeded(ctx, workspace, quota, snapshotStatus)
+	}
+
+	// Init containers that fail until the bootstrap timeout mark the workspace failed rather than starting
+	bootstrap := sm.reconcileBootstrap(ctx, workspace)
+	if bootstrap.timedOut && !deploymentReady {
+		logger.Info("Bootstrap timed out, not marking the workspace as running")
+		compute.recordNames(workspace)
+		return ctrl.Result{}, sm.statusManager.UpdateBootstrapFailedStatus(ctx, workspace, bootstrap.message, snapshotStatus)
+	}
+
+	// Apply access strategy when compute and service resources are ready
+	if deploymentReady && serviceReady {
+		return sm.markRunning(ctx, workspace, snapshotStatus, compute.service, accessStrategy)
+	}
+	return sm.markStarting(ctx, workspace, snapshotStatus, compute, deploymentReady, serviceReady, bootstrap)
+}
+
+// runningCompute holds the deployment and service of a workspace brought to Running
+type runningCompute struct {
+	deployment *appsv1.Deployment
+	service    *corev1.Service
+}
+
+// recordNames reports the names of the deployment and service in the status of the workspace
+func (c runningCompute) recordNames(workspace *workspacev1alpha1.Workspace) {
+	workspace.Status.DeploymentName = c.deployment.GetName()
+	workspace.Status.ServiceName = c.service.GetName()
+}
+
+// resolveRunningInputs resolves the pod inputs taken from the admitted template revision and keeps the
+// ConfigMap holding the resolved post-start script. It returns false with the result to return when the
+// workspace cannot proceed.
+func (sm *StateMachine) resolveRunningInputs(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (bool, ctrl.Result, error) {
+	err := sm.resolveTemplateInputs(ctx, workspace)
+	if failed, ok := asImageResolutionFailed(err); ok {
+		result, err := sm.handleImageResolutionFailed(ctx, workspace, failed, snapshotStatus)
+		return false, result, err
+	}
+	if err == nil {
+		err = sm.resourceManager.EnsurePostStartConfigMap(ctx, workspace)
+	}
+	if conflict, ok := asChildResourceConflict(err); ok {
+		result, err := sm.handleChildResourceConflict(ctx, workspace, conflict, snapshotStatus)
+		return false, result, err
+	}
+	if err != nil {
+		return false, ctrl.Result{}, sm.reportRunningError(ctx, workspace, ReasonDeploymentError, err, snapshotStatus)
+	}
+	return true, ctrl.Result{}, nil
+}
+
+// ensureRunningStorage prepares the home volume of the workspace and ensures its PVC exists. It returns false
+// with the result to return while the storage blocks the start.
+func (sm *StateMachine) ensureRunningStorage(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (bool, ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	// Recreate an unbound PVC whose StorageClass was deleted, before the deployment holds it again
+	recreating, err := sm.recreateUnprovisionableStorage(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to recreate the unbound workspace PVC")
+		return false, ctrl.Result{}, err
+	}
+	if recreating {
+		logDecision(logger, "WaitForStorageRecreation")
+		return false, ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+	}
+
+	// The home volume of a hibernated workspace is restored from its VolumeSnapshot
+	restoreBlocked, err := sm.reconcileHibernationRestore(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to restore the hibernated workspace home volume")
+		return false, ctrl.Result{}, err
+	}
+	if restoreBlocked != nil {
+		logDecision(logger, "WaitForHibernatedStorageDeletion")
+		result, err := sm.waitForStart(ctx, workspace, restoreBlocked, PollRequeueDelay, snapshotStatus)
+		return false, result, err
+	}
+
+	// A cloned home volume is created from its source once the source is ready
+	cloneBlocked, err := sm.reconcileClone(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to clone the workspace home volume")
+		return false, ctrl.Result{}, err
+	}
+	if cloneBlocked != nil {
+		logDecision(logger, "WaitForCloneSource")
+		result, err := sm.waitForStart(ctx, workspace, cloneBlocked, CloneRequeueDelay, snapshotStatus)
+		return false, result, err
+	}
+
+	// The pod is not started while a shared volume of the template is missing
+	sharedVolumeBlocked, err := sm.reconcileSharedVolumes(ctx, workspace)
+	if err != nil {
+		logger.Error(err, "Failed to look up the shared volumes of the template")
+		return false, ctrl.Result{}, err
+	}
+	if sharedVolumeBlocked != nil {
+		logDecision(logger, "WaitForSharedVolume")
+		result, err := sm.waitForStart(ctx, workspace, sharedVolumeBlocked, SharedVolumeRequeueDelay, snapshotStatus)
+		return false, result, err
+	}
+
+	// Report whether the home directory outlives the pod, so that users do not expect it to persist
+	workspace.Status.Storage = StorageModeOf(workspace)
+
 	// Ensure PVC exists first (if storage is configured)
-	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	if quota, ok := QuotaExceededFromError(err); ok {
+		result, err := sm.handleQuotaExceeded(ctx, workspace, quota, snapshotStatus)
+		return false, result, err
+	}
+	if conflict, ok := asChildResourceConflict(err); ok {
+		result, err := sm.handleChildResourceConflict(ctx, workspace, conflict, snapshotStatus)
+		return false, result, err
+	}
 	if err != nil {
 		pvcErr := fmt.Errorf("failed to ensure PVC exists: %w", err)
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, pvcErr.Error(), snapshotStatus); statusErr != nil {
-			logger.Error(statusErr, "Failed to update error status")
-		}
-		return ctrl.Result{}, pvcErr
+		return false, ctrl.Result{}, sm.reportRunningError(ctx, workspace, ReasonDeploymentError, pvcErr, snapshotStatus)
+	}
+
+	// Track the expansion of the PVC after its storage size was increased
+	sm.reconcileStorageResizing(ctx, workspace, pvc)
+
+	// Report a PVC whose StorageClass was deleted cluster-side
+	sm.reconcileStorageDegraded(ctx, workspace, pvc)
+	return true, ctrl.Result{}, nil
+}
+
+// ensureRunningCompute ensures the envFrom sources, the deployment and the service of the workspace. It returns
+// false with the result to return when they cannot be ensured.
+func (sm *StateMachine) ensureRunningCompute(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (runningCompute, bool, ctrl.Result, error) {
+	// Check the envFrom sources before the pod references them: a missing one fails the container
+	err := sm.resourceManager.EnsureEnvFromSources(ctx, workspace)
+	if missing, ok := asEnvFromSourceMissing(err); ok {
+		result, err := sm.handleEnvFromSourceMissing(ctx, workspace, missing, snapshotStatus)
+		return runningCompute{}, false, result, err
+	}
+	if conflict, ok := asChildResourceConflict(err); ok {
+		result, err := sm.handleChildResourceConflict(ctx, workspace, conflict, snapshotStatus)
+		return runningCompute{}, false, result, err
+	}
+	if err != nil {
+		envFromErr := fmt.Errorf("failed to ensure envFrom sources: %w", err)
+		return runningCompute{}, false, ctrl.Result{},
+			sm.reportRunningError(ctx, workspace, ReasonDeploymentError, envFromErr, snapshotStatus)
 	}
 
 	// EnsureDeploymentExists creates deployment if missing, or returns existing deployment
 	deployment, err := sm.resourceManager.EnsureDeploymentExists(ctx, workspace, accessStrategy)
+	if conflict, ok := asChildResourceConflict(err); ok {
+		result, err := sm.handleChildResourceConflict(ctx, workspace, conflict, snapshotStatus)
+		return runningCompute{}, false, result, err
+	}
 	if err != nil {
 		deployErr := fmt.Errorf("failed to ensure deployment exists: %w", err)
-		// Update error condition
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonDeploymentError, deployErr.Error(), snapshotStatus); statusErr != nil {
-			logger.Error(statusErr, "Failed to update error status")
-		}
-		return ctrl.Result{}, deployErr
+		return runningCompute{}, false, ctrl.Result{},
+			sm.reportRunningError(ctx, workspace, ReasonDeploymentError, deployErr, snapshotStatus)
 	}
 
 	// Ensure service exists
 	// EnsureServiceExists internally fetches the service and returns it with current status
 	service, err := sm.resourceManager.EnsureServiceExists(ctx, workspace)
+	if conflict, ok := asChildResourceConflict(err); ok {
+		result, err := sm.handleChildResourceConflict(ctx, workspace, conflict, snapshotStatus)
+		return runningCompute{}, false, result, err
+	}
 	if err != nil {
 		serviceErr := fmt.Errorf("failed to ensure service exists: %w", err)
-		// Update error condition
-		if statusErr := sm.statusManager.UpdateErrorStatus(
-			ctx, workspace, ReasonServiceError, serviceErr.Error(), snapshotStatus); statusErr != nil {
-			logger.Error(statusErr, "Failed to update error status")
-		}
-		return ctrl.Result{}, serviceErr
+		return runningCompute{}, false, ctrl.Result{},
+			sm.reportRunningError(ctx, workspace, ReasonServiceError, serviceErr, snapshotStatus)
 	}
+	return runningCompute{deployment: deployment, service: service}, true, ctrl.Result{}, nil
+}
 
-	// Check if resources are fully ready (asynchronous readiness check)
-	// For deployments, we check the Available condition and/or replica counts
-	// For services, we just check if the Service object exists
-	deploymentReady := sm.resourceManager.IsDeploymentAvailable(deployment)
-	serviceReady := sm.resourceManager.IsServiceAvailable(service)
+// markRunning gives access to the workspace whose deployment and service are ready, then updates it to Running
+func (sm *StateMachine) markRunning(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus,
+	service *corev1.Service,
+	accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
 
-	// Apply access strategy when compute and service resources are ready
-	if deploymentReady && serviceReady {
-		// ReconcileAccess returns nil (no error) only when it successfully initiated
-		// the creation of all AccessRessources.
-		// TODO: add probe and requeue https://github.com/jupyter-infra/jupyter-k8s/issues/36
-		if err := sm.ReconcileAccessForDesiredRunningStatus(ctx, workspace, service, accessStrategy); err != nil {
-			return ctrl.Result{}, err
-		}
+	// The template startup check gates the workspace becoming available under FailurePolicy Fail
+	startupCheck, err := sm.reconcileStartupCheck(ctx, workspace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if startupCheck.failed {
+		logger.Info("Startup check failed, not marking the workspace as running")
+		return ctrl.Result{}, sm.statusManager.UpdateStartupCheckFailedStatus(ctx, workspace, startupCheck.message, snapshotStatus)
+	}
 
-		// Then only update to running status
-		logger.Info("Deployment and Service are both ready, updating to Running status")
+	// A failed post-start script is reported; the workspace runs anyway
+	if err := sm.reconcilePostStartScript(ctx, workspace); err != nil {
+		return ctrl.Result{}, err
+	}
 
-		// Record workspace running event
-		sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceRunning", "Workspace is now running")
+	// ReconcileAccess returns nil (no error) only when it successfully initiated
+	// the creation of all AccessRessources.
+	// TODO: add probe and requeue https://github.com/jupyter-infra/jupyter-k8s/issues/36
+	if err := sm.ReconcileAccessForDesiredRunningStatus(ctx, workspace, service, accessStrategy); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Then only update to running status
+	logger.Info("Deployment and Service are both ready, updating to Running status")
+
+	// Record workspace running event
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "WorkspaceRunning", "Workspace is now running")
 
-		if err := sm.statusManager.UpdateRunningStatus(ctx, workspace, snapshotStatus); err != nil {
+	// Record the start of a workspace becoming available
+	if !sm.statusManager.IsWorkspaceAvailable(workspace) {
+		startedAt := metav1.Now()
+		workspace.Status.LastStartTime = &startedAt
+		clearIdleShutdownCondition(workspace)
+		if err := sm.clearPreemption(ctx, workspace); err != nil {
 			return ctrl.Result{}, err
 		}
+	}
+
+	clearImagePullFailedCondition(workspace)
+	sm.reconcileStartupProgress(ctx, workspace, true)
+	sm.reconcileStartupTimeout(ctx, workspace, true, time.Now())
 
-		// Handle idle shutdown for running workspaces
-		return sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	if err := sm.statusManager.UpdateRunningStatus(ctx, workspace, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// Resources are being created/started but not fully ready yet
-	// Update status to Starting and requeue to check again later
-	logger.Info("Resources not fully ready", "deploymentReady", deploymentReady, "serviceReady", serviceReady)
-	workspace.Status.DeploymentName = deployment.GetName()
-	workspace.Status.ServiceName = service.GetName()
+	// Handle idle shutdown for running workspaces
+	result, err := sm.handleIdleShutdownForRunningWorkspace(ctx, workspace)
+	return withEnvFromResync(workspace, result), err
+}
+
+// markStarting updates the workspace whose resources are being created or started to Starting, and requeues
+// it to check their readiness again later
+func (sm *StateMachine) markStarting(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus,
+	compute runningCompute,
+	deploymentReady, serviceReady bool,
+	bootstrap bootstrapOutcome) (ctrl.Result, error) {
+	logf.FromContext(ctx).Info("Resources not fully ready", "deploymentReady", deploymentReady, "serviceReady", serviceReady)
+	compute.recordNames(workspace)
 	readiness := WorkspaceRunningReadiness{
 		computeReady:         deploymentReady,
 		serviceReady:         serviceReady,
 		accessResourcesReady: false,
 	}
+	if !deploymentReady {
+		readiness.computeNotReadyReason, readiness.computeNotReadyMessage = sm.diagnoseComputeNotReady(ctx, workspace)
+		if readiness.computeNotReadyReason == "" && bootstrap.failed {
+			readiness.computeNotReadyReason, readiness.computeNotReadyMessage = ReasonInitContainerFailed, bootstrap.message
+		}
+	} else {
+		clearImagePullFailedCondition(workspace)
+	}
+	// Only a ready server is published: Jupyter answers 503 until it has loaded its extensions
+	workspace.Status.URL = ""
+	sm.reconcileStartupProgress(ctx, workspace, false)
+	sm.reconcileStartupTimeout(ctx, workspace, false, time.Now())
 	if err := sm.statusManager.UpdateStartingStatus(
 		ctx, workspace, readiness, snapshotStatus); err != nil {
 		return ctrl.Result{}, err
@@ -294,6 +789,124 @@ func (sm *StateMachine) reconcileDesiredRunningStatus(
 	return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
 }
 
+// waitForStart reports the condition blocking the start of the workspace in its Starting status, and requeues
+// it after delay
+func (sm *StateMachine) waitForStart(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	blocked *metav1.Condition,
+	delay time.Duration,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	readiness := WorkspaceRunningReadiness{
+		computeNotReadyReason:  blocked.Reason,
+		computeNotReadyMessage: blocked.Message,
+	}
+	if err := sm.statusManager.UpdateStartingStatus(ctx, workspace, readiness, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: delay}, nil
+}
+
+// reportRunningError records err in the error condition of the workspace with reason, and returns err
+func (sm *StateMachine) reportRunningError(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	reason string,
+	err error,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	if statusErr := sm.statusManager.UpdateErrorStatus(
+		ctx, workspace, reason, err.Error(), snapshotStatus); statusErr != nil {
+		logf.FromContext(ctx).Error(statusErr, "Failed to update error status")
+	}
+	return err
+}
+
+// handleQuotaExceeded reports a ResourceQuota denial to the user and backs off: quota rarely frees up
+// quickly, and the ResourceQuota watch requeues the workspace as soon as headroom appears
+func (sm *StateMachine) handleQuotaExceeded(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	quota *QuotaExceeded,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	message := quota.Message(workspace.Namespace)
+	logger.Info("Workspace blocked by namespace ResourceQuota", "resourceQuota", quota.QuotaName, "message", message)
+
+	// Only emit the event when the denial is new or changed, not on every retry
+	if existing := FindCondition(&workspace.Status.Conditions, ConditionTypeQuotaExceeded); existing == nil ||
+		existing.Status != metav1.ConditionTrue || existing.Message != message {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonQuotaExceeded, message)
+	}
+
+	if err := sm.statusManager.UpdateQuotaExceededStatus(ctx, workspace, message, snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: QuotaExceededRequeueDelay}, nil
+}
+
+// handleChildResourceConflict reports a child resource name taken by a foreign object. Retrying
+// quickly cannot help: someone has to remove or rename the conflicting object.
+func (sm *StateMachine) handleChildResourceConflict(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	conflict *ChildResourceConflictError,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	logger.Info("Workspace child resource conflicts with an existing object", "conflict", conflict.Error())
+
+	sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonResourceConflict, conflict.Error())
+	if err := sm.statusManager.UpdateErrorStatus(
+		ctx, workspace, ReasonResourceConflict, conflict.Error(), snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+}
+
+// handleImageResolutionFailed reports an image tag the registry could not resolve to a digest. The workspace
+// pod is not created until the image resolves; the error is returned so that the resolution is retried with
+// the backoff of the controller.
+func (sm *StateMachine) handleImageResolutionFailed(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	failed *ImageResolutionFailedError,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logf.FromContext(ctx).Info("Workspace image could not be resolved to a digest", "image", failed.Image, "error", failed.Err.Error())
+
+	// Only emit the event when the failure is new or changed, not on every retry
+	if existing := FindCondition(&workspace.Status.Conditions, ConditionTypeImageResolutionFailed); existing == nil ||
+		existing.Status != metav1.ConditionTrue || existing.Message != failed.Error() {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonImageResolutionFailed, failed.Error())
+	}
+
+	if err := sm.statusManager.UpdateImageResolutionFailedStatus(ctx, workspace, failed.Error(), snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, failed
+}
+
+// handleEnvFromSourceMissing reports a required envFrom source that does not exist. Sources are not
+// watched, so the workspace is retried after a long delay.
+func (sm *StateMachine) handleEnvFromSourceMissing(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	missing *EnvFromSourceMissingError,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	logger.Info("Workspace envFrom source does not exist", "source", missing.Source.String())
+
+	// Only emit the event when the missing source is new, not on every retry
+	if existing := FindCondition(&workspace.Status.Conditions, ConditionTypeDegraded); existing == nil ||
+		existing.Reason != ReasonEnvFromSourceMissing || existing.Message != missing.Error() {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, ReasonEnvFromSourceMissing, missing.Error())
+	}
+
+	if err := sm.statusManager.UpdateErrorStatus(
+		ctx, workspace, ReasonEnvFromSourceMissing, missing.Error(), snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+}
+
 // handleIdleShutdownForRunningWorkspace handles idle shutdown logic for running workspaces
 func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 	ctx context.Context,
@@ -328,9 +941,27 @@ func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 		return ctrl.Result{RequeueAfter: IdleCheckInterval}, nil
 	}
 
-	result, err := sm.idleChecker.CheckWorkspaceIdle(ctx, workspace, idleConfig)
+	// The probe runs in the background; its outcome is picked up by a later reconcile
+	outcome, ok := sm.idleProbes.take(client.ObjectKeyFromObject(workspace), IdleCheckInterval)
+	if !ok {
+		// The culling status written by the last evaluation requeues the workspace: wait for the next check
+		now := time.Now()
+		if culling := workspace.Status.Culling; culling != nil && now.Sub(culling.EvaluatedAt.Time) < IdleCheckInterval {
+			return ctrl.Result{RequeueAfter: IdleCheckInterval - now.Sub(culling.EvaluatedAt.Time)}, nil
+		}
+		sm.idleProbes.submit(ctx, workspace, idleConfig)
+		logger.V(1).Info("Submitted idle probe", "timeout", IdleProbeTimeout)
+		return ctrl.Result{RequeueAfter: IdleProbeTimeout}, nil
+	}
+
+	now := time.Now()
+	result, err := outcome.result, outcome.err
 	if err != nil {
-		if !result.ShouldRetry {
+		culling := newCullingStatus(idleConfig, result, workspacev1alpha1.CullingDecisionCheckFailed, "", err.Error(), now)
+		if err := sm.statusManager.UpdateCullingStatus(ctx, workspace, time.Time{}, culling); err != nil {
+			logger.Error(err, "Failed to record the culling status")
+		}
+		if result == nil || !result.ShouldRetry {
 			logger.Error(err, "Permanent failure checking idle status, disabling idle shutdown for this workspace")
 			return ctrl.Result{}, nil // No requeue - permanent failure
 		}
@@ -338,10 +969,18 @@ func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 		logger.Error(err, "Temporary failure checking idle status, will retry")
 	} else {
 		logger.V(1).Info("Successfully checked idle status", "isIdle", result.IsIdle)
-		if result.IsIdle {
-			logger.Info("Workspace idle timeout reached, stopping workspace",
-				"timeout", idleConfig.IdleTimeoutInMinutes)
-			return sm.stopWorkspaceDueToIdle(ctx, workspace, idleConfig)
+		decision := decideIdleShutdown(workspace, idleConfig, result, now)
+		cullingDecision, message := sm.cullingOutcome(workspace, decision)
+		culling := newCullingStatus(idleConfig, result, cullingDecision, decision.reason, message, now)
+		sm.reportCullingOutcome(workspace, workspace.Status.Culling, culling, now)
+		if err := sm.statusManager.UpdateCullingStatus(ctx, workspace, result.LastActivity, culling); err != nil {
+			logger.Error(err, "Failed to record the culling status")
+		}
+		logDecision(logger, "EvaluateCulling", "decision", cullingDecision, "rule", decision.reason,
+			"timeout", idleConfig.IdleTimeoutInMinutes, "neverConnectedTimeout", idleConfig.NeverConnectedTimeoutInMinutes)
+		if cullingDecision == workspacev1alpha1.CullingDecisionCull {
+			logger.Info("Workspace idle shutdown rule reached, stopping workspace", "reason", decision.reason)
+			return sm.stopWorkspaceDueToIdle(ctx, workspace, decision)
 		}
 	}
 
@@ -350,23 +989,41 @@ func (sm *StateMachine) handleIdleShutdownForRunningWorkspace(
 	return ctrl.Result{RequeueAfter: IdleCheckInterval}, nil
 }
 
-// stopWorkspaceDueToIdle stops the workspace due to idle timeout
-func (sm *StateMachine) stopWorkspaceDueToIdle(ctx context.Context, workspace *workspacev1alpha1.Workspace, idleConfig *workspacev1alpha1.IdleShutdownSpec) (ctrl.Result, error) {
+// stopWorkspaceDueToIdle stops the workspace due to the idle shutdown rule of the decision
+func (sm *StateMachine) stopWorkspaceDueToIdle(ctx context.Context, workspace *workspacev1alpha1.Workspace, decision idleShutdownDecision) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name)
 
 	// Record event
-	sm.recorder.Event(workspace, corev1.EventTypeNormal, "IdleShutdown",
-		fmt.Sprintf("Stopping workspace due to idle timeout of %d minutes", idleConfig.IdleTimeoutInMinutes))
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, "IdleShutdown", decision.message)
 
-	// Update desired status to trigger stop
+	// Record the culler intent and update desired status to trigger stop
+	cullerIntent, err := EncodeDesiredStatusIntent(DesiredStatusIntentRecord{
+		DesiredStatus: DesiredStateStopped,
+		SetAt:         &metav1.Time{Time: time.Now()},
+	})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	original := workspace.DeepCopy()
+	if workspace.Annotations == nil {
+		workspace.Annotations = map[string]string{}
+	}
+	workspace.Annotations[AnnotationCullerIntent] = cullerIntent
 	workspace.Spec.DesiredStatus = DesiredStateStopped
-	if err := sm.resourceManager.client.Update(ctx, workspace); err != nil {
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
 		logger.Error(err, "Failed to update workspace desired status")
 		return ctrl.Result{}, err
 	}
 
 	logger.Info("Updated workspace desired status to Stopped")
 
+	// Record which rule stopped the workspace
+	snapshotStatus := workspace.Status.DeepCopy()
+	setIdleShutdownCondition(workspace, decision)
+	if err := sm.statusManager.updateStatus(ctx, workspace, &[]metav1.Condition{}, snapshotStatus); err != nil {
+		logger.Error(err, "Failed to record idle shutdown condition")
+	}
+
 	// Requeue after a minimal wait
 	return ctrl.Result{RequeueAfter: MinimalRequeueDelay}, nil
 }
@@ -411,27 +1068,69 @@ func (sm *StateMachine) ReconcileDeletion(ctx context.Context, workspace *worksp
 		return ctrl.Result{}, nil
 	}
 
-	// Update status to Deleting
-	if err := sm.statusManager.UpdateDeletingStatus(ctx, workspace); err != nil {
-		logger.Error(err, "Failed to update deleting status")
+	snapshotStatus := workspace.DeepCopy().Status
+
+	// Look up the resources of the workspace under the names they were created with
+	if err := sm.resourceManager.ResolveChildNamePrefix(ctx, workspace); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Clean up all workspace resources via resource manager
-	allDeleted, err := sm.resourceManager.CleanupAllResources(ctx, workspace)
-	if err != nil {
-		logger.Error(err, "Failed to cleanup workspace resources")
-		return ctrl.Result{}, err
+	// Run the finalization steps in order, resuming from the step recorded in the Terminating condition.
+	// A step only completes once its resources are confirmed absent, not merely once deletion is issued.
+	steps := sm.finalizationSteps()
+	for i := resumeFinalizationStep(workspace, steps); i < len(steps); i++ {
+		step := steps[i]
+		done, err := step.run(ctx, workspace)
+		if err != nil {
+			logger.Error(err, "Finalization step failed", "step", step.reason)
+			message := fmt.Sprintf("Failed to delete %s: %v", step.resource, err)
+			if statusErr := sm.statusManager.UpdateTerminatingStatus(
+				ctx, workspace, step.reason, message, &snapshotStatus); statusErr != nil {
+				logger.Error(statusErr, "Failed to update terminating status")
+			}
+			return ctrl.Result{}, err
+		}
+		if !done {
+			logger.Info("Waiting for resources to be deleted", "step", step.reason)
+			message := fmt.Sprintf("Waiting for %s to be deleted", step.resource)
+			if step.waitingMessage != "" {
+				message = step.waitingMessage
+			}
+			requeueAfter := PollRequeueDelay
+			if step.progress != nil {
+				message, requeueAfter = step.progress(workspace)
+			}
+			if err := sm.statusManager.UpdateTerminatingStatus(
+				ctx, workspace, step.reason, message, &snapshotStatus); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
 	}
-	if !allDeleted {
-		logger.Info("Resources still being deleted, will retry")
+
+	// Confirm every child resource is absent before releasing the finalizer,
+	// and start over if anything reappeared since its step completed
+	if !sm.resourceManager.AreAllResourcesDeleted(ctx, workspace) {
+		logger.Info("Workspace resources still present after finalization steps, restarting finalization")
+		if err := sm.statusManager.UpdateTerminatingStatus(
+			ctx, workspace, steps[0].reason, "Restarting finalization", &snapshotStatus); err != nil {
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
 	}
 
+	// Record the last step before removing the finalizer, so a restarted controller resumes there
+	if err := sm.statusManager.UpdateTerminatingStatus(
+		ctx, workspace, ReasonRemovingFinalizer, "All workspace resources confirmed deleted", &snapshotStatus); err != nil {
+		logger.Error(err, "Failed to update terminating status")
+		return ctrl.Result{}, err
+	}
+
 	// All resources cleaned up, remove finalizer to allow deletion
 	logger.Info("All resources cleaned up, removing finalizer")
+	original := workspace.DeepCopy()
 	controllerutil.RemoveFinalizer(workspace, WorkspaceFinalizerName)
-	if err := sm.resourceManager.client.Update(ctx, workspace); err != nil {
+	if err := workspaceutil.PatchChanges(ctx, sm.resourceManager.client, workspace, original); err != nil {
 		logger.Error(err, "Failed to remove finalizer")
 		return ctrl.Result{}, err
 	}
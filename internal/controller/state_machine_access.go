@@ -7,6 +7,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -40,6 +41,7 @@ func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 		workspace.Status.AccessURL = accessUrl
 		workspace.Status.AccessResourceSelector = sm.resourceManager.accessResourcesBuilder.ResolveAccessResourceSelector(
 			workspace, accessStrategy)
+		setConnectionStatus(workspace, service)
 		return nil
 	}
 	// END OF CASE 1
@@ -47,6 +49,7 @@ func (sm *StateMachine) ReconcileAccessForDesiredRunningStatus(
 	// CASE 2: there is no AccessStrategy (it may have been removed by an update)
 	workspace.Status.AccessURL = ""
 	workspace.Status.AccessResourceSelector = ""
+	setConnectionStatus(workspace, service)
 
 	err := sm.resourceManager.EnsureAccessResourcesDeleted(ctx, workspace)
 	if err != nil {
@@ -62,6 +65,8 @@ func (sm *StateMachine) ReconcileAccessForDesiredStoppedStatus(ctx context.Conte
 
 	workspace.Status.AccessURL = ""
 	workspace.Status.AccessResourceSelector = ""
+	workspace.Status.URL = ""
+	workspace.Status.Connection = nil
 
 	err := sm.resourceManager.EnsureAccessResourcesDeleted(ctx, workspace)
 	if err != nil {
@@ -70,3 +75,23 @@ func (sm *StateMachine) ReconcileAccessForDesiredStoppedStatus(ctx context.Conte
 	}
 	return nil
 }
+
+// setConnectionStatus publishes how to reach the running workspace through its Service: status.url is the
+// AccessURL of its access strategy, or else the in-cluster DNS name of the Service, which port-forwarding
+// reaches from outside the cluster
+func setConnectionStatus(workspace *workspacev1alpha1.Workspace, service *corev1.Service) {
+	port := int32(JupyterPort)
+	if len(service.Spec.Ports) > 0 {
+		port = service.Spec.Ports[0].Port
+	}
+	workspace.Status.Connection = &workspacev1alpha1.WorkspaceConnectionStatus{
+		ServiceName: service.Name,
+		Port:        port,
+		PortForwardCommand: fmt.Sprintf("kubectl port-forward -n %s service/%s %d:%d",
+			service.Namespace, service.Name, port, port),
+	}
+	workspace.Status.URL = workspace.Status.AccessURL
+	if workspace.Status.URL == "" {
+		workspace.Status.URL = fmt.Sprintf("http://%s.%s.svc:%d", service.Name, service.Namespace, port)
+	}
+}
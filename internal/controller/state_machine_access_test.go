@@ -0,0 +1,55 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newConnectionTestService(workspace *workspacev1alpha1.Workspace) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: serviceNameFor(workspace), Namespace: workspace.Namespace},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: JupyterPort}}},
+	}
+}
+
+func TestWorkspaceWithoutAccessStrategyPublishesItsServiceDNSName(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	service := newConnectionTestService(workspace)
+
+	require.NoError(t, sm.ReconcileAccessForDesiredRunningStatus(ctx, workspace, service, nil))
+	assert.Equal(t, "http://"+service.Name+".team-a.svc:8888", workspace.Status.URL)
+	assert.Equal(t, &workspacev1alpha1.WorkspaceConnectionStatus{
+		ServiceName:        service.Name,
+		Port:               8888,
+		PortForwardCommand: "kubectl port-forward -n team-a service/" + service.Name + " 8888:8888",
+	}, workspace.Status.Connection)
+
+	// Stopping the workspace withdraws the connection
+	require.NoError(t, sm.ReconcileAccessForDesiredStoppedStatus(ctx, workspace))
+	assert.Empty(t, workspace.Status.URL)
+	assert.Nil(t, workspace.Status.Connection)
+}
+
+func TestConnectionURLIsTheAccessURLOfTheAccessStrategy(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Status.AccessURL = "https://jupyter.example.com/workspaces/team-a/ws/"
+
+	setConnectionStatus(workspace, newConnectionTestService(workspace))
+	assert.Equal(t, "https://jupyter.example.com/workspaces/team-a/ws/", workspace.Status.URL)
+	assert.Equal(t, int32(8888), workspace.Status.Connection.Port)
+}
@@ -9,10 +9,10 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -41,6 +41,9 @@ func (sm *StatusManager) updateStatus(
 		// requesting to modify condition: overwrite
 		workspace.Status.Conditions = *conditionsToUpdate
 	}
+	// The blocked reason rolls up the conditions: derive it on every write so that it never lags behind them
+	workspace.Status.BlockedReason, workspace.Status.BlockedMessage = GetWorkspaceBlockedReason(workspace)
+	setPhase(workspace)
 
 	if reflect.DeepEqual(workspace.Status, snapshotStatus) {
 		// no-op: status hasn't changed
@@ -69,6 +72,9 @@ type WorkspaceRunningReadiness struct {
 	computeReady         bool
 	serviceReady         bool
 	accessResourcesReady bool
+	// computeNotReadyReason and computeNotReadyMessage explain why the compute is not ready, when known
+	computeNotReadyReason  string
+	computeNotReadyMessage string
 }
 
 // UpdateStartingStatus sets Available to false and Progressing to true
@@ -102,6 +108,12 @@ func (sm *StatusManager) UpdateStartingStatus(
 	}
 	// Nothing ready corresponds to the default
 
+	// A known obstacle to the compute readiness tells more than which resources are not ready
+	if !readiness.computeReady && readiness.computeNotReadyReason != "" {
+		startingReason = readiness.computeNotReadyReason
+		startingMessage = readiness.computeNotReadyMessage
+	}
+
 	availableCondition := NewCondition(
 		ConditionTypeAvailable,
 		metav1.ConditionFalse,
@@ -140,6 +152,8 @@ func (sm *StatusManager) UpdateStartingStatus(
 		stoppedCondition,
 	}
 
+	conditions = appendQuotaClearedCondition(workspace, conditions)
+	conditions = appendPauseClearedCondition(workspace, conditions, ReasonDesiredStateRunning)
 	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
@@ -207,12 +221,16 @@ func (sm *StatusManager) UpdateRunningStatus(
 		stoppedCondition,
 	}
 
+	conditions = appendQuotaClearedCondition(workspace, conditions)
+	conditions = appendPauseClearedCondition(workspace, conditions, ReasonDesiredStateRunning)
 	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
 
 // WorkspaceStoppingReadiness wraps the readiness flag of underlying components
 type WorkspaceStoppingReadiness struct {
+	connectionsDraining    bool
+	podsTerminating        bool
 	computeStopped         bool
 	serviceStopped         bool
 	accessResourcesStopped bool
@@ -224,29 +242,30 @@ func (sm *StatusManager) UpdateStoppingStatus(
 	workspace *workspacev1alpha1.Workspace,
 	readiness WorkspaceStoppingReadiness,
 	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
-	// default: nothing is stopped yet
-	stoppingReason := ReasonResourcesNotStopped
-	stoppingMessage := "Resources are still running"
-
 	if readiness.computeStopped && readiness.serviceStopped && readiness.accessResourcesStopped {
 		return fmt.Errorf("invalid call: not all resources should be stopped in method UpdateStoppingStatus")
 	}
 
-	waitingForCompute := !readiness.computeStopped && readiness.serviceStopped && readiness.accessResourcesStopped
-	waitingForService := readiness.computeStopped && !readiness.serviceStopped && readiness.accessResourcesStopped
-	waitingForAccess := readiness.computeStopped && readiness.serviceStopped && !readiness.accessResourcesStopped
-
-	if waitingForCompute {
+	// Resources stop in order: connections drain, then compute, access and the service stop.
+	// The reason names the step in progress.
+	var stoppingReason, stoppingMessage string
+	switch {
+	case readiness.connectionsDraining:
+		stoppingReason = ReasonDrainingConnections
+		stoppingMessage = "Waiting for in-flight requests to complete"
+	case readiness.podsTerminating:
+		stoppingReason = ReasonComputeNotStopped
+		stoppingMessage = "Waiting for the workspace pods to terminate"
+	case !readiness.computeStopped:
 		stoppingReason = ReasonComputeNotStopped
 		stoppingMessage = "Compute is still running"
-	} else if waitingForAccess {
+	case !readiness.accessResourcesStopped:
 		stoppingReason = ReasonAccessNotStopped
 		stoppingMessage = "Access is still up"
-	} else if waitingForService {
+	default:
 		stoppingReason = ReasonServiceNotStopped
 		stoppingMessage = "Service is still up"
 	}
-	// Nothing stopped corresponds to the default
 
 	// ensure AvailableCondition is set to false with ReasonDesiredStateStopped
 	availableCondition := NewCondition(
@@ -288,6 +307,7 @@ func (sm *StatusManager) UpdateStoppingStatus(
 		stoppedCondition,
 	}
 
+	conditions = appendPauseClearedCondition(workspace, conditions, ReasonDesiredStateStopped)
 	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
@@ -299,7 +319,7 @@ func (sm *StatusManager) UpdateStoppedStatus(
 	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
 	// Check if workspace was stopped due to preemption
 	var availableCondition metav1.Condition
-	if workspace.Annotations != nil && workspace.Annotations[PreemptionReasonAnnotation] == PreemptedReason {
+	if isPreempted(workspace) {
 		availableCondition = NewCondition(
 			ConditionTypeAvailable,
 			metav1.ConditionFalse,
@@ -347,7 +367,12 @@ func (sm *StatusManager) UpdateStoppedStatus(
 		degradedCondition,
 		stoppedCondition,
 	}
+	if isPreempted(workspace) {
+		conditions = append(conditions, newPreemptedCondition(workspace))
+	}
 
+	conditions = appendQuotaClearedCondition(workspace, conditions)
+	conditions = appendPauseClearedCondition(workspace, conditions, ReasonDesiredStateStopped)
 	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
 
 	// Clear resource names since all workspace resources have been deleted at this point.
@@ -357,16 +382,257 @@ func (sm *StatusManager) UpdateStoppedStatus(
 	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
 }
 
-// UpdateDeletingStatus sets the workspace status to indicate deletion in progress
-func (sm *StatusManager) UpdateDeletingStatus(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
-	condition := metav1.Condition{
-		Type:               "Deleting",
-		Status:             metav1.ConditionTrue,
-		Reason:             "DeletionInProgress",
-		Message:            "Workspace resources are being deleted",
-		LastTransitionTime: metav1.Now(),
+// UpdatePausingStatus sets Available to false and Progressing to true while the workspace pod stops
+// for the workspace to pause
+func (sm *StatusManager) UpdatePausingStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	readiness WorkspaceStoppingReadiness,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	pausingReason := ReasonComputeNotStopped
+	pausingMessage := "Compute is still running"
+	switch {
+	case readiness.connectionsDraining:
+		pausingReason = ReasonDrainingConnections
+		pausingMessage = "Waiting for in-flight requests to complete"
+	case readiness.podsTerminating:
+		pausingMessage = "Waiting for the workspace pods to terminate"
+	}
+
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonDesiredStatePaused, "Desired status is Paused"),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionTrue, ReasonDesiredStatePaused, pausingMessage),
+		NewCondition(ConditionTypeDegraded, metav1.ConditionFalse, ReasonNoError, "No errors detected"),
+		NewCondition(ConditionTypeStopped, metav1.ConditionFalse, ReasonDesiredStatePaused, "Workspace is pausing"),
+		NewCondition(ConditionTypePaused, metav1.ConditionFalse, pausingReason, pausingMessage),
+	}
+
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateComputePausedStatus sets Available and Progressing to false and Paused to true once the workspace
+// pod is gone. Unlike UpdatePausedStatus, which reports a paused reconciliation, the workspace is paused
+// by its desired status; the service name is kept as the service is.
+func (sm *StatusManager) UpdateComputePausedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonDesiredStatePaused, "Workspace is paused"),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionFalse, ReasonDesiredStatePaused, "Workspace is paused"),
+		NewCondition(ConditionTypeDegraded, metav1.ConditionFalse, ReasonNoError, "No errors detected"),
+		NewCondition(ConditionTypeStopped, metav1.ConditionFalse, ReasonDesiredStatePaused, "Workspace is paused"),
+		NewCondition(ConditionTypePaused, metav1.ConditionTrue, ReasonComputePaused,
+			"Workspace pod is stopped; the service, access resources and storage are kept"),
+	}
+
+	conditions = appendQuotaClearedCondition(workspace, conditions)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+
+	// The deployment is gone; the service still routes to the workspace once it resumes
+	workspace.Status.DeploymentName = ""
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateLastActivityTime records the last activity reported for the workspace, when it moved enough,
+// and the first connection to the workspace
+func (sm *StatusManager) UpdateLastActivityTime(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	lastActivity time.Time) error {
+	snapshotStatus := workspace.DeepCopy().Status
+	advanced := advanceLastActivityTime(workspace, lastActivity)
+	if connected := recordFirstConnection(workspace, lastActivity); !advanced && !connected {
+		return nil
 	}
+	return sm.updateStatus(ctx, workspace, &[]metav1.Condition{}, &snapshotStatus)
+}
+
+// UpdateCullingStatus records the last evaluation of the idle shutdown rules of the workspace, with the last
+// activity and the first connection it reported
+func (sm *StatusManager) UpdateCullingStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	lastActivity time.Time,
+	culling *workspacev1alpha1.CullingStatus) error {
+	snapshotStatus := workspace.DeepCopy().Status
+	advanceLastActivityTime(workspace, lastActivity)
+	recordFirstConnection(workspace, lastActivity)
+	workspace.Status.Culling = culling
+	return sm.updateStatus(ctx, workspace, &[]metav1.Condition{}, &snapshotStatus)
+}
+
+// UpdatePausedStatus sets the ReconciliationPaused condition to true, leaving other conditions untouched
+func (sm *StatusManager) UpdatePausedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	pausedCondition := NewCondition(
+		ConditionTypeReconciliationPaused,
+		metav1.ConditionTrue,
+		ReasonPausedByAnnotation,
+		message,
+	)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{pausedCondition})
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateResumedStatus sets the ReconciliationPaused condition to false
+func (sm *StatusManager) UpdateResumedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	resumedCondition := NewCondition(
+		ConditionTypeReconciliationPaused,
+		metav1.ConditionFalse,
+		ReasonReconciliationResumed,
+		"Reconciliation resumed",
+	)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{resumedCondition})
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateValidationFailedStatus parks the workspace with the ValidationFailed condition set to true
+func (sm *StatusManager) UpdateValidationFailedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	violation string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	failedCondition := NewCondition(
+		ConditionTypeValidationFailed,
+		metav1.ConditionTrue,
+		ReasonRevalidationFailed,
+		fmt.Sprintf("Reconciliation is parked until the workspace passes validation: %s", violation),
+	)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{failedCondition})
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
 
-	meta.SetStatusCondition(&workspace.Status.Conditions, condition)
-	return sm.client.Status().Update(ctx, workspace)
+// UpdateValidationPassedStatus sets the ValidationFailed condition to false
+func (sm *StatusManager) UpdateValidationPassedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	passedCondition := NewCondition(
+		ConditionTypeValidationFailed,
+		metav1.ConditionFalse,
+		ReasonRevalidationPassed,
+		"Workspace passed validation",
+	)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{passedCondition})
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateTerminatingStatus records the current finalization step in the Terminating condition
+func (sm *StatusManager) UpdateTerminatingStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	reason string,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	terminatingCondition := NewCondition(
+		ConditionTypeTerminating,
+		metav1.ConditionTrue,
+		reason,
+		message,
+	)
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &[]metav1.Condition{terminatingCondition})
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateStartupCheckFailedStatus sets Available to false and Degraded to true when the template startup
+// check failed under FailurePolicy Fail; the workspace stays so until its pod restarts
+func (sm *StatusManager) UpdateStartupCheckFailedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonStartupCheckFailed, message),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionFalse, ReasonStartupCheckFailed, message),
+		NewCondition(ConditionTypeDegraded, metav1.ConditionTrue, ReasonStartupCheckFailed, message),
+		NewCondition(ConditionTypeStopped, metav1.ConditionFalse, ReasonDesiredStateRunning, "Workspace is running"),
+	}
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateBootstrapFailedStatus sets Available and Progressing to false and Degraded to true when the init
+// containers of the workspace pod did not complete within the bootstrap timeout
+func (sm *StatusManager) UpdateBootstrapFailedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonBootstrapTimedOut, message),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionFalse, ReasonBootstrapTimedOut, message),
+		NewCondition(ConditionTypeDegraded, metav1.ConditionTrue, ReasonBootstrapTimedOut, message),
+		NewCondition(ConditionTypeStopped, metav1.ConditionFalse, ReasonDesiredStateRunning, "Workspace is running"),
+	}
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateImageResolutionFailedStatus sets ImageResolutionFailed to true and parks the workspace as progressing
+// until its image resolves
+func (sm *StatusManager) UpdateImageResolutionFailedStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeImageResolutionFailed, metav1.ConditionTrue, ReasonImageResolutionFailed, message),
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonImageResolutionFailed, message),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionTrue, ReasonImageResolutionFailed, message),
+	}
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// UpdateQuotaExceededStatus sets QuotaExceeded to true and parks the workspace as progressing
+// with ReasonQuotaRecheckPending until the namespace ResourceQuota has room for it
+func (sm *StatusManager) UpdateQuotaExceededStatus(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	message string,
+	snapshotStatus *workspacev1alpha1.WorkspaceStatus) error {
+	conditions := []metav1.Condition{
+		NewCondition(ConditionTypeQuotaExceeded, metav1.ConditionTrue, ReasonQuotaExceeded, message),
+		NewCondition(ConditionTypeAvailable, metav1.ConditionFalse, ReasonQuotaRecheckPending, message),
+		NewCondition(ConditionTypeProgressing, metav1.ConditionTrue, ReasonQuotaRecheckPending, message),
+	}
+	conditionsToUpdate := MergeConditionsIfChanged(ctx, workspace, &conditions)
+	return sm.updateStatus(ctx, workspace, &conditionsToUpdate, snapshotStatus)
+}
+
+// appendQuotaClearedCondition flips a previously set QuotaExceeded condition back to false;
+// workspaces that never hit a quota do not get the condition at all
+func appendQuotaClearedCondition(workspace *workspacev1alpha1.Workspace, conditions []metav1.Condition) []metav1.Condition {
+	if !IsQuotaExceeded(workspace) {
+		return conditions
+	}
+	return append(conditions, NewCondition(
+		ConditionTypeQuotaExceeded,
+		metav1.ConditionFalse,
+		ReasonWithinQuota,
+		"Namespace ResourceQuota has room for the workspace",
+	))
+}
+
+// appendPauseClearedCondition flips the Paused condition of a workspace leaving the pause to false, with
+// the reason of the desired status it moves to; workspaces that never paused do not get the condition at all
+func appendPauseClearedCondition(workspace *workspacev1alpha1.Workspace, conditions []metav1.Condition, reason string) []metav1.Condition {
+	paused := FindCondition(&workspace.Status.Conditions, ConditionTypePaused)
+	if paused == nil || paused.Reason == reason {
+		return conditions
+	}
+	return append(conditions, NewCondition(
+		ConditionTypePaused,
+		metav1.ConditionFalse,
+		reason,
+		"Workspace is not paused",
+	))
 }
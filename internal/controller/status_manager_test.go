@@ -238,6 +238,12 @@ var _ = Describe("StatusManager", func() {
 				availableCond := findCondition(workspace.Status.Conditions, ConditionTypeAvailable)
 				Expect(availableCond).NotTo(BeNil())
 				Expect(availableCond.Reason).To(Equal(ReasonPreempted))
+
+				// Verify Preempted=True
+				preemptedCond := findCondition(workspace.Status.Conditions, ConditionTypePreempted)
+				Expect(preemptedCond).NotTo(BeNil())
+				Expect(preemptedCond.Status).To(Equal(metav1.ConditionTrue))
+				Expect(preemptedCond.Reason).To(Equal(ReasonPreempted))
 			})
 		})
 
@@ -0,0 +1,43 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestStatusWritesGoThroughStatusSubresource(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+
+	// Status sent with a write to the main resource is dropped
+	forged := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), forged))
+	forged.Status.AccessURL = "https://attacker.example.com/"
+	require.NoError(t, k8sClient.Update(ctx, forged))
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Empty(t, stored.Status.AccessURL)
+
+	// Controller status updates go through the status subresource
+	snapshot := stored.Status.DeepCopy()
+	require.NoError(t, sm.statusManager.UpdateErrorStatus(ctx, stored, ReasonDeploymentError, "failed", snapshot))
+
+	updated := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), updated))
+	condition := FindCondition(&updated.Status.Conditions, ConditionTypeDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonDeploymentError, condition.Reason)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// newReleaseTestPod returns a pod of the workspace holding a GPU on a GPU node
+func newReleaseTestPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "team-a",
+			Labels:    GenerateLabels("ws"),
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"},
+			Containers: []corev1.Container{{
+				Name:  "workspace",
+				Image: "jupyter/base-notebook:latest",
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func stoppedConditionMessage(workspace *workspacev1alpha1.Workspace) string {
+	if condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStopped); condition != nil {
+		return condition.Message
+	}
+	return ""
+}
+
+func TestStopWaitsForPodsToReleaseTheirResources(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+	pod := newReleaseTestPod("ws-pod", corev1.PodRunning)
+	require.NoError(t, k8sClient.Create(ctx, pod))
+
+	// The deployment is deleted, its pod keeps terminating
+	workspace, _ := reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonComputeNotStopped, stoppedConditionReason(workspace))
+
+	workspace, requeueAfter := reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonComputeNotStopped, stoppedConditionReason(workspace))
+	assert.Contains(t, stoppedConditionMessage(workspace), "pods to terminate")
+	assert.Equal(t, PollRequeueDelay, requeueAfter)
+	assert.Equal(t, WorkspacePhaseStopping, GetWorkspacePhase(workspace))
+	assert.NotEmpty(t, workspace.Status.AccessURL)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: GenerateServiceName("ws"), Namespace: "team-a"}, &corev1.Service{}))
+
+	// The stop proceeds on the first pass after the pod is gone
+	require.NoError(t, k8sClient.Delete(ctx, pod))
+	workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonServiceNotStopped, stoppedConditionReason(workspace))
+	assert.Empty(t, workspace.Status.AccessURL)
+
+	workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonResourcesStopped, stoppedConditionReason(workspace))
+	assert.Equal(t, WorkspacePhaseStopped, GetWorkspacePhase(workspace))
+}
+
+func TestStopKeepsTheStorageSecretsAndLabels(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+	workspace.Labels = map[string]string{LabelWorkspaceTemplateNamespace: "jupyter-k8s-shared"}
+	require.NoError(t, k8sClient.Update(ctx, workspace))
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+		Name: GeneratePVCName("ws"), Namespace: "team-a", Labels: GenerateLabels("ws"), UID: "pvc-uid"}}
+	require.NoError(t, k8sClient.Create(ctx, pvc))
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "ws-credentials", Namespace: "team-a", Labels: GenerateLabels("ws")}}
+	require.NoError(t, k8sClient.Create(ctx, secret))
+
+	for range 3 {
+		workspace, _ = reconcileDrainTestStop(t, sm, k8sClient)
+	}
+	require.Equal(t, ReasonResourcesStopped, stoppedConditionReason(workspace))
+
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: GenerateDeploymentName("ws"), Namespace: "team-a"}, &appsv1.Deployment{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the deployment to be deleted, got %v", err)
+	stored := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), stored))
+	assert.Equal(t, pvc.UID, stored.UID)
+	assert.Nil(t, stored.DeletionTimestamp)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(secret), &corev1.Secret{}))
+	assert.Equal(t, "jupyter-k8s-shared", workspace.Labels[LabelWorkspaceTemplateNamespace])
+
+	assert.Equal(t, WorkspacePhaseStopped, workspace.Status.Phase)
+	ready := FindCondition(&workspace.Status.Conditions, ConditionTypeReady)
+	require.NotNil(t, ready)
+	assert.Equal(t, metav1.ConditionFalse, ready.Status)
+	assert.Equal(t, WorkspacePhaseStopped, ready.Reason)
+}
+
+func TestStopIgnoresCompletedPods(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, _ := newDrainTestStateMachine(t)
+	sm.drainPeriod = 0
+	require.NoError(t, k8sClient.Create(ctx, newReleaseTestPod("ws-failed", corev1.PodFailed)))
+	require.NoError(t, k8sClient.Create(ctx, newReleaseTestPod("ws-succeeded", corev1.PodSucceeded)))
+
+	reconcileDrainTestStop(t, sm, k8sClient)
+	workspace, _ := reconcileDrainTestStop(t, sm, k8sClient)
+	assert.Equal(t, ReasonServiceNotStopped, stoppedConditionReason(workspace))
+}
+
+func TestCountWorkspacePodsOnlyCountsTheWorkspace(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newDrainTestStateMachine(t)
+	require.NoError(t, k8sClient.Create(ctx, newReleaseTestPod("ws-pod", corev1.PodPending)))
+	other := newReleaseTestPod("other-pod", corev1.PodRunning)
+	other.Labels = GenerateLabels("other")
+	require.NoError(t, k8sClient.Create(ctx, other))
+
+	count, err := sm.resourceManager.CountWorkspacePods(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// EventReasonDeletionScheduled is the reason of the warning event recorded ahead of the deletion of a
+	// workspace stopped for longer than its retention
+	EventReasonDeletionScheduled = "DeletionScheduled"
+	// EventReasonDeletionCanceled is the reason of the event recorded when a scheduled deletion no longer applies
+	EventReasonDeletionCanceled = "DeletionCanceled"
+	// EventReasonDeletedAfterStopped is the reason of the event recorded when the controller deletes a
+	// workspace stopped for longer than its retention
+	EventReasonDeletedAfterStopped = "DeletedAfterStopped"
+)
+
+// stoppedDeletionOutcome is the result of applying the retention of a stopped workspace
+type stoppedDeletionOutcome struct {
+	// nextTransition is when the evaluation may change; zero when it only changes on a workspace update
+	nextTransition time.Time
+
+	// deleted is true when the workspace was deleted
+	deleted bool
+}
+
+// recordStoppedAt records when the workspace stopped or paused, unless it already did: a paused workspace
+// that stops has been stopped since it paused. Workspaces stopped before the controller recorded it are
+// considered stopped since now.
+func recordStoppedAt(workspace *workspacev1alpha1.Workspace) {
+	if workspace.Status.StoppedAt == nil {
+		workspace.Status.StoppedAt = &metav1.Time{Time: time.Now().Truncate(time.Second)}
+	}
+}
+
+// deleteAfterStopped returns how long the workspace may stay stopped before it is deleted, zero when it
+// is kept, with what keeps a workspace that would otherwise be deleted
+func deleteAfterStopped(workspace *workspacev1alpha1.Workspace) (time.Duration, string) {
+	retention := workspace.Spec.Retention
+	if retention == nil || retention.DeleteAfterStoppedSeconds == nil || *retention.DeleteAfterStoppedSeconds <= 0 {
+		return 0, ""
+	}
+	if workspace.Spec.DeletionProtection {
+		return 0, "its deletion protection"
+	}
+	if workspace.Labels[LabelRetain] == "true" {
+		return 0, fmt.Sprintf("its label %s=true", LabelRetain)
+	}
+	return time.Duration(*retention.DeleteAfterStoppedSeconds) * time.Second, ""
+}
+
+// reconcileStoppedDeletion deletes a workspace stopped or paused for longer than spec.retention. Its owner
+// is warned with an event the warning period ahead, and the deletion time is recorded in
+// Status.DeletionScheduledAt; the owner always gets the full warning period, even when the controller
+// was down when the warning was due. The clock only reads the status, so that a restarted controller
+// deletes the workspace at the time it announced. Starting the workspace, protecting it from deletion or
+// labeling it retained cancels the deletion. The status is updated in memory.
+func (sm *StateMachine) reconcileStoppedDeletion(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	desiredStatus string,
+	now time.Time) (stoppedDeletionOutcome, error) {
+	logger := logf.FromContext(ctx)
+
+	if desiredStatus == DesiredStateRunning {
+		workspace.Status.StoppedAt = nil
+	}
+	ttl, keptBy := deleteAfterStopped(workspace)
+	stoppedAt := workspace.Status.StoppedAt
+	scheduled := workspace.Status.DeletionScheduledAt
+	if ttl == 0 || stoppedAt == nil {
+		if scheduled != nil {
+			message := "Deletion canceled, the workspace is no longer stopped"
+			switch {
+			case keptBy != "":
+				message = "Deletion canceled by " + keptBy
+			case ttl == 0 && stoppedAt != nil:
+				message = "Deletion canceled, the retention of the workspace no longer deletes it"
+			}
+			logger.Info("Canceling the deletion of stopped workspace", "scheduledAt", scheduled.Time)
+			sm.recorder.Event(workspace, corev1.EventTypeNormal, EventReasonDeletionCanceled, message)
+			workspace.Status.DeletionScheduledAt = nil
+		}
+		return stoppedDeletionOutcome{}, nil
+	}
+
+	warningPeriod := sm.deletionWarningPeriod
+	if warningPeriod <= 0 {
+		warningPeriod = DefaultDeletionWarningPeriod
+	}
+	deleteAt := stoppedAt.Add(ttl)
+	// A longer retention postpones the deletion, which is announced again
+	if scheduled != nil && deleteAt.After(scheduled.Time) {
+		logger.Info("Postponing the deletion of stopped workspace", "scheduledAt", scheduled.Time, "deleteAt", deleteAt)
+		scheduled = nil
+		workspace.Status.DeletionScheduledAt = nil
+	}
+
+	if scheduled == nil {
+		if warnAt := deleteAt.Add(-warningPeriod); now.Before(warnAt) {
+			return stoppedDeletionOutcome{nextTransition: warnAt}, nil
+		}
+		if earliest := now.Add(warningPeriod); deleteAt.Before(earliest) {
+			deleteAt = earliest
+		}
+		scheduled = &metav1.Time{Time: deleteAt.Truncate(time.Second)}
+		workspace.Status.DeletionScheduledAt = scheduled
+		message := fmt.Sprintf("Workspace stopped since %s will be deleted with its storage at %s, unless it is started or labeled %s=true",
+			stoppedAt.UTC().Format(time.RFC3339), scheduled.UTC().Format(time.RFC3339), LabelRetain)
+		logger.Info("Scheduling the deletion of stopped workspace", "stoppedAt", stoppedAt.Time, "deleteAt", scheduled.Time)
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonDeletionScheduled, message)
+		return stoppedDeletionOutcome{nextTransition: scheduled.Time}, nil
+	}
+
+	if now.Before(scheduled.Time) {
+		return stoppedDeletionOutcome{nextTransition: scheduled.Time}, nil
+	}
+
+	// The preconditions fail if the workspace changed since it was read, e.g. it was started again
+	uid, resourceVersion := workspace.UID, workspace.ResourceVersion
+	err := sm.resourceManager.client.Delete(ctx, workspace,
+		client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion})
+	if apierrors.IsNotFound(err) {
+		return stoppedDeletionOutcome{deleted: true}, nil
+	}
+	if err != nil {
+		return stoppedDeletionOutcome{}, fmt.Errorf("failed to delete stopped workspace: %w", err)
+	}
+	logger.Info("Deleted workspace stopped for longer than its retention", "stoppedAt", stoppedAt.Time, "deleteAfter", ttl)
+	sm.recorder.Event(workspace, corev1.EventTypeWarning, EventReasonDeletedAfterStopped,
+		fmt.Sprintf("Deleted workspace stopped since %s, for longer than its retention of %s",
+			stoppedAt.UTC().Format(time.RFC3339), ttl))
+	return stoppedDeletionOutcome{deleted: true}, nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newStoppedDeletionTestWorkspace returns a workspace deleted once stopped for 7 days, stopped at stoppedAt
+func newStoppedDeletionTestWorkspace(stoppedAt time.Time) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.DesiredStatus = DesiredStateStopped
+	workspace.Spec.Retention = &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: ptr.To[int64](7 * 86400)}
+	workspace.Status.StoppedAt = &metav1.Time{Time: stoppedAt}
+	return workspace
+}
+
+func newStoppedDeletionTestStateMachine(t *testing.T, objects ...client.Object) (*StateMachine, client.Client) {
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, objects...)
+	sm.deletionWarningPeriod = day
+	return sm, k8sClient
+}
+
+func TestStoppedDeletionWarnsTheOwnerAheadOfTheRetention(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	workspace := newStoppedDeletionTestWorkspace(now.Add(-5 * day))
+	sm, _ := newStoppedDeletionTestStateMachine(t, workspace)
+	events := sm.recorder.(*record.FakeRecorder).Events
+
+	outcome, err := sm.reconcileStoppedDeletion(ctx, workspace, DesiredStateStopped, now)
+	require.NoError(t, err)
+	assert.False(t, outcome.deleted)
+	assert.Equal(t, now.Add(day), outcome.nextTransition, "the owner is warned a day before the deletion")
+	assert.Nil(t, workspace.Status.DeletionScheduledAt)
+	assert.Empty(t, events)
+
+	outcome, err = sm.reconcileStoppedDeletion(ctx, workspace, DesiredStateStopped, now.Add(day))
+	require.NoError(t, err)
+	assert.False(t, outcome.deleted)
+	require.NotNil(t, workspace.Status.DeletionScheduledAt)
+	assert.Equal(t, now.Add(2*day), workspace.Status.DeletionScheduledAt.Time)
+	assert.Equal(t, now.Add(2*day), outcome.nextTransition)
+	require.Len(t, events, 1)
+	event := <-events
+	assert.Contains(t, event, fmt.Sprintf("%s %s", corev1.EventTypeWarning, EventReasonDeletionScheduled))
+	assert.Contains(t, event, LabelRetain)
+}
+
+func TestStoppedDeletionClockSurvivesControllerRestarts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	workspace := newStoppedDeletionTestWorkspace(now.Add(-6*day - time.Hour))
+	sm, k8sClient := newStoppedDeletionTestStateMachine(t, workspace)
+
+	_, err := sm.reconcileStoppedDeletion(ctx, workspace, DesiredStateStopped, now)
+	require.NoError(t, err)
+	require.NotNil(t, workspace.Status.DeletionScheduledAt)
+	scheduledAt := workspace.Status.DeletionScheduledAt.Time
+	require.NoError(t, k8sClient.Status().Update(ctx, workspace))
+
+	// A restarted controller only knows the workspace status
+	restarted := func() (*StateMachine, client.Client, *workspacev1alpha1.Workspace) {
+		stored := getStaleTestWorkspace(t, k8sClient)
+		stored.ResourceVersion = ""
+		sm, k8sClient := newStoppedDeletionTestStateMachine(t, stored)
+		return sm, k8sClient, getStaleTestWorkspace(t, k8sClient)
+	}
+
+	sm, _, stored := restarted()
+	outcome, err := sm.reconcileStoppedDeletion(ctx, stored, DesiredStateStopped, scheduledAt.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, outcome.deleted)
+	assert.Equal(t, scheduledAt, outcome.nextTransition)
+	assert.Empty(t, sm.recorder.(*record.FakeRecorder).Events, "the owner is not warned again")
+
+	sm, k8sClient, stored = restarted()
+	outcome, err = sm.reconcileStoppedDeletion(ctx, stored, DesiredStateStopped, scheduledAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, outcome.deleted)
+	err = k8sClient.Get(ctx, client.ObjectKeyFromObject(stored), &workspacev1alpha1.Workspace{})
+	assert.True(t, apierrors.IsNotFound(err), "expected the workspace to be deleted, got %v", err)
+	events := sm.recorder.(*record.FakeRecorder).Events
+	require.Len(t, events, 1)
+	assert.Contains(t, <-events, EventReasonDeletedAfterStopped)
+}
+
+func TestStoppedDeletionGivesTheFullWarningPeriodWhenLate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	workspace := newStoppedDeletionTestWorkspace(now.Add(-30 * day))
+	workspace.Spec.DesiredStatus = DesiredStatePaused
+	sm, k8sClient := newStoppedDeletionTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStoppedDeletion(ctx, workspace, DesiredStatePaused, now)
+	require.NoError(t, err)
+	assert.False(t, outcome.deleted)
+	require.NotNil(t, workspace.Status.DeletionScheduledAt)
+	assert.Equal(t, now.Add(day), workspace.Status.DeletionScheduledAt.Time)
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), &workspacev1alpha1.Workspace{}))
+}
+
+func TestStoppedDeletionIsCanceled(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	tests := []struct {
+		name          string
+		update        func(workspace *workspacev1alpha1.Workspace)
+		desiredStatus string
+		message       string
+	}{
+		{
+			name: "by the retain label",
+			update: func(workspace *workspacev1alpha1.Workspace) {
+				workspace.Labels = map[string]string{LabelRetain: "true"}
+			},
+			desiredStatus: DesiredStateStopped,
+			message:       LabelRetain,
+		},
+		{
+			name: "by deletion protection",
+			update: func(workspace *workspacev1alpha1.Workspace) {
+				workspace.Spec.DeletionProtection = true
+			},
+			desiredStatus: DesiredStateStopped,
+			message:       "deletion protection",
+		},
+		{
+			name: "by a retention that keeps the workspace",
+			update: func(workspace *workspacev1alpha1.Workspace) {
+				workspace.Spec.Retention.DeleteAfterStoppedSeconds = ptr.To[int64](0)
+			},
+			desiredStatus: DesiredStateStopped,
+			message:       "no longer deletes it",
+		},
+		{
+			name:          "by starting the workspace",
+			update:        func(workspace *workspacev1alpha1.Workspace) { workspace.Spec.DesiredStatus = DesiredStateRunning },
+			desiredStatus: DesiredStateRunning,
+			message:       "no longer stopped",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workspace := newStoppedDeletionTestWorkspace(now.Add(-10 * day))
+			workspace.Status.DeletionScheduledAt = &metav1.Time{Time: now.Add(-time.Hour)}
+			tt.update(workspace)
+			sm, k8sClient := newStoppedDeletionTestStateMachine(t, workspace)
+
+			outcome, err := sm.reconcileStoppedDeletion(context.Background(), workspace, tt.desiredStatus, now)
+			require.NoError(t, err)
+			assert.False(t, outcome.deleted)
+			assert.True(t, outcome.nextTransition.IsZero())
+			assert.Nil(t, workspace.Status.DeletionScheduledAt)
+			require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), &workspacev1alpha1.Workspace{}))
+			events := sm.recorder.(*record.FakeRecorder).Events
+			require.Len(t, events, 1)
+			event := <-events
+			assert.Contains(t, event, fmt.Sprintf("%s %s", corev1.EventTypeNormal, EventReasonDeletionCanceled))
+			assert.Contains(t, event, tt.message)
+		})
+	}
+}
+
+func TestStoppedDeletionClearsStoppedAtOfStartedWorkspaces(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	workspace := newStoppedDeletionTestWorkspace(now.Add(-3 * day))
+	workspace.Spec.DesiredStatus = DesiredStateRunning
+	sm, _ := newStoppedDeletionTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStoppedDeletion(context.Background(), workspace, DesiredStateRunning, now)
+	require.NoError(t, err)
+	assert.Equal(t, stoppedDeletionOutcome{}, outcome)
+	assert.Nil(t, workspace.Status.StoppedAt)
+	assert.Empty(t, sm.recorder.(*record.FakeRecorder).Events)
+}
+
+func TestStoppedDeletionIsPostponedByALongerRetention(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	workspace := newStoppedDeletionTestWorkspace(now.Add(-6*day - time.Hour))
+	workspace.Status.DeletionScheduledAt = &metav1.Time{Time: now.Add(23 * time.Hour)}
+	workspace.Spec.Retention.DeleteAfterStoppedSeconds = ptr.To[int64](14 * 86400)
+	sm, _ := newStoppedDeletionTestStateMachine(t, workspace)
+
+	outcome, err := sm.reconcileStoppedDeletion(context.Background(), workspace, DesiredStateStopped, now)
+	require.NoError(t, err)
+	assert.False(t, outcome.deleted)
+	assert.Nil(t, workspace.Status.DeletionScheduledAt, "the new deletion is announced again")
+	assert.Equal(t, now.Add(6*day+23*time.Hour), outcome.nextTransition)
+}
+
+func TestRecordStoppedAtKeepsTheFirstStop(t *testing.T) {
+	workspace := newAdoptionTestWorkspace("ws")
+	recordStoppedAt(workspace)
+	require.NotNil(t, workspace.Status.StoppedAt)
+	assert.WithinDuration(t, time.Now(), workspace.Status.StoppedAt.Time, time.Second)
+
+	pausedAt := metav1.NewTime(time.Now().Add(-2 * day))
+	workspace.Status.StoppedAt = &pausedAt
+	recordStoppedAt(workspace)
+	assert.Equal(t, pausedAt, *workspace.Status.StoppedAt, "a paused workspace that stops keeps the time it paused")
+}
@@ -0,0 +1,245 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// defaultStorageClassAnnotation is the annotation marking the cluster default StorageClass
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// missingStorageClass returns the name of the StorageClass the PVC was created with once it no longer exists,
+// or an empty string; a PVC without a StorageClass name never misses one
+func (rm *ResourceManager) missingStorageClass(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (string, error) {
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return "", nil
+	}
+	name := *pvc.Spec.StorageClassName
+	err := rm.client.Get(ctx, types.NamespacedName{Name: name}, &storagev1.StorageClass{})
+	if apierrors.IsNotFound(err) {
+		return name, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get StorageClass %s: %w", name, err)
+	}
+	return "", nil
+}
+
+// pvcBound returns true if the PVC is bound to a volume
+func pvcBound(pvc *corev1.PersistentVolumeClaim) bool {
+	return pvc.Status.Phase == corev1.ClaimBound || pvc.Spec.VolumeName != ""
+}
+
+// observeStorageDegraded returns the StorageDegraded condition of the PVC whose StorageClass is missing,
+// False when none is
+func observeStorageDegraded(pvc *corev1.PersistentVolumeClaim, missing string) (metav1.ConditionStatus, string, string) {
+	switch {
+	case missing == "":
+		return metav1.ConditionFalse, ReasonStorageClassAvailable, fmt.Sprintf("The StorageClass of PVC %s exists", pvc.Name)
+	case pvcBound(pvc):
+		return metav1.ConditionTrue, ReasonStorageClassMissing, fmt.Sprintf(
+			"PVC %s is bound but its StorageClass %s no longer exists: the volume keeps working, it cannot be resized",
+			pvc.Name, missing)
+	default:
+		return metav1.ConditionTrue, ReasonStorageUnprovisionable, fmt.Sprintf(
+			"PVC %s is not bound and its StorageClass %s no longer exists: it will never be provisioned; "+
+				"set the %s annotation to recreate it under the default StorageClass of the namespace",
+			pvc.Name, missing, AnnotationRecreateUnboundStorage)
+	}
+}
+
+// reconcileStorageDegraded records in the StorageDegraded condition a PVC of the workspace whose StorageClass
+// no longer exists, with an event on each transition. The status is updated in memory. Failures to read the
+// StorageClass are logged: storage reporting never blocks a start.
+func (sm *StateMachine) reconcileStorageDegraded(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	pvc *corev1.PersistentVolumeClaim) {
+	if pvc == nil {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeStorageDegraded)
+		return
+	}
+
+	missing, err := sm.resourceManager.missingStorageClass(ctx, pvc)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to check the StorageClass of the workspace PVC")
+		return
+	}
+
+	previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageDegraded)
+	status, reason, message := observeStorageDegraded(pvc, missing)
+	if status == metav1.ConditionFalse && (previous == nil || previous.Status == metav1.ConditionFalse) {
+		// The StorageClass never went missing, or nothing changed since its recovery
+		if previous != nil {
+			apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+				ConditionTypeStorageDegraded, status, reason, message))
+		}
+		return
+	}
+	if previous == nil || previous.Reason != reason {
+		eventType := corev1.EventTypeWarning
+		if status == metav1.ConditionFalse {
+			eventType = corev1.EventTypeNormal
+		}
+		sm.recorder.Event(workspace, eventType, reason, message)
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeStorageDegraded, status, reason, message))
+}
+
+// recreationStorageClass returns the StorageClass unbound PVCs of the namespace are recreated under: the one
+// the namespace annotation names, or the cluster default. It returns an empty name and why when there is none.
+func (rm *ResourceManager) recreationStorageClass(ctx context.Context, namespace string) (string, string, error) {
+	ns := &corev1.Namespace{}
+	if err := rm.client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil && !apierrors.IsNotFound(err) {
+		return "", "", fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	if name := ns.Annotations[AnnotationDefaultStorageClass]; name != "" {
+		err := rm.client.Get(ctx, types.NamespacedName{Name: name}, &storagev1.StorageClass{})
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Sprintf("the default StorageClass %s of namespace %s does not exist", name, namespace), nil
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get StorageClass %s: %w", name, err)
+		}
+		return name, "", nil
+	}
+
+	storageClasses := &storagev1.StorageClassList{}
+	if err := rm.client.List(ctx, storageClasses); err != nil {
+		return "", "", fmt.Errorf("failed to list StorageClasses: %w", err)
+	}
+	for _, storageClass := range storageClasses.Items {
+		if storageClass.Annotations[defaultStorageClassAnnotation] == "true" {
+			return storageClass.Name, "", nil
+		}
+	}
+	return "", fmt.Sprintf("namespace %s has no %s annotation and the cluster has no default StorageClass",
+		namespace, AnnotationDefaultStorageClass), nil
+}
+
+// recreateUnprovisionableStorage recreates the unbound PVC of the workspace whose StorageClass no longer exists.
+// On request of the owner, with the AnnotationRecreateUnboundStorage annotation, the storage of the workspace
+// moves to the default StorageClass of the namespace and the annotation is removed; an unbound PVC whose
+// StorageClass is missing and differs from that of the workspace is then deleted, with the deployment whose
+// pod holds it, for EnsurePVCExists to create it again. It returns true while the PVC is deleted.
+func (sm *StateMachine) recreateUnprovisionableStorage(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+	if !hasPersistentStorage(workspace) {
+		return false, nil
+	}
+	rm := sm.resourceManager
+	pvc, err := rm.getPVC(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get PVC: %w", err)
+	}
+	if !pvc.DeletionTimestamp.IsZero() && !pvcBound(pvc) {
+		// Creating the deployment again would keep the unbound PVC from going away
+		return true, nil
+	}
+	if ownership, _ := classifyChild(workspace, pvc); ownership != childOwned {
+		return false, nil
+	}
+
+	missing, err := rm.missingStorageClass(ctx, pvc)
+	if err != nil {
+		return false, err
+	}
+	_, requested := workspace.Annotations[AnnotationRecreateUnboundStorage]
+	if requested {
+		if missing == "" || pvcBound(pvc) {
+			sm.recorder.Eventf(workspace, corev1.EventTypeWarning, ReasonStorageRecreateRejected,
+				"PVC %s was not recreated: only an unbound PVC whose StorageClass no longer exists is", pvc.Name)
+			return false, sm.removeRecreateStorageAnnotation(ctx, workspace)
+		}
+		if err := sm.moveStorageClass(ctx, workspace); err != nil {
+			return false, err
+		}
+	}
+	if missing == "" || pvcBound(pvc) || storageClassNameOf(workspace) == missing {
+		return false, nil
+	}
+
+	if _, err := rm.EnsureDeploymentDeleted(ctx, workspace); err != nil {
+		return false, err
+	}
+	if err := rm.client.Delete(ctx, pvc); err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete PVC: %w", err)
+	}
+	message := fmt.Sprintf("Recreating unbound PVC %s under StorageClass %s: StorageClass %s no longer exists",
+		pvc.Name, storageClassNameOf(workspace), missing)
+	logf.FromContext(ctx).Info(message)
+	sm.recorder.Event(workspace, corev1.EventTypeNormal, ReasonStorageRecreated, message)
+	return true, nil
+}
+
+// moveStorageClass sets the storage of the workspace to the default StorageClass of its namespace and removes
+// the AnnotationRecreateUnboundStorage annotation
+func (sm *StateMachine) moveStorageClass(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	storageClass, unavailable, err := sm.resourceManager.recreationStorageClass(ctx, workspace.Namespace)
+	if err != nil {
+		return err
+	}
+	if storageClass == "" {
+		sm.recorder.Eventf(workspace, corev1.EventTypeWarning, ReasonStorageRecreateRejected,
+			"PVC %s was not recreated: %s", pvcNameFor(workspace), unavailable)
+		return sm.removeRecreateStorageAnnotation(ctx, workspace)
+	}
+
+	updated := workspace.DeepCopy()
+	delete(updated.Annotations, AnnotationRecreateUnboundStorage)
+	updated.Spec.Storage.StorageClassName = ptr.To(storageClass)
+	err = workspaceutil.PatchChanges(ctx, sm.resourceManager.client, updated, workspace)
+	if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) {
+		// The template allowlist or the webhook reject the StorageClass; keep the PVC and drop the request
+		sm.recorder.Eventf(workspace, corev1.EventTypeWarning, ReasonStorageRecreateRejected,
+			"PVC %s was not recreated: moving the storage to StorageClass %s was rejected: %v",
+			pvcNameFor(workspace), storageClass, err)
+		return sm.removeRecreateStorageAnnotation(ctx, workspace)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to move the storage to StorageClass %s: %w", storageClass, err)
+	}
+	// Keep the status updated in memory by this reconcile
+	updated.ObjectMeta.DeepCopyInto(&workspace.ObjectMeta)
+	updated.Spec.DeepCopyInto(&workspace.Spec)
+	return nil
+}
+
+// removeRecreateStorageAnnotation removes the AnnotationRecreateUnboundStorage annotation
+func (sm *StateMachine) removeRecreateStorageAnnotation(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	patch := client.MergeFrom(workspace.DeepCopy())
+	delete(workspace.Annotations, AnnotationRecreateUnboundStorage)
+	if err := sm.resourceManager.client.Patch(ctx, workspace, patch); err != nil {
+		return fmt.Errorf("failed to remove annotation %s: %w", AnnotationRecreateUnboundStorage, err)
+	}
+	return nil
+}
+
+// storageClassNameOf returns the StorageClass of the workspace storage, empty for the cluster default
+func storageClassNameOf(workspace *workspacev1alpha1.Workspace) string {
+	if className := resolveStorageClassName(workspace); className != nil {
+		return *className
+	}
+	return ""
+}
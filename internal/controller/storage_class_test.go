@@ -0,0 +1,314 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newStorageClassTestWorkspace returns a workspace whose PVC was created with the legacy StorageClass, in a
+// namespace with the other objects
+func newStorageClassTestWorkspace(t *testing.T, objects ...client.Object) (*StateMachine, client.Client, *workspacev1alpha1.Workspace) {
+	legacy := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Storage.StorageClassName = ptr.To(legacy.Name)
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, append(objects, workspace, legacy)...)
+	_, err := sm.resourceManager.EnsurePVCExists(context.Background(), workspace)
+	require.NoError(t, err)
+	return sm, k8sClient, workspace
+}
+
+// deleteStorageClassTestClass deletes the StorageClass cluster-side
+func deleteStorageClassTestClass(t *testing.T, k8sClient client.Client, name string) {
+	require.NoError(t, k8sClient.Delete(context.Background(), &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}}))
+}
+
+// requestStorageClassTestRecreation sets the AnnotationRecreateUnboundStorage annotation on the workspace,
+// keeping the status in memory
+func requestStorageClassTestRecreation(t *testing.T, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) {
+	ctx := context.Background()
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	stored.Annotations = map[string]string{AnnotationRecreateUnboundStorage: "true"}
+	require.NoError(t, k8sClient.Update(ctx, stored))
+	workspace.Annotations = stored.Annotations
+	workspace.ResourceVersion = stored.ResourceVersion
+}
+
+func TestUnboundPVCWhoseStorageClassIsDeletedIsRecreatedOnRequest(t *testing.T) {
+	ctx := context.Background()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-a", Annotations: map[string]string{AnnotationDefaultStorageClass: "standard"}}}
+	standard := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}}
+	sm, k8sClient, workspace := newStorageClassTestWorkspace(t, namespace, standard)
+	recorder := sm.recorder.(*record.FakeRecorder)
+	pvc := getResizeTestPVC(t, sm, workspace)
+
+	deleteStorageClassTestClass(t, k8sClient, "legacy")
+	sm.reconcileStorageDegraded(ctx, workspace, pvc)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonStorageUnprovisionable, condition.Reason)
+	assert.Contains(t, condition.Message, "will never be provisioned")
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning StorageUnprovisionable")
+
+	// Without a request the PVC is kept, and the transition is not reported again
+	recreating, err := sm.recreateUnprovisionableStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, recreating)
+	sm.reconcileStorageDegraded(ctx, workspace, pvc)
+	assert.Empty(t, recorder.Events)
+
+	// The pending pod of the deployment holds the PVC
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deploymentNameFor(workspace), Namespace: "team-a"}}
+	require.NoError(t, k8sClient.Create(ctx, deployment))
+
+	requestStorageClassTestRecreation(t, k8sClient, workspace)
+	recreating, err = sm.recreateUnprovisionableStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.True(t, recreating)
+	assert.Contains(t, <-recorder.Events, "Normal StorageRecreated Recreating unbound PVC")
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})))
+	assert.True(t, apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(deployment), &appsv1.Deployment{})))
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationRecreateUnboundStorage)
+	assert.Equal(t, ptr.To("standard"), stored.Spec.Storage.StorageClassName)
+
+	// The PVC is created again under the new StorageClass, which recovers the storage
+	pvc, err = sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, ptr.To("standard"), pvc.Spec.StorageClassName)
+	recreating, err = sm.recreateUnprovisionableStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, recreating)
+	sm.reconcileStorageDegraded(ctx, workspace, pvc)
+	condition = apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonStorageClassAvailable, condition.Reason)
+	assert.Contains(t, <-recorder.Events, "Normal StorageClassAvailable")
+}
+
+func TestBoundPVCWhoseStorageClassIsDeletedIsKept(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newStorageClassTestWorkspace(t)
+	recorder := sm.recorder.(*record.FakeRecorder)
+	pvc := getResizeTestPVC(t, sm, workspace)
+	pvc.Spec.VolumeName = "pv-1"
+	pvc.Status.Phase = corev1.ClaimBound
+	require.NoError(t, k8sClient.Update(ctx, pvc))
+
+	deleteStorageClassTestClass(t, k8sClient, "legacy")
+	sm.reconcileStorageDegraded(ctx, workspace, pvc)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageDegraded)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonStorageClassMissing, condition.Reason)
+	assert.Contains(t, condition.Message, "cannot be resized")
+	assert.Contains(t, <-recorder.Events, "Warning StorageClassMissing")
+
+	// A bound PVC holds the data of the workspace: a request to recreate it is rejected
+	requestStorageClassTestRecreation(t, k8sClient, workspace)
+	recreating, err := sm.recreateUnprovisionableStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, recreating)
+	assert.Contains(t, <-recorder.Events, "Warning StorageRecreateRejected")
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{}))
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.NotContains(t, stored.Annotations, AnnotationRecreateUnboundStorage)
+	assert.Equal(t, ptr.To("legacy"), stored.Spec.Storage.StorageClassName)
+}
+
+func TestRecreationWithoutADefaultStorageClassIsRejected(t *testing.T) {
+	ctx := context.Background()
+	sm, k8sClient, workspace := newStorageClassTestWorkspace(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}})
+	recorder := sm.recorder.(*record.FakeRecorder)
+
+	deleteStorageClassTestClass(t, k8sClient, "legacy")
+	requestStorageClassTestRecreation(t, k8sClient, workspace)
+	recreating, err := sm.recreateUnprovisionableStorage(ctx, workspace)
+	require.NoError(t, err)
+	assert.False(t, recreating)
+	assert.Contains(t, <-recorder.Events, "the cluster has no default StorageClass")
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: pvcNameFor(workspace), Namespace: "team-a"},
+		&corev1.PersistentVolumeClaim{}))
+
+	// The cluster default StorageClass applies to namespaces that name none
+	standard := &storagev1.StorageClass{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "standard"}, standard))
+	standard.Annotations = map[string]string{defaultStorageClassAnnotation: "true"}
+	require.NoError(t, k8sClient.Update(ctx, standard))
+	storageClass, unavailable, err := sm.resourceManager.recreationStorageClass(ctx, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "standard", storageClass)
+	assert.Empty(t, unavailable)
+}
+
+func TestObserveStorageDegraded(t *testing.T) {
+	pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "workspace-ws-pvc"}}
+
+	status, reason, _ := observeStorageDegraded(pvc, "")
+	assert.Equal(t, metav1.ConditionFalse, status)
+	assert.Equal(t, ReasonStorageClassAvailable, reason)
+
+	status, reason, message := observeStorageDegraded(pvc, "legacy")
+	assert.Equal(t, metav1.ConditionTrue, status)
+	assert.Equal(t, ReasonStorageUnprovisionable, reason)
+	assert.Contains(t, message, AnnotationRecreateUnboundStorage)
+
+	pvc.Status.Phase = corev1.ClaimBound
+	status, reason, _ = observeStorageDegraded(pvc, "legacy")
+	assert.Equal(t, metav1.ConditionTrue, status)
+	assert.Equal(t, ReasonStorageClassMissing, reason)
+}
+
+var _ = Describe("Workspaces whose StorageClass is deleted", func() {
+	var (
+		ctx          context.Context
+		workspaceKey types.NamespacedName
+		sm           *StateMachine
+		recorder     *record.FakeRecorder
+	)
+
+	getWorkspace := func() *workspacev1alpha1.Workspace {
+		workspace := &workspacev1alpha1.Workspace{}
+		Expect(k8sClient.Get(ctx, workspaceKey, workspace)).To(Succeed())
+		return workspace
+	}
+
+	getPVC := func() *corev1.PersistentVolumeClaim {
+		pvc := &corev1.PersistentVolumeClaim{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: GeneratePVCName(workspaceKey.Name), Namespace: "default"}, pvc)).To(Succeed())
+		return pvc
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		workspaceKey = types.NamespacedName{Name: "storage-class-test", Namespace: "default"}
+		recorder = record.NewFakeRecorder(10)
+		statusManager := NewStatusManager(k8sClient)
+		resourceManager := NewResourceManager(k8sClient, k8sClient.Scheme(), nil, nil,
+			NewPVCBuilder(k8sClient.Scheme()), nil, statusManager)
+		sm = NewStateMachine(resourceManager, statusManager, recorder, nil, nil)
+
+		for _, name := range []string{"envtest-legacy", "envtest-standard"} {
+			Expect(k8sClient.Create(ctx, &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: name},
+				Provisioner: "example.com/" + name,
+			})).To(Succeed())
+		}
+		namespace := &corev1.Namespace{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+		namespace.Annotations = map[string]string{AnnotationDefaultStorageClass: "envtest-standard"}
+		Expect(k8sClient.Update(ctx, namespace)).To(Succeed())
+
+		Expect(k8sClient.Create(ctx, &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: workspaceKey.Name, Namespace: workspaceKey.Namespace},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Storage Class Test",
+				Image:       "jupyter/base-notebook:latest",
+				Storage: &workspacev1alpha1.StorageSpec{
+					Size:             resource.MustParse("1Gi"),
+					StorageClassName: ptr.To("envtest-legacy"),
+				},
+			},
+		})).To(Succeed())
+		_, err := sm.resourceManager.EnsurePVCExists(ctx, getWorkspace())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		for _, obj := range []client.Object{
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: GeneratePVCName(workspaceKey.Name), Namespace: "default"}},
+			&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: workspaceKey.Name, Namespace: workspaceKey.Namespace}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "envtest-legacy"}},
+			&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "envtest-standard"}},
+		} {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err == nil {
+				obj.SetFinalizers(nil)
+				_ = k8sClient.Update(ctx, obj)
+				_ = k8sClient.Delete(ctx, obj)
+			}
+		}
+		namespace := &corev1.Namespace{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, namespace)).To(Succeed())
+		delete(namespace.Annotations, AnnotationDefaultStorageClass)
+		Expect(k8sClient.Update(ctx, namespace)).To(Succeed())
+	})
+
+	It("should report the unbound PVC and recreate it under the namespace default StorageClass on request", func() {
+		By("deleting the StorageClass of the unbound PVC")
+		Expect(k8sClient.Delete(ctx, &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "envtest-legacy"}})).To(Succeed())
+		workspace := getWorkspace()
+		sm.reconcileStorageDegraded(ctx, workspace, getPVC())
+		condition := FindCondition(&workspace.Status.Conditions, ConditionTypeStorageDegraded)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(ReasonStorageUnprovisionable))
+		Expect(<-recorder.Events).To(ContainSubstring("Warning StorageUnprovisionable"))
+
+		By("requesting the recreation while a finalizer holds the PVC")
+		pvc := getPVC()
+		pvc.Finalizers = []string{holdFinalizer}
+		Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+		workspace.Annotations = map[string]string{AnnotationRecreateUnboundStorage: "true"}
+		Expect(k8sClient.Update(ctx, workspace)).To(Succeed())
+
+		recreating, err := sm.recreateUnprovisionableStorage(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recreating).To(BeTrue())
+		Expect(<-recorder.Events).To(ContainSubstring("Normal StorageRecreated"))
+		Expect(getWorkspace().Spec.Storage.StorageClassName).To(Equal(ptr.To("envtest-standard")))
+		Expect(getWorkspace().Annotations).NotTo(HaveKey(AnnotationRecreateUnboundStorage))
+
+		By("waiting for the PVC to go away")
+		recreating, err = sm.recreateUnprovisionableStorage(ctx, getWorkspace())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recreating).To(BeTrue())
+		pvc = getPVC()
+		pvc.Finalizers = nil
+		Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{}))
+		}).Should(BeTrue())
+
+		By("creating the PVC again under the new StorageClass")
+		workspace = getWorkspace()
+		recreating, err = sm.recreateUnprovisionableStorage(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recreating).To(BeFalse())
+		pvc, err = sm.resourceManager.EnsurePVCExists(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvc.Spec.StorageClassName).To(Equal(ptr.To("envtest-standard")))
+	})
+})
@@ -0,0 +1,149 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// pvcRequestedSize returns the storage size the PVC requests
+func pvcRequestedSize(pvc *corev1.PersistentVolumeClaim) resource.Quantity {
+	return pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
+// storageExpansionBlocked returns why the PVC cannot grow to the storage size of the workspace, or an empty
+// string when the workspace does not grow it or its StorageClass allows volume expansion
+func (rm *ResourceManager) storageExpansionBlocked(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+	workspace *workspacev1alpha1.Workspace) (string, error) {
+	desired := resolveStorageSize(workspace)
+	current := pvcRequestedSize(pvc)
+	if current.IsZero() || desired.Cmp(current) <= 0 {
+		return "", nil
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return fmt.Sprintf("Cannot expand the volume from %s to %s: PVC %s has no StorageClass",
+			current.String(), desired.String(), pvc.Name), nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := rm.client.Get(ctx, types.NamespacedName{Name: *pvc.Spec.StorageClassName}, storageClass); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Sprintf("Cannot expand the volume from %s to %s: StorageClass %s not found",
+				current.String(), desired.String(), *pvc.Spec.StorageClassName), nil
+		}
+		return "", fmt.Errorf("failed to get StorageClass %s: %w", *pvc.Spec.StorageClassName, err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return fmt.Sprintf("Cannot expand the volume from %s to %s: StorageClass %s does not allow volume expansion",
+			current.String(), desired.String(), storageClass.Name), nil
+	}
+	return "", nil
+}
+
+// withResizableStorageSize returns the workspace the PVC is updated from: a copy keeping the size of the PVC
+// when the workspace would shrink it or its StorageClass cannot expand it, the workspace otherwise
+func (rm *ResourceManager) withResizableStorageSize(
+	ctx context.Context,
+	pvc *corev1.PersistentVolumeClaim,
+	workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.Workspace, error) {
+	current := pvcRequestedSize(pvc)
+	if current.IsZero() {
+		return workspace, nil
+	}
+	blocked, err := rm.storageExpansionBlocked(ctx, pvc, workspace)
+	if err != nil {
+		return nil, err
+	}
+	// Bound claims cannot shrink; the webhook rejects workspaces lowering their size
+	desired := resolveStorageSize(workspace)
+	if blocked == "" && desired.Cmp(current) >= 0 {
+		return workspace, nil
+	}
+	sized := workspace.DeepCopy()
+	sized.Spec.Storage.Size = current
+	return sized, nil
+}
+
+// reconcileStorageResizing records in the StorageResizing condition the expansion of the PVC of the workspace,
+// from the request of the PVC growing until the filesystem resize completes. The status is updated in memory.
+// Failures to read the StorageClass are logged: resize reporting never blocks a start.
+func (sm *StateMachine) reconcileStorageResizing(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace,
+	pvc *corev1.PersistentVolumeClaim) {
+	if pvc == nil {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeStorageResizing)
+		return
+	}
+
+	blocked, err := sm.resourceManager.storageExpansionBlocked(ctx, pvc, workspace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to check the StorageClass of the workspace PVC")
+		return
+	}
+
+	previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageResizing)
+	status, reason, message := observeStorageResize(pvc, blocked)
+	if reason == ReasonStorageResized && previous == nil {
+		// The PVC was never resized
+		return
+	}
+	if (reason == ReasonExpansionNotSupported || reason == ReasonStorageResizeFailed) &&
+		(previous == nil || previous.Reason != reason) {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, reason, message)
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeStorageResizing, status, reason, message))
+}
+
+// observeStorageResize returns the StorageResizing condition of the PVC: True while the volume or its filesystem
+// is expanded, from the conditions of the PVC and its capacity, False once the capacity reaches the request or
+// when the expansion is blocked
+func observeStorageResize(pvc *corev1.PersistentVolumeClaim, blocked string) (metav1.ConditionStatus, string, string) {
+	if blocked != "" {
+		return metav1.ConditionFalse, ReasonExpansionNotSupported, blocked
+	}
+
+	requested := pvcRequestedSize(pvc)
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case corev1.PersistentVolumeClaimControllerResizeError, corev1.PersistentVolumeClaimNodeResizeError:
+			return metav1.ConditionTrue, ReasonStorageResizeFailed,
+				fmt.Sprintf("Expanding the volume to %s failed: %s", requested.String(), condition.Message)
+		case corev1.PersistentVolumeClaimFileSystemResizePending:
+			return metav1.ConditionTrue, ReasonFileSystemResizePending,
+				fmt.Sprintf("The volume was expanded to %s; its filesystem is resized when the workspace pod mounts it",
+					requested.String())
+		case corev1.PersistentVolumeClaimResizing:
+			return metav1.ConditionTrue, ReasonVolumeResizing,
+				fmt.Sprintf("Expanding the volume to %s", requested.String())
+		}
+	}
+
+	capacity, known := pvc.Status.Capacity[corev1.ResourceStorage]
+	if known && capacity.Cmp(requested) < 0 {
+		return metav1.ConditionTrue, ReasonVolumeResizing,
+			fmt.Sprintf("Expanding the volume from %s to %s", capacity.String(), requested.String())
+	}
+	return metav1.ConditionFalse, ReasonStorageResized, fmt.Sprintf("The volume has its requested size of %s", requested.String())
+}
@@ -0,0 +1,146 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newResizeTestWorkspace returns an available workspace whose 1Gi PVC was created with the storage class
+func newResizeTestWorkspace(t *testing.T, storageClass *storagev1.StorageClass) (*StateMachine, *workspacev1alpha1.Workspace) {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Storage.StorageClassName = ptr.To(storageClass.Name)
+	workspace.Status.Conditions = []metav1.Condition{
+		NewCondition(ConditionTypeAvailable, metav1.ConditionTrue, ReasonResourcesReady, "")}
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, storageClass)
+	_, err := sm.resourceManager.EnsurePVCExists(context.Background(), workspace)
+	require.NoError(t, err)
+	return sm, workspace
+}
+
+// getResizeTestPVC returns the PVC of the workspace
+func getResizeTestPVC(t *testing.T, sm *StateMachine, workspace *workspacev1alpha1.Workspace) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, sm.resourceManager.client.Get(context.Background(),
+		types.NamespacedName{Name: pvcNameFor(workspace), Namespace: workspace.Namespace}, pvc))
+	return pvc
+}
+
+// getResizeTestPVCSize returns the storage size the PVC of the workspace requests
+func getResizeTestPVCSize(t *testing.T, sm *StateMachine, workspace *workspacev1alpha1.Workspace) string {
+	size := pvcRequestedSize(getResizeTestPVC(t, sm, workspace))
+	return size.String()
+}
+
+func TestEnsurePVCExistsExpandsTheVolumeOfAnExpandableStorageClass(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newResizeTestWorkspace(t, &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "expandable"}, AllowVolumeExpansion: ptr.To(true)})
+
+	workspace.Spec.Storage.Size = resource.MustParse("5Gi")
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "5Gi", getResizeTestPVCSize(t, sm, workspace))
+
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+	sm.reconcileStorageResizing(ctx, workspace, pvc)
+	assert.True(t, apimeta.IsStatusConditionTrue(workspace.Status.Conditions, ConditionTypeStorageResizing),
+		"the PVC has no capacity of the new size yet")
+
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}
+	sm.reconcileStorageResizing(ctx, workspace, pvc)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageResizing)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonStorageResized, condition.Reason)
+}
+
+func TestEnsurePVCExistsKeepsTheVolumeOfAStorageClassWithoutExpansion(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newResizeTestWorkspace(t, &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}})
+
+	workspace.Spec.Storage.Size = resource.MustParse("5Gi")
+	pvc, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "1Gi", getResizeTestPVCSize(t, sm, workspace))
+
+	sm.reconcileStorageResizing(ctx, workspace, pvc)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageResizing)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonExpansionNotSupported, condition.Reason)
+	assert.Contains(t, condition.Message, "StorageClass fixed does not allow volume expansion")
+}
+
+func TestEnsurePVCExistsNeverShrinksTheVolume(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newResizeTestWorkspace(t, &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "expandable"}, AllowVolumeExpansion: ptr.To(true)})
+
+	workspace.Spec.Storage.Size = resource.MustParse("500Mi")
+	_, err := sm.resourceManager.EnsurePVCExists(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "1Gi", getResizeTestPVCSize(t, sm, workspace))
+}
+
+func TestReconcileStorageResizingAddsNoConditionToANeverResizedVolume(t *testing.T) {
+	ctx := context.Background()
+	sm, workspace := newResizeTestWorkspace(t, &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}})
+	pvc := getResizeTestPVC(t, sm, workspace)
+	pvc.Status.Capacity = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}
+
+	sm.reconcileStorageResizing(ctx, workspace, pvc)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeStorageResizing))
+}
+
+func TestObserveStorageResize(t *testing.T) {
+	for name, tc := range map[string]struct {
+		capacity       string
+		conditions     []corev1.PersistentVolumeClaimCondition
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		"capacity below the request": {"1Gi", nil, metav1.ConditionTrue, ReasonVolumeResizing},
+		"volume resizing": {"1Gi", []corev1.PersistentVolumeClaimCondition{
+			{Type: corev1.PersistentVolumeClaimResizing, Status: corev1.ConditionTrue}},
+			metav1.ConditionTrue, ReasonVolumeResizing},
+		"filesystem resize pending": {"1Gi", []corev1.PersistentVolumeClaimCondition{
+			{Type: corev1.PersistentVolumeClaimFileSystemResizePending, Status: corev1.ConditionTrue}},
+			metav1.ConditionTrue, ReasonFileSystemResizePending},
+		"resize failed": {"1Gi", []corev1.PersistentVolumeClaimCondition{
+			{Type: corev1.PersistentVolumeClaimControllerResizeError, Status: corev1.ConditionTrue, Message: "quota"}},
+			metav1.ConditionTrue, ReasonStorageResizeFailed},
+		"resize completed": {"5Gi", nil, metav1.ConditionFalse, ReasonStorageResized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pvc := &corev1.PersistentVolumeClaim{
+				Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")}}},
+				Status: corev1.PersistentVolumeClaimStatus{
+					Capacity:   corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(tc.capacity)},
+					Conditions: tc.conditions,
+				},
+			}
+			status, reason, _ := observeStorageResize(pvc, "")
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
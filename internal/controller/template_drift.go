@@ -0,0 +1,103 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ReconcileTemplateChecksum records the checksum of the template content the workspace resolved in
+// Status.ResolvedTemplate. Workspaces following their template record each new content. Workspaces
+// pinning their template generation keep the checksum recorded first, and changes made to the template
+// since are reported in the TemplateDrifted condition, so that a shared template altered after workspaces
+// bound to it is noticed. The status is updated in memory.
+// When the template cannot be found, the recorded checksum is kept.
+func (rm *ResourceManager) ReconcileTemplateChecksum(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	key := client.ObjectKeyFromObject(workspace)
+	if rm.templateResolver == nil || workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		workspace.Status.ResolvedTemplate = nil
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted)
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateMissing)
+		templateDriftedWorkspaces.remove(key)
+		return nil
+	}
+
+	resolved, err := rm.templateResolver.ResolveTemplateRevision(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateMissing)
+
+	recorded := workspace.Status.ResolvedTemplate
+	pinned := workspace.Spec.TemplateRef.UpdatePolicy == workspacev1alpha1.TemplateUpdatePolicyPin
+	if recorded == nil || recorded.Name != resolved.Name || recorded.Namespace != resolved.Namespace || !pinned {
+		checksum, err := workspaceutil.TemplateSpecChecksum(&resolved.Spec)
+		if err != nil {
+			return err
+		}
+		workspace.Status.ResolvedTemplate = &workspacev1alpha1.ResolvedTemplateStatus{
+			Name:       resolved.Name,
+			Namespace:  resolved.Namespace,
+			Generation: resolved.Generation,
+			Checksum:   checksum,
+		}
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted)
+		templateDriftedWorkspaces.remove(key)
+		return nil
+	}
+
+	// The pinned workspace resolves its admitted revision: compare the current template to it
+	current, err := rm.templateResolver.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		return err
+	}
+	checksum, err := workspaceutil.TemplateSpecChecksum(&current.Spec)
+	if err != nil {
+		return err
+	}
+	templateKey := types.NamespacedName{Name: current.Name, Namespace: current.Namespace}
+	if checksum == recorded.Checksum {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted)
+		templateDriftedWorkspaces.remove(key)
+		return nil
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeTemplateDrifted,
+		metav1.ConditionTrue,
+		ReasonTemplateContentChanged,
+		fmt.Sprintf("Template %s/%s changed since generation %d (checksum %s) was resolved: generation %d has checksum %s. "+
+			"The workspace keeps its pinned template; set templateRef.updatePolicy to Follow to take the changes",
+			current.Namespace, current.Name, recorded.Generation, recorded.Checksum, current.Generation, checksum),
+	))
+	templateDriftedWorkspaces.set(key, templateKey, true)
+	return nil
+}
+
+// templateContentChangedPredicate only passes WorkspaceTemplate updates that change the template spec
+func templateContentChangedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration()
+		},
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newDriftTestTemplate(generation int64, image string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team-a", Generation: generation},
+		Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Base", DefaultImage: image},
+	}
+}
+
+func newDriftTestWorkspace(policy workspacev1alpha1.TemplateUpdatePolicy, generation string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "base", UpdatePolicy: policy}
+	workspace.Annotations = map[string]string{AnnotationTemplateGeneration: generation}
+	return workspace
+}
+
+func templateDriftedGaugeValue() float64 {
+	return testutil.ToFloat64(templateDriftedWorkspacesGauge.WithLabelValues("team-a", "base"))
+}
+
+func TestReconcileTemplateChecksumFollowRecordsNewContent(t *testing.T) {
+	ctx := context.Background()
+	template := newDriftTestTemplate(1, "jupyter/base-notebook:2025.01")
+	workspace := newDriftTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyFollow, "1")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	recorded := workspace.Status.ResolvedTemplate
+	require.NotNil(t, recorded)
+	assert.Equal(t, "base", recorded.Name)
+	assert.Equal(t, "team-a", recorded.Namespace)
+	assert.Equal(t, int64(1), recorded.Generation)
+	expected, err := workspaceutil.TemplateSpecChecksum(&template.Spec)
+	require.NoError(t, err)
+	assert.Equal(t, expected, recorded.Checksum)
+
+	// The webhook admits the workspace against the new generation
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(template), template))
+	template.Spec.DefaultImage = "jupyter/base-notebook:2025.02"
+	require.NoError(t, k8sClient.Update(ctx, template))
+	workspace.Annotations[AnnotationTemplateGeneration] = "0"
+
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	assert.NotEqual(t, expected, workspace.Status.ResolvedTemplate.Checksum)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted))
+}
+
+func TestReconcileTemplateChecksumPinReportsDrift(t *testing.T) {
+	ctx := context.Background()
+	admitted := newDriftTestTemplate(1, "jupyter/base-notebook:2025.01")
+	revision, err := workspaceutil.NewTemplateRevision(admitted)
+	require.NoError(t, err)
+	template := newDriftTestTemplate(2, "jupyter/base-notebook:2025.02")
+	workspace := newDriftTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyPin, "1")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template, revision)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	defer templateDriftedWorkspaces.remove(client.ObjectKeyFromObject(workspace))
+
+	// The checksum of the admitted revision is recorded first
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	expected, err := workspaceutil.TemplateSpecChecksum(&admitted.Spec)
+	require.NoError(t, err)
+	require.NotNil(t, workspace.Status.ResolvedTemplate)
+	assert.Equal(t, int64(1), workspace.Status.ResolvedTemplate.Generation)
+	assert.Equal(t, expected, workspace.Status.ResolvedTemplate.Checksum)
+
+	// The template has changed since
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonTemplateContentChanged, condition.Reason)
+	assert.Contains(t, condition.Message, "since generation 1")
+	assert.Contains(t, condition.Message, "generation 2")
+	assert.Equal(t, expected, workspace.Status.ResolvedTemplate.Checksum)
+	assert.Equal(t, float64(1), templateDriftedGaugeValue())
+
+	// Reverting the template content clears the drift
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(template), template))
+	template.Spec.DefaultImage = "jupyter/base-notebook:2025.01"
+	require.NoError(t, k8sClient.Update(ctx, template))
+
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted))
+	assert.Equal(t, float64(0), templateDriftedGaugeValue())
+}
+
+func TestReconcileTemplateChecksumWithoutTemplateClearsStatus(t *testing.T) {
+	ctx := context.Background()
+	admitted := newDriftTestTemplate(1, "jupyter/base-notebook:2025.01")
+	revision, err := workspaceutil.NewTemplateRevision(admitted)
+	require.NoError(t, err)
+	template := newDriftTestTemplate(2, "jupyter/base-notebook:2025.02")
+	workspace := newDriftTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyPin, "1")
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template, revision)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	require.NotNil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted))
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(ctx, workspace))
+	assert.Nil(t, workspace.Status.ResolvedTemplate)
+	assert.Nil(t, FindCondition(&workspace.Status.Conditions, ConditionTypeTemplateDrifted))
+	assert.Equal(t, float64(0), templateDriftedGaugeValue())
+}
+
+func TestReconcileTemplateChecksumKeepsRecordWhenTemplateIsMissing(t *testing.T) {
+	workspace := newDriftTestWorkspace(workspacev1alpha1.TemplateUpdatePolicyPin, "1")
+	workspace.Status.ResolvedTemplate = &workspacev1alpha1.ResolvedTemplateStatus{
+		Name: "base", Namespace: "team-a", Generation: 1, Checksum: "sha256:recorded",
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ReconcileTemplateChecksum(context.Background(), workspace))
+	require.NotNil(t, workspace.Status.ResolvedTemplate)
+	assert.Equal(t, "sha256:recorded", workspace.Status.ResolvedTemplate.Checksum)
+}
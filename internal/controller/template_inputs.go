@@ -0,0 +1,81 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// templateInputResolver records in the status of the workspace one input of its pod taken from the admitted
+// template revision, nil for workspaces without a template
+type templateInputResolver struct {
+	input   string
+	resolve func(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error
+}
+
+// templateInputResolvers returns the resolvers of the pod inputs taken from the template, in resolution order
+func (sm *StateMachine) templateInputResolvers() []templateInputResolver {
+	rm := sm.resourceManager
+	return []templateInputResolver{
+		{input: "child metadata", resolve: rm.ResolveChildMetadata},
+		{input: "cluster access", resolve: rm.ResolveClusterAccess},
+		{input: "accelerators", resolve: rm.ResolveAccelerators},
+		{input: "scheduling defaults", resolve: rm.ResolveScheduling},
+		{input: "shared volumes", resolve: rm.ResolveSharedVolumes},
+		{input: "image pull secrets", resolve: rm.ResolveImagePullSecrets},
+		{input: "image pinning", resolve: rm.ResolveImageDigest},
+		{input: "server type", resolve: rm.ResolveServerType},
+		{input: "sidecars", resolve: rm.ResolveSidecars},
+		{input: "isolation level", resolve: sm.resolveIsolationLevel},
+		{input: "init containers", resolve: rm.ResolveInitContainers},
+		{input: "post-start script", resolve: rm.ResolvePostStartScript},
+	}
+}
+
+// ResolveAdmittedTemplate returns the template of the workspace at the revision the workspace was admitted
+// against, nil when the workspace has no template. A template that cannot be found is returned as a NotFound
+// error.
+func (rm *ResourceManager) ResolveAdmittedTemplate(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.WorkspaceTemplate, error) {
+	if rm.templateResolver == nil || workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return nil, nil
+	}
+
+	// Template changes made after admission apply once the workspace is admitted again
+	template, err := rm.templateResolver.ResolveTemplateRevision(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+	if admitted := workspaceutil.AdmittedTemplateGeneration(workspace); admitted != 0 && admitted != template.Generation {
+		logf.FromContext(ctx).Info("Admitted template revision is no longer kept, using the current template",
+			"template", template.Name, "admittedGeneration", admitted, "generation", template.Generation)
+	}
+	return template, nil
+}
+
+// resolveTemplateInputs resolves the admitted template revision once, then records the pod inputs taken from
+// it, so that the pod spec of a reconcile never mixes template generations. When the template cannot be found,
+// the previously resolved inputs are kept. The status is updated in memory.
+func (sm *StateMachine) resolveTemplateInputs(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	template, err := sm.resourceManager.ResolveAdmittedTemplate(ctx, workspace)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to resolve the admitted template revision: %w", err)
+	}
+	for _, resolver := range sm.templateInputResolvers() {
+		if err := resolver.resolve(ctx, workspace, template); err != nil {
+			return fmt.Errorf("failed to resolve template %s: %w", resolver.input, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const (
+	// TemplateLabelMigrationVersion is the version of the template label contract the migration brings existing
+	// workspaces to: both template labels set from spec.templateRef, and status.resolvedTemplate recorded.
+	// Bump it when the contract changes, for the migration to run again.
+	TemplateLabelMigrationVersion = 1
+
+	// MigrationStateConfigMapName is the name of the ConfigMap, in the controller namespace, recording the
+	// migrations that completed
+	MigrationStateConfigMapName = "jupyter-k8s-migration-state"
+
+	// MigrationStateKeyTemplateLabels is the key of the MigrationStateConfigMapName ConfigMap recording the
+	// template label migration version that completed
+	MigrationStateKeyTemplateLabels = "template-labels"
+
+	// TemplateLabelMigrationRetryDelay is the delay before the migration runs again after it failed
+	TemplateLabelMigrationRetryDelay = time.Minute
+)
+
+// TemplateLabelMigration backfills the template labels and status.resolvedTemplate of workspaces created before
+// the template-namespace label existed, or labelled with legacy keys, so that the template usage index and the
+// template finalizer see them. It runs once on startup; a marker in the controller namespace records that it
+// completed, so that later starts skip it. Until it completes, templates keep their finalizer.
+type TemplateLabelMigration struct {
+	client   client.Client
+	reader   client.Reader
+	resolver *workspaceutil.TemplateResolver
+
+	// namespace is the controller namespace holding the migration marker; no marker is kept when empty
+	namespace string
+	// legacyLabelKeys are label keys that named the template of a workspace in earlier deployments
+	legacyLabelKeys []string
+	scope           *workspaceutil.Scope
+
+	completed atomic.Bool
+}
+
+// NewTemplateLabelMigration creates a new TemplateLabelMigration
+func NewTemplateLabelMigration(
+	k8sClient client.Client,
+	reader client.Reader,
+	defaultTemplateNamespace string,
+	namespace string,
+	legacyLabelKeys []string,
+	scope *workspaceutil.Scope,
+) *TemplateLabelMigration {
+	return &TemplateLabelMigration{
+		client:          k8sClient,
+		reader:          reader,
+		resolver:        workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+		namespace:       namespace,
+		legacyLabelKeys: legacyLabelKeys,
+		scope:           scope,
+	}
+}
+
+// Completed returns true once every workspace follows the template label contract. A nil migration is complete.
+func (m *TemplateLabelMigration) Completed() bool {
+	return m == nil || m.completed.Load()
+}
+
+// Start runs the migration unless the marker records it completed, retrying until it does.
+// Implements the controller-runtime Runnable interface.
+func (m *TemplateLabelMigration) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("template-label-migration")
+	ctx = logf.IntoContext(ctx, logger)
+
+	for {
+		err := m.run(ctx)
+		if err == nil {
+			m.completed.Store(true)
+			return nil
+		}
+		logger.Error(err, "Template label migration failed, retrying", "retryDelay", TemplateLabelMigrationRetryDelay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(TemplateLabelMigrationRetryDelay):
+		}
+	}
+}
+
+// NeedLeaderElection returns true because the migration updates workspaces.
+func (m *TemplateLabelMigration) NeedLeaderElection() bool {
+	return true
+}
+
+// run migrates every workspace in scope, then records the marker
+func (m *TemplateLabelMigration) run(ctx context.Context) error {
+	logger := logf.FromContext(ctx)
+
+	completedVersion, err := m.completedVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if completedVersion >= TemplateLabelMigrationVersion {
+		logger.V(1).Info("Template label migration already completed", "version", completedVersion)
+		return nil
+	}
+
+	logger.Info("Running template label migration", "version", TemplateLabelMigrationVersion)
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := workspaceutil.ListAll(ctx, m.client, workspaces); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	migrated, unresolved, paused := 0, 0, 0
+	for i := range workspaces.Items {
+		workspace := &workspaces.Items[i]
+		if !workspace.DeletionTimestamp.IsZero() || !m.scope.Matches(workspace) {
+			continue
+		}
+		// Paused workspaces are not mutated, not even by the migration
+		if IsReconciliationPaused(workspace) {
+			logger.Info("Skipping template label migration of paused workspace",
+				"workspace", workspace.Name, "workspaceNamespace", workspace.Namespace)
+			paused++
+			continue
+		}
+		changed, resolved, err := m.migrateWorkspace(ctx, workspace)
+		if err != nil {
+			return fmt.Errorf("failed to migrate workspace %s/%s: %w", workspace.Namespace, workspace.Name, err)
+		}
+		if changed {
+			migrated++
+		}
+		if !resolved {
+			unresolved++
+		}
+	}
+
+	if err := m.recordCompleted(ctx); err != nil {
+		return err
+	}
+	logger.Info("Template label migration completed", "version", TemplateLabelMigrationVersion,
+		"migrated", migrated, "unresolved", unresolved, "paused", paused)
+	return nil
+}
+
+// migrateWorkspace sets the template labels of the workspace from spec.templateRef and removes its legacy labels,
+// then resolves its template read-only: the resolved template is recorded in status.resolvedTemplate when missing,
+// and the TemplateMissing condition flags a template that cannot be found. It returns whether the workspace
+// changed, and whether its template resolved.
+func (m *TemplateLabelMigration) migrateWorkspace(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) (bool, bool, error) {
+	logger := logf.FromContext(ctx).WithValues("workspace", workspace.Name, "workspaceNamespace", workspace.Namespace)
+
+	labels := templateContractLabels(workspace, m.legacyLabelKeys)
+	labelsChanged := !maps.Equal(labels, workspace.Labels)
+	if labelsChanged {
+		patch := client.MergeFrom(workspace.DeepCopy())
+		workspace.Labels = labels
+		if err := m.client.Patch(ctx, workspace, patch); err != nil {
+			return false, true, client.IgnoreNotFound(err)
+		}
+		logger.Info("Backfilled workspace template labels")
+	}
+
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		return labelsChanged, true, nil
+	}
+
+	statusPatch := client.MergeFrom(workspace.DeepCopy())
+	resolved := true
+	template, err := m.resolver.ResolveTemplateRevision(ctx, workspace)
+	switch {
+	case apierrors.IsNotFound(err):
+		resolved = false
+		logger.Info("Template of workspace not found", "template", workspace.Spec.TemplateRef.Name)
+		apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+			ConditionTypeTemplateMissing,
+			metav1.ConditionTrue,
+			ReasonTemplateNotFound,
+			fmt.Sprintf("Template %s/%s was not found", workspaceutil.GetTemplateRefNamespace(workspace), workspace.Spec.TemplateRef.Name),
+		))
+	case err != nil:
+		return labelsChanged, false, err
+	default:
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeTemplateMissing)
+		if workspace.Status.ResolvedTemplate == nil {
+			checksum, err := workspaceutil.TemplateSpecChecksum(&template.Spec)
+			if err != nil {
+				return labelsChanged, true, err
+			}
+			workspace.Status.ResolvedTemplate = &workspacev1alpha1.ResolvedTemplateStatus{
+				Name:       template.Name,
+				Namespace:  template.Namespace,
+				Generation: template.Generation,
+				Checksum:   checksum,
+			}
+		}
+	}
+
+	statusChanged, err := patchChangedStatus(ctx, m.client, workspace, statusPatch)
+	if err != nil {
+		return labelsChanged, resolved, client.IgnoreNotFound(err)
+	}
+	return labelsChanged || statusChanged, resolved, nil
+}
+
+// templateContractLabels returns the labels of the workspace with both template labels set from spec.templateRef,
+// or removed when it references no template, and without its legacy template labels
+func templateContractLabels(workspace *workspacev1alpha1.Workspace, legacyLabelKeys []string) map[string]string {
+	labels := maps.Clone(workspace.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for _, key := range legacyLabelKeys {
+		delete(labels, key)
+	}
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.Name == "" {
+		delete(labels, workspaceutil.LabelWorkspaceTemplate)
+		delete(labels, workspaceutil.LabelWorkspaceTemplateNamespace)
+		return labels
+	}
+	labels[workspaceutil.LabelWorkspaceTemplate] = workspace.Spec.TemplateRef.Name
+	labels[workspaceutil.LabelWorkspaceTemplateNamespace] = workspaceutil.GetTemplateRefNamespace(workspace)
+	return labels
+}
+
+// patchChangedStatus patches the status of the workspace when it differs from the patch base,
+// and returns whether it did
+func patchChangedStatus(ctx context.Context, k8sClient client.Client, workspace *workspacev1alpha1.Workspace, patch client.Patch) (bool, error) {
+	data, err := patch.Data(workspace)
+	if err != nil {
+		return false, fmt.Errorf("failed to compute status patch: %w", err)
+	}
+	if string(data) == "{}" {
+		return false, nil
+	}
+	if err := k8sClient.Status().Patch(ctx, workspace, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// completedVersion returns the migration version the marker records, 0 when none completed
+func (m *TemplateLabelMigration) completedVersion(ctx context.Context) (int, error) {
+	if m.namespace == "" {
+		return 0, nil
+	}
+	state := &corev1.ConfigMap{}
+	err := m.reader.Get(ctx, client.ObjectKey{Name: MigrationStateConfigMapName, Namespace: m.namespace}, state)
+	if apierrors.IsNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get migration state: %w", err)
+	}
+	version, err := strconv.Atoi(state.Data[MigrationStateKeyTemplateLabels])
+	if err != nil {
+		return 0, nil
+	}
+	return version, nil
+}
+
+// recordCompleted records in the marker that the current migration version completed
+func (m *TemplateLabelMigration) recordCompleted(ctx context.Context) error {
+	if m.namespace == "" {
+		logf.FromContext(ctx).Info("Controller namespace unknown, the template label migration runs again on next start",
+			"envVar", ControllerPodNamespaceEnv)
+		return nil
+	}
+	version := strconv.Itoa(TemplateLabelMigrationVersion)
+	state := &corev1.ConfigMap{}
+	err := m.reader.Get(ctx, client.ObjectKey{Name: MigrationStateConfigMapName, Namespace: m.namespace}, state)
+	if apierrors.IsNotFound(err) {
+		state = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: MigrationStateConfigMapName, Namespace: m.namespace},
+			Data:       map[string]string{MigrationStateKeyTemplateLabels: version},
+		}
+		if err := m.client.Create(ctx, state); err != nil {
+			return fmt.Errorf("failed to record migration state: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get migration state: %w", err)
+	}
+	if state.Data == nil {
+		state.Data = map[string]string{}
+	}
+	state.Data[MigrationStateKeyTemplateLabels] = version
+	if err := m.client.Update(ctx, state); err != nil {
+		return fmt.Errorf("failed to record migration state: %w", err)
+	}
+	return nil
+}
+
+// SetupTemplateLabelMigration adds the template label migration to the manager and returns it, for the
+// template controller to wait for its completion
+func SetupTemplateLabelMigration(
+	mgr ctrl.Manager,
+	defaultTemplateNamespace string,
+	legacyLabelKeys []string,
+	scope *workspaceutil.Scope,
+) (*TemplateLabelMigration, error) {
+	migration := NewTemplateLabelMigration(
+		newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout),
+		newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
+		defaultTemplateNamespace,
+		os.Getenv(ControllerPodNamespaceEnv),
+		legacyLabelKeys,
+		scope,
+	)
+	if err := mgr.Add(migration); err != nil {
+		return nil, fmt.Errorf("failed to add template label migration: %w", err)
+	}
+	return migration, nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+const legacyTestTemplateLabel = "jupyter.example.com/template"
+
+// newLegacyTestWorkspace returns a workspace shaped like those created before the template-namespace label:
+// it references the template, with only the template name label or a legacy label key
+func newLegacyTestWorkspace(name string, labels map[string]string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace(name)
+	workspace.Labels = labels
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "base"}
+	return workspace
+}
+
+func newMigrationTestClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}, &workspacev1alpha1.WorkspaceTemplate{}).
+		Build()
+}
+
+func getMigrationTestWorkspace(t *testing.T, k8sClient client.Client, name string) *workspacev1alpha1.Workspace {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "team-a"}, workspace))
+	return workspace
+}
+
+func TestTemplateLabelMigrationBackfillsLegacyWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	nameOnly := newLegacyTestWorkspace("ws-name-only", map[string]string{workspaceutil.LabelWorkspaceTemplate: "base"})
+	legacyKey := newLegacyTestWorkspace("ws-legacy-key", map[string]string{legacyTestTemplateLabel: "base", "team": "ml"})
+	unlabelled := newLegacyTestWorkspace("ws-unlabelled", nil)
+	k8sClient := newMigrationTestClient(t, newRevisionTestTemplate(), nameOnly, legacyKey, unlabelled)
+
+	// The usage index does not see the legacy workspaces
+	used, err := workspaceutil.HasActiveWorkspacesWithTemplate(ctx, k8sClient, "base", "team-a")
+	require.NoError(t, err)
+	require.False(t, used)
+
+	migration := NewTemplateLabelMigration(k8sClient, k8sClient, "", "jupyter-k8s-system",
+		[]string{legacyTestTemplateLabel}, nil)
+	require.False(t, migration.Completed())
+	require.NoError(t, migration.Start(ctx))
+	assert.True(t, migration.Completed())
+
+	workspaces, _, err := workspaceutil.ListActiveWorkspacesByTemplate(ctx, k8sClient, "base", "team-a", "", 0)
+	require.NoError(t, err)
+	assert.Len(t, workspaces, 3)
+
+	migrated := getMigrationTestWorkspace(t, k8sClient, "ws-legacy-key")
+	assert.Equal(t, map[string]string{
+		workspaceutil.LabelWorkspaceTemplate:          "base",
+		workspaceutil.LabelWorkspaceTemplateNamespace: "team-a",
+		"team": "ml",
+	}, migrated.Labels)
+	require.NotNil(t, migrated.Status.ResolvedTemplate)
+	assert.Equal(t, "base", migrated.Status.ResolvedTemplate.Name)
+	assert.Equal(t, int64(1), migrated.Status.ResolvedTemplate.Generation)
+	assert.NotEmpty(t, migrated.Status.ResolvedTemplate.Checksum)
+
+	state := &corev1.ConfigMap{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: MigrationStateConfigMapName, Namespace: "jupyter-k8s-system"}, state))
+	assert.Equal(t, "1", state.Data[MigrationStateKeyTemplateLabels])
+}
+
+func TestTemplateLabelMigrationFlagsWorkspacesWhoseTemplateIsMissing(t *testing.T) {
+	ctx := context.Background()
+	workspace := newLegacyTestWorkspace("ws", map[string]string{workspaceutil.LabelWorkspaceTemplate: "gone"})
+	workspace.Spec.TemplateRef.Name = "gone"
+	k8sClient := newMigrationTestClient(t, workspace)
+
+	require.NoError(t, NewTemplateLabelMigration(k8sClient, k8sClient, "", "jupyter-k8s-system", nil, nil).Start(ctx))
+
+	migrated := getMigrationTestWorkspace(t, k8sClient, "ws")
+	assert.Equal(t, "team-a", migrated.Labels[workspaceutil.LabelWorkspaceTemplateNamespace])
+	assert.Nil(t, migrated.Status.ResolvedTemplate)
+	condition := FindCondition(&migrated.Status.Conditions, ConditionTypeTemplateMissing)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonTemplateNotFound, condition.Reason)
+	assert.Contains(t, condition.Message, "team-a/gone")
+}
+
+func TestTemplateLabelMigrationSkipsPausedWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	workspace := newLegacyTestWorkspace("ws", map[string]string{legacyTestTemplateLabel: "base"})
+	workspace.Annotations = map[string]string{AnnotationPaused: "true"}
+	k8sClient := newMigrationTestClient(t, newRevisionTestTemplate(), workspace)
+
+	migration := NewTemplateLabelMigration(k8sClient, k8sClient, "", "jupyter-k8s-system",
+		[]string{legacyTestTemplateLabel}, nil)
+	require.NoError(t, migration.Start(ctx))
+
+	assert.True(t, migration.Completed())
+	skipped := getMigrationTestWorkspace(t, k8sClient, "ws")
+	assert.Equal(t, map[string]string{legacyTestTemplateLabel: "base"}, skipped.Labels)
+	assert.Nil(t, skipped.Status.ResolvedTemplate)
+}
+
+func TestTemplateLabelMigrationDoesNotRerunOnceCompleted(t *testing.T) {
+	ctx := context.Background()
+	state := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: MigrationStateConfigMapName, Namespace: "jupyter-k8s-system"},
+		Data:       map[string]string{MigrationStateKeyTemplateLabels: "1"},
+	}
+	workspace := newLegacyTestWorkspace("ws", map[string]string{legacyTestTemplateLabel: "base"})
+	k8sClient := newMigrationTestClient(t, newRevisionTestTemplate(), workspace, state)
+
+	migration := NewTemplateLabelMigration(k8sClient, k8sClient, "", "jupyter-k8s-system",
+		[]string{legacyTestTemplateLabel}, nil)
+	require.NoError(t, migration.Start(ctx))
+
+	assert.True(t, migration.Completed())
+	assert.Equal(t, map[string]string{legacyTestTemplateLabel: "base"}, getMigrationTestWorkspace(t, k8sClient, "ws").Labels)
+}
+
+func TestTemplateKeepsItsFinalizerUntilTheLabelMigrationCompletes(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+	controllerutil.AddFinalizer(template, templateFinalizerName)
+	workspace := newLegacyTestWorkspace("ws", map[string]string{workspaceutil.LabelWorkspaceTemplate: "base"})
+	k8sClient := newMigrationTestClient(t, template, workspace)
+	migration := NewTemplateLabelMigration(k8sClient, k8sClient, "", "", nil, nil)
+	reconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), labelMigration: migration}
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(template)}
+
+	// The workspace is invisible to the usage check: the finalizer stays while the migration is pending
+	result, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter)
+	stored := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+
+	// Once migrated, the usage check sees the workspace and the finalizer keeps protecting the template
+	require.NoError(t, migration.Start(ctx))
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+	assert.Equal(t, int32(1), stored.Status.WorkspaceCount)
+}
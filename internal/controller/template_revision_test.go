@@ -0,0 +1,118 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func newRevisionTestTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team-a", Generation: 1, UID: "base-uid"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName: "Base",
+			ChildMetadata: &workspacev1alpha1.ChildMetadata{
+				Pod: &workspacev1alpha1.ChildObjectMetadata{Labels: map[string]string{"tier": "standard"}},
+			},
+		},
+	}
+}
+
+func TestTemplateChangedAfterAdmissionDoesNotApply(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+
+	// The webhook admitted the workspace against generation 1 and applied its defaults
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Annotations = map[string]string{AnnotationTemplateGeneration: "1"}
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "base"}
+	workspace.Spec.Resources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+	templateReconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+	require.NoError(t, templateReconciler.recordTemplateRevision(ctx, template))
+
+	// The admin updates the template before the first reconcile
+	template.Generation = 2
+	template.Spec.ChildMetadata.Pod.Labels["tier"] = "premium"
+	template.Spec.DefaultResources = &corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")},
+	}
+	require.NoError(t, k8sClient.Update(ctx, template))
+	require.NoError(t, templateReconciler.recordTemplateRevision(ctx, template))
+
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	deployment, err := sm.resourceManager.deploymentBuilder.BuildDeployment(ctx, workspace)
+	require.NoError(t, err)
+	assert.Equal(t, "standard", deployment.Spec.Template.Labels["tier"])
+	cpu := deployment.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]
+	assert.Equal(t, "1", cpu.String())
+
+	// Admitting the workspace again moves it to the new generation
+	workspace.Annotations[AnnotationTemplateGeneration] = "2"
+	require.NoError(t, sm.resolveTemplateInputs(ctx, workspace))
+	assert.Equal(t, "premium", workspace.Status.ChildMetadata.Pod.Labels["tier"])
+}
+
+func TestRecordTemplateRevisionPrunesOldRevisions(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, template)
+	templateReconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+	for generation := int64(1); generation <= workspaceutil.MaxTemplateRevisions+2; generation++ {
+		template.Generation = generation
+		require.NoError(t, templateReconciler.recordTemplateRevision(ctx, template))
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	require.NoError(t, k8sClient.List(ctx, revisions, client.InNamespace("team-a")))
+	assert.Len(t, revisions.Items, workspaceutil.MaxTemplateRevisions)
+	names := map[string]bool{}
+	for _, revision := range revisions.Items {
+		names[revision.Name] = true
+	}
+	assert.False(t, names["base-1"])
+	assert.False(t, names["base-2"])
+	assert.True(t, names[fmt.Sprintf("base-%d", workspaceutil.MaxTemplateRevisions+2)])
+}
+
+func TestRecordTemplateRevisionReplacesRevisionsOfADeletedTemplate(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+
+	// A template of the same name was deleted before the garbage collector removed its revisions
+	deleted := newRevisionTestTemplate()
+	deleted.UID = "deleted-uid"
+	deleted.Spec.DisplayName = "Deleted"
+	foreign, err := workspaceutil.NewTemplateRevision(deleted)
+	require.NoError(t, err)
+
+	_, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, template, foreign)
+	templateReconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+	require.NoError(t, templateReconciler.recordTemplateRevision(ctx, template))
+
+	revision := &appsv1.ControllerRevision{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: "base-1", Namespace: "team-a"}, revision))
+	assert.True(t, metav1.IsControlledBy(revision, template))
+	assert.Contains(t, string(revision.Data.Raw), `"displayName":"Base"`)
+}
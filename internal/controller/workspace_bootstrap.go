@@ -0,0 +1,258 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// GitSyncImage is the image of the init containers cloning the git repositories of the workspace bootstrap
+	GitSyncImage = "registry.k8s.io/git-sync/git-sync:v4.4.0"
+
+	// GitCloneContainerPrefix prefixes the names of the init containers cloning git repositories;
+	// template init containers cannot use it
+	GitCloneContainerPrefix = "git-clone-"
+
+	// MaxTemplateInitContainers is the number of init containers a template may define
+	MaxTemplateInitContainers = 10
+
+	// DefaultBootstrapTimeout is how long the init containers of a workspace pod may take by default
+	// before the workspace is marked failed
+	DefaultBootstrapTimeout = 15 * time.Minute
+
+	// gitSyncRootDir is the directory of the home directory git-sync keeps its clones in; the directory
+	// of a repository is a link to its checkout
+	gitSyncRootDir = ".git-sync"
+)
+
+// ResolveInitContainers records the init containers of the admitted template revision in Status.InitContainers
+// for the deployment builder to apply. The init containers of a workspace whose deployment exists are kept until
+// it restarts. The status is updated in memory. A nil template, for workspaces without one, clears them.
+func (rm *ResourceManager) ResolveInitContainers(ctx context.Context, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template == nil {
+		workspace.Status.InitContainers = nil
+		return nil
+	}
+	if equality.Semantic.DeepEqual(workspace.Status.InitContainers, template.Spec.InitContainers) {
+		return nil
+	}
+
+	exists, err := rm.deploymentExists(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if exists {
+		logDecision(logf.FromContext(ctx), "DeferInitContainerUpdate",
+			"reason", "the template init containers apply when the workspace restarts")
+		return nil
+	}
+
+	workspace.Status.InitContainers = nil
+	for i := range template.Spec.InitContainers {
+		workspace.Status.InitContainers = append(workspace.Status.InitContainers, *template.Spec.InitContainers[i].DeepCopy())
+	}
+	return nil
+}
+
+// applyInitContainers adds to the pod the init containers cloning the git repositories of the workspace
+// bootstrap, then the template init containers resolved in the status of the workspace. Their mounts other
+// than the home directory, and init containers whose name is taken, are skipped, the webhook rejects them.
+func applyInitContainers(podSpec *corev1.PodSpec, workspace *workspacev1alpha1.Workspace) {
+	storageConfig := ResolveStorageConfig(workspace)
+	if workspace.Spec.Bootstrap != nil && storageConfig != nil {
+		for i, repository := range workspace.Spec.Bootstrap.GitRepositories {
+			podSpec.InitContainers = append(podSpec.InitContainers,
+				buildGitCloneContainer(podSpec, i, repository, storageConfig))
+		}
+	}
+	for _, initContainer := range workspace.Status.InitContainers {
+		if containerNameTaken(podSpec, initContainer.Name) {
+			continue
+		}
+		podSpec.InitContainers = append(podSpec.InitContainers, withHomeMountOnly(initContainer, storageConfig))
+	}
+}
+
+// buildGitCloneContainer returns the init container cloning the repository into the home directory, unless
+// its directory exists already. It runs with the user and the resources of the workspace container, so that
+// the checkout belongs to the notebook user and the init container does not raise the requests of the pod.
+func buildGitCloneContainer(
+	podSpec *corev1.PodSpec,
+	index int,
+	repository workspacev1alpha1.GitRepositorySpec,
+	storageConfig *ResolvedStorageConfig) corev1.Container {
+	homePath := storageConfig.MountPath
+	container := corev1.Container{
+		Name:  fmt.Sprintf("%s%d", GitCloneContainerPrefix, index),
+		Image: GitSyncImage,
+		// The repository is read from the environment, not interpolated in the command
+		Command: []string{"sh", "-c", `test -e "$GITSYNC_LINK" || exec /git-sync`},
+		Env: []corev1.EnvVar{
+			{Name: "GITSYNC_REPO", Value: repository.URL},
+			{Name: "GITSYNC_REF", Value: gitRef(repository)},
+			{Name: "GITSYNC_ROOT", Value: path.Join(homePath, gitSyncRootDir, repository.Directory)},
+			{Name: "GITSYNC_LINK", Value: path.Join(homePath, repository.Directory)},
+			{Name: "GITSYNC_ONE_TIME", Value: "true"},
+			{Name: "GITSYNC_DEPTH", Value: "1"},
+			// git writes its configuration in the home of the user, which the image may not have
+			{Name: "HOME", Value: "/tmp"},
+		},
+		VolumeMounts: []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: homePath, SubPath: storageConfig.SubPath}},
+	}
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == workspaceContainerName {
+			container.Resources = *podSpec.Containers[i].Resources.DeepCopy()
+			container.SecurityContext = podSpec.Containers[i].SecurityContext.DeepCopy()
+		}
+	}
+	return container
+}
+
+// gitRef returns the ref git-sync checks out, HEAD for the default branch
+func gitRef(repository workspacev1alpha1.GitRepositorySpec) string {
+	if repository.Ref == "" {
+		return "HEAD"
+	}
+	return repository.Ref
+}
+
+// TemplateInitContainerConflict returns why the template init container cannot run in the workspace pod,
+// or "" when it can
+func TemplateInitContainerConflict(initContainer corev1.Container) string {
+	if strings.HasPrefix(initContainer.Name, GitCloneContainerPrefix) {
+		return fmt.Sprintf("init container name '%s' uses the prefix '%s' of the git clone init containers",
+			initContainer.Name, GitCloneContainerPrefix)
+	}
+	return templateContainerConflict("init container", initContainer)
+}
+
+// bootstrapOutcome is what the init containers of the current workspace pod did
+type bootstrapOutcome struct {
+	// failed is true if an init container failed or they did not complete within the timeout
+	failed bool
+	// timedOut is true if the init containers did not complete within the timeout
+	timedOut bool
+	message  string
+}
+
+// hasBootstrap returns true if the workspace pod runs init containers of the bootstrap or the template
+func hasBootstrap(workspace *workspacev1alpha1.Workspace) bool {
+	return len(workspace.Status.InitContainers) > 0 ||
+		(workspace.Spec.Bootstrap != nil && len(workspace.Spec.Bootstrap.GitRepositories) > 0)
+}
+
+// reconcileBootstrap records in the BootstrapFailed condition whether the init containers of the latest
+// workspace pod failed, completed, or exceeded the bootstrap timeout. The status is updated in memory.
+// Failures to read the pods are logged: bootstrap reporting never blocks a start.
+func (sm *StateMachine) reconcileBootstrap(
+	ctx context.Context,
+	workspace *workspacev1alpha1.Workspace) bootstrapOutcome {
+	if !hasBootstrap(workspace) {
+		apimeta.RemoveStatusCondition(&workspace.Status.Conditions, ConditionTypeBootstrapFailed)
+		return bootstrapOutcome{}
+	}
+
+	podList := &corev1.PodList{}
+	if err := sm.resourceManager.client.List(ctx, podList,
+		client.InNamespace(workspace.Namespace), client.MatchingLabels(GenerateLabels(workspace.Name))); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list workspace pods")
+		return bootstrapOutcome{}
+	}
+	var pod *corev1.Pod
+	for i := range podList.Items {
+		candidate := &podList.Items[i]
+		if candidate.DeletionTimestamp != nil {
+			continue
+		}
+		if pod == nil || pod.CreationTimestamp.Before(&candidate.CreationTimestamp) {
+			pod = candidate
+		}
+	}
+	if pod == nil {
+		return bootstrapOutcome{}
+	}
+
+	status, reason, message := observeBootstrap(pod, sm.bootstrapTimeoutFor(workspace), time.Now())
+	previous := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeBootstrapFailed)
+	if status == metav1.ConditionTrue && (previous == nil || previous.Reason != reason) {
+		sm.recorder.Event(workspace, corev1.EventTypeWarning, reason, message)
+	}
+	apimeta.SetStatusCondition(&workspace.Status.Conditions, NewCondition(
+		ConditionTypeBootstrapFailed, status, reason, message))
+	if status == metav1.ConditionUnknown {
+		return bootstrapOutcome{}
+	}
+	return bootstrapOutcome{
+		failed:   status == metav1.ConditionTrue,
+		timedOut: reason == ReasonBootstrapTimedOut,
+		message:  message,
+	}
+}
+
+// bootstrapTimeoutFor returns how long the init containers of the workspace pod may take
+func (sm *StateMachine) bootstrapTimeoutFor(workspace *workspacev1alpha1.Workspace) time.Duration {
+	if workspace.Spec.Bootstrap != nil && workspace.Spec.Bootstrap.Timeout != nil && workspace.Spec.Bootstrap.Timeout.Duration > 0 {
+		return workspace.Spec.Bootstrap.Timeout.Duration
+	}
+	if sm.bootstrapTimeout > 0 {
+		return sm.bootstrapTimeout
+	}
+	return DefaultBootstrapTimeout
+}
+
+// observeBootstrap returns the BootstrapFailed condition of the pod: False once its init containers completed,
+// True with the exit code of an init container that failed or once the timeout elapsed since the pod was
+// created, Unknown while they run
+func observeBootstrap(pod *corev1.Pod, timeout time.Duration, now time.Time) (metav1.ConditionStatus, string, string) {
+	if condition := findPodCondition(pod, corev1.PodInitialized); condition != nil && condition.Status == corev1.ConditionTrue {
+		return metav1.ConditionFalse, ReasonBootstrapCompleted, "Init containers completed"
+	}
+
+	failure := ""
+	for _, status := range pod.Status.InitContainerStatuses {
+		terminated := status.State.Terminated
+		if terminated == nil || terminated.ExitCode == 0 {
+			// A failed init container waits in back-off before it runs again
+			terminated = status.LastTerminationState.Terminated
+		}
+		if terminated == nil || terminated.ExitCode == 0 {
+			continue
+		}
+		failure = fmt.Sprintf("Init container %q exited with code %d", status.Name, terminated.ExitCode)
+		if terminated.Reason != "" {
+			failure = fmt.Sprintf("%s (%s)", failure, terminated.Reason)
+		}
+		if terminated.Message != "" {
+			failure = fmt.Sprintf("%s: %s", failure, strings.TrimSpace(terminated.Message))
+		}
+		break
+	}
+
+	if !pod.CreationTimestamp.IsZero() && now.Sub(pod.CreationTimestamp.Time) > timeout {
+		message := fmt.Sprintf("Init containers did not complete within %s", timeout)
+		if failure != "" {
+			message = fmt.Sprintf("%s: %s", message, failure)
+		}
+		return metav1.ConditionTrue, ReasonBootstrapTimedOut, message
+	}
+	if failure != "" {
+		return metav1.ConditionTrue, ReasonInitContainerFailed, failure
+	}
+	return metav1.ConditionUnknown, ReasonBootstrapRunning, "Init containers are running"
+}
@@ -0,0 +1,207 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newBootstrapTestPod returns a workspace pod created at createdAt whose init container is in the status
+func newBootstrapTestPod(createdAt time.Time, initStatus corev1.ContainerStatus, initialized bool) *corev1.Pod {
+	pod := newPendingWorkspacePod(nil, nil)
+	pod.CreationTimestamp = metav1.NewTime(createdAt)
+	pod.Status.InitContainerStatuses = []corev1.ContainerStatus{initStatus}
+	if initialized {
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodInitialized, Status: corev1.ConditionTrue}}
+	}
+	return pod
+}
+
+// crashLoopingInitStatus returns the status of an init container that exited with the code and waits to run again
+func crashLoopingInitStatus(exitCode int32) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:  "git-clone-0",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: exitCode, Reason: "Error", Message: "fatal: repository not found\n",
+		}},
+	}
+}
+
+func TestPodsCloneTheBootstrapGitRepositories(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Spec.Bootstrap = &workspacev1alpha1.BootstrapSpec{
+		GitRepositories: []workspacev1alpha1.GitRepositorySpec{
+			{URL: "https://github.com/org/course.git", Directory: "course"},
+			{URL: "https://github.com/org/datasets.git", Ref: "v2", Directory: "datasets"},
+		},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 2)
+	clone := podSpec.InitContainers[1]
+	assert.Equal(t, "git-clone-1", clone.Name)
+	assert.Equal(t, GitSyncImage, clone.Image)
+	assert.Contains(t, clone.Env, corev1.EnvVar{Name: "GITSYNC_REPO", Value: "https://github.com/org/datasets.git"})
+	assert.Contains(t, clone.Env, corev1.EnvVar{Name: "GITSYNC_REF", Value: "v2"})
+	assert.Contains(t, clone.Env, corev1.EnvVar{Name: "GITSYNC_LINK", Value: "/home/jovyan/datasets"})
+	assert.Contains(t, clone.Env, corev1.EnvVar{Name: "GITSYNC_ROOT", Value: "/home/jovyan/.git-sync/datasets"})
+	assert.Equal(t, []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/home/jovyan"}}, clone.VolumeMounts)
+	assert.Contains(t, podSpec.InitContainers[0].Env, corev1.EnvVar{Name: "GITSYNC_REF", Value: "HEAD"})
+	assert.Equal(t, podSpec.Containers[0].Resources, clone.Resources,
+		"the clone does not raise the requests of the pod")
+	assert.Equal(t, podSpec.Containers[0].SecurityContext, clone.SecurityContext)
+}
+
+func TestPodsRunTheTemplateInitContainersAfterTheClones(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["full"]
+	workspace.Spec.Bootstrap = &workspacev1alpha1.BootstrapSpec{
+		GitRepositories: []workspacev1alpha1.GitRepositorySpec{{URL: "https://github.com/org/course.git", Directory: "course"}},
+	}
+	workspace.Status.InitContainers = []corev1.Container{
+		{
+			Name:         "download-dataset",
+			Image:        "curlimages/curl:8.8.0",
+			VolumeMounts: []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/data"}, {Name: "host-root", MountPath: "/host"}},
+		},
+		{Name: "git-clone-0", Image: "busybox"},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	require.Len(t, podSpec.InitContainers, 2, "an init container whose name is taken is skipped")
+	assert.Equal(t, GitSyncImage, podSpec.InitContainers[0].Image)
+	download := podSpec.InitContainers[1]
+	assert.Equal(t, "download-dataset", download.Name)
+	assert.Equal(t, []corev1.VolumeMount{{Name: HomeVolumeName, MountPath: "/data"}}, download.VolumeMounts)
+}
+
+func TestPodsWithoutStorageCloneNoGitRepository(t *testing.T) {
+	workspace := newGoldenHashWorkspaces()["minimal"]
+	workspace.Spec.Bootstrap = &workspacev1alpha1.BootstrapSpec{
+		GitRepositories: []workspacev1alpha1.GitRepositorySpec{{URL: "https://github.com/org/course.git", Directory: "course"}},
+	}
+
+	podSpec := renderHashTestDeployment(t, workspace).Spec.Template.Spec
+	assert.Empty(t, podSpec.InitContainers)
+}
+
+func TestResolveInitContainersWaitsForTheWorkspaceToRestart(t *testing.T) {
+	ctx := context.Background()
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "datasets", Namespace: "team-a"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:    "datasets",
+			InitContainers: []corev1.Container{{Name: "download-dataset", Image: "curlimages/curl:8.8.0"}},
+		},
+	}
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "datasets"}
+	sm, k8sClient := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, template)
+	sm.resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, "")
+
+	require.NoError(t, sm.resourceManager.ResolveInitContainers(ctx, workspace, template))
+	require.Len(t, workspace.Status.InitContainers, 1)
+
+	// Once the workspace runs, template changes wait for its restart
+	_, err := sm.resourceManager.createDeployment(ctx, workspace, nil)
+	require.NoError(t, err)
+	template.Spec.InitContainers[0].Image = "curlimages/curl:8.9.0"
+	require.NoError(t, k8sClient.Update(ctx, template))
+	require.NoError(t, sm.resourceManager.ResolveInitContainers(ctx, workspace, template))
+	assert.Equal(t, "curlimages/curl:8.8.0", workspace.Status.InitContainers[0].Image)
+
+	workspace.Spec.TemplateRef = nil
+	require.NoError(t, sm.resourceManager.ResolveInitContainers(ctx, workspace, nil))
+	assert.Nil(t, workspace.Status.InitContainers)
+}
+
+func TestObserveBootstrap(t *testing.T) {
+	now := time.Now()
+	timeout := 10 * time.Minute
+	for name, tc := range map[string]struct {
+		pod             *corev1.Pod
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		"completed": {
+			pod:            newBootstrapTestPod(now.Add(-time.Hour), crashLoopingInitStatus(128), true),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: ReasonBootstrapCompleted,
+		},
+		"running": {
+			pod: newBootstrapTestPod(now.Add(-time.Minute), corev1.ContainerStatus{
+				Name: "git-clone-0", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			}, false),
+			expectedStatus: metav1.ConditionUnknown,
+			expectedReason: ReasonBootstrapRunning,
+		},
+		"failed": {
+			pod:             newBootstrapTestPod(now.Add(-time.Minute), crashLoopingInitStatus(128), false),
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  ReasonInitContainerFailed,
+			expectedMessage: `Init container "git-clone-0" exited with code 128 (Error): fatal: repository not found`,
+		},
+		"timed out": {
+			pod:             newBootstrapTestPod(now.Add(-time.Hour), crashLoopingInitStatus(1), false),
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  ReasonBootstrapTimedOut,
+			expectedMessage: `Init containers did not complete within 10m0s: Init container "git-clone-0" exited with code 1`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, reason, message := observeBootstrap(tc.pod, timeout, now)
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedReason, reason)
+			assert.Contains(t, message, tc.expectedMessage)
+		})
+	}
+}
+
+func TestReconcileBootstrapRecordsTheBootstrapFailedCondition(t *testing.T) {
+	ctx := context.Background()
+	workspace := newAdoptionTestWorkspace("ws")
+	workspace.Spec.Bootstrap = &workspacev1alpha1.BootstrapSpec{
+		GitRepositories: []workspacev1alpha1.GitRepositorySpec{{URL: "https://github.com/org/course.git", Directory: "course"}},
+		Timeout:         &metav1.Duration{Duration: 5 * time.Minute},
+	}
+	pod := newBootstrapTestPod(time.Now().Add(-time.Minute), crashLoopingInitStatus(128), false)
+	sm, _ := newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+
+	outcome := sm.reconcileBootstrap(ctx, workspace)
+	assert.True(t, outcome.failed)
+	assert.False(t, outcome.timedOut)
+	condition := apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeBootstrapFailed)
+	require.NotNil(t, condition)
+	assert.Equal(t, ReasonInitContainerFailed, condition.Reason)
+	assert.Contains(t, condition.Message, "exited with code 128")
+
+	// The timeout of the workspace overrides the one of the controller
+	pod = newBootstrapTestPod(time.Now().Add(-6*time.Minute), crashLoopingInitStatus(128), false)
+	sm, _ = newAdoptionTestStateMachine(t, interceptor.Funcs{}, workspace, pod)
+	sm.bootstrapTimeout = time.Hour
+	outcome = sm.reconcileBootstrap(ctx, workspace)
+	assert.True(t, outcome.timedOut)
+	condition = apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeBootstrapFailed)
+	assert.Equal(t, ReasonBootstrapTimedOut, condition.Reason)
+
+	// Workspaces without init containers carry no condition
+	workspace.Spec.Bootstrap = nil
+	outcome = sm.reconcileBootstrap(ctx, workspace)
+	assert.False(t, outcome.failed)
+	assert.Nil(t, apimeta.FindStatusCondition(workspace.Status.Conditions, ConditionTypeBootstrapFailed))
+}
@@ -7,15 +7,21 @@ package controller
 
 import (
 	"context"
+	"os"
 	"strings"
+	"time"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/cleanuphook"
+	"github.com/jupyter-infra/jupyter-k8s/internal/health"
 	"github.com/jupyter-infra/jupyter-k8s/internal/plugin"
 	"github.com/jupyter-infra/jupyter-k8s/internal/pluginclient"
+	"github.com/jupyter-infra/jupyter-k8s/internal/registry"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +69,60 @@ type WorkspaceControllerOptions struct {
 	// (e.g. {"aws": "http://localhost:8080"}).
 	// When set, remote access operations are delegated to the named plugin.
 	PluginEndpoints map[string]string
+
+	// ServiceMeshMode identifies the service mesh running in the cluster ("istio" or "linkerd").
+	// When empty, the controller does not render any mesh-specific pod annotations.
+	ServiceMeshMode string
+
+	// UserIntentCooldown is how long a manual desiredStatus change takes precedence over
+	// schedule and idle culling intents
+	UserIntentCooldown time.Duration
+
+	// ConnectionDrainPeriod is how long in-flight requests are given to complete, once the workspace
+	// service stops routing new connections, before a stopping or deleted workspace pod stops.
+	// Zero stops the pod right away.
+	ConnectionDrainPeriod time.Duration
+
+	// DeletionWarningPeriod is how long before deleting a workspace stopped for longer than its
+	// spec.retention its owner is warned with an event. Zero uses DefaultDeletionWarningPeriod.
+	DeletionWarningPeriod time.Duration
+
+	// CleanupHooks are called when a workspace is deleted, before its finalizer is removed. Their tokens
+	// are read from Secrets of the controller namespace.
+	CleanupHooks []cleanuphook.Hook
+
+	// ChildNamePrefix is the prefix of the names of the resources generated for new workspaces,
+	// instead of "workspace". Namespaces can override it with an annotation.
+	ChildNamePrefix string
+
+	// StaleWorkspacePolicy flags workspaces unused for too long, then requests their archival.
+	// Namespaces can override it with annotations.
+	StaleWorkspacePolicy StaleWorkspacePolicy
+
+	// Scope restricts the workspaces this instance manages, so that several instances can share
+	// a cluster. Nil manages every workspace.
+	Scope *workspaceutil.Scope
+
+	// DebugLogDuration bounds how long the debug log level annotation of a workspace raises the verbosity
+	// of its logs. Zero uses DefaultDebugLogDuration.
+	DebugLogDuration time.Duration
+
+	// BootstrapTimeout is how long the init containers of a workspace pod may take before the workspace is
+	// marked failed, unless the workspace sets spec.bootstrap.timeout. Zero uses DefaultBootstrapTimeout.
+	BootstrapTimeout time.Duration
+
+	// CullingDryRun evaluates the idle shutdown rules and reports the workspaces they would stop, in
+	// status.culling, events and metrics, without stopping them
+	CullingDryRun bool
+
+	// AdmissionCoverage describes the workspaces the admission webhooks see while they roll out
+	// namespace by namespace; the others are revalidated by the controller. Nil when the webhooks
+	// see every workspace.
+	AdmissionCoverage *AdmissionCoverage
+
+	// UserNamespacesSupported is true when the cluster runs pods in user namespaces (Kubernetes 1.30+).
+	// Templates with the UserNamespace isolation level run their workspaces Rootless otherwise.
+	UserNamespacesSupported bool
 }
 
 // WorkspaceReconciler reconciles a Workspace object
@@ -82,16 +142,25 @@ func (r *WorkspaceReconciler) SetStateMachine(sm StateMachineInterface) {
 
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=*,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaces/finalizers,verbs=update
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaces/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods;serviceaccounts,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.k8s.io,resources=priorityclasses,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=get;list
 // +kubebuilder:rbac:groups=traefik.io,resources=ingressroutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=traefik.io,resources=middlewares,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -100,27 +169,72 @@ func (r *WorkspaceReconciler) SetStateMachine(sm StateMachineInterface) {
 //
 // nolint:gocyclo
 func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	logger := logf.FromContext(ctx)
-	logger.Info("Starting reconciliation", "workspace", req.NamespacedName)
+	// Bound the whole reconcile: every call below derives its context from this one
+	ctx, cancel := context.WithTimeout(ctx, ReconcileTimeout)
+	defer cancel()
+	defer func() { _ = recordCallTimeout(CallTypeReconcile, ctx.Err()) }()
 
 	// Fetch the Workspace instance
 	workspace, err := r.getWorkspace(ctx, req)
 	if err != nil {
+		ctx = r.withDebugLogCapture(ctx, req.NamespacedName, nil)
+		logger := logf.FromContext(ctx)
 		if errors.IsNotFound(err) {
 			logger.Info("Workspace not found, assuming deleted")
+			pausedWorkspaces.set(req.NamespacedName, false)
+			unclaimedWorkspaces.set(req.NamespacedName, false)
+			templateDriftedWorkspaces.remove(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "Failed to get Workspace")
 		return ctrl.Result{}, err
 	}
 
+	// Keep the records of the workspace for `kubectl workspace debug-log`
+	ctx = r.withDebugLogCapture(ctx, req.NamespacedName, workspace)
+	logger := logf.FromContext(ctx)
+	logger.Info("Starting reconciliation", "workspace", req.NamespacedName)
+	logger.V(DecisionLogLevel).Info("Reconciling workspace",
+		"generation", workspace.Generation, "resourceVersion", workspace.ResourceVersion, "spec", workspace.Spec)
+
+	// Workspaces of other operator instances are never written to
+	if !r.options.Scope.Matches(workspace) {
+		logDecision(logger, "SkipOutOfScope")
+		return r.reconcileOutOfScope(ctx, workspace), nil
+	}
+	unclaimedWorkspaces.set(req.NamespacedName, false)
+
+	// Break-glass pause: observe only, skip every mutating action
+	paused := IsReconciliationPaused(workspace)
+	pausedWorkspaces.set(req.NamespacedName, paused)
+	if paused {
+		logDecision(logger, "ObservePaused")
+		return r.reconcilePaused(ctx, workspace)
+	}
+	if err := r.resumeIfPaused(ctx, workspace); err != nil {
+		logger.Error(err, "Failed to update status after resuming reconciliation")
+		return ctrl.Result{}, err
+	}
+
 	// Handle deletion if DeletionTimestamp is set
 	if !workspace.DeletionTimestamp.IsZero() {
+		logDecision(logger, "ReconcileDeletion", "finalizers", workspace.Finalizers)
 		return r.stateMachine.ReconcileDeletion(ctx, workspace)
 	}
 
+	// Workspaces the admission webhooks did not see are parked while they fail revalidation
+	if parked, result, err := r.reconcileAdmissionCoverage(ctx, workspace); err != nil || parked {
+		if err != nil {
+			logger.Error(err, "Failed to revalidate workspace")
+		} else {
+			logDecision(logger, "ParkUnrevalidated")
+		}
+		return result, err
+	}
+
 	// Consolidated function to ensure labels are set correctly
-	// and perform at most one update
+	// and perform at most one patch, of the keys the controller owns
+	original := workspace.DeepCopy()
 	needsUpdate := false
 	finalizerAdded := false
 	labelsChanged := map[string]string{}
@@ -134,6 +248,15 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		finalizerAdded = true
 	}
 
+	// Persist the default desiredStatus on workspaces created before it was defaulted at admission
+	desiredStatusDefaulted := false
+	if workspace.Spec.DesiredStatus == "" {
+		logger.Info("Defaulting desiredStatus", "desiredStatus", DefaultDesiredStatus)
+		workspace.Spec.DesiredStatus = DefaultDesiredStatus
+		needsUpdate = true
+		desiredStatusDefaulted = true
+	}
+
 	// Initialize labels map if needed
 	if workspace.Labels == nil {
 		workspace.Labels = make(map[string]string)
@@ -206,11 +329,12 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if needsUpdate {
 		logger.Info("Updating workspace labels",
 			"finalizerAdded", finalizerAdded,
+			"desiredStatusDefaulted", desiredStatusDefaulted,
 			"labelsChanged", labelsChanged,
 			"labelsRemoved", labelsRemoved,
 		)
 
-		if err := r.Update(ctx, workspace); err != nil {
+		if err := workspaceutil.PatchChanges(ctx, r.Client, workspace, original); err != nil {
 			logger.Error(err, "Failed to update workspace labels or finalizers")
 			return ctrl.Result{}, err
 		}
@@ -222,9 +346,9 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	// Get desired status to decide if we need to fetch AccessStrategy
 	desiredStatus := r.stateMachine.getDesiredStatus(workspace)
 
-	// Only fetch AccessStrategy if desiredStatus is not Stopped and workspace has AccessStrategy defined
+	// Only fetch AccessStrategy if desiredStatus is neither Stopped nor Paused and workspace has AccessStrategy defined
 	var accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
-	if desiredStatus != DesiredStateStopped && workspace.Spec.AccessStrategy != nil {
+	if !isStopDesiredStatus(desiredStatus) && workspace.Spec.AccessStrategy != nil {
 		accessStrategy, err = r.stateMachine.GetAccessStrategyForWorkspace(ctx, workspace)
 		if err != nil {
 			logger.Error(err, "Failed to get AccessStrategy")
@@ -233,7 +357,11 @@ func (r *WorkspaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	// Delegate to state machine for business logic, passing the accessStrategy
-	return r.stateMachine.ReconcileDesiredState(ctx, workspace, accessStrategy)
+	logDecision(logger, "ReconcileDesiredState",
+		"desiredStatus", desiredStatus, "accessStrategy", accessStrategy != nil)
+	result, err := r.stateMachine.ReconcileDesiredState(ctx, workspace, accessStrategy)
+	logDecision(logger, "ReconcileResult", "requeueAfter", result.RequeueAfter.String(), "error", err != nil)
+	return result, err
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -244,7 +372,21 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// Watch for standard Kubernetes resources
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
-		Owns(&corev1.PersistentVolumeClaim{})
+		Owns(&corev1.PersistentVolumeClaim{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{})
+	// The informers of the watched types, reported by the readiness checks
+	watched := []client.Object{
+		&workspacev1alpha1.Workspace{},
+		&appsv1.Deployment{},
+		&corev1.Service{},
+		&corev1.PersistentVolumeClaim{},
+		&rbacv1.Role{},
+		&rbacv1.RoleBinding{},
+		&workspacev1alpha1.WorkspaceAccessStrategy{},
+		&workspacev1alpha1.WorkspaceTemplate{},
+		&corev1.ResourceQuota{},
+	}
 
 	// Watch for changes to AccessStrategy resources to trigger reconciliation
 	// of Workspaces that reference them
@@ -253,6 +395,21 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		handler.EnqueueRequestsFromMapFunc(r.accessStrategyEventHandler),
 	)
 
+	// Watch WorkspaceTemplates to apply child metadata changes to the resources of workspaces
+	// that follow the current template generation, and to detect changes to pinned templates
+	builder.Watches(
+		&workspacev1alpha1.WorkspaceTemplate{},
+		handler.EnqueueRequestsFromMapFunc(r.templateChildMetadataEventHandler),
+		builderPkg.WithPredicates(predicate.Or(templateChildMetadataChangedPredicate(), templateContentChangedPredicate())),
+	)
+
+	// Watch ResourceQuotas to retry workspaces blocked by a quota as soon as headroom appears
+	builder.Watches(
+		&corev1.ResourceQuota{},
+		handler.EnqueueRequestsFromMapFunc(r.resourceQuotaEventHandler),
+		builderPkg.WithPredicates(resourceQuotaHeadroomPredicate()),
+	)
+
 	// Conditionally watch pods based on configuration
 	if r.options.EnableWorkspacePodWatching {
 		builder.Watches(
@@ -282,6 +439,7 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					(event.Reason == "Preempted")
 			})),
 		)
+		watched = append(watched, &corev1.Pod{}, &corev1.Event{})
 	}
 
 	// Optional traefik configuration (backward compatibility)
@@ -298,6 +456,7 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 		// Watch NetworkPolicy resources using typed API
 		builder.Owns(&networkingv1.NetworkPolicy{}).Owns(ingressRouteGVK).Owns(middlewareGVK)
+		watched = append(watched, &networkingv1.NetworkPolicy{}, ingressRouteGVK, middlewareGVK)
 	}
 
 	// Add additional resource watches from ResourceWatches config
@@ -315,14 +474,18 @@ func (r *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		obj.SetAPIVersion(apiVersion)
 		obj.SetKind(gvk.Kind)
 		builder.Owns(obj)
+		watched = append(watched, obj)
 	}
 
-	return builder.Complete(r)
+	if err := builder.Complete(r); err != nil {
+		return err
+	}
+	return health.AddReadyzChecks(mgr, health.InformerSyncedChecks(mgr.GetCache(), mgr.GetScheme(), watched...)...)
 }
 
 // SetupWorkspaceController sets up the controller with the Manager and specified options
 func SetupWorkspaceController(mgr mngr.Manager, options WorkspaceControllerOptions) error {
-	k8sClient := mgr.GetClient()
+	k8sClient := newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout)
 	scheme := mgr.GetScheme()
 
 	// Create managers
@@ -330,17 +493,38 @@ func SetupWorkspaceController(mgr mngr.Manager, options WorkspaceControllerOptio
 	resourceManager := NewResourceManager(
 		k8sClient,
 		scheme,
-		NewDeploymentBuilder(scheme, options, k8sClient),
+		NewDeploymentBuilder(scheme, options),
 		NewServiceBuilder(scheme),
 		NewPVCBuilder(scheme),
 		NewAccessResourcesBuilder(),
 		statusManager,
 	)
+	resourceManager.apiReader = newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout)
+	resourceManager.templateResolver = workspaceutil.NewTemplateResolver(k8sClient, options.DefaultTemplateNamespace)
+	resourceManager.digestResolver = registry.NewClient()
+	resourceManager.childNamePrefix = options.ChildNamePrefix
+	resourceManager.userNamespacesSupported = options.UserNamespacesSupported
 
 	// Create state machine
 	eventRecorder := mgr.GetEventRecorderFor("workspace-controller")
 	idleChecker := NewWorkspaceIdleChecker(k8sClient)
-	stateMachine := NewStateMachine(resourceManager, statusManager, eventRecorder, idleChecker)
+	intentResolver := NewDesiredStatusResolver(options.UserIntentCooldown)
+	stateMachine := NewStateMachine(resourceManager, statusManager, eventRecorder, idleChecker, intentResolver)
+	stateMachine.stalePolicy = options.StaleWorkspacePolicy
+	stateMachine.drainPeriod = options.ConnectionDrainPeriod
+	stateMachine.deletionWarningPeriod = options.DeletionWarningPeriod
+	stateMachine.bootstrapTimeout = options.BootstrapTimeout
+	stateMachine.cullingDryRun = options.CullingDryRun
+	if len(options.CleanupHooks) > 0 {
+		stateMachine.cleanupHooks = options.CleanupHooks
+		stateMachine.cleanupHookClient = cleanuphook.NewClient(
+			newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout), os.Getenv(ControllerPodNamespaceEnv))
+	}
+	if execUtil, err := NewPodExecUtil(); err != nil {
+		logf.Log.Error(err, "Failed to create pod exec util, template startup checks and server shutdown requests will not run")
+	} else {
+		stateMachine.podExec = execUtil
+	}
 
 	// Create plugin clients for pod event handling (if configured)
 	pluginClients := map[string]plugin.RemoteAccessPluginApis{}
@@ -350,6 +534,7 @@ func SetupWorkspaceController(mgr mngr.Manager, options WorkspaceControllerOptio
 
 	// Create pod event handler
 	podEventHandler := NewPodEventHandler(k8sClient, resourceManager, pluginClients)
+	podEventHandler.scope = options.Scope
 
 	// Create reconciler with dependencies
 	reconciler := &WorkspaceReconciler{
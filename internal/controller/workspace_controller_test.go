@@ -274,6 +274,61 @@ var _ = Describe("Workspace Controller", func() {
 				Expect(updatedWorkspace.Labels).NotTo(HaveKey(LabelAccessStrategyNamespace), "No access strategy namespace label should be set")
 			})
 
+			It("should keep a label the user adds between the Get and the label write of the controller", func() {
+				By("referencing a template on a workspace that already has the finalizer")
+				existingWorkspace := &workspacev1alpha1.Workspace{}
+				Expect(k8sClient.Get(ctx, workspaceKey, existingWorkspace)).To(Succeed())
+				existingWorkspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{
+					Name:      templateName,
+					Namespace: templateNamespace,
+				}
+				controllerutil.AddFinalizer(existingWorkspace, WorkspaceFinalizerName)
+				Expect(k8sClient.Update(ctx, existingWorkspace)).To(Succeed())
+
+				By("setting up a client that lets the user label the workspace right after the controller reads it")
+				userLabeled := false
+				interleavingClient := &MockClient{Client: k8sClient}
+				interleavingClient.getFunc = func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := k8sClient.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+					if _, isWorkspace := obj.(*workspacev1alpha1.Workspace); !isWorkspace || key != workspaceKey || userLabeled {
+						return nil
+					}
+					userLabeled = true
+					userCopy := &workspacev1alpha1.Workspace{}
+					Expect(k8sClient.Get(ctx, key, userCopy)).To(Succeed())
+					patch := client.MergeFrom(userCopy.DeepCopy())
+					if userCopy.Labels == nil {
+						userCopy.Labels = map[string]string{}
+					}
+					userCopy.Labels["team"] = "data-science"
+					Expect(k8sClient.Patch(ctx, userCopy, patch)).To(Succeed())
+					return nil
+				}
+
+				controllerReconciler := &WorkspaceReconciler{
+					Client:        interleavingClient,
+					Scheme:        k8sClient.Scheme(),
+					stateMachine:  &MockStateMachine{},
+					statusManager: &StatusManager{client: k8sClient},
+				}
+
+				By("Reconciling the workspace with template")
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: workspaceKey,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(userLabeled).To(BeTrue(), "the user label must be written between the Get and the write")
+
+				By("Verifying that both the user label and the template labels were stored")
+				updatedWorkspace := &workspacev1alpha1.Workspace{}
+				Expect(k8sClient.Get(ctx, workspaceKey, updatedWorkspace)).To(Succeed())
+				Expect(updatedWorkspace.Labels).To(HaveKeyWithValue("team", "data-science"), "The user label should survive")
+				Expect(updatedWorkspace.Labels).To(HaveKeyWithValue(workspaceutil.LabelWorkspaceTemplate, templateName))
+				Expect(updatedWorkspace.Labels).To(HaveKeyWithValue(workspaceutil.LabelWorkspaceTemplateNamespace, templateNamespace))
+			})
+
 			It("should add finalizer and access strategy labels when workspace references an AccessStrategy", func() {
 				By("updating the existing Workspace to add an accessStrategy reference")
 
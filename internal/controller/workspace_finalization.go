@@ -0,0 +1,110 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// finalizationStep is one idempotent step of workspace finalization
+type finalizationStep struct {
+	// reason is recorded in the Terminating condition while the step is in progress
+	reason string
+
+	// resource describes what the step deletes, for status messages
+	resource string
+
+	// waitingMessage is recorded while the step waits, when waiting is not for the resource deletion
+	waitingMessage string
+
+	// run issues any deletion still needed and returns true once the resources are confirmed absent
+	run func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error)
+
+	// progress, when set, describes the progress of a step that waits in place of waitingMessage, and
+	// returns when to run it again in place of PollRequeueDelay
+	progress func(workspace *workspacev1alpha1.Workspace) (string, time.Duration)
+}
+
+// finalizationSteps returns the workspace finalization steps in execution order.
+// Template and access strategy protection need no step: their controllers stop counting
+// a workspace as soon as its deletion timestamp is set, and release their finalizers on their own.
+// The cleanup hooks step only exists when hooks are configured.
+func (sm *StateMachine) finalizationSteps() []finalizationStep {
+	rm := sm.resourceManager
+	steps := []finalizationStep{
+		{
+			// The service stops routing new connections first, and the access resources stay
+			// until the pod is gone, so that open tabs are not routed to a dying pod
+			reason:         ReasonDrainingConnections,
+			resource:       "service endpoints",
+			waitingMessage: "Waiting for in-flight requests to complete",
+			run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+				remaining, err := sm.connectionDrainRemaining(
+					ctx, workspace, FindCondition(&workspace.Status.Conditions, ConditionTypeTerminating), time.Now())
+				return remaining == 0 && err == nil, err
+			},
+		},
+		{
+			reason:   ReasonDeletingDeployment,
+			resource: "deployment",
+			run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+				deployment, err := rm.EnsureDeploymentDeleted(ctx, workspace)
+				return deployment == nil && err == nil, err
+			},
+		},
+		{
+			reason:   ReasonDeletingAccessResources,
+			resource: "access resources",
+			run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+				if err := rm.EnsureAccessResourcesDeleted(ctx, workspace); err != nil {
+					return false, err
+				}
+				return rm.AreAccessResourcesDeleted(workspace), nil
+			},
+		},
+		{
+			reason:   ReasonDeletingService,
+			resource: "service",
+			run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+				service, err := rm.EnsureServiceDeleted(ctx, workspace)
+				return service == nil && err == nil, err
+			},
+		},
+		{
+			reason:   ReasonDeletingStorage,
+			resource: "persistent volume claim",
+			run: func(ctx context.Context, workspace *workspacev1alpha1.Workspace) (bool, error) {
+				pvc, err := rm.EnsurePVCDeleted(ctx, workspace)
+				return pvc == nil && err == nil, err
+			},
+		},
+	}
+	if len(sm.cleanupHooks) > 0 {
+		steps = append(steps, sm.cleanupHooksStep())
+	}
+	return steps
+}
+
+// resumeFinalizationStep returns the index of the step recorded in the Terminating condition,
+// so that a restarted controller continues where the previous one left off
+func resumeFinalizationStep(workspace *workspacev1alpha1.Workspace, steps []finalizationStep) int {
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeTerminating)
+	if condition == nil {
+		return 0
+	}
+	if condition.Reason == ReasonRemovingFinalizer {
+		return len(steps)
+	}
+	for i, step := range steps {
+		if step.reason == condition.Reason {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,203 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// holdFinalizer keeps a child resource in terminating state until the test releases it
+const holdFinalizer = "test.jupyter.org/hold"
+
+var _ = Describe("Workspace Finalization", func() {
+	var (
+		ctx          context.Context
+		workspaceKey types.NamespacedName
+		deployment   *appsv1.Deployment
+		service      *corev1.Service
+		pvc          *corev1.PersistentVolumeClaim
+	)
+
+	// newFinalizationStateMachine simulates a controller restart: no state is shared between instances
+	newFinalizationStateMachine := func() *StateMachine {
+		statusManager := NewStatusManager(k8sClient)
+		resourceManager := NewResourceManager(k8sClient, k8sClient.Scheme(), nil, nil, nil, nil, statusManager)
+		return NewStateMachine(resourceManager, statusManager, record.NewFakeRecorder(10), nil, nil)
+	}
+
+	reconcileWithRestart := func() {
+		workspace := &workspacev1alpha1.Workspace{}
+		Expect(k8sClient.Get(ctx, workspaceKey, workspace)).To(Succeed())
+		_, err := newFinalizationStateMachine().ReconcileDeletion(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	expectTerminatingStep := func(reason string) {
+		workspace := &workspacev1alpha1.Workspace{}
+		Expect(k8sClient.Get(ctx, workspaceKey, workspace)).To(Succeed())
+		Expect(controllerutil.ContainsFinalizer(workspace, WorkspaceFinalizerName)).To(BeTrue())
+		condition := FindCondition(&workspace.Status.Conditions, ConditionTypeTerminating)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(reason))
+	}
+
+	release := func(obj client.Object) {
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		Expect(obj.GetDeletionTimestamp()).NotTo(BeNil(), "deletion should have been issued before release")
+		obj.SetFinalizers(nil)
+		Expect(k8sClient.Update(ctx, obj)).To(Succeed())
+	}
+
+	expectAbsent := func(obj client.Object) {
+		err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+		Expect(errors.IsNotFound(err)).To(BeTrue(), "expected %s to be deleted", obj.GetName())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		workspaceKey = types.NamespacedName{Name: "finalization-test", Namespace: "default"}
+		labels := map[string]string{"app": "finalization-test"}
+
+		workspace := &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       workspaceKey.Name,
+				Namespace:  workspaceKey.Namespace,
+				Finalizers: []string{WorkspaceFinalizerName},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Finalization Test",
+				Image:       "jupyter/base-notebook:latest",
+			},
+		}
+		Expect(k8sClient.Create(ctx, workspace)).To(Succeed())
+
+		deployment = &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       GenerateDeploymentName(workspaceKey.Name),
+				Namespace:  workspaceKey.Namespace,
+				Finalizers: []string{holdFinalizer},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: labels},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "workspace", Image: "jupyter/base-notebook:latest"}},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+		service = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       GenerateServiceName(workspaceKey.Name),
+				Namespace:  workspaceKey.Namespace,
+				Finalizers: []string{holdFinalizer},
+			},
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports:    []corev1.ServicePort{{Port: 8888}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, service)).To(Succeed())
+
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       GeneratePVCName(workspaceKey.Name),
+				Namespace:  workspaceKey.Namespace,
+				Finalizers: []string{holdFinalizer},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pvc)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, workspace)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		for _, obj := range []client.Object{
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deployment.Name, Namespace: deployment.Namespace}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvc.Name, Namespace: pvc.Namespace}},
+			&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: workspaceKey.Name, Namespace: workspaceKey.Namespace}},
+		} {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err == nil {
+				obj.SetFinalizers(nil)
+				_ = k8sClient.Update(ctx, obj)
+				_ = k8sClient.Delete(ctx, obj)
+			}
+		}
+	})
+
+	It("should only remove the finalizer once every child is confirmed absent across restarts", func() {
+		By("issuing deletion of the deployment and waiting for it")
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDeletingDeployment)
+
+		By("repeating the same step idempotently after a restart")
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDeletingDeployment)
+
+		By("moving to the service once the deployment is gone")
+		release(deployment)
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDeletingService)
+
+		By("moving to the storage once the service is gone")
+		release(service)
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDeletingStorage)
+
+		By("removing the finalizer once the storage is gone")
+		release(pvc)
+		reconcileWithRestart()
+
+		By("verifying no orphans remain")
+		expectAbsent(&workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: workspaceKey.Name, Namespace: workspaceKey.Namespace}})
+		expectAbsent(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: deployment.Name, Namespace: deployment.Namespace}})
+		expectAbsent(&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: service.Name, Namespace: service.Namespace}})
+		expectAbsent(&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvc.Name, Namespace: pvc.Namespace}})
+	})
+
+	It("should restart finalization when a child is still present at the recorded step", func() {
+		By("recording that only the finalizer removal remains, as a crashed controller would have")
+		workspace := &workspacev1alpha1.Workspace{}
+		Expect(k8sClient.Get(ctx, workspaceKey, workspace)).To(Succeed())
+		workspace.Status.Conditions = []metav1.Condition{
+			NewCondition(ConditionTypeTerminating, metav1.ConditionTrue, ReasonRemovingFinalizer, "test"),
+		}
+		Expect(k8sClient.Status().Update(ctx, workspace)).To(Succeed())
+
+		By("reconciling after a restart")
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDrainingConnections)
+
+		By("resuming the steps from the beginning")
+		reconcileWithRestart()
+		expectTerminatingStep(ReasonDeletingDeployment)
+	})
+})
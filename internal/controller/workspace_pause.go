@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// IsReconciliationPaused returns true if the workspace carries the paused annotation
+func IsReconciliationPaused(workspace *workspacev1alpha1.Workspace) bool {
+	return workspace.Annotations[AnnotationPaused] == "true"
+}
+
+// reconcilePaused only observes the workspace resources and reports them in status.
+// No mutating action is taken on the workspace or its resources, including deletion cleanup.
+func (r *WorkspaceReconciler) reconcilePaused(ctx context.Context, workspace *workspacev1alpha1.Workspace) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+	logger.Info("Reconciliation paused by annotation, skipping mutating actions", "annotation", AnnotationPaused)
+
+	snapshotStatus := workspace.DeepCopy().Status
+	message := fmt.Sprintf("Reconciliation is paused by annotation %s=true", AnnotationPaused)
+
+	// Observe the deployment
+	deployment := &appsv1.Deployment{}
+	deploymentName := deploymentNameFor(workspace)
+	err := r.Get(ctx, types.NamespacedName{Name: deploymentName, Namespace: workspace.Namespace}, deployment)
+	switch {
+	case err == nil:
+		workspace.Status.DeploymentName = deploymentName
+		message = fmt.Sprintf("%s; deployment %s has %d/%d ready replicas",
+			message, deploymentName, deployment.Status.ReadyReplicas, deployment.Status.Replicas)
+	case errors.IsNotFound(err):
+		workspace.Status.DeploymentName = ""
+		message = fmt.Sprintf("%s; deployment %s does not exist", message, deploymentName)
+	default:
+		return ctrl.Result{}, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	// Observe the service
+	service := &corev1.Service{}
+	serviceName := serviceNameFor(workspace)
+	err = r.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: workspace.Namespace}, service)
+	switch {
+	case err == nil:
+		workspace.Status.ServiceName = serviceName
+	case errors.IsNotFound(err):
+		workspace.Status.ServiceName = ""
+	default:
+		return ctrl.Result{}, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	if !workspace.DeletionTimestamp.IsZero() {
+		message = fmt.Sprintf("%s; deletion is pending until reconciliation resumes", message)
+	}
+
+	if err := r.statusManager.UpdatePausedStatus(ctx, workspace, message, &snapshotStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Keep observing; unpausing triggers an immediate reconciliation through the workspace watch
+	return ctrl.Result{RequeueAfter: LongRequeueDelay}, nil
+}
+
+// resumeIfPaused flips the ReconciliationPaused condition back to false once the annotation is removed
+func (r *WorkspaceReconciler) resumeIfPaused(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	condition := FindCondition(&workspace.Status.Conditions, ConditionTypeReconciliationPaused)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Reconciliation resumed, running full reconciliation")
+	snapshotStatus := workspace.DeepCopy().Status
+	return r.statusManager.UpdateResumedStatus(ctx, workspace, &snapshotStatus)
+}
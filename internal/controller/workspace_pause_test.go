@@ -0,0 +1,123 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPauseTestReconciler(t *testing.T, objects ...client.Object) *WorkspaceReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&workspacev1alpha1.Workspace{}).
+		Build()
+
+	return &WorkspaceReconciler{
+		Client:        k8sClient,
+		Scheme:        scheme,
+		statusManager: NewStatusManager(k8sClient),
+	}
+}
+
+func TestIsReconciliationPaused(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{}
+	assert.False(t, IsReconciliationPaused(workspace))
+
+	workspace.Annotations = map[string]string{AnnotationPaused: "false"}
+	assert.False(t, IsReconciliationPaused(workspace))
+
+	workspace.Annotations[AnnotationPaused] = "true"
+	assert.True(t, IsReconciliationPaused(workspace))
+}
+
+func TestReconcilePausedWorkspaceOnlyObserves(t *testing.T) {
+	ctx := context.Background()
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "paused-ws",
+			Namespace:   "default",
+			Annotations: map[string]string{AnnotationPaused: "true"},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{DesiredStatus: DesiredStateStopped},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateDeploymentName("paused-ws"), Namespace: "default"},
+		Status:     appsv1.DeploymentStatus{Replicas: 1, ReadyReplicas: 1},
+	}
+	r := newPauseTestReconciler(t, workspace, deployment)
+	key := types.NamespacedName{Name: "paused-ws", Namespace: "default"}
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, LongRequeueDelay, result.RequeueAfter)
+
+	updated := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, key, updated))
+	assert.Empty(t, updated.Finalizers, "paused reconciliation must not add the finalizer")
+	assert.Equal(t, GenerateDeploymentName("paused-ws"), updated.Status.DeploymentName)
+	condition := FindCondition(&updated.Status.Conditions, ConditionTypeReconciliationPaused)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonPausedByAnnotation, condition.Reason)
+	assert.Contains(t, condition.Message, "1/1 ready replicas")
+
+	// The deployment is left running even though the workspace is stopped
+	observed := &appsv1.Deployment{}
+	require.NoError(t, r.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: "default"}, observed))
+	assert.Nil(t, observed.DeletionTimestamp)
+
+	// Resuming flips the condition
+	delete(updated.Annotations, AnnotationPaused)
+	require.NoError(t, r.resumeIfPaused(ctx, updated))
+	resumed := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, key, resumed))
+	condition = FindCondition(&resumed.Status.Conditions, ConditionTypeReconciliationPaused)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonReconciliationResumed, condition.Reason)
+
+	pausedWorkspaces.set(key, false)
+}
+
+func TestWorkspaceSetTracker(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_paused_workspaces"})
+	tracker := newWorkspaceSetTracker(gauge)
+	first := types.NamespacedName{Name: "a", Namespace: "ns"}
+	second := types.NamespacedName{Name: "b", Namespace: "ns"}
+
+	tracker.set(first, true)
+	tracker.set(first, true)
+	tracker.set(second, true)
+	assert.Equal(t, float64(2), testutil.ToFloat64(gauge))
+
+	tracker.set(first, false)
+	assert.Equal(t, float64(1), testutil.ToFloat64(gauge))
+
+	tracker.set(second, false)
+	tracker.set(second, false)
+	assert.Equal(t, float64(0), testutil.ToFloat64(gauge))
+}
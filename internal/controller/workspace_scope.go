@@ -0,0 +1,44 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// UnclaimedWorkspaceGracePeriod is how long a workspace outside the scope of this instance may
+// wait for another instance to add its finalizer before it is reported as unclaimed
+const UnclaimedWorkspaceGracePeriod = time.Minute
+
+// reconcileOutOfScope handles a workspace managed by another operator instance. Nothing is written:
+// the workspace is only checked for an instance having claimed it, so that a workspace matching
+// the scope of no instance shows up in the unclaimed metric and logs instead of waiting silently.
+func (r *WorkspaceReconciler) reconcileOutOfScope(ctx context.Context, workspace *workspacev1alpha1.Workspace) ctrl.Result {
+	key := client.ObjectKeyFromObject(workspace)
+	if controllerutil.ContainsFinalizer(workspace, WorkspaceFinalizerName) || !workspace.DeletionTimestamp.IsZero() {
+		unclaimedWorkspaces.set(key, false)
+		return ctrl.Result{}
+	}
+
+	if age := time.Since(workspace.CreationTimestamp.Time); age < UnclaimedWorkspaceGracePeriod {
+		unclaimedWorkspaces.set(key, false)
+		return ctrl.Result{RequeueAfter: UnclaimedWorkspaceGracePeriod - age}
+	}
+
+	unclaimedWorkspaces.set(key, true)
+	logf.FromContext(ctx).Info("Warning: workspace is outside the scope of this operator instance and no other instance claimed it",
+		"scope", r.options.Scope.String(),
+		"labels", workspace.Labels,
+		"templateRef", workspace.Spec.TemplateRef)
+	return ctrl.Result{}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func newScopeTestWorkspace(name string, created time.Time, finalizers ...string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{"team": "web"},
+			CreationTimestamp: metav1.NewTime(created),
+			Finalizers:        finalizers,
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{DesiredStatus: DesiredStateRunning},
+	}
+}
+
+func newScopeTestReconciler(t *testing.T, workspace *workspacev1alpha1.Workspace) *WorkspaceReconciler {
+	scope, err := workspaceutil.NewScope("team=ml", nil, "")
+	require.NoError(t, err)
+	r := newPauseTestReconciler(t, workspace)
+	r.options.Scope = scope
+	return r
+}
+
+func TestReconcileOutOfScopeWorkspaceWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScopeTestWorkspace("other-ws", time.Now())
+	r := newScopeTestReconciler(t, workspace)
+	key := types.NamespacedName{Name: "other-ws", Namespace: "default"}
+	before := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, key, before))
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Positive(t, result.RequeueAfter, "checked again once the grace period ends")
+	assert.LessOrEqual(t, result.RequeueAfter, UnclaimedWorkspaceGracePeriod)
+
+	after := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, key, after))
+	assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	assert.Empty(t, after.Finalizers)
+	assert.Empty(t, after.Status.Conditions)
+}
+
+func TestReconcileReportsWorkspaceNoInstanceClaimed(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScopeTestWorkspace("orphan-ws", time.Now().Add(-2*UnclaimedWorkspaceGracePeriod))
+	r := newScopeTestReconciler(t, workspace)
+	key := types.NamespacedName{Name: "orphan-ws", Namespace: "default"}
+	before := testutil.ToFloat64(unclaimedWorkspacesGauge)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, before+1, testutil.ToFloat64(unclaimedWorkspacesGauge))
+
+	// Another instance claims the workspace
+	claimed := &workspacev1alpha1.Workspace{}
+	require.NoError(t, r.Get(ctx, key, claimed))
+	claimed.Finalizers = []string{WorkspaceFinalizerName}
+	require.NoError(t, r.Update(ctx, claimed))
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, before, testutil.ToFloat64(unclaimedWorkspacesGauge))
+}
+
+func TestReconcileDoesNotReportWorkspaceOfAnotherInstance(t *testing.T) {
+	ctx := context.Background()
+	workspace := newScopeTestWorkspace("claimed-ws", time.Now().Add(-2*UnclaimedWorkspaceGracePeriod), WorkspaceFinalizerName)
+	r := newScopeTestReconciler(t, workspace)
+	before := testutil.ToFloat64(unclaimedWorkspacesGauge)
+
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "claimed-ws", Namespace: "default"}})
+	require.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.Equal(t, before, testutil.ToFloat64(unclaimedWorkspacesGauge))
+}
@@ -0,0 +1,108 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// newFailingListReader returns a reader whose workspace lists fail, e.g. on an expired continue token
+func newFailingListReader(t *testing.T, err error) client.Reader {
+	return interceptor.NewClient(fake.NewClientBuilder().WithScheme(newMigrationTestClient(t).Scheme()).Build(),
+		interceptor.Funcs{
+			List: func(context.Context, client.WithWatch, client.ObjectList, ...client.ListOption) error {
+				return err
+			},
+		})
+}
+
+func TestTemplateFinalizerIsOnlyRemovedOnceTheAPIServerListsNoWorkspace(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+	controllerutil.AddFinalizer(template, templateFinalizerName)
+	k8sClient := newMigrationTestClient(t, template)
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(template)}
+	stored := &workspacev1alpha1.WorkspaceTemplate{}
+
+	// The cache does not list the workspace just created yet
+	reconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(),
+		apiReader: newMigrationTestClient(t, newCountTestWorkspace("ws-a"))}
+	result, err := reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+
+	// A list that fails keeps the finalizer
+	listErr := errors.New("the continue token has expired")
+	reconciler.apiReader = newFailingListReader(t, listErr)
+	_, err = reconciler.Reconcile(ctx, request)
+	assert.ErrorIs(t, err, listErr)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+
+	reconciler.apiReader = newMigrationTestClient(t)
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.False(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+}
+
+func TestDeletedTemplateIsKeptWhileTheAPIServerListsWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	template := newRevisionTestTemplate()
+	controllerutil.AddFinalizer(template, templateFinalizerName)
+	template.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	k8sClient := newMigrationTestClient(t, template)
+	reconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(),
+		apiReader: newMigrationTestClient(t, newCountTestWorkspace("ws-a"))}
+
+	result, err := reconciler.handleDeletion(ctx, template)
+	require.NoError(t, err)
+	assert.Equal(t, PollRequeueDelay, result.RequeueAfter)
+	stored := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(template), stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, templateFinalizerName))
+}
+
+func TestAccessStrategyFinalizerIsKeptWhenTheAPIServerListFails(t *testing.T) {
+	ctx := context.Background()
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+		ObjectMeta: metav1.ObjectMeta{Name: "oauth", Namespace: "team-a",
+			Finalizers: []string{workspaceutil.AccessStrategyFinalizerName}},
+	}
+	k8sClient := newMigrationTestClient(t, accessStrategy)
+	listErr := errors.New("the continue token has expired")
+	reconciler := &WorkspaceAccessStrategyReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(),
+		apiReader: newFailingListReader(t, listErr)}
+	request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(accessStrategy)}
+
+	_, err := reconciler.Reconcile(ctx, request)
+	assert.ErrorIs(t, err, listErr)
+	stored := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.True(t, controllerutil.ContainsFinalizer(stored, workspaceutil.AccessStrategyFinalizerName))
+
+	reconciler.apiReader = newMigrationTestClient(t)
+	_, err = reconciler.Reconcile(ctx, request)
+	require.NoError(t, err)
+	require.NoError(t, k8sClient.Get(ctx, request.NamespacedName, stored))
+	assert.False(t, controllerutil.ContainsFinalizer(stored, workspaceutil.AccessStrategyFinalizerName))
+}
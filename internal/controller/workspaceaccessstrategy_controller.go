@@ -29,6 +29,10 @@ type WorkspaceAccessStrategyReconciler struct {
 	client.Client
 	Scheme        *runtime.Scheme
 	EventRecorder record.EventRecorder
+
+	// apiReader reads past the cache to confirm that no workspace uses an AccessStrategy before its finalizer
+	// is removed
+	apiReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspaceaccessstrategies/finalizers,verbs=update
@@ -84,11 +88,20 @@ func (r *WorkspaceAccessStrategyReconciler) Reconcile(ctx context.Context, req c
 	// Case 2: No workspaces, but finalizer is present → Remove finalizer
 	// This handles the case where all workspaces were deleted
 	if !hasWorkspaces && hasFinalizer {
+		unused, err := r.confirmAccessStrategyUnused(ctx, accessStrategy)
+		if err != nil {
+			logger.Error(err, "Failed to confirm that no workspace uses the AccessStrategy, keeping its finalizer")
+			return ctrl.Result{}, err
+		}
+		if !unused {
+			logger.Info("Keeping finalizer until the cache lists the workspaces using the AccessStrategy")
+			return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+		}
 		logger.Info("Removing finalizer from AccessStrategy (no workspaces using it)",
 			"finalizer", workspace.AccessStrategyFinalizerName)
 
 		// Use the safe utility to remove finalizer (handles conflicts)
-		err := workspace.SafelyRemoveFinalizerFromAccessStrategy(ctx, logger, r.Client, accessStrategy, false)
+		err = workspace.SafelyRemoveFinalizerFromAccessStrategy(ctx, logger, r.Client, accessStrategy, false)
 		if err != nil {
 			logger.Error(err, "Failed to remove finalizer from AccessStrategy")
 			return ctrl.Result{}, err
@@ -101,6 +114,21 @@ func (r *WorkspaceAccessStrategyReconciler) Reconcile(ctx context.Context, req c
 	return ctrl.Result{}, nil
 }
 
+// confirmAccessStrategyUnused lists every page of the workspaces using the AccessStrategy from the API server,
+// once the cache lists none: the cache lags behind workspaces just created
+func (r *WorkspaceAccessStrategyReconciler) confirmAccessStrategyUnused(
+	ctx context.Context, accessStrategy *workspacev1alpha1.WorkspaceAccessStrategy) (bool, error) {
+	if r.apiReader == nil {
+		return true, nil
+	}
+	hasWorkspaces, err := workspace.HasActiveWorkspacesWithAccessStrategy(
+		ctx, r.apiReader, accessStrategy.Name, accessStrategy.Namespace)
+	if err != nil {
+		return false, err
+	}
+	return !hasWorkspaces, nil
+}
+
 // SetupWithManager sets up the controller with the Manager.
 // It configures watches for WorkspaceAccessStrategy resources and triggers reconciliation
 // when Workspaces change to manage finalizers based on AccessStrategy usage.
@@ -169,7 +197,7 @@ func (r *WorkspaceAccessStrategyReconciler) findAccessStrategiesForWorkspace(ctx
 
 // SetupWorkspaceAccessStrategyController sets up the controller with the Manager.
 func SetupWorkspaceAccessStrategyController(mgr ctrl.Manager) error {
-	k8sClient := mgr.GetClient()
+	k8sClient := newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout)
 	scheme := mgr.GetScheme()
 	eventRecorder := mgr.GetEventRecorderFor("workspaceaccessstrategy-controller")
 
@@ -177,6 +205,7 @@ func SetupWorkspaceAccessStrategyController(mgr ctrl.Manager) error {
 		Client:        k8sClient,
 		Scheme:        scheme,
 		EventRecorder: eventRecorder,
+		apiReader:     newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
 	}
 
 	return reconciler.SetupWithManager(mgr)
@@ -0,0 +1,212 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// WorkspaceNamespaceStatusReconciler maintains one WorkspaceNamespaceStatus per namespace
+// summarizing the state of the namespace's workspaces.
+type WorkspaceNamespaceStatusReconciler struct {
+	client.Client
+
+	// debounceInterval is the minimum interval between two status writes
+	debounceInterval time.Duration
+
+	// now returns the current time (allows for unit testing)
+	now func() time.Time
+}
+
+// NewWorkspaceNamespaceStatusReconciler creates a new WorkspaceNamespaceStatusReconciler
+func NewWorkspaceNamespaceStatusReconciler(k8sClient client.Client) *WorkspaceNamespaceStatusReconciler {
+	return &WorkspaceNamespaceStatusReconciler{
+		Client:           k8sClient,
+		debounceInterval: NamespaceStatusDebounceInterval,
+		now:              time.Now,
+	}
+}
+
+// +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacenamespacestatuses/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+
+// Reconcile recomputes the namespace summary and writes it if it changed.
+// The summary object is created with the first workspace of the namespace and deleted with the last one.
+// Writes are debounced: when the previous write is more recent than the debounce interval,
+// the request is requeued so that bursts of workspace changes result in a single write.
+func (r *WorkspaceNamespaceStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx).WithValues("namespace", req.Namespace)
+
+	if req.Name != workspacev1alpha1.WorkspaceNamespaceStatusName {
+		logger.V(1).Info("Ignoring WorkspaceNamespaceStatus with unexpected name", "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	summary, err := r.computeSummary(ctx, req.Namespace)
+	if err != nil {
+		logger.Error(err, "Failed to compute namespace summary")
+		return ctrl.Result{}, err
+	}
+
+	nsStatus := &workspacev1alpha1.WorkspaceNamespaceStatus{}
+	if err := r.Get(ctx, req.NamespacedName, nsStatus); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		// Only create the summary object once the namespace has workspaces
+		if summary.TotalWorkspaces == 0 {
+			return ctrl.Result{}, nil
+		}
+		nsStatus = &workspacev1alpha1.WorkspaceNamespaceStatus{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: req.Namespace,
+			},
+		}
+		if err := r.Create(ctx, nsStatus); err != nil {
+			logger.Error(err, "Failed to create WorkspaceNamespaceStatus")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Created WorkspaceNamespaceStatus")
+	} else if summary.TotalWorkspaces == 0 {
+		// The last workspace of the namespace is gone: drop the summary object with it
+		if err := r.Delete(ctx, nsStatus); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "Failed to delete WorkspaceNamespaceStatus")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Deleted WorkspaceNamespaceStatus of namespace without workspaces")
+		return ctrl.Result{}, nil
+	}
+
+	if summaryEqual(&nsStatus.Status, summary) {
+		return ctrl.Result{}, nil
+	}
+
+	now := r.now()
+	if last := nsStatus.Status.LastUpdateTime; last != nil {
+		if elapsed := now.Sub(last.Time); elapsed < r.debounceInterval {
+			logger.V(1).Info("Debouncing namespace summary update", "retryAfter", r.debounceInterval-elapsed)
+			return ctrl.Result{RequeueAfter: r.debounceInterval - elapsed}, nil
+		}
+	}
+
+	summary.LastUpdateTime = &metav1.Time{Time: now}
+	nsStatus.Status = *summary
+	if err := r.Status().Update(ctx, nsStatus); err != nil {
+		logger.Error(err, "Failed to update WorkspaceNamespaceStatus")
+		return ctrl.Result{}, err
+	}
+
+	logger.V(1).Info("Updated namespace summary",
+		"totalWorkspaces", summary.TotalWorkspaces,
+		"degradedWorkspaces", summary.DegradedWorkspaces)
+	return ctrl.Result{}, nil
+}
+
+// computeSummary aggregates the workspaces and resource quotas of a namespace
+func (r *WorkspaceNamespaceStatusReconciler) computeSummary(ctx context.Context, namespace string) (*workspacev1alpha1.WorkspaceNamespaceSummary, error) {
+	summary := &workspacev1alpha1.WorkspaceNamespaceSummary{}
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	if err := r.List(ctx, workspaces, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	for i := range workspaces.Items {
+		ws := &workspaces.Items[i]
+		if !ws.DeletionTimestamp.IsZero() {
+			continue
+		}
+		summary.TotalWorkspaces++
+
+		if degraded := FindCondition(&ws.Status.Conditions, ConditionTypeDegraded); degraded != nil && degraded.Status == metav1.ConditionTrue {
+			summary.DegradedWorkspaces++
+		}
+
+		switch GetWorkspacePhase(ws) {
+		case WorkspacePhaseRunning:
+			summary.PhaseCounts.Running++
+		case WorkspacePhasePending:
+			summary.PhaseCounts.Pending++
+			since := ws.CreationTimestamp
+			if progressing := FindCondition(&ws.Status.Conditions, ConditionTypeProgressing); progressing != nil {
+				since = progressing.LastTransitionTime
+			}
+			if summary.OldestPendingSince == nil || since.Before(summary.OldestPendingSince) {
+				summary.OldestPendingSince = since.DeepCopy()
+			}
+		case WorkspacePhaseStopping:
+			summary.PhaseCounts.Stopping++
+		case WorkspacePhaseStopped:
+			summary.PhaseCounts.Stopped++
+		default:
+			summary.PhaseCounts.Unknown++
+		}
+	}
+
+	quotas := &corev1.ResourceQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	for _, quota := range quotas.Items {
+		summary.Quotas = append(summary.Quotas, workspacev1alpha1.NamespaceQuotaUsage{
+			Name: quota.Name,
+			Hard: quota.Status.Hard,
+			Used: quota.Status.Used,
+		})
+	}
+
+	return summary, nil
+}
+
+// summaryEqual compares two summaries ignoring the last update time
+func summaryEqual(existing, desired *workspacev1alpha1.WorkspaceNamespaceSummary) bool {
+	a := existing.DeepCopy()
+	b := desired.DeepCopy()
+	a.LastUpdateTime = nil
+	b.LastUpdateTime = nil
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+// Workspace and ResourceQuota changes enqueue the summary object of their namespace.
+func (r *WorkspaceNamespaceStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&workspacev1alpha1.WorkspaceNamespaceStatus{}).
+		Watches(&workspacev1alpha1.Workspace{}, handler.EnqueueRequestsFromMapFunc(namespaceStatusRequest)).
+		Watches(&corev1.ResourceQuota{}, handler.EnqueueRequestsFromMapFunc(namespaceStatusRequest)).
+		Named("workspacenamespacestatus").
+		Complete(r)
+}
+
+// namespaceStatusRequest maps any namespaced object to the summary object of its namespace
+func namespaceStatusRequest(_ context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{
+			Name:      workspacev1alpha1.WorkspaceNamespaceStatusName,
+			Namespace: obj.GetNamespace(),
+		}},
+	}
+}
+
+// SetupWorkspaceNamespaceStatusController sets up the WorkspaceNamespaceStatus controller with the Manager
+func SetupWorkspaceNamespaceStatusController(mgr ctrl.Manager) error {
+	return NewWorkspaceNamespaceStatusReconciler(newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout)).SetupWithManager(mgr)
+}
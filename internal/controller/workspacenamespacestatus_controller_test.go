@@ -0,0 +1,256 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testSummaryNamespace = "team-a"
+
+type namespaceStatusFixture struct {
+	client     client.Client
+	reconciler *WorkspaceNamespaceStatusReconciler
+	clock      time.Time
+}
+
+func newNamespaceStatusFixture(t *testing.T, objects ...client.Object) *namespaceStatusFixture {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceNamespaceStatus{}).
+		Build()
+
+	f := &namespaceStatusFixture{
+		client: k8sClient,
+		clock:  time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.reconciler = NewWorkspaceNamespaceStatusReconciler(k8sClient)
+	f.reconciler.now = func() time.Time { return f.clock }
+	return f
+}
+
+func (f *namespaceStatusFixture) reconcile(t *testing.T) ctrl.Result {
+	result, err := f.reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testSummaryNamespace, Name: workspacev1alpha1.WorkspaceNamespaceStatusName},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+// reconcileUntilSettled replays debounced requeues like the workqueue would, advancing the clock
+func (f *namespaceStatusFixture) reconcileUntilSettled(t *testing.T) {
+	for i := 0; i < 5; i++ {
+		result := f.reconcile(t)
+		if result.RequeueAfter == 0 {
+			return
+		}
+		f.clock = f.clock.Add(result.RequeueAfter)
+	}
+	t.Fatal("namespace summary did not settle")
+}
+
+func (f *namespaceStatusFixture) summary(t *testing.T) *workspacev1alpha1.WorkspaceNamespaceStatus {
+	nsStatus := &workspacev1alpha1.WorkspaceNamespaceStatus{}
+	require.NoError(t, f.client.Get(context.Background(), types.NamespacedName{
+		Namespace: testSummaryNamespace, Name: workspacev1alpha1.WorkspaceNamespaceStatusName,
+	}, nsStatus))
+	return nsStatus
+}
+
+func summaryTestWorkspace(name, desiredStatus string, conditions ...metav1.Condition) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testSummaryNamespace},
+		Spec:       workspacev1alpha1.WorkspaceSpec{DisplayName: name, DesiredStatus: desiredStatus},
+		Status:     workspacev1alpha1.WorkspaceStatus{Conditions: conditions},
+	}
+}
+
+func summaryCondition(conditionType string, status metav1.ConditionStatus, since time.Time) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status, LastTransitionTime: metav1.NewTime(since)}
+}
+
+func TestNamespaceStatusNotCreatedWithoutWorkspaces(t *testing.T) {
+	f := newNamespaceStatusFixture(t)
+
+	f.reconcile(t)
+
+	list := &workspacev1alpha1.WorkspaceNamespaceStatusList{}
+	require.NoError(t, f.client.List(context.Background(), list))
+	assert.Empty(t, list.Items)
+}
+
+func TestNamespaceStatusSummarizesWorkspaces(t *testing.T) {
+	oldest := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)
+
+	quota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "compute", Namespace: testSummaryNamespace},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("8")},
+			Used: corev1.ResourceList{corev1.ResourceLimitsCPU: resource.MustParse("2")},
+		},
+	}
+
+	f := newNamespaceStatusFixture(t,
+		summaryTestWorkspace("running", DesiredStateRunning,
+			summaryCondition(ConditionTypeAvailable, metav1.ConditionTrue, oldest)),
+		summaryTestWorkspace("pending-old", DesiredStateRunning,
+			summaryCondition(ConditionTypeProgressing, metav1.ConditionTrue, oldest)),
+		summaryTestWorkspace("pending-new", DesiredStateRunning,
+			summaryCondition(ConditionTypeProgressing, metav1.ConditionTrue, newer),
+			summaryCondition(ConditionTypeDegraded, metav1.ConditionTrue, newer)),
+		summaryTestWorkspace("stopping", DesiredStateStopped,
+			summaryCondition(ConditionTypeProgressing, metav1.ConditionTrue, newer)),
+		summaryTestWorkspace("stopped", DesiredStateStopped,
+			summaryCondition(ConditionTypeStopped, metav1.ConditionTrue, newer)),
+		summaryTestWorkspace("new", ""),
+		&workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "team-b"},
+		},
+		quota,
+	)
+
+	f.reconcile(t)
+	status := f.summary(t).Status
+
+	assert.Equal(t, int32(6), status.TotalWorkspaces)
+	assert.Equal(t, workspacev1alpha1.WorkspacePhaseCounts{
+		Running: 1, Pending: 2, Stopping: 1, Stopped: 1, Unknown: 1,
+	}, status.PhaseCounts)
+	assert.Equal(t, int32(1), status.DegradedWorkspaces)
+	require.NotNil(t, status.OldestPendingSince)
+	assert.True(t, status.OldestPendingSince.Time.Equal(oldest))
+	require.Len(t, status.Quotas, 1)
+	assert.Equal(t, "compute", status.Quotas[0].Name)
+	assert.True(t, resource.MustParse("2").Equal(status.Quotas[0].Used[corev1.ResourceLimitsCPU]))
+	require.NotNil(t, status.LastUpdateTime)
+	assert.True(t, status.LastUpdateTime.Time.Equal(f.clock))
+}
+
+func TestNamespaceStatusDebouncesWrites(t *testing.T) {
+	f := newNamespaceStatusFixture(t, summaryTestWorkspace("ws-0", DesiredStateRunning))
+	ctx := context.Background()
+
+	f.reconcile(t)
+	firstUpdate := f.summary(t).Status.LastUpdateTime.Time
+
+	// A change shortly after the previous write is deferred
+	require.NoError(t, f.client.Create(ctx, summaryTestWorkspace("ws-1", DesiredStateRunning)))
+	f.clock = f.clock.Add(3 * time.Second)
+
+	result := f.reconcile(t)
+	assert.Equal(t, NamespaceStatusDebounceInterval-3*time.Second, result.RequeueAfter)
+	assert.Equal(t, int32(1), f.summary(t).Status.TotalWorkspaces)
+
+	// Once the interval has elapsed the change is written
+	f.clock = f.clock.Add(result.RequeueAfter)
+	result = f.reconcile(t)
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, int32(2), f.summary(t).Status.TotalWorkspaces)
+	assert.True(t, f.summary(t).Status.LastUpdateTime.Time.After(firstUpdate))
+}
+
+func TestNamespaceStatusSkipsUnchangedWrites(t *testing.T) {
+	f := newNamespaceStatusFixture(t, summaryTestWorkspace("ws-0", DesiredStateRunning))
+
+	f.reconcile(t)
+	before := f.summary(t)
+
+	f.clock = f.clock.Add(time.Minute)
+	result := f.reconcile(t)
+	after := f.summary(t)
+
+	assert.Zero(t, result.RequeueAfter)
+	assert.Equal(t, before.ResourceVersion, after.ResourceVersion)
+	assert.True(t, after.Status.LastUpdateTime.Time.Equal(before.Status.LastUpdateTime.Time))
+}
+
+func TestNamespaceStatusConvergesAfterBulkCreateAndDelete(t *testing.T) {
+	f := newNamespaceStatusFixture(t)
+	ctx := context.Background()
+	const count = 50
+
+	for i := 0; i < count; i++ {
+		ws := summaryTestWorkspace(fmt.Sprintf("bulk-%d", i), DesiredStateRunning,
+			summaryCondition(ConditionTypeAvailable, metav1.ConditionTrue, f.clock))
+		require.NoError(t, f.client.Create(ctx, ws))
+		// Every workspace event enqueues the summary; the controller must not fall behind
+		f.reconcile(t)
+		f.clock = f.clock.Add(100 * time.Millisecond)
+	}
+	f.reconcileUntilSettled(t)
+
+	status := f.summary(t).Status
+	assert.Equal(t, int32(count), status.TotalWorkspaces)
+	assert.Equal(t, int32(count), status.PhaseCounts.Running)
+
+	for i := 0; i < count; i++ {
+		ws := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("bulk-%d", i), Namespace: testSummaryNamespace}}
+		require.NoError(t, f.client.Delete(ctx, ws))
+		f.reconcile(t)
+		f.clock = f.clock.Add(100 * time.Millisecond)
+	}
+	f.reconcileUntilSettled(t)
+
+	list := &workspacev1alpha1.WorkspaceNamespaceStatusList{}
+	require.NoError(t, f.client.List(ctx, list, client.InNamespace(testSummaryNamespace)))
+	assert.Empty(t, list.Items, "the summary object must be deleted with the last workspace")
+}
+
+func TestNamespaceStatusDeletedWithLastWorkspace(t *testing.T) {
+	ctx := context.Background()
+	ws := summaryTestWorkspace("ws-0", DesiredStateRunning)
+	f := newNamespaceStatusFixture(t, ws)
+	f.reconcile(t)
+	f.summary(t)
+
+	require.NoError(t, f.client.Delete(ctx, ws))
+	f.reconcile(t)
+
+	err := f.client.Get(ctx, types.NamespacedName{
+		Namespace: testSummaryNamespace, Name: workspacev1alpha1.WorkspaceNamespaceStatusName,
+	}, &workspacev1alpha1.WorkspaceNamespaceStatus{})
+	assert.True(t, errors.IsNotFound(err), "expected the summary object to be deleted, got %v", err)
+
+	// Reconciling the deleted summary again is a no-op
+	f.reconcile(t)
+}
+
+func TestNamespaceStatusIgnoresUnexpectedName(t *testing.T) {
+	f := newNamespaceStatusFixture(t, summaryTestWorkspace("ws-0", DesiredStateRunning))
+
+	result, err := f.reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Namespace: testSummaryNamespace, Name: "something-else"},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+
+	list := &workspacev1alpha1.WorkspaceNamespaceStatusList{}
+	require.NoError(t, f.client.List(context.Background(), list))
+	assert.Empty(t, list.Items)
+}
@@ -6,10 +6,14 @@ Distributed under the terms of the MIT license
 package controller
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
 
+	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -33,10 +37,18 @@ type WorkspaceTemplateReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	recorder record.EventRecorder
+
+	// labelMigration backfills the template labels the usage checks rely on; templates keep their
+	// finalizer until it completes
+	labelMigration *TemplateLabelMigration
+
+	// apiReader reads past the cache to confirm that no workspace uses a template before its finalizer is removed
+	apiReader client.Reader
 }
 
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacetemplates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=workspace.jupyter.org,resources=workspacetemplates/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -64,6 +76,14 @@ func (r *WorkspaceTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Handle spec changes to track generation updates
 	shouldUpdateStatus, newGeneration := r.handleSpecChanges(ctx, template)
 
+	// Keep the new generation for the workspaces admitted against it
+	if shouldUpdateStatus {
+		if err := r.recordTemplateRevision(ctx, template); err != nil {
+			logger.Error(err, "Failed to record template revision")
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Manage finalizer based on workspace usage (lazy finalizer pattern)
 	// This follows Kubernetes best practice: only add finalizers when needed
 	result, err := r.manageFinalizer(ctx, template)
@@ -71,6 +91,12 @@ func (r *WorkspaceTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return result, err
 	}
 
+	// Keep the count of workspaces the delete webhook asks administrators to confirm
+	if err := r.updateStatusWorkspaceCount(ctx, template); err != nil {
+		logger.Error(err, "Failed to update status.workspaceCount")
+		return ctrl.Result{}, err
+	}
+
 	// Update status.observedGeneration AFTER all reconciliation work completes
 	// This follows Kubernetes semantics: observedGeneration reflects fully-processed state
 	if shouldUpdateStatus {
@@ -111,8 +137,9 @@ func (r *WorkspaceTemplateReconciler) manageFinalizer(ctx context.Context, templ
 		logger.Info("Adding finalizer to template (workspaces are using it)",
 			"finalizer", templateFinalizerName,
 			"hasWorkspaces", hasWorkspaces)
+		original := template.DeepCopy()
 		controllerutil.AddFinalizer(template, templateFinalizerName)
-		if err := r.Update(ctx, template); err != nil {
+		if err := workspace.PatchChanges(ctx, r.Client, template, original); err != nil {
 			logger.Error(err, "Failed to add finalizer to template")
 			return ctrl.Result{}, err
 		}
@@ -122,11 +149,25 @@ func (r *WorkspaceTemplateReconciler) manageFinalizer(ctx context.Context, templ
 
 	// Case 2: No workspaces, but finalizer is present → Remove finalizer
 	// This handles the case where all workspaces were deleted
+	if !hasWorkspaces && hasFinalizer && !r.labelMigration.Completed() {
+		logger.V(1).Info("Keeping finalizer until the template label migration completes")
+		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+	}
 	if !hasWorkspaces && hasFinalizer {
+		unused, err := r.confirmTemplateUnused(ctx, template)
+		if err != nil {
+			logger.Error(err, "Failed to confirm that no workspace uses the template, keeping its finalizer")
+			return ctrl.Result{}, err
+		}
+		if !unused {
+			logger.Info("Keeping finalizer until the cache lists the workspaces using the template")
+			return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+		}
 		logger.Info("Removing finalizer from template (no workspaces using it)",
 			"finalizer", templateFinalizerName)
+		original := template.DeepCopy()
 		controllerutil.RemoveFinalizer(template, templateFinalizerName)
-		if err := r.Update(ctx, template); err != nil {
+		if err := workspace.PatchChanges(ctx, r.Client, template, original); err != nil {
 			logger.Error(err, "Failed to remove finalizer from template")
 			return ctrl.Result{}, err
 		}
@@ -139,6 +180,20 @@ func (r *WorkspaceTemplateReconciler) manageFinalizer(ctx context.Context, templ
 	return ctrl.Result{}, nil
 }
 
+// confirmTemplateUnused lists every page of the workspaces using the template from the API server, once the
+// cache lists none: the cache lags behind workspaces just created, and the finalizer is not added back in time
+func (r *WorkspaceTemplateReconciler) confirmTemplateUnused(
+	ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) (bool, error) {
+	if r.apiReader == nil {
+		return true, nil
+	}
+	hasWorkspaces, err := workspace.HasActiveWorkspacesWithTemplate(ctx, r.apiReader, template.Name, template.Namespace)
+	if err != nil {
+		return false, err
+	}
+	return !hasWorkspaces, nil
+}
+
 func (r *WorkspaceTemplateReconciler) handleDeletion(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 	logger.Info("Handling template deletion", "templateName", template.Name)
@@ -177,11 +232,31 @@ func (r *WorkspaceTemplateReconciler) handleDeletion(ctx context.Context, templa
 		return ctrl.Result{}, nil
 	}
 
+	// Workspaces the migration did not label yet are invisible to the usage check
+	if !r.labelMigration.Completed() {
+		logger.Info("Template label migration not completed, blocking deletion",
+			"templateName", template.Name)
+		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+	}
+
+	unused, err := r.confirmTemplateUnused(ctx, template)
+	if err != nil {
+		logger.Error(err, "Failed to confirm that no workspace uses the template, blocking deletion",
+			"templateName", template.Name)
+		return ctrl.Result{}, err
+	}
+	if !unused {
+		logger.Info("Template is in use by workspaces the cache does not list yet, blocking deletion",
+			"templateName", template.Name)
+		return ctrl.Result{RequeueAfter: PollRequeueDelay}, nil
+	}
+
 	// No workspaces using template - safe to delete
 	logger.Info("No workspaces using template, removing finalizer",
 		"templateName", template.Name)
+	original := template.DeepCopy()
 	controllerutil.RemoveFinalizer(template, templateFinalizerName)
-	if err := r.Update(ctx, template); err != nil {
+	if err := workspace.PatchChanges(ctx, r.Client, template, original); err != nil {
 		logger.Error(err, "Failed to remove finalizer from template",
 			"templateName", template.Name)
 		return ctrl.Result{}, err
@@ -259,19 +334,22 @@ func (r *WorkspaceTemplateReconciler) findTemplatesForWorkspace(ctx context.Cont
 	}
 }
 
-// SetupWorkspaceTemplateController sets up the WorkspaceTemplate controller with the Manager
-func SetupWorkspaceTemplateController(mgr ctrl.Manager) error {
+// SetupWorkspaceTemplateController sets up the WorkspaceTemplate controller with the Manager.
+// Templates keep their finalizer until the label migration, when not nil, completes.
+func SetupWorkspaceTemplateController(mgr ctrl.Manager, labelMigration *TemplateLabelMigration) error {
 	logger := mgr.GetLogger().WithName("workspacetemplate-init")
 	logger.Info("Initializing WorkspaceTemplate controller")
 
-	k8sClient := mgr.GetClient()
+	k8sClient := newTimeoutClient(mgr.GetClient(), KubernetesAPICallTimeout)
 	scheme := mgr.GetScheme()
 	eventRecorder := mgr.GetEventRecorderFor("workspacetemplate-controller")
 
 	reconciler := &WorkspaceTemplateReconciler{
-		Client:   k8sClient,
-		Scheme:   scheme,
-		recorder: eventRecorder,
+		Client:         k8sClient,
+		Scheme:         scheme,
+		recorder:       eventRecorder,
+		labelMigration: labelMigration,
+		apiReader:      newTimeoutReader(mgr.GetAPIReader(), KubernetesAPICallTimeout),
 	}
 
 	logger.Info("Calling SetupWithManager for WorkspaceTemplate controller")
@@ -343,3 +421,89 @@ func (r *WorkspaceTemplateReconciler) updateStatusObservedGeneration(ctx context
 
 	return nil
 }
+
+// updateStatusWorkspaceCount updates the template's status.workspaceCount field to the number of active
+// workspaces using the template
+func (r *WorkspaceTemplateReconciler) updateStatusWorkspaceCount(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	workspaces, _, err := workspace.ListActiveWorkspacesByTemplate(ctx, r.Client, template.Name, template.Namespace, "", 0)
+	if err != nil {
+		return err
+	}
+	count := int32(len(workspaces))
+	if template.Status.WorkspaceCount == count {
+		return nil
+	}
+
+	patch := client.MergeFrom(template.DeepCopy())
+	template.Status.WorkspaceCount = count
+	if err := r.Status().Patch(ctx, template, patch); err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	logf.FromContext(ctx).V(1).Info("Updated status.workspaceCount",
+		"templateName", template.Name,
+		"workspaceCount", count)
+	return nil
+}
+
+// recordTemplateRevision stores the spec of the current template generation in a ControllerRevision, from
+// which the workspace controller resolves the template of workspaces admitted against that generation.
+// A revision of the same name left by a deleted template of the same name is replaced. Only the latest
+// MaxTemplateRevisions revisions are kept.
+func (r *WorkspaceTemplateReconciler) recordTemplateRevision(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	logger := logf.FromContext(ctx)
+
+	revision, err := workspace.NewTemplateRevision(template)
+	if err != nil {
+		return err
+	}
+	err = r.Create(ctx, revision)
+	if errors.IsAlreadyExists(err) {
+		err = r.replaceForeignTemplateRevision(ctx, template, revision)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create template revision %s: %w", revision.Name, err)
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := r.List(ctx, revisions, client.InNamespace(template.Namespace),
+		client.MatchingLabels{workspace.LabelWorkspaceTemplate: template.Name}); err != nil {
+		return fmt.Errorf("failed to list template revisions: %w", err)
+	}
+	owned := make([]appsv1.ControllerRevision, 0, len(revisions.Items))
+	for _, item := range revisions.Items {
+		if metav1.IsControlledBy(&item, template) {
+			owned = append(owned, item)
+		}
+	}
+	slices.SortFunc(owned, func(a, b appsv1.ControllerRevision) int { return cmp.Compare(a.Revision, b.Revision) })
+	for len(owned) > workspace.MaxTemplateRevisions {
+		logger.V(1).Info("Pruning template revision", "revision", owned[0].Name)
+		if err := r.Delete(ctx, &owned[0]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to prune template revision %s: %w", owned[0].Name, err)
+		}
+		owned = owned[1:]
+	}
+	return nil
+}
+
+// replaceForeignTemplateRevision replaces the existing revision named like the given one when another template
+// controls it, which happens when a template is deleted and created again under the same name before the
+// garbage collector removes the revisions of the deleted one
+func (r *WorkspaceTemplateReconciler) replaceForeignTemplateRevision(
+	ctx context.Context,
+	template *workspacev1alpha1.WorkspaceTemplate,
+	revision *appsv1.ControllerRevision) error {
+	existing := &appsv1.ControllerRevision{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(revision), existing); err != nil {
+		return err
+	}
+	if metav1.IsControlledBy(existing, template) {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Replacing template revision left by a deleted template", "revision", existing.Name)
+	if err := r.Delete(ctx, existing, client.Preconditions{UID: &existing.UID}); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return r.Create(ctx, revision)
+}
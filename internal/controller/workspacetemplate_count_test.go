@@ -0,0 +1,60 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+func newCountTestWorkspace(name string) *workspacev1alpha1.Workspace {
+	workspace := newAdoptionTestWorkspace(name)
+	workspace.Labels = map[string]string{
+		workspaceutil.LabelWorkspaceTemplate:          "base",
+		workspaceutil.LabelWorkspaceTemplateNamespace: "team-a",
+	}
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "base", Namespace: "team-a"}
+	return workspace
+}
+
+func TestTemplateStatusCountsTheWorkspacesUsingIt(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	template := newRevisionTestTemplate()
+	template.Status.WorkspaceCount = 5
+	deleting := newCountTestWorkspace("ws-deleting")
+	deleting.Finalizers = []string{"test"}
+	deleting.DeletionTimestamp = &metav1.Time{Time: metav1.Now().Time}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(template, newCountTestWorkspace("ws-a"), newCountTestWorkspace("ws-b"), deleting).
+		WithStatusSubresource(&workspacev1alpha1.WorkspaceTemplate{}).
+		Build()
+	reconciler := &WorkspaceTemplateReconciler{Client: k8sClient, Scheme: scheme}
+
+	require.NoError(t, reconciler.updateStatusWorkspaceCount(ctx, template))
+	stored := &workspacev1alpha1.WorkspaceTemplate{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(template), stored))
+	assert.Equal(t, int32(2), stored.Status.WorkspaceCount)
+
+	// The count drops to zero once the last workspace is gone
+	require.NoError(t, k8sClient.Delete(ctx, newCountTestWorkspace("ws-a")))
+	require.NoError(t, k8sClient.Delete(ctx, newCountTestWorkspace("ws-b")))
+	require.NoError(t, reconciler.updateStatusWorkspaceCount(ctx, stored))
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(template), stored))
+	assert.Zero(t, stored.Status.WorkspaceCount)
+}
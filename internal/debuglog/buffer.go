@@ -0,0 +1,155 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package debuglog keeps the most recent log records of each workspace in memory, so that
+// support engineers can read what the controller decided about one workspace without
+// searching the logs of the operator. The extension API serves the records, read by
+// `kubectl workspace debug-log NAME`.
+package debuglog
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultMaxRecordsPerObject is the number of records kept for each workspace
+	DefaultMaxRecordsPerObject = 200
+	// DefaultMaxObjects is the number of workspaces records are kept for; the workspace
+	// logged to least recently is forgotten first
+	DefaultMaxObjects = 1000
+	// MaxValueLength bounds the length of each value of a record, longer values are truncated
+	MaxValueLength = 2048
+)
+
+// Default is the buffer the controller writes to and the extension API reads from
+var Default = NewBuffer(DefaultMaxRecordsPerObject, DefaultMaxObjects)
+
+// Record is a log record of a workspace
+type Record struct {
+	Time    time.Time `json:"time"`
+	Level   int       `json:"level"`
+	Logger  string    `json:"logger,omitempty"`
+	Message string    `json:"message"`
+	Error   string    `json:"error,omitempty"`
+	// Values are the key/value pairs of the record, redacted and formatted
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// entry holds the records of one workspace in a ring
+type entry struct {
+	key     types.NamespacedName
+	records []Record
+	next    int
+	full    bool
+	// debugSince is when the debug verbosity of the workspace was first requested, or zero
+	debugSince time.Time
+}
+
+// Buffer keeps the last records of each object, bounded both in records per object and in objects
+type Buffer struct {
+	maxRecords int
+	maxObjects int
+
+	mu      sync.Mutex
+	entries map[types.NamespacedName]*list.Element
+	// lru orders the entries from the most to the least recently written one
+	lru *list.List
+}
+
+// NewBuffer returns a buffer keeping at most maxRecords records for each of at most maxObjects objects
+func NewBuffer(maxRecords, maxObjects int) *Buffer {
+	return &Buffer{
+		maxRecords: max(maxRecords, 1),
+		maxObjects: max(maxObjects, 1),
+		entries:    map[types.NamespacedName]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// entryFor returns the entry of the key, creating it and evicting the least recently used
+// entry when the buffer is full. The caller holds the lock.
+func (b *Buffer) entryFor(key types.NamespacedName) *entry {
+	if element, ok := b.entries[key]; ok {
+		b.lru.MoveToFront(element)
+		return element.Value.(*entry)
+	}
+	if b.lru.Len() >= b.maxObjects {
+		oldest := b.lru.Back()
+		b.lru.Remove(oldest)
+		delete(b.entries, oldest.Value.(*entry).key)
+	}
+	e := &entry{key: key, records: make([]Record, 0, min(b.maxRecords, 16))}
+	b.entries[key] = b.lru.PushFront(e)
+	return e
+}
+
+// Add appends a record of the object, replacing its oldest record when it has maxRecords
+func (b *Buffer) Add(key types.NamespacedName, record Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e := b.entryFor(key)
+	if len(e.records) < b.maxRecords {
+		e.records = append(e.records, record)
+		return
+	}
+	e.records[e.next] = record
+	e.next = (e.next + 1) % b.maxRecords
+	e.full = true
+}
+
+// Records returns the records of the object written at or after since, oldest first
+func (b *Buffer) Records(key types.NamespacedName, since time.Time) []Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	element, ok := b.entries[key]
+	if !ok {
+		return nil
+	}
+	e := element.Value.(*entry)
+	ordered := make([]Record, 0, len(e.records))
+	if e.full {
+		ordered = append(ordered, e.records[e.next:]...)
+		ordered = append(ordered, e.records[:e.next]...)
+	} else {
+		ordered = append(ordered, e.records...)
+	}
+
+	records := ordered[:0]
+	for _, record := range ordered {
+		if !record.Time.Before(since) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// DebugSince records that the debug verbosity of the object is requested and returns when it was
+// first requested, so that the elevated verbosity stays bounded however often it is checked
+func (b *Buffer) DebugSince(key types.NamespacedName, now time.Time) time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	e := b.entryFor(key)
+	if e.debugSince.IsZero() {
+		e.debugSince = now
+	}
+	return e.debugSince
+}
+
+// ClearDebug forgets the debug verbosity request of the object, the next request starts a new period
+func (b *Buffer) ClearDebug(key types.NamespacedName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if element, ok := b.entries[key]; ok {
+		element.Value.(*entry).debugSince = time.Time{}
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package debuglog
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestBufferKeepsTheLastRecordsOfEachObject(t *testing.T) {
+	buffer := NewBuffer(3, 10)
+	key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+	start := time.Now()
+	for i := range 5 {
+		buffer.Add(key, Record{Time: start.Add(time.Duration(i) * time.Second), Message: fmt.Sprint(i)})
+	}
+
+	var messages []string
+	for _, record := range buffer.Records(key, time.Time{}) {
+		messages = append(messages, record.Message)
+	}
+	assert.Equal(t, []string{"2", "3", "4"}, messages, "oldest first, the first records are replaced")
+
+	recent := buffer.Records(key, start.Add(4*time.Second))
+	assert.Len(t, recent, 1)
+	assert.Empty(t, buffer.Records(types.NamespacedName{Namespace: "team-a", Name: "other"}, time.Time{}))
+}
+
+func TestBufferForgetsTheLeastRecentlyWrittenObject(t *testing.T) {
+	buffer := NewBuffer(3, 2)
+	first := types.NamespacedName{Namespace: "team-a", Name: "first"}
+	second := types.NamespacedName{Namespace: "team-a", Name: "second"}
+	third := types.NamespacedName{Namespace: "team-a", Name: "third"}
+
+	buffer.Add(first, Record{Message: "a"})
+	buffer.Add(second, Record{Message: "b"})
+	buffer.Add(first, Record{Message: "c"})
+	buffer.Add(third, Record{Message: "d"})
+
+	assert.Len(t, buffer.Records(first, time.Time{}), 2)
+	assert.Empty(t, buffer.Records(second, time.Time{}))
+	assert.Len(t, buffer.Records(third, time.Time{}), 1)
+}
+
+func TestBufferDebugSince(t *testing.T) {
+	buffer := NewBuffer(3, 2)
+	key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+	start := time.Now()
+
+	assert.Equal(t, start, buffer.DebugSince(key, start))
+	assert.Equal(t, start, buffer.DebugSince(key, start.Add(time.Hour)), "checking again does not extend the period")
+
+	buffer.ClearDebug(key)
+	assert.Equal(t, start.Add(time.Hour), buffer.DebugSince(key, start.Add(time.Hour)))
+}
@@ -0,0 +1,130 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package debuglog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// Redacted replaces the values that may hold secrets
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyParts are the parts of keys whose values are redacted, compared in lower case
+var sensitiveKeyParts = []string{"password", "passwd", "secret", "token", "credential", "apikey", "api-key",
+	"api_key", "privatekey", "private-key", "private_key", "authorization", "cookie"}
+
+// isSensitiveKey returns true if values of the key may hold secrets
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactKeysAndValues returns the key/value pairs with the values that may hold secrets redacted
+func RedactKeysAndValues(keysAndValues []any) []any {
+	redacted := make([]any, len(keysAndValues))
+	for i := 0; i < len(keysAndValues); i += 2 {
+		redacted[i] = keysAndValues[i]
+		if i+1 >= len(keysAndValues) {
+			break
+		}
+		key, _ := keysAndValues[i].(string)
+		if isSensitiveKey(key) {
+			redacted[i+1] = Redacted
+			continue
+		}
+		redacted[i+1] = Redact(keysAndValues[i+1])
+	}
+	return redacted
+}
+
+// Redact returns the value with its fields that may hold secrets redacted. Structured values,
+// e.g. a workspace spec, are returned as their JSON form with the values of sensitive keys and
+// of environment variables replaced; scalars, errors and Stringers are returned unchanged.
+func Redact(value any) any {
+	switch value.(type) {
+	case nil, string, bool, int, int32, int64, uint, uint32, uint64, float32, float64, error, fmt.Stringer:
+		return value
+	}
+	if marshaler, ok := value.(logr.Marshaler); ok {
+		value = marshaler.MarshalLog()
+	}
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return value
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+	return redactGeneric(generic)
+}
+
+// redactGeneric redacts a decoded JSON value in place
+func redactGeneric(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		// Environment variables and data entries carry their value beside their name
+		_, hasName := typed["name"]
+		_, hasValue := typed["value"]
+		for key, child := range typed {
+			if isSensitiveKey(key) || (hasName && hasValue && key == "value") {
+				typed[key] = Redacted
+				continue
+			}
+			typed[key] = redactGeneric(child)
+		}
+		return typed
+	case []any:
+		for i := range typed {
+			typed[i] = redactGeneric(typed[i])
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// formatValue formats a redacted value for a record, truncated to MaxValueLength
+func formatValue(value any) string {
+	var formatted string
+	switch typed := value.(type) {
+	case string:
+		formatted = typed
+	case error:
+		formatted = typed.Error()
+	case fmt.Stringer:
+		formatted = typed.String()
+	case map[string]any, []any:
+		data, err := json.Marshal(typed)
+		if err != nil {
+			formatted = fmt.Sprintf("%v", typed)
+		} else {
+			formatted = string(data)
+		}
+	default:
+		formatted = fmt.Sprintf("%v", typed)
+	}
+	if len(formatted) > MaxValueLength {
+		formatted = formatted[:MaxValueLength] + "...(truncated)"
+	}
+	return formatted
+}
@@ -0,0 +1,133 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package debuglog
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// captureSink forwards records to the sink of the operator and copies them to the buffer of one
+// object. With debug set, records of every verbosity are kept and written, at the verbosity of the
+// operator, so that one object can be debugged without raising the verbosity of all of them.
+type captureSink struct {
+	base   logr.LogSink
+	buffer *Buffer
+	key    types.NamespacedName
+	debug  bool
+	name   string
+	// values are the values of the logger the records are captured with, base already holds them
+	values []any
+	now    func() time.Time
+}
+
+var _ logr.CallDepthLogSink = &captureSink{}
+
+// NewCaptureLogger returns a logger writing to base that also keeps its records in the buffer, under
+// key. Values of keys and fields that may hold secrets are redacted from both. keysAndValues are
+// added to the records kept in the buffer only, for the values base was created with.
+func NewCaptureLogger(base logr.Logger, buffer *Buffer, key types.NamespacedName, debug bool,
+	keysAndValues ...any) logr.Logger {
+	sink := base.GetSink()
+	if sink == nil {
+		return base
+	}
+	// The capture sink adds a frame between the caller and the sink of the operator
+	if withDepth, ok := sink.(logr.CallDepthLogSink); ok {
+		sink = withDepth.WithCallDepth(1)
+	}
+	return logr.New(&captureSink{
+		base:   sink,
+		buffer: buffer,
+		key:    key,
+		debug:  debug,
+		values: RedactKeysAndValues(keysAndValues),
+		now:    time.Now,
+	}).V(base.GetV())
+}
+
+// Init implements logr.LogSink, the sink of the operator is initialized already
+func (s *captureSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements logr.LogSink
+func (s *captureSink) Enabled(level int) bool {
+	return s.debug || s.base.Enabled(level)
+}
+
+// Info implements logr.LogSink
+func (s *captureSink) Info(level int, msg string, keysAndValues ...any) {
+	keysAndValues = RedactKeysAndValues(keysAndValues)
+	s.record(level, msg, nil, keysAndValues)
+	if s.base.Enabled(level) {
+		s.base.Info(level, msg, keysAndValues...)
+		return
+	}
+	s.base.Info(0, msg, append(keysAndValues, "debugLevel", level)...)
+}
+
+// Error implements logr.LogSink
+func (s *captureSink) Error(err error, msg string, keysAndValues ...any) {
+	keysAndValues = RedactKeysAndValues(keysAndValues)
+	s.record(0, msg, err, keysAndValues)
+	s.base.Error(err, msg, keysAndValues...)
+}
+
+// WithValues implements logr.LogSink
+func (s *captureSink) WithValues(keysAndValues ...any) logr.LogSink {
+	keysAndValues = RedactKeysAndValues(keysAndValues)
+	clone := *s
+	clone.base = s.base.WithValues(keysAndValues...)
+	clone.values = append(append([]any{}, s.values...), keysAndValues...)
+	return &clone
+}
+
+// WithName implements logr.LogSink
+func (s *captureSink) WithName(name string) logr.LogSink {
+	clone := *s
+	clone.base = s.base.WithName(name)
+	clone.name = name
+	if s.name != "" {
+		clone.name = s.name + "." + name
+	}
+	return &clone
+}
+
+// WithCallDepth implements logr.CallDepthLogSink
+func (s *captureSink) WithCallDepth(depth int) logr.LogSink {
+	withDepth, ok := s.base.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	clone := *s
+	clone.base = withDepth.WithCallDepth(depth)
+	return &clone
+}
+
+// record keeps the record in the buffer of the object
+func (s *captureSink) record(level int, msg string, err error, keysAndValues []any) {
+	record := Record{
+		Time:    s.now(),
+		Level:   level,
+		Logger:  s.name,
+		Message: msg,
+		Values:  map[string]string{},
+	}
+	if err != nil {
+		record.Error = formatValue(err)
+	}
+	for _, pairs := range [][]any{s.values, keysAndValues} {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				continue
+			}
+			record.Values[key] = formatValue(pairs[i+1])
+		}
+	}
+	s.buffer.Add(s.key, record)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package debuglog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newTestBaseLogger returns a logger at verbosity 0 that appends its lines to lines
+func newTestBaseLogger(lines *[]string) logr.Logger {
+	return funcr.New(func(prefix, args string) {
+		*lines = append(*lines, args)
+	}, funcr.Options{})
+}
+
+func TestCaptureLoggerKeepsTheRecordsOfTheObject(t *testing.T) {
+	var lines []string
+	buffer := NewBuffer(10, 10)
+	key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+
+	logger := NewCaptureLogger(newTestBaseLogger(&lines), buffer, key, false, "reconcileID", "1234")
+	logger = logger.WithName("state-machine").WithValues("phase", "Starting")
+	logger.Info("Creating deployment", "password", "hunter2")
+	logger.V(1).Info("Debug decision")
+	logger.Error(errors.New("boom"), "Failed to create deployment")
+
+	records := buffer.Records(key, time.Time{})
+	require.Len(t, records, 2, "records above the verbosity of the operator are not kept")
+	assert.Equal(t, "Creating deployment", records[0].Message)
+	assert.Equal(t, "state-machine", records[0].Logger)
+	assert.Equal(t, map[string]string{"reconcileID": "1234", "phase": "Starting", "password": Redacted}, records[0].Values)
+	assert.Equal(t, "boom", records[1].Error)
+
+	require.Len(t, lines, 2)
+	assert.NotContains(t, lines[0], "hunter2", "the operator logs are redacted too")
+	assert.NotContains(t, lines[0], "reconcileID", "values of the base logger are not written twice")
+}
+
+func TestCaptureLoggerInDebugWritesEveryVerbosity(t *testing.T) {
+	var lines []string
+	buffer := NewBuffer(10, 10)
+	key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+
+	logger := NewCaptureLogger(newTestBaseLogger(&lines), buffer, key, true)
+	logger.V(2).Info("Debug decision", "decision", "requeue")
+
+	records := buffer.Records(key, time.Time{})
+	require.Len(t, records, 1)
+	assert.Equal(t, 2, records[0].Level)
+	assert.Equal(t, "requeue", records[0].Values["decision"])
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"debugLevel"=2`)
+}
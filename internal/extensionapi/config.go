@@ -21,6 +21,10 @@ const (
 	DefaultWriteTimeoutSeconds = 120
 	DefaultAllowedOrigin       = "*"
 
+	// DefaultCatalogCacheTTL bounds how long a cached template catalog, including the permission
+	// check behind it, is served before being computed again
+	DefaultCatalogCacheTTL = 30 * time.Second
+
 	// JWT defaults
 	DefaultJwtIssuer      = "workspaces-controller"
 	DefaultJwtAudience    = "workspaces-controller"
@@ -28,6 +32,15 @@ const (
 	DefaultNewKeyUseDelay = 5 * time.Second
 )
 
+// Names of the readiness checks of the extension API server
+const (
+	// ReadyzCheckServer fails while the extension API server does not listen
+	ReadyzCheckServer = "extension-api"
+
+	// ReadyzCheckTemplateCatalog fails until the template catalog cache follows template changes
+	ReadyzCheckTemplateCatalog = "template-catalog"
+)
+
 // ExtensionConfig contains the configuration for the extension API server
 type ExtensionConfig struct {
 	ApiPath             string
@@ -45,6 +58,10 @@ type ExtensionConfig struct {
 	// Controller namespace (from Downward API)
 	ControllerNamespace string
 
+	// Template catalog section
+	DefaultTemplateNamespace string
+	CatalogCacheTTL          time.Duration
+
 	// JWT signing section
 	JwtIssuer      string
 	JwtAudience    string
@@ -133,6 +150,20 @@ func WithControllerNamespace(ns string) ConfigOption {
 	}
 }
 
+// WithDefaultTemplateNamespace sets the namespace of the templates shared by all namespaces
+func WithDefaultTemplateNamespace(ns string) ConfigOption {
+	return func(c *ExtensionConfig) {
+		c.DefaultTemplateNamespace = ns
+	}
+}
+
+// WithCatalogCacheTTL sets how long a cached template catalog is served
+func WithCatalogCacheTTL(ttl time.Duration) ConfigOption {
+	return func(c *ExtensionConfig) {
+		c.CatalogCacheTTL = ttl
+	}
+}
+
 // WithJwtIssuer sets the JWT issuer claim.
 func WithJwtIssuer(issuer string) ConfigOption {
 	return func(c *ExtensionConfig) {
@@ -182,6 +213,7 @@ func NewConfig(opts ...ConfigOption) *ExtensionConfig {
 		ReadTimeoutSeconds:  DefaultReadTimeoutSeconds,
 		WriteTimeoutSeconds: DefaultWriteTimeoutSeconds,
 		AllowedOrigin:       DefaultAllowedOrigin,
+		CatalogCacheTTL:     DefaultCatalogCacheTTL,
 	}
 
 	// Apply all options
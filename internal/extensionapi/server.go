@@ -9,10 +9,14 @@ package extensionapi
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
+	"github.com/jupyter-infra/jupyter-k8s/internal/debuglog"
+	"github.com/jupyter-infra/jupyter-k8s/internal/health"
 	"github.com/jupyter-infra/jupyter-k8s/internal/jwt"
 	"github.com/jupyter-infra/jupyter-k8s/internal/pluginclient"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -46,6 +50,8 @@ type ExtensionServer struct {
 	genericServer  *genericapiserver.GenericAPIServer
 	routes         map[string]func(http.ResponseWriter, *http.Request)
 	mux            *mux.PathRecorderMux
+	catalogCache   *templateCatalogCache
+	debugLogs      *debuglog.Buffer
 }
 
 // NewExtensionServer creates a new extension API server using GenericAPIServer.
@@ -72,6 +78,8 @@ func NewExtensionServer(
 		routes:         make(map[string]func(http.ResponseWriter, *http.Request)),
 		genericServer:  genericServer,
 		mux:            genericServer.Handler.NonGoRestfulMux,
+		catalogCache:   newTemplateCatalogCache(config.CatalogCacheTTL, config.DefaultTemplateNamespace),
+		debugLogs:      debuglog.Default,
 	}
 }
 
@@ -171,11 +179,16 @@ func (s *ExtensionServer) registerAllRoutes() {
 	// Register API discovery route
 	s.registerRoute(s.config.ApiPath, s.handleDiscovery)
 
+	// Register build info route, read by `kubectl workspace version --server`
+	s.registerRoute(s.config.ApiPath+"/version", s.handleVersion)
+
 	// Register all namespaced routes
 	s.registerNamespacedRoutes(map[string]func(http.ResponseWriter, *http.Request){
-		"workspaceconnections":    s.HandleConnectionCreate,
-		"connectionaccessreviews": s.handleConnectionAccessReview,
-		"bearertokenreviews":      s.handleBearerTokenReview,
+		"workspaceconnections":      s.HandleConnectionCreate,
+		"connectionaccessreviews":   s.handleConnectionAccessReview,
+		"bearertokenreviews":        s.handleBearerTokenReview,
+		"workspacetemplatecatalogs": s.handleTemplateCatalog,
+		"workspacedebuglogs":        s.handleWorkspaceDebugLog,
 	})
 }
 
@@ -373,6 +386,26 @@ func SetupExtensionAPIServerWithManager(mgr ctrl.Manager, config *ExtensionConfi
 	// Create and configure extension server
 	server := createExtensionServer(genericServer, config, &logger, mgr.GetClient(), sarClient, signerFactory, tokenValidator, pluginClients)
 
+	// Drop cached template catalogs as soon as a template changes
+	invalidation, err := registerTemplateCatalogInvalidation(mgr, server.catalogCache, logger.WithName("template-catalog"))
+	if err != nil {
+		return err
+	}
+
+	// Report whether the server listens and the catalog cache follows template changes
+	if err := health.AddReadyzChecks(mgr,
+		health.Check{
+			Name:    ReadyzCheckServer,
+			Checker: health.ListeningChecker(net.JoinHostPort("localhost", strconv.Itoa(config.ServerPort))),
+		},
+		health.Check{
+			Name:    ReadyzCheckTemplateCatalog,
+			Checker: health.SyncedChecker("template catalog invalidation", invalidation.HasSynced),
+		},
+	); err != nil {
+		return err
+	}
+
 	// Add server to manager
 	return addServerToManager(mgr, server)
 }
@@ -263,6 +263,10 @@ var _ = Describe("Server", func() {
 			Expect(server.routes).To(HaveKey(config.ApiPath))
 		})
 
+		It("Should register the /version route under the api root", func() {
+			Expect(server.routes).To(HaveKey(config.ApiPath + "/version"))
+		})
+
 		It("Should register /workspaceconnections, /connectionaccessreviews and /bearertokenreviews routes as namespaced", func() {
 			namespacedPathPrefix := config.ApiPath + "/namespaces/*/"
 			Expect(server.routes).To(HaveKey(namespacedPathPrefix + "workspaceconnections"))
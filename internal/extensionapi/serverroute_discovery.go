@@ -39,6 +39,18 @@ func (s *ExtensionServer) handleDiscovery(w http.ResponseWriter, _ *http.Request
 			"namespaced": true,
 			"kind": "BearerTokenReview",
 			"verbs": ["create"]
+		}, {
+			"name": "workspacetemplatecatalogs",
+			"singularName": "workspacetemplatecatalog",
+			"namespaced": true,
+			"kind": "WorkspaceTemplateCatalog",
+			"verbs": ["get", "watch"]
+		}, {
+			"name": "workspacedebuglogs",
+			"singularName": "workspacedebuglog",
+			"namespaced": true,
+			"kind": "WorkspaceDebugLog",
+			"verbs": ["get"]
 		}]
 	}`, connectionv1alpha1.WorkspaceConnectionAPIVersion, connectionv1alpha1.WorkspaceConnectionKind)
 
@@ -0,0 +1,265 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// TemplateCatalogEntry describes a template that workspaces of the namespace can be created from
+type TemplateCatalogEntry struct {
+	Name         string `json:"name"`
+	Namespace    string `json:"namespace"`
+	DisplayName  string `json:"displayName"`
+	Description  string `json:"description,omitempty"`
+	DefaultImage string `json:"defaultImage"`
+	AppType      string `json:"appType,omitempty"`
+	Deprecated   bool   `json:"deprecated,omitempty"`
+
+	// StarterConfigs are the example workspaces of the template, which the template is known to accept
+	StarterConfigs []TemplateStarterConfig `json:"starterConfigs,omitempty"`
+
+	// DefaultPolicies are the lifecycle policies the template gives the workspaces that do not set their own
+	DefaultPolicies *workspacev1alpha1.EffectivePolicies `json:"defaultPolicies,omitempty"`
+}
+
+// TemplateStarterConfig is an example workspace a workspace of the template can start from
+type TemplateStarterConfig struct {
+	Name        string                          `json:"name"`
+	Description string                          `json:"description,omitempty"`
+	Spec        workspacev1alpha1.WorkspaceSpec `json:"spec"`
+}
+
+// TemplateCatalog lists the templates available to workspaces of a namespace
+type TemplateCatalog struct {
+	Namespace string                 `json:"namespace"`
+	Templates []TemplateCatalogEntry `json:"templates"`
+}
+
+// errTemplateCatalogForbidden reports a caller not allowed to create workspaces in the namespace
+var errTemplateCatalogForbidden = errors.New("not allowed to create workspaces in this namespace")
+
+// handleTemplateCatalog serves the templates available to workspaces of the namespace. With
+// ?watch=true the catalog is streamed as server-sent events, sent again whenever it changes.
+func (s *ExtensionServer) handleTemplateCatalog(w http.ResponseWriter, r *http.Request) {
+	logger := GetLoggerFromContext(r.Context())
+
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "WorkspaceTemplateCatalog must use GET method")
+		return
+	}
+
+	namespace, err := GetNamespaceFromPath(r.URL.Path)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "WorkspaceTemplateCatalog must be namespaced")
+		return
+	}
+
+	username := GetUser(r)
+	if username == "" {
+		WriteError(w, http.StatusUnauthorized, "user information not found in request")
+		return
+	}
+	groups := GetGroups(r)
+
+	catalog, err := s.templateCatalogFor(r.Context(), namespace, username, groups)
+	if errors.Is(err, errTemplateCatalogForbidden) {
+		WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error(err, "Failed to build template catalog", "namespace", namespace)
+		WriteError(w, http.StatusInternalServerError, "failed to build template catalog")
+		return
+	}
+
+	if r.URL.Query().Get("watch") == "true" {
+		s.watchTemplateCatalog(w, r, namespace, username, groups, catalog)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(catalog); err != nil {
+		logger.Error(err, "Failed to encode template catalog")
+	}
+}
+
+// watchTemplateCatalog streams the catalog as server-sent events: once at the start, then after each
+// template change that alters it. The permission of the caller is checked again every TTL, and the
+// stream ends once it is revoked.
+func (s *ExtensionServer) watchTemplateCatalog(
+	w http.ResponseWriter,
+	r *http.Request,
+	namespace string,
+	username string,
+	groups []string,
+	catalog *TemplateCatalog) {
+	logger := GetLoggerFromContext(r.Context())
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		WriteError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	changes, unsubscribe := s.catalogCache.subscribe()
+	defer unsubscribe()
+	recheck := time.NewTicker(s.catalogCache.ttl)
+	defer recheck.Stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	var last []byte
+	for {
+		data, err := json.Marshal(catalog)
+		if err != nil {
+			logger.Error(err, "Failed to encode template catalog")
+			return
+		}
+		if !bytes.Equal(data, last) {
+			if _, err := fmt.Fprintf(w, "event: catalog\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+			last = data
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changes:
+		case <-recheck.C:
+		}
+
+		catalog, err = s.templateCatalogFor(r.Context(), namespace, username, groups)
+		if err != nil {
+			if r.Context().Err() == nil {
+				logger.Info("Ending template catalog watch", "namespace", namespace, "reason", err.Error())
+				_, _ = fmt.Fprintf(w, "event: error\ndata: %q\n\n", err.Error())
+				flusher.Flush()
+			}
+			return
+		}
+	}
+}
+
+// templateCatalogFor returns the catalog of the namespace for the caller, from the cache when possible
+func (s *ExtensionServer) templateCatalogFor(
+	ctx context.Context,
+	namespace string,
+	username string,
+	groups []string) (*TemplateCatalog, error) {
+	key := newCatalogKey(namespace, username, groups)
+	if catalog, ok := s.catalogCache.get(key); ok {
+		return catalog, nil
+	}
+	generation := s.catalogCache.generation(namespace)
+
+	allowed, err := s.canCreateWorkspaces(ctx, namespace, username, groups)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		// Denials are not cached, so that a new permission takes effect at once
+		return nil, errTemplateCatalogForbidden
+	}
+
+	catalog, err := s.buildTemplateCatalog(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	s.catalogCache.put(key, generation, catalog)
+	return catalog, nil
+}
+
+// canCreateWorkspaces checks with a SubjectAccessReview that the caller may create workspaces in the namespace
+func (s *ExtensionServer) canCreateWorkspaces(ctx context.Context, namespace, username string, groups []string) (bool, error) {
+	review, err := s.sarClient.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "create",
+				Group:     workspacev1alpha1.GroupVersion.Group,
+				Resource:  "workspaces",
+			},
+			User:   username,
+			Groups: groups,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
+
+// buildTemplateCatalog lists the templates a workspace of the namespace can reference without a
+// namespace, in the order template resolution tries them: a template of the namespace hides a
+// template of the same name in the default template namespace
+func (s *ExtensionServer) buildTemplateCatalog(ctx context.Context, namespace string) (*TemplateCatalog, error) {
+	namespaces := []string{namespace}
+	if defaultNamespace := s.catalogCache.defaultTemplateNamespace; defaultNamespace != "" && defaultNamespace != namespace {
+		namespaces = append(namespaces, defaultNamespace)
+	}
+
+	catalog := &TemplateCatalog{Namespace: namespace, Templates: []TemplateCatalogEntry{}}
+	seen := map[string]bool{}
+	for _, templateNamespace := range namespaces {
+		templates := &workspacev1alpha1.WorkspaceTemplateList{}
+		if err := s.k8sClient.List(ctx, templates, client.InNamespace(templateNamespace)); err != nil {
+			return nil, fmt.Errorf("failed to list templates in namespace %s: %w", templateNamespace, err)
+		}
+		sort.Slice(templates.Items, func(i, j int) bool { return templates.Items[i].Name < templates.Items[j].Name })
+
+		for _, template := range templates.Items {
+			if seen[template.Name] || !template.DeletionTimestamp.IsZero() {
+				continue
+			}
+			seen[template.Name] = true
+			catalog.Templates = append(catalog.Templates, TemplateCatalogEntry{
+				Name:            template.Name,
+				Namespace:       template.Namespace,
+				DisplayName:     template.Spec.DisplayName,
+				Description:     template.Spec.Description,
+				DefaultImage:    template.Spec.DefaultImage,
+				AppType:         template.Spec.AppType,
+				Deprecated:      template.Spec.Deprecated,
+				StarterConfigs:  starterConfigs(&template),
+				DefaultPolicies: workspaceutil.TemplatePolicies(&template),
+			})
+		}
+	}
+	return catalog, nil
+}
+
+// starterConfigs returns the example workspaces of the template
+func starterConfigs(template *workspacev1alpha1.WorkspaceTemplate) []TemplateStarterConfig {
+	var configs []TemplateStarterConfig
+	for _, example := range template.Spec.ExampleWorkspaces {
+		configs = append(configs, TemplateStarterConfig{
+			Name:        example.Name,
+			Description: example.Description,
+			Spec:        *example.Spec.DeepCopy(),
+		})
+	}
+	return configs
+}
@@ -0,0 +1,345 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const catalogPath = DefaultApiPath + "/namespaces/team-a/workspacetemplatecatalogs"
+
+func newCatalogTemplate(name, namespace, displayName string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  displayName,
+			DefaultImage: "jupyter/base-notebook:latest",
+		},
+	}
+}
+
+func newCatalogRequest(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	return req.WithContext(request.WithUser(req.Context(), &user.DefaultInfo{
+		Name:   "alice",
+		Groups: []string{"team-a", "system:authenticated"},
+	}))
+}
+
+func decodeCatalog(body []byte) TemplateCatalog {
+	var catalog TemplateCatalog
+	ExpectWithOffset(1, json.Unmarshal(body, &catalog)).To(Succeed())
+	return catalog
+}
+
+var _ = Describe("ServerRouteTemplateCatalog", func() {
+	var (
+		server        *ExtensionServer
+		k8sClient     client.Client
+		mockSarClient *MockSarClient
+		now           time.Time
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newCatalogTemplate("python", "team-a", "Team Python"),
+			newCatalogTemplate("python", "shared", "Shared Python"),
+			newCatalogTemplate("r", "shared", "Shared R"),
+			newCatalogTemplate("other", "team-b", "Other team"),
+		).Build()
+
+		mockSarClient = NewMockSarClient()
+		logger := logr.Discard()
+		now = time.Now()
+		server = &ExtensionServer{
+			k8sClient:    k8sClient,
+			sarClient:    mockSarClient,
+			logger:       &logger,
+			catalogCache: newTemplateCatalogCache(time.Minute, "shared"),
+		}
+		server.catalogCache.now = func() time.Time { return now }
+	})
+
+	It("Should list the templates of the namespace before those of the default namespace", func() {
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		catalog := decodeCatalog(recorder.Body.Bytes())
+		Expect(catalog.Namespace).To(Equal("team-a"))
+		Expect(catalog.Templates).To(HaveLen(2))
+		Expect(catalog.Templates[0].Namespace).To(Equal("team-a"))
+		Expect(catalog.Templates[0].DisplayName).To(Equal("Team Python"), "the namespace template hides the shared one")
+		Expect(catalog.Templates[1].Name).To(Equal("r"))
+
+		review := mockSarClient.LastCreateParams
+		Expect(review.Spec.User).To(Equal("alice"))
+		Expect(review.Spec.ResourceAttributes.Resource).To(Equal("workspaces"))
+		Expect(review.Spec.ResourceAttributes.Verb).To(Equal("create"))
+		Expect(review.Spec.ResourceAttributes.Namespace).To(Equal("team-a"))
+	})
+
+	It("Should reject callers who cannot create workspaces in the namespace", func() {
+		mockSarClient.SetupDenied("no access")
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should reject other methods", func() {
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodPost, catalogPath))
+
+		Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("Should offer the example workspaces of templates as starter configs", func() {
+		template := &workspacev1alpha1.WorkspaceTemplate{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "r", Namespace: "shared"}, template)).To(Succeed())
+		template.Spec.ExampleWorkspaces = []workspacev1alpha1.TemplateExampleWorkspace{{
+			Name:        "tidyverse",
+			Description: "R with the tidyverse",
+			Spec:        workspacev1alpha1.WorkspaceSpec{DisplayName: "Tidyverse", Image: "rocker/tidyverse:4"},
+		}}
+		Expect(k8sClient.Update(context.Background(), template)).To(Succeed())
+
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+
+		catalog := decodeCatalog(recorder.Body.Bytes())
+		Expect(catalog.Templates[0].StarterConfigs).To(BeEmpty())
+		Expect(catalog.Templates[1].StarterConfigs).To(HaveLen(1))
+		Expect(catalog.Templates[1].StarterConfigs[0].Name).To(Equal("tidyverse"))
+		Expect(catalog.Templates[1].StarterConfigs[0].Description).To(Equal("R with the tidyverse"))
+		Expect(catalog.Templates[1].StarterConfigs[0].Spec.Image).To(Equal("rocker/tidyverse:4"))
+	})
+
+	It("Should describe the default lifecycle policies of templates", func() {
+		template := &workspacev1alpha1.WorkspaceTemplate{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "r", Namespace: "shared"}, template)).To(Succeed())
+		template.Spec.DefaultIdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 45}
+		template.Spec.DefaultSchedule = &workspacev1alpha1.ScheduleSpec{StopCron: "0 20 * * *"}
+		deleteAfter := int64(14 * 86400)
+		template.Spec.Retention = &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: &deleteAfter}
+		template.Spec.MaxLifetime = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+		Expect(k8sClient.Update(context.Background(), template)).To(Succeed())
+
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+
+		catalog := decodeCatalog(recorder.Body.Bytes())
+		Expect(catalog.Templates[0].DefaultPolicies).To(BeNil())
+		policies := catalog.Templates[1].DefaultPolicies
+		Expect(policies).NotTo(BeNil())
+		Expect(policies.IdleShutdown.IdleTimeoutInMinutes).To(Equal(45))
+		Expect(policies.IdleShutdown.Source).To(Equal(workspacev1alpha1.PolicySourceTemplate))
+		Expect(policies.Schedule.StopCron).To(Equal("0 20 * * *"))
+		Expect(policies.DeleteAfterStopped.After.Duration).To(Equal(14 * 24 * time.Hour))
+		Expect(policies.MaxLifetime.MaxLifetime.Duration).To(Equal(30 * 24 * time.Hour))
+		Expect(policies.MaxLifetime.Action).To(Equal(workspacev1alpha1.MaxLifetimeActionStop))
+		Expect(policies.MaxLifetime.ExpiresAt).To(BeNil(), "templates do not expire")
+	})
+
+	It("Should serve repeated requests from the cache", func() {
+		for range 3 {
+			recorder := httptest.NewRecorder()
+			server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		}
+		Expect(mockSarClient.CreateCallCount).To(Equal(1))
+	})
+
+	It("Should report a template becoming deprecated as soon as the template changes", func() {
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+		Expect(decodeCatalog(recorder.Body.Bytes()).Templates[1].Deprecated).To(BeFalse())
+
+		template := &workspacev1alpha1.WorkspaceTemplate{}
+		Expect(k8sClient.Get(context.Background(), client.ObjectKey{Name: "r", Namespace: "shared"}, template)).To(Succeed())
+		template.Spec.Deprecated = true
+		Expect(k8sClient.Update(context.Background(), template)).To(Succeed())
+		server.catalogCache.invalidate("shared")
+
+		recorder = httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+		Expect(decodeCatalog(recorder.Body.Bytes()).Templates[1].Deprecated).To(BeTrue())
+	})
+
+	It("Should check the permission again once the TTL expires", func() {
+		recorder := httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+
+		mockSarClient.SetupDenied("revoked")
+		recorder = httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+		Expect(recorder.Code).To(Equal(http.StatusOK), "still cached")
+
+		now = now.Add(time.Minute)
+		recorder = httptest.NewRecorder()
+		server.handleTemplateCatalog(recorder, newCatalogRequest(http.MethodGet, catalogPath))
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("Should push the catalog again to watchers when a template changes", func() {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := request.WithUser(r.Context(), &user.DefaultInfo{Name: "alice"})
+			server.handleTemplateCatalog(w, r.WithContext(ctx))
+		}))
+		defer httpServer.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+catalogPath+"?watch=true", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+		scanner := bufio.NewScanner(resp.Body)
+		nextCatalog := func() TemplateCatalog {
+			for scanner.Scan() {
+				if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+					return decodeCatalog([]byte(data))
+				}
+			}
+			Fail(fmt.Sprintf("stream ended: %v", scanner.Err()))
+			return TemplateCatalog{}
+		}
+
+		Expect(nextCatalog().Templates[0].Deprecated).To(BeFalse())
+
+		template := &workspacev1alpha1.WorkspaceTemplate{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: "python", Namespace: "team-a"}, template)).To(Succeed())
+		template.Spec.Deprecated = true
+		Expect(k8sClient.Update(ctx, template)).To(Succeed())
+		server.catalogCache.invalidate("team-a")
+
+		Expect(nextCatalog().Templates[0].Deprecated).To(BeTrue())
+	})
+})
+
+var _ = Describe("TemplateCatalogCache", func() {
+	It("Should share entries between callers with the same groups in any order", func() {
+		Expect(newCatalogKey("ns", "alice", []string{"a", "b"})).To(Equal(newCatalogKey("ns", "alice", []string{"b", "a"})))
+		Expect(newCatalogKey("ns", "alice", []string{"a"})).NotTo(Equal(newCatalogKey("ns", "bob", []string{"a"})))
+	})
+
+	It("Should drop every catalog when a template of the default namespace changes", func() {
+		cache := newTemplateCatalogCache(time.Minute, "shared")
+		teamA := newCatalogKey("team-a", "alice", nil)
+		teamB := newCatalogKey("team-b", "alice", nil)
+		cache.put(teamA, cache.generation("team-a"), &TemplateCatalog{})
+		cache.put(teamB, cache.generation("team-b"), &TemplateCatalog{})
+
+		cache.invalidate("team-a")
+		_, okA := cache.get(teamA)
+		_, okB := cache.get(teamB)
+		Expect(okA).To(BeFalse())
+		Expect(okB).To(BeTrue())
+
+		cache.invalidate("shared")
+		_, okB = cache.get(teamB)
+		Expect(okB).To(BeFalse())
+	})
+
+	It("Should not cache a catalog computed before its namespace was invalidated", func() {
+		cache := newTemplateCatalogCache(time.Minute, "shared")
+		teamA := newCatalogKey("team-a", "alice", nil)
+
+		generation := cache.generation("team-a")
+		cache.invalidate("team-a")
+		cache.put(teamA, generation, &TemplateCatalog{})
+		_, ok := cache.get(teamA)
+		Expect(ok).To(BeFalse())
+
+		generation = cache.generation("team-a")
+		cache.invalidate("shared")
+		cache.put(teamA, generation, &TemplateCatalog{})
+		_, ok = cache.get(teamA)
+		Expect(ok).To(BeFalse())
+
+		cache.put(teamA, cache.generation("team-a"), &TemplateCatalog{})
+		_, ok = cache.get(teamA)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Should sweep expired catalogs once full and stop caching while none expired", func() {
+		now := time.Now()
+		cache := newTemplateCatalogCache(time.Minute, "")
+		cache.now = func() time.Time { return now }
+		for i := 0; i < maxCatalogCacheEntries; i++ {
+			cache.put(newCatalogKey("team-a", fmt.Sprintf("user-%d", i), nil), 0, &TemplateCatalog{})
+		}
+
+		extra := newCatalogKey("team-a", "extra", nil)
+		cache.put(extra, 0, &TemplateCatalog{})
+		_, ok := cache.get(extra)
+		Expect(ok).To(BeFalse())
+
+		now = now.Add(time.Minute)
+		cache.put(extra, 0, &TemplateCatalog{})
+		_, ok = cache.get(extra)
+		Expect(ok).To(BeTrue())
+		Expect(cache.entries).To(HaveLen(1))
+	})
+
+	It("Should only count spec, label and annotation changes of a template as catalog changes", func() {
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team-a", Generation: 1},
+		}
+
+		statusOnly := template.DeepCopy()
+		statusOnly.Status.WorkspaceCount = 3
+		Expect(templateCatalogChanged(template, statusOnly)).To(BeFalse())
+
+		specChanged := template.DeepCopy()
+		specChanged.Generation = 2
+		Expect(templateCatalogChanged(template, specChanged)).To(BeTrue())
+
+		labelled := template.DeepCopy()
+		labelled.Labels = map[string]string{"team": "a"}
+		Expect(templateCatalogChanged(template, labelled)).To(BeTrue())
+
+		annotated := template.DeepCopy()
+		annotated.Annotations = map[string]string{"team.example.com/owner": "alice"}
+		Expect(templateCatalogChanged(template, annotated)).To(BeTrue())
+	})
+
+	It("Should notify subscribers without blocking", func() {
+		cache := newTemplateCatalogCache(time.Minute, "")
+		changes, unsubscribe := cache.subscribe()
+		defer unsubscribe()
+
+		cache.invalidate("team-a")
+		cache.invalidate("team-b")
+		Expect(<-changes).To(Equal("team-a"))
+		Consistently(changes).ShouldNot(Receive())
+	})
+})
@@ -0,0 +1,21 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"net/http"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+)
+
+// handleVersion responds with the build and feature information of the operator
+func (s *ExtensionServer) handleVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "version only supports GET")
+		return
+	}
+	buildinfo.Handler().ServeHTTP(w, r)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/buildinfo"
+)
+
+var _ = Describe("ServerRouteVersion", func() {
+	Context("handleVersion", func() {
+		var (
+			server   *ExtensionServer
+			recorder *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			server = &ExtensionServer{}
+			recorder = httptest.NewRecorder()
+		})
+
+		It("Should write the build info as JSON", func() {
+			server.handleVersion(recorder, httptest.NewRequest("GET", DefaultApiPath+"/version", nil))
+
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			var info buildinfo.Info
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &info)).To(Succeed())
+			Expect(info.Version).To(Equal(buildinfo.Version))
+		})
+
+		It("Should reject other methods", func() {
+			server.handleVersion(recorder, httptest.NewRequest("POST", DefaultApiPath+"/version", nil))
+
+			Expect(recorder.Code).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
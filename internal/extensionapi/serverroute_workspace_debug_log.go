@@ -0,0 +1,125 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	connectionv1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/connection/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/debuglog"
+)
+
+// DefaultDebugLogWindow is how far back the debug log of a workspace goes when the caller sets no ?since
+const DefaultDebugLogWindow = 10 * time.Minute
+
+// WorkspaceDebugLog lists the last log records the controller wrote about a workspace
+type WorkspaceDebugLog struct {
+	Namespace string `json:"namespace"`
+	Workspace string `json:"workspace"`
+	// Replica is the operator pod the records were read from; only the leader reconciles workspaces
+	Replica string            `json:"replica,omitempty"`
+	Records []debuglog.Record `json:"records"`
+}
+
+// handleWorkspaceDebugLog serves the records the controller kept about the ?workspace= of the namespace,
+// the last 10 minutes of them or those of the ?since= duration. The records are kept in memory by the
+// operator replica that reconciled the workspace.
+func (s *ExtensionServer) handleWorkspaceDebugLog(w http.ResponseWriter, r *http.Request) {
+	logger := GetLoggerFromContext(r.Context())
+
+	if r.Method != http.MethodGet {
+		WriteError(w, http.StatusMethodNotAllowed, "WorkspaceDebugLog must use GET method")
+		return
+	}
+
+	namespace, err := GetNamespaceFromPath(r.URL.Path)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, "WorkspaceDebugLog must be namespaced")
+		return
+	}
+	workspaceName := r.URL.Query().Get("workspace")
+	if workspaceName == "" {
+		WriteError(w, http.StatusBadRequest, "workspace query parameter is required")
+		return
+	}
+	window := DefaultDebugLogWindow
+	if since := r.URL.Query().Get("since"); since != "" {
+		window, err = time.ParseDuration(since)
+		if err != nil || window <= 0 {
+			WriteError(w, http.StatusBadRequest, "since must be a positive duration, e.g. 10m")
+			return
+		}
+	}
+
+	username := GetUser(r)
+	if username == "" {
+		WriteError(w, http.StatusUnauthorized, "user information not found in request")
+		return
+	}
+
+	allowed, err := s.canReadDebugLog(r.Context(), namespace, workspaceName, username, GetGroups(r))
+	if err != nil {
+		logger.Error(err, "Failed to check debug log permission", "namespace", namespace, "workspace", workspaceName)
+		WriteError(w, http.StatusInternalServerError, "failed to check permission")
+		return
+	}
+	if !allowed {
+		WriteError(w, http.StatusForbidden, "not allowed to get the debug log of this workspace")
+		return
+	}
+
+	key := types.NamespacedName{Namespace: namespace, Name: workspaceName}
+	debugLog := WorkspaceDebugLog{
+		Namespace: namespace,
+		Workspace: workspaceName,
+		Replica:   os.Getenv("HOSTNAME"),
+		Records:   s.debugLogs.Records(key, time.Now().Add(-window)),
+	}
+	if debugLog.Records == nil {
+		debugLog.Records = []debuglog.Record{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(debugLog); err != nil {
+		logger.Error(err, "Failed to encode workspace debug log")
+	}
+}
+
+// canReadDebugLog checks with a SubjectAccessReview that the caller may get the debug log of the workspace
+func (s *ExtensionServer) canReadDebugLog(
+	ctx context.Context,
+	namespace string,
+	workspaceName string,
+	username string,
+	groups []string) (bool, error) {
+	review, err := s.sarClient.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     connectionv1alpha1.SchemeGroupVersion.Group,
+				Resource:  "workspacedebuglogs",
+				Name:      workspaceName,
+			},
+			User:   username,
+			Groups: groups,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to create SubjectAccessReview: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/debuglog"
+)
+
+const debugLogPath = DefaultApiPath + "/namespaces/team-a/workspacedebuglogs"
+
+var _ = Describe("ServerRouteWorkspaceDebugLog", func() {
+	var (
+		server        *ExtensionServer
+		mockSarClient *MockSarClient
+		buffer        *debuglog.Buffer
+	)
+
+	BeforeEach(func() {
+		mockSarClient = NewMockSarClient()
+		buffer = debuglog.NewBuffer(10, 10)
+		logger := logr.Discard()
+		server = &ExtensionServer{
+			sarClient: mockSarClient,
+			logger:    &logger,
+			debugLogs: buffer,
+		}
+
+		key := types.NamespacedName{Namespace: "team-a", Name: "ws"}
+		buffer.Add(key, debuglog.Record{Time: time.Now().Add(-time.Hour), Message: "Old decision"})
+		buffer.Add(key, debuglog.Record{Time: time.Now(), Message: "Recent decision",
+			Values: map[string]string{"decision": "ReconcileTowards"}})
+	})
+
+	It("Should return the records of the last 10 minutes of the workspace by default", func() {
+		recorder := httptest.NewRecorder()
+		server.handleWorkspaceDebugLog(recorder, newCatalogRequest(http.MethodGet, debugLogPath+"?workspace=ws"))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		var debugLog WorkspaceDebugLog
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &debugLog)).To(Succeed())
+		Expect(debugLog.Namespace).To(Equal("team-a"))
+		Expect(debugLog.Workspace).To(Equal("ws"))
+		Expect(debugLog.Records).To(HaveLen(1))
+		Expect(debugLog.Records[0].Values["decision"]).To(Equal("ReconcileTowards"))
+
+		review := mockSarClient.LastCreateParams
+		Expect(review.Spec.User).To(Equal("alice"))
+		Expect(review.Spec.ResourceAttributes.Resource).To(Equal("workspacedebuglogs"))
+		Expect(review.Spec.ResourceAttributes.Verb).To(Equal("get"))
+		Expect(review.Spec.ResourceAttributes.Name).To(Equal("ws"))
+	})
+
+	It("Should return older records with since", func() {
+		recorder := httptest.NewRecorder()
+		server.handleWorkspaceDebugLog(recorder, newCatalogRequest(http.MethodGet, debugLogPath+"?workspace=ws&since=2h"))
+
+		var debugLog WorkspaceDebugLog
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &debugLog)).To(Succeed())
+		Expect(debugLog.Records).To(HaveLen(2))
+	})
+
+	It("Should return an empty list for workspaces without records", func() {
+		recorder := httptest.NewRecorder()
+		server.handleWorkspaceDebugLog(recorder, newCatalogRequest(http.MethodGet, debugLogPath+"?workspace=other"))
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Body.String()).To(ContainSubstring(`"records":[]`))
+	})
+
+	It("Should reject callers not allowed to get the debug log", func() {
+		mockSarClient.SetupDenied("no access")
+		recorder := httptest.NewRecorder()
+		server.handleWorkspaceDebugLog(recorder, newCatalogRequest(http.MethodGet, debugLogPath+"?workspace=ws"))
+
+		Expect(recorder.Code).To(Equal(http.StatusForbidden))
+	})
+
+	DescribeTable("Should reject invalid requests",
+		func(method, target string, status int) {
+			recorder := httptest.NewRecorder()
+			server.handleWorkspaceDebugLog(recorder, newCatalogRequest(method, target))
+			Expect(recorder.Code).To(Equal(status))
+		},
+		Entry("other methods", http.MethodPost, debugLogPath+"?workspace=ws", http.StatusMethodNotAllowed),
+		Entry("no workspace", http.MethodGet, debugLogPath, http.StatusBadRequest),
+		Entry("an invalid since", http.MethodGet, debugLogPath+"?workspace=ws&since=yesterday", http.StatusBadRequest),
+	)
+})
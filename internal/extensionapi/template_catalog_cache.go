@@ -0,0 +1,230 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package extensionapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// maxCatalogCacheEntries bounds the number of cached catalogs, one per namespace and identity
+const maxCatalogCacheEntries = 10000
+
+// catalogKey identifies a cached catalog: catalogs depend on the namespace and, through the
+// permission check, on the identity of the caller
+type catalogKey struct {
+	namespace string
+	identity  string
+}
+
+// newCatalogKey hashes the user and groups so that callers with the same identity share an entry
+func newCatalogKey(namespace, username string, groups []string) catalogKey {
+	sorted := append([]string(nil), groups...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(username + "\n" + strings.Join(sorted, "\n")))
+	return catalogKey{namespace: namespace, identity: hex.EncodeToString(sum[:])}
+}
+
+// cachedCatalog is a computed catalog with the time it stops being served
+type cachedCatalog struct {
+	catalog   *TemplateCatalog
+	expiresAt time.Time
+}
+
+// templateCatalogCache caches template catalogs for a bounded TTL, so that polling UIs do not
+// trigger a permission check and a template list on every request. Template changes invalidate
+// the catalogs of their namespace at once and are broadcast to watchers. Permission changes are
+// not observed: they take effect when the entry expires, within the TTL.
+//
+// Each namespace has a generation bumped by every invalidation, so that a catalog computed from
+// templates read before a change is not cached after the change invalidated the namespace.
+type templateCatalogCache struct {
+	ttl                      time.Duration
+	defaultTemplateNamespace string
+	now                      func() time.Time
+
+	mu          sync.Mutex
+	entries     map[catalogKey]cachedCatalog
+	generations map[string]uint64
+	subscribers map[chan string]struct{}
+}
+
+// newTemplateCatalogCache creates an empty cache
+func newTemplateCatalogCache(ttl time.Duration, defaultTemplateNamespace string) *templateCatalogCache {
+	return &templateCatalogCache{
+		ttl:                      ttl,
+		defaultTemplateNamespace: defaultTemplateNamespace,
+		now:                      time.Now,
+		entries:                  map[catalogKey]cachedCatalog{},
+		generations:              map[string]uint64{},
+		subscribers:              map[chan string]struct{}{},
+	}
+}
+
+// get returns the cached catalog for key, unless it expired
+func (c *templateCatalogCache) get(key catalogKey) (*TemplateCatalog, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.catalog, true
+}
+
+// generation returns the generation of the catalogs of namespace, to pass to put once the catalog
+// is computed
+func (c *templateCatalogCache) generation(namespace string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generationLocked(namespace)
+}
+
+// generationLocked sums the generations of the namespace and of the default namespace, whose
+// templates appear in every catalog. Both only grow, so the sum changes with either.
+func (c *templateCatalogCache) generationLocked(namespace string) uint64 {
+	generation := c.generations[namespace]
+	if c.defaultTemplateNamespace != "" && namespace != c.defaultTemplateNamespace {
+		generation += c.generations[c.defaultTemplateNamespace]
+	}
+	return generation
+}
+
+// put caches the catalog for key for the TTL, unless its namespace was invalidated since the
+// generation was read. When the cache is full, expired entries are swept first; the catalog is not
+// cached if none expired.
+func (c *templateCatalogCache) put(key catalogKey, generation uint64, catalog *TemplateCatalog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generationLocked(key.namespace) != generation {
+		return
+	}
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxCatalogCacheEntries {
+		for cachedKey, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, cachedKey)
+			}
+		}
+		if len(c.entries) >= maxCatalogCacheEntries {
+			return
+		}
+	}
+	c.entries[key] = cachedCatalog{catalog: catalog, expiresAt: now.Add(c.ttl)}
+}
+
+// invalidate drops the catalogs affected by a change of a template in namespace and notifies
+// the watchers. Templates of the default namespace appear in every catalog.
+func (c *templateCatalogCache) invalidate(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[namespace]++
+	shared := c.defaultTemplateNamespace != "" && namespace == c.defaultTemplateNamespace
+	for key := range c.entries {
+		if shared || key.namespace == namespace {
+			delete(c.entries, key)
+		}
+	}
+
+	for subscriber := range c.subscribers {
+		// Never block the informer on a slow watcher: one pending notification is enough
+		// for it to recompute its catalog
+		select {
+		case subscriber <- namespace:
+		default:
+		}
+	}
+}
+
+// subscribe returns a channel receiving the namespace of each template change, and a function
+// ending the subscription
+func (c *templateCatalogCache) subscribe() (<-chan string, func()) {
+	subscriber := make(chan string, 1)
+
+	c.mu.Lock()
+	c.subscribers[subscriber] = struct{}{}
+	c.mu.Unlock()
+
+	return subscriber, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.subscribers, subscriber)
+	}
+}
+
+// registerTemplateCatalogInvalidation invalidates the cached catalogs of a namespace when a template
+// of that namespace is added, deleted or changed; status-only updates leave the catalogs cached. The
+// returned registration has synced once the invalidation has seen every template.
+func registerTemplateCatalogInvalidation(
+	mgr ctrl.Manager,
+	catalogCache *templateCatalogCache,
+	logger logr.Logger) (toolscache.ResourceEventHandlerRegistration, error) {
+	informer, err := mgr.GetCache().GetInformer(context.Background(), &workspacev1alpha1.WorkspaceTemplate{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template informer: %w", err)
+	}
+
+	invalidate := func(obj interface{}) {
+		if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		template, ok := obj.(client.Object)
+		if !ok {
+			return
+		}
+		logger.V(1).Info("Template changed, invalidating catalogs", "template", template.GetName(), "namespace", template.GetNamespace())
+		catalogCache.invalidate(template.GetNamespace())
+	}
+
+	registration, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+		AddFunc: invalidate,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if templateCatalogChanged(oldObj, newObj) {
+				invalidate(newObj)
+			}
+		},
+		DeleteFunc: invalidate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add event handler to template informer: %w", err)
+	}
+	return registration, nil
+}
+
+// templateCatalogChanged returns true if a template update may change the catalogs: the spec, tracked
+// by the generation, or the labels and annotations changed. Status writes, such as workspace counts,
+// do not change the catalogs.
+func templateCatalogChanged(oldObj, newObj interface{}) bool {
+	oldTemplate, oldOk := oldObj.(client.Object)
+	newTemplate, newOk := newObj.(client.Object)
+	if !oldOk || !newOk {
+		return true
+	}
+	return oldTemplate.GetGeneration() != newTemplate.GetGeneration() ||
+		!maps.Equal(oldTemplate.GetLabels(), newTemplate.GetLabels()) ||
+		!maps.Equal(oldTemplate.GetAnnotations(), newTemplate.GetAnnotations())
+}
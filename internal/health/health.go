@@ -0,0 +1,227 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package health registers named readiness checks for the subsystems of the operator, so that
+// `kubectl get --raw /readyz?verbose` names the subsystem that is not working, and publishes
+// the outcome of each check as a metric. Subsystems disabled by configuration are registered
+// as skipped: they pass the readiness check and report the skipped state.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Check states reported by the readiness check metric
+const (
+	StateReady   = "ready"
+	StateFailing = "failing"
+	StateSkipped = "skipped"
+)
+
+// dialTimeout bounds how long a listening check waits for a connection
+const dialTimeout = 2 * time.Second
+
+// checkStates are the states of each check, exactly one of which is set to 1
+var checkStates = []string{StateReady, StateFailing, StateSkipped}
+
+// readinessCheckGauge reports the state of each readiness check as of its last evaluation
+var readinessCheckGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "jupyter_k8s_readiness_check",
+	Help: "State of each named readiness check of the operator as of its last evaluation, 1 for the current state",
+}, []string{"check", "state"})
+
+func init() {
+	metrics.Registry.MustRegister(readinessCheckGauge)
+}
+
+// ReadyzCheckAdder is the part of the manager readiness checks are registered with
+type ReadyzCheckAdder interface {
+	AddReadyzCheck(name string, check healthz.Checker) error
+}
+
+// Check is a named readiness check of a subsystem
+type Check struct {
+	// Name is the name of the check on /readyz
+	Name string
+
+	// Checker returns an error when the subsystem is not working
+	Checker healthz.Checker
+}
+
+// AddReadyzChecks registers the checks with the manager, publishing their state on each evaluation
+func AddReadyzChecks(mgr ReadyzCheckAdder, checks ...Check) error {
+	for _, check := range checks {
+		checker := check.Checker
+		name := check.Name
+		setState(name, StateFailing)
+		if err := mgr.AddReadyzCheck(name, func(req *http.Request) error {
+			err := checker(req)
+			if err != nil {
+				setState(name, StateFailing)
+				return err
+			}
+			setState(name, StateReady)
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to add readiness check %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// AddSkippedChecks registers the checks of subsystems disabled by configuration. They always
+// pass and report the skipped state.
+func AddSkippedChecks(mgr ReadyzCheckAdder, names ...string) error {
+	for _, name := range names {
+		setState(name, StateSkipped)
+		if err := mgr.AddReadyzCheck(name, healthz.Ping); err != nil {
+			return fmt.Errorf("failed to add readiness check %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setState sets the gauge of the current state of the check and clears the others
+func setState(name, state string) {
+	for _, s := range checkStates {
+		value := 0.0
+		if s == state {
+			value = 1
+		}
+		readinessCheckGauge.WithLabelValues(name, s).Set(value)
+	}
+}
+
+// InformerSyncedChecks returns a check per object type, named informer-<kind>.<group>, that fails
+// until the informer of the type has synced. Types that cannot be mapped to a kind are skipped.
+func InformerSyncedChecks(informers cache.Informers, scheme *runtime.Scheme, objs ...client.Object) []Check {
+	var checks []Check
+	seen := map[string]bool{}
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			continue
+		}
+		name := "informer-" + strings.ToLower(gvk.Kind)
+		if gvk.Group != "" {
+			name += "." + gvk.Group
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		checks = append(checks, Check{Name: name, Checker: func(req *http.Request) error {
+			informer, err := informers.GetInformer(req.Context(), obj, cache.BlockUntilSynced(false))
+			if err != nil {
+				return fmt.Errorf("failed to get informer for %s: %w", gvk, err)
+			}
+			if !informer.HasSynced() {
+				return fmt.Errorf("informer for %s has not synced", gvk)
+			}
+			return nil
+		}})
+	}
+	return checks
+}
+
+// ListeningChecker returns a checker failing when nothing accepts connections on the address
+func ListeningChecker(address string) healthz.Checker {
+	return func(req *http.Request) error {
+		dialer := &net.Dialer{Timeout: dialTimeout}
+		conn, err := dialer.DialContext(req.Context(), "tcp", address)
+		if err != nil {
+			return fmt.Errorf("nothing is listening on %s: %w", address, err)
+		}
+		return conn.Close()
+	}
+}
+
+// ReachableWithin returns a checker probing a dependency on each evaluation, that fails only when
+// no probe succeeded within the window, so that a transient failure does not make the operator unready.
+// The checker fails until a first probe succeeds.
+func ReachableWithin(window time.Duration, probe func(ctx context.Context) error) healthz.Checker {
+	return newReachability(window, probe, time.Now).check
+}
+
+// AllReachableWithin returns a checker probing each named dependency like ReachableWithin, that
+// fails naming every dependency not reachable within the window
+func AllReachableWithin(window time.Duration, probes map[string]func(ctx context.Context) error) healthz.Checker {
+	names := make([]string, 0, len(probes))
+	checkers := make(map[string]healthz.Checker, len(probes))
+	for name, probe := range probes {
+		names = append(names, name)
+		checkers[name] = ReachableWithin(window, probe)
+	}
+	sort.Strings(names)
+	return func(req *http.Request) error {
+		var errs []error
+		for _, name := range names {
+			if err := checkers[name](req); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// reachability tracks the last successful probe of a dependency
+type reachability struct {
+	window time.Duration
+	probe  func(ctx context.Context) error
+	now    func() time.Time
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+func newReachability(window time.Duration, probe func(ctx context.Context) error, now func() time.Time) *reachability {
+	return &reachability{window: window, probe: probe, now: now}
+}
+
+func (r *reachability) check(req *http.Request) error {
+	err := r.probe(req.Context())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if err == nil {
+		r.lastSuccess = now
+		return nil
+	}
+	if r.lastSuccess.IsZero() {
+		return fmt.Errorf("not reachable yet: %w", err)
+	}
+	if now.Sub(r.lastSuccess) > r.window {
+		return fmt.Errorf("not reachable since %s: %w", r.lastSuccess.UTC().Format(time.RFC3339), err)
+	}
+	return nil
+}
+
+// SyncedChecker returns a checker failing until the synced function returns true
+func SyncedChecker(what string, synced func() bool) healthz.Checker {
+	return func(*http.Request) error {
+		if synced == nil || !synced() {
+			return fmt.Errorf("%s has not synced", what)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// fakeReadyzChecks records the registered checks like the manager does
+type fakeReadyzChecks map[string]healthz.Checker
+
+func (f fakeReadyzChecks) AddReadyzCheck(name string, check healthz.Checker) error {
+	f[name] = check
+	return nil
+}
+
+func newCheckRequest() *http.Request {
+	return httptest.NewRequest(http.MethodGet, "/readyz", nil)
+}
+
+func stateValue(name, state string) float64 {
+	return testutil.ToFloat64(readinessCheckGauge.WithLabelValues(name, state))
+}
+
+func TestAddReadyzChecksPublishesState(t *testing.T) {
+	checks := fakeReadyzChecks{}
+	var failure error = errors.New("sink unreachable")
+	require.NoError(t, AddReadyzChecks(checks, Check{
+		Name:    "test-subsystem",
+		Checker: func(*http.Request) error { return failure },
+	}))
+	assert.Equal(t, float64(1), stateValue("test-subsystem", StateFailing))
+
+	require.Error(t, checks["test-subsystem"](newCheckRequest()))
+	assert.Equal(t, float64(1), stateValue("test-subsystem", StateFailing))
+	assert.Equal(t, float64(0), stateValue("test-subsystem", StateReady))
+
+	failure = nil
+	require.NoError(t, checks["test-subsystem"](newCheckRequest()))
+	assert.Equal(t, float64(1), stateValue("test-subsystem", StateReady))
+	assert.Equal(t, float64(0), stateValue("test-subsystem", StateFailing))
+}
+
+func TestAddSkippedChecksPass(t *testing.T) {
+	checks := fakeReadyzChecks{}
+	require.NoError(t, AddSkippedChecks(checks, "test-disabled"))
+
+	require.NoError(t, checks["test-disabled"](newCheckRequest()))
+	assert.Equal(t, float64(1), stateValue("test-disabled", StateSkipped))
+	assert.Equal(t, float64(0), stateValue("test-disabled", StateFailing))
+}
+
+func TestInformerSyncedChecks(t *testing.T) {
+	informers := &informertest.FakeInformers{Scheme: clientgoscheme.Scheme}
+	checks := InformerSyncedChecks(informers, clientgoscheme.Scheme,
+		&appsv1.Deployment{}, &corev1.Pod{}, &corev1.Pod{})
+	require.Len(t, checks, 2)
+	assert.Equal(t, "informer-deployment.apps", checks[0].Name)
+	assert.Equal(t, "informer-pod", checks[1].Name)
+
+	err := checks[1].Checker(newCheckRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has not synced")
+
+	informer, err := informers.FakeInformerFor(context.Background(), &corev1.Pod{})
+	require.NoError(t, err)
+	informer.Synced = true
+	assert.NoError(t, checks[1].Checker(newCheckRequest()))
+}
+
+func TestListeningChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+
+	assert.NoError(t, ListeningChecker(address)(newCheckRequest()))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, ListeningChecker(address)(newCheckRequest()))
+}
+
+func TestReachableWithinToleratesTransientFailures(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var failure error = errors.New("connection refused")
+	r := newReachability(5*time.Minute,
+		func(context.Context) error { return failure },
+		func() time.Time { return now })
+
+	err := r.check(newCheckRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not reachable yet")
+
+	failure = nil
+	require.NoError(t, r.check(newCheckRequest()))
+
+	failure = errors.New("connection refused")
+	now = now.Add(4 * time.Minute)
+	assert.NoError(t, r.check(newCheckRequest()))
+
+	now = now.Add(2 * time.Minute)
+	err = r.check(newCheckRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not reachable since 2025-01-01T00:00:00Z")
+}
+
+func TestAllReachableWithinNamesFailingDependencies(t *testing.T) {
+	checker := AllReachableWithin(time.Minute, map[string]func(context.Context) error{
+		"aws": func(context.Context) error { return nil },
+		"gcp": func(context.Context) error { return errors.New("connection refused") },
+	})
+
+	err := checker(newCheckRequest())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gcp: ")
+	assert.NotContains(t, err.Error(), "aws")
+}
+
+func TestSyncedChecker(t *testing.T) {
+	synced := false
+	checker := SyncedChecker("template catalog", func() bool { return synced })
+	assert.Error(t, checker(newCheckRequest()))
+
+	synced = true
+	assert.NoError(t, checker(newCheckRequest()))
+}
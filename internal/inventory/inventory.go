@@ -0,0 +1,289 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package inventory serves a read-only inventory of the workspaces of the cluster, for an external
+// aggregator polling the operators of several clusters
+package inventory
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+const (
+	// DefaultPageSize is the number of workspaces of a page when the request sets no limit
+	DefaultPageSize = 500
+
+	// MaxPageSize is the largest number of workspaces of a page
+	MaxPageSize = 5000
+)
+
+// Inventory fields the ?fields= parameter selects; name and namespace are always included
+const (
+	FieldOwner            = "owner"
+	FieldPhase            = "phase"
+	FieldTemplate         = "template"
+	FieldStartedAt        = "startedAt"
+	FieldLastActivityTime = "lastActivityTime"
+	FieldResources        = "resources"
+	FieldPodRequests      = "podRequests"
+	FieldGPUCount         = "gpuCount"
+	FieldStorageSize      = "storageSize"
+	FieldRecommendations  = "recommendations"
+	FieldResourceVersion  = "resourceVersion"
+)
+
+// allFields are the fields of a record, in the order they are documented
+var allFields = []string{FieldOwner, FieldPhase, FieldTemplate, FieldStartedAt, FieldLastActivityTime, FieldResources,
+	FieldPodRequests, FieldGPUCount, FieldStorageSize, FieldRecommendations, FieldResourceVersion}
+
+// WorkspaceRecord describes a workspace in the inventory, with the usage and cost fields of its status
+type WorkspaceRecord struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	// Owner is the user who created the workspace
+	Owner string `json:"owner,omitempty"`
+	// Phase is derived from the conditions of the workspace: Running, Pending, Stopping, Stopped or Unknown
+	Phase string `json:"phase,omitempty"`
+	// Template is the namespace/name of the template of the workspace
+	Template string `json:"template,omitempty"`
+	// StartedAt is when the workspace last became available
+	StartedAt        *metav1.Time                 `json:"startedAt,omitempty"`
+	LastActivityTime *metav1.Time                 `json:"lastActivityTime,omitempty"`
+	Resources        *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// PodRequests are the requests the workspace pod totals, its workspace container and the template sidecars
+	PodRequests corev1.ResourceList `json:"podRequests,omitempty"`
+	// GPUCount is the number of accelerators the workspace requests, of AcceleratorResourceName
+	GPUCount                *int32                                     `json:"gpuCount,omitempty"`
+	AcceleratorResourceName string                                     `json:"acceleratorResourceName,omitempty"`
+	StorageSize             string                                     `json:"storageSize,omitempty"`
+	Recommendations         *workspacev1alpha1.ResourceRecommendations `json:"recommendations,omitempty"`
+	ResourceVersion         string                                     `json:"resourceVersion,omitempty"`
+}
+
+// WorkspacePage is a page of the inventory
+type WorkspacePage struct {
+	// Cluster is the name the operator was given for the cluster
+	Cluster string `json:"cluster,omitempty"`
+	// ResourceVersion is the highest resource version of the workspaces when the listing started; passed as
+	// ?sinceResourceVersion= once the last page is read, the next listing returns the workspaces changed since
+	ResourceVersion string            `json:"resourceVersion"`
+	Items           []WorkspaceRecord `json:"items"`
+	// Continue is passed as ?continue= to read the next page, empty on the last page
+	Continue string `json:"continue,omitempty"`
+}
+
+// Query selects the workspaces of a page and their fields
+type Query struct {
+	// Namespace restricts the inventory to a namespace when set
+	Namespace string
+	// Limit is the number of workspaces of the page
+	Limit int
+	// Fields are the fields of the records besides name and namespace, all of them when empty
+	Fields []string
+	// SinceResourceVersion restricts the inventory to the workspaces changed after the resource version
+	SinceResourceVersion uint64
+	// Continue resumes a listing after the last workspace of the previous page
+	Continue string
+}
+
+// continueToken is the position of a listing, encoded in the continue parameter
+type continueToken struct {
+	// After is the namespace/name of the last workspace of the previous page
+	After string `json:"after"`
+	// ResourceVersion is the resource version of the listing, reported on each of its pages
+	ResourceVersion uint64 `json:"rv"`
+}
+
+// encodeContinue returns the continue parameter of the listing position
+func encodeContinue(token continueToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeContinue returns the listing position of the continue parameter
+func decodeContinue(value string) (continueToken, error) {
+	token := continueToken{}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return token, fmt.Errorf("invalid continue token")
+	}
+	if err := json.Unmarshal(data, &token); err != nil {
+		return token, fmt.Errorf("invalid continue token")
+	}
+	return token, nil
+}
+
+// ParseFields returns the fields of the comma-separated ?fields= value, or an error naming an unknown field
+func ParseFields(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		switch {
+		case field == "" || field == "name" || field == "namespace":
+			continue
+		case !slices.Contains(allFields, field):
+			return nil, fmt.Errorf("unknown field %q, expected some of name, namespace, %s", field, strings.Join(allFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// resourceVersionOf returns the resource version of the workspace as a number, 0 when it is not one
+func resourceVersionOf(workspace *workspacev1alpha1.Workspace) uint64 {
+	version, err := strconv.ParseUint(workspace.ResourceVersion, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// BuildPage returns the page of the workspaces the query selects. The workspaces are read only; they are
+// ordered by namespace and name, so that a listing resumes where its previous page ended.
+func BuildPage(workspaces []*workspacev1alpha1.Workspace, query Query) (WorkspacePage, error) {
+	position := continueToken{}
+	if query.Continue != "" {
+		var err error
+		if position, err = decodeContinue(query.Continue); err != nil {
+			return WorkspacePage{}, err
+		}
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	selected := make([]*workspacev1alpha1.Workspace, 0, len(workspaces))
+	snapshot := position.ResourceVersion
+	for _, workspace := range workspaces {
+		if query.Namespace != "" && workspace.Namespace != query.Namespace {
+			continue
+		}
+		version := resourceVersionOf(workspace)
+		// The first page fixes the resource version of the listing
+		if query.Continue == "" {
+			snapshot = max(snapshot, version)
+		}
+		if version <= query.SinceResourceVersion {
+			continue
+		}
+		if position.After != "" && workspaceKey(workspace) <= position.After {
+			continue
+		}
+		selected = append(selected, workspace)
+	}
+	if query.Continue == "" {
+		snapshot = max(snapshot, query.SinceResourceVersion)
+	}
+	slices.SortFunc(selected, func(a, b *workspacev1alpha1.Workspace) int {
+		return strings.Compare(workspaceKey(a), workspaceKey(b))
+	})
+
+	page := WorkspacePage{
+		ResourceVersion: strconv.FormatUint(snapshot, 10),
+		Items:           make([]WorkspaceRecord, 0, min(limit, len(selected))),
+	}
+	for _, workspace := range selected[:min(limit, len(selected))] {
+		page.Items = append(page.Items, project(newWorkspaceRecord(workspace), query.Fields))
+	}
+	if len(selected) > limit {
+		page.Continue = encodeContinue(continueToken{
+			After:           workspaceKey(selected[limit-1]),
+			ResourceVersion: snapshot,
+		})
+	}
+	return page, nil
+}
+
+// workspaceKey orders the workspaces of a listing; names cannot hold a slash, so keys are unique
+func workspaceKey(workspace *workspacev1alpha1.Workspace) string {
+	return workspace.Namespace + "/" + workspace.Name
+}
+
+// newWorkspaceRecord returns the record of the workspace, with every field
+func newWorkspaceRecord(workspace *workspacev1alpha1.Workspace) WorkspaceRecord {
+	record := WorkspaceRecord{
+		Name:             workspace.Name,
+		Namespace:        workspace.Namespace,
+		Owner:            workspace.Annotations[controller.AnnotationCreatedBy],
+		Phase:            controller.GetWorkspacePhase(workspace),
+		StartedAt:        workspace.Status.LastStartTime,
+		LastActivityTime: workspace.Status.LastActivityTime,
+		Resources:        workspace.Spec.Resources,
+		PodRequests: controller.PodResourceRequests(controller.RequestedResources(workspace), workspace.Status.Sidecars,
+			workspace.Status.SidecarResourceAccounting),
+		GPUCount:        workspace.Spec.GPUCount,
+		Recommendations: workspace.Status.Recommendations,
+		ResourceVersion: workspace.ResourceVersion,
+	}
+	if ref := workspace.Spec.TemplateRef; ref != nil && ref.Name != "" {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = workspace.Namespace
+		}
+		record.Template = namespace + "/" + ref.Name
+	}
+	if workspace.Spec.GPUCount != nil && *workspace.Spec.GPUCount > 0 && workspace.Status.Accelerators != nil {
+		record.AcceleratorResourceName = string(workspace.Status.Accelerators.ResourceName)
+		if record.AcceleratorResourceName == "" {
+			record.AcceleratorResourceName = string(controller.DefaultAcceleratorResourceName)
+		}
+	}
+	if workspace.Spec.Storage != nil {
+		record.StorageSize = workspace.Spec.Storage.Size.String()
+	}
+	return record
+}
+
+// project returns the record with the fields not selected cleared; all fields are kept when none is selected
+func project(record WorkspaceRecord, fields []string) WorkspaceRecord {
+	if len(fields) == 0 {
+		return record
+	}
+	projected := WorkspaceRecord{Name: record.Name, Namespace: record.Namespace}
+	for _, field := range fields {
+		switch field {
+		case FieldOwner:
+			projected.Owner = record.Owner
+		case FieldPhase:
+			projected.Phase = record.Phase
+		case FieldTemplate:
+			projected.Template = record.Template
+		case FieldStartedAt:
+			projected.StartedAt = record.StartedAt
+		case FieldLastActivityTime:
+			projected.LastActivityTime = record.LastActivityTime
+		case FieldResources:
+			projected.Resources = record.Resources
+		case FieldPodRequests:
+			projected.PodRequests = record.PodRequests
+		case FieldGPUCount:
+			projected.GPUCount = record.GPUCount
+			projected.AcceleratorResourceName = record.AcceleratorResourceName
+		case FieldStorageSize:
+			projected.StorageSize = record.StorageSize
+		case FieldRecommendations:
+			projected.Recommendations = record.Recommendations
+		case FieldResourceVersion:
+			projected.ResourceVersion = record.ResourceVersion
+		}
+	}
+	return projected
+}
@@ -0,0 +1,167 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package inventory
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// newInventoryTestWorkspaces returns count workspaces spread across ten namespaces, with resource versions
+// 1 to count in an order unrelated to their names
+func newInventoryTestWorkspaces(count int) []*workspacev1alpha1.Workspace {
+	workspaces := make([]*workspacev1alpha1.Workspace, 0, count)
+	for i := range count {
+		workspaces = append(workspaces, &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("ws-%05d", (i*7919)%count),
+				Namespace:       fmt.Sprintf("team-%d", i%10),
+				ResourceVersion: strconv.Itoa(i + 1),
+				Annotations:     map[string]string{controller.AnnotationCreatedBy: fmt.Sprintf("user-%d", i)},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "workspace",
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: "python"},
+				Storage:     &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+			},
+		})
+	}
+	return workspaces
+}
+
+// readAllPages returns the records of every page of the listing of the query, and the resource version of its pages
+func readAllPages(t testing.TB, workspaces []*workspacev1alpha1.Workspace, query Query) ([]WorkspaceRecord, string) {
+	var records []WorkspaceRecord
+	resourceVersion := ""
+	for {
+		page, err := BuildPage(workspaces, query)
+		require.NoError(t, err)
+		if resourceVersion == "" {
+			resourceVersion = page.ResourceVersion
+		}
+		require.Equal(t, resourceVersion, page.ResourceVersion, "the pages of a listing report its resource version")
+		records = append(records, page.Items...)
+		if page.Continue == "" {
+			return records, resourceVersion
+		}
+		query.Continue = page.Continue
+	}
+}
+
+func TestBuildPageListsTenThousandWorkspacesOnceAcrossPages(t *testing.T) {
+	workspaces := newInventoryTestWorkspaces(10000)
+
+	records, resourceVersion := readAllPages(t, workspaces, Query{Limit: 750})
+	require.Len(t, records, 10000)
+	assert.Equal(t, "10000", resourceVersion)
+	seen := map[string]bool{}
+	for i, record := range records {
+		key := record.Namespace + "/" + record.Name
+		assert.False(t, seen[key], "workspace %s is listed twice", key)
+		seen[key] = true
+		if i > 0 {
+			assert.Less(t, records[i-1].Namespace+"/"+records[i-1].Name, key)
+		}
+	}
+}
+
+func TestBuildPageKeepsTheListingResourceVersionWhenWorkspacesChange(t *testing.T) {
+	workspaces := newInventoryTestWorkspaces(100)
+
+	first, err := BuildPage(workspaces, Query{Limit: 60})
+	require.NoError(t, err)
+	require.NotEmpty(t, first.Continue)
+	workspaces[0].ResourceVersion = "101"
+
+	second, err := BuildPage(workspaces, Query{Limit: 60, Continue: first.Continue})
+	require.NoError(t, err)
+	assert.Equal(t, "100", second.ResourceVersion)
+	assert.Len(t, second.Items, 40)
+	assert.Empty(t, second.Continue)
+
+	// The next incremental listing returns the workspace which changed during the previous one
+	changed, err := BuildPage(workspaces, Query{SinceResourceVersion: 100})
+	require.NoError(t, err)
+	require.Len(t, changed.Items, 1)
+	assert.Equal(t, workspaces[0].Name, changed.Items[0].Name)
+	assert.Equal(t, "101", changed.ResourceVersion)
+}
+
+func TestBuildPageReturnsTheWorkspacesChangedSinceAResourceVersion(t *testing.T) {
+	workspaces := newInventoryTestWorkspaces(1000)
+
+	records, resourceVersion := readAllPages(t, workspaces, Query{Limit: 100, SinceResourceVersion: 990})
+	assert.Len(t, records, 10)
+	assert.Equal(t, "1000", resourceVersion)
+
+	unchanged, err := BuildPage(workspaces, Query{SinceResourceVersion: 1000})
+	require.NoError(t, err)
+	assert.Empty(t, unchanged.Items)
+	assert.Equal(t, "1000", unchanged.ResourceVersion, "an unchanged inventory keeps the resource version")
+}
+
+func TestBuildPageFiltersTheNamespace(t *testing.T) {
+	workspaces := newInventoryTestWorkspaces(100)
+
+	records, _ := readAllPages(t, workspaces, Query{Namespace: "team-3", Limit: 3})
+	assert.Len(t, records, 10)
+	for _, record := range records {
+		assert.Equal(t, "team-3", record.Namespace)
+	}
+}
+
+func TestBuildPageProjectsTheSelectedFields(t *testing.T) {
+	workspaces := newInventoryTestWorkspaces(1)
+
+	page, err := BuildPage(workspaces, Query{})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "user-0", page.Items[0].Owner)
+	assert.Equal(t, "team-0/python", page.Items[0].Template)
+	assert.Equal(t, "10Gi", page.Items[0].StorageSize)
+	assert.Equal(t, "1", page.Items[0].ResourceVersion)
+
+	page, err = BuildPage(workspaces, Query{Fields: []string{FieldOwner}})
+	require.NoError(t, err)
+	assert.Equal(t, WorkspaceRecord{Name: "ws-00000", Namespace: "team-0", Owner: "user-0"}, page.Items[0])
+}
+
+func TestBuildPageRejectsAnInvalidContinueToken(t *testing.T) {
+	_, err := BuildPage(newInventoryTestWorkspaces(1), Query{Continue: "not-a-token"})
+	assert.ErrorContains(t, err, "invalid continue token")
+}
+
+func TestParseFields(t *testing.T) {
+	fields, err := ParseFields("name, owner,phase")
+	require.NoError(t, err)
+	assert.Equal(t, []string{FieldOwner, FieldPhase}, fields)
+
+	fields, err = ParseFields("")
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+
+	_, err = ParseFields("owner,password")
+	assert.ErrorContains(t, err, `unknown field "password"`)
+}
+
+func BenchmarkBuildPageOfTenThousandWorkspaces(b *testing.B) {
+	workspaces := newInventoryTestWorkspaces(10000)
+	b.ResetTimer()
+	for range b.N {
+		if _, err := BuildPage(workspaces, Query{Limit: MaxPageSize}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
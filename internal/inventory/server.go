@@ -0,0 +1,228 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package inventory
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// RouteWorkspaces is the path of the workspace inventory
+const RouteWorkspaces = "/inventory/v1/workspaces"
+
+// shutdownTimeout is how long in-flight requests may complete when the operator stops
+const shutdownTimeout = 10 * time.Second
+
+// requestsTotal counts the inventory requests, by response code
+var requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "jupyter_k8s_inventory_requests_total",
+	Help: "Number of requests of the workspace inventory endpoint, by response code",
+}, []string{"code"})
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal)
+}
+
+// Config configures the inventory server
+type Config struct {
+	// BindAddress is the address the server listens on, e.g. ":8090"
+	BindAddress string
+	// TokenFile holds the bearer tokens accepted by the server, one per line, so that a token can be
+	// rotated without downtime. It is read on every request: a mounted Secret is picked up when it changes.
+	TokenFile string
+	// CertDir holds the tls.crt and tls.key the server serves HTTPS with; HTTP is served when empty
+	CertDir string
+	// ClusterName is reported in every page, for the aggregator to tell the clusters apart
+	ClusterName string
+}
+
+// Server serves the workspace inventory from the informer cache of the manager. It is read-only and
+// runs on every replica: it does not need leader election.
+type Server struct {
+	reader     client.Reader
+	config     Config
+	httpServer *http.Server
+	logger     logr.Logger
+}
+
+// NewServer returns an inventory server reading workspaces from the reader, the cache of the manager
+func NewServer(reader client.Reader, config Config, logger logr.Logger) (*Server, error) {
+	if config.TokenFile == "" {
+		return nil, errors.New("the inventory server requires a token file")
+	}
+	s := &Server{reader: reader, config: config, logger: logger}
+	mux := http.NewServeMux()
+	mux.HandleFunc(RouteWorkspaces, s.handleWorkspaces)
+	s.httpServer = &http.Server{
+		Addr:              config.BindAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s, nil
+}
+
+// Handler returns the handler of the server, for tests
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// Start implements manager.Runnable; it serves until the context is done
+func (s *Server) Start(ctx context.Context) error {
+	errs := make(chan error, 1)
+	go func() {
+		s.logger.Info("Starting inventory server", "addr", s.config.BindAddress, "tls", s.config.CertDir != "")
+		var err error
+		if s.config.CertDir != "" {
+			err = s.httpServer.ListenAndServeTLS(
+				filepath.Join(s.config.CertDir, "tls.crt"), filepath.Join(s.config.CertDir, "tls.key"))
+		} else {
+			err = s.httpServer.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errs <- err
+		}
+		close(errs)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return s.httpServer.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// handleWorkspaces serves a page of the workspace inventory. Parameters: namespace, limit, fields (a
+// comma-separated list of the record fields), sinceResourceVersion and continue.
+func (s *Server) handleWorkspaces(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, "the inventory is read-only, use GET")
+		return
+	}
+	authorized, err := s.authorized(r)
+	if err != nil {
+		s.logger.Error(err, "Failed to read the inventory tokens")
+		s.writeError(w, http.StatusInternalServerError, "failed to check the token")
+		return
+	}
+	if !authorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		s.writeError(w, http.StatusUnauthorized, "a valid bearer token is required")
+		return
+	}
+
+	query, err := parseQuery(r)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	list := &workspacev1alpha1.WorkspaceList{}
+	options := []client.ListOption{client.UnsafeDisableDeepCopy}
+	if query.Namespace != "" {
+		options = append(options, client.InNamespace(query.Namespace))
+	}
+	if err := s.reader.List(r.Context(), list, options...); err != nil {
+		s.logger.Error(err, "Failed to list workspaces")
+		s.writeError(w, http.StatusInternalServerError, "failed to list workspaces")
+		return
+	}
+	workspaces := make([]*workspacev1alpha1.Workspace, len(list.Items))
+	for i := range list.Items {
+		workspaces[i] = &list.Items[i]
+	}
+
+	page, err := BuildPage(workspaces, query)
+	if err != nil {
+		s.writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page.Cluster = s.config.ClusterName
+	s.writeJSON(w, http.StatusOK, page)
+}
+
+// parseQuery returns the query of the request parameters
+func parseQuery(r *http.Request) (Query, error) {
+	values := r.URL.Query()
+	query := Query{Namespace: values.Get("namespace"), Continue: values.Get("continue")}
+	if limit := values.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed <= 0 {
+			return query, fmt.Errorf("limit must be a positive number, at most %d", MaxPageSize)
+		}
+		query.Limit = parsed
+	}
+	if since := values.Get("sinceResourceVersion"); since != "" {
+		parsed, err := strconv.ParseUint(since, 10, 64)
+		if err != nil {
+			return query, errors.New("sinceResourceVersion must be the resourceVersion of a previous listing")
+		}
+		query.SinceResourceVersion = parsed
+	}
+	fields, err := ParseFields(values.Get("fields"))
+	if err != nil {
+		return query, err
+	}
+	query.Fields = fields
+	return query, nil
+}
+
+// authorized returns true if the request carries one of the tokens of the token file
+func (s *Server) authorized(r *http.Request) (bool, error) {
+	presented, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || presented == "" {
+		return false, nil
+	}
+	data, err := os.ReadFile(s.config.TokenFile)
+	if err != nil {
+		return false, err
+	}
+	for _, token := range strings.Split(string(data), "\n") {
+		token = strings.TrimSpace(token)
+		if token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(presented)) == 1 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// writeError writes an error response
+func (s *Server) writeError(w http.ResponseWriter, status int, message string) {
+	s.writeJSON(w, status, map[string]string{"error": message})
+}
+
+// writeJSON writes the body as JSON and counts the request
+func (s *Server) writeJSON(w http.ResponseWriter, status int, body any) {
+	requestsTotal.WithLabelValues(strconv.Itoa(status)).Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		s.logger.Error(err, "Failed to encode the inventory response")
+	}
+}
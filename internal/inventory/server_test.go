@@ -0,0 +1,118 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// newInventoryTestServer returns an inventory server of the workspaces accepting the tokens
+func newInventoryTestServer(t *testing.T, tokens string, workspaces ...*workspacev1alpha1.Workspace) *Server {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	objects := make([]client.Object, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		workspace = workspace.DeepCopy()
+		workspace.ResourceVersion = ""
+		objects = append(objects, workspace)
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	tokenFile := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(tokens), 0o600))
+	server, err := NewServer(reader, Config{TokenFile: tokenFile, ClusterName: "prod-eu"}, logr.Discard())
+	require.NoError(t, err)
+	return server
+}
+
+// serveInventory returns the response of the server to a request of the target with the token
+func serveInventory(server *Server, method, target, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestNewServerRequiresATokenFile(t *testing.T) {
+	_, err := NewServer(nil, Config{}, logr.Discard())
+	assert.Error(t, err)
+}
+
+func TestServerServesTheInventoryPages(t *testing.T) {
+	server := newInventoryTestServer(t, "old-token\nnew-token\n", newInventoryTestWorkspaces(25)...)
+
+	response := serveInventory(server, http.MethodGet, RouteWorkspaces+"?limit=20&fields=owner", "new-token")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	page := WorkspacePage{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	assert.Equal(t, "prod-eu", page.Cluster)
+	assert.Len(t, page.Items, 20)
+	assert.NotEmpty(t, page.Items[0].Owner)
+	assert.Empty(t, page.Items[0].Template)
+	require.NotEmpty(t, page.Continue)
+
+	response = serveInventory(server, http.MethodGet, RouteWorkspaces+"?limit=20&continue="+page.Continue, "old-token")
+	require.Equal(t, http.StatusOK, response.Code)
+	next := WorkspacePage{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &next))
+	assert.Len(t, next.Items, 5)
+	assert.Equal(t, page.ResourceVersion, next.ResourceVersion)
+	assert.Empty(t, next.Continue)
+}
+
+func TestServerFiltersTheNamespace(t *testing.T) {
+	server := newInventoryTestServer(t, "token", newInventoryTestWorkspaces(20)...)
+
+	response := serveInventory(server, http.MethodGet, RouteWorkspaces+"?namespace=team-1", "token")
+	require.Equal(t, http.StatusOK, response.Code)
+	page := WorkspacePage{}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &page))
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, "team-1", page.Items[0].Namespace)
+}
+
+func TestServerRejectsInvalidRequests(t *testing.T) {
+	server := newInventoryTestServer(t, "token\n", newInventoryTestWorkspaces(1)...)
+
+	for name, tc := range map[string]struct {
+		method       string
+		target       string
+		token        string
+		expectedCode int
+	}{
+		"no token":           {http.MethodGet, RouteWorkspaces, "", http.StatusUnauthorized},
+		"wrong token":        {http.MethodGet, RouteWorkspaces, "other", http.StatusUnauthorized},
+		"write":              {http.MethodPost, RouteWorkspaces, "token", http.StatusMethodNotAllowed},
+		"unknown field":      {http.MethodGet, RouteWorkspaces + "?fields=secret", "token", http.StatusBadRequest},
+		"invalid limit":      {http.MethodGet, RouteWorkspaces + "?limit=-1", "token", http.StatusBadRequest},
+		"invalid since":      {http.MethodGet, RouteWorkspaces + "?sinceResourceVersion=abc", "token", http.StatusBadRequest},
+		"invalid continue":   {http.MethodGet, RouteWorkspaces + "?continue=abc", "token", http.StatusBadRequest},
+		"valid token listed": {http.MethodGet, RouteWorkspaces, "token", http.StatusOK},
+	} {
+		t.Run(name, func(t *testing.T) {
+			response := serveInventory(server, tc.method, tc.target, tc.token)
+			assert.Equal(t, tc.expectedCode, response.Code, response.Body.String())
+		})
+	}
+}
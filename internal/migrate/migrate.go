@@ -0,0 +1,228 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package migrate moves workspaces from one template to another in bulk.
+// Each workspace is checked against the target template with the same defaulting and template
+// validation as the webhook before its templateRef is rewritten. The rewrite goes through the
+// webhook, which updates the template labels, protects the target template with its finalizer
+// and records the admitted generation; the template controller then releases the source template.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookv1alpha1 "github.com/jupyter-infra/jupyter-k8s/internal/webhook/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// listPageSize is the number of workspaces listed per request
+const listPageSize int64 = 100
+
+// Outcome is the result of migrating one workspace
+type Outcome string
+
+const (
+	// OutcomeMigrated means the templateRef of the workspace was rewritten
+	OutcomeMigrated Outcome = "Migrated"
+
+	// OutcomeWouldMigrate means the target template accepts the workspace (dry run)
+	OutcomeWouldMigrate Outcome = "WouldMigrate"
+
+	// OutcomeRejected means the target template does not accept the workspace
+	OutcomeRejected Outcome = "Rejected"
+
+	// OutcomeSkipped means the workspace stopped referencing the source template during the migration
+	OutcomeSkipped Outcome = "Skipped"
+
+	// OutcomeFailed means the workspace could not be updated
+	OutcomeFailed Outcome = "Failed"
+)
+
+// Options configures a migration
+type Options struct {
+	// From is the template the workspaces reference
+	From types.NamespacedName
+
+	// To is the template the workspaces are moved to
+	To types.NamespacedName
+
+	// DefaultTemplateNamespace is the shared template namespace workspaces of any namespace may
+	// reference, as configured on the operator
+	DefaultTemplateNamespace string
+
+	// DryRun reports what would be migrated without updating anything
+	DryRun bool
+}
+
+// Result is the outcome of migrating one workspace
+type Result struct {
+	// Workspace is the migrated workspace
+	Workspace types.NamespacedName
+
+	// Outcome is what happened to the workspace
+	Outcome Outcome
+
+	// Message explains a rejection or failure
+	Message string
+}
+
+// Succeeded returns true if the workspace was, or would be, migrated
+func (r Result) Succeeded() bool {
+	return r.Outcome == OutcomeMigrated || r.Outcome == OutcomeWouldMigrate
+}
+
+// MigrateTemplate moves every workspace referencing the source template to the target template and
+// returns the outcome for each workspace, ordered by namespace and name. Workspaces the target template
+// does not accept are left on the source template. An error is returned only when the migration
+// cannot start.
+func MigrateTemplate(ctx context.Context, k8sClient client.Client, opts Options) ([]Result, error) {
+	if opts.From == opts.To {
+		return nil, errors.New("the source and target templates are the same")
+	}
+
+	target := &workspacev1alpha1.WorkspaceTemplate{}
+	if err := k8sClient.Get(ctx, opts.To, target); err != nil {
+		return nil, fmt.Errorf("failed to get target template %s: %w", opts.To, err)
+	}
+	if !target.DeletionTimestamp.IsZero() {
+		return nil, fmt.Errorf("target template %s is being deleted", opts.To)
+	}
+
+	workspaces, err := listWorkspaces(ctx, k8sClient, opts.From)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(workspaces))
+	for i := range workspaces {
+		results = append(results, migrateWorkspace(ctx, k8sClient, &workspaces[i], target, opts))
+	}
+	return results, nil
+}
+
+// listWorkspaces returns the active workspaces referencing the template, ordered by namespace and name
+func listWorkspaces(
+	ctx context.Context,
+	k8sClient client.Client,
+	template types.NamespacedName) ([]workspacev1alpha1.Workspace, error) {
+	var workspaces []workspacev1alpha1.Workspace
+	continueToken := ""
+	for {
+		page, next, err := workspaceutil.ListActiveWorkspacesByTemplate(
+			ctx, k8sClient, template.Name, template.Namespace, continueToken, listPageSize)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, page...)
+		if next == "" {
+			break
+		}
+		continueToken = next
+	}
+	sort.Slice(workspaces, func(i, j int) bool {
+		if workspaces[i].Namespace != workspaces[j].Namespace {
+			return workspaces[i].Namespace < workspaces[j].Namespace
+		}
+		return workspaces[i].Name < workspaces[j].Name
+	})
+	return workspaces, nil
+}
+
+// migrateWorkspace validates the workspace against the target template and rewrites its templateRef
+func migrateWorkspace(
+	ctx context.Context,
+	k8sClient client.Client,
+	workspace *workspacev1alpha1.Workspace,
+	target *workspacev1alpha1.WorkspaceTemplate,
+	opts Options) Result {
+	result := Result{Workspace: client.ObjectKeyFromObject(workspace)}
+
+	if problems := checkTarget(workspace, target, opts.DefaultTemplateNamespace); len(problems) > 0 {
+		result.Outcome = OutcomeRejected
+		result.Message = strings.Join(problems, "; ")
+		return result
+	}
+	if opts.DryRun {
+		result.Outcome = OutcomeWouldMigrate
+		return result
+	}
+
+	skipped := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &workspacev1alpha1.Workspace{}
+		if err := k8sClient.Get(ctx, result.Workspace, latest); err != nil {
+			return err
+		}
+		if !referencesTemplate(latest, opts.From) {
+			skipped = true
+			return nil
+		}
+		latest.Spec.TemplateRef = retargetTemplateRef(latest.Spec.TemplateRef, target)
+		return k8sClient.Update(ctx, latest)
+	})
+	switch {
+	case apierrors.IsNotFound(err):
+		result.Outcome = OutcomeSkipped
+		result.Message = "workspace was deleted"
+	case err != nil:
+		result.Outcome = OutcomeFailed
+		result.Message = err.Error()
+	case skipped:
+		result.Outcome = OutcomeSkipped
+		result.Message = fmt.Sprintf("workspace no longer references template %s", opts.From)
+	default:
+		result.Outcome = OutcomeMigrated
+	}
+	return result
+}
+
+// checkTarget returns why the webhook would not admit the workspace on the target template
+func checkTarget(
+	workspace *workspacev1alpha1.Workspace,
+	target *workspacev1alpha1.WorkspaceTemplate,
+	defaultTemplateNamespace string) []string {
+	var problems []string
+	if target.Namespace != workspace.Namespace && target.Namespace != defaultTemplateNamespace {
+		problems = append(problems, fmt.Sprintf(
+			"template namespace %q is neither the workspace namespace nor the shared template namespace", target.Namespace))
+	}
+
+	// The webhook applies the target template defaults before validating the workspace against it
+	candidate := workspace.DeepCopy()
+	candidate.Spec.TemplateRef = retargetTemplateRef(candidate.Spec.TemplateRef, target)
+	webhookv1alpha1.ApplyTemplateDefaultsFrom(candidate, target)
+	for _, violation := range webhookv1alpha1.ValidateWorkspaceAgainstTemplate(candidate, target) {
+		problems = append(problems, fmt.Sprintf("%s: %s", violation.Field, violation.Message))
+	}
+	return problems
+}
+
+// referencesTemplate returns true if the workspace references the template
+func referencesTemplate(workspace *workspacev1alpha1.Workspace, template types.NamespacedName) bool {
+	return workspace.Spec.TemplateRef != nil &&
+		workspace.Spec.TemplateRef.Name == template.Name &&
+		workspaceutil.GetTemplateRefNamespace(workspace) == template.Namespace
+}
+
+// retargetTemplateRef returns the template reference pointing to the target, keeping its update policy
+func retargetTemplateRef(
+	ref *workspacev1alpha1.TemplateRef,
+	target *workspacev1alpha1.WorkspaceTemplate) *workspacev1alpha1.TemplateRef {
+	retargeted := &workspacev1alpha1.TemplateRef{Name: target.Name, Namespace: target.Namespace}
+	if ref != nil {
+		retargeted.UpdatePolicy = ref.UpdatePolicy
+	}
+	return retargeted
+}
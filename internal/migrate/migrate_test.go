@@ -0,0 +1,178 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+var (
+	sourceTemplate = types.NamespacedName{Name: "basic-template", Namespace: "team-a"}
+	targetTemplate = types.NamespacedName{Name: "standard", Namespace: "platform-templates"}
+)
+
+func newTestTemplate(key types.NamespacedName, allowedImages ...string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:   key.Name,
+			DefaultImage:  "jupyter/base-notebook:latest",
+			AllowedImages: allowedImages,
+		},
+	}
+}
+
+func newTestWorkspace(name, image string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "team-a",
+			Labels: map[string]string{
+				workspaceutil.LabelWorkspaceTemplate:          sourceTemplate.Name,
+				workspaceutil.LabelWorkspaceTemplateNamespace: sourceTemplate.Namespace,
+			},
+		},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			Image: image,
+			TemplateRef: &workspacev1alpha1.TemplateRef{
+				Name:         sourceTemplate.Name,
+				UpdatePolicy: workspacev1alpha1.TemplateUpdatePolicyPin,
+			},
+		},
+	}
+}
+
+func newTestClient(funcs interceptor.Funcs, objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(funcs).Build()
+}
+
+func testOptions(dryRun bool) Options {
+	return Options{
+		From:                     sourceTemplate,
+		To:                       targetTemplate,
+		DefaultTemplateNamespace: "platform-templates",
+		DryRun:                   dryRun,
+	}
+}
+
+func getTemplateRef(t *testing.T, k8sClient client.Client, name string) *workspacev1alpha1.TemplateRef {
+	workspace := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: name, Namespace: "team-a"}, workspace))
+	return workspace.Spec.TemplateRef
+}
+
+func TestMigrateTemplateRewritesAcceptedWorkspaces(t *testing.T) {
+	k8sClient := newTestClient(interceptor.Funcs{},
+		newTestTemplate(sourceTemplate),
+		newTestTemplate(targetTemplate, "jupyter/base-notebook:latest"),
+		newTestWorkspace("ws-b", "jupyter/base-notebook:latest"),
+		newTestWorkspace("ws-a", "jupyter/base-notebook:latest"),
+		newTestWorkspace("ws-custom", "registry.example.com/custom:1.0"))
+
+	results, err := MigrateTemplate(context.Background(), k8sClient, testOptions(false))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "ws-a", results[0].Workspace.Name)
+	assert.Equal(t, OutcomeMigrated, results[0].Outcome)
+	assert.Equal(t, "ws-b", results[1].Workspace.Name)
+	assert.Equal(t, OutcomeMigrated, results[1].Outcome)
+	assert.Equal(t, "ws-custom", results[2].Workspace.Name)
+	assert.Equal(t, OutcomeRejected, results[2].Outcome)
+	assert.Contains(t, results[2].Message, "registry.example.com/custom:1.0")
+	assert.False(t, results[2].Succeeded())
+
+	assert.Equal(t, &workspacev1alpha1.TemplateRef{
+		Name:         targetTemplate.Name,
+		Namespace:    targetTemplate.Namespace,
+		UpdatePolicy: workspacev1alpha1.TemplateUpdatePolicyPin,
+	}, getTemplateRef(t, k8sClient, "ws-a"))
+	assert.Equal(t, sourceTemplate.Name, getTemplateRef(t, k8sClient, "ws-custom").Name)
+}
+
+func TestMigrateTemplateDryRunUpdatesNothing(t *testing.T) {
+	k8sClient := newTestClient(interceptor.Funcs{
+		Update: func(context.Context, client.WithWatch, client.Object, ...client.UpdateOption) error {
+			return errors.New("dry run must not update")
+		},
+	},
+		newTestTemplate(sourceTemplate),
+		newTestTemplate(targetTemplate),
+		newTestWorkspace("ws-a", "jupyter/base-notebook:latest"))
+
+	results, err := MigrateTemplate(context.Background(), k8sClient, testOptions(true))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, OutcomeWouldMigrate, results[0].Outcome)
+	assert.True(t, results[0].Succeeded())
+	assert.Equal(t, sourceTemplate.Name, getTemplateRef(t, k8sClient, "ws-a").Name)
+}
+
+func TestMigrateTemplateRejectsUnreachableTemplateNamespace(t *testing.T) {
+	k8sClient := newTestClient(interceptor.Funcs{},
+		newTestTemplate(sourceTemplate),
+		newTestTemplate(targetTemplate),
+		newTestWorkspace("ws-a", "jupyter/base-notebook:latest"))
+
+	opts := testOptions(false)
+	opts.DefaultTemplateNamespace = ""
+	results, err := MigrateTemplate(context.Background(), k8sClient, opts)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, OutcomeRejected, results[0].Outcome)
+	assert.Contains(t, results[0].Message, "platform-templates")
+}
+
+func TestMigrateTemplateReportsFailedUpdates(t *testing.T) {
+	k8sClient := newTestClient(interceptor.Funcs{
+		Update: func(context.Context, client.WithWatch, client.Object, ...client.UpdateOption) error {
+			return errors.New("admission webhook denied the request")
+		},
+	},
+		newTestTemplate(sourceTemplate),
+		newTestTemplate(targetTemplate),
+		newTestWorkspace("ws-a", "jupyter/base-notebook:latest"))
+
+	results, err := MigrateTemplate(context.Background(), k8sClient, testOptions(false))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, OutcomeFailed, results[0].Outcome)
+	assert.Contains(t, results[0].Message, "admission webhook denied")
+}
+
+func TestMigrateTemplateRequiresTargetTemplate(t *testing.T) {
+	k8sClient := newTestClient(interceptor.Funcs{},
+		newTestTemplate(sourceTemplate),
+		newTestWorkspace("ws-a", "jupyter/base-notebook:latest"))
+
+	_, err := MigrateTemplate(context.Background(), k8sClient, testOptions(false))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), targetTemplate.String())
+
+	opts := testOptions(false)
+	opts.To = opts.From
+	_, err = MigrateTemplate(context.Background(), k8sClient, opts)
+	require.Error(t, err)
+}
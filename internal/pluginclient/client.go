@@ -46,6 +46,27 @@ func NewPluginClient(baseURL string, logger logr.Logger) *PluginClient {
 	}
 }
 
+// CheckHealth calls the health endpoint of the plugin and returns an error unless it reports healthy.
+// Health checks are not retried: readiness probes repeat them.
+func (c *PluginClient) CheckHealth(ctx context.Context) error {
+	callCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(callCtx, pluginapi.RouteHealthz.Method, c.baseURL+pluginapi.RouteHealthz.Path, nil)
+	if err != nil {
+		return fmt.Errorf("plugin client: create health request: %w", err)
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("plugin client: health check: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &plugin.StatusError{Code: resp.StatusCode, Message: "health check failed"}
+	}
+	return nil
+}
+
 // doPost sends a JSON POST request to the plugin and decodes the response.
 // This is a free function (not a method) because Go does not allow type parameters on methods.
 //
@@ -297,3 +297,31 @@ func TestDoPost_SucceedsAfterTransientFailure(t *testing.T) {
 	assert.Equal(t, "recovered", resp.Token)
 	assert.Equal(t, 2, attempts)
 }
+
+func TestCheckHealth(t *testing.T) {
+	healthy := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, pluginapi.RouteHealthz.Path, r.URL.Path)
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewPluginClient(srv.URL, logr.Discard())
+	require.NoError(t, client.CheckHealth(context.Background()))
+
+	healthy = false
+	err := client.CheckHealth(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
+
+func TestCheckHealth_Unreachable(t *testing.T) {
+	client := NewPluginClient("http://127.0.0.1:1", logr.Discard())
+	err := client.CheckHealth(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "health check")
+}
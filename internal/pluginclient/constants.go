@@ -19,4 +19,7 @@ const (
 	defaultRetryCount  = 3                      // 3 retries = 4 total attempts
 	defaultRetryDelay  = 500 * time.Millisecond // fixed delay between retries (no backoff — it's localhost)
 	defaultCallTimeout = 30 * time.Second       // per-call timeout; generous for cloud SDK operations
+
+	// healthCheckTimeout bounds a health check, which runs on every readiness probe
+	healthCheckTimeout = 2 * time.Second
 )
@@ -0,0 +1,241 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// headerContentDigest is the header the registry returns the digest of a manifest in
+	headerContentDigest = "Docker-Content-Digest"
+
+	// maxErrorBodyLength bounds the part of a response body reported in errors, which end in the workspace status
+	maxErrorBodyLength = 256
+
+	// maxManifestLength bounds the manifests read to compute their digest
+	maxManifestLength = 4 << 20
+)
+
+// manifestMediaTypes are the manifest types accepted, multi-platform indexes first, so that the digest
+// pins the image on every platform
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// StatusError is returned when the registry answers with an unexpected status
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("registry answered %d", e.Code)
+	}
+	return fmt.Sprintf("registry answered %d: %s", e.Code, e.Body)
+}
+
+// Client resolves image tags to digests
+type Client struct {
+	httpClient *http.Client
+}
+
+// NewClient returns a registry client
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{},
+	}
+}
+
+// ResolveDigest returns the digest, e.g. sha256:..., of the manifest the tag of an image points at.
+// The registry is authenticated with the credentials of its host, if any, through the token service it
+// names in its challenge. Calls are not retried here: the caller owns the retry policy.
+func (c *Client) ResolveDigest(ctx context.Context, image string, credentials Credentials) (string, error) {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.apiHost(), ref.Repository, ref.Tag)
+	credential, hasCredential := credentials.For(ref)
+
+	resp, err := c.getManifest(ctx, http.MethodHead, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	authorization := ""
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+		if authorization, err = c.authorize(ctx, challenge, ref, credential, hasCredential); err != nil {
+			return "", fmt.Errorf("failed to authenticate to %s: %w", ref.Registry, err)
+		}
+		if resp, err = c.getManifest(ctx, http.MethodHead, manifestURL, authorization); err != nil {
+			return "", err
+		}
+	}
+	defer func() { _ = resp.Body.Close() }()
+	return c.digestOf(ctx, resp, manifestURL, authorization)
+}
+
+// digestOf returns the digest of the manifest answered to a HEAD request. Registries that do not
+// return the digest header are asked for the manifest, whose digest is computed.
+func (c *Client) digestOf(ctx context.Context, resp *http.Response, manifestURL, authorization string) (string, error) {
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+	if digest := resp.Header.Get(headerContentDigest); digest != "" {
+		return digest, nil
+	}
+
+	get, err := c.getManifest(ctx, http.MethodGet, manifestURL, authorization)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = get.Body.Close() }()
+	if err := checkStatus(get); err != nil {
+		return "", err
+	}
+	if digest := get.Header.Get(headerContentDigest); digest != "" {
+		return digest, nil
+	}
+	manifest, err := io.ReadAll(io.LimitReader(get.Body, maxManifestLength))
+	if err != nil {
+		return "", fmt.Errorf("failed to read manifest: %w", err)
+	}
+	sum := sha256.Sum256(manifest)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// getManifest requests the manifest of an image, with the Authorization header when not empty
+func (c *Client) getManifest(ctx context.Context, method, manifestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, manifestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create manifest request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request manifest: %w", err)
+	}
+	return resp, nil
+}
+
+// authorize returns the Authorization header answering the challenge of the registry: the credential
+// itself for Basic, a token of the token service for Bearer. Anonymous tokens are requested without credential.
+func (c *Client) authorize(
+	ctx context.Context, challenge string, ref Reference, credential Credential, hasCredential bool) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCredential {
+			return "", fmt.Errorf("registry requires credentials, none found in the image pull secrets")
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(credential.Username, credential.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, params, ref, credential, hasCredential)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	}
+	return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+}
+
+// fetchToken requests a pull token of the repository from the token service named by a Bearer challenge
+func (c *Client) fetchToken(
+	ctx context.Context, params map[string]string, ref Reference, credential Credential, hasCredential bool) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", ref.Repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	if hasCredential {
+		req.SetBasicAuth(credential.Username, credential.Password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if err := checkStatus(resp); err != nil {
+		return "", err
+	}
+
+	body := struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestLength)).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", fmt.Errorf("token response holds no token")
+}
+
+// parseChallenge splits a WWW-Authenticate challenge, e.g. Bearer realm="...",service="...", into its
+// lower-cased scheme and its parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			params[key] = value
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// checkStatus returns a StatusError unless the response is a success
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+	return &StatusError{Code: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b"
+
+// newTestRegistry serves the manifest of team/notebook:v1 behind a token service granting pulls to
+// alice, or to anyone when anonymous is true
+func newTestRegistry(t *testing.T, anonymous bool) (*httptest.Server, *Client) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			assert.Equal(t, "registry.test", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:team/notebook:pull", r.URL.Query().Get("scope"))
+			if username, password, ok := r.BasicAuth(); !anonymous && (!ok || username != "alice" || password != "s3cret") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"pull-token"}`))
+		case "/v2/team/notebook/manifests/v1":
+			assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json")
+			if r.Header.Get("Authorization") != "Bearer pull-token" {
+				w.Header().Set("WWW-Authenticate",
+					`Bearer realm="`+server.URL+`/token",service="registry.test",scope="repository:team/notebook:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set(headerContentDigest, testDigest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &Client{httpClient: server.Client()}
+}
+
+func TestResolveDigestAuthenticatesWithThePullSecretCredentials(t *testing.T) {
+	server, registryClient := newTestRegistry(t, false)
+	host := strings.TrimPrefix(server.URL, "https://")
+	credentials := Credentials{host: {Username: "alice", Password: "s3cret"}}
+
+	digest, err := registryClient.ResolveDigest(context.Background(), host+"/team/notebook:v1", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+
+	_, err = registryClient.ResolveDigest(context.Background(), host+"/team/notebook:v1", nil)
+	assert.EqualError(t, err, "failed to authenticate to "+host+": registry answered 401")
+}
+
+func TestResolveDigestUsesAnonymousTokens(t *testing.T) {
+	server, registryClient := newTestRegistry(t, true)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	digest, err := registryClient.ResolveDigest(context.Background(), host+"/team/notebook:v1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, testDigest, digest)
+}
+
+func TestResolveDigestReportsTheRegistryError(t *testing.T) {
+	server, registryClient := newTestRegistry(t, true)
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	_, err := registryClient.ResolveDigest(context.Background(), host+"/team/notebook:v2", nil)
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.Code)
+}
+
+func TestResolveDigestComputesTheDigestWithoutHeader(t *testing.T) {
+	manifest := []byte(`{"schemaVersion":2}`)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "alice" || password != "s3cret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(manifest)
+		}
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "https://")
+	registryClient := &Client{httpClient: server.Client()}
+
+	digest, err := registryClient.ResolveDigest(context.Background(), host+"/notebook:v1",
+		Credentials{host: {Username: "alice", Password: "s3cret"}})
+	require.NoError(t, err)
+	sum := sha256.Sum256(manifest)
+	assert.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), digest)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token", service="registry.docker.io",scope="repository:library/ubuntu:pull"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/ubuntu:pull",
+	}, params)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Credential is the username and password a registry is authenticated with
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Credentials holds the credentials of registries, by registry host
+type Credentials map[string]Credential
+
+// dockerConfigEntry is an entry of the auths of a docker config
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// dockerConfigJSON is the content of a kubernetes.io/dockerconfigjson Secret
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// CredentialsFromSecrets reads the registry credentials of image pull Secrets, of type
+// kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg. Secrets of other types are ignored;
+// when several Secrets hold credentials for a registry, the first one wins, as for the kubelet.
+func CredentialsFromSecrets(secrets []corev1.Secret) (Credentials, error) {
+	credentials := Credentials{}
+	for _, secret := range secrets {
+		var auths map[string]dockerConfigEntry
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson:
+			config := dockerConfigJSON{}
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+				return nil, fmt.Errorf("invalid docker config in secret %s: %w", secret.Name, err)
+			}
+			auths = config.Auths
+		case corev1.SecretTypeDockercfg:
+			if err := json.Unmarshal(secret.Data[corev1.DockerConfigKey], &auths); err != nil {
+				return nil, fmt.Errorf("invalid docker config in secret %s: %w", secret.Name, err)
+			}
+		default:
+			continue
+		}
+
+		for server, entry := range auths {
+			credential, err := entry.credential()
+			if err != nil {
+				return nil, fmt.Errorf("invalid credentials for %s in secret %s: %w", server, secret.Name, err)
+			}
+			host := registryHost(server)
+			if _, ok := credentials[host]; !ok {
+				credentials[host] = credential
+			}
+		}
+	}
+	return credentials, nil
+}
+
+// credential returns the username and password of the entry, decoding them from auth when set
+func (e dockerConfigEntry) credential() (Credential, error) {
+	if e.Auth == "" {
+		return Credential{Username: e.Username, Password: e.Password}, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(e.Auth)
+	if err != nil {
+		return Credential{}, fmt.Errorf("auth is not base64: %w", err)
+	}
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return Credential{}, fmt.Errorf("auth is not username:password")
+	}
+	return Credential{Username: username, Password: password}, nil
+}
+
+// registryHost returns the registry host of a docker config server, which may be a URL, e.g.
+// https://index.docker.io/v1/ for Docker Hub
+func registryHost(server string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(server, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	switch host {
+	case "index.docker.io", dockerHubAPIHost:
+		return DockerHub
+	}
+	return host
+}
+
+// For returns the credential of the registry of the reference, if any
+func (c Credentials) For(ref Reference) (Credential, bool) {
+	credential, ok := c[ref.Registry]
+	return credential, ok
+}
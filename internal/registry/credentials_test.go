@@ -0,0 +1,58 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPullSecret(name, config string) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Type:       corev1.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{corev1.DockerConfigJsonKey: []byte(config)},
+	}
+}
+
+func TestCredentialsFromSecretsReadTheDockerConfigs(t *testing.T) {
+	credentials, err := CredentialsFromSecrets([]corev1.Secret{
+		// alice:s3cret
+		newPullSecret("quay", `{"auths":{"quay.io":{"auth":"YWxpY2U6czNjcmV0"}}}`),
+		newPullSecret("hub", `{"auths":{"https://index.docker.io/v1/":{"username":"bob","password":"hunter2"},
+			"quay.io":{"username":"carol","password":"ignored"}}}`),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy"},
+			Type:       corev1.SecretTypeDockercfg,
+			Data:       map[string][]byte{corev1.DockerConfigKey: []byte(`{"registry.example.com:5000":{"username":"dave","password":"pw"}}`)},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "opaque"}, Data: map[string][]byte{"token": []byte("x")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{
+		"quay.io":                   {Username: "alice", Password: "s3cret"},
+		DockerHub:                   {Username: "bob", Password: "hunter2"},
+		"registry.example.com:5000": {Username: "dave", Password: "pw"},
+	}, credentials, "the first secret holding credentials for a registry wins")
+
+	ref, err := ParseReference("jupyter/base-notebook")
+	require.NoError(t, err)
+	credential, ok := credentials.For(ref)
+	assert.True(t, ok)
+	assert.Equal(t, "bob", credential.Username)
+}
+
+func TestCredentialsFromSecretsRejectInvalidConfigs(t *testing.T) {
+	_, err := CredentialsFromSecrets([]corev1.Secret{newPullSecret("broken", `{"auths":`)})
+	assert.ErrorContains(t, err, "invalid docker config in secret broken")
+
+	_, err = CredentialsFromSecrets([]corev1.Secret{newPullSecret("broken", `{"auths":{"quay.io":{"auth":"bm9jb2xvbg=="}}}`)})
+	assert.ErrorContains(t, err, "invalid credentials for quay.io in secret broken")
+}
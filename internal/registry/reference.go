@@ -0,0 +1,82 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package registry resolves image tags to digests through the Registry HTTP API V2
+package registry
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// DockerHub is the registry of image references that do not name one
+	DockerHub = "docker.io"
+
+	// dockerHubAPIHost serves the Registry HTTP API V2 of Docker Hub
+	dockerHubAPIHost = "registry-1.docker.io"
+
+	// defaultTag is the tag of image references that neither name a tag nor a digest
+	defaultTag = "latest"
+)
+
+// Reference is an image reference pointing at a tag
+type Reference struct {
+	// Registry is the host, and optional port, of the registry, e.g. quay.io or localhost:5000
+	Registry string
+
+	// Repository is the path of the image in the registry, e.g. jupyter/base-notebook
+	Repository string
+
+	// Tag of the image, latest when the reference does not name one
+	Tag string
+}
+
+// ParseReference parses an image reference the way the container runtime does: the first path component
+// is the registry when it looks like a host, otherwise the image is on Docker Hub, where images without
+// a namespace are in library/. References pinned by digest are rejected, they need no resolution.
+func ParseReference(image string) (Reference, error) {
+	if image == "" {
+		return Reference{}, fmt.Errorf("empty image reference")
+	}
+	if strings.Contains(image, "@") {
+		return Reference{}, fmt.Errorf("image %s is already pinned by digest", image)
+	}
+
+	ref := Reference{Registry: DockerHub, Tag: defaultTag}
+	name := image
+	// A colon after the last slash separates the tag; before it, it is the port of the registry
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		name, ref.Tag = image[:i], image[i+1:]
+		if ref.Tag == "" {
+			return Reference{}, fmt.Errorf("image %s has an empty tag", image)
+		}
+	}
+
+	if first, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.Registry, name = first, rest
+	}
+	if ref.Registry == DockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" {
+		return Reference{}, fmt.Errorf("image %s has no repository", image)
+	}
+	ref.Repository = name
+	return ref, nil
+}
+
+// String returns the reference in its canonical form
+func (r Reference) String() string {
+	return fmt.Sprintf("%s/%s:%s", r.Registry, r.Repository, r.Tag)
+}
+
+// apiHost returns the host serving the Registry HTTP API V2 of the registry
+func (r Reference) apiHost() string {
+	if r.Registry == DockerHub {
+		return dockerHubAPIHost
+	}
+	return r.Registry
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReferenceFollowsTheContainerRuntime(t *testing.T) {
+	tests := []struct {
+		image string
+		want  Reference
+	}{
+		{"ubuntu", Reference{Registry: DockerHub, Repository: "library/ubuntu", Tag: "latest"}},
+		{"jupyter/base-notebook:2024-01-01", Reference{Registry: DockerHub, Repository: "jupyter/base-notebook", Tag: "2024-01-01"}},
+		{"quay.io/jupyter/scipy-notebook:python-3.11", Reference{Registry: "quay.io", Repository: "jupyter/scipy-notebook", Tag: "python-3.11"}},
+		{"localhost:5000/notebook", Reference{Registry: "localhost:5000", Repository: "notebook", Tag: "latest"}},
+		{"localhost/org/notebook:v2", Reference{Registry: "localhost", Repository: "org/notebook", Tag: "v2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := ParseReference(tt.image)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ref)
+		})
+	}
+}
+
+func TestParseReferenceRejectsReferencesWithoutTagToResolve(t *testing.T) {
+	for _, image := range []string{"", "quay.io/jupyter/base-notebook@sha256:0123", "quay.io/jupyter/base-notebook:", "quay.io/"} {
+		_, err := ParseReference(image)
+		assert.Error(t, err, image)
+	}
+}
+
+func TestDockerHubIsServedByItsAPIHost(t *testing.T) {
+	ref, err := ParseReference("ubuntu:24.04")
+	require.NoError(t, err)
+	assert.Equal(t, "registry-1.docker.io", ref.apiHost())
+	assert.Equal(t, "docker.io/library/ubuntu:24.04", ref.String())
+}
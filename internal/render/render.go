@@ -0,0 +1,173 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package render builds the child resources of a workspace in memory, without creating anything.
+// It runs the same template defaulting, template validation and resource builders as the
+// webhook and the controller, so administrators can review what a workspace would produce.
+package render
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookv1alpha1 "github.com/jupyter-infra/jupyter-k8s/internal/webhook/v1alpha1"
+)
+
+// Options configures rendering
+type Options struct {
+	// Template is the template the workspace resolves to. It is required when the workspace
+	// references a template, and overrides the template reference when set.
+	Template *workspacev1alpha1.WorkspaceTemplate
+
+	// AccessStrategy is the access strategy the workspace resolves to, if any
+	AccessStrategy *workspacev1alpha1.WorkspaceAccessStrategy
+
+	// ControllerOptions are the controller settings the child resources are built with
+	ControllerOptions controller.WorkspaceControllerOptions
+}
+
+// Result holds the outcome of rendering a workspace
+type Result struct {
+	// Workspace is the workspace after defaulting
+	Workspace *workspacev1alpha1.Workspace
+
+	// Objects are the child resources the controller would create, in creation order
+	Objects []client.Object
+
+	// Warnings lists the template constraint violations the webhook would reject the workspace for
+	Warnings []string
+}
+
+// Render applies defaulting to the workspace and builds its child resources
+func Render(ctx context.Context, workspace *workspacev1alpha1.Workspace, opts Options) (*Result, error) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(workspacev1alpha1.AddToScheme(scheme))
+
+	ws := workspace.DeepCopy()
+	if ws.Namespace == "" {
+		ws.Namespace = "default"
+	}
+	result := &Result{Workspace: ws}
+
+	// Resolution and defaulting
+	if opts.Template != nil {
+		ws.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: opts.Template.Name, Namespace: opts.Template.Namespace}
+		webhookv1alpha1.ApplyTemplateDefaultsFrom(ws, opts.Template)
+		// The controller resolves the cluster access, accelerators, sidecars, isolation level, init containers,
+		// post-start script, scheduling defaults, server type, image pull secrets and shared volumes of the
+		// template for the deployment builder
+		ws.Status.ClusterAccess = opts.Template.Spec.ClusterAccess.DeepCopy()
+		ws.Status.Accelerators = opts.Template.Spec.Accelerators.DeepCopy()
+		ws.Status.Scheduling = controller.TemplateSchedulingDefaults(opts.Template)
+		ws.Status.ServerType = controller.TemplateServerType(opts.Template, ws)
+		ws.Status.ImagePullSecrets = slices.Clone(opts.Template.Spec.ImagePullSecrets)
+		ws.Status.SharedVolumes = slices.Clone(opts.Template.Spec.SharedVolumes)
+		ws.Status.SidecarResourceAccounting = opts.Template.Spec.SidecarResourceAccounting
+		ws.Status.IsolationLevel = controller.EffectiveIsolationLevel(opts.Template.Spec.IsolationLevel,
+			opts.ControllerOptions.UserNamespacesSupported)
+		if opts.Template.Spec.Lifecycle != nil {
+			ws.Status.PostStartScript = opts.Template.Spec.Lifecycle.PostStartScript
+		}
+		for i := range opts.Template.Spec.Sidecars {
+			ws.Status.Sidecars = append(ws.Status.Sidecars, *opts.Template.Spec.Sidecars[i].DeepCopy())
+		}
+		for i := range opts.Template.Spec.InitContainers {
+			ws.Status.InitContainers = append(ws.Status.InitContainers, *opts.Template.Spec.InitContainers[i].DeepCopy())
+		}
+		for _, violation := range webhookv1alpha1.ValidateWorkspaceAgainstTemplate(ws, opts.Template) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", violation.Field, violation.Message))
+		}
+	} else if ws.Spec.TemplateRef != nil && ws.Spec.TemplateRef.Name != "" {
+		return nil, fmt.Errorf("workspace references template %s but no template was provided", ws.Spec.TemplateRef.Name)
+	}
+	webhookv1alpha1.SetWorkspaceSharingDefaults(ws)
+
+	// Child resources, in the order the controller creates them
+	if controller.StorageModeOf(ws) == workspacev1alpha1.StorageModePersistent {
+		pvc, err := controller.NewPVCBuilder(scheme).BuildPVC(ws)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build persistent volume claim: %w", err)
+		}
+		result.Objects = append(result.Objects, pvc)
+	}
+
+	if ws.Status.PostStartScript != "" {
+		configMap, err := controller.BuildPostStartConfigMap(ws, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build post-start ConfigMap: %w", err)
+		}
+		result.Objects = append(result.Objects, configMap)
+	}
+
+	deployment, err := controller.NewDeploymentBuilder(scheme, opts.ControllerOptions).
+		BuildDeploymentWithAccessStrategy(ctx, ws, opts.AccessStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build deployment: %w", err)
+	}
+	result.Objects = append(result.Objects, deployment)
+
+	service, err := controller.NewServiceBuilder(scheme).BuildService(ws)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build service: %w", err)
+	}
+	result.Objects = append(result.Objects, service)
+
+	if opts.AccessStrategy != nil {
+		accessResourcesBuilder := controller.NewAccessResourcesBuilder()
+		for _, resourceTemplate := range opts.AccessStrategy.Spec.AccessResourceTemplates {
+			obj, err := accessResourcesBuilder.BuildUnstructuredResource(resourceTemplate, ws, opts.AccessStrategy, service)
+			if err != nil {
+				return nil, fmt.Errorf("failed to build access resource %s: %w", resourceTemplate.Kind, err)
+			}
+			if err := controllerutil.SetControllerReference(ws, obj, scheme); err != nil {
+				return nil, fmt.Errorf("failed to set controller reference: %w", err)
+			}
+			result.Objects = append(result.Objects, obj)
+		}
+	}
+
+	// Builders leave the type metadata empty; set it so the output is a valid manifest
+	for _, obj := range result.Objects {
+		if !obj.GetObjectKind().GroupVersionKind().Empty() {
+			continue
+		}
+		gvk, err := apiutil.GVKForObject(obj, scheme)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve kind of %s: %w", obj.GetName(), err)
+		}
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	return result, nil
+}
+
+// EncodeYAML serializes the rendered objects as a multi-document YAML stream
+func EncodeYAML(objects []client.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", obj.GetName(), err)
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,281 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package render
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/inventory"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// loadOptional decodes testdata/<dir>/<name> into obj, returning false when the file does not exist
+func loadOptional(t *testing.T, dir, name string, obj any) bool {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if os.IsNotExist(err) {
+		return false
+	}
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, obj))
+	return true
+}
+
+// TestRenderGolden renders each testdata case and compares it to expected.yaml.
+// Run with -update to regenerate the golden files after an intended change.
+func TestRenderGolden(t *testing.T) {
+	cases, err := os.ReadDir("testdata")
+	require.NoError(t, err)
+
+	for _, c := range cases {
+		if !c.IsDir() {
+			continue
+		}
+		t.Run(c.Name(), func(t *testing.T) {
+			dir := filepath.Join("testdata", c.Name())
+
+			workspace := &workspacev1alpha1.Workspace{}
+			require.True(t, loadOptional(t, dir, "workspace.yaml", workspace))
+
+			opts := Options{
+				ControllerOptions: controller.WorkspaceControllerOptions{
+					ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+					UserNamespacesSupported:     true,
+				},
+			}
+			template := &workspacev1alpha1.WorkspaceTemplate{}
+			if loadOptional(t, dir, "template.yaml", template) {
+				opts.Template = template
+			}
+			accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+			if loadOptional(t, dir, "accessstrategy.yaml", accessStrategy) {
+				opts.AccessStrategy = accessStrategy
+			}
+
+			result, err := Render(context.Background(), workspace, opts)
+			require.NoError(t, err)
+			assert.Empty(t, result.Warnings)
+
+			actual, err := EncodeYAML(result.Objects)
+			require.NoError(t, err)
+
+			goldenPath := filepath.Join(dir, "expected.yaml")
+			if *update {
+				require.NoError(t, os.WriteFile(goldenPath, actual, 0o644))
+			}
+			expected, err := os.ReadFile(goldenPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestRenderDoesNotMutateInput(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws"},
+		Spec:       workspacev1alpha1.WorkspaceSpec{Image: "jupyter/base-notebook:latest"},
+	}
+	original := workspace.DeepCopy()
+
+	result, err := Render(context.Background(), workspace, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, original, workspace)
+	assert.Equal(t, "default", result.Workspace.Namespace)
+	assert.NotEmpty(t, result.Workspace.Spec.OwnershipType)
+}
+
+func TestRenderRequiresReferencedTemplate(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			TemplateRef: &workspacev1alpha1.TemplateRef{Name: "missing"},
+		},
+	}
+
+	_, err := Render(context.Background(), workspace, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing")
+}
+
+func TestRenderReportsTemplateViolations(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Spec:       workspacev1alpha1.WorkspaceSpec{Image: "not/allowed:1.0"},
+	}
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:   "Restricted",
+			DefaultImage:  "allowed/image:1.0",
+			AllowedImages: []string{"allowed/image:1.0"},
+		},
+	}
+
+	result, err := Render(context.Background(), workspace, Options{Template: template})
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "spec.image")
+}
+
+// podRequests returns the requests the containers of the pod total, a resource a container only limits
+// counting at its limit
+func podRequests(podSpec corev1.PodSpec) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		requests := corev1.ResourceList{}
+		for name, limit := range container.Resources.Limits {
+			requests[name] = limit
+		}
+		for name, request := range container.Resources.Requests {
+			requests[name] = request
+		}
+		for name, quantity := range requests {
+			sum := total[name]
+			sum.Add(quantity)
+			total[name] = sum
+		}
+	}
+	return total
+}
+
+// TestSidecarResourceAccountingAgrees locks in that the pod the deployment builder renders, the template
+// webhook and the inventory account for the resources of the sidecars alike
+func TestSidecarResourceAccountingAgrees(t *testing.T) {
+	sidecars := []corev1.Container{
+		{
+			Name:  "oauth-proxy",
+			Image: "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")},
+			},
+		},
+		{
+			// Requested at its limit, as the scheduler counts it
+			Name:  "telemetry",
+			Image: "otel/opentelemetry-collector:0.100.0",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("400m"), corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+	}
+	requested := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	for name, tc := range map[string]struct {
+		accounting       workspacev1alpha1.SidecarResourceAccounting
+		minimum          corev1.ResourceList
+		expectedPrimary  corev1.ResourceList
+		expectedPod      corev1.ResourceList
+		expectedWarnings int
+	}{
+		"additive": {
+			accounting:      workspacev1alpha1.SidecarResourceAccountingAdditive,
+			expectedPrimary: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+			expectedPod:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2500m"), corev1.ResourceMemory: resource.MustParse("4480Mi")},
+		},
+		"carved": {
+			accounting:      workspacev1alpha1.SidecarResourceAccountingCarved,
+			minimum:         corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			expectedPrimary: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m"), corev1.ResourceMemory: resource.MustParse("3712Mi")},
+			expectedPod:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		},
+		"carved below the minimum": {
+			accounting:       workspacev1alpha1.SidecarResourceAccountingCarved,
+			minimum:          corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1800m")},
+			expectedPrimary:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m"), corev1.ResourceMemory: resource.MustParse("3712Mi")},
+			expectedPod:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+			expectedWarnings: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			workspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+				Spec:       workspacev1alpha1.WorkspaceSpec{Image: "jupyter/base-notebook:latest", Resources: requested.DeepCopy()},
+			}
+			template := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "sidecars", Namespace: "default"},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DisplayName:                  "Sidecars",
+					DefaultImage:                 "jupyter/base-notebook:latest",
+					AllowedImages:                []string{"jupyter/base-notebook:latest"},
+					Sidecars:                     sidecars,
+					SidecarResourceAccounting:    tc.accounting,
+					MinPrimaryContainerResources: tc.minimum,
+				},
+			}
+
+			result, err := Render(context.Background(), workspace, Options{Template: template})
+			require.NoError(t, err)
+			assert.Len(t, result.Warnings, tc.expectedWarnings)
+
+			var podSpec *corev1.PodSpec
+			for _, object := range result.Objects {
+				if deployment, ok := object.(*appsv1.Deployment); ok {
+					podSpec = &deployment.Spec.Template.Spec
+				}
+			}
+			require.NotNil(t, podSpec)
+			assertQuantitiesEqual(t, tc.expectedPrimary, podSpec.Containers[0].Resources.Requests)
+			assertQuantitiesEqual(t, tc.expectedPod, podRequests(*podSpec))
+
+			// The inventory lists the workspace once the API server stored it
+			result.Workspace.ResourceVersion = "1"
+			page, err := inventory.BuildPage([]*workspacev1alpha1.Workspace{result.Workspace}, inventory.Query{})
+			require.NoError(t, err)
+			require.Len(t, page.Items, 1)
+			assertQuantitiesEqual(t, tc.expectedPod, page.Items[0].PodRequests)
+		})
+	}
+}
+
+// assertQuantitiesEqual asserts the lists hold the same quantities, however they are formatted
+func assertQuantitiesEqual(t *testing.T, expected, actual corev1.ResourceList) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for name, quantity := range expected {
+		assert.Zero(t, quantity.Cmp(actual[name]), "%s: expected %s, got %s", name, quantity.String(), actual.Name(name, "").String())
+	}
+}
+
+func TestRenderIncludesThePostStartConfigMap(t *testing.T) {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+		Spec:       workspacev1alpha1.WorkspaceSpec{Image: "jupyter/base-notebook:latest"},
+	}
+	template := &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "conda", Namespace: "default"},
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DisplayName:  "Conda",
+			DefaultImage: "jupyter/base-notebook:latest",
+			Lifecycle:    &workspacev1alpha1.TemplateLifecycleSpec{PostStartScript: "pip config set global.index-url https://pypi.internal"},
+		},
+	}
+
+	result, err := Render(context.Background(), workspace, Options{Template: template})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Objects)
+	configMap, ok := result.Objects[0].(*corev1.ConfigMap)
+	require.True(t, ok, "the post-start ConfigMap is created before the deployment")
+	assert.Equal(t, template.Spec.Lifecycle.PostStartScript, configMap.Data[controller.PostStartScriptKey])
+}
@@ -0,0 +1,210 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package rightsizing summarizes the observed resource usage of workspaces and derives resource
+// recommendations from it.
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// maxDecayExponent bounds the growth of the weights of new samples before the histogram moves its
+	// reference time forward, keeping the weights far from the float64 limits
+	maxDecayExponent = 100
+
+	// checkpointMaxWeight is the weight of the heaviest bucket in a checkpoint; the other buckets are
+	// scaled to it, so that checkpoints store small integers whatever the decay
+	checkpointMaxWeight = 10000
+)
+
+// HistogramOptions describes the buckets of a Histogram. Bucket sizes grow exponentially: bucket i
+// starts at FirstBucketSize * (Ratio^i - 1) / (Ratio - 1), so that the relative precision is the
+// same for small and large values.
+type HistogramOptions struct {
+	// FirstBucketSize is the size of the first bucket, in the unit of the samples
+	FirstBucketSize float64
+	// Ratio is the size ratio of consecutive buckets, greater than 1
+	Ratio float64
+	// MaxValue is the smallest value the last bucket holds; larger samples fall in the last bucket
+	MaxValue float64
+}
+
+// CPUHistogramOptions buckets CPU usage, in cores, from 10m to 1000 cores with a 5% precision
+var CPUHistogramOptions = HistogramOptions{FirstBucketSize: 0.01, Ratio: 1.05, MaxValue: 1000}
+
+// MemoryHistogramOptions buckets memory usage, in bytes, from 10MB to 1TB with a 5% precision
+var MemoryHistogramOptions = HistogramOptions{FirstBucketSize: 1e7, Ratio: 1.05, MaxValue: 1e12}
+
+// numBuckets returns the number of buckets needed to hold MaxValue
+func (o HistogramOptions) numBuckets() int {
+	return o.bucketFor(o.MaxValue) + 1
+}
+
+// bucketFor returns the index of the bucket holding the value, without bound
+func (o HistogramOptions) bucketFor(value float64) int {
+	if value < o.FirstBucketSize {
+		return 0
+	}
+	return int(math.Floor(math.Log(value*(o.Ratio-1)/o.FirstBucketSize+1) / math.Log(o.Ratio)))
+}
+
+// bucketStart returns the smallest value of the bucket
+func (o HistogramOptions) bucketStart(bucket int) float64 {
+	return o.FirstBucketSize * (math.Pow(o.Ratio, float64(bucket)) - 1) / (o.Ratio - 1)
+}
+
+// validate checks that the options describe increasing buckets
+func (o HistogramOptions) validate() error {
+	if o.FirstBucketSize <= 0 || o.Ratio <= 1 || o.MaxValue < o.FirstBucketSize {
+		return fmt.Errorf("invalid histogram options: first bucket size %g, ratio %g, max value %g",
+			o.FirstBucketSize, o.Ratio, o.MaxValue)
+	}
+	return nil
+}
+
+// Histogram is a histogram of samples whose weights halve every half-life: the percentiles it
+// reports follow the recent usage, while older samples fade out rather than drop off a window edge.
+// The weights are relative to a reference time, moved forward as samples come in.
+type Histogram struct {
+	options  HistogramOptions
+	halfLife time.Duration
+
+	weights       []float64
+	totalWeight   float64
+	referenceTime time.Time
+}
+
+// NewHistogram creates an empty Histogram whose sample weights halve every halfLife
+func NewHistogram(options HistogramOptions, halfLife time.Duration) (*Histogram, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	if halfLife <= 0 {
+		return nil, fmt.Errorf("invalid histogram half-life %s", halfLife)
+	}
+	return &Histogram{
+		options:  options,
+		halfLife: halfLife,
+		weights:  make([]float64, options.numBuckets()),
+	}, nil
+}
+
+// IsEmpty returns true if the histogram holds no sample
+func (h *Histogram) IsEmpty() bool {
+	return h.totalWeight <= 0
+}
+
+// AddSample adds a sample of the weight taken at the time. Negative values count as zero.
+func (h *Histogram) AddSample(value, weight float64, at time.Time) {
+	if weight <= 0 {
+		return
+	}
+	if h.IsEmpty() {
+		h.referenceTime = at
+	}
+	if h.decayExponent(at) > maxDecayExponent {
+		h.shiftReferenceTime(at)
+	}
+
+	bucket := min(h.options.bucketFor(max(value, 0)), len(h.weights)-1)
+	decayed := weight * math.Exp2(h.decayExponent(at))
+	h.weights[bucket] += decayed
+	h.totalWeight += decayed
+}
+
+// Percentile returns the upper bound of the bucket holding the percentile, between 0 and 1, of the
+// samples; it is 0 when the histogram is empty. Reporting the bucket end errs on the side of more
+// resources.
+func (h *Histogram) Percentile(percentile float64) float64 {
+	if h.IsEmpty() {
+		return 0
+	}
+	threshold := min(max(percentile, 0), 1) * h.totalWeight
+	cumulative := 0.0
+	last := 0
+	for bucket, weight := range h.weights {
+		if weight <= 0 {
+			continue
+		}
+		cumulative += weight
+		last = bucket
+		if cumulative >= threshold {
+			break
+		}
+	}
+	return h.options.bucketStart(last + 1)
+}
+
+// decayExponent returns the exponent of the weight multiplier of samples taken at the time
+func (h *Histogram) decayExponent(at time.Time) float64 {
+	return float64(at.Sub(h.referenceTime)) / float64(h.halfLife)
+}
+
+// shiftReferenceTime moves the reference time to the time, scaling the weights to it
+func (h *Histogram) shiftReferenceTime(at time.Time) {
+	factor := math.Exp2(-h.decayExponent(at))
+	h.totalWeight = 0
+	for bucket := range h.weights {
+		h.weights[bucket] *= factor
+		h.totalWeight += h.weights[bucket]
+	}
+	h.referenceTime = at
+}
+
+// HistogramCheckpoint is the serializable state of a Histogram. Bucket weights are scaled so that the
+// heaviest bucket weighs checkpointMaxWeight; buckets too light to register are dropped.
+type HistogramCheckpoint struct {
+	ReferenceTime time.Time      `json:"referenceTime"`
+	TotalWeight   float64        `json:"totalWeight"`
+	BucketWeights map[int]uint32 `json:"bucketWeights,omitempty"`
+}
+
+// Checkpoint returns the state of the histogram
+func (h *Histogram) Checkpoint() HistogramCheckpoint {
+	checkpoint := HistogramCheckpoint{ReferenceTime: h.referenceTime, BucketWeights: map[int]uint32{}}
+	maxWeight := 0.0
+	for _, weight := range h.weights {
+		maxWeight = max(maxWeight, weight)
+	}
+	if maxWeight <= 0 {
+		return checkpoint
+	}
+	checkpoint.TotalWeight = h.totalWeight
+	for bucket, weight := range h.weights {
+		if scaled := uint32(math.Round(weight / maxWeight * checkpointMaxWeight)); scaled > 0 {
+			checkpoint.BucketWeights[bucket] = scaled
+		}
+	}
+	return checkpoint
+}
+
+// LoadCheckpoint replaces the state of the histogram with the checkpoint
+func (h *Histogram) LoadCheckpoint(checkpoint HistogramCheckpoint) error {
+	weights := make([]float64, len(h.weights))
+	sum := 0.0
+	for bucket, weight := range checkpoint.BucketWeights {
+		if bucket < 0 || bucket >= len(weights) {
+			return fmt.Errorf("checkpoint bucket %d out of range [0, %d)", bucket, len(weights))
+		}
+		weights[bucket] = float64(weight)
+		sum += float64(weight)
+	}
+
+	h.weights = weights
+	h.totalWeight = 0
+	h.referenceTime = checkpoint.ReferenceTime
+	if sum <= 0 || checkpoint.TotalWeight <= 0 {
+		return nil
+	}
+	for bucket := range h.weights {
+		h.weights[bucket] *= checkpoint.TotalWeight / sum
+		h.totalWeight += h.weights[bucket]
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package rightsizing
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var histogramTestStart = time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestCPUHistogram(t *testing.T) *Histogram {
+	histogram, err := NewHistogram(CPUHistogramOptions, DefaultHalfLife)
+	require.NoError(t, err)
+	return histogram
+}
+
+func TestHistogramBucketsGrowExponentially(t *testing.T) {
+	options := CPUHistogramOptions
+	assert.Equal(t, 0, options.bucketFor(0))
+	assert.Equal(t, 0, options.bucketFor(options.FirstBucketSize/2))
+	assert.Equal(t, 1, options.bucketFor(options.FirstBucketSize))
+	for bucket := 1; bucket < options.numBuckets(); bucket++ {
+		start := options.bucketStart(bucket)
+		// Float rounding may land the exact start in the previous bucket; just above it must not
+		assert.Equal(t, bucket, options.bucketFor(start*(1+1e-9)), "bucket %d", bucket)
+		size := options.bucketStart(bucket+1) - start
+		assert.InDelta(t, options.FirstBucketSize*math.Pow(options.Ratio, float64(bucket)), size, 1e-9)
+	}
+	assert.GreaterOrEqual(t, options.bucketStart(options.numBuckets()), options.MaxValue)
+}
+
+func TestNewHistogramRejectsInvalidOptions(t *testing.T) {
+	_, err := NewHistogram(HistogramOptions{FirstBucketSize: 1, Ratio: 1, MaxValue: 10}, time.Hour)
+	assert.Error(t, err)
+	_, err = NewHistogram(HistogramOptions{FirstBucketSize: 0, Ratio: 2, MaxValue: 10}, time.Hour)
+	assert.Error(t, err)
+	_, err = NewHistogram(CPUHistogramOptions, 0)
+	assert.Error(t, err)
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	assert.True(t, histogram.IsEmpty())
+	assert.Zero(t, histogram.Percentile(0.95))
+
+	// 1..100 hundredths of a core, all at once
+	for i := 1; i <= 100; i++ {
+		histogram.AddSample(float64(i)/100, 1, histogramTestStart)
+	}
+	assert.False(t, histogram.IsEmpty())
+
+	for _, percentile := range []float64{0.5, 0.9, 0.95, 0.99} {
+		got := histogram.Percentile(percentile)
+		assert.GreaterOrEqual(t, got, percentile, "percentile %g never under-reports", percentile)
+		assert.InEpsilon(t, percentile, got, 0.1, "percentile %g is within the bucket precision", percentile)
+	}
+	assert.GreaterOrEqual(t, histogram.Percentile(1), 1.0)
+	assert.Equal(t, histogram.Percentile(1), histogram.Percentile(2), "percentiles are clamped")
+}
+
+func TestHistogramIgnoresSamplesWithoutWeight(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	histogram.AddSample(1, 0, histogramTestStart)
+	assert.True(t, histogram.IsEmpty())
+
+	histogram.AddSample(-1, 1, histogramTestStart)
+	assert.Equal(t, CPUHistogramOptions.bucketStart(1), histogram.Percentile(1), "negative usage counts as zero")
+
+	histogram.AddSample(5000, 1, histogramTestStart)
+	assert.Equal(t, CPUHistogramOptions.bucketStart(CPUHistogramOptions.numBuckets()), histogram.Percentile(1),
+		"usage beyond the last bucket lands in it")
+}
+
+func TestHistogramFavoursRecentSamples(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	for i := 0; i < 100; i++ {
+		histogram.AddSample(2, 1, histogramTestStart)
+	}
+	// Ten half-lives later, the same number of samples weighs 1024 times more
+	later := histogramTestStart.Add(10 * DefaultHalfLife)
+	for i := 0; i < 100; i++ {
+		histogram.AddSample(0.2, 1, later)
+	}
+
+	assert.InEpsilon(t, 0.2, histogram.Percentile(0.95), 0.1)
+	assert.InEpsilon(t, 2, histogram.Percentile(1), 0.1, "old samples fade out without being dropped")
+}
+
+func TestHistogramMovesItsReferenceTimeForward(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	histogram.AddSample(2, 1, histogramTestStart)
+
+	// Far beyond the decay exponent bound, the weights would overflow without a new reference time
+	muchLater := histogramTestStart.Add(10 * maxDecayExponent * DefaultHalfLife)
+	for i := 0; i < 10; i++ {
+		histogram.AddSample(0.5, 1, muchLater)
+	}
+
+	assert.Equal(t, muchLater, histogram.referenceTime)
+	assert.False(t, math.IsInf(histogram.totalWeight, 0))
+	assert.InDelta(t, 10, histogram.totalWeight, 1e-6)
+	assert.InEpsilon(t, 0.5, histogram.Percentile(1), 0.1)
+}
+
+func TestHistogramCheckpointRoundTrip(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	for i := 0; i < 1000; i++ {
+		at := histogramTestStart.Add(time.Duration(i) * time.Minute)
+		histogram.AddSample(0.1+float64(i%50)/100, 1, at)
+	}
+
+	data, err := json.Marshal(histogram.Checkpoint())
+	require.NoError(t, err)
+	var checkpoint HistogramCheckpoint
+	require.NoError(t, json.Unmarshal(data, &checkpoint))
+
+	restored := newTestCPUHistogram(t)
+	require.NoError(t, restored.LoadCheckpoint(checkpoint))
+	assert.Equal(t, histogram.referenceTime, restored.referenceTime)
+	assert.InEpsilon(t, histogram.totalWeight, restored.totalWeight, 1e-9)
+	for _, percentile := range []float64{0.5, 0.95, 0.99} {
+		assert.Equal(t, histogram.Percentile(percentile), restored.Percentile(percentile), "percentile %g", percentile)
+	}
+}
+
+func TestHistogramCheckpointOfEmptyHistogram(t *testing.T) {
+	checkpoint := newTestCPUHistogram(t).Checkpoint()
+	assert.Empty(t, checkpoint.BucketWeights)
+
+	restored := newTestCPUHistogram(t)
+	require.NoError(t, restored.LoadCheckpoint(checkpoint))
+	assert.True(t, restored.IsEmpty())
+}
+
+func TestHistogramRejectsCheckpointsOutOfRange(t *testing.T) {
+	histogram := newTestCPUHistogram(t)
+	histogram.AddSample(1, 1, histogramTestStart)
+
+	err := histogram.LoadCheckpoint(HistogramCheckpoint{TotalWeight: 1, BucketWeights: map[int]uint32{100000: 1}})
+	assert.Error(t, err)
+	assert.False(t, histogram.IsEmpty(), "a rejected checkpoint leaves the histogram untouched")
+}
@@ -0,0 +1,177 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package rightsizing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultHalfLife is the time after which a usage sample weighs half as much as a new one
+const DefaultHalfLife = 24 * time.Hour
+
+// UsageHistory summarizes the CPU and memory usage observed for a workspace container
+type UsageHistory struct {
+	// CPU holds the CPU usage samples, in cores
+	CPU *Histogram
+	// Memory holds the memory usage samples, in bytes
+	Memory *Histogram
+
+	// FirstSampleTime and LastSampleTime bound the observation period
+	FirstSampleTime time.Time
+	LastSampleTime  time.Time
+	// SampleCount is the number of samples added
+	SampleCount int64
+}
+
+// NewUsageHistory creates an empty UsageHistory whose samples fade with the half-life
+func NewUsageHistory(halfLife time.Duration) (*UsageHistory, error) {
+	cpu, err := NewHistogram(CPUHistogramOptions, halfLife)
+	if err != nil {
+		return nil, err
+	}
+	memory, err := NewHistogram(MemoryHistogramOptions, halfLife)
+	if err != nil {
+		return nil, err
+	}
+	return &UsageHistory{CPU: cpu, Memory: memory}, nil
+}
+
+// AddSample adds the usage observed at the time. Samples not newer than the last one are ignored,
+// so that the same metrics-server window is not counted twice.
+func (u *UsageHistory) AddSample(cpuCores, memoryBytes float64, at time.Time) bool {
+	if !u.LastSampleTime.IsZero() && !at.After(u.LastSampleTime) {
+		return false
+	}
+	u.CPU.AddSample(cpuCores, 1, at)
+	u.Memory.AddSample(memoryBytes, 1, at)
+	if u.FirstSampleTime.IsZero() {
+		u.FirstSampleTime = at
+	}
+	u.LastSampleTime = at
+	u.SampleCount++
+	return true
+}
+
+// ObservedFor returns the length of the observation period
+func (u *UsageHistory) ObservedFor() time.Duration {
+	if u.FirstSampleTime.IsZero() {
+		return 0
+	}
+	return u.LastSampleTime.Sub(u.FirstSampleTime)
+}
+
+// UsageCheckpoint is the serializable state of a UsageHistory
+type UsageCheckpoint struct {
+	CPU             HistogramCheckpoint `json:"cpu"`
+	Memory          HistogramCheckpoint `json:"memory"`
+	FirstSampleTime time.Time           `json:"firstSampleTime"`
+	LastSampleTime  time.Time           `json:"lastSampleTime"`
+	SampleCount     int64               `json:"sampleCount"`
+}
+
+// Checkpoint returns the state of the usage history
+func (u *UsageHistory) Checkpoint() UsageCheckpoint {
+	return UsageCheckpoint{
+		CPU:             u.CPU.Checkpoint(),
+		Memory:          u.Memory.Checkpoint(),
+		FirstSampleTime: u.FirstSampleTime,
+		LastSampleTime:  u.LastSampleTime,
+		SampleCount:     u.SampleCount,
+	}
+}
+
+// LoadCheckpoint replaces the state of the usage history with the checkpoint
+func (u *UsageHistory) LoadCheckpoint(checkpoint UsageCheckpoint) error {
+	if err := u.CPU.LoadCheckpoint(checkpoint.CPU); err != nil {
+		return fmt.Errorf("invalid CPU checkpoint: %w", err)
+	}
+	if err := u.Memory.LoadCheckpoint(checkpoint.Memory); err != nil {
+		return fmt.Errorf("invalid memory checkpoint: %w", err)
+	}
+	u.FirstSampleTime = checkpoint.FirstSampleTime
+	u.LastSampleTime = checkpoint.LastSampleTime
+	u.SampleCount = checkpoint.SampleCount
+	return nil
+}
+
+// Policy derives resource recommendations from a usage history
+type Policy struct {
+	// RequestPercentile is the usage percentile, between 0 and 1, the recommended request covers
+	RequestPercentile float64
+	// LimitPercentile is the usage percentile, between RequestPercentile and 1, the recommended limit covers
+	LimitPercentile float64
+	// Headroom is the fraction added on top of the usage percentiles
+	Headroom float64
+	// MinHistory is the observation period required before recommending anything
+	MinHistory time.Duration
+	// MinCPU and MinMemory are the smallest recommendations, in cores and bytes
+	MinCPU    float64
+	MinMemory float64
+}
+
+// DefaultPolicy recommends the p95 of the usage plus 15% as request and its p99 plus 15% as limit,
+// after a week of observation
+var DefaultPolicy = Policy{
+	RequestPercentile: 0.95,
+	LimitPercentile:   0.99,
+	Headroom:          0.15,
+	MinHistory:        7 * 24 * time.Hour,
+	MinCPU:            0.01,
+	MinMemory:         64 * 1024 * 1024,
+}
+
+// Validate checks the consistency of the policy
+func (p Policy) Validate() error {
+	if p.RequestPercentile <= 0 || p.RequestPercentile > 1 {
+		return fmt.Errorf("request percentile %g must be in (0, 1]", p.RequestPercentile)
+	}
+	if p.LimitPercentile < p.RequestPercentile || p.LimitPercentile > 1 {
+		return fmt.Errorf("limit percentile %g must be in [%g, 1]", p.LimitPercentile, p.RequestPercentile)
+	}
+	if p.Headroom < 0 {
+		return fmt.Errorf("headroom %g must not be negative", p.Headroom)
+	}
+	if p.MinHistory < 0 {
+		return fmt.Errorf("minimum history %s must not be negative", p.MinHistory)
+	}
+	return nil
+}
+
+// Recommendation is a recommended request and limit for a resource, in the unit of its samples
+type Recommendation struct {
+	// Usage is the usage at the request percentile
+	Usage   float64
+	Request float64
+	Limit   float64
+}
+
+// Recommend returns the CPU and memory recommendations, and false while the history is shorter than
+// MinHistory
+func (p Policy) Recommend(history *UsageHistory) (cpu, memory Recommendation, ok bool) {
+	if history.SampleCount == 0 || history.ObservedFor() < p.MinHistory {
+		return Recommendation{}, Recommendation{}, false
+	}
+	return p.recommend(history.CPU, p.MinCPU), p.recommend(history.Memory, p.MinMemory), true
+}
+
+func (p Policy) recommend(histogram *Histogram, minimum float64) Recommendation {
+	usage := histogram.Percentile(p.RequestPercentile)
+	request := max(usage*(1+p.Headroom), minimum)
+	limit := max(histogram.Percentile(p.LimitPercentile)*(1+p.Headroom), request)
+	return Recommendation{Usage: usage, Request: request, Limit: limit}
+}
+
+// RelativeGap returns how far the requested amount is from the recommended one, relative to the
+// requested amount: 0.5 means the request is 50% over or under the recommendation. A missing request
+// has no gap.
+func RelativeGap(requested, recommended float64) float64 {
+	if requested <= 0 {
+		return 0
+	}
+	return math.Abs(requested-recommended) / requested
+}
@@ -0,0 +1,141 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package rightsizing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func newTestUsageHistory(t *testing.T) *UsageHistory {
+	history, err := NewUsageHistory(DefaultHalfLife)
+	require.NoError(t, err)
+	return history
+}
+
+// addDailyUsage samples the usage every 10 minutes for the days, with a busy hour a day
+func addDailyUsage(history *UsageHistory, days int, idleCPU, busyCPU, idleMemory, busyMemory float64) {
+	for at := histogramTestStart; at.Before(histogramTestStart.Add(time.Duration(days) * 24 * time.Hour)); at = at.Add(10 * time.Minute) {
+		if at.Hour() == 14 {
+			history.AddSample(busyCPU, busyMemory, at)
+		} else {
+			history.AddSample(idleCPU, idleMemory, at)
+		}
+	}
+}
+
+func TestUsageHistoryIgnoresRepeatedSamples(t *testing.T) {
+	history := newTestUsageHistory(t)
+	assert.Zero(t, history.ObservedFor())
+
+	assert.True(t, history.AddSample(1, gib, histogramTestStart))
+	assert.False(t, history.AddSample(2, gib, histogramTestStart), "the same metrics window")
+	assert.False(t, history.AddSample(2, gib, histogramTestStart.Add(-time.Minute)), "an older metrics window")
+	assert.True(t, history.AddSample(2, gib, histogramTestStart.Add(time.Minute)))
+
+	assert.Equal(t, int64(2), history.SampleCount)
+	assert.Equal(t, time.Minute, history.ObservedFor())
+}
+
+func TestUsageHistoryCheckpointRoundTrip(t *testing.T) {
+	history := newTestUsageHistory(t)
+	addDailyUsage(history, 2, 0.2, 1.5, gib, 3*gib)
+
+	restored := newTestUsageHistory(t)
+	require.NoError(t, restored.LoadCheckpoint(history.Checkpoint()))
+	assert.Equal(t, history.FirstSampleTime, restored.FirstSampleTime)
+	assert.Equal(t, history.LastSampleTime, restored.LastSampleTime)
+	assert.Equal(t, history.SampleCount, restored.SampleCount)
+
+	policy := DefaultPolicy
+	policy.MinHistory = 0
+	cpu, memory, _ := policy.Recommend(history)
+	restoredCPU, restoredMemory, _ := policy.Recommend(restored)
+	assert.Equal(t, cpu, restoredCPU)
+	assert.Equal(t, memory, restoredMemory)
+}
+
+func TestRecommendWaitsForTheMinimumHistory(t *testing.T) {
+	history := newTestUsageHistory(t)
+	_, _, ok := DefaultPolicy.Recommend(history)
+	assert.False(t, ok, "nothing observed")
+
+	addDailyUsage(history, 6, 0.2, 1.5, gib, 3*gib)
+	_, _, ok = DefaultPolicy.Recommend(history)
+	assert.False(t, ok, "six days of a one week policy")
+
+	addDailyUsage(history, 8, 0.2, 1.5, gib, 3*gib)
+	_, _, ok = DefaultPolicy.Recommend(history)
+	assert.True(t, ok)
+}
+
+func TestRecommendAddsHeadroomToThePercentiles(t *testing.T) {
+	history := newTestUsageHistory(t)
+	// One busy hour a day is 1/24th of the samples: beyond the p95, within the p99
+	addDailyUsage(history, 8, 0.2, 1.5, gib, 3*gib)
+
+	cpu, memory, ok := DefaultPolicy.Recommend(history)
+	require.True(t, ok)
+
+	assert.InEpsilon(t, 0.2, cpu.Usage, 0.1)
+	assert.InEpsilon(t, cpu.Usage*1.15, cpu.Request, 1e-9)
+	assert.InEpsilon(t, 1.5*1.15, cpu.Limit, 0.1)
+
+	assert.InEpsilon(t, float64(gib), memory.Usage, 0.1)
+	assert.GreaterOrEqual(t, memory.Usage, float64(gib))
+	assert.InEpsilon(t, memory.Usage*1.15, memory.Request, 1e-9)
+	assert.InEpsilon(t, 3*gib*1.15, memory.Limit, 0.1)
+}
+
+func TestRecommendRespectsTheMinimums(t *testing.T) {
+	history := newTestUsageHistory(t)
+	addDailyUsage(history, 8, 0, 0, 0, 0)
+
+	cpu, memory, ok := DefaultPolicy.Recommend(history)
+	require.True(t, ok)
+	// Idle usage is reported as the end of the first bucket
+	assert.InDelta(t, CPUHistogramOptions.FirstBucketSize*1.15, cpu.Request, 1e-9)
+	assert.Equal(t, DefaultPolicy.MinMemory, memory.Request, "the first memory bucket is below the minimum")
+
+	policy := DefaultPolicy
+	policy.MinCPU = 0.1
+	cpu, _, _ = policy.Recommend(history)
+	assert.Equal(t, 0.1, cpu.Request)
+	assert.GreaterOrEqual(t, cpu.Limit, cpu.Request)
+	assert.GreaterOrEqual(t, memory.Limit, memory.Request)
+}
+
+func TestPolicyValidate(t *testing.T) {
+	assert.NoError(t, DefaultPolicy.Validate())
+
+	tests := map[string]func(*Policy){
+		"request percentile of zero":     func(p *Policy) { p.RequestPercentile = 0 },
+		"request percentile above one":   func(p *Policy) { p.RequestPercentile = 1.5 },
+		"limit percentile below request": func(p *Policy) { p.LimitPercentile = 0.5 },
+		"limit percentile above one":     func(p *Policy) { p.LimitPercentile = 1.1 },
+		"negative headroom":              func(p *Policy) { p.Headroom = -0.1 },
+		"negative minimum history":       func(p *Policy) { p.MinHistory = -time.Hour },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := DefaultPolicy
+			mutate(&policy)
+			assert.Error(t, policy.Validate())
+		})
+	}
+}
+
+func TestRelativeGap(t *testing.T) {
+	assert.InDelta(t, 0.8, RelativeGap(4, 0.8), 1e-9, "over-provisioned")
+	assert.InDelta(t, 2, RelativeGap(1, 3), 1e-9, "under-provisioned")
+	assert.Zero(t, RelativeGap(2, 2))
+	assert.Zero(t, RelativeGap(0, 2), "no request")
+}
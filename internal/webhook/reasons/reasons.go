@@ -0,0 +1,43 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package reasons defines the reasons and causes of the metav1.Status the admission webhooks reject
+// workspaces with. They are a stable contract clients and tests can match on, unlike the messages,
+// whose wording may change.
+package reasons
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of the admission rejections
+const (
+	// TemplateNotFound rejects a workspace whose template is in none of the namespaces it is looked up in.
+	// Each namespace tried is a cause of type CauseTypeNamespaceTried.
+	TemplateNotFound metav1.StatusReason = "TemplateNotFound"
+
+	// TemplateNamespaceForbidden rejects a workspace referencing a template of a namespace it may not use.
+	// Each namespace it may use is a cause of type CauseTypeNamespaceAllowed.
+	TemplateNamespaceForbidden metav1.StatusReason = "TemplateNamespaceForbidden"
+
+	// AccessStrategyNamespaceForbidden rejects a workspace referencing an access strategy of a namespace
+	// it may not use. Each namespace it may use is a cause of type CauseTypeNamespaceAllowed.
+	AccessStrategyNamespaceForbidden metav1.StatusReason = "AccessStrategyNamespaceForbidden"
+)
+
+// Types of the causes of the admission rejections; the message of a cause is a namespace
+const (
+	// CauseTypeNamespaceTried is a namespace the referenced resource was looked up in
+	CauseTypeNamespaceTried metav1.CauseType = "NamespaceTried"
+
+	// CauseTypeNamespaceAllowed is a namespace the referenced resource may be in
+	CauseTypeNamespaceAllowed metav1.CauseType = "NamespaceAllowed"
+)
+
+// Fields of the causes of the admission rejections
+const (
+	FieldTemplateRefNamespace    = "spec.templateRef.namespace"
+	FieldAccessStrategyNamespace = "spec.accessStrategy.namespace"
+)
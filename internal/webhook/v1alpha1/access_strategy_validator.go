@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
 )
 
 // AccessStrategyValidator handles access strategy namespace validation for webhooks
@@ -38,20 +39,22 @@ func (v *AccessStrategyValidator) validateAccessStrategyNamespace(workspace *wor
 	}
 
 	if v.sharedNamespace == "" {
-		return fmt.Errorf(
-			"accessStrategy.namespace %q is not allowed: access strategies must be in the workspace namespace %q",
-			asNamespace, workspaceNamespace,
-		)
+		return namespaceForbiddenError(reasons.AccessStrategyNamespaceForbidden, reasons.FieldAccessStrategyNamespace,
+			resourceWorkspaceAccessStrategies, workspace.Spec.AccessStrategy.Name, fmt.Sprintf(
+				"accessStrategy.namespace %q is not allowed: access strategies must be in the workspace namespace %q",
+				asNamespace, workspaceNamespace,
+			), workspaceNamespace)
 	}
 
 	if asNamespace == v.sharedNamespace {
 		return nil
 	}
 
-	return fmt.Errorf(
-		"accessStrategy.namespace %q is not allowed: access strategies must be in the workspace namespace %q or the shared namespace %q",
-		asNamespace, workspaceNamespace, v.sharedNamespace,
-	)
+	return namespaceForbiddenError(reasons.AccessStrategyNamespaceForbidden, reasons.FieldAccessStrategyNamespace,
+		resourceWorkspaceAccessStrategies, workspace.Spec.AccessStrategy.Name, fmt.Sprintf(
+			"accessStrategy.namespace %q is not allowed: access strategies must be in the workspace namespace %q or the shared namespace %q",
+			asNamespace, workspaceNamespace, v.sharedNamespace,
+		), workspaceNamespace, v.sharedNamespace)
 }
 
 // ValidateCreateWorkspace validates access strategy namespace on workspace creation
@@ -8,9 +8,11 @@ package v1alpha1
 import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
 )
 
 var _ = Describe("AccessStrategyValidator", func() {
@@ -30,9 +32,13 @@ var _ = Describe("AccessStrategyValidator", func() {
 
 			err := validator.ValidateCreateWorkspace(workspace)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("team-b"))
-			Expect(err.Error()).To(ContainSubstring("team-a"))
-			Expect(err.Error()).To(ContainSubstring("jupyter-k8s-shared"))
+			Expect(apierrors.ReasonForError(err)).To(Equal(reasons.AccessStrategyNamespaceForbidden))
+			status := err.(apierrors.APIStatus).Status()
+			Expect(status.Details.Name).To(Equal("some-strategy"))
+			Expect(status.Details.Causes).To(Equal([]metav1.StatusCause{
+				{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldAccessStrategyNamespace, Message: "team-a"},
+				{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldAccessStrategyNamespace, Message: "jupyter-k8s-shared"},
+			}))
 		})
 
 		It("should allow accessStrategy targeting the workspace's own namespace", func() {
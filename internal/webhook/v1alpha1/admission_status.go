@@ -0,0 +1,85 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// Resources named in the details of the admission rejections
+const (
+	resourceWorkspaceTemplates        = "workspacetemplates"
+	resourceWorkspaceAccessStrategies = "workspaceaccessstrategies"
+)
+
+// withTemplateNotFoundReason turns a template found in none of the namespaces it was looked up in into a
+// TemplateNotFound rejection listing the namespaces tried. Other errors are returned unchanged.
+// The webhooks answer with the status of the error, rather than with the NotFound of the last lookup.
+func withTemplateNotFoundReason(err error) error {
+	var notFound *workspaceutil.TemplateNotFoundError
+	if !errors.As(err, &notFound) {
+		return err
+	}
+	causes := make([]metav1.StatusCause, 0, len(notFound.Namespaces))
+	for _, namespace := range notFound.Namespaces {
+		causes = append(causes, metav1.StatusCause{
+			Type:    reasons.CauseTypeNamespaceTried,
+			Field:   reasons.FieldTemplateRefNamespace,
+			Message: namespace,
+		})
+	}
+	message := fmt.Sprintf("template %q not found in namespace %q", notFound.Name, notFound.Namespaces[0])
+	if len(notFound.Namespaces) > 1 {
+		message += fmt.Sprintf(" or fallback namespace %q", notFound.Namespaces[len(notFound.Namespaces)-1])
+	}
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusNotFound,
+		Reason:  reasons.TemplateNotFound,
+		Message: message,
+		Details: &metav1.StatusDetails{
+			Name:   notFound.Name,
+			Group:  workspacev1alpha1.GroupVersion.Group,
+			Kind:   resourceWorkspaceTemplates,
+			Causes: causes,
+		},
+	}}
+}
+
+// namespaceForbiddenError rejects a reference to a resource of a namespace the workspace may not use,
+// listing the namespaces it may use
+func namespaceForbiddenError(reason metav1.StatusReason, field, resource, name, message string,
+	allowedNamespaces ...string) *apierrors.StatusError {
+	causes := make([]metav1.StatusCause, 0, len(allowedNamespaces))
+	for _, namespace := range allowedNamespaces {
+		causes = append(causes, metav1.StatusCause{
+			Type:    reasons.CauseTypeNamespaceAllowed,
+			Field:   field,
+			Message: namespace,
+		})
+	}
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    http.StatusForbidden,
+		Reason:  reason,
+		Message: message,
+		Details: &metav1.StatusDetails{
+			Name:   name,
+			Group:  workspacev1alpha1.GroupVersion.Group,
+			Kind:   resource,
+			Causes: causes,
+		},
+	}}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+// getCloneSource returns the source workspace of a new workspace cloned from another one, and its home volume
+func (vv *VolumeValidator) getCloneSource(
+	ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.Workspace, *corev1.PersistentVolumeClaim, error) {
+	sourceName := workspace.Spec.CloneFrom.WorkspaceName
+	source := &workspacev1alpha1.Workspace{}
+	err := vv.client.Get(ctx, types.NamespacedName{Name: sourceName, Namespace: workspace.Namespace}, source)
+	if apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("spec.cloneFrom references workspace '%s', which does not exist in namespace '%s'",
+			sourceName, workspace.Namespace)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get source workspace '%s': %w", sourceName, err)
+	}
+
+	pvcName := controller.HomePVCNameFor(source)
+	pvc := &corev1.PersistentVolumeClaim{}
+	err = vv.client.Get(ctx, types.NamespacedName{Name: pvcName, Namespace: workspace.Namespace}, pvc)
+	if apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("spec.cloneFrom references workspace '%s', which has no home volume", sourceName)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get PVC '%s' of source workspace '%s': %w", pvcName, sourceName, err)
+	}
+	if pvc.Status.Phase != corev1.ClaimBound {
+		return nil, nil, fmt.Errorf("spec.cloneFrom references workspace '%s', whose home volume '%s' is not bound",
+			sourceName, pvcName)
+	}
+	return source, pvc, nil
+}
+
+// ValidateCloneSource checks that the workspace a new workspace clones exists in its namespace and has a bound
+// home volume the storage of the new workspace can hold
+func (vv *VolumeValidator) ValidateCloneSource(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	cloneFrom := workspace.Spec.CloneFrom
+	if cloneFrom == nil {
+		return nil
+	}
+	if workspace.Spec.Storage == nil {
+		return fmt.Errorf("spec.cloneFrom requires spec.storage: the home volume of workspace '%s' is copied into it",
+			cloneFrom.WorkspaceName)
+	}
+	if controller.StorageModeOf(workspace) != workspacev1alpha1.StorageModePersistent {
+		return fmt.Errorf("spec.cloneFrom requires Persistent storage: the home volume of workspace '%s' is copied "+
+			"into a PVC", cloneFrom.WorkspaceName)
+	}
+	if cloneFrom.WorkspaceName == workspace.Name {
+		return fmt.Errorf("spec.cloneFrom cannot reference the workspace itself")
+	}
+
+	_, pvc, err := vv.getCloneSource(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	size := workspace.Spec.Storage.Size
+	if sourceSize, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok && !size.IsZero() && size.Cmp(sourceSize) < 0 {
+		return fmt.Errorf("spec.storage.size %s cannot hold the home volume of workspace '%s': it must be at least %s",
+			size.String(), cloneFrom.WorkspaceName, sourceSize.String())
+	}
+	// A PVC is cloned in the StorageClass of its source; a VolumeSnapshot may be restored in another one
+	className := workspace.Spec.Storage.StorageClassName
+	if cloneFrom.VolumeSnapshotClassName == "" && className != nil && pvc.Spec.StorageClassName != nil &&
+		*className != *pvc.Spec.StorageClassName {
+		return fmt.Errorf("spec.storage.storageClassName '%s' differs from the StorageClass '%s' of the home volume of "+
+			"workspace '%s': a volume is cloned in the StorageClass of its source, set spec.cloneFrom.volumeSnapshotClassName "+
+			"to copy it through a VolumeSnapshot instead", *className, *pvc.Spec.StorageClassName, cloneFrom.WorkspaceName)
+	}
+	return nil
+}
+
+// ValidateCloneSourceAccess checks that the user may read the data of an OwnerOnly source workspace, which
+// only its owner may
+func (vv *VolumeValidator) ValidateCloneSourceAccess(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.CloneFrom == nil {
+		return nil
+	}
+	source, _, err := vv.getCloneSource(ctx, workspace)
+	if err != nil {
+		return err
+	}
+	if getEffectiveOwnershipType(source.Spec.OwnershipType) != webhookconst.OwnershipTypeOwnerOnly {
+		return nil
+	}
+	if err := validateOwnershipPermission(ctx, source); err != nil {
+		return fmt.Errorf("spec.cloneFrom cannot reference OwnerOnly workspace '%s' of another user: %w", source.Name, err)
+	}
+	return nil
+}
+
+// validateCloneFromUnchanged rejects changes to spec.cloneFrom, including setting or removing it: the home volume
+// is cloned once, when it is created
+func validateCloneFromUnchanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if equality.Semantic.DeepEqual(oldWorkspace.Spec.CloneFrom, newWorkspace.Spec.CloneFrom) {
+		return nil
+	}
+	return fmt.Errorf("spec.cloneFrom cannot change after creation: the home volume is cloned only when it is created")
+}
@@ -0,0 +1,159 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("Clone validation", func() {
+	var (
+		ctx       context.Context
+		source    *workspacev1alpha1.Workspace
+		sourcePVC *corev1.PersistentVolumeClaim
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	newValidator := func(objs ...client.Object) *VolumeValidator {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		return NewVolumeValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		source = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "onboarding", Namespace: "team-a",
+				Annotations: map[string]string{controller.AnnotationCreatedBy: "alice"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("5Gi")},
+			},
+		}
+		sourcePVC = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: controller.HomePVCNameFor(source), Namespace: "team-a"},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				StorageClassName: ptr.To("gp3"),
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("5Gi")},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "new-teammate", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage:   &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi")},
+				CloneFrom: &workspacev1alpha1.CloneSpec{WorkspaceName: "onboarding"},
+			},
+		}
+	})
+
+	It("should allow cloning a workspace with a bound home volume", func() {
+		Expect(newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)).To(Succeed())
+	})
+
+	It("should allow workspaces that do not clone", func() {
+		workspace.Spec.CloneFrom = nil
+		Expect(newValidator().ValidateCloneSource(ctx, workspace)).To(Succeed())
+	})
+
+	It("should reject a source workspace that does not exist", func() {
+		err := newValidator().ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("workspace 'onboarding', which does not exist in namespace 'team-a'")))
+	})
+
+	It("should reject a source workspace of another namespace", func() {
+		source.Namespace = "team-b"
+		sourcePVC.Namespace = "team-b"
+		err := newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("does not exist in namespace 'team-a'")))
+	})
+
+	It("should reject a source workspace whose home volume is not bound", func() {
+		sourcePVC.Status.Phase = corev1.ClaimPending
+		err := newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("whose home volume '" + sourcePVC.Name + "' is not bound")))
+
+		err = newValidator(source).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("which has no home volume")))
+	})
+
+	It("should reject a clone without persistent storage or smaller than its source", func() {
+		workspace.Spec.Storage.Size = resource.MustParse("1Gi")
+		err := newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("it must be at least 5Gi")))
+
+		workspace.Spec.Storage.Mode = workspacev1alpha1.StorageModeEphemeral
+		err = newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("spec.cloneFrom requires Persistent storage")))
+
+		workspace.Spec.Storage = nil
+		err = newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("spec.cloneFrom requires spec.storage")))
+	})
+
+	It("should reject a workspace cloning itself", func() {
+		workspace.Spec.CloneFrom.WorkspaceName = workspace.Name
+		err := newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("cannot reference the workspace itself")))
+	})
+
+	It("should only allow another StorageClass when cloning through a VolumeSnapshot", func() {
+		workspace.Spec.Storage.StorageClassName = ptr.To("io2")
+		err := newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)
+		Expect(err).To(MatchError(ContainSubstring("a volume is cloned in the StorageClass of its source")))
+
+		workspace.Spec.CloneFrom.VolumeSnapshotClassName = "ebs-snapshots"
+		Expect(newValidator(source, sourcePVC).ValidateCloneSource(ctx, workspace)).To(Succeed())
+	})
+
+	It("should only let the owner of an OwnerOnly source workspace clone it", func() {
+		source.Spec.OwnershipType = webhookconst.OwnershipTypeOwnerOnly
+		validator := newValidator(source, sourcePVC)
+
+		err := validator.ValidateCloneSourceAccess(createUserContext(ctx, "CREATE", "bob"), workspace)
+		Expect(err).To(MatchError(ContainSubstring("cannot reference OwnerOnly workspace 'onboarding' of another user")))
+		Expect(validator.ValidateCloneSourceAccess(createUserContext(ctx, "CREATE", "alice"), workspace)).To(Succeed())
+
+		source.Spec.OwnershipType = webhookconst.OwnershipTypePublic
+		validator = newValidator(source, sourcePVC)
+		Expect(validator.ValidateCloneSourceAccess(createUserContext(ctx, "CREATE", "bob"), workspace)).To(Succeed())
+	})
+
+	It("should reject setting, changing or removing cloneFrom after creation", func() {
+		oldWorkspace := workspace.DeepCopy()
+		updated := workspace.DeepCopy()
+		updated.Spec.DesiredStatus = "Stopped"
+		Expect(validateCloneFromUnchanged(oldWorkspace, updated)).To(Succeed())
+
+		updated.Spec.CloneFrom = &workspacev1alpha1.CloneSpec{WorkspaceName: "other"}
+		Expect(validateCloneFromUnchanged(oldWorkspace, updated)).To(MatchError(ContainSubstring("cannot change after creation")))
+
+		updated.Spec.CloneFrom = nil
+		Expect(validateCloneFromUnchanged(oldWorkspace, updated)).To(MatchError(ContainSubstring("cannot change after creation")))
+
+		oldWorkspace.Spec.CloneFrom = nil
+		updated.Spec.CloneFrom = &workspacev1alpha1.CloneSpec{WorkspaceName: "onboarding"}
+		Expect(validateCloneFromUnchanged(oldWorkspace, updated)).To(MatchError(ContainSubstring("cannot change after creation")))
+	})
+})
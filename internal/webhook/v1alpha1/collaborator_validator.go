@@ -0,0 +1,69 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+// validateCollaboratorsChange rejects changes to the collaborators of a workspace by any user but its owner.
+// Controller and admin users are checked upstream.
+func validateCollaboratorsChange(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if equality.Semantic.DeepEqual(oldWorkspace.Spec.Collaborators, newWorkspace.Spec.Collaborators) {
+		return nil
+	}
+	if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
+		return fmt.Errorf("access denied: only the workspace owner can change spec.collaborators")
+	}
+	return nil
+}
+
+// validateEditPermission checks the user may update an OwnerOnly workspace: its owner, or one of its Editor
+// collaborators, who may not make it Public
+func validateEditPermission(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	err := validateOwnershipPermission(ctx, oldWorkspace)
+	if err == nil {
+		return nil
+	}
+	if getEffectiveOwnershipType(newWorkspace.Spec.OwnershipType) == webhookconst.OwnershipTypeOwnerOnly &&
+		isEditorCollaborator(ctx, oldWorkspace) {
+		return nil
+	}
+	return err
+}
+
+// isEditorCollaborator checks if the user of the request, or one of its groups, is an Editor collaborator of the workspace
+func isEditorCollaborator(ctx context.Context, workspace *workspacev1alpha1.Workspace) bool {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	for _, collaborator := range workspace.Spec.Collaborators {
+		if collaborator.Role != workspacev1alpha1.CollaboratorRoleEditor {
+			continue
+		}
+		switch collaborator.Kind {
+		case rbacv1.UserKind:
+			if collaborator.Name == req.UserInfo.Username {
+				return true
+			}
+		case rbacv1.GroupKind:
+			if slices.Contains(req.UserInfo.Groups, collaborator.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Collaborator Validator", func() {
+
+	var (
+		ctx          context.Context
+		oldWorkspace *workspacev1alpha1.Workspace
+		newWorkspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		oldWorkspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-workspace",
+				Namespace:   "default",
+				Annotations: map[string]string{controller.AnnotationCreatedBy: "owner-user"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				OwnershipType: "OwnerOnly",
+				Collaborators: []workspacev1alpha1.WorkspaceCollaborator{
+					{Kind: "User", Name: "viewer-user", Role: workspacev1alpha1.CollaboratorRoleViewer},
+					{Kind: "User", Name: "editor-user", Role: workspacev1alpha1.CollaboratorRoleEditor},
+					{Kind: "Group", Name: "reviewers", Role: workspacev1alpha1.CollaboratorRoleEditor},
+				},
+			},
+		}
+		newWorkspace = oldWorkspace.DeepCopy()
+	})
+
+	It("should allow updates that leave the collaborators unchanged", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "editor-user")
+		Expect(validateCollaboratorsChange(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+	})
+
+	It("should only allow the owner to change the collaborators", func() {
+		newWorkspace.Spec.Collaborators = append(newWorkspace.Spec.Collaborators,
+			workspacev1alpha1.WorkspaceCollaborator{Kind: "User", Name: "other-user", Role: workspacev1alpha1.CollaboratorRoleEditor})
+
+		ownerCtx := createUserContext(ctx, "UPDATE", "owner-user")
+		Expect(validateCollaboratorsChange(ownerCtx, oldWorkspace, newWorkspace)).To(Succeed())
+
+		editorCtx := createUserContext(ctx, "UPDATE", "editor-user")
+		err := validateCollaboratorsChange(editorCtx, oldWorkspace, newWorkspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("spec.collaborators"))
+	})
+
+	It("should allow editors, by user or group, to update an OwnerOnly workspace", func() {
+		newWorkspace.Spec.Image = "jupyter/scipy-notebook:latest"
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "editor-user"), oldWorkspace, newWorkspace)).To(Succeed())
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "someone", "reviewers"), oldWorkspace, newWorkspace)).To(Succeed())
+	})
+
+	It("should reject viewers and other users updating an OwnerOnly workspace", func() {
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "viewer-user"), oldWorkspace, newWorkspace)).NotTo(Succeed())
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "other-user"), oldWorkspace, newWorkspace)).NotTo(Succeed())
+	})
+
+	It("should reject editors making an OwnerOnly workspace Public", func() {
+		newWorkspace.Spec.OwnershipType = "Public"
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "editor-user"), oldWorkspace, newWorkspace)).NotTo(Succeed())
+		Expect(validateEditPermission(createUserContext(ctx, "UPDATE", "owner-user"), oldWorkspace, newWorkspace)).To(Succeed())
+	})
+})
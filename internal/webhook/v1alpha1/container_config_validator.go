@@ -0,0 +1,66 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// commandOverrideAllowed returns true if the template lets workspaces set their own command and args
+func commandOverrideAllowed(template *workspacev1alpha1.WorkspaceTemplate) bool {
+	return template.Spec.AllowCommandOverride != nil && *template.Spec.AllowCommandOverride
+}
+
+// emptyContainerConfig returns true if the container config sets neither a command nor args
+func emptyContainerConfig(config *workspacev1alpha1.ContainerConfig) bool {
+	return config == nil || (len(config.Command) == 0 && len(config.Args) == 0)
+}
+
+// overridesCommand returns true if the workspace sets a command or args other than the template defaults
+func overridesCommand(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	config := workspace.Spec.ContainerConfig
+	defaults := template.Spec.DefaultContainerConfig
+	if emptyContainerConfig(config) {
+		return false
+	}
+	if emptyContainerConfig(defaults) {
+		return true
+	}
+	return !equality.Semantic.DeepEqual(config.Command, defaults.Command) ||
+		!equality.Semantic.DeepEqual(config.Args, defaults.Args)
+}
+
+// validateCommandOverride rejects a workspace setting a command or args other than the template defaults,
+// unless the template allows command overrides
+func validateCommandOverride(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if commandOverrideAllowed(template) || !overridesCommand(workspace, template) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeCommandOverrideNotAllowed,
+		Field:   "spec.containerConfig",
+		Message: fmt.Sprintf("Template '%s' does not allow workspaces to override the container command and args", template.Name),
+		Allowed: "the template default command and args",
+		Actual: fmt.Sprintf("command %q, args %q",
+			workspace.Spec.ContainerConfig.Command, workspace.Spec.ContainerConfig.Args),
+	}
+}
+
+// withoutKeptCommandOverrideViolations drops the command override violation of an update keeping the command
+// and args of the workspace, so that workspaces admitted before their template disallowed overrides stay editable
+func withoutKeptCommandOverrideViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if !equality.Semantic.DeepEqual(oldWorkspace.Spec.ContainerConfig, newWorkspace.Spec.ContainerConfig) {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeCommandOverrideNotAllowed
+	})
+}
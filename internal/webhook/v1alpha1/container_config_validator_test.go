@@ -0,0 +1,83 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Container Config Validator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "jupyter"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultContainerConfig: &workspacev1alpha1.ContainerConfig{
+					Command: []string{"start-notebook.py"},
+					Args:    []string{"--ServerApp.root_dir=/home/jovyan"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{}
+	})
+
+	It("should allow workspaces keeping the template command and args", func() {
+		Expect(validateCommandOverride(workspace, template)).To(BeNil())
+		workspace.Spec.ContainerConfig = template.Spec.DefaultContainerConfig.DeepCopy()
+		Expect(validateCommandOverride(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a command or args override when the template does not allow it", func() {
+		workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"code-server"}}
+		violation := validateCommandOverride(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeCommandOverrideNotAllowed))
+		Expect(violation.Field).To(Equal("spec.containerConfig"))
+
+		workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{
+			Command: template.Spec.DefaultContainerConfig.Command,
+			Args:    []string{"--debug"},
+		}
+		Expect(validateCommandOverride(workspace, template)).NotTo(BeNil())
+
+		template.Spec.AllowCommandOverride = ptr.To(false)
+		Expect(validateCommandOverride(workspace, template)).NotTo(BeNil())
+	})
+
+	It("should reject a command on a template without default command", func() {
+		template.Spec.DefaultContainerConfig = nil
+		workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Args: []string{"--debug"}}
+		Expect(validateCommandOverride(workspace, template)).NotTo(BeNil())
+	})
+
+	It("should allow an override when the template allows it", func() {
+		template.Spec.AllowCommandOverride = ptr.To(true)
+		workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"code-server"}}
+		Expect(validateCommandOverride(workspace, template)).To(BeNil())
+		Expect(ValidateWorkspaceAgainstTemplate(workspace, template)).To(BeEmpty())
+	})
+
+	It("should keep admitting updates that do not change an override", func() {
+		oldWorkspace := &workspacev1alpha1.Workspace{}
+		oldWorkspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"code-server"}}
+		workspace.Spec.ContainerConfig = oldWorkspace.Spec.ContainerConfig.DeepCopy()
+		violations := []TemplateViolation{*validateCommandOverride(workspace, template)}
+
+		Expect(withoutKeptCommandOverrideViolations(violations, oldWorkspace, workspace)).To(BeEmpty())
+
+		workspace.Spec.ContainerConfig.Command = []string{"sleep", "infinity"}
+		Expect(withoutKeptCommandOverrideViolations(violations, oldWorkspace, workspace)).To(HaveLen(1))
+	})
+})
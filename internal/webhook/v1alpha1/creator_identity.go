@@ -0,0 +1,89 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const (
+	// TrustedCreatorsEnv lists, comma-separated, the usernames allowed to create workspaces on behalf of other users
+	TrustedCreatorsEnv = "TRUSTED_CREATORS"
+	// TrustedCreatorGroupsEnv lists, comma-separated, the groups allowed to create workspaces on behalf of other users
+	TrustedCreatorGroupsEnv = "TRUSTED_CREATOR_GROUPS"
+)
+
+// isTrustedCreator checks if the requesting principal is in the trusted creators allowlist
+func isTrustedCreator(req admission.Request) bool {
+	if slices.Contains(splitEnvList(TrustedCreatorsEnv), req.UserInfo.Username) {
+		return true
+	}
+	trustedGroups := splitEnvList(TrustedCreatorGroupsEnv)
+	for _, group := range req.UserInfo.Groups {
+		if slices.Contains(trustedGroups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitEnvList returns the non-empty comma-separated entries of an environment variable
+func splitEnvList(name string) []string {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// applyCreatorIdentity sets the created-by annotation on CREATE to the effective owner of the workspace.
+// A trusted creator may name the owner with the on-behalf-of annotation, in which case the owner is taken
+// from the annotation and the trusted creator is recorded as the delegate. The on-behalf-of annotation of
+// an untrusted requester is left in place so that validation rejects the request.
+// The webhook may be invoked again on the workspace it mutated: a trusted creator recorded as the delegate
+// then keeps the owner it named.
+func applyCreatorIdentity(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "CREATE" {
+		return
+	}
+
+	requester := stringutil.SanitizeUsername(req.UserInfo.Username)
+	owner := requester
+	delegate := workspace.Annotations[controller.AnnotationCreatedByDelegate]
+	delete(workspace.Annotations, controller.AnnotationCreatedByDelegate)
+
+	if onBehalfOf, ok := workspace.Annotations[controller.AnnotationOnBehalfOf]; ok && isTrustedCreator(req) {
+		owner = stringutil.SanitizeUsername(onBehalfOf)
+		workspace.Annotations[controller.AnnotationCreatedByDelegate] = requester
+		delete(workspace.Annotations, controller.AnnotationOnBehalfOf)
+		workspacelog.Info("Creating workspace on behalf of user", "workspace", workspace.GetName(), "owner", owner, "delegate", requester)
+	} else if createdBy := workspace.Annotations[controller.AnnotationCreatedBy]; delegate == requester &&
+		createdBy != "" && isTrustedCreator(req) {
+		owner = createdBy
+		workspace.Annotations[controller.AnnotationCreatedByDelegate] = requester
+	}
+
+	workspace.Annotations[controller.AnnotationCreatedBy] = owner
+	workspacelog.Info("Added created-by annotation", "workspace", workspace.GetName(), "user", owner, "namespace", workspace.GetNamespace())
+}
+
+// validateOnBehalfOf rejects an on-behalf-of annotation that was not consumed on creation by a trusted creator
+func validateOnBehalfOf(workspace *workspacev1alpha1.Workspace) error {
+	if _, ok := workspace.Annotations[controller.AnnotationOnBehalfOf]; ok {
+		return fmt.Errorf("annotation '%s' can only be set by a trusted creator when creating a workspace", controller.AnnotationOnBehalfOf)
+	}
+	return nil
+}
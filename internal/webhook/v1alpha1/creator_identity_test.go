@@ -0,0 +1,158 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Creator Identity", func() {
+	const (
+		portalUser = "system:serviceaccount:portal:portal-sa"
+		endUser    = "alice"
+	)
+
+	var workspace *workspacev1alpha1.Workspace
+
+	newRequest := func(operation, username string, groups ...string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Operation(operation),
+			UserInfo:  authenticationv1.UserInfo{Username: username, Groups: groups},
+		}}
+	}
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "portal-workspace",
+				Namespace:   "default",
+				Annotations: map[string]string{controller.AnnotationOnBehalfOf: endUser},
+			},
+		}
+		Expect(os.Setenv(TrustedCreatorsEnv, "someone-else, "+portalUser)).To(Succeed())
+		Expect(os.Setenv(TrustedCreatorGroupsEnv, "portal-admins")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(TrustedCreatorsEnv)).To(Succeed())
+		Expect(os.Unsetenv(TrustedCreatorGroupsEnv)).To(Succeed())
+	})
+
+	Context("trusted creator", func() {
+		It("should set the effective owner and record the delegate", func() {
+			applyCreatorIdentity(newRequest("CREATE", portalUser), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal(endUser))
+			Expect(workspace.Annotations[controller.AnnotationCreatedByDelegate]).To(Equal(portalUser))
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationOnBehalfOf))
+			Expect(validateOnBehalfOf(workspace)).To(Succeed())
+		})
+
+		It("should trust members of a trusted creator group", func() {
+			applyCreatorIdentity(newRequest("CREATE", "portal-2", "portal-admins"), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal(endUser))
+			Expect(workspace.Annotations[controller.AnnotationCreatedByDelegate]).To(Equal("portal-2"))
+		})
+
+		It("should use the requester as owner when no on-behalf-of annotation is set", func() {
+			delete(workspace.Annotations, controller.AnnotationOnBehalfOf)
+			applyCreatorIdentity(newRequest("CREATE", portalUser), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal(portalUser))
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationCreatedByDelegate))
+		})
+
+		It("should keep the effective owner when the defaulter is invoked again", func() {
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+			defaulter := &WorkspaceCustomDefaulter{
+				templateDefaulter:       NewTemplateDefaulter(k8sClient, ""),
+				serviceAccountDefaulter: NewServiceAccountDefaulter(k8sClient),
+				templateGetter:          NewTemplateGetter(k8sClient, ""),
+				client:                  k8sClient,
+			}
+			ctx := admission.NewContextWithRequest(context.Background(), newRequest("CREATE", portalUser))
+
+			Expect(defaulter.Default(ctx, workspace)).To(Succeed())
+			Expect(defaulter.Default(ctx, workspace)).To(Succeed())
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal(endUser))
+			Expect(workspace.Annotations[controller.AnnotationCreatedByDelegate]).To(Equal(portalUser))
+		})
+
+		It("should enforce OwnerOnly permissions against the effective owner", func() {
+			applyCreatorIdentity(newRequest("CREATE", portalUser), workspace)
+
+			ownerCtx := createUserContext(context.Background(), "UPDATE", endUser)
+			Expect(validateOwnershipPermission(ownerCtx, workspace)).To(Succeed())
+
+			portalCtx := createUserContext(context.Background(), "UPDATE", portalUser)
+			Expect(validateOwnershipPermission(portalCtx, workspace)).NotTo(Succeed())
+		})
+	})
+
+	Context("untrusted creator", func() {
+		It("should keep the real requester as owner and leave the annotation for validation to reject", func() {
+			applyCreatorIdentity(newRequest("CREATE", "mallory"), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal("mallory"))
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationCreatedByDelegate))
+			err := validateOnBehalfOf(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(controller.AnnotationOnBehalfOf))
+		})
+
+		It("should not keep an owner named by a forged delegate annotation", func() {
+			delete(workspace.Annotations, controller.AnnotationOnBehalfOf)
+			workspace.Annotations[controller.AnnotationCreatedBy] = endUser
+			workspace.Annotations[controller.AnnotationCreatedByDelegate] = "mallory"
+			applyCreatorIdentity(newRequest("CREATE", "mallory"), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal("mallory"))
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationCreatedByDelegate))
+		})
+
+		It("should drop a forged delegate annotation", func() {
+			delete(workspace.Annotations, controller.AnnotationOnBehalfOf)
+			workspace.Annotations[controller.AnnotationCreatedByDelegate] = portalUser
+			applyCreatorIdentity(newRequest("CREATE", "mallory"), workspace)
+
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationCreatedByDelegate))
+		})
+
+		It("should deny the untrusted requester's target user OwnerOnly access", func() {
+			applyCreatorIdentity(newRequest("CREATE", "mallory"), workspace)
+
+			ownerCtx := createUserContext(context.Background(), "UPDATE", endUser)
+			Expect(validateOwnershipPermission(ownerCtx, workspace)).NotTo(Succeed())
+		})
+
+		It("should not change the owner on update", func() {
+			workspace.Annotations[controller.AnnotationCreatedBy] = endUser
+			applyCreatorIdentity(newRequest("UPDATE", portalUser), workspace)
+
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal(endUser))
+			Expect(validateOnBehalfOf(workspace)).NotTo(Succeed())
+		})
+	})
+})
@@ -0,0 +1,54 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// DeletionProtectionValidator rejects the deletion of workspaces with deletion protection
+type DeletionProtectionValidator struct {
+	client client.Client
+}
+
+// NewDeletionProtectionValidator creates a new DeletionProtectionValidator
+func NewDeletionProtectionValidator(k8sClient client.Client) *DeletionProtectionValidator {
+	return &DeletionProtectionValidator{
+		client: k8sClient,
+	}
+}
+
+// ValidateDelete rejects the deletion of a protected workspace (applies to all users), unless its namespace
+// is terminating: the namespace could not finish deleting otherwise, and some deletion paths of a namespace
+// do not call the webhook at all, so it does not protect workspaces from namespace deletion
+func (dv *DeletionProtectionValidator) ValidateDelete(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if !workspace.Spec.DeletionProtection {
+		return nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := dv.client.Get(ctx, types.NamespacedName{Name: workspace.Namespace}, namespace); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get namespace %s: %w", workspace.Namespace, err)
+		}
+	} else if !namespace.DeletionTimestamp.IsZero() {
+		workspacelog.Info("Allowing deletion of a protected workspace of a terminating namespace",
+			"workspace", workspace.Name, "namespace", workspace.Namespace)
+		return nil
+	}
+
+	return fmt.Errorf("workspace %s has deletion protection enabled: set spec.deletionProtection to false "+
+		"in a separate update before deleting it (kubectl workspace unprotect %s -n %s)",
+		workspace.Name, workspace.Name, workspace.Namespace)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("DeletionProtectionValidator", func() {
+	var (
+		ctx       context.Context
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	newValidator := func(namespace *corev1.Namespace) *WorkspaceCustomValidator {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		objects := []client.Object{}
+		if namespace != nil {
+			objects = append(objects, namespace)
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		return &WorkspaceCustomValidator{deletionValidator: NewDeletionProtectionValidator(k8sClient)}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "team-a"},
+			Spec:       workspacev1alpha1.WorkspaceSpec{DeletionProtection: true},
+		}
+	})
+
+	It("should reject deleting a protected workspace", func() {
+		validator := newValidator(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+
+		_, err := validator.ValidateDelete(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("workspace research has deletion protection enabled"))
+		Expect(err.Error()).To(ContainSubstring("kubectl workspace unprotect research -n team-a"))
+	})
+
+	It("should reject deleting a protected workspace for admin users", func() {
+		validator := newValidator(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+
+		_, err := validator.ValidateDelete(createUserContext(ctx, "DELETE", "admin", webhookconst.DefaultAdminGroup), workspace)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should allow deleting a workspace once its protection is removed", func() {
+		validator := newValidator(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})
+		workspace.Spec.DeletionProtection = false
+
+		_, err := validator.ValidateDelete(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should allow deleting a protected workspace of a terminating namespace", func() {
+		validator := newValidator(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:              "team-a",
+			DeletionTimestamp: &metav1.Time{Time: metav1.Now().Time},
+			Finalizers:        []string{"kubernetes"},
+		}})
+
+		_, err := validator.ValidateDelete(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,41 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// applyDesiredStatusDefault sets desiredStatus to Running when it is omitted. The CRD schema
+// defaults the field too; this covers objects that reach the webhook without schema defaulting.
+// It must run after stampDesiredStatusSetAt so that the default is not mistaken for a manual change.
+func applyDesiredStatusDefault(workspace *workspacev1alpha1.Workspace) {
+	if workspace.Spec.DesiredStatus == "" {
+		workspace.Spec.DesiredStatus = controller.DefaultDesiredStatus
+	}
+}
+
+// isResuming returns true if the update only flips desiredStatus from Paused, Stopped or Hibernated to Running.
+// A paused, stopped or hibernated workspace resumes with the spec it had, including the edits made while it did not
+// run, so template defaults are not applied again.
+func isResuming(req admission.Request, workspace *workspacev1alpha1.Workspace) bool {
+	if req.Operation != "UPDATE" || workspace.Spec.DesiredStatus != controller.DesiredStateRunning {
+		return false
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return false
+	}
+	return (oldWorkspace.Spec.DesiredStatus == controller.DesiredStatePaused ||
+		oldWorkspace.Spec.DesiredStatus == controller.DesiredStateStopped ||
+		oldWorkspace.Spec.DesiredStatus == controller.DesiredStateHibernated) &&
+		onlyDesiredStatusChanged(&oldWorkspace.Spec, &workspace.Spec)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// desiredStatusTransitions lists the legal desiredStatus changes by previous value.
+// The empty value only exists on workspaces created before desiredStatus was defaulted.
+// A stopped workspace has no service or access resources left to keep, so it cannot pause.
+// A hibernated workspace has no PVC until it runs again, which restores it from its snapshot.
+var desiredStatusTransitions = map[string][]string{
+	"": {controller.DesiredStateRunning, controller.DesiredStateStopped, controller.DesiredStatePaused},
+	controller.DesiredStateRunning: {
+		controller.DesiredStateStopped, controller.DesiredStatePaused, controller.DesiredStateHibernated},
+	controller.DesiredStateStopped: {controller.DesiredStateRunning, controller.DesiredStateHibernated},
+	controller.DesiredStatePaused: {
+		controller.DesiredStateRunning, controller.DesiredStateStopped, controller.DesiredStateHibernated},
+	controller.DesiredStateHibernated: {controller.DesiredStateRunning},
+}
+
+// validateDesiredStatusTransition rejects desiredStatus changes that are not in desiredStatusTransitions
+func validateDesiredStatusTransition(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	from := oldWorkspace.Spec.DesiredStatus
+	to := newWorkspace.Spec.DesiredStatus
+	if from == to {
+		return nil
+	}
+
+	allowed, known := desiredStatusTransitions[from]
+	if !known {
+		// Leaving a state this version does not know about is always allowed; the CRD enum checks the new value
+		return nil
+	}
+	if !slices.Contains(allowed, to) {
+		return fmt.Errorf("desiredStatus cannot change from %q to %q", from, to)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("DesiredStatus Validator", func() {
+	workspaceWith := func(desiredStatus string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			Spec: workspacev1alpha1.WorkspaceSpec{DesiredStatus: desiredStatus},
+		}
+	}
+
+	DescribeTable("validateDesiredStatusTransition",
+		func(from, to string, expectAllowed bool) {
+			err := validateDesiredStatusTransition(workspaceWith(from), workspaceWith(to))
+			if expectAllowed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("desiredStatus cannot change"))
+			}
+		},
+		Entry("unchanged", controller.DesiredStateRunning, controller.DesiredStateRunning, true),
+		Entry("Running to Stopped", controller.DesiredStateRunning, controller.DesiredStateStopped, true),
+		Entry("Stopped to Running", controller.DesiredStateStopped, controller.DesiredStateRunning, true),
+		Entry("legacy unset to Running", "", controller.DesiredStateRunning, true),
+		Entry("legacy unset to Stopped", "", controller.DesiredStateStopped, true),
+		Entry("Running to Paused", controller.DesiredStateRunning, controller.DesiredStatePaused, true),
+		Entry("Paused to Running", controller.DesiredStatePaused, controller.DesiredStateRunning, true),
+		Entry("Paused to Stopped", controller.DesiredStatePaused, controller.DesiredStateStopped, true),
+		Entry("Stopped to Paused", controller.DesiredStateStopped, controller.DesiredStatePaused, false),
+		Entry("Running to unset", controller.DesiredStateRunning, "", false),
+		Entry("Running to Hibernated", controller.DesiredStateRunning, controller.DesiredStateHibernated, true),
+		Entry("Stopped to Hibernated", controller.DesiredStateStopped, controller.DesiredStateHibernated, true),
+		Entry("Hibernated to Running", controller.DesiredStateHibernated, controller.DesiredStateRunning, true),
+		Entry("Hibernated to Stopped", controller.DesiredStateHibernated, controller.DesiredStateStopped, false),
+		Entry("legacy unset to Hibernated", "", controller.DesiredStateHibernated, false),
+		Entry("unknown previous state to Running", "Archived", controller.DesiredStateRunning, true),
+	)
+
+	Describe("applyDesiredStatusDefault", func() {
+		It("should default an omitted desiredStatus to Running", func() {
+			workspace := workspaceWith("")
+			applyDesiredStatusDefault(workspace)
+			Expect(workspace.Spec.DesiredStatus).To(Equal(controller.DesiredStateRunning))
+		})
+
+		It("should keep an explicit desiredStatus", func() {
+			workspace := workspaceWith(controller.DesiredStateStopped)
+			applyDesiredStatusDefault(workspace)
+			Expect(workspace.Spec.DesiredStatus).To(Equal(controller.DesiredStateStopped))
+		})
+	})
+
+	Describe("isResuming", func() {
+		updateFrom := func(oldWorkspace *workspacev1alpha1.Workspace) admission.Request {
+			raw, err := json.Marshal(oldWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+			return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				OldObject: runtime.RawExtension{Raw: raw},
+			}}
+		}
+
+		It("should detect a paused workspace set back to Running", func() {
+			req := updateFrom(workspaceWith(controller.DesiredStatePaused))
+			Expect(isResuming(req, workspaceWith(controller.DesiredStateRunning))).To(BeTrue())
+		})
+
+		It("should detect a stopped workspace set back to Running", func() {
+			req := updateFrom(workspaceWith(controller.DesiredStateStopped))
+			Expect(isResuming(req, workspaceWith(controller.DesiredStateRunning))).To(BeTrue())
+		})
+
+		It("should detect a hibernated workspace set back to Running", func() {
+			req := updateFrom(workspaceWith(controller.DesiredStateHibernated))
+			Expect(isResuming(req, workspaceWith(controller.DesiredStateRunning))).To(BeTrue())
+		})
+
+		It("should not detect a running workspace updated", func() {
+			req := updateFrom(workspaceWith(controller.DesiredStateRunning))
+			Expect(isResuming(req, workspaceWith(controller.DesiredStateRunning))).To(BeFalse())
+		})
+
+		It("should not detect a resume changing other fields", func() {
+			req := updateFrom(workspaceWith(controller.DesiredStatePaused))
+			workspace := workspaceWith(controller.DesiredStateRunning)
+			workspace.Spec.Image = "jupyter/scipy-notebook:latest"
+			Expect(isResuming(req, workspace)).To(BeFalse())
+		})
+	})
+})
@@ -7,10 +7,12 @@ package v1alpha1
 
 import (
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
 // applyEnvDefaults merges template's BaseEnv into workspace's Env.
-// Workspace env vars take precedence by name (same pattern as baseLabels).
+// Workspace env vars take precedence by name (same pattern as baseLabels). Template vars are appended
+// after the workspace ones in template order; of template vars sharing a name, the first one is kept.
 func applyEnvDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
 	if len(template.Spec.BaseEnv) == 0 {
 		return
@@ -26,6 +28,41 @@ func applyEnvDefaults(workspace *workspacev1alpha1.Workspace, template *workspac
 	for _, e := range template.Spec.BaseEnv {
 		if _, exists := existing[e.Name]; !exists {
 			workspace.Spec.Env = append(workspace.Spec.Env, *e.DeepCopy())
+			existing[e.Name] = struct{}{}
 		}
 	}
 }
+
+// applyEnvFromDefaults merges template's BaseEnvFrom into workspace's EnvFrom.
+// Sources the workspace already lists are not added twice. Sources of a template in another
+// namespace record that namespace, the controller mirrors them into the workspace namespace.
+func applyEnvFromDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if len(template.Spec.BaseEnvFrom) == 0 {
+		return
+	}
+
+	// Build lookup of existing workspace sources
+	existing := make(map[workspaceutil.EnvFromSourceRef]struct{}, len(workspace.Spec.EnvFrom))
+	for _, source := range workspace.Spec.EnvFrom {
+		existing[envFromSourceKey(workspaceutil.ResolveEnvFromSource(workspace, source))] = struct{}{}
+	}
+
+	// Add template sources if not already listed
+	for _, source := range template.Spec.BaseEnvFrom {
+		entry := workspacev1alpha1.EnvFromSource{EnvFromSource: *source.DeepCopy()}
+		if template.Namespace != "" && template.Namespace != workspace.Namespace {
+			entry.SourceNamespace = template.Namespace
+		}
+		key := envFromSourceKey(workspaceutil.ResolveEnvFromSource(workspace, entry))
+		if _, exists := existing[key]; !exists {
+			workspace.Spec.EnvFrom = append(workspace.Spec.EnvFrom, entry)
+			existing[key] = struct{}{}
+		}
+	}
+}
+
+// envFromSourceKey identifies a source regardless of whether it is optional
+func envFromSourceKey(ref workspaceutil.EnvFromSourceRef) workspaceutil.EnvFromSourceRef {
+	ref.Optional = false
+	return ref
+}
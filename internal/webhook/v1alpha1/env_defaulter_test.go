@@ -69,6 +69,29 @@ var _ = Describe("EnvDefaulter", func() {
 		Expect(workspace.Spec.Env[1].Name).To(Equal("T"))
 	})
 
+	It("should merge duplicate names deterministically", func() {
+		workspace.Spec.Env = []corev1.EnvVar{
+			{Name: "MLFLOW_TRACKING_URI", Value: "http://mlflow.team-a"},
+			{Name: "W", Value: "w-val"},
+		}
+		template.Spec.BaseEnv = []corev1.EnvVar{
+			{Name: "T", Value: "first"},
+			{Name: "MLFLOW_TRACKING_URI", Value: "http://mlflow.shared"},
+			{Name: "T", Value: "second"},
+		}
+
+		applyEnvDefaults(workspace, template)
+		Expect(workspace.Spec.Env).To(Equal([]corev1.EnvVar{
+			{Name: "MLFLOW_TRACKING_URI", Value: "http://mlflow.team-a"},
+			{Name: "W", Value: "w-val"},
+			{Name: "T", Value: "first"},
+		}))
+
+		// Defaulting again, as on every update, changes nothing
+		applyEnvDefaults(workspace, template)
+		Expect(workspace.Spec.Env).To(HaveLen(3))
+	})
+
 	It("should deep copy template env vars to prevent mutation", func() {
 		template.Spec.BaseEnv = []corev1.EnvVar{{Name: "X", Value: "original"}}
 
@@ -77,4 +100,62 @@ var _ = Describe("EnvDefaulter", func() {
 
 		Expect(template.Spec.BaseEnv[0].Value).To(Equal("original"))
 	})
+
+	Context("envFrom", func() {
+		telemetry := corev1.EnvFromSource{
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "telemetry"}},
+		}
+
+		BeforeEach(func() {
+			workspace.Namespace = "team-a"
+			template.Namespace = "team-a"
+		})
+
+		It("should add template sources of the workspace namespace without a source namespace", func() {
+			template.Spec.BaseEnvFrom = []corev1.EnvFromSource{telemetry}
+
+			applyEnvFromDefaults(workspace, template)
+
+			Expect(workspace.Spec.EnvFrom).To(HaveLen(1))
+			Expect(workspace.Spec.EnvFrom[0].ConfigMapRef.Name).To(Equal("telemetry"))
+			Expect(workspace.Spec.EnvFrom[0].SourceNamespace).To(BeEmpty())
+		})
+
+		It("should record the namespace of a template in another namespace", func() {
+			template.Namespace = "shared"
+			template.Spec.BaseEnvFrom = []corev1.EnvFromSource{telemetry}
+
+			applyEnvFromDefaults(workspace, template)
+
+			Expect(workspace.Spec.EnvFrom).To(HaveLen(1))
+			Expect(workspace.Spec.EnvFrom[0].SourceNamespace).To(Equal("shared"))
+		})
+
+		It("should not add a source the workspace already lists", func() {
+			optional := true
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{{EnvFromSource: corev1.EnvFromSource{
+				Prefix: "WS_",
+				ConfigMapRef: &corev1.ConfigMapEnvSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "telemetry"},
+					Optional:             &optional,
+				},
+			}}}
+			template.Spec.BaseEnvFrom = []corev1.EnvFromSource{telemetry}
+
+			applyEnvFromDefaults(workspace, template)
+
+			Expect(workspace.Spec.EnvFrom).To(HaveLen(1))
+			Expect(workspace.Spec.EnvFrom[0].Prefix).To(Equal("WS_"))
+		})
+
+		It("should treat a ConfigMap and a Secret of the same name as different sources", func() {
+			template.Spec.BaseEnvFrom = []corev1.EnvFromSource{telemetry, {
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "telemetry"}},
+			}}
+
+			applyEnvFromDefaults(workspace, template)
+
+			Expect(workspace.Spec.EnvFrom).To(HaveLen(2))
+		})
+	})
 })
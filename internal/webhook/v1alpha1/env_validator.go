@@ -6,10 +6,14 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"path"
 	"regexp"
+	"strings"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
 // validateEnvRequirements checks workspace env vars against template's EnvRequirements
@@ -64,3 +68,108 @@ func validateEnvRequirements(workspace *workspacev1alpha1.Workspace, template *w
 
 	return violations
 }
+
+// validateEnvAllowed checks the names of the workspace env vars against the template's AllowedEnvPatterns.
+// The names the template itself sets or requires are always allowed, so that workspaces can override them.
+func validateEnvAllowed(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	patterns := template.Spec.AllowedEnvPatterns
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	for _, e := range workspace.Spec.Env {
+		if templateDeclaresEnv(template, e.Name) || envNameMatches(patterns, e.Name) {
+			continue
+		}
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeEnvNotAllowed,
+			Field:   fmt.Sprintf("spec.env[%s]", e.Name),
+			Message: fmt.Sprintf("Environment variable '%s' is not allowed by template", e.Name),
+			Allowed: strings.Join(patterns, ", "),
+			Actual:  e.Name,
+		})
+	}
+	return violations
+}
+
+// envNameMatches returns true if the name matches one of the glob patterns
+func envNameMatches(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// templateDeclaresEnv returns true if the template lists the env var in its BaseEnv or EnvRequirements
+func templateDeclaresEnv(template *workspacev1alpha1.WorkspaceTemplate, name string) bool {
+	for _, e := range template.Spec.BaseEnv {
+		if e.Name == name {
+			return true
+		}
+	}
+	for _, req := range template.Spec.EnvRequirements {
+		if req.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTemplateEnvPatterns rejects templates whose AllowedEnvPatterns are not valid globs
+func validateTemplateEnvPatterns(template *workspacev1alpha1.WorkspaceTemplate) error {
+	for _, pattern := range template.Spec.AllowedEnvPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("template '%s' has an invalid allowedEnvPatterns entry %q: %w", template.Name, pattern, err)
+		}
+	}
+	return nil
+}
+
+// ValidateEnvFromNamespaces checks that envFrom sources outside the workspace namespace come from its template.
+// The controller copies such sources into the workspace namespace, so a user must not be able to name an
+// arbitrary ConfigMap or Secret of another namespace. On update (oldWorkspace not nil) only added sources
+// are checked, so that removing a source from the template does not block updates of existing workspaces.
+func (tv *TemplateValidator) ValidateEnvFromNamespaces(
+	ctx context.Context, oldWorkspace, workspace *workspacev1alpha1.Workspace) error {
+	previous := map[workspaceutil.EnvFromSourceRef]struct{}{}
+	if oldWorkspace != nil {
+		for _, source := range oldWorkspace.Spec.EnvFrom {
+			previous[envFromSourceKey(workspaceutil.ResolveEnvFromSource(oldWorkspace, source))] = struct{}{}
+		}
+	}
+
+	var template *workspacev1alpha1.WorkspaceTemplate
+	for _, source := range workspace.Spec.EnvFrom {
+		ref := workspaceutil.ResolveEnvFromSource(workspace, source)
+		if _, existed := previous[envFromSourceKey(ref)]; existed || !ref.Mirrored(workspace.Namespace) {
+			continue
+		}
+		if workspace.Spec.TemplateRef == nil {
+			return fmt.Errorf("envFrom %s is outside the workspace namespace: only a template can provide such sources", ref)
+		}
+		if template == nil {
+			var err error
+			if template, err = tv.fetchTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace); err != nil {
+				return err
+			}
+		}
+		if !templateProvidesEnvFrom(template, ref) {
+			return fmt.Errorf("envFrom %s is outside the workspace namespace and is not provided by template '%s'",
+				ref, template.Name)
+		}
+	}
+	return nil
+}
+
+// templateProvidesEnvFrom returns true if the template lists the source in its BaseEnvFrom
+func templateProvidesEnvFrom(template *workspacev1alpha1.WorkspaceTemplate, ref workspaceutil.EnvFromSourceRef) bool {
+	for _, source := range template.Spec.BaseEnvFrom {
+		if envFromSourceKey(workspaceutil.ResolveTemplateEnvFromSource(template, source)) == envFromSourceKey(ref) {
+			return true
+		}
+	}
+	return false
+}
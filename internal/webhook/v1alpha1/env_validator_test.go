@@ -6,9 +6,14 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
@@ -140,4 +145,125 @@ var _ = Describe("EnvValidator", func() {
 			Expect(violations).To(HaveLen(2))
 		})
 	})
+
+	Context("env name allowlist", func() {
+		BeforeEach(func() {
+			template.Spec.AllowedEnvPatterns = []string{"MLFLOW_*", "HTTP_PROXY"}
+		})
+
+		It("should allow any name when the template has no patterns", func() {
+			template.Spec.AllowedEnvPatterns = nil
+			workspace.Spec.Env = []corev1.EnvVar{{Name: "LD_PRELOAD", Value: "/tmp/x.so"}}
+			Expect(validateEnvAllowed(workspace, template)).To(BeNil())
+		})
+
+		It("should allow names matching a pattern", func() {
+			workspace.Spec.Env = []corev1.EnvVar{
+				{Name: "MLFLOW_TRACKING_URI", Value: "http://mlflow"},
+				{Name: "HTTP_PROXY", Value: "http://proxy"},
+			}
+			Expect(validateEnvAllowed(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject names matching no pattern", func() {
+			workspace.Spec.Env = []corev1.EnvVar{
+				{Name: "LD_PRELOAD", Value: "/tmp/x.so"},
+				{Name: "MLFLOW_TRACKING_URI", Value: "http://mlflow"},
+			}
+			violations := validateEnvAllowed(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeEnvNotAllowed))
+			Expect(violations[0].Field).To(Equal("spec.env[LD_PRELOAD]"))
+			Expect(violations[0].Allowed).To(Equal("MLFLOW_*, HTTP_PROXY"))
+		})
+
+		It("should allow the names the template sets or requires", func() {
+			template.Spec.BaseEnv = []corev1.EnvVar{{Name: "JUPYTER_ENABLE_LAB", Value: "yes"}}
+			template.Spec.EnvRequirements = []workspacev1alpha1.EnvRequirement{{Name: "TEAM"}}
+			workspace.Spec.Env = []corev1.EnvVar{
+				{Name: "JUPYTER_ENABLE_LAB", Value: "no"},
+				{Name: "TEAM", Value: "ml"},
+			}
+			Expect(validateEnvAllowed(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject templates with an invalid pattern", func() {
+			Expect(validateTemplateEnvPatterns(template)).To(Succeed())
+			template.Spec.AllowedEnvPatterns = []string{"MLFLOW_[*"}
+			Expect(validateTemplateEnvPatterns(template)).To(MatchError(ContainSubstring("invalid allowedEnvPatterns")))
+		})
+	})
+
+	Context("envFrom namespaces", func() {
+		var (
+			ctx       context.Context
+			validator *TemplateValidator
+			shared    corev1.EnvFromSource
+		)
+
+		crossNamespace := func(name string) workspacev1alpha1.EnvFromSource {
+			return workspacev1alpha1.EnvFromSource{
+				EnvFromSource: corev1.EnvFromSource{
+					SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+				},
+				SourceNamespace: "shared",
+			}
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			shared = corev1.EnvFromSource{
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "telemetry-token"}},
+			}
+			template = &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "org-template", Namespace: "shared"},
+				Spec:       workspacev1alpha1.WorkspaceTemplateSpec{BaseEnvFrom: []corev1.EnvFromSource{shared}},
+			}
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator = NewTemplateValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "shared")
+
+			workspace = &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: "org-template", Namespace: "shared"},
+				},
+			}
+		})
+
+		It("should allow sources of the workspace namespace", func() {
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{{EnvFromSource: shared}}
+
+			Expect(validator.ValidateEnvFromNamespaces(ctx, nil, workspace)).To(Succeed())
+		})
+
+		It("should allow a source of another namespace provided by the template", func() {
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{crossNamespace("telemetry-token")}
+
+			Expect(validator.ValidateEnvFromNamespaces(ctx, nil, workspace)).To(Succeed())
+		})
+
+		It("should reject a source of another namespace the template does not provide", func() {
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{crossNamespace("database-password")}
+
+			err := validator.ValidateEnvFromNamespaces(ctx, nil, workspace)
+			Expect(err).To(MatchError(ContainSubstring("Secret shared/database-password")))
+		})
+
+		It("should reject a source of another namespace on a workspace without template", func() {
+			workspace.Spec.TemplateRef = nil
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{crossNamespace("telemetry-token")}
+
+			err := validator.ValidateEnvFromNamespaces(ctx, nil, workspace)
+			Expect(err).To(MatchError(ContainSubstring("only a template can provide")))
+		})
+
+		It("should not reject on update a source the template no longer provides", func() {
+			workspace.Spec.EnvFrom = []workspacev1alpha1.EnvFromSource{crossNamespace("retired-token")}
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.Image = "jupyter/base-notebook:latest"
+
+			Expect(validator.ValidateEnvFromNamespaces(ctx, oldWorkspace, workspace)).To(Succeed())
+		})
+	})
 })
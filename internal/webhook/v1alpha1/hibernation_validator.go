@@ -0,0 +1,50 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// volumeSnapshotGroupKind is the kind the controller keeps the home volume of hibernated workspaces in
+var volumeSnapshotGroupKind = schema.GroupKind{Group: "snapshot.storage.k8s.io", Kind: "VolumeSnapshot"}
+
+// HibernationValidator rejects hibernating workspaces on clusters without the VolumeSnapshot API
+type HibernationValidator struct {
+	restMapper meta.RESTMapper
+}
+
+// NewHibernationValidator creates a new HibernationValidator
+func NewHibernationValidator(restMapper meta.RESTMapper) *HibernationValidator {
+	return &HibernationValidator{
+		restMapper: restMapper,
+	}
+}
+
+// ValidateHibernationSupported rejects setting desiredStatus to Hibernated when the cluster does not serve
+// the VolumeSnapshot API the home volume is kept in; workspaces already hibernated are left alone
+func (hv *HibernationValidator) ValidateHibernationSupported(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if newWorkspace.Spec.DesiredStatus != controller.DesiredStateHibernated ||
+		(oldWorkspace != nil && oldWorkspace.Spec.DesiredStatus == controller.DesiredStateHibernated) {
+		return nil
+	}
+
+	_, err := hv.restMapper.RESTMapping(volumeSnapshotGroupKind, "v1")
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("desiredStatus %s is not supported on this cluster: it requires the VolumeSnapshot API "+
+			"(snapshot.storage.k8s.io/v1), which is not installed", controller.DesiredStateHibernated)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check the VolumeSnapshot API: %w", err)
+	}
+	return nil
+}
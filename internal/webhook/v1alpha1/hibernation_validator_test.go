@@ -0,0 +1,58 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("HibernationValidator", func() {
+	workspaceWith := func(desiredStatus string) *workspacev1alpha1.Workspace {
+		return &workspacev1alpha1.Workspace{
+			Spec: workspacev1alpha1.WorkspaceSpec{DesiredStatus: desiredStatus},
+		}
+	}
+	snapshotVersion := schema.GroupVersion{Group: "snapshot.storage.k8s.io", Version: "v1"}
+
+	var restMapper *meta.DefaultRESTMapper
+
+	BeforeEach(func() {
+		restMapper = meta.NewDefaultRESTMapper([]schema.GroupVersion{snapshotVersion})
+	})
+
+	It("should reject hibernation on clusters without the VolumeSnapshot API", func() {
+		validator := NewHibernationValidator(restMapper)
+		err := validator.ValidateHibernationSupported(
+			workspaceWith(controller.DesiredStateRunning), workspaceWith(controller.DesiredStateHibernated))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("desiredStatus Hibernated is not supported on this cluster"))
+		Expect(err.Error()).To(ContainSubstring("snapshot.storage.k8s.io/v1"))
+
+		err = validator.ValidateHibernationSupported(nil, workspaceWith(controller.DesiredStateHibernated))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should accept hibernation on clusters with the VolumeSnapshot API", func() {
+		restMapper.Add(snapshotVersion.WithKind("VolumeSnapshot"), meta.RESTScopeNamespace)
+		validator := NewHibernationValidator(restMapper)
+		Expect(validator.ValidateHibernationSupported(
+			workspaceWith(controller.DesiredStateRunning), workspaceWith(controller.DesiredStateHibernated))).To(Succeed())
+	})
+
+	It("should ignore other desired statuses and workspaces already hibernated", func() {
+		validator := NewHibernationValidator(restMapper)
+		Expect(validator.ValidateHibernationSupported(
+			workspaceWith(controller.DesiredStateRunning), workspaceWith(controller.DesiredStateStopped))).To(Succeed())
+		Expect(validator.ValidateHibernationSupported(
+			workspaceWith(controller.DesiredStateHibernated), workspaceWith(controller.DesiredStateHibernated))).To(Succeed())
+	})
+})
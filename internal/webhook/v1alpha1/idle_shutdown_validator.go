@@ -0,0 +1,99 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// overridesIdleShutdown returns true if the workspace sets an idle shutdown other than the template default
+func overridesIdleShutdown(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	return workspace.Spec.IdleShutdown != nil &&
+		!equality.Semantic.DeepEqual(workspace.Spec.IdleShutdown, template.Spec.DefaultIdleShutdown)
+}
+
+// validateIdleShutdownOverride rejects a workspace overriding the idle shutdown of the template when the
+// template does not allow overrides, or with an idle timeout outside of the template bounds. The bounds are
+// inclusive; disabling idle shutdown exceeds any maximum timeout.
+func validateIdleShutdownOverride(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	overrides := template.Spec.IdleShutdownOverrides
+	if overrides == nil || !overridesIdleShutdown(workspace, template) {
+		return nil
+	}
+	idleShutdown := workspace.Spec.IdleShutdown
+
+	if overrides.Allow != nil && !*overrides.Allow {
+		return &TemplateViolation{
+			Type:    ViolationTypeIdleShutdownOverrideNotAllowed,
+			Field:   "spec.idleShutdown",
+			Message: fmt.Sprintf("Template '%s' does not allow workspaces to override idle shutdown", template.Name),
+			Allowed: "the template default idle shutdown",
+			Actual:  describeIdleShutdown(idleShutdown),
+		}
+	}
+
+	minTimeout, maxTimeout := overrides.MinIdleTimeoutInMinutes, overrides.MaxIdleTimeoutInMinutes
+	if !idleShutdown.Enabled {
+		if maxTimeout == nil {
+			return nil
+		}
+		return &TemplateViolation{
+			Type:    ViolationTypeIdleShutdownTimeoutOutOfBounds,
+			Field:   "spec.idleShutdown.enabled",
+			Message: fmt.Sprintf("Template '%s' caps the idle timeout of workspaces: idle shutdown cannot be disabled", template.Name),
+			Allowed: describeIdleTimeoutBounds(minTimeout, maxTimeout),
+			Actual:  describeIdleShutdown(idleShutdown),
+		}
+	}
+	if (minTimeout != nil && idleShutdown.IdleTimeoutInMinutes < *minTimeout) ||
+		(maxTimeout != nil && idleShutdown.IdleTimeoutInMinutes > *maxTimeout) {
+		return &TemplateViolation{
+			Type:    ViolationTypeIdleShutdownTimeoutOutOfBounds,
+			Field:   "spec.idleShutdown.idleTimeoutInMinutes",
+			Message: "Idle timeout is outside of the bounds of the template",
+			Allowed: describeIdleTimeoutBounds(minTimeout, maxTimeout),
+			Actual:  describeIdleShutdown(idleShutdown),
+		}
+	}
+	return nil
+}
+
+// describeIdleShutdown describes an idle shutdown setting in a violation
+func describeIdleShutdown(idleShutdown *workspacev1alpha1.IdleShutdownSpec) string {
+	if !idleShutdown.Enabled {
+		return "disabled"
+	}
+	return fmt.Sprintf("%d minutes", idleShutdown.IdleTimeoutInMinutes)
+}
+
+// describeIdleTimeoutBounds describes the idle timeout bounds of a template in a violation
+func describeIdleTimeoutBounds(minTimeout, maxTimeout *int) string {
+	switch {
+	case minTimeout != nil && maxTimeout != nil:
+		return fmt.Sprintf("between %d and %d minutes", *minTimeout, *maxTimeout)
+	case maxTimeout != nil:
+		return fmt.Sprintf("at most %d minutes", *maxTimeout)
+	default:
+		return fmt.Sprintf("at least %d minutes", *minTimeout)
+	}
+}
+
+// withoutKeptIdleShutdownViolations drops the idle shutdown violations of an update keeping the idle shutdown of
+// the workspace, so that workspaces admitted before their template restricted overrides stay editable
+func withoutKeptIdleShutdownViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if !equality.Semantic.DeepEqual(oldWorkspace.Spec.IdleShutdown, newWorkspace.Spec.IdleShutdown) {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeIdleShutdownOverrideNotAllowed ||
+			violation.Type == ViolationTypeIdleShutdownTimeoutOutOfBounds
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Idle Shutdown Override Validator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "course"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultIdleShutdown: &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 30},
+				IdleShutdownOverrides: &workspacev1alpha1.IdleShutdownOverridePolicy{
+					Allow:                   ptr.To(true),
+					MinIdleTimeoutInMinutes: ptr.To(10),
+					MaxIdleTimeoutInMinutes: ptr.To(3 * 24 * 60),
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{}
+		workspace.Spec.IdleShutdown = template.Spec.DefaultIdleShutdown.DeepCopy()
+	})
+
+	It("should allow workspaces keeping the template idle shutdown", func() {
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+		template.Spec.IdleShutdownOverrides.Allow = ptr.To(false)
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+		workspace.Spec.IdleShutdown = nil
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+	})
+
+	It("should allow an override equal to the maximum timeout", func() {
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 3 * 24 * 60
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+		Expect(ValidateWorkspaceAgainstTemplate(workspace, template)).To(BeEmpty())
+	})
+
+	It("should allow an override equal to the minimum timeout", func() {
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 10
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+	})
+
+	It("should reject an override exceeding the maximum timeout", func() {
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 3*24*60 + 1
+		violation := validateIdleShutdownOverride(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeIdleShutdownTimeoutOutOfBounds))
+		Expect(violation.Field).To(Equal("spec.idleShutdown.idleTimeoutInMinutes"))
+		Expect(violation.Allowed).To(Equal("between 10 and 4320 minutes"))
+		Expect(violation.Actual).To(Equal("4321 minutes"))
+	})
+
+	It("should reject an override below the minimum timeout", func() {
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 9
+		violation := validateIdleShutdownOverride(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeIdleShutdownTimeoutOutOfBounds))
+	})
+
+	It("should reject disabling idle shutdown only when the template caps the timeout", func() {
+		workspace.Spec.IdleShutdown.Enabled = false
+		violation := validateIdleShutdownOverride(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Field).To(Equal("spec.idleShutdown.enabled"))
+		Expect(violation.Actual).To(Equal("disabled"))
+
+		template.Spec.IdleShutdownOverrides.MaxIdleTimeoutInMinutes = nil
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+	})
+
+	It("should reject any override when the template does not allow it", func() {
+		template.Spec.IdleShutdownOverrides.Allow = ptr.To(false)
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 60
+		violation := validateIdleShutdownOverride(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeIdleShutdownOverrideNotAllowed))
+		Expect(violation.Field).To(Equal("spec.idleShutdown"))
+	})
+
+	It("should not constrain workspaces of templates without override policy", func() {
+		template.Spec.IdleShutdownOverrides = nil
+		workspace.Spec.IdleShutdown.Enabled = false
+		Expect(validateIdleShutdownOverride(workspace, template)).To(BeNil())
+	})
+
+	It("should keep admitting updates that do not change an override", func() {
+		oldWorkspace := &workspacev1alpha1.Workspace{}
+		oldWorkspace.Spec.IdleShutdown = &workspacev1alpha1.IdleShutdownSpec{Enabled: true, IdleTimeoutInMinutes: 10000}
+		workspace.Spec.IdleShutdown = oldWorkspace.Spec.IdleShutdown.DeepCopy()
+		violations := []TemplateViolation{*validateIdleShutdownOverride(workspace, template)}
+
+		Expect(withoutKeptIdleShutdownViolations(violations, oldWorkspace, workspace)).To(BeEmpty())
+
+		workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 20000
+		Expect(withoutKeptIdleShutdownViolations(violations, oldWorkspace, workspace)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,20 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyImagePolicyDefaults applies the template image policy pull policy to workspace.
+// Unlike other defaults, a pull policy forced by the template overrides the workspace value.
+func applyImagePolicyDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if template.Spec.ImagePolicy == nil || template.Spec.ImagePolicy.PullPolicy == "" {
+		return
+	}
+
+	workspace.Spec.ImagePullPolicy = template.Spec.ImagePolicy.PullPolicy
+}
@@ -0,0 +1,64 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ImagePolicyDefaulter", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-template",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-workspace",
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{},
+		}
+	})
+
+	Describe("applyImagePolicyDefaults", func() {
+		It("should force the template pull policy", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{PullPolicy: corev1.PullAlways}
+			workspace.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+
+			applyImagePolicyDefaults(workspace, template)
+
+			Expect(workspace.Spec.ImagePullPolicy).To(Equal(corev1.PullAlways))
+		})
+
+		It("should leave the workspace pull policy when the template does not force one", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{ForbidMutableTags: true}
+			workspace.Spec.ImagePullPolicy = corev1.PullNever
+
+			applyImagePolicyDefaults(workspace, template)
+
+			Expect(workspace.Spec.ImagePullPolicy).To(Equal(corev1.PullNever))
+		})
+
+		It("should do nothing without an image policy", func() {
+			applyImagePolicyDefaults(workspace, template)
+
+			Expect(workspace.Spec.ImagePullPolicy).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,111 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// mutableImageTag is the tag that always resolves to the most recent image
+const mutableImageTag = "latest"
+
+// splitImageReference returns the tag and digest of an image reference; either may be empty
+func splitImageReference(image string) (tag string, digest string) {
+	name := image
+	if at := strings.Index(name, "@"); at >= 0 {
+		digest = name[at+1:]
+		name = name[:at]
+	}
+
+	// The tag follows the last colon of the last path component; a colon before
+	// the first slash is a registry port (e.g. registry:5000/image)
+	lastComponent := name
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		lastComponent = name[slash+1:]
+	}
+	if colon := strings.LastIndex(lastComponent, ":"); colon >= 0 {
+		tag = lastComponent[colon+1:]
+	}
+	return tag, digest
+}
+
+// validateImagePolicy checks a single image reference against the template's image policy
+func validateImagePolicy(image, field string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	policy := template.Spec.ImagePolicy
+	if policy == nil || image == "" {
+		return nil
+	}
+
+	tag, digest := splitImageReference(image)
+
+	if policy.RequireDigest && digest == "" {
+		return &TemplateViolation{
+			Type:    ViolationTypeImagePolicyViolation,
+			Field:   field,
+			Message: fmt.Sprintf("Image '%s' is not pinned by digest, which is required by the image policy of template '%s'", image, template.Name),
+			Allowed: "image@sha256:<digest>",
+			Actual:  image,
+		}
+	}
+
+	if policy.ForbidMutableTags && digest == "" {
+		if tag == "" {
+			return &TemplateViolation{
+				Type:    ViolationTypeImagePolicyViolation,
+				Field:   field,
+				Message: fmt.Sprintf("Image '%s' has no tag, and mutable tags are forbidden by the image policy of template '%s'", image, template.Name),
+				Allowed: "explicit non-latest tag or digest",
+				Actual:  image,
+			}
+		}
+		if tag == mutableImageTag {
+			return &TemplateViolation{
+				Type:    ViolationTypeImagePolicyViolation,
+				Field:   field,
+				Message: fmt.Sprintf("Image '%s' uses the mutable tag '%s', which is forbidden by the image policy of template '%s'", image, tag, template.Name),
+				Allowed: "explicit non-latest tag or digest",
+				Actual:  image,
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateImagePullPolicy checks that the workspace uses the pull policy forced by the template
+func validateImagePullPolicy(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	policy := template.Spec.ImagePolicy
+	if policy == nil || policy.PullPolicy == "" || workspace.Spec.ImagePullPolicy == policy.PullPolicy {
+		return nil
+	}
+
+	return &TemplateViolation{
+		Type:    ViolationTypeImagePolicyViolation,
+		Field:   "spec.imagePullPolicy",
+		Message: fmt.Sprintf("Image pull policy '%s' is not allowed by the image policy of template '%s', which requires '%s'", workspace.Spec.ImagePullPolicy, template.Name, policy.PullPolicy),
+		Allowed: string(policy.PullPolicy),
+		Actual:  string(workspace.Spec.ImagePullPolicy),
+	}
+}
+
+// validateTemplateImageOptions checks the template's own image options against its image policy
+func validateTemplateImageOptions(template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	var violations []TemplateViolation
+
+	if violation := validateImagePolicy(template.Spec.DefaultImage, "spec.defaultImage", template); violation != nil {
+		violations = append(violations, *violation)
+	}
+	for i, image := range template.Spec.AllowedImages {
+		if violation := validateImagePolicy(image, fmt.Sprintf("spec.allowedImages[%d]", i), template); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	return violations
+}
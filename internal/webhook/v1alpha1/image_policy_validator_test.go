@@ -0,0 +1,180 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+const testPinnedImage = "registry.example.com:5000/jupyter/base-notebook@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+var _ = Describe("ImagePolicyValidator", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "regulated",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultImage: "jupyter/base-notebook:2024.01",
+			},
+		}
+	})
+
+	Describe("splitImageReference", func() {
+		DescribeTable("should extract tag and digest",
+			func(image, expectedTag, expectedDigest string) {
+				tag, digest := splitImageReference(image)
+				Expect(tag).To(Equal(expectedTag))
+				Expect(digest).To(Equal(expectedDigest))
+			},
+			Entry("no tag", "jupyter/base-notebook", "", ""),
+			Entry("tag", "jupyter/base-notebook:2024.01", "2024.01", ""),
+			Entry("registry port without tag", "registry:5000/jupyter/base-notebook", "", ""),
+			Entry("registry port with tag", "registry:5000/jupyter/base-notebook:latest", "latest", ""),
+			Entry("digest", "jupyter/base-notebook@sha256:abc", "", "sha256:abc"),
+			Entry("tag and digest", "jupyter/base-notebook:1.0@sha256:abc", "1.0", "sha256:abc"),
+		)
+	})
+
+	Describe("validateImagePolicy", func() {
+		It("should accept any image without a policy", func() {
+			Expect(validateImagePolicy("jupyter/base-notebook:latest", "spec.image", template)).To(BeNil())
+		})
+
+		It("should reject the latest tag when mutable tags are forbidden", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{ForbidMutableTags: true}
+
+			violation := validateImagePolicy("jupyter/base-notebook:latest", "spec.image", template)
+
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeImagePolicyViolation))
+			Expect(violation.Field).To(Equal("spec.image"))
+			Expect(violation.Actual).To(Equal("jupyter/base-notebook:latest"))
+			Expect(violation.Message).To(ContainSubstring("'jupyter/base-notebook:latest'"))
+		})
+
+		It("should reject untagged images when mutable tags are forbidden", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{ForbidMutableTags: true}
+
+			violation := validateImagePolicy("registry:5000/jupyter/base-notebook", "spec.image", template)
+
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Message).To(ContainSubstring("'registry:5000/jupyter/base-notebook' has no tag"))
+		})
+
+		It("should accept explicit tags and digests when mutable tags are forbidden", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{ForbidMutableTags: true}
+
+			Expect(validateImagePolicy("jupyter/base-notebook:2024.01", "spec.image", template)).To(BeNil())
+			Expect(validateImagePolicy(testPinnedImage, "spec.image", template)).To(BeNil())
+		})
+
+		It("should reject non-digest references in required-digest mode", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{RequireDigest: true}
+
+			violation := validateImagePolicy("jupyter/base-notebook:2024.01", "spec.image", template)
+
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Message).To(ContainSubstring("'jupyter/base-notebook:2024.01' is not pinned by digest"))
+			Expect(validateImagePolicy(testPinnedImage, "spec.image", template)).To(BeNil())
+		})
+	})
+
+	Describe("validateImagePullPolicy", func() {
+		It("should reject a pull policy different from the one forced by the template", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{PullPolicy: corev1.PullAlways}
+			workspace := &workspacev1alpha1.Workspace{
+				Spec: workspacev1alpha1.WorkspaceSpec{ImagePullPolicy: corev1.PullIfNotPresent},
+			}
+
+			violation := validateImagePullPolicy(workspace, template)
+
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Field).To(Equal("spec.imagePullPolicy"))
+			Expect(violation.Allowed).To(Equal("Always"))
+		})
+
+		It("should accept the forced pull policy", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{PullPolicy: corev1.PullAlways}
+			workspace := &workspacev1alpha1.Workspace{
+				Spec: workspacev1alpha1.WorkspaceSpec{ImagePullPolicy: corev1.PullAlways},
+			}
+
+			Expect(validateImagePullPolicy(workspace, template)).To(BeNil())
+		})
+	})
+
+	Describe("validateTemplateImagePolicy", func() {
+		It("should reject templates whose image options violate the policy", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{ForbidMutableTags: true}
+			template.Spec.AllowedImages = []string{"jupyter/base-notebook:2024.01", "jupyter/scipy-notebook:latest"}
+
+			violations := validateTemplateImageOptions(template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Field).To(Equal("spec.allowedImages[1]"))
+
+			err := validateTemplateImagePolicy(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("'jupyter/scipy-notebook:latest'"))
+		})
+
+		It("should accept templates whose image options satisfy the policy", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{RequireDigest: true}
+			template.Spec.DefaultImage = testPinnedImage
+
+			Expect(validateTemplateImagePolicy(template)).To(Succeed())
+		})
+	})
+
+	Describe("image policy modes", func() {
+		It("should only allow the default image in DefaultOnly mode", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{Mode: workspacev1alpha1.ImagePolicyModeDefaultOnly}
+			template.Spec.AllowedImages = []string{"jupyter/scipy-notebook:2024.01"}
+
+			Expect(validateImageAllowed("jupyter/base-notebook:2024.01", template)).To(BeNil())
+			violation := validateImageAllowed("jupyter/scipy-notebook:2024.01", template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeImageNotAllowed))
+			Expect(violation.Message).To(ContainSubstring("only allows its default image 'jupyter/base-notebook:2024.01'"))
+		})
+
+		It("should match the allowed images as globs in AllowList mode", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{Mode: workspacev1alpha1.ImagePolicyModeAllowList}
+			template.Spec.AllowedImages = []string{"quay.io/jupyter/*:2024-*"}
+
+			Expect(validateImageAllowed("jupyter/base-notebook:2024.01", template)).To(BeNil())
+			Expect(validateImageAllowed("quay.io/jupyter/scipy-notebook:2024-05-01", template)).To(BeNil())
+			for _, image := range []string{"quay.io/jupyter/scipy-notebook:latest", "quay.io/jupyter/nested/notebook:2024-05-01"} {
+				violation := validateImageAllowed(image, template)
+				Expect(violation).NotTo(BeNil(), image)
+				Expect(violation.Message).To(ContainSubstring("[quay.io/jupyter/*:2024-*]"))
+			}
+		})
+
+		It("should allow any image in Any mode", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{Mode: workspacev1alpha1.ImagePolicyModeAny}
+
+			Expect(validateImageAllowed("any/custom:image", template)).To(BeNil())
+		})
+
+		It("should reject AllowList templates whose allowed images are not valid globs", func() {
+			template.Spec.ImagePolicy = &workspacev1alpha1.ImagePolicy{Mode: workspacev1alpha1.ImagePolicyModeAllowList}
+			template.Spec.AllowedImages = []string{"quay.io/jupyter/[a-:*"}
+
+			err := validateTemplateImagePolicy(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.allowedImages[0]"))
+		})
+	})
+})
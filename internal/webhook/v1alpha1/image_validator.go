@@ -7,12 +7,68 @@ package v1alpha1
 
 import (
 	"fmt"
+	"path"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
 
+// imagePolicyMode returns the image policy mode of the template. Templates without a mode keep the
+// behavior of allowCustomImages and allowedImages, whose images are matched exactly.
+func imagePolicyMode(template *workspacev1alpha1.WorkspaceTemplate) workspacev1alpha1.ImagePolicyMode {
+	if template.Spec.ImagePolicy == nil {
+		return ""
+	}
+	return template.Spec.ImagePolicy.Mode
+}
+
+// isTemplateImage returns true if image is the default image of the template or of one of its server types
+func isTemplateImage(image string, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	return image == template.Spec.DefaultImage || isServerTypeImage(image, template)
+}
+
+// isServerTypeImage returns true if image is the image of one of the server types of the template
+func isServerTypeImage(image string, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	for _, serverType := range template.Spec.ServerTypes {
+		if serverType.Image != "" && image == serverType.Image {
+			return true
+		}
+	}
+	return false
+}
+
 // validateImageAllowed checks if image is in template's allowed list
 func validateImageAllowed(image string, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	switch imagePolicyMode(template) {
+	case workspacev1alpha1.ImagePolicyModeAny:
+		return nil
+	case workspacev1alpha1.ImagePolicyModeDefaultOnly:
+		// The defaulter sets the default image of workspaces leaving spec.image empty
+		if isTemplateImage(image, template) {
+			return nil
+		}
+		return &TemplateViolation{
+			Type:  ViolationTypeImageNotAllowed,
+			Field: "spec.image",
+			Message: fmt.Sprintf("Image '%s' is not allowed by template '%s', whose image policy only allows its default image '%s'; leave spec.image empty",
+				image, template.Name, template.Spec.DefaultImage),
+			Allowed: template.Spec.DefaultImage,
+			Actual:  image,
+		}
+	case workspacev1alpha1.ImagePolicyModeAllowList:
+		if isTemplateImage(image, template) || imageMatchesAllowList(image, template.Spec.AllowedImages) {
+			return nil
+		}
+		allowed := append([]string{template.Spec.DefaultImage}, template.Spec.AllowedImages...)
+		return &TemplateViolation{
+			Type:  ViolationTypeImageNotAllowed,
+			Field: "spec.image",
+			Message: fmt.Sprintf("Image '%s' is not allowed by template '%s'. Allowed images: the default image '%s' or images matching %v",
+				image, template.Name, template.Spec.DefaultImage, template.Spec.AllowedImages),
+			Allowed: fmt.Sprintf("%v", allowed),
+			Actual:  image,
+		}
+	}
+
 	// Skip validation if custom images are allowed
 	if template.Spec.AllowCustomImages != nil && *template.Spec.AllowCustomImages {
 		return nil
@@ -22,6 +78,10 @@ func validateImageAllowed(image string, template *workspacev1alpha1.WorkspaceTem
 	if len(template.Spec.AllowedImages) == 0 {
 		effectiveAllowedImages = []string{template.Spec.DefaultImage}
 	}
+	// The images of the server types are allowed like the default image
+	if isServerTypeImage(image, template) {
+		return nil
+	}
 
 	for _, allowed := range effectiveAllowedImages {
 		if image == allowed {
@@ -37,3 +97,42 @@ func validateImageAllowed(image string, template *workspacev1alpha1.WorkspaceTem
 		Actual:  image,
 	}
 }
+
+// explainKeptImageViolations rewords the image violations of an update keeping the image of the workspace:
+// the template restricted its images after the workspace was admitted, which keeps running until its
+// image changes
+func explainKeptImageViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) {
+	if oldWorkspace.Spec.Image == "" || oldWorkspace.Spec.Image != newWorkspace.Spec.Image {
+		return
+	}
+	for i := range violations {
+		if violations[i].Type != ViolationTypeImageNotAllowed {
+			continue
+		}
+		violations[i].Message = fmt.Sprintf("The image of the workspace is no longer allowed since its template changed; "+
+			"set spec.image to an allowed image to update the workspace. %s", violations[i].Message)
+	}
+}
+
+// imageMatchesAllowList returns true if the image matches one of the glob patterns
+func imageMatchesAllowList(image string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, image); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTemplateAllowedImagePatterns rejects AllowList templates whose allowedImages are not valid globs
+func validateTemplateAllowedImagePatterns(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if imagePolicyMode(template) != workspacev1alpha1.ImagePolicyModeAllowList {
+		return nil
+	}
+	for i, pattern := range template.Spec.AllowedImages {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("template '%s' spec.allowedImages[%d] '%s' is not a valid glob: %w", template.Name, i, pattern, err)
+		}
+	}
+	return nil
+}
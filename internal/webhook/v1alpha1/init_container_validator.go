@@ -0,0 +1,23 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validateTemplateInitContainers checks the init containers of the template, which the CRD schema does not
+// describe: like sidecars, each needs a name no sidecar uses and an image, and may only mount the home
+// directory of the workspace. Names of the git clone init containers of the bootstrap are reserved.
+func validateTemplateInitContainers(template *workspacev1alpha1.WorkspaceTemplate) error {
+	names := map[string]struct{}{}
+	for _, sidecar := range template.Spec.Sidecars {
+		names[sidecar.Name] = struct{}{}
+	}
+	return validateTemplateContainers(template, "init container", template.Spec.InitContainers,
+		controller.MaxTemplateInitContainers, controller.TemplateInitContainerConflict, names)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("validateTemplateInitContainers", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "datasets"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName: "Datasets",
+				Sidecars:    []corev1.Container{{Name: "oauth-proxy", Image: "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0"}},
+				InitContainers: []corev1.Container{{
+					Name:         "download-dataset",
+					Image:        "curlimages/curl:8.8.0",
+					VolumeMounts: []corev1.VolumeMount{{Name: "workspace-storage", MountPath: "/data"}},
+				}},
+			},
+		}
+	})
+
+	It("should allow init containers mounting the home directory", func() {
+		Expect(validateTemplateInitContainers(template)).To(Succeed())
+	})
+
+	It("should reject an init container named like a sidecar", func() {
+		template.Spec.InitContainers[0].Name = "oauth-proxy"
+		Expect(validateTemplateInitContainers(template)).To(MatchError(ContainSubstring("more than once")))
+	})
+
+	It("should reject the names of the git clone init containers", func() {
+		template.Spec.InitContainers[0].Name = "git-clone-0"
+		Expect(validateTemplateInitContainers(template)).To(MatchError(ContainSubstring("git clone init containers")))
+	})
+
+	It("should reject an init container without image", func() {
+		template.Spec.InitContainers[0].Image = ""
+		Expect(validateTemplateInitContainers(template)).To(MatchError(ContainSubstring("has no image")))
+	})
+
+	It("should reject an init container mounting another volume", func() {
+		template.Spec.InitContainers[0].VolumeMounts[0].Name = "host-root"
+		Expect(validateTemplateInitContainers(template)).To(
+			MatchError(ContainSubstring("init containers may only mount 'workspace-storage'")))
+	})
+})
@@ -0,0 +1,82 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateIsolationLevel rejects the security contexts of a workspace that weaken the isolation level of its
+// template. Under Rootless, the workspace may not run as root, write its root filesystem, escalate privileges
+// or add capabilities; under both Rootless and UserNamespace, it may not run privileged.
+func validateIsolationLevel(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	level := template.Spec.IsolationLevel
+	if level != workspacev1alpha1.IsolationLevelRootless && level != workspacev1alpha1.IsolationLevelUserNamespace {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	weakened := func(field, actual, allowed string) {
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeIsolationLevelWeakened,
+			Field:   field,
+			Message: fmt.Sprintf("%s weakens the %s isolation level of template '%s'", field, level, template.Name),
+			Allowed: allowed,
+			Actual:  actual,
+		})
+	}
+
+	container := workspace.Spec.ContainerSecurityContext
+	if container != nil && container.Privileged != nil && *container.Privileged {
+		weakened("spec.containerSecurityContext.privileged", "true", "false")
+	}
+	if level != workspacev1alpha1.IsolationLevelRootless {
+		return violations
+	}
+
+	if pod := workspace.Spec.PodSecurityContext; pod != nil {
+		if pod.RunAsNonRoot != nil && !*pod.RunAsNonRoot {
+			weakened("spec.podSecurityContext.runAsNonRoot", "false", "true")
+		}
+		if pod.RunAsUser != nil && *pod.RunAsUser == 0 {
+			weakened("spec.podSecurityContext.runAsUser", "0", "a non-zero UID")
+		}
+	}
+	if container == nil {
+		return violations
+	}
+	if container.RunAsNonRoot != nil && !*container.RunAsNonRoot {
+		weakened("spec.containerSecurityContext.runAsNonRoot", "false", "true")
+	}
+	if container.RunAsUser != nil && *container.RunAsUser == 0 {
+		weakened("spec.containerSecurityContext.runAsUser", "0", "a non-zero UID")
+	}
+	if container.ReadOnlyRootFilesystem != nil && !*container.ReadOnlyRootFilesystem {
+		weakened("spec.containerSecurityContext.readOnlyRootFilesystem", "false", "true")
+	}
+	if container.AllowPrivilegeEscalation != nil && *container.AllowPrivilegeEscalation {
+		weakened("spec.containerSecurityContext.allowPrivilegeEscalation", "true", "false")
+	}
+	if container.Capabilities != nil && len(container.Capabilities.Add) > 0 {
+		weakened("spec.containerSecurityContext.capabilities.add", fmt.Sprint(container.Capabilities.Add), "none")
+	}
+	return violations
+}
+
+// validateTemplateIsolationLevel rejects templates whose default security contexts weaken their own isolation
+// level, which would make every workspace taking the defaults invalid
+func validateTemplateIsolationLevel(template *workspacev1alpha1.WorkspaceTemplate) error {
+	workspace := &workspacev1alpha1.Workspace{}
+	applySecurityDefaults(workspace, template)
+	violations := validateIsolationLevel(workspace, template)
+	if len(violations) == 0 {
+		return nil
+	}
+	return fmt.Errorf("template '%s' default security contexts violate its isolation level: %s",
+		template.Name, formatViolations(violations))
+}
@@ -0,0 +1,103 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("IsolationValidator", func() {
+	var (
+		workspace *workspacev1alpha1.Workspace
+		template  *workspacev1alpha1.WorkspaceTemplate
+	)
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{}
+		template = &workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "untrusted"}}
+	})
+
+	weakeningContainer := func() *corev1.SecurityContext {
+		return &corev1.SecurityContext{
+			RunAsNonRoot:             ptr.To(false),
+			RunAsUser:                ptr.To(int64(0)),
+			ReadOnlyRootFilesystem:   ptr.To(false),
+			AllowPrivilegeEscalation: ptr.To(true),
+			Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+		}
+	}
+
+	It("should accept any security context under the Standard level", func() {
+		template.Spec.IsolationLevel = workspacev1alpha1.IsolationLevelStandard
+		workspace.Spec.ContainerSecurityContext = weakeningContainer()
+		workspace.Spec.ContainerSecurityContext.Privileged = ptr.To(true)
+		Expect(validateIsolationLevel(workspace, template)).To(BeEmpty())
+	})
+
+	It("should reject every weakening field under the Rootless level", func() {
+		template.Spec.IsolationLevel = workspacev1alpha1.IsolationLevelRootless
+		workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{
+			RunAsNonRoot: ptr.To(false),
+			RunAsUser:    ptr.To(int64(0)),
+		}
+		workspace.Spec.ContainerSecurityContext = weakeningContainer()
+
+		violations := validateIsolationLevel(workspace, template)
+		fields := []string{}
+		for _, violation := range violations {
+			Expect(violation.Type).To(Equal(ViolationTypeIsolationLevelWeakened))
+			fields = append(fields, violation.Field)
+		}
+		Expect(fields).To(ConsistOf(
+			"spec.podSecurityContext.runAsNonRoot",
+			"spec.podSecurityContext.runAsUser",
+			"spec.containerSecurityContext.runAsNonRoot",
+			"spec.containerSecurityContext.runAsUser",
+			"spec.containerSecurityContext.readOnlyRootFilesystem",
+			"spec.containerSecurityContext.allowPrivilegeEscalation",
+			"spec.containerSecurityContext.capabilities.add",
+		))
+		Expect(violations[0].Message).To(ContainSubstring("weakens the Rootless isolation level of template 'untrusted'"))
+	})
+
+	It("should accept stricter values under the Rootless level", func() {
+		template.Spec.IsolationLevel = workspacev1alpha1.IsolationLevelRootless
+		workspace.Spec.PodSecurityContext = &corev1.PodSecurityContext{FSGroup: ptr.To(int64(100))}
+		workspace.Spec.ContainerSecurityContext = &corev1.SecurityContext{
+			RunAsUser:    ptr.To(int64(2000)),
+			Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		}
+		Expect(validateIsolationLevel(workspace, template)).To(BeEmpty())
+	})
+
+	It("should only reject privileged containers under the UserNamespace level", func() {
+		template.Spec.IsolationLevel = workspacev1alpha1.IsolationLevelUserNamespace
+		workspace.Spec.ContainerSecurityContext = weakeningContainer()
+		Expect(validateIsolationLevel(workspace, template)).To(BeEmpty())
+
+		workspace.Spec.ContainerSecurityContext.Privileged = ptr.To(true)
+		violations := validateIsolationLevel(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Field).To(Equal("spec.containerSecurityContext.privileged"))
+	})
+
+	It("should reject templates whose defaults weaken their isolation level", func() {
+		template.Spec.IsolationLevel = workspacev1alpha1.IsolationLevelRootless
+		template.Spec.DefaultContainerSecurityContext = &corev1.SecurityContext{ReadOnlyRootFilesystem: ptr.To(false)}
+		err := validateTemplateIsolationLevel(template)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("readOnlyRootFilesystem"))
+
+		template.Spec.DefaultContainerSecurityContext = &corev1.SecurityContext{RunAsUser: ptr.To(int64(1000))}
+		Expect(validateTemplateIsolationLevel(template)).To(Succeed())
+	})
+})
@@ -20,4 +20,14 @@ func applyLifecycleDefaults(workspace *workspacev1alpha1.Workspace, template *wo
 	if workspace.Spec.IdleShutdown == nil && template.Spec.DefaultIdleShutdown != nil {
 		workspace.Spec.IdleShutdown = template.Spec.DefaultIdleShutdown.DeepCopy()
 	}
+
+	// Apply schedule defaults
+	if workspace.Spec.Schedule == nil && template.Spec.DefaultSchedule != nil {
+		workspace.Spec.Schedule = template.Spec.DefaultSchedule.DeepCopy()
+	}
+
+	// Apply retention defaults
+	if workspace.Spec.Retention == nil && template.Spec.Retention != nil {
+		workspace.Spec.Retention = template.Spec.Retention.DeepCopy()
+	}
 }
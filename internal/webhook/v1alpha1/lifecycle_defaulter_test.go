@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
@@ -98,5 +99,33 @@ var _ = Describe("LifecycleDefaulter", func() {
 
 			Expect(workspace.Spec.IdleShutdown.Enabled).To(BeFalse())
 		})
+
+		It("should apply schedule defaults without overriding an existing schedule", func() {
+			template.Spec.DefaultSchedule = &workspacev1alpha1.ScheduleSpec{StartCron: "0 8 * * 1-5", StopCron: "0 18 * * 1-5"}
+
+			applyLifecycleDefaults(workspace, template)
+
+			Expect(workspace.Spec.Schedule).To(Equal(template.Spec.DefaultSchedule))
+			Expect(workspace.Spec.Schedule).NotTo(BeIdenticalTo(template.Spec.DefaultSchedule))
+
+			workspace.Spec.Schedule = &workspacev1alpha1.ScheduleSpec{StopCron: "0 20 * * *"}
+			applyLifecycleDefaults(workspace, template)
+
+			Expect(workspace.Spec.Schedule.StopCron).To(Equal("0 20 * * *"))
+		})
+
+		It("should apply retention defaults without overriding an existing retention", func() {
+			template.Spec.Retention = &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: ptr.To[int64](30 * 86400)}
+
+			applyLifecycleDefaults(workspace, template)
+
+			Expect(workspace.Spec.Retention).To(Equal(template.Spec.Retention))
+			Expect(workspace.Spec.Retention).NotTo(BeIdenticalTo(template.Spec.Retention))
+
+			workspace.Spec.Retention = &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: ptr.To[int64](0)}
+			applyLifecycleDefaults(workspace, template)
+
+			Expect(*workspace.Spec.Retention.DeleteAfterStoppedSeconds).To(BeZero())
+		})
 	})
 })
@@ -0,0 +1,53 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// templatePostStartScript returns the post-start script of the template, or "" when it has none
+func templatePostStartScript(template *workspacev1alpha1.WorkspaceTemplate) string {
+	if template.Spec.Lifecycle == nil {
+		return ""
+	}
+	return template.Spec.Lifecycle.PostStartScript
+}
+
+// chainablePostStartHook returns true if the post-start script can run before the postStart hook of the
+// lifecycle: the hook is an exec hook, or there is none
+func chainablePostStartHook(lifecycle *corev1.Lifecycle) bool {
+	return lifecycle == nil || lifecycle.PostStart == nil || lifecycle.PostStart.Exec != nil
+}
+
+// validatePostStartHook checks the postStart hook of the workspace can run after the post-start script of
+// its template, which runs in the postStart hook of the workspace container
+func validatePostStartHook(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if templatePostStartScript(template) == "" || chainablePostStartHook(workspace.Spec.Lifecycle) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:  ViolationTypePostStartHookConflict,
+		Field: "spec.lifecycle.postStart",
+		Message: fmt.Sprintf("Template '%s' runs a post-start script in the postStart hook of the workspace container; "+
+			"spec.lifecycle.postStart must be an exec hook, which runs after the script", template.Name),
+		Allowed: "exec",
+		Actual:  "non-exec hook",
+	}
+}
+
+// validateTemplateLifecycle rejects templates with a post-start script whose default postStart hook cannot run after it
+func validateTemplateLifecycle(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if templatePostStartScript(template) == "" || chainablePostStartHook(template.Spec.DefaultLifecycle) {
+		return nil
+	}
+	return fmt.Errorf("template '%s' has a post-start script: spec.defaultLifecycle.postStart must be an exec hook, "+
+		"which runs after the script", template.Name)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Lifecycle Validator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+		httpHook  *corev1.Lifecycle
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "conda"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				Lifecycle: &workspacev1alpha1.TemplateLifecycleSpec{PostStartScript: "git config --global pull.rebase true"},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{}
+		httpHook = &corev1.Lifecycle{PostStart: &corev1.LifecycleHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/started"}}}
+	})
+
+	It("should allow workspaces without a postStart hook or with an exec hook", func() {
+		Expect(validatePostStartHook(workspace, template)).To(BeNil())
+		workspace.Spec.Lifecycle = &corev1.Lifecycle{PostStart: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{Command: []string{"touch", "/tmp/started"}},
+		}}
+		Expect(validatePostStartHook(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a non-exec postStart hook under a post-start script", func() {
+		workspace.Spec.Lifecycle = httpHook
+		violation := validatePostStartHook(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypePostStartHookConflict))
+		Expect(violation.Field).To(Equal("spec.lifecycle.postStart"))
+	})
+
+	It("should allow any postStart hook on templates without a post-start script", func() {
+		template.Spec.Lifecycle = nil
+		workspace.Spec.Lifecycle = httpHook
+		Expect(validatePostStartHook(workspace, template)).To(BeNil())
+	})
+
+	It("should reject a template whose default postStart hook cannot run after its script", func() {
+		Expect(validateTemplateLifecycle(template)).To(Succeed())
+		template.Spec.DefaultLifecycle = httpHook
+		Expect(validateTemplateLifecycle(template)).To(MatchError(ContainSubstring("spec.defaultLifecycle.postStart must be an exec hook")))
+	})
+})
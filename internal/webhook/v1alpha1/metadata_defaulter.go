@@ -6,6 +6,11 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"encoding/json"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 	workspacequery "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
@@ -13,6 +18,8 @@ import (
 
 // applyMetadataDefaults applies metadata defaults (labels and annotations) from template to workspace
 func applyMetadataDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	pinned := isTemplateGenerationPinned(workspace, template)
+
 	// Add template tracking label
 	if workspace.Labels == nil {
 		workspace.Labels = make(map[string]string)
@@ -24,10 +31,59 @@ func applyMetadataDefaults(workspace *workspacev1alpha1.Workspace, template *wor
 	templateNamespace := workspacequery.GetTemplateRefNamespace(workspace)
 	workspace.Labels[controller.LabelWorkspaceTemplateNamespace] = templateNamespace
 
+	// Record the template revision the workspace is admitted against
+	if template.Generation > 0 && !pinned {
+		if workspace.Annotations == nil {
+			workspace.Annotations = make(map[string]string)
+		}
+		workspace.Annotations[controller.AnnotationTemplateGeneration] = strconv.FormatInt(template.Generation, 10)
+	}
+
 	// Add template labels to workspace if not already present
 	baseLabels(workspace, template)
 }
 
+// discardClientTemplateGeneration drops the template generation supplied with the write: a new workspace
+// has none and an update keeps the stored one. The generation is only recorded by this webhook when it admits
+// the workspace against its template, so that a forged older generation cannot select an older revision.
+func discardClientTemplateGeneration(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	delete(workspace.Annotations, controller.AnnotationTemplateGeneration)
+	if req.Operation != "UPDATE" {
+		return
+	}
+
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		workspacelog.Error(err, "Failed to decode old workspace, discarding template generation", "workspace", workspace.GetName())
+		return
+	}
+	// The stored generation is kept only for the template it was recorded against
+	if oldWorkspace.Labels[controller.LabelWorkspaceTemplate] != workspace.Labels[controller.LabelWorkspaceTemplate] ||
+		oldWorkspace.Labels[controller.LabelWorkspaceTemplateNamespace] != workspace.Labels[controller.LabelWorkspaceTemplateNamespace] {
+		return
+	}
+	if generation, ok := oldWorkspace.Annotations[controller.AnnotationTemplateGeneration]; ok {
+		if workspace.Annotations == nil {
+			workspace.Annotations = make(map[string]string)
+		}
+		workspace.Annotations[controller.AnnotationTemplateGeneration] = generation
+	}
+}
+
+// isTemplateGenerationPinned returns true if the workspace pins the template generation it was admitted
+// against, and was admitted against this template before. The generation checked is the stored one:
+// discardClientTemplateGeneration has already replaced the one supplied with the write.
+func isTemplateGenerationPinned(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	if workspace.Spec.TemplateRef == nil || workspace.Spec.TemplateRef.UpdatePolicy != workspacev1alpha1.TemplateUpdatePolicyPin {
+		return false
+	}
+	if workspace.Annotations[controller.AnnotationTemplateGeneration] == "" {
+		return false
+	}
+	return workspace.Labels[controller.LabelWorkspaceTemplate] == template.Name &&
+		workspace.Labels[controller.LabelWorkspaceTemplateNamespace] == workspacequery.GetTemplateRefNamespace(workspace)
+}
+
 // baseLabels adds template labels to workspace if the key doesn't already exist
 func baseLabels(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
 	for _, label := range template.Spec.BaseLabels {
@@ -36,3 +92,31 @@ func baseLabels(workspace *workspacev1alpha1.Workspace, template *workspacev1alp
 		}
 	}
 }
+
+// applyPodMetadataDefaults adds the pod labels and annotations of the template the workspace podMetadata
+// does not set: the entries of the workspace win
+func applyPodMetadataDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	defaults := template.Spec.PodMetadata
+	if defaults == nil || (len(defaults.Labels) == 0 && len(defaults.Annotations) == 0) {
+		return
+	}
+	if workspace.Spec.PodMetadata == nil {
+		workspace.Spec.PodMetadata = &workspacev1alpha1.ChildObjectMetadata{}
+	}
+	workspace.Spec.PodMetadata.Labels = withDefaultEntries(workspace.Spec.PodMetadata.Labels, defaults.Labels)
+	workspace.Spec.PodMetadata.Annotations = withDefaultEntries(workspace.Spec.PodMetadata.Annotations, defaults.Annotations)
+}
+
+// withDefaultEntries adds the default entries missing from entries
+func withDefaultEntries(entries, defaults map[string]string) map[string]string {
+	for key, value := range defaults {
+		if _, exists := entries[key]; exists {
+			continue
+		}
+		if entries == nil {
+			entries = make(map[string]string, len(defaults))
+		}
+		entries[key] = value
+	}
+	return entries
+}
@@ -6,9 +6,18 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
+	"encoding/json"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
@@ -60,6 +69,46 @@ var _ = Describe("MetadataDefaulter", func() {
 
 			Expect(workspace.Labels).To(HaveKeyWithValue(controller.LabelWorkspaceTemplate, "production-template"))
 		})
+
+		It("should record the template generation the workspace is admitted against", func() {
+			template.Generation = 3
+			workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "2"}
+
+			applyMetadataDefaults(workspace, template)
+
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "3"))
+		})
+
+		It("should keep the admitted template generation of a workspace pinning it", func() {
+			template.Generation = 3
+			workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{
+				Name:         "production-template",
+				UpdatePolicy: workspacev1alpha1.TemplateUpdatePolicyPin,
+			}
+			workspace.Labels = map[string]string{
+				controller.LabelWorkspaceTemplate:          "production-template",
+				controller.LabelWorkspaceTemplateNamespace: "",
+			}
+			workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "2"}
+
+			applyMetadataDefaults(workspace, template)
+
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "2"))
+		})
+
+		It("should record the generation when a pinned workspace moves to another template", func() {
+			template.Generation = 3
+			workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{
+				Name:         "production-template",
+				UpdatePolicy: workspacev1alpha1.TemplateUpdatePolicyPin,
+			}
+			workspace.Labels = map[string]string{controller.LabelWorkspaceTemplate: "old-template"}
+			workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "7"}
+
+			applyMetadataDefaults(workspace, template)
+
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "3"))
+		})
 	})
 
 	Context("baseLabels", func() {
@@ -101,4 +150,139 @@ var _ = Describe("MetadataDefaulter", func() {
 			Expect(workspace.Labels).To(HaveKeyWithValue(controller.LabelWorkspaceTemplate, "production-template"))
 		})
 	})
+
+	Context("applyPodMetadataDefaults", func() {
+		It("should merge the template pod metadata under the workspace one", func() {
+			template.Spec.PodMetadata = &workspacev1alpha1.ChildObjectMetadata{
+				Labels:      map[string]string{"team": "platform", "cost-center": "cc-1"},
+				Annotations: map[string]string{"example.com/budget": "shared"},
+			}
+			workspace.Spec.PodMetadata = &workspacev1alpha1.ChildObjectMetadata{
+				Labels: map[string]string{"team": "research"},
+			}
+
+			applyPodMetadataDefaults(workspace, template)
+
+			Expect(workspace.Spec.PodMetadata.Labels).To(Equal(map[string]string{"team": "research", "cost-center": "cc-1"}))
+			Expect(workspace.Spec.PodMetadata.Annotations).To(Equal(map[string]string{"example.com/budget": "shared"}))
+			template.Spec.PodMetadata.Labels["cost-center"] = "cc-2"
+			Expect(workspace.Spec.PodMetadata.Labels).To(HaveKeyWithValue("cost-center", "cc-1"))
+		})
+
+		It("should leave workspaces of templates without pod metadata alone", func() {
+			applyPodMetadataDefaults(workspace, template)
+
+			Expect(workspace.Spec.PodMetadata).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("Template generation admission", func() {
+	var (
+		ctx       context.Context
+		defaulter *WorkspaceCustomDefaulter
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	requestContext := func(operation string, oldWorkspace *workspacev1alpha1.Workspace) context.Context {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo:  authenticationv1.UserInfo{Username: "user1"},
+			Operation: admissionv1.Operation(operation),
+		}}
+		if oldWorkspace != nil {
+			raw, err := json.Marshal(oldWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return admission.NewContextWithRequest(ctx, req)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "production-template", Namespace: "team-a", Generation: 5},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  "Production",
+				DefaultImage: "jupyter/base-notebook:latest",
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build()
+		defaulter = &WorkspaceCustomDefaulter{
+			templateDefaulter:       NewTemplateDefaulter(k8sClient, ""),
+			serviceAccountDefaulter: NewServiceAccountDefaulter(k8sClient),
+			templateGetter:          NewTemplateGetter(k8sClient, ""),
+			client:                  k8sClient,
+		}
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DisplayName: "Workspace",
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: "production-template"},
+			},
+		}
+	})
+
+	It("should overwrite an older generation supplied on update with the admitted one", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "4"}
+		workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "1"}
+
+		Expect(defaulter.Default(requestContext("UPDATE", oldWorkspace), workspace)).To(Succeed())
+		Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "5"))
+	})
+
+	It("should ignore a generation supplied on create with forged template labels", func() {
+		workspace.Spec.TemplateRef.UpdatePolicy = workspacev1alpha1.TemplateUpdatePolicyPin
+		workspace.Labels = map[string]string{
+			controller.LabelWorkspaceTemplate:          "production-template",
+			controller.LabelWorkspaceTemplateNamespace: "team-a",
+		}
+		workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "1"}
+
+		Expect(defaulter.Default(requestContext("CREATE", nil), workspace)).To(Succeed())
+		Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "5"))
+	})
+
+	It("should restore the stored generation when a forged one comes with a resume", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.DesiredStatus = controller.DesiredStateStopped
+		oldWorkspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "4"}
+		workspace.Spec.DesiredStatus = controller.DesiredStateRunning
+		workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "1"}
+
+		Expect(defaulter.Default(requestContext("UPDATE", oldWorkspace), workspace)).To(Succeed())
+		Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "4"))
+	})
+
+	Context("when the workspace pins its template generation", func() {
+		var oldWorkspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			workspace.Spec.TemplateRef.UpdatePolicy = workspacev1alpha1.TemplateUpdatePolicyPin
+			workspace.Labels = map[string]string{
+				controller.LabelWorkspaceTemplate:          "production-template",
+				controller.LabelWorkspaceTemplateNamespace: "team-a",
+			}
+			oldWorkspace = workspace.DeepCopy()
+			oldWorkspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "4"}
+		})
+
+		It("should keep the stored generation over the one supplied on update", func() {
+			workspace.Annotations = map[string]string{controller.AnnotationTemplateGeneration: "1"}
+
+			Expect(defaulter.Default(requestContext("UPDATE", oldWorkspace), workspace)).To(Succeed())
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "4"))
+		})
+
+		It("should not keep the generation of another template behind forged template labels", func() {
+			oldWorkspace.Labels[controller.LabelWorkspaceTemplate] = "staging-template"
+
+			Expect(defaulter.Default(requestContext("UPDATE", oldWorkspace), workspace)).To(Succeed())
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationTemplateGeneration, "5"))
+		})
+	})
 })
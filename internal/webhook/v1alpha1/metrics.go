@@ -0,0 +1,48 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Operation label values of the admission latency histogram
+const (
+	admissionOperationDefault        = "default"
+	admissionOperationValidateCreate = "validate_create"
+	admissionOperationValidateUpdate = "validate_update"
+	admissionOperationValidateDelete = "validate_delete"
+)
+
+// Outcome label values of the admission latency histogram
+const (
+	admissionOutcomeAllowed = "allowed"
+	admissionOutcomeDenied  = "denied"
+)
+
+// workspaceAdmissionDuration tracks the latency of workspace admission handlers,
+// which is dominated by template and access strategy lookups
+var workspaceAdmissionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "jupyter_k8s_workspace_admission_duration_seconds",
+	Help:    "Latency of workspace admission webhook handlers by operation and outcome",
+	Buckets: []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+}, []string{"operation", "outcome"})
+
+func init() {
+	metrics.Registry.MustRegister(workspaceAdmissionDuration)
+}
+
+// observeAdmission records the latency of an admission handler that started at start
+func observeAdmission(operation string, start time.Time, err error) {
+	outcome := admissionOutcomeAllowed
+	if err != nil {
+		outcome = admissionOutcomeDenied
+	}
+	workspaceAdmissionDuration.WithLabelValues(operation, outcome).Observe(time.Since(start).Seconds())
+}
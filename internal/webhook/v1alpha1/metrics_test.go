@@ -0,0 +1,38 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// admissionSampleCount returns the number of observations recorded for the operation and outcome
+func admissionSampleCount(operation, outcome string) uint64 {
+	metric := &dto.Metric{}
+	histogram := workspaceAdmissionDuration.WithLabelValues(operation, outcome).(prometheus.Histogram)
+	Expect(histogram.Write(metric)).To(Succeed())
+	return metric.GetHistogram().GetSampleCount()
+}
+
+var _ = Describe("Admission Metrics", func() {
+	It("should record allowed and denied outcomes separately", func() {
+		allowedBefore := admissionSampleCount(admissionOperationValidateDelete, admissionOutcomeAllowed)
+		deniedBefore := admissionSampleCount(admissionOperationValidateDelete, admissionOutcomeDenied)
+
+		observeAdmission(admissionOperationValidateDelete, time.Now(), nil)
+		observeAdmission(admissionOperationValidateDelete, time.Now(), nil)
+		observeAdmission(admissionOperationValidateDelete, time.Now(), errors.New("denied"))
+
+		Expect(admissionSampleCount(admissionOperationValidateDelete, admissionOutcomeAllowed)).To(Equal(allowedBefore + 2))
+		Expect(admissionSampleCount(admissionOperationValidateDelete, admissionOutcomeDenied)).To(Equal(deniedBefore + 1))
+	})
+})
@@ -0,0 +1,54 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PauseReconciliationGroupEnv names the group whose members may pause workspace reconciliation
+// in addition to the controller and admin users
+const PauseReconciliationGroupEnv = "PAUSE_RECONCILIATION_GROUP"
+
+// validatePausedAnnotation rejects adding, changing or removing the paused annotation unless the
+// user belongs to the pause reconciliation group. Controller and admin users are checked upstream.
+// oldWorkspace is nil on create.
+func validatePausedAnnotation(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	var oldValue string
+	var oldExists bool
+	if oldWorkspace != nil {
+		oldValue, oldExists = oldWorkspace.Annotations[controller.AnnotationPaused]
+	}
+	newValue, newExists := newWorkspace.Annotations[controller.AnnotationPaused]
+	if oldExists == newExists && oldValue == newValue {
+		return nil
+	}
+
+	if isPauseReconciliationUser(ctx) {
+		return nil
+	}
+	return fmt.Errorf("annotation '%s' can only be changed by members of the pause reconciliation group", controller.AnnotationPaused)
+}
+
+// isPauseReconciliationUser checks if the user belongs to the configured pause reconciliation group
+func isPauseReconciliationUser(ctx context.Context) bool {
+	pauseGroup := os.Getenv(PauseReconciliationGroupEnv)
+	if pauseGroup == "" {
+		return false
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(req.UserInfo.Groups, pauseGroup)
+}
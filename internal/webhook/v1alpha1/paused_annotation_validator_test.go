@@ -0,0 +1,88 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Paused Annotation Validator", func() {
+
+	var (
+		ctx          context.Context
+		oldWorkspace *workspacev1alpha1.Workspace
+		newWorkspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		oldWorkspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-workspace",
+				Namespace: "default",
+			},
+		}
+		newWorkspace = oldWorkspace.DeepCopy()
+		Expect(os.Setenv(PauseReconciliationGroupEnv, "break-glass")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(PauseReconciliationGroupEnv)).To(Succeed())
+	})
+
+	It("should allow updates that leave the annotation unchanged", func() {
+		oldWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		userCtx := createUserContext(ctx, "UPDATE", "user1")
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+	})
+
+	It("should reject a user outside the group setting the annotation on create", func() {
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		userCtx := createUserContext(ctx, "CREATE", "user1", "system:authenticated")
+		err := validatePausedAnnotation(userCtx, nil, newWorkspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(controller.AnnotationPaused))
+	})
+
+	It("should reject a user outside the group adding, changing or removing the annotation", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+
+		oldWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "false"}
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+
+		newWorkspace.Annotations = nil
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+	})
+
+	It("should allow members of the pause reconciliation group to pause and unpause", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "oncall", "break-glass")
+
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+
+		Expect(validatePausedAnnotation(userCtx, newWorkspace, oldWorkspace)).To(Succeed())
+	})
+
+	It("should reject everyone when no pause reconciliation group is configured", func() {
+		Expect(os.Unsetenv(PauseReconciliationGroupEnv)).To(Succeed())
+		userCtx := createUserContext(ctx, "UPDATE", "oncall", "break-glass")
+		newWorkspace.Annotations = map[string]string{controller.AnnotationPaused: "true"}
+		Expect(validatePausedAnnotation(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+	})
+})
@@ -0,0 +1,59 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validatePodMetadata rejects pod labels and annotations with reserved or invalid keys, and labels with
+// invalid values. Keys with the reserved prefix would let users spoof the labels the controller selects
+// workspace pods by, such as the template namespace.
+func validatePodMetadata(metadata *workspacev1alpha1.ChildObjectMetadata, field string) error {
+	if metadata == nil {
+		return nil
+	}
+	var violations []string
+	for _, key := range slices.Sorted(maps.Keys(metadata.Labels)) {
+		violations = append(violations, validatePodMetadataKey(field, "label", key)...)
+		for _, msg := range validation.IsValidLabelValue(metadata.Labels[key]) {
+			violations = append(violations, fmt.Sprintf("%s label '%s': %s", field, key, msg))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(metadata.Annotations)) {
+		violations = append(violations, validatePodMetadataKey(field, "annotation", key)...)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("invalid pod metadata: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// validateWorkspacePodMetadata rejects workspaces whose spec.podMetadata has reserved or invalid keys or values
+func validateWorkspacePodMetadata(workspace *workspacev1alpha1.Workspace) error {
+	return validatePodMetadata(workspace.Spec.PodMetadata, "spec.podMetadata")
+}
+
+// validatePodMetadataKey checks the key of a pod label or annotation
+func validatePodMetadataKey(field, kind, key string) []string {
+	var violations []string
+	if strings.HasPrefix(key, controller.ReservedMetadataPrefix) {
+		violations = append(violations, fmt.Sprintf("%s %s '%s': keys with prefix '%s' are managed by the controller",
+			field, kind, key, controller.ReservedMetadataPrefix))
+	}
+	for _, msg := range validation.IsQualifiedName(key) {
+		violations = append(violations, fmt.Sprintf("%s %s '%s': %s", field, kind, key, msg))
+	}
+	return violations
+}
@@ -0,0 +1,46 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("PodMetadataValidator", func() {
+	It("should accept cost-allocation labels and annotations", func() {
+		Expect(validatePodMetadata(&workspacev1alpha1.ChildObjectMetadata{
+			Labels:      map[string]string{"team": "research", "example.com/cost-center": "cc-42"},
+			Annotations: map[string]string{"example.com/owner": "Alice Doe"},
+		}, "spec.podMetadata")).To(Succeed())
+		Expect(validatePodMetadata(nil, "spec.podMetadata")).To(Succeed())
+	})
+
+	It("should reject keys with the reserved prefix", func() {
+		err := validatePodMetadata(&workspacev1alpha1.ChildObjectMetadata{
+			Labels: map[string]string{controller.LabelWorkspaceTemplateNamespace: "other-team"},
+		}, "spec.podMetadata")
+
+		Expect(err).To(MatchError(ContainSubstring(
+			"spec.podMetadata label 'workspace.jupyter.org/template-namespace': keys with prefix 'workspace.jupyter.org/' are managed by the controller")))
+
+		err = validatePodMetadata(&workspacev1alpha1.ChildObjectMetadata{
+			Annotations: map[string]string{controller.AnnotationCreatedBy: "alice"},
+		}, "spec.podMetadata")
+		Expect(err).To(MatchError(ContainSubstring("spec.podMetadata annotation")))
+	})
+
+	It("should reject invalid label values", func() {
+		err := validatePodMetadata(&workspacev1alpha1.ChildObjectMetadata{
+			Labels: map[string]string{"owner": "Alice Doe"},
+		}, "spec.podMetadata")
+
+		Expect(err).To(MatchError(ContainSubstring("spec.podMetadata label 'owner'")))
+	})
+})
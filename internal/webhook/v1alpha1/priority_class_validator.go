@@ -0,0 +1,50 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// PriorityClassValidator rejects workspaces naming a PriorityClass that does not exist
+type PriorityClassValidator struct {
+	client client.Client
+}
+
+// NewPriorityClassValidator creates a new PriorityClassValidator
+func NewPriorityClassValidator(k8sClient client.Client) *PriorityClassValidator {
+	return &PriorityClassValidator{
+		client: k8sClient,
+	}
+}
+
+// ValidatePriorityClassExists rejects a workspace whose PriorityClass does not exist, the API server refusing
+// to create its pod. On update (oldWorkspace not nil) only a changed PriorityClass is checked, so that deleting
+// a PriorityClass does not block updates of the workspaces using it.
+func (pv *PriorityClassValidator) ValidatePriorityClassExists(
+	ctx context.Context, oldWorkspace, workspace *workspacev1alpha1.Workspace) error {
+	name := workspace.Spec.PriorityClassName
+	if name == "" || (oldWorkspace != nil && oldWorkspace.Spec.PriorityClassName == name) {
+		return nil
+	}
+
+	priorityClass := &schedulingv1.PriorityClass{}
+	if err := pv.client.Get(ctx, types.NamespacedName{Name: name}, priorityClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("PriorityClass %s of spec.priorityClassName does not exist", name)
+		}
+		return fmt.Errorf("failed to get PriorityClass %s: %w", name, err)
+	}
+	return nil
+}
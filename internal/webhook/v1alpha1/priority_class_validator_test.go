@@ -0,0 +1,63 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("PriorityClassValidator", func() {
+	var (
+		ctx       context.Context
+		validator *PriorityClassValidator
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(schedulingv1.AddToScheme(scheme)).To(Succeed())
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: "notebooks"}, Value: 1000},
+		).Build()
+		validator = NewPriorityClassValidator(k8sClient)
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "team-a"},
+		}
+	})
+
+	It("should allow workspaces without or with an existing PriorityClass", func() {
+		Expect(validator.ValidatePriorityClassExists(ctx, nil, workspace)).To(Succeed())
+		workspace.Spec.PriorityClassName = "notebooks"
+		Expect(validator.ValidatePriorityClassExists(ctx, nil, workspace)).To(Succeed())
+	})
+
+	It("should reject a PriorityClass that does not exist", func() {
+		workspace.Spec.PriorityClassName = "batch-high"
+		err := validator.ValidatePriorityClassExists(ctx, nil, workspace)
+		Expect(err).To(MatchError("PriorityClass batch-high of spec.priorityClassName does not exist"))
+
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.PriorityClassName = "notebooks"
+		Expect(validator.ValidatePriorityClassExists(ctx, oldWorkspace, workspace)).NotTo(Succeed())
+	})
+
+	It("should keep admitting updates of workspaces whose PriorityClass was deleted", func() {
+		workspace.Spec.PriorityClassName = "batch-high"
+		oldWorkspace := workspace.DeepCopy()
+		workspace.Spec.DisplayName = "Research"
+		Expect(validator.ValidatePriorityClassExists(ctx, oldWorkspace, workspace)).To(Succeed())
+	})
+})
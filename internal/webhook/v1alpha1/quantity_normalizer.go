@@ -0,0 +1,256 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// nearMissQuantityPattern splits a value that is not a valid quantity into its number and suffix
+var nearMissQuantityPattern = regexp.MustCompile(`^([+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+))\s*([A-Za-z]*)$`)
+
+// nearMissQuantitySuffixes maps lowercased suffixes users write to the Kubernetes suffix they mean.
+// Suffixes naming bytes (kB, MiB, ...) are read as the unit without the byte.
+var nearMissQuantitySuffixes = map[string]string{
+	"b": "", "byte": "", "bytes": "",
+	"kb": "k", "ki": "Ki", "kib": "Ki",
+	"mb": "M", "mi": "Mi", "mib": "Mi",
+	"g": "G", "gb": "G", "gi": "Gi", "gib": "Gi",
+	"t": "T", "tb": "T", "ti": "Ti", "tib": "Ti",
+	"p": "P", "pb": "P", "pi": "Pi", "pib": "Pi",
+	"e": "E", "eb": "E", "ei": "Ei", "eib": "Ei",
+}
+
+// quantityCorrection is a quantity field whose value the webhook rewrote
+type quantityCorrection struct {
+	// path is the JSON pointer of the field
+	path string
+	// field is the path of the field for messages
+	field    string
+	original string
+	value    string
+	// nearMiss is true when the original value was not a valid quantity
+	nearMiss bool
+}
+
+// warning describes a near-miss correction to the user
+func (c quantityCorrection) warning() string {
+	return fmt.Sprintf("%s: %q is not a valid quantity and was read as %q", c.field, c.original, c.value)
+}
+
+// normalizeQuantity returns the canonical form of a quantity field value, accepting near-miss
+// suffixes and spaces. Integer byte counts are written with binary suffixes.
+func normalizeQuantity(value interface{}, countsBytes bool) (string, bool, error) {
+	var input string
+	switch v := value.(type) {
+	case string:
+		input = v
+	case json.Number:
+		input = v.String()
+	default:
+		return "", false, fmt.Errorf("expected a string or a number, got %T", value)
+	}
+
+	// ParseQuantity reads a suffix without a number, such as "Gi", as zero
+	input = strings.TrimSpace(input)
+	if !strings.ContainsAny(input, "0123456789") {
+		return "", false, fmt.Errorf("quantity %q has no number", input)
+	}
+
+	nearMiss, plain := false, isPlainInteger(input)
+	quantity, err := resource.ParseQuantity(input)
+	if err != nil {
+		match := nearMissQuantityPattern.FindStringSubmatch(input)
+		if match == nil {
+			return "", false, err
+		}
+		suffix, known := match[2], true
+		if _, parseErr := resource.ParseQuantity(match[1] + suffix); parseErr != nil {
+			suffix, known = nearMissQuantitySuffixes[strings.ToLower(suffix)]
+		}
+		if !known {
+			return "", false, err
+		}
+		if quantity, err = resource.ParseQuantity(match[1] + suffix); err != nil {
+			return "", false, err
+		}
+		nearMiss, plain = true, suffix == "" && isPlainInteger(match[1])
+	}
+
+	if countsBytes && quantity.Format == resource.DecimalSI && plain {
+		quantity = *resource.NewQuantity(quantity.Value(), resource.BinarySI)
+	}
+	return quantity.String(), nearMiss, nil
+}
+
+// isPlainInteger returns true if the value is a number of units without a suffix
+func isPlainInteger(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isByteResource returns true if quantities of the resource count bytes
+func isByteResource(name string) bool {
+	switch name {
+	case "memory", "storage", "ephemeral-storage":
+		return true
+	}
+	return strings.HasPrefix(name, "hugepages-")
+}
+
+// normalizeWorkspaceQuantities rewrites the quantity fields of a raw workspace (storage size, resource
+// requests and limits, /tmp size limit) in their canonical form and returns the rewritten object with the corrections.
+// Values that are not quantities even with a tolerant reading are reported as errors.
+func normalizeWorkspaceQuantities(raw []byte) ([]byte, []quantityCorrection, error) {
+	// Numbers are kept as written, rather than read as floats
+	obj := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, nil, err
+	}
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return raw, nil, nil
+	}
+
+	var corrections []quantityCorrection
+	normalizeField := func(parent map[string]interface{}, key string, path []string, countsBytes bool) error {
+		value, ok := parent[key]
+		if !ok || value == nil {
+			return nil
+		}
+		normalized, nearMiss, err := normalizeQuantity(value, countsBytes)
+		field := strings.Join(append(path, key), ".")
+		if err != nil {
+			return fmt.Errorf("%s: %v is not a valid quantity, use a value like \"10Gi\" or \"500m\"", field, value)
+		}
+		if original, isString := value.(string); isString && original == normalized {
+			return nil
+		}
+		parent[key] = normalized
+		corrections = append(corrections, quantityCorrection{
+			path:     jsonPointer(append(path, key)...),
+			field:    field,
+			original: fmt.Sprint(value),
+			value:    normalized,
+			nearMiss: nearMiss,
+		})
+		return nil
+	}
+
+	if storage, ok := spec["storage"].(map[string]interface{}); ok {
+		if err := normalizeField(storage, "size", []string{"spec", "storage"}, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	if tmpVolume, ok := spec["tmpVolume"].(map[string]interface{}); ok {
+		if err := normalizeField(tmpVolume, "sizeLimit", []string{"spec", "tmpVolume"}, true); err != nil {
+			return nil, nil, err
+		}
+	}
+	if resources, ok := spec["resources"].(map[string]interface{}); ok {
+		for _, list := range []string{"requests", "limits"} {
+			quantities, ok := resources[list].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			names := make([]string, 0, len(quantities))
+			for name := range quantities {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if err := normalizeField(quantities, name, []string{"spec", "resources", list}, isByteResource(name)); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+	}
+
+	if len(corrections) == 0 {
+		return raw, nil, nil
+	}
+	normalized, err := json.Marshal(obj)
+	if err != nil {
+		return nil, nil, err
+	}
+	return normalized, corrections, nil
+}
+
+// jsonPointer returns the JSON pointer of a field
+func jsonPointer(path ...string) string {
+	escaped := make([]string, len(path))
+	for i, segment := range path {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(segment, "~", "~0"), "/", "~1")
+	}
+	return "/" + strings.Join(escaped, "/")
+}
+
+// quantityNormalizingHandler canonicalizes the quantity fields of a workspace before the defaulter
+// decodes it, so that near-miss values such as "50GB" or "50 Gi" are admitted with a warning
+// instead of failing to decode. Workspaces outside the operator scope are normalized too: the defaulter
+// of every operator instance decodes them.
+type quantityNormalizingHandler struct {
+	next admission.Handler
+}
+
+var _ admission.Handler = &quantityNormalizingHandler{}
+
+// Handle implements admission.Handler
+func (h *quantityNormalizingHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) ||
+		len(req.Object.Raw) == 0 {
+		return h.next.Handle(ctx, req)
+	}
+
+	normalized, corrections, err := normalizeWorkspaceQuantities(req.Object.Raw)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if len(corrections) == 0 {
+		return h.next.Handle(ctx, req)
+	}
+
+	// The defaulter patch applies to the normalized object: patch the submitted object into it first
+	req.Object.Raw = normalized
+	resp := h.next.Handle(ctx, req)
+	if !resp.Allowed {
+		return resp
+	}
+	patches := make([]jsonpatch.JsonPatchOperation, 0, len(corrections)+len(resp.Patches))
+	for _, correction := range corrections {
+		patches = append(patches, jsonpatch.NewOperation("replace", correction.path, correction.value))
+		if correction.nearMiss {
+			resp.Warnings = append(resp.Warnings, correction.warning())
+		}
+	}
+	resp.Patches = append(patches, resp.Patches...)
+	if resp.PatchType == nil {
+		patchType := admissionv1.PatchTypeJSONPatch
+		resp.PatchType = &patchType
+	}
+	return resp
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// labelingDefaulter stands for the workspace defaulter behind the quantity normalization
+type labelingDefaulter struct{}
+
+func (labelingDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	workspace := obj.(*workspacev1alpha1.Workspace)
+	workspace.Labels = map[string]string{"defaulted": "true"}
+	return nil
+}
+
+var _ = Describe("Quantity Normalizer", func() {
+	DescribeTable("normalizes quantity values",
+		func(value interface{}, countsBytes bool, expected string, expectNearMiss bool) {
+			normalized, nearMiss, err := normalizeQuantity(value, countsBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(normalized).To(Equal(expected))
+			Expect(nearMiss).To(Equal(expectNearMiss))
+		},
+		Entry("canonical value", "50Gi", true, "50Gi", false),
+		Entry("equivalent binary value", "51200Mi", true, "50Gi", false),
+		Entry("fractional binary value", "0.5Gi", true, "512Mi", false),
+		Entry("surrounding spaces", " 10Gi ", true, "10Gi", false),
+		Entry("byte count", json.Number("1073741824"), true, "1Gi", false),
+		Entry("byte count string", "10737418240", true, "10Gi", false),
+		Entry("byte count not a power of two", json.Number("1000"), true, "1k", false),
+		Entry("cpu count", json.Number("2"), false, "2", false),
+		Entry("fractional cpu", "0.5", false, "500m", false),
+		Entry("space before suffix", "50 Gi", true, "50Gi", true),
+		Entry("decimal byte suffix", "50GB", true, "50G", true),
+		Entry("binary byte suffix", "50GiB", true, "50Gi", true),
+		Entry("lowercase binary suffix", "512mi", true, "512Mi", true),
+		Entry("lowercase decimal suffix", "2g", true, "2G", true),
+		Entry("lowercase byte suffix", "512mb", true, "512M", true),
+		Entry("kilobytes", "100 KB", true, "100k", true),
+		Entry("byte unit", "4096 bytes", true, "4Ki", true),
+	)
+
+	DescribeTable("rejects values that are not quantities",
+		func(value interface{}) {
+			_, _, err := normalizeQuantity(value, true)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("no number", "Gi"),
+		Entry("unknown suffix", "50XB"),
+		Entry("two numbers", "50 60Gi"),
+		Entry("empty", ""),
+		Entry("boolean", true),
+	)
+
+	Describe("quantityNormalizingHandler", func() {
+		var handler admission.Handler
+
+		newRequest := func(operation admissionv1.Operation, workspace map[string]interface{}) admission.Request {
+			raw, err := json.Marshal(workspace)
+			Expect(err).NotTo(HaveOccurred())
+			return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			}}
+		}
+		newWorkspace := func(size interface{}, memory interface{}) map[string]interface{} {
+			return map[string]interface{}{
+				"apiVersion": "workspace.jupyter.org/v1alpha1",
+				"kind":       "Workspace",
+				"metadata":   map[string]interface{}{"name": "ws", "namespace": "default"},
+				"spec": map[string]interface{}{
+					"displayName": "Workspace",
+					"storage":     map[string]interface{}{"size": size},
+					"resources": map[string]interface{}{
+						"requests": map[string]interface{}{"memory": memory, "cpu": "500m"},
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			defaulter := admission.WithCustomDefaulter(scheme, &workspacev1alpha1.Workspace{}, labelingDefaulter{})
+			handler = &quantityNormalizingHandler{next: defaulter.Handler}
+		})
+
+		It("should admit near-miss quantities with a warning and patch them first", func() {
+			resp := handler.Handle(context.Background(), newRequest(admissionv1.Create, newWorkspace("50GB", "2 Gi")))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ConsistOf(
+				ContainSubstring(`spec.storage.size: "50GB"`),
+				ContainSubstring(`spec.resources.requests.memory: "2 Gi"`),
+			))
+			Expect(resp.Patches).NotTo(BeEmpty())
+			Expect(resp.Patches[0].Operation).To(Equal("replace"))
+			Expect(resp.Patches[0].Path).To(Equal("/spec/storage/size"))
+			Expect(resp.Patches[0].Value).To(Equal("50G"))
+			Expect(resp.Patches[1].Path).To(Equal("/spec/resources/requests/memory"))
+			Expect(resp.Patches[1].Value).To(Equal("2Gi"))
+
+			// The defaulter still ran on the normalized workspace
+			paths := []string{}
+			for _, patch := range resp.Patches {
+				paths = append(paths, patch.Path)
+			}
+			Expect(paths).To(ContainElement(HavePrefix("/metadata/labels")))
+		})
+
+		It("should canonicalize equivalent representations without a warning", func() {
+			resp := handler.Handle(context.Background(), newRequest(admissionv1.Update, newWorkspace("51200Mi", json.Number("1073741824"))))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(BeEmpty())
+			Expect(resp.Patches[0].Value).To(Equal("50Gi"))
+			Expect(resp.Patches[1].Value).To(Equal("1Gi"))
+		})
+
+		It("should leave canonical workspaces to the defaulter alone", func() {
+			resp := handler.Handle(context.Background(), newRequest(admissionv1.Create, newWorkspace("10Gi", "1Gi")))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(BeEmpty())
+			for _, patch := range resp.Patches {
+				Expect(patch.Path).To(HavePrefix("/metadata"))
+			}
+		})
+
+		It("should normalize the /tmp size limit", func() {
+			workspace := newWorkspace("10Gi", "1Gi")
+			workspace["spec"].(map[string]interface{})["tmpVolume"] = map[string]interface{}{"sizeLimit": "2 GB"}
+
+			resp := handler.Handle(context.Background(), newRequest(admissionv1.Create, workspace))
+
+			Expect(resp.Allowed).To(BeTrue())
+			Expect(resp.Warnings).To(ConsistOf(ContainSubstring(`spec.tmpVolume.sizeLimit: "2 GB"`)))
+			Expect(resp.Patches[0].Path).To(Equal("/spec/tmpVolume/sizeLimit"))
+			Expect(resp.Patches[0].Value).To(Equal("2G"))
+		})
+
+		It("should deny values that are not quantities with the field path", func() {
+			resp := handler.Handle(context.Background(), newRequest(admissionv1.Create, newWorkspace("fifty gigs", "1Gi")))
+
+			Expect(resp.Allowed).To(BeFalse())
+			Expect(resp.Result.Message).To(ContainSubstring("spec.storage.size"))
+		})
+	})
+
+	It("should compare equivalent storage sizes as equal", func() {
+		Expect(storageEqual(
+			&workspacev1alpha1.StorageSpec{Size: resource.MustParse("51200Mi")},
+			&workspacev1alpha1.StorageSpec{Size: resource.MustParse("50Gi")},
+		)).To(BeTrue())
+	})
+})
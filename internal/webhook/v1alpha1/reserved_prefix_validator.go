@@ -14,7 +14,7 @@ import (
 )
 
 // validateReservedPrefixOnCreate rejects any workspace.jupyter.org/ prefixed labels or annotations
-// that are not in the system-managed allow-list.
+// that are not in the system-managed allow-list, or that only the system may set.
 func validateReservedPrefixOnCreate(workspace *workspacev1alpha1.Workspace) error {
 	if err := checkReservedKeys(workspace.Labels, "label"); err != nil {
 		return err
@@ -25,6 +25,7 @@ func validateReservedPrefixOnCreate(workspace *workspacev1alpha1.Workspace) erro
 // validateReservedPrefixOnUpdate rejects user changes to workspace.jupyter.org/ prefixed labels or annotations.
 // For SetOnCreateOnly keys: rejects any value change or removal.
 // For SetAlways keys: allows changes (system will overwrite).
+// For SetBySystemOnly keys: rejects additions, changes, and removals.
 // For unknown labels/annotations with reserved keys: rejects additions, changes, and removals.
 func validateReservedPrefixOnUpdate(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
 	if err := checkReservedKeyChanges(oldWorkspace.Labels, newWorkspace.Labels, "label"); err != nil {
@@ -37,9 +38,13 @@ func validateReservedPrefixOnUpdate(oldWorkspace, newWorkspace *workspacev1alpha
 func checkReservedKeys(metadata map[string]string, kind string) error {
 	for key := range metadata {
 		if strings.HasPrefix(key, controller.ReservedMetadataPrefix) {
-			if _, ok := controller.SystemManagedMetadataKeys[key]; !ok {
+			policy, ok := controller.SystemManagedMetadataKeys[key]
+			if !ok {
 				return fmt.Errorf("%s '%s' uses reserved prefix %s", kind, key, controller.ReservedMetadataPrefix)
 			}
+			if policy == controller.SetBySystemOnly {
+				return fmt.Errorf("%s '%s' can only be set by the system", kind, key)
+			}
 		}
 	}
 	return nil
@@ -65,6 +70,11 @@ func checkReservedKeyChanges(oldMeta, newMeta map[string]string, kind string) er
 		if existed && oldVal != newVal && policy == controller.SetOnCreateOnly {
 			return fmt.Errorf("%s '%s' is immutable", kind, key)
 		}
+
+		// Reject if added or changed reserved key is set by the system only
+		if (!existed || oldVal != newVal) && policy == controller.SetBySystemOnly {
+			return fmt.Errorf("%s '%s' can only be set by the system", kind, key)
+		}
 	}
 
 	// Check for removed keys
@@ -76,7 +86,7 @@ func checkReservedKeyChanges(oldMeta, newMeta map[string]string, kind string) er
 
 			// Reject if deleted reserved key is set on create only
 			policy, isSystem := controller.SystemManagedMetadataKeys[key]
-			if !isSystem || policy == controller.SetOnCreateOnly {
+			if !isSystem || policy == controller.SetOnCreateOnly || policy == controller.SetBySystemOnly {
 				return fmt.Errorf("%s '%s' cannot be removed", kind, key)
 			}
 		}
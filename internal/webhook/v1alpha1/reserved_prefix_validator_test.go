@@ -45,6 +45,15 @@ var _ = Describe("Reserved Prefix Validator", func() {
 			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
 		})
 
+		It("should allow owners to label workspaces retained", func() {
+			workspace.Labels = map[string]string{controller.LabelRetain: "true"}
+			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
+
+			updated := workspace.DeepCopy()
+			delete(updated.Labels, controller.LabelRetain)
+			Expect(validateReservedPrefixOnUpdate(workspace, updated)).To(Succeed())
+		})
+
 		It("should allow workspace with system-managed annotations", func() {
 			workspace.Annotations = map[string]string{
 				controller.AnnotationCreatedBy:     "user1",
@@ -53,6 +62,15 @@ var _ = Describe("Reserved Prefix Validator", func() {
 			Expect(validateReservedPrefixOnCreate(workspace)).To(Succeed())
 		})
 
+		It("should reject workspace with SetBySystemOnly annotation", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationMaintenanceIntent: `{"desiredStatus":"Stopped"}`,
+			}
+			err := validateReservedPrefixOnCreate(workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/maintenance-intent' can only be set by the system"))
+		})
+
 		It("should reject workspace with unknown reserved prefix label", func() {
 			workspace.Labels = map[string]string{
 				"workspace.jupyter.org/custom-label": "value",
@@ -192,5 +210,53 @@ var _ = Describe("Reserved Prefix Validator", func() {
 			}
 			Expect(validateReservedPrefixOnUpdate(oldWorkspace, workspace)).To(Succeed())
 		})
+
+		It("should reject adding SetBySystemOnly annotation", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationCullerIntent: `{"desiredStatus":"Stopped"}`,
+			}
+			err := validateReservedPrefixOnUpdate(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/culler-intent' can only be set by the system"))
+		})
+
+		It("should reject exempting a workspace from the maximum lifetime of its template", func() {
+			workspace.Annotations = map[string]string{
+				controller.AnnotationMaxLifetimeExempt: "true",
+			}
+			err := validateReservedPrefixOnUpdate(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/max-lifetime-exempt' can only be set by the system"))
+		})
+
+		It("should reject users clearing the stale exemption of a workspace", func() {
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationStaleExempt: "true",
+			}
+			workspace.Annotations = map[string]string{}
+			err := validateReservedPrefixOnUpdate(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/stale-exempt' cannot be removed"))
+		})
+
+		It("should reject removing SetBySystemOnly annotation", func() {
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationMaintenanceIntent: `{"desiredStatus":"Stopped"}`,
+			}
+			workspace.Annotations = map[string]string{}
+			err := validateReservedPrefixOnUpdate(oldWorkspace, workspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("annotation 'workspace.jupyter.org/maintenance-intent' cannot be removed"))
+		})
+
+		It("should allow update when SetBySystemOnly annotation is unchanged", func() {
+			oldWorkspace.Annotations = map[string]string{
+				controller.AnnotationCullerIntent: `{"desiredStatus":"Stopped"}`,
+			}
+			workspace.Annotations = map[string]string{
+				controller.AnnotationCullerIntent: `{"desiredStatus":"Stopped"}`,
+			}
+			Expect(validateReservedPrefixOnUpdate(oldWorkspace, workspace)).To(Succeed())
+		})
 	})
 })
@@ -0,0 +1,32 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+// validateResetToDefaultsRequest rejects requesting the reset of an OwnerOnly workspace to its template
+// defaults, which overwrites the settings of its owner, by any user but the owner, administrators included.
+// Removing the annotation, as the controller does once the reset is handled, is not restricted.
+func validateResetToDefaultsRequest(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if newWorkspace.Annotations[controller.AnnotationResetToDefaults] != "true" ||
+		oldWorkspace.Annotations[controller.AnnotationResetToDefaults] == "true" {
+		return nil
+	}
+	if getEffectiveOwnershipType(oldWorkspace.Spec.OwnershipType) != webhookconst.OwnershipTypeOwnerOnly {
+		return nil
+	}
+	if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
+		return fmt.Errorf("annotation '%s' can only be set by the owner of an OwnerOnly workspace", controller.AnnotationResetToDefaults)
+	}
+	return nil
+}
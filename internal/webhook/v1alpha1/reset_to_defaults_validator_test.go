@@ -0,0 +1,67 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	webhookconst "github.com/jupyter-infra/jupyter-k8s/internal/webhook"
+)
+
+var _ = Describe("Reset To Defaults Validator", func() {
+
+	var (
+		ctx          context.Context
+		oldWorkspace *workspacev1alpha1.Workspace
+		newWorkspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		oldWorkspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-workspace",
+				Namespace:   "default",
+				Annotations: map[string]string{controller.AnnotationCreatedBy: "owner-user"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{OwnershipType: "OwnerOnly"},
+		}
+		newWorkspace = oldWorkspace.DeepCopy()
+		newWorkspace.Annotations[controller.AnnotationResetToDefaults] = "true"
+	})
+
+	It("should allow the owner to reset an OwnerOnly workspace", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "owner-user")
+		Expect(validateResetToDefaultsRequest(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+	})
+
+	It("should reject other users resetting an OwnerOnly workspace, administrators included", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "other-user")
+		err := validateResetToDefaultsRequest(userCtx, oldWorkspace, newWorkspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(controller.AnnotationResetToDefaults))
+
+		adminCtx := createUserContext(ctx, "UPDATE", "admin-user", webhookconst.DefaultAdminGroup)
+		Expect(validateResetToDefaultsRequest(adminCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+	})
+
+	It("should allow anyone to reset a Public workspace", func() {
+		oldWorkspace.Spec.OwnershipType = "Public"
+		userCtx := createUserContext(ctx, "UPDATE", "other-user")
+		Expect(validateResetToDefaultsRequest(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+	})
+
+	It("should allow the removal of the annotation once the reset is handled", func() {
+		userCtx := createUserContext(ctx, "UPDATE", "system:serviceaccount:jupyter-k8s-system:jupyter-k8s-controller-manager")
+		Expect(validateResetToDefaultsRequest(userCtx, newWorkspace, oldWorkspace)).To(Succeed())
+	})
+})
@@ -6,7 +6,13 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // applyResourceDefaults applies resource defaults from template to workspace
@@ -15,3 +21,59 @@ func applyResourceDefaults(workspace *workspacev1alpha1.Workspace, template *wor
 		workspace.Spec.Resources = template.Spec.DefaultResources.DeepCopy()
 	}
 }
+
+// applyTmpVolumeDefaults applies the default /tmp volume from template to workspace
+func applyTmpVolumeDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.TmpVolume == nil && template.Spec.DefaultTmpVolume != nil {
+		workspace.Spec.TmpVolume = template.Spec.DefaultTmpVolume.DeepCopy()
+	}
+}
+
+// ApplyEphemeralStorageDefault reserves ephemeral-storage for a new workspace that does not request any,
+// within the bounds of its template. Existing workspaces are left alone: changing their resources
+// would restart them.
+func (td *TemplateDefaulter) ApplyEphemeralStorageDefault(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	var template *workspacev1alpha1.WorkspaceTemplate
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		var err error
+		template, err = td.fetchTemplate(ctx, *workspace.Spec.TemplateRef, workspace.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+	applyEphemeralStorageDefault(workspace, template)
+	return nil
+}
+
+// applyEphemeralStorageDefault sets the ephemeral-storage request of the workspace to
+// controller.DefaultEphemeralStorageRequest, lowered to its limit and kept within the template bounds.
+// Resources the workspace leaves unset get the controller defaults, which would otherwise apply.
+func applyEphemeralStorageDefault(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.Resources == nil {
+		defaults := controller.DefaultResourceRequirements()
+		workspace.Spec.Resources = &defaults
+	}
+	resources := workspace.Spec.Resources
+	if resources.Requests == nil {
+		resources.Requests = controller.DefaultResourceRequirements().Requests
+	}
+	if _, ok := resources.Requests[corev1.ResourceEphemeralStorage]; ok {
+		return
+	}
+
+	request := resource.MustParse(controller.DefaultEphemeralStorageRequest)
+	if limit, ok := resources.Limits[corev1.ResourceEphemeralStorage]; ok && limit.Cmp(request) < 0 {
+		request = limit
+	}
+	if template != nil && template.Spec.ResourceBounds != nil {
+		if bounds, ok := template.Spec.ResourceBounds.Resources[corev1.ResourceEphemeralStorage]; ok {
+			if request.Cmp(bounds.Min) < 0 {
+				request = bounds.Min
+			}
+			if request.Cmp(bounds.Max) > 0 {
+				request = bounds.Max
+			}
+		}
+	}
+	resources.Requests[corev1.ResourceEphemeralStorage] = request
+}
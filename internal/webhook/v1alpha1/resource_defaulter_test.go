@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 var _ = Describe("ResourceDefaulter", func() {
@@ -86,4 +87,80 @@ var _ = Describe("ResourceDefaulter", func() {
 			Expect(template.Spec.DefaultResources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("200m")))
 		})
 	})
+
+	Context("applyTmpVolumeDefaults", func() {
+		It("should apply the template tmp volume when unset", func() {
+			sizeLimit := resource.MustParse("5Gi")
+			template.Spec.DefaultTmpVolume = &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &sizeLimit}
+
+			applyTmpVolumeDefaults(workspace, template)
+
+			Expect(workspace.Spec.TmpVolume).NotTo(BeNil())
+			Expect(workspace.Spec.TmpVolume.SizeLimit.String()).To(Equal("5Gi"))
+		})
+
+		It("should not override the workspace tmp volume", func() {
+			templateLimit, workspaceLimit := resource.MustParse("5Gi"), resource.MustParse("1Gi")
+			template.Spec.DefaultTmpVolume = &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &templateLimit}
+			workspace.Spec.TmpVolume = &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &workspaceLimit}
+
+			applyTmpVolumeDefaults(workspace, template)
+
+			Expect(workspace.Spec.TmpVolume.SizeLimit.String()).To(Equal("1Gi"))
+		})
+	})
+
+	Context("applyEphemeralStorageDefault", func() {
+		defaultRequest := resource.MustParse(controller.DefaultEphemeralStorageRequest)
+
+		It("should add the default request to the controller defaults without a template", func() {
+			applyEphemeralStorageDefault(workspace, nil)
+
+			Expect(workspace.Spec.Resources).NotTo(BeNil())
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(defaultRequest))
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse(controller.DefaultCPURequest)))
+			Expect(workspace.Spec.Resources.Limits).NotTo(HaveKey(corev1.ResourceEphemeralStorage))
+		})
+
+		It("should add the default request to the template defaults", func() {
+			applyResourceDefaults(workspace, template)
+			applyEphemeralStorageDefault(workspace, template)
+
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceCPU]).To(Equal(resource.MustParse("200m")))
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(defaultRequest))
+			Expect(template.Spec.DefaultResources.Requests).NotTo(HaveKey(corev1.ResourceEphemeralStorage))
+		})
+
+		It("should keep an explicit request", func() {
+			workspace.Spec.Resources = &corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("20Gi"),
+			}}
+
+			applyEphemeralStorageDefault(workspace, template)
+
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(resource.MustParse("20Gi")))
+		})
+
+		It("should not request more than the limit", func() {
+			workspace.Spec.Resources = &corev1.ResourceRequirements{Limits: corev1.ResourceList{
+				corev1.ResourceEphemeralStorage: resource.MustParse("512Mi"),
+			}}
+
+			applyEphemeralStorageDefault(workspace, nil)
+
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(resource.MustParse("512Mi")))
+		})
+
+		It("should keep the request within the template bounds", func() {
+			template.Spec.ResourceBounds = &workspacev1alpha1.ResourceBounds{
+				Resources: map[corev1.ResourceName]workspacev1alpha1.ResourceRange{
+					corev1.ResourceEphemeralStorage: {Min: resource.MustParse("10Gi"), Max: resource.MustParse("100Gi")},
+				},
+			}
+
+			applyEphemeralStorageDefault(workspace, template)
+
+			Expect(workspace.Spec.Resources.Requests[corev1.ResourceEphemeralStorage]).To(Equal(resource.MustParse("10Gi")))
+		})
+	})
 })
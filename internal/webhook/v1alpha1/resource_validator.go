@@ -7,10 +7,12 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // validateResourceBounds checks if resources are within template bounds
@@ -19,30 +21,25 @@ func validateResourceBounds(resources corev1.ResourceRequirements, template *wor
 
 	// Validate limits >= requests
 	if resources.Requests != nil && resources.Limits != nil {
-		if cpuRequest, hasRequest := resources.Requests[corev1.ResourceCPU]; hasRequest {
-			if cpuLimit, hasLimit := resources.Limits[corev1.ResourceCPU]; hasLimit {
-				if cpuLimit.Cmp(cpuRequest) < 0 {
-					violations = append(violations, TemplateViolation{
-						Type:    ViolationTypeResourceExceeded,
-						Field:   "spec.resources.limits.cpu",
-						Message: "CPU limit must be greater than or equal to CPU request",
-						Allowed: cpuRequest.String(),
-						Actual:  cpuLimit.String(),
-					})
-				}
-			}
-		}
-		if memRequest, hasRequest := resources.Requests[corev1.ResourceMemory]; hasRequest {
-			if memLimit, hasLimit := resources.Limits[corev1.ResourceMemory]; hasLimit {
-				if memLimit.Cmp(memRequest) < 0 {
-					violations = append(violations, TemplateViolation{
-						Type:    ViolationTypeResourceExceeded,
-						Field:   "spec.resources.limits.memory",
-						Message: "Memory limit must be greater than or equal to memory request",
-						Allowed: memRequest.String(),
-						Actual:  memLimit.String(),
-					})
-				}
+		for _, check := range []struct {
+			name         corev1.ResourceName
+			limitLabel   string
+			requestLabel string
+		}{
+			{corev1.ResourceCPU, "CPU", "CPU"},
+			{corev1.ResourceMemory, "Memory", "memory"},
+			{corev1.ResourceEphemeralStorage, "Ephemeral-storage", "ephemeral-storage"},
+		} {
+			request, hasRequest := resources.Requests[check.name]
+			limit, hasLimit := resources.Limits[check.name]
+			if hasRequest && hasLimit && limit.Cmp(request) < 0 {
+				violations = append(violations, TemplateViolation{
+					Type:    ViolationTypeResourceExceeded,
+					Field:   fmt.Sprintf("spec.resources.limits.%s", check.name),
+					Message: fmt.Sprintf("%s limit must be greater than or equal to %s request", check.limitLabel, check.requestLabel),
+					Allowed: request.String(),
+					Actual:  limit.String(),
+				})
 			}
 		}
 	}
@@ -65,6 +62,72 @@ func validateResourceBounds(resources corev1.ResourceRequirements, template *wor
 	return violations
 }
 
+// validateCarvedSidecarResources checks, under Carved sidecar resource accounting, that the workspace container
+// keeps enough of the resources of the workspace once the template sidecars take theirs
+func validateCarvedSidecarResources(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	var violations []TemplateViolation
+	shortfalls := controller.CarvedResourceShortfalls(controller.RequestedResources(workspace), template.Spec.Sidecars,
+		template.Spec.SidecarResourceAccounting, template.Spec.MinPrimaryContainerResources)
+	for _, shortfall := range shortfalls {
+		violations = append(violations, TemplateViolation{
+			Type:  ViolationTypeSidecarResourcesExceeded,
+			Field: "spec.resources",
+			Message: fmt.Sprintf("Template '%s' takes the resources of its sidecars out of those of the workspace: %s",
+				template.Name, shortfall),
+			Allowed: fmt.Sprintf("min: %v", template.Spec.MinPrimaryContainerResources),
+			Actual:  shortfall,
+		})
+	}
+	return violations
+}
+
+// validateTemplateSidecarResourceAccounting rejects Carved templates whose sidecars leave the workspace container
+// too little of the largest resources the template allows, which no workspace could then be admitted with
+func validateTemplateSidecarResourceAccounting(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.SidecarResourceAccounting != workspacev1alpha1.SidecarResourceAccountingCarved ||
+		template.Spec.ResourceBounds == nil {
+		return nil
+	}
+	largest := corev1.ResourceRequirements{Requests: corev1.ResourceList{}}
+	for name, bounds := range template.Spec.ResourceBounds.Resources {
+		largest.Requests[name] = bounds.Max.DeepCopy()
+	}
+	shortfalls := controller.CarvedResourceShortfalls(largest, template.Spec.Sidecars,
+		template.Spec.SidecarResourceAccounting, template.Spec.MinPrimaryContainerResources)
+	if len(shortfalls) > 0 {
+		return fmt.Errorf("template '%s' takes the resources of its sidecars out of those of the workspace, "+
+			"even its maximum resources leave the workspace container too little: %s", template.Name, strings.Join(shortfalls, "; "))
+	}
+	return nil
+}
+
+// validateGPUCount checks the accelerators requested by the workspace against the template accelerators
+func validateGPUCount(gpuCount *int32, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if gpuCount == nil || *gpuCount == 0 {
+		return nil
+	}
+	accelerators := template.Spec.Accelerators
+	if accelerators == nil {
+		return &TemplateViolation{
+			Type:    ViolationTypeAcceleratorsNotAllowed,
+			Field:   "spec.gpuCount",
+			Message: fmt.Sprintf("Template '%s' defines no accelerators: spec.gpuCount cannot be set", template.Name),
+			Allowed: "0",
+			Actual:  fmt.Sprint(*gpuCount),
+		}
+	}
+	if *gpuCount > accelerators.MaxCount {
+		return &TemplateViolation{
+			Type:    ViolationTypeAcceleratorsExceeded,
+			Field:   "spec.gpuCount",
+			Message: fmt.Sprintf("GPU count %d exceeds the maximum %d allowed by template '%s'", *gpuCount, accelerators.MaxCount, template.Name),
+			Allowed: fmt.Sprintf("max %d", accelerators.MaxCount),
+			Actual:  fmt.Sprint(*gpuCount),
+		}
+	}
+	return nil
+}
+
 // validateResourceListBounds checks a resource list (requests or limits) against template bounds.
 // kind is "request" or "limit", used for field paths and error messages.
 func validateResourceListBounds(
@@ -103,6 +166,28 @@ func validateResourceListBounds(
 	return violations
 }
 
+// validateTmpVolumeSize checks the /tmp volume fits in the ephemeral-storage the template allows
+func validateTmpVolumeSize(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if workspace.Spec.TmpVolume == nil || workspace.Spec.TmpVolume.SizeLimit == nil || template.Spec.ResourceBounds == nil {
+		return nil
+	}
+	bounds, ok := template.Spec.ResourceBounds.Resources[corev1.ResourceEphemeralStorage]
+	if !ok {
+		return nil
+	}
+	sizeLimit := workspace.Spec.TmpVolume.SizeLimit
+	if sizeLimit.Cmp(bounds.Max) <= 0 {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeResourceExceeded,
+		Field:   "spec.tmpVolume.sizeLimit",
+		Message: fmt.Sprintf("tmp volume size limit %s exceeds maximum ephemeral-storage %s allowed by template '%s'", sizeLimit.String(), bounds.Max.String(), template.Name),
+		Allowed: fmt.Sprintf("max: %s", bounds.Max.String()),
+		Actual:  sizeLimit.String(),
+	}
+}
+
 // resourcesEqual compares two ResourceRequirements for equality
 func resourcesEqual(old, new *corev1.ResourceRequirements) bool {
 	if old == nil && new == nil {
@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
@@ -264,6 +265,20 @@ var _ = Describe("Resource Validator", func() {
 			Expect(violations).To(HaveLen(1))
 			Expect(violations[0].Message).To(ContainSubstring("Memory limit must be greater than or equal to memory request"))
 		})
+
+		It("should reject ephemeral-storage limit less than request", func() {
+			resources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceEphemeralStorage: resource.MustParse("10Gi"),
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceEphemeralStorage: resource.MustParse("5Gi"),
+				},
+			}
+			violations := validateResourceBounds(resources, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Field).To(Equal("spec.resources.limits.ephemeral-storage"))
+		})
 	})
 
 	Context("GPU bounds validation", func() {
@@ -384,6 +399,44 @@ var _ = Describe("Resource Validator", func() {
 		})
 	})
 
+	Context("ephemeral-storage bounds validation", func() {
+		BeforeEach(func() {
+			template.Spec.ResourceBounds.Resources[corev1.ResourceEphemeralStorage] = workspacev1alpha1.ResourceRange{
+				Min: resource.MustParse("1Gi"),
+				Max: resource.MustParse("20Gi"),
+			}
+		})
+
+		It("should reject ephemeral-storage requests above the template maximum", func() {
+			resources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceEphemeralStorage: resource.MustParse("50Gi"),
+				},
+			}
+			violations := validateResourceBounds(resources, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Field).To(Equal("spec.resources.requests.ephemeral-storage"))
+		})
+
+		It("should reject a tmp volume larger than the template maximum", func() {
+			sizeLimit := resource.MustParse("30Gi")
+			workspace := &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+				TmpVolume: &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &sizeLimit},
+			}}
+			violation := validateTmpVolumeSize(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Field).To(Equal("spec.tmpVolume.sizeLimit"))
+		})
+
+		It("should allow a tmp volume within the template maximum", func() {
+			sizeLimit := resource.MustParse("10Gi")
+			workspace := &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+				TmpVolume: &workspacev1alpha1.TmpVolumeSpec{SizeLimit: &sizeLimit},
+			}}
+			Expect(validateTmpVolumeSize(workspace, template)).To(BeNil())
+		})
+	})
+
 	Context("resourcesEqual", func() {
 		It("should return true for nil resources", func() {
 			Expect(resourcesEqual(nil, nil)).To(BeTrue())
@@ -429,4 +482,93 @@ var _ = Describe("Resource Validator", func() {
 			Expect(resourcesEqual(resources1, resources2)).To(BeFalse())
 		})
 	})
+
+	Context("gpu count validation", func() {
+		It("should allow no gpu count on a template without accelerators", func() {
+			Expect(validateGPUCount(nil, template)).To(BeNil())
+			Expect(validateGPUCount(ptr.To[int32](0), template)).To(BeNil())
+		})
+
+		It("should reject a gpu count on a template without accelerators", func() {
+			violation := validateGPUCount(ptr.To[int32](1), template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeAcceleratorsNotAllowed))
+			Expect(violation.Field).To(Equal("spec.gpuCount"))
+		})
+
+		It("should allow a gpu count up to the template maximum", func() {
+			template.Spec.Accelerators = &workspacev1alpha1.AcceleratorSpec{MaxCount: 2}
+			Expect(validateGPUCount(ptr.To[int32](2), template)).To(BeNil())
+		})
+
+		It("should reject a gpu count above the template maximum", func() {
+			template.Spec.Accelerators = &workspacev1alpha1.AcceleratorSpec{MaxCount: 2}
+			violation := validateGPUCount(ptr.To[int32](3), template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeAcceleratorsExceeded))
+			Expect(violation.Allowed).To(Equal("max 2"))
+			Expect(violation.Actual).To(Equal("3"))
+		})
+	})
+
+	Context("carved sidecar resources", func() {
+		var workspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			template.Spec.SidecarResourceAccounting = workspacev1alpha1.SidecarResourceAccountingCarved
+			template.Spec.MinPrimaryContainerResources = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}
+			template.Spec.Sidecars = []corev1.Container{{
+				Name:  "oauth-proxy",
+				Image: "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m")},
+				},
+			}}
+			workspace = &workspacev1alpha1.Workspace{
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+					},
+				},
+			}
+		})
+
+		It("should allow a workspace container keeping the template minimum", func() {
+			Expect(validateCarvedSidecarResources(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject a workspace container left below the template minimum", func() {
+			workspace.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("600m")
+			violations := validateCarvedSidecarResources(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeSidecarResourcesExceeded))
+			Expect(violations[0].Message).To(ContainSubstring("cpu request 600m less 250m for the sidecars leaves 350m"))
+		})
+
+		It("should carve the default resources of a workspace requesting none", func() {
+			workspace.Spec.Resources = nil
+			template.Spec.Sidecars[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("2Gi")
+			violations := validateCarvedSidecarResources(workspace, template)
+			Expect(violations).To(HaveLen(2))
+			Expect(violations[0].Message).To(ContainSubstring("cpu request 100m less 250m for the sidecars leaves -150m"))
+			Expect(violations[1].Message).To(ContainSubstring("memory request 128Mi less 2Gi for the sidecars"))
+		})
+
+		It("should allow any workspace under additive accounting", func() {
+			template.Spec.SidecarResourceAccounting = workspacev1alpha1.SidecarResourceAccountingAdditive
+			workspace.Spec.Resources.Requests[corev1.ResourceCPU] = resource.MustParse("100m")
+			Expect(validateCarvedSidecarResources(workspace, template)).To(BeEmpty())
+		})
+
+		It("should accept a template whose maximum resources leave the workspace container its minimum", func() {
+			Expect(validateTemplateSidecarResourceAccounting(template)).To(Succeed())
+		})
+
+		It("should reject a template whose sidecars take more than its maximum resources", func() {
+			template.Spec.Sidecars[0].Resources.Requests[corev1.ResourceCPU] = resource.MustParse("2")
+			err := validateTemplateSidecarResourceAccounting(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("even its maximum resources leave the workspace container too little"))
+		})
+	})
 })
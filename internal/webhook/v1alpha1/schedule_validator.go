@@ -0,0 +1,35 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validateSchedule rejects a workspace schedule whose cron expressions or time zone the controller cannot evaluate
+func validateSchedule(workspace *workspacev1alpha1.Workspace) error {
+	if workspace.Spec.Schedule == nil {
+		return nil
+	}
+	if _, _, err := controller.ParseSchedule(workspace.Spec.Schedule); err != nil {
+		return fmt.Errorf("spec.schedule: %w", err)
+	}
+	return nil
+}
+
+// validateTemplateSchedule rejects a default schedule whose cron expressions or time zone the controller cannot evaluate
+func validateTemplateSchedule(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.DefaultSchedule == nil {
+		return nil
+	}
+	if _, _, err := controller.ParseSchedule(template.Spec.DefaultSchedule); err != nil {
+		return fmt.Errorf("spec.defaultSchedule: %w", err)
+	}
+	return nil
+}
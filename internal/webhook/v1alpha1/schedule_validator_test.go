@@ -0,0 +1,75 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Schedule validation", func() {
+	var workspace *workspacev1alpha1.Workspace
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "classroom", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Schedule: &workspacev1alpha1.ScheduleSpec{
+					StartCron: "0 8 * * MON-FRI",
+					StopCron:  "0 18 * * MON-FRI",
+					TimeZone:  "Europe/Paris",
+				},
+			},
+		}
+	})
+
+	It("should accept a schedule the controller can evaluate", func() {
+		Expect(validateSchedule(workspace)).To(Succeed())
+
+		workspace.Spec.Schedule = &workspacev1alpha1.ScheduleSpec{StopCron: "@daily"}
+		Expect(validateSchedule(workspace)).To(Succeed())
+
+		workspace.Spec.Schedule = nil
+		Expect(validateSchedule(workspace)).To(Succeed())
+	})
+
+	It("should reject invalid cron expressions, naming the field", func() {
+		workspace.Spec.Schedule.StopCron = "0 25 * * *"
+		Expect(validateSchedule(workspace)).To(MatchError(ContainSubstring("spec.schedule: invalid stopCron")))
+
+		workspace.Spec.Schedule.StopCron = ""
+		workspace.Spec.Schedule.StartCron = "8am"
+		Expect(validateSchedule(workspace)).To(MatchError(ContainSubstring("spec.schedule: invalid startCron")))
+	})
+
+	It("should reject unknown time zones", func() {
+		workspace.Spec.Schedule.TimeZone = "Europe/Atlantis"
+		Expect(validateSchedule(workspace)).To(MatchError(ContainSubstring("unknown time zone")))
+	})
+
+	It("should reject an invalid template default schedule on create and update", func() {
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "classroom", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:     "Classroom",
+				DefaultImage:    "jupyter/base-notebook:latest",
+				DefaultSchedule: &workspacev1alpha1.ScheduleSpec{StartCron: "0 8 * * 1-5", StopCron: "0 18 * * 1-5"},
+			},
+		}
+		_, err := (&WorkspaceTemplateCustomValidator{}).ValidateCreate(context.Background(), template)
+		Expect(err).NotTo(HaveOccurred())
+
+		newTemplate := template.DeepCopy()
+		newTemplate.Spec.DefaultSchedule.StopCron = "0 18 * * 1-8"
+		_, err = (&WorkspaceTemplateCustomValidator{}).ValidateUpdate(context.Background(), template, newTemplate)
+		Expect(err).To(MatchError(ContainSubstring("spec.defaultSchedule: invalid stopCron")))
+	})
+})
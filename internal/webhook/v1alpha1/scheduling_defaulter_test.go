@@ -174,5 +174,16 @@ var _ = Describe("SchedulingDefaulter", func() {
 			Expect(workspace.Spec.Tolerations).NotTo(BeNil())
 			Expect(workspace.Spec.Tolerations).To(BeEmpty())
 		})
+
+		It("should apply the priority class default when unset", func() {
+			template.Spec.DefaultPriorityClassName = "notebooks"
+
+			applySchedulingDefaults(workspace, template)
+			Expect(workspace.Spec.PriorityClassName).To(Equal("notebooks"))
+
+			workspace.Spec.PriorityClassName = "notebooks-high"
+			applySchedulingDefaults(workspace, template)
+			Expect(workspace.Spec.PriorityClassName).To(Equal("notebooks-high"))
+		})
 	})
 })
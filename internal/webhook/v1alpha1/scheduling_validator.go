@@ -0,0 +1,179 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateSchedulingPolicy checks the node selector, node affinity and tolerations of the workspace against the
+// template's SchedulingPolicy. The scheduling defaults of the template are always allowed.
+func validateSchedulingPolicy(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	policy := template.Spec.SchedulingPolicy
+	if policy == nil {
+		return nil
+	}
+
+	var violations []TemplateViolation
+	if len(policy.AllowedNodeSelectors) > 0 {
+		allowed := describeNodeSelectorPatterns(policy.AllowedNodeSelectors)
+		for _, key := range slices.Sorted(maps.Keys(workspace.Spec.NodeSelector)) {
+			value := workspace.Spec.NodeSelector[key]
+			if defaultValue, found := template.Spec.DefaultNodeSelector[key]; found && defaultValue == value {
+				continue
+			}
+			if nodeSelectorMatches(policy.AllowedNodeSelectors, key, value) {
+				continue
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeSchedulingNotAllowed,
+				Field:   fmt.Sprintf("spec.nodeSelector[%s]", key),
+				Message: fmt.Sprintf("Node selector '%s=%s' is not allowed by template", key, value),
+				Allowed: allowed,
+				Actual:  fmt.Sprintf("%s=%s", key, value),
+			})
+		}
+
+		// A node affinity would place the pod on the nodes the node selector allowlist keeps it off
+		if overridesNodeAffinity(workspace, template) {
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeSchedulingNotAllowed,
+				Field:   "spec.affinity.nodeAffinity",
+				Message: fmt.Sprintf("Template '%s' restricts the node selector of workspaces: node affinity cannot be set", template.Name),
+				Allowed: "the template default node affinity",
+				Actual:  "a custom node affinity",
+			})
+		}
+	}
+
+	if len(policy.AllowedTolerationKeys) > 0 {
+		for i, toleration := range workspace.Spec.Tolerations {
+			if templateDeclaresToleration(template, toleration) || tolerationKeyMatches(policy.AllowedTolerationKeys, toleration.Key) {
+				continue
+			}
+			violations = append(violations, TemplateViolation{
+				Type:    ViolationTypeSchedulingNotAllowed,
+				Field:   fmt.Sprintf("spec.tolerations[%d]", i),
+				Message: fmt.Sprintf("Toleration of key '%s' is not allowed by template", toleration.Key),
+				Allowed: strings.Join(policy.AllowedTolerationKeys, ", "),
+				Actual:  toleration.Key,
+			})
+		}
+	}
+	return violations
+}
+
+// validatePriorityClassAllowed checks the priority class of the workspace against the template's
+// AllowedPriorityClassNames. The default priority class of the template is always allowed.
+func validatePriorityClassAllowed(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	allowed := template.Spec.AllowedPriorityClassNames
+	name := workspace.Spec.PriorityClassName
+	if len(allowed) == 0 || name == "" || name == template.Spec.DefaultPriorityClassName || slices.Contains(allowed, name) {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypePriorityClassNotAllowed,
+		Field:   "spec.priorityClassName",
+		Message: fmt.Sprintf("PriorityClass '%s' is not allowed by template", name),
+		Allowed: strings.Join(allowed, ", "),
+		Actual:  name,
+	}
+}
+
+// nodeSelectorMatches returns true if the node selector entry matches one of the patterns
+func nodeSelectorMatches(patterns []workspacev1alpha1.NodeSelectorPattern, key, value string) bool {
+	for _, pattern := range patterns {
+		keyMatched, keyErr := path.Match(pattern.Key, key)
+		valueMatched, valueErr := path.Match(pattern.Value, value)
+		if keyErr == nil && valueErr == nil && keyMatched && valueMatched {
+			return true
+		}
+	}
+	return false
+}
+
+// tolerationKeyMatches returns true if the toleration key matches one of the glob patterns
+func tolerationKeyMatches(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, key); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// describeNodeSelectorPatterns describes the node selector patterns of a template in a violation
+func describeNodeSelectorPatterns(patterns []workspacev1alpha1.NodeSelectorPattern) string {
+	described := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		described = append(described, fmt.Sprintf("%s=%s", pattern.Key, pattern.Value))
+	}
+	return strings.Join(described, ", ")
+}
+
+// overridesNodeAffinity returns true if the workspace sets a node affinity other than the template default
+func overridesNodeAffinity(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	if workspace.Spec.Affinity == nil || workspace.Spec.Affinity.NodeAffinity == nil {
+		return false
+	}
+	var defaultNodeAffinity *corev1.NodeAffinity
+	if template.Spec.DefaultAffinity != nil {
+		defaultNodeAffinity = template.Spec.DefaultAffinity.NodeAffinity
+	}
+	return !equality.Semantic.DeepEqual(workspace.Spec.Affinity.NodeAffinity, defaultNodeAffinity)
+}
+
+// templateDeclaresToleration returns true if the toleration is one of the template DefaultTolerations
+func templateDeclaresToleration(template *workspacev1alpha1.WorkspaceTemplate, toleration corev1.Toleration) bool {
+	return slices.ContainsFunc(template.Spec.DefaultTolerations, func(defaultToleration corev1.Toleration) bool {
+		return equality.Semantic.DeepEqual(defaultToleration, toleration)
+	})
+}
+
+// withoutKeptSchedulingViolations drops the scheduling and priority class violations of an update keeping the
+// node selector, affinity, tolerations and priority class of the workspace, so that workspaces admitted before
+// their template restricted scheduling stay editable
+func withoutKeptSchedulingViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if !equality.Semantic.DeepEqual(oldWorkspace.Spec.NodeSelector, newWorkspace.Spec.NodeSelector) ||
+		!equality.Semantic.DeepEqual(oldWorkspace.Spec.Affinity, newWorkspace.Spec.Affinity) ||
+		!equality.Semantic.DeepEqual(oldWorkspace.Spec.Tolerations, newWorkspace.Spec.Tolerations) ||
+		oldWorkspace.Spec.PriorityClassName != newWorkspace.Spec.PriorityClassName {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeSchedulingNotAllowed || violation.Type == ViolationTypePriorityClassNotAllowed
+	})
+}
+
+// validateTemplateSchedulingPolicy rejects templates whose SchedulingPolicy patterns are not valid globs
+func validateTemplateSchedulingPolicy(template *workspacev1alpha1.WorkspaceTemplate) error {
+	policy := template.Spec.SchedulingPolicy
+	if policy == nil {
+		return nil
+	}
+	for _, pattern := range policy.AllowedNodeSelectors {
+		for _, glob := range []string{pattern.Key, pattern.Value} {
+			if _, err := path.Match(glob, ""); err != nil {
+				return fmt.Errorf("template '%s' has an invalid allowedNodeSelectors entry %q: %w", template.Name, glob, err)
+			}
+		}
+	}
+	for _, pattern := range policy.AllowedTolerationKeys {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("template '%s' has an invalid allowedTolerationKeys entry %q: %w", template.Name, pattern, err)
+		}
+	}
+	return nil
+}
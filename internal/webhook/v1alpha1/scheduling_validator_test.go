@@ -0,0 +1,183 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("Scheduling Policy Validator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "research"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultNodeSelector: map[string]string{"pool": "standard"},
+				DefaultTolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "jupyter", Effect: corev1.TaintEffectNoSchedule},
+				},
+				SchedulingPolicy: &workspacev1alpha1.SchedulingPolicy{
+					AllowedNodeSelectors: []workspacev1alpha1.NodeSelectorPattern{
+						{Key: "pool", Value: "highmem"},
+						{Key: "topology.kubernetes.io/zone", Value: "*"},
+					},
+					AllowedTolerationKeys: []string{"gpu-*"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{}
+	})
+
+	It("should allow the template defaults and the allowed entries", func() {
+		workspace.Spec.NodeSelector = map[string]string{"pool": "standard"}
+		workspace.Spec.Tolerations = template.Spec.DefaultTolerations
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+
+		workspace.Spec.NodeSelector = map[string]string{"pool": "highmem", "topology.kubernetes.io/zone": "eu-west-1b"}
+		workspace.Spec.Tolerations = []corev1.Toleration{{Key: "gpu-a100", Operator: corev1.TolerationOpExists}}
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+		Expect(ValidateWorkspaceAgainstTemplate(workspace, template)).To(BeEmpty())
+	})
+
+	It("should reject node selector entries matching no pattern", func() {
+		workspace.Spec.NodeSelector = map[string]string{"pool": "gpu", "kubernetes.io/hostname": "node-1"}
+		violations := validateSchedulingPolicy(workspace, template)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Type).To(Equal(ViolationTypeSchedulingNotAllowed))
+		Expect(violations[0].Field).To(Equal("spec.nodeSelector[kubernetes.io/hostname]"))
+		Expect(violations[1].Field).To(Equal("spec.nodeSelector[pool]"))
+		Expect(violations[1].Allowed).To(Equal("pool=highmem, topology.kubernetes.io/zone=*"))
+		Expect(violations[1].Actual).To(Equal("pool=gpu"))
+	})
+
+	It("should reject a node affinity other than the template default", func() {
+		workspace.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu"}},
+				}}},
+			},
+		}}
+		violations := validateSchedulingPolicy(workspace, template)
+		Expect(violations).To(HaveLen(1))
+		Expect(violations[0].Field).To(Equal("spec.affinity.nodeAffinity"))
+
+		template.Spec.DefaultAffinity = workspace.Spec.Affinity.DeepCopy()
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+
+		workspace.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty(), "pod affinities are not bounded")
+	})
+
+	It("should reject tolerations of keys matching no pattern", func() {
+		workspace.Spec.Tolerations = []corev1.Toleration{
+			{Key: "gpu-h100", Operator: corev1.TolerationOpExists},
+			{Key: "dedicated", Operator: corev1.TolerationOpExists},
+			{Operator: corev1.TolerationOpExists},
+		}
+		violations := validateSchedulingPolicy(workspace, template)
+		Expect(violations).To(HaveLen(2))
+		Expect(violations[0].Field).To(Equal("spec.tolerations[1]"))
+		Expect(violations[1].Field).To(Equal("spec.tolerations[2]"), "tolerating every taint needs *")
+
+		template.Spec.SchedulingPolicy.AllowedTolerationKeys = []string{"*"}
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+	})
+
+	It("should not constrain workspaces of templates without scheduling policy", func() {
+		template.Spec.SchedulingPolicy = nil
+		workspace.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+		workspace.Spec.Tolerations = []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+
+		template.Spec.SchedulingPolicy = &workspacev1alpha1.SchedulingPolicy{}
+		Expect(validateSchedulingPolicy(workspace, template)).To(BeEmpty())
+	})
+
+	It("should keep admitting updates that do not change the scheduling", func() {
+		oldWorkspace := &workspacev1alpha1.Workspace{}
+		oldWorkspace.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+		workspace.Spec.NodeSelector = map[string]string{"pool": "gpu"}
+		violations := validateSchedulingPolicy(workspace, template)
+		Expect(violations).To(HaveLen(1))
+
+		Expect(withoutKeptSchedulingViolations(violations, oldWorkspace, workspace)).To(BeEmpty())
+
+		workspace.Spec.Tolerations = []corev1.Toleration{{Key: "gpu-a100", Operator: corev1.TolerationOpExists}}
+		Expect(withoutKeptSchedulingViolations(violations, oldWorkspace, workspace)).To(HaveLen(1))
+	})
+
+	It("should reject templates with invalid patterns", func() {
+		Expect(validateTemplateSchedulingPolicy(template)).To(Succeed())
+
+		template.Spec.SchedulingPolicy.AllowedNodeSelectors[0].Value = "[highmem"
+		Expect(validateTemplateSchedulingPolicy(template)).To(MatchError(ContainSubstring("invalid allowedNodeSelectors")))
+
+		template.Spec.SchedulingPolicy.AllowedNodeSelectors = nil
+		template.Spec.SchedulingPolicy.AllowedTolerationKeys = []string{"gpu-["}
+		Expect(validateTemplateSchedulingPolicy(template)).To(MatchError(ContainSubstring("invalid allowedTolerationKeys")))
+	})
+})
+
+var _ = Describe("Priority Class Allowlist Validator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "course"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultPriorityClassName:  "notebooks",
+				AllowedPriorityClassNames: []string{"notebooks-high"},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{}
+	})
+
+	It("should allow the template default and the allowed names", func() {
+		Expect(validatePriorityClassAllowed(workspace, template)).To(BeNil())
+		workspace.Spec.PriorityClassName = "notebooks"
+		Expect(validatePriorityClassAllowed(workspace, template)).To(BeNil())
+		workspace.Spec.PriorityClassName = "notebooks-high"
+		Expect(validatePriorityClassAllowed(workspace, template)).To(BeNil())
+		Expect(ValidateWorkspaceAgainstTemplate(workspace, template)).To(BeEmpty())
+	})
+
+	It("should reject a name outside of the allowlist", func() {
+		workspace.Spec.PriorityClassName = "batch-high"
+		violation := validatePriorityClassAllowed(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypePriorityClassNotAllowed))
+		Expect(violation.Field).To(Equal("spec.priorityClassName"))
+		Expect(violation.Allowed).To(Equal("notebooks-high"))
+
+		template.Spec.AllowedPriorityClassNames = nil
+		Expect(validatePriorityClassAllowed(workspace, template)).To(BeNil())
+	})
+
+	It("should keep admitting updates that do not change the priority class", func() {
+		oldWorkspace := &workspacev1alpha1.Workspace{}
+		oldWorkspace.Spec.PriorityClassName = "batch-high"
+		workspace.Spec.PriorityClassName = "batch-high"
+		violations := []TemplateViolation{*validatePriorityClassAllowed(workspace, template)}
+
+		Expect(withoutKeptSchedulingViolations(violations, oldWorkspace, workspace)).To(BeEmpty())
+
+		workspace.Spec.PriorityClassName = "system-cluster-critical"
+		Expect(withoutKeptSchedulingViolations(violations, oldWorkspace, workspace)).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,19 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyServerAdapterDefaults applies the server adapter from template to workspace. A workspace overriding the
+// command of the template does not inherit it: the probes and token args of the adapter describe the default
+// entrypoint, and would keep another server from ever becoming ready.
+func applyServerAdapterDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.ServerAdapter == nil && template.Spec.ServerAdapter != nil && !overridesCommand(workspace, template) {
+		workspace.Spec.ServerAdapter = template.Spec.ServerAdapter.DeepCopy()
+	}
+}
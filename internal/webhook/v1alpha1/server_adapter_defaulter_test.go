@@ -0,0 +1,86 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ServerAdapterDefaulter", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-template",
+				Namespace: "default",
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-workspace",
+			},
+		}
+	})
+
+	Describe("applyServerAdapterDefaults", func() {
+		It("should apply the template server adapter", func() {
+			template.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+				Preset:        workspacev1alpha1.ServerAdapterPresetJupyterLab,
+				ReadinessPath: "/status",
+			}
+
+			applyServerAdapterDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServerAdapter).To(Equal(template.Spec.ServerAdapter))
+			template.Spec.ServerAdapter.ReadinessPath = "/api"
+			Expect(workspace.Spec.ServerAdapter.ReadinessPath).To(Equal("/status"))
+		})
+
+		It("should not override the workspace server adapter", func() {
+			workspace.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+				Preset: workspacev1alpha1.ServerAdapterPresetCodeServer,
+			}
+			template.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+				Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab,
+			}
+
+			applyServerAdapterDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServerAdapter.Preset).To(Equal(workspacev1alpha1.ServerAdapterPresetCodeServer))
+		})
+
+		It("should not apply the template server adapter to a workspace overriding the command", func() {
+			template.Spec.ServerAdapter = &workspacev1alpha1.ServerAdapterSpec{
+				Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab,
+			}
+			template.Spec.DefaultContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"start-notebook.py"}}
+			workspace.Spec.ContainerConfig = &workspacev1alpha1.ContainerConfig{Command: []string{"code-server"}}
+
+			applyServerAdapterDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServerAdapter).To(BeNil())
+
+			workspace.Spec.ContainerConfig = template.Spec.DefaultContainerConfig.DeepCopy()
+			applyServerAdapterDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServerAdapter).To(Equal(template.Spec.ServerAdapter))
+		})
+
+		It("should leave workspace unchanged when template has no server adapter", func() {
+			applyServerAdapterDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServerAdapter).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// findServerType returns the server type of the template with the name, or nil if not found
+func findServerType(template *workspacev1alpha1.WorkspaceTemplate, name string) *workspacev1alpha1.ServerType {
+	for i := range template.Spec.ServerTypes {
+		if template.Spec.ServerTypes[i].Name == name {
+			return &template.Spec.ServerTypes[i]
+		}
+	}
+	return nil
+}
+
+// applyServerTypeDefaults selects the first server type of the template for a workspace that selects none,
+// and defaults the image of the workspace to the image of its server type. It applies before the core
+// defaults, whose default image is the fallback of server types without image. The controller renders the
+// port, readiness probe and args of the server type.
+func applyServerTypeDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if len(template.Spec.ServerTypes) == 0 {
+		return
+	}
+	if workspace.Spec.ServerType == "" {
+		workspace.Spec.ServerType = template.Spec.ServerTypes[0].Name
+	}
+	serverType := findServerType(template, workspace.Spec.ServerType)
+	if serverType != nil && workspace.Spec.Image == "" {
+		workspace.Spec.Image = serverType.Image
+	}
+}
+
+// resetImageOnServerTypeChange clears the image of an update switching the server type without changing the
+// image, for the template defaults to set the image of the new server type
+func resetImageOnServerTypeChange(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "UPDATE" {
+		return
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return
+	}
+	if oldWorkspace.Spec.ServerType != "" && oldWorkspace.Spec.ServerType != workspace.Spec.ServerType &&
+		oldWorkspace.Spec.Image == workspace.Spec.Image {
+		workspace.Spec.Image = ""
+	}
+}
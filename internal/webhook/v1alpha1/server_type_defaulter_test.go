@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ServerTypeDefaulter", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "servers", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultImage: "quay.io/jupyter/base-notebook:latest",
+				ServerTypes: []workspacev1alpha1.ServerType{
+					{Name: "lab", Preset: workspacev1alpha1.ServerAdapterPresetJupyterLab},
+					{Name: "vscode", Image: "codercom/code-server:4.96.2", Preset: workspacev1alpha1.ServerAdapterPresetCodeServer},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "test-workspace"}}
+	})
+
+	It("should select the first server type and fall back to the default image of the template", func() {
+		ApplyTemplateDefaultsFrom(workspace, template)
+
+		Expect(workspace.Spec.ServerType).To(Equal("lab"))
+		Expect(workspace.Spec.Image).To(Equal("quay.io/jupyter/base-notebook:latest"))
+	})
+
+	It("should default the image to the image of the selected server type", func() {
+		workspace.Spec.ServerType = "vscode"
+
+		ApplyTemplateDefaultsFrom(workspace, template)
+
+		Expect(workspace.Spec.Image).To(Equal("codercom/code-server:4.96.2"))
+	})
+
+	It("should leave workspaces of templates without server types alone", func() {
+		template.Spec.ServerTypes = nil
+
+		applyServerTypeDefaults(workspace, template)
+
+		Expect(workspace.Spec.ServerType).To(BeEmpty())
+		Expect(workspace.Spec.Image).To(BeEmpty())
+	})
+
+	It("should let an update switching the server type pick the image of the new server type", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.ServerType = "lab"
+		oldWorkspace.Spec.Image = "quay.io/jupyter/base-notebook:latest"
+		raw, err := json.Marshal(oldWorkspace)
+		Expect(err).NotTo(HaveOccurred())
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+
+		workspace.Spec.ServerType = "vscode"
+		workspace.Spec.Image = "quay.io/jupyter/base-notebook:latest"
+		resetImageOnServerTypeChange(req, workspace)
+		ApplyTemplateDefaultsFrom(workspace, template)
+		Expect(workspace.Spec.Image).To(Equal("codercom/code-server:4.96.2"))
+
+		By("keeping an image the update sets")
+		workspace.Spec.Image = "codercom/code-server:4.95.0"
+		resetImageOnServerTypeChange(req, workspace)
+		Expect(workspace.Spec.Image).To(Equal("codercom/code-server:4.95.0"))
+	})
+})
@@ -0,0 +1,56 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// serverTypeNames returns the names of the server types of the template, in order
+func serverTypeNames(template *workspacev1alpha1.WorkspaceTemplate) []string {
+	names := make([]string, 0, len(template.Spec.ServerTypes))
+	for _, serverType := range template.Spec.ServerTypes {
+		names = append(names, serverType.Name)
+	}
+	return names
+}
+
+// validateServerType rejects a workspace selecting a server type the template does not define
+func validateServerType(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	name := workspace.Spec.ServerType
+	names := serverTypeNames(template)
+	if name == "" || slices.Contains(names, name) {
+		return nil
+	}
+	message := fmt.Sprintf("Server type '%s' is not defined by template '%s'. Valid server types: %s",
+		name, template.Name, strings.Join(names, ", "))
+	if len(names) == 0 {
+		message = fmt.Sprintf("Server type '%s' is not defined by template '%s', which defines no server types; leave spec.serverType empty",
+			name, template.Name)
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeUnknownServerType,
+		Field:   "spec.serverType",
+		Message: message,
+		Allowed: strings.Join(names, ", "),
+		Actual:  name,
+	}
+}
+
+// withoutKeptServerTypeViolations drops the server type violation of an update keeping the server type: a
+// workspace whose server type was removed from the template can still be updated, e.g. stopped
+func withoutKeptServerTypeViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if oldWorkspace.Spec.ServerType != newWorkspace.Spec.ServerType {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeUnknownServerType
+	})
+}
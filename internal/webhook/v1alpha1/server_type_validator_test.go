@@ -0,0 +1,76 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ServerTypeValidator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "servers", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultImage: "quay.io/jupyter/base-notebook:latest",
+				ServerTypes: []workspacev1alpha1.ServerType{
+					{Name: "lab"},
+					{Name: "notebook"},
+					{Name: "vscode", Image: "codercom/code-server:4.96.2"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: "test-workspace"}}
+	})
+
+	It("should accept the server types of the template", func() {
+		workspace.Spec.ServerType = "vscode"
+		workspace.Spec.Image = "codercom/code-server:4.96.2"
+
+		Expect(ValidateWorkspaceAgainstTemplate(workspace, template)).To(BeEmpty(),
+			"the images of the server types are allowed")
+	})
+
+	It("should reject unknown server types listing the valid ones", func() {
+		workspace.Spec.ServerType = "rstudio"
+
+		violation := validateServerType(workspace, template)
+
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeUnknownServerType))
+		Expect(violation.Field).To(Equal("spec.serverType"))
+		Expect(violation.Message).To(ContainSubstring("Valid server types: lab, notebook, vscode"))
+	})
+
+	It("should reject server types for templates without server types", func() {
+		template.Spec.ServerTypes = nil
+		workspace.Spec.ServerType = "lab"
+
+		violation := validateServerType(workspace, template)
+
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Message).To(ContainSubstring("defines no server types"))
+	})
+
+	It("should keep accepting updates of workspaces whose server type was removed from the template", func() {
+		workspace.Spec.ServerType = "rstudio"
+		violations := []TemplateViolation{*validateServerType(workspace, template)}
+
+		Expect(withoutKeptServerTypeViolations(violations, workspace.DeepCopy(), workspace)).To(BeEmpty())
+
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.ServerType = "lab"
+		Expect(withoutKeptServerTypeViolations(violations, oldWorkspace, workspace)).To(HaveLen(1))
+	})
+})
@@ -61,3 +61,11 @@ func (sad *ServiceAccountDefaulter) ApplyServiceAccountDefaults(ctx context.Cont
 	workspace.Spec.ServiceAccountName = defaultSA
 	return nil
 }
+
+// applyTemplateServiceAccountDefaults applies the default service account of the template. It applies before
+// the default service account of the namespace, which workspaces of templates without default get.
+func applyTemplateServiceAccountDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.ServiceAccountName == "" {
+		workspace.Spec.ServiceAccountName = template.Spec.DefaultServiceAccountName
+	}
+}
@@ -173,4 +173,19 @@ var _ = Describe("ServiceAccountDefaulter", func() {
 			Expect(result).To(BeEmpty())
 		})
 	})
+
+	Describe("applyTemplateServiceAccountDefaults", func() {
+		It("should apply the default service account of the template", func() {
+			template := &workspacev1alpha1.WorkspaceTemplate{
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{DefaultServiceAccountName: "spark-driver"},
+			}
+
+			applyTemplateServiceAccountDefaults(workspace, template)
+			Expect(workspace.Spec.ServiceAccountName).To(Equal("spark-driver"))
+
+			workspace.Spec.ServiceAccountName = "pod-reader"
+			applyTemplateServiceAccountDefaults(workspace, template)
+			Expect(workspace.Spec.ServiceAccountName).To(Equal("pod-reader"))
+		})
+	})
 })
@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -147,3 +148,35 @@ func (sav *ServiceAccountValidator) ValidateServiceAccountAccess(ctx context.Con
 
 	return nil
 }
+
+// validateServiceAccountAllowed rejects a workspace setting a service account the template does not allow:
+// templates with a default service account or an allowlist allow the default and the listed names only
+func validateServiceAccountAllowed(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	defaultName := template.Spec.DefaultServiceAccountName
+	allowed := template.Spec.AllowedServiceAccounts
+	name := workspace.Spec.ServiceAccountName
+	if (defaultName == "" && len(allowed) == 0) || name == "" || name == defaultName || slices.Contains(allowed, name) {
+		return nil
+	}
+	if defaultName != "" {
+		allowed = append([]string{defaultName}, allowed...)
+	}
+	return &TemplateViolation{
+		Type:    ViolationTypeServiceAccountNotAllowed,
+		Field:   "spec.serviceAccountName",
+		Message: fmt.Sprintf("ServiceAccount '%s' is not allowed by template '%s'", name, template.Name),
+		Allowed: strings.Join(allowed, ", "),
+		Actual:  name,
+	}
+}
+
+// withoutKeptServiceAccountViolations drops the service account violation of an update keeping the service
+// account: the template restricted its service accounts after the workspace was admitted
+func withoutKeptServiceAccountViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if oldWorkspace.Spec.ServiceAccountName != newWorkspace.Spec.ServiceAccountName {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeServiceAccountNotAllowed
+	})
+}
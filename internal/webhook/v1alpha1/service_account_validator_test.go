@@ -183,4 +183,50 @@ var _ = Describe("ServiceAccount Validator", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("validateServiceAccountAllowed", func() {
+		var (
+			template  *workspacev1alpha1.WorkspaceTemplate
+			workspace *workspacev1alpha1.Workspace
+		)
+
+		BeforeEach(func() {
+			template = &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "spark", Namespace: "default"},
+				Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DefaultServiceAccountName: "spark-driver"},
+			}
+			workspace = &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-workspace", Namespace: "default"},
+			}
+		})
+
+		It("should allow any service account when the template restricts none", func() {
+			template.Spec.DefaultServiceAccountName = ""
+			workspace.Spec.ServiceAccountName = "anything"
+			Expect(validateServiceAccountAllowed(workspace, template)).To(BeNil())
+		})
+
+		It("should only allow the default when the allowlist is empty", func() {
+			workspace.Spec.ServiceAccountName = "spark-driver"
+			Expect(validateServiceAccountAllowed(workspace, template)).To(BeNil())
+
+			workspace.Spec.ServiceAccountName = "cluster-admin"
+			violation := validateServiceAccountAllowed(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Type).To(Equal(ViolationTypeServiceAccountNotAllowed))
+			Expect(violation.Allowed).To(Equal("spark-driver"))
+		})
+
+		It("should allow the listed service accounts", func() {
+			template.Spec.AllowedServiceAccounts = []string{"pod-reader"}
+			workspace.Spec.ServiceAccountName = "pod-reader"
+			Expect(validateServiceAccountAllowed(workspace, template)).To(BeNil())
+
+			workspace.Spec.ServiceAccountName = "default"
+			violation := validateServiceAccountAllowed(workspace, template)
+			Expect(violation).NotTo(BeNil())
+			Expect(violation.Allowed).To(Equal("spark-driver, pod-reader"))
+			Expect(withoutKeptServiceAccountViolations([]TemplateViolation{*violation}, workspace.DeepCopy(), workspace)).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,17 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// applyServiceMeshDefaults applies service mesh defaults from template to workspace
+func applyServiceMeshDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.ServiceMesh == nil && template.Spec.DefaultServiceMesh != nil {
+		workspace.Spec.ServiceMesh = template.Spec.DefaultServiceMesh.DeepCopy()
+	}
+}
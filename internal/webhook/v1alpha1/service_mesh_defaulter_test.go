@@ -0,0 +1,80 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("ServiceMeshDefaulter", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-template",
+				Namespace: "default",
+			},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-workspace",
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{},
+		}
+	})
+
+	Describe("applyServiceMeshDefaults", func() {
+		It("should apply service mesh defaults", func() {
+			template.Spec.DefaultServiceMesh = &workspacev1alpha1.ServiceMeshSpec{
+				SidecarInjection: boolPtr(false),
+			}
+
+			applyServiceMeshDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServiceMesh).ToNot(BeNil())
+			Expect(*workspace.Spec.ServiceMesh.SidecarInjection).To(BeFalse())
+		})
+
+		It("should not override existing service mesh settings", func() {
+			workspace.Spec.ServiceMesh = &workspacev1alpha1.ServiceMeshSpec{
+				SidecarInjection: boolPtr(true),
+			}
+			template.Spec.DefaultServiceMesh = &workspacev1alpha1.ServiceMeshSpec{
+				SidecarInjection: boolPtr(false),
+			}
+
+			applyServiceMeshDefaults(workspace, template)
+
+			Expect(*workspace.Spec.ServiceMesh.SidecarInjection).To(BeTrue())
+		})
+
+		It("should not share pointers with the template", func() {
+			template.Spec.DefaultServiceMesh = &workspacev1alpha1.ServiceMeshSpec{
+				SidecarInjection: boolPtr(true),
+			}
+
+			applyServiceMeshDefaults(workspace, template)
+			*template.Spec.DefaultServiceMesh.SidecarInjection = false
+
+			Expect(*workspace.Spec.ServiceMesh.SidecarInjection).To(BeTrue())
+		})
+
+		It("should leave workspace unchanged when template has no default", func() {
+			applyServiceMeshDefaults(workspace, template)
+
+			Expect(workspace.Spec.ServiceMesh).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,74 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// validateSharedVolumesKept checks the volumes and the home directory of the workspace do not remove or remount
+// a shared volume of the template
+func validateSharedVolumesKept(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	shared := template.Spec.SharedVolumes
+	if len(shared) == 0 {
+		return nil
+	}
+	violation := func(field, actual, conflict string) *TemplateViolation {
+		return &TemplateViolation{
+			Type:    ViolationTypeSharedVolumeRemounted,
+			Field:   field,
+			Message: fmt.Sprintf("%s: %s of template '%s' cannot be removed or remounted", field, conflict, template.Name),
+			Allowed: "a name and mount path outside the shared volumes of the template",
+			Actual:  actual,
+		}
+	}
+	if workspace.Spec.Storage != nil && workspace.Spec.Storage.MountPath != "" {
+		if conflict := controller.SharedVolumeConflict(shared, "", workspace.Spec.Storage.MountPath); conflict != "" {
+			return violation("spec.storage.mountPath", workspace.Spec.Storage.MountPath, conflict)
+		}
+	}
+	for i, vol := range workspace.Spec.Volumes {
+		if conflict := controller.SharedVolumeConflict(shared, vol.Name, vol.MountPath); conflict != "" {
+			return violation(fmt.Sprintf("spec.volumes[%d]", i), vol.MountPath, conflict)
+		}
+	}
+	for i, vol := range workspace.Spec.ExtraVolumes {
+		if conflict := controller.SharedVolumeConflict(shared, vol.Name, vol.MountPath); conflict != "" {
+			return violation(fmt.Sprintf("spec.extraVolumes[%d]", i), vol.MountPath, conflict)
+		}
+	}
+	return nil
+}
+
+// validateTemplateSharedVolumes rejects shared volumes taking the name of a volume of the controller, mounted at a
+// system path, or mounted at or under another shared volume
+func validateTemplateSharedVolumes(template *workspacev1alpha1.WorkspaceTemplate) error {
+	for i, volume := range template.Spec.SharedVolumes {
+		switch volume.Name {
+		case "workspace-storage", controller.TmpVolumeName, controller.ServiceAccountTokenVolumeName,
+			controller.ServerTokenVolumeName:
+			return fmt.Errorf("template '%s' spec.sharedVolumes[%d].name '%s' is reserved", template.Name, i, volume.Name)
+		}
+		if conflict := homeMountPathConflict(volume.MountPath); conflict != "" {
+			return fmt.Errorf("template '%s' spec.sharedVolumes[%d].mountPath cannot be '%s': %s",
+				template.Name, i, volume.MountPath, conflict)
+		}
+		for _, other := range template.Spec.SharedVolumes[:i] {
+			if controller.SharedVolumeConflict([]workspacev1alpha1.SharedVolume{other}, "", volume.MountPath) != "" {
+				return fmt.Errorf("template '%s' spec.sharedVolumes[%d].mountPath cannot be '%s': it is mounted at or under "+
+					"shared volume '%s'", template.Name, i, volume.MountPath, other.Name)
+			}
+			if controller.SharedVolumeConflict([]workspacev1alpha1.SharedVolume{volume}, "", other.MountPath) != "" {
+				return fmt.Errorf("template '%s' spec.sharedVolumes[%d].mountPath cannot be '%s': shared volume '%s' "+
+					"is mounted under it", template.Name, i, volume.MountPath, other.Name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("SharedVolumeValidator", func() {
+	var (
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "genomics", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				SharedVolumes: []workspacev1alpha1.SharedVolume{
+					{Name: "reference", PersistentVolumeClaimName: "reference-genomes", MountPath: "/data/reference"},
+				},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage: &workspacev1alpha1.StorageSpec{MountPath: "/home/jovyan"},
+				Volumes: []workspacev1alpha1.VolumeSpec{
+					{Name: "scratch", PersistentVolumeClaimName: "scratch", MountPath: "/data/scratch"},
+				},
+			},
+		}
+	})
+
+	It("should allow workspaces keeping the shared volumes", func() {
+		Expect(validateSharedVolumesKept(workspace, template)).To(BeNil())
+		template.Spec.SharedVolumes = nil
+		workspace.Spec.Volumes[0].MountPath = "/data/reference"
+		Expect(validateSharedVolumesKept(workspace, template)).To(BeNil())
+	})
+
+	It("should reject workspace volumes remounting a shared volume", func() {
+		workspace.Spec.Volumes[0].MountPath = "/data/reference/hg38"
+		violation := validateSharedVolumesKept(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Type).To(Equal(ViolationTypeSharedVolumeRemounted))
+		Expect(violation.Field).To(Equal("spec.volumes[0]"))
+
+		workspace.Spec.Volumes = nil
+		workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{
+			{Name: "reference", PersistentVolumeClaimName: "mine", MountPath: "/mine"},
+		}
+		violation = validateSharedVolumesKept(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Message).To(ContainSubstring("volume name 'reference' is used by a shared volume"))
+
+		workspace.Spec.ExtraVolumes = nil
+		workspace.Spec.Storage.MountPath = "/data/reference"
+		violation = validateSharedVolumesKept(workspace, template)
+		Expect(violation).NotTo(BeNil())
+		Expect(violation.Field).To(Equal("spec.storage.mountPath"))
+	})
+
+	It("should reject template shared volumes with reserved names or system paths", func() {
+		Expect(validateTemplateSharedVolumes(template)).To(Succeed())
+
+		template.Spec.SharedVolumes[0].Name = "workspace-storage"
+		Expect(validateTemplateSharedVolumes(template)).To(MatchError(
+			"template 'genomics' spec.sharedVolumes[0].name 'workspace-storage' is reserved"))
+
+		template.Spec.SharedVolumes[0].Name = "reference"
+		template.Spec.SharedVolumes[0].MountPath = "/etc"
+		Expect(validateTemplateSharedVolumes(template)).To(MatchError(
+			ContainSubstring("spec.sharedVolumes[0].mountPath cannot be '/etc'")))
+	})
+
+	It("should reject template shared volumes mounted under one another", func() {
+		template.Spec.SharedVolumes = append(template.Spec.SharedVolumes, workspacev1alpha1.SharedVolume{
+			Name: "annotations", PersistentVolumeClaimName: "annotations", MountPath: "/data/reference/annotations",
+		})
+		Expect(validateTemplateSharedVolumes(template)).To(MatchError(
+			ContainSubstring("it is mounted at or under shared volume 'reference'")))
+
+		template.Spec.SharedVolumes[1].MountPath = "/data"
+		Expect(validateTemplateSharedVolumes(template)).To(MatchError(
+			ContainSubstring("shared volume 'reference' is mounted under it")))
+
+		template.Spec.SharedVolumes[1].MountPath = "/data/annotations"
+		Expect(validateTemplateSharedVolumes(template)).To(Succeed())
+	})
+})
@@ -12,14 +12,14 @@ import (
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 )
 
-var _ = Describe("setWorkspaceSharingDefaults", func() {
+var _ = Describe("SetWorkspaceSharingDefaults", func() {
 	It("should set Public AccessType when OwnershipType is Public and AccessType is not populated", func() {
 		workspace := &workspacev1alpha1.Workspace{
 			Spec: workspacev1alpha1.WorkspaceSpec{
 				OwnershipType: "Public",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("Public"))
 	})
@@ -31,7 +31,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType:    "OwnerOnly",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("OwnerOnly"))
 	})
@@ -43,7 +43,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType:    "Public",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("Public"))
 	})
@@ -54,7 +54,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				OwnershipType: "OwnerOnly",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("OwnerOnly"))
 		Expect(workspace.Spec.AccessType).To(Equal("OwnerOnly"))
 	})
@@ -66,7 +66,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType:    "OwnerOnly",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("OwnerOnly"))
 		Expect(workspace.Spec.AccessType).To(Equal("OwnerOnly"))
 	})
@@ -78,7 +78,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType:    "Public",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("OwnerOnly"))
 		Expect(workspace.Spec.AccessType).To(Equal("Public"))
 	})
@@ -87,7 +87,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 		workspace := &workspacev1alpha1.Workspace{
 			Spec: workspacev1alpha1.WorkspaceSpec{},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("Public"))
 	})
@@ -98,7 +98,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType: "OwnerOnly",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("OwnerOnly"))
 	})
@@ -109,7 +109,7 @@ var _ = Describe("setWorkspaceSharingDefaults", func() {
 				AccessType: "Public",
 			},
 		}
-		setWorkspaceSharingDefaults(workspace)
+		SetWorkspaceSharingDefaults(workspace)
 		Expect(workspace.Spec.OwnershipType).To(Equal("Public"))
 		Expect(workspace.Spec.AccessType).To(Equal("Public"))
 	})
@@ -0,0 +1,148 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// optionalContainersField is the field of the access strategy listing the sidecars workspaces may disable
+const optionalContainersField = "spec.deploymentModifications.podModifications.optionalContainers"
+
+// SidecarValidator handles validation of the sidecars a workspace disables
+type SidecarValidator struct {
+	client client.Client
+}
+
+// NewSidecarValidator creates a new SidecarValidator
+func NewSidecarValidator(k8sClient client.Client) *SidecarValidator {
+	return &SidecarValidator{
+		client: k8sClient,
+	}
+}
+
+// ValidateCreateWorkspace validates the sidecars disabled on workspace creation
+func (sv *SidecarValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	return sv.validateDisabledSidecars(ctx, workspace)
+}
+
+// ValidateUpdateWorkspace validates the sidecars disabled on workspace update, when they or the
+// access strategy change, so that workspaces keep updating after a sidecar was made mandatory
+func (sv *SidecarValidator) ValidateUpdateWorkspace(
+	ctx context.Context,
+	oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if slices.Equal(oldWorkspace.Spec.DisabledSidecars, newWorkspace.Spec.DisabledSidecars) &&
+		equality.Semantic.DeepEqual(oldWorkspace.Spec.AccessStrategy, newWorkspace.Spec.AccessStrategy) {
+		return nil
+	}
+	return sv.validateDisabledSidecars(ctx, newWorkspace)
+}
+
+// validateDisabledSidecars checks that the workspace only disables sidecars its access strategy
+// marks as optional. Whether a sidecar is optional is for the owner of the access strategy to decide.
+func (sv *SidecarValidator) validateDisabledSidecars(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	if len(workspace.Spec.DisabledSidecars) == 0 {
+		return nil
+	}
+	if workspace.Spec.AccessStrategy == nil || workspace.Spec.AccessStrategy.Name == "" {
+		return fmt.Errorf("spec.disabledSidecars requires an accessStrategy: sidecars are injected by access strategies")
+	}
+
+	key := types.NamespacedName{
+		Name:      workspace.Spec.AccessStrategy.Name,
+		Namespace: workspaceutil.GetAccessStrategyRefNamespace(workspace),
+	}
+	accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{}
+	if err := sv.client.Get(ctx, key, accessStrategy); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("referenced AccessStrategy %s not found in namespace %s", key.Name, key.Namespace)
+		}
+		return fmt.Errorf("failed to get AccessStrategy %s: %w", key, err)
+	}
+
+	var sidecars, optional []string
+	if mods := accessStrategy.Spec.DeploymentModifications; mods != nil && mods.PodModifications != nil {
+		for _, container := range mods.PodModifications.AdditionalContainers {
+			sidecars = append(sidecars, container.Name)
+		}
+		optional = mods.PodModifications.OptionalContainers
+	}
+
+	var unknown, mandatory []string
+	for _, name := range workspace.Spec.DisabledSidecars {
+		switch {
+		case !slices.Contains(sidecars, name):
+			unknown = append(unknown, name)
+		case !slices.Contains(optional, name):
+			mandatory = append(mandatory, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("spec.disabledSidecars: AccessStrategy %s injects no sidecar named %s",
+			key, strings.Join(unknown, ", "))
+	}
+	if len(mandatory) > 0 {
+		return fmt.Errorf("spec.disabledSidecars: sidecar %s of AccessStrategy %s is mandatory; "+
+			"the owner of the AccessStrategy can make it optional by listing it in %s",
+			strings.Join(mandatory, ", "), key, optionalContainersField)
+	}
+	return nil
+}
+
+// validateTemplateSidecars checks the sidecars of the template, which the CRD schema does not describe:
+// each needs a unique name, other than the name of the workspace container, and an image, and may only
+// mount the home directory of the workspace
+func validateTemplateSidecars(template *workspacev1alpha1.WorkspaceTemplate) error {
+	return validateTemplateContainers(template, "sidecar", template.Spec.Sidecars,
+		controller.MaxTemplateSidecars, controller.TemplateSidecarConflict, map[string]struct{}{})
+}
+
+// validateTemplateContainers checks the containers of the kind the template adds to workspace pods, whose
+// names must not be in names already; it adds their names to names
+func validateTemplateContainers(
+	template *workspacev1alpha1.WorkspaceTemplate,
+	kind string,
+	containers []corev1.Container,
+	maxContainers int,
+	conflict func(corev1.Container) string,
+	names map[string]struct{}) error {
+	if len(containers) > maxContainers {
+		return fmt.Errorf("template '%s' defines %d %ss, at most %d are allowed",
+			template.Name, len(containers), kind, maxContainers)
+	}
+
+	for _, container := range containers {
+		if errs := validation.IsDNS1123Label(container.Name); len(errs) > 0 {
+			return fmt.Errorf("template '%s' has a %s with invalid name %q: %s",
+				template.Name, kind, container.Name, strings.Join(errs, ", "))
+		}
+		if _, duplicate := names[container.Name]; duplicate {
+			return fmt.Errorf("template '%s' defines container '%s' more than once", template.Name, container.Name)
+		}
+		names[container.Name] = struct{}{}
+		if container.Image == "" {
+			return fmt.Errorf("template '%s' %s '%s' has no image", template.Name, kind, container.Name)
+		}
+		if message := conflict(container); message != "" {
+			return fmt.Errorf("template '%s': %s", template.Name, message)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("SidecarValidator", func() {
+	var (
+		ctx       context.Context
+		validator *SidecarValidator
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		accessStrategy := &workspacev1alpha1.WorkspaceAccessStrategy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-ui", Namespace: "jupyter-k8s-shared"},
+			Spec: workspacev1alpha1.WorkspaceAccessStrategySpec{
+				DisplayName: "Web UI",
+				DeploymentModifications: &workspacev1alpha1.DeploymentModifications{
+					PodModifications: &workspacev1alpha1.PodModifications{
+						AdditionalContainers: []corev1.Container{
+							{Name: "telemetry", Image: "telemetry:1.0"},
+							{Name: "auth-proxy", Image: "auth-proxy:1.0"},
+						},
+						OptionalContainers: []string{"telemetry"},
+					},
+				},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator = NewSidecarValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(accessStrategy).Build())
+
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				AccessStrategy: &workspacev1alpha1.AccessStrategyRef{
+					Name:      "web-ui",
+					Namespace: "jupyter-k8s-shared",
+				},
+			},
+		}
+	})
+
+	It("should allow disabling an optional sidecar", func() {
+		workspace.Spec.DisabledSidecars = []string{"telemetry"}
+		Expect(validator.ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+	})
+
+	It("should reject disabling a mandatory sidecar naming the owner field", func() {
+		workspace.Spec.DisabledSidecars = []string{"telemetry", "auth-proxy"}
+		err := validator.ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("auth-proxy"))
+		Expect(err.Error()).To(ContainSubstring("is mandatory"))
+		Expect(err.Error()).To(ContainSubstring(optionalContainersField))
+		Expect(err.Error()).NotTo(ContainSubstring("telemetry,"))
+	})
+
+	It("should reject disabling a sidecar the access strategy does not inject", func() {
+		workspace.Spec.DisabledSidecars = []string{"log-shipper"}
+		err := validator.ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("no sidecar named log-shipper"))
+	})
+
+	It("should reject disabled sidecars without an access strategy", func() {
+		workspace.Spec.AccessStrategy = nil
+		workspace.Spec.DisabledSidecars = []string{"telemetry"}
+		err := validator.ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("requires an accessStrategy"))
+	})
+
+	It("should reject a missing access strategy", func() {
+		workspace.Spec.AccessStrategy.Name = "missing"
+		workspace.Spec.DisabledSidecars = []string{"telemetry"}
+		err := validator.ValidateCreateWorkspace(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("not found"))
+	})
+
+	It("should allow workspaces disabling no sidecar without reading the access strategy", func() {
+		Expect(NewSidecarValidator(nil).ValidateCreateWorkspace(ctx, workspace)).To(Succeed())
+	})
+
+	It("should validate updates only when the disabled sidecars or the access strategy change", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Spec.DisabledSidecars = []string{"auth-proxy"}
+		newWorkspace := oldWorkspace.DeepCopy()
+		newWorkspace.Spec.DisplayName = "Renamed"
+		Expect(validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)).To(Succeed())
+
+		oldWorkspace.Spec.DisabledSidecars = nil
+		err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("is mandatory"))
+	})
+})
+
+var _ = Describe("validateTemplateSidecars", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "proxied"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName: "Proxied",
+				Sidecars: []corev1.Container{{
+					Name:         "oauth-proxy",
+					Image:        "quay.io/oauth2-proxy/oauth2-proxy:v7.6.0",
+					VolumeMounts: []corev1.VolumeMount{{Name: "workspace-storage", MountPath: "/data"}},
+				}},
+			},
+		}
+	})
+
+	It("should allow sidecars mounting the home directory", func() {
+		Expect(validateTemplateSidecars(template)).To(Succeed())
+	})
+
+	It("should reject an invalid sidecar name", func() {
+		template.Spec.Sidecars[0].Name = "OAuth_Proxy"
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("invalid name")))
+	})
+
+	It("should reject a sidecar defined twice", func() {
+		template.Spec.Sidecars = append(template.Spec.Sidecars, template.Spec.Sidecars[0])
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("more than once")))
+	})
+
+	It("should reject a sidecar without image", func() {
+		template.Spec.Sidecars[0].Image = ""
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("has no image")))
+	})
+
+	It("should reject a sidecar named like the workspace container", func() {
+		template.Spec.Sidecars[0].Name = "workspace"
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("name of the workspace container")))
+	})
+
+	It("should reject a sidecar mounting another volume", func() {
+		template.Spec.Sidecars[0].VolumeMounts[0].Name = "host-root"
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("may only mount 'workspace-storage'")))
+	})
+
+	It("should reject more than 10 sidecars", func() {
+		template.Spec.Sidecars = nil
+		for i := 0; i <= 10; i++ {
+			template.Spec.Sidecars = append(template.Spec.Sidecars,
+				corev1.Container{Name: fmt.Sprintf("sidecar-%d", i), Image: "busybox"})
+		}
+		Expect(validateTemplateSidecars(template)).To(MatchError(ContainSubstring("at most 10")))
+	})
+})
@@ -0,0 +1,73 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/stringutil"
+)
+
+// ApplyStartApproval records on the workspace whether its template requires start approval, for the
+// controller to enforce, and replaces the start approval annotation value with the identity of the
+// user setting it. Workspaces created under a template requiring approval start Stopped, unless
+// they are approved on creation.
+func (td *TemplateDefaulter) ApplyStartApproval(ctx context.Context, req admission.Request, workspace *workspacev1alpha1.Workspace) error {
+	var template *workspacev1alpha1.WorkspaceTemplate
+	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
+		var err error
+		template, err = td.fetchTemplate(ctx, *workspace.Spec.TemplateRef, workspace.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	var oldWorkspace *workspacev1alpha1.Workspace
+	if req.Operation == "UPDATE" {
+		oldWorkspace = &workspacev1alpha1.Workspace{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+			workspacelog.Error(err, "Failed to decode old workspace, skipping start approval stamp", "workspace", workspace.GetName())
+			return nil
+		}
+	}
+
+	applyStartApproval(req.UserInfo.Username, oldWorkspace, workspace, template)
+	return nil
+}
+
+// applyStartApproval applies the start approval requirement of the template. oldWorkspace is nil on create.
+func applyStartApproval(username string, oldWorkspace, workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Annotations == nil {
+		workspace.Annotations = make(map[string]string)
+	}
+
+	required := template != nil && template.Spec.RequireStartApproval
+	if required {
+		workspace.Annotations[controller.AnnotationStartApprovalRequired] = "true"
+	} else {
+		delete(workspace.Annotations, controller.AnnotationStartApprovalRequired)
+	}
+
+	// The controller never approves starts; its writes keep the recorded approver
+	if !isControllerServiceAccount(username) {
+		var oldApprover string
+		if oldWorkspace != nil {
+			oldApprover = oldWorkspace.Annotations[controller.AnnotationStartApprovedBy]
+		}
+		if approver, ok := workspace.Annotations[controller.AnnotationStartApprovedBy]; ok && approver != oldApprover {
+			workspace.Annotations[controller.AnnotationStartApprovedBy] = stringutil.SanitizeUsername(username)
+		}
+	}
+
+	if required && oldWorkspace == nil && workspace.Annotations[controller.AnnotationStartApprovedBy] == "" {
+		workspace.Spec.DesiredStatus = controller.DesiredStateStopped
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// ValidateStartApproval enforces the start approval requirement of the workspace template: only members
+// of the template start approver groups may approve a start or withdraw an approval, and an unapproved
+// workspace may not be set to Running. Controller and admin users are checked upstream.
+// oldWorkspace is nil on create.
+func (tv *TemplateValidator) ValidateStartApproval(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	var oldApprover, oldDesiredStatus string
+	var oldApproved bool
+	if oldWorkspace != nil {
+		oldApprover, oldApproved = oldWorkspace.Annotations[controller.AnnotationStartApprovedBy]
+		oldDesiredStatus = oldWorkspace.Spec.DesiredStatus
+	}
+	newApprover, newApproved := newWorkspace.Annotations[controller.AnnotationStartApprovedBy]
+	approvalChanged := oldApproved != newApproved || oldApprover != newApprover
+	startRequested := newWorkspace.Spec.DesiredStatus == controller.DesiredStateRunning &&
+		(oldDesiredStatus != controller.DesiredStateRunning || templateRefChanged(oldWorkspace, newWorkspace))
+	if !approvalChanged && !startRequested {
+		return nil
+	}
+
+	templateRef := newWorkspace.Spec.TemplateRef
+	if templateRef == nil || templateRef.Name == "" {
+		if newApproved && approvalChanged {
+			return fmt.Errorf("annotation '%s' only applies to workspaces whose template requires start approval", controller.AnnotationStartApprovedBy)
+		}
+		return nil
+	}
+	template, err := tv.fetchTemplate(ctx, templateRef, newWorkspace.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if !template.Spec.RequireStartApproval {
+		// Leftover approvals may be removed once the template no longer requires them
+		if newApproved && approvalChanged {
+			return fmt.Errorf("annotation '%s' only applies to workspaces whose template requires start approval", controller.AnnotationStartApprovedBy)
+		}
+		return nil
+	}
+
+	if approvalChanged && !isStartApprover(ctx, template) {
+		return fmt.Errorf("annotation '%s' can only be changed by members of the start approver groups of template '%s'",
+			controller.AnnotationStartApprovedBy, template.Name)
+	}
+	if startRequested && newApprover == "" {
+		return fmt.Errorf("template '%s' requires start approval: a member of its start approver groups must set annotation '%s' before the workspace can run",
+			template.Name, controller.AnnotationStartApprovedBy)
+	}
+	return nil
+}
+
+// isStartApprover checks if the user belongs to one of the start approver groups of the template
+func isStartApprover(ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) bool {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(req.UserInfo.Groups, func(group string) bool {
+		return slices.Contains(template.Spec.StartApproverGroups, group)
+	})
+}
+
+// ValidateStartApprovalRequired rejects start approval requirements that do not match the workspace template.
+// The webhook records the requirement of the template; only controller and admin users, checked upstream, may
+// change it otherwise. oldWorkspace is nil on create.
+func (tv *TemplateValidator) ValidateStartApprovalRequired(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	value, set := newWorkspace.Annotations[controller.AnnotationStartApprovalRequired]
+	if oldWorkspace != nil && !templateRefChanged(oldWorkspace, newWorkspace) {
+		oldValue, oldSet := oldWorkspace.Annotations[controller.AnnotationStartApprovalRequired]
+		if oldSet == set && oldValue == value {
+			return nil
+		}
+	}
+
+	required := false
+	if templateRef := newWorkspace.Spec.TemplateRef; templateRef != nil && templateRef.Name != "" {
+		template, err := tv.fetchTemplate(ctx, templateRef, newWorkspace.Namespace)
+		if err != nil {
+			return err
+		}
+		required = template.Spec.RequireStartApproval
+	}
+	if set != required || (set && value != "true") {
+		return fmt.Errorf("annotation '%s' is recorded from the workspace template and can only be changed by administrators",
+			controller.AnnotationStartApprovalRequired)
+	}
+	return nil
+}
+
+// templateRefChanged returns true if the update points the workspace to another template
+func templateRefChanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) bool {
+	if oldWorkspace == nil {
+		return false
+	}
+	oldRef, newRef := oldWorkspace.Spec.TemplateRef, newWorkspace.Spec.TemplateRef
+	if oldRef == nil || newRef == nil {
+		return (oldRef == nil) != (newRef == nil)
+	}
+	return oldRef.Name != newRef.Name ||
+		workspaceutil.GetTemplateRefNamespace(oldWorkspace) != workspaceutil.GetTemplateRefNamespace(newWorkspace)
+}
+
+// onlyStartApprovalChanged returns true if the update only approves a start or withdraws an approval,
+// which start approvers may do on workspaces they do not own
+func onlyStartApprovalChanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) bool {
+	if specChanged(&oldWorkspace.Spec, &newWorkspace.Spec) || !maps.Equal(oldWorkspace.Labels, newWorkspace.Labels) {
+		return false
+	}
+	ignored := []string{controller.AnnotationStartApprovedBy, controller.AnnotationLastUpdatedBy}
+	oldAnnotations := maps.Clone(oldWorkspace.Annotations)
+	newAnnotations := maps.Clone(newWorkspace.Annotations)
+	for _, key := range ignored {
+		delete(oldAnnotations, key)
+		delete(newAnnotations, key)
+	}
+	return maps.Equal(oldAnnotations, newAnnotations) &&
+		oldWorkspace.Annotations[controller.AnnotationStartApprovedBy] != newWorkspace.Annotations[controller.AnnotationStartApprovedBy]
+}
@@ -0,0 +1,176 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Start Approval", func() {
+	var (
+		ctx          context.Context
+		template     *workspacev1alpha1.WorkspaceTemplate
+		validator    *TemplateValidator
+		oldWorkspace *workspacev1alpha1.Workspace
+		newWorkspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "regulated", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:          "Regulated",
+				DefaultImage:         "jupyter/base-notebook:latest",
+				RequireStartApproval: true,
+				StartApproverGroups:  []string{"team-a-leads"},
+			},
+		}
+		scheme := runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator = NewTemplateValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "")
+
+		oldWorkspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ws",
+				Namespace:   "team-a",
+				Annotations: map[string]string{controller.AnnotationStartApprovalRequired: "true"},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				DesiredStatus: controller.DesiredStateStopped,
+				TemplateRef:   &workspacev1alpha1.TemplateRef{Name: "regulated"},
+			},
+		}
+		newWorkspace = oldWorkspace.DeepCopy()
+	})
+
+	Context("Defaulting", func() {
+		It("should create workspaces of a template requiring approval stopped", func() {
+			newWorkspace.Annotations = nil
+			newWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			applyStartApproval("user1", nil, newWorkspace, template)
+			Expect(newWorkspace.Spec.DesiredStatus).To(Equal(controller.DesiredStateStopped))
+			Expect(newWorkspace.Annotations).To(HaveKeyWithValue(controller.AnnotationStartApprovalRequired, "true"))
+		})
+
+		It("should leave the desired status of other templates alone", func() {
+			template.Spec.RequireStartApproval = false
+			newWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			applyStartApproval("user1", nil, newWorkspace, template)
+			Expect(newWorkspace.Spec.DesiredStatus).To(Equal(controller.DesiredStateRunning))
+			Expect(newWorkspace.Annotations).NotTo(HaveKey(controller.AnnotationStartApprovalRequired))
+		})
+
+		It("should record the identity of the user setting the approval", func() {
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "true"
+			applyStartApproval("lead1", oldWorkspace, newWorkspace, template)
+			Expect(newWorkspace.Annotations).To(HaveKeyWithValue(controller.AnnotationStartApprovedBy, "lead1"))
+		})
+
+		It("should keep the recorded approver on unrelated updates", func() {
+			oldWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			applyStartApproval("user1", oldWorkspace, newWorkspace, template)
+			Expect(newWorkspace.Annotations).To(HaveKeyWithValue(controller.AnnotationStartApprovedBy, "lead1"))
+		})
+	})
+
+	Context("Validation", func() {
+		It("should reject approvals from users outside the approver groups", func() {
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "user1"
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			err := validator.ValidateStartApproval(userCtx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("start approver groups"))
+		})
+
+		It("should allow approvers to approve and revoke", func() {
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			leadCtx := createUserContext(ctx, "UPDATE", "lead1", "team-a-leads")
+			Expect(validator.ValidateStartApproval(leadCtx, oldWorkspace, newWorkspace)).To(Succeed())
+			Expect(validator.ValidateStartApproval(leadCtx, newWorkspace, oldWorkspace)).To(Succeed())
+		})
+
+		It("should reject revocations from users outside the approver groups", func() {
+			oldWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			Expect(validator.ValidateStartApproval(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should reject starting an unapproved workspace", func() {
+			newWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			err := validator.ValidateStartApproval(userCtx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requires start approval"))
+		})
+
+		It("should allow the owner to start an approved workspace", func() {
+			oldWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			newWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			Expect(validator.ValidateStartApproval(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should reject approvals for templates that do not require them", func() {
+			template.Spec.RequireStartApproval = false
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator = NewTemplateValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "")
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			leadCtx := createUserContext(ctx, "UPDATE", "lead1", "team-a-leads")
+			Expect(validator.ValidateStartApproval(leadCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should reject users dropping the start approval requirement", func() {
+			delete(newWorkspace.Annotations, controller.AnnotationStartApprovalRequired)
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			err := validator.ValidateStartApprovalRequired(userCtx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can only be changed by administrators"))
+
+			newWorkspace.Annotations[controller.AnnotationStartApprovalRequired] = "false"
+			Expect(validator.ValidateStartApprovalRequired(userCtx, oldWorkspace, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should accept the start approval requirement recorded from the template", func() {
+			userCtx := createUserContext(ctx, "CREATE", "user1", "system:authenticated")
+			Expect(validator.ValidateStartApprovalRequired(userCtx, nil, newWorkspace)).To(Succeed())
+
+			delete(newWorkspace.Annotations, controller.AnnotationStartApprovalRequired)
+			Expect(validator.ValidateStartApprovalRequired(userCtx, nil, newWorkspace)).NotTo(Succeed())
+		})
+
+		It("should let the requirement go once the template no longer requires approval", func() {
+			template.Spec.RequireStartApproval = false
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator = NewTemplateValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build(), "")
+			delete(newWorkspace.Annotations, controller.AnnotationStartApprovalRequired)
+			userCtx := createUserContext(ctx, "UPDATE", "user1", "system:authenticated")
+			Expect(validator.ValidateStartApprovalRequired(userCtx, oldWorkspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should only let approval-only updates bypass ownership checks", func() {
+			newWorkspace.Annotations[controller.AnnotationStartApprovedBy] = "lead1"
+			newWorkspace.Annotations[controller.AnnotationLastUpdatedBy] = "lead1"
+			Expect(onlyStartApprovalChanged(oldWorkspace, newWorkspace)).To(BeTrue())
+
+			newWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			Expect(onlyStartApprovalChanged(oldWorkspace, newWorkspace)).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,33 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// discardClientStatus drops the status supplied with writes to the workspace resource: a new workspace
+// starts with an empty status and an update keeps the stored one. Status is only written by the controller
+// through the status subresource, which this webhook does not intercept. The API server already ignores
+// status on the main resource; this keeps later defaulters and validators from acting on a forged status.
+func discardClientStatus(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "UPDATE" {
+		workspace.Status = workspacev1alpha1.WorkspaceStatus{}
+		return
+	}
+
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		workspacelog.Error(err, "Failed to decode old workspace, discarding status", "workspace", workspace.GetName())
+		workspace.Status = workspacev1alpha1.WorkspaceStatus{}
+		return
+	}
+	workspace.Status = oldWorkspace.Status
+}
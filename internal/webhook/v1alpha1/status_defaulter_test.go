@@ -0,0 +1,78 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("Status Defaulter", func() {
+	var workspace *workspacev1alpha1.Workspace
+
+	newRequest := func(operation string, oldWorkspace *workspacev1alpha1.Workspace) admission.Request {
+		req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo:  authenticationv1.UserInfo{Username: "user1"},
+			Operation: admissionv1.Operation(operation),
+		}}
+		if oldWorkspace != nil {
+			raw, err := json.Marshal(oldWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+			req.OldObject = runtime.RawExtension{Raw: raw}
+		}
+		return req
+	}
+
+	forgedStatus := func() workspacev1alpha1.WorkspaceStatus {
+		return workspacev1alpha1.WorkspaceStatus{
+			AccessURL: "https://attacker.example.com/",
+			Conditions: []metav1.Condition{{
+				Type:   controller.ConditionTypeAvailable,
+				Status: metav1.ConditionTrue,
+				Reason: "Forged",
+			}},
+		}
+	}
+
+	BeforeEach(func() {
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+			Spec:       workspacev1alpha1.WorkspaceSpec{DisplayName: "Workspace"},
+		}
+	})
+
+	It("should discard the status supplied on create", func() {
+		workspace.Status = forgedStatus()
+		discardClientStatus(newRequest("CREATE", nil), workspace)
+		Expect(workspace.Status).To(Equal(workspacev1alpha1.WorkspaceStatus{}))
+	})
+
+	It("should keep the stored status on update", func() {
+		oldWorkspace := workspace.DeepCopy()
+		oldWorkspace.Status.AccessURL = "https://workspaces.example.com/team-a/ws/"
+		workspace.Status = forgedStatus()
+		discardClientStatus(newRequest("UPDATE", oldWorkspace), workspace)
+		Expect(workspace.Status).To(Equal(oldWorkspace.Status))
+	})
+
+	It("should discard the status when the old workspace cannot be decoded", func() {
+		workspace.Status = forgedStatus()
+		req := newRequest("UPDATE", nil)
+		req.OldObject = runtime.RawExtension{Raw: []byte("{")}
+		discardClientStatus(req, workspace)
+		Expect(workspace.Status).To(Equal(workspacev1alpha1.WorkspaceStatus{}))
+	})
+})
@@ -0,0 +1,63 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+// workspaceStatusWebhookPath is the path of the validating webhook for the Workspace status subresource
+const workspaceStatusWebhookPath = "/validate-workspace-jupyter-org-v1alpha1-workspace-status"
+
+// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspace-status,mutating=false,failurePolicy=ignore,sideEffects=None,groups=workspace.jupyter.org,resources=workspaces/status,verbs=update,versions=v1alpha1,name=vworkspace-status-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+
+// WorkspaceStatusValidator keeps the blocks of the workspace status the controller maintains read-only
+// for other clients writing the status subresource. Users with the RBAC to write the status could
+// otherwise misreport the policies that govern a workspace, or the cleanup of a deleted workspace.
+type WorkspaceStatusValidator struct {
+	scope *workspaceutil.Scope
+}
+
+// Handle validates updates of the workspace status subresource
+func (v *WorkspaceStatusValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if isControllerServiceAccount(req.UserInfo.Username) {
+		return admission.Allowed("")
+	}
+
+	workspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.Object.Raw, workspace); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode workspace: %w", err))
+	}
+	if !v.scope.Matches(workspace) {
+		return admission.Allowed("workspace outside the operator scope")
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode old workspace: %w", err))
+	}
+
+	if !equality.Semantic.DeepEqual(workspace.Status.EffectivePolicies, oldWorkspace.Status.EffectivePolicies) {
+		workspacelog.Info("Denying write of the effective policies of workspace",
+			"name", req.Name, "namespace", req.Namespace, "user", req.UserInfo.Username)
+		return admission.Denied("status.effectivePolicies is maintained by the controller and cannot be modified")
+	}
+	// Forging the progress of the cleanup hooks would skip them without the skip annotation of an administrator
+	if !equality.Semantic.DeepEqual(workspace.Status.CleanupHooks, oldWorkspace.Status.CleanupHooks) {
+		workspacelog.Info("Denying write of the cleanup hooks of workspace",
+			"name", req.Name, "namespace", req.Namespace, "user", req.UserInfo.Username)
+		return admission.Denied("status.cleanupHooks is maintained by the controller and cannot be modified")
+	}
+	return admission.Allowed("")
+}
@@ -0,0 +1,104 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("WorkspaceStatusValidator", func() {
+	var (
+		validator *WorkspaceStatusValidator
+		ctx       context.Context
+		stored    *workspacev1alpha1.Workspace
+	)
+
+	statusUpdate := func(username string, workspace *workspacev1alpha1.Workspace) admission.Request {
+		object, err := json.Marshal(workspace)
+		Expect(err).NotTo(HaveOccurred())
+		oldObject, err := json.Marshal(stored)
+		Expect(err).NotTo(HaveOccurred())
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Name:        workspace.Name,
+			Namespace:   workspace.Namespace,
+			Operation:   admissionv1.Update,
+			SubResource: "status",
+			UserInfo:    authenticationv1.UserInfo{Username: username},
+			Object:      runtime.RawExtension{Raw: object},
+			OldObject:   runtime.RawExtension{Raw: oldObject},
+		}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		validator = &WorkspaceStatusValidator{}
+		GinkgoT().Setenv(controller.ControllerPodNamespaceEnv, "jupyter-k8s-system")
+		GinkgoT().Setenv(controller.ControllerPodServiceAccountEnv, "jupyter-k8s-controller-manager")
+
+		stored = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "default"},
+			Status: workspacev1alpha1.WorkspaceStatus{
+				EffectivePolicies: &workspacev1alpha1.EffectivePolicies{
+					IdleShutdown: &workspacev1alpha1.EffectiveIdleShutdownPolicy{
+						IdleTimeoutInMinutes: 60,
+						Source:               workspacev1alpha1.PolicySourceTemplate,
+					},
+				},
+			},
+		}
+	})
+
+	It("should deny users changing the effective policies", func() {
+		forged := stored.DeepCopy()
+		forged.Status.EffectivePolicies.IdleShutdown.IdleTimeoutInMinutes = 600
+
+		resp := validator.Handle(ctx, statusUpdate("alice", forged))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("status.effectivePolicies is maintained by the controller"))
+
+		forged.Status.EffectivePolicies = nil
+		Expect(validator.Handle(ctx, statusUpdate("alice", forged)).Allowed).To(BeFalse())
+	})
+
+	It("should deny users changing the progress of the cleanup hooks", func() {
+		stored.Status.CleanupHooks = []workspacev1alpha1.CleanupHookStatus{
+			{Name: "home-dirs", Phase: workspacev1alpha1.CleanupHookFailed, Attempts: 5},
+		}
+		forged := stored.DeepCopy()
+		forged.Status.CleanupHooks[0].Phase = workspacev1alpha1.CleanupHookAcknowledged
+
+		resp := validator.Handle(ctx, statusUpdate("alice", forged))
+		Expect(resp.Allowed).To(BeFalse())
+		Expect(resp.Result.Message).To(ContainSubstring("status.cleanupHooks is maintained by the controller"))
+	})
+
+	It("should allow users changing other status fields", func() {
+		updated := stored.DeepCopy()
+		updated.Status.AccessURL = "https://example.com/ws"
+
+		Expect(validator.Handle(ctx, statusUpdate("alice", updated)).Allowed).To(BeTrue())
+	})
+
+	It("should allow the controller changing the effective policies", func() {
+		updated := stored.DeepCopy()
+		updated.Status.EffectivePolicies.IdleShutdown.IdleTimeoutInMinutes = 30
+
+		resp := validator.Handle(ctx, statusUpdate("system:serviceaccount:jupyter-k8s-system:jupyter-k8s-controller-manager", updated))
+		Expect(resp.Allowed).To(BeTrue())
+	})
+})
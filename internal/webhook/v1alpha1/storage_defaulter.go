@@ -6,7 +6,12 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"encoding/json"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // applyStorageDefaults applies storage defaults from template to workspace
@@ -22,13 +27,19 @@ func applyStorageDefaults(workspace *workspacev1alpha1.Workspace, template *work
 
 	// Apply individual storage defaults if storage exists
 	if workspace.Spec.Storage != nil {
+		// Apply the storage mode of the template if not specified
+		if workspace.Spec.Storage.Mode == "" {
+			workspace.Spec.Storage.Mode = template.Spec.PrimaryStorage.Mode
+		}
+
 		// Apply default size if not specified
 		if workspace.Spec.Storage.Size.IsZero() && !template.Spec.PrimaryStorage.DefaultSize.IsZero() {
 			workspace.Spec.Storage.Size = template.Spec.PrimaryStorage.DefaultSize
 		}
 
-		// Apply default storage class name if not specified
-		if workspace.Spec.Storage.StorageClassName == nil && template.Spec.PrimaryStorage.DefaultStorageClassName != nil {
+		// Apply default storage class name if not specified; ephemeral storage has no PVC
+		if workspace.Spec.Storage.StorageClassName == nil && template.Spec.PrimaryStorage.DefaultStorageClassName != nil &&
+			workspace.Spec.Storage.Mode != workspacev1alpha1.StorageModeEphemeral {
 			workspace.Spec.Storage.StorageClassName = template.Spec.PrimaryStorage.DefaultStorageClassName
 		}
 
@@ -38,3 +49,74 @@ func applyStorageDefaults(workspace *workspacev1alpha1.Workspace, template *work
 		}
 	}
 }
+
+// applyHomeMountDefaults mounts the home volume where the template image expects it: the home mount path of the
+// template replaces an empty or defaulted mount path, the /home/jovyan default of the API server or the default
+// mount path of the primary storage, and its home subPath an empty subPath
+func applyHomeMountDefaults(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
+	if workspace.Spec.Storage == nil {
+		return
+	}
+	mountPath := workspace.Spec.Storage.MountPath
+	defaulted := mountPath == "" || mountPath == controller.DefaultMountPath ||
+		(template.Spec.PrimaryStorage != nil && mountPath == template.Spec.PrimaryStorage.DefaultMountPath)
+	if template.Spec.HomeMountPath != "" && defaulted {
+		workspace.Spec.Storage.MountPath = template.Spec.HomeMountPath
+	}
+	if workspace.Spec.Storage.SubPath == "" {
+		workspace.Spec.Storage.SubPath = template.Spec.HomeSubPath
+	}
+}
+
+// keepStorageClassOnUpdate clears the storage class the template defaults set on an update of a workspace whose
+// storage had none: its PVC keeps the cluster default storage class, which cannot change
+func keepStorageClassOnUpdate(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "UPDATE" || workspace.Spec.Storage == nil || workspace.Spec.Storage.StorageClassName == nil {
+		return
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return
+	}
+	if oldWorkspace.Spec.Storage != nil && oldWorkspace.Spec.Storage.StorageClassName == nil {
+		workspace.Spec.Storage.StorageClassName = nil
+	}
+}
+
+// keepSubPathOnUpdate restores the subPath of the existing storage on an update the template defaults set a
+// subPath in: the data of the workspace stays where its volume has it
+func keepSubPathOnUpdate(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "UPDATE" || workspace.Spec.Storage == nil || workspace.Spec.Storage.SubPath == "" {
+		return
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return
+	}
+	if oldWorkspace.Spec.Storage != nil {
+		workspace.Spec.Storage.SubPath = oldWorkspace.Spec.Storage.SubPath
+	}
+}
+
+// storageModeSetBy returns the storage mode the workspace sets, empty when it sets none
+func storageModeSetBy(workspace *workspacev1alpha1.Workspace) workspacev1alpha1.StorageMode {
+	if workspace.Spec.Storage == nil {
+		return ""
+	}
+	return workspace.Spec.Storage.Mode
+}
+
+// keepStorageModeOnUpdate restores the storage mode of the existing storage on an update the template defaults
+// set a storage mode in: the home directory of the workspace stays where it is
+func keepStorageModeOnUpdate(req admission.Request, workspace *workspacev1alpha1.Workspace) {
+	if req.Operation != "UPDATE" || workspace.Spec.Storage == nil || workspace.Spec.Storage.Mode == "" {
+		return
+	}
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		return
+	}
+	if oldWorkspace.Spec.Storage != nil {
+		workspace.Spec.Storage.Mode = oldWorkspace.Spec.Storage.Mode
+	}
+}
@@ -74,6 +74,26 @@ var _ = Describe("StorageDefaulter", func() {
 			Expect(workspace.Spec.Storage.MountPath).To(Equal("/existing"))
 		})
 
+		It("should apply the storage mode of the template without a storage class", func() {
+			template.Spec.PrimaryStorage.Mode = workspacev1alpha1.StorageModeEphemeral
+
+			applyStorageDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage.Mode).To(Equal(workspacev1alpha1.StorageModeEphemeral))
+			Expect(workspace.Spec.Storage.StorageClassName).To(BeNil(), "ephemeral storage has no PVC")
+			Expect(workspace.Spec.Storage.Size).To(Equal(resource.MustParse("5Gi")))
+		})
+
+		It("should not override the storage mode of the workspace", func() {
+			template.Spec.PrimaryStorage.Mode = workspacev1alpha1.StorageModeEphemeral
+			workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{Mode: workspacev1alpha1.StorageModePersistent}
+
+			applyStorageDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage.Mode).To(Equal(workspacev1alpha1.StorageModePersistent))
+			Expect(*workspace.Spec.Storage.StorageClassName).To(Equal("fast-ssd"))
+		})
+
 		It("should do nothing when template has no primary storage", func() {
 			template.Spec.PrimaryStorage = nil
 
@@ -90,4 +110,42 @@ var _ = Describe("StorageDefaulter", func() {
 			Expect(workspace.Spec.Storage).To(BeNil())
 		})
 	})
+
+	Context("applyHomeMountDefaults", func() {
+		BeforeEach(func() {
+			template.Spec.HomeMountPath = "/home/user"
+			template.Spec.HomeSubPath = "home"
+		})
+
+		It("should replace the default mount path and set the subPath", func() {
+			workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{MountPath: "/home/jovyan"}
+
+			applyHomeMountDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage.MountPath).To(Equal("/home/user"))
+			Expect(workspace.Spec.Storage.SubPath).To(Equal("home"))
+		})
+
+		It("should take precedence over the default mount path of the primary storage", func() {
+			applyStorageDefaults(workspace, template)
+			applyHomeMountDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage.MountPath).To(Equal("/home/user"))
+		})
+
+		It("should not override the mount path and subPath of the workspace", func() {
+			workspace.Spec.Storage = &workspacev1alpha1.StorageSpec{MountPath: "/data", SubPath: "mine"}
+
+			applyHomeMountDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage.MountPath).To(Equal("/data"))
+			Expect(workspace.Spec.Storage.SubPath).To(Equal("mine"))
+		})
+
+		It("should do nothing for workspaces without storage", func() {
+			applyHomeMountDefaults(workspace, template)
+
+			Expect(workspace.Spec.Storage).To(BeNil())
+		})
+	})
 })
@@ -6,11 +6,17 @@ Distributed under the terms of the MIT license
 package v1alpha1
 
 import (
+	"context"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // validateStorageSize checks if storage size is within template bounds
@@ -43,6 +49,222 @@ func validateStorageSize(size resource.Quantity, template *workspacev1alpha1.Wor
 	return nil
 }
 
+// validateStorageNotShrunk rejects updates lowering the storage size of a workspace (applies to all users):
+// the PVC of the workspace can be expanded but never shrunk
+func validateStorageNotShrunk(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.Storage == nil || newWorkspace.Spec.Storage == nil ||
+		controller.StorageModeOf(newWorkspace) == workspacev1alpha1.StorageModeEphemeral {
+		return nil
+	}
+	oldSize := oldWorkspace.Spec.Storage.Size
+	newSize := newWorkspace.Spec.Storage.Size
+	if oldSize.IsZero() || newSize.IsZero() || newSize.Cmp(oldSize) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("spec.storage.size cannot decrease from %s to %s: the volume of the workspace can be expanded "+
+		"but not shrunk; to use a smaller volume, create a new workspace and copy the data over",
+		oldSize.String(), newSize.String())
+}
+
+// templateStorageModeOf returns the storage mode of the workspaces of the template, Persistent unless it sets another
+func templateStorageModeOf(template *workspacev1alpha1.WorkspaceTemplate) workspacev1alpha1.StorageMode {
+	if template.Spec.PrimaryStorage == nil || template.Spec.PrimaryStorage.Mode == "" {
+		return workspacev1alpha1.StorageModePersistent
+	}
+	return template.Spec.PrimaryStorage.Mode
+}
+
+// validateStorageMode checks the storage mode of the workspace is the one of the template, unless the template
+// allows workspaces to override it
+func validateStorageMode(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	if workspace.Spec.Storage == nil || (template.Spec.PrimaryStorage != nil && template.Spec.PrimaryStorage.AllowModeOverride) {
+		return nil
+	}
+	mode := controller.StorageModeOf(workspace)
+	templateMode := templateStorageModeOf(template)
+	if mode == templateMode {
+		return nil
+	}
+	return &TemplateViolation{
+		Type:  ViolationTypeStorageModeNotAllowed,
+		Field: "spec.storage.mode",
+		Message: fmt.Sprintf("Storage mode %s is not allowed by template '%s', whose workspaces use %s storage",
+			mode, template.Name, templateMode),
+		Allowed: string(templateMode),
+		Actual:  string(mode),
+	}
+}
+
+// withoutKeptStorageModeViolations drops the storage mode violation of an update keeping the storage mode of the
+// workspace: the template changed its storage mode after the workspace got its storage, whose mode cannot change
+func withoutKeptStorageModeViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if oldWorkspace.Spec.Storage == nil || controller.StorageModeOf(oldWorkspace) != controller.StorageModeOf(newWorkspace) {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeStorageModeNotAllowed
+	})
+}
+
+// validateStorageModeUnchanged rejects updates switching the storage mode of a workspace with storage (applies to
+// all users): an ephemeral home directory cannot be moved into a PVC, nor a PVC given up for an emptyDir volume
+func validateStorageModeUnchanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.Storage == nil || newWorkspace.Spec.Storage == nil {
+		return nil
+	}
+	oldMode := controller.StorageModeOf(oldWorkspace)
+	newMode := controller.StorageModeOf(newWorkspace)
+	if oldMode == newMode {
+		return nil
+	}
+	return fmt.Errorf("spec.storage.mode cannot change from %s to %s: the home directory of the workspace is not "+
+		"moved between storage modes; to use another storage mode, create a new workspace", oldMode, newMode)
+}
+
+// storageClassNameOf returns the storage class of the workspace storage, empty for the cluster default
+func storageClassNameOf(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Spec.Storage == nil || workspace.Spec.Storage.StorageClassName == nil {
+		return ""
+	}
+	return *workspace.Spec.Storage.StorageClassName
+}
+
+// validateStorageClassAllowed checks the storage class of the workspace storage is in the template allowlist
+func validateStorageClassAllowed(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
+	config := template.Spec.PrimaryStorage
+	if workspace.Spec.Storage == nil || config == nil || len(config.AllowedStorageClassNames) == 0 {
+		return nil
+	}
+
+	storageClassName := storageClassNameOf(workspace)
+	if slices.Contains(config.AllowedStorageClassNames, storageClassName) {
+		return nil
+	}
+	actual := storageClassName
+	if actual == "" {
+		actual = "the cluster default"
+	}
+	return &TemplateViolation{
+		Type:  ViolationTypeStorageClassNotAllowed,
+		Field: "spec.storage.storageClassName",
+		Message: fmt.Sprintf("Storage class %s is not allowed by template '%s'. Allowed storage classes: %v",
+			actual, template.Name, config.AllowedStorageClassNames),
+		Allowed: fmt.Sprintf("%v", config.AllowedStorageClassNames),
+		Actual:  storageClassName,
+	}
+}
+
+// withoutKeptStorageClassViolations drops the storage class violations of an update keeping the storage class
+// of the workspace: the template restricted its storage classes after the PVC was created, whose storage class
+// cannot change
+func withoutKeptStorageClassViolations(violations []TemplateViolation, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) []TemplateViolation {
+	if oldWorkspace.Spec.Storage == nil || storageClassNameOf(oldWorkspace) != storageClassNameOf(newWorkspace) {
+		return violations
+	}
+	return slices.DeleteFunc(violations, func(violation TemplateViolation) bool {
+		return violation.Type == ViolationTypeStorageClassNotAllowed
+	})
+}
+
+// validateStorageClassUnchanged rejects updates changing the storage class of a workspace with storage (applies
+// to all users): the storage class of its PVC is immutable
+func validateStorageClassUnchanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.Storage == nil || newWorkspace.Spec.Storage == nil {
+		return nil
+	}
+	oldClass := storageClassNameOf(oldWorkspace)
+	newClass := storageClassNameOf(newWorkspace)
+	if oldClass == newClass {
+		return nil
+	}
+	return fmt.Errorf("spec.storage.storageClassName cannot change from %q to %q: the storage class of the workspace "+
+		"volume is immutable; to use another storage class, create a new workspace and copy the data over",
+		oldClass, newClass)
+}
+
+// validateStorageClassUpdate rejects updates changing the storage class of a workspace with storage, except by
+// the controller, which moves the storage when it recreates an unbound PVC whose StorageClass was deleted
+func validateStorageClassUpdate(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if req, err := admission.RequestFromContext(ctx); err == nil && isControllerServiceAccount(req.UserInfo.Username) {
+		return nil
+	}
+	return validateStorageClassUnchanged(oldWorkspace, newWorkspace)
+}
+
+// systemPaths are the directories of the container image the home volume must not be mounted at, and
+// systemTrees the ones it must not be mounted under either
+var (
+	systemPaths = []string{"/", "/root", "/tmp", "/usr", "/var"}
+	systemTrees = []string{"/bin", "/boot", "/dev", "/etc", "/lib", "/lib64", "/proc", "/run", "/sbin", "/sys",
+		"/usr/bin", "/usr/lib", "/usr/sbin", "/var/run"}
+)
+
+// homeMountPathConflict returns why the home volume cannot be mounted at the path, or "" when it can: the
+// path must be absolute and not a system path of the image
+func homeMountPathConflict(mountPath string) string {
+	if !path.IsAbs(mountPath) {
+		return fmt.Sprintf("'%s' is not an absolute path", mountPath)
+	}
+	cleaned := path.Clean(mountPath)
+	if slices.Contains(systemPaths, cleaned) || slices.ContainsFunc(systemTrees, func(tree string) bool {
+		return cleaned == tree || strings.HasPrefix(cleaned, tree+"/")
+	}) {
+		return fmt.Sprintf("'%s' is a system path of the image", mountPath)
+	}
+	return ""
+}
+
+// validateHomeMountPath rejects workspaces mounting their storage at a relative or system path (applies to
+// all users). Workspaces keeping their mount path on update are not checked.
+func validateHomeMountPath(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if newWorkspace.Spec.Storage == nil || newWorkspace.Spec.Storage.MountPath == "" {
+		return nil
+	}
+	mountPath := newWorkspace.Spec.Storage.MountPath
+	if oldWorkspace != nil && oldWorkspace.Spec.Storage != nil && oldWorkspace.Spec.Storage.MountPath == mountPath {
+		return nil
+	}
+	if conflict := homeMountPathConflict(mountPath); conflict != "" {
+		return fmt.Errorf("spec.storage.mountPath cannot be '%s': %s", mountPath, conflict)
+	}
+	return nil
+}
+
+// storageSubPathOf returns the subPath of the workspace storage, empty for the root of the volume
+func storageSubPathOf(workspace *workspacev1alpha1.Workspace) string {
+	if workspace.Spec.Storage == nil {
+		return ""
+	}
+	return workspace.Spec.Storage.SubPath
+}
+
+// validateSubPathUnchanged rejects updates changing the subPath of a workspace with storage (applies to all
+// users): the data of the workspace is in the directory of its volume it was created with
+func validateSubPathUnchanged(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	if oldWorkspace.Spec.Storage == nil || newWorkspace.Spec.Storage == nil {
+		return nil
+	}
+	oldSubPath := storageSubPathOf(oldWorkspace)
+	newSubPath := storageSubPathOf(newWorkspace)
+	if oldSubPath == newSubPath {
+		return nil
+	}
+	return fmt.Errorf("spec.storage.subPath cannot change from %q to %q: the data of the workspace is in the "+
+		"directory of its volume it was created with", oldSubPath, newSubPath)
+}
+
+// validateTemplateHomeMount rejects templates mounting the home volume at a system path
+func validateTemplateHomeMount(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if template.Spec.HomeMountPath == "" {
+		return nil
+	}
+	if conflict := homeMountPathConflict(template.Spec.HomeMountPath); conflict != "" {
+		return fmt.Errorf("template '%s' spec.homeMountPath cannot be '%s': %s",
+			template.Name, template.Spec.HomeMountPath, conflict)
+	}
+	return nil
+}
+
 // storageEqual compares two StorageSpec for equality
 func storageEqual(old, new *workspacev1alpha1.StorageSpec) bool {
 	if old == nil && new == nil {
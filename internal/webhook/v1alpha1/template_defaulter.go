@@ -19,28 +19,39 @@ type DefaultApplicator func(workspace *workspacev1alpha1.Workspace, template *wo
 
 // TemplateDefaulter handles applying template defaults to workspaces
 type TemplateDefaulter struct {
-	resolver *workspaceutil.TemplateResolver
+	resolver                 *workspaceutil.TemplateResolver
+	defaultTemplateNamespace string
 }
 
 // NewTemplateDefaulter creates a new TemplateDefaulter
 func NewTemplateDefaulter(k8sClient client.Client, defaultTemplateNamespace string) *TemplateDefaulter {
 	return &TemplateDefaulter{
-		resolver: workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+		resolver:                 workspaceutil.NewTemplateResolver(k8sClient, defaultTemplateNamespace),
+		defaultTemplateNamespace: defaultTemplateNamespace,
 	}
 }
 
 // defaultApplicators is the registry of all default applicators
 var defaultApplicators = []DefaultApplicator{
+	applyServerTypeDefaults,
 	applyCoreDefaults,
 	applyResourceDefaults,
 	applyStorageDefaults,
+	applyHomeMountDefaults,
 	applyVolumeDefaults,
+	applyTmpVolumeDefaults,
 	applySchedulingDefaults,
 	applyMetadataDefaults,
+	applyPodMetadataDefaults,
 	applyAccessStrategyDefaults,
 	applyLifecycleDefaults,
 	applySecurityDefaults,
+	applyTemplateServiceAccountDefaults,
 	applyEnvDefaults,
+	applyEnvFromDefaults,
+	applyServiceMeshDefaults,
+	applyServerAdapterDefaults,
+	applyImagePolicyDefaults,
 }
 
 // ApplyTemplateDefaults applies template defaults to workspace
@@ -49,20 +60,29 @@ func (td *TemplateDefaulter) ApplyTemplateDefaults(ctx context.Context, workspac
 		return nil
 	}
 
+	// Templates of namespaces the workspace may not use are not looked up, so that their existence is not revealed
+	if err := validateTemplateNamespace(workspace, td.defaultTemplateNamespace); err != nil {
+		return err
+	}
 	template, err := td.fetchTemplate(ctx, *workspace.Spec.TemplateRef, workspace.Namespace)
 	if err != nil {
 		return err
 	}
 
+	ApplyTemplateDefaultsFrom(workspace, template)
+	return nil
+}
+
+// ApplyTemplateDefaultsFrom applies the defaults of an already resolved template to the workspace
+func ApplyTemplateDefaultsFrom(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) {
 	// Apply all defaults using registered applicators
 	for _, applicator := range defaultApplicators {
 		applicator(workspace, template)
 	}
-
-	return nil
 }
 
 // fetchTemplate retrieves a template using centralized resolver
 func (td *TemplateDefaulter) fetchTemplate(ctx context.Context, templateRef workspacev1alpha1.TemplateRef, workspaceNamespace string) (*workspacev1alpha1.WorkspaceTemplate, error) {
-	return td.resolver.ResolveTemplate(ctx, &templateRef, workspaceNamespace)
+	template, err := td.resolver.ResolveTemplate(ctx, &templateRef, workspaceNamespace)
+	return template, withTemplateNotFoundReason(err)
 }
@@ -11,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -18,6 +19,7 @@ import (
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
 )
 
 var _ = Describe("TemplateDefaulter", func() {
@@ -136,7 +138,43 @@ var _ = Describe("TemplateDefaulter", func() {
 
 			err := defaulter.ApplyTemplateDefaults(ctx, workspace)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("failed to get template"))
+			Expect(apierrors.ReasonForError(err)).To(Equal(reasons.TemplateNotFound))
+			status := err.(apierrors.APIStatus).Status()
+			Expect(status.Details.Name).To(Equal("non-existent-template"))
+			Expect(status.Details.Causes).To(Equal([]metav1.StatusCause{{
+				Type:    reasons.CauseTypeNamespaceTried,
+				Field:   reasons.FieldTemplateRefNamespace,
+				Message: "default",
+			}}))
+		})
+
+		It("should list the fallback namespace tried when template not found", func() {
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			defaulter = NewTemplateDefaulter(fake.NewClientBuilder().WithScheme(scheme).Build(), "shared")
+			workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: "non-existent-template"}
+
+			err := defaulter.ApplyTemplateDefaults(ctx, workspace)
+			Expect(apierrors.ReasonForError(err)).To(Equal(reasons.TemplateNotFound))
+			causes := err.(apierrors.APIStatus).Status().Details.Causes
+			Expect(causes).To(HaveLen(2))
+			Expect(causes[0].Message).To(Equal("default"))
+			Expect(causes[1].Message).To(Equal("shared"))
+		})
+
+		It("should not look up templates of namespaces the workspace may not use", func() {
+			workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: template.Name, Namespace: "team-b"}
+
+			err := defaulter.ApplyTemplateDefaults(ctx, workspace)
+			Expect(apierrors.ReasonForError(err)).To(Equal(reasons.TemplateNamespaceForbidden))
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			causes := err.(apierrors.APIStatus).Status().Details.Causes
+			Expect(causes).To(Equal([]metav1.StatusCause{{
+				Type:    reasons.CauseTypeNamespaceAllowed,
+				Field:   reasons.FieldTemplateRefNamespace,
+				Message: "default",
+			}}))
+			Expect(workspace.Spec.Image).To(BeEmpty())
 		})
 	})
 })
@@ -0,0 +1,43 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// validateTemplateExampleWorkspaces rejects templates that do not accept one of their example workspaces.
+// Each example goes through the defaulting and template validation of a workspace referencing the template.
+func validateTemplateExampleWorkspaces(template *workspacev1alpha1.WorkspaceTemplate) error {
+	var problems []string
+	for _, example := range template.Spec.ExampleWorkspaces {
+		for _, violation := range validateTemplateExampleWorkspace(template, &example) {
+			problems = append(problems, fmt.Sprintf("example '%s': %s: %s", example.Name, violation.Field, violation.Message))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("template '%s' does not accept its example workspaces: %s", template.Name, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateTemplateExampleWorkspace returns the violations of the example workspace once defaulted by the template
+func validateTemplateExampleWorkspace(
+	template *workspacev1alpha1.WorkspaceTemplate,
+	example *workspacev1alpha1.TemplateExampleWorkspace) []TemplateViolation {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: example.Name, Namespace: template.Namespace},
+		Spec:       *example.Spec.DeepCopy(),
+	}
+	workspace.Spec.TemplateRef = &workspacev1alpha1.TemplateRef{Name: template.Name, Namespace: template.Namespace}
+	ApplyTemplateDefaultsFrom(workspace, template)
+	return ValidateWorkspaceAgainstTemplate(workspace, template)
+}
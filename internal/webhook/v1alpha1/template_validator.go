@@ -13,6 +13,7 @@ import (
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
 	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
@@ -32,12 +33,13 @@ func NewTemplateValidator(k8sClient client.Client, defaultTemplateNamespace stri
 
 // fetchTemplate retrieves a template using centralized resolver
 func (tv *TemplateValidator) fetchTemplate(ctx context.Context, templateRef *workspacev1alpha1.TemplateRef, workspaceNamespace string) (*workspacev1alpha1.WorkspaceTemplate, error) {
-	return tv.resolver.ResolveTemplate(ctx, templateRef, workspaceNamespace)
+	template, err := tv.resolver.ResolveTemplate(ctx, templateRef, workspaceNamespace)
+	return template, withTemplateNotFoundReason(err)
 }
 
 // validateTemplateNamespace checks that templateRef.namespace targets an allowed namespace.
 // Workspaces can only reference templates from their own namespace or the shared namespace
-func (tv *TemplateValidator) validateTemplateNamespace(workspace *workspacev1alpha1.Workspace) error {
+func validateTemplateNamespace(workspace *workspacev1alpha1.Workspace, defaultTemplateNamespace string) error {
 	templateNamespace := workspace.Spec.TemplateRef.Namespace
 	workspaceNamespace := workspace.Namespace
 
@@ -45,31 +47,39 @@ func (tv *TemplateValidator) validateTemplateNamespace(workspace *workspacev1alp
 		return nil
 	}
 
-	if tv.defaultTemplateNamespace == "" {
-		return fmt.Errorf(
-			"templateRef.namespace %q is not allowed: templates must be in the workspace namespace %q",
-			templateNamespace, workspaceNamespace,
-		)
+	if defaultTemplateNamespace == "" {
+		return namespaceForbiddenError(reasons.TemplateNamespaceForbidden, reasons.FieldTemplateRefNamespace,
+			resourceWorkspaceTemplates, workspace.Spec.TemplateRef.Name, fmt.Sprintf(
+				"templateRef.namespace %q is not allowed: templates must be in the workspace namespace %q",
+				templateNamespace, workspaceNamespace,
+			), workspaceNamespace)
 	}
 
-	if templateNamespace == tv.defaultTemplateNamespace {
+	if templateNamespace == defaultTemplateNamespace {
 		return nil
 	}
 
-	return fmt.Errorf(
-		"templateRef.namespace %q is not allowed: templates must be in the workspace namespace %q or the shared namespace %q",
-		templateNamespace, workspaceNamespace, tv.defaultTemplateNamespace,
-	)
+	return namespaceForbiddenError(reasons.TemplateNamespaceForbidden, reasons.FieldTemplateRefNamespace,
+		resourceWorkspaceTemplates, workspace.Spec.TemplateRef.Name, fmt.Sprintf(
+			"templateRef.namespace %q is not allowed: templates must be in the workspace namespace %q or the shared namespace %q",
+			templateNamespace, workspaceNamespace, defaultTemplateNamespace,
+		), workspaceNamespace, defaultTemplateNamespace)
 }
 
 // ValidateCreateWorkspace validates workspace against template constraints
 func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
+	return tv.validateWorkspace(ctx, nil, workspace)
+}
+
+// validateWorkspace validates workspace against template constraints; oldWorkspace is the workspace
+// before an update with the same templateRef, nil otherwise
+func (tv *TemplateValidator) validateWorkspace(ctx context.Context, oldWorkspace, workspace *workspacev1alpha1.Workspace) error {
 	if workspace.Spec.TemplateRef == nil {
 		return nil
 	}
 
 	// Reject templateRef.namespace if it targets a namespace other than the workspace's own ns
-	if err := tv.validateTemplateNamespace(workspace); err != nil {
+	if err := validateTemplateNamespace(workspace, tv.defaultTemplateNamespace); err != nil {
 		return err
 	}
 
@@ -78,6 +88,26 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		return err
 	}
 
+	violations := ValidateWorkspaceAgainstTemplate(workspace, template)
+	if oldWorkspace != nil {
+		violations = withoutKeptStorageClassViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptStorageModeViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptCommandOverrideViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptIdleShutdownViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptSchedulingViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptServerTypeViolations(violations, oldWorkspace, workspace)
+		violations = withoutKeptServiceAccountViolations(violations, oldWorkspace, workspace)
+		explainKeptImageViolations(violations, oldWorkspace, workspace)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("workspace violates template '%s' constraints: %s", workspace.Spec.TemplateRef.Name, formatViolations(violations))
+	}
+
+	return nil
+}
+
+// ValidateWorkspaceAgainstTemplate returns the violations of an already resolved template's constraints
+func ValidateWorkspaceAgainstTemplate(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
 	var violations []TemplateViolation
 
 	// Validate image
@@ -85,6 +115,19 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		if violation := validateImageAllowed(workspace.Spec.Image, template); violation != nil {
 			violations = append(violations, *violation)
 		}
+		if violation := validateImagePolicy(workspace.Spec.Image, "spec.image", template); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	// Validate the server type
+	if violation := validateServerType(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate image pull policy
+	if violation := validateImagePullPolicy(workspace, template); violation != nil {
+		violations = append(violations, *violation)
 	}
 
 	// Validate resources
@@ -94,6 +137,56 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		}
 	}
 
+	// Validate the workspace container keeps enough resources beside the sidecars
+	if sidecarViolations := validateCarvedSidecarResources(workspace, template); len(sidecarViolations) > 0 {
+		violations = append(violations, sidecarViolations...)
+	}
+
+	// Validate the container command and args
+	if violation := validateCommandOverride(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the postStart hook can run after the template post-start script
+	if violation := validatePostStartHook(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the idle shutdown override
+	if violation := validateIdleShutdownOverride(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate accelerators
+	if violation := validateGPUCount(workspace.Spec.GPUCount, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the security contexts keep the isolation level
+	if isolationViolations := validateIsolationLevel(workspace, template); len(isolationViolations) > 0 {
+		violations = append(violations, isolationViolations...)
+	}
+
+	// Validate the node selector, node affinity and tolerations
+	if schedulingViolations := validateSchedulingPolicy(workspace, template); len(schedulingViolations) > 0 {
+		violations = append(violations, schedulingViolations...)
+	}
+
+	// Validate the priority class against the allowlist
+	if violation := validatePriorityClassAllowed(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the service account against the allowlist
+	if violation := validateServiceAccountAllowed(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the /tmp volume
+	if violation := validateTmpVolumeSize(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
 	// Only validate storage if it changed
 	if workspace.Spec.Storage != nil && !workspace.Spec.Storage.Size.IsZero() {
 		if violation := validateStorageSize(workspace.Spec.Storage.Size, template); violation != nil {
@@ -101,11 +194,31 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		}
 	}
 
+	// Validate the storage class against the allowlist
+	if violation := validateStorageClassAllowed(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the storage mode against the one of the template
+	if violation := validateStorageMode(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
+	// Validate the workspace volumes keep the shared volumes of the template
+	if violation := validateSharedVolumesKept(workspace, template); violation != nil {
+		violations = append(violations, *violation)
+	}
+
 	// Validate secondary storage volumes
 	if violation := validateSecondaryStorages(workspace.Spec.Volumes, template); violation != nil {
 		violations = append(violations, *violation)
 	}
 
+	// Validate the source types of extra volumes
+	if volumeViolations := validateVolumeSources(workspace, template); len(volumeViolations) > 0 {
+		violations = append(violations, volumeViolations...)
+	}
+
 	// Validate label requirements
 	if labelViolations := validateLabelRequirements(workspace, template); len(labelViolations) > 0 {
 		violations = append(violations, labelViolations...)
@@ -116,11 +229,12 @@ func (tv *TemplateValidator) ValidateCreateWorkspace(ctx context.Context, worksp
 		violations = append(violations, envViolations...)
 	}
 
-	if len(violations) > 0 {
-		return fmt.Errorf("workspace violates template '%s' constraints: %s", workspace.Spec.TemplateRef.Name, formatViolations(violations))
+	// Validate env names against the allowlist
+	if envViolations := validateEnvAllowed(workspace, template); len(envViolations) > 0 {
+		violations = append(violations, envViolations...)
 	}
 
-	return nil
+	return violations
 }
 
 // ValidateUpdateWorkspace validates entire spec when any spec field changes (Kubernetes best practice)
@@ -132,7 +246,9 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 	// Detect templateRef transitions
 	templateRefDeleted := oldTemplateRef != nil && newTemplateRef == nil
 	templateRefAdded := oldTemplateRef == nil && newTemplateRef != nil
-	templateRefChanged := oldTemplateRef != nil && newTemplateRef != nil && oldTemplateRef.Name != newTemplateRef.Name
+	templateRefChanged := oldTemplateRef != nil && newTemplateRef != nil &&
+		(oldTemplateRef.Name != newTemplateRef.Name ||
+			workspaceutil.GetTemplateRefNamespace(oldWorkspace) != workspaceutil.GetTemplateRefNamespace(newWorkspace))
 
 	// Case 1: TemplateRef deleted (template → standalone)
 	// Removing constraints is always safe - no validation needed
@@ -141,7 +257,7 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 		return nil
 	}
 
-	// Case 2: TemplateRef changed (template A → template B)
+	// Case 2: TemplateRef changed (template A → template B, including a template of the same name in another namespace)
 	// Must validate entire spec against NEW template
 	if templateRefChanged {
 		workspacelog.Info("TemplateRef changed, validating against new template",
@@ -182,11 +298,20 @@ func (tv *TemplateValidator) ValidateUpdateWorkspace(ctx context.Context, oldWor
 		return nil
 	}
 
+	// Pausing and hibernating release the compute like a stop does
+	if (newWorkspace.Spec.DesiredStatus == controller.DesiredStatePaused ||
+		newWorkspace.Spec.DesiredStatus == controller.DesiredStateHibernated) &&
+		onlyDesiredStatusChanged(&oldWorkspace.Spec, &newWorkspace.Spec) {
+		workspacelog.Info("Allowing workspace pause or hibernation without template validation (status-only change)",
+			"workspace", newWorkspace.Name)
+		return nil
+	}
+
 	// Spec changed with same template - validate ENTIRE spec against template
 	// This follows Kubernetes best practices: admission webhooks validate desired state, not deltas
 	// This includes cases where stopping + other changes occur simultaneously
 	workspacelog.Info("Spec changed, validating entire workspace against template", "workspace", newWorkspace.Name)
-	return tv.ValidateCreateWorkspace(ctx, newWorkspace)
+	return tv.validateWorkspace(ctx, oldWorkspace, newWorkspace)
 }
 
 // formatViolations formats template violations into a readable error message
@@ -179,4 +179,97 @@ var _ = Describe("TemplateValidator", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 	})
+
+	Context("TemplateRef changes", func() {
+		It("should validate against a template of the same name in another namespace", func() {
+			template := &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "standard",
+					Namespace: "jupyter-k8s-shared",
+				},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DefaultImage:  "jupyter/base-notebook:latest",
+					DisplayName:   "Standard",
+					AllowedImages: []string{"jupyter/base-notebook:latest"},
+				},
+			}
+			validator := buildValidator("jupyter-k8s-shared", template)
+
+			oldWorkspace := &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Image:       "jupyter/scipy-notebook:latest",
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: "standard"},
+				},
+			}
+			newWorkspace := oldWorkspace.DeepCopy()
+			newWorkspace.Spec.TemplateRef.Namespace = "jupyter-k8s-shared"
+
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("jupyter/scipy-notebook:latest"))
+		})
+	})
+
+	Context("Image policy changes", func() {
+		var template *workspacev1alpha1.WorkspaceTemplate
+		var oldWorkspace *workspacev1alpha1.Workspace
+
+		BeforeEach(func() {
+			// The workspace was admitted while the template allowed any image
+			template = &workspacev1alpha1.WorkspaceTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "standard", Namespace: "team-a"},
+				Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+					DefaultImage:  "jupyter/base-notebook:2024.01",
+					DisplayName:   "Standard",
+					AllowedImages: []string{"jupyter/scipy-notebook:*"},
+					ImagePolicy:   &workspacev1alpha1.ImagePolicy{Mode: workspacev1alpha1.ImagePolicyModeAllowList},
+				},
+			}
+			oldWorkspace = &workspacev1alpha1.Workspace{
+				ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+				Spec: workspacev1alpha1.WorkspaceSpec{
+					Image:       "custom/notebook:1.0",
+					TemplateRef: &workspacev1alpha1.TemplateRef{Name: "standard"},
+				},
+			}
+		})
+
+		It("should allow metadata updates of a workspace whose image is no longer allowed", func() {
+			validator := buildValidator("", template)
+			newWorkspace := oldWorkspace.DeepCopy()
+			newWorkspace.Labels = map[string]string{"team": "a"}
+
+			Expect(validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should reject spec updates keeping an image that is no longer allowed", func() {
+			validator := buildValidator("", template)
+			newWorkspace := oldWorkspace.DeepCopy()
+			newWorkspace.Spec.DisplayName = "renamed"
+
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no longer allowed since its template changed"))
+			Expect(err.Error()).To(ContainSubstring("images matching [jupyter/scipy-notebook:*]"))
+		})
+
+		It("should accept spec updates moving to an allowed image", func() {
+			validator := buildValidator("", template)
+			newWorkspace := oldWorkspace.DeepCopy()
+			newWorkspace.Spec.Image = "jupyter/scipy-notebook:2024.01"
+
+			Expect(validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)).To(Succeed())
+		})
+
+		It("should not reword the rejection of a new image", func() {
+			validator := buildValidator("", template)
+			newWorkspace := oldWorkspace.DeepCopy()
+			newWorkspace.Spec.Image = "custom/notebook:2.0"
+
+			err := validator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("no longer allowed"))
+		})
+	})
 })
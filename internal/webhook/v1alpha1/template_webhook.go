@@ -8,15 +8,23 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
 )
 
 // log is for logging in this package.
@@ -25,11 +33,11 @@ var templatelog = logf.Log.WithName("workspacetemplate-resource")
 // SetupWorkspaceTemplateWebhookWithManager registers the webhook for WorkspaceTemplate in the manager.
 func SetupWorkspaceTemplateWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&workspacev1alpha1.WorkspaceTemplate{}).
-		WithValidator(&WorkspaceTemplateCustomValidator{}).
+		WithValidator(&WorkspaceTemplateCustomValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
-// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspacetemplate,mutating=false,failurePolicy=ignore,sideEffects=None,groups=workspace.jupyter.org,resources=workspacetemplates,verbs=update,versions=v1alpha1,name=vworkspacetemplate-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+// +kubebuilder:webhook:path=/validate-workspace-jupyter-org-v1alpha1-workspacetemplate,mutating=false,failurePolicy=ignore,sideEffects=None,groups=workspace.jupyter.org,resources=workspacetemplates,verbs=create;update;delete,versions=v1alpha1,name=vworkspacetemplate-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
 
 // WorkspaceTemplateCustomValidator struct is responsible for validating the WorkspaceTemplate resource
 // when it is updated. It checks if constraint fields changed and returns warnings.
@@ -38,6 +46,8 @@ func SetupWorkspaceTemplateWebhookWithManager(mgr ctrl.Manager) error {
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type WorkspaceTemplateCustomValidator struct {
+	// client lists the workspaces using a template being deleted; status.workspaceCount is used when nil
+	client client.Client
 }
 
 var _ webhook.CustomValidator = &WorkspaceTemplateCustomValidator{}
@@ -50,8 +60,77 @@ func (v *WorkspaceTemplateCustomValidator) ValidateCreate(ctx context.Context, o
 	}
 	templatelog.Info("Validation for WorkspaceTemplate upon creation", "name", template.GetName())
 
-	// No special validation needed on create
-	return nil, nil
+	// Validate the template's own image options against its image policy
+	if err := validateTemplateImagePolicy(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the labels and annotations the template sets on generated resources
+	if err := validateTemplateChildMetadata(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the labels and annotations the template sets on workspace pods
+	if err := validatePodMetadata(template.Spec.PodMetadata, "spec.podMetadata"); err != nil {
+		return nil, err
+	}
+
+	// Validate the home volume is not mounted at a system path
+	if err := validateTemplateHomeMount(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the shared volumes are not mounted at a system path nor over each other
+	if err := validateTemplateSharedVolumes(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the env name allowlist
+	if err := validateTemplateEnvPatterns(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the node selector and toleration allowlists
+	if err := validateTemplateSchedulingPolicy(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the sidecars the schema does not describe
+	if err := validateTemplateSidecars(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the sidecars leave the workspace container resources
+	if err := validateTemplateSidecarResourceAccounting(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the default postStart hook can run after the post-start script
+	if err := validateTemplateLifecycle(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the default security contexts keep the isolation level
+	if err := validateTemplateIsolationLevel(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the default schedule can be evaluated
+	if err := validateTemplateSchedule(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the init containers the schema does not describe
+	if err := validateTemplateInitContainers(template); err != nil {
+		return nil, err
+	}
+
+	// Validate the template accepts its example workspaces
+	if err := validateTemplateExampleWorkspaces(template); err != nil {
+		return nil, err
+	}
+
+	return clusterAccessWarnings(template), nil
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type WorkspaceTemplate.
@@ -66,14 +145,86 @@ func (v *WorkspaceTemplateCustomValidator) ValidateUpdate(ctx context.Context, o
 	}
 	templatelog.Info("Validation for WorkspaceTemplate upon update", "name", newTemplate.GetName())
 
+	// Validate the template's own image options against its image policy
+	if err := validateTemplateImagePolicy(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the labels and annotations the template sets on generated resources
+	if err := validateTemplateChildMetadata(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the labels and annotations the template sets on workspace pods
+	if err := validatePodMetadata(newTemplate.Spec.PodMetadata, "spec.podMetadata"); err != nil {
+		return nil, err
+	}
+
+	// Validate the home volume is not mounted at a system path
+	if err := validateTemplateHomeMount(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the shared volumes are not mounted at a system path nor over each other
+	if err := validateTemplateSharedVolumes(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the env name allowlist
+	if err := validateTemplateEnvPatterns(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the node selector and toleration allowlists
+	if err := validateTemplateSchedulingPolicy(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the sidecars the schema does not describe
+	if err := validateTemplateSidecars(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the sidecars leave the workspace container resources
+	if err := validateTemplateSidecarResourceAccounting(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the default postStart hook can run after the post-start script
+	if err := validateTemplateLifecycle(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the default security contexts keep the isolation level
+	if err := validateTemplateIsolationLevel(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the default schedule can be evaluated
+	if err := validateTemplateSchedule(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the init containers the schema does not describe
+	if err := validateTemplateInitContainers(newTemplate); err != nil {
+		return nil, err
+	}
+
+	// Validate the template still accepts its example workspaces
+	if err := validateTemplateExampleWorkspaces(newTemplate); err != nil {
+		return nil, err
+	}
+
+	warnings := clusterAccessWarnings(newTemplate)
+
 	// Check if constraint fields changed
 	if constraintsChanged(oldTemplate, newTemplate) {
 		templatelog.Info("Template constraints changed, controller will mark workspaces for compliance check", "template", newTemplate.GetName())
 		// Return a warning to inform the user that workspaces will be validated
-		return admission.Warnings{"Template constraints changed. Affected workspaces will be marked for compliance validation by the controller."}, nil
+		warnings = append(warnings, "Template constraints changed. Affected workspaces will be marked for compliance validation by the controller.")
 	}
 
-	return nil, nil
+	return warnings, nil
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type WorkspaceTemplate.
@@ -84,10 +235,149 @@ func (v *WorkspaceTemplateCustomValidator) ValidateDelete(ctx context.Context, o
 	}
 	templatelog.Info("Validation for WorkspaceTemplate upon deletion", "name", template.GetName())
 
-	// Deletion is handled by finalizers in the controller
+	// Require acknowledging the workspaces using the template; the finalizer of the controller
+	// still keeps the template until no workspace uses it
+	if err := v.validateDeleteConfirmation(ctx, template); err != nil {
+		return nil, err
+	}
 	return nil, nil
 }
 
+// deleteConfirmationSampleSize is the number of workspaces named when a template delete is rejected
+const deleteConfirmationSampleSize = 5
+
+// validateDeleteConfirmation rejects deleting a template used by workspaces unless its
+// workspace.jupyter.org/confirm-delete annotation matches the number of workspaces using it.
+// The workspaces are counted from the cache when possible, status.workspaceCount lagging behind deletions.
+func (v *WorkspaceTemplateCustomValidator) validateDeleteConfirmation(
+	ctx context.Context, template *workspacev1alpha1.WorkspaceTemplate) error {
+	count := int(template.Status.WorkspaceCount)
+	var workspaces []workspacev1alpha1.Workspace
+	if v.client != nil {
+		listed, _, err := workspaceutil.ListActiveWorkspacesByTemplate(
+			ctx, v.client, template.Name, template.Namespace, "", 0)
+		if err != nil {
+			templatelog.Error(err, "Failed to list the workspaces using the template", "template", template.Name)
+		} else {
+			workspaces, count = listed, len(listed)
+		}
+	}
+	if count == 0 || template.Annotations[controller.AnnotationConfirmDelete] == strconv.Itoa(count) {
+		return nil
+	}
+
+	return fmt.Errorf("template '%s' is used by %d workspace(s)%s. Deleting it does not delete them: "+
+		"they keep running with their current configuration, but no longer re-resolve template defaults and bounds, "+
+		"and the template remains until the last of them is deleted. To confirm, annotate the template with %s=%q",
+		template.Name, count, formatAffectedWorkspaces(workspaces), controller.AnnotationConfirmDelete, strconv.Itoa(count))
+}
+
+// formatAffectedWorkspaces names a sample of the workspaces using a template
+func formatAffectedWorkspaces(workspaces []workspacev1alpha1.Workspace) string {
+	if len(workspaces) == 0 {
+		return ""
+	}
+	names := make([]string, 0, deleteConfirmationSampleSize)
+	for _, ws := range workspaces {
+		names = append(names, ws.Namespace+"/"+ws.Name)
+	}
+	slices.Sort(names)
+	if len(names) > deleteConfirmationSampleSize {
+		names = names[:deleteConfirmationSampleSize]
+	}
+	sample := strings.Join(names, ", ")
+	if others := len(workspaces) - len(names); others > 0 {
+		sample = fmt.Sprintf("%s and %d more", sample, others)
+	}
+	return " (" + sample + ")"
+}
+
+// clusterAccessWarnings flags templates still relying on the legacy automounted service account token,
+// which is long-lived and readable by any code running in the workspace
+func clusterAccessWarnings(template *workspacev1alpha1.WorkspaceTemplate) admission.Warnings {
+	access := template.Spec.ClusterAccess
+	if access == nil || !access.Enabled || access.TokenMount != workspacev1alpha1.ServiceAccountTokenMountLegacy {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf(
+		"Template %s relies on the legacy automounted service account token; set clusterAccess.tokenMount to %s "+
+			"for a short-lived, audience-bound token", template.Name, workspacev1alpha1.ServiceAccountTokenMountProjected)}
+}
+
+// validateTemplateImagePolicy rejects templates whose image options violate their own image policy
+func validateTemplateImagePolicy(template *workspacev1alpha1.WorkspaceTemplate) error {
+	if err := validateTemplateAllowedImagePatterns(template); err != nil {
+		return err
+	}
+	if violations := validateTemplateImageOptions(template); len(violations) > 0 {
+		return fmt.Errorf("template '%s' violates its image policy: %s", template.Name, formatViolations(violations))
+	}
+	return nil
+}
+
+// childMetadataSampleVars are used to check that expanded child label values are valid label values
+var childMetadataSampleVars = workspaceutil.ChildMetadataVars{
+	WorkspaceName:      "workspace",
+	WorkspaceNamespace: "namespace",
+	WorkspaceOwner:     "owner",
+}
+
+// validateTemplateChildMetadata rejects child metadata with reserved or invalid keys and values
+// that reference unknown variables or do not form valid label values
+func validateTemplateChildMetadata(template *workspacev1alpha1.WorkspaceTemplate) error {
+	metadata := template.Spec.ChildMetadata
+	if metadata == nil {
+		return nil
+	}
+	children := map[string]*workspacev1alpha1.ChildObjectMetadata{
+		"pod":     metadata.Pod,
+		"service": metadata.Service,
+		"ingress": metadata.Ingress,
+		"pvc":     metadata.PVC,
+		"secret":  metadata.Secret,
+	}
+	var violations []string
+	for _, child := range slices.Sorted(maps.Keys(children)) {
+		object := children[child]
+		if object == nil {
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(object.Labels)) {
+			value := object.Labels[key]
+			violations = append(violations, validateChildMetadataEntry(child, "label", key, value)...)
+			if err := workspaceutil.ValidateChildMetadataValue(value); err == nil {
+				expanded := workspaceutil.ExpandChildMetadataValue(value, childMetadataSampleVars)
+				for _, msg := range validation.IsValidLabelValue(expanded) {
+					violations = append(violations, fmt.Sprintf("childMetadata.%s label '%s': %s", child, key, msg))
+				}
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(object.Annotations)) {
+			violations = append(violations, validateChildMetadataEntry(child, "annotation", key, object.Annotations[key])...)
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("template '%s' has invalid child metadata: %s", template.Name, strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// validateChildMetadataEntry checks the key and the variable references of a child label or annotation
+func validateChildMetadataEntry(child, kind, key, value string) []string {
+	var violations []string
+	if strings.HasPrefix(key, controller.ReservedMetadataPrefix) {
+		violations = append(violations, fmt.Sprintf("childMetadata.%s %s '%s': keys with prefix '%s' are managed by the controller",
+			child, kind, key, controller.ReservedMetadataPrefix))
+	}
+	for _, msg := range validation.IsQualifiedName(key) {
+		violations = append(violations, fmt.Sprintf("childMetadata.%s %s '%s': %s", child, kind, key, msg))
+	}
+	if err := workspaceutil.ValidateChildMetadataValue(value); err != nil {
+		violations = append(violations, fmt.Sprintf("childMetadata.%s %s '%s': %s", child, kind, key, err))
+	}
+	return violations
+}
+
 // constraintsChanged checks if any constraint fields changed between old and new templates
 // Constraint fields are those that affect workspace validation (resource bounds, allowed images, etc.)
 func constraintsChanged(oldTemplate, newTemplate *workspacev1alpha1.WorkspaceTemplate) bool {
@@ -99,6 +389,11 @@ func constraintsChanged(oldTemplate, newTemplate *workspacev1alpha1.WorkspaceTem
 		return true
 	}
 
+	// Check ImagePolicy changes
+	if !equality.Semantic.DeepEqual(oldSpec.ImagePolicy, newSpec.ImagePolicy) {
+		return true
+	}
+
 	// Check ResourceBounds changes
 	if resourceBoundsChanged(oldSpec.ResourceBounds, newSpec.ResourceBounds) {
 		return true
@@ -124,6 +419,42 @@ func constraintsChanged(oldTemplate, newTemplate *workspacev1alpha1.WorkspaceTem
 		return true
 	}
 
+	// Check AllowedVolumeSources changes
+	if !slices.Equal(oldSpec.AllowedVolumeSources, newSpec.AllowedVolumeSources) {
+		return true
+	}
+
+	// Check AllowedEnvPatterns changes
+	if !slices.Equal(oldSpec.AllowedEnvPatterns, newSpec.AllowedEnvPatterns) {
+		return true
+	}
+
+	// Check SchedulingPolicy changes
+	if !equality.Semantic.DeepEqual(oldSpec.SchedulingPolicy, newSpec.SchedulingPolicy) {
+		return true
+	}
+
+	// Check AllowedPriorityClassNames changes
+	if !slices.Equal(oldSpec.AllowedPriorityClassNames, newSpec.AllowedPriorityClassNames) {
+		return true
+	}
+
+	// Check the service account default and allowlist changes
+	if oldSpec.DefaultServiceAccountName != newSpec.DefaultServiceAccountName ||
+		!slices.Equal(oldSpec.AllowedServiceAccounts, newSpec.AllowedServiceAccounts) {
+		return true
+	}
+
+	// Check ServerTypes changes
+	if !equality.Semantic.DeepEqual(oldSpec.ServerTypes, newSpec.ServerTypes) {
+		return true
+	}
+
+	// Check SharedVolumes changes
+	if !equality.Semantic.DeepEqual(oldSpec.SharedVolumes, newSpec.SharedVolumes) {
+		return true
+	}
+
 	return false
 }
 
@@ -176,6 +507,11 @@ func maxStorageSizeChanged(oldStorage, newStorage *workspacev1alpha1.StorageConf
 		return true
 	}
 
+	// Check storage mode changes (also affects validation)
+	if oldStorage.Mode != newStorage.Mode || oldStorage.AllowModeOverride != newStorage.AllowModeOverride {
+		return true
+	}
+
 	return false
 }
 
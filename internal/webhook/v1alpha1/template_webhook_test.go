@@ -0,0 +1,211 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	workspaceutil "github.com/jupyter-infra/jupyter-k8s/internal/workspace"
+)
+
+var _ = Describe("WorkspaceTemplate Webhook", func() {
+	var template *workspacev1alpha1.WorkspaceTemplate
+
+	BeforeEach(func() {
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "dns", Namespace: "default"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DefaultImage:  "jupyter/base-notebook:latest",
+				ChildMetadata: &workspacev1alpha1.ChildMetadata{},
+			},
+		}
+	})
+
+	Describe("validateTemplateChildMetadata", func() {
+		It("should accept labels and annotations referencing supported variables", func() {
+			template.Spec.ChildMetadata.Service = &workspacev1alpha1.ChildObjectMetadata{
+				Labels: map[string]string{"team": "${workspace.namespace}"},
+				Annotations: map[string]string{
+					"external-dns.alpha.kubernetes.io/hostname": "${workspace.name}.${workspace.namespace}.example.com",
+					"backup.example.com/owner":                  "${workspace.owner}",
+				},
+			}
+			_, err := (&WorkspaceTemplateCustomValidator{}).ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject unknown variables", func() {
+			template.Spec.ChildMetadata.Ingress = &workspacev1alpha1.ChildObjectMetadata{
+				Annotations: map[string]string{"cert-manager.io/common-name": "${workspace.uid}"},
+			}
+			err := validateTemplateChildMetadata(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown variable ${workspace.uid}"))
+		})
+
+		It("should reject keys managed by the controller", func() {
+			template.Spec.ChildMetadata.Pod = &workspacev1alpha1.ChildObjectMetadata{
+				Labels: map[string]string{"workspace.jupyter.org/workspace-name": "other"},
+			}
+			err := validateTemplateChildMetadata(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("managed by the controller"))
+		})
+
+		It("should reject invalid keys and label values", func() {
+			template.Spec.ChildMetadata.PVC = &workspacev1alpha1.ChildObjectMetadata{
+				Labels:      map[string]string{"policy": "daily backups"},
+				Annotations: map[string]string{"not a key": "value"},
+			}
+			err := validateTemplateChildMetadata(template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("childMetadata.pvc label 'policy'"))
+			Expect(err.Error()).To(ContainSubstring("childMetadata.pvc annotation 'not a key'"))
+		})
+
+		It("should reject invalid child metadata on update", func() {
+			newTemplate := template.DeepCopy()
+			newTemplate.Spec.ChildMetadata.Secret = &workspacev1alpha1.ChildObjectMetadata{
+				Annotations: map[string]string{"reflector/owner": "${workspace.owner"},
+			}
+			_, err := (&WorkspaceTemplateCustomValidator{}).ValidateUpdate(context.Background(), template, newTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unterminated variable reference"))
+		})
+	})
+
+	Describe("validateTemplateExampleWorkspaces", func() {
+		BeforeEach(func() {
+			template.Spec.AllowedImages = []string{"jupyter/base-notebook:latest", "jupyter/scipy-notebook:latest"}
+			template.Spec.ExampleWorkspaces = []workspacev1alpha1.TemplateExampleWorkspace{
+				{Name: "default", Spec: workspacev1alpha1.WorkspaceSpec{DisplayName: "Default"}},
+				{Name: "scipy", Spec: workspacev1alpha1.WorkspaceSpec{DisplayName: "SciPy", Image: "jupyter/scipy-notebook:latest"}},
+			}
+		})
+
+		It("should accept a template accepting its examples once defaulted", func() {
+			_, err := (&WorkspaceTemplateCustomValidator{}).ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject an update breaking an example, naming the example and the rule", func() {
+			newTemplate := template.DeepCopy()
+			newTemplate.Spec.AllowedImages = []string{"jupyter/base-notebook:latest"}
+			_, err := (&WorkspaceTemplateCustomValidator{}).ValidateUpdate(context.Background(), template, newTemplate)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("example 'scipy': spec.image"))
+			Expect(err.Error()).NotTo(ContainSubstring("example 'default'"))
+		})
+
+		It("should not change the examples while validating them", func() {
+			Expect(validateTemplateExampleWorkspaces(template)).To(Succeed())
+			Expect(template.Spec.ExampleWorkspaces[0].Spec.Image).To(BeEmpty())
+			Expect(template.Spec.ExampleWorkspaces[0].Spec.TemplateRef).To(BeNil())
+		})
+	})
+
+	Describe("clusterAccessWarnings", func() {
+		It("should not warn for templates without cluster access or with projected tokens", func() {
+			Expect(clusterAccessWarnings(template)).To(BeEmpty())
+			template.Spec.ClusterAccess = &workspacev1alpha1.ClusterAccessSpec{
+				Enabled: true, TokenMount: workspacev1alpha1.ServiceAccountTokenMountProjected,
+			}
+			Expect(clusterAccessWarnings(template)).To(BeEmpty())
+			template.Spec.ClusterAccess = &workspacev1alpha1.ClusterAccessSpec{TokenMount: workspacev1alpha1.ServiceAccountTokenMountLegacy}
+			Expect(clusterAccessWarnings(template)).To(BeEmpty(), "no token is mounted while disabled")
+		})
+
+		It("should warn on create and update when the template relies on the legacy token", func() {
+			template.Spec.ClusterAccess = &workspacev1alpha1.ClusterAccessSpec{
+				Enabled: true, TokenMount: workspacev1alpha1.ServiceAccountTokenMountLegacy,
+			}
+			warnings, err := (&WorkspaceTemplateCustomValidator{}).ValidateCreate(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("legacy automounted service account token")))
+
+			warnings, err = (&WorkspaceTemplateCustomValidator{}).ValidateUpdate(context.Background(), template, template.DeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("clusterAccess.tokenMount to Projected")))
+		})
+	})
+
+	Describe("ValidateDelete", func() {
+		var validator *WorkspaceTemplateCustomValidator
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+			var workspaces []client.Object
+			for _, name := range []string{"ws-f", "ws-e", "ws-d", "ws-c", "ws-b", "ws-a"} {
+				workspaces = append(workspaces, &workspacev1alpha1.Workspace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      name,
+						Namespace: "team-a",
+						Labels: map[string]string{
+							workspaceutil.LabelWorkspaceTemplate:          template.Name,
+							workspaceutil.LabelWorkspaceTemplateNamespace: template.Namespace,
+						},
+					},
+					Spec: workspacev1alpha1.WorkspaceSpec{
+						TemplateRef: &workspacev1alpha1.TemplateRef{Name: template.Name, Namespace: template.Namespace},
+					},
+				})
+			}
+			validator = &WorkspaceTemplateCustomValidator{
+				client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspaces...).Build(),
+			}
+			template.Status.WorkspaceCount = 6
+		})
+
+		It("should allow deleting a template no workspace uses", func() {
+			template.Name = "unused"
+			_, err := validator.ValidateDelete(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject deleting a used template without confirmation, naming a sample of its workspaces", func() {
+			_, err := validator.ValidateDelete(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("used by 6 workspace(s) (team-a/ws-a, team-a/ws-b, team-a/ws-c, team-a/ws-d, team-a/ws-e and 1 more)"))
+			Expect(err.Error()).To(ContainSubstring("does not delete them"))
+			Expect(err.Error()).To(ContainSubstring(`workspace.jupyter.org/confirm-delete="6"`))
+		})
+
+		It("should reject a confirmation of another count", func() {
+			template.Annotations = map[string]string{controller.AnnotationConfirmDelete: "5"}
+			_, err := validator.ValidateDelete(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow deleting a used template once the count is confirmed", func() {
+			template.Annotations = map[string]string{controller.AnnotationConfirmDelete: "6"}
+			_, err := validator.ValidateDelete(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should count the workspaces rather than trust a stale status", func() {
+			template.Status.WorkspaceCount = 7
+			template.Annotations = map[string]string{controller.AnnotationConfirmDelete: "6"}
+			_, err := validator.ValidateDelete(context.Background(), template)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject without naming workspaces when it cannot list them", func() {
+			_, err := (&WorkspaceTemplateCustomValidator{}).ValidateDelete(context.Background(), template)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("used by 6 workspace(s). Deleting"))
+		})
+	})
+})
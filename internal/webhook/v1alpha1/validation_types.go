@@ -28,8 +28,14 @@ type TemplateViolation struct {
 const (
 	ViolationTypeImageNotAllowed                = "ImageNotAllowed"
 	ViolationTypeResourceExceeded               = "ResourceExceeded"
+	ViolationTypeSidecarResourcesExceeded       = "SidecarResourcesExceeded"
+	ViolationTypePostStartHookConflict          = "PostStartHookConflict"
+	ViolationTypeCommandOverrideNotAllowed      = "CommandOverrideNotAllowed"
 	ViolationTypeStorageExceeded                = "StorageExceeded"
+	ViolationTypeStorageClassNotAllowed         = "StorageClassNotAllowed"
+	ViolationTypeStorageModeNotAllowed          = "StorageModeNotAllowed"
 	ViolationTypeSecondaryStorageNotAllowed     = "SecondaryStorageNotAllowed"
+	ViolationTypeVolumeSourceNotAllowed         = "VolumeSourceNotAllowed"
 	ViolationTypeVolumeOwnedByAnotherWorkspace  = "VolumeOwnedByAnotherWorkspace"
 	ViolationTypeInvalidTemplate                = "InvalidTemplate"
 	ViolationTypeIdleShutdownOverrideNotAllowed = "IdleShutdownOverrideNotAllowed"
@@ -38,4 +44,14 @@ const (
 	ViolationTypeLabelRegexMismatch             = "LabelRegexMismatch"
 	ViolationTypeEnvRequired                    = "EnvRequired"
 	ViolationTypeEnvRegexMismatch               = "EnvRegexMismatch"
+	ViolationTypeEnvNotAllowed                  = "EnvNotAllowed"
+	ViolationTypeAcceleratorsNotAllowed         = "AcceleratorsNotAllowed"
+	ViolationTypeAcceleratorsExceeded           = "AcceleratorsExceeded"
+	ViolationTypeImagePolicyViolation           = "ImagePolicyViolation"
+	ViolationTypeIsolationLevelWeakened         = "IsolationLevelWeakened"
+	ViolationTypeSchedulingNotAllowed           = "SchedulingNotAllowed"
+	ViolationTypePriorityClassNotAllowed        = "PriorityClassNotAllowed"
+	ViolationTypeUnknownServerType              = "UnknownServerType"
+	ViolationTypeServiceAccountNotAllowed       = "ServiceAccountNotAllowed"
+	ViolationTypeSharedVolumeRemounted          = "SharedVolumeRemounted"
 )
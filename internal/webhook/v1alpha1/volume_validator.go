@@ -9,11 +9,15 @@ import (
 	"context"
 	"fmt"
 
+	"slices"
+
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
 )
 
 // VolumeValidator handles volume validation for webhooks
@@ -36,6 +40,72 @@ func (vv *VolumeValidator) ValidateVolumeOwnership(ctx context.Context, workspac
 	return nil
 }
 
+// ValidateExtraVolumes checks that the extra volumes do not collide with the home directory or the other
+// mounts of the workspace, and that the PVCs they reference exist in the workspace namespace. On update
+// (oldWorkspace not nil) only added PVC references are checked, so that deleting a PVC does not block
+// updates of the workspaces mounting it, e.g. stopping them.
+func (vv *VolumeValidator) ValidateExtraVolumes(
+	ctx context.Context, oldWorkspace, workspace *workspacev1alpha1.Workspace) error {
+	for _, vol := range workspace.Spec.ExtraVolumes {
+		if conflict := controller.ExtraVolumeConflict(workspace, vol); conflict != "" {
+			return fmt.Errorf("extra volume '%s' cannot be mounted: %s", vol.Name, conflict)
+		}
+		if vol.SourceType() != workspacev1alpha1.VolumeSourcePersistentVolumeClaim ||
+			(oldWorkspace != nil && mountsExtraPVC(oldWorkspace, vol.PersistentVolumeClaimName)) {
+			continue
+		}
+		pvc := &corev1.PersistentVolumeClaim{}
+		err := vv.client.Get(ctx, types.NamespacedName{Name: vol.PersistentVolumeClaimName, Namespace: workspace.Namespace}, pvc)
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("extra volume '%s' references PVC '%s' which does not exist in namespace '%s'",
+				vol.Name, vol.PersistentVolumeClaimName, workspace.Namespace)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get PVC '%s' of extra volume '%s': %w", vol.PersistentVolumeClaimName, vol.Name, err)
+		}
+	}
+	return nil
+}
+
+// mountsExtraPVC returns true if the workspace mounts the PVC as an extra volume
+func mountsExtraPVC(workspace *workspacev1alpha1.Workspace, claimName string) bool {
+	return slices.ContainsFunc(workspace.Spec.ExtraVolumes, func(vol workspacev1alpha1.ExtraVolumeSpec) bool {
+		return vol.SourceType() == workspacev1alpha1.VolumeSourcePersistentVolumeClaim && vol.PersistentVolumeClaimName == claimName
+	})
+}
+
+// validateVolumeSources checks the source types of the extra volumes against the template's AllowedVolumeSources.
+// Templates disallowing secondary storages disallow PVC extra volumes as well.
+func validateVolumeSources(workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) []TemplateViolation {
+	allowed := template.Spec.AllowedVolumeSources
+	noSecondaryStorages := template.Spec.AllowSecondaryStorages != nil && !*template.Spec.AllowSecondaryStorages
+
+	var violations []TemplateViolation
+	for _, vol := range workspace.Spec.ExtraVolumes {
+		sourceType := vol.SourceType()
+		if (len(allowed) == 0 || slices.Contains(allowed, sourceType)) &&
+			!(noSecondaryStorages && sourceType == workspacev1alpha1.VolumeSourcePersistentVolumeClaim) {
+			continue
+		}
+		allowedDescription := fmt.Sprint(allowed)
+		if len(allowed) == 0 {
+			allowedDescription = "[ConfigMap Secret]"
+		} else if noSecondaryStorages {
+			allowedDescription = fmt.Sprint(slices.DeleteFunc(slices.Clone(allowed), func(t workspacev1alpha1.VolumeSourceType) bool {
+				return t == workspacev1alpha1.VolumeSourcePersistentVolumeClaim
+			}))
+		}
+		violations = append(violations, TemplateViolation{
+			Type:    ViolationTypeVolumeSourceNotAllowed,
+			Field:   fmt.Sprintf("spec.extraVolumes[%s]", vol.Name),
+			Message: fmt.Sprintf("Template '%s' does not allow %s extra volumes", template.Name, sourceType),
+			Allowed: allowedDescription,
+			Actual:  string(sourceType),
+		})
+	}
+	return violations
+}
+
 // validateSecondaryStorages checks if secondary storage volumes are allowed by template
 func validateSecondaryStorages(volumes []workspacev1alpha1.VolumeSpec, template *workspacev1alpha1.WorkspaceTemplate) *TemplateViolation {
 	// Skip validation if no volumes specified
@@ -60,33 +130,52 @@ func validateSecondaryStorages(volumes []workspacev1alpha1.VolumeSpec, template
 // validateVolumeOwnership checks that volumes don't reference PVCs owned by other workspaces
 func validateVolumeOwnership(ctx context.Context, k8sClient client.Client, workspace *workspacev1alpha1.Workspace) *TemplateViolation {
 	for _, volume := range workspace.Spec.Volumes {
-		// Get the PVC
-		pvc := &corev1.PersistentVolumeClaim{}
-		err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      volume.PersistentVolumeClaimName,
-			Namespace: workspace.Namespace,
-		}, pvc)
-
-		// If PVC doesn't exist, skip validation (let other validation handle it)
-		if err != nil {
+		field := fmt.Sprintf("spec.volumes[%s].persistentVolumeClaimName", volume.Name)
+		if violation := validatePVCOwnership(ctx, k8sClient, workspace, field, volume.Name, volume.PersistentVolumeClaimName); violation != nil {
+			return violation
+		}
+	}
+	for _, volume := range workspace.Spec.ExtraVolumes {
+		if volume.SourceType() != workspacev1alpha1.VolumeSourcePersistentVolumeClaim {
 			continue
 		}
+		field := fmt.Sprintf("spec.extraVolumes[%s].persistentVolumeClaimName", volume.Name)
+		if violation := validatePVCOwnership(ctx, k8sClient, workspace, field, volume.Name, volume.PersistentVolumeClaimName); violation != nil {
+			return violation
+		}
+	}
+
+	return nil
+}
+
+// validatePVCOwnership checks that the PVC a volume references is not owned by another workspace
+func validatePVCOwnership(ctx context.Context, k8sClient client.Client, workspace *workspacev1alpha1.Workspace,
+	field, volumeName, claimName string) *TemplateViolation {
+	// Get the PVC
+	pvc := &corev1.PersistentVolumeClaim{}
+	err := k8sClient.Get(ctx, types.NamespacedName{
+		Name:      claimName,
+		Namespace: workspace.Namespace,
+	}, pvc)
 
-		// Check if PVC is owned by another workspace
-		for _, ownerRef := range pvc.OwnerReferences {
-			if ownerRef.APIVersion == "workspace.jupyter.org/v1alpha1" &&
-				ownerRef.Kind == "Workspace" &&
-				ownerRef.UID != workspace.UID {
-				return &TemplateViolation{
-					Type:    ViolationTypeVolumeOwnedByAnotherWorkspace,
-					Field:   fmt.Sprintf("spec.volumes[%s].persistentVolumeClaimName", volume.Name),
-					Message: fmt.Sprintf("Volume '%s' references PVC '%s' which is owned by another workspace '%s'", volume.Name, volume.PersistentVolumeClaimName, ownerRef.Name),
-					Allowed: "PVCs not owned by other workspaces",
-					Actual:  fmt.Sprintf("PVC owned by workspace '%s'", ownerRef.Name),
-				}
+	// If PVC doesn't exist, skip validation (let other validation handle it)
+	if err != nil {
+		return nil
+	}
+
+	// Check if PVC is owned by another workspace
+	for _, ownerRef := range pvc.OwnerReferences {
+		if ownerRef.APIVersion == "workspace.jupyter.org/v1alpha1" &&
+			ownerRef.Kind == "Workspace" &&
+			ownerRef.UID != workspace.UID {
+			return &TemplateViolation{
+				Type:    ViolationTypeVolumeOwnedByAnotherWorkspace,
+				Field:   field,
+				Message: fmt.Sprintf("Volume '%s' references PVC '%s' which is owned by another workspace '%s'", volumeName, claimName, ownerRef.Name),
+				Allowed: "PVCs not owned by other workspaces",
+				Actual:  fmt.Sprintf("PVC owned by workspace '%s'", ownerRef.Name),
 			}
 		}
 	}
-
 	return nil
 }
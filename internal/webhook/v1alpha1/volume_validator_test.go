@@ -0,0 +1,142 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+var _ = Describe("VolumeValidator", func() {
+	var (
+		ctx       context.Context
+		validator *VolumeValidator
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	datasets := workspacev1alpha1.ExtraVolumeSpec{
+		Name: "datasets", MountPath: "/datasets", ReadOnly: true, PersistentVolumeClaimName: "shared-datasets",
+	}
+	startup := workspacev1alpha1.ExtraVolumeSpec{Name: "startup", MountPath: "/opt/startup", ConfigMapName: "startup-script"}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "shared-datasets", Namespace: "team-a"}}
+		validator = NewVolumeValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(pvc).Build())
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a", UID: types.UID("ws-uid")},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("1Gi"), MountPath: "/home/jovyan"},
+			},
+		}
+	})
+
+	Context("extra volumes", func() {
+		It("should allow existing PVCs, ConfigMaps and Secrets mounted beside the home directory", func() {
+			workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{
+				datasets,
+				startup,
+				{Name: "scratch", MountPath: "/home/jovyan/scratch", SecretName: "credentials"},
+			}
+			Expect(validator.ValidateExtraVolumes(ctx, nil, workspace)).To(Succeed())
+		})
+
+		It("should reject a PVC missing from the workspace namespace", func() {
+			workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{{
+				Name: "datasets", MountPath: "/datasets", PersistentVolumeClaimName: "missing",
+			}}
+			err := validator.ValidateExtraVolumes(ctx, nil, workspace)
+			Expect(err).To(MatchError(ContainSubstring("PVC 'missing' which does not exist in namespace 'team-a'")))
+
+			// A PVC deleted after it was mounted does not block updates
+			oldWorkspace := workspace.DeepCopy()
+			workspace.Spec.DesiredStatus = "Stopped"
+			Expect(validator.ValidateExtraVolumes(ctx, oldWorkspace, workspace)).To(Succeed())
+		})
+
+		DescribeTable("should reject mounts colliding with the mounts of the workspace",
+			func(vol workspacev1alpha1.ExtraVolumeSpec, message string) {
+				workspace.Spec.TmpVolume = &workspacev1alpha1.TmpVolumeSpec{}
+				workspace.Spec.Volumes = []workspacev1alpha1.VolumeSpec{
+					{Name: "data", PersistentVolumeClaimName: "shared-datasets", MountPath: "/data"},
+				}
+				workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{vol}
+				Expect(validator.ValidateExtraVolumes(ctx, nil, workspace)).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("the home directory", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "home", MountPath: "/home/jovyan/", ConfigMapName: "c"}, "would hide the home directory"),
+			Entry("a parent of the home directory", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "home", MountPath: "/home", ConfigMapName: "c"}, "would hide the home directory"),
+			Entry("the tmp volume", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "scratch", MountPath: "/tmp", ConfigMapName: "c"}, "would hide the tmp volume"),
+			Entry("a secondary storage", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "other", MountPath: "/data", ConfigMapName: "c"}, "would hide volume 'data'"),
+			Entry("a reserved name", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "workspace-storage", MountPath: "/x", ConfigMapName: "c"}, "is reserved"),
+			Entry("the name of a secondary storage", workspacev1alpha1.ExtraVolumeSpec{
+				Name: "data", MountPath: "/x", ConfigMapName: "c"}, "is used by spec.volumes"),
+		)
+
+		It("should reject a PVC owned by another workspace", func() {
+			owned := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+				Name: "other-home", Namespace: "team-a",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "workspace.jupyter.org/v1alpha1", Kind: "Workspace", Name: "other", UID: "other-uid",
+				}},
+			}}
+			scheme := runtime.NewScheme()
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			validator = NewVolumeValidator(fake.NewClientBuilder().WithScheme(scheme).WithObjects(owned).Build())
+			workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{{
+				Name: "stolen", MountPath: "/stolen", PersistentVolumeClaimName: "other-home",
+			}}
+
+			err := validator.ValidateVolumeOwnership(ctx, workspace)
+			Expect(err).To(MatchError(ContainSubstring("owned by another workspace 'other'")))
+		})
+	})
+
+	Context("validateVolumeSources", func() {
+		var template *workspacev1alpha1.WorkspaceTemplate
+
+		BeforeEach(func() {
+			template = &workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "restricted"}}
+			workspace.Spec.ExtraVolumes = []workspacev1alpha1.ExtraVolumeSpec{datasets, startup}
+		})
+
+		It("should allow every source type when the template does not restrict them", func() {
+			Expect(validateVolumeSources(workspace, template)).To(BeEmpty())
+		})
+
+		It("should reject source types the template does not allow", func() {
+			template.Spec.AllowedVolumeSources = []workspacev1alpha1.VolumeSourceType{workspacev1alpha1.VolumeSourceConfigMap}
+			violations := validateVolumeSources(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Type).To(Equal(ViolationTypeVolumeSourceNotAllowed))
+			Expect(violations[0].Field).To(Equal("spec.extraVolumes[datasets]"))
+		})
+
+		It("should reject PVC extra volumes when the template disallows secondary storages", func() {
+			allowSecondaryStorages := false
+			template.Spec.AllowSecondaryStorages = &allowSecondaryStorages
+			violations := validateVolumeSources(workspace, template)
+			Expect(violations).To(HaveLen(1))
+			Expect(violations[0].Actual).To(Equal(string(workspacev1alpha1.VolumeSourcePersistentVolumeClaim)))
+		})
+	})
+})
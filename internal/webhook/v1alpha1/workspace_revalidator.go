@@ -0,0 +1,67 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+// WorkspaceRevalidator runs the creation checks of the validating webhook that apply to all users against
+// stored workspaces, for the controller to park the workspaces of the namespaces the webhooks do not cover yet.
+// The checks depending on the requesting user cannot run after admission and are skipped.
+type WorkspaceRevalidator struct {
+	templateValidator       *TemplateValidator
+	accessStrategyValidator *AccessStrategyValidator
+	volumeValidator         *VolumeValidator
+	sidecarValidator        *SidecarValidator
+}
+
+var _ controller.WorkspaceRevalidator = &WorkspaceRevalidator{}
+
+// NewWorkspaceRevalidator creates a new WorkspaceRevalidator
+func NewWorkspaceRevalidator(k8sClient client.Client, defaultTemplateNamespace string) *WorkspaceRevalidator {
+	return &WorkspaceRevalidator{
+		templateValidator:       NewTemplateValidator(k8sClient, defaultTemplateNamespace),
+		accessStrategyValidator: NewAccessStrategyValidator(defaultTemplateNamespace),
+		volumeValidator:         NewVolumeValidator(k8sClient),
+		sidecarValidator:        NewSidecarValidator(k8sClient),
+	}
+}
+
+// Revalidate implements controller.WorkspaceRevalidator. It returns the first violation of the workspace.
+func (r *WorkspaceRevalidator) Revalidate(ctx context.Context, workspace *workspacev1alpha1.Workspace) (string, error) {
+	checks := []func() error{
+		func() error { return r.templateValidator.ValidateCreateWorkspace(ctx, workspace) },
+		func() error { return r.accessStrategyValidator.ValidateCreateWorkspace(workspace) },
+		func() error { return r.sidecarValidator.ValidateCreateWorkspace(ctx, workspace) },
+		func() error { return r.volumeValidator.ValidateVolumeOwnership(ctx, workspace) },
+		func() error { return r.templateValidator.ValidateEnvFromNamespaces(ctx, nil, workspace) },
+		func() error { return validateOnBehalfOf(workspace) },
+	}
+	for _, check := range checks {
+		err := check()
+		if err == nil {
+			continue
+		}
+		var status apierrors.APIStatus
+		if errors.As(err, &status) {
+			// A missing template is reported by the controller; other API errors mean the check could not run
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		return err.Error(), nil
+	}
+	return "", nil
+}
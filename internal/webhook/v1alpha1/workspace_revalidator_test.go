@@ -0,0 +1,107 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+var _ = Describe("WorkspaceRevalidator", func() {
+	var (
+		ctx       context.Context
+		scheme    *runtime.Scheme
+		template  *workspacev1alpha1.WorkspaceTemplate
+		workspace *workspacev1alpha1.Workspace
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(workspacev1alpha1.AddToScheme(scheme)).To(Succeed())
+		template = &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:   "Base",
+				DefaultImage:  "jupyter/base-notebook:latest",
+				AllowedImages: []string{"jupyter/base-notebook:latest"},
+			},
+		}
+		workspace = &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				Image:       "jupyter/base-notebook:latest",
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: "base"},
+			},
+		}
+	})
+
+	newRevalidator := func(funcs interceptor.Funcs) *WorkspaceRevalidator {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).WithInterceptorFuncs(funcs).Build()
+		return NewWorkspaceRevalidator(k8sClient, "")
+	}
+
+	It("should pass a workspace complying with its template", func() {
+		violation, err := newRevalidator(interceptor.Funcs{}).Revalidate(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violation).To(BeEmpty())
+	})
+
+	It("should report the template constraint a workspace violates", func() {
+		workspace.Spec.Image = "evil/miner:latest"
+		violation, err := newRevalidator(interceptor.Funcs{}).Revalidate(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violation).To(ContainSubstring("violates template 'base' constraints"))
+	})
+
+	It("should report an impersonation the defaulter did not accept", func() {
+		workspace.Annotations = map[string]string{controller.AnnotationOnBehalfOf: "alice"}
+		violation, err := newRevalidator(interceptor.Funcs{}).Revalidate(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violation).To(ContainSubstring(controller.AnnotationOnBehalfOf))
+	})
+
+	It("should leave a missing template to the controller", func() {
+		workspace.Spec.TemplateRef.Name = "gone"
+		violation, err := newRevalidator(interceptor.Funcs{}).Revalidate(ctx, workspace)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(violation).To(BeEmpty())
+	})
+
+	It("should return an error when the checks cannot run", func() {
+		revalidator := newRevalidator(interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return apierrors.NewServiceUnavailable("etcd is down")
+			},
+		})
+		_, err := revalidator.Revalidate(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(apierrors.IsServiceUnavailable(err)).To(BeTrue())
+	})
+
+	It("should not report forbidden lookups as violations", func() {
+		revalidator := newRevalidator(interceptor.Funcs{
+			Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+				return apierrors.NewForbidden(schema.GroupResource{Resource: "workspacetemplates"}, "base", nil)
+			},
+		})
+		violation, err := revalidator.Revalidate(ctx, workspace)
+		Expect(err).To(HaveOccurred())
+		Expect(violation).To(BeEmpty())
+	})
+})
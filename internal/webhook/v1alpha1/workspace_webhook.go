@@ -7,8 +7,10 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,9 +40,9 @@ func ensureTemplateFinalizer(ctx context.Context, k8sClient client.Client, templ
 		return nil
 	}
 
-	// Check if at least 1 active workspace uses this template (limit=1 for efficiency)
-	// ListActiveWorkspacesByTemplate filters out workspaces with DeletionTimestamp != nil
-	workspaces, _, err := workspaceutil.ListActiveWorkspacesByTemplate(ctx, k8sClient, templateName, templateNamespace, "", 1)
+	// Check if at least 1 active workspace uses this template. A limited page could hold only workspaces being
+	// deleted, which are filtered out, so every page is read
+	inUse, err := workspaceutil.HasActiveWorkspacesWithTemplate(ctx, k8sClient, templateName, templateNamespace)
 	if err != nil {
 		workspacelog.Error(err, "Failed to check workspace usage", "template", templateName, "templateNamespace", templateNamespace)
 		return fmt.Errorf("failed to check workspace usage for template %s/%s: %w", templateNamespace, templateName, err)
@@ -48,7 +50,7 @@ func ensureTemplateFinalizer(ctx context.Context, k8sClient client.Client, templ
 
 	// If no active workspaces use the template, don't add finalizer
 	// This implements lazy finalizer pattern - controller will add it when needed
-	if len(workspaces) == 0 {
+	if !inUse {
 		workspacelog.V(1).Info("No active workspaces use template, skipping finalizer", "template", templateName, "templateNamespace", templateNamespace)
 		return nil
 	}
@@ -66,9 +68,10 @@ func ensureTemplateFinalizer(ctx context.Context, k8sClient client.Client, templ
 		return nil
 	}
 
-	// Add finalizer since active workspace(s) use this template
+	// Add finalizer since active workspace(s) use this template, leaving the rest of the template alone
+	original := template.DeepCopy()
 	controllerutil.AddFinalizer(template, workspaceutil.TemplateFinalizerName)
-	if err := k8sClient.Update(ctx, template); err != nil {
+	if err := workspaceutil.PatchChanges(ctx, k8sClient, template, original); err != nil {
 		workspacelog.Error(err, "Failed to add finalizer to template", "template", templateName, "templateNamespace", templateNamespace)
 		return fmt.Errorf("failed to add finalizer to template %s/%s: %w", templateNamespace, templateName, err)
 	}
@@ -157,14 +160,8 @@ func isControllerOrAdminUser(ctx context.Context) bool {
 	}
 
 	// Check if user is controller
-	controllerServiceAccount := os.Getenv(controller.ControllerPodServiceAccountEnv)
-	controllerNamespace := os.Getenv(controller.ControllerPodNamespaceEnv)
-	if controllerServiceAccount != "" && controllerNamespace != "" {
-		// Build the full service account name: system:serviceaccount:namespace:name
-		fullControllerSA := fmt.Sprintf("system:serviceaccount:%s:%s", controllerNamespace, controllerServiceAccount)
-		if req.UserInfo.Username == fullControllerSA {
-			return true
-		}
+	if isControllerServiceAccount(req.UserInfo.Username) {
+		return true
 	}
 
 	// Check if user is admin
@@ -183,6 +180,56 @@ func isControllerOrAdminUser(ctx context.Context) bool {
 	return false
 }
 
+// isControllerServiceAccount checks if the username is the controller service account
+func isControllerServiceAccount(username string) bool {
+	controllerServiceAccount := os.Getenv(controller.ControllerPodServiceAccountEnv)
+	controllerNamespace := os.Getenv(controller.ControllerPodNamespaceEnv)
+	if controllerServiceAccount == "" || controllerNamespace == "" {
+		return false
+	}
+	// Build the full service account name: system:serviceaccount:namespace:name
+	fullControllerSA := fmt.Sprintf("system:serviceaccount:%s:%s", controllerNamespace, controllerServiceAccount)
+	return username == fullControllerSA
+}
+
+// stampDesiredStatusSetAt records when a user manually sets spec.desiredStatus, so that the controller
+// can give the manual change precedence over schedule and culler intents during the cooldown.
+// Writes by the controller are never stamped, and a user-supplied value is replaced by the previous one.
+func stampDesiredStatusSetAt(req admission.Request, workspace *workspacev1alpha1.Workspace, now time.Time) {
+	if isControllerServiceAccount(req.UserInfo.Username) {
+		return
+	}
+
+	stamp := func() {
+		workspace.Annotations[controller.AnnotationDesiredStatusSetAt] = now.UTC().Format(time.RFC3339)
+	}
+
+	if req.Operation != "UPDATE" {
+		if workspace.Spec.DesiredStatus != "" {
+			stamp()
+		} else {
+			delete(workspace.Annotations, controller.AnnotationDesiredStatusSetAt)
+		}
+		return
+	}
+
+	oldWorkspace := &workspacev1alpha1.Workspace{}
+	if err := json.Unmarshal(req.OldObject.Raw, oldWorkspace); err != nil {
+		workspacelog.Error(err, "Failed to decode old workspace, skipping desired status stamp", "workspace", workspace.GetName())
+		return
+	}
+
+	if oldWorkspace.Spec.DesiredStatus != workspace.Spec.DesiredStatus {
+		stamp()
+		return
+	}
+	if previous, ok := oldWorkspace.Annotations[controller.AnnotationDesiredStatusSetAt]; ok {
+		workspace.Annotations[controller.AnnotationDesiredStatusSetAt] = previous
+	} else {
+		delete(workspace.Annotations, controller.AnnotationDesiredStatusSetAt)
+	}
+}
+
 // validateOwnershipPermission checks if the user has permission to modify/delete an OwnerOnly workspace
 func validateOwnershipPermission(ctx context.Context, workspace *workspacev1alpha1.Workspace) error {
 	req, err := admission.RequestFromContext(ctx)
@@ -206,10 +253,27 @@ func validateOwnershipPermission(ctx context.Context, workspace *workspacev1alph
 	return fmt.Errorf("access denied: only workspace owner can modify OwnerOnly workspaces")
 }
 
+// validateOwnershipUpdate checks the user may update an OwnerOnly workspace, or make a workspace OwnerOnly
+func validateOwnershipUpdate(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+	originalOwnershipType := getEffectiveOwnershipType(oldWorkspace.Spec.OwnershipType)
+	newOwnershipType := getEffectiveOwnershipType(newWorkspace.Spec.OwnershipType)
+	workspacelog.Info("Ownership validation check", "originalType", originalOwnershipType, "newType", newOwnershipType)
+	if originalOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
+		// Existing OwnerOnly workspace - check against old workspace, whose Editor collaborators may update it too
+		return validateEditPermission(ctx, oldWorkspace, newWorkspace)
+	}
+	if newOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
+		// Changing to OwnerOnly - only allow if user is the original creator
+		return validateOwnershipPermission(ctx, oldWorkspace)
+	}
+	return nil
+}
+
 // SetupWorkspaceWebhookWithManager registers the webhook for Workspace in the manager.
 // RBAC Note: This webhook requires WorkspaceTemplate access (get, update, finalizers/update)
 // which is provided by the workspacetemplate controller RBAC markers.
-func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace string) error {
+// Workspaces outside scope are admitted unchanged, for the operator instance managing them.
+func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace string, scope *workspaceutil.Scope) error {
 	templateValidator := NewTemplateValidator(mgr.GetClient(), defaultTemplateNamespace)
 	accessStrategyValidator := NewAccessStrategyValidator(defaultTemplateNamespace)
 	templateDefaulter := NewTemplateDefaulter(mgr.GetClient(), defaultTemplateNamespace)
@@ -217,6 +281,23 @@ func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace
 	serviceAccountValidator := NewServiceAccountValidator(mgr.GetClient())
 	serviceAccountDefaulter := NewServiceAccountDefaulter(mgr.GetClient())
 	volumeValidator := NewVolumeValidator(mgr.GetClient())
+	sidecarValidator := NewSidecarValidator(mgr.GetClient())
+	deletionValidator := NewDeletionProtectionValidator(mgr.GetClient())
+	priorityClassValidator := NewPriorityClassValidator(mgr.GetClient())
+	hibernationValidator := NewHibernationValidator(mgr.GetRESTMapper())
+
+	// Quantity fields are normalized before the defaulter decodes the workspace
+	defaulter := admission.WithCustomDefaulter(mgr.GetScheme(), &workspacev1alpha1.Workspace{}, &WorkspaceCustomDefaulter{
+		templateDefaulter:       templateDefaulter,
+		serviceAccountDefaulter: serviceAccountDefaulter,
+		templateGetter:          templateGetter,
+		client:                  mgr.GetClient(),
+		scope:                   scope,
+	})
+	defaulter.Handler = &quantityNormalizingHandler{next: defaulter.Handler}
+	mgr.GetWebhookServer().Register(workspaceMutatingWebhookPath, defaulter)
+	mgr.GetWebhookServer().Register(workspaceStatusWebhookPath,
+		&admission.Webhook{Handler: &WorkspaceStatusValidator{scope: scope}})
 
 	return ctrl.NewWebhookManagedBy(mgr).For(&workspacev1alpha1.Workspace{}).
 		WithValidator(&WorkspaceCustomValidator{
@@ -224,17 +305,19 @@ func SetupWorkspaceWebhookWithManager(mgr ctrl.Manager, defaultTemplateNamespace
 			accessStrategyValidator: accessStrategyValidator,
 			serviceAccountValidator: serviceAccountValidator,
 			volumeValidator:         volumeValidator,
-		}).
-		WithDefaulter(&WorkspaceCustomDefaulter{
-			templateDefaulter:       templateDefaulter,
-			serviceAccountDefaulter: serviceAccountDefaulter,
-			templateGetter:          templateGetter,
-			client:                  mgr.GetClient(),
+			sidecarValidator:        sidecarValidator,
+			deletionValidator:       deletionValidator,
+			hibernationValidator:    hibernationValidator,
+			priorityClassValidator:  priorityClassValidator,
+			scope:                   scope,
 		}).
 		Complete()
 }
 
-// +kubebuilder:webhook:path=/mutate-workspace-jupyter-org-v1alpha1-workspace,mutating=true,failurePolicy=fail,sideEffects=None,groups=workspace.jupyter.org,resources=workspaces,verbs=create;update,versions=v1alpha1,name=mworkspace-v1alpha1.kb.io,admissionReviewVersions=v1,serviceName=jupyter-k8s-controller-manager,servicePort=9443
+// workspaceMutatingWebhookPath is the path of the mutating webhook for Workspace
+const workspaceMutatingWebhookPath = "/mutate-workspace-jupyter-org-v1alpha1-workspace"
+
+// +kubebuilder:webhook:path=/mutate-workspace-jupyter-org-v1alpha1-workspace,mutating=true,failurePolicy=fail,sideEffects=None,groups=workspace.jupyter.org,resources=workspaces,verbs=create;update,versions=v1alpha1,name=mworkspace-v1alpha1.kb.io,admissionReviewVersions=v1,reinvocationPolicy=IfNeeded,serviceName=jupyter-k8s-controller-manager,servicePort=9443
 
 // WorkspaceCustomDefaulter struct is responsible for setting default values on the custom resource of the
 // Kind Workspace when those are created or updated.
@@ -246,12 +329,15 @@ type WorkspaceCustomDefaulter struct {
 	serviceAccountDefaulter *ServiceAccountDefaulter
 	templateGetter          *TemplateGetter
 	client                  client.Client
+	scope                   *workspaceutil.Scope
 }
 
 var _ webhook.CustomDefaulter = &WorkspaceCustomDefaulter{}
 
 // Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind Workspace.
-func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, obj runtime.Object) (err error) {
+	defer func(start time.Time) { observeAdmission(admissionOperationDefault, start, err) }(time.Now())
+
 	workspace, ok := obj.(*workspacev1alpha1.Workspace)
 
 	if !ok {
@@ -259,6 +345,12 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, obj runtime.Obje
 	}
 	workspacelog.Info("Defaulting for Workspace", "name", workspace.GetName(), "namespace", workspace.GetNamespace())
 
+	// Workspaces of other operator instances are defaulted by their own webhook
+	if !d.scope.Matches(workspace) {
+		workspacelog.Info("Skipping defaulting for workspace outside the operator scope", "name", workspace.GetName())
+		return nil
+	}
+
 	// Skip template defaulting if workspace is being deleted
 	// During deletion, only finalizer removal happens and we don't need to apply defaults
 	// This prevents webhook failures when template is already deleted
@@ -272,33 +364,83 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, obj runtime.Obje
 		workspace.Annotations = make(map[string]string)
 	}
 
-	// Extract user info from request context
-	if req, err := admission.RequestFromContext(ctx); err == nil {
+	// Extract user info from request context; defaults that depend on the request are skipped without one
+	req, reqErr := admission.RequestFromContext(ctx)
+	hasRequest := reqErr == nil
+	if hasRequest {
+		// Status is only written through the status subresource, the template generation by this webhook
+		discardClientStatus(req, workspace)
+		discardClientTemplateGeneration(req, workspace)
+
 		sanitizedUsername := stringutil.SanitizeUsername(req.UserInfo.Username)
 
-		// Always set created-by on CREATE operations
-		if req.Operation == "CREATE" {
-			workspace.Annotations[controller.AnnotationCreatedBy] = sanitizedUsername
-			workspacelog.Info("Added created-by annotation", "workspace", workspace.GetName(), "user", sanitizedUsername, "namespace", workspace.GetNamespace())
-		}
+		// Always set created-by on CREATE operations, honoring trusted creators acting on behalf of a user
+		applyCreatorIdentity(req, workspace)
 
 		// Always set last-updated-by (CREATE and UPDATE operations)
 		workspace.Annotations[controller.AnnotationLastUpdatedBy] = sanitizedUsername
 		workspacelog.Info("Added last-updated-by annotation", "workspace", workspace.GetName(), "user", sanitizedUsername, "namespace", workspace.GetNamespace())
+
+		// Record manual desiredStatus changes (CREATE and UPDATE operations)
+		stampDesiredStatusSetAt(req, workspace, time.Now())
 	}
 
+	// Start workspaces by default; after the stamp so the default does not count as a manual change
+	applyDesiredStatusDefault(workspace)
+
 	// Apply template getter
 	if err := d.templateGetter.ApplyTemplateName(ctx, workspace); err != nil {
 		workspacelog.Error(err, "Failed to apply template reference", "workspace", workspace.GetName())
 		return fmt.Errorf("failed to apply template reference: %w", err)
 	}
 
-	// Apply template defaults
-	if err := d.templateDefaulter.ApplyTemplateDefaults(ctx, workspace); err != nil {
+	// Let the template defaults set the image of the server type a workspace switches to
+	if hasRequest {
+		resetImageOnServerTypeChange(req, workspace)
+	}
+
+	// Apply template defaults, except to a workspace resuming from a pause or a stop
+	requestedStorageClassName := storageClassNameOf(workspace)
+	requestedSubPath := storageSubPathOf(workspace)
+	requestedStorageMode := storageModeSetBy(workspace)
+	if hasRequest && isResuming(req, workspace) {
+		workspacelog.Info("Skipping template defaults for resuming workspace", "workspace", workspace.GetName())
+	} else if err := d.templateDefaulter.ApplyTemplateDefaults(ctx, workspace); err != nil {
 		workspacelog.Error(err, "Failed to apply template defaults", "workspace", workspace.GetName())
 		return fmt.Errorf("failed to apply template defaults: %w", err)
 	}
 
+	// Keep the storage class of the existing storage of a workspace the template now defaults
+	if hasRequest && requestedStorageClassName == "" {
+		keepStorageClassOnUpdate(req, workspace)
+	}
+
+	// Keep the subPath of the existing storage of a workspace the template now defaults
+	if hasRequest && requestedSubPath == "" {
+		keepSubPathOnUpdate(req, workspace)
+	}
+
+	// Keep the storage mode of the existing storage of a workspace the template now defaults
+	if hasRequest && requestedStorageMode == "" {
+		keepStorageModeOnUpdate(req, workspace)
+	}
+
+	// Reserve ephemeral-storage for new workspaces; after the template resource defaults
+	if hasRequest && req.Operation == "CREATE" {
+		if err := d.templateDefaulter.ApplyEphemeralStorageDefault(ctx, workspace); err != nil {
+			workspacelog.Error(err, "Failed to apply ephemeral-storage default", "workspace", workspace.GetName())
+			return fmt.Errorf("failed to apply ephemeral-storage default: %w", err)
+		}
+	}
+
+	// Record the start approval requirement and approver; after the desired status default
+	if hasRequest {
+		if err := d.templateDefaulter.ApplyStartApproval(ctx, req, workspace); err != nil {
+			workspacelog.Error(err, "Failed to apply start approval", "workspace", workspace.GetName())
+			return fmt.Errorf("failed to apply start approval: %w", err)
+		}
+	}
+
 	// Apply service account defaults
 	if err := d.serviceAccountDefaulter.ApplyServiceAccountDefaults(ctx, workspace); err != nil {
 		workspacelog.Error(err, "Failed to apply service account defaults", "workspace", workspace.GetName())
@@ -306,7 +448,7 @@ func (d *WorkspaceCustomDefaulter) Default(ctx context.Context, obj runtime.Obje
 	}
 
 	// Set workspace defaults for OwnershipType and AccessType
-	setWorkspaceSharingDefaults(workspace)
+	SetWorkspaceSharingDefaults(workspace)
 
 	// Ensure template has finalizer to prevent deletion while in use
 	if workspace.Spec.TemplateRef != nil && workspace.Spec.TemplateRef.Name != "" {
@@ -340,30 +482,191 @@ type WorkspaceCustomValidator struct {
 	accessStrategyValidator *AccessStrategyValidator
 	serviceAccountValidator *ServiceAccountValidator
 	volumeValidator         *VolumeValidator
+	sidecarValidator        *SidecarValidator
+	deletionValidator       *DeletionProtectionValidator
+	hibernationValidator    *HibernationValidator
+	priorityClassValidator  *PriorityClassValidator
+	scope                   *workspaceutil.Scope
 }
 
 var _ webhook.CustomValidator = &WorkspaceCustomValidator{}
 
+// workspaceCheck validates the admission of newWorkspace, an update of oldWorkspace or a creation when
+// oldWorkspace is nil
+type workspaceCheck func(ctx context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error
+
+// transitionCheck adapts a check of the change from oldWorkspace to newWorkspace that needs no context
+func transitionCheck(check func(oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error) workspaceCheck {
+	return func(_ context.Context, oldWorkspace, newWorkspace *workspacev1alpha1.Workspace) error {
+		return check(oldWorkspace, newWorkspace)
+	}
+}
+
+// newWorkspaceCheck adapts a check of the admitted workspace alone
+func newWorkspaceCheck(check func(ctx context.Context, workspace *workspacev1alpha1.Workspace) error) workspaceCheck {
+	return func(ctx context.Context, _, newWorkspace *workspacev1alpha1.Workspace) error {
+		return check(ctx, newWorkspace)
+	}
+}
+
+// specCheck adapts a check of the admitted workspace alone that needs no context
+func specCheck(check func(workspace *workspacev1alpha1.Workspace) error) workspaceCheck {
+	return func(_ context.Context, _, newWorkspace *workspacev1alpha1.Workspace) error {
+		return check(newWorkspace)
+	}
+}
+
+// runWorkspaceChecks runs the checks in order and returns the error of the first one failing
+func runWorkspaceChecks(
+	ctx context.Context,
+	oldWorkspace, newWorkspace *workspacev1alpha1.Workspace,
+	checks []workspaceCheck) error {
+	for _, check := range checks {
+		if err := check(ctx, oldWorkspace, newWorkspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createChecksForAllUsers returns the checks of a created workspace that apply to all users, in order
+func (v *WorkspaceCustomValidator) createChecksForAllUsers() []workspaceCheck {
+	return []workspaceCheck{
+		// Template constraints
+		newWorkspaceCheck(v.templateValidator.ValidateCreateWorkspace),
+		// Access strategy namespace scope
+		specCheck(v.accessStrategyValidator.ValidateCreateWorkspace),
+		// Only optional sidecars are disabled
+		newWorkspaceCheck(v.sidecarValidator.ValidateCreateWorkspace),
+		// Volume ownership (security check)
+		newWorkspaceCheck(v.volumeValidator.ValidateVolumeOwnership),
+		// Extra volumes do not hide the home directory and reference existing PVCs
+		v.volumeValidator.ValidateExtraVolumes,
+		// The clone source exists and has a bound home volume
+		newWorkspaceCheck(v.volumeValidator.ValidateCloneSource),
+		// The cluster can keep the home volume of a hibernated workspace
+		transitionCheck(v.hibernationValidator.ValidateHibernationSupported),
+		// The PriorityClass of the workspace pod exists
+		v.priorityClassValidator.ValidatePriorityClassExists,
+		// envFrom sources of other namespaces come from the template (security check)
+		v.templateValidator.ValidateEnvFromNamespaces,
+		// Owner impersonation was accepted (security check)
+		specCheck(validateOnBehalfOf),
+		// The home directory is not mounted over a system path
+		transitionCheck(validateHomeMountPath),
+		// The schedule can be evaluated
+		specCheck(validateSchedule),
+	}
+}
+
+// createChecksForUsers returns the checks of a workspace created by a user other than the controller or an
+// admin, in order
+func (v *WorkspaceCustomValidator) createChecksForUsers() []workspaceCheck {
+	return []workspaceCheck{
+		// No user-submitted reserved prefix labels/annotations
+		specCheck(validateReservedPrefixOnCreate),
+		// The pod labels and annotations do not spoof the controller-managed ones
+		specCheck(validateWorkspacePodMetadata),
+		// The paused annotation is only set by the pause reconciliation group
+		validatePausedAnnotation,
+		// The start approval requirement is the one of the template
+		v.templateValidator.ValidateStartApprovalRequired,
+		// Start approval of templates requiring it
+		v.templateValidator.ValidateStartApproval,
+		// Service account access
+		newWorkspaceCheck(v.serviceAccountValidator.ValidateServiceAccountAccess),
+		// The data of an OwnerOnly clone source is only copied by its owner
+		newWorkspaceCheck(v.volumeValidator.ValidateCloneSourceAccess),
+	}
+}
+
+// updateChecksForAllUsers returns the checks of a workspace update that apply to all users, in order
+func (v *WorkspaceCustomValidator) updateChecksForAllUsers() []workspaceCheck {
+	return []workspaceCheck{
+		// Owner impersonation is not attempted after creation (security check)
+		specCheck(validateOnBehalfOf),
+		// The desiredStatus change is a legal transition
+		transitionCheck(validateDesiredStatusTransition),
+		// The cluster can keep the home volume of a hibernated workspace
+		transitionCheck(v.hibernationValidator.ValidateHibernationSupported),
+		// The PriorityClass of the workspace pod exists
+		v.priorityClassValidator.ValidatePriorityClassExists,
+		// The storage size is not decreased, which the PVC does not allow
+		transitionCheck(validateStorageNotShrunk),
+		// The storage class is not changed, which the PVC does not allow
+		validateStorageClassUpdate,
+		// The storage subPath is not changed, which would hide the data of the workspace
+		transitionCheck(validateSubPathUnchanged),
+		// The storage mode is not switched, which would lose or strand the home directory
+		transitionCheck(validateStorageModeUnchanged),
+		// The home directory is not mounted over a system path
+		transitionCheck(validateHomeMountPath),
+		// The clone source is not changed, the home volume being cloned once
+		transitionCheck(validateCloneFromUnchanged),
+		// The schedule can be evaluated
+		specCheck(validateSchedule),
+		// Only the owner resets an OwnerOnly workspace to its template defaults
+		validateResetToDefaultsRequest,
+	}
+}
+
+// updateChecksForApprovers returns the checks of a workspace update by a user other than the controller or an
+// admin, start approvers included, in order
+func (v *WorkspaceCustomValidator) updateChecksForApprovers() []workspaceCheck {
+	return []workspaceCheck{
+		// No user modifications to reserved prefix labels/annotations
+		transitionCheck(validateReservedPrefixOnUpdate),
+		// The pod labels and annotations do not spoof the controller-managed ones
+		specCheck(validateWorkspacePodMetadata),
+		// The paused annotation is only changed by the pause reconciliation group
+		validatePausedAnnotation,
+		// The start approval requirement is the one of the template
+		v.templateValidator.ValidateStartApprovalRequired,
+		// Start approvals and transitions to Running of templates requiring approval
+		v.templateValidator.ValidateStartApproval,
+	}
+}
+
+// updateChecksForEditors returns the checks of a workspace update going beyond a start approval, in order
+func (v *WorkspaceCustomValidator) updateChecksForEditors() []workspaceCheck {
+	return []workspaceCheck{
+		// Service account access for new workspace
+		newWorkspaceCheck(v.serviceAccountValidator.ValidateServiceAccountAccess),
+		// Only the owner changes the collaborators of the workspace
+		validateCollaboratorsChange,
+		// Only the owner or its Editor collaborators update an OwnerOnly workspace
+		validateOwnershipUpdate,
+		// Template constraints for new workspace (only changed fields)
+		v.templateValidator.ValidateUpdateWorkspace,
+		// Access strategy namespace scope
+		transitionCheck(v.accessStrategyValidator.ValidateUpdateWorkspace),
+		// Only optional sidecars are disabled
+		v.sidecarValidator.ValidateUpdateWorkspace,
+		// Volume ownership (security check)
+		newWorkspaceCheck(v.volumeValidator.ValidateVolumeOwnership),
+		// Extra volumes do not hide the home directory and reference existing PVCs
+		v.volumeValidator.ValidateExtraVolumes,
+		// envFrom sources of other namespaces come from the template (security check)
+		v.templateValidator.ValidateEnvFromNamespaces,
+	}
+}
+
 // ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type Workspace.
-func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (_ admission.Warnings, err error) {
+	defer func(start time.Time) { observeAdmission(admissionOperationValidateCreate, start, err) }(time.Now())
+
 	workspace, ok := obj.(*workspacev1alpha1.Workspace)
 	if !ok {
 		return nil, fmt.Errorf("expected a Workspace object but got %T", obj)
 	}
 	workspacelog.Info("Validation for Workspace upon creation", "name", workspace.GetName(), "namespace", workspace.GetNamespace())
 
-	// Validate template constraints
-	if err := v.templateValidator.ValidateCreateWorkspace(ctx, workspace); err != nil {
-		return nil, err
-	}
-
-	// Validate access strategy namespace scope
-	if err := v.accessStrategyValidator.ValidateCreateWorkspace(workspace); err != nil {
-		return nil, err
+	// Workspaces of other operator instances are validated by their own webhook
+	if !v.scope.Matches(workspace) {
+		return nil, nil
 	}
 
-	// Validate volume ownership (security check - applies to all users)
-	if err := v.volumeValidator.ValidateVolumeOwnership(ctx, workspace); err != nil {
+	if err := runWorkspaceChecks(ctx, nil, workspace, v.createChecksForAllUsers()); err != nil {
 		return nil, err
 	}
 
@@ -372,21 +675,13 @@ func (v *WorkspaceCustomValidator) ValidateCreate(ctx context.Context, obj runti
 		return nil, nil
 	}
 
-	// Validate no user-submitted reserved prefix labels/annotations
-	if err := validateReservedPrefixOnCreate(workspace); err != nil {
-		return nil, err
-	}
-
-	// Validate service account access
-	if err := v.serviceAccountValidator.ValidateServiceAccountAccess(ctx, workspace); err != nil {
-		return nil, err
-	}
-
-	return nil, nil
+	return nil, runWorkspaceChecks(ctx, nil, workspace, v.createChecksForUsers())
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type Workspace.
-func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (_ admission.Warnings, err error) {
+	defer func(start time.Time) { observeAdmission(admissionOperationValidateUpdate, start, err) }(time.Now())
+
 	oldWorkspace, ok := oldObj.(*workspacev1alpha1.Workspace)
 	if !ok {
 		return nil, fmt.Errorf("expected a Workspace object for the oldObj but got %T", oldObj)
@@ -397,75 +692,60 @@ func (v *WorkspaceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, n
 	}
 	workspacelog.Info("Validation for Workspace upon update", "name", newWorkspace.GetName(), "namespace", newWorkspace.GetNamespace())
 
+	// Like an objectSelector, validate updates moving a workspace into or out of the operator scope
+	if !v.scope.Matches(oldWorkspace) && !v.scope.Matches(newWorkspace) {
+		return nil, nil
+	}
+
 	// Skip validation if workspace is being deleted (has deletionTimestamp)
 	// This allows finalizer removal even if template is already deleted
 	if !newWorkspace.DeletionTimestamp.IsZero() {
 		return nil, nil
 	}
 
-	// Controller or admin users bypass validation
-	isAdmin := isControllerOrAdminUser(ctx)
+	if err := runWorkspaceChecks(ctx, oldWorkspace, newWorkspace, v.updateChecksForAllUsers()); err != nil {
+		return nil, err
+	}
 
 	// NOTE: Removed templateRef immutability check to enable template mutability (PR #129)
 	// Templates can now be changed after workspace creation
 
-	// Admin users bypass user validation
-	if isAdmin {
+	// Controller or admin users bypass validation
+	if isControllerOrAdminUser(ctx) {
 		return nil, nil
 	}
 
-	// Validate no user modifications to reserved prefix labels/annotations
-	if err := validateReservedPrefixOnUpdate(oldWorkspace, newWorkspace); err != nil {
-		return nil, err
-	}
-
-	// Validate service account access for new workspace
-	if err := v.serviceAccountValidator.ValidateServiceAccountAccess(ctx, newWorkspace); err != nil {
-		return nil, err
-	}
-
-	originalOwnershipType := getEffectiveOwnershipType(oldWorkspace.Spec.OwnershipType)
-	newOwnershipType := getEffectiveOwnershipType(newWorkspace.Spec.OwnershipType)
-	workspacelog.Info("Ownership validation check", "originalType", originalOwnershipType, "newType", newOwnershipType)
-	// For OwnerOnly workspaces, check if user has permission
-	if originalOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
-		// Existing OwnerOnly workspace - check against old workspace
-		if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
-			return nil, err
-		}
-	} else if newOwnershipType == webhookconst.OwnershipTypeOwnerOnly {
-		// Changing to OwnerOnly - only allow if user is the original creator
-		if err := validateOwnershipPermission(ctx, oldWorkspace); err != nil {
-			return nil, err
-		}
-	}
-
-	// Validate template constraints for new workspace (only changed fields)
-	if err := v.templateValidator.ValidateUpdateWorkspace(ctx, oldWorkspace, newWorkspace); err != nil {
+	if err := runWorkspaceChecks(ctx, oldWorkspace, newWorkspace, v.updateChecksForApprovers()); err != nil {
 		return nil, err
 	}
 
-	// Validate access strategy namespace scope
-	if err := v.accessStrategyValidator.ValidateUpdateWorkspace(oldWorkspace, newWorkspace); err != nil {
-		return nil, err
-	}
-
-	// Validate volume ownership (security check - applies to all users)
-	if err := v.volumeValidator.ValidateVolumeOwnership(ctx, newWorkspace); err != nil {
-		return nil, err
+	// Start approvers may approve workspaces they do not own
+	if onlyStartApprovalChanged(oldWorkspace, newWorkspace) {
+		return nil, nil
 	}
 
-	return nil, nil
+	return nil, runWorkspaceChecks(ctx, oldWorkspace, newWorkspace, v.updateChecksForEditors())
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type Workspace.
-func (v *WorkspaceCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *WorkspaceCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (_ admission.Warnings, err error) {
+	defer func(start time.Time) { observeAdmission(admissionOperationValidateDelete, start, err) }(time.Now())
+
 	workspace, ok := obj.(*workspacev1alpha1.Workspace)
 	if !ok {
 		return nil, fmt.Errorf("expected a Workspace object but got %T", obj)
 	}
 	workspacelog.Info("Validation for Workspace upon deletion", "name", workspace.GetName(), "namespace", workspace.GetNamespace())
 
+	if !v.scope.Matches(workspace) {
+		return nil, nil
+	}
+
+	// Validate the workspace is not protected from deletion (applies to all users)
+	if err := v.deletionValidator.ValidateDelete(ctx, workspace); err != nil {
+		return nil, err
+	}
+
 	// Controller or admin users bypass validation
 	if isControllerOrAdminUser(ctx) {
 		return nil, nil
@@ -7,8 +7,10 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -554,6 +556,55 @@ var _ = Describe("Workspace Webhook", func() {
 		})
 	})
 
+	Context("Operator scope", func() {
+		BeforeEach(func() {
+			scope, err := workspaceutil.NewScope("team=ml", nil, "")
+			Expect(err).NotTo(HaveOccurred())
+			defaulter.scope = scope
+			validator.scope = scope
+		})
+
+		It("should leave workspaces of other instances unchanged", func() {
+			userCtx := createUserContext(ctx, "CREATE", "test-user")
+
+			Expect(defaulter.Default(userCtx, workspace)).To(Succeed())
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationCreatedBy))
+		})
+
+		It("should admit workspaces of other instances without validation", func() {
+			userCtx := createUserContext(ctx, "UPDATE", "different-user")
+
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Annotations = map[string]string{controller.AnnotationCreatedBy: "original-user"}
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Annotations = map[string]string{controller.AnnotationCreatedBy: "malicious-user"}
+
+			_, err := validator.ValidateUpdate(userCtx, oldWorkspace, newWorkspace)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should validate updates moving a workspace into the scope", func() {
+			userCtx := createUserContext(ctx, "UPDATE", "different-user")
+
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Annotations = map[string]string{controller.AnnotationCreatedBy: "original-user"}
+			newWorkspace := workspace.DeepCopy()
+			newWorkspace.Labels = map[string]string{"team": "ml"}
+			newWorkspace.Annotations = map[string]string{controller.AnnotationCreatedBy: "malicious-user"}
+
+			_, err := validator.ValidateUpdate(userCtx, oldWorkspace, newWorkspace)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should default workspaces in the scope", func() {
+			userCtx := createUserContext(ctx, "CREATE", "test-user")
+			workspace.Labels = map[string]string{"team": "ml"}
+
+			Expect(defaulter.Default(userCtx, workspace)).To(Succeed())
+			Expect(workspace.Annotations[controller.AnnotationCreatedBy]).To(Equal("test-user"))
+		})
+	})
+
 	Context("validateOwnershipPermission", func() {
 		var ownerOnlyWorkspace *workspacev1alpha1.Workspace
 
@@ -693,6 +744,253 @@ var _ = Describe("Workspace Webhook", func() {
 			})
 		})
 
+		Context("validateStorageNotShrunk", func() {
+			withStorageSize := func(size string) *workspacev1alpha1.Workspace {
+				return &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse(size)},
+				}}
+			}
+
+			It("should allow increasing the storage size", func() {
+				Expect(validateStorageNotShrunk(withStorageSize("10Gi"), withStorageSize("20Gi"))).To(Succeed())
+			})
+
+			It("should allow an equivalent storage size", func() {
+				Expect(validateStorageNotShrunk(withStorageSize("10Gi"), withStorageSize("10240Mi"))).To(Succeed())
+			})
+
+			It("should reject decreasing the storage size", func() {
+				err := validateStorageNotShrunk(withStorageSize("20Gi"), withStorageSize("10Gi"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("cannot decrease from 20Gi to 10Gi"))
+				Expect(err.Error()).To(ContainSubstring("create a new workspace"))
+			})
+
+			It("should reject decreasing the storage size through the update webhook", func() {
+				_, err := validator.ValidateUpdate(ctx, withStorageSize("20Gi"), withStorageSize("19Gi"))
+				Expect(err).To(MatchError(ContainSubstring("cannot decrease")))
+			})
+		})
+
+		Context("storage classes", func() {
+			withStorageClass := func(storageClassName *string) *workspacev1alpha1.Workspace {
+				return &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi"), StorageClassName: storageClassName},
+				}}
+			}
+			fast := "fast"
+			standard := "standard"
+
+			BeforeEach(func() {
+				template.Spec.PrimaryStorage = &workspacev1alpha1.StorageConfig{
+					DefaultStorageClassName:  &standard,
+					AllowedStorageClassNames: []string{standard, fast},
+				}
+			})
+
+			It("should allow a storage class of the allowlist", func() {
+				Expect(validateStorageClassAllowed(withStorageClass(&fast), template)).To(BeNil())
+			})
+
+			It("should reject a storage class outside the allowlist", func() {
+				slow := "slow"
+				violation := validateStorageClassAllowed(withStorageClass(&slow), template)
+				Expect(violation).NotTo(BeNil())
+				Expect(violation.Type).To(Equal(ViolationTypeStorageClassNotAllowed))
+				Expect(violation.Message).To(ContainSubstring("Storage class slow is not allowed by template 'test-template'"))
+			})
+
+			It("should reject the cluster default storage class when the template has an allowlist", func() {
+				violation := validateStorageClassAllowed(withStorageClass(nil), template)
+				Expect(violation).NotTo(BeNil())
+				Expect(violation.Message).To(ContainSubstring("the cluster default"))
+			})
+
+			It("should allow any storage class without an allowlist", func() {
+				template.Spec.PrimaryStorage.AllowedStorageClassNames = nil
+				slow := "slow"
+				Expect(validateStorageClassAllowed(withStorageClass(&slow), template)).To(BeNil())
+			})
+
+			It("should keep the storage class violations of an update changing the storage class", func() {
+				violations := []TemplateViolation{{Type: ViolationTypeStorageClassNotAllowed}, {Type: ViolationTypeImageNotAllowed}}
+				Expect(withoutKeptStorageClassViolations(violations, withStorageClass(&standard), withStorageClass(&fast))).To(HaveLen(2))
+				Expect(withoutKeptStorageClassViolations(violations, withStorageClass(&standard), withStorageClass(&standard))).To(
+					ConsistOf(TemplateViolation{Type: ViolationTypeImageNotAllowed}))
+			})
+
+			It("should reject changing the storage class of a workspace with storage", func() {
+				Expect(validateStorageClassUnchanged(withStorageClass(&standard), withStorageClass(&standard))).To(Succeed())
+				err := validateStorageClassUnchanged(withStorageClass(&standard), withStorageClass(&fast))
+				Expect(err).To(MatchError(ContainSubstring(`cannot change from "standard" to "fast"`)))
+				err = validateStorageClassUnchanged(withStorageClass(nil), withStorageClass(&fast))
+				Expect(err).To(MatchError(ContainSubstring("immutable")))
+			})
+
+			It("should let the controller move the storage class of a workspace", func() {
+				GinkgoT().Setenv(controller.ControllerPodNamespaceEnv, "default")
+				GinkgoT().Setenv(controller.ControllerPodServiceAccountEnv, "controller")
+
+				userCtx := createUserContext(ctx, "UPDATE", "regular-user")
+				_, err := validator.ValidateUpdate(userCtx, withStorageClass(&standard), withStorageClass(&fast))
+				Expect(err).To(MatchError(ContainSubstring(`cannot change from "standard" to "fast"`)))
+
+				controllerCtx := createUserContext(ctx, "UPDATE", "system:serviceaccount:default:controller")
+				_, err = validator.ValidateUpdate(controllerCtx, withStorageClass(&standard), withStorageClass(&fast))
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should allow a storage class on a workspace adding storage", func() {
+				Expect(validateStorageClassUnchanged(&workspacev1alpha1.Workspace{}, withStorageClass(&fast))).To(Succeed())
+			})
+
+			It("should clear the storage class the template defaults on an update of storage without one", func() {
+				oldRaw, err := json.Marshal(withStorageClass(nil))
+				Expect(err).NotTo(HaveOccurred())
+				req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update, OldObject: runtime.RawExtension{Raw: oldRaw}}}
+
+				updated := withStorageClass(&standard)
+				keepStorageClassOnUpdate(req, updated)
+				Expect(updated.Spec.Storage.StorageClassName).To(BeNil())
+
+				req.Operation = admissionv1.Create
+				created := withStorageClass(&standard)
+				keepStorageClassOnUpdate(req, created)
+				Expect(created.Spec.Storage.StorageClassName).To(Equal(&standard))
+			})
+		})
+
+		Context("home mount", func() {
+			withHome := func(mountPath, subPath string) *workspacev1alpha1.Workspace {
+				return &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi"), MountPath: mountPath, SubPath: subPath},
+				}}
+			}
+
+			It("should allow home directories outside the system paths", func() {
+				for _, mountPath := range []string{"/home/jovyan", "/workspace", "/home/user", "/var/lib/home", "/usr/local/home"} {
+					Expect(homeMountPathConflict(mountPath)).To(BeEmpty(), mountPath)
+				}
+			})
+
+			It("should reject relative and system paths", func() {
+				Expect(homeMountPathConflict("home/user")).To(ContainSubstring("not an absolute path"))
+				for _, mountPath := range []string{"/", "/etc", "/etc/jupyter", "/var/run", "/var/run/secrets", "/usr", "/proc/1", "/tmp/"} {
+					Expect(homeMountPathConflict(mountPath)).To(ContainSubstring("system path"), mountPath)
+				}
+			})
+
+			It("should reject workspaces mounting their storage at a system path", func() {
+				_, err := validator.ValidateCreate(ctx, withHome("/etc", ""))
+				Expect(err).To(MatchError(ContainSubstring("spec.storage.mountPath cannot be '/etc'")))
+			})
+
+			It("should allow updates keeping the mount path", func() {
+				Expect(validateHomeMountPath(withHome("/etc", ""), withHome("/etc", ""))).To(Succeed())
+				Expect(validateHomeMountPath(withHome("/etc", ""), withHome("/var/run", ""))).To(HaveOccurred())
+			})
+
+			It("should reject changing the subPath of a workspace with storage", func() {
+				Expect(validateSubPathUnchanged(&workspacev1alpha1.Workspace{}, withHome("/home/user", "home"))).To(Succeed())
+				err := validateSubPathUnchanged(withHome("/home/user", ""), withHome("/home/user", "home"))
+				Expect(err).To(MatchError(ContainSubstring(`cannot change from "" to "home"`)))
+			})
+
+			It("should keep the subPath of the existing storage on updates", func() {
+				oldRaw, err := json.Marshal(withHome("/home/jovyan", ""))
+				Expect(err).NotTo(HaveOccurred())
+				req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update, OldObject: runtime.RawExtension{Raw: oldRaw}}}
+
+				updated := withHome("/home/user", "home")
+				keepSubPathOnUpdate(req, updated)
+				Expect(updated.Spec.Storage.SubPath).To(BeEmpty())
+
+				req.Operation = admissionv1.Create
+				created := withHome("/home/user", "home")
+				keepSubPathOnUpdate(req, created)
+				Expect(created.Spec.Storage.SubPath).To(Equal("home"))
+			})
+
+			It("should reject templates mounting the home volume at a system path", func() {
+				template.Spec.HomeMountPath = "/workspace"
+				Expect(validateTemplateHomeMount(template)).To(Succeed())
+				template.Spec.HomeMountPath = "/var/run"
+				Expect(validateTemplateHomeMount(template)).To(MatchError(ContainSubstring("spec.homeMountPath cannot be '/var/run'")))
+			})
+		})
+
+		Context("storage modes", func() {
+			withMode := func(mode workspacev1alpha1.StorageMode) *workspacev1alpha1.Workspace {
+				return &workspacev1alpha1.Workspace{Spec: workspacev1alpha1.WorkspaceSpec{
+					Storage: &workspacev1alpha1.StorageSpec{Size: resource.MustParse("10Gi"), Mode: mode},
+				}}
+			}
+
+			BeforeEach(func() {
+				template.Spec.PrimaryStorage = &workspacev1alpha1.StorageConfig{Mode: workspacev1alpha1.StorageModeEphemeral}
+			})
+
+			It("should reject a storage mode other than the one of the template", func() {
+				Expect(validateStorageMode(withMode(workspacev1alpha1.StorageModeEphemeral), template)).To(BeNil())
+				violation := validateStorageMode(withMode(""), template)
+				Expect(violation).NotTo(BeNil())
+				Expect(violation.Type).To(Equal(ViolationTypeStorageModeNotAllowed))
+				Expect(violation.Message).To(ContainSubstring(
+					"Storage mode Persistent is not allowed by template 'test-template', whose workspaces use Ephemeral storage"))
+			})
+
+			It("should allow any storage mode when the template allows overrides", func() {
+				template.Spec.PrimaryStorage.AllowModeOverride = true
+				Expect(validateStorageMode(withMode(workspacev1alpha1.StorageModePersistent), template)).To(BeNil())
+			})
+
+			It("should only allow Persistent storage under a template without primary storage", func() {
+				template.Spec.PrimaryStorage = nil
+				Expect(validateStorageMode(withMode(""), template)).To(BeNil())
+				Expect(validateStorageMode(withMode(workspacev1alpha1.StorageModeEphemeral), template)).NotTo(BeNil())
+			})
+
+			It("should drop the storage mode violations of an update keeping the storage mode", func() {
+				violations := []TemplateViolation{{Type: ViolationTypeStorageModeNotAllowed}, {Type: ViolationTypeImageNotAllowed}}
+				Expect(withoutKeptStorageModeViolations(violations, withMode(""), withMode(workspacev1alpha1.StorageModePersistent))).To(
+					ConsistOf(TemplateViolation{Type: ViolationTypeImageNotAllowed}))
+				Expect(withoutKeptStorageModeViolations(violations, &workspacev1alpha1.Workspace{}, withMode(""))).To(HaveLen(2))
+			})
+
+			It("should reject switching the storage mode of a workspace with storage", func() {
+				Expect(validateStorageModeUnchanged(withMode(""), withMode(workspacev1alpha1.StorageModePersistent))).To(Succeed())
+				Expect(validateStorageModeUnchanged(&workspacev1alpha1.Workspace{}, withMode(workspacev1alpha1.StorageModeEphemeral))).To(Succeed())
+
+				_, err := validator.ValidateUpdate(ctx, withMode(""), withMode(workspacev1alpha1.StorageModeEphemeral))
+				Expect(err).To(MatchError(ContainSubstring("spec.storage.mode cannot change from Persistent to Ephemeral")))
+			})
+
+			It("should let ephemeral storage shrink", func() {
+				larger := withMode(workspacev1alpha1.StorageModeEphemeral)
+				smaller := withMode(workspacev1alpha1.StorageModeEphemeral)
+				smaller.Spec.Storage.Size = resource.MustParse("1Gi")
+				Expect(validateStorageNotShrunk(larger, smaller)).To(Succeed())
+			})
+
+			It("should keep the storage mode of the existing storage on updates", func() {
+				oldRaw, err := json.Marshal(withMode(""))
+				Expect(err).NotTo(HaveOccurred())
+				req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update, OldObject: runtime.RawExtension{Raw: oldRaw}}}
+
+				updated := withMode(workspacev1alpha1.StorageModeEphemeral)
+				keepStorageModeOnUpdate(req, updated)
+				Expect(updated.Spec.Storage.Mode).To(BeEmpty())
+
+				req.Operation = admissionv1.Create
+				created := withMode(workspacev1alpha1.StorageModeEphemeral)
+				keepStorageModeOnUpdate(req, created)
+				Expect(created.Spec.Storage.Mode).To(Equal(workspacev1alpha1.StorageModeEphemeral))
+			})
+		})
+
 		Context("validateSecondaryStorages", func() {
 			It("should allow volumes when AllowSecondaryStorages is true", func() {
 				allowSecondaryStorages := true
@@ -813,6 +1111,74 @@ var _ = Describe("Workspace Webhook", func() {
 		})
 	})
 
+	Context("stampDesiredStatusSetAt", func() {
+		var now time.Time
+
+		newRequest := func(operation, username string, oldWorkspace *workspacev1alpha1.Workspace) admission.Request {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+				Operation: admissionv1.Operation(operation),
+			}}
+			if oldWorkspace != nil {
+				raw, err := json.Marshal(oldWorkspace)
+				Expect(err).NotTo(HaveOccurred())
+				req.OldObject = runtime.RawExtension{Raw: raw}
+			}
+			return req
+		}
+
+		BeforeEach(func() {
+			now = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			workspace.Annotations = map[string]string{}
+		})
+
+		It("should stamp on create when desiredStatus is set", func() {
+			workspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			stampDesiredStatusSetAt(newRequest("CREATE", "user1", nil), workspace, now)
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationDesiredStatusSetAt, "2026-01-02T03:04:05Z"))
+		})
+
+		It("should drop a user-supplied stamp on create without desiredStatus", func() {
+			workspace.Spec.DesiredStatus = ""
+			workspace.Annotations[controller.AnnotationDesiredStatusSetAt] = "2099-01-01T00:00:00Z"
+			stampDesiredStatusSetAt(newRequest("CREATE", "user1", nil), workspace, now)
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationDesiredStatusSetAt))
+		})
+
+		It("should stamp on update when desiredStatus changes", func() {
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Spec.DesiredStatus = controller.DesiredStateStopped
+			workspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			stampDesiredStatusSetAt(newRequest("UPDATE", "user1", oldWorkspace), workspace, now)
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationDesiredStatusSetAt, "2026-01-02T03:04:05Z"))
+		})
+
+		It("should restore the previous stamp when desiredStatus is unchanged", func() {
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			oldWorkspace.Annotations = map[string]string{controller.AnnotationDesiredStatusSetAt: "2025-12-31T00:00:00Z"}
+			workspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			workspace.Annotations[controller.AnnotationDesiredStatusSetAt] = "2099-01-01T00:00:00Z"
+			stampDesiredStatusSetAt(newRequest("UPDATE", "user1", oldWorkspace), workspace, now)
+			Expect(workspace.Annotations).To(HaveKeyWithValue(controller.AnnotationDesiredStatusSetAt, "2025-12-31T00:00:00Z"))
+		})
+
+		It("should not stamp writes by the controller service account", func() {
+			Expect(os.Setenv(controller.ControllerPodNamespaceEnv, "default")).To(Succeed())
+			Expect(os.Setenv(controller.ControllerPodServiceAccountEnv, "controller")).To(Succeed())
+			defer func() {
+				_ = os.Unsetenv(controller.ControllerPodNamespaceEnv)
+				_ = os.Unsetenv(controller.ControllerPodServiceAccountEnv)
+			}()
+
+			oldWorkspace := workspace.DeepCopy()
+			oldWorkspace.Spec.DesiredStatus = controller.DesiredStateRunning
+			workspace.Spec.DesiredStatus = controller.DesiredStateStopped
+			stampDesiredStatusSetAt(newRequest("UPDATE", "system:serviceaccount:default:controller", oldWorkspace), workspace, now)
+			Expect(workspace.Annotations).NotTo(HaveKey(controller.AnnotationDesiredStatusSetAt))
+		})
+	})
+
 	Context("Metadata-Only Updates (GAP-7)", func() {
 		It("should skip validation for metadata-only updates (labels)", func() {
 			userCtx := createUserContext(ctx, "UPDATE", "test-user")
@@ -0,0 +1,253 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package webhookconfig
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+)
+
+// Keys of the webhook certificate secret
+const (
+	// SecretKeyCACert holds the PEM-encoded certificates the webhook configurations trust: the current CA,
+	// followed by the previous one while a CA rotation is in progress
+	SecretKeyCACert = "ca.crt"
+	// SecretKeyCAKey holds the PEM-encoded private key of the current CA
+	SecretKeyCAKey = "ca.key"
+	// SecretKeyTLSCert holds the PEM-encoded serving certificate of the webhook server
+	SecretKeyTLSCert = "tls.crt"
+	// SecretKeyTLSKey holds the PEM-encoded private key of the serving certificate
+	SecretKeyTLSKey = "tls.key"
+)
+
+const (
+	// caValidity is how long an issued CA is valid
+	caValidity = 5 * 365 * 24 * time.Hour
+	// servingValidity is how long an issued serving certificate is valid
+	servingValidity = 90 * 24 * time.Hour
+	// caRenewBefore is how long before its expiry the CA is replaced. It exceeds servingValidity so that
+	// the serving certificates signed by the previous CA expire before it is dropped from the CA bundle.
+	caRenewBefore = 180 * 24 * time.Hour
+	// servingRenewBefore is how long before its expiry the serving certificate is reissued
+	servingRenewBefore = 30 * 24 * time.Hour
+	// clockSkew backdates the certificates so that they are valid on API servers whose clock is behind
+	clockSkew = time.Hour
+)
+
+// certificateBundle is the content of the webhook certificate secret
+type certificateBundle struct {
+	caBundle   []byte
+	caCert     []byte
+	caKey      []byte
+	tlsCert    []byte
+	tlsKey     []byte
+	caNotAfter time.Time
+	notAfter   time.Time
+	dnsNames   []string
+	signedByCA bool
+}
+
+// parseCertificateBundle reads the certificates of the secret data. Missing or malformed entries leave
+// the corresponding fields empty, so that they are reissued.
+func parseCertificateBundle(data map[string][]byte) *certificateBundle {
+	bundle := &certificateBundle{
+		caBundle: data[SecretKeyCACert],
+		caKey:    data[SecretKeyCAKey],
+		tlsCert:  data[SecretKeyTLSCert],
+		tlsKey:   data[SecretKeyTLSKey],
+	}
+
+	var ca *x509.Certificate
+	if block, _ := pem.Decode(bundle.caBundle); block != nil {
+		if parsed, err := x509.ParseCertificate(block.Bytes); err == nil {
+			ca = parsed
+			bundle.caCert = pem.EncodeToMemory(block)
+			bundle.caNotAfter = parsed.NotAfter
+		}
+	}
+	if ca == nil || parsePrivateKey(bundle.caKey) == nil {
+		bundle.caCert, bundle.caKey, bundle.caNotAfter = nil, nil, time.Time{}
+	}
+
+	if block, _ := pem.Decode(bundle.tlsCert); block != nil {
+		if serving, err := x509.ParseCertificate(block.Bytes); err == nil && parsePrivateKey(bundle.tlsKey) != nil {
+			bundle.notAfter = serving.NotAfter
+			bundle.dnsNames = serving.DNSNames
+			bundle.signedByCA = ca != nil && serving.CheckSignatureFrom(ca) == nil
+		}
+	}
+	return bundle
+}
+
+// data returns the secret data of the bundle
+func (b *certificateBundle) data() map[string][]byte {
+	return map[string][]byte{
+		SecretKeyCACert:  b.caBundle,
+		SecretKeyCAKey:   b.caKey,
+		SecretKeyTLSCert: b.tlsCert,
+		SecretKeyTLSKey:  b.tlsKey,
+	}
+}
+
+// renew issues the CA and serving certificate the bundle needs at the time, and returns true if it changed.
+// A replaced CA stays in the CA bundle until its expiry, so that API servers keep trusting the serving
+// certificates it signed while the webhook configurations pick up the new CA.
+func (b *certificateBundle) renew(dnsNames []string, now time.Time) (bool, error) {
+	changed := false
+	if b.caCert == nil || now.Add(caRenewBefore).After(b.caNotAfter) {
+		caCert, caKey, err := issueCA(now)
+		if err != nil {
+			return false, err
+		}
+		previous := b.caCert
+		if previous != nil && now.After(b.caNotAfter) {
+			previous = nil
+		}
+		b.caCert, b.caKey, b.caNotAfter = caCert, caKey, now.Add(caValidity)
+		b.caBundle = append(append([]byte{}, caCert...), previous...)
+		b.signedByCA = false
+		changed = true
+	} else if !bytes.HasPrefix(b.caBundle, b.caCert) {
+		b.caBundle = b.caCert
+		changed = true
+	}
+
+	if !b.signedByCA || now.Add(servingRenewBefore).After(b.notAfter) || !slices.Equal(b.dnsNames, dnsNames) {
+		tlsCert, tlsKey, err := issueServingCertificate(b.caCert, b.caKey, dnsNames, now)
+		if err != nil {
+			return false, err
+		}
+		b.tlsCert, b.tlsKey = tlsCert, tlsKey
+		b.notAfter, b.dnsNames, b.signedByCA = now.Add(servingValidity), dnsNames, true
+		changed = true
+	}
+
+	// Drop the previous CA once the serving certificates it signed expired
+	if previous := b.caBundle[len(b.caCert):]; len(previous) > 0 {
+		if block, _ := pem.Decode(previous); block == nil {
+			b.caBundle = b.caCert
+			changed = true
+		} else if cert, err := x509.ParseCertificate(block.Bytes); err != nil || now.After(cert.NotAfter) {
+			b.caBundle = b.caCert
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// issueCA returns a new self-signed CA certificate and its key, PEM-encoded
+func issueCA(now time.Time) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the CA key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "jupyter-k8s-webhook-ca"},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to issue the CA certificate: %w", err)
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// issueServingCertificate returns a new serving certificate for the DNS names signed by the CA, and its
+// key, PEM-encoded
+func issueServingCertificate(caCertPEM, caKeyPEM []byte, dnsNames []string, now time.Time) ([]byte, []byte, error) {
+	block, _ := pem.Decode(caCertPEM)
+	if block == nil {
+		return nil, nil, fmt.Errorf("failed to decode the CA certificate")
+	}
+	ca, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the CA certificate: %w", err)
+	}
+	caKey := parsePrivateKey(caKeyPEM)
+	if caKey == nil {
+		return nil, nil, fmt.Errorf("failed to parse the CA key")
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate the serving key: %w", err)
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(servingValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to issue the serving certificate: %w", err)
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), keyPEM, nil
+}
+
+// newSerialNumber returns a random certificate serial number
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate a serial number: %w", err)
+	}
+	return serial, nil
+}
+
+// encodePrivateKey returns the PEM encoding of the key
+func encodePrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+}
+
+// parsePrivateKey returns the key of the PEM encoding, or nil if it is malformed
+func parsePrivateKey(keyPEM []byte) *ecdsa.PrivateKey {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return key
+}
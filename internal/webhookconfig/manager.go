@@ -0,0 +1,338 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package webhookconfig lets the operator own its admission webhooks at runtime: it issues and rotates the
+// serving certificate of the webhook server, and keeps the webhook configurations and their CA bundle up to
+// date, with the namespace and object selectors that roll the workspace webhooks out gradually.
+package webhookconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// DefaultSecretName is the name of the secret holding the webhook certificates
+	DefaultSecretName = "webhook-server-cert"
+	// DefaultServiceName is the name of the service fronting the webhook server
+	DefaultServiceName = "jupyter-k8s-webhook-service"
+	// DefaultMutatingConfigurationName is the name of the mutating webhook configuration
+	DefaultMutatingConfigurationName = "jupyter-k8s-mutating-webhook-configuration"
+	// DefaultValidatingConfigurationName is the name of the validating webhook configuration
+	DefaultValidatingConfigurationName = "jupyter-k8s-validating-webhook-configuration"
+	// DefaultResyncInterval is how often the certificates, webhook configurations and coverage are reconciled
+	DefaultResyncInterval = time.Minute
+	// retryDelay is how long a failed reconciliation waits before it is retried
+	retryDelay = 10 * time.Second
+)
+
+// coveredNamespaces reports how many namespaces the namespace selector of the workspace webhooks covers
+var coveredNamespaces = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "jupyter_k8s_webhook_covered_namespaces",
+	Help: "Number of namespaces selected by the namespace selector of the workspace admission webhooks",
+})
+
+func init() {
+	metrics.Registry.MustRegister(coveredNamespaces)
+}
+
+// Options configures the webhooks the Manager owns
+type Options struct {
+	// Namespace is the namespace of the operator, holding the certificate secret and the webhook service
+	Namespace string
+	// SecretName is the name of the secret the certificates are stored in
+	SecretName string
+	// ServiceName is the name of the service fronting the webhook server
+	ServiceName string
+	// MutatingConfigurationName and ValidatingConfigurationName name the webhook configurations
+	MutatingConfigurationName   string
+	ValidatingConfigurationName string
+	// NamespaceSelector restricts the namespaces the workspace webhooks see. Nil selects every namespace.
+	NamespaceSelector *metav1.LabelSelector
+	// ObjectSelector restricts the workspaces the workspace webhooks see. Nil selects every workspace.
+	ObjectSelector *metav1.LabelSelector
+	// EnableWorkspaceWebhooks registers the workspace and pod exec webhooks
+	EnableWorkspaceWebhooks bool
+	// EnableTemplateWebhook registers the workspace template webhook
+	EnableTemplateWebhook bool
+	// ResyncInterval is how often the webhooks are reconciled. Defaults to DefaultResyncInterval.
+	ResyncInterval time.Duration
+}
+
+// Manager issues the serving certificate of the webhook server and keeps the webhook configurations of the
+// operator up to date. Every replica runs it, since each one serves the webhooks with the shared certificate.
+type Manager struct {
+	client            client.Client
+	reader            client.Reader
+	options           Options
+	namespaceSelector labels.Selector
+	certificate       atomic.Pointer[tls.Certificate]
+	now               func() time.Time
+}
+
+var _ manager.Runnable = &Manager{}
+var _ manager.LeaderElectionRunnable = &Manager{}
+
+// NewManager creates a Manager. It is created before the controller manager, for the webhook server to
+// take its TLS option, and runs once added to the controller manager.
+func NewManager(options Options) (*Manager, error) {
+	if options.Namespace == "" {
+		return nil, fmt.Errorf("the namespace of the webhook service is required")
+	}
+	if options.SecretName == "" {
+		options.SecretName = DefaultSecretName
+	}
+	if options.ServiceName == "" {
+		options.ServiceName = DefaultServiceName
+	}
+	if options.MutatingConfigurationName == "" {
+		options.MutatingConfigurationName = DefaultMutatingConfigurationName
+	}
+	if options.ValidatingConfigurationName == "" {
+		options.ValidatingConfigurationName = DefaultValidatingConfigurationName
+	}
+	if options.ResyncInterval <= 0 {
+		options.ResyncInterval = DefaultResyncInterval
+	}
+
+	namespaceSelector := labels.Everything()
+	if options.NamespaceSelector != nil {
+		var err error
+		if namespaceSelector, err = metav1.LabelSelectorAsSelector(options.NamespaceSelector); err != nil {
+			return nil, fmt.Errorf("invalid webhook namespace selector: %w", err)
+		}
+	}
+	if options.ObjectSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(options.ObjectSelector); err != nil {
+			return nil, fmt.Errorf("invalid webhook object selector: %w", err)
+		}
+	}
+
+	return &Manager{
+		options:           options,
+		namespaceSelector: namespaceSelector,
+		now:               time.Now,
+	}, nil
+}
+
+// SetupWithManager adds the Manager to the controller manager. The certificate secret and the webhook
+// configurations are read uncached, so that every replica sees the certificate issued by another.
+func (m *Manager) SetupWithManager(mgr manager.Manager) error {
+	m.client = mgr.GetClient()
+	m.reader = mgr.GetAPIReader()
+	return mgr.Add(m)
+}
+
+// TLSOption sets the certificate of the webhook server to the one the Manager issues. The webhook server
+// does not watch certificate files when it is set.
+func (m *Manager) TLSOption() func(*tls.Config) {
+	return func(config *tls.Config) {
+		config.GetCertificate = m.GetCertificate
+	}
+}
+
+// GetCertificate returns the current serving certificate, for the handshakes of the webhook server
+func (m *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	certificate := m.certificate.Load()
+	if certificate == nil {
+		return nil, errors.New("the webhook serving certificate is not issued yet")
+	}
+	return certificate, nil
+}
+
+// NeedLeaderElection returns false: every replica needs the serving certificate
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// Start reconciles the webhooks until the context is cancelled
+func (m *Manager) Start(ctx context.Context) error {
+	logger := logf.FromContext(ctx).WithName("webhook-config")
+	for {
+		delay := m.options.ResyncInterval
+		if err := m.Reconcile(ctx); err != nil {
+			logger.Error(err, "Failed to reconcile the webhook configurations, retrying", "retryAfter", retryDelay)
+			delay = retryDelay
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// Reconcile issues or renews the certificates, loads the serving certificate, then brings the webhook
+// configurations and the coverage metric up to date
+func (m *Manager) Reconcile(ctx context.Context) error {
+	bundle, err := m.reconcileCertificates(ctx)
+	if err != nil {
+		return err
+	}
+	certificate, err := tls.X509KeyPair(bundle.tlsCert, bundle.tlsKey)
+	if err != nil {
+		return fmt.Errorf("failed to load the webhook serving certificate: %w", err)
+	}
+	m.certificate.Store(&certificate)
+
+	if err := m.reconcileMutatingConfiguration(ctx, bundle.caBundle); err != nil {
+		return err
+	}
+	if err := m.reconcileValidatingConfiguration(ctx, bundle.caBundle); err != nil {
+		return err
+	}
+	return m.reportCoverage(ctx)
+}
+
+// dnsNames returns the names the webhook service is reached at
+func (m *Manager) dnsNames() []string {
+	return []string{
+		fmt.Sprintf("%s.%s.svc", m.options.ServiceName, m.options.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", m.options.ServiceName, m.options.Namespace),
+	}
+}
+
+// reconcileCertificates returns the certificates of the secret, issuing or renewing them first when
+// needed. Replicas racing to renew them are resolved by the optimistic concurrency of the secret: the
+// losers adopt the certificates of the winner on their next attempt.
+func (m *Manager) reconcileCertificates(ctx context.Context) (*certificateBundle, error) {
+	logger := logf.FromContext(ctx)
+	key := client.ObjectKey{Name: m.options.SecretName, Namespace: m.options.Namespace}
+
+	secret := &corev1.Secret{}
+	err := m.reader.Get(ctx, key, secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to get the webhook certificate secret %s: %w", key, err)
+	}
+	exists := err == nil
+
+	bundle := parseCertificateBundle(secret.Data)
+	changed, err := bundle.renew(m.dnsNames(), m.now())
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return bundle, nil
+	}
+
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data:       bundle.data(),
+		}
+		if err := m.client.Create(ctx, secret); err != nil {
+			return nil, fmt.Errorf("failed to create the webhook certificate secret %s: %w", key, err)
+		}
+		logger.Info("Issued the webhook certificates", "secret", key)
+		return bundle, nil
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for name, value := range bundle.data() {
+		secret.Data[name] = value
+	}
+	if err := m.client.Update(ctx, secret); err != nil {
+		return nil, fmt.Errorf("failed to update the webhook certificate secret %s: %w", key, err)
+	}
+	logger.Info("Renewed the webhook certificates", "secret", key)
+	return bundle, nil
+}
+
+// reconcileMutatingConfiguration creates or updates the mutating webhook configuration
+func (m *Manager) reconcileMutatingConfiguration(ctx context.Context, caBundle []byte) error {
+	webhooks := m.mutatingWebhooks(caBundle)
+	configuration := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	err := m.reader.Get(ctx, client.ObjectKey{Name: m.options.MutatingConfigurationName}, configuration)
+	switch {
+	case apierrors.IsNotFound(err):
+		configuration = &admissionregistrationv1.MutatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: m.options.MutatingConfigurationName},
+			Webhooks:   webhooks,
+		}
+		if err := m.client.Create(ctx, configuration); err != nil {
+			return fmt.Errorf("failed to create the mutating webhook configuration: %w", err)
+		}
+		logf.FromContext(ctx).Info("Created the mutating webhook configuration", "name", configuration.Name)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get the mutating webhook configuration: %w", err)
+	}
+
+	if equality.Semantic.DeepEqual(configuration.Webhooks, webhooks) {
+		return nil
+	}
+	configuration.Webhooks = webhooks
+	if err := m.client.Update(ctx, configuration); err != nil {
+		return fmt.Errorf("failed to update the mutating webhook configuration: %w", err)
+	}
+	logf.FromContext(ctx).Info("Updated the mutating webhook configuration", "name", configuration.Name)
+	return nil
+}
+
+// reconcileValidatingConfiguration creates or updates the validating webhook configuration
+func (m *Manager) reconcileValidatingConfiguration(ctx context.Context, caBundle []byte) error {
+	webhooks := m.validatingWebhooks(caBundle)
+	configuration := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	err := m.reader.Get(ctx, client.ObjectKey{Name: m.options.ValidatingConfigurationName}, configuration)
+	switch {
+	case apierrors.IsNotFound(err):
+		configuration = &admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: m.options.ValidatingConfigurationName},
+			Webhooks:   webhooks,
+		}
+		if err := m.client.Create(ctx, configuration); err != nil {
+			return fmt.Errorf("failed to create the validating webhook configuration: %w", err)
+		}
+		logf.FromContext(ctx).Info("Created the validating webhook configuration", "name", configuration.Name)
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to get the validating webhook configuration: %w", err)
+	}
+
+	if equality.Semantic.DeepEqual(configuration.Webhooks, webhooks) {
+		return nil
+	}
+	configuration.Webhooks = webhooks
+	if err := m.client.Update(ctx, configuration); err != nil {
+		return fmt.Errorf("failed to update the validating webhook configuration: %w", err)
+	}
+	logf.FromContext(ctx).Info("Updated the validating webhook configuration", "name", configuration.Name)
+	return nil
+}
+
+// reportCoverage sets the coverage metric to the number of namespaces the namespace selector selects
+func (m *Manager) reportCoverage(ctx context.Context) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := m.client.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: m.namespaceSelector}); err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	covered := 0
+	for _, namespace := range namespaces.Items {
+		if namespace.DeletionTimestamp.IsZero() {
+			covered++
+		}
+	}
+	coveredNamespaces.Set(float64(covered))
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package webhookconfig
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var rolloutSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"jupyter.org/webhooks": "enabled"}}
+
+func newTestManager(t *testing.T, objects ...client.Object) (*Manager, client.Client) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, admissionregistrationv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	m, err := NewManager(Options{
+		Namespace:               "jupyter-k8s-system",
+		NamespaceSelector:       rolloutSelector,
+		ObjectSelector:          &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ml"}},
+		EnableWorkspaceWebhooks: true,
+		EnableTemplateWebhook:   true,
+	})
+	require.NoError(t, err)
+	m.client, m.reader = k8sClient, k8sClient
+	return m, k8sClient
+}
+
+func getCertificateSecret(t *testing.T, k8sClient client.Client) *corev1.Secret {
+	secret := &corev1.Secret{}
+	require.NoError(t, k8sClient.Get(context.Background(),
+		client.ObjectKey{Name: DefaultSecretName, Namespace: "jupyter-k8s-system"}, secret))
+	return secret
+}
+
+// parseCertificates returns the certificates of the PEM data, in order
+func parseCertificates(t *testing.T, data []byte) []*x509.Certificate {
+	var certificates []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		certificates = append(certificates, certificate)
+	}
+	return certificates
+}
+
+func TestReconcileIssuesCertificatesAndConfiguresTheWebhooks(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: rolloutSelector.MatchLabels}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}})
+
+	_, err := m.GetCertificate(nil)
+	require.Error(t, err, "no certificate is served before it is issued")
+	require.NoError(t, m.Reconcile(ctx))
+
+	secret := getCertificateSecret(t, k8sClient)
+	cas := parseCertificates(t, secret.Data[SecretKeyCACert])
+	require.Len(t, cas, 1)
+	serving := parseCertificates(t, secret.Data[SecretKeyTLSCert])
+	require.Len(t, serving, 1)
+	assert.NoError(t, serving[0].CheckSignatureFrom(cas[0]))
+	assert.Contains(t, serving[0].DNSNames, "jupyter-k8s-webhook-service.jupyter-k8s-system.svc")
+	served, err := m.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, serving[0].Raw, served.Certificate[0])
+
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: DefaultMutatingConfigurationName}, mutating))
+	require.Len(t, mutating.Webhooks, 1)
+	assert.Equal(t, secret.Data[SecretKeyCACert], mutating.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, rolloutSelector, mutating.Webhooks[0].NamespaceSelector)
+	assert.Equal(t, map[string]string{"team": "ml"}, mutating.Webhooks[0].ObjectSelector.MatchLabels)
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: DefaultValidatingConfigurationName}, validating))
+	names := map[string]*metav1.LabelSelector{}
+	for _, webhook := range validating.Webhooks {
+		assert.Equal(t, secret.Data[SecretKeyCACert], webhook.ClientConfig.CABundle)
+		names[webhook.Name] = webhook.NamespaceSelector
+	}
+	assert.Equal(t, map[string]*metav1.LabelSelector{
+		WebhookNameValidatePodExec:           {},
+		WebhookNameValidateWorkspace:         rolloutSelector,
+		WebhookNameValidateWorkspaceStatus:   rolloutSelector,
+		WebhookNameValidateWorkspaceTemplate: {},
+	}, names, "only the workspace webhooks roll out gradually")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(coveredNamespaces))
+
+	// Up to date, nothing is rewritten
+	resourceVersion := secret.ResourceVersion
+	require.NoError(t, m.Reconcile(ctx))
+	assert.Equal(t, resourceVersion, getCertificateSecret(t, k8sClient).ResourceVersion)
+}
+
+func TestReconcileRestoresDriftedWebhookConfigurations(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(t)
+	require.NoError(t, m.Reconcile(ctx))
+
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: DefaultValidatingConfigurationName}, validating))
+	validating.Webhooks[1].NamespaceSelector = &metav1.LabelSelector{}
+	validating.Webhooks[1].ClientConfig.CABundle = nil
+	require.NoError(t, k8sClient.Update(ctx, validating))
+
+	require.NoError(t, m.Reconcile(ctx))
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKey{Name: DefaultValidatingConfigurationName}, validating))
+	assert.Equal(t, rolloutSelector, validating.Webhooks[1].NamespaceSelector)
+	assert.NotEmpty(t, validating.Webhooks[1].ClientConfig.CABundle)
+}
+
+func TestReconcileRenewsTheServingCertificateBeforeItExpires(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(t)
+	require.NoError(t, m.Reconcile(ctx))
+	issued := getCertificateSecret(t, k8sClient)
+
+	m.now = func() time.Time { return time.Now().Add(servingValidity - servingRenewBefore + time.Hour) }
+	require.NoError(t, m.Reconcile(ctx))
+
+	renewed := getCertificateSecret(t, k8sClient)
+	assert.Equal(t, issued.Data[SecretKeyCACert], renewed.Data[SecretKeyCACert], "the CA is kept")
+	assert.NotEqual(t, issued.Data[SecretKeyTLSCert], renewed.Data[SecretKeyTLSCert])
+}
+
+func TestReconcileRotatesTheCAKeepingThePreviousOneTrusted(t *testing.T) {
+	ctx := context.Background()
+	m, k8sClient := newTestManager(t)
+	require.NoError(t, m.Reconcile(ctx))
+	previousCA := parseCertificates(t, getCertificateSecret(t, k8sClient).Data[SecretKeyCACert])[0]
+
+	rotatedAt := time.Now().Add(caValidity - caRenewBefore + time.Hour)
+	m.now = func() time.Time { return rotatedAt }
+	require.NoError(t, m.Reconcile(ctx))
+
+	secret := getCertificateSecret(t, k8sClient)
+	cas := parseCertificates(t, secret.Data[SecretKeyCACert])
+	require.Len(t, cas, 2)
+	assert.Equal(t, previousCA.Raw, cas[1].Raw, "the previous CA stays trusted")
+	serving := parseCertificates(t, secret.Data[SecretKeyTLSCert])
+	assert.NoError(t, serving[0].CheckSignatureFrom(cas[0]), "the serving certificate is reissued by the new CA")
+
+	// The previous CA is dropped once expired
+	m.now = func() time.Time { return previousCA.NotAfter.Add(time.Hour) }
+	require.NoError(t, m.Reconcile(ctx))
+	cas = parseCertificates(t, getCertificateSecret(t, k8sClient).Data[SecretKeyCACert])
+	require.Len(t, cas, 1)
+	assert.NotEqual(t, previousCA.Raw, cas[0].Raw)
+}
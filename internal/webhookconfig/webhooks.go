@@ -0,0 +1,140 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package webhookconfig
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// Names of the webhooks of the operator, as registered by the webhook server
+const (
+	WebhookNameMutateWorkspace           = "mworkspace-v1alpha1.kb.io"
+	WebhookNameValidatePodExec           = "vpods-exec-workspace-v1.kb.io"
+	WebhookNameValidateWorkspace         = "vworkspace-v1alpha1.kb.io"
+	WebhookNameValidateWorkspaceStatus   = "vworkspace-status-v1alpha1.kb.io"
+	WebhookNameValidateWorkspaceTemplate = "vworkspacetemplate-v1alpha1.kb.io"
+)
+
+// Paths the webhook server serves the webhooks on
+const (
+	pathMutateWorkspace           = "/mutate-workspace-jupyter-org-v1alpha1-workspace"
+	pathValidatePodExec           = "/validate-pods-exec-workspace"
+	pathValidateWorkspace         = "/validate-workspace-jupyter-org-v1alpha1-workspace"
+	pathValidateWorkspaceStatus   = "/validate-workspace-jupyter-org-v1alpha1-workspace-status"
+	pathValidateWorkspaceTemplate = "/validate-workspace-jupyter-org-v1alpha1-workspacetemplate"
+)
+
+// workspaceRules returns the rules matching workspaces for the operations
+func workspaceRules(resource string, operations ...admissionregistrationv1.OperationType) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{{
+		Operations: operations,
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{"workspace.jupyter.org"},
+			APIVersions: []string{"v1alpha1"},
+			Resources:   []string{resource},
+			Scope:       ptr.To(admissionregistrationv1.AllScopes),
+		},
+	}}
+}
+
+// clientConfig returns the client config of the webhook served on the path
+func (m *Manager) clientConfig(path string, caBundle []byte) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Name:      m.options.ServiceName,
+			Namespace: m.options.Namespace,
+			Path:      ptr.To(path),
+			Port:      ptr.To[int32](443),
+		},
+		CABundle: caBundle,
+	}
+}
+
+// selectors returns the namespace and object selectors of the workspace webhooks. Unset selectors match
+// everything, like the API server defaults them.
+func (m *Manager) selectors() (*metav1.LabelSelector, *metav1.LabelSelector) {
+	namespaceSelector, objectSelector := &metav1.LabelSelector{}, &metav1.LabelSelector{}
+	if m.options.NamespaceSelector != nil {
+		namespaceSelector = m.options.NamespaceSelector.DeepCopy()
+	}
+	if m.options.ObjectSelector != nil {
+		objectSelector = m.options.ObjectSelector.DeepCopy()
+	}
+	return namespaceSelector, objectSelector
+}
+
+// mutatingWebhooks returns the webhooks of the mutating webhook configuration
+func (m *Manager) mutatingWebhooks(caBundle []byte) []admissionregistrationv1.MutatingWebhook {
+	if !m.options.EnableWorkspaceWebhooks {
+		return nil
+	}
+	namespaceSelector, objectSelector := m.selectors()
+	return []admissionregistrationv1.MutatingWebhook{{
+		Name:                    WebhookNameMutateWorkspace,
+		ClientConfig:            m.clientConfig(pathMutateWorkspace, caBundle),
+		Rules:                   workspaceRules("workspaces", admissionregistrationv1.Create, admissionregistrationv1.Update),
+		FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+		MatchPolicy:             ptr.To(admissionregistrationv1.Equivalent),
+		SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+		TimeoutSeconds:          ptr.To[int32](10),
+		AdmissionReviewVersions: []string{"v1"},
+		ReinvocationPolicy:      ptr.To(admissionregistrationv1.IfNeededReinvocationPolicy),
+		NamespaceSelector:       namespaceSelector,
+		ObjectSelector:          objectSelector,
+	}}
+}
+
+// validatingWebhooks returns the webhooks of the validating webhook configuration
+func (m *Manager) validatingWebhooks(caBundle []byte) []admissionregistrationv1.ValidatingWebhook {
+	var webhooks []admissionregistrationv1.ValidatingWebhook
+	validatingWebhook := func(name, path string, failurePolicy admissionregistrationv1.FailurePolicyType,
+		rules []admissionregistrationv1.RuleWithOperations) admissionregistrationv1.ValidatingWebhook {
+		return admissionregistrationv1.ValidatingWebhook{
+			Name:                    name,
+			ClientConfig:            m.clientConfig(path, caBundle),
+			Rules:                   rules,
+			FailurePolicy:           ptr.To(failurePolicy),
+			MatchPolicy:             ptr.To(admissionregistrationv1.Equivalent),
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			TimeoutSeconds:          ptr.To[int32](10),
+			AdmissionReviewVersions: []string{"v1"},
+			NamespaceSelector:       &metav1.LabelSelector{},
+			ObjectSelector:          &metav1.LabelSelector{},
+		}
+	}
+
+	if m.options.EnableWorkspaceWebhooks {
+		webhooks = append(webhooks, validatingWebhook(WebhookNameValidatePodExec, pathValidatePodExec,
+			admissionregistrationv1.Ignore, []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Connect},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"pods/exec"},
+					Scope:       ptr.To(admissionregistrationv1.AllScopes),
+				},
+			}}))
+
+		workspaceWebhook := validatingWebhook(WebhookNameValidateWorkspace, pathValidateWorkspace,
+			admissionregistrationv1.Fail, workspaceRules("workspaces",
+				admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete))
+		workspaceWebhook.NamespaceSelector, workspaceWebhook.ObjectSelector = m.selectors()
+		webhooks = append(webhooks, workspaceWebhook)
+
+		statusWebhook := validatingWebhook(WebhookNameValidateWorkspaceStatus, pathValidateWorkspaceStatus,
+			admissionregistrationv1.Ignore, workspaceRules("workspaces/status", admissionregistrationv1.Update))
+		statusWebhook.NamespaceSelector, statusWebhook.ObjectSelector = m.selectors()
+		webhooks = append(webhooks, statusWebhook)
+	}
+	if m.options.EnableTemplateWebhook {
+		webhooks = append(webhooks, validatingWebhook(WebhookNameValidateWorkspaceTemplate, pathValidateWorkspaceTemplate,
+			admissionregistrationv1.Ignore, workspaceRules("workspacetemplates",
+				admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete)))
+	}
+	return webhooks
+}
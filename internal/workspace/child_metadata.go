@@ -0,0 +1,108 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Variables child metadata values may reference as ${variable}
+const (
+	ChildMetadataVarWorkspaceName      = "workspace.name"
+	ChildMetadataVarWorkspaceNamespace = "workspace.namespace"
+	ChildMetadataVarWorkspaceOwner     = "workspace.owner"
+)
+
+var childMetadataReference = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// ChildMetadataVars holds the values of the variables of child metadata values
+type ChildMetadataVars struct {
+	WorkspaceName      string
+	WorkspaceNamespace string
+	WorkspaceOwner     string
+}
+
+func (v ChildMetadataVars) lookup(name string) (string, bool) {
+	switch name {
+	case ChildMetadataVarWorkspaceName:
+		return v.WorkspaceName, true
+	case ChildMetadataVarWorkspaceNamespace:
+		return v.WorkspaceNamespace, true
+	case ChildMetadataVarWorkspaceOwner:
+		return v.WorkspaceOwner, true
+	}
+	return "", false
+}
+
+// ValidateChildMetadataValue checks that a child metadata value only references known variables
+func ValidateChildMetadataValue(value string) error {
+	for _, match := range childMetadataReference.FindAllStringSubmatch(value, -1) {
+		if _, ok := (ChildMetadataVars{}).lookup(match[1]); !ok {
+			return fmt.Errorf("unknown variable ${%s}: supported variables are ${%s}, ${%s} and ${%s}",
+				match[1], ChildMetadataVarWorkspaceName, ChildMetadataVarWorkspaceNamespace, ChildMetadataVarWorkspaceOwner)
+		}
+	}
+	if strings.Contains(childMetadataReference.ReplaceAllString(value, ""), "${") {
+		return fmt.Errorf("unterminated variable reference in %q", value)
+	}
+	return nil
+}
+
+// ExpandChildMetadataValue replaces the variable references of a child metadata value
+func ExpandChildMetadataValue(value string, vars ChildMetadataVars) string {
+	return childMetadataReference.ReplaceAllStringFunc(value, func(reference string) string {
+		resolved, _ := vars.lookup(reference[2 : len(reference)-1])
+		return resolved
+	})
+}
+
+// ExpandChildMetadata returns a copy of the child metadata with variable references replaced.
+// Labels whose expanded value is not a valid label value (e.g. an owner email) are left out
+// and returned as skipped.
+func ExpandChildMetadata(metadata *workspacev1alpha1.ChildMetadata, vars ChildMetadataVars) (*workspacev1alpha1.ChildMetadata, []string) {
+	if metadata == nil {
+		return nil, nil
+	}
+	var skipped []string
+	expand := func(object *workspacev1alpha1.ChildObjectMetadata) *workspacev1alpha1.ChildObjectMetadata {
+		if object == nil {
+			return nil
+		}
+		expanded := &workspacev1alpha1.ChildObjectMetadata{}
+		for key, value := range object.Labels {
+			value = ExpandChildMetadataValue(value, vars)
+			if len(validation.IsValidLabelValue(value)) > 0 {
+				skipped = append(skipped, key)
+				continue
+			}
+			if expanded.Labels == nil {
+				expanded.Labels = map[string]string{}
+			}
+			expanded.Labels[key] = value
+		}
+		if len(object.Annotations) > 0 {
+			expanded.Annotations = maps.Clone(object.Annotations)
+			for key, value := range expanded.Annotations {
+				expanded.Annotations[key] = ExpandChildMetadataValue(value, vars)
+			}
+		}
+		return expanded
+	}
+	return &workspacev1alpha1.ChildMetadata{
+		Pod:     expand(metadata.Pod),
+		Service: expand(metadata.Service),
+		Ingress: expand(metadata.Ingress),
+		PVC:     expand(metadata.PVC),
+		Secret:  expand(metadata.Secret),
+	}, skipped
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateChildMetadataValue(t *testing.T) {
+	assert.NoError(t, ValidateChildMetadataValue("plain"))
+	assert.NoError(t, ValidateChildMetadataValue("${workspace.name}.${workspace.namespace}.example.com"))
+	assert.NoError(t, ValidateChildMetadataValue("owner=${workspace.owner}"))
+
+	err := ValidateChildMetadataValue("${workspace.uid}")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown variable ${workspace.uid}")
+
+	assert.Error(t, ValidateChildMetadataValue("${workspace.name"))
+}
+
+func TestExpandChildMetadata(t *testing.T) {
+	vars := ChildMetadataVars{WorkspaceName: "ws", WorkspaceNamespace: "team-a", WorkspaceOwner: "alice@example.com"}
+	metadata := &workspacev1alpha1.ChildMetadata{
+		Service: &workspacev1alpha1.ChildObjectMetadata{
+			Labels: map[string]string{
+				"team":  "${workspace.namespace}",
+				"owner": "${workspace.owner}",
+			},
+			Annotations: map[string]string{
+				"external-dns.alpha.kubernetes.io/hostname": "${workspace.name}.${workspace.namespace}.example.com",
+				"backup/owner": "${workspace.owner}",
+			},
+		},
+	}
+
+	expanded, skipped := ExpandChildMetadata(metadata, vars)
+	require.NotNil(t, expanded.Service)
+	assert.Nil(t, expanded.Pod)
+	assert.Equal(t, map[string]string{"team": "team-a"}, expanded.Service.Labels)
+	assert.Equal(t, []string{"owner"}, skipped)
+	assert.Equal(t, "ws.team-a.example.com", expanded.Service.Annotations["external-dns.alpha.kubernetes.io/hostname"])
+	assert.Equal(t, "alice@example.com", expanded.Service.Annotations["backup/owner"])
+
+	// The template values are left unexpanded
+	assert.Equal(t, "${workspace.owner}", metadata.Service.Annotations["backup/owner"])
+
+	expanded, skipped = ExpandChildMetadata(nil, vars)
+	assert.Nil(t, expanded)
+	assert.Empty(t, skipped)
+}
@@ -15,6 +15,12 @@ const (
 	// LabelWorkspaceTemplateNamespace is the label key for template namespace in the Workspace labels
 	LabelWorkspaceTemplateNamespace = "workspace.jupyter.org/template-namespace"
 
+	// AnnotationTemplateGeneration records the generation of the template the webhook admitted the Workspace against
+	AnnotationTemplateGeneration = "workspace.jupyter.org/template-generation"
+
+	// MaxTemplateRevisions is the number of revisions of a template kept for the workspaces admitted against them
+	MaxTemplateRevisions = 10
+
 	// LabelAccessStrategyName is the label key for access strategy name in the Workspace labels
 	LabelAccessStrategyName = "workspace.jupyter.org/access-strategy-name"
 
@@ -33,6 +39,6 @@ const (
 	// ConflictRetryJitterMilliseconds is the maximum random jitter in milliseconds added to the retry delay
 	ConflictRetryJitterMilliseconds = 50
 
-	// WorkspacePageLimit defines the maximum number of Workspace returned by List(Workspace) call
+	// WorkspacePageLimit defines the maximum number of objects returned by each page of a paginated List call
 	WorkspacePageLimit int64 = 100
 )
@@ -0,0 +1,132 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// TemplatePolicies returns the lifecycle policies a template gives the workspaces that do not set their own,
+// nil when it gives none. The stale workspace retention is configured on namespaces and the controller,
+// not on templates.
+func TemplatePolicies(template *workspacev1alpha1.WorkspaceTemplate) *workspacev1alpha1.EffectivePolicies {
+	if template == nil {
+		return nil
+	}
+	return nonEmptyPolicies(&workspacev1alpha1.EffectivePolicies{
+		IdleShutdown: idleShutdownPolicy(template.Spec.DefaultIdleShutdown, workspacev1alpha1.PolicySourceTemplate),
+		Schedule:     schedulePolicy(template.Spec.DefaultSchedule, workspacev1alpha1.PolicySourceTemplate),
+		DeleteAfterStopped: deleteAfterStoppedPolicy(template.Spec.Retention,
+			workspacev1alpha1.PolicySourceTemplate),
+		MaxLifetime: maxLifetimePolicy(template, nil),
+	})
+}
+
+// ResolvePolicies returns the idle shutdown, schedule, deletion and lifetime policies the controller enforces on a
+// workspace, nil when none applies. The defaults of the template are copied into the workspace spec at admission:
+// a policy is attributed to the template while the workspace one matches the template default. The maximum
+// lifetime is set by the template only. The template is the revision the workspace resolves, and may be nil.
+func ResolvePolicies(
+	workspace *workspacev1alpha1.Workspace, template *workspacev1alpha1.WorkspaceTemplate) *workspacev1alpha1.EffectivePolicies {
+	var defaultIdleShutdown *workspacev1alpha1.IdleShutdownSpec
+	var defaultSchedule *workspacev1alpha1.ScheduleSpec
+	var defaultRetention *workspacev1alpha1.RetentionSpec
+	if template != nil {
+		defaultIdleShutdown = template.Spec.DefaultIdleShutdown
+		defaultSchedule = template.Spec.DefaultSchedule
+		defaultRetention = template.Spec.Retention
+	}
+	return nonEmptyPolicies(&workspacev1alpha1.EffectivePolicies{
+		IdleShutdown: idleShutdownPolicy(workspace.Spec.IdleShutdown,
+			policySource(workspace.Spec.IdleShutdown, defaultIdleShutdown)),
+		Schedule: schedulePolicy(workspace.Spec.Schedule, policySource(workspace.Spec.Schedule, defaultSchedule)),
+		DeleteAfterStopped: deleteAfterStoppedPolicy(workspace.Spec.Retention,
+			policySource(workspace.Spec.Retention, defaultRetention)),
+		MaxLifetime: maxLifetimePolicy(template, workspace),
+	})
+}
+
+// policySource attributes a workspace setting to the template when it matches the template default
+func policySource[T any](value, templateDefault *T) workspacev1alpha1.PolicySource {
+	if templateDefault != nil && equality.Semantic.DeepEqual(value, templateDefault) {
+		return workspacev1alpha1.PolicySourceTemplate
+	}
+	return workspacev1alpha1.PolicySourceWorkspace
+}
+
+func idleShutdownPolicy(
+	spec *workspacev1alpha1.IdleShutdownSpec, source workspacev1alpha1.PolicySource) *workspacev1alpha1.EffectiveIdleShutdownPolicy {
+	if spec == nil || !spec.Enabled {
+		return nil
+	}
+	policy := &workspacev1alpha1.EffectiveIdleShutdownPolicy{
+		IdleTimeoutInMinutes: spec.IdleTimeoutInMinutes,
+		Source:               source,
+	}
+	if spec.NeverConnectedTimeoutInMinutes != nil {
+		timeout := *spec.NeverConnectedTimeoutInMinutes
+		policy.NeverConnectedTimeoutInMinutes = &timeout
+	}
+	return policy
+}
+
+func schedulePolicy(
+	spec *workspacev1alpha1.ScheduleSpec, source workspacev1alpha1.PolicySource) *workspacev1alpha1.EffectiveSchedulePolicy {
+	if spec == nil {
+		return nil
+	}
+	return &workspacev1alpha1.EffectiveSchedulePolicy{
+		StartCron: spec.StartCron,
+		StopCron:  spec.StopCron,
+		TimeZone:  spec.TimeZone,
+		Source:    source,
+	}
+}
+
+func deleteAfterStoppedPolicy(
+	spec *workspacev1alpha1.RetentionSpec, source workspacev1alpha1.PolicySource) *workspacev1alpha1.EffectiveDeleteAfterStoppedPolicy {
+	if spec == nil || spec.DeleteAfterStoppedSeconds == nil || *spec.DeleteAfterStoppedSeconds <= 0 {
+		return nil
+	}
+	return &workspacev1alpha1.EffectiveDeleteAfterStoppedPolicy{
+		After:  metav1.Duration{Duration: time.Duration(*spec.DeleteAfterStoppedSeconds) * time.Second},
+		Source: source,
+	}
+}
+
+// maxLifetimePolicy returns the maximum lifetime of the template, expiring from the creation of the workspace
+// when not nil
+func maxLifetimePolicy(
+	template *workspacev1alpha1.WorkspaceTemplate, workspace *workspacev1alpha1.Workspace) *workspacev1alpha1.EffectiveMaxLifetimePolicy {
+	if template == nil || template.Spec.MaxLifetime == nil || template.Spec.MaxLifetime.Duration <= 0 {
+		return nil
+	}
+	policy := &workspacev1alpha1.EffectiveMaxLifetimePolicy{
+		MaxLifetime: *template.Spec.MaxLifetime,
+		Action:      template.Spec.MaxLifetimeAction,
+		Source:      workspacev1alpha1.PolicySourceTemplate,
+	}
+	if policy.Action == "" {
+		policy.Action = workspacev1alpha1.MaxLifetimeActionStop
+	}
+	if workspace != nil && !workspace.CreationTimestamp.IsZero() {
+		policy.ExpiresAt = &metav1.Time{Time: workspace.CreationTimestamp.Add(policy.MaxLifetime.Duration)}
+	}
+	return policy
+}
+
+func nonEmptyPolicies(policies *workspacev1alpha1.EffectivePolicies) *workspacev1alpha1.EffectivePolicies {
+	if policies.IdleShutdown == nil && policies.Schedule == nil && policies.Retention == nil &&
+		policies.DeleteAfterStopped == nil && policies.MaxLifetime == nil {
+		return nil
+	}
+	return policies
+}
@@ -0,0 +1,113 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"testing"
+	"time"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newPoliciesTestTemplate() *workspacev1alpha1.WorkspaceTemplate {
+	neverConnected := 15
+	deleteAfter := int64(7 * 24 * 3600)
+	return &workspacev1alpha1.WorkspaceTemplate{
+		Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+			DefaultIdleShutdown: &workspacev1alpha1.IdleShutdownSpec{
+				Enabled:                        true,
+				IdleTimeoutInMinutes:           60,
+				NeverConnectedTimeoutInMinutes: &neverConnected,
+			},
+			DefaultSchedule: &workspacev1alpha1.ScheduleSpec{StartCron: "0 8 * * 1-5", StopCron: "0 18 * * 1-5"},
+			Retention:       &workspacev1alpha1.RetentionSpec{DeleteAfterStoppedSeconds: &deleteAfter},
+		},
+	}
+}
+
+func TestTemplatePolicies(t *testing.T) {
+	assert.Nil(t, TemplatePolicies(nil))
+	assert.Nil(t, TemplatePolicies(&workspacev1alpha1.WorkspaceTemplate{}))
+
+	policies := TemplatePolicies(newPoliciesTestTemplate())
+	require.NotNil(t, policies)
+	require.NotNil(t, policies.IdleShutdown)
+	assert.Equal(t, 60, policies.IdleShutdown.IdleTimeoutInMinutes)
+	assert.Equal(t, 15, *policies.IdleShutdown.NeverConnectedTimeoutInMinutes)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.IdleShutdown.Source)
+	require.NotNil(t, policies.Schedule)
+	assert.Equal(t, "0 18 * * 1-5", policies.Schedule.StopCron)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.Schedule.Source)
+	require.NotNil(t, policies.DeleteAfterStopped)
+	assert.Equal(t, 7*24*time.Hour, policies.DeleteAfterStopped.After.Duration)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.DeleteAfterStopped.Source)
+	assert.Nil(t, policies.Retention)
+}
+
+func TestResolvePolicies(t *testing.T) {
+	template := newPoliciesTestTemplate()
+
+	workspace := &workspacev1alpha1.Workspace{}
+	assert.Nil(t, ResolvePolicies(workspace, template), "template defaults the workspace lacks are not enforced")
+
+	// Defaulted at admission
+	workspace.Spec.IdleShutdown = template.Spec.DefaultIdleShutdown.DeepCopy()
+	workspace.Spec.Schedule = template.Spec.DefaultSchedule.DeepCopy()
+	workspace.Spec.Retention = template.Spec.Retention.DeepCopy()
+	policies := ResolvePolicies(workspace, template)
+	require.NotNil(t, policies)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.IdleShutdown.Source)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.Schedule.Source)
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.DeleteAfterStopped.Source)
+
+	// Overridden by the workspace
+	workspace.Spec.IdleShutdown.IdleTimeoutInMinutes = 120
+	workspace.Spec.Schedule.TimeZone = "Europe/Paris"
+	policies = ResolvePolicies(workspace, template)
+	assert.Equal(t, 120, policies.IdleShutdown.IdleTimeoutInMinutes)
+	assert.Equal(t, workspacev1alpha1.PolicySourceWorkspace, policies.IdleShutdown.Source)
+	assert.Equal(t, "Europe/Paris", policies.Schedule.TimeZone)
+	assert.Equal(t, workspacev1alpha1.PolicySourceWorkspace, policies.Schedule.Source)
+
+	// Without template
+	policies = ResolvePolicies(workspace, nil)
+	assert.Equal(t, workspacev1alpha1.PolicySourceWorkspace, policies.IdleShutdown.Source)
+
+	// Disabled idle shutdown does not apply
+	workspace.Spec.IdleShutdown.Enabled = false
+	policies = ResolvePolicies(workspace, template)
+	assert.Nil(t, policies.IdleShutdown)
+	assert.NotNil(t, policies.Schedule)
+
+	// A workspace opting out of the deletion of its template keeps it
+	keep := int64(0)
+	workspace.Spec.Retention.DeleteAfterStoppedSeconds = &keep
+	policies = ResolvePolicies(workspace, template)
+	assert.Nil(t, policies.DeleteAfterStopped)
+}
+
+func TestResolvePoliciesExpireWorkspacesFromTheirCreation(t *testing.T) {
+	template := &workspacev1alpha1.WorkspaceTemplate{}
+	template.Spec.MaxLifetime = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+
+	policies := ResolvePolicies(workspace, template)
+	require.NotNil(t, policies)
+	require.NotNil(t, policies.MaxLifetime)
+	assert.Equal(t, workspacev1alpha1.MaxLifetimeActionStop, policies.MaxLifetime.Action, "workspaces are stopped by default")
+	assert.Equal(t, workspacev1alpha1.PolicySourceTemplate, policies.MaxLifetime.Source)
+	require.NotNil(t, policies.MaxLifetime.ExpiresAt)
+	assert.Equal(t, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC), policies.MaxLifetime.ExpiresAt.UTC())
+
+	template.Spec.MaxLifetimeAction = workspacev1alpha1.MaxLifetimeActionDelete
+	assert.Equal(t, workspacev1alpha1.MaxLifetimeActionDelete, TemplatePolicies(template).MaxLifetime.Action)
+	assert.Nil(t, TemplatePolicies(template).MaxLifetime.ExpiresAt)
+	assert.Nil(t, ResolvePolicies(workspace, nil))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Kinds of envFrom sources
+const (
+	EnvFromKindConfigMap = "ConfigMap"
+	EnvFromKindSecret    = "Secret"
+)
+
+// EnvFromSourceRef identifies the ConfigMap or Secret behind an envFrom source
+type EnvFromSourceRef struct {
+	Kind      string
+	Namespace string
+	Name      string
+	Optional  bool
+}
+
+// Mirrored returns true if the source lives outside the workspace namespace and must be mirrored into it
+func (r EnvFromSourceRef) Mirrored(workspaceNamespace string) bool {
+	return r.Namespace != workspaceNamespace
+}
+
+// String renders the source as Kind namespace/name
+func (r EnvFromSourceRef) String() string {
+	return r.Kind + " " + r.Namespace + "/" + r.Name
+}
+
+// ResolveEnvFromSource returns the ConfigMap or Secret an envFrom source of the workspace refers to
+func ResolveEnvFromSource(ws *workspacev1alpha1.Workspace, source workspacev1alpha1.EnvFromSource) EnvFromSourceRef {
+	namespace := source.SourceNamespace
+	if namespace == "" {
+		namespace = ws.Namespace
+	}
+	return envFromSourceRef(source.EnvFromSource, namespace)
+}
+
+// ResolveTemplateEnvFromSource returns the ConfigMap or Secret an envFrom source of the template refers to;
+// template sources live in the template namespace
+func ResolveTemplateEnvFromSource(template *workspacev1alpha1.WorkspaceTemplate, source corev1.EnvFromSource) EnvFromSourceRef {
+	return envFromSourceRef(source, template.Namespace)
+}
+
+func envFromSourceRef(source corev1.EnvFromSource, namespace string) EnvFromSourceRef {
+	ref := EnvFromSourceRef{Namespace: namespace}
+	switch {
+	case source.ConfigMapRef != nil:
+		ref.Kind = EnvFromKindConfigMap
+		ref.Name = source.ConfigMapRef.Name
+		ref.Optional = source.ConfigMapRef.Optional != nil && *source.ConfigMapRef.Optional
+	case source.SecretRef != nil:
+		ref.Kind = EnvFromKindSecret
+		ref.Name = source.SecretRef.Name
+		ref.Optional = source.SecretRef.Optional != nil && *source.SecretRef.Optional
+	}
+	return ref
+}
@@ -0,0 +1,89 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cacheContinueToken is the continue token the controller-runtime cache sets on every list it serves:
+// the cache holds every object in memory and does not continue lists
+const cacheContinueToken = "continue-not-supported"
+
+// ListPage lists one page of at most limit objects into list, every object when limit is zero, resuming
+// after the page of the continue token. List.Continue is the token of the next page, empty on the last one.
+// The informer cache cuts lists at the limit without a way to continue them, so a page served by the cache
+// holds every matching object and has no next page.
+func ListPage(
+	ctx context.Context,
+	reader client.Reader,
+	list client.ObjectList,
+	continueToken string,
+	limit int64,
+	opts ...client.ListOption) error {
+	pageOpts := slices.Clone(opts)
+	if limit > 0 {
+		pageOpts = append(pageOpts, client.Limit(limit))
+	}
+	if continueToken != "" {
+		pageOpts = append(pageOpts, client.Continue(continueToken))
+	}
+	if err := reader.List(ctx, list, pageOpts...); err != nil {
+		return err
+	}
+	if list.GetContinue() != cacheContinueToken {
+		return nil
+	}
+
+	if limit > 0 && apimeta.LenList(list) >= int(limit) {
+		// The cache may have cut the list: read it whole, it is in memory
+		if err := reader.List(ctx, list, opts...); err != nil {
+			return err
+		}
+	}
+	list.SetContinue("")
+	return nil
+}
+
+// ListAll lists every object matching the options into list, ordered by namespace then name. Lists served by
+// the API server are read in pages of WorkspacePageLimit objects; an error on any page fails the whole list,
+// so that callers never act on a partial view, e.g. when the continue token expired between two pages.
+func ListAll(ctx context.Context, reader client.Reader, list client.ObjectList, opts ...client.ListOption) error {
+	var items []runtime.Object
+	continueToken := ""
+	for {
+		if err := ListPage(ctx, reader, list, continueToken, WorkspacePageLimit, opts...); err != nil {
+			return err
+		}
+		page, err := apimeta.ExtractList(list)
+		if err != nil {
+			return fmt.Errorf("failed to read the listed objects: %w", err)
+		}
+		// The items point into the list, which the next page is decoded into
+		for _, item := range page {
+			items = append(items, item.DeepCopyObject())
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	slices.SortFunc(items, func(a, b runtime.Object) int {
+		first, second := a.(client.Object), b.(client.Object)
+		return cmp.Or(
+			cmp.Compare(first.GetNamespace(), second.GetNamespace()),
+			cmp.Compare(first.GetName(), second.GetName()))
+	})
+	return apimeta.SetList(list, items)
+}
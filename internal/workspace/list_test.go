@@ -0,0 +1,189 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// pagingClient serves workspace lists in pages, the way the API server or the cache does
+type pagingClient struct {
+	client.WithWatch
+	calls int
+}
+
+// newTestWorkspaces returns count workspaces using the template, spread over two namespaces, in reverse order
+func newTestWorkspaces(count int) []client.Object {
+	objs := make([]client.Object, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		objs = append(objs, &workspacev1alpha1.Workspace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("ws-%03d", i),
+				Namespace: fmt.Sprintf("team-%d", i%2),
+				Labels: map[string]string{
+					LabelWorkspaceTemplate:          "python",
+					LabelWorkspaceTemplateNamespace: "shared",
+				},
+			},
+			Spec: workspacev1alpha1.WorkspaceSpec{
+				TemplateRef: &workspacev1alpha1.TemplateRef{Name: "python", Namespace: "shared"},
+			},
+		})
+	}
+	return objs
+}
+
+// newPagingClient returns a client whose workspace lists are cut into pages by servePage, which receives
+// every matching workspace and the options of the call
+func newPagingClient(
+	t *testing.T,
+	servePage func(list *workspacev1alpha1.WorkspaceList, opts *client.ListOptions) error,
+	objs ...client.Object) *pagingClient {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	paging := &pagingClient{}
+	paging.WithWatch = interceptor.NewClient(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				paging.calls++
+				workspaces, ok := list.(*workspacev1alpha1.WorkspaceList)
+				if !ok {
+					return c.List(ctx, list, opts...)
+				}
+				listOpts := &client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				var selector []client.ListOption
+				if listOpts.LabelSelector != nil {
+					selector = append(selector, client.MatchingLabelsSelector{Selector: listOpts.LabelSelector})
+				}
+				if err := c.List(ctx, workspaces, selector...); err != nil {
+					return err
+				}
+				return servePage(workspaces, listOpts)
+			},
+		})
+	return paging
+}
+
+// serveAPIServerPage pages lists the way the API server does, ordered by key, continue tokens being item offsets
+func serveAPIServerPage(list *workspacev1alpha1.WorkspaceList, opts *client.ListOptions) error {
+	slices.SortFunc(list.Items, func(a, b workspacev1alpha1.Workspace) int {
+		return cmp.Compare(a.Namespace+"/"+a.Name, b.Namespace+"/"+b.Name)
+	})
+	start := 0
+	if opts.Continue != "" {
+		var err error
+		if start, err = strconv.Atoi(opts.Continue); err != nil {
+			return apierrors.NewBadRequest("invalid continue token")
+		}
+	}
+	end := len(list.Items)
+	if opts.Limit > 0 && start+int(opts.Limit) < end {
+		end = start + int(opts.Limit)
+		list.Continue = strconv.Itoa(end)
+	}
+	list.Items = list.Items[start:end]
+	return nil
+}
+
+// serveCachePage cuts lists at the limit without a way to continue them, the way the informer cache does
+func serveCachePage(list *workspacev1alpha1.WorkspaceList, opts *client.ListOptions) error {
+	if opts.Continue != "" {
+		return fmt.Errorf("continue list option is not supported by the cache")
+	}
+	if opts.Limit > 0 && int(opts.Limit) < len(list.Items) {
+		list.Items = list.Items[:opts.Limit]
+	}
+	list.Continue = cacheContinueToken
+	return nil
+}
+
+func assertSortedWorkspaces(t *testing.T, workspaces []workspacev1alpha1.Workspace) {
+	for i := 1; i < len(workspaces); i++ {
+		previous, current := workspaces[i-1], workspaces[i]
+		assert.True(t, previous.Namespace < current.Namespace ||
+			(previous.Namespace == current.Namespace && previous.Name < current.Name),
+			"%s/%s is listed before %s/%s", previous.Namespace, previous.Name, current.Namespace, current.Name)
+	}
+}
+
+func TestListAllReadsEveryPageOfTheAPIServer(t *testing.T) {
+	paging := newPagingClient(t, serveAPIServerPage, newTestWorkspaces(250)...)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	require.NoError(t, ListAll(context.Background(), paging, workspaces))
+	assert.Len(t, workspaces.Items, 250)
+	assert.Equal(t, 3, paging.calls, "250 workspaces are read in pages of 100")
+	assert.Empty(t, workspaces.Continue)
+	assertSortedWorkspaces(t, workspaces.Items)
+}
+
+func TestListAllReadsCacheListsWhole(t *testing.T) {
+	paging := newPagingClient(t, serveCachePage, newTestWorkspaces(150)...)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	require.NoError(t, ListAll(context.Background(), paging, workspaces))
+	assert.Len(t, workspaces.Items, 150, "the workspaces past the limit of the cache are listed too")
+	assert.Empty(t, workspaces.Continue)
+	assertSortedWorkspaces(t, workspaces.Items)
+}
+
+func TestListAllFailsWhenAPageFails(t *testing.T) {
+	expired := apierrors.NewResourceExpired("the continue token has expired")
+	paging := newPagingClient(t, func(list *workspacev1alpha1.WorkspaceList, opts *client.ListOptions) error {
+		if opts.Continue != "" {
+			return expired
+		}
+		return serveAPIServerPage(list, opts)
+	}, newTestWorkspaces(150)...)
+
+	workspaces := &workspacev1alpha1.WorkspaceList{}
+	err := ListAll(context.Background(), paging, workspaces)
+	assert.ErrorIs(t, err, expired)
+
+	// Usage checks fail rather than report the template unused from the first page only
+	inUse, err := HasActiveWorkspacesWithTemplate(context.Background(), paging, "python", "shared")
+	assert.Error(t, err)
+	assert.False(t, inUse)
+}
+
+func TestHasActiveWorkspacesReadsPastPagesOfDeletedWorkspaces(t *testing.T) {
+	objs := newTestWorkspaces(150)
+	// The first page only holds workspaces being deleted: the 75 of team-0 and the first 25 of team-1
+	for _, obj := range objs {
+		if obj.GetNamespace() == "team-0" || obj.GetName() < "ws-050" {
+			obj.SetFinalizers([]string{"workspace.jupyter.org/cleanup"})
+			obj.SetDeletionTimestamp(&metav1.Time{Time: metav1.Now().Time})
+		}
+	}
+	paging := newPagingClient(t, serveAPIServerPage, objs...)
+
+	inUse, err := HasActiveWorkspacesWithTemplate(context.Background(), paging, "python", "shared")
+	require.NoError(t, err)
+	assert.True(t, inUse)
+	assert.Equal(t, 2, paging.calls)
+
+	page, next, err := ListActiveWorkspacesByTemplate(context.Background(), paging, "python", "shared", "", 1)
+	require.NoError(t, err)
+	assert.Empty(t, page, "a limited page may only hold workspaces being deleted")
+	assert.NotEmpty(t, next)
+}
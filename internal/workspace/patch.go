@@ -0,0 +1,26 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"context"
+	"slices"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PatchChanges writes the changes made to obj since original was copied as a merge patch, instead of
+// a full update carrying every field of a possibly stale copy. Only the label and annotation keys that
+// changed are sent, so keys edited concurrently by users survive the write.
+// A merge patch replaces lists whole: when the finalizers changed, the patch carries the resource version
+// of original and conflicts rather than drop a finalizer added concurrently.
+func PatchChanges(ctx context.Context, k8sClient client.Client, obj, original client.Object) error {
+	patch := client.MergeFrom(original)
+	if !slices.Equal(original.GetFinalizers(), obj.GetFinalizers()) {
+		patch = client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+	}
+	return k8sClient.Patch(ctx, obj, patch)
+}
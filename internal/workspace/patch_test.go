@@ -0,0 +1,74 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newPatchTestClient returns a fake client holding a workspace, and the copy of it a controller read
+func newPatchTestClient(t *testing.T) (client.Client, *workspacev1alpha1.Workspace) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ws",
+			Namespace:  "default",
+			Labels:     map[string]string{"team": "data"},
+			Finalizers: []string{"workspace.jupyter.org/finalizer"},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace).Build()
+	read := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(workspace), read))
+	return k8sClient, read
+}
+
+// addUserLabel adds a label to the stored workspace, as a user editing it concurrently does
+func addUserLabel(t *testing.T, k8sClient client.Client, key, value string) {
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Name: "ws", Namespace: "default"}, stored))
+	patch := client.MergeFrom(stored.DeepCopy())
+	stored.Labels[key] = value
+	require.NoError(t, k8sClient.Patch(context.Background(), stored, patch))
+}
+
+func TestPatchChangesKeepsConcurrentLabelEdits(t *testing.T) {
+	ctx := context.Background()
+	k8sClient, workspace := newPatchTestClient(t)
+	addUserLabel(t, k8sClient, "owner", "alice")
+
+	original := workspace.DeepCopy()
+	workspace.Labels[LabelWorkspaceTemplateNamespace] = "shared"
+	delete(workspace.Labels, "team")
+	require.NoError(t, PatchChanges(ctx, k8sClient, workspace, original))
+
+	stored := &workspacev1alpha1.Workspace{}
+	require.NoError(t, k8sClient.Get(ctx, client.ObjectKeyFromObject(workspace), stored))
+	assert.Equal(t, map[string]string{"owner": "alice", LabelWorkspaceTemplateNamespace: "shared"}, stored.Labels)
+	assert.Equal(t, stored.Labels, workspace.Labels, "the workspace is refreshed from the response")
+}
+
+func TestPatchChangesOfFinalizersConflictWithConcurrentEdits(t *testing.T) {
+	ctx := context.Background()
+	k8sClient, workspace := newPatchTestClient(t)
+	addUserLabel(t, k8sClient, "owner", "alice")
+
+	original := workspace.DeepCopy()
+	workspace.Finalizers = nil
+	err := PatchChanges(ctx, k8sClient, workspace, original)
+	assert.True(t, apierrors.IsConflict(err), "a stale finalizer list must not replace the stored one")
+}
@@ -39,10 +39,11 @@ func GetAccessStrategyRefNamespace(ws *workspacev1alpha1.Workspace) string {
 // with eventual consistency guarantees. Filters out workspaces being deleted (DeletionTimestamp set).
 // Validates templateRef matches label to guard against drift.
 // If templateNamespace is empty, it acts as a wildcard (backwards compatible).
-// Supports pagination for large-scale deployments via continueToken and limit parameters.
+// Supports pagination for large-scale deployments via continueToken and limit parameters; see ListPage for
+// the pages served by the cache.
 func ListActiveWorkspacesByTemplate(
 	ctx context.Context,
-	k8sClient client.Client,
+	reader client.Reader,
 	templateName string,
 	templateNamespace string,
 	continueToken string,
@@ -57,19 +58,7 @@ func ListActiveWorkspacesByTemplate(
 		LabelWorkspaceTemplateNamespace: templateNamespace,
 	}
 
-	listOptions := []client.ListOption{
-		client.MatchingLabels(labels),
-	}
-
-	// Add pagination options if specified
-	if limit > 0 {
-		listOptions = append(listOptions, client.Limit(limit))
-	}
-	if continueToken != "" {
-		listOptions = append(listOptions, client.Continue(continueToken))
-	}
-
-	if err := k8sClient.List(ctx, workspaceList, listOptions...); err != nil {
+	if err := ListPage(ctx, reader, workspaceList, continueToken, limit, client.MatchingLabels(labels)); err != nil {
 		return nil, "", fmt.Errorf("failed to list workspaces by template label: %w", err)
 	}
 
@@ -125,9 +114,10 @@ func ListActiveWorkspacesByTemplate(
 }
 
 // HasActiveWorkspacesWithTemplate checks if any active (non-deleted) workspace uses the specified template.
-// Reads from controller-runtime's informer cache with eventual consistency guarantees.
+// Reads every page of the workspaces of the reader, the informer cache or the API server; fails rather than
+// answer from a partial list.
 // Returns true if at least one active workspace uses the template.
-func HasActiveWorkspacesWithTemplate(ctx context.Context, k8sClient client.Client, templateName string, templateNamespace string) (bool, error) {
+func HasActiveWorkspacesWithTemplate(ctx context.Context, reader client.Reader, templateName string, templateNamespace string) (bool, error) {
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
 
 	// Build label selector
@@ -136,7 +126,7 @@ func HasActiveWorkspacesWithTemplate(ctx context.Context, k8sClient client.Clien
 		LabelWorkspaceTemplateNamespace: templateNamespace,
 	}
 
-	if err := k8sClient.List(ctx, workspaceList, client.MatchingLabels(labels)); err != nil {
+	if err := ListAll(ctx, reader, workspaceList, client.MatchingLabels(labels)); err != nil {
 		return false, fmt.Errorf("failed to check workspaces by template label: %w", err)
 	}
 
@@ -158,11 +148,12 @@ func HasActiveWorkspacesWithTemplate(ctx context.Context, k8sClient client.Clien
 }
 
 // HasActiveWorkspacesWithAccessStrategy checks if any active (non-deleted) workspace uses the specified access strategy.
-// Reads from controller-runtime's informer cache with eventual consistency guarantees.
-// Returns true if at least one active workspace uses the template.
+// Reads every page of the workspaces of the reader, the informer cache or the API server; fails rather than
+// answer from a partial list.
+// Returns true if at least one active workspace uses the access strategy.
 func HasActiveWorkspacesWithAccessStrategy(
 	ctx context.Context,
-	k8sClient client.Client,
+	reader client.Reader,
 	accessStrategyName string,
 	accessStrategyNamespace string) (bool, error) {
 	workspaceList := &workspacev1alpha1.WorkspaceList{}
@@ -172,7 +163,7 @@ func HasActiveWorkspacesWithAccessStrategy(
 		LabelAccessStrategyNamespace: accessStrategyNamespace,
 	}
 
-	if err := k8sClient.List(ctx, workspaceList, client.MatchingLabels(labels)); err != nil {
+	if err := ListAll(ctx, reader, workspaceList, client.MatchingLabels(labels)); err != nil {
 		return false, fmt.Errorf("failed to check workspaces by access strategy label: %w", err)
 	}
 
@@ -195,11 +186,12 @@ func HasActiveWorkspacesWithAccessStrategy(
 // Reads from controller-runtime's informer cache (not direct API calls), providing efficient lookup
 // with eventual consistency guarantees. Filters out workspaces being deleted (DeletionTimestamp set).
 // Validates AccessStrategy reference matches label to guard against drift.
-// Supports pagination for large-scale deployments via continueToken and limit parameters.
+// Supports pagination for large-scale deployments via continueToken and limit parameters; see ListPage for
+// the pages served by the cache.
 // Returns list of workspaces, continuationToken and error.
 func ListActiveWorkspacesByAccessStrategy(
 	ctx context.Context,
-	k8sClient client.Client,
+	reader client.Reader,
 	accessStrategyName string,
 	accessStrategyNamespace string,
 	continueToken string,
@@ -213,19 +205,7 @@ func ListActiveWorkspacesByAccessStrategy(
 		LabelAccessStrategyNamespace: accessStrategyNamespace,
 	}
 
-	listOptions := []client.ListOption{
-		client.MatchingLabels(labels),
-	}
-
-	// Add pagination options if specified
-	if limit > 0 {
-		listOptions = append(listOptions, client.Limit(limit))
-	}
-	if continueToken != "" {
-		listOptions = append(listOptions, client.Continue(continueToken))
-	}
-
-	if err := k8sClient.List(ctx, workspaceList, listOptions...); err != nil {
+	if err := ListPage(ctx, reader, workspaceList, continueToken, limit, client.MatchingLabels(labels)); err != nil {
 		return nil, "", fmt.Errorf("failed to list workspaces by AccessStrategy label: %w", err)
 	}
 
@@ -309,3 +289,30 @@ func GetWorkspaceReconciliationRequestsForAccessStrategy(
 
 	return allRequests, nil
 }
+
+// GetWorkspaceReconciliationRequestsForTemplate retrieves all active workspaces using the specified WorkspaceTemplate.
+// This function retrieves all matching workspaces in a single call without using continuation tokens,
+// which is compatible with the controller-runtime cache client.
+// Returns a list of Workspace reconciliation requests.
+func GetWorkspaceReconciliationRequestsForTemplate(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateName string,
+	templateNamespace string) ([]reconcile.Request, error) {
+
+	workspaces, _, err := ListActiveWorkspacesByTemplate(ctx, k8sClient, templateName, templateNamespace, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces by template: %w", err)
+	}
+
+	requests := make([]reconcile.Request, 0, len(workspaces))
+	for _, ws := range workspaces {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      ws.Name,
+				Namespace: ws.Namespace,
+			},
+		})
+	}
+	return requests, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// Scope selects the workspaces an operator instance manages, so that several instances can share
+// a cluster. Workspaces outside the scope are left entirely to other instances: no defaulting,
+// no validation, no finalizer and no status write. A nil Scope manages every workspace.
+type Scope struct {
+	selector                 labels.Selector
+	templateNamespaces       []string
+	defaultTemplateNamespace string
+}
+
+// NewScope parses the workspace label selector and the template namespaces of an operator instance.
+// It returns nil when neither is set, i.e. when the instance manages every workspace.
+func NewScope(selector string, templateNamespaces []string, defaultTemplateNamespace string) (*Scope, error) {
+	if selector == "" && len(templateNamespaces) == 0 {
+		return nil, nil
+	}
+
+	parsed := labels.Everything()
+	if selector != "" {
+		var err error
+		if parsed, err = labels.Parse(selector); err != nil {
+			return nil, fmt.Errorf("invalid workspace selector %q: %w", selector, err)
+		}
+	}
+	return &Scope{
+		selector:                 parsed,
+		templateNamespaces:       templateNamespaces,
+		defaultTemplateNamespace: defaultTemplateNamespace,
+	}, nil
+}
+
+// Matches returns true if the operator instance manages the workspace. With template namespaces,
+// the workspace must reference a template in one of them; a reference without namespace counts
+// for the workspace namespace and for the default template namespace it can fall back to.
+func (s *Scope) Matches(ws *workspacev1alpha1.Workspace) bool {
+	if s == nil {
+		return true
+	}
+	if !s.MatchesLabels(ws.Labels) {
+		return false
+	}
+	if len(s.templateNamespaces) == 0 {
+		return true
+	}
+
+	ref := ws.Spec.TemplateRef
+	if ref == nil {
+		return false
+	}
+	if ref.Namespace != "" {
+		return slices.Contains(s.templateNamespaces, ref.Namespace)
+	}
+	return slices.Contains(s.templateNamespaces, ws.Namespace) ||
+		(s.defaultTemplateNamespace != "" && slices.Contains(s.templateNamespaces, s.defaultTemplateNamespace))
+}
+
+// MatchesLabels returns true if the labels match the workspace selector of the scope. Workspace
+// pods carry the labels of their workspace, so their events can be filtered without a lookup.
+func (s *Scope) MatchesLabels(objLabels map[string]string) bool {
+	return s == nil || s.selector.Matches(labels.Set(objLabels))
+}
+
+// String describes the scope for logs
+func (s *Scope) String() string {
+	if s == nil {
+		return "all workspaces"
+	}
+	return fmt.Sprintf("selector=%q templateNamespaces=%v", s.selector.String(), s.templateNamespaces)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"testing"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newScopeTestWorkspace(labels map[string]string, ref *workspacev1alpha1.TemplateRef) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a", Labels: labels},
+		Spec:       workspacev1alpha1.WorkspaceSpec{TemplateRef: ref},
+	}
+}
+
+func TestNewScopeWithoutRestrictionManagesEverything(t *testing.T) {
+	scope, err := NewScope("", nil, "shared")
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+	assert.True(t, scope.Matches(newScopeTestWorkspace(nil, nil)))
+	assert.True(t, scope.MatchesLabels(nil))
+}
+
+func TestNewScopeRejectsInvalidSelector(t *testing.T) {
+	_, err := NewScope("team in (", nil, "")
+	assert.Error(t, err)
+}
+
+func TestScopeMatchesSelector(t *testing.T) {
+	scope, err := NewScope("team=ml", nil, "")
+	require.NoError(t, err)
+
+	assert.True(t, scope.Matches(newScopeTestWorkspace(map[string]string{"team": "ml"}, nil)))
+	assert.False(t, scope.Matches(newScopeTestWorkspace(map[string]string{"team": "web"}, nil)))
+	assert.False(t, scope.Matches(newScopeTestWorkspace(nil, nil)))
+}
+
+func TestScopeMatchesTemplateNamespaces(t *testing.T) {
+	scope, err := NewScope("", []string{"ml-templates"}, "")
+	require.NoError(t, err)
+
+	assert.True(t, scope.Matches(newScopeTestWorkspace(nil, &workspacev1alpha1.TemplateRef{Name: "t", Namespace: "ml-templates"})))
+	assert.False(t, scope.Matches(newScopeTestWorkspace(nil, &workspacev1alpha1.TemplateRef{Name: "t", Namespace: "other"})))
+	assert.False(t, scope.Matches(newScopeTestWorkspace(nil, &workspacev1alpha1.TemplateRef{Name: "t"})),
+		"a reference without namespace resolves in the workspace namespace")
+	assert.False(t, scope.Matches(newScopeTestWorkspace(nil, nil)), "no template to resolve")
+}
+
+func TestScopeMatchesReferenceWithoutNamespace(t *testing.T) {
+	workspaceNamespace, err := NewScope("", []string{"team-a"}, "")
+	require.NoError(t, err)
+	assert.True(t, workspaceNamespace.Matches(newScopeTestWorkspace(nil, &workspacev1alpha1.TemplateRef{Name: "t"})))
+
+	defaultNamespace, err := NewScope("", []string{"shared"}, "shared")
+	require.NoError(t, err)
+	assert.True(t, defaultNamespace.Matches(newScopeTestWorkspace(nil, &workspacev1alpha1.TemplateRef{Name: "t"})))
+}
+
+func TestScopeRequiresSelectorAndTemplateNamespace(t *testing.T) {
+	scope, err := NewScope("team=ml", []string{"ml-templates"}, "")
+	require.NoError(t, err)
+	ref := &workspacev1alpha1.TemplateRef{Name: "t", Namespace: "ml-templates"}
+
+	assert.True(t, scope.Matches(newScopeTestWorkspace(map[string]string{"team": "ml"}, ref)))
+	assert.False(t, scope.Matches(newScopeTestWorkspace(nil, ref)))
+	assert.False(t, scope.MatchesLabels(map[string]string{"team": "web"}))
+}
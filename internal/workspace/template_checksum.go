@@ -0,0 +1,48 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// TemplateChecksumPrefix prefixes template checksums with the hash function computing them
+const TemplateChecksumPrefix = "sha256:"
+
+// TemplateSpecChecksum returns the content hash of a template spec. The spec is encoded with its object
+// keys sorted, so that the hash only changes with the content: not with the order fields are declared or
+// were written in, nor between a template and the stored revision of the same generation.
+// Example workspaces do not configure workspaces and are left out.
+func TemplateSpecChecksum(spec *workspacev1alpha1.WorkspaceTemplateSpec) (string, error) {
+	if len(spec.ExampleWorkspaces) > 0 {
+		spec = spec.DeepCopy()
+		spec.ExampleWorkspaces = nil
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode template spec: %w", err)
+	}
+
+	// Maps are encoded with sorted keys; numbers are kept as written
+	var canonical interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&canonical); err != nil {
+		return "", fmt.Errorf("failed to decode template spec: %w", err)
+	}
+	if data, err = json.Marshal(canonical); err != nil {
+		return "", fmt.Errorf("failed to encode template spec: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return TemplateChecksumPrefix + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func checksumOf(t *testing.T, spec *workspacev1alpha1.WorkspaceTemplateSpec) string {
+	checksum, err := TemplateSpecChecksum(spec)
+	require.NoError(t, err)
+	return checksum
+}
+
+func decodeTemplateSpec(t *testing.T, data string) *workspacev1alpha1.WorkspaceTemplateSpec {
+	spec := &workspacev1alpha1.WorkspaceTemplateSpec{}
+	require.NoError(t, json.Unmarshal([]byte(data), spec))
+	return spec
+}
+
+func TestTemplateSpecChecksumIsStableAcrossFieldOrder(t *testing.T) {
+	first := decodeTemplateSpec(t, `{
+		"displayName": "Base",
+		"defaultImage": "jupyter/base-notebook:2025.01",
+		"defaultNodeSelector": {"pool": "cpu", "zone": "a"},
+		"defaultResources": {"requests": {"cpu": "500m", "memory": "1Gi"}}
+	}`)
+	second := decodeTemplateSpec(t, `{
+		"defaultResources": {"requests": {"memory": "1Gi", "cpu": "500m"}},
+		"defaultNodeSelector": {"zone": "a", "pool": "cpu"},
+		"defaultImage": "jupyter/base-notebook:2025.01",
+		"displayName": "Base"
+	}`)
+
+	assert.Equal(t, checksumOf(t, first), checksumOf(t, second))
+}
+
+func TestTemplateSpecChecksumIsStableAcrossEquivalentValues(t *testing.T) {
+	first := &workspacev1alpha1.WorkspaceTemplateSpec{
+		DisplayName: "Base",
+		DefaultResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1024Mi")},
+		},
+	}
+	second := &workspacev1alpha1.WorkspaceTemplateSpec{
+		DisplayName: "Base",
+		DefaultResources: &corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		},
+		AllowedImages: []string{},
+	}
+
+	assert.Equal(t, checksumOf(t, first), checksumOf(t, second))
+}
+
+func TestTemplateSpecChecksumMatchesStoredRevision(t *testing.T) {
+	template := newRevisionTestTemplate(4, "jupyter/base-notebook:2025.01")
+	template.Spec.DefaultNodeSelector = map[string]string{"zone": "a", "pool": "cpu"}
+	revision, err := NewTemplateRevision(template)
+	require.NoError(t, err)
+
+	stored := decodeTemplateSpec(t, string(revision.Data.Raw))
+	assert.Equal(t, checksumOf(t, &template.Spec), checksumOf(t, stored))
+}
+
+func TestTemplateSpecChecksumChangesWithContent(t *testing.T) {
+	spec := &workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Base", DefaultImage: "jupyter/base-notebook:2025.01"}
+	original := checksumOf(t, spec)
+	assert.True(t, strings.HasPrefix(original, TemplateChecksumPrefix))
+	assert.Len(t, original, len(TemplateChecksumPrefix)+64)
+
+	spec.DefaultImage = "registry.example.com/base-notebook:2025.01"
+	assert.NotEqual(t, original, checksumOf(t, spec))
+
+	spec.DefaultImage = "jupyter/base-notebook:2025.01"
+	spec.AllowedImages = []string{"jupyter/base-notebook:2025.01"}
+	assert.NotEqual(t, original, checksumOf(t, spec))
+}
+
+func TestTemplateSpecChecksumIgnoresExampleWorkspaces(t *testing.T) {
+	spec := &workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Base", DefaultImage: "jupyter/base-notebook:2025.01"}
+	original := checksumOf(t, spec)
+
+	spec.ExampleWorkspaces = []workspacev1alpha1.TemplateExampleWorkspace{
+		{Name: "small", Spec: workspacev1alpha1.WorkspaceSpec{DisplayName: "Small"}},
+	}
+	assert.Equal(t, original, checksumOf(t, spec))
+	assert.Len(t, spec.ExampleWorkspaces, 1)
+}
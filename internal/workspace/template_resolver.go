@@ -33,36 +33,94 @@ func NewTemplateResolver(k8sClient client.Client, defaultTemplateNamespace strin
 // 1. Try templateRef.namespace (if specified)
 // 2. Try workspace.namespace (if templateRef.namespace empty)
 // 3. Try defaultTemplateNamespace (if configured and previous failed)
+//
+// The lookups for all tiers are issued concurrently so that a miss in one tier does not
+// add a full round trip to admission latency. Results are still consumed in priority order,
+// so the outcome never depends on which lookup returns first.
 func (tr *TemplateResolver) ResolveTemplate(ctx context.Context, templateRef *workspacev1alpha1.TemplateRef, workspaceNamespace string) (*workspacev1alpha1.WorkspaceTemplate, error) {
 	if templateRef == nil {
 		return nil, fmt.Errorf("templateRef is nil")
 	}
 
-	// Determine template namespace using fallback logic
-	templateNamespace := templateRef.Namespace
-	if templateNamespace == "" {
-		templateNamespace = workspaceNamespace
+	namespaces := tr.lookupNamespaces(templateRef, workspaceNamespace)
+
+	// Cancel the lower-priority lookups once a higher-priority tier decides the outcome
+	lookupCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan templateLookup, len(namespaces))
+	for i, namespace := range namespaces {
+		results[i] = make(chan templateLookup, 1)
+		go func() {
+			template := &workspacev1alpha1.WorkspaceTemplate{}
+			err := tr.client.Get(lookupCtx, client.ObjectKey{Name: templateRef.Name, Namespace: namespace}, template)
+			results[i] <- templateLookup{template: template, err: err}
+		}()
 	}
 
-	// Try to get template from determined namespace
-	template := &workspacev1alpha1.WorkspaceTemplate{}
-	templateKey := client.ObjectKey{Name: templateRef.Name, Namespace: templateNamespace}
-	err := tr.client.Get(ctx, templateKey, template)
-
-	// If not found and we have a default namespace, try there
-	if apierrors.IsNotFound(err) && tr.defaultTemplateNamespace != "" && templateNamespace != tr.defaultTemplateNamespace {
-		templateKey = client.ObjectKey{Name: templateRef.Name, Namespace: tr.defaultTemplateNamespace}
-		if fallbackErr := tr.client.Get(ctx, templateKey, template); fallbackErr == nil {
-			return template, nil
-		} else {
-			return nil, fmt.Errorf("failed to get template %s from namespace %s or fallback namespace %s: %w", templateRef.Name, templateNamespace, tr.defaultTemplateNamespace, fallbackErr)
+	for i, namespace := range namespaces {
+		lookup := <-results[i]
+		if lookup.err == nil {
+			return lookup.template, nil
+		}
+
+		// Only a NotFound moves resolution on to the next tier
+		if apierrors.IsNotFound(lookup.err) {
+			if i < len(namespaces)-1 {
+				continue
+			}
+			return nil, &TemplateNotFoundError{Name: templateRef.Name, Namespaces: namespaces, err: lookup.err}
 		}
+		if i == 0 {
+			return nil, fmt.Errorf("failed to get template %s: %w", templateRef.Name, lookup.err)
+		}
+		return nil, fmt.Errorf("failed to get template %s from namespace %s or fallback namespace %s: %w", templateRef.Name, namespaces[0], namespace, lookup.err)
+	}
+	return nil, fmt.Errorf("failed to get template %s: no namespace to look up", templateRef.Name)
+}
+
+// TemplateNotFoundError reports a template found in none of the namespaces it was looked up in.
+// It unwraps to the NotFound error of the last lookup.
+type TemplateNotFoundError struct {
+	// Name of the template
+	Name string
+
+	// Namespaces the template was looked up in, highest priority first
+	Namespaces []string
+
+	err error
+}
+
+func (e *TemplateNotFoundError) Error() string {
+	if len(e.Namespaces) == 1 {
+		return fmt.Sprintf("failed to get template %s: %v", e.Name, e.err)
+	}
+	return fmt.Sprintf("failed to get template %s from namespace %s or fallback namespace %s: %v",
+		e.Name, e.Namespaces[0], e.Namespaces[len(e.Namespaces)-1], e.err)
+}
+
+func (e *TemplateNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// templateLookup holds the outcome of looking up a template in one namespace
+type templateLookup struct {
+	template *workspacev1alpha1.WorkspaceTemplate
+	err      error
+}
+
+// lookupNamespaces returns the namespaces to look the template up in, highest priority first
+func (tr *TemplateResolver) lookupNamespaces(templateRef *workspacev1alpha1.TemplateRef, workspaceNamespace string) []string {
+	templateNamespace := templateRef.Namespace
+	if templateNamespace == "" {
+		templateNamespace = workspaceNamespace
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get template %s: %w", templateRef.Name, err)
+	namespaces := []string{templateNamespace}
+	if tr.defaultTemplateNamespace != "" && templateNamespace != tr.defaultTemplateNamespace {
+		namespaces = append(namespaces, tr.defaultTemplateNamespace)
 	}
-	return template, nil
+	return namespaces
 }
 
 // ResolveTemplateForWorkspace convenience method that extracts templateRef and namespace from workspace
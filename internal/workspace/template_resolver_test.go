@@ -7,7 +7,10 @@ package workspace
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,6 +47,7 @@ func TestResolveTemplate(t *testing.T) {
 		expectedNamespace        string
 		expectError              bool
 		errorContains            string
+		expectNamespacesTried    []string
 	}{
 		{
 			name:          "nil templateRef",
@@ -133,17 +137,17 @@ func TestResolveTemplate(t *testing.T) {
 			defaultTemplateNamespace: "default-ns",
 			existingTemplates:        []client.Object{},
 			expectError:              true,
-			errorContains:            "failed to get template missing-template",
+			expectNamespacesTried:    []string{"workspace-ns", "default-ns"},
 		},
 		{
 			name: "no fallback when no default namespace configured",
 			templateRef: &workspacev1alpha1.TemplateRef{
 				Name: "test-template",
 			},
-			workspaceNamespace: "workspace-ns",
-			existingTemplates:  []client.Object{},
-			expectError:        true,
-			errorContains:      "failed to get template test-template",
+			workspaceNamespace:    "workspace-ns",
+			existingTemplates:     []client.Object{},
+			expectError:           true,
+			expectNamespacesTried: []string{"workspace-ns"},
 		},
 		{
 			name: "non-NotFound error should be lifted up without fallback",
@@ -184,6 +188,15 @@ func TestResolveTemplate(t *testing.T) {
 				if tt.errorContains != "" {
 					assert.Contains(t, err.Error(), tt.errorContains)
 				}
+				var notFound *TemplateNotFoundError
+				if tt.expectNamespacesTried != nil {
+					require.ErrorAs(t, err, &notFound)
+					assert.Equal(t, tt.templateRef.Name, notFound.Name)
+					assert.Equal(t, tt.expectNamespacesTried, notFound.Namespaces)
+					assert.True(t, apierrors.IsNotFound(err), "the NotFound of the last lookup is kept")
+				} else {
+					assert.False(t, errors.As(err, &notFound))
+				}
 				assert.Nil(t, template)
 			} else {
 				assert.NoError(t, err)
@@ -218,6 +231,103 @@ func TestResolveTemplate_NonNotFoundError(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to get template test-template")
 }
 
+// delayedClient answers template lookups after a per-namespace delay, so tests can make
+// lower-priority tiers respond before higher-priority ones
+type delayedClient struct {
+	client.Client
+	delays map[string]time.Duration
+	calls  atomic.Int32
+}
+
+func (d *delayedClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	d.calls.Add(1)
+	select {
+	case <-time.After(d.delays[key.Namespace]):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return d.Client.Get(ctx, key, obj, opts...)
+}
+
+func TestResolveTemplate_PriorityOrderIndependentOfResponseOrder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+
+	tests := []struct {
+		name              string
+		existingTemplates []client.Object
+		expectedNamespace string
+		expectError       bool
+		errorContains     string
+	}{
+		{
+			name: "slow primary hit wins over fast fallback hit",
+			existingTemplates: []client.Object{
+				&workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "workspace-ns"}},
+				&workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default-ns"}},
+			},
+			expectedNamespace: "workspace-ns",
+		},
+		{
+			name: "slow primary miss falls back to fast fallback hit",
+			existingTemplates: []client.Object{
+				&workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default-ns"}},
+			},
+			expectedNamespace: "default-ns",
+		},
+		{
+			name:          "miss in every tier reports both namespaces",
+			expectError:   true,
+			errorContains: "from namespace workspace-ns or fallback namespace default-ns",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := &delayedClient{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.existingTemplates...).Build(),
+				delays: map[string]time.Duration{"workspace-ns": 20 * time.Millisecond},
+			}
+			resolver := NewTemplateResolver(k8sClient, "default-ns")
+
+			for range 5 {
+				template, err := resolver.ResolveTemplate(context.Background(), &workspacev1alpha1.TemplateRef{Name: "test-template"}, "workspace-ns")
+				if tt.expectError {
+					assert.Error(t, err)
+					assert.Contains(t, err.Error(), tt.errorContains)
+					assert.Nil(t, template)
+					continue
+				}
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedNamespace, template.Namespace)
+			}
+		})
+	}
+}
+
+func TestResolveTemplate_LookupsRunConcurrently(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+
+	delay := 100 * time.Millisecond
+	k8sClient := &delayedClient{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&workspacev1alpha1.WorkspaceTemplate{ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default-ns"}},
+		).Build(),
+		delays: map[string]time.Duration{"workspace-ns": delay, "default-ns": delay},
+	}
+	resolver := NewTemplateResolver(k8sClient, "default-ns")
+
+	start := time.Now()
+	template, err := resolver.ResolveTemplate(context.Background(), &workspacev1alpha1.TemplateRef{Name: "test-template"}, "workspace-ns")
+	elapsed := time.Since(start)
+
+	require.NoError(t, err)
+	assert.Equal(t, "default-ns", template.Namespace)
+	assert.Equal(t, int32(2), k8sClient.calls.Load())
+	assert.Less(t, elapsed, 2*delay, "tier lookups should not be serialized")
+}
+
 func TestResolveTemplateForWorkspace(t *testing.T) {
 	scheme := runtime.NewScheme()
 	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
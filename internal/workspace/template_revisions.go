@@ -0,0 +1,93 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+// TemplateRevisionName returns the name of the ControllerRevision holding a generation of a template
+func TemplateRevisionName(templateName string, generation int64) string {
+	return fmt.Sprintf("%s-%d", templateName, generation)
+}
+
+// NewTemplateRevision returns a ControllerRevision holding the spec of the current generation of the template,
+// controlled by the template
+func NewTemplateRevision(template *workspacev1alpha1.WorkspaceTemplate) (*appsv1.ControllerRevision, error) {
+	data, err := json.Marshal(template.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec of template %s: %w", template.Name, err)
+	}
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TemplateRevisionName(template.Name, template.Generation),
+			Namespace: template.Namespace,
+			Labels:    map[string]string{LabelWorkspaceTemplate: template.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(template, workspacev1alpha1.GroupVersion.WithKind("WorkspaceTemplate")),
+			},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: template.Generation,
+	}, nil
+}
+
+// AdmittedTemplateGeneration returns the template generation the webhook admitted the workspace against,
+// 0 when it was not recorded
+func AdmittedTemplateGeneration(workspace *workspacev1alpha1.Workspace) int64 {
+	generation, err := strconv.ParseInt(workspace.Annotations[AnnotationTemplateGeneration], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return generation
+}
+
+// ResolveTemplateRevision resolves the template of the workspace at the generation the webhook admitted
+// the workspace against, so that template changes made after admission do not apply until the workspace
+// is admitted again. The current template is returned when no generation was recorded, or when the
+// revision is no longer kept, including when the revision of that name was left by a deleted template of the same
+// name; the generation of the returned template tells which revision it holds.
+func (tr *TemplateResolver) ResolveTemplateRevision(ctx context.Context, workspace *workspacev1alpha1.Workspace) (*workspacev1alpha1.WorkspaceTemplate, error) {
+	template, err := tr.ResolveTemplate(ctx, workspace.Spec.TemplateRef, workspace.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	generation := AdmittedTemplateGeneration(workspace)
+	if generation == 0 || generation == template.Generation {
+		return template, nil
+	}
+
+	revision := &appsv1.ControllerRevision{}
+	key := client.ObjectKey{Name: TemplateRevisionName(template.Name, generation), Namespace: template.Namespace}
+	if err := tr.client.Get(ctx, key, revision); err != nil {
+		if apierrors.IsNotFound(err) {
+			return template, nil
+		}
+		return nil, fmt.Errorf("failed to get revision %d of template %s: %w", generation, template.Name, err)
+	}
+	if !metav1.IsControlledBy(revision, template) {
+		return template, nil
+	}
+
+	admitted := template.DeepCopy()
+	admitted.Spec = workspacev1alpha1.WorkspaceTemplateSpec{}
+	if err := json.Unmarshal(revision.Data.Raw, &admitted.Spec); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %d of template %s: %w", generation, template.Name, err)
+	}
+	admitted.Generation = revision.Revision
+	return admitted, nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package workspace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+)
+
+func newRevisionTestResolver(t *testing.T, objects ...client.Object) *TemplateResolver {
+	scheme := runtime.NewScheme()
+	require.NoError(t, workspacev1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	return NewTemplateResolver(fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(), "")
+}
+
+func newRevisionTestTemplate(generation int64, image string) *workspacev1alpha1.WorkspaceTemplate {
+	return &workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Namespace: "team-a", Generation: generation, UID: "base-uid"},
+		Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Base", DefaultImage: image},
+	}
+}
+
+func newRevisionTestWorkspace(annotations map[string]string) *workspacev1alpha1.Workspace {
+	return &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a", Annotations: annotations},
+		Spec:       workspacev1alpha1.WorkspaceSpec{TemplateRef: &workspacev1alpha1.TemplateRef{Name: "base"}},
+	}
+}
+
+func TestResolveTemplateRevisionReturnsAdmittedGeneration(t *testing.T) {
+	revision, err := NewTemplateRevision(newRevisionTestTemplate(1, "jupyter/base-notebook:2024.01"))
+	require.NoError(t, err)
+	assert.Equal(t, "base-1", revision.Name)
+	resolver := newRevisionTestResolver(t, newRevisionTestTemplate(2, "jupyter/base-notebook:2025.01"), revision)
+
+	template, err := resolver.ResolveTemplateRevision(context.Background(),
+		newRevisionTestWorkspace(map[string]string{AnnotationTemplateGeneration: "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), template.Generation)
+	assert.Equal(t, "jupyter/base-notebook:2024.01", template.Spec.DefaultImage)
+	assert.Equal(t, "base", template.Name)
+}
+
+func TestResolveTemplateRevisionFallsBackToCurrentTemplate(t *testing.T) {
+	resolver := newRevisionTestResolver(t, newRevisionTestTemplate(2, "jupyter/base-notebook:2025.01"))
+
+	// No generation recorded
+	template, err := resolver.ResolveTemplateRevision(context.Background(), newRevisionTestWorkspace(nil))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), template.Generation)
+
+	// Revision no longer kept
+	template, err = resolver.ResolveTemplateRevision(context.Background(),
+		newRevisionTestWorkspace(map[string]string{AnnotationTemplateGeneration: "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), template.Generation)
+	assert.Equal(t, "jupyter/base-notebook:2025.01", template.Spec.DefaultImage)
+}
+
+func TestResolveTemplateRevisionIgnoresRevisionsOfADeletedTemplate(t *testing.T) {
+	// The template was deleted and created again under the same name before its revisions were collected
+	deleted := newRevisionTestTemplate(1, "jupyter/base-notebook:2024.01")
+	deleted.UID = "deleted-uid"
+	revision, err := NewTemplateRevision(deleted)
+	require.NoError(t, err)
+	resolver := newRevisionTestResolver(t, newRevisionTestTemplate(2, "jupyter/base-notebook:2025.01"), revision)
+
+	template, err := resolver.ResolveTemplateRevision(context.Background(),
+		newRevisionTestWorkspace(map[string]string{AnnotationTemplateGeneration: "1"}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), template.Generation)
+	assert.Equal(t, "jupyter/base-notebook:2025.01", template.Spec.DefaultImage)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	applyv1alpha1 "github.com/jupyter-infra/jupyter-k8s/pkg/client/applyconfiguration/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/client/clientset/versioned/fake"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/client/informers/externalversions"
+)
+
+func TestClientsetAppliesWorkspaces(t *testing.T) {
+	ctx := context.Background()
+	// NewClientset manages fields with the OpenAPI schema of the types, which the apply configurations
+	// of pkg/client are generated without; a real API server serves the schema of the CRDs
+	clientset := fake.NewSimpleClientset(&workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "team-a"},
+		Spec:       workspacev1alpha1.WorkspaceSpec{DisplayName: "Workspace"},
+	})
+
+	workspace := applyv1alpha1.Workspace("ws", "team-a").
+		WithSpec(applyv1alpha1.WorkspaceSpec().WithDesiredStatus("Stopped"))
+	_, err := clientset.WorkspaceV1alpha1().Workspaces("team-a").Apply(ctx, workspace,
+		metav1.ApplyOptions{FieldManager: "test", Force: true})
+	require.NoError(t, err)
+
+	applied, err := clientset.WorkspaceV1alpha1().Workspaces("team-a").Get(ctx, "ws", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Stopped", applied.Spec.DesiredStatus)
+}
+
+func TestInformersListTemplates(t *testing.T) {
+	clientset := fake.NewClientset(&workspacev1alpha1.WorkspaceTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "python", Namespace: "jupyter-k8s-shared"},
+		Spec:       workspacev1alpha1.WorkspaceTemplateSpec{DisplayName: "Python"},
+	})
+
+	factory := externalversions.NewSharedInformerFactory(clientset, 0)
+	informer := factory.Workspace().V1alpha1().WorkspaceTemplates()
+	synced := informer.Informer().HasSynced
+	// The informers stop before the factory shuts down
+	defer factory.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	factory.Start(ctx.Done())
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), synced))
+
+	template, err := informer.Lister().WorkspaceTemplates("jupyter-k8s-shared").Get("python")
+	require.NoError(t, err)
+	assert.Equal(t, "Python", template.Spec.DisplayName)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Command list-workspaces shows how to use the typed client of the workspace API: it lists the
+// workspaces of a namespace through an informer, optionally stops one with a server-side apply,
+// then prints workspace changes until interrupted.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	applyv1alpha1 "github.com/jupyter-infra/jupyter-k8s/pkg/client/applyconfiguration/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/client/clientset/versioned"
+	"github.com/jupyter-infra/jupyter-k8s/pkg/client/informers/externalversions"
+)
+
+// fieldManager identifies the changes of this program in the managed fields of the workspaces
+const fieldManager = "list-workspaces-example"
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", clientcmd.RecommendedHomeFile, "Path to the kubeconfig file")
+	namespace := flag.String("namespace", "default", "Namespace of the workspaces")
+	stop := flag.String("stop", "", "Name of a workspace to stop with a server-side apply")
+	flag.Parse()
+
+	if err := run(*kubeconfig, *namespace, *stop); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(kubeconfig, namespace, stop string) error {
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	clientset, err := versioned.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create clientset: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if stop != "" {
+		// Only the fields set in the apply configuration are owned by this field manager
+		workspace := applyv1alpha1.Workspace(stop, namespace).
+			WithSpec(applyv1alpha1.WorkspaceSpec().WithDesiredStatus("Stopped"))
+		if _, err := clientset.WorkspaceV1alpha1().Workspaces(namespace).Apply(ctx, workspace,
+			metav1.ApplyOptions{FieldManager: fieldManager}); err != nil {
+			return fmt.Errorf("failed to stop workspace %s: %w", stop, err)
+		}
+	}
+
+	factory := externalversions.NewSharedInformerFactoryWithOptions(clientset, 10*time.Minute,
+		externalversions.WithNamespace(namespace))
+	informer := factory.Workspace().V1alpha1().Workspaces()
+	if _, err := informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(_, obj any) { printWorkspace("updated", obj.(*workspacev1alpha1.Workspace)) },
+		DeleteFunc: func(obj any) {
+			if workspace, ok := obj.(*workspacev1alpha1.Workspace); ok {
+				printWorkspace("deleted", workspace)
+			}
+		},
+	}); err != nil {
+		return fmt.Errorf("failed to watch workspaces: %w", err)
+	}
+	factory.Start(ctx.Done())
+	// The informers stop with the context, before the factory shuts down
+	defer factory.Shutdown()
+	defer cancel()
+	for informerType, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return fmt.Errorf("failed to sync the informer of %v", informerType)
+		}
+	}
+
+	workspaces, err := informer.Lister().Workspaces(namespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	for _, workspace := range workspaces {
+		printWorkspace("found", workspace)
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func printWorkspace(change string, workspace *workspacev1alpha1.Workspace) {
+	desiredStatus := workspace.Spec.DesiredStatus
+	if desiredStatus == "" {
+		desiredStatus = "Running"
+	}
+	fmt.Printf("%s\t%s\tdesired=%s\turl=%s\n", change, workspace.Name, desiredStatus, workspace.Status.AccessURL)
+}
@@ -37,6 +37,8 @@ const (
 	ConditionTypeDegraded    = "Degraded"
 	ConditionTypeAvailable   = "Available"
 	ConditionTypeStopped     = "Stopped"
+	ConditionTypePaused      = "Paused"
+	ConditionTypeReady       = "Ready"
 )
 
 // WorkspaceLabelName is the label key used to identify workspace resources
@@ -66,6 +66,15 @@ func createPvcForTest(pvcFilename, groupDir, subgroupDir string) {
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 }
 
+// createConfigMapForTest creates a ConfigMap from a YAML file
+func createConfigMapForTest(filename, groupDir, subgroupDir string) {
+	ginkgo.GinkgoHelper()
+	path := BuildTestResourcePath(filename, groupDir, subgroupDir)
+	cmd := exec.Command("kubectl", "apply", "-f", path)
+	_, err := utils.Run(cmd)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+}
+
 // WaitForPVCBinding waits for a PVC to be bound
 func WaitForPVCBinding(pvcName, namespace string) {
 	ginkgo.GinkgoHelper()
@@ -235,3 +244,30 @@ func VerifyHomeVolumeDataPersisted(workspaceName, namespace string) {
 	}, 60*time.Second, 2*time.Second).Should(gomega.Succeed(),
 		fmt.Sprintf("Failed to verify persisted file %s after retries", filepath))
 }
+
+// VerifyPodFileExists verifies that a file exists in the workspace container, retrying transient exec failures.
+//
+// No-op when using Finch (known cgroup exec issues in Kind).
+func VerifyPodFileExists(workspaceName, namespace, filepath string) {
+	ginkgo.GinkgoHelper()
+	if isUsingFinch() {
+		ginkgo.By("skipping exec-based file check (Finch has known cgroup access issues)")
+		return
+	}
+
+	podSelector := fmt.Sprintf("%s=%s", WorkspaceLabelName, workspaceName)
+	podName, podErr := kubectlGetByLabels("pod", podSelector, namespace, "{.items[*].metadata.name}")
+	gomega.Expect(podErr).NotTo(gomega.HaveOccurred())
+	gomega.Expect(podName).NotTo(gomega.BeEmpty())
+
+	WaitForWorkspacePodToBeReady(podName, namespace)
+
+	gomega.Eventually(func() error {
+		checkCmd := exec.Command("kubectl", "exec", podName, "-n", namespace, "--", "ls", filepath)
+		if _, checkErr := utils.Run(checkCmd); checkErr != nil {
+			return fmt.Errorf("failed to list file %s: %w", filepath, checkErr)
+		}
+		return nil
+	}, 60*time.Second, 2*time.Second).Should(gomega.Succeed(),
+		fmt.Sprintf("Failed to verify file %s exists after retries", filepath))
+}
@@ -9,14 +9,25 @@ Distributed under the terms of the MIT license
 package e2e
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/jupyter-infra/jupyter-k8s/test/utils"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/yaml"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/test/utils"
 )
 
 // WaitForWorkspaceToReachCondition polls a Workspace.status till a condition reaches the expected status
@@ -104,6 +115,34 @@ func VerifyCreateWorkspaceRejectedByWebhook(
 	gomega.Expect(output).To(gomega.BeEmpty(), "Workspace should not exist after webhook rejection")
 }
 
+// GetCreateWorkspaceRejection creates the workspace of a test resource file through the API and returns the
+// status the admission webhooks reject it with, so that specs match on its reason and causes rather than on
+// the wording of its message
+func GetCreateWorkspaceRejection(filename string, group string, subgroup string) metav1.Status {
+	ginkgo.GinkgoHelper()
+
+	projectDir, err := utils.GetProjectDir()
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	data, err := os.ReadFile(filepath.Join(projectDir, BuildTestResourcePath(filename, group, subgroup)))
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	workspace := &workspacev1alpha1.Workspace{}
+	gomega.Expect(yaml.Unmarshal(data, workspace)).To(gomega.Succeed())
+
+	scheme := runtime.NewScheme()
+	gomega.Expect(workspacev1alpha1.AddToScheme(scheme)).To(gomega.Succeed())
+	cfg, err := config.GetConfig()
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	k8sClient, err := client.New(cfg, client.Options{Scheme: scheme})
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	ginkgo.By(fmt.Sprintf("attempting to create workspace %s", workspace.Name))
+	err = k8sClient.Create(context.Background(), workspace)
+	gomega.Expect(err).To(gomega.HaveOccurred(), fmt.Sprintf("Expected webhook to reject workspace %s", workspace.Name))
+	status, ok := err.(apierrors.APIStatus)
+	gomega.Expect(ok).To(gomega.BeTrue(), "Expected the rejection to carry a status: %v", err)
+	return status.Status()
+}
+
 // RestartWorkspacePod force a restart of the underlying pod
 func RestartWorkspacePod(workspaceName, namespace string) {
 	ginkgo.GinkgoHelper()
@@ -58,6 +58,7 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -101,6 +102,7 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -210,6 +212,7 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionFalse,
+				controller.ConditionTypeReady:       ConditionFalse,
 				controller.ConditionTypeStopped:     ConditionTrue,
 			})
 
@@ -298,6 +301,7 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionFalse,
+				controller.ConditionTypeReady:       ConditionFalse,
 				controller.ConditionTypeStopped:     ConditionTrue,
 			})
 
@@ -320,6 +324,64 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 			Expect(accessURL).To(BeEmpty())
 		})
 
+		It("should stop routing to the workspace pod before removing its routes", func() {
+			workspaceFilename := "workspace-running-access-strategy"
+			workspaceName := "workspace-running-access-strategy"
+			primaryRouteName := fmt.Sprintf("primary-route-%s", workspaceName)
+			serviceName := controller.GenerateServiceName(workspaceName)
+			deploymentName := controller.GenerateDeploymentName(workspaceName)
+			// The window between the service dropping the pod and the routes going away
+			// is the drain period plus the time the deployment takes to be removed
+			maxUnroutedWindow := controller.DefaultConnectionDrainPeriod + 15*time.Second
+
+			By("creating an access strategy with both env vars and resources")
+			createAccessStrategyForTest(accessStrategyFilename, groupDir, subGroup)
+
+			By("creating a workspace with desiredState=running referencing AccessStrategy")
+			createWorkspaceForTest(workspaceFilename, groupDir, subGroup)
+
+			By("waiting for the workspace to become available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				controller.ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			By("updating workspace to desiredState=stopped")
+			UpdateWorkspaceDesiredState(workspaceName, workspaceNamespace, "Stopped")
+
+			By("observing the teardown until the routes are removed")
+			var endpointsRemovedAt time.Time
+			Eventually(func(g Gomega) {
+				endpoints, _ := kubectlGet("endpoints", serviceName, workspaceNamespace,
+					"{.subsets[*].addresses[*].ip}")
+				routeExists := ResourceExists("ingressroute.traefik.io", primaryRouteName, workspaceNamespace, "{.metadata.name}")
+				deploymentExists := ResourceExists("deployment", deploymentName, workspaceNamespace, "{.metadata.name}")
+
+				if endpoints == "" && endpointsRemovedAt.IsZero() {
+					endpointsRemovedAt = time.Now()
+				}
+				Expect(endpoints == "" || endpointsRemovedAt.IsZero()).To(BeTrue(),
+					"the service routed to the workspace pod again after draining")
+				Expect(routeExists || !deploymentExists).To(BeTrue(),
+					"the routes were removed while the workspace deployment was still running")
+				g.Expect(routeExists).To(BeFalse())
+			}, 2*time.Minute, "1s").Should(Succeed())
+
+			By("verifying the routes went away within the drain window")
+			Expect(endpointsRemovedAt).NotTo(BeZero())
+			Expect(time.Since(endpointsRemovedAt)).To(BeNumerically("<", maxUnroutedWindow))
+
+			By("waiting for the workspace to become stopped")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				controller.ConditionTypeStopped,
+				ConditionTrue,
+			)
+		})
+
 		It("should create access resources when transitioning from stopped to running", func() {
 			workspaceFilename := "workspace-stopped-access-strategy"
 			workspaceName := "workspace-stopped-access-strategy"
@@ -375,6 +437,7 @@ var _ = Describe("Workspace Access Strategy", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -14,8 +14,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/internal/webhook/reasons"
 	"github.com/jupyter-infra/jupyter-k8s/test/utils"
 )
 
@@ -64,12 +66,18 @@ var _ = Describe("Workspace Namespace Scope", Ordered, func() {
 		})
 
 		It("should reject workspace referencing a template from another team's namespace", func() {
-			path := BuildTestResourcePath("ws-cross-ns-rejected", groupDir, subgroup)
-			cmd := exec.Command("kubectl", "apply", "-f", path)
-			_, err := utils.Run(cmd)
-			Expect(err).To(HaveOccurred(), "Expected webhook to reject cross-namespace template reference")
-
-			cmd = exec.Command("kubectl", "get", "workspace", "ws-cross-ns-rejected",
+			status := GetCreateWorkspaceRejection("ws-cross-ns-rejected", groupDir, subgroup)
+			Expect(status.Reason).To(Equal(reasons.TemplateNamespaceForbidden))
+			Expect(status.Details).NotTo(BeNil())
+			Expect(status.Details.Name).To(Equal("team-b-template"))
+			Expect(status.Details.Causes).To(ConsistOf(
+				metav1.StatusCause{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldTemplateRefNamespace,
+					Message: workspaceNamespace},
+				metav1.StatusCause{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldTemplateRefNamespace,
+					Message: SharedNamespace},
+			))
+
+			cmd := exec.Command("kubectl", "get", "workspace", "ws-cross-ns-rejected",
 				"-n", workspaceNamespace, "--ignore-not-found")
 			output, err := utils.Run(cmd)
 			Expect(err).NotTo(HaveOccurred())
@@ -143,12 +151,17 @@ var _ = Describe("Workspace Namespace Scope", Ordered, func() {
 		})
 
 		It("should reject workspace referencing an access strategy from another team's namespace", func() {
-			path := BuildTestResourcePath("ws-cross-ns-as-rejected", groupDir, subgroup)
-			cmd := exec.Command("kubectl", "apply", "-f", path)
-			_, err := utils.Run(cmd)
-			Expect(err).To(HaveOccurred(), "Expected webhook to reject cross-namespace access strategy reference")
-
-			cmd = exec.Command("kubectl", "get", "workspace", "ws-cross-ns-as-rejected",
+			status := GetCreateWorkspaceRejection("ws-cross-ns-as-rejected", groupDir, subgroup)
+			Expect(status.Reason).To(Equal(reasons.AccessStrategyNamespaceForbidden))
+			Expect(status.Details).NotTo(BeNil())
+			Expect(status.Details.Causes).To(ConsistOf(
+				metav1.StatusCause{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldAccessStrategyNamespace,
+					Message: workspaceNamespace},
+				metav1.StatusCause{Type: reasons.CauseTypeNamespaceAllowed, Field: reasons.FieldAccessStrategyNamespace,
+					Message: SharedNamespace},
+			))
+
+			cmd := exec.Command("kubectl", "get", "workspace", "ws-cross-ns-as-rejected",
 				"-n", workspaceNamespace, "--ignore-not-found")
 			output, err := utils.Run(cmd)
 			Expect(err).NotTo(HaveOccurred())
@@ -10,6 +10,7 @@ package e2e
 
 import (
 	"os/exec"
+	"strings"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -24,7 +25,12 @@ const (
 	ownershipNamespace   = "default"
 	user1                = "user-1"
 	user2                = "user-2"
+	user3                = "user-3"
+	user4                = "user-4"
 	adminUser            = "admin-user"
+
+	// privateWorkspaceAccessName is the Role and RoleBinding the controller grants the owner of private-workspace
+	privateWorkspaceAccessName = "workspace-private-workspace-owner"
 )
 
 var _ = Describe("Workspace Ownership", Ordered, func() {
@@ -209,9 +215,116 @@ var _ = Describe("Workspace Ownership", Ordered, func() {
 				WaitForResourceToNotExist("workspace", "owner-only-workspace", ownershipNamespace, 60*time.Second, 2*time.Second)
 			})
 		})
+
+		Context("OwnerOnly workspace of a user who may only create workspaces", func() {
+			BeforeAll(func() {
+				By("creating RBAC role for workspace creation only")
+				cmd := exec.Command("kubectl", "create", "-f",
+					BuildTestResourcePath("workspace-creator-role", ownershipGroupDir, ownershipSubgroupDir))
+				_, err := utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, binding := range []string{"workspace-creator-user3-binding", "workspace-creator-user4-binding"} {
+					By("creating RoleBinding " + binding)
+					cmd = exec.Command("kubectl", "create", "-f",
+						BuildTestResourcePath(binding, ownershipGroupDir, ownershipSubgroupDir))
+					_, err = utils.Run(cmd)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				By("creating an OwnerOnly workspace as user-3")
+				err = createObjectAsUser(
+					BuildTestResourcePath("private-workspace", ownershipGroupDir, ownershipSubgroupDir), user3, []string{})
+				Expect(err).NotTo(HaveOccurred())
+
+				By("waiting for the controller to grant user-3 access to the workspace")
+				WaitForResourceToExist("rolebinding", privateWorkspaceAccessName, ownershipNamespace, "{.metadata.name}",
+					60*time.Second, 2*time.Second)
+			})
+
+			It("should stamp the creating user as the owner", func() {
+				output, err := kubectlGet("workspace", "private-workspace", ownershipNamespace,
+					"{.metadata.annotations.workspace\\.jupyter\\.org/created-by}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal(user3))
+			})
+
+			It("should grant the owner access to the workspace only", func() {
+				By("checking the RoleBinding names only user-3")
+				output, err := kubectlGet("rolebinding", privateWorkspaceAccessName, ownershipNamespace, "{.subjects[*].name}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal(user3))
+
+				By("checking the Role is limited to the workspace")
+				output, err = kubectlGet("role", privateWorkspaceAccessName, ownershipNamespace, "{.rules[0].resourceNames}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(ContainSubstring("private-workspace"))
+
+				for _, verb := range []string{"get", "update", "delete"} {
+					Expect(canUserAccessWorkspace(verb, "private-workspace", user3)).To(BeTrue(),
+						"user-3 should be able to %s their workspace", verb)
+					Expect(canUserAccessWorkspace(verb, "private-workspace", user4)).To(BeFalse(),
+						"user-4 should not be able to %s the workspace of user-3", verb)
+				}
+				Expect(canUserAccessWorkspace("get", "other-workspace", user3)).To(BeFalse(),
+					"user-3 should not be granted access to other workspaces")
+			})
+
+			It("should deny user-4 from reading the workspace of user-3", func() {
+				cmd := exec.Command("kubectl", "get", "workspace", "private-workspace", "-n", ownershipNamespace,
+					"--as="+user4)
+				_, err := utils.Run(cmd)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("forbidden"))
+			})
+
+			It("should deny other users from changing the owner", func() {
+				By("attempting to take over the workspace as user-2, who may update every workspace")
+				cmd := exec.Command("kubectl", "annotate", "workspace", "private-workspace", "-n", ownershipNamespace,
+					"--overwrite", "workspace.jupyter.org/created-by="+user2, "--as="+user2)
+				_, err := utils.Run(cmd)
+				Expect(err).To(HaveOccurred(), "user-2 should NOT be able to change the owner")
+
+				output, err := kubectlGet("workspace", "private-workspace", ownershipNamespace,
+					"{.metadata.annotations.workspace\\.jupyter\\.org/created-by}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal(user3))
+			})
+
+			It("should allow user-3 to update and delete their workspace", func() {
+				By("updating the workspace as user-3")
+				err := updateObjectAsUser(
+					BuildTestResourcePath("private-workspace-updated", ownershipGroupDir, ownershipSubgroupDir), user3, []string{})
+				Expect(err).NotTo(HaveOccurred(), "user-3 should be able to update their own workspace")
+
+				Eventually(func(g Gomega) {
+					output, err := kubectlGet("workspace", "private-workspace", ownershipNamespace, "{.spec.displayName}")
+					g.Expect(err).NotTo(HaveOccurred())
+					g.Expect(output).To(Equal("Private Workspace Updated"))
+				}).WithTimeout(30 * time.Second).WithPolling(2 * time.Second).Should(Succeed())
+
+				By("deleting the workspace as user-3")
+				err = deleteWorkspaceAsUser("private-workspace", user3, []string{})
+				Expect(err).NotTo(HaveOccurred(), "user-3 should be able to delete their own workspace")
+				WaitForResourceToNotExist("workspace", "private-workspace", ownershipNamespace, 60*time.Second, 2*time.Second)
+
+				By("verifying the access of user-3 is deleted with the workspace")
+				WaitForResourceToNotExist("rolebinding", privateWorkspaceAccessName, ownershipNamespace,
+					60*time.Second, 2*time.Second)
+			})
+		})
 	})
 })
 
+// canUserAccessWorkspace checks with kubectl auth can-i whether the user may use the verb on the workspace
+func canUserAccessWorkspace(verb, name, user string) bool {
+	GinkgoHelper()
+	cmd := exec.Command("kubectl", "auth", "can-i", verb, "workspaces.workspace.jupyter.org/"+name,
+		"-n", ownershipNamespace, "--as="+user)
+	output, _ := utils.Run(cmd)
+	return strings.TrimSpace(output) == "yes"
+}
+
 // deleteResourcesForOwnershipTest cleans up resources created during ownership tests
 func deleteResourcesForOwnershipTest() {
 	GinkgoHelper()
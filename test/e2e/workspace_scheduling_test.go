@@ -50,6 +50,7 @@ var _ = Describe("Workspace Scheduling", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -83,6 +84,7 @@ var _ = Describe("Workspace Scheduling", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -115,6 +117,7 @@ var _ = Describe("Workspace Scheduling", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
@@ -0,0 +1,103 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package e2e
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/test/utils"
+)
+
+// listPodsScript lists the pods of the namespace of the workspace through the Kubernetes API, with the
+// service account token mounted where in-cluster clients read it
+const listPodsScript = `
+import json, ssl, urllib.request
+base = "/var/run/secrets/kubernetes.io/serviceaccount/"
+token = open(base + "token").read()
+namespace = open(base + "namespace").read()
+context = ssl.create_default_context(cafile=base + "ca.crt")
+request = urllib.request.Request(
+    "https://kubernetes.default.svc/api/v1/namespaces/" + namespace + "/pods",
+    headers={"Authorization": "Bearer " + token})
+pods = json.load(urllib.request.urlopen(request, context=context))
+print(" ".join(pod["metadata"]["name"] for pod in pods["items"]))
+`
+
+var _ = Describe("Workspace Service Account", Ordered, func() {
+	const (
+		workspaceNamespace = "default"
+		groupDir           = "service-account"
+	)
+
+	BeforeAll(func() {
+		By("creating the pod-reader ServiceAccount and the template granting it")
+		cmd := exec.Command("kubectl", "apply", "-f", BuildTestResourcePath("pod-reader", groupDir, ""))
+		_, err := utils.Run(cmd)
+		Expect(err).NotTo(HaveOccurred())
+		createTemplateForTest("service-account-template", groupDir, "")
+	})
+
+	AfterAll(func() {
+		By("cleaning up test resources")
+		cmd := exec.Command("kubectl", "delete", "workspace", "--all", "-n", workspaceNamespace,
+			"--ignore-not-found", "--wait=true", "--timeout=120s")
+		_, _ = utils.Run(cmd)
+		cmd = exec.Command("kubectl", "delete", "workspacetemplate", "service-account-template",
+			"-n", workspaceNamespace, "--ignore-not-found")
+		_, _ = utils.Run(cmd)
+		cmd = exec.Command("kubectl", "delete", "-f", BuildTestResourcePath("pod-reader", groupDir, ""),
+			"--ignore-not-found")
+		_, _ = utils.Run(cmd)
+	})
+
+	It("should reject a ServiceAccount the template does not allow", func() {
+		VerifyCreateWorkspaceRejectedByWebhook("disallowed-service-account-workspace", groupDir, "",
+			"disallowed-service-account-workspace", workspaceNamespace)
+	})
+
+	It("should list pods with the ServiceAccount granted by the template", func() {
+		workspaceName := "pod-reader-workspace"
+		createWorkspaceForTest(workspaceName, groupDir, "")
+
+		By("verifying the workspace defaults to the ServiceAccount of the template")
+		serviceAccount, err := kubectlGet("workspace", workspaceName, workspaceNamespace, "{.spec.serviceAccountName}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(serviceAccount).To(Equal("pod-reader"))
+
+		WaitForWorkspaceToReachCondition(workspaceName, workspaceNamespace,
+			controller.ConditionTypeAvailable, ConditionTrue)
+
+		if isUsingFinch() {
+			Skip("skipping exec-based API access test (Finch has known cgroup access issues)")
+		}
+
+		podSelector := fmt.Sprintf("%s=%s", WorkspaceLabelName, workspaceName)
+		podName, err := kubectlGetByLabels("pod", podSelector, workspaceNamespace, "{.items[*].metadata.name}")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(podName).NotTo(BeEmpty())
+		WaitForWorkspacePodToBeReady(podName, workspaceNamespace)
+
+		By("listing the pods of the namespace from the workspace")
+		// Retries handle transient OCI exec failures
+		Eventually(func(g Gomega) {
+			cmd := exec.Command("kubectl", "exec", podName, "-n", workspaceNamespace, "--",
+				"python3", "-c", listPodsScript)
+			output, err := utils.Run(cmd)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(strings.Fields(output)).To(ContainElement(podName))
+		}, 60*time.Second, 2*time.Second).Should(Succeed())
+	})
+})
@@ -58,6 +58,7 @@ var _ = Describe("Workspace Status", Ordered, func() {
 				ConditionTypeProgressing: ConditionFalse,
 				ConditionTypeDegraded:    ConditionFalse,
 				ConditionTypeAvailable:   ConditionTrue,
+				ConditionTypeReady:       ConditionTrue,
 				ConditionTypeStopped:     ConditionFalse,
 			})
 		})
@@ -158,6 +159,7 @@ var _ = Describe("Workspace Status", Ordered, func() {
 				ConditionTypeProgressing: ConditionFalse,
 				ConditionTypeDegraded:    ConditionFalse,
 				ConditionTypeAvailable:   ConditionFalse,
+				ConditionTypeReady:       ConditionFalse,
 				ConditionTypeStopped:     ConditionTrue,
 			})
 		})
@@ -277,6 +279,7 @@ var _ = Describe("Workspace Status", Ordered, func() {
 				ConditionTypeProgressing: ConditionFalse,
 				ConditionTypeDegraded:    ConditionFalse,
 				ConditionTypeAvailable:   ConditionFalse,
+				ConditionTypeReady:       ConditionFalse,
 				ConditionTypeStopped:     ConditionTrue,
 			})
 
@@ -333,6 +336,7 @@ var _ = Describe("Workspace Status", Ordered, func() {
 				ConditionTypeProgressing: ConditionFalse,
 				ConditionTypeDegraded:    ConditionFalse,
 				ConditionTypeAvailable:   ConditionTrue,
+				ConditionTypeReady:       ConditionTrue,
 				ConditionTypeStopped:     ConditionFalse,
 			})
 
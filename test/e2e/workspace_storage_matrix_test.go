@@ -0,0 +1,300 @@
+//go:build e2e
+// +build e2e
+
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+	"github.com/jupyter-infra/jupyter-k8s/test/utils"
+)
+
+// StorageMatrixClassesEnv lists, comma separated, the storage classes the storage matrix runs against.
+// When not set, the matrix runs against every storage class of the test cluster.
+const StorageMatrixClassesEnv = "E2E_STORAGE_CLASSES"
+
+// StorageMatrixLabel labels the specs of the storage matrix; each class is also labeled storage-class:<name>
+const StorageMatrixLabel = "storage-matrix"
+
+// The storage matrix runs the core workspace lifecycle against each storage class of the cluster, so that
+// clusters with different provisioners catch the features that break on their classes. Select it with
+// -ginkgo.label-filter=storage-matrix, or a single class with -ginkgo.label-filter='storage-class: {name}'.
+// Steps needing a capability the class lacks are skipped with the reason.
+var _ = Describe("Workspace Storage Matrix", Label(StorageMatrixLabel), func() {
+	storageClasses, discoveryErr := discoverStorageClassesForMatrix()
+
+	if discoveryErr != nil || len(storageClasses) == 0 {
+		It("should run against the storage classes of the cluster", func() {
+			if discoveryErr != nil {
+				Skip(fmt.Sprintf("storage classes could not be discovered, set %s to list them: %v",
+					StorageMatrixClassesEnv, discoveryErr))
+			}
+			Skip("the cluster has no storage class")
+		})
+		return
+	}
+
+	for i, storageClassName := range storageClasses {
+		describeStorageClassLifecycle(storageClassName, fmt.Sprintf("storage-matrix-%d", i))
+	}
+})
+
+// describeStorageClassLifecycle registers the lifecycle specs of a workspace using the storage class
+func describeStorageClassLifecycle(storageClassName, workspaceName string) {
+	const workspaceNamespace = "default"
+
+	Describe(fmt.Sprintf("with storage class %s", storageClassName), Ordered,
+		Label("storage-class:"+storageClassName), func() {
+			var storageClass *storagev1.StorageClass
+			pvcName := controller.GeneratePVCName(workspaceName)
+
+			BeforeAll(func() {
+				var err error
+				storageClass, err = getStorageClassForMatrix(storageClassName)
+				if err != nil {
+					Skip(fmt.Sprintf("storage class %s is not available: %v", storageClassName, err))
+				}
+				AddReportEntry("storageClass", storageClassName)
+				AddReportEntry("provisioner", storageClass.Provisioner)
+				AddReportEntry("reclaimPolicy", string(storageClassReclaimPolicy(storageClass)))
+				AddReportEntry("allowVolumeExpansion", fmt.Sprintf("%t", storageClassAllowsExpansion(storageClass)))
+			})
+
+			AfterAll(func() {
+				deleteStorageMatrixResources(workspaceName, workspaceNamespace, pvcName)
+			})
+
+			It("should start a workspace and write to its home volume", func() {
+				applyStorageMatrixWorkspace(workspaceName, workspaceNamespace, storageClassName)
+
+				By("waiting for the workspace to become Available")
+				WaitForWorkspaceToReachCondition(workspaceName, workspaceNamespace, ConditionTypeAvailable, ConditionTrue)
+
+				By("verifying the pvc uses the storage class")
+				className, err := kubectlGet("pvc", pvcName, workspaceNamespace, "{.spec.storageClassName}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(className).To(Equal(storageClassName))
+
+				VerifyPodCanAccessHomeVolume(workspaceName, workspaceNamespace)
+			})
+
+			It("should keep the data across a stop and a start", func() {
+				UpdateWorkspaceDesiredState(workspaceName, workspaceNamespace, "Stopped")
+				WaitForWorkspaceToReachCondition(workspaceName, workspaceNamespace, ConditionTypeStopped, ConditionTrue)
+
+				By("verifying the pvc outlives the stopped workspace")
+				Expect(ResourceExists("pvc", pvcName, workspaceNamespace, "{.metadata.name}")).To(BeTrue())
+
+				UpdateWorkspaceDesiredState(workspaceName, workspaceNamespace, "Running")
+				WaitForWorkspaceToReachCondition(workspaceName, workspaceNamespace, ConditionTypeAvailable, ConditionTrue)
+
+				VerifyHomeVolumeDataPersisted(workspaceName, workspaceNamespace)
+			})
+
+			It("should expand the home volume", func() {
+				if !storageClassAllowsExpansion(storageClass) {
+					Skip(fmt.Sprintf("storage class %s does not allow volume expansion", storageClassName))
+				}
+
+				By("requesting a larger volume")
+				cmd := exec.Command("kubectl", "patch", "pvc", pvcName, "-n", workspaceNamespace, "--type=merge",
+					"-p", `{"spec":{"resources":{"requests":{"storage":"3Gi"}}}}`)
+				_, err := utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("waiting for the volume to be expanded")
+				Eventually(func() (string, error) {
+					return kubectlGet("pvc", pvcName, workspaceNamespace, "{.status.capacity.storage}")
+				}, 3*time.Minute, 5*time.Second).Should(Equal("3Gi"))
+
+				By("verifying the controller keeps the expanded request")
+				Consistently(func() (string, error) {
+					return kubectlGet("pvc", pvcName, workspaceNamespace, "{.spec.resources.requests.storage}")
+				}, 10*time.Second, 2*time.Second).Should(Equal("3Gi"))
+
+				VerifyHomeVolumeDataPersisted(workspaceName, workspaceNamespace)
+			})
+
+			It("should let a recreated workspace adopt its retained volume", func() {
+				if reclaimPolicy := storageClassReclaimPolicy(storageClass); reclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+					Skip(fmt.Sprintf("storage class %s reclaims volumes with policy %s, not %s",
+						storageClassName, reclaimPolicy, corev1.PersistentVolumeReclaimRetain))
+				}
+
+				volumeName, err := kubectlGet("pvc", pvcName, workspaceNamespace, "{.spec.volumeName}")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(volumeName).NotTo(BeEmpty())
+
+				By("deleting the workspace")
+				cmd := exec.Command("kubectl", "delete", "workspace", workspaceName, "-n", workspaceNamespace,
+					"--wait=true", "--timeout=180s")
+				_, err = utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+				WaitForResourceToNotExist("pvc", pvcName, workspaceNamespace, 60*time.Second, 5*time.Second)
+
+				By("verifying the volume was retained")
+				Eventually(func() (string, error) {
+					return kubectlGet("pv", volumeName, "", "{.status.phase}")
+				}, 60*time.Second, 2*time.Second).Should(Equal(string(corev1.VolumeReleased)))
+
+				By("binding a pvc labeled for the workspace to the retained volume")
+				cmd = exec.Command("kubectl", "patch", "pv", volumeName, "--type=json",
+					"-p", `[{"op":"remove","path":"/spec/claimRef"}]`)
+				_, err = utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+				applyStorageMatrixManifest(fmt.Sprintf(storageMatrixPVCManifest,
+					pvcName, workspaceNamespace, WorkspaceLabelName, workspaceName, storageClassName, volumeName))
+				WaitForPVCBinding(pvcName, workspaceNamespace)
+
+				By("recreating the workspace")
+				applyStorageMatrixWorkspace(workspaceName, workspaceNamespace, storageClassName)
+				WaitForWorkspaceToReachCondition(workspaceName, workspaceNamespace, ConditionTypeAvailable, ConditionTrue)
+
+				By("verifying the workspace adopted the pvc")
+				Eventually(func() (string, error) {
+					return kubectlGet("pvc", pvcName, workspaceNamespace, "{.metadata.ownerReferences[0].name}")
+				}, 30*time.Second, 2*time.Second).Should(Equal(workspaceName))
+
+				VerifyHomeVolumeDataPersisted(workspaceName, workspaceNamespace)
+			})
+		})
+}
+
+// storageMatrixWorkspaceManifest is the workspace of the matrix; its home volume uses the storage class
+const storageMatrixWorkspaceManifest = `apiVersion: workspace.jupyter.org/v1alpha1
+kind: Workspace
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  displayName: "Storage matrix workspace"
+  image: jk8s-application-jupyter-uv:latest
+  desiredStatus: Running
+  storage:
+    size: 2Gi
+    storageClassName: %s
+  resources:
+    requests:
+      cpu: 100m
+      memory: 256Mi
+    limits:
+      cpu: 500m
+      memory: 512Mi
+`
+
+// storageMatrixPVCManifest is a pvc bound to a retained volume, labeled for the workspace to adopt it
+const storageMatrixPVCManifest = `apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: %s
+  namespace: %s
+  labels:
+    %s: %s
+spec:
+  accessModes:
+  - ReadWriteOnce
+  storageClassName: %s
+  volumeName: %s
+  resources:
+    requests:
+      storage: 2Gi
+`
+
+// discoverStorageClassesForMatrix returns the storage classes listed by StorageMatrixClassesEnv,
+// or else those of the cluster. It runs when the spec tree is built, before the suite setup.
+func discoverStorageClassesForMatrix() ([]string, error) {
+	if listed := os.Getenv(StorageMatrixClassesEnv); listed != "" {
+		var classes []string
+		for _, name := range strings.Split(listed, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				classes = append(classes, name)
+			}
+		}
+		return classes, nil
+	}
+	output, err := exec.Command("kubectl", "get", "storageclass", "--request-timeout=10s",
+		"-o", "jsonpath={.items[*].metadata.name}").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list storage classes: %w", err)
+	}
+	return strings.Fields(string(output)), nil
+}
+
+// getStorageClassForMatrix fetches the storage class to read its capabilities
+func getStorageClassForMatrix(name string) (*storagev1.StorageClass, error) {
+	cmd := exec.Command("kubectl", "get", "storageclass", name, "-o", "json")
+	output, err := utils.Run(cmd)
+	if err != nil {
+		return nil, err
+	}
+	storageClass := &storagev1.StorageClass{}
+	if err := json.Unmarshal([]byte(output), storageClass); err != nil {
+		return nil, fmt.Errorf("failed to decode storage class %s: %w", name, err)
+	}
+	return storageClass, nil
+}
+
+// storageClassReclaimPolicy returns the reclaim policy of the volumes of the class, Delete by default
+func storageClassReclaimPolicy(storageClass *storagev1.StorageClass) corev1.PersistentVolumeReclaimPolicy {
+	if storageClass.ReclaimPolicy == nil {
+		return corev1.PersistentVolumeReclaimDelete
+	}
+	return *storageClass.ReclaimPolicy
+}
+
+// storageClassAllowsExpansion returns true if the volumes of the class can be expanded
+func storageClassAllowsExpansion(storageClass *storagev1.StorageClass) bool {
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion
+}
+
+// applyStorageMatrixWorkspace creates the matrix workspace using the storage class
+func applyStorageMatrixWorkspace(workspaceName, namespace, storageClassName string) {
+	GinkgoHelper()
+	By(fmt.Sprintf("creating workspace %s with storage class %s", workspaceName, storageClassName))
+	applyStorageMatrixManifest(fmt.Sprintf(storageMatrixWorkspaceManifest, workspaceName, namespace, storageClassName))
+}
+
+// applyStorageMatrixManifest applies a manifest passed on the standard input of kubectl
+func applyStorageMatrixManifest(manifest string) {
+	GinkgoHelper()
+	cmd := exec.Command("kubectl", "apply", "-f", "-")
+	cmd.Stdin = strings.NewReader(manifest)
+	_, err := utils.Run(cmd)
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// deleteStorageMatrixResources deletes the workspace of the matrix, its pvc, and the volume a Retain
+// class kept for it
+func deleteStorageMatrixResources(workspaceName, namespace, pvcName string) {
+	volumeName, _ := kubectlGet("pvc", pvcName, namespace, "{.spec.volumeName}")
+
+	By(fmt.Sprintf("deleting workspace %s", workspaceName))
+	cmd := exec.Command("kubectl", "delete", "workspace", workspaceName, "-n", namespace,
+		"--ignore-not-found", "--wait=true", "--timeout=180s")
+	_, _ = utils.Run(cmd)
+
+	cmd = exec.Command("kubectl", "delete", "pvc", pvcName, "-n", namespace,
+		"--ignore-not-found", "--wait=true", "--timeout=60s")
+	_, _ = utils.Run(cmd)
+
+	if volumeName != "" {
+		cmd = exec.Command("kubectl", "delete", "pv", volumeName, "--ignore-not-found", "--wait=false")
+		_, _ = utils.Run(cmd)
+	}
+}
@@ -9,6 +9,7 @@ Distributed under the terms of the MIT license
 package e2e
 
 import (
+	"fmt"
 	"os/exec"
 	"time"
 
@@ -35,6 +36,7 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 		baseSubgroup     = "base"
 		externalSubgroup = "external"
 		templateSubgroup = "template"
+		classSubgroup    = "class"
 
 		baseWorkspaceName = "workspace-with-storage"
 		externalPvc1Name  = "external-pvc-1"
@@ -167,6 +169,80 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 				"workspace-storage", "/home/jovyan/data")
 		})
 
+		It("should keep the same pvc across stop and start cycles", func() {
+			workspaceFilename := baseWorkspaceName
+			workspaceName := baseWorkspaceName
+
+			By("creating a workspace with a pvc")
+			createWorkspaceForTest(workspaceFilename, group, baseSubgroup)
+
+			By("waiting for the workspace to become Available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			By("retrieving the uid of the pvc")
+			pvcName := controller.GeneratePVCName(workspaceName)
+			pvcUID, err := kubectlGet("pvc", pvcName, workspaceNamespace, "{.metadata.uid}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pvcUID).NotTo(BeEmpty())
+
+			for cycle := 1; cycle <= 3; cycle++ {
+				By(fmt.Sprintf("stopping the workspace, cycle %d", cycle))
+				cmd := exec.Command("kubectl", "patch", "workspace", workspaceName,
+					"-n", workspaceNamespace,
+					"--type=merge", "-p", `{"spec":{"desiredStatus":"Stopped"}}`)
+				_, err = utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+
+				WaitForWorkspaceToReachCondition(
+					workspaceName,
+					workspaceNamespace,
+					ConditionTypeStopped,
+					ConditionTrue,
+				)
+
+				By("verifying the phase and the Ready condition of the stopped workspace")
+				phase, phaseErr := kubectlGet("workspace", workspaceName, workspaceNamespace, "{.status.phase}")
+				Expect(phaseErr).NotTo(HaveOccurred())
+				Expect(phase).To(Equal("Stopped"))
+				readyReason, readyErr := kubectlGet("workspace", workspaceName, workspaceNamespace,
+					`{.status.conditions[?(@.type=="Ready")].reason}`)
+				Expect(readyErr).NotTo(HaveOccurred())
+				Expect(readyReason).To(Equal("Stopped"))
+
+				By("verifying the pvc was kept")
+				uid, uidErr := kubectlGet("pvc", pvcName, workspaceNamespace, "{.metadata.uid}")
+				Expect(uidErr).NotTo(HaveOccurred())
+				Expect(uid).To(Equal(pvcUID), "the pvc should not be recreated while stopped")
+
+				By(fmt.Sprintf("starting the workspace, cycle %d", cycle))
+				cmd = exec.Command("kubectl", "patch", "workspace", workspaceName,
+					"-n", workspaceNamespace,
+					"--type=merge", "-p", `{"spec":{"desiredStatus":"Running"}}`)
+				_, err = utils.Run(cmd)
+				Expect(err).NotTo(HaveOccurred())
+
+				WaitForWorkspaceToReachCondition(
+					workspaceName,
+					workspaceNamespace,
+					ConditionTypeReady,
+					ConditionTrue,
+				)
+
+				By("verifying the restarted workspace mounts the same pvc")
+				uid, uidErr = kubectlGet("pvc", pvcName, workspaceNamespace, "{.metadata.uid}")
+				Expect(uidErr).NotTo(HaveOccurred())
+				Expect(uid).To(Equal(pvcUID), "the pvc should not be recreated on restart")
+			}
+
+			VerifyWorkspaceVolumeMount(workspaceName, workspaceNamespace,
+				"workspace-storage", "/home/jovyan")
+		})
+
 		It("should delete pvc when workspace is deleted", func() {
 			workspaceFilename := baseWorkspaceName
 			workspaceName := baseWorkspaceName
@@ -225,6 +301,67 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 			By("verifying the data was persisted")
 			VerifyHomeVolumeDataPersisted(workspaceName, workspaceNamespace)
 		})
+		It("should keep the pvc and service of a paused workspace and resume quickly", func() {
+			workspaceFilename := baseWorkspaceName
+			workspaceName := baseWorkspaceName
+			pvcName := controller.GeneratePVCName(workspaceName)
+			podSelector := fmt.Sprintf("%s=%s", WorkspaceLabelName, workspaceName)
+
+			By("creating a workspace with a pvc")
+			createWorkspaceForTest(workspaceFilename, group, baseSubgroup)
+
+			By("waiting for the workspace to become Available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			By("verifying can write to volume")
+			VerifyPodCanAccessHomeVolume(workspaceName, workspaceNamespace)
+
+			UpdateWorkspaceDesiredState(workspaceName, workspaceNamespace, controller.DesiredStatePaused)
+
+			By("waiting for the workspace to be Paused")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypePaused,
+				ConditionTrue,
+			)
+
+			By("verifying the pod is gone")
+			Eventually(func(g Gomega) {
+				pods, err := kubectlGetByLabels("pods", podSelector, workspaceNamespace, "{.items[*].metadata.name}")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(pods).To(BeEmpty())
+			}).WithTimeout(60 * time.Second).WithPolling(2 * time.Second).Should(Succeed())
+
+			By("verifying the pvc is still bound")
+			phase, err := kubectlGet("pvc", pvcName, workspaceNamespace, "{.status.phase}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(phase).To(Equal("Bound"))
+
+			By("verifying the service is kept")
+			Expect(ResourceExists("service", controller.GenerateServiceName(workspaceName), workspaceNamespace,
+				"{.metadata.name}")).To(BeTrue())
+
+			resumedAt := time.Now()
+			UpdateWorkspaceDesiredState(workspaceName, workspaceNamespace, controller.DesiredStateRunning)
+
+			By("waiting for the workspace to become Available again")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+			Expect(time.Since(resumedAt)).To(BeNumerically("<", 2*time.Minute), "resuming only recreates the pod")
+
+			By("verifying the workspace resumed on the same pvc")
+			VerifyHomeVolumeDataPersisted(workspaceName, workspaceNamespace)
+		})
 	})
 
 	Context("External volumes", func() {
@@ -313,6 +450,58 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 		})
 	})
 
+	Context("Extra volumes", func() {
+		const extraSubgroup = "extra"
+
+		It("should mount a shared dataset PVC and a ConfigMap startup script", func() {
+			workspaceFilename := "workspace-with-extra-volumes"
+			workspaceName := "workspace-with-extra-volumes"
+
+			By("creating the shared dataset pvc")
+			createPvcForTest("shared-dataset-pvc", group, extraSubgroup)
+
+			By("creating the startup script configmap")
+			createConfigMapForTest("startup-script-configmap", group, extraSubgroup)
+			DeferCleanup(func() {
+				cmd := exec.Command("kubectl", "delete", "configmap", "startup-script", "-n", workspaceNamespace,
+					"--ignore-not-found")
+				_, _ = utils.Run(cmd)
+			})
+
+			By("creating a workspace mounting both as extra volumes")
+			createWorkspaceForTest(workspaceFilename, group, extraSubgroup)
+
+			By("waiting for the workspace to become available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			By("verifying the extra mounts")
+			VerifyWorkspaceVolumeMount(workspaceName, workspaceNamespace, "dataset", "/datasets/shared")
+			VerifyWorkspaceVolumeMount(workspaceName, workspaceNamespace, "startup", "/opt/startup")
+			VerifyWorkspaceVolumeMount(workspaceName, workspaceNamespace, "workspace-storage", "/home/jovyan")
+
+			By("verifying can write to the shared dataset pvc")
+			VerifyPodCanAccessExternalVolumes(workspaceName, workspaceNamespace, "shared-dataset", "/datasets/shared")
+
+			By("verifying the startup script ran")
+			VerifyPodFileExists(workspaceName, workspaceNamespace, "/tmp/startup-script-ran")
+		})
+
+		It("should reject an extra volume referencing a missing PVC", func() {
+			VerifyCreateWorkspaceRejectedByWebhook("workspace-extra-volume-missing-pvc", group, extraSubgroup,
+				"workspace-extra-volume-missing-pvc", workspaceNamespace)
+		})
+
+		It("should reject an extra volume mounted over the home directory", func() {
+			VerifyCreateWorkspaceRejectedByWebhook("workspace-extra-volume-over-home", group, extraSubgroup,
+				"workspace-extra-volume-over-home", workspaceNamespace)
+		})
+	})
+
 	Context("Template-based", func() {
 		It("should create PVC within template bounds", func() {
 			templateFilename := templateName
@@ -423,6 +612,103 @@ var _ = Describe("Workspace Storage", Ordered, func() {
 			VerifyCreateWorkspaceRejectedByWebhook(workspaceFilename, group, templateSubgroup, workspaceName, workspaceNamespace)
 		})
 	})
+
+	Context("Storage classes", func() {
+		const classTemplateName = "storage-class-template"
+
+		It("should create the PVC with the template default storage class", func() {
+			workspaceName := "workspace-default-storage-class"
+
+			By("creating the template")
+			createTemplateForTest(classTemplateName, group, classSubgroup)
+
+			By("creating the workspace referencing the template")
+			createWorkspaceForTest(workspaceName, group, classSubgroup)
+
+			By("verifying that the workspace becomes available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			testPvcForStorageTest(
+				workspaceName,
+				workspaceNamespace,
+				[]valueTestCaseForStorageTest{
+					{
+						description: "verifying pvc storage class",
+						jsonPath:    "{.spec.storageClassName}",
+						expected:    "standard",
+					},
+					{
+						description: "verifying binding status",
+						jsonPath:    "{.status.phase}",
+						expected:    "Bound",
+					},
+				},
+			)
+		})
+
+		It("should create the PVC with the storage class the workspace selects", func() {
+			workspaceName := "workspace-selected-storage-class"
+
+			By("creating the template")
+			createTemplateForTest(classTemplateName, group, classSubgroup)
+
+			By("creating the workspace selecting the manually created storage class")
+			createWorkspaceForTest(workspaceName, group, classSubgroup)
+
+			By("verifying that the workspace becomes available")
+			WaitForWorkspaceToReachCondition(
+				workspaceName,
+				workspaceNamespace,
+				ConditionTypeAvailable,
+				ConditionTrue,
+			)
+
+			testPvcForStorageTest(
+				workspaceName,
+				workspaceNamespace,
+				[]valueTestCaseForStorageTest{
+					{
+						description: "verifying pvc storage class",
+						jsonPath:    "{.spec.storageClassName}",
+						expected:    storageClassName,
+					},
+					{
+						description: "verifying binding status",
+						jsonPath:    "{.status.phase}",
+						expected:    "Bound",
+					},
+				},
+			)
+
+			By("verifying the webhook rejects changing the storage class")
+			cmd := exec.Command("kubectl", "patch", "workspace", workspaceName, "-n", workspaceNamespace,
+				"--type=merge", "-p", `{"spec":{"storage":{"storageClassName":"standard"}}}`)
+			_, err := utils.Run(cmd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("immutable"))
+
+			By("verifying the pvc kept its storage class")
+			output, err := kubectlGet("pvc", controller.GeneratePVCName(workspaceName), workspaceNamespace,
+				"{.spec.storageClassName}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(Equal(storageClassName))
+		})
+
+		It("should reject a storage class outside the template allowlist", func() {
+			workspaceName := "workspace-disallowed-storage-class"
+
+			By("creating the template")
+			createTemplateForTest(classTemplateName, group, classSubgroup)
+
+			By("verifying the webhook rejects the workspace creation")
+			VerifyCreateWorkspaceRejectedByWebhook(workspaceName, group, classSubgroup, workspaceName, workspaceNamespace)
+		})
+	})
 })
 
 //nolint:unparam
@@ -83,6 +83,7 @@ var _ = Describe("Workspace Template", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 		})
@@ -159,6 +160,7 @@ var _ = Describe("Workspace Template", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 		})
@@ -421,7 +423,7 @@ var _ = Describe("Workspace Template", Ordered, func() {
 	})
 
 	Context("Mutability and Deletion Protection", func() {
-		It("should prevent template deletion when workspace is using it", func() {
+		It("should require confirming deletion and keep the template while a workspace uses it", func() {
 			workspaceName := "deletion-protection-workspace"
 			workspaceFilename := "deletion-protection-workspace"
 
@@ -445,9 +447,31 @@ var _ = Describe("Workspace Template", Ordered, func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(output).To(Equal(baseTemplateName))
 
-			By("attempting to delete the template")
+			By("verifying the template counts the workspace")
+			Eventually(func(g Gomega) {
+				output, err := kubectlGet("workspacetemplate",
+					baseTemplateName, SharedNamespace, "{.status.workspaceCount}")
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(output).To(Equal("1"))
+			}).WithTimeout(30 * time.Second).WithPolling(1 * time.Second).Should(Succeed())
+
+			By("attempting to delete the template without confirmation")
 			cmd := exec.Command("kubectl", "delete", "workspacetemplate",
 				baseTemplateName, "-n", SharedNamespace, "--wait=false")
+			output, err = utils.Run(cmd)
+			Expect(err).To(HaveOccurred(), "expected the webhook to reject an unconfirmed delete")
+			Expect(output).To(ContainSubstring(workspaceNamespace + "/" + workspaceName))
+			Expect(output).To(ContainSubstring("Deleting it does not delete them"))
+
+			By("confirming the number of affected workspaces")
+			cmd = exec.Command("kubectl", "annotate", "workspacetemplate", baseTemplateName,
+				"-n", SharedNamespace, controller.AnnotationConfirmDelete+"=1")
+			_, err = utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("deleting the template with confirmation")
+			cmd = exec.Command("kubectl", "delete", "workspacetemplate",
+				baseTemplateName, "-n", SharedNamespace, "--wait=false")
 			_, err = utils.Run(cmd)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -520,6 +544,7 @@ var _ = Describe("Workspace Template", Ordered, func() {
 				controller.ConditionTypeProgressing: ConditionFalse,
 				controller.ConditionTypeDegraded:    ConditionFalse,
 				controller.ConditionTypeAvailable:   ConditionTrue,
+				controller.ConditionTypeReady:       ConditionTrue,
 				controller.ConditionTypeStopped:     ConditionFalse,
 			})
 
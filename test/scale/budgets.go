@@ -0,0 +1,147 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+// Package scale drives synthetic workspace lifecycles against the real controllers, to estimate the
+// behavior of the operator at a target scale and to gate regressions of its performance.
+package scale
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// LatencyBudget bounds percentiles of a latency; an omitted percentile is not checked
+type LatencyBudget struct {
+	P50 *metav1.Duration `json:"p50,omitempty"`
+	P90 *metav1.Duration `json:"p90,omitempty"`
+	P99 *metav1.Duration `json:"p99,omitempty"`
+}
+
+// Budgets are the limits a run fails on when exceeded; an omitted or zero budget is not checked.
+// Budgets are calibrated for the default harness flags: the writes per workspace grow with the
+// stop/start cycles of each workspace.
+type Budgets struct {
+	// ReconcileLatency bounds the reconcile time of the workspace controller
+	ReconcileLatency LatencyBudget `json:"reconcileLatency,omitempty"`
+
+	// TimeToReady bounds the time from creating or starting a workspace until it is available
+	TimeToReady LatencyBudget `json:"timeToReady,omitempty"`
+
+	// MaxWritesPerWorkspace bounds the API writes of the operator per workspace lifecycle
+	MaxWritesPerWorkspace float64 `json:"maxWritesPerWorkspace,omitempty"`
+
+	// MaxHeapMiB bounds the peak heap in use by the operator process
+	MaxHeapMiB float64 `json:"maxHeapMiB,omitempty"`
+
+	// MaxGoroutines bounds the peak number of goroutines of the operator process
+	MaxGoroutines int `json:"maxGoroutines,omitempty"`
+
+	// MaxLeakedGoroutines bounds the goroutines left once every workspace is deleted, above those
+	// running before the first was created
+	MaxLeakedGoroutines int `json:"maxLeakedGoroutines,omitempty"`
+}
+
+// LoadBudgets reads the budgets of a YAML file
+func LoadBudgets(path string) (*Budgets, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read budgets %s: %w", path, err)
+	}
+	budgets := &Budgets{}
+	if err := yaml.UnmarshalStrict(data, budgets); err != nil {
+		return nil, fmt.Errorf("failed to parse budgets %s: %w", path, err)
+	}
+	return budgets, nil
+}
+
+// Percentiles are the p50, p90 and p99 of a latency
+type Percentiles struct {
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+}
+
+// String formats the percentiles for the report
+func (p Percentiles) String() string {
+	return fmt.Sprintf("p50=%s p90=%s p99=%s", p.P50, p.P90, p.P99)
+}
+
+// Report is what a run measured
+type Report struct {
+	// Workspaces is the number of workspace lifecycles driven
+	Workspaces int
+	// Templates is the number of templates the workspaces were spread over
+	Templates int
+	// Cycles is the number of stop/start cycles of each workspace
+	Cycles int
+	// Duration is how long the run took
+	Duration time.Duration
+
+	// ReconcileLatency is estimated from the reconcile time histogram of the workspace controller
+	ReconcileLatency Percentiles
+	// Reconciles is the number of reconciles of the workspace controller
+	Reconciles uint64
+	// TimeToReady is measured from creating or starting each workspace until it is available
+	TimeToReady Percentiles
+
+	// Writes is the number of API writes of the operator, by resource
+	Writes map[string]int
+	// WritesPerWorkspace is the number of API writes of the operator per workspace lifecycle
+	WritesPerWorkspace float64
+
+	// PeakHeapMiB is the peak heap in use sampled during the run
+	PeakHeapMiB float64
+	// PeakGoroutines is the peak number of goroutines sampled during the run
+	PeakGoroutines int
+	// LeakedGoroutines is the number of goroutines left once every workspace is deleted, above the baseline
+	LeakedGoroutines int
+}
+
+// checkLatency describes each percentile above its budget
+func checkLatency(name string, budget LatencyBudget, measured Percentiles) []string {
+	var violations []string
+	for _, percentile := range []struct {
+		name     string
+		budget   *metav1.Duration
+		measured time.Duration
+	}{
+		{"p50", budget.P50, measured.P50},
+		{"p90", budget.P90, measured.P90},
+		{"p99", budget.P99, measured.P99},
+	} {
+		if percentile.budget != nil && percentile.budget.Duration > 0 && percentile.measured > percentile.budget.Duration {
+			violations = append(violations, fmt.Sprintf("%s %s %s exceeds its budget of %s",
+				name, percentile.name, percentile.measured, percentile.budget.Duration))
+		}
+	}
+	return violations
+}
+
+// Check describes each measure of the report above its budget
+func (b *Budgets) Check(report *Report) []string {
+	violations := checkLatency("reconcile latency", b.ReconcileLatency, report.ReconcileLatency)
+	violations = append(violations, checkLatency("time to ready", b.TimeToReady, report.TimeToReady)...)
+	if b.MaxWritesPerWorkspace > 0 && report.WritesPerWorkspace > b.MaxWritesPerWorkspace {
+		violations = append(violations, fmt.Sprintf("%.1f API writes per workspace exceed the budget of %.1f",
+			report.WritesPerWorkspace, b.MaxWritesPerWorkspace))
+	}
+	if b.MaxHeapMiB > 0 && report.PeakHeapMiB > b.MaxHeapMiB {
+		violations = append(violations, fmt.Sprintf("peak heap of %.1fMiB exceeds the budget of %.1fMiB",
+			report.PeakHeapMiB, b.MaxHeapMiB))
+	}
+	if b.MaxGoroutines > 0 && report.PeakGoroutines > b.MaxGoroutines {
+		violations = append(violations, fmt.Sprintf("peak of %d goroutines exceeds the budget of %d",
+			report.PeakGoroutines, b.MaxGoroutines))
+	}
+	if b.MaxLeakedGoroutines > 0 && report.LeakedGoroutines > b.MaxLeakedGoroutines {
+		violations = append(violations, fmt.Sprintf("%d goroutines left after the run exceed the budget of %d",
+			report.LeakedGoroutines, b.MaxLeakedGoroutines))
+	}
+	return violations
+}
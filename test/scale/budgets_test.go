@@ -0,0 +1,121 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package scale
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestShippedBudgetsLoad(t *testing.T) {
+	for _, path := range []string{"budgets.yaml", "budgets-1500.yaml"} {
+		budgets, err := LoadBudgets(path)
+		require.NoError(t, err, path)
+		require.NotNil(t, budgets.ReconcileLatency.P99, path)
+		assert.Positive(t, budgets.ReconcileLatency.P99.Duration, path)
+		assert.Positive(t, budgets.MaxWritesPerWorkspace, path)
+	}
+}
+
+func TestLoadBudgetsRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "budgets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("maxWritesPerWorkpsace: 10\n"), 0o600))
+
+	_, err := LoadBudgets(path)
+	assert.Error(t, err, "a misspelled budget must not go unchecked")
+}
+
+func TestCheckReportsEachExceededBudget(t *testing.T) {
+	budgets := &Budgets{
+		ReconcileLatency:      LatencyBudget{P99: &metav1.Duration{Duration: time.Second}},
+		TimeToReady:           LatencyBudget{P50: &metav1.Duration{Duration: 5 * time.Second}},
+		MaxWritesPerWorkspace: 20,
+		MaxHeapMiB:            256,
+		MaxGoroutines:         1000,
+		MaxLeakedGoroutines:   10,
+	}
+	report := &Report{
+		ReconcileLatency:   Percentiles{P50: time.Millisecond, P90: 10 * time.Second, P99: 2 * time.Second},
+		TimeToReady:        Percentiles{P50: 6 * time.Second},
+		WritesPerWorkspace: 20.5,
+		PeakHeapMiB:        300,
+		PeakGoroutines:     1000,
+		LeakedGoroutines:   11,
+	}
+
+	violations := budgets.Check(report)
+	assert.Len(t, violations, 5, "the p90 and goroutines have no budget or stay within it: %v", violations)
+	assert.Contains(t, violations[0], "reconcile latency p99")
+	assert.Contains(t, violations[1], "time to ready p50")
+}
+
+func TestCheckWithoutBudgetsPasses(t *testing.T) {
+	report := &Report{ReconcileLatency: Percentiles{P99: time.Hour}, WritesPerWorkspace: 1000}
+	assert.Empty(t, (&Budgets{}).Check(report))
+}
+
+// histogramOf returns a histogram of the cumulative counts of buckets bounded at 0.1s, 1s and +Inf
+func histogramOf(counts ...uint64) *dto.Histogram {
+	histogram := &dto.Histogram{SampleCount: ptr.To(counts[len(counts)-1])}
+	for i, bound := range []float64{0.1, 1, math.Inf(1)} {
+		histogram.Bucket = append(histogram.Bucket, &dto.Bucket{UpperBound: ptr.To(bound), CumulativeCount: ptr.To(counts[i])})
+	}
+	return histogram
+}
+
+func TestHistogramQuantileInterpolatesWithinBuckets(t *testing.T) {
+	histogram := histogramOf(50, 100, 100)
+
+	assert.InDelta(t, 50*time.Millisecond, histogramQuantile(0.25, histogram), float64(time.Microsecond))
+	assert.InDelta(t, 100*time.Millisecond, histogramQuantile(0.50, histogram), float64(time.Microsecond))
+	assert.InDelta(t, 820*time.Millisecond, histogramQuantile(0.90, histogram), float64(time.Microsecond))
+	assert.Zero(t, histogramQuantile(0.99, &dto.Histogram{}))
+}
+
+func TestHistogramQuantileInTheInfiniteBucketIsItsLowerBound(t *testing.T) {
+	assert.Equal(t, time.Second, histogramQuantile(0.99, histogramOf(0, 10, 100)))
+}
+
+func TestReconcileLatencySinceIgnoresTheBaseline(t *testing.T) {
+	baseline := histogramOf(0, 0, 10)
+	latency, reconciles := reconcileLatencySince(baseline, histogramOf(100, 100, 110))
+
+	assert.Equal(t, uint64(100), reconciles)
+	assert.InDelta(t, 99*time.Millisecond, latency.P99, float64(time.Microsecond), "the slow baseline reconciles are not counted")
+}
+
+func TestSamplePercentilesByNearestRank(t *testing.T) {
+	var samples []time.Duration
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Second)
+	}
+
+	assert.Equal(t, Percentiles{P50: 50 * time.Second, P90: 90 * time.Second, P99: 99 * time.Second}, samplePercentiles(samples))
+	assert.Equal(t, 100*time.Second, samples[0], "the samples are not reordered")
+	assert.Equal(t, Percentiles{}, samplePercentiles(nil))
+}
+
+func TestResourceOfPath(t *testing.T) {
+	for path, resource := range map[string]string{
+		"/api/v1/namespaces/team-a/pods":                                              "pods",
+		"/api/v1/namespaces/team-a/events/ws.1234":                                    "events",
+		"/apis/apps/v1/namespaces/team-a/deployments/workspace-ws":                    "deployments",
+		"/apis/workspace.jupyter.org/v1alpha1/namespaces/team-a/workspaces/ws/status": "workspaces/status",
+		"/api/v1/namespaces/team-a":                                                   "namespaces",
+		"/apis/storage.k8s.io/v1/storageclasses/gp3":                                  "storageclasses",
+	} {
+		assert.Equal(t, resource, resourceOfPath(path), path)
+	}
+}
@@ -0,0 +1,491 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	workspacev1alpha1 "github.com/jupyter-infra/jupyter-k8s/api/v1alpha1"
+	"github.com/jupyter-infra/jupyter-k8s/internal/controller"
+)
+
+const (
+	// workspaceControllerName is the name the workspace controller records its metrics under
+	workspaceControllerName = "workspace"
+
+	// pollInterval is how often the harness checks the workspaces it waits for
+	pollInterval = 200 * time.Millisecond
+
+	// settlePeriod is how long the operator is given to settle before its goroutines are counted
+	settlePeriod = 5 * time.Second
+)
+
+// Config is the load a run drives
+type Config struct {
+	// Workspaces is the number of workspace lifecycles to drive
+	Workspaces int
+	// Templates is the number of templates the workspaces are spread over
+	Templates int
+	// Cycles is the number of stop/start cycles of each workspace
+	Cycles int
+	// Concurrency is the number of workspaces changed at once
+	Concurrency int
+	// Timeout bounds how long a workspace may take to become available, stopped or deleted
+	Timeout time.Duration
+	// WatchPods enables the workspace pod watching of the controller, as the Helm chart does
+	WatchPods bool
+	// ProfileDir is where heap and goroutine profiles are written, at steady state and at the end; none when empty
+	ProfileDir string
+	// UseExistingCluster runs against the cluster of the current kubeconfig, kind for instance, instead of
+	// envtest. The cluster must not run the operator: the harness runs the controllers itself, and its
+	// pods are run by the cluster instead of the readiness injector.
+	UseExistingCluster bool
+	// Logf reports the progress of the run
+	Logf func(format string, args ...any)
+}
+
+// run is the state of a run
+type run struct {
+	config    Config
+	driver    client.Client
+	namespace string
+	writes    *writeCounter
+	sampler   *processSampler
+
+	mu          sync.Mutex
+	timeToReady []time.Duration
+}
+
+// Run starts the workspace and template controllers, drives the lifecycles of the config against them and
+// reports what it measured. Each workspace is created and waited for until available, then the workspaces
+// are stopped and started again for each cycle, and finally deleted.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Logf == nil {
+		cfg.Logf = func(string, ...any) {}
+	}
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+		UseExistingCluster:    ptr.To(cfg.UseExistingCluster),
+	}
+	if dir := getFirstFoundEnvTestBinaryDir(); dir != "" {
+		env.BinaryAssetsDirectory = dir
+	}
+	restConfig, err := env.Start()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the test environment: %w", err)
+	}
+	defer func() {
+		if err := env.Stop(); err != nil {
+			cfg.Logf("failed to stop the test environment: %v", err)
+		}
+	}()
+
+	scheme := k8sruntime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := workspacev1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+
+	// The harness and the readiness injector are not rate limited, and their writes are not counted
+	driverConfig := rest.CopyConfig(restConfig)
+	driverConfig.QPS = -1
+	driver, err := client.New(driverConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the harness client: %w", err)
+	}
+
+	writes := newWriteCounter()
+	operatorConfig := rest.CopyConfig(restConfig)
+	operatorConfig.WrapTransport = writes.wrap
+	mgr, err := ctrl.NewManager(operatorConfig, ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: "0"},
+		HealthProbeBindAddress: "0",
+		Controller:             config.Controller{SkipNameValidation: ptr.To(true)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the manager: %w", err)
+	}
+	if err := controller.SetupWorkspaceController(mgr, controller.WorkspaceControllerOptions{
+		ApplicationImagesPullPolicy: corev1.PullIfNotPresent,
+		EnableWorkspacePodWatching:  cfg.WatchPods,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to set up the workspace controller: %w", err)
+	}
+	if err := controller.SetupWorkspaceTemplateController(mgr, nil); err != nil {
+		return nil, fmt.Errorf("failed to set up the template controller: %w", err)
+	}
+	if !cfg.UseExistingCluster {
+		if err := setupReadinessInjector(mgr, driver); err != nil {
+			return nil, fmt.Errorf("failed to set up the readiness injector: %w", err)
+		}
+	}
+
+	mgrCtx, cancel := context.WithCancel(ctx)
+	var mgrErr error
+	mgrDone := make(chan struct{})
+	go func() {
+		defer close(mgrDone)
+		mgrErr = mgr.Start(mgrCtx)
+	}()
+	defer func() {
+		cancel()
+		<-mgrDone
+	}()
+	if !mgr.GetCache().WaitForCacheSync(ctx) {
+		return nil, errors.New("the manager caches did not sync")
+	}
+
+	r := &run{config: cfg, driver: driver, writes: writes, sampler: &processSampler{}}
+	report, err := r.drive(ctx)
+	cancel()
+	<-mgrDone
+	if err == nil && mgrErr != nil {
+		err = fmt.Errorf("the manager failed: %w", mgrErr)
+	}
+	return report, err
+}
+
+// drive runs the lifecycles of the workspaces against the started manager
+func (r *run) drive(ctx context.Context) (*Report, error) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "scale-"}}
+	if err := r.driver.Create(ctx, namespace); err != nil {
+		return nil, fmt.Errorf("failed to create the namespace: %w", err)
+	}
+	r.namespace = namespace.Name
+	defer func() {
+		if err := r.driver.Delete(context.WithoutCancel(ctx), namespace); err != nil {
+			r.config.Logf("failed to delete namespace %s: %v", namespace.Name, err)
+		}
+	}()
+	templates, err := r.createTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// A first lifecycle starts the informers the controllers create lazily, so that they are neither
+	// counted as leaked goroutines nor as writes of the measured workspaces
+	if err := r.lifecycle(ctx, "scale-warmup", templates[0]); err != nil {
+		return nil, fmt.Errorf("warm-up lifecycle failed: %w", err)
+	}
+	r.timeToReady = nil
+	r.writes.reset()
+	histogramBaseline, err := reconcileHistogram(metrics.Registry, workspaceControllerName)
+	if err != nil {
+		return nil, err
+	}
+	baselineGoroutines := settledGoroutines(ctx)
+
+	samplerCtx, stopSampler := context.WithCancel(ctx)
+	defer stopSampler()
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			r.sampler.sample()
+			select {
+			case <-samplerCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	started := time.Now()
+	names := make([]string, r.config.Workspaces)
+	for i := range names {
+		names[i] = fmt.Sprintf("scale-ws-%04d", i)
+	}
+	r.config.Logf("creating %d workspaces over %d templates", len(names), len(templates))
+	if err := r.forEach(ctx, names, func(ctx context.Context, i int, name string) error {
+		return r.create(ctx, name, templates[i%len(templates)])
+	}); err != nil {
+		return nil, err
+	}
+	if err := r.writeProfiles("steady"); err != nil {
+		return nil, err
+	}
+	for cycle := range r.config.Cycles {
+		r.config.Logf("stop/start cycle %d of %d", cycle+1, r.config.Cycles)
+		if err := r.forEach(ctx, names, func(ctx context.Context, _ int, name string) error {
+			return r.setDesiredStatus(ctx, name, controller.DesiredStateStopped)
+		}); err != nil {
+			return nil, err
+		}
+		if err := r.forEach(ctx, names, func(ctx context.Context, _ int, name string) error {
+			return r.setDesiredStatus(ctx, name, controller.DesiredStateRunning)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	r.config.Logf("deleting %d workspaces", len(names))
+	if err := r.forEach(ctx, names, func(ctx context.Context, _ int, name string) error {
+		return r.delete(ctx, name)
+	}); err != nil {
+		return nil, err
+	}
+	duration := time.Since(started)
+	stopSampler()
+
+	leaked := max(settledGoroutines(ctx)-baselineGoroutines, 0)
+	if err := r.writeProfiles("end"); err != nil {
+		return nil, err
+	}
+	histogram, err := reconcileHistogram(metrics.Registry, workspaceControllerName)
+	if err != nil {
+		return nil, err
+	}
+	latency, reconciles := reconcileLatencySince(histogramBaseline, histogram)
+	writes, total := r.writes.snapshot()
+	peakHeap, peakGoroutines := r.sampler.peaks()
+	return &Report{
+		Workspaces:         r.config.Workspaces,
+		Templates:          len(templates),
+		Cycles:             r.config.Cycles,
+		Duration:           duration,
+		ReconcileLatency:   latency,
+		Reconciles:         reconciles,
+		TimeToReady:        samplePercentiles(r.timeToReady),
+		Writes:             writes,
+		WritesPerWorkspace: float64(total) / float64(max(r.config.Workspaces, 1)),
+		PeakHeapMiB:        peakHeap,
+		PeakGoroutines:     peakGoroutines,
+		LeakedGoroutines:   leaked,
+	}, nil
+}
+
+// createTemplates creates the templates the workspaces are spread over
+func (r *run) createTemplates(ctx context.Context) ([]string, error) {
+	names := make([]string, max(r.config.Templates, 1))
+	for i := range names {
+		names[i] = fmt.Sprintf("scale-template-%02d", i)
+		template := &workspacev1alpha1.WorkspaceTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: names[i], Namespace: r.namespace},
+			Spec: workspacev1alpha1.WorkspaceTemplateSpec{
+				DisplayName:  fmt.Sprintf("Scale template %d", i),
+				DefaultImage: "jupyter/base-notebook:latest",
+			},
+		}
+		if err := r.driver.Create(ctx, template); err != nil {
+			return nil, fmt.Errorf("failed to create template %s: %w", names[i], err)
+		}
+	}
+	return names, nil
+}
+
+// forEach calls the function on each workspace, with the configured concurrency, and returns the first error
+func (r *run) forEach(ctx context.Context, names []string, fn func(context.Context, int, string) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	indexes := make(chan int)
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for range max(r.config.Concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(ctx, i, names[i]); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+	for i := range names {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return firstErr
+}
+
+// lifecycle creates, stops, starts and deletes one workspace
+func (r *run) lifecycle(ctx context.Context, name, template string) error {
+	if err := r.create(ctx, name, template); err != nil {
+		return err
+	}
+	if err := r.setDesiredStatus(ctx, name, controller.DesiredStateStopped); err != nil {
+		return err
+	}
+	if err := r.setDesiredStatus(ctx, name, controller.DesiredStateRunning); err != nil {
+		return err
+	}
+	return r.delete(ctx, name)
+}
+
+// create creates the workspace and waits until it is available
+func (r *run) create(ctx context.Context, name, template string) error {
+	workspace := &workspacev1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.namespace},
+		Spec: workspacev1alpha1.WorkspaceSpec{
+			DisplayName:   name,
+			DesiredStatus: controller.DesiredStateRunning,
+			TemplateRef:   &workspacev1alpha1.TemplateRef{Name: template},
+		},
+	}
+	started := time.Now()
+	if err := r.driver.Create(ctx, workspace); err != nil {
+		return fmt.Errorf("failed to create workspace %s: %w", name, err)
+	}
+	if err := r.waitForCondition(ctx, name, controller.ConditionTypeAvailable); err != nil {
+		return err
+	}
+	r.recordTimeToReady(time.Since(started))
+	return nil
+}
+
+// setDesiredStatus sets the desired status of the workspace and waits until the workspace reaches it
+func (r *run) setDesiredStatus(ctx context.Context, name, desiredStatus string) error {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.namespace}}
+	patch := fmt.Appendf(nil, `{"spec":{"desiredStatus":%q}}`, desiredStatus)
+	started := time.Now()
+	if err := r.driver.Patch(ctx, workspace, client.RawPatch("application/merge-patch+json", patch)); err != nil {
+		return fmt.Errorf("failed to set the desired status of workspace %s to %s: %w", name, desiredStatus, err)
+	}
+	if desiredStatus == controller.DesiredStateStopped {
+		return r.waitForCondition(ctx, name, controller.ConditionTypeStopped)
+	}
+	if err := r.waitForCondition(ctx, name, controller.ConditionTypeAvailable); err != nil {
+		return err
+	}
+	r.recordTimeToReady(time.Since(started))
+	return nil
+}
+
+// delete deletes the workspace and waits until it is gone
+func (r *run) delete(ctx context.Context, name string) error {
+	workspace := &workspacev1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.namespace}}
+	if err := r.driver.Delete(ctx, workspace); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete workspace %s: %w", name, err)
+	}
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, r.config.Timeout, true, func(ctx context.Context) (bool, error) {
+		err := r.driver.Get(ctx, client.ObjectKeyFromObject(workspace), workspace)
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("workspace %s was not deleted: %w", name, err)
+	}
+	return nil
+}
+
+// waitForCondition waits until the condition of the workspace is true
+func (r *run) waitForCondition(ctx context.Context, name, conditionType string) error {
+	workspace := &workspacev1alpha1.Workspace{}
+	key := client.ObjectKey{Name: name, Namespace: r.namespace}
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, r.config.Timeout, true, func(ctx context.Context) (bool, error) {
+		if err := r.driver.Get(ctx, key, workspace); err != nil {
+			return false, err
+		}
+		return apimeta.IsStatusConditionTrue(workspace.Status.Conditions, conditionType), nil
+	})
+	if err != nil {
+		return fmt.Errorf("workspace %s did not reach %s: %w; conditions: %v",
+			name, conditionType, err, workspace.Status.Conditions)
+	}
+	return nil
+}
+
+// recordTimeToReady records how long a workspace took to become available
+func (r *run) recordTimeToReady(elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timeToReady = append(r.timeToReady, elapsed)
+}
+
+// writeProfiles writes the heap and goroutine profiles of the process, named after the stage of the run
+func (r *run) writeProfiles(stage string) error {
+	if r.config.ProfileDir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(r.config.ProfileDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the profile directory: %w", err)
+	}
+	runtime.GC()
+	for _, profile := range []string{"heap", "goroutine"} {
+		path := filepath.Join(r.config.ProfileDir, fmt.Sprintf("%s-%s.pprof", profile, stage))
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create profile %s: %w", path, err)
+		}
+		err = pprof.Lookup(profile).WriteTo(file, 0)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write profile %s: %w", path, err)
+		}
+		r.config.Logf("wrote %s", path)
+	}
+	return nil
+}
+
+// settledGoroutines returns the number of goroutines once the settle period has passed
+func settledGoroutines(ctx context.Context) int {
+	select {
+	case <-ctx.Done():
+	case <-time.After(settlePeriod):
+	}
+	return runtime.NumGoroutine()
+}
+
+// getFirstFoundEnvTestBinaryDir locates the envtest binaries 'make setup-envtest' downloads, to run
+// without the KUBEBUILDER_ASSETS variable the Makefile targets set
+func getFirstFoundEnvTestBinaryDir() string {
+	basePath := filepath.Join("..", "..", "bin", "k8s")
+	entries, err := os.ReadDir(basePath)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			return filepath.Join(basePath, entry.Name())
+		}
+	}
+	return ""
+}
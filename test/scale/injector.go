@@ -0,0 +1,170 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package scale
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// injectorName is the name of the readiness injector controller
+	injectorName = "scale-readiness-injector"
+
+	// labelInjectedFor links the pods the readiness injector creates to their deployment
+	labelInjectedFor = "scale.jupyter.org/deployment"
+)
+
+// readinessInjector stands in for the deployment controller, scheduler and kubelet envtest lacks: it runs a
+// pod of each workspace deployment, marked running and ready, and reports the deployment available. Its
+// writes go through a client of its own, so that they are not counted as writes of the operator.
+type readinessInjector struct {
+	reader client.Reader
+	writer client.Client
+}
+
+// setupReadinessInjector registers the readiness injector with the manager
+func setupReadinessInjector(mgr manager.Manager, writer client.Client) error {
+	injector := &readinessInjector{reader: mgr.GetClient(), writer: writer}
+	return builder.ControllerManagedBy(mgr).
+		Named(injectorName).
+		For(&appsv1.Deployment{}).
+		Complete(injector)
+}
+
+// Reconcile makes the pods of the deployment match its replicas, then reports them available
+func (i *readinessInjector) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	deployment := &appsv1.Deployment{}
+	err := i.reader.Get(ctx, req.NamespacedName, deployment)
+	if apierrors.IsNotFound(err) {
+		return reconcile.Result{}, i.deletePods(ctx, req.Namespace, req.Name)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	if !deployment.DeletionTimestamp.IsZero() || replicas == 0 {
+		if err := i.deletePods(ctx, req.Namespace, req.Name); err != nil {
+			return reconcile.Result{}, err
+		}
+		if !deployment.DeletionTimestamp.IsZero() {
+			return reconcile.Result{}, nil
+		}
+	} else if err := i.ensureReadyPod(ctx, deployment); err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, i.reportAvailable(ctx, deployment, replicas)
+}
+
+// ensureReadyPod creates the pod of the deployment, running and ready. The pod has no node, so that the API
+// server deletes it right away rather than waiting for a kubelet.
+func (i *readinessInjector) ensureReadyPod(ctx context.Context, deployment *appsv1.Deployment) error {
+	template := deployment.Spec.Template.DeepCopy()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        deployment.Name + "-scale",
+			Namespace:   deployment.Namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+		},
+		Spec: template.Spec,
+	}
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[labelInjectedFor] = deployment.Name
+	pod.Spec.NodeName = ""
+
+	existing := &corev1.Pod{}
+	err := i.reader.Get(ctx, client.ObjectKeyFromObject(pod), existing)
+	switch {
+	case apierrors.IsNotFound(err):
+		if err := i.writer.Create(ctx, pod); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create pod %s: %w", pod.Name, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get pod %s: %w", pod.Name, err)
+	default:
+		if existing.Status.Phase == corev1.PodRunning {
+			return nil
+		}
+		pod = existing
+	}
+
+	now := metav1.Now()
+	pod.Status = corev1.PodStatus{
+		Phase:     corev1.PodRunning,
+		StartTime: &now,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			{Type: corev1.PodInitialized, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			{Type: corev1.ContainersReady, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		},
+	}
+	for _, container := range pod.Spec.Containers {
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:    container.Name,
+			Image:   container.Image,
+			Ready:   true,
+			Started: ptr.To(true),
+			State:   corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: now}},
+		})
+	}
+	if err := i.writer.Status().Update(ctx, pod); err != nil {
+		return fmt.Errorf("failed to mark pod %s ready: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// reportAvailable sets the status of the deployment to its replicas, all available
+func (i *readinessInjector) reportAvailable(ctx context.Context, deployment *appsv1.Deployment, replicas int32) error {
+	status := deployment.Status
+	if status.ObservedGeneration == deployment.Generation && status.AvailableReplicas == replicas &&
+		status.ReadyReplicas == replicas && len(status.Conditions) > 0 {
+		return nil
+	}
+	now := metav1.Now()
+	deployment.Status = appsv1.DeploymentStatus{
+		ObservedGeneration: deployment.Generation,
+		Replicas:           replicas,
+		UpdatedReplicas:    replicas,
+		ReadyReplicas:      replicas,
+		AvailableReplicas:  replicas,
+		Conditions: []appsv1.DeploymentCondition{{
+			Type:               appsv1.DeploymentAvailable,
+			Status:             corev1.ConditionTrue,
+			Reason:             "MinimumReplicasAvailable",
+			Message:            "Deployment has minimum availability.",
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+		}},
+	}
+	if err := i.writer.Status().Update(ctx, deployment); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to mark deployment %s available: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// deletePods deletes the pods the injector created for the deployment
+func (i *readinessInjector) deletePods(ctx context.Context, namespace, deploymentName string) error {
+	err := i.writer.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(namespace),
+		client.MatchingLabels{labelInjectedFor: deploymentName})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the pods of deployment %s: %w", deploymentName, err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package scale
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/utils/ptr"
+)
+
+// reconcileTimeMetric is the histogram controller-runtime records the reconcile time of each controller in
+const reconcileTimeMetric = "controller_runtime_reconcile_time_seconds"
+
+// histogramQuantile estimates the quantile of a histogram by linear interpolation within its buckets,
+// as the PromQL histogram_quantile function does
+func histogramQuantile(q float64, histogram *dto.Histogram) time.Duration {
+	count := float64(histogram.GetSampleCount())
+	if count == 0 {
+		return 0
+	}
+	rank := q * count
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, bucket := range histogram.GetBucket() {
+		upperBound, upperCount := bucket.GetUpperBound(), float64(bucket.GetCumulativeCount())
+		if upperCount >= rank {
+			if math.IsInf(upperBound, 1) {
+				return time.Duration(lowerBound * float64(time.Second))
+			}
+			fraction := 0.0
+			if upperCount > lowerCount {
+				fraction = (rank - lowerCount) / (upperCount - lowerCount)
+			}
+			return time.Duration((lowerBound + (upperBound-lowerBound)*fraction) * float64(time.Second))
+		}
+		lowerBound, lowerCount = upperBound, upperCount
+	}
+	return time.Duration(lowerBound * float64(time.Second))
+}
+
+// reconcileHistogram returns the reconcile time histogram of the controller
+func reconcileHistogram(gatherer prometheus.Gatherer, controllerName string) (*dto.Histogram, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	for _, family := range families {
+		if family.GetName() != reconcileTimeMetric {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "controller" && label.GetValue() == controllerName {
+					return metric.GetHistogram(), nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no %s metric for controller %s", reconcileTimeMetric, controllerName)
+}
+
+// reconcileLatencySince returns the percentiles and the number of the reconciles recorded in the histogram
+// after its baseline; a nil baseline has no reconciles
+func reconcileLatencySince(baseline, histogram *dto.Histogram) (Percentiles, uint64) {
+	since := &dto.Histogram{SampleCount: ptr.To(histogram.GetSampleCount() - baseline.GetSampleCount())}
+	for i, bucket := range histogram.GetBucket() {
+		count := bucket.GetCumulativeCount()
+		if i < len(baseline.GetBucket()) {
+			count -= baseline.GetBucket()[i].GetCumulativeCount()
+		}
+		since.Bucket = append(since.Bucket, &dto.Bucket{UpperBound: bucket.UpperBound, CumulativeCount: ptr.To(count)})
+	}
+	return Percentiles{
+		P50: histogramQuantile(0.50, since),
+		P90: histogramQuantile(0.90, since),
+		P99: histogramQuantile(0.99, since),
+	}, since.GetSampleCount()
+}
+
+// samplePercentiles returns the percentiles of the samples, by nearest rank
+func samplePercentiles(samples []time.Duration) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	rank := func(q float64) time.Duration {
+		index := int(math.Ceil(q*float64(len(sorted)))) - 1
+		return sorted[max(index, 0)]
+	}
+	return Percentiles{P50: rank(0.50), P90: rank(0.90), P99: rank(0.99)}
+}
+
+// writeCounter counts the API writes going through the transport it wraps, by resource
+type writeCounter struct {
+	mu     sync.Mutex
+	writes map[string]int
+}
+
+// newWriteCounter returns a counter without writes
+func newWriteCounter() *writeCounter {
+	return &writeCounter{writes: map[string]int{}}
+}
+
+// wrap returns a transport counting the writes of the transport, for rest.Config.WrapTransport
+func (c *writeCounter) wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		switch req.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			c.mu.Lock()
+			c.writes[resourceOfPath(req.URL.Path)]++
+			c.mu.Unlock()
+		}
+		return rt.RoundTrip(req)
+	})
+}
+
+// reset forgets the writes counted so far
+func (c *writeCounter) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = map[string]int{}
+}
+
+// snapshot returns the writes counted so far, by resource, and their total
+func (c *writeCounter) snapshot() (map[string]int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writes := make(map[string]int, len(c.writes))
+	total := 0
+	for resource, count := range c.writes {
+		writes[resource] = count
+		total += count
+	}
+	return writes, total
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// resourceOfPath returns the resource, and subresource, an API path names: after the namespace, or after
+// the group version of a cluster-scoped resource
+func resourceOfPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if segment == "namespaces" && i+2 < len(segments) {
+			segments = segments[i+2:]
+			break
+		}
+	}
+	if len(segments) > 0 && (segments[0] == "api" || segments[0] == "apis") {
+		// A cluster-scoped resource: /api/v1/... or /apis/group/version/...
+		skip := 2
+		if segments[0] == "apis" {
+			skip = 3
+		}
+		if len(segments) <= skip {
+			return path
+		}
+		segments = segments[skip:]
+	}
+	switch len(segments) {
+	case 0:
+		return path
+	case 1, 2:
+		return segments[0]
+	default:
+		return segments[0] + "/" + segments[2]
+	}
+}
+
+// processSampler samples the heap in use and the goroutines of the process
+type processSampler struct {
+	mu             sync.Mutex
+	peakHeapBytes  uint64
+	peakGoroutines int
+}
+
+// sample records the current heap in use and goroutines
+func (s *processSampler) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	goroutines := runtime.NumGoroutine()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peakHeapBytes = max(s.peakHeapBytes, stats.HeapInuse)
+	s.peakGoroutines = max(s.peakGoroutines, goroutines)
+}
+
+// peaks returns the peak heap in use, in MiB, and the peak goroutines sampled
+func (s *processSampler) peaks() (float64, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return float64(s.peakHeapBytes) / (1 << 20), s.peakGoroutines
+}
@@ -0,0 +1,75 @@
+//go:build scale
+// +build scale
+
+/*
+Copyright (c) Amazon Web Services
+Distributed under the terms of the MIT license
+*/
+
+package scale
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+var (
+	workspaces         = flag.Int("scale.workspaces", 100, "number of workspace lifecycles to drive")
+	templates          = flag.Int("scale.templates", 5, "number of templates the workspaces are spread over")
+	cycles             = flag.Int("scale.cycles", 1, "number of stop/start cycles of each workspace")
+	concurrency        = flag.Int("scale.concurrency", 25, "number of workspaces changed at once")
+	timeout            = flag.Duration("scale.timeout", 2*time.Minute, "how long a workspace may take to change state")
+	watchPods          = flag.Bool("scale.watch-pods", true, "enable the workspace pod watching of the controller")
+	budgetsPath        = flag.String("scale.budgets", "budgets.yaml", "budgets the run fails on when exceeded")
+	profileDir         = flag.String("scale.profile-dir", "", "directory to write heap and goroutine profiles to")
+	useExistingCluster = flag.Bool("scale.use-existing-cluster", false,
+		"run against the cluster of the current kubeconfig, such as kind, instead of envtest")
+	verbose = flag.Bool("scale.verbose", false, "write the operator logs to stderr")
+)
+
+func TestScale(t *testing.T) {
+	// The operator logs only when verbose, to keep the report readable
+	logs := io.Discard
+	if *verbose {
+		logs = os.Stderr
+	}
+	logf.SetLogger(zap.New(zap.WriteTo(logs), zap.UseDevMode(true)))
+
+	budgets, err := LoadBudgets(*budgetsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Run(context.Background(), Config{
+		Workspaces:         *workspaces,
+		Templates:          *templates,
+		Cycles:             *cycles,
+		Concurrency:        *concurrency,
+		Timeout:            *timeout,
+		WatchPods:          *watchPods,
+		ProfileDir:         *profileDir,
+		UseExistingCluster: *useExistingCluster,
+		Logf:               t.Logf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Logf("%d workspaces over %d templates, %d stop/start cycles, in %s",
+		report.Workspaces, report.Templates, report.Cycles, report.Duration)
+	t.Logf("reconcile latency: %s over %d reconciles", report.ReconcileLatency, report.Reconciles)
+	t.Logf("time to ready: %s", report.TimeToReady)
+	t.Logf("API writes: %.1f per workspace, by resource %v", report.WritesPerWorkspace, report.Writes)
+	t.Logf("peak heap %.1fMiB, peak goroutines %d, leaked goroutines %d",
+		report.PeakHeapMiB, report.PeakGoroutines, report.LeakedGoroutines)
+
+	for _, violation := range budgets.Check(report) {
+		t.Error(violation)
+	}
+}